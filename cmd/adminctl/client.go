@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// credentials 保存在本地的服务器地址和登录令牌
+type credentials struct {
+	Server       string `json:"server"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// credentialsPath 凭据文件路径，ADMINCTL_CONFIG 优先于用户配置目录
+func credentialsPath() (string, error) {
+	if path := os.Getenv("ADMINCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "adminctl", "credentials.json"), nil
+}
+
+// loadCredentials 读取凭据，文件不存在时返回空凭据
+func loadCredentials(path string) (*credentials, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &credentials{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials: %w", err)
+	}
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("parse credentials %s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+// save 写入凭据，只有当前用户可读写
+func (c *credentials) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	return nil
+}
+
+// apiError 接口返回的错误
+type apiError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *apiError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%d: %s", e.Status, e.Message)
+}
+
+// decodeError 解析两种错误响应：{"error": {"code", "message"}} 和 {"message", "error": "..."}
+func decodeError(status int, body []byte) error {
+	var envelope struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+
+	e := &apiError{Status: status, Message: envelope.Message}
+	var nested struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	var detail string
+	if json.Unmarshal(envelope.Error, &nested) == nil && nested.Message != "" {
+		e.Code, e.Message = nested.Code, nested.Message
+	} else if json.Unmarshal(envelope.Error, &detail) == nil && detail != "" {
+		if e.Message != "" {
+			detail = e.Message + ": " + detail
+		}
+		e.Message = detail
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+	return e
+}
+
+// client 调用管理接口的HTTP客户端
+type client struct {
+	http      *http.Client
+	creds     *credentials
+	credsPath string
+}
+
+func newClient(creds *credentials, credsPath string) *client {
+	return &client{
+		http:      &http.Client{Timeout: 5 * time.Minute},
+		creds:     creds,
+		credsPath: credsPath,
+	}
+}
+
+// do 发送请求并把响应中的 data 解析到 out，out 为 nil 时忽略响应内容。
+// 访问令牌过期时使用刷新令牌换取新令牌，保存后重试一次
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	status, data, err := c.send(ctx, method, path, payload)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusUnauthorized && c.creds.RefreshToken != "" && !strings.HasPrefix(path, "/api/v1/auth/") {
+		if err := c.refresh(ctx); err != nil {
+			return fmt.Errorf("session expired, run adminctl login: %w", err)
+		}
+		if status, data, err = c.send(ctx, method, path, payload); err != nil {
+			return err
+		}
+	}
+	if status >= http.StatusBadRequest {
+		return decodeError(status, data)
+	}
+
+	if out == nil {
+		return nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// send 发送一次请求，带上当前的访问令牌
+func (c *client) send(ctx context.Context, method, path string, payload []byte) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.server(), "/")+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.creds.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.creds.AccessToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, data, nil
+}
+
+// refresh 用刷新令牌换取新的令牌对并保存
+func (c *client) refresh(ctx context.Context) error {
+	var tokens dto.TokenResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/refresh", dto.RefreshTokenRequest{RefreshToken: c.creds.RefreshToken}, &tokens); err != nil {
+		return err
+	}
+	c.creds.AccessToken = tokens.AccessToken
+	c.creds.RefreshToken = tokens.RefreshToken
+	return c.creds.save(c.credsPath)
+}
+
+// server 服务器地址
+func (c *client) server() string {
+	if c.creds.Server != "" {
+		return c.creds.Server
+	}
+	return defaultServer
+}
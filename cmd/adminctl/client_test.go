@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "Application error",
+			status: http.StatusNotFound,
+			body:   `{"error": {"code": "USER_NOT_FOUND", "message": "用户不存在", "request_id": "r1"}}`,
+			want:   "404 USER_NOT_FOUND: 用户不存在",
+		},
+		{
+			name:   "Validation error",
+			status: http.StatusBadRequest,
+			body:   `{"code": 400, "message": "Validation failed", "error": "Password is required"}`,
+			want:   "400: Validation failed: Password is required",
+		},
+		{
+			name:   "Body without a message",
+			status: http.StatusBadGateway,
+			body:   `<html>bad gateway</html>`,
+			want:   "502: Bad Gateway",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, decodeError(tt.status, []byte(tt.body)), tt.want)
+		})
+	}
+}
+
+func TestClientRefreshesExpiredToken(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/refresh":
+			refreshes++
+			var body struct {
+				RefreshToken string `json:"refresh_token"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body.RefreshToken != "refresh-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"code": 200, "data": {"access_token": "access-2", "refresh_token": "refresh-2"}}`))
+		case "/api/v1/mcp/tools":
+			if r.Header.Get("Authorization") != "Bearer access-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": {"code": "UNAUTHORIZED", "message": "令牌已过期"}}`))
+				return
+			}
+			w.Write([]byte(`{"code": 200, "data": {"tools": [{"name": "echo"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	c := newClient(&credentials{Server: server.URL, AccessToken: "access-1", RefreshToken: "refresh-1"}, path)
+
+	var data struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	require.NoError(t, c.do(context.Background(), http.MethodGet, "/api/v1/mcp/tools", nil, &data))
+	require.Len(t, data.Tools, 1)
+	assert.Equal(t, "echo", data.Tools[0].Name)
+	assert.Equal(t, 1, refreshes)
+
+	// 新令牌已保存
+	saved, err := loadCredentials(path)
+	require.NoError(t, err)
+	assert.Equal(t, credentials{Server: server.URL, AccessToken: "access-2", RefreshToken: "refresh-2"}, *saved)
+
+	// 刷新令牌失效时提示重新登录
+	c.creds.AccessToken, c.creds.RefreshToken = "access-1", "revoked"
+	err = c.do(context.Background(), http.MethodGet, "/api/v1/mcp/tools", nil, &data)
+	assert.ErrorContains(t, err, "run adminctl login")
+}
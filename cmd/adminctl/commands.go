@@ -0,0 +1,477 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/provider"
+)
+
+// cli 命令执行环境
+type cli struct {
+	client *client
+	in     *bufio.Reader
+	out    io.Writer
+	json   bool // 以JSON输出接口返回的数据
+}
+
+// command 子命令，args 为子命令名之后的参数
+type command func(ctx context.Context, args []string) error
+
+// commands 子命令表，嵌套命令使用 "组 命令" 作为键
+func (c *cli) commands() map[string]command {
+	return map[string]command{
+		"login":          c.login,
+		"logout":         c.logout,
+		"providers":      c.providers,
+		"models list":    c.listModels,
+		"models enable":  c.setModelEnabled(true),
+		"models disable": c.setModelEnabled(false),
+		"keys set":       c.setKey,
+		"tools list":     c.listTools,
+		"tools run":      c.runTool,
+		"tools selftest": c.selfTest,
+		"logs tail":      c.tailLogs,
+		"users list":     c.listUsers,
+		"users create":   c.createUser,
+	}
+}
+
+// newFlagSet 创建子命令参数，解析错误由调用方返回
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// parseArgs 解析子命令参数并检查位置参数个数
+func parseArgs(flags *flag.FlagSet, args []string, positional ...string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != len(positional) {
+		return nil, fmt.Errorf("%s expects %d argument(s): %s", flags.Name(), len(positional), strings.Join(positional, " "))
+	}
+	return flags.Args(), nil
+}
+
+// readSecret 从标准输入读取一行，终端输入时先输出提示
+func (c *cli) readSecret(prompt string) (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, prompt)
+	}
+	line, err := c.in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("read %s from standard input: %w", strings.TrimSuffix(strings.ToLower(prompt), ": "), err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// print 以JSON或表格输出，table 为空时只支持JSON
+func (c *cli) print(data interface{}, table func(w *tabwriter.Writer)) error {
+	if c.json || table == nil {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func (c *cli) login(ctx context.Context, args []string) error {
+	flags := newFlagSet("login")
+	username := flags.String("username", "", "username")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("login requires -username")
+	}
+	password, err := c.readSecret("Password: ")
+	if err != nil {
+		return err
+	}
+
+	// 登录不使用旧令牌
+	c.client.creds.AccessToken, c.client.creds.RefreshToken = "", ""
+	var tokens dto.TokenResponse
+	if err := c.client.do(ctx, http.MethodPost, "/api/v1/auth/login", dto.LoginRequest{Username: *username, Password: password}, &tokens); err != nil {
+		return err
+	}
+	c.client.creds.Server = c.client.server()
+	c.client.creds.AccessToken = tokens.AccessToken
+	c.client.creds.RefreshToken = tokens.RefreshToken
+	if err := c.client.creds.save(c.client.credsPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Logged in to %s as %s\n", c.client.creds.Server, *username)
+	return nil
+}
+
+func (c *cli) logout(ctx context.Context, args []string) error {
+	if _, err := parseArgs(newFlagSet("logout"), args); err != nil {
+		return err
+	}
+	if c.client.creds.RefreshToken != "" {
+		if err := c.client.do(ctx, http.MethodPost, "/api/v1/auth/logout", dto.RefreshTokenRequest{RefreshToken: c.client.creds.RefreshToken}, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not revoke the session: %v\n", err)
+		}
+	}
+	c.client.creds.AccessToken, c.client.creds.RefreshToken = "", ""
+	return c.client.creds.save(c.client.credsPath)
+}
+
+func (c *cli) providers(ctx context.Context, args []string) error {
+	if _, err := parseArgs(newFlagSet("providers"), args); err != nil {
+		return err
+	}
+	var data struct {
+		Providers []provider.ProviderInfo `json:"providers"`
+	}
+	if err := c.client.do(ctx, http.MethodGet, "/api/v1/ai/providers", nil, &data); err != nil {
+		return err
+	}
+	return c.print(data.Providers, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "TYPE\tNAME\tHEALTHY\tMODELS")
+		for _, p := range data.Providers {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%d\n", p.Type, p.Name, p.Healthy, p.ModelCount)
+		}
+	})
+}
+
+func (c *cli) listModels(ctx context.Context, args []string) error {
+	flags := newFlagSet("models list")
+	all := flags.Bool("all", false, "include disabled models")
+	positional, err := parseArgs(flags, args, "PROVIDER")
+	if err != nil {
+		return err
+	}
+	path := "/api/v1/ai/" + url.PathEscape(positional[0]) + "/models"
+	if *all {
+		path += "/all"
+	}
+
+	var data struct {
+		Models map[string]*provider.ModelConfig `json:"models"`
+	}
+	if err := c.client.do(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return err
+	}
+	models := make([]*provider.ModelConfig, 0, len(data.Models))
+	for _, model := range data.Models {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return c.print(models, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tDISPLAY NAME\tMAX TOKENS\tENABLED")
+		for _, m := range models {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%t\n", m.Name, m.DisplayName, m.MaxTokens, m.Enabled)
+		}
+	})
+}
+
+func (c *cli) setModelEnabled(enabled bool) command {
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	return func(ctx context.Context, args []string) error {
+		positional, err := parseArgs(newFlagSet("models "+action), args, "PROVIDER", "MODEL")
+		if err != nil {
+			return err
+		}
+		path := fmt.Sprintf("/api/v1/ai/%s/models/%s/%s", url.PathEscape(positional[0]), url.PathEscape(positional[1]), action)
+		if err := c.client.do(ctx, http.MethodPut, path, nil, nil); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Model %s/%s %sd\n", positional[0], positional[1], action)
+		return nil
+	}
+}
+
+func (c *cli) setKey(ctx context.Context, args []string) error {
+	flags := newFlagSet("keys set")
+	project := flags.String("project", "", "store the key for this project")
+	allow := flags.String("allow", "", "comma-separated models the key may use")
+	positional, err := parseArgs(flags, args, "PROVIDER")
+	if err != nil {
+		return err
+	}
+	key, err := c.readSecret("API key: ")
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("empty API key")
+	}
+
+	req := dto.SetAPIKeyRequest{APIKey: key}
+	if *allow != "" {
+		for _, model := range strings.Split(*allow, ",") {
+			if model = strings.TrimSpace(model); model != "" {
+				req.AllowedModels = append(req.AllowedModels, model)
+			}
+		}
+	}
+	path := "/api/v1/ai/" + url.PathEscape(positional[0]) + "/api-key"
+	if *project != "" {
+		path += "?project=" + url.QueryEscape(*project)
+	}
+	if err := c.client.do(ctx, http.MethodPost, path, req, nil); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "API key for %s saved\n", positional[0])
+	return nil
+}
+
+func (c *cli) listTools(ctx context.Context, args []string) error {
+	if _, err := parseArgs(newFlagSet("tools list"), args); err != nil {
+		return err
+	}
+	var data dto.MCPToolsResponse
+	if err := c.client.do(ctx, http.MethodGet, "/api/v1/mcp/tools", nil, &data); err != nil {
+		return err
+	}
+	return c.print(data.Tools, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tDESCRIPTION")
+		for _, tool := range data.Tools {
+			fmt.Fprintf(w, "%s\t%s\n", tool.Name, tool.Description)
+		}
+	})
+}
+
+func (c *cli) runTool(ctx context.Context, args []string) error {
+	flags := newFlagSet("tools run")
+	arguments := flags.String("args", "{}", "tool arguments as a JSON object")
+	positional, err := parseArgs(flags, args, "NAME")
+	if err != nil {
+		return err
+	}
+	req := dto.MCPExecuteRequest{Name: positional[0]}
+	if err := json.Unmarshal([]byte(*arguments), &req.Arguments); err != nil {
+		return fmt.Errorf("-args must be a JSON object: %w", err)
+	}
+
+	var result dto.MCPExecuteResponse
+	if err := c.client.do(ctx, http.MethodPost, "/api/v1/mcp/execute", req, &result); err != nil {
+		return err
+	}
+	if c.json {
+		return c.print(result, nil)
+	}
+	for _, content := range result.Content {
+		if content.Text != "" {
+			fmt.Fprintln(c.out, content.Text)
+		} else if err := c.print(content, nil); err != nil {
+			return err
+		}
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s reported an error", req.Name)
+	}
+	return nil
+}
+
+func (c *cli) selfTest(ctx context.Context, args []string) error {
+	flags := newFlagSet("tools selftest")
+	tool := flags.String("tool", "", "also execute this tool to check its result and notifications")
+	arguments := flags.String("args", "{}", "arguments for -tool as a JSON object")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	req := dto.MCPSelfTestRequest{Tool: *tool}
+	if err := json.Unmarshal([]byte(*arguments), &req.Arguments); err != nil {
+		return fmt.Errorf("-args must be a JSON object: %w", err)
+	}
+
+	var report dto.MCPSelfTestReport
+	if err := c.client.do(ctx, http.MethodPost, "/api/v1/mcp/selftest", req, &report); err != nil {
+		return err
+	}
+	if err := c.print(report, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+		for _, check := range report.Checks {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, check.Status, check.Detail)
+			for _, violation := range check.Violations {
+				fmt.Fprintf(w, "\t\t%s\n", violation)
+			}
+			for _, warning := range check.Warnings {
+				fmt.Fprintf(w, "\t\twarning: %s\n", warning)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("MCP self-test found protocol violations")
+	}
+	return nil
+}
+
+func (c *cli) tailLogs(ctx context.Context, args []string) error {
+	flags := newFlagSet("logs tail")
+	user := flags.String("user", "", "only logs of this user ID")
+	limit := flags.Int("n", 20, "number of recent logs to print (max 100)")
+	follow := flags.Bool("f", false, "keep polling for new logs")
+	interval := flags.Duration("interval", 2*time.Second, "polling interval with -f")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *user != "" {
+		query.Set("user_id", *user)
+	}
+	fetch := func(n int) ([]*dto.MCPToolExecutionLog, error) {
+		query.Set("limit", fmt.Sprint(n))
+		var data struct {
+			Logs []*dto.MCPToolExecutionLog `json:"logs"`
+		}
+		err := c.client.do(ctx, http.MethodGet, "/api/v1/mcp/logs?"+query.Encode(), nil, &data)
+		return data.Logs, err
+	}
+
+	logs, err := fetch(*limit)
+	if err != nil {
+		return err
+	}
+	printed := make(map[string]bool)
+	c.printLogs(logs, printed, false)
+	if !*follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		logs, err := fetch(100)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		c.printLogs(logs, printed, true)
+	}
+}
+
+// printLogs 按开始时间从旧到新输出未输出过的日志；finishedOnly 时跳过仍在执行的日志，等结束后再输出
+func (c *cli) printLogs(logs []*dto.MCPToolExecutionLog, printed map[string]bool, finishedOnly bool) {
+	sort.Slice(logs, func(i, j int) bool { return logs[i].StartTime.Before(logs[j].StartTime) })
+	for _, log := range logs {
+		if printed[log.ID] || (finishedOnly && log.EndTime == nil) {
+			continue
+		}
+		printed[log.ID] = true
+		if c.json {
+			data, _ := json.Marshal(log)
+			fmt.Fprintln(c.out, string(data))
+			continue
+		}
+		fmt.Fprintln(c.out, formatLog(log))
+	}
+}
+
+// formatLog 单行输出执行日志：开始时间、工具、耗时、用户、结果和日志ID
+func formatLog(log *dto.MCPToolExecutionLog) string {
+	duration := "running"
+	if log.Duration != nil {
+		duration = log.Duration.Round(time.Millisecond).String()
+	}
+	user := "-"
+	if log.UserID != nil {
+		user = *log.UserID
+	}
+	status := "ok"
+	if log.Error != nil {
+		status = "error: " + log.Error.Message
+	} else if log.Result != nil && log.Result.IsError {
+		status = "error"
+	}
+	return fmt.Sprintf("%s  %-20s %8s  user=%s  %s  %s", log.StartTime.Local().Format(time.RFC3339), log.ToolName, duration, user, status, log.ID)
+}
+
+func (c *cli) listUsers(ctx context.Context, args []string) error {
+	flags := newFlagSet("users list")
+	search := flags.String("search", "", "username or email substring")
+	cursor := flags.String("cursor", "", "cursor printed by the previous page")
+	limit := flags.Int("limit", 20, "users per page")
+	deleted := flags.Bool("deleted", false, "include soft deleted users")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(*limit))
+	if *cursor != "" {
+		query.Set("cursor", *cursor)
+	}
+	if *search != "" {
+		query.Set("search", *search)
+	}
+	if *deleted {
+		query.Set("include_deleted", "true")
+	}
+	var data dto.UserListResponse
+	if err := c.client.do(ctx, http.MethodGet, "/api/v1/admin/users?"+query.Encode(), nil, &data); err != nil {
+		return err
+	}
+	return c.print(data, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tACTIVE\tADMIN\tCREATED")
+		for _, u := range data.Users {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%t\t%s\n", u.ID, u.Username, u.Email, u.IsActive, u.IsAdmin, u.CreatedAt.Local().Format("2006-01-02"))
+		}
+		fmt.Fprintf(w, "\n%d of %d users\n", len(data.Users), data.Total)
+		if data.NextCursor != "" {
+			fmt.Fprintf(w, "next page: -cursor %s\n", data.NextCursor)
+		}
+	})
+}
+
+func (c *cli) createUser(ctx context.Context, args []string) error {
+	flags := newFlagSet("users create")
+	req := dto.CreateUserRequest{}
+	flags.StringVar(&req.Username, "username", "", "username")
+	flags.StringVar(&req.Email, "email", "", "email address")
+	flags.StringVar(&req.FullName, "full-name", "", "full name")
+	if _, err := parseArgs(flags, args); err != nil {
+		return err
+	}
+	if req.Username == "" || req.Email == "" {
+		return fmt.Errorf("users create requires -username and -email")
+	}
+	password, err := c.readSecret("Password: ")
+	if err != nil {
+		return err
+	}
+	req.Password = password
+
+	var user dto.UserResponse
+	if err := c.client.do(ctx, http.MethodPost, "/api/v1/admin/users", req, &user); err != nil {
+		return err
+	}
+	if c.json {
+		return c.print(user, nil)
+	}
+	fmt.Fprintf(c.out, "Created user %s (id %d)\n", user.Username, user.ID)
+	return nil
+}
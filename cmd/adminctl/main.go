@@ -0,0 +1,100 @@
+// adminctl 管理命令行工具，通过HTTP接口完成常用的管理操作
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+const usage = `Usage: adminctl [-server URL] [-json] <command> [flags] [args]
+
+Commands:
+  login -username NAME                  log in; the password is read from standard input
+  logout                                revoke the stored session
+  providers                             list AI providers
+  models list [-all] PROVIDER           list enabled (or all) models of a provider
+  models enable PROVIDER MODEL          enable a model
+  models disable PROVIDER MODEL         disable a model
+  keys set [-project NAME] [-allow M1,M2] PROVIDER
+                                        store an API key read from standard input
+  tools list                            list MCP tools
+  tools run [-args JSON] NAME           execute an MCP tool
+  tools selftest [-tool NAME] [-args JSON]
+                                        check the MCP server against the protocol spec
+  logs tail [-user ID] [-n N] [-f] [-interval D]
+                                        print recent tool execution logs, -f keeps polling
+  users list [-search TEXT] [-cursor CURSOR] [-limit N] [-deleted]
+                                        list users
+  users create -username NAME -email EMAIL [-full-name NAME]
+                                        create a user; the password is read from standard input
+
+Credentials are stored in $ADMINCTL_CONFIG or <user config dir>/adminctl/credentials.json.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "adminctl:", err)
+		os.Exit(1)
+	}
+}
+
+// run 解析全局参数并执行子命令
+func run(args []string) error {
+	flags := flag.NewFlagSet("adminctl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	server := flags.String("server", "", "server URL (default: the stored server or "+defaultServer+")")
+	jsonOutput := flags.Bool("json", false, "print JSON instead of tables")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	creds, err := loadCredentials(path)
+	if err != nil {
+		return err
+	}
+	if *server != "" && strings.TrimRight(*server, "/") != strings.TrimRight(creds.Server, "/") {
+		// 令牌只对签发它的服务器有效
+		creds = &credentials{Server: *server}
+	}
+
+	c := &cli{client: newClient(creds, path), in: bufio.NewReader(os.Stdin), out: os.Stdout, json: *jsonOutput}
+	name, cmd, rest := lookupCommand(c.commands(), flags.Args())
+	if cmd == nil {
+		flags.Usage()
+		if name == "" {
+			return fmt.Errorf("expected a command")
+		}
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return cmd(ctx, rest)
+}
+
+// lookupCommand 按一级或两级命令名查找子命令，返回命令名、子命令和剩余参数
+func lookupCommand(commands map[string]command, args []string) (string, command, []string) {
+	if len(args) == 0 {
+		return "", nil, nil
+	}
+	if len(args) > 1 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return args[0] + " " + args[1], cmd, args[2:]
+		}
+	}
+	return args[0], commands[args[0]], args[1:]
+}
@@ -2,8 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"go-springAi/internal/config"
 	"go-springAi/internal/dto"
 	"go-springAi/internal/logger"
 	"go-springAi/internal/wire"
@@ -12,6 +20,23 @@ import (
 )
 
 func main() {
+	// migrate 子命令只执行数据库迁移，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --sandbox 以沙箱模式运行，无需凭据也不访问外部网络
+	flags := flag.NewFlagSet("go-springAi", flag.ExitOnError)
+	sandboxMode := flags.Bool("sandbox", false, "serve AI providers from the mock provider and market data from fixtures, and block all other outbound HTTP")
+	flags.Parse(os.Args[1:])
+	if *sandboxMode {
+		config.EnableSandbox()
+	}
+
 	// 使用wire初始化应用
 	app, cleanup, err := wire.InitializeApp(".")
 	if err != nil {
@@ -41,13 +66,107 @@ func main() {
 		logger.Module(logger.ModuleServer),
 		logger.Operation(logger.OpStart))
 
-	if err := router.Run(addr); err != nil {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+	redirectSrv, err := configureTLS(srv, app.Config.Server)
+	if err != nil {
+		logger.Fatal(logger.MsgServerError,
+			logger.ZapError(err),
+			logger.Module(logger.ModuleServer),
+			logger.Operation(logger.OpStart))
+	}
+	// 开始关闭时先断开SSE客户端，否则长连接会一直占用关闭等待时间
+	if app.MCPService != nil {
+		srv.RegisterOnShutdown(app.MCPService.CloseSSEClients)
+	}
+
+	serverErr := make(chan error, 3)
+	go func() {
+		if err := serve(srv); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	if redirectSrv != nil {
+		logger.Info(logger.MsgServerStarting,
+			logger.String("address", redirectSrv.Addr),
+			logger.String("message", "Redirecting HTTP to HTTPS"),
+			logger.Module(logger.ModuleServer),
+			logger.Operation(logger.OpStart))
+		go func() {
+			if err := redirectSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+		}()
+	}
+	if app.GRPCServer.Enabled() {
+		logger.Info(logger.MsgServerStarting,
+			logger.String("address", app.GRPCServer.Addr()),
+			logger.String("message", "Serving gRPC"),
+			logger.Module(logger.ModuleServer),
+			logger.Operation(logger.OpStart))
+		go func() {
+			if err := app.GRPCServer.ListenAndServe(); err != nil {
+				serverErr <- err
+			}
+		}()
+	}
+
+	// 等待退出信号，收到信号后恢复默认处理，再次发送信号可强制退出
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
 		logger.Fatal(logger.MsgServerError,
 			logger.ZapError(err),
 			logger.String("address", addr),
 			logger.Module(logger.ModuleServer),
 			logger.Operation(logger.OpStart))
+	case <-ctx.Done():
+	}
+	stop()
+
+	shutdown(srv, redirectSrv, app)
+}
+
+// shutdown 优雅关闭服务器和HTTP重定向服务器：停止接收新连接并断开SSE客户端，
+// 在超时前等待进行中的请求和工具执行结束，之后由 cleanup 释放资源
+func shutdown(srv, redirectSrv *http.Server, app *wire.App) {
+	timeout := time.Duration(app.Config.Server.ShutdownTimeout) * time.Second
+	logger.Info(logger.MsgServerStopping,
+		logger.Duration("timeout", timeout),
+		logger.Module(logger.ModuleServer),
+		logger.Operation(logger.OpStop))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn(logger.MsgServerError,
+			logger.ZapError(err),
+			logger.String("message", "Timed out waiting for in-flight requests"),
+			logger.Module(logger.ModuleServer),
+			logger.Operation(logger.OpStop))
+	}
+
+	if redirectSrv != nil {
+		redirectSrv.Shutdown(ctx)
 	}
+	app.GRPCServer.Shutdown(ctx)
+
+	if app.MCPService != nil {
+		if err := app.MCPService.WaitForExecutions(ctx); err != nil {
+			logger.Warn(logger.MsgServerError,
+				logger.ZapError(err),
+				logger.String("message", "Timed out waiting for tool executions"),
+				logger.Module(logger.ModuleServer),
+				logger.Operation(logger.OpStop))
+		}
+	}
+
+	logger.Info(logger.MsgServerStopped,
+		logger.Module(logger.ModuleServer),
+		logger.Operation(logger.OpStop))
 }
 
 // initializeMCPSystem 自动初始化MCP系统
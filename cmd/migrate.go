@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"go-springAi/internal/config"
+	"go-springAi/internal/database"
+	"go-springAi/internal/wire"
+	"go-springAi/schemas"
+)
+
+const migrateUsage = `Usage: go-springAi migrate [-config DIR] <command>
+
+Commands:
+  up [N]             apply pending migrations (all by default)
+  down [N]           roll back the last N applied migrations (1 by default)
+  status             list migrations and when they were applied
+  baseline [VERSION] mark migrations up to VERSION (all by default) as applied without running them
+`
+
+// runMigrate 执行 migrate 子命令，使用配置中的数据库和内嵌的迁移文件
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), migrateUsage) }
+	configPath := flags.String("config", ".", "directory containing config.yaml")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() == 0 || flags.NArg() > 2 {
+		flags.Usage()
+		return fmt.Errorf("expected a command")
+	}
+	command, arg := flags.Arg(0), flags.Arg(1)
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	db, err := wire.ProvideDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, schemas.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		steps, err := parseSteps(arg, 0)
+		if err != nil {
+			return err
+		}
+		applied, err := migrator.Up(ctx, steps)
+		printVersions("Applied", applied)
+		return err
+	case "down":
+		steps, err := parseSteps(arg, 1)
+		if err != nil {
+			return err
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		printVersions("Rolled back", rolledBack)
+		return err
+	case "baseline":
+		marked, err := migrator.Baseline(ctx, arg)
+		printVersions("Marked as applied", marked)
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED AT")
+		for _, migration := range status {
+			appliedAt := "pending"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", migration.Version, appliedAt)
+		}
+		return w.Flush()
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// parseSteps 解析迁移数量参数
+func parseSteps(arg string, def int) (int, error) {
+	if arg == "" {
+		return def, nil
+	}
+	steps, err := strconv.Atoi(arg)
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", arg)
+	}
+	return steps, nil
+}
+
+func printVersions(action string, versions []string) {
+	if len(versions) == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+	for _, version := range versions {
+		fmt.Printf("%s %s\n", action, version)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"go-springAi/internal/config"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// configureTLS 按配置为服务器设置证书，返回监听HTTP并重定向到HTTPS的服务器，未配置 http_addr 时为 nil
+func configureTLS(srv *http.Server, cfg config.ServerConfig) (*http.Server, error) {
+	tlsCfg := cfg.TLS
+	if !tlsCfg.Enabled {
+		return nil, nil
+	}
+
+	redirect := httpsRedirectHandler(cfg.Port)
+	if tlsCfg.Autocert.Enabled {
+		if len(tlsCfg.Autocert.Hosts) == 0 {
+			return nil, fmt.Errorf("server.tls.autocert.hosts must list at least one host")
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(tlsCfg.Autocert.Hosts...),
+			Cache:      autocert.DirCache(tlsCfg.Autocert.CacheDir),
+			Email:      tlsCfg.Autocert.Email,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		// HTTP端口同时处理ACME HTTP-01验证
+		redirect = manager.HTTPHandler(redirect)
+	} else {
+		if tlsCfg.CertFile == "" || tlsCfg.KeyFile == "" {
+			return nil, fmt.Errorf("server.tls.cert_file and server.tls.key_file are required when autocert is disabled")
+		}
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS certificate: %w", err)
+		}
+		srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	srv.TLSConfig.MinVersion = tls.VersionTLS12
+
+	if tlsCfg.HTTPAddr == "" {
+		return nil, nil
+	}
+	return &http.Server{Addr: tlsCfg.HTTPAddr, Handler: redirect}, nil
+}
+
+// httpsRedirectHandler 将HTTP请求永久重定向到HTTPS，HTTPS端口不是443时保留端口
+func httpsRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
+// serve 启动服务器，设置了证书时使用HTTPS
+func serve(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	return srv.ListenAndServe()
+}
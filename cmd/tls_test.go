@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		target    string
+		want      string
+	}{
+		{name: "Default HTTPS port", httpsPort: "443", target: "http://api.example.com/api/projects?page=2", want: "https://api.example.com/api/projects?page=2"},
+		{name: "HTTP port is dropped", httpsPort: "443", target: "http://api.example.com:80/health", want: "https://api.example.com/health"},
+		{name: "Custom HTTPS port is kept", httpsPort: "8443", target: "http://localhost:8080/health", want: "https://localhost:8443/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			httpsRedirectHandler(tt.httpsPort).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestConfigureTLS(t *testing.T) {
+	tests := []struct {
+		name         string
+		tls          config.TLSConfig
+		wantErr      bool
+		wantTLS      bool
+		wantRedirect bool
+	}{
+		{name: "Disabled", tls: config.TLSConfig{HTTPAddr: ":80"}},
+		{name: "Missing certificate files", tls: config.TLSConfig{Enabled: true}, wantErr: true},
+		{name: "Unreadable certificate", tls: config.TLSConfig{Enabled: true, CertFile: "missing.pem", KeyFile: "missing-key.pem"}, wantErr: true},
+		{name: "Autocert without hosts", tls: config.TLSConfig{Enabled: true, Autocert: config.AutocertConfig{Enabled: true}}, wantErr: true},
+		{name: "Autocert with redirect", tls: config.TLSConfig{Enabled: true, HTTPAddr: ":80", Autocert: config.AutocertConfig{Enabled: true, Hosts: []string{"api.example.com"}, CacheDir: t.TempDir()}}, wantTLS: true, wantRedirect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &http.Server{}
+			redirect, err := configureTLS(srv, config.ServerConfig{Port: "443", TLS: tt.tls})
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantTLS, srv.TLSConfig != nil)
+			assert.Equal(t, tt.wantRedirect, redirect != nil)
+		})
+	}
+}
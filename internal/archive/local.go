@@ -0,0 +1,118 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LocalConfig 本地目录归档配置
+type LocalConfig struct {
+	Dir string
+}
+
+// LocalStore 将归档对象保存为本地文件，对象名中的 / 对应子目录
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore 创建本地目录归档存储，目录不存在时自动创建
+func NewLocalStore(cfg LocalConfig) (*LocalStore, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("archive directory is required")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+	return &LocalStore{dir: cfg.Dir}, nil
+}
+
+// Put 先写入临时文件再重命名，避免读取到写了一半的对象
+func (s *LocalStore) Put(ctx context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write archive %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write archive %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write archive %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write archive %s: %w", name, err)
+	}
+	return nil
+}
+
+// Get 读取对象
+func (s *LocalStore) Get(ctx context.Context, name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read archive %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// List 列出以 prefix 开头的对象，跳过临时文件
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Name: name, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list archives: %w", err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+// path 将对象名转换为目录内的文件路径，拒绝跳出归档目录的名称
+func (s *LocalStore) path(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(strings.Trim(name, "/")))
+	if clean == "." || !filepath.IsLocal(clean) {
+		return "", fmt.Errorf("invalid archive name %q", name)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
@@ -0,0 +1,189 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go-springAi/internal/secrets"
+)
+
+// S3Config S3 兼容存储配置，凭证为空时读取标准 AWS_* 环境变量
+type S3Config struct {
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // 自定义端点（如 MinIO），为空时使用区域默认端点
+	Prefix          string // 对象名前缀，如 go-springai
+}
+
+// S3Store 基于 S3 REST 接口的归档存储，使用路径风格的地址
+type S3Store struct {
+	cfg        S3Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// s3Error S3 错误响应
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+	Status  int    `xml:"-"`
+}
+
+func (e *s3Error) Error() string {
+	return fmt.Sprintf("s3 error (HTTP %d): %s: %s", e.Status, e.Code, e.Message)
+}
+
+// NewS3Store 创建 S3 兼容归档存储
+func NewS3Store(cfg S3Config, httpClient *http.Client) (*S3Store, error) {
+	if cfg.Region == "" {
+		cfg.Region = firstNonEmpty(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	}
+	if cfg.AccessKeyID == "" && cfg.SecretAccessKey == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("archive bucket is required")
+	}
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("archive region and credentials are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+
+	return &S3Store{cfg: cfg, httpClient: httpClient, now: time.Now}, nil
+}
+
+// Put 写入对象
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) error {
+	_, err := s.call(ctx, http.MethodPut, s.key(name), nil, data)
+	return err
+}
+
+// Get 读取对象
+func (s *S3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	data, err := s.call(ctx, http.MethodGet, s.key(name), nil, nil)
+	if s3Err, ok := err.(*s3Error); ok && (s3Err.Code == "NoSuchKey" || s3Err.Status == http.StatusNotFound) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// List 使用 ListObjectsV2 分页列出对象
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	query := url.Values{"list-type": {"2"}, "prefix": {s.key(prefix)}}
+	for {
+		body, err := s.call(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key  string `xml:"Key"`
+				Size int64  `xml:"Size"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("decode s3 response: %w", err)
+		}
+		for _, content := range result.Contents {
+			objects = append(objects, Object{Name: s.name(content.Key), Size: content.Size})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// key 拼接带前缀的对象键
+func (s *S3Store) key(name string) string {
+	if s.cfg.Prefix == "" {
+		return strings.TrimLeft(name, "/")
+	}
+	return s.cfg.Prefix + "/" + strings.TrimLeft(name, "/")
+}
+
+// name 去掉对象键的前缀
+func (s *S3Store) name(key string) string {
+	if s.cfg.Prefix == "" {
+		return key
+	}
+	return strings.TrimPrefix(key, s.cfg.Prefix+"/")
+}
+
+// call 调用 S3 接口，key 为空时请求存储桶本身
+func (s *S3Store) call(ctx context.Context, method, key string, query url.Values, payload []byte) ([]byte, error) {
+	u, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive endpoint: %w", err)
+	}
+	u.Path = "/" + s.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create s3 request: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	secrets.SignV4(req, payload, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, "s3", s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send s3 request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read s3 response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s3Err := &s3Error{Status: resp.StatusCode}
+		if err := xml.Unmarshal(body, s3Err); err != nil || s3Err.Code == "" {
+			s3Err.Code = "UnknownError"
+			s3Err.Message = string(body)
+		}
+		return nil, s3Err
+	}
+	return body, nil
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 支持的归档存储后端
+const (
+	BackendNone  = ""      // 默认：不归档
+	BackendLocal = "local" // 本地目录
+	BackendS3    = "s3"
+	BackendGCS   = "gcs" // 通过 Cloud Storage 的 S3 兼容接口和 HMAC 密钥访问
+)
+
+// gcsEndpoint Cloud Storage 的 S3 兼容接口地址
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// ErrNotFound 归档对象不存在
+var ErrNotFound = errors.New("archive object not found")
+
+// Object 归档对象
+type Object struct {
+	Name string
+	Size int64
+}
+
+// Store 归档对象存储接口，name 为不含前缀的逻辑路径，如 "execution-logs/xxx.jsonl.gz"
+type Store interface {
+	// Put 写入对象，已存在时覆盖
+	Put(ctx context.Context, name string, data []byte) error
+	// Get 读取对象，不存在时返回 ErrNotFound
+	Get(ctx context.Context, name string) ([]byte, error)
+	// List 按名称顺序列出以 prefix 开头的对象
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Config 归档存储配置
+type Config struct {
+	Backend string
+	Timeout time.Duration
+	Local   LocalConfig
+	S3      S3Config
+}
+
+// New 根据配置创建归档存储，未配置后端时返回 nil 表示不归档
+func New(cfg Config) (Store, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	switch cfg.Backend {
+	case BackendNone:
+		return nil, nil
+	case BackendLocal:
+		return NewLocalStore(cfg.Local)
+	case BackendS3:
+		return NewS3Store(cfg.S3, httpClient)
+	case BackendGCS:
+		s3 := cfg.S3
+		if s3.Endpoint == "" {
+			s3.Endpoint = gcsEndpoint
+		}
+		if s3.Region == "" {
+			s3.Region = "auto"
+		}
+		return NewS3Store(s3, httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported archive backend: %s", cfg.Backend)
+	}
+}
@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeS3 内存中的 S3 路径风格接口，只校验签名头是否存在
+func fakeS3(t *testing.T) *httptest.Server {
+	objects := map[string][]byte{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/logs/")
+		switch {
+		case r.Method == http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			objects[key] = data
+		case r.Method == http.MethodGet && r.URL.Path == "/logs":
+			type content struct {
+				Key  string
+				Size int
+			}
+			var result struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []content
+			}
+			for k, v := range objects {
+				if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+					result.Contents = append(result.Contents, content{Key: k, Size: len(v)})
+				}
+			}
+			sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+			xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodGet:
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`))
+				return
+			}
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestStores(t *testing.T) {
+	server := fakeS3(t)
+	defer server.Close()
+
+	s3, err := NewS3Store(S3Config{Bucket: "logs", Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret", Endpoint: server.URL, Prefix: "/app/"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := NewLocalStore(LocalConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, store := range map[string]Store{"s3": s3, "local": local} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if _, err := store.Get(ctx, "a/missing"); err != ErrNotFound {
+				t.Fatalf("Get missing: got %v, want ErrNotFound", err)
+			}
+			if err := store.Put(ctx, "a/2", []byte("two")); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Put(ctx, "a/1", []byte("one")); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Put(ctx, "b/1", []byte("other")); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := store.Get(ctx, "a/1")
+			if err != nil || string(data) != "one" {
+				t.Fatalf("Get: got %q, %v", data, err)
+			}
+			objects, err := store.List(ctx, "a/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []Object{{Name: "a/1", Size: 3}, {Name: "a/2", Size: 3}}
+			if len(objects) != len(want) || objects[0] != want[0] || objects[1] != want[1] {
+				t.Fatalf("List: got %v, want %v", objects, want)
+			}
+		})
+	}
+
+	if err := local.Put(context.Background(), "../escape", nil); err == nil {
+		t.Fatal("expected error for name outside the archive directory")
+	}
+}
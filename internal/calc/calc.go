@@ -0,0 +1,374 @@
+// Package calc 安全的十进制表达式求值。表达式只能包含数字、运算符、常量和白名单函数，
+// 不执行任何代码；运算使用任意精度十进制数，避免二进制浮点数的舍入误差
+package calc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// MaxExpressionLength 表达式的最大字符数
+	MaxExpressionLength = 1000
+	// Precision 除法、开方等非精确运算保留的小数位数
+	Precision = 34
+	// maxDepth 括号和函数调用的最大嵌套深度
+	maxDepth = 64
+	// maxCalls 幂运算和函数调用的总次数上限，这两类运算的开销远高于四则运算
+	maxCalls = 100
+	// maxExponent 幂运算指数的绝对值上限
+	maxExponent = 10000
+	// maxDigits 中间结果整数部分的最大位数
+	maxDigits = 1000
+)
+
+// Error 表达式错误，Pos 为出错位置（从1开始的字符序号）
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("calc: position %d: %s", e.Pos, e.Msg)
+}
+
+// Evaluate 计算表达式的值。
+//
+// 支持 + - * / ^ (或 **)、括号、一元正负号和后缀百分号（x% 等于 x/100），
+// 常量 pi、e 以及 Functions 中列出的函数
+func Evaluate(expression string) (decimal.Decimal, error) {
+	if len([]rune(expression)) > MaxExpressionLength {
+		return decimal.Decimal{}, &Error{Pos: MaxExpressionLength + 1, Msg: fmt.Sprintf("expression longer than %d characters", MaxExpressionLength)}
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	p := &parser{tokens: tokens}
+	value, err := p.parseExpr()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return decimal.Decimal{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return value, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize 将表达式切分为记号
+func tokenize(expression string) ([]token, error) {
+	runes := []rune(expression)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			// 科学计数法: 1.5e-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: strings.ReplaceAll(string(runes[start:i]), "_", ""), pos: start + 1})
+		case unicode.IsLetter(r):
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(string(runes[start:i])), pos: start + 1})
+		case r == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			tokens = append(tokens, token{kind: tokenOperator, text: "^", pos: start + 1})
+			i += 2
+		case strings.ContainsRune("+-*/^%(),", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: start + 1})
+			i++
+		case r == '×' || r == '÷' || r == '−':
+			tokens = append(tokens, token{kind: tokenOperator, text: map[rune]string{'×': "*", '÷': "/", '−': "-"}[r], pos: start + 1})
+			i++
+		default:
+			return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(runes) + 1}), nil
+}
+
+// parser 递归下降求值器，边解析边计算
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	calls  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 当前记号是指定运算符时前进并返回 true
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return &Error{Pos: tok.pos, Msg: fmt.Sprintf("expected %q, got %q", op, tok.text)}
+	}
+	return nil
+}
+
+// parseExpr expr := term (('+' | '-') term)*
+func (p *parser) parseExpr() (decimal.Decimal, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return decimal.Decimal{}, &Error{Pos: p.peek().pos, Msg: "expression nested too deeply"}
+	}
+
+	left, err := p.parseTerm()
+	if err != nil {
+		return left, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case p.accept("+"):
+			right, err := p.parseTerm()
+			if err != nil {
+				return right, err
+			}
+			left = left.Add(right)
+		case p.accept("-"):
+			right, err := p.parseTerm()
+			if err != nil {
+				return right, err
+			}
+			left = left.Sub(right)
+		default:
+			return left, nil
+		}
+		if err := checkSize(left, tok.pos); err != nil {
+			return left, err
+		}
+	}
+}
+
+// parseTerm term := unary (('*' | '/') unary)*
+func (p *parser) parseTerm() (decimal.Decimal, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case p.accept("*"):
+			right, err := p.parseUnary()
+			if err != nil {
+				return right, err
+			}
+			left = trim(left.Mul(right))
+		case p.accept("/"):
+			right, err := p.parseUnary()
+			if err != nil {
+				return right, err
+			}
+			if right.IsZero() {
+				return left, &Error{Pos: tok.pos, Msg: "division by zero"}
+			}
+			left = left.DivRound(right, Precision)
+		default:
+			return left, nil
+		}
+		if err := checkSize(left, tok.pos); err != nil {
+			return left, err
+		}
+	}
+}
+
+// parseUnary unary := ('+' | '-') unary | power
+func (p *parser) parseUnary() (decimal.Decimal, error) {
+	if p.accept("-") {
+		value, err := p.parseUnary()
+		return value.Neg(), err
+	}
+	if p.accept("+") {
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+// parsePower power := postfix ('^' unary)?，右结合，-2^2 = -4
+func (p *parser) parsePower() (decimal.Decimal, error) {
+	base, err := p.parsePostfix()
+	if err != nil {
+		return base, err
+	}
+	tok := p.peek()
+	if !p.accept("^") {
+		return base, nil
+	}
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return exponent, err
+	}
+	if err := p.countCall(tok.pos); err != nil {
+		return exponent, err
+	}
+	result, err := pow(base, exponent)
+	if err != nil {
+		return result, &Error{Pos: tok.pos, Msg: err.Error()}
+	}
+	return result, nil
+}
+
+// parsePostfix postfix := primary '%'*
+func (p *parser) parsePostfix() (decimal.Decimal, error) {
+	value, err := p.parsePrimary()
+	if err != nil {
+		return value, err
+	}
+	for p.accept("%") {
+		value = trim(value.Shift(-2))
+	}
+	return value, nil
+}
+
+// parsePrimary primary := number | constant | function '(' args ')' | '(' expr ')'
+func (p *parser) parsePrimary() (decimal.Decimal, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := decimal.NewFromString(tok.text)
+		if err != nil {
+			return value, &Error{Pos: tok.pos, Msg: fmt.Sprintf("invalid number %q", tok.text)}
+		}
+		if value.Exponent() < -maxDigits {
+			return value, &Error{Pos: tok.pos, Msg: fmt.Sprintf("number %q out of range", tok.text)}
+		}
+		return value, checkSize(value, tok.pos)
+	case tokenIdent:
+		if value, ok := constants[tok.text]; ok {
+			return value, nil
+		}
+		fn, ok := Functions[tok.text]
+		if !ok {
+			return decimal.Decimal{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unknown name %q", tok.text)}
+		}
+		args, err := p.parseArgs()
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		if err := p.countCall(tok.pos); err != nil {
+			return decimal.Decimal{}, err
+		}
+		if len(args) < fn.MinArgs || (fn.MaxArgs >= 0 && len(args) > fn.MaxArgs) {
+			return decimal.Decimal{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("%s expects %s", tok.text, fn.Usage)}
+		}
+		value, err := fn.call(args)
+		if err != nil {
+			return value, &Error{Pos: tok.pos, Msg: fmt.Sprintf("%s: %v", tok.text, err)}
+		}
+		return value, checkSize(value, tok.pos)
+	case tokenOperator:
+		if tok.text == "(" {
+			value, err := p.parseExpr()
+			if err != nil {
+				return value, err
+			}
+			return value, p.expect(")")
+		}
+	}
+	return decimal.Decimal{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+}
+
+// parseArgs args := '(' (expr (',' expr)*)? ')'
+func (p *parser) parseArgs() ([]decimal.Decimal, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []decimal.Decimal
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// countCall 记录一次幂运算或函数调用
+func (p *parser) countCall(pos int) error {
+	p.calls++
+	if p.calls > maxCalls {
+		return &Error{Pos: pos, Msg: fmt.Sprintf("more than %d function calls or powers", maxCalls)}
+	}
+	return nil
+}
+
+// trim 将小数位数超过 Precision 的结果舍入，避免连乘时小数位数不断增长
+func trim(value decimal.Decimal) decimal.Decimal {
+	if value.Exponent() < -Precision {
+		return value.Round(Precision)
+	}
+	return value
+}
+
+// checkSize 限制中间结果的大小，防止超大数拖慢服务
+func checkSize(value decimal.Decimal, pos int) error {
+	if digits := value.NumDigits() + int(value.Exponent()); digits > maxDigits {
+		return &Error{Pos: pos, Msg: fmt.Sprintf("result exceeds %d digits", maxDigits)}
+	}
+	return nil
+}
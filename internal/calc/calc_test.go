@@ -0,0 +1,90 @@
+package calc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string // 保留 10 位小数后的结果
+	}{
+		{"0.1 + 0.2", "0.3"},
+		{"2 + 3 * 4", "14"},
+		{"(2 + 3) * 4", "20"},
+		{"-2 ^ 2", "-4"},
+		{"2 ^ 3 ^ 2", "512"},
+		{"2 ** 10", "1024"},
+		{"10 / 4", "2.5"},
+		{"1 / 3", "0.3333333333"},
+		{"15%", "0.15"},
+		{"200 * 7.5%", "15"},
+		{"1_000_000 * 1e-3", "1000"},
+		{"10000 × 2% ÷ 4", "50"},
+		{"sqrt(2)", "1.4142135624"},
+		{"round(2.345, 2)", "2.35"},
+		{"floor(-1.5) + ceil(1.2)", "0"},
+		{"max(1, 5, 3) - min(4, 2)", "3"},
+		{"mod(10, 3)", "1"},
+		{"ln(e)", "1"},
+		{"log10(1000)", "3"},
+		{"exp(0)", "1"},
+		{"abs(-pi)", "3.1415926536"},
+		{"compound(10000, 5%, 10)", "16288.9462677744"},
+		{"compound(10000, 12%, 1, 12)", "11268.2503013197"},
+		{"cagr(100, 200, 5)", "0.148698355"},
+		{"annualize(1%, 12)", "0.1268250301"},
+		{"pmt(0.5%, 360, 300000)", "1798.6515754583"},
+		{"pmt(0, 10, 1000)", "100"},
+		{"fv(1%, 12, 100)", "1268.2503013197"},
+		{"pv(1%, 12, 100)", "1125.5077473485"},
+		{"PI * 2", "6.2831853072"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := Evaluate(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Round(10).String())
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"", "unexpected \"end of expression\""},
+		{"1 +", "position 4"},
+		{"(1 + 2", "expected \")\""},
+		{"1 / 0", "division by zero"},
+		{"5 % 3", "unexpected \"3\""},
+		{"foo(1)", "unknown name \"foo\""},
+		{"os.exit(1)", "unknown name \"os\""},
+		{"1 ; 2", "unexpected character ';'"},
+		{"sqrt(-1)", "negative argument"},
+		{"ln(0)", "argument must be positive"},
+		{"round(1, 2, 3)", "round expects round(x[, places])"},
+		{"0 ^ 0", "0^0 is undefined"},
+		{"(-8) ^ 0.5", "negative base"},
+		{"2 ^ 100000", "exponent exceeds"},
+		{"10 ^ 5000", "result exceeds"},
+		{"1.5 ^ 9999", "result exceeds"},
+		{strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), "nested too deeply"},
+		{"1 + 1e-5000", "out of range"},
+		{"exp(701)", "out of range"},
+		{strings.Repeat("abs(1)+", 101) + "1", "more than 100"},
+		{strings.Repeat("1+", 600) + "1", "longer than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := Evaluate(tt.expression)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
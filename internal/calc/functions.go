@@ -0,0 +1,216 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Function 白名单函数，MaxArgs 为 -1 表示参数个数不限
+type Function struct {
+	MinArgs int
+	MaxArgs int
+	Usage   string
+	call    func(args []decimal.Decimal) (decimal.Decimal, error)
+}
+
+var (
+	one = decimal.NewFromInt(1)
+
+	constants = map[string]decimal.Decimal{
+		"pi": decimal.RequireFromString("3.1415926535897932384626433832795028841971693993751"),
+		"e":  decimal.RequireFromString("2.7182818284590452353602874713526624977572470936999"),
+	}
+)
+
+// Functions 表达式中可调用的函数
+var Functions = map[string]Function{
+	"abs": {MinArgs: 1, MaxArgs: 1, Usage: "abs(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return a[0].Abs(), nil
+	}},
+	"sqrt": {MinArgs: 1, MaxArgs: 1, Usage: "sqrt(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		if a[0].IsNegative() {
+			return decimal.Decimal{}, errors.New("negative argument")
+		}
+		return pow(a[0], decimal.NewFromFloat(0.5))
+	}},
+	"pow": {MinArgs: 2, MaxArgs: 2, Usage: "pow(x, y)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return pow(a[0], a[1])
+	}},
+	"exp": {MinArgs: 1, MaxArgs: 1, Usage: "exp(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		if a[0].Abs().GreaterThan(decimal.NewFromInt(700)) {
+			return decimal.Decimal{}, errors.New("argument out of range")
+		}
+		return a[0].ExpTaylor(Precision)
+	}},
+	"ln": {MinArgs: 1, MaxArgs: 1, Usage: "ln(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return ln(a[0])
+	}},
+	"log10": {MinArgs: 1, MaxArgs: 1, Usage: "log10(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		x, err := ln(a[0])
+		if err != nil {
+			return x, err
+		}
+		ten, _ := ln(decimal.NewFromInt(10))
+		return x.DivRound(ten, Precision), nil
+	}},
+	"round": {MinArgs: 1, MaxArgs: 2, Usage: "round(x[, places])", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		places, err := places(a)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		return a[0].Round(places), nil
+	}},
+	"floor": {MinArgs: 1, MaxArgs: 1, Usage: "floor(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return a[0].Floor(), nil
+	}},
+	"ceil": {MinArgs: 1, MaxArgs: 1, Usage: "ceil(x)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return a[0].Ceil(), nil
+	}},
+	"min": {MinArgs: 1, MaxArgs: -1, Usage: "min(x, ...)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.Min(a[0], a[1:]...), nil
+	}},
+	"max": {MinArgs: 1, MaxArgs: -1, Usage: "max(x, ...)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		return decimal.Max(a[0], a[1:]...), nil
+	}},
+	"mod": {MinArgs: 2, MaxArgs: 2, Usage: "mod(x, y)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		if a[1].IsZero() {
+			return decimal.Decimal{}, errors.New("division by zero")
+		}
+		return a[0].Mod(a[1]), nil
+	}},
+	// compound(本金, 每期利率, 期数[, 每期复利次数]) = p × (1 + r/m)^(n×m)
+	"compound": {MinArgs: 3, MaxArgs: 4, Usage: "compound(principal, rate, periods[, compoundsPerPeriod])", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		m := one
+		if len(a) == 4 {
+			if !a[3].IsInteger() || !a[3].IsPositive() {
+				return decimal.Decimal{}, errors.New("compoundsPerPeriod must be a positive integer")
+			}
+			m = a[3]
+		}
+		growth, err := pow(one.Add(a[1].DivRound(m, Precision)), a[2].Mul(m))
+		if err != nil {
+			return growth, err
+		}
+		return a[0].Mul(growth), nil
+	}},
+	// cagr(期初值, 期末值, 年数) = (end/start)^(1/years) - 1
+	"cagr": {MinArgs: 3, MaxArgs: 3, Usage: "cagr(start, end, years)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		if !a[0].IsPositive() || a[1].IsNegative() || !a[2].IsPositive() {
+			return decimal.Decimal{}, errors.New("start and years must be positive, end must not be negative")
+		}
+		growth, err := pow(a[1].DivRound(a[0], Precision), one.DivRound(a[2], Precision))
+		if err != nil {
+			return growth, err
+		}
+		return growth.Sub(one), nil
+	}},
+	// annualize(每期收益率, 每年期数) = (1 + r)^periods - 1
+	"annualize": {MinArgs: 2, MaxArgs: 2, Usage: "annualize(rate, periodsPerYear)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		growth, err := pow(one.Add(a[0]), a[1])
+		if err != nil {
+			return growth, err
+		}
+		return growth.Sub(one), nil
+	}},
+	// pmt(每期利率, 期数, 现值) 等额还款的每期金额
+	"pmt": {MinArgs: 3, MaxArgs: 3, Usage: "pmt(rate, periods, presentValue)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		rate, n, pv := a[0], a[1], a[2]
+		if !n.IsPositive() {
+			return decimal.Decimal{}, errors.New("periods must be positive")
+		}
+		if rate.IsZero() {
+			return pv.DivRound(n, Precision), nil
+		}
+		discount, err := pow(one.Add(rate), n.Neg())
+		if err != nil {
+			return discount, err
+		}
+		denominator := one.Sub(discount)
+		if denominator.IsZero() {
+			return decimal.Decimal{}, errors.New("rate too small for the number of periods")
+		}
+		return pv.Mul(rate).DivRound(denominator, Precision), nil
+	}},
+	// fv(每期利率, 期数, 每期投入) 定投的期末价值
+	"fv": {MinArgs: 3, MaxArgs: 3, Usage: "fv(rate, periods, payment)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		rate, n, payment := a[0], a[1], a[2]
+		if rate.IsZero() {
+			return payment.Mul(n), nil
+		}
+		growth, err := pow(one.Add(rate), n)
+		if err != nil {
+			return growth, err
+		}
+		return payment.Mul(growth.Sub(one)).DivRound(rate, Precision), nil
+	}},
+	// pv(每期利率, 期数, 每期金额) 年金现值
+	"pv": {MinArgs: 3, MaxArgs: 3, Usage: "pv(rate, periods, payment)", call: func(a []decimal.Decimal) (decimal.Decimal, error) {
+		rate, n, payment := a[0], a[1], a[2]
+		if rate.IsZero() {
+			return payment.Mul(n), nil
+		}
+		discount, err := pow(one.Add(rate), n.Neg())
+		if err != nil {
+			return discount, err
+		}
+		return payment.Mul(one.Sub(discount)).DivRound(rate, Precision), nil
+	}},
+}
+
+// FunctionUsages 返回按名称排序的函数用法，供工具描述使用
+func FunctionUsages() []string {
+	usages := make([]string, 0, len(Functions))
+	for _, fn := range Functions {
+		usages = append(usages, fn.Usage)
+	}
+	sort.Strings(usages)
+	return usages
+}
+
+// pow 计算 x^y，整数指数精确计算，小数指数保留 Precision 位小数
+func pow(x, y decimal.Decimal) (decimal.Decimal, error) {
+	if y.Abs().GreaterThan(decimal.NewFromInt(maxExponent)) {
+		return decimal.Decimal{}, fmt.Errorf("exponent exceeds %d", maxExponent)
+	}
+	if x.IsZero() {
+		if y.IsZero() {
+			return decimal.Decimal{}, errors.New("0^0 is undefined")
+		}
+		if y.IsNegative() {
+			return decimal.Decimal{}, errors.New("division by zero")
+		}
+		return decimal.Zero, nil
+	}
+	if x.IsNegative() && !y.IsInteger() {
+		return decimal.Decimal{}, errors.New("negative base with fractional exponent")
+	}
+	// 预估结果位数，避免先算出巨大的数再报错
+	if math.Log10(x.Abs().InexactFloat64())*y.InexactFloat64() > maxDigits {
+		return decimal.Decimal{}, fmt.Errorf("result exceeds %d digits", maxDigits)
+	}
+	result, err := x.PowWithPrecision(y, Precision)
+	return trim(result), err
+}
+
+// ln 自然对数
+func ln(x decimal.Decimal) (decimal.Decimal, error) {
+	if !x.IsPositive() {
+		return decimal.Decimal{}, errors.New("argument must be positive")
+	}
+	return x.Ln(Precision)
+}
+
+// places round 的小数位数参数，默认为 0
+func places(a []decimal.Decimal) (int32, error) {
+	if len(a) < 2 {
+		return 0, nil
+	}
+	if !a[1].IsInteger() || a[1].IsNegative() || a[1].GreaterThan(decimal.NewFromInt(Precision)) {
+		return 0, fmt.Errorf("places must be an integer between 0 and %d", Precision)
+	}
+	return int32(a[1].IntPart()), nil
+}
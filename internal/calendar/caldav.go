@@ -0,0 +1,359 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/vcr"
+
+	"github.com/google/uuid"
+)
+
+// icalUTCFormat iCalendar 中 UTC 时间的格式
+const icalUTCFormat = "20060102T150405Z"
+
+// icalTextEscaper 转义 iCalendar 文本值
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icalTextUnescaper 还原 iCalendar 文本值
+var icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// CalDAVConfig CalDAV 配置
+type CalDAVConfig struct {
+	URL      string // 日历集合地址，例如 https://caldav.example.com/calendars/alice/default/
+	Username string
+	Password string
+}
+
+// CalDAV CalDAV 后端，所有调用者共用配置的日历集合
+type CalDAV struct {
+	cfg        CalDAVConfig
+	location   *time.Location
+	httpClient *http.Client
+}
+
+// NewCalDAV 创建 CalDAV 后端，location 用于全天事件和不带时区的时间
+func NewCalDAV(cfg CalDAVConfig, location *time.Location) *CalDAV {
+	if !strings.HasSuffix(cfg.URL, "/") {
+		cfg.URL += "/"
+	}
+	return &CalDAV{
+		cfg:      cfg,
+		location: location,
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// multistatus WebDAV REPORT 响应中用到的字段
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Prop struct {
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// Events 通过 calendar-query 列出事件，由服务端展开重复事件
+func (c *CalDAV) Events(ctx context.Context, owner string, from, to time.Time, max int) ([]dto.CalendarEvent, bool, error) {
+	start, end := from.UTC().Format(icalUTCFormat), to.UTC().Format(icalUTCFormat)
+	query := `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:calendar-data><C:expand start="` + start + `" end="` + end + `"/></C:calendar-data>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT"><C:time-range start="` + start + `" end="` + end + `"/></C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+	data, err := c.do(ctx, "REPORT", c.cfg.URL, "application/xml; charset=utf-8", []byte(query), map[string]string{"Depth": "1"})
+	if err != nil {
+		return nil, false, err
+	}
+	var status multistatus
+	if err := xml.Unmarshal(data, &status); err != nil {
+		return nil, false, fmt.Errorf("calendar: parse caldav response: %w", err)
+	}
+
+	var events []dto.CalendarEvent
+	for _, response := range status.Responses {
+		for _, propstat := range response.Propstat {
+			if propstat.Prop.CalendarData == "" {
+				continue
+			}
+			for _, event := range parseICalEvents(propstat.Prop.CalendarData, c.location) {
+				event.URL = c.resolve(response.Href)
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	if len(events) > max {
+		return events[:max], true, nil
+	}
+	return events, false, nil
+}
+
+// Create 以新的 UID 保存事件，设置了提醒时附带显示提醒
+func (c *CalDAV) Create(ctx context.Context, owner string, event dto.CalendarEvent) (*dto.CalendarEvent, error) {
+	event.ID = uuid.NewString()
+	target := c.cfg.URL + event.ID + ".ics"
+	headers := map[string]string{"If-None-Match": "*"}
+	if _, err := c.do(ctx, http.MethodPut, target, "text/calendar; charset=utf-8", []byte(formatICalEvent(event, c.location)), headers); err != nil {
+		return nil, err
+	}
+	event.URL = target
+	return &event, nil
+}
+
+// resolve 把响应中的 href 转为完整地址
+func (c *CalDAV) resolve(href string) string {
+	base, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// do 发送带基本认证的 WebDAV 请求
+func (c *CalDAV) do(ctx context.Context, method, endpoint, contentType string, body []byte, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("calendar: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("calendar: read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("calendar: caldav server returned %d: %s", resp.StatusCode, truncate(string(bytes.TrimSpace(data)), 200))
+	}
+	return data, nil
+}
+
+// formatICalEvent 生成只包含一个事件的 iCalendar 文本
+func formatICalEvent(event dto.CalendarEvent, loc *time.Location) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-springAi//calendar//EN",
+		"BEGIN:VEVENT",
+		"UID:" + event.ID,
+		"DTSTAMP:" + time.Now().UTC().Format(icalUTCFormat),
+	}
+	if event.AllDay {
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+event.Start.In(loc).Format("20060102"),
+			"DTEND;VALUE=DATE:"+event.End.In(loc).Format("20060102"))
+	} else {
+		lines = append(lines,
+			"DTSTART:"+event.Start.UTC().Format(icalUTCFormat),
+			"DTEND:"+event.End.UTC().Format(icalUTCFormat))
+	}
+	lines = append(lines, "SUMMARY:"+icalTextEscaper.Replace(event.Title))
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+icalTextEscaper.Replace(event.Description))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+icalTextEscaper.Replace(event.Location))
+	}
+	if event.ReminderMinutes > 0 {
+		lines = append(lines,
+			"BEGIN:VALARM",
+			"ACTION:DISPLAY",
+			"DESCRIPTION:"+icalTextEscaper.Replace(event.Title),
+			fmt.Sprintf("TRIGGER:-PT%dM", event.ReminderMinutes),
+			"END:VALARM")
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(foldICalLine(line))
+		sb.WriteString("\r\n")
+	}
+	return sb.String()
+}
+
+// foldICalLine 按 RFC 5545 把超过 75 字节的行折行，不拆开多字节字符
+func foldICalLine(line string) string {
+	var sb strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(r)
+		width += size
+	}
+	return sb.String()
+}
+
+// icalProperty iCalendar 属性
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICalProperty 解析一行属性，例如 DTSTART;TZID=Europe/Berlin:20240522T090000
+func parseICalProperty(line string) icalProperty {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	property := icalProperty{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: value}
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return property
+}
+
+// parseICalEvents 解析 iCalendar 文本中的事件，忽略无法解析开始时间的事件
+func parseICalEvents(data string, loc *time.Location) []dto.CalendarEvent {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.NewReplacer("\n ", "", "\n\t", "").Replace(data)
+
+	var events []dto.CalendarEvent
+	var event *dto.CalendarEvent
+	hasEnd, inAlarm := false, false
+	for _, line := range strings.Split(data, "\n") {
+		property := parseICalProperty(strings.TrimSpace(line))
+		switch {
+		case property.name == "BEGIN" && property.value == "VEVENT":
+			event, hasEnd = &dto.CalendarEvent{}, false
+		case property.name == "BEGIN" && property.value == "VALARM":
+			inAlarm = true
+		case property.name == "END" && property.value == "VALARM":
+			inAlarm = false
+		case property.name == "END" && property.value == "VEVENT":
+			if event != nil && !event.Start.IsZero() {
+				if !hasEnd {
+					event.End = event.Start
+					if event.AllDay {
+						event.End = event.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *event)
+			}
+			event = nil
+		case event == nil:
+		case inAlarm:
+			if property.name == "TRIGGER" && property.params["VALUE"] != "DATE-TIME" && strings.HasPrefix(property.value, "-") {
+				if minutes, ok := parseICalDuration(property.value[1:]); ok && (event.ReminderMinutes == 0 || minutes < event.ReminderMinutes) {
+					event.ReminderMinutes = minutes
+				}
+			}
+		case property.name == "UID":
+			event.ID = property.value
+		case property.name == "SUMMARY":
+			event.Title = icalTextUnescaper.Replace(property.value)
+		case property.name == "DESCRIPTION":
+			event.Description = icalTextUnescaper.Replace(property.value)
+		case property.name == "LOCATION":
+			event.Location = icalTextUnescaper.Replace(property.value)
+		case property.name == "DTSTART":
+			if start, allDay, ok := parseICalTime(property, loc); ok {
+				event.Start, event.AllDay = start, allDay
+			}
+		case property.name == "DTEND":
+			if end, _, ok := parseICalTime(property, loc); ok {
+				event.End, hasEnd = end, true
+			}
+		}
+	}
+	return events
+}
+
+// parseICalTime 解析日期或时间，第二个返回值表示是否为全天日期
+func parseICalTime(property icalProperty, loc *time.Location) (time.Time, bool, bool) {
+	value := property.value
+	if property.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err == nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalUTCFormat, value)
+		return t, false, err == nil
+	}
+	if tzid := property.params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err == nil
+}
+
+// parseICalDuration 把 iCalendar 时长 (例如 PT15M、P1D、P1W) 转为分钟
+func parseICalDuration(value string) (int, bool) {
+	value = strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(value, "P") {
+		return 0, false
+	}
+	minutes, number := 0, ""
+	units := map[byte]int{'W': 7 * 24 * 60, 'D': 24 * 60, 'H': 60, 'M': 1, 'S': 0}
+	for i := 1; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == 'T':
+		case ch >= '0' && ch <= '9':
+			number += string(ch)
+		default:
+			unit, ok := units[ch]
+			n, err := strconv.Atoi(number)
+			if !ok || err != nil {
+				return 0, false
+			}
+			minutes += n * unit
+			number = ""
+		}
+	}
+	return minutes, number == ""
+}
+
+// truncate 截断过长的错误信息
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
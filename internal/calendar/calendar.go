@@ -0,0 +1,30 @@
+// Package calendar 通过 Google Calendar API 或 CalDAV 读取和创建日历事件。
+package calendar
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-springAi/internal/dto"
+)
+
+// 日历后端类型
+const (
+	ProviderGoogle = "google"
+	ProviderCalDAV = "caldav"
+)
+
+// MaxReminderMinutes 提醒最多提前的分钟数，与 Google Calendar 的限制一致
+const MaxReminderMinutes = 4 * 7 * 24 * 60
+
+// ErrOwnerRequired 需要代表调用者访问日历但调用者没有邮箱
+var ErrOwnerRequired = errors.New("calendar: caller email is required")
+
+// Calendar 日历后端。owner 为调用者的账号邮箱，后端按配置决定访问其个人日历还是共享日历
+type Calendar interface {
+	// Events 返回与 [from, to) 有交集的事件，按开始时间排序，最多 max 个，第二个返回值表示是否还有更多事件
+	Events(ctx context.Context, owner string, from, to time.Time, max int) ([]dto.CalendarEvent, bool, error)
+	// Create 创建事件并返回保存后的事件
+	Create(ctx context.Context, owner string, event dto.CalendarEvent) (*dto.CalendarEvent, error)
+}
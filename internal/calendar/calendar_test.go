@@ -0,0 +1,171 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogle(t *testing.T) {
+	var created map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token-for-ann@acme.com", r.Header.Get("Authorization"))
+		assert.Equal(t, "true", r.URL.Query().Get("singleEvents"))
+		assert.Equal(t, "2024-05-01T00:00:00Z", r.URL.Query().Get("timeMin"))
+		w.Write([]byte(`{"items":[
+			{"id":"e1","summary":"NVDA earnings","start":{"date":"2024-05-22"},"end":{"date":"2024-05-23"},"reminders":{"useDefault":false,"overrides":[{"method":"email","minutes":1440},{"method":"popup","minutes":60}]}},
+			{"id":"e2","status":"cancelled","summary":"Cancelled","start":{"dateTime":"2024-05-02T10:00:00Z"},"end":{"dateTime":"2024-05-02T11:00:00Z"}},
+			{"id":"e3","summary":"Standup","htmlLink":"https://calendar.google.com/e3","start":{"dateTime":"2024-05-02T09:00:00-04:00"},"end":{"dateTime":"2024-05-02T09:15:00-04:00"}}
+		],"nextPageToken":"more"}`))
+	})
+	mux.HandleFunc("POST /calendars/primary/events", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.Write([]byte(`{"id":"new","htmlLink":"https://calendar.google.com/new","summary":"NVDA earnings","start":{"date":"2024-05-22"},"end":{"date":"2024-05-23"},"reminders":{"useDefault":false,"overrides":[{"method":"popup","minutes":1440}]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	token := func(ctx context.Context, subject string) (string, error) { return "token-for-" + subject, nil }
+	google := NewGoogle(GoogleConfig{ImpersonateCaller: true}, token, ny)
+	google.baseURL = server.URL
+	ctx := context.Background()
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	events, more, err := google.Events(ctx, "ann@acme.com", from, from.AddDate(0, 1, 0), 10)
+	require.NoError(t, err)
+	assert.True(t, more)
+	require.Len(t, events, 2)
+	assert.True(t, events[0].AllDay)
+	assert.Equal(t, time.Date(2024, 5, 22, 0, 0, 0, 0, ny), events[0].Start)
+	assert.Equal(t, 60, events[0].ReminderMinutes)
+	assert.Equal(t, "https://calendar.google.com/e3", events[1].URL)
+	assert.Equal(t, 15*time.Minute, events[1].End.Sub(events[1].Start))
+
+	_, _, err = google.Events(ctx, "", from, from.AddDate(0, 1, 0), 10)
+	assert.ErrorIs(t, err, ErrOwnerRequired)
+
+	event, err := google.Create(ctx, "ann@acme.com", dto.CalendarEvent{
+		Title:           "NVDA earnings",
+		Start:           time.Date(2024, 5, 22, 0, 0, 0, 0, ny),
+		End:             time.Date(2024, 5, 23, 0, 0, 0, 0, ny),
+		AllDay:          true,
+		ReminderMinutes: 1440,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "new", event.ID)
+	assert.Equal(t, map[string]interface{}{"date": "2024-05-22"}, created["start"])
+	assert.Equal(t, map[string]interface{}{
+		"useDefault": false,
+		"overrides":  []interface{}{map[string]interface{}{"method": "popup", "minutes": 1440.0}},
+	}, created["reminders"])
+}
+
+func TestCalDAV(t *testing.T) {
+	var report, stored string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "ann:secret", user+":"+password)
+		body, _ := io.ReadAll(r.Body)
+		switch r.Method {
+		case "REPORT":
+			assert.Equal(t, "/cal/", r.URL.Path)
+			assert.Equal(t, "1", r.Header.Get("Depth"))
+			report = string(body)
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response><d:href>/cal/b.ics</d:href><d:propstat><d:prop><cal:calendar-data>BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:b
+SUMMARY:Review\, Q2
+DTSTART;TZID=Europe/Berlin:20240503T090000
+DTEND;TZID=Europe/Berlin:20240503T100000
+BEGIN:VALARM
+TRIGGER:-PT15M
+END:VALARM
+END:VEVENT
+END:VCALENDAR
+</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+  <d:response><d:href>/cal/a.ics</d:href><d:propstat><d:prop><cal:calendar-data>BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:a
+SUMMARY:NVDA earnings
+DESCRIPTION:line one\nline
+  two
+DTSTART;VALUE=DATE:20240502
+BEGIN:VALARM
+TRIGGER:-P1D
+END:VALARM
+END:VEVENT
+END:VCALENDAR
+</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+</d:multistatus>`))
+		case http.MethodPut:
+			assert.Equal(t, "*", r.Header.Get("If-None-Match"))
+			assert.True(t, strings.HasSuffix(r.URL.Path, ".ics"))
+			stored = string(body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	caldav := NewCalDAV(CalDAVConfig{URL: server.URL + "/cal", Username: "ann", Password: "secret"}, time.UTC)
+	ctx := context.Background()
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	events, more, err := caldav.Events(ctx, "", from, from.AddDate(0, 0, 7), 10)
+	require.NoError(t, err)
+	assert.False(t, more)
+	assert.Contains(t, report, `<C:time-range start="20240501T000000Z" end="20240508T000000Z"/>`)
+	require.Len(t, events, 2)
+	assert.Equal(t, "a", events[0].ID)
+	assert.True(t, events[0].AllDay)
+	assert.Equal(t, from.AddDate(0, 0, 2), events[0].End)
+	assert.Equal(t, "line one\nline two", events[0].Description)
+	assert.Equal(t, 24*60, events[0].ReminderMinutes)
+	assert.Equal(t, server.URL+"/cal/a.ics", events[0].URL)
+	assert.Equal(t, "Review, Q2", events[1].Title)
+	assert.Equal(t, time.Date(2024, 5, 3, 7, 0, 0, 0, time.UTC), events[1].Start.UTC())
+	assert.Equal(t, 15, events[1].ReminderMinutes)
+
+	event, err := caldav.Create(ctx, "", dto.CalendarEvent{
+		Title:           "Call; with, team",
+		Start:           time.Date(2024, 5, 6, 14, 0, 0, 0, time.UTC),
+		End:             time.Date(2024, 5, 6, 14, 30, 0, 0, time.UTC),
+		ReminderMinutes: 30,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, stored, "UID:"+event.ID+"\r\n")
+	assert.Contains(t, stored, "SUMMARY:Call\\; with\\, team\r\n")
+	assert.Contains(t, stored, "DTSTART:20240506T140000Z\r\n")
+	assert.Contains(t, stored, "TRIGGER:-PT30M\r\n")
+
+	parsed := parseICalEvents(stored, time.UTC)
+	require.Len(t, parsed, 1)
+	assert.Equal(t, "Call; with, team", parsed[0].Title)
+	assert.Equal(t, 30, parsed[0].ReminderMinutes)
+}
+
+func TestFoldICalLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("财", 40)
+	folded := foldICalLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(part), 75)
+	}
+	assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+}
@@ -0,0 +1,238 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/vcr"
+)
+
+// googleCalendarAPIBase Google Calendar API 地址
+const googleCalendarAPIBase = "https://www.googleapis.com/calendar/v3"
+
+// GoogleTokenFunc 返回代表 subject 的访问令牌，subject 为空时使用服务账号自身
+type GoogleTokenFunc func(ctx context.Context, subject string) (string, error)
+
+// GoogleConfig Google Calendar 配置
+type GoogleConfig struct {
+	CalendarID        string // 日历ID，为空时使用 primary
+	ImpersonateCaller bool   // 以域范围授权代表调用者访问其日历，提醒才会发给调用者本人
+}
+
+// Google Google Calendar 后端
+type Google struct {
+	cfg        GoogleConfig
+	token      GoogleTokenFunc
+	location   *time.Location
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewGoogle 创建 Google Calendar 后端，location 用于全天事件的日期
+func NewGoogle(cfg GoogleConfig, token GoogleTokenFunc, location *time.Location) *Google {
+	if cfg.CalendarID == "" {
+		cfg.CalendarID = "primary"
+	}
+	return &Google{
+		cfg:      cfg,
+		token:    token,
+		location: location,
+		baseURL:  googleCalendarAPIBase,
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// googleEventTime Google Calendar 事件时间，全天事件只有 date
+type googleEventTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+}
+
+// googleEvent Google Calendar 事件中用到的字段
+type googleEvent struct {
+	ID          string          `json:"id,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	HTMLLink    string          `json:"htmlLink,omitempty"`
+	Summary     string          `json:"summary"`
+	Description string          `json:"description,omitempty"`
+	Location    string          `json:"location,omitempty"`
+	Start       googleEventTime `json:"start"`
+	End         googleEventTime `json:"end"`
+	Reminders   struct {
+		UseDefault bool             `json:"useDefault"`
+		Overrides  []googleReminder `json:"overrides,omitempty"`
+	} `json:"reminders"`
+}
+
+// googleReminder Google Calendar 事件提醒
+type googleReminder struct {
+	Method  string `json:"method"`
+	Minutes int    `json:"minutes"`
+}
+
+// Events 列出事件，重复事件展开为单次事件
+func (g *Google) Events(ctx context.Context, owner string, from, to time.Time, max int) ([]dto.CalendarEvent, bool, error) {
+	query := url.Values{
+		"timeMin":      {from.Format(time.RFC3339)},
+		"timeMax":      {to.Format(time.RFC3339)},
+		"singleEvents": {"true"},
+		"orderBy":      {"startTime"},
+		"maxResults":   {strconv.Itoa(max)},
+	}
+	var response struct {
+		Items         []googleEvent `json:"items"`
+		NextPageToken string        `json:"nextPageToken"`
+	}
+	if err := g.do(ctx, owner, http.MethodGet, g.eventsURL()+"?"+query.Encode(), nil, &response); err != nil {
+		return nil, false, err
+	}
+
+	events := make([]dto.CalendarEvent, 0, len(response.Items))
+	for _, item := range response.Items {
+		if item.Status == "cancelled" {
+			continue
+		}
+		event, err := g.fromGoogle(item)
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, *event)
+	}
+	return events, response.NextPageToken != "", nil
+}
+
+// Create 创建事件，设置了提醒时用弹窗提醒代替日历的默认提醒
+func (g *Google) Create(ctx context.Context, owner string, event dto.CalendarEvent) (*dto.CalendarEvent, error) {
+	request := googleEvent{
+		Summary:     event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+	}
+	if event.AllDay {
+		request.Start.Date = event.Start.In(g.location).Format(time.DateOnly)
+		request.End.Date = event.End.In(g.location).Format(time.DateOnly)
+	} else {
+		request.Start.DateTime = event.Start.Format(time.RFC3339)
+		request.End.DateTime = event.End.Format(time.RFC3339)
+	}
+	request.Reminders.UseDefault = event.ReminderMinutes <= 0
+	if event.ReminderMinutes > 0 {
+		request.Reminders.Overrides = []googleReminder{{Method: "popup", Minutes: event.ReminderMinutes}}
+	}
+
+	var response googleEvent
+	if err := g.do(ctx, owner, http.MethodPost, g.eventsURL(), request, &response); err != nil {
+		return nil, err
+	}
+	return g.fromGoogle(response)
+}
+
+// eventsURL 事件集合地址
+func (g *Google) eventsURL() string {
+	return g.baseURL + "/calendars/" + url.PathEscape(g.cfg.CalendarID) + "/events"
+}
+
+// fromGoogle 转换 Google Calendar 事件
+func (g *Google) fromGoogle(item googleEvent) (*dto.CalendarEvent, error) {
+	event := &dto.CalendarEvent{
+		ID:          item.ID,
+		Title:       item.Summary,
+		Description: item.Description,
+		Location:    item.Location,
+		URL:         item.HTMLLink,
+	}
+	var err error
+	if item.Start.Date != "" {
+		event.AllDay = true
+		if event.Start, err = time.ParseInLocation(time.DateOnly, item.Start.Date, g.location); err != nil {
+			return nil, fmt.Errorf("calendar: parse event start: %w", err)
+		}
+		if event.End, err = time.ParseInLocation(time.DateOnly, item.End.Date, g.location); err != nil {
+			return nil, fmt.Errorf("calendar: parse event end: %w", err)
+		}
+	} else {
+		if event.Start, err = time.Parse(time.RFC3339, item.Start.DateTime); err != nil {
+			return nil, fmt.Errorf("calendar: parse event start: %w", err)
+		}
+		if event.End, err = time.Parse(time.RFC3339, item.End.DateTime); err != nil {
+			return nil, fmt.Errorf("calendar: parse event end: %w", err)
+		}
+	}
+	for _, reminder := range item.Reminders.Overrides {
+		if event.ReminderMinutes == 0 || reminder.Minutes < event.ReminderMinutes {
+			event.ReminderMinutes = reminder.Minutes
+		}
+	}
+	return event, nil
+}
+
+// do 调用 Google Calendar API，代表调用者访问时 owner 不能为空
+func (g *Google) do(ctx context.Context, owner, method, endpoint string, request, response interface{}) error {
+	subject := ""
+	if g.cfg.ImpersonateCaller {
+		if owner == "" {
+			return ErrOwnerRequired
+		}
+		subject = owner
+	}
+	token, err := g.token(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("calendar: encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("calendar: create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calendar: request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("calendar: read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var payload struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		message := string(bytes.TrimSpace(data))
+		if json.Unmarshal(data, &payload) == nil && payload.Error.Message != "" {
+			message = payload.Error.Message
+		}
+		return fmt.Errorf("calendar: google calendar returned %d: %s", resp.StatusCode, message)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("calendar: parse response: %w", err)
+	}
+	return nil
+}
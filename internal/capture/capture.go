@@ -0,0 +1,308 @@
+// Package capture 保存最近若干次AI提供商调用的原始请求和响应，用于排查模型为何忽略工具等问题
+//
+// 认证相关的请求头和URL中的密钥参数会被替换为 [REDACTED]，请求体和响应体超过上限时截断。
+// 流式响应在读取时同步保存，调用方关闭响应体后记录完成。
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-springAi/internal/requestid"
+)
+
+// Redacted 替换敏感值的占位符
+const Redacted = "[REDACTED]"
+
+// DefaultMaxBodyBytes 未配置时每个请求体和响应体保存的最大字节数
+const DefaultMaxBodyBytes = 64 << 10
+
+// redactedHeaders 值被替换的请求头和响应头（小写）
+var redactedHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"x-goog-api-key":      true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// redactedParams 值被替换的查询参数（小写）
+var redactedParams = map[string]bool{
+	"key":          true,
+	"apikey":       true,
+	"api_key":      true,
+	"token":        true,
+	"access_token": true,
+}
+
+// Options 捕获配置
+type Options struct {
+	Size         int // 保留最近的调用数
+	MaxBodyBytes int // 每个请求体和响应体保存的最大字节数，0 使用 DefaultMaxBodyBytes
+}
+
+// Exchange 一次提供商调用
+type Exchange struct {
+	ID                string              `json:"id"`
+	Provider          string              `json:"provider"`
+	RequestID         string              `json:"request_id,omitempty"` // 触发调用的API请求ID
+	Method            string              `json:"method"`
+	URL               string              `json:"url"`
+	StartedAt         time.Time           `json:"started_at"`
+	DurationMs        int64               `json:"duration_ms"`
+	Completed         bool                `json:"completed"` // 响应体读取结束或调用失败
+	RequestHeaders    map[string][]string `json:"request_headers"`
+	RequestBody       string              `json:"request_body,omitempty"`
+	RequestTruncated  bool                `json:"request_truncated,omitempty"`
+	Status            int                 `json:"status,omitempty"`
+	ResponseHeaders   map[string][]string `json:"response_headers,omitempty"`
+	ResponseBody      string              `json:"response_body,omitempty"`
+	ResponseTruncated bool                `json:"response_truncated,omitempty"`
+	Error             string              `json:"error,omitempty"`
+}
+
+// Recorder 保存最近的调用，可并发使用
+type Recorder struct {
+	opts Options
+
+	mu      sync.Mutex
+	entries []*Exchange // 环形缓冲区
+	next    int         // 下一个写入位置
+	seq     uint64
+}
+
+// New 创建捕获器
+func New(opts Options) (*Recorder, error) {
+	if opts.Size <= 0 {
+		return nil, fmt.Errorf("capture: size must be positive")
+	}
+	if opts.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("capture: max body bytes must not be negative")
+	}
+	if opts.MaxBodyBytes == 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	return &Recorder{opts: opts, entries: make([]*Exchange, 0, opts.Size)}, nil
+}
+
+// defaultRecorder SetDefault 设置的全局捕获器，Transport 未指定捕获器时使用
+var defaultRecorder atomic.Pointer[Recorder]
+
+// SetDefault 设置全局捕获器，为 nil 时关闭捕获
+func SetDefault(recorder *Recorder) {
+	defaultRecorder.Store(recorder)
+}
+
+// List 返回保存的调用，最近的在前，provider 不为空时只返回该提供商的调用
+func (r *Recorder) List(provider string) []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exchanges := make([]Exchange, 0, len(r.entries))
+	for i := 1; i <= len(r.entries); i++ {
+		entry := r.entries[(r.next-i+len(r.entries))%len(r.entries)]
+		if provider == "" || entry.Provider == provider {
+			exchanges = append(exchanges, *entry)
+		}
+	}
+	return exchanges
+}
+
+// Get 按ID返回调用，已被新调用挤出时返回 false
+func (r *Recorder) Get(id string) (Exchange, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.ID == id {
+			return *entry, true
+		}
+	}
+	return Exchange{}, false
+}
+
+// Clear 删除保存的全部调用
+func (r *Recorder) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = r.entries[:0]
+	r.next = 0
+}
+
+// add 保存新调用，缓冲区满时覆盖最早的调用
+func (r *Recorder) add(entry *Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	entry.ID = strconv.FormatUint(r.seq, 10)
+	if len(r.entries) < r.opts.Size {
+		r.entries = append(r.entries, entry)
+	} else {
+		r.entries[r.next] = entry
+	}
+	r.next = (r.next + 1) % r.opts.Size
+}
+
+// update 在锁内修改已保存的调用
+func (r *Recorder) update(entry *Exchange, fn func(*Exchange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(entry)
+}
+
+// Transport 保存经过的请求和响应的 http.RoundTripper
+type Transport struct {
+	Base     http.RoundTripper // 为空时使用 http.DefaultTransport
+	Recorder *Recorder         // 为空时使用 SetDefault 设置的全局捕获器
+	Provider string            // 记录在调用上的提供商名称
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorder := t.Recorder
+	if recorder == nil {
+		recorder = defaultRecorder.Load()
+	}
+	if recorder == nil {
+		return base.RoundTrip(req)
+	}
+
+	entry := &Exchange{
+		Provider:       t.Provider,
+		RequestID:      requestid.FromContext(req.Context()),
+		Method:         req.Method,
+		URL:            redactURL(req),
+		StartedAt:      time.Now(),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	// RoundTripper 不能修改原请求，读取请求体后用副本发出
+	outgoing := req
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("capture: read request body: %w", err)
+		}
+		entry.RequestBody, entry.RequestTruncated = truncate(body, recorder.opts.MaxBodyBytes)
+		outgoing = req.Clone(req.Context())
+		outgoing.Body = io.NopCloser(bytes.NewReader(body))
+		outgoing.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	recorder.add(entry)
+
+	resp, err := base.RoundTrip(outgoing)
+	if err != nil {
+		recorder.update(entry, func(e *Exchange) {
+			e.Error = err.Error()
+			e.DurationMs = time.Since(e.StartedAt).Milliseconds()
+			e.Completed = true
+		})
+		return nil, err
+	}
+
+	recorder.update(entry, func(e *Exchange) {
+		e.Status = resp.StatusCode
+		e.ResponseHeaders = redactHeaders(resp.Header)
+	})
+	resp.Body = &teeBody{ReadCloser: resp.Body, recorder: recorder, entry: entry}
+	return resp, nil
+}
+
+// teeBody 在调用方读取响应体的同时保存内容，读到结尾或关闭时记录完成
+type teeBody struct {
+	io.ReadCloser
+	recorder *Recorder
+	entry    *Exchange
+	buf      bytes.Buffer
+	overflow bool
+	done     sync.Once
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if room := b.recorder.opts.MaxBodyBytes - b.buf.Len(); room >= n {
+			b.buf.Write(p[:n])
+		} else {
+			b.buf.Write(p[:max(room, 0)])
+			b.overflow = true
+		}
+	}
+	if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+func (b *teeBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+	return err
+}
+
+// finish 记录响应体，读取出错（非 EOF）时一并记录错误
+func (b *teeBody) finish(readErr error) {
+	b.done.Do(func() {
+		b.recorder.update(b.entry, func(e *Exchange) {
+			e.ResponseBody = b.buf.String()
+			e.ResponseTruncated = b.overflow
+			e.DurationMs = time.Since(e.StartedAt).Milliseconds()
+			e.Completed = true
+			if readErr != nil && readErr != io.EOF {
+				e.Error = readErr.Error()
+			}
+		})
+	})
+}
+
+// truncate 截断超过上限的内容
+func truncate(body []byte, limit int) (string, bool) {
+	if len(body) > limit {
+		return string(body[:limit]), true
+	}
+	return string(body), false
+}
+
+// redactHeaders 复制请求头并替换敏感值
+func redactHeaders(header http.Header) map[string][]string {
+	redacted := make(map[string][]string, len(header))
+	for name, values := range header {
+		if redactedHeaders[strings.ToLower(name)] {
+			redacted[name] = []string{Redacted}
+			continue
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// redactURL 返回替换了密钥参数和用户信息的URL
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if redactedParams[strings.ToLower(name)] {
+			query.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
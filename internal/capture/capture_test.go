@@ -0,0 +1,121 @@
+package capture
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportCapturesRedactedExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"echo":"` + string(body) + `"}`))
+	}))
+	defer server.Close()
+
+	recorder, err := New(Options{Size: 2, MaxBodyBytes: 12})
+	require.NoError(t, err)
+	client := &http.Client{Transport: &Transport{Recorder: recorder, Provider: "openai"}}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/chat?key=real&model=gpt", strings.NewReader("tools"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-real")
+	req.Header.Set("X-Goog-Api-Key", "real")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	// 响应体读完前记录尚未完成
+	pending := recorder.List("")
+	require.Len(t, pending, 1)
+	assert.False(t, pending[0].Completed)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, `{"echo":"tools"}`, string(body), "capture must not alter the response the caller reads")
+
+	got, ok := recorder.Get(pending[0].ID)
+	require.True(t, ok)
+	assert.True(t, got.Completed)
+	assert.Equal(t, "openai", got.Provider)
+	assert.Equal(t, http.StatusOK, got.Status)
+	assert.Contains(t, got.URL, "key=%5BREDACTED%5D")
+	assert.NotContains(t, got.URL, "real")
+	assert.Contains(t, got.URL, "model=gpt")
+	assert.Equal(t, []string{Redacted}, got.RequestHeaders["Authorization"])
+	assert.Equal(t, []string{Redacted}, got.RequestHeaders["X-Goog-Api-Key"])
+	assert.Equal(t, []string{Redacted}, got.ResponseHeaders["Set-Cookie"])
+	assert.Equal(t, "tools", got.RequestBody)
+	assert.False(t, got.RequestTruncated)
+	assert.Equal(t, `{"echo":"too`, got.ResponseBody)
+	assert.True(t, got.ResponseTruncated)
+}
+
+func TestRecorderKeepsLastN(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	recorder, err := New(Options{Size: 2})
+	require.NoError(t, err)
+	openai := &http.Client{Transport: &Transport{Recorder: recorder, Provider: "openai"}}
+	googleai := &http.Client{Transport: &Transport{Recorder: recorder, Provider: "googleai"}}
+
+	for _, client := range []*http.Client{openai, googleai, openai} {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	all := recorder.List("")
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"3", "2"}, []string{all[0].ID, all[1].ID}, "newest first, oldest evicted")
+	_, ok := recorder.Get("1")
+	assert.False(t, ok)
+
+	onlyOpenAI := recorder.List("openai")
+	require.Len(t, onlyOpenAI, 1)
+	assert.Equal(t, "3", onlyOpenAI[0].ID)
+
+	recorder.Clear()
+	assert.Empty(t, recorder.List(""))
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTransportRecordsErrors(t *testing.T) {
+	recorder, err := New(Options{Size: 1})
+	require.NoError(t, err)
+	client := &http.Client{Transport: &Transport{Base: failingTransport{}, Recorder: recorder}}
+
+	_, err = client.Get("http://provider.invalid/v1/models")
+	require.Error(t, err)
+
+	exchanges := recorder.List("")
+	require.Len(t, exchanges, 1)
+	assert.True(t, exchanges[0].Completed)
+	assert.Equal(t, "connection refused", exchanges[0].Error)
+	assert.Zero(t, exchanges[0].Status)
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	_, err := New(Options{})
+	assert.Error(t, err)
+	_, err = New(Options{Size: 1, MaxBodyBytes: -1})
+	assert.Error(t, err)
+
+	recorder, err := New(Options{Size: 1})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxBodyBytes, recorder.opts.MaxBodyBytes)
+}
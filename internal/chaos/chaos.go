@@ -0,0 +1,188 @@
+// Package chaos 按配置的概率随机注入延迟、5xx 错误和连接重置，用于验证重试、退避和密钥冷却逻辑
+package chaos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Header 注入故障的响应头，值为 latency、error 或 reset
+const Header = "X-Chaos-Fault"
+
+// 故障类型
+const (
+	FaultLatency = "latency"
+	FaultError   = "error"
+	FaultReset   = "reset"
+)
+
+// ErrConnectionReset 注入的连接重置错误，错误信息与真实的连接重置一致，重试逻辑可以识别
+var ErrConnectionReset = fmt.Errorf("chaos: injected fault: %w", syscall.ECONNRESET)
+
+// defaultErrorStatuses 未配置时随机使用的错误状态码
+var defaultErrorStatuses = []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+
+// Options 故障注入配置，概率取值 0-1
+type Options struct {
+	LatencyRate   float64
+	MaxLatency    time.Duration // 注入的延迟在 0 到该值之间均匀分布
+	ErrorRate     float64
+	ErrorStatuses []int // 为空时使用 500、502、503
+	ResetRate     float64
+	Paths         []string // HTTP中间件生效的路径前缀，为空时对所有请求生效
+	Seed          int64    // 非0时使用固定随机种子，便于复现
+}
+
+// Fault 一次请求要注入的故障
+type Fault struct {
+	Delay  time.Duration
+	Status int  // 非0时返回该状态码
+	Reset  bool // 为 true 时断开连接，优先于 Status
+}
+
+// Injector 故障注入器，可并发使用
+type Injector struct {
+	opts Options
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New 创建故障注入器
+func New(opts Options) (*Injector, error) {
+	rates := []struct {
+		name string
+		rate float64
+	}{{"latency", opts.LatencyRate}, {"error", opts.ErrorRate}, {"reset", opts.ResetRate}}
+	for _, r := range rates {
+		if r.rate < 0 || r.rate > 1 {
+			return nil, fmt.Errorf("chaos: %s rate must be between 0 and 1", r.name)
+		}
+	}
+	if opts.ErrorRate+opts.ResetRate > 1 {
+		return nil, fmt.Errorf("chaos: error rate and reset rate must not add up to more than 1")
+	}
+	if opts.LatencyRate > 0 && opts.MaxLatency <= 0 {
+		return nil, fmt.Errorf("chaos: max latency is required when latency rate is set")
+	}
+	for _, status := range opts.ErrorStatuses {
+		if status < 500 || status > 599 {
+			return nil, fmt.Errorf("chaos: error status %d is not a 5xx status", status)
+		}
+	}
+	if len(opts.ErrorStatuses) == 0 {
+		opts.ErrorStatuses = defaultErrorStatuses
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{opts: opts, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Applies 判断HTTP中间件是否对该路径注入故障
+func (i *Injector) Applies(path string) bool {
+	if len(i.opts.Paths) == 0 {
+		return true
+	}
+	for _, prefix := range i.opts.Paths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next 按概率抽取本次请求的故障，延迟与错误或重置相互独立
+func (i *Injector) Next() Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var fault Fault
+	if i.rand.Float64() < i.opts.LatencyRate {
+		fault.Delay = time.Duration(i.rand.Int63n(int64(i.opts.MaxLatency) + 1))
+	}
+	switch roll := i.rand.Float64(); {
+	case roll < i.opts.ResetRate:
+		fault.Reset = true
+	case roll < i.opts.ResetRate+i.opts.ErrorRate:
+		fault.Status = i.opts.ErrorStatuses[i.rand.Intn(len(i.opts.ErrorStatuses))]
+	}
+	return fault
+}
+
+// defaultInjector SetDefault 设置的全局注入器，Transport 未指定注入器时使用
+var defaultInjector atomic.Pointer[Injector]
+
+// SetDefault 设置对外部HTTP调用生效的全局注入器，为 nil 时关闭
+func SetDefault(injector *Injector) {
+	defaultInjector.Store(injector)
+}
+
+// Transport 在发出请求前注入故障的 http.RoundTripper
+type Transport struct {
+	Base     http.RoundTripper // 为空时使用 http.DefaultTransport
+	Injector *Injector         // 为空时使用 SetDefault 设置的全局注入器
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	injector := t.Injector
+	if injector == nil {
+		injector = defaultInjector.Load()
+	}
+	if injector == nil {
+		return base.RoundTrip(req)
+	}
+
+	fault := injector.Next()
+	if fault.Delay > 0 {
+		timer := time.NewTimer(fault.Delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+	switch {
+	case fault.Reset:
+		closeBody(req)
+		return nil, ErrConnectionReset
+	case fault.Status != 0:
+		closeBody(req)
+		body := fmt.Sprintf(`{"error":{"message":"chaos: injected %d","type":"server_error"}}`, fault.Status)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", fault.Status, http.StatusText(fault.Status)),
+			StatusCode:    fault.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}, Header: []string{FaultError}},
+			Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return base.RoundTrip(req)
+}
+
+// closeBody RoundTripper 不发出请求时也要关闭请求体
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,124 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "Disabled", opts: Options{}},
+		{name: "All faults", opts: Options{LatencyRate: 0.5, MaxLatency: time.Second, ErrorRate: 0.2, ResetRate: 0.1, ErrorStatuses: []int{503}}},
+		{name: "Rate above one", opts: Options{ErrorRate: 1.5}, wantErr: true},
+		{name: "Negative rate", opts: Options{ResetRate: -0.1}, wantErr: true},
+		{name: "Error and reset above one", opts: Options{ErrorRate: 0.6, ResetRate: 0.6}, wantErr: true},
+		{name: "Latency without maximum", opts: Options{LatencyRate: 0.1}, wantErr: true},
+		{name: "Non 5xx status", opts: Options{ErrorRate: 0.1, ErrorStatuses: []int{429}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInjectorNextRates(t *testing.T) {
+	injector, err := New(Options{LatencyRate: 0.5, MaxLatency: 100 * time.Millisecond, ErrorRate: 0.2, ResetRate: 0.1, Seed: 42})
+	require.NoError(t, err)
+
+	const n = 10000
+	var delayed, errored, reset int
+	for i := 0; i < n; i++ {
+		fault := injector.Next()
+		assert.LessOrEqual(t, fault.Delay, 100*time.Millisecond)
+		if fault.Delay > 0 {
+			delayed++
+		}
+		if fault.Status != 0 {
+			assert.Contains(t, defaultErrorStatuses, fault.Status)
+			errored++
+		}
+		if fault.Reset {
+			assert.Zero(t, fault.Status)
+			reset++
+		}
+	}
+	assert.InDelta(t, 0.5, float64(delayed)/n, 0.03)
+	assert.InDelta(t, 0.2, float64(errored)/n, 0.03)
+	assert.InDelta(t, 0.1, float64(reset)/n, 0.03)
+
+	// 相同种子得到相同序列
+	a, _ := New(Options{ErrorRate: 0.5, Seed: 7})
+	b, _ := New(Options{ErrorRate: 0.5, Seed: 7})
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Next(), b.Next())
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		opts       Options
+		wantStatus int
+		wantErr    error
+	}{
+		{name: "No fault", opts: Options{}, wantStatus: http.StatusOK},
+		{name: "Error", opts: Options{ErrorRate: 1, ErrorStatuses: []int{503}}, wantStatus: http.StatusServiceUnavailable},
+		{name: "Reset", opts: Options{ResetRate: 1}, wantErr: syscall.ECONNRESET},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector, err := New(tt.opts)
+			require.NoError(t, err)
+			client := &http.Client{Transport: &Transport{Injector: injector}}
+
+			resp, err := client.Post(server.URL, "text/plain", strings.NewReader("body"))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), "connection reset")
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != http.StatusOK {
+				assert.Equal(t, FaultError, resp.Header.Get(Header))
+				body, _ := io.ReadAll(resp.Body)
+				assert.Contains(t, string(body), "chaos: injected 503")
+			}
+		})
+	}
+
+	// 注入的延迟随请求取消而结束
+	injector, err := New(Options{LatencyRate: 1, MaxLatency: time.Hour, Seed: 1})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: &Transport{Injector: injector}}).Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
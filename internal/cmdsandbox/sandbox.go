@@ -0,0 +1,201 @@
+// Package cmdsandbox 在受限环境中执行命令：只允许白名单中的程序，不经过 shell 解析参数，
+// 使用干净的环境变量，并限制执行时间、CPU 时间、内存和输出大小；Linux 上可通过
+// 独立的网络命名空间禁止网络访问
+package cmdsandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认限制
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultCPUSeconds     = 10
+	DefaultMemoryMB       = 512
+	DefaultMaxOutputBytes = 64 * 1024
+)
+
+// searchPath 查找白名单程序的目录，也是子进程的 PATH
+const searchPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// ErrCommandNotAllowed 命令不在白名单中
+var ErrCommandNotAllowed = errors.New("cmdsandbox: command not allowed")
+
+// ErrIsolationUnsupported 当前平台或内核不支持网络隔离
+var ErrIsolationUnsupported = errors.New("cmdsandbox: network isolation unavailable")
+
+// Config 沙箱配置
+type Config struct {
+	AllowedCommands []string      // 命令名（在 searchPath 中查找）或绝对路径
+	WorkDir         string        // 工作目录，同时作为 HOME
+	Timeout         time.Duration // 墙钟时间上限
+	CPUSeconds      int           // CPU 时间上限（ulimit -t）
+	MemoryMB        int           // 虚拟内存上限（ulimit -v）
+	MaxOutputBytes  int           // stdout 和 stderr 各自保留的最大字节数
+	IsolateNetwork  bool          // 在独立的网络命名空间中执行，无法隔离时拒绝执行
+}
+
+// Result 执行结果，命令以非零状态退出不视为错误
+type Result struct {
+	Path      string
+	ExitCode  int
+	Stdout    string
+	Stderr    string
+	Truncated bool
+	TimedOut  bool
+	Duration  time.Duration
+}
+
+// Sandbox 命令执行沙箱
+type Sandbox struct {
+	cfg     Config
+	allowed map[string]bool
+}
+
+// New 创建沙箱，未设置的限制使用默认值
+func New(cfg Config) (*Sandbox, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.CPUSeconds <= 0 {
+		cfg.CPUSeconds = DefaultCPUSeconds
+	}
+	if cfg.MemoryMB <= 0 {
+		cfg.MemoryMB = DefaultMemoryMB
+	}
+	if cfg.MaxOutputBytes <= 0 {
+		cfg.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+	if cfg.WorkDir == "" {
+		cfg.WorkDir = os.TempDir()
+	}
+	workDir, err := filepath.Abs(cfg.WorkDir)
+	if err != nil {
+		return nil, fmt.Errorf("cmdsandbox: invalid work dir: %w", err)
+	}
+	if err := os.MkdirAll(workDir, 0o750); err != nil {
+		return nil, fmt.Errorf("cmdsandbox: create work dir: %w", err)
+	}
+	cfg.WorkDir = workDir
+
+	allowed := make(map[string]bool, len(cfg.AllowedCommands))
+	for _, command := range cfg.AllowedCommands {
+		if command = strings.TrimSpace(command); command != "" {
+			allowed[command] = true
+		}
+	}
+	return &Sandbox{cfg: cfg, allowed: allowed}, nil
+}
+
+// Allowed 返回白名单中的命令
+func (s *Sandbox) Allowed() []string {
+	return s.cfg.AllowedCommands
+}
+
+// Timeout 返回默认的墙钟时间上限
+func (s *Sandbox) Timeout() time.Duration {
+	return s.cfg.Timeout
+}
+
+// Resolve 检查命令是否在白名单中并返回程序的绝对路径
+func (s *Sandbox) Resolve(command string) (string, error) {
+	if !s.allowed[command] {
+		return "", fmt.Errorf("%w: %s", ErrCommandNotAllowed, command)
+	}
+	if strings.Contains(command, "/") {
+		if !filepath.IsAbs(command) {
+			return "", fmt.Errorf("%w: %s must be an absolute path", ErrCommandNotAllowed, command)
+		}
+		return command, nil
+	}
+	for _, dir := range filepath.SplitList(searchPath) {
+		path := filepath.Join(dir, command)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("cmdsandbox: %s not found in %s", command, searchPath)
+}
+
+// Run 执行白名单命令，timeout 为 0 或超过配置上限时使用配置的上限
+func (s *Sandbox) Run(ctx context.Context, command string, args []string, timeout time.Duration) (*Result, error) {
+	path, err := s.Resolve(command)
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 || timeout > s.cfg.Timeout {
+		timeout = s.cfg.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 通过 sh 设置资源限制后 exec 目标程序，命令和参数以位置参数传入，不经过 shell 解析
+	wrapper := []string{"-c", `ulimit -t "$1" && ulimit -v "$2" && shift 2 && exec "$@"`, "sh",
+		strconv.Itoa(s.cfg.CPUSeconds), strconv.Itoa(s.cfg.MemoryMB * 1024), path}
+	cmd := exec.CommandContext(ctx, "/bin/sh", append(wrapper, args...)...)
+	cmd.Dir = s.cfg.WorkDir
+	cmd.Env = []string{"PATH=" + searchPath, "HOME=" + s.cfg.WorkDir, "LANG=C", "LC_ALL=C"}
+	cmd.Stdin = nil
+	stdout := &limitedBuffer{limit: s.cfg.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: s.cfg.MaxOutputBytes}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second
+	if err := configure(cmd, s.cfg.IsolateNetwork); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		if s.cfg.IsolateNetwork {
+			return nil, fmt.Errorf("%w: %v", ErrIsolationUnsupported, err)
+		}
+		return nil, fmt.Errorf("cmdsandbox: start %s: %w", command, err)
+	}
+	err = cmd.Wait()
+
+	result := &Result{
+		Path:      path,
+		ExitCode:  cmd.ProcessState.ExitCode(),
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+		TimedOut:  errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Duration:  time.Since(start),
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !result.TimedOut {
+		return result, fmt.Errorf("cmdsandbox: run %s: %w", command, err)
+	}
+	return result, nil
+}
+
+// limitedBuffer 只保留前 limit 个字节的输出，超出部分丢弃但不报错，避免阻塞子进程
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
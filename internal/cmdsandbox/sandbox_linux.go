@@ -0,0 +1,26 @@
+//go:build linux
+
+package cmdsandbox
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// configure 让子进程成为独立进程组，超时时结束整个进程组；需要网络隔离时在新的用户和
+// 网络命名空间中启动，命名空间内只有未启用的回环接口
+func configure(cmd *exec.Cmd, isolateNetwork bool) error {
+	attr := &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	if isolateNetwork {
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	}
+	cmd.SysProcAttr = attr
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package cmdsandbox
+
+import "os/exec"
+
+// configure 非 Linux 平台不支持网络隔离，要求隔离时拒绝执行
+func configure(cmd *exec.Cmd, isolateNetwork bool) error {
+	if isolateNetwork {
+		return ErrIsolationUnsupported
+	}
+	return nil
+}
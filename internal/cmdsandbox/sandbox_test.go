@@ -0,0 +1,79 @@
+package cmdsandbox
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSandbox(t *testing.T, cfg Config) *Sandbox {
+	t.Helper()
+	cfg.WorkDir = t.TempDir()
+	s, err := New(cfg)
+	require.NoError(t, err)
+	return s
+}
+
+func TestRun(t *testing.T) {
+	s := newTestSandbox(t, Config{AllowedCommands: []string{"echo", "sh", "sleep", "pwd"}, MaxOutputBytes: 8})
+
+	t.Run("arguments are not interpreted by a shell", func(t *testing.T) {
+		result, err := s.Run(context.Background(), "echo", []string{"$HOME;", "`id`"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "$HOME; `", result.Stdout)
+		assert.True(t, result.Truncated)
+		assert.Equal(t, 0, result.ExitCode)
+	})
+
+	t.Run("runs in the work dir", func(t *testing.T) {
+		result, err := s.Run(context.Background(), "pwd", nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, s.cfg.WorkDir[:8], result.Stdout)
+	})
+
+	t.Run("non-zero exit is not an error", func(t *testing.T) {
+		result, err := s.Run(context.Background(), "sh", []string{"-c", "exit 3"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ExitCode)
+	})
+
+	t.Run("timeout kills the process", func(t *testing.T) {
+		start := time.Now()
+		result, err := s.Run(context.Background(), "sleep", []string{"10"}, 200*time.Millisecond)
+		require.NoError(t, err)
+		assert.True(t, result.TimedOut)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("command outside the allowlist", func(t *testing.T) {
+		_, err := s.Run(context.Background(), "id", nil, 0)
+		assert.ErrorIs(t, err, ErrCommandNotAllowed)
+		_, err = s.Run(context.Background(), "/bin/echo", nil, 0)
+		assert.ErrorIs(t, err, ErrCommandNotAllowed)
+	})
+}
+
+func TestRunIsolateNetwork(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("network isolation requires linux")
+	}
+	if err := exec.Command("unshare", "-Urn", "true").Run(); err != nil {
+		t.Skip("user namespaces unavailable")
+	}
+	s := newTestSandbox(t, Config{AllowedCommands: []string{"cat"}, IsolateNetwork: true})
+
+	// 新的网络命名空间中只有回环接口
+	result, err := s.Run(context.Background(), "cat", []string{"/proc/net/dev"}, 0)
+	if errors.Is(err, ErrIsolationUnsupported) {
+		t.Skip(err.Error())
+	}
+	require.NoError(t, err)
+	assert.Contains(t, result.Stdout, "lo:")
+	assert.NotContains(t, result.Stdout, "eth0:")
+}
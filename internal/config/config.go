@@ -7,44 +7,666 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	OpenAI   OpenAIConfig   `mapstructure:"openai"`
-	GoogleAI GoogleAIConfig `mapstructure:"googleai"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	JWT             JWTConfig             `mapstructure:"jwt"`
+	OpenAI          OpenAIConfig          `mapstructure:"openai"`
+	GoogleAI        GoogleAIConfig        `mapstructure:"googleai"`
+	Mock            MockConfig            `mapstructure:"mock"`
+	Sandbox         SandboxConfig         `mapstructure:"sandbox"`
+	LoadTest        LoadTestConfig        `mapstructure:"loadtest"`
+	HTTPFixtures    HTTPFixturesConfig    `mapstructure:"http_fixtures"`
+	ProviderCapture ProviderCaptureConfig `mapstructure:"provider_capture"`
+	Report          ReportConfig          `mapstructure:"report"`
+	Stock           StockConfig           `mapstructure:"stock"`
+	MCP             MCPConfig             `mapstructure:"mcp"`
+	User            UserConfig            `mapstructure:"user"`
+	Quota           QuotaConfig           `mapstructure:"quota"`
+	Budget          BudgetConfig          `mapstructure:"budget"`
+	APIKeys         APIKeysConfig         `mapstructure:"api_keys"`
+	Webhooks        WebhooksConfig        `mapstructure:"webhooks"`
+	Scheduler       SchedulerConfig       `mapstructure:"scheduler"`
+	Notifications   NotificationsConfig   `mapstructure:"notifications"`
+	EventBus        EventBusConfig        `mapstructure:"event_bus"`
+	Secrets         SecretsConfig         `mapstructure:"secrets"`
+	Metrics         MetricsConfig         `mapstructure:"metrics"`
+	AdminUI         AdminUIConfig         `mapstructure:"admin_ui"`
+	Log             LogConfig             `mapstructure:"log"`
+	ErrorReporting  ErrorReportingConfig  `mapstructure:"error_reporting"`
+	ErrorResponse   ErrorResponseConfig   `mapstructure:"error_response"`
+	RateLimit       RateLimitConfig       `mapstructure:"rate_limit"`
+	Compression     CompressionConfig     `mapstructure:"compression"`
+	BodyLimit       BodyLimitConfig       `mapstructure:"body_limit"`
+	Idempotency     IdempotencyConfig     `mapstructure:"idempotency"`
+	Timeout         TimeoutConfig         `mapstructure:"timeout"`
+	CORS            CORSConfig            `mapstructure:"cors"`
+	Chaos           ChaosConfig           `mapstructure:"chaos"`
 }
 
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port string `mapstructure:"port"`
-	Mode string `mapstructure:"mode"`
+	Host            string     `mapstructure:"host"`
+	Port            string     `mapstructure:"port"`
+	Mode            string     `mapstructure:"mode"`
+	ShutdownTimeout int        `mapstructure:"shutdown_timeout"` // 优雅关闭时等待进行中请求的时长（秒）
+	TrustedProxies  []string   `mapstructure:"trusted_proxies"`  // 可信反向代理的IP或CIDR，只有来自这些地址的请求才按 X-Forwarded-For 识别客户端IP，为空时使用连接地址
+	TLS             TLSConfig  `mapstructure:"tls"`
+	GRPC            GRPCConfig `mapstructure:"grpc"`
+}
+
+// GRPCConfig 与HTTP并行运行的gRPC服务配置，供内部服务调用
+type GRPCConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Port       string `mapstructure:"port"`
+	CertFile   string `mapstructure:"cert_file"` // 证书和私钥都为空时使用明文连接
+	KeyFile    string `mapstructure:"key_file"`
+	Reflection bool   `mapstructure:"reflection"` // 注册服务反射，便于 grpcurl 等工具调试
+}
+
+// TLSConfig HTTPS配置，启用 autocert 时从Let's Encrypt自动申请证书，否则使用证书文件
+type TLSConfig struct {
+	Enabled  bool           `mapstructure:"enabled"`
+	CertFile string         `mapstructure:"cert_file"`
+	KeyFile  string         `mapstructure:"key_file"`
+	HTTPAddr string         `mapstructure:"http_addr"` // 非空时在该地址监听HTTP并重定向到HTTPS，例如 ":80"
+	Autocert AutocertConfig `mapstructure:"autocert"`
+}
+
+// AutocertConfig ACME自动证书配置
+type AutocertConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Hosts    []string `mapstructure:"hosts"` // 允许申请证书的域名白名单
+	Email    string   `mapstructure:"email"`
+	CacheDir string   `mapstructure:"cache_dir"` // 证书缓存目录，重启后复用已申请的证书
 }
 
 type DatabaseConfig struct {
-	Driver string `mapstructure:"driver"`
-	DSN    string `mapstructure:"dsn"`
+	Driver                 string       `mapstructure:"driver"`
+	DSN                    string       `mapstructure:"dsn"`
+	MaxOpenConns           int          `mapstructure:"max_open_conns"`            // 0 表示不限制
+	MaxIdleConns           int          `mapstructure:"max_idle_conns"`            // 0 使用驱动默认值 2
+	ConnMaxLifetimeSeconds int          `mapstructure:"conn_max_lifetime_seconds"` // 0 表示不限制
+	ConnMaxIdleTimeSeconds int          `mapstructure:"conn_max_idle_time_seconds"`
+	SQLite                 SQLiteConfig `mapstructure:"sqlite"`
+}
+
+// SQLiteConfig 每个SQLite连接打开时设置的 PRAGMA，dsn 中已有的同名参数优先
+type SQLiteConfig struct {
+	JournalMode   string `mapstructure:"journal_mode"`    // WAL 允许读写并发
+	BusyTimeoutMS int    `mapstructure:"busy_timeout_ms"` // 数据库被锁定时的等待时间
+	ForeignKeys   bool   `mapstructure:"foreign_keys"`
+	Synchronous   string `mapstructure:"synchronous"`
 }
 
 type JWTConfig struct {
-	Secret     string `mapstructure:"secret"`
-	ExpireTime int    `mapstructure:"expire_time"`
+	Secret            string `mapstructure:"secret"`
+	ExpireTime        int    `mapstructure:"expire_time"`
+	RefreshExpireTime int    `mapstructure:"refresh_expire_time"`
+	ImpersonationTTL  int    `mapstructure:"impersonation_ttl"`
+	ReauthMaxAge      int    `mapstructure:"reauth_max_age"` // 重新认证后可执行敏感操作的时长（分钟）
 }
 
 type OpenAIConfig struct {
-	APIKey       string `mapstructure:"api_key"`
-	BaseURL      string `mapstructure:"base_url"`
-	Timeout      int    `mapstructure:"timeout"`
-	MaxRetries   int    `mapstructure:"max_retries"`
-	DefaultModel string `mapstructure:"default_model"`
+	APIKey       string   `mapstructure:"api_key"`
+	ExtraAPIKeys []string `mapstructure:"extra_api_keys"` // 与 api_key 轮换使用的附加密钥
+	BaseURL      string   `mapstructure:"base_url"`
+	Timeout      int      `mapstructure:"timeout"`
+	MaxRetries   int      `mapstructure:"max_retries"`
+	DefaultModel string   `mapstructure:"default_model"`
+	// FineTuningSyncIntervalMinutes 刷新微调任务状态并登记完成模型的间隔，0 表示关闭
+	FineTuningSyncIntervalMinutes int `mapstructure:"fine_tuning_sync_interval_minutes"`
 }
 
 type GoogleAIConfig struct {
-	APIKey       string `mapstructure:"api_key"`
-	ProjectID    string `mapstructure:"project_id"`
-	Location     string `mapstructure:"location"`
-	Timeout      int    `mapstructure:"timeout"`
-	MaxRetries   int    `mapstructure:"max_retries"`
-	DefaultModel string `mapstructure:"default_model"`
+	APIKey       string   `mapstructure:"api_key"`
+	ExtraAPIKeys []string `mapstructure:"extra_api_keys"` // 与 api_key 轮换使用的附加密钥
+	ProjectID    string   `mapstructure:"project_id"`
+	Location     string   `mapstructure:"location"`
+	Timeout      int      `mapstructure:"timeout"`
+	MaxRetries   int      `mapstructure:"max_retries"`
+	DefaultModel string   `mapstructure:"default_model"`
+}
+
+// MockConfig 模拟提供商配置
+type MockConfig struct {
+	ScenarioFiles []string        `mapstructure:"scenario_files"` // 脚本场景文件，支持通配符，按顺序匹配
+	Seed          int64           `mapstructure:"seed"`           // 非0时响应ID和创建时间可复现
+	Usage         MockUsageConfig `mapstructure:"usage"`
+}
+
+// MockUsageConfig 模拟提供商的用量模板（text/template），为空时使用固定值
+type MockUsageConfig struct {
+	PromptTokens     string `mapstructure:"prompt_tokens"`
+	CompletionTokens string `mapstructure:"completion_tokens"`
+}
+
+// SandboxConfig 沙箱模式配置，启用后AI提供商由模拟提供商代替，行情接口只从 fixtures_dir 回放，
+// 其余出站HTTP请求一律拒绝
+type SandboxConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	FixturesDir string `mapstructure:"fixtures_dir"` // 覆盖 http_fixtures 的回放目录
+}
+
+// LoadTestConfig 压测提供商配置，按延迟分布和token速率返回生成的内容，不调用外部接口
+type LoadTestConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	Models            []string      `mapstructure:"models"`
+	FirstTokenLatency LatencyConfig `mapstructure:"first_token_latency"`
+	TokensPerSecond   float64       `mapstructure:"tokens_per_second"` // 0 表示不等待
+	CompletionTokens  int           `mapstructure:"completion_tokens"`
+	Seed              int64         `mapstructure:"seed"` // 非0时延迟和内容可复现
+}
+
+// LatencyConfig 延迟分布（fixed、uniform、normal、lognormal），结果限制在 min_ms 和 max_ms 之间
+type LatencyConfig struct {
+	Distribution string `mapstructure:"distribution"`
+	MeanMS       int    `mapstructure:"mean_ms"`
+	StdDevMS     int    `mapstructure:"stddev_ms"`
+	MinMS        int    `mapstructure:"min_ms"`
+	MaxMS        int    `mapstructure:"max_ms"` // 0 表示不限上限
+}
+
+// HTTPFixturesConfig 外部HTTP调用（Yahoo Finance、AI提供商等）的录制回放配置
+type HTTPFixturesConfig struct {
+	Mode string `mapstructure:"mode"` // off / record / replay
+	Dir  string `mapstructure:"dir"`  // 响应文件目录
+}
+
+// ProviderCaptureConfig AI提供商调用的调试捕获配置，保存的内容包含提示词，只在排查问题时启用
+type ProviderCaptureConfig struct {
+	Enabled      bool `mapstructure:"enabled"`
+	Size         int  `mapstructure:"size"`           // 保留最近的调用数
+	MaxBodyBytes int  `mapstructure:"max_body_bytes"` // 每个请求体和响应体保存的最大字节数
+}
+
+type ReportConfig struct {
+	FontPath string `mapstructure:"font_path"`
+}
+
+type StockConfig struct {
+	RiskFreeRate     float64 `mapstructure:"risk_free_rate"`
+	TranscriptAPIKey string  `mapstructure:"transcript_api_key"`
+}
+
+type MCPConfig struct {
+	SamplingModel string           `mapstructure:"sampling_model"`
+	LogArchive    LogArchiveConfig `mapstructure:"log_archive"`
+	WebSearch     WebSearchConfig  `mapstructure:"web_search"`
+	Shell         ShellConfig      `mapstructure:"shell"`
+	Workspace     WorkspaceConfig  `mapstructure:"workspace"`
+	SQLQuery      SQLQueryConfig   `mapstructure:"sql_query"`
+	K8s           K8sConfig        `mapstructure:"k8s"`
+	GitHub        GitHubConfig     `mapstructure:"github"`
+	Jira          JiraConfig       `mapstructure:"jira"`
+	SendEmail     SendEmailConfig  `mapstructure:"send_email"`
+	Sheets        SheetsConfig     `mapstructure:"sheets"`
+	Slack         SlackPostConfig  `mapstructure:"slack"`
+	Calendar      CalendarConfig   `mapstructure:"calendar"`
+	RSSFeed       RSSFeedConfig    `mapstructure:"rss_feed"`
+}
+
+// RSSFeedConfig 新闻订阅源聚合工具配置，未配置订阅源时不注册工具
+type RSSFeedConfig struct {
+	Feeds        []RSSFeedSource `mapstructure:"feeds"`
+	CacheMinutes int             `mapstructure:"cache_minutes"` // 订阅源内容的缓存时间
+	MaxItems     int             `mapstructure:"max_items"`     // 默认返回的新闻条数
+}
+
+// RSSFeedSource 一个 RSS 或 Atom 订阅源
+type RSSFeedSource struct {
+	Name     string `mapstructure:"name"`
+	URL      string `mapstructure:"url"`
+	Category string `mapstructure:"category"` // 可选，用于按分类筛选订阅源
+}
+
+// CalendarConfig 日历工具配置，未配置 provider 时不注册工具
+type CalendarConfig struct {
+	Provider string               `mapstructure:"provider"` // google 或 caldav
+	Timezone string               `mapstructure:"timezone"` // 解析不带时区的时间和全天事件日期使用的时区
+	Google   GoogleCalendarConfig `mapstructure:"google"`
+	CalDAV   CalDAVConfig         `mapstructure:"caldav"`
+}
+
+// GoogleCalendarConfig Google Calendar 配置，使用服务账号认证
+type GoogleCalendarConfig struct {
+	CredentialsFile   string `mapstructure:"credentials_file"`   // 服务账号 JSON 密钥文件
+	CalendarID        string `mapstructure:"calendar_id"`        // 日历ID，默认 primary
+	ImpersonateCaller bool   `mapstructure:"impersonate_caller"` // 以域范围授权代表调用者访问其个人日历
+}
+
+// CalDAVConfig CalDAV 日历配置，所有调用者共用同一个日历集合
+type CalDAVConfig struct {
+	URL      string `mapstructure:"url"` // 日历集合地址
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// SlackPostConfig Slack 消息工具配置，未配置 Bot 令牌或 Webhook 时不注册工具
+type SlackPostConfig struct {
+	BotToken string            `mapstructure:"bot_token"` // Bot 令牌 (xoxb-...)，需要 chat:write 权限
+	Channels []string          `mapstructure:"channels"`  // 通过 Bot 令牌可发送的频道名称 (#alerts) 或ID
+	Webhooks map[string]string `mapstructure:"webhooks"`  // 频道名称到 Incoming Webhook 地址，优先于 Bot 令牌
+}
+
+// SheetsConfig Google Sheets 导出工具配置，使用服务账号认证，未配置密钥文件时不注册工具
+type SheetsConfig struct {
+	CredentialsFile string   `mapstructure:"credentials_file"`  // 服务账号 JSON 密钥文件
+	ShareWith       []string `mapstructure:"share_with"`        // 新建表格总是共享给这些邮箱
+	ShareWithCaller bool     `mapstructure:"share_with_caller"` // 新建表格共享给调用者的账号邮箱
+	MaxRows         int      `mapstructure:"max_rows"`          // 单次导出的最大数据行数
+}
+
+// SendEmailConfig 邮件发送工具配置，使用 notifications.email 的 SMTP 设置，未配置 SMTP 时不注册工具
+type SendEmailConfig struct {
+	Enabled           bool     `mapstructure:"enabled"`
+	AllowedRecipients []string `mapstructure:"allowed_recipients"` // 完整地址或 "@example.com" 表示整个域名
+	AllowSelf         bool     `mapstructure:"allow_self"`         // 始终允许发送到调用者自己的账号邮箱
+	MaxRecipients     int      `mapstructure:"max_recipients"`     // 单次发送的最大收件人数
+	MaxBodyKB         int      `mapstructure:"max_body_kb"`        // 正文的最大大小
+	PerUserPerHour    int      `mapstructure:"per_user_per_hour"`  // 每个用户每小时的发送次数，0 表示不限制
+}
+
+// JiraConfig Jira 工具配置，未配置 base_url 时不注册工具。调用者通过 API 密钥接口保存的 jira 令牌优先于这里的令牌
+type JiraConfig struct {
+	BaseURL  string   `mapstructure:"base_url"` // 例如 https://acme.atlassian.net
+	Token    string   `mapstructure:"token"`    // "邮箱:API token" (Jira Cloud) 或 Bearer 令牌
+	Projects []string `mapstructure:"projects"` // 允许访问的项目 key，为空表示不限制，第一个为创建 issue 的默认项目
+}
+
+// GitHubConfig GitHub 工具配置，未配置仓库时不注册工具。调用者通过 API 密钥接口保存的 github 令牌优先于这里的令牌
+type GitHubConfig struct {
+	BaseURL   string   `mapstructure:"base_url"` // GitHub Enterprise 使用 https://<host>/api/v3
+	Token     string   `mapstructure:"token"`
+	Repos     []string `mapstructure:"repos"`       // 允许访问的仓库 (owner/name)
+	MaxFileKB int      `mapstructure:"max_file_kb"` // get_file 返回的最大文件大小
+}
+
+// K8sConfig Kubernetes 运维工具配置，仅限管理员只读访问，未配置集群时不注册工具
+type K8sConfig struct {
+	TimeoutSeconds int                `mapstructure:"timeout_seconds"` // 单次 API 请求的超时时间
+	MaxLogBytes    int                `mapstructure:"max_log_bytes"`   // 单次读取日志的最大字节数
+	Clusters       []K8sClusterConfig `mapstructure:"clusters"`
+}
+
+// K8sClusterConfig 集群连接配置，建议使用只有只读权限的服务账号
+type K8sClusterConfig struct {
+	Name       string   `mapstructure:"name"`
+	Kubeconfig string   `mapstructure:"kubeconfig"` // 为空时使用默认加载规则（KUBECONFIG 或 ~/.kube/config）
+	Context    string   `mapstructure:"context"`    // 为空时使用当前上下文
+	InCluster  bool     `mapstructure:"in_cluster"` // 使用 Pod 的服务账号
+	Namespaces []string `mapstructure:"namespaces"` // 允许访问的命名空间，为空表示不限制
+}
+
+// SQLQueryConfig 只读 SQL 分析工具配置，未配置数据库时不注册工具
+type SQLQueryConfig struct {
+	MaxRows        int                 `mapstructure:"max_rows"`        // 单次查询返回的最大行数
+	TimeoutSeconds int                 `mapstructure:"timeout_seconds"` // 单次查询的超时时间
+	Databases      []SQLDatabaseConfig `mapstructure:"databases"`
+}
+
+// SQLDatabaseConfig 外部业务数据库，与应用数据库分开配置，建议使用只读账号
+type SQLDatabaseConfig struct {
+	Name          string   `mapstructure:"name"`
+	Driver        string   `mapstructure:"driver"` // postgres / mysql / sqlite3
+	DSN           string   `mapstructure:"dsn"`
+	Description   string   `mapstructure:"description"`    // 提供给模型的数据库说明
+	RedactColumns []string `mapstructure:"redact_columns"` // 列名通配规则，例如 email、*_ssn
+}
+
+// WorkspaceConfig 文件工作区工具配置，每个用户在 root 下有独立的目录
+type WorkspaceConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Root      string `mapstructure:"root"`
+	QuotaMB   int    `mapstructure:"quota_mb"`    // 每个用户的总大小上限
+	MaxFileMB int    `mapstructure:"max_file_mb"` // 单个文件的大小上限
+	MaxFiles  int    `mapstructure:"max_files"`   // 每个用户的文件数上限
+}
+
+// ShellConfig 沙箱命令工具配置，默认关闭；启用后只有管理员可以执行白名单中的命令
+type ShellConfig struct {
+	Enabled          bool     `mapstructure:"enabled"`
+	AllowedCommands  []string `mapstructure:"allowed_commands"`  // 命令名或绝对路径
+	WorkDir          string   `mapstructure:"work_dir"`          // 命令的工作目录和 HOME
+	TimeoutSeconds   int      `mapstructure:"timeout_seconds"`   // 墙钟时间上限
+	CPUSeconds       int      `mapstructure:"cpu_seconds"`       // CPU 时间上限
+	MemoryMB         int      `mapstructure:"memory_mb"`         // 虚拟内存上限
+	MaxOutputBytes   int      `mapstructure:"max_output_bytes"`  // stdout 和 stderr 各自保留的最大字节数
+	NetworkIsolation bool     `mapstructure:"network_isolation"` // 在独立的网络命名空间中执行（仅 Linux），无法隔离时拒绝执行
+}
+
+// WebSearchConfig 网页搜索工具配置，调用者通过 API 密钥接口保存的密钥优先于这里的密钥
+type WebSearchConfig struct {
+	Backend       string `mapstructure:"backend"`     // brave / bing / serpapi
+	MaxResults    int    `mapstructure:"max_results"` // 默认返回的结果数量
+	BraveAPIKey   string `mapstructure:"brave_api_key"`
+	BingAPIKey    string `mapstructure:"bing_api_key"`
+	SerpAPIAPIKey string `mapstructure:"serpapi_api_key"`
+}
+
+// LogArchiveConfig 执行日志归档配置，backend 为空时日志只保存在内存中
+type LogArchiveConfig struct {
+	Backend       string             `mapstructure:"backend"`        // local / s3 / gcs
+	RetentionDays int                `mapstructure:"retention_days"` // 内存中保留的天数，更早的日志被归档
+	IntervalHours int                `mapstructure:"interval_hours"` // 归档任务执行间隔，0 表示只能通过管理接口手动归档
+	Timeout       int                `mapstructure:"timeout"`        // 请求超时（秒）
+	Local         LocalArchiveConfig `mapstructure:"local"`
+	S3            S3ArchiveConfig    `mapstructure:"s3"`
+}
+
+// LocalArchiveConfig 本地目录归档配置
+type LocalArchiveConfig struct {
+	Dir string `mapstructure:"dir"`
+}
+
+// S3ArchiveConfig S3 兼容存储配置，gcs 后端使用 Cloud Storage 的 HMAC 密钥，凭证为空时读取标准 AWS 环境变量
+type S3ArchiveConfig struct {
+	Bucket          string `mapstructure:"bucket"`
+	Region          string `mapstructure:"region"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	Endpoint        string `mapstructure:"endpoint"`
+	Prefix          string `mapstructure:"prefix"`
+}
+
+type UserConfig struct {
+	PurgeRetentionDays int `mapstructure:"purge_retention_days"`
+	PurgeIntervalHours int `mapstructure:"purge_interval_hours"`
+}
+
+// QuotaConfig AI用量配额，优先级为 users > roles > default
+type QuotaConfig struct {
+	Enabled        bool                  `mapstructure:"enabled"`
+	WarningPercent int64                 `mapstructure:"warning_percent"` // 用量达到上限的该百分比时发送预警通知，0 表示不预警
+	Default        QuotaLimit            `mapstructure:"default"`
+	Roles          map[string]QuotaLimit `mapstructure:"roles"` // admin / user / anonymous
+	Users          map[string]QuotaLimit `mapstructure:"users"` // 按用户名配置，viper 会将键转为小写
+}
+
+// QuotaLimit 配额上限，0 表示不限制
+type QuotaLimit struct {
+	RequestsPerDay int64 `mapstructure:"requests_per_day"`
+	TokensPerMonth int64 `mapstructure:"tokens_per_month"`
+}
+
+// BudgetConfig 每月AI费用预算(美元)，按 metrics.model_prices 和模型元数据中的价格计算费用
+type BudgetConfig struct {
+	Enabled   bool                   `mapstructure:"enabled"`
+	Default   BudgetLimit            `mapstructure:"default"`   // 未单独配置预算的用户
+	Users     map[string]BudgetLimit `mapstructure:"users"`     // 按用户名配置
+	Projects  map[string]BudgetLimit `mapstructure:"projects"`  // 按 所有者用户名/项目名 配置
+	Providers map[string]BudgetLimit `mapstructure:"providers"` // 按提供商类型配置，统计所有用户
+}
+
+// BudgetLimit 预算阈值，达到 soft_usd 时发送通知，达到 hard_usd 时拒绝请求，0 表示不限制
+type BudgetLimit struct {
+	SoftUSD float64 `mapstructure:"soft_usd"`
+	HardUSD float64 `mapstructure:"hard_usd"`
+}
+
+// APIKeysConfig 用户 API 密钥配置
+type APIKeysConfig struct {
+	RotationGraceMinutes           int    `mapstructure:"rotation_grace_minutes"`            // 轮换后旧密钥仍可使用的时长
+	PoolStrategy                   string `mapstructure:"pool_strategy"`                     // 多密钥选择策略：round_robin / failover
+	CooldownSeconds                int    `mapstructure:"cooldown_seconds"`                  // 密钥被限流或拒绝后暂停使用的时长
+	ValidationIntervalMinutes      int    `mapstructure:"validation_interval_minutes"`       // 定期验证已保存密钥的间隔，0 表示关闭
+	ExpirationCheckIntervalMinutes int    `mapstructure:"expiration_check_interval_minutes"` // 检查密钥过期时间的间隔，0 表示关闭
+	ExpiryWarningDays              int    `mapstructure:"expiry_warning_days"`               // 密钥过期前多少天提醒所有者
+}
+
+// WebhooksConfig 出站 Webhook 投递配置
+type WebhooksConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	Workers             int  `mapstructure:"workers"`               // 并发投递数
+	MaxAttempts         int  `mapstructure:"max_attempts"`          // 包括首次投递在内的最大尝试次数
+	Timeout             int  `mapstructure:"timeout"`               // 单次请求超时（秒）
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"` // 检查到期重试的间隔
+	BackoffSeconds      int  `mapstructure:"backoff_seconds"`       // 第一次重试前的等待时间，之后每次翻倍
+	MaxBackoffMinutes   int  `mapstructure:"max_backoff_minutes"`   // 重试等待时间上限
+	RetentionDays       int  `mapstructure:"retention_days"`        // 已结束的投递记录保留天数，0 表示不清理
+}
+
+// SchedulerConfig 定时任务调度配置，任务本身通过 /api/v1/admin/scheduler/jobs 管理
+type SchedulerConfig struct {
+	Enabled             bool   `mapstructure:"enabled"`               // 关闭后不自动执行到期任务，仍可手动执行
+	PollIntervalSeconds int    `mapstructure:"poll_interval_seconds"` // 检查到期任务的间隔
+	TimeoutMinutes      int    `mapstructure:"timeout_minutes"`       // 单次执行超时
+	Timezone            string `mapstructure:"timezone"`              // 解释 cron 表达式使用的时区，如 Asia/Shanghai
+	ReportDir           string `mapstructure:"report_dir"`            // 未配置日志归档存储时，定时报告保存的本地目录
+}
+
+// NotificationsConfig 用户通知配置，用户通过 /api/v1/notifications/channels 选择接收渠道
+type NotificationsConfig struct {
+	Enabled     bool                        `mapstructure:"enabled"`
+	Workers     int                         `mapstructure:"workers"`      // 并发发送数
+	QueueSize   int                         `mapstructure:"queue_size"`   // 待发送事件的队列长度，队列满时丢弃新事件
+	Timeout     int                         `mapstructure:"timeout"`      // 单条通知发送超时（秒）
+	TemplateDir string                      `mapstructure:"template_dir"` // 覆盖内置模板的目录，文件名为 <语言>.tmpl
+	Email       EmailNotificationsConfig    `mapstructure:"email"`
+	Slack       SlackNotificationsConfig    `mapstructure:"slack"`
+	Telegram    TelegramNotificationsConfig `mapstructure:"telegram"`
+}
+
+// EmailNotificationsConfig SMTP 发信配置，host 为空时不提供邮件渠道
+type EmailNotificationsConfig struct {
+	Host        string `mapstructure:"host"`
+	Port        int    `mapstructure:"port"`
+	Username    string `mapstructure:"username"`
+	Password    string `mapstructure:"password"`
+	From        string `mapstructure:"from"`         // 发件人，如 "goAdmin <noreply@example.com>"
+	ImplicitTLS bool   `mapstructure:"implicit_tls"` // 直接建立 TLS 连接（465 端口），否则使用 STARTTLS
+}
+
+// SlackNotificationsConfig Slack Incoming Webhook 配置
+type SlackNotificationsConfig struct {
+	AllowedHosts []string `mapstructure:"allowed_hosts"` // 用户可填写的 Webhook 主机名
+}
+
+// TelegramNotificationsConfig Telegram 机器人配置，bot_token 为空时不提供 Telegram 渠道
+type TelegramNotificationsConfig struct {
+	BotToken string `mapstructure:"bot_token"`
+	APIURL   string `mapstructure:"api_url"`
+}
+
+// EventBusConfig 领域事件发布配置，backend 为空时不发布
+type EventBusConfig struct {
+	Backend     string              `mapstructure:"backend"`      // nats / kafka
+	TopicPrefix string              `mapstructure:"topic_prefix"` // 主题为前缀加事件类型，如 go-springai.tool.executed
+	Source      string              `mapstructure:"source"`       // 写入消息的 source 字段，为空时使用主机名
+	BufferSize  int                 `mapstructure:"buffer_size"`  // 待发送事件的队列长度，队列满时丢弃新事件
+	Timeout     int                 `mapstructure:"timeout"`      // 单条消息发送超时（秒）
+	NATS        NATSEventBusConfig  `mapstructure:"nats"`
+	Kafka       KafkaEventBusConfig `mapstructure:"kafka"`
+}
+
+// NATSEventBusConfig NATS 连接配置
+type NATSEventBusConfig struct {
+	URL      string `mapstructure:"url"` // 多个地址用逗号分隔
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"`
+}
+
+// KafkaEventBusConfig Kafka 连接配置，仅支持明文连接
+type KafkaEventBusConfig struct {
+	Brokers  []string `mapstructure:"brokers"`
+	ClientID string   `mapstructure:"client_id"`
+	Acks     int      `mapstructure:"acks"` // 1 或 -1（所有同步副本）
+}
+
+// ErrorReportingConfig Sentry（或兼容服务）错误上报配置，dsn 为空表示关闭
+type ErrorReportingConfig struct {
+	DSN         string  `mapstructure:"dsn"`
+	Environment string  `mapstructure:"environment"`
+	Release     string  `mapstructure:"release"`     // 为空时使用 SENTRY_RELEASE 或构建信息
+	SampleRate  float64 `mapstructure:"sample_rate"` // 上报比例，0 表示全部上报
+}
+
+// ErrorResponseConfig 错误响应格式配置
+type ErrorResponseConfig struct {
+	Format      string `mapstructure:"format"`        // default 或 problem（RFC 7807 application/problem+json）
+	TypeBaseURI string `mapstructure:"type_base_uri"` // problem 的 type 前缀，后接由错误码生成的路径段
+}
+
+// LogConfig 日志文件配置
+type LogConfig struct {
+	App    LogFileConfig   `mapstructure:"app"`    // 应用日志
+	Access AccessLogConfig `mapstructure:"access"` // HTTP访问日志，未配置文件时写入应用日志
+}
+
+// AccessLogConfig HTTP访问日志配置
+type AccessLogConfig struct {
+	LogFileConfig `mapstructure:",squash"`
+	LogBodies     bool `mapstructure:"log_bodies"`     // 记录脱敏后的JSON/表单请求体和JSON响应体
+	MaxBodyBytes  int  `mapstructure:"max_body_bytes"` // 请求体和响应体的最大记录长度
+}
+
+// LogFileConfig 日志文件滚动和保留配置，filename 为空表示只输出到控制台
+type LogFileConfig struct {
+	Filename   string `mapstructure:"filename"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`  // 单个文件达到该大小后滚动
+	MaxAgeDays int    `mapstructure:"max_age_days"` // 滚动文件保留天数，0 表示不按时间清理
+	MaxBackups int    `mapstructure:"max_backups"`  // 滚动文件保留个数，0 表示不按个数清理
+	Compress   bool   `mapstructure:"compress"`
+}
+
+// CompressionConfig 响应压缩配置，按 Accept-Encoding 使用brotli或gzip
+type CompressionConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	MinLength    int      `mapstructure:"min_length"`    // 响应体达到该字节数才压缩
+	ExcludePaths []string `mapstructure:"exclude_paths"` // 不压缩的路径前缀
+}
+
+// BodyLimitConfig 请求体大小限制，按最长路径前缀匹配，0 表示不限制
+type BodyLimitConfig struct {
+	DefaultBytes int64                 `mapstructure:"default_bytes"`
+	Paths        []PathBodyLimitConfig `mapstructure:"paths"`
+}
+
+// PathBodyLimitConfig 路径前缀（路由分组）的请求体大小限制
+type PathBodyLimitConfig struct {
+	Prefix   string `mapstructure:"prefix"`
+	MaxBytes int64  `mapstructure:"max_bytes"`
+}
+
+// CORSConfig 跨域配置，allowed_origins 为空时只允许同源访问
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"` // 支持 "*" 和 "https://*.example.com"
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAgeSeconds    int      `mapstructure:"max_age_seconds"` // 预检结果的缓存时长
+}
+
+// ChaosConfig 故障注入配置，概率取值 0-1，只应在测试和压测环境启用
+type ChaosConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	LatencyRate   float64  `mapstructure:"latency_rate"`
+	MaxLatencyMS  int      `mapstructure:"max_latency_ms"`
+	ErrorRate     float64  `mapstructure:"error_rate"`
+	ErrorStatuses []int    `mapstructure:"error_statuses"`
+	ResetRate     float64  `mapstructure:"reset_rate"`
+	Paths         []string `mapstructure:"paths"`     // HTTP中间件生效的路径前缀，为空时对所有请求生效
+	Providers     bool     `mapstructure:"providers"` // 同时对AI提供商调用注入故障
+	Seed          int64    `mapstructure:"seed"`      // 非0时使用固定随机种子
+}
+
+// TimeoutConfig 请求处理时限，按最长路径前缀匹配，0 表示不限制
+type TimeoutConfig struct {
+	DefaultSeconds int                 `mapstructure:"default_seconds"`
+	Paths          []PathTimeoutConfig `mapstructure:"paths"`
+}
+
+// PathTimeoutConfig 路径前缀（路由分组）的处理时限
+type PathTimeoutConfig struct {
+	Prefix  string `mapstructure:"prefix"`
+	Seconds int    `mapstructure:"seconds"`
+}
+
+// IdempotencyConfig Idempotency-Key 重放配置
+type IdempotencyConfig struct {
+	Enabled  bool        `mapstructure:"enabled"`
+	Backend  string      `mapstructure:"backend"`   // memory / redis，多实例部署使用 redis
+	TTLHours int         `mapstructure:"ttl_hours"` // 首个响应的保存时长
+	Redis    RedisConfig `mapstructure:"redis"`
+}
+
+// RateLimitConfig 请求限流配置，rate 为每秒请求数，rate 为0的规则不启用
+type RateLimitConfig struct {
+	Enabled bool                   `mapstructure:"enabled"`
+	Backend string                 `mapstructure:"backend"` // memory / redis，多实例部署使用 redis
+	Redis   RedisConfig            `mapstructure:"redis"`
+	Global  RateLimitRuleConfig    `mapstructure:"global"`   // 全部请求共享
+	PerIP   RateLimitRuleConfig    `mapstructure:"per_ip"`   // 每个客户端IP
+	PerUser RateLimitRuleConfig    `mapstructure:"per_user"` // 每个登录用户
+	Groups  []RateLimitGroupConfig `mapstructure:"groups"`   // 路由分组内每个用户（未登录时每个IP）
+}
+
+// RateLimitRuleConfig 令牌桶规则
+type RateLimitRuleConfig struct {
+	Rate  float64 `mapstructure:"rate"`  // 每秒补充的令牌数
+	Burst int     `mapstructure:"burst"` // 桶容量，即允许的突发请求数
+}
+
+// RateLimitGroupConfig 路由分组限流规则，name 为 auth、ai、assistant、mcp、stock 等分组名
+type RateLimitGroupConfig struct {
+	Name                string `mapstructure:"name"`
+	RateLimitRuleConfig `mapstructure:",squash"`
+}
+
+// RedisConfig Redis连接配置
+type RedisConfig struct {
+	Addr      string `mapstructure:"addr"`
+	Password  string `mapstructure:"password"`
+	DB        int    `mapstructure:"db"`
+	KeyPrefix string `mapstructure:"key_prefix"`
+}
+
+// MetricsConfig Prometheus指标配置
+type MetricsConfig struct {
+	Enabled     bool               `mapstructure:"enabled"`
+	Token       string             `mapstructure:"token"`        // 设置后抓取 /metrics 需携带 Bearer 令牌
+	ModelPrices []ModelPriceConfig `mapstructure:"model_prices"` // 用于估算费用的模型价格
+}
+
+// AdminUIConfig 内嵌管理后台配置
+type AdminUIConfig struct {
+	Enabled bool `mapstructure:"enabled"` // 在 /admin 提供管理后台页面
+}
+
+// ModelPriceConfig 模型每百万令牌的美元价格，model 按最长前缀匹配
+type ModelPriceConfig struct {
+	Model      string  `mapstructure:"model"`
+	Prompt     float64 `mapstructure:"prompt"`
+	Completion float64 `mapstructure:"completion"`
+}
+
+// SecretsConfig 外部密钥存储配置，backend 为 database 时继续使用数据库存储
+type SecretsConfig struct {
+	Backend       string             `mapstructure:"backend"` // database / vault / aws
+	Timeout       int                `mapstructure:"timeout"` // 请求超时（秒）
+	JWTSecretName string             `mapstructure:"jwt_secret_name"`
+	Vault         VaultSecretsConfig `mapstructure:"vault"`
+	AWS           AWSSecretsConfig   `mapstructure:"aws"`
+}
+
+// VaultSecretsConfig HashiCorp Vault KV v2 配置
+type VaultSecretsConfig struct {
+	Address   string `mapstructure:"address"`
+	Token     string `mapstructure:"token"`
+	Namespace string `mapstructure:"namespace"`
+	Mount     string `mapstructure:"mount"`
+	Prefix    string `mapstructure:"prefix"`
+}
+
+// AWSSecretsConfig AWS Secrets Manager 配置，凭证为空时读取标准 AWS 环境变量
+type AWSSecretsConfig struct {
+	Region          string `mapstructure:"region"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	Endpoint        string `mapstructure:"endpoint"`
+	Prefix          string `mapstructure:"prefix"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -72,22 +694,41 @@ func LoadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
+// EnableSandbox 启用沙箱模式，优先于配置文件，供命令行 --sandbox 在 LoadConfig 之前调用
+func EnableSandbox() {
+	viper.Set("sandbox.enabled", true)
+}
+
 func setDefaults() {
 	viper.SetDefault("server.host", "localhost")
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.shutdown_timeout", 30)
+	viper.SetDefault("server.tls.enabled", false)
+	viper.SetDefault("server.tls.autocert.cache_dir", "./data/autocert")
+	viper.SetDefault("server.grpc.enabled", false)
+	viper.SetDefault("server.grpc.port", "9090")
 
 	viper.SetDefault("database.driver", "sqlite3")
 	viper.SetDefault("database.dsn", "./data/admin.db")
+	viper.SetDefault("database.max_idle_conns", 2)
+	viper.SetDefault("database.sqlite.journal_mode", "WAL")
+	viper.SetDefault("database.sqlite.busy_timeout_ms", 5000)
+	viper.SetDefault("database.sqlite.foreign_keys", true)
+	viper.SetDefault("database.sqlite.synchronous", "NORMAL")
 
 	viper.SetDefault("jwt.secret", "your-secret-key")
 	viper.SetDefault("jwt.expire_time", 24)
+	viper.SetDefault("jwt.refresh_expire_time", 168)
+	viper.SetDefault("jwt.impersonation_ttl", 15)
+	viper.SetDefault("jwt.reauth_max_age", 5)
 
 	viper.SetDefault("openai.api_key", "")
 	viper.SetDefault("openai.base_url", "https://api.openai.com/v1")
 	viper.SetDefault("openai.timeout", 30)
 	viper.SetDefault("openai.max_retries", 3)
 	viper.SetDefault("openai.default_model", "gpt-3.5-turbo")
+	viper.SetDefault("openai.fine_tuning_sync_interval_minutes", 5)
 
 	viper.SetDefault("googleai.api_key", "")
 	viper.SetDefault("googleai.project_id", "")
@@ -95,6 +736,203 @@ func setDefaults() {
 	viper.SetDefault("googleai.timeout", 30)
 	viper.SetDefault("googleai.max_retries", 3)
 	viper.SetDefault("googleai.default_model", "gemini-1.5-flash")
+
+	viper.SetDefault("mock.scenario_files", []string{})
+	viper.SetDefault("mock.seed", 0)
+	viper.SetDefault("mock.usage.prompt_tokens", "")
+	viper.SetDefault("mock.usage.completion_tokens", "")
+
+	viper.SetDefault("sandbox.enabled", false)
+	viper.SetDefault("sandbox.fixtures_dir", "testdata/sandbox")
+
+	viper.SetDefault("loadtest.enabled", false)
+	viper.SetDefault("loadtest.models", []string{"loadtest-1"})
+	viper.SetDefault("loadtest.first_token_latency.distribution", "lognormal")
+	viper.SetDefault("loadtest.first_token_latency.mean_ms", 500)
+	viper.SetDefault("loadtest.first_token_latency.stddev_ms", 250)
+	viper.SetDefault("loadtest.first_token_latency.min_ms", 50)
+	viper.SetDefault("loadtest.first_token_latency.max_ms", 5000)
+	viper.SetDefault("loadtest.tokens_per_second", 50)
+	viper.SetDefault("loadtest.completion_tokens", 200)
+	viper.SetDefault("loadtest.seed", 0)
+
+	viper.SetDefault("http_fixtures.mode", "off")
+	viper.SetDefault("http_fixtures.dir", "testdata/http_fixtures")
+
+	viper.SetDefault("provider_capture.enabled", false)
+	viper.SetDefault("provider_capture.size", 50)
+	viper.SetDefault("provider_capture.max_body_bytes", 65536)
+
+	viper.SetDefault("report.font_path", "")
+
+	viper.SetDefault("stock.risk_free_rate", 0.02)
+	viper.SetDefault("stock.transcript_api_key", "")
+
+	viper.SetDefault("mcp.sampling_model", "gpt-3.5-turbo")
+	viper.SetDefault("mcp.log_archive.retention_days", 7)
+	viper.SetDefault("mcp.log_archive.interval_hours", 24)
+	viper.SetDefault("mcp.log_archive.timeout", 30)
+	viper.SetDefault("mcp.log_archive.local.dir", "./data/archive")
+	viper.SetDefault("mcp.log_archive.s3.prefix", "go-springai")
+	viper.SetDefault("mcp.web_search.backend", "brave")
+	viper.SetDefault("mcp.web_search.max_results", 5)
+	viper.SetDefault("mcp.web_search.brave_api_key", "")
+	viper.SetDefault("mcp.web_search.bing_api_key", "")
+	viper.SetDefault("mcp.web_search.serpapi_api_key", "")
+	viper.SetDefault("mcp.shell.enabled", false)
+	viper.SetDefault("mcp.shell.allowed_commands", []string{})
+	viper.SetDefault("mcp.shell.work_dir", "./data/shell")
+	viper.SetDefault("mcp.shell.timeout_seconds", 30)
+	viper.SetDefault("mcp.shell.cpu_seconds", 10)
+	viper.SetDefault("mcp.shell.memory_mb", 512)
+	viper.SetDefault("mcp.shell.max_output_bytes", 65536)
+	viper.SetDefault("mcp.shell.network_isolation", true)
+	viper.SetDefault("mcp.workspace.enabled", true)
+	viper.SetDefault("mcp.workspace.root", "./data/workspace")
+	viper.SetDefault("mcp.workspace.quota_mb", 50)
+	viper.SetDefault("mcp.workspace.max_file_mb", 10)
+	viper.SetDefault("mcp.workspace.max_files", 1000)
+	viper.SetDefault("mcp.sql_query.max_rows", 500)
+	viper.SetDefault("mcp.sql_query.timeout_seconds", 15)
+	viper.SetDefault("mcp.k8s.timeout_seconds", 15)
+	viper.SetDefault("mcp.k8s.max_log_bytes", 65536)
+	viper.SetDefault("mcp.github.base_url", "https://api.github.com")
+	viper.SetDefault("mcp.github.token", "")
+	viper.SetDefault("mcp.github.repos", []string{})
+	viper.SetDefault("mcp.github.max_file_kb", 256)
+	viper.SetDefault("mcp.jira.base_url", "")
+	viper.SetDefault("mcp.jira.token", "")
+	viper.SetDefault("mcp.jira.projects", []string{})
+	viper.SetDefault("mcp.send_email.enabled", true)
+	viper.SetDefault("mcp.send_email.allowed_recipients", []string{})
+	viper.SetDefault("mcp.send_email.allow_self", true)
+	viper.SetDefault("mcp.send_email.max_recipients", 5)
+	viper.SetDefault("mcp.send_email.max_body_kb", 100)
+	viper.SetDefault("mcp.send_email.per_user_per_hour", 10)
+	viper.SetDefault("mcp.sheets.credentials_file", "")
+	viper.SetDefault("mcp.sheets.share_with", []string{})
+	viper.SetDefault("mcp.sheets.share_with_caller", true)
+	viper.SetDefault("mcp.sheets.max_rows", 5000)
+	viper.SetDefault("mcp.slack.bot_token", "")
+	viper.SetDefault("mcp.slack.channels", []string{})
+	viper.SetDefault("mcp.slack.webhooks", map[string]string{})
+	viper.SetDefault("mcp.calendar.provider", "")
+	viper.SetDefault("mcp.calendar.timezone", "UTC")
+	viper.SetDefault("mcp.calendar.google.credentials_file", "")
+	viper.SetDefault("mcp.calendar.google.calendar_id", "primary")
+	viper.SetDefault("mcp.calendar.google.impersonate_caller", true)
+	viper.SetDefault("mcp.calendar.caldav.url", "")
+	viper.SetDefault("mcp.calendar.caldav.username", "")
+	viper.SetDefault("mcp.calendar.caldav.password", "")
+	viper.SetDefault("mcp.rss_feed.feeds", []map[string]string{})
+	viper.SetDefault("mcp.rss_feed.cache_minutes", 10)
+	viper.SetDefault("mcp.rss_feed.max_items", 20)
+
+	viper.SetDefault("user.purge_retention_days", 30)
+	viper.SetDefault("user.purge_interval_hours", 24)
+
+	viper.SetDefault("quota.enabled", false)
+	viper.SetDefault("quota.warning_percent", 80)
+	viper.SetDefault("quota.default.requests_per_day", 0)
+	viper.SetDefault("quota.default.tokens_per_month", 0)
+
+	viper.SetDefault("budget.enabled", false)
+	viper.SetDefault("budget.default.soft_usd", 0)
+	viper.SetDefault("budget.default.hard_usd", 0)
+
+	viper.SetDefault("api_keys.rotation_grace_minutes", 60)
+	viper.SetDefault("api_keys.pool_strategy", "round_robin")
+	viper.SetDefault("api_keys.cooldown_seconds", 60)
+	viper.SetDefault("api_keys.validation_interval_minutes", 60)
+	viper.SetDefault("api_keys.expiration_check_interval_minutes", 60)
+	viper.SetDefault("api_keys.expiry_warning_days", 7)
+
+	viper.SetDefault("webhooks.enabled", true)
+	viper.SetDefault("webhooks.workers", 4)
+	viper.SetDefault("webhooks.max_attempts", 6)
+	viper.SetDefault("webhooks.timeout", 10)
+	viper.SetDefault("webhooks.poll_interval_seconds", 5)
+	viper.SetDefault("webhooks.backoff_seconds", 30)
+	viper.SetDefault("webhooks.max_backoff_minutes", 60)
+	viper.SetDefault("webhooks.retention_days", 30)
+
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.poll_interval_seconds", 15)
+	viper.SetDefault("scheduler.timeout_minutes", 30)
+	viper.SetDefault("scheduler.timezone", "UTC")
+	viper.SetDefault("scheduler.report_dir", "./data/reports")
+
+	viper.SetDefault("notifications.enabled", true)
+	viper.SetDefault("notifications.workers", 2)
+	viper.SetDefault("notifications.queue_size", 256)
+	viper.SetDefault("notifications.timeout", 10)
+	viper.SetDefault("notifications.email.port", 587)
+	viper.SetDefault("notifications.slack.allowed_hosts", []string{"hooks.slack.com"})
+	viper.SetDefault("notifications.telegram.api_url", "https://api.telegram.org")
+
+	viper.SetDefault("event_bus.topic_prefix", "go-springai.")
+	viper.SetDefault("event_bus.buffer_size", 1024)
+	viper.SetDefault("event_bus.timeout", 5)
+	viper.SetDefault("event_bus.nats.url", "nats://127.0.0.1:4222")
+	viper.SetDefault("event_bus.kafka.brokers", []string{"127.0.0.1:9092"})
+	viper.SetDefault("event_bus.kafka.client_id", "go-springai")
+	viper.SetDefault("event_bus.kafka.acks", 1)
+
+	viper.SetDefault("secrets.backend", "database")
+	viper.SetDefault("secrets.timeout", 10)
+	viper.SetDefault("secrets.jwt_secret_name", "jwt-secret")
+	viper.SetDefault("secrets.vault.mount", "secret")
+	viper.SetDefault("secrets.vault.prefix", "go-springai")
+	viper.SetDefault("secrets.aws.prefix", "go-springai")
+
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("admin_ui.enabled", true)
+
+	viper.SetDefault("error_response.format", "default")
+	viper.SetDefault("error_response.type_base_uri", "/problems/")
+
+	viper.SetDefault("log.app.max_size_mb", 100)
+	viper.SetDefault("log.app.max_age_days", 30)
+	viper.SetDefault("log.app.max_backups", 10)
+	viper.SetDefault("log.app.compress", true)
+	viper.SetDefault("log.access.max_size_mb", 100)
+	viper.SetDefault("log.access.max_age_days", 14)
+	viper.SetDefault("log.access.max_backups", 10)
+	viper.SetDefault("log.access.compress", true)
+	viper.SetDefault("log.access.log_bodies", false)
+	viper.SetDefault("log.access.max_body_bytes", 4096)
+
+	viper.SetDefault("compression.enabled", true)
+	viper.SetDefault("compression.min_length", 1024)
+
+	viper.SetDefault("body_limit.default_bytes", 1<<20)
+
+	viper.SetDefault("timeout.default_seconds", 30)
+
+	viper.SetDefault("cors.allowed_origins", []string{"http://localhost:5173"})
+	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", "Cache-Control", "Pragma", "X-Requested-With", "X-Request-ID", "Idempotency-Key"})
+	viper.SetDefault("cors.exposed_headers", []string{"Content-Disposition", "Content-Language", "X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After", "Idempotent-Replayed", "X-Impersonated-By", "X-Impersonation-Banner"})
+	viper.SetDefault("cors.allow_credentials", true)
+	viper.SetDefault("cors.max_age_seconds", 600)
+
+	viper.SetDefault("chaos.enabled", false)
+	viper.SetDefault("chaos.error_statuses", []int{500, 502, 503})
+
+	viper.SetDefault("idempotency.enabled", true)
+	viper.SetDefault("idempotency.backend", "memory")
+	viper.SetDefault("idempotency.ttl_hours", 24)
+	viper.SetDefault("idempotency.redis.addr", "localhost:6379")
+	viper.SetDefault("idempotency.redis.key_prefix", "go-springai:idempotency:")
+
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("rate_limit.backend", "memory")
+	viper.SetDefault("rate_limit.redis.addr", "localhost:6379")
+	viper.SetDefault("rate_limit.redis.key_prefix", "go-springai:ratelimit:")
+	viper.SetDefault("rate_limit.per_ip.rate", 20)
+	viper.SetDefault("rate_limit.per_ip.burst", 40)
+	viper.SetDefault("rate_limit.per_user.rate", 10)
+	viper.SetDefault("rate_limit.per_user.burst", 20)
 }
 
 func (c *Config) GetDatabaseDSN() string {
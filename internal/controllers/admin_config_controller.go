@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/response"
+	"go-springAi/internal/runtimeconfig"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminConfigController 运行时配置导出导入控制器
+type AdminConfigController struct {
+	BaseController
+	configService *runtimeconfig.Service
+}
+
+// NewAdminConfigController 创建运行时配置导出导入控制器
+func NewAdminConfigController(configService *runtimeconfig.Service, errorHandler *errors.ErrorHandler) *AdminConfigController {
+	return &AdminConfigController{
+		BaseController: *NewBaseController(errorHandler),
+		configService:  configService,
+	}
+}
+
+// ExportConfig 导出当前生效的配置，format 为 json（默认）或 yaml，返回可直接导入的文档
+func (cc *AdminConfigController) ExportConfig(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		cc.HandleError(c, errors.NewValidationError("format must be json or yaml"))
+		return
+	}
+
+	exported, err := cc.configService.Export(c.Request.Context())
+	if err != nil {
+		cc.HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "config."+format))
+	if format == "yaml" {
+		c.YAML(http.StatusOK, exported)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, exported)
+}
+
+// ImportConfig 导入配置，请求体为导出的 JSON 或 YAML 文档；dry_run=true 时只返回差异不做修改
+func (cc *AdminConfigController) ImportConfig(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		cc.HandleError(c, errors.NewValidationError("dry_run must be true or false"))
+		return
+	}
+
+	var imported dto.RuntimeConfig
+	switch c.ContentType() {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		err = c.ShouldBindYAML(&imported)
+	default:
+		err = c.ShouldBindJSON(&imported)
+	}
+	if err != nil {
+		cc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := cc.configService.Import(c.Request.Context(), &imported, dryRun)
+	if err != nil {
+		cc.HandleError(c, err)
+		return
+	}
+
+	messageID := "response.config.imported"
+	if dryRun {
+		messageID = "response.config.preview"
+	}
+	response.I18nSuccess(c, http.StatusOK, messageID, result, nil)
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/pagination"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminUserController 用户管理控制器
+type AdminUserController struct {
+	BaseController
+	userAdminService  service.UserAdminService
+	authService       service.AuthService
+	permissionService service.UserPermissionService
+	logger            *zap.Logger
+}
+
+// NewAdminUserController 创建用户管理控制器
+func NewAdminUserController(userAdminService service.UserAdminService, authService service.AuthService, permissionService service.UserPermissionService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AdminUserController {
+	return &AdminUserController{
+		BaseController:    *NewBaseController(errorHandler),
+		userAdminService:  userAdminService,
+		authService:       authService,
+		permissionService: permissionService,
+		logger:            logger,
+	}
+}
+
+// ListUsers 按条件分页查询用户
+func (uc *AdminUserController) ListUsers(c *gin.Context) {
+	var query dto.UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		uc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := uc.userAdminService.ListUsers(c.Request.Context(), &query)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+	pagination.Links(&result.PageInfo, c.Request.URL)
+
+	response.I18nSuccess(c, http.StatusOK, "response.users.retrieved", result, nil)
+}
+
+// CreateUser 创建用户
+func (uc *AdminUserController) CreateUser(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		uc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	user, err := uc.userAdminService.CreateUser(c.Request.Context(), req)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.users.created", user, nil)
+}
+
+// DeleteUser 软删除用户
+func (uc *AdminUserController) DeleteUser(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := uc.userAdminService.DeleteUser(c.Request.Context(), userID); err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.users.deleted", nil, nil)
+}
+
+// RestoreUser 恢复已软删除的用户
+func (uc *AdminUserController) RestoreUser(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	user, err := uc.userAdminService.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.users.restored", user, nil)
+}
+
+// ImpersonateUser 获取目标用户的短期模拟登录令牌
+func (uc *AdminUserController) ImpersonateUser(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		uc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := uc.authService.Impersonate(c.Request.Context(), adminID, userID, req.Reason)
+	if err != nil {
+		uc.logger.Warn("模拟登录失败", zap.Int64("admin_id", adminID), zap.Int64("user_id", userID), zap.Error(err))
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.users.impersonated", result, nil)
+}
+
+// ListPermissions 获取用户的权限
+func (uc *AdminUserController) ListPermissions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	permissions, err := uc.permissionService.ListPermissions(c.Request.Context(), userID)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.permissions.retrieved", permissions, nil)
+}
+
+// GrantPermission 授予用户权限
+func (uc *AdminUserController) GrantPermission(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	var req dto.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		uc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := uc.permissionService.GrantPermission(c.Request.Context(), adminID, userID, req.Permission); err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.permissions.granted", nil, nil)
+}
+
+// RevokePermission 撤销用户权限
+func (uc *AdminUserController) RevokePermission(c *gin.Context) {
+	adminID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		uc.HandleError(c, errors.NewValidationError("用户ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := uc.permissionService.RevokePermission(c.Request.Context(), adminID, userID, c.Param("permission")); err != nil {
+		uc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.permissions.revoked", nil, nil)
+}
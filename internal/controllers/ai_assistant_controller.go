@@ -3,8 +3,10 @@ package controllers
 import (
 	"net/http"
 
+	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
 	"go-springAi/internal/logger"
+	"go-springAi/internal/middleware"
 	"go-springAi/internal/response"
 	"go-springAi/internal/service"
 
@@ -16,14 +18,16 @@ import (
 type AIAssistantController struct {
 	*BaseController
 	aiAssistantService *service.AIAssistantService
+	quotaService       service.QuotaService
 	logger             *zap.Logger
 }
 
 // NewAIAssistantController 创建AI助手控制器
-func NewAIAssistantController(aiAssistantService *service.AIAssistantService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AIAssistantController {
+func NewAIAssistantController(aiAssistantService *service.AIAssistantService, quotaService service.QuotaService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AIAssistantController {
 	return &AIAssistantController{
 		BaseController:     NewBaseController(errorHandler),
 		aiAssistantService: aiAssistantService,
+		quotaService:       quotaService,
 		logger:             logger,
 	}
 }
@@ -51,7 +55,11 @@ func (ac *AIAssistantController) Chat(c *gin.Context) {
 
 	// 不再在控制器层设置默认模型，让服务层处理提供商和模型的选择
 
+	// 未认证的请求按匿名用户统计配额
+	req.UserID, _ = middleware.GetUserIDFromContext(c)
+
 	result, err := ac.aiAssistantService.Chat(c.Request.Context(), &req)
+	ac.setQuotaHeaders(c, req.UserID)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -60,6 +68,11 @@ func (ac *AIAssistantController) Chat(c *gin.Context) {
 			logger.ZapError(err),
 			logger.String("error_message", err.Error()),
 			logger.String("model", req.Model))
+		if _, ok := errors.IsAppError(err); ok {
+			// 应用错误(如配额超限)需要返回重置时间等附加信息
+			ac.HandleError(c, err)
+			return
+		}
 		c.Error(err)
 		return
 	}
@@ -79,7 +92,7 @@ func (ac *AIAssistantController) Chat(c *gin.Context) {
 		logger.Int("tool_calls", toolCallsCount),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "Chat completed successfully", result)
+	response.I18nSuccess(c, http.StatusOK, "response.assistant.chat", result, nil)
 }
 
 // Initialize 初始化AI助手
@@ -108,7 +121,66 @@ func (ac *AIAssistantController) Initialize(c *gin.Context) {
 		logger.Operation("initialize"),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "AI assistant initialized successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.assistant.initialized", gin.H{
 		"status": "initialized",
-	})
-}
\ No newline at end of file
+	}, nil)
+}
+
+// EstimateCost 估算聊天请求在各候选模型上的令牌数和费用，不调用提供商也不计入配额
+func (ac *AIAssistantController) EstimateCost(c *gin.Context) {
+	var req service.CostEstimateRequest
+	if err := ac.BindAndValidate(c, &req); err != nil {
+		return
+	}
+
+	result, err := ac.aiAssistantService.EstimateCost(c.Request.Context(), &req)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.assistant.estimate", result, nil)
+}
+
+// GetQuota 获取当前用户的AI用量配额
+func (ac *AIAssistantController) GetQuota(c *gin.Context) {
+	// 未认证的请求返回匿名配额
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	status, err := ac.quotaService.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+	if status.Enabled {
+		writeQuotaHeaders(c, status)
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.assistant.quota", status, nil)
+}
+
+// setQuotaHeaders 按用户当前配额设置限流响应头，便于客户端自行控制请求速度
+func (ac *AIAssistantController) setQuotaHeaders(c *gin.Context, userID int64) {
+	if !ac.quotaService.Enabled() {
+		return
+	}
+	status, err := ac.quotaService.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		logger.WarnCtx(c.Request.Context(), logger.MsgAPIError,
+			logger.Module(logger.ModuleController),
+			logger.Component("ai_assistant"),
+			logger.Operation("quota_headers"),
+			logger.ZapError(err))
+		return
+	}
+	writeQuotaHeaders(c, status)
+}
+
+// writeQuotaHeaders 写入有上限的各项配额，保留剩余量最少的一项
+func writeQuotaHeaders(c *gin.Context, status *dto.QuotaStatusResponse) {
+	for _, usage := range []dto.QuotaUsage{status.RequestsPerDay, status.TokensPerMonth} {
+		if usage.Limit > 0 {
+			middleware.SetRateLimitHeaders(c, usage.Limit, usage.Remaining, usage.ResetAt)
+		}
+	}
+}
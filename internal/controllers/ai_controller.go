@@ -1,16 +1,22 @@
 package controllers
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
 	"go-springAi/internal/logger"
+	"go-springAi/internal/mcp/tools"
 	"go-springAi/internal/middleware"
 	"go-springAi/internal/provider"
 	"go-springAi/internal/response"
 	"go-springAi/internal/service"
+	"go-springAi/internal/types"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -21,19 +27,33 @@ type AIController struct {
 	BaseController
 	providerManager *provider.Manager
 	apiKeyService   service.APIKeyService
+	projectService  service.ProjectService
+	modelMetadata   service.ModelMetadataService
 	logger          *zap.Logger
 }
 
 // NewAIController 创建统一AI控制器
-func NewAIController(providerManager *provider.Manager, apiKeyService service.APIKeyService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AIController {
+func NewAIController(providerManager *provider.Manager, apiKeyService service.APIKeyService, projectService service.ProjectService, modelMetadata service.ModelMetadataService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AIController {
 	return &AIController{
 		BaseController:  *NewBaseController(errorHandler),
 		providerManager: providerManager,
 		apiKeyService:   apiKeyService,
+		projectService:  projectService,
+		modelMetadata:   modelMetadata,
 		logger:          logger,
 	}
 }
 
+// withMetadata 将管理员维护的元数据填充到模型配置中
+func (ac *AIController) withMetadata(providerType string, models map[string]*provider.ModelConfig) map[string]*provider.ModelConfig {
+	for name, model := range models {
+		if metadata, ok := ac.modelMetadata.Get(providerType, name); ok {
+			model.ModelMetadata = metadata
+		}
+	}
+	return models
+}
+
 // ListModels 列出指定提供商的模型
 func (ac *AIController) ListModels(c *gin.Context) {
 	providerType := c.Param("provider")
@@ -69,10 +89,10 @@ func (ac *AIController) ListModels(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Models retrieved successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.models.retrieved", gin.H{
 		"provider": providerType,
-		"models":   models,
-	})
+		"models":   ac.withMetadata(providerType, models),
+	}, nil)
 }
 
 // ListAllModels 列出指定提供商的所有模型（包括禁用的，用于模型管理）
@@ -110,10 +130,10 @@ func (ac *AIController) ListAllModels(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "All models retrieved successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.models.all", gin.H{
 		"provider": providerType,
-		"models":   models,
-	})
+		"models":   ac.withMetadata(providerType, models),
+	}, nil)
 }
 
 // ListProviders 列出所有可用的提供商
@@ -123,11 +143,25 @@ func (ac *AIController) ListProviders(c *gin.Context) {
 		logger.Component("ai"),
 		logger.Operation("list_providers"))
 
-	providers := ac.providerManager.ListProviders()
+	providers := ac.providerManager.ListProviders(c.Request.Context())
 
-	response.Success(c, http.StatusOK, "Providers retrieved successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.providers.retrieved", gin.H{
 		"providers": providers,
-	})
+	}, nil)
+}
+
+// GetKeyHealth 获取各提供商每个密钥的健康状态（管理员）
+func (ac *AIController) GetKeyHealth(c *gin.Context) {
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("ai"),
+		logger.Operation("get_key_health"))
+
+	health := ac.providerManager.GetKeyHealth()
+
+	response.I18nSuccess(c, http.StatusOK, "response.keys.health", gin.H{
+		"providers": health,
+	}, nil)
 }
 
 // GetModelConfig 获取指定提供商的模型配置
@@ -168,11 +202,15 @@ func (ac *AIController) GetModelConfig(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Model config retrieved successfully", gin.H{
+	if metadata, ok := ac.modelMetadata.Get(providerType, modelName); ok {
+		config.ModelMetadata = metadata
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.model.config", gin.H{
 		"provider": providerType,
 		"model":    modelName,
 		"config":   config,
-	})
+	}, nil)
 }
 
 // EnableModel 启用指定提供商的模型
@@ -213,10 +251,10 @@ func (ac *AIController) EnableModel(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Model enabled successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.model.enabled", gin.H{
 		"provider": providerType,
 		"model":    modelName,
-	})
+	}, nil)
 }
 
 // DisableModel 禁用指定提供商的模型
@@ -257,10 +295,124 @@ func (ac *AIController) DisableModel(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Model disabled successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.model.disabled", gin.H{
 		"provider": providerType,
 		"model":    modelName,
+	}, nil)
+}
+
+// UpdateModelMetadata 覆盖保存指定模型的元数据：上下文窗口、价格和能力
+func (ac *AIController) UpdateModelMetadata(c *gin.Context) {
+	providerType := c.Param("provider")
+	modelName := c.Param("model")
+
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("ai"),
+		logger.Operation("update_model_metadata"),
+		logger.String("provider", providerType),
+		logger.String("model", modelName))
+
+	var req dto.UpdateModelMetadataRequest
+	if err := ac.BindAndValidate(c, &req); err != nil {
+		return
+	}
+
+	prov, err := ac.providerManager.GetProvider(provider.ProviderType(providerType))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid provider", err.Error())
+		return
+	}
+	if _, err := prov.GetModelConfig(modelName); err != nil {
+		ac.HandleError(c, errors.NewNotFoundError("Model"))
+		return
+	}
+
+	metadata, err := ac.modelMetadata.Update(c.Request.Context(), providerType, modelName, types.ModelMetadata{
+		ContextWindow:  req.ContextWindow,
+		InputPrice:     req.InputPrice,
+		OutputPrice:    req.OutputPrice,
+		SupportsTools:  req.SupportsTools,
+		SupportsVision: req.SupportsVision,
 	})
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.model.metadata.updated", gin.H{
+		"provider": providerType,
+		"model":    modelName,
+		"metadata": metadata,
+	}, nil)
+}
+
+// DeleteModelMetadata 删除指定模型的元数据
+func (ac *AIController) DeleteModelMetadata(c *gin.Context) {
+	providerType := c.Param("provider")
+	modelName := c.Param("model")
+
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("ai"),
+		logger.Operation("delete_model_metadata"),
+		logger.String("provider", providerType),
+		logger.String("model", modelName))
+
+	if err := ac.modelMetadata.Delete(c.Request.Context(), providerType, modelName); err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.model.metadata.deleted", gin.H{
+		"provider": providerType,
+		"model":    modelName,
+	}, nil)
+}
+
+// UpdateModels 批量启用或禁用指定提供商的模型，全部成功或全部不生效
+func (ac *AIController) UpdateModels(c *gin.Context) {
+	providerType := c.Param("provider")
+
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("ai"),
+		logger.Operation("update_models"),
+		logger.String("provider", providerType))
+
+	var req dto.BulkModelUpdateRequest
+	if err := ac.BindAndValidate(c, &req); err != nil {
+		return
+	}
+
+	changes := make([]provider.ModelChange, len(req.Changes))
+	for i, change := range req.Changes {
+		changes[i] = provider.ModelChange{Model: change.Model, Enabled: *change.Enabled}
+	}
+
+	results, err := ac.providerManager.SetModelsEnabled(provider.ProviderType(providerType), changes)
+	switch {
+	case stderrors.Is(err, provider.ErrModelChangesRejected):
+		ac.HandleError(c, errors.NewValidationError("Model changes rejected").WithMetadata("results", results))
+		return
+	case stderrors.Is(err, provider.ErrModelChangesFailed):
+		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
+			logger.Module(logger.ModuleController),
+			logger.Component("ai"),
+			logger.Operation("update_models"),
+			logger.String("provider", providerType),
+			logger.ZapError(err))
+		ac.HandleError(c, errors.NewOperationFailedError("update models").WithMetadata("results", results))
+		return
+	case err != nil:
+		response.Error(c, http.StatusBadRequest, "Invalid provider", err.Error())
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.models.updated", gin.H{
+		"provider": providerType,
+		"results":  results,
+	}, nil)
 }
 
 // ValidateAPIKey 验证指定提供商的API密钥
@@ -288,18 +440,40 @@ func (ac *AIController) ValidateAPIKey(c *gin.Context) {
 
 	err = prov.ValidateAPIKey(c.Request.Context())
 	if err != nil {
-		response.Success(c, http.StatusOK, "API key validation failed", gin.H{
+		response.I18nSuccess(c, http.StatusOK, "response.api.key.invalid", gin.H{
 			"provider": providerType,
 			"valid":    false,
 			"message":  err.Error(),
-		})
+		}, nil)
 		return
 	}
 
-	response.Success(c, http.StatusOK, "API key is valid", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.validated", gin.H{
 		"provider": providerType,
 		"valid":    true,
-	})
+	}, nil)
+}
+
+// setToolAPIKey 保存工具使用的API密钥（网页搜索后端、GitHub 和 Jira 令牌），不支持项目级密钥
+func (ac *AIController) setToolAPIKey(c *gin.Context, userID, projectID int64, backend, apiKey string) {
+	if projectID > 0 {
+		response.Error(c, http.StatusBadRequest, "Invalid project", fmt.Sprintf("%s keys cannot be scoped to a project", backend))
+		return
+	}
+	if err := ac.apiKeyService.SetAPIKey(c.Request.Context(), userID, 0, backend, apiKey); err != nil {
+		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
+			logger.Module(logger.ModuleController),
+			logger.Component("ai"),
+			logger.Operation("set_api_key"),
+			logger.String("provider", backend),
+			logger.String("user_id", strconv.FormatInt(userID, 10)),
+			logger.ZapError(err))
+		response.Error(c, http.StatusInternalServerError, "Failed to save API key", err.Error())
+		return
+	}
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.set", gin.H{
+		"provider": backend,
+	}, nil)
 }
 
 // SetAPIKey 设置指定提供商的API密钥
@@ -339,6 +513,17 @@ func (ac *AIController) SetAPIKey(c *gin.Context) {
 		return
 	}
 
+	projectID, ok := ac.projectFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
+	// 网页搜索后端、GitHub 和 Jira 不是AI提供商，密钥只供对应的工具按调用者读取
+	if tools.IsToolKeyProvider(providerType) {
+		ac.setToolAPIKey(c, userID, projectID, providerType, req.APIKey)
+		return
+	}
+
 	// 获取Provider
 	prov, err := ac.providerManager.GetProvider(provider.ProviderType(providerType))
 	if err != nil {
@@ -353,8 +538,30 @@ func (ac *AIController) SetAPIKey(c *gin.Context) {
 		return
 	}
 
+	// 校验模型限制中的模型都属于该提供商
+	allowedModels := make([]string, 0, len(req.AllowedModels))
+	for _, model := range req.AllowedModels {
+		model = strings.TrimSpace(model)
+		if _, err := prov.GetModelConfig(model); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid model", fmt.Sprintf("model %q is not available for provider %s", model, providerType))
+			return
+		}
+		allowedModels = append(allowedModels, model)
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		response.Error(c, http.StatusBadRequest, "Invalid expiry", "expires_at must be in the future")
+		return
+	}
+
 	// 保存API密钥到数据库
-	err = ac.apiKeyService.SetAPIKey(c.Request.Context(), userID, providerType, req.APIKey)
+	err = ac.apiKeyService.SetAPIKey(c.Request.Context(), userID, projectID, providerType, req.APIKey)
+	if err == nil {
+		err = ac.apiKeyService.SetAllowedModels(c.Request.Context(), userID, projectID, providerType, allowedModels)
+	}
+	if err == nil {
+		err = ac.apiKeyService.SetAPIKeyExpiration(c.Request.Context(), userID, projectID, providerType, req.ExpiresAt)
+	}
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -367,6 +574,17 @@ func (ac *AIController) SetAPIKey(c *gin.Context) {
 		return
 	}
 
+	// 项目密钥只用于指定了该项目的请求，不替换Provider的全局密钥
+	if projectID > 0 {
+		response.I18nSuccess(c, http.StatusOK, "response.api.key.set", gin.H{
+			"provider":       providerType,
+			"project":        c.Query("project"),
+			"allowed_models": allowedModels,
+			"expires_at":     req.ExpiresAt,
+		}, nil)
+		return
+	}
+
 	// 设置Provider的API密钥（用于当前会话）
 	err = prov.SetAPIKey(req.APIKey)
 	if err != nil {
@@ -381,15 +599,94 @@ func (ac *AIController) SetAPIKey(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, http.StatusOK, "API key set successfully", gin.H{
+	// 同步轮换宽限期内的上一版本密钥，新密钥鉴权失败时Provider会自动回退
+	ac.syncPreviousAPIKey(c, prov, userID, providerType)
+
+	// 同步共享密钥的模型限制，由Provider管理器在转发请求前检查
+	ac.providerManager.SetAllowedModels(provider.ProviderType(providerType), allowedModels)
+
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.set", gin.H{
+		"provider":       providerType,
+		"allowed_models": allowedModels,
+		"expires_at":     req.ExpiresAt,
+	}, nil)
+}
+
+// syncPreviousAPIKey 将仍处于宽限期的上一版本密钥同步到Provider
+func (ac *AIController) syncPreviousAPIKey(c *gin.Context, prov provider.Provider, userID int64, providerType string) {
+	previousKey, expiresAt, err := ac.apiKeyService.GetPreviousAPIKey(c.Request.Context(), userID, 0, providerType)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+			logger.WarnCtx(c.Request.Context(), logger.MsgAPIError,
+				logger.Module(logger.ModuleController),
+				logger.Component("ai"),
+				logger.Operation("set_api_key"),
+				logger.String("provider", providerType),
+				logger.String("user_id", strconv.FormatInt(userID, 10)),
+				logger.ZapError(err))
+		}
+		prov.SetPreviousAPIKey("", time.Time{})
+		return
+	}
+
+	prov.SetPreviousAPIKey(previousKey, expiresAt)
+}
+
+// ListAPIKeyVersions 获取指定提供商的API密钥轮换历史
+func (ac *AIController) ListAPIKeyVersions(c *gin.Context) {
+	providerType := c.Param("provider")
+
+	// 轮换历史只对登录用户开放，不回退到默认用户
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("ai"),
+		logger.Operation("list_api_key_versions"),
+		logger.String("provider", providerType),
+		logger.String("user_id", strconv.FormatInt(userID, 10)))
+
+	// 验证提供商类型
+	if !ac.isValidProviderType(providerType) {
+		response.Error(c, http.StatusBadRequest, "Invalid provider type", "Unsupported provider: "+providerType)
+		return
+	}
+
+	projectID, ok := ac.projectFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
+	versions, err := ac.apiKeyService.ListAPIKeyVersions(c.Request.Context(), userID, projectID, providerType)
+	if err != nil {
+		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
+			logger.Module(logger.ModuleController),
+			logger.Component("ai"),
+			logger.Operation("list_api_key_versions"),
+			logger.String("provider", providerType),
+			logger.String("user_id", strconv.FormatInt(userID, 10)),
+			logger.ZapError(err))
+		response.Error(c, http.StatusInternalServerError, "Failed to list API key versions", err.Error())
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.versions", gin.H{
 		"provider": providerType,
-	})
+		"versions": versions,
+	}, nil)
 }
 
 // APIKeyInfo API密钥信息结构体
 type APIKeyInfo struct {
-	HasKey    bool   `json:"has_key"`
-	MaskedKey string `json:"masked_key,omitempty"`
+	HasKey        bool                        `json:"has_key"`
+	MaskedKey     string                      `json:"masked_key,omitempty"`
+	AllowedModels []string                    `json:"allowed_models,omitempty"` // 为空表示不限制模型
+	Validation    *dto.APIKeyValidationStatus `json:"validation,omitempty"`     // 最近一次定期验证的结果
+	Expiration    *dto.APIKeyExpirationStatus `json:"expiration,omitempty"`     // 未设置过期时间时为空
 }
 
 // GetAPIKeyStatus 获取用户的API密钥状态
@@ -413,6 +710,11 @@ func (ac *AIController) GetAPIKeyStatus(c *gin.Context) {
 		logger.Operation("get_api_key_status"),
 		logger.String("user_id", strconv.FormatInt(userID, 10)))
 
+	projectID, ok := ac.projectFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
 	// 获取所有提供商的API密钥状态
 	apiKeyStatus := make(map[string]APIKeyInfo)
 	
@@ -420,7 +722,7 @@ func (ac *AIController) GetAPIKeyStatus(c *gin.Context) {
 	supportedProviders := []string{"openai", "googleai", "mock"}
 	
 	for _, providerType := range supportedProviders {
-		hasKey, err := ac.apiKeyService.CheckAPIKeyExists(c.Request.Context(), userID, providerType)
+		hasKey, err := ac.apiKeyService.CheckAPIKeyExists(c.Request.Context(), userID, projectID, providerType)
 		if err != nil {
 			logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 				logger.Module(logger.ModuleController),
@@ -438,7 +740,7 @@ func (ac *AIController) GetAPIKeyStatus(c *gin.Context) {
 		
 		// 如果有密钥，获取脱敏的密钥信息
 		if hasKey {
-			maskedKey, err := ac.apiKeyService.GetMaskedAPIKey(c.Request.Context(), userID, providerType)
+			maskedKey, err := ac.apiKeyService.GetMaskedAPIKey(c.Request.Context(), userID, projectID, providerType)
 			if err != nil {
 				logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 					logger.Module(logger.ModuleController),
@@ -452,6 +754,36 @@ func (ac *AIController) GetAPIKeyStatus(c *gin.Context) {
 			} else {
 				keyInfo.MaskedKey = maskedKey
 			}
+			
+			if allowedModels, err := ac.apiKeyService.GetAllowedModels(c.Request.Context(), userID, projectID, providerType); err == nil {
+				keyInfo.AllowedModels = allowedModels
+			}
+			
+			validation, err := ac.apiKeyService.GetAPIKeyValidation(c.Request.Context(), userID, projectID, providerType)
+			if err != nil {
+				logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
+					logger.Module(logger.ModuleController),
+					logger.Component("ai"),
+					logger.Operation("get_api_key_validation"),
+					logger.String("user_id", strconv.FormatInt(userID, 10)),
+					logger.String("provider", providerType),
+					logger.ZapError(err))
+			} else {
+				keyInfo.Validation = validation
+			}
+			
+			expiration, err := ac.apiKeyService.GetAPIKeyExpiration(c.Request.Context(), userID, projectID, providerType)
+			if err != nil {
+				logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
+					logger.Module(logger.ModuleController),
+					logger.Component("ai"),
+					logger.Operation("get_api_key_expiration"),
+					logger.String("user_id", strconv.FormatInt(userID, 10)),
+					logger.String("provider", providerType),
+					logger.ZapError(err))
+			} else {
+				keyInfo.Expiration = expiration
+			}
 		}
 		
 		apiKeyStatus[providerType] = keyInfo
@@ -463,25 +795,18 @@ func (ac *AIController) GetAPIKeyStatus(c *gin.Context) {
 		logger.Operation("get_api_key_status"),
 		logger.String("user_id", strconv.FormatInt(userID, 10)))
 
-	response.Success(c, http.StatusOK, "API key status retrieved successfully", apiKeyStatus)
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.status", apiKeyStatus, nil)
 }
 
-// GetPlainAPIKey 获取明文API密钥
+// GetPlainAPIKey 获取明文API密钥，路由层要求管理员、专用权限和近期重新认证
 func (ac *AIController) GetPlainAPIKey(c *gin.Context) {
 	providerType := c.Param("provider")
 	
-	// 获取用户ID，如果没有认证则使用默认用户ID
+	// 明文密钥只返回给已认证用户本人，不再回退到默认用户
 	userID, err := middleware.GetUserIDFromContext(c)
 	if err != nil {
-		// 在没有认证的情况下，使用默认用户ID 1
-		userID = 1
-		logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
-			logger.Module(logger.ModuleController),
-			logger.Component("ai"),
-			logger.Operation("get_plain_api_key"),
-			logger.String("provider", providerType),
-			logger.String("user_id", "1"),
-			logger.String("auth_status", "unauthenticated"))
+		ac.HandleError(c, err)
+		return
 	}
 
 	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
@@ -504,8 +829,13 @@ func (ac *AIController) GetPlainAPIKey(c *gin.Context) {
 		return
 	}
 
+	projectID, ok := ac.projectFromQuery(c, userID)
+	if !ok {
+		return
+	}
+
 	// 检查API密钥是否存在
-	hasKey, err := ac.apiKeyService.CheckAPIKeyExists(c.Request.Context(), userID, providerType)
+	hasKey, err := ac.apiKeyService.CheckAPIKeyExists(c.Request.Context(), userID, projectID, providerType)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -531,7 +861,7 @@ func (ac *AIController) GetPlainAPIKey(c *gin.Context) {
 	}
 
 	// 获取明文API密钥
-	plainKey, err := ac.apiKeyService.GetAPIKey(c.Request.Context(), userID, providerType)
+	plainKey, err := ac.apiKeyService.GetAPIKey(c.Request.Context(), userID, projectID, providerType)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -553,10 +883,20 @@ func (ac *AIController) GetPlainAPIKey(c *gin.Context) {
 		logger.String("user_id", strconv.FormatInt(userID, 10)),
 		logger.String("access_type", "plain_text"))
 
-	response.Success(c, http.StatusOK, "Plain API key retrieved successfully", gin.H{
+	response.I18nSuccess(c, http.StatusOK, "response.api.key.plain", gin.H{
 		"provider": providerType,
 		"api_key":  plainKey,
-	})
+	}, nil)
+}
+
+// projectFromQuery 解析 project 查询参数，未指定时返回 0 表示默认密钥
+func (ac *AIController) projectFromQuery(c *gin.Context, userID int64) (int64, bool) {
+	projectID, err := ac.projectService.Resolve(c.Request.Context(), userID, c.Query("project"))
+	if err != nil {
+		ac.HandleError(c, err)
+		return 0, false
+	}
+	return projectID, true
 }
 
 // isValidProviderType 验证提供商类型是否有效
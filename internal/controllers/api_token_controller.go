@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APITokenController 个人访问令牌控制器
+type APITokenController struct {
+	BaseController
+	apiTokenService service.APITokenService
+	logger          *zap.Logger
+}
+
+// NewAPITokenController 创建个人访问令牌控制器
+func NewAPITokenController(apiTokenService service.APITokenService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *APITokenController {
+	return &APITokenController{
+		BaseController:  *NewBaseController(errorHandler),
+		apiTokenService: apiTokenService,
+		logger:          logger,
+	}
+}
+
+// CreateToken 创建个人访问令牌
+func (tc *APITokenController) CreateToken(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		tc.HandleError(c, err)
+		return
+	}
+
+	var req dto.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := tc.apiTokenService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		tc.logger.Error("创建个人访问令牌失败", zap.Int64("user_id", userID), zap.Error(err))
+		tc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.tokens.created", result, nil)
+}
+
+// ListTokens 获取当前用户的个人访问令牌
+func (tc *APITokenController) ListTokens(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		tc.HandleError(c, err)
+		return
+	}
+
+	tokens, err := tc.apiTokenService.List(c.Request.Context(), userID)
+	if err != nil {
+		tc.logger.Error("获取个人访问令牌失败", zap.Int64("user_id", userID), zap.Error(err))
+		tc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.tokens.retrieved", tokens, nil)
+}
+
+// RevokeToken 吊销个人访问令牌
+func (tc *APITokenController) RevokeToken(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		tc.HandleError(c, err)
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		tc.HandleError(c, errors.NewValidationError("令牌ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := tc.apiTokenService.Revoke(c.Request.Context(), userID, tokenID); err != nil {
+		tc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.tokens.revoked", nil, nil)
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuthController 认证控制器
+type AuthController struct {
+	BaseController
+	authService service.AuthService
+	logger      *zap.Logger
+}
+
+// NewAuthController 创建认证控制器
+func NewAuthController(authService service.AuthService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *AuthController {
+	return &AuthController{
+		BaseController: *NewBaseController(errorHandler),
+		authService:    authService,
+		logger:         logger,
+	}
+}
+
+// Login 用户登录
+func (ac *AuthController) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := ac.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		ac.logger.Warn("用户登录失败", zap.String("username", req.Username), zap.Error(err))
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.auth.login", result, nil)
+}
+
+// Refresh 刷新访问令牌
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := ac.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		ac.logger.Warn("刷新令牌失败", zap.Error(err))
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.auth.refreshed", result, nil)
+}
+
+// Logout 用户登出，吊销刷新令牌
+func (ac *AuthController) Logout(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := ac.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		ac.logger.Error("用户登出失败", zap.Error(err))
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.auth.logout", nil, nil)
+}
+
+// Reauthenticate 校验当前用户密码，签发可用于敏感操作的访问令牌
+func (ac *AuthController) Reauthenticate(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	var req dto.ReauthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := ac.authService.Reauthenticate(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.auth.reauthenticated", result, nil)
+}
@@ -34,12 +34,21 @@ func (bc *BaseController) HandleValidationError(c *gin.Context, err error) {
 		for _, e := range validationErrors {
 			errorMessages = append(errorMessages, utils.GetValidationErrorMessage(e))
 		}
-		response.BadRequest(c, "Validation failed", strings.Join(errorMessages, "; "))
+		bc.badRequest(c, "Validation failed", strings.Join(errorMessages, "; "))
 		return
 	}
 
 	// 其他类型的绑定错误
-	response.BadRequest(c, "Invalid request data", err.Error())
+	bc.badRequest(c, "Invalid request data", err.Error())
+}
+
+// badRequest 返回400，客户端要求 problem+json 时交给错误处理器
+func (bc *BaseController) badRequest(c *gin.Context, message, detail string) {
+	if bc.errorHandler != nil && bc.errorHandler.ProblemRequested(c) {
+		bc.errorHandler.HandleError(c, errors.NewValidationError(message+": "+detail))
+		return
+	}
+	response.BadRequest(c, message, detail)
 }
 
 
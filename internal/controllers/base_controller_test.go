@@ -10,6 +10,7 @@ import (
 
 	"go-springAi/internal/errors"
 	"go-springAi/internal/i18n"
+	"go-springAi/internal/requestid"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -395,4 +396,98 @@ func TestBaseController_HandleError(t *testing.T) {
 	responseBody := w.Body.String()
 	assert.Contains(t, responseBody, "error")
 	assert.Contains(t, responseBody, "INTERNAL_ERROR")
-}
\ No newline at end of file
+}
+
+func TestBaseController_ProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		accept      string
+		lang        string
+		err         error
+		wantProblem bool
+		wantStatus  int
+		wantType    string
+		wantTitle   string
+	}{
+		{
+			name:       "Default format",
+			err:        errors.NewUserNotFoundError(),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:        "Requested by Accept header",
+			accept:      "application/json, application/problem+json;q=0.9",
+			lang:        "en",
+			err:         errors.NewUserNotFoundError(),
+			wantProblem: true,
+			wantStatus:  http.StatusNotFound,
+			wantType:    "https://docs.example.com/problems/user-not-found",
+			wantTitle:   "User Not Found",
+		},
+		{
+			name:        "Enabled by configuration with localized title",
+			enabled:     true,
+			lang:        "zh",
+			err:         errors.NewUserNotFoundError(),
+			wantProblem: true,
+			wantStatus:  http.StatusNotFound,
+			wantType:    "https://docs.example.com/problems/user-not-found",
+			wantTitle:   "用户未找到",
+		},
+		{
+			name:        "Binding error with default language",
+			enabled:     true,
+			wantProblem: true,
+			wantStatus:  http.StatusBadRequest,
+			wantType:    "https://docs.example.com/problems/validation-failed",
+			wantTitle:   "验证失败",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errorHandler := createTestErrorHandler()
+			errorHandler.SetProblemDetails(tt.enabled, "https://docs.example.com/problems/")
+			controller := NewBaseController(errorHandler)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"name": 1}`))
+			c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), "req-1"))
+			c.Request.Header.Set("Content-Type", "application/json")
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+			if tt.lang != "" {
+				c.Request.Header.Set("Accept-Language", tt.lang)
+			}
+
+			if tt.err != nil {
+				controller.HandleError(c, tt.err)
+			} else {
+				var req struct {
+					Name string `json:"name"`
+				}
+				require.Error(t, controller.BindAndValidate(c, &req))
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if !tt.wantProblem {
+				assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+				return
+			}
+			assert.Equal(t, errors.ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem errors.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, tt.wantType, problem.Type)
+			assert.Equal(t, tt.wantTitle, problem.Title)
+			assert.Equal(t, tt.wantStatus, problem.Status)
+			assert.Equal(t, "req-1", problem.Instance)
+			assert.NotEmpty(t, problem.Detail)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ProjectHeader OpenAI SDK 配置 project 时发送的请求头，对应本服务的项目
+const ProjectHeader = "OpenAI-Project"
+
+// ChatCompletionController OpenAI 兼容接口控制器，OpenAI SDK、LangChain、continue.dev 等可直接接入
+type ChatCompletionController struct {
+	*BaseController
+	aiAssistantService *service.AIAssistantService
+	providerManager    *provider.Manager
+	logger             *zap.Logger
+}
+
+// NewChatCompletionController 创建 OpenAI 兼容接口控制器
+func NewChatCompletionController(aiAssistantService *service.AIAssistantService, providerManager *provider.Manager, logger *zap.Logger, errorHandler *errors.ErrorHandler) *ChatCompletionController {
+	return &ChatCompletionController{
+		BaseController:     NewBaseController(errorHandler),
+		aiAssistantService: aiAssistantService,
+		providerManager:    providerManager,
+		logger:             logger,
+	}
+}
+
+// ChatCompletions 聊天补全，stream 为 true 时以 SSE 返回分块并以 data: [DONE] 结束
+func (cc *ChatCompletionController) ChatCompletions(c *gin.Context) {
+	var req dto.ChatCompletionRequest
+	if err := cc.BindAndValidate(c, &req); err != nil {
+		return
+	}
+
+	// 未认证的请求按匿名用户统计配额
+	userID, _ := middleware.GetUserIDFromContext(c)
+	project := c.GetHeader(ProjectHeader)
+
+	if !req.Stream {
+		resp, err := cc.aiAssistantService.ChatCompletion(c.Request.Context(), &req, userID, project)
+		if err != nil {
+			cc.handleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	started := false
+	err := cc.aiAssistantService.ChatCompletionStream(c.Request.Context(), &req, userID, project, func(chunk *dto.ChatCompletionChunk) error {
+		if !started {
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		return writeSSEData(c, chunk)
+	})
+	if err != nil {
+		if !started {
+			cc.handleError(c, err)
+			return
+		}
+		// 响应头已发送，只能在流中返回错误
+		cc.logger.Error("Chat completion stream failed", zap.String("model", req.Model), zap.Error(err))
+		writeSSEData(c, gin.H{"error": gin.H{"message": err.Error(), "type": "server_error"}})
+		return
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
+
+// ListModels 列出所有提供商已启用的模型，按模型名排序
+func (cc *ChatCompletionController) ListModels(c *gin.Context) {
+	ctx := c.Request.Context()
+	models := make([]dto.ModelInfo, 0)
+	for _, info := range cc.providerManager.ListProviders(ctx) {
+		prov, err := cc.providerManager.GetProvider(info.Type)
+		if err != nil {
+			continue
+		}
+		configs, err := prov.ListModels(ctx)
+		if err != nil {
+			cc.logger.Warn("Failed to list provider models", zap.String("provider", string(info.Type)), zap.Error(err))
+			continue
+		}
+		for name := range configs {
+			models = append(models, dto.ModelInfo{ID: name, Object: dto.ModelObject, OwnedBy: string(info.Type)})
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+
+	c.JSON(http.StatusOK, dto.ModelList{Object: dto.ModelListObject, Data: models})
+}
+
+// handleError 应用错误由错误处理器返回状态码和附加信息，其他错误交给错误中间件
+func (cc *ChatCompletionController) handleError(c *gin.Context, err error) {
+	if _, ok := errors.IsAppError(err); ok {
+		cc.HandleError(c, err)
+		return
+	}
+	c.Error(err)
+}
+
+// writeSSEData 以 data: 事件写出JSON并立即刷新
+func writeSSEData(c *gin.Context, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
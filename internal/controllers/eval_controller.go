@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EvalController 提示词评测控制器
+type EvalController struct {
+	BaseController
+	evalService service.EvalService
+	logger      *zap.Logger
+}
+
+// NewEvalController 创建提示词评测控制器
+func NewEvalController(evalService service.EvalService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *EvalController {
+	return &EvalController{
+		BaseController: *NewBaseController(errorHandler),
+		evalService:    evalService,
+		logger:         logger,
+	}
+}
+
+// ListSuites 获取所有评测套件
+func (ec *EvalController) ListSuites(c *gin.Context) {
+	result, err := ec.evalService.ListSuites(c.Request.Context())
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.suites_retrieved", result, nil)
+}
+
+// GetSuite 获取评测套件
+func (ec *EvalController) GetSuite(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	suite, err := ec.evalService.GetSuite(c.Request.Context(), id)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.suite_retrieved", suite, nil)
+}
+
+// CreateSuite 创建评测套件
+func (ec *EvalController) CreateSuite(c *gin.Context) {
+	var req dto.CreateEvalSuiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ec.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	suite, err := ec.evalService.CreateSuite(c.Request.Context(), userID, &req)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.eval.suite_created", suite, nil)
+}
+
+// UpdateSuite 更新评测套件
+func (ec *EvalController) UpdateSuite(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateEvalSuiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ec.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	suite, err := ec.evalService.UpdateSuite(c.Request.Context(), id, &req)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.suite_updated", suite, nil)
+}
+
+// DeleteSuite 删除评测套件及其运行记录
+func (ec *EvalController) DeleteSuite(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := ec.evalService.DeleteSuite(c.Request.Context(), id); err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.suite_deleted", nil, nil)
+}
+
+// StartRun 在后台运行评测套件，通过 GetRun 查看结果
+func (ec *EvalController) StartRun(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.CreateEvalRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ec.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	run, err := ec.evalService.StartRun(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		ec.logger.Error("启动评测失败", zap.Int64("suite_id", id), zap.Error(err))
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusAccepted, "response.eval.run_started", run, nil)
+}
+
+// ListRuns 获取套件最近的运行记录，limit 默认 20，最大 100
+func (ec *EvalController) ListRuns(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			ec.HandleError(c, errors.NewValidationError("limit 必须为正整数"))
+			return
+		}
+	}
+
+	result, err := ec.evalService.ListRuns(c.Request.Context(), id, limit)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.runs_retrieved", result, nil)
+}
+
+// GetRun 获取运行记录及逐用例结果
+func (ec *EvalController) GetRun(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	run, err := ec.evalService.GetRun(c.Request.Context(), id)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.run_retrieved", run, nil)
+}
+
+// CompareRuns 对比同一套件的多次运行，runs 为逗号分隔的运行ID
+func (ec *EvalController) CompareRuns(c *gin.Context) {
+	var runIDs []int64
+	for _, raw := range strings.Split(c.Query("runs"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			ec.HandleError(c, errors.NewValidationError("ID无效").WithDetails("runs"))
+			return
+		}
+		runIDs = append(runIDs, id)
+	}
+
+	result, err := ec.evalService.Compare(c.Request.Context(), runIDs)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.eval.compared", result, nil)
+}
+
+// parseID 解析路径中的ID，失败时已写入错误响应
+func (ec *EvalController) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ec.HandleError(c, errors.NewValidationError("ID无效").WithDetails("id"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExecutionLogArchiveController 执行日志归档管理控制器
+type ExecutionLogArchiveController struct {
+	BaseController
+	archiveService service.ExecutionLogArchiveService
+}
+
+// NewExecutionLogArchiveController 创建执行日志归档管理控制器
+func NewExecutionLogArchiveController(archiveService service.ExecutionLogArchiveService, errorHandler *errors.ErrorHandler) *ExecutionLogArchiveController {
+	return &ExecutionLogArchiveController{
+		BaseController: *NewBaseController(errorHandler),
+		archiveService: archiveService,
+	}
+}
+
+// ListArchives 列出与时间范围有交集的归档
+func (ac *ExecutionLogArchiveController) ListArchives(c *gin.Context) {
+	var query dto.MCPExecutionLogArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	archives, err := ac.archiveService.ListArchives(c.Request.Context(), query.From, query.To)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.archives.retrieved", gin.H{"archives": archives}, nil)
+}
+
+// QueryArchivedLogs 查询已归档的执行日志
+func (ac *ExecutionLogArchiveController) QueryArchivedLogs(c *gin.Context) {
+	var query dto.MCPExecutionLogArchiveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ac.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	logs, err := ac.archiveService.QueryArchived(c.Request.Context(), &query)
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.archives.logs", gin.H{"logs": logs, "count": len(logs)}, nil)
+}
+
+// ArchiveNow 立即归档超过保留期的执行日志
+func (ac *ExecutionLogArchiveController) ArchiveNow(c *gin.Context) {
+	archive, err := ac.archiveService.Archive(c.Request.Context())
+	if err != nil {
+		ac.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.archives.created", gin.H{"archive": archive}, nil)
+}
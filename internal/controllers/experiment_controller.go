@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExperimentController A/B 实验控制器
+type ExperimentController struct {
+	BaseController
+	experimentService service.ExperimentService
+	logger            *zap.Logger
+}
+
+// NewExperimentController 创建 A/B 实验控制器
+func NewExperimentController(experimentService service.ExperimentService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *ExperimentController {
+	return &ExperimentController{
+		BaseController:    *NewBaseController(errorHandler),
+		experimentService: experimentService,
+		logger:            logger,
+	}
+}
+
+// ListExperiments 获取所有实验
+func (ec *ExperimentController) ListExperiments(c *gin.Context) {
+	result, err := ec.experimentService.List(c.Request.Context())
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.list_retrieved", result, nil)
+}
+
+// GetExperiment 获取实验
+func (ec *ExperimentController) GetExperiment(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	experiment, err := ec.experimentService.Get(c.Request.Context(), id)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.retrieved", experiment, nil)
+}
+
+// CreateExperiment 创建草稿状态的实验
+func (ec *ExperimentController) CreateExperiment(c *gin.Context) {
+	var req dto.CreateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ec.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	experiment, err := ec.experimentService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.experiment.created", experiment, nil)
+}
+
+// UpdateExperiment 更新草稿状态的实验
+func (ec *ExperimentController) UpdateExperiment(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ec.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	experiment, err := ec.experimentService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.updated", experiment, nil)
+}
+
+// DeleteExperiment 删除实验及其结果
+func (ec *ExperimentController) DeleteExperiment(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := ec.experimentService.Delete(c.Request.Context(), id); err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.deleted", nil, nil)
+}
+
+// StartExperiment 开始实验，角色的聊天请求开始按流量比例分配到两个变体
+func (ec *ExperimentController) StartExperiment(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	experiment, err := ec.experimentService.Start(c.Request.Context(), id)
+	if err != nil {
+		ec.logger.Error("开始实验失败", zap.Int64("experiment_id", id), zap.Error(err))
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.started", experiment, nil)
+}
+
+// StopExperiment 停止运行中的实验
+func (ec *ExperimentController) StopExperiment(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	experiment, err := ec.experimentService.Stop(c.Request.Context(), id)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.stopped", experiment, nil)
+}
+
+// GetReport 获取各变体的结果汇总和胜出的变体
+func (ec *ExperimentController) GetReport(c *gin.Context) {
+	id, ok := ec.parseID(c)
+	if !ok {
+		return
+	}
+
+	report, err := ec.experimentService.Report(c.Request.Context(), id)
+	if err != nil {
+		ec.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.experiment.report_retrieved", report, nil)
+}
+
+// parseID 解析路径中的ID，失败时已写入错误响应
+func (ec *ExperimentController) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		ec.HandleError(c, errors.NewValidationError("ID无效").WithDetails("id"))
+		return 0, false
+	}
+	return id, true
+}
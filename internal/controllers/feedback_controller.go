@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FeedbackController 助手回复评价控制器
+type FeedbackController struct {
+	BaseController
+	feedbackService service.FeedbackService
+	logger          *zap.Logger
+}
+
+// NewFeedbackController 创建回复评价控制器
+func NewFeedbackController(feedbackService service.FeedbackService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *FeedbackController {
+	return &FeedbackController{
+		BaseController:  *NewBaseController(errorHandler),
+		feedbackService: feedbackService,
+		logger:          logger,
+	}
+}
+
+// SubmitFeedback 评价助手的一条回复，需要登录
+func (fc *FeedbackController) SubmitFeedback(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+	var req dto.ResponseFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	feedback, err := fc.feedbackService.Submit(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.feedback.submitted", feedback, nil)
+}
+
+// ListFeedback 获取最近的评价，可按 rating 过滤
+func (fc *FeedbackController) ListFeedback(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			fc.HandleError(c, errors.NewValidationError("limit 必须为正整数"))
+			return
+		}
+	}
+
+	result, err := fc.feedbackService.List(c.Request.Context(), c.Query("rating"), limit)
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.feedback.list_retrieved", result, nil)
+}
+
+// ExportToEval 把最近的差评导出为评测用例
+func (fc *FeedbackController) ExportToEval(c *gin.Context) {
+	var req dto.ExportFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	result, err := fc.feedbackService.ExportToEval(c.Request.Context(), &req)
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.feedback.exported", result, nil)
+}
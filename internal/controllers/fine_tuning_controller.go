@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FineTuningController OpenAI 微调任务管理控制器
+type FineTuningController struct {
+	BaseController
+	fineTuningService service.FineTuningService
+	logger            *zap.Logger
+}
+
+// NewFineTuningController 创建微调任务管理控制器
+func NewFineTuningController(fineTuningService service.FineTuningService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *FineTuningController {
+	return &FineTuningController{
+		BaseController:    *NewBaseController(errorHandler),
+		fineTuningService: fineTuningService,
+		logger:            logger,
+	}
+}
+
+// UploadFile 上传 JSONL 训练文件，表单字段为 file
+func (fc *FineTuningController) UploadFile(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		fc.HandleError(c, errors.NewValidationError("缺少训练文件").WithDetails(err.Error()))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		fc.HandleError(c, errors.NewFileUploadFailedError(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	result, err := fc.fineTuningService.UploadFile(c.Request.Context(), header.Filename, file)
+	if err != nil {
+		fc.logger.Error("上传微调训练文件失败", zap.String("filename", header.Filename), zap.Error(err))
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.fine_tuning.file_uploaded", result, nil)
+}
+
+// CreateJob 创建微调任务
+func (fc *FineTuningController) CreateJob(c *gin.Context) {
+	var req dto.CreateFineTuningJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		fc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	job, err := fc.fineTuningService.CreateJob(c.Request.Context(), userID, &req)
+	if err != nil {
+		fc.logger.Error("创建微调任务失败", zap.String("model", req.Model), zap.String("training_file", req.TrainingFile), zap.Error(err))
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.fine_tuning.job_created", job, nil)
+}
+
+// ListJobs 获取最近的微调任务，limit 默认 20，最大 100
+func (fc *FineTuningController) ListJobs(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			fc.HandleError(c, errors.NewValidationError("limit 必须为正整数"))
+			return
+		}
+	}
+
+	result, err := fc.fineTuningService.ListJobs(c.Request.Context(), limit)
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.fine_tuning.jobs_retrieved", result, nil)
+}
+
+// GetJob 获取微调任务的最新状态
+func (fc *FineTuningController) GetJob(c *gin.Context) {
+	job, err := fc.fineTuningService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.fine_tuning.job_retrieved", job, nil)
+}
+
+// CancelJob 取消微调任务
+func (fc *FineTuningController) CancelJob(c *gin.Context) {
+	job, err := fc.fineTuningService.CancelJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		fc.logger.Error("取消微调任务失败", zap.String("job_id", c.Param("id")), zap.Error(err))
+		fc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.fine_tuning.job_cancelled", job, nil)
+}
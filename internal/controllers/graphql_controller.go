@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/graphqlapi"
+	"go-springAi/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLController 管理后台 GraphQL 查询控制器
+type GraphQLController struct {
+	BaseController
+	schema *graphqlapi.Schema
+}
+
+// NewGraphQLController 创建 GraphQL 查询控制器
+func NewGraphQLController(schema *graphqlapi.Schema, errorHandler *errors.ErrorHandler) *GraphQLController {
+	return &GraphQLController{
+		BaseController: *NewBaseController(errorHandler),
+		schema:         schema,
+	}
+}
+
+// Query 执行 GraphQL 查询，响应使用 GraphQL 的 data/errors 格式
+func (gc *GraphQLController) Query(c *gin.Context) {
+	var req dto.GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		gc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		gc.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gc.schema.Exec(c.Request.Context(), userID, &req))
+}
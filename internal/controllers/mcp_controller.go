@@ -10,6 +10,7 @@ import (
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
 	"go-springAi/internal/logger"
+	"go-springAi/internal/pagination"
 	"go-springAi/internal/response"
 	"go-springAi/internal/service"
 
@@ -73,7 +74,7 @@ func (mc *MCPController) Initialize(c *gin.Context) {
 		logger.String("protocolVersion", result.ProtocolVersion),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "MCP service initialized successfully", result)
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.initialized", result, nil)
 }
 
 // ListTools 获取工具列表
@@ -103,7 +104,7 @@ func (mc *MCPController) ListTools(c *gin.Context) {
 		logger.Int("toolCount", len(result.Tools)),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "Tools retrieved successfully", result)
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.tools", result, nil)
 }
 
 // ExecuteTool 执行工具
@@ -128,7 +129,13 @@ func (mc *MCPController) ExecuteTool(c *gin.Context) {
 		return
 	}
 
-	result, err := mc.mcpService.ExecuteTool(c.Request.Context(), &req)
+	// 传递调用者身份，工具据此使用用户自己保存的外部服务密钥，执行日志也记录调用者
+	ctx := c.Request.Context()
+	if userID := c.GetString("user_id"); userID != "" {
+		ctx = service.WithCaller(ctx, userID)
+	}
+
+	result, err := mc.mcpService.ExecuteTool(ctx, &req)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -148,7 +155,7 @@ func (mc *MCPController) ExecuteTool(c *gin.Context) {
 		logger.Bool("isError", result.IsError),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "Tool executed successfully", result)
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.executed", result, nil)
 }
 
 // StreamSSE SSE流式端点
@@ -167,8 +174,6 @@ func (mc *MCPController) StreamSSE(c *gin.Context) {
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
-	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Headers", "Cache-Control")
 
 	// 添加SSE客户端
 	eventChan := mc.mcpService.(*service.MCPServiceImpl).AddSSEClient(clientID)
@@ -206,7 +211,11 @@ func (mc *MCPController) StreamSSE(c *gin.Context) {
 				logger.String("clientId", clientID))
 			return
 
-		case event := <-eventChan:
+		case event, ok := <-eventChan:
+			// 通道关闭表示服务正在关闭
+			if !ok {
+				return
+			}
 			if err := mc.writeSSEEvent(c, event); err != nil {
 				logger.ErrorCtx(c.Request.Context(), "Failed to write SSE event",
 					logger.Module(logger.ModuleController),
@@ -303,7 +312,7 @@ func (mc *MCPController) GetExecutionLog(c *gin.Context) {
 		logger.String("executionId", executionID),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "Execution log retrieved successfully", result)
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.log", result, nil)
 }
 
 // GetStatus 获取MCP系统状态
@@ -351,7 +360,36 @@ func (mc *MCPController) GetStatus(c *gin.Context) {
 		logger.Int("toolCount", toolCount),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "MCP status retrieved successfully", status)
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.status", status, nil)
+}
+
+// SelfTest 按MCP规范自检初始化、工具列表、工具调用和错误路径，请求体可选，
+// 指定 tool 时会真实执行该工具；有违规时仍返回200，由 passed 字段表示结果
+func (mc *MCPController) SelfTest(c *gin.Context) {
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIRequest,
+		logger.Module(logger.ModuleController),
+		logger.Component("mcp"),
+		logger.Operation("selftest"),
+		logger.String("method", c.Request.Method),
+		logger.String("path", c.Request.URL.Path))
+
+	var req dto.MCPSelfTestRequest
+	if c.Request.ContentLength != 0 {
+		if err := mc.BindAndValidate(c, &req); err != nil {
+			return
+		}
+	}
+
+	report := service.RunMCPSelfTest(c.Request.Context(), mc.mcpService, &req)
+
+	logger.InfoCtx(c.Request.Context(), logger.MsgAPIResponse,
+		logger.Module(logger.ModuleController),
+		logger.Component("mcp"),
+		logger.Operation("selftest"),
+		logger.Bool("passed", report.Passed),
+		logger.Int("status", http.StatusOK))
+
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.selftest", report, nil)
 }
 
 // ListExecutionLogs 列出执行日志
@@ -369,14 +407,14 @@ func (mc *MCPController) ListExecutionLogs(c *gin.Context) {
 		userID = &uid
 	}
 
-	limit := 50 // 默认限制
+	limit := 0 // 默认50条，最多100条
 	if limitStr := c.Query("limit"); limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
 			limit = parsedLimit
 		}
 	}
 
-	result, err := mc.mcpService.ListExecutionLogs(c.Request.Context(), userID, limit)
+	result, err := mc.mcpService.ListExecutionLogsPage(c.Request.Context(), userID, c.Query(pagination.CursorParam), limit)
 	if err != nil {
 		logger.ErrorCtx(c.Request.Context(), logger.MsgAPIError,
 			logger.Module(logger.ModuleController),
@@ -386,17 +424,14 @@ func (mc *MCPController) ListExecutionLogs(c *gin.Context) {
 		c.Error(err)
 		return
 	}
+	pagination.Links(&result.PageInfo, c.Request.URL)
 
 	logger.InfoCtx(c.Request.Context(), logger.MsgAPIResponse,
 		logger.Module(logger.ModuleController),
 		logger.Component("mcp"),
 		logger.Operation("list_execution_logs"),
-		logger.Int("logCount", len(result)),
+		logger.Int("logCount", result.Count),
 		logger.Int("status", http.StatusOK))
 
-	response.Success(c, http.StatusOK, "Execution logs retrieved successfully", map[string]interface{}{
-		"logs":  result,
-		"count": len(result),
-		"limit": limit,
-	})
+	response.I18nSuccess(c, http.StatusOK, "response.mcp.logs", result, nil)
 }
\ No newline at end of file
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MemoryController 用户长期记忆控制器，只能查看和删除自己的记忆
+type MemoryController struct {
+	BaseController
+	memoryService service.MemoryService
+	logger        *zap.Logger
+}
+
+// NewMemoryController 创建用户长期记忆控制器
+func NewMemoryController(memoryService service.MemoryService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *MemoryController {
+	return &MemoryController{
+		BaseController: *NewBaseController(errorHandler),
+		memoryService:  memoryService,
+		logger:         logger,
+	}
+}
+
+// ListMemories 获取当前用户的所有记忆
+func (mc *MemoryController) ListMemories(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	result, err := mc.memoryService.List(c.Request.Context(), userID)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.memory.list_retrieved", result, nil)
+}
+
+// DeleteMemory 删除当前用户的一条记忆
+func (mc *MemoryController) DeleteMemory(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+	id, ok := mc.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := mc.memoryService.Delete(c.Request.Context(), userID, id); err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.memory.deleted", nil, nil)
+}
+
+// DeleteMemories 删除当前用户的所有记忆
+func (mc *MemoryController) DeleteMemories(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	result, err := mc.memoryService.DeleteAll(c.Request.Context(), userID)
+	if err != nil {
+		mc.logger.Error("删除用户记忆失败", zap.Int64("user_id", userID), zap.Error(err))
+		mc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.memory.cleared", result, nil)
+}
+
+// GetSettings 获取当前用户的记忆设置
+func (mc *MemoryController) GetSettings(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	settings, err := mc.memoryService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.memory.settings_retrieved", settings, nil)
+}
+
+// UpdateSettings 开启或关闭当前用户的记忆
+func (mc *MemoryController) UpdateSettings(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		mc.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdateMemorySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	settings, err := mc.memoryService.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		mc.logger.Error("更新用户记忆设置失败", zap.Int64("user_id", userID), zap.Error(err))
+		mc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.memory.settings_updated", settings, nil)
+}
+
+// parseID 解析路径中的ID，失败时已写入错误响应
+func (mc *MemoryController) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		mc.HandleError(c, errors.NewValidationError("ID无效").WithDetails("id"))
+		return 0, false
+	}
+	return id, true
+}
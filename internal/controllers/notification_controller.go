@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NotificationController 用户通知渠道控制器
+type NotificationController struct {
+	BaseController
+	notificationService service.NotificationService
+	logger              *zap.Logger
+}
+
+// NewNotificationController 创建用户通知渠道控制器
+func NewNotificationController(notificationService service.NotificationService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *NotificationController {
+	return &NotificationController{
+		BaseController:      *NewBaseController(errorHandler),
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// ListChannels 获取当前用户的通知渠道
+func (nc *NotificationController) ListChannels(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		nc.HandleError(c, err)
+		return
+	}
+
+	result, err := nc.notificationService.List(c.Request.Context(), userID)
+	if err != nil {
+		nc.logger.Error("获取通知渠道失败", zap.Int64("user_id", userID), zap.Error(err))
+		nc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.notifications.retrieved", result, nil)
+}
+
+// SaveChannel 创建或覆盖当前用户的通知渠道
+func (nc *NotificationController) SaveChannel(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		nc.HandleError(c, err)
+		return
+	}
+
+	var req dto.SaveNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		nc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	channel, err := nc.notificationService.Save(c.Request.Context(), userID, c.Param("channel"), &req)
+	if err != nil {
+		nc.logger.Error("保存通知渠道失败", zap.Int64("user_id", userID), zap.String("channel", c.Param("channel")), zap.Error(err))
+		nc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.notifications.saved", channel, nil)
+}
+
+// DeleteChannel 删除当前用户的通知渠道
+func (nc *NotificationController) DeleteChannel(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		nc.HandleError(c, err)
+		return
+	}
+
+	if err := nc.notificationService.Delete(c.Request.Context(), userID, c.Param("channel")); err != nil {
+		nc.logger.Error("删除通知渠道失败", zap.Int64("user_id", userID), zap.String("channel", c.Param("channel")), zap.Error(err))
+		nc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.notifications.deleted", nil, nil)
+}
+
+// TestChannel 向当前用户的通知渠道发送测试通知
+func (nc *NotificationController) TestChannel(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		nc.HandleError(c, err)
+		return
+	}
+
+	if err := nc.notificationService.Test(c.Request.Context(), userID, c.Param("channel")); err != nil {
+		nc.logger.Warn("发送测试通知失败", zap.Int64("user_id", userID), zap.String("channel", c.Param("channel")), zap.Error(err))
+		nc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.notifications.test_sent", nil, nil)
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PersonaController 助手角色控制器
+type PersonaController struct {
+	BaseController
+	personaService service.PersonaService
+	logger         *zap.Logger
+}
+
+// NewPersonaController 创建助手角色控制器
+func NewPersonaController(personaService service.PersonaService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *PersonaController {
+	return &PersonaController{
+		BaseController: *NewBaseController(errorHandler),
+		personaService: personaService,
+		logger:         logger,
+	}
+}
+
+// ListPersonas 获取所有角色
+func (pc *PersonaController) ListPersonas(c *gin.Context) {
+	result, err := pc.personaService.List(c.Request.Context())
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.persona.list_retrieved", result, nil)
+}
+
+// GetPersona 获取角色
+func (pc *PersonaController) GetPersona(c *gin.Context) {
+	id, ok := pc.parseID(c)
+	if !ok {
+		return
+	}
+
+	persona, err := pc.personaService.Get(c.Request.Context(), id)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.persona.retrieved", persona, nil)
+}
+
+// CreatePersona 创建角色
+func (pc *PersonaController) CreatePersona(c *gin.Context) {
+	var req dto.PersonaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	persona, err := pc.personaService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.persona.created", persona, nil)
+}
+
+// UpdatePersona 覆盖保存角色
+func (pc *PersonaController) UpdatePersona(c *gin.Context) {
+	id, ok := pc.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.PersonaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	persona, err := pc.personaService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.persona.updated", persona, nil)
+}
+
+// DeletePersona 删除角色
+func (pc *PersonaController) DeletePersona(c *gin.Context) {
+	id, ok := pc.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.personaService.Delete(c.Request.Context(), id); err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.persona.deleted", nil, nil)
+}
+
+// parseID 解析路径中的ID，失败时已写入错误响应
+func (pc *PersonaController) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		pc.HandleError(c, errors.NewValidationError("ID无效").WithDetails("id"))
+		return 0, false
+	}
+	return id, true
+}
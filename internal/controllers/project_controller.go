@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ProjectController 项目控制器
+type ProjectController struct {
+	BaseController
+	projectService service.ProjectService
+	logger         *zap.Logger
+}
+
+// NewProjectController 创建项目控制器
+func NewProjectController(projectService service.ProjectService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *ProjectController {
+	return &ProjectController{
+		BaseController: *NewBaseController(errorHandler),
+		projectService: projectService,
+		logger:         logger,
+	}
+}
+
+// CreateProject 创建项目
+func (pc *ProjectController) CreateProject(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	project, err := pc.projectService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		pc.logger.Error("创建项目失败", zap.Int64("user_id", userID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.projects.created", project, nil)
+}
+
+// ListProjects 获取当前用户的项目
+func (pc *ProjectController) ListProjects(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	projects, err := pc.projectService.List(c.Request.Context(), userID)
+	if err != nil {
+		pc.logger.Error("获取项目列表失败", zap.Int64("user_id", userID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.projects.retrieved", projects, nil)
+}
+
+// DeleteProject 删除项目及其API密钥
+func (pc *ProjectController) DeleteProject(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		pc.HandleError(c, errors.NewValidationError("项目ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	if err := pc.projectService.Delete(c.Request.Context(), userID, projectID); err != nil {
+		pc.logger.Error("删除项目失败", zap.Int64("user_id", userID), zap.Int64("project_id", projectID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.projects.deleted", nil, nil)
+}
+
+// GetProjectUsage 获取项目最近的AI用量，days 默认 30
+func (pc *ProjectController) GetProjectUsage(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		pc.HandleError(c, errors.NewValidationError("项目ID无效").WithDetails(err.Error()))
+		return
+	}
+
+	days := 0
+	if raw := c.Query("days"); raw != "" {
+		days, err = strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			pc.HandleError(c, errors.NewValidationError("days 必须为正整数"))
+			return
+		}
+	}
+
+	usage, err := pc.projectService.GetUsage(c.Request.Context(), userID, projectID, days)
+	if err != nil {
+		pc.logger.Error("获取项目用量失败", zap.Int64("user_id", userID), zap.Int64("project_id", projectID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.projects.usage", usage, nil)
+}
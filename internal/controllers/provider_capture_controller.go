@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/capture"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProviderCaptureController AI提供商调用捕获查询控制器
+type ProviderCaptureController struct {
+	BaseController
+	recorder *capture.Recorder // 未启用捕获时为 nil
+}
+
+// NewProviderCaptureController 创建AI提供商调用捕获查询控制器
+func NewProviderCaptureController(recorder *capture.Recorder, errorHandler *errors.ErrorHandler) *ProviderCaptureController {
+	return &ProviderCaptureController{
+		BaseController: *NewBaseController(errorHandler),
+		recorder:       recorder,
+	}
+}
+
+// ListCaptures 列出最近的提供商调用，最近的在前，可按 provider 过滤
+func (pc *ProviderCaptureController) ListCaptures(c *gin.Context) {
+	if !pc.enabled(c) {
+		return
+	}
+	captures := pc.recorder.List(c.Query("provider"))
+	response.I18nSuccess(c, http.StatusOK, "response.captures.retrieved", gin.H{"captures": captures, "count": len(captures)}, nil)
+}
+
+// GetCapture 获取单次提供商调用的完整请求和响应
+func (pc *ProviderCaptureController) GetCapture(c *gin.Context) {
+	if !pc.enabled(c) {
+		return
+	}
+	exchange, ok := pc.recorder.Get(c.Param("id"))
+	if !ok {
+		pc.HandleError(c, errors.NewNotFoundError("provider capture"))
+		return
+	}
+	response.I18nSuccess(c, http.StatusOK, "response.captures.retrieved", gin.H{"capture": exchange}, nil)
+}
+
+// ClearCaptures 清空已保存的提供商调用
+func (pc *ProviderCaptureController) ClearCaptures(c *gin.Context) {
+	if !pc.enabled(c) {
+		return
+	}
+	pc.recorder.Clear()
+	response.I18nSuccess(c, http.StatusOK, "response.captures.cleared", nil, nil)
+}
+
+// enabled 未启用捕获时返回404
+func (pc *ProviderCaptureController) enabled(c *gin.Context) bool {
+	if pc.recorder == nil {
+		pc.HandleError(c, errors.NewAppError(errors.ErrCodeNotFound, "提供商调用捕获未启用，请设置 provider_capture.enabled", errors.SeverityLow, http.StatusNotFound))
+		return false
+	}
+	return true
+}
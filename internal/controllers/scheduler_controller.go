@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SchedulerController 定时任务管理控制器
+type SchedulerController struct {
+	BaseController
+	schedulerService service.SchedulerService
+	logger           *zap.Logger
+}
+
+// NewSchedulerController 创建定时任务管理控制器
+func NewSchedulerController(schedulerService service.SchedulerService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *SchedulerController {
+	return &SchedulerController{
+		BaseController:   *NewBaseController(errorHandler),
+		schedulerService: schedulerService,
+		logger:           logger,
+	}
+}
+
+// ListJobs 获取所有任务及可用的任务类型
+func (sc *SchedulerController) ListJobs(c *gin.Context) {
+	result, err := sc.schedulerService.List(c.Request.Context())
+	if err != nil {
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.scheduler.retrieved", result, nil)
+}
+
+// CreateJob 创建任务
+func (sc *SchedulerController) CreateJob(c *gin.Context) {
+	var req dto.CreateScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	job, err := sc.schedulerService.Create(c.Request.Context(), &req)
+	if err != nil {
+		sc.logger.Error("创建定时任务失败", zap.String("name", req.Name), zap.String("job_type", req.JobType), zap.Error(err))
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.scheduler.created", job, nil)
+}
+
+// UpdateJob 更新任务
+func (sc *SchedulerController) UpdateJob(c *gin.Context) {
+	id, ok := sc.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateScheduledJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	job, err := sc.schedulerService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		sc.logger.Error("更新定时任务失败", zap.Int64("job_id", id), zap.Error(err))
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.scheduler.updated", job, nil)
+}
+
+// PauseJob 暂停任务
+func (sc *SchedulerController) PauseJob(c *gin.Context) {
+	sc.setEnabled(c, false, "response.scheduler.paused")
+}
+
+// ResumeJob 恢复任务
+func (sc *SchedulerController) ResumeJob(c *gin.Context) {
+	sc.setEnabled(c, true, "response.scheduler.resumed")
+}
+
+// RunJob 立即在后台执行一次任务
+func (sc *SchedulerController) RunJob(c *gin.Context) {
+	id, ok := sc.parseID(c)
+	if !ok {
+		return
+	}
+
+	job, err := sc.schedulerService.RunNow(c.Request.Context(), id)
+	if err != nil {
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusAccepted, "response.scheduler.triggered", job, nil)
+}
+
+// DeleteJob 删除任务
+func (sc *SchedulerController) DeleteJob(c *gin.Context) {
+	id, ok := sc.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := sc.schedulerService.Delete(c.Request.Context(), id); err != nil {
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.scheduler.deleted", nil, nil)
+}
+
+// setEnabled 暂停或恢复任务
+func (sc *SchedulerController) setEnabled(c *gin.Context, enabled bool, messageKey string) {
+	id, ok := sc.parseID(c)
+	if !ok {
+		return
+	}
+
+	job, err := sc.schedulerService.SetEnabled(c.Request.Context(), id, enabled)
+	if err != nil {
+		sc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, messageKey, job, nil)
+}
+
+// parseID 解析路径中的任务ID，失败时已写入错误响应
+func (sc *SchedulerController) parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		sc.HandleError(c, errors.NewValidationError("ID无效").WithDetails("id"))
+		return 0, false
+	}
+	return id, true
+}
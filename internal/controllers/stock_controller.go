@@ -1,11 +1,14 @@
 package controllers
 
 import (
-	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
 	"go-springAi/internal/response"
 	"go-springAi/internal/service"
 
@@ -17,18 +20,47 @@ import (
 type StockController struct {
 	BaseController
 	stockAnalysisService *service.StockAnalysisService
+	stockReportService   *service.StockReportService
+	preferenceService    service.UserPreferenceService
 	logger               *zap.Logger
 }
 
 // NewStockController 创建新的股票控制器
-func NewStockController(stockAnalysisService *service.StockAnalysisService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *StockController {
+func NewStockController(stockAnalysisService *service.StockAnalysisService, stockReportService *service.StockReportService, preferenceService service.UserPreferenceService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *StockController {
 	return &StockController{
 		BaseController:       *NewBaseController(errorHandler),
 		stockAnalysisService: stockAnalysisService,
+		stockReportService:   stockReportService,
+		preferenceService:    preferenceService,
 		logger:               logger,
 	}
 }
 
+// userPreferences 获取当前登录用户的偏好设置，匿名请求或读取失败时返回空设置
+func (sc *StockController) userPreferences(c *gin.Context) *dto.UserPreferences {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil || sc.preferenceService == nil {
+		return &dto.UserPreferences{}
+	}
+	prefs, err := sc.preferenceService.Get(c.Request.Context(), userID)
+	if err != nil {
+		sc.logger.Warn("读取用户偏好设置失败", zap.Int64("user_id", userID), zap.Error(err))
+		return &dto.UserPreferences{}
+	}
+	return prefs
+}
+
+// periodQuery 读取 period 查询参数，未指定时优先使用用户偏好中受支持的周期
+func periodQuery(c *gin.Context, prefs *dto.UserPreferences, fallback string, validPeriods map[string]bool) string {
+	if period := c.Query("period"); period != "" {
+		return period
+	}
+	if period := prefs.DefaultPeriod; validPeriods[period] {
+		return period
+	}
+	return fallback
+}
+
 // AnalyzeStock 分析股票
 func (sc *StockController) AnalyzeStock(c *gin.Context) {
 	var req dto.StockAnalysisRequest
@@ -44,15 +76,24 @@ func (sc *StockController) AnalyzeStock(c *gin.Context) {
 		return
 	}
 
+	// 未指定的周期和语言使用用户偏好
+	prefs := sc.userPreferences(c)
+	if req.Period == "" {
+		req.Period = prefs.DefaultPeriod
+	}
+	if req.Language == "" {
+		req.Language = prefs.Locale
+	}
+
 	// 调用股票分析服务
-	result, err := sc.stockAnalysisService.AnalyzeStock(context.Background(), &req)
+	result, err := sc.stockAnalysisService.AnalyzeStock(c.Request.Context(), &req)
 	if err != nil {
 		sc.logger.Error("股票分析失败", zap.Error(err), zap.String("symbol", req.Symbol))
 		sc.HandleError(c, errors.NewInternalError("股票分析失败").WithCause(err))
 		return
 	}
 
-	response.Success(c, http.StatusOK, "股票分析成功", result)
+	response.I18nSuccess(c, http.StatusOK, "response.stock.analyzed", result, nil)
 }
 
 // CompareStocks 对比股票
@@ -70,15 +111,47 @@ func (sc *StockController) CompareStocks(c *gin.Context) {
 		return
 	}
 
+	// 未指定的周期和语言使用用户偏好
+	prefs := sc.userPreferences(c)
+	if req.Period == "" {
+		req.Period = prefs.DefaultPeriod
+	}
+	if req.Language == "" {
+		req.Language = prefs.Locale
+	}
+
 	// 调用股票对比服务
-	result, err := sc.stockAnalysisService.CompareStocks(context.Background(), &req)
+	result, err := sc.stockAnalysisService.CompareStocks(c.Request.Context(), &req)
 	if err != nil {
 		sc.logger.Error("股票对比失败", zap.Error(err), zap.Strings("symbols", req.Symbols))
 		sc.HandleError(c, errors.NewInternalError("股票对比失败").WithCause(err))
 		return
 	}
 
-	response.Success(c, http.StatusOK, "股票对比成功", result)
+	response.I18nSuccess(c, http.StatusOK, "response.stock.compared", result, nil)
+}
+
+// AnalyzePortfolioRisk 分析投资组合风险
+func (sc *StockController) AnalyzePortfolioRisk(c *gin.Context) {
+	var req dto.PortfolioRiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sc.logger.Error("绑定投资组合风险请求失败", zap.Error(err))
+		sc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	if req.Period == "" {
+		req.Period = sc.userPreferences(c).DefaultPeriod
+	}
+
+	result, err := sc.stockAnalysisService.AnalyzePortfolioRisk(c.Request.Context(), &req)
+	if err != nil {
+		sc.logger.Error("投资组合风险分析失败", zap.Error(err), zap.Int("holdings", len(req.Holdings)))
+		sc.HandleError(c, errors.NewInternalError("投资组合风险分析失败").WithCause(err))
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.stock.risk", result, nil)
 }
 
 // GetStockQuote 获取股票报价
@@ -97,7 +170,7 @@ func (sc *StockController) GetStockQuote(c *gin.Context) {
 	}
 
 	// 调用股票分析服务获取基本信息
-	result, err := sc.stockAnalysisService.AnalyzeStock(context.Background(), req)
+	result, err := sc.stockAnalysisService.AnalyzeStock(c.Request.Context(), req)
 	if err != nil {
 		sc.logger.Error("获取股票报价失败", zap.Error(err), zap.String("symbol", symbol))
 		sc.HandleError(c, errors.NewInternalError("获取股票报价失败").WithCause(err))
@@ -112,7 +185,7 @@ func (sc *StockController) GetStockQuote(c *gin.Context) {
 		"company_name":  result.CompanyName,
 	}
 
-	response.Success(c, http.StatusOK, "获取股票报价成功", quote)
+	response.I18nSuccess(c, http.StatusOK, "response.stock.quote", quote, nil)
 }
 
 // GetStockHistory 获取股票历史数据
@@ -123,15 +196,17 @@ func (sc *StockController) GetStockHistory(c *gin.Context) {
 		return
 	}
 
-	// 获取查询参数
-	period := c.DefaultQuery("period", "1y")
-	analysisType := c.DefaultQuery("analysis_type", "technical")
-
 	// 验证参数
 	validPeriods := map[string]bool{
 		"1d": true, "5d": true, "1mo": true, "3mo": true,
 		"6mo": true, "1y": true, "2y": true, "5y": true, "10y": true,
 	}
+
+	// 获取查询参数，未指定的周期和语言使用用户偏好
+	prefs := sc.userPreferences(c)
+	period := periodQuery(c, prefs, "1y", validPeriods)
+	analysisType := c.DefaultQuery("analysis_type", "technical")
+
 	if !validPeriods[period] {
 		sc.HandleError(c, errors.NewValidationError("无效的时间周期"))
 		return
@@ -142,17 +217,54 @@ func (sc *StockController) GetStockHistory(c *gin.Context) {
 		Symbol:       symbol,
 		Period:       period,
 		AnalysisType: analysisType,
+		Language:     prefs.Locale,
 	}
 
 	// 调用股票分析服务
-	result, err := sc.stockAnalysisService.AnalyzeStock(context.Background(), req)
+	result, err := sc.stockAnalysisService.AnalyzeStock(c.Request.Context(), req)
 	if err != nil {
 		sc.logger.Error("获取股票历史数据失败", zap.Error(err), zap.String("symbol", symbol))
 		sc.HandleError(c, errors.NewInternalError("获取股票历史数据失败").WithCause(err))
 		return
 	}
 
-	response.Success(c, http.StatusOK, "获取股票历史数据成功", result)
+	response.I18nSuccess(c, http.StatusOK, "response.stock.history", result, nil)
+}
+
+// GetStockReportPDF 生成股票综合分析PDF报告
+func (sc *StockController) GetStockReportPDF(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if symbol == "" {
+		sc.HandleError(c, errors.NewValidationError("股票代码不能为空"))
+		return
+	}
+
+	validPeriods := map[string]bool{
+		"1mo": true, "3mo": true, "6mo": true, "1y": true,
+		"2y": true, "5y": true, "10y": true, "ytd": true,
+	}
+	period := periodQuery(c, sc.userPreferences(c), "3mo", validPeriods)
+	if !validPeriods[period] {
+		sc.HandleError(c, errors.NewValidationError("无效的时间周期"))
+		return
+	}
+
+	req := &dto.StockAnalysisRequest{
+		Symbol:    symbol,
+		Period:    period,
+		Benchmark: c.Query("benchmark"),
+	}
+
+	report, err := sc.stockReportService.GenerateReport(c.Request.Context(), req)
+	if err != nil {
+		sc.logger.Error("生成股票分析报告失败", zap.Error(err), zap.String("symbol", symbol))
+		sc.HandleError(c, errors.NewInternalError("生成股票分析报告失败").WithCause(err))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-report-%s.pdf", symbol, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", report)
 }
 
 // GetMarketSummary 获取市场概览
@@ -169,7 +281,7 @@ func (sc *StockController) GetMarketSummary(c *gin.Context) {
 			AnalysisType: "basic",
 		}
 		
-		result, err := sc.stockAnalysisService.AnalyzeStock(context.Background(), req)
+		result, err := sc.stockAnalysisService.AnalyzeStock(c.Request.Context(), req)
 		if err != nil {
 			sc.logger.Warn("获取市场指数失败", zap.Error(err), zap.String("symbol", symbol))
 			continue
@@ -183,7 +295,7 @@ func (sc *StockController) GetMarketSummary(c *gin.Context) {
 		summaries = append(summaries, summary)
 	}
 	
-	response.Success(c, http.StatusOK, "获取市场概览成功", map[string]interface{}{
+	response.I18nSuccess(c, http.StatusOK, "response.stock.market", map[string]interface{}{
 		"indices": summaries,
-	})
+	}, nil)
 }
\ No newline at end of file
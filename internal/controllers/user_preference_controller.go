@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UserPreferenceController 用户偏好设置控制器
+type UserPreferenceController struct {
+	BaseController
+	preferenceService service.UserPreferenceService
+	logger            *zap.Logger
+}
+
+// NewUserPreferenceController 创建用户偏好设置控制器
+func NewUserPreferenceController(preferenceService service.UserPreferenceService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *UserPreferenceController {
+	return &UserPreferenceController{
+		BaseController:    *NewBaseController(errorHandler),
+		preferenceService: preferenceService,
+		logger:            logger,
+	}
+}
+
+// GetPreferences 获取当前用户的偏好设置
+func (pc *UserPreferenceController) GetPreferences(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	prefs, err := pc.preferenceService.Get(c.Request.Context(), userID)
+	if err != nil {
+		pc.logger.Error("获取用户偏好设置失败", zap.Int64("user_id", userID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.preferences.retrieved", prefs, nil)
+}
+
+// UpdatePreferences 更新当前用户的偏好设置
+func (pc *UserPreferenceController) UpdatePreferences(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		pc.HandleError(c, err)
+		return
+	}
+
+	var req dto.UpdateUserPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	prefs, err := pc.preferenceService.Update(c.Request.Context(), userID, &req)
+	if err != nil {
+		pc.logger.Error("更新用户偏好设置失败", zap.Int64("user_id", userID), zap.Error(err))
+		pc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.preferences.updated", prefs, nil)
+}
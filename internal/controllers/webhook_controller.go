@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/response"
+	"go-springAi/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WebhookController Webhook端点管理控制器
+type WebhookController struct {
+	BaseController
+	webhookService service.WebhookService
+	logger         *zap.Logger
+}
+
+// NewWebhookController 创建Webhook端点管理控制器
+func NewWebhookController(webhookService service.WebhookService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *WebhookController {
+	return &WebhookController{
+		BaseController: *NewBaseController(errorHandler),
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// ListWebhooks 获取所有端点
+func (wc *WebhookController) ListWebhooks(c *gin.Context) {
+	result, err := wc.webhookService.List(c.Request.Context())
+	if err != nil {
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.webhooks.retrieved", result, nil)
+}
+
+// CreateWebhook 创建端点，签名密钥只在此时完整返回
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		wc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	endpoint, err := wc.webhookService.Create(c.Request.Context(), &req)
+	if err != nil {
+		wc.logger.Error("创建Webhook端点失败", zap.String("url", req.URL), zap.Error(err))
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusCreated, "response.webhooks.created", endpoint, nil)
+}
+
+// UpdateWebhook 更新端点
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	id, ok := wc.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		wc.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+		return
+	}
+
+	endpoint, err := wc.webhookService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		wc.logger.Error("更新Webhook端点失败", zap.Int64("endpoint_id", id), zap.Error(err))
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.webhooks.updated", endpoint, nil)
+}
+
+// DeleteWebhook 删除端点及其投递记录
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	id, ok := wc.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := wc.webhookService.Delete(c.Request.Context(), id); err != nil {
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.webhooks.deleted", nil, nil)
+}
+
+// ListDeliveries 获取端点最近的投递记录，limit 默认 50
+func (wc *WebhookController) ListDeliveries(c *gin.Context) {
+	id, ok := wc.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			wc.HandleError(c, errors.NewValidationError("limit 必须为正整数"))
+			return
+		}
+	}
+
+	deliveries, err := wc.webhookService.ListDeliveries(c.Request.Context(), id, limit)
+	if err != nil {
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusOK, "response.webhooks.deliveries", gin.H{"deliveries": deliveries, "count": len(deliveries)}, nil)
+}
+
+// PingWebhook 向端点投递测试事件
+func (wc *WebhookController) PingWebhook(c *gin.Context) {
+	id, ok := wc.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	delivery, err := wc.webhookService.Ping(c.Request.Context(), id)
+	if err != nil {
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusAccepted, "response.webhooks.ping", delivery, nil)
+}
+
+// RedeliverWebhook 重新投递一条投递记录
+func (wc *WebhookController) RedeliverWebhook(c *gin.Context) {
+	id, ok := wc.parseID(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := wc.parseID(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	if err := wc.webhookService.Redeliver(c.Request.Context(), id, deliveryID); err != nil {
+		wc.HandleError(c, err)
+		return
+	}
+
+	response.I18nSuccess(c, http.StatusAccepted, "response.webhooks.redelivered", nil, nil)
+}
+
+// parseID 解析路径中的ID参数，失败时已写入错误响应
+func (wc *WebhookController) parseID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		wc.HandleError(c, errors.NewValidationError("ID无效").WithDetails(name))
+		return 0, false
+	}
+	return id, true
+}
@@ -4,29 +4,81 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	"go-springAi/internal/database/generated/ai_spend"
+	"go-springAi/internal/database/generated/ai_usage"
 	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/database/generated/evals"
+	"go-springAi/internal/database/generated/experiments"
+	"go-springAi/internal/database/generated/feedback"
+	"go-springAi/internal/database/generated/fine_tuning_jobs"
+	"go-springAi/internal/database/generated/memories"
+	"go-springAi/internal/database/generated/model_metadata"
+	"go-springAi/internal/database/generated/notification_channels"
+	"go-springAi/internal/database/generated/personal_access_tokens"
+	"go-springAi/internal/database/generated/personas"
+	"go-springAi/internal/database/generated/projects"
+	"go-springAi/internal/database/generated/refresh_tokens"
+	"go-springAi/internal/database/generated/scheduled_jobs"
+	"go-springAi/internal/database/generated/user_permissions"
+	"go-springAi/internal/database/generated/user_preferences"
 	"go-springAi/internal/database/generated/users"
+	"go-springAi/internal/database/generated/webhooks"
 	"go-springAi/internal/logger"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
 // DB wraps the database connection and provides access to generated queries
 type DB struct {
-	conn    *sql.DB
-	Users   *users.Queries
-	APIKeys *api_keys.Queries
+	conn                 *sql.DB
+	tx                   *sql.Tx
+	dialect              Dialect
+	Users                *users.Queries
+	APIKeys              *api_keys.Queries
+	RefreshTokens        *refresh_tokens.Queries
+	PersonalAccessTokens *personal_access_tokens.Queries
+	AIUsage              *ai_usage.Queries
+	UserPreferences      *user_preferences.Queries
+	UserPermissions      *user_permissions.Queries
+	Projects             *projects.Queries
+	Webhooks             *webhooks.Queries
+	ScheduledJobs        *scheduled_jobs.Queries
+	NotificationChannels *notification_channels.Queries
+	ModelMetadata        *model_metadata.Queries
+	AISpend              *ai_spend.Queries
+	FineTuningJobs       *fine_tuning_jobs.Queries
+	Evals                *evals.Queries
+	Experiments          *experiments.Queries
+	Feedback             *feedback.Queries
+	Personas             *personas.Queries
+	Memories             *memories.Queries
+}
+
+// Options configures the connection pool; zero values keep the database/sql defaults
+type Options struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	SQLite          SQLiteOptions
 }
 
 // NewConnection creates a new database connection
-func NewConnection(driverName, dataSourceName string) (*DB, error) {
+func NewConnection(driverName, dataSourceName string, opts Options) (*DB, error) {
 	logger.Info(logger.MsgDBConnecting,
 		logger.Module(logger.ModuleDatabase),
 		logger.Operation(logger.OpConnect),
 		logger.String("driver", driverName))
 
-	conn, err := sql.Open(driverName, dataSourceName)
+	dialect, err := ParseDialect(driverName)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect == DialectSQLite {
+		dataSourceName = sqliteDSN(dataSourceName, opts.SQLite)
+	}
+	conn, err := dialect.open(dataSourceName)
 	if err != nil {
 		logger.LogError(logger.MsgDBError,
 			logger.Module(logger.ModuleDatabase),
@@ -36,6 +88,19 @@ func NewConnection(driverName, dataSourceName string) (*DB, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	if opts.MaxOpenConns > 0 {
+		conn.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		conn.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		conn.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime > 0 {
+		conn.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+
 	if err := conn.Ping(); err != nil {
 		logger.LogError(logger.MsgDBError,
 			logger.Module(logger.ModuleDatabase),
@@ -50,15 +115,47 @@ func NewConnection(driverName, dataSourceName string) (*DB, error) {
 		logger.Operation(logger.OpConnect),
 		logger.String("driver", driverName))
 
+	return newDB(conn, nil, dialect), nil
+}
+
+// newDB builds the generated queries on top of the connection or, when tx is set, the transaction
+func newDB(conn *sql.DB, tx *sql.Tx, dialect Dialect) *DB {
+	var q DBTX = conn
+	if tx != nil {
+		q = tx
+	}
+	q = dialect.wrap(q)
 	return &DB{
-		conn:    conn,
-		Users:   users.New(conn),
-		APIKeys: api_keys.New(conn),
-	}, nil
+		conn:                 conn,
+		tx:                   tx,
+		dialect:              dialect,
+		Users:                users.New(q),
+		APIKeys:              api_keys.New(q),
+		RefreshTokens:        refresh_tokens.New(q),
+		PersonalAccessTokens: personal_access_tokens.New(q),
+		AIUsage:              ai_usage.New(q),
+		UserPreferences:      user_preferences.New(q),
+		UserPermissions:      user_permissions.New(q),
+		Projects:             projects.New(q),
+		Webhooks:             webhooks.New(q),
+		ScheduledJobs:        scheduled_jobs.New(q),
+		NotificationChannels: notification_channels.New(q),
+		ModelMetadata:        model_metadata.New(q),
+		AISpend:              ai_spend.New(q),
+		FineTuningJobs:       fine_tuning_jobs.New(q),
+		Evals:                evals.New(q),
+		Experiments:          experiments.New(q),
+		Feedback:             feedback.New(q),
+		Personas:             personas.New(q),
+		Memories:             memories.New(q),
+	}
 }
 
-// Close closes the database connection
+// Close closes the database connection; it is a no-op inside a transaction
 func (db *DB) Close() error {
+	if db.tx != nil {
+		return nil
+	}
 	return db.conn.Close()
 }
 
@@ -67,13 +164,29 @@ func (db *DB) Ping() error {
 	return db.conn.Ping()
 }
 
+// Dialect returns the SQL dialect of the connection
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
 // GetConnection returns the underlying sql.DB connection
 func (db *DB) GetConnection() *sql.DB {
 	return db.conn
 }
 
-// WithTx executes a function within a database transaction
-func (db *DB) WithTx(ctx context.Context, fn func(*users.Queries) error) error {
+// InTx reports whether the queries run inside a transaction
+func (db *DB) InTx() bool {
+	return db.tx != nil
+}
+
+// WithTx executes fn within a database transaction. All queries on the DB passed to fn
+// share the transaction, which is committed when fn returns nil and rolled back otherwise.
+// Calling WithTx on a DB that is already in a transaction joins the outer transaction.
+func (db *DB) WithTx(ctx context.Context, fn func(*DB) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
 	logger.DebugCtx(ctx, logger.MsgDBTransaction,
 		logger.Module(logger.ModuleDatabase),
 		logger.Operation("begin"))
@@ -88,8 +201,7 @@ func (db *DB) WithTx(ctx context.Context, fn func(*users.Queries) error) error {
 	}
 	defer tx.Rollback()
 
-	qtx := db.Users.WithTx(tx)
-	if err := fn(qtx); err != nil {
+	if err := fn(newDB(db.conn, tx, db.dialect)); err != nil {
 		logger.ErrorCtx(ctx, logger.MsgDBError,
 			logger.Module(logger.ModuleDatabase),
 			logger.Operation("execute_tx"),
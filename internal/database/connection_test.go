@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go-springAi/internal/database/generated/projects"
+	"go-springAi/internal/database/generated/users"
+	"go-springAi/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), Options{SQLite: SQLiteOptions{ForeignKeys: true}})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+
+	// createUserAndProject 在事务中创建用户和项目，fail 为 true 时在最后一步返回错误
+	createUserAndProject := func(name string, fail bool) error {
+		return db.WithTx(ctx, func(tx *DB) error {
+			assert.True(t, tx.InTx())
+			user, err := tx.Users.CreateUser(ctx, users.CreateUserParams{Username: name, Email: name + "@example.com", PasswordHash: "x"})
+			if err != nil {
+				return err
+			}
+			// 嵌套调用加入外层事务
+			return tx.WithTx(ctx, func(inner *DB) error {
+				if _, err := inner.Projects.CreateProject(ctx, projects.CreateProjectParams{UserID: user.ID, Name: "default"}); err != nil {
+					return err
+				}
+				if fail {
+					return errors.New("boom")
+				}
+				return nil
+			})
+		})
+	}
+
+	require.NoError(t, createUserAndProject("alice", false))
+	alice, err := db.Users.GetUserByUsername(ctx, "alice")
+	require.NoError(t, err)
+	list, err := db.Projects.ListProjectsByUser(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	assert.EqualError(t, createUserAndProject("bob", true), "boom")
+	_, err = db.Users.GetUserByUsername(ctx, "bob")
+	assert.Error(t, err, "user must be rolled back")
+	assert.False(t, db.InTx())
+}
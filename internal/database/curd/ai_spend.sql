@@ -0,0 +1,17 @@
+-- name: RecordAISpend :exec
+INSERT INTO ai_spend (
+    user_id, project_id, provider, usage_date, cost_usd
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+)
+ON CONFLICT (user_id, project_id, provider, usage_date) DO UPDATE SET
+    cost_usd = ai_spend.cost_usd + excluded.cost_usd,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetAISpendSummary :one
+SELECT
+    CAST(COALESCE(SUM(CASE WHEN user_id = sqlc.arg(user_id) THEN cost_usd ELSE 0 END), 0.0) AS DECIMAL(20,6)) AS user_spend,
+    CAST(COALESCE(SUM(CASE WHEN project_id = sqlc.arg(project_id) THEN cost_usd ELSE 0 END), 0.0) AS DECIMAL(20,6)) AS project_spend,
+    CAST(COALESCE(SUM(CASE WHEN provider = sqlc.arg(provider) THEN cost_usd ELSE 0 END), 0.0) AS DECIMAL(20,6)) AS provider_spend
+FROM ai_spend
+WHERE usage_date >= sqlc.arg(month_start);
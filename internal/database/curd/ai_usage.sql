@@ -0,0 +1,17 @@
+-- name: RecordAIUsage :exec
+INSERT INTO ai_usage (
+    user_id, usage_date, request_count, token_count
+) VALUES (
+    ?1, ?2, 1, ?3
+)
+ON CONFLICT (user_id, usage_date) DO UPDATE SET
+    request_count = ai_usage.request_count + 1,
+    token_count = ai_usage.token_count + excluded.token_count,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetAIUsageSummary :one
+SELECT
+    CAST(COALESCE(SUM(CASE WHEN usage_date = sqlc.arg(today) THEN request_count ELSE 0 END), 0) AS BIGINT) AS day_requests,
+    CAST(COALESCE(SUM(token_count), 0) AS BIGINT) AS month_tokens
+FROM ai_usage
+WHERE user_id = sqlc.arg(user_id) AND usage_date >= sqlc.arg(month_start);
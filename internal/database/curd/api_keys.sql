@@ -1,56 +1,181 @@
 -- name: CreateAPIKey :one
 INSERT INTO api_keys (
-    user_id, provider_type, encrypted_key, key_hash, is_active
+    user_id, project_id, provider_type, encrypted_key, key_hash, is_active
 ) VALUES (
-    ?1, ?2, ?3, ?4, ?5
-) RETURNING id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at;
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at;
 
 -- name: GetAPIKey :one
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: GetAPIKeyByID :one
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
+FROM api_keys
+WHERE id = ?1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListAPIKeysByProject :many
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE id = ?1 LIMIT 1;
+WHERE user_id = ?1 AND project_id = ?2 AND is_active = TRUE AND deleted_at IS NULL
+ORDER BY provider_type;
 
 -- name: ListAPIKeysByUser :many
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE user_id = ?1 AND is_active = TRUE
+WHERE user_id = sqlc.arg('user_id') AND is_active = TRUE AND (deleted_at IS NULL OR CAST(sqlc.arg('include_deleted') AS BOOLEAN))
 ORDER BY created_at DESC;
 
 -- name: ListAPIKeysByProvider :many
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE provider_type = ?1 AND is_active = TRUE
+WHERE provider_type = ?1 AND is_active = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: UpdateAPIKey :one
 UPDATE api_keys 
-SET encrypted_key = ?3, key_hash = ?4, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE
-RETURNING id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at;
+SET encrypted_key = ?4, key_hash = ?5, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
+RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at;
+
+-- name: UpdateAPIKeyAllowedModels :execrows
+UPDATE api_keys 
+SET allowed_models = ?4, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL;
 
 -- name: DeactivateAPIKey :exec
 UPDATE api_keys 
 SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = ?1 AND provider_type = ?2;
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NULL;
+
+-- name: SoftDeleteAPIKey :execrows
+UPDATE api_keys
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NULL;
 
--- name: DeleteAPIKey :exec
+-- name: RestoreAPIKey :one
+UPDATE api_keys
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NOT NULL
+RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at;
+
+-- name: PurgeDeletedAPIKey :exec
 DELETE FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2;
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NOT NULL;
 
 -- name: CountAPIKeysByUser :one
 SELECT COUNT(*) FROM api_keys
-WHERE user_id = ?1 AND is_active = TRUE;
+WHERE user_id = ?1 AND is_active = TRUE AND deleted_at IS NULL;
 
 -- name: CountAPIKeysByProvider :one
 SELECT COUNT(*) FROM api_keys
-WHERE provider_type = ?1 AND is_active = TRUE;
+WHERE provider_type = ?1 AND is_active = TRUE AND deleted_at IS NULL;
 
 -- name: CheckAPIKeyExists :one
 SELECT COUNT(*) FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE;
\ No newline at end of file
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL;
+
+-- name: DeleteAPIKeyVersionsByProject :exec
+DELETE FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2;
+
+-- name: CreateAPIKeyVersion :one
+INSERT INTO api_key_versions (
+    user_id, project_id, provider_type, version, encrypted_key, key_hash
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at;
+
+-- name: ExpireAPIKeyVersionGrace :exec
+UPDATE api_key_versions
+SET grace_expires_at = ?4
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NOT NULL AND grace_expires_at > ?4;
+
+-- name: GetLatestAPIKeyVersion :one
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetPreviousAPIKeyVersion :one
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NOT NULL AND grace_expires_at > ?4
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: ListAPIKeyVersions :many
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3
+ORDER BY version DESC;
+
+-- name: RetireAPIKeyVersions :exec
+UPDATE api_key_versions
+SET rotated_at = CURRENT_TIMESTAMP, grace_expires_at = ?4
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NULL;
+
+-- name: ListActiveAPIKeys :many
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
+FROM api_keys
+WHERE is_active = TRUE AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetAPIKeyValidation :one
+SELECT api_key_id, key_hash, valid, error, validated_at
+FROM api_key_validations
+WHERE api_key_id = ?1 LIMIT 1;
+
+-- name: UpsertAPIKeyValidation :exec
+INSERT INTO api_key_validations (
+    api_key_id, key_hash, valid, error, validated_at
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+)
+ON CONFLICT (api_key_id) DO UPDATE SET
+    key_hash = excluded.key_hash,
+    valid = excluded.valid,
+    error = excluded.error,
+    validated_at = excluded.validated_at;
+
+-- name: GetAPIKeyExpiration :one
+SELECT api_key_id, key_hash, expires_at, warned_at, expired_at
+FROM api_key_expirations
+WHERE api_key_id = ?1 LIMIT 1;
+
+-- name: UpsertAPIKeyExpiration :exec
+INSERT INTO api_key_expirations (
+    api_key_id, key_hash, expires_at, warned_at, expired_at
+) VALUES (
+    ?1, ?2, ?3, NULL, NULL
+)
+ON CONFLICT (api_key_id) DO UPDATE SET
+    key_hash = excluded.key_hash,
+    expires_at = excluded.expires_at,
+    warned_at = NULL,
+    expired_at = NULL;
+
+-- name: DeleteAPIKeyExpiration :exec
+DELETE FROM api_key_expirations
+WHERE api_key_id = ?1;
+
+-- name: ListDueAPIKeyExpirations :many
+SELECT e.api_key_id, e.key_hash, e.expires_at, e.warned_at, e.expired_at, k.user_id, k.project_id, k.provider_type
+FROM api_key_expirations e
+JOIN api_keys k ON k.id = e.api_key_id
+WHERE k.is_active = TRUE AND k.deleted_at IS NULL AND e.key_hash = k.key_hash
+    AND e.expired_at IS NULL AND e.expires_at <= ?1
+ORDER BY e.expires_at;
+
+-- name: MarkAPIKeyExpirationWarned :exec
+UPDATE api_key_expirations
+SET warned_at = ?2
+WHERE api_key_id = ?1;
+
+-- name: MarkAPIKeyExpired :exec
+UPDATE api_key_expirations
+SET expired_at = ?2
+WHERE api_key_id = ?1;
@@ -0,0 +1,52 @@
+-- name: CreateEvalRun :one
+INSERT INTO eval_runs (
+    suite_id, status, targets, judge, results, summary, error, created_by
+) VALUES (
+    ?1, ?2, ?3, ?4, '', '', '', ?5
+) RETURNING id, suite_id, status, targets, judge, results, summary, error, created_by, finished_at, created_at, updated_at;
+
+-- name: CreateEvalSuite :one
+INSERT INTO eval_suites (
+    name, description, cases, created_by
+) VALUES (
+    ?1, ?2, ?3, ?4
+) RETURNING id, name, description, cases, created_by, created_at, updated_at;
+
+-- name: DeleteEvalSuite :execrows
+DELETE FROM eval_suites
+WHERE id = ?1;
+
+-- name: FinishEvalRun :exec
+UPDATE eval_runs
+SET status = ?2, results = ?3, summary = ?4, error = ?5, finished_at = ?6, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1;
+
+-- name: GetEvalRun :one
+SELECT id, suite_id, status, targets, judge, results, summary, error, created_by, finished_at, created_at, updated_at
+FROM eval_runs
+WHERE id = ?1
+LIMIT 1;
+
+-- name: GetEvalSuite :one
+SELECT id, name, description, cases, created_by, created_at, updated_at
+FROM eval_suites
+WHERE id = ?1
+LIMIT 1;
+
+-- name: ListEvalRuns :many
+SELECT id, suite_id, status, targets, judge, results, summary, error, created_by, finished_at, created_at, updated_at
+FROM eval_runs
+WHERE suite_id = ?1
+ORDER BY id DESC
+LIMIT ?2;
+
+-- name: ListEvalSuites :many
+SELECT id, name, description, cases, created_by, created_at, updated_at
+FROM eval_suites
+ORDER BY id;
+
+-- name: UpdateEvalSuite :one
+UPDATE eval_suites
+SET name = ?2, description = ?3, cases = ?4, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, name, description, cases, created_by, created_at, updated_at;
@@ -0,0 +1,67 @@
+-- name: CreateExperiment :one
+INSERT INTO experiments (
+    name, description, persona, traffic_split, variant_a, variant_b, created_by
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7
+) RETURNING id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at;
+
+-- name: CreateExperimentOutcome :exec
+INSERT INTO experiment_outcomes (
+    experiment_id, variant, response_id, user_id, success, latency_ms, tool_calls, tool_failures
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8
+);
+
+-- name: DeleteExperiment :execrows
+DELETE FROM experiments
+WHERE id = ?1;
+
+-- name: GetExperiment :one
+SELECT id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at
+FROM experiments
+WHERE id = ?1
+LIMIT 1;
+
+-- name: GetRunningExperimentByPersona :one
+SELECT id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at
+FROM experiments
+WHERE persona = ?1 AND status = 'running'
+ORDER BY id
+LIMIT 1;
+
+-- name: ListExperimentVariantStats :many
+SELECT
+    variant,
+    COUNT(*) AS requests,
+    CAST(COALESCE(SUM(CASE WHEN success THEN 0 ELSE 1 END), 0) AS BIGINT) AS errors,
+    CAST(COALESCE(SUM(CASE WHEN success THEN latency_ms ELSE 0 END), 0) AS BIGINT) AS total_latency_ms,
+    CAST(COALESCE(SUM(tool_calls), 0) AS BIGINT) AS tool_calls,
+    CAST(COALESCE(SUM(tool_failures), 0) AS BIGINT) AS tool_failures,
+    CAST(COALESCE(SUM(CASE WHEN feedback > 0 THEN 1 ELSE 0 END), 0) AS BIGINT) AS feedback_up,
+    CAST(COALESCE(SUM(CASE WHEN feedback < 0 THEN 1 ELSE 0 END), 0) AS BIGINT) AS feedback_down
+FROM experiment_outcomes
+WHERE experiment_id = ?1
+GROUP BY variant
+ORDER BY variant;
+
+-- name: ListExperiments :many
+SELECT id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at
+FROM experiments
+ORDER BY id;
+
+-- name: SetExperimentOutcomeFeedback :execrows
+UPDATE experiment_outcomes
+SET feedback = ?2, updated_at = CURRENT_TIMESTAMP
+WHERE response_id = ?1;
+
+-- name: UpdateExperiment :one
+UPDATE experiments
+SET name = ?2, description = ?3, persona = ?4, traffic_split = ?5, variant_a = ?6, variant_b = ?7, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at;
+
+-- name: UpdateExperimentStatus :one
+UPDATE experiments
+SET status = ?2, started_at = ?3, stopped_at = ?4, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, name, description, persona, status, traffic_split, variant_a, variant_b, created_by, started_at, stopped_at, created_at, updated_at;
@@ -0,0 +1,25 @@
+-- name: ListResponseFeedback :many
+SELECT id, response_id, user_id, rating, category, comment, provider, model, persona, conversation, created_at, updated_at
+FROM response_feedback
+ORDER BY id DESC
+LIMIT ?1;
+
+-- name: ListResponseFeedbackByRating :many
+SELECT id, response_id, user_id, rating, category, comment, provider, model, persona, conversation, created_at, updated_at
+FROM response_feedback
+WHERE rating = ?1
+ORDER BY id DESC
+LIMIT ?2;
+
+-- name: UpsertResponseFeedback :one
+INSERT INTO response_feedback (
+    response_id, user_id, rating, category, comment, provider, model, persona, conversation
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9
+)
+ON CONFLICT (response_id, user_id) DO UPDATE SET
+    rating = excluded.rating,
+    category = excluded.category,
+    comment = excluded.comment,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, response_id, user_id, rating, category, comment, provider, model, persona, conversation, created_at, updated_at;
@@ -0,0 +1,35 @@
+-- name: CreateFineTuningJob :one
+INSERT INTO fine_tuning_jobs (
+    job_id, user_id, base_model, training_file, validation_file, suffix, status
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7
+) RETURNING id, job_id, user_id, base_model, training_file, validation_file, suffix, status, fine_tuned_model, error, model_registered, finished_at, created_at, updated_at;
+
+-- name: GetFineTuningJob :one
+SELECT id, job_id, user_id, base_model, training_file, validation_file, suffix, status, fine_tuned_model, error, model_registered, finished_at, created_at, updated_at
+FROM fine_tuning_jobs
+WHERE job_id = ?1
+LIMIT 1;
+
+-- name: ListFineTuningJobs :many
+SELECT id, job_id, user_id, base_model, training_file, validation_file, suffix, status, fine_tuned_model, error, model_registered, finished_at, created_at, updated_at
+FROM fine_tuning_jobs
+ORDER BY id DESC
+LIMIT ?1;
+
+-- name: ListPendingFineTuningJobs :many
+SELECT id, job_id, user_id, base_model, training_file, validation_file, suffix, status, fine_tuned_model, error, model_registered, finished_at, created_at, updated_at
+FROM fine_tuning_jobs
+WHERE status NOT IN ('succeeded', 'failed', 'cancelled')
+   OR (status = 'succeeded' AND model_registered = FALSE)
+ORDER BY id;
+
+-- name: MarkFineTuningModelRegistered :exec
+UPDATE fine_tuning_jobs
+SET model_registered = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE job_id = ?1;
+
+-- name: UpdateFineTuningJobStatus :exec
+UPDATE fine_tuning_jobs
+SET status = ?2, fine_tuned_model = ?3, error = ?4, finished_at = ?5, updated_at = CURRENT_TIMESTAMP
+WHERE job_id = ?1;
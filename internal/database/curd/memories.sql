@@ -0,0 +1,41 @@
+-- name: DeleteUserMemories :execrows
+DELETE FROM user_memories
+WHERE user_id = ?1;
+
+-- name: DeleteUserMemory :execrows
+DELETE FROM user_memories
+WHERE id = ?1 AND user_id = ?2;
+
+-- name: GetUserMemorySettings :one
+SELECT user_id, enabled, updated_at
+FROM user_memory_settings
+WHERE user_id = ?1 LIMIT 1;
+
+-- name: ListUserMemories :many
+SELECT id, user_id, category, memory_key, content, source_response_id, created_at, updated_at
+FROM user_memories
+WHERE user_id = ?1
+ORDER BY updated_at DESC, id DESC;
+
+-- name: UpsertUserMemory :one
+INSERT INTO user_memories (
+    user_id, category, memory_key, content, source_response_id
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+)
+ON CONFLICT (user_id, memory_key) DO UPDATE SET
+    content = excluded.content,
+    source_response_id = excluded.source_response_id,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, user_id, category, memory_key, content, source_response_id, created_at, updated_at;
+
+-- name: UpsertUserMemorySettings :one
+INSERT INTO user_memory_settings (
+    user_id, enabled
+) VALUES (
+    ?1, ?2
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    enabled = excluded.enabled,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, enabled, updated_at;
@@ -0,0 +1,23 @@
+-- name: DeleteModelMetadata :execrows
+DELETE FROM model_metadata
+WHERE provider = ?1 AND model = ?2;
+
+-- name: ListModelMetadata :many
+SELECT provider, model, context_window, input_price, output_price, supports_tools, supports_vision, updated_at
+FROM model_metadata
+ORDER BY provider, model;
+
+-- name: UpsertModelMetadata :one
+INSERT INTO model_metadata (
+    provider, model, context_window, input_price, output_price, supports_tools, supports_vision
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7
+)
+ON CONFLICT (provider, model) DO UPDATE SET
+    context_window = excluded.context_window,
+    input_price = excluded.input_price,
+    output_price = excluded.output_price,
+    supports_tools = excluded.supports_tools,
+    supports_vision = excluded.supports_vision,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING provider, model, context_window, input_price, output_price, supports_tools, supports_vision, updated_at;
@@ -0,0 +1,34 @@
+-- name: DeleteNotificationChannel :execrows
+DELETE FROM notification_channels
+WHERE user_id = ?1 AND channel = ?2;
+
+-- name: GetNotificationChannel :one
+SELECT id, user_id, channel, target, events, enabled, created_at, updated_at
+FROM notification_channels
+WHERE user_id = ?1 AND channel = ?2
+LIMIT 1;
+
+-- name: ListEnabledNotificationChannels :many
+SELECT id, user_id, channel, target, events, enabled, created_at, updated_at
+FROM notification_channels
+WHERE enabled = TRUE
+ORDER BY user_id, channel;
+
+-- name: ListUserNotificationChannels :many
+SELECT id, user_id, channel, target, events, enabled, created_at, updated_at
+FROM notification_channels
+WHERE user_id = ?1
+ORDER BY channel;
+
+-- name: UpsertNotificationChannel :one
+INSERT INTO notification_channels (
+    user_id, channel, target, events, enabled
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+)
+ON CONFLICT (user_id, channel) DO UPDATE SET
+    target = excluded.target,
+    events = excluded.events,
+    enabled = excluded.enabled,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, user_id, channel, target, events, enabled, created_at, updated_at;
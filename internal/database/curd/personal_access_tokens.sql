@@ -0,0 +1,27 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+    user_id, name, token_hash, token_prefix, scopes, expires_at
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM personal_access_tokens
+WHERE token_hash = ?1 LIMIT 1;
+
+-- name: ListUserPersonalAccessTokens :many
+SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM personal_access_tokens
+WHERE user_id = ?1
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND user_id = ?2 AND revoked_at IS NULL;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?1;
@@ -0,0 +1,33 @@
+-- name: CreatePersona :one
+INSERT INTO personas (
+    name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9
+) RETURNING id, name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by, created_at, updated_at;
+
+-- name: DeletePersona :execrows
+DELETE FROM personas
+WHERE id = ?1;
+
+-- name: GetPersona :one
+SELECT id, name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by, created_at, updated_at
+FROM personas
+WHERE id = ?1
+LIMIT 1;
+
+-- name: GetPersonaByName :one
+SELECT id, name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by, created_at, updated_at
+FROM personas
+WHERE name = ?1
+LIMIT 1;
+
+-- name: ListPersonas :many
+SELECT id, name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by, created_at, updated_at
+FROM personas
+ORDER BY name;
+
+-- name: UpdatePersona :one
+UPDATE personas
+SET name = ?2, description = ?3, system_prompt = ?4, allowed_tools = ?5, default_provider = ?6, default_model = ?7, temperature = ?8, max_tokens = ?9, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, name, description, system_prompt, allowed_tools, default_provider, default_model, temperature, max_tokens, created_by, created_at, updated_at;
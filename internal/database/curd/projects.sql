@@ -0,0 +1,49 @@
+-- name: CreateProject :one
+INSERT INTO projects (
+    user_id, name, description
+) VALUES (
+    ?1, ?2, ?3
+) RETURNING id, user_id, name, description, created_at, updated_at;
+
+-- name: DeleteProject :execrows
+DELETE FROM projects
+WHERE id = ?1 AND user_id = ?2;
+
+-- name: DeleteProjectUsage :exec
+DELETE FROM ai_project_usage
+WHERE project_id = ?1;
+
+-- name: GetProject :one
+SELECT id, user_id, name, description, created_at, updated_at
+FROM projects
+WHERE id = ?1 AND user_id = ?2
+LIMIT 1;
+
+-- name: GetProjectByName :one
+SELECT id, user_id, name, description, created_at, updated_at
+FROM projects
+WHERE user_id = ?1 AND name = ?2
+LIMIT 1;
+
+-- name: ListProjectUsage :many
+SELECT id, project_id, usage_date, request_count, token_count, updated_at
+FROM ai_project_usage
+WHERE project_id = ?1 AND usage_date >= ?2
+ORDER BY usage_date;
+
+-- name: ListProjectsByUser :many
+SELECT id, user_id, name, description, created_at, updated_at
+FROM projects
+WHERE user_id = ?1
+ORDER BY name;
+
+-- name: RecordProjectUsage :exec
+INSERT INTO ai_project_usage (
+    project_id, usage_date, request_count, token_count
+) VALUES (
+    ?1, ?2, 1, ?3
+)
+ON CONFLICT (project_id, usage_date) DO UPDATE SET
+    request_count = ai_project_usage.request_count + 1,
+    token_count = ai_project_usage.token_count + excluded.token_count,
+    updated_at = CURRENT_TIMESTAMP;
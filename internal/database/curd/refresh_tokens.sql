@@ -0,0 +1,30 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (
+    user_id, token_hash, family_id, expires_at
+) VALUES (
+    ?1, ?2, ?3, ?4
+) RETURNING id, user_id, token_hash, family_id, expires_at, revoked_at, created_at;
+
+-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, created_at
+FROM refresh_tokens
+WHERE token_hash = ?1 LIMIT 1;
+
+-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family_id = ?1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND revoked_at IS NULL;
+
+-- name: DeleteExpiredRefreshTokens :exec
+DELETE FROM refresh_tokens
+WHERE expires_at < ?1;
@@ -0,0 +1,50 @@
+-- name: ClaimScheduledJob :execrows
+UPDATE scheduled_jobs
+SET next_run_at = ?2
+WHERE id = ?1 AND enabled = TRUE AND next_run_at <= ?3;
+
+-- name: CreateScheduledJob :one
+INSERT INTO scheduled_jobs (
+    name, job_type, schedule, params, enabled, next_run_at
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at;
+
+-- name: DeleteScheduledJob :execrows
+DELETE FROM scheduled_jobs
+WHERE id = ?1;
+
+-- name: GetScheduledJob :one
+SELECT id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at
+FROM scheduled_jobs
+WHERE id = ?1
+LIMIT 1;
+
+-- name: GetScheduledJobByName :one
+SELECT id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at
+FROM scheduled_jobs
+WHERE name = ?1
+LIMIT 1;
+
+-- name: ListDueScheduledJobs :many
+SELECT id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at
+FROM scheduled_jobs
+WHERE enabled = TRUE AND next_run_at <= ?1
+ORDER BY next_run_at, id
+LIMIT ?2;
+
+-- name: ListScheduledJobs :many
+SELECT id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at
+FROM scheduled_jobs
+ORDER BY id;
+
+-- name: RecordScheduledJobRun :exec
+UPDATE scheduled_jobs
+SET last_run_at = ?2, last_status = ?3, last_result = ?4, last_error = ?5, last_duration_ms = ?6, run_count = run_count + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1;
+
+-- name: UpdateScheduledJob :one
+UPDATE scheduled_jobs
+SET name = ?2, schedule = ?3, params = ?4, enabled = ?5, next_run_at = ?6, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, name, job_type, schedule, params, enabled, next_run_at, last_run_at, last_status, last_result, last_error, last_duration_ms, run_count, created_at, updated_at;
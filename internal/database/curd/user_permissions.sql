@@ -0,0 +1,21 @@
+-- name: GrantUserPermission :exec
+INSERT INTO user_permissions (
+    user_id, permission, granted_by
+) VALUES (
+    ?1, ?2, ?3
+)
+ON CONFLICT (user_id, permission) DO NOTHING;
+
+-- name: HasUserPermission :one
+SELECT COUNT(*) FROM user_permissions
+WHERE user_id = ?1 AND permission = ?2;
+
+-- name: ListUserPermissions :many
+SELECT user_id, permission, granted_by, created_at
+FROM user_permissions
+WHERE user_id = ?1
+ORDER BY permission;
+
+-- name: RevokeUserPermission :execrows
+DELETE FROM user_permissions
+WHERE user_id = ?1 AND permission = ?2;
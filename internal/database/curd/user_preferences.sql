@@ -0,0 +1,19 @@
+-- name: GetUserPreferences :one
+SELECT user_id, default_provider, default_model, temperature, locale, default_period, updated_at
+FROM user_preferences
+WHERE user_id = ?1 LIMIT 1;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (
+    user_id, default_provider, default_model, temperature, locale, default_period
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    default_provider = excluded.default_provider,
+    default_model = excluded.default_model,
+    temperature = excluded.temperature,
+    locale = excluded.locale,
+    default_period = excluded.default_period,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, default_provider, default_model, temperature, locale, default_period, updated_at;
@@ -1,5 +1,14 @@
 -- name: CountUsers :one
-SELECT COUNT(*) FROM users;
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL;
+
+-- name: CountFilteredUsers :one
+SELECT COUNT(*) FROM users
+WHERE (CAST(sqlc.narg('search') AS TEXT) IS NULL OR (username LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\'))
+    AND (sqlc.narg('is_active') IS NULL OR is_active = sqlc.narg('is_active'))
+    AND (sqlc.narg('created_from') IS NULL OR created_at >= datetime(sqlc.narg('created_from')))
+    AND (sqlc.narg('created_before') IS NULL OR created_at < datetime(sqlc.narg('created_before')))
+    AND (deleted_at IS NULL OR CAST(sqlc.arg('include_deleted') AS BOOLEAN));
 
 -- name: CountUsersByEmail :one
 SELECT COUNT(*) FROM users
@@ -14,39 +23,103 @@ INSERT INTO users (
     username, email, password_hash, first_name, last_name
 ) VALUES (
     ?1, ?2, ?3, ?4, ?5
-) RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at;
-
--- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = ?1;
+) RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at;
 
 -- name: GetActiveUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE is_active = TRUE
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE is_active = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT ?1 OFFSET ?2;
 
 -- name: GetAdminUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE is_admin = TRUE
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE is_admin = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: GetUser :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE id = ?1 LIMIT 1;
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE id = ?1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE email = ?1 LIMIT 1;
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE email = ?1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE username = ?1 LIMIT 1;
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE username = ?1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-ORDER BY created_at DESC
-LIMIT ?1 OFFSET ?2;
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
+FROM users, (SELECT CAST(sqlc.arg('sort_by') AS TEXT) AS sort_by, CAST(sqlc.arg('sort_order') AS TEXT) AS sort_order) AS sort_opts
+WHERE (CAST(sqlc.narg('search') AS TEXT) IS NULL OR (username LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\'))
+    AND (sqlc.narg('is_active') IS NULL OR is_active = sqlc.narg('is_active'))
+    AND (sqlc.narg('created_from') IS NULL OR created_at >= datetime(sqlc.narg('created_from')))
+    AND (sqlc.narg('created_before') IS NULL OR created_at < datetime(sqlc.narg('created_before')))
+    AND (deleted_at IS NULL OR CAST(sqlc.arg('include_deleted') AS BOOLEAN))
+ORDER BY
+    CASE WHEN sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'asc' THEN id END ASC,
+    CASE WHEN sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'desc' THEN id END DESC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' THEN username END ASC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' THEN username END DESC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' THEN email END ASC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' THEN email END DESC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' THEN created_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' THEN created_at END DESC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' THEN updated_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' THEN updated_at END DESC,
+    id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListUsersAfter :many
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
+FROM users, (SELECT CAST(sqlc.arg('sort_by') AS TEXT) AS sort_by, CAST(sqlc.arg('sort_order') AS TEXT) AS sort_order) AS sort_opts
+WHERE (CAST(sqlc.narg('search') AS TEXT) IS NULL OR (username LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(sqlc.narg('search') AS TEXT) || '%' ESCAPE '\'))
+    AND (sqlc.narg('is_active') IS NULL OR is_active = sqlc.narg('is_active'))
+    AND (sqlc.narg('created_from') IS NULL OR created_at >= datetime(sqlc.narg('created_from')))
+    AND (sqlc.narg('created_before') IS NULL OR created_at < datetime(sqlc.narg('created_before')))
+    AND (deleted_at IS NULL OR CAST(sqlc.arg('include_deleted') AS BOOLEAN))
+    AND (sqlc.narg('after_id') IS NULL
+        OR (sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'asc' AND id > sqlc.narg('after_id'))
+        OR (sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'desc' AND id < sqlc.narg('after_id'))
+        OR (sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' AND (username > sqlc.narg('after_text') OR (username = sqlc.narg('after_text') AND id > sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' AND (username < sqlc.narg('after_text') OR (username = sqlc.narg('after_text') AND id < sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' AND (email > sqlc.narg('after_text') OR (email = sqlc.narg('after_text') AND id > sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' AND (email < sqlc.narg('after_text') OR (email = sqlc.narg('after_text') AND id < sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' AND (created_at > datetime(sqlc.narg('after_time')) OR (created_at = datetime(sqlc.narg('after_time')) AND id > sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' AND (created_at < datetime(sqlc.narg('after_time')) OR (created_at = datetime(sqlc.narg('after_time')) AND id < sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' AND (updated_at > datetime(sqlc.narg('after_time')) OR (updated_at = datetime(sqlc.narg('after_time')) AND id > sqlc.narg('after_id'))))
+        OR (sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' AND (updated_at < datetime(sqlc.narg('after_time')) OR (updated_at = datetime(sqlc.narg('after_time')) AND id < sqlc.narg('after_id')))))
+ORDER BY
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' THEN username END ASC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' THEN username END DESC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' THEN email END ASC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' THEN email END DESC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' THEN created_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' THEN created_at END DESC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' THEN updated_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' THEN updated_at END DESC,
+    CASE WHEN sort_opts.sort_order = 'asc' THEN id END ASC,
+    id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < ?1;
+
+-- name: RestoreUser :one
+UPDATE users
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND deleted_at IS NOT NULL
+RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND deleted_at IS NULL;
 
 -- name: UpdateUser :one
 UPDATE users
@@ -56,5 +129,5 @@ SET
     last_name = COALESCE(?4, last_name),
     is_active = COALESCE(?5, is_active),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = ?1
-RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at;
\ No newline at end of file
+WHERE id = ?1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at;
\ No newline at end of file
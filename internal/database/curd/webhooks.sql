@@ -0,0 +1,74 @@
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+    endpoint_id, event_id, event_type, payload, next_attempt_at
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+) RETURNING id, endpoint_id, event_id, event_type, payload, status, attempts, response_code, error, next_attempt_at, created_at, updated_at;
+
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    url, secret, event_types, description, enabled
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+) RETURNING id, url, secret, event_types, description, enabled, created_at, updated_at;
+
+-- name: DeleteFinishedWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries
+WHERE status <> 'pending' AND next_attempt_at < ?1;
+
+-- name: DeleteWebhookEndpoint :execrows
+DELETE FROM webhook_endpoints
+WHERE id = ?1;
+
+-- name: GetWebhookDelivery :one
+SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, response_code, error, next_attempt_at, created_at, updated_at
+FROM webhook_deliveries
+WHERE id = ?1
+LIMIT 1;
+
+-- name: GetWebhookEndpoint :one
+SELECT id, url, secret, event_types, description, enabled, created_at, updated_at
+FROM webhook_endpoints
+WHERE id = ?1
+LIMIT 1;
+
+-- name: ListDueWebhookDeliveries :many
+SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, response_code, error, next_attempt_at, created_at, updated_at
+FROM webhook_deliveries
+WHERE status = 'pending' AND next_attempt_at <= ?1
+ORDER BY next_attempt_at, id
+LIMIT ?2;
+
+-- name: ListEnabledWebhookEndpoints :many
+SELECT id, url, secret, event_types, description, enabled, created_at, updated_at
+FROM webhook_endpoints
+WHERE enabled = TRUE
+ORDER BY id;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, endpoint_id, event_id, event_type, payload, status, attempts, response_code, error, next_attempt_at, created_at, updated_at
+FROM webhook_deliveries
+WHERE endpoint_id = ?1
+ORDER BY id DESC
+LIMIT ?2;
+
+-- name: ListWebhookEndpoints :many
+SELECT id, url, secret, event_types, description, enabled, created_at, updated_at
+FROM webhook_endpoints
+ORDER BY id;
+
+-- name: RequeueWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = ?2, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status = ?2, attempts = ?3, response_code = ?4, error = ?5, next_attempt_at = ?6, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET url = ?2, secret = ?3, event_types = ?4, description = ?5, enabled = ?6, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1
+RETURNING id, url, secret, event_types, description, enabled, created_at, updated_at;
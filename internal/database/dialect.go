@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Dialect 数据库方言
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite3"
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+)
+
+// ParseDialect 解析配置中的 database.driver
+func ParseDialect(driver string) (Dialect, error) {
+	switch strings.ToLower(driver) {
+	case "", "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return DialectPostgres, nil
+	case "mysql", "mariadb":
+		return DialectMySQL, nil
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// open 打开数据库连接池
+func (d Dialect) open(dsn string) (*sql.DB, error) {
+	switch d {
+	case DialectPostgres:
+		return sql.Open("pgx", dsn)
+	case DialectMySQL:
+		return openMySQL(dsn)
+	default:
+		return sql.Open("sqlite3", dsn)
+	}
+}
+
+// DBTX 生成代码使用的数据库接口，*sql.DB 和 *sql.Tx 均实现该接口
+type DBTX interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// wrap sqlc 按 SQLite 语法生成查询，PostgreSQL 执行前改写占位符，
+// MySQL 需要在同一连接上模拟 RETURNING，改写在连接层完成
+func (d Dialect) wrap(db DBTX) DBTX {
+	if d == DialectPostgres {
+		return postgresDBTX{db}
+	}
+	return db
+}
+
+// postgresDBTX 将 ? 和 ?N 占位符改写为 $N
+type postgresDBTX struct {
+	db DBTX
+}
+
+func (p postgresDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, rebindPostgres(query), args...)
+}
+
+func (p postgresDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, rebindPostgres(query))
+}
+
+func (p postgresDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, rebindPostgres(query), args...)
+}
+
+func (p postgresDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, rebindPostgres(query), args...)
+}
+
+// rebindPostgres 将占位符改写为 $N
+func rebindPostgres(query string) string {
+	return rebind(query, func(index int) string {
+		return "$" + strconv.Itoa(index)
+	})
+}
+
+// rebind 将 ? 和 ?N 占位符替换为 placeholder 的返回值，index 从 1 开始，
+// 跳过字符串、带引号的标识符和注释中的问号
+func rebind(query string, placeholder func(index int) string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	next := 1
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(query[i+1:], ch)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case ch == '?':
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			index := next
+			if j > i+1 {
+				index, _ = strconv.Atoi(query[i+1 : j])
+			} else {
+				next++
+			}
+			b.WriteString(placeholder(index))
+			i = j - 1
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
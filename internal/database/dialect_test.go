@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebindPostgres(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "Numbered placeholders", query: "SELECT * FROM users WHERE id = ?1 AND email = ?2", want: "SELECT * FROM users WHERE id = $1 AND email = $2"},
+		{name: "Reused placeholder", query: "WHERE (?1 IS NULL OR name LIKE '%' || ?1)", want: "WHERE ($1 IS NULL OR name LIKE '%' || $1)"},
+		{name: "Positional placeholders", query: "VALUES (?, ?, ?)", want: "VALUES ($1, $2, $3)"},
+		{name: "Question mark in string literal", query: "SELECT '?1', 'it''s ?' WHERE id = ?1", want: "SELECT '?1', 'it''s ?' WHERE id = $1"},
+		{name: "Question mark in identifier", query: `SELECT "a?" FROM t WHERE id = ?1`, want: `SELECT "a?" FROM t WHERE id = $1`},
+		{name: "Question mark in comment", query: "-- name: GetUser? :one\nSELECT * FROM users WHERE id = ?1", want: "-- name: GetUser? :one\nSELECT * FROM users WHERE id = $1"},
+		{name: "Backslash escape literal", query: `WHERE name LIKE ?1 ESCAPE '\' AND id = ?2`, want: `WHERE name LIKE $1 ESCAPE '\' AND id = $2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rebindPostgres(tt.query))
+		})
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		driver  string
+		want    Dialect
+		wantErr bool
+	}{
+		{driver: "sqlite3", want: DialectSQLite},
+		{driver: "", want: DialectSQLite},
+		{driver: "postgres", want: DialectPostgres},
+		{driver: "PostgreSQL", want: DialectPostgres},
+		{driver: "oracle", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			got, err := ParseDialect(tt.driver)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
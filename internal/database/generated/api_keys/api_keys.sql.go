@@ -8,20 +8,22 @@ package api_keys
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const checkAPIKeyExists = `-- name: CheckAPIKeyExists :one
 SELECT COUNT(*) FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
 `
 
 type CheckAPIKeyExistsParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
 	ProviderType string `json:"provider_type"`
 }
 
 func (q *Queries) CheckAPIKeyExists(ctx context.Context, arg CheckAPIKeyExistsParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, checkAPIKeyExists, arg.UserID, arg.ProviderType)
+	row := q.db.QueryRowContext(ctx, checkAPIKeyExists, arg.UserID, arg.ProjectID, arg.ProviderType)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -29,7 +31,7 @@ func (q *Queries) CheckAPIKeyExists(ctx context.Context, arg CheckAPIKeyExistsPa
 
 const countAPIKeysByProvider = `-- name: CountAPIKeysByProvider :one
 SELECT COUNT(*) FROM api_keys
-WHERE provider_type = ?1 AND is_active = TRUE
+WHERE provider_type = ?1 AND is_active = TRUE AND deleted_at IS NULL
 `
 
 func (q *Queries) CountAPIKeysByProvider(ctx context.Context, providerType string) (int64, error) {
@@ -41,7 +43,7 @@ func (q *Queries) CountAPIKeysByProvider(ctx context.Context, providerType strin
 
 const countAPIKeysByUser = `-- name: CountAPIKeysByUser :one
 SELECT COUNT(*) FROM api_keys
-WHERE user_id = ?1 AND is_active = TRUE
+WHERE user_id = ?1 AND is_active = TRUE AND deleted_at IS NULL
 `
 
 func (q *Queries) CountAPIKeysByUser(ctx context.Context, userID int64) (int64, error) {
@@ -53,14 +55,15 @@ func (q *Queries) CountAPIKeysByUser(ctx context.Context, userID int64) (int64,
 
 const createAPIKey = `-- name: CreateAPIKey :one
 INSERT INTO api_keys (
-    user_id, provider_type, encrypted_key, key_hash, is_active
+    user_id, project_id, provider_type, encrypted_key, key_hash, is_active
 ) VALUES (
-    ?1, ?2, ?3, ?4, ?5
-) RETURNING id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at
 `
 
 type CreateAPIKeyParams struct {
 	UserID       int64        `json:"user_id"`
+	ProjectID    int64        `json:"project_id"`
 	ProviderType string       `json:"provider_type"`
 	EncryptedKey string       `json:"encrypted_key"`
 	KeyHash      string       `json:"key_hash"`
@@ -70,6 +73,7 @@ type CreateAPIKeyParams struct {
 func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
 	row := q.db.QueryRowContext(ctx, createAPIKey,
 		arg.UserID,
+		arg.ProjectID,
 		arg.ProviderType,
 		arg.EncryptedKey,
 		arg.KeyHash,
@@ -79,12 +83,57 @@ func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (Api
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.ProjectID,
 		&i.ProviderType,
 		&i.EncryptedKey,
 		&i.KeyHash,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AllowedModels,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createAPIKeyVersion = `-- name: CreateAPIKeyVersion :one
+INSERT INTO api_key_versions (
+    user_id, project_id, provider_type, version, encrypted_key, key_hash
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5, ?6
+) RETURNING id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+`
+
+type CreateAPIKeyVersionParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+	Version      int64  `json:"version"`
+	EncryptedKey string `json:"encrypted_key"`
+	KeyHash      string `json:"key_hash"`
+}
+
+func (q *Queries) CreateAPIKeyVersion(ctx context.Context, arg CreateAPIKeyVersionParams) (ApiKeyVersion, error) {
+	row := q.db.QueryRowContext(ctx, createAPIKeyVersion,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ProviderType,
+		arg.Version,
+		arg.EncryptedKey,
+		arg.KeyHash,
+	)
+	var i ApiKeyVersion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProjectID,
+		&i.ProviderType,
+		&i.Version,
+		&i.EncryptedKey,
+		&i.KeyHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.GraceExpiresAt,
 	)
 	return i, err
 }
@@ -92,66 +141,104 @@ func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (Api
 const deactivateAPIKey = `-- name: DeactivateAPIKey :exec
 UPDATE api_keys 
 SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = ?1 AND provider_type = ?2
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NULL
 `
 
 type DeactivateAPIKeyParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
 	ProviderType string `json:"provider_type"`
 }
 
 func (q *Queries) DeactivateAPIKey(ctx context.Context, arg DeactivateAPIKeyParams) error {
-	_, err := q.db.ExecContext(ctx, deactivateAPIKey, arg.UserID, arg.ProviderType)
+	_, err := q.db.ExecContext(ctx, deactivateAPIKey, arg.UserID, arg.ProjectID, arg.ProviderType)
 	return err
 }
 
-const deleteAPIKey = `-- name: DeleteAPIKey :exec
-DELETE FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2
+const deleteAPIKeyExpiration = `-- name: DeleteAPIKeyExpiration :exec
+DELETE FROM api_key_expirations
+WHERE api_key_id = ?1
 `
 
-type DeleteAPIKeyParams struct {
-	UserID       int64  `json:"user_id"`
-	ProviderType string `json:"provider_type"`
+func (q *Queries) DeleteAPIKeyExpiration(ctx context.Context, apiKeyID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeyExpiration, apiKeyID)
+	return err
+}
+
+const deleteAPIKeyVersionsByProject = `-- name: DeleteAPIKeyVersionsByProject :exec
+DELETE FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2
+`
+
+type DeleteAPIKeyVersionsByProjectParams struct {
+	UserID    int64 `json:"user_id"`
+	ProjectID int64 `json:"project_id"`
+}
+
+func (q *Queries) DeleteAPIKeyVersionsByProject(ctx context.Context, arg DeleteAPIKeyVersionsByProjectParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIKeyVersionsByProject, arg.UserID, arg.ProjectID)
+	return err
+}
+
+const expireAPIKeyVersionGrace = `-- name: ExpireAPIKeyVersionGrace :exec
+UPDATE api_key_versions
+SET grace_expires_at = ?4
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NOT NULL AND grace_expires_at > ?4
+`
+
+type ExpireAPIKeyVersionGraceParams struct {
+	UserID         int64        `json:"user_id"`
+	ProjectID      int64        `json:"project_id"`
+	ProviderType   string       `json:"provider_type"`
+	GraceExpiresAt sql.NullTime `json:"grace_expires_at"`
 }
 
-func (q *Queries) DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) error {
-	_, err := q.db.ExecContext(ctx, deleteAPIKey, arg.UserID, arg.ProviderType)
+func (q *Queries) ExpireAPIKeyVersionGrace(ctx context.Context, arg ExpireAPIKeyVersionGraceParams) error {
+	_, err := q.db.ExecContext(ctx, expireAPIKeyVersionGrace,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ProviderType,
+		arg.GraceExpiresAt,
+	)
 	return err
 }
 
 const getAPIKey = `-- name: GetAPIKey :one
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
 LIMIT 1
 `
 
 type GetAPIKeyParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
 	ProviderType string `json:"provider_type"`
 }
 
 func (q *Queries) GetAPIKey(ctx context.Context, arg GetAPIKeyParams) (ApiKey, error) {
-	row := q.db.QueryRowContext(ctx, getAPIKey, arg.UserID, arg.ProviderType)
+	row := q.db.QueryRowContext(ctx, getAPIKey, arg.UserID, arg.ProjectID, arg.ProviderType)
 	var i ApiKey
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.ProjectID,
 		&i.ProviderType,
 		&i.EncryptedKey,
 		&i.KeyHash,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AllowedModels,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE id = ?1 LIMIT 1
+WHERE id = ?1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetAPIKeyByID(ctx context.Context, id int64) (ApiKey, error) {
@@ -160,20 +247,225 @@ func (q *Queries) GetAPIKeyByID(ctx context.Context, id int64) (ApiKey, error) {
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.ProjectID,
 		&i.ProviderType,
 		&i.EncryptedKey,
 		&i.KeyHash,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AllowedModels,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getAPIKeyExpiration = `-- name: GetAPIKeyExpiration :one
+SELECT api_key_id, key_hash, expires_at, warned_at, expired_at
+FROM api_key_expirations
+WHERE api_key_id = ?1 LIMIT 1
+`
+
+func (q *Queries) GetAPIKeyExpiration(ctx context.Context, apiKeyID int64) (ApiKeyExpiration, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyExpiration, apiKeyID)
+	var i ApiKeyExpiration
+	err := row.Scan(
+		&i.ApiKeyID,
+		&i.KeyHash,
+		&i.ExpiresAt,
+		&i.WarnedAt,
+		&i.ExpiredAt,
 	)
 	return i, err
 }
 
+const getAPIKeyValidation = `-- name: GetAPIKeyValidation :one
+SELECT api_key_id, key_hash, valid, error, validated_at
+FROM api_key_validations
+WHERE api_key_id = ?1 LIMIT 1
+`
+
+func (q *Queries) GetAPIKeyValidation(ctx context.Context, apiKeyID int64) (ApiKeyValidation, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyValidation, apiKeyID)
+	var i ApiKeyValidation
+	err := row.Scan(
+		&i.ApiKeyID,
+		&i.KeyHash,
+		&i.Valid,
+		&i.Error,
+		&i.ValidatedAt,
+	)
+	return i, err
+}
+
+const getLatestAPIKeyVersion = `-- name: GetLatestAPIKeyVersion :one
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3
+ORDER BY version DESC
+LIMIT 1
+`
+
+type GetLatestAPIKeyVersionParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+func (q *Queries) GetLatestAPIKeyVersion(ctx context.Context, arg GetLatestAPIKeyVersionParams) (ApiKeyVersion, error) {
+	row := q.db.QueryRowContext(ctx, getLatestAPIKeyVersion, arg.UserID, arg.ProjectID, arg.ProviderType)
+	var i ApiKeyVersion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProjectID,
+		&i.ProviderType,
+		&i.Version,
+		&i.EncryptedKey,
+		&i.KeyHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.GraceExpiresAt,
+	)
+	return i, err
+}
+
+const getPreviousAPIKeyVersion = `-- name: GetPreviousAPIKeyVersion :one
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NOT NULL AND grace_expires_at > ?4
+ORDER BY version DESC
+LIMIT 1
+`
+
+type GetPreviousAPIKeyVersionParams struct {
+	UserID         int64        `json:"user_id"`
+	ProjectID      int64        `json:"project_id"`
+	ProviderType   string       `json:"provider_type"`
+	GraceExpiresAt sql.NullTime `json:"grace_expires_at"`
+}
+
+func (q *Queries) GetPreviousAPIKeyVersion(ctx context.Context, arg GetPreviousAPIKeyVersionParams) (ApiKeyVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousAPIKeyVersion,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ProviderType,
+		arg.GraceExpiresAt,
+	)
+	var i ApiKeyVersion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProjectID,
+		&i.ProviderType,
+		&i.Version,
+		&i.EncryptedKey,
+		&i.KeyHash,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.GraceExpiresAt,
+	)
+	return i, err
+}
+
+const listAPIKeyVersions = `-- name: ListAPIKeyVersions :many
+SELECT id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at
+FROM api_key_versions
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3
+ORDER BY version DESC
+`
+
+type ListAPIKeyVersionsParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+func (q *Queries) ListAPIKeyVersions(ctx context.Context, arg ListAPIKeyVersionsParams) ([]ApiKeyVersion, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeyVersions, arg.UserID, arg.ProjectID, arg.ProviderType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKeyVersion{}
+	for rows.Next() {
+		var i ApiKeyVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProjectID,
+			&i.ProviderType,
+			&i.Version,
+			&i.EncryptedKey,
+			&i.KeyHash,
+			&i.CreatedAt,
+			&i.RotatedAt,
+			&i.GraceExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAPIKeysByProject = `-- name: ListAPIKeysByProject :many
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
+FROM api_keys
+WHERE user_id = ?1 AND project_id = ?2 AND is_active = TRUE AND deleted_at IS NULL
+ORDER BY provider_type
+`
+
+type ListAPIKeysByProjectParams struct {
+	UserID    int64 `json:"user_id"`
+	ProjectID int64 `json:"project_id"`
+}
+
+func (q *Queries) ListAPIKeysByProject(ctx context.Context, arg ListAPIKeysByProjectParams) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeysByProject, arg.UserID, arg.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProjectID,
+			&i.ProviderType,
+			&i.EncryptedKey,
+			&i.KeyHash,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AllowedModels,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAPIKeysByProvider = `-- name: ListAPIKeysByProvider :many
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE provider_type = ?1 AND is_active = TRUE
+WHERE provider_type = ?1 AND is_active = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -189,12 +481,15 @@ func (q *Queries) ListAPIKeysByProvider(ctx context.Context, providerType string
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.ProjectID,
 			&i.ProviderType,
 			&i.EncryptedKey,
 			&i.KeyHash,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AllowedModels,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -210,14 +505,19 @@ func (q *Queries) ListAPIKeysByProvider(ctx context.Context, providerType string
 }
 
 const listAPIKeysByUser = `-- name: ListAPIKeysByUser :many
-SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at 
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
 FROM api_keys
-WHERE user_id = ?1 AND is_active = TRUE
+WHERE user_id = ?1 AND is_active = TRUE AND (deleted_at IS NULL OR CAST(?2 AS BOOLEAN))
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]ApiKey, error) {
-	rows, err := q.db.QueryContext(ctx, listAPIKeysByUser, userID)
+type ListAPIKeysByUserParams struct {
+	UserID         int64 `json:"user_id"`
+	IncludeDeleted bool  `json:"include_deleted"`
+}
+
+func (q *Queries) ListAPIKeysByUser(ctx context.Context, arg ListAPIKeysByUserParams) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeysByUser, arg.UserID, arg.IncludeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -228,12 +528,109 @@ func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]ApiKey
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.ProjectID,
 			&i.ProviderType,
 			&i.EncryptedKey,
 			&i.KeyHash,
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AllowedModels,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveAPIKeys = `-- name: ListActiveAPIKeys :many
+SELECT id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at 
+FROM api_keys
+WHERE is_active = TRUE AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListActiveAPIKeys(ctx context.Context) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProjectID,
+			&i.ProviderType,
+			&i.EncryptedKey,
+			&i.KeyHash,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AllowedModels,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueAPIKeyExpirations = `-- name: ListDueAPIKeyExpirations :many
+SELECT e.api_key_id, e.key_hash, e.expires_at, e.warned_at, e.expired_at, k.user_id, k.project_id, k.provider_type
+FROM api_key_expirations e
+JOIN api_keys k ON k.id = e.api_key_id
+WHERE k.is_active = TRUE AND k.deleted_at IS NULL AND e.key_hash = k.key_hash
+    AND e.expired_at IS NULL AND e.expires_at <= ?1
+ORDER BY e.expires_at
+`
+
+type ListDueAPIKeyExpirationsRow struct {
+	ApiKeyID     int64        `json:"api_key_id"`
+	KeyHash      string       `json:"key_hash"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	WarnedAt     sql.NullTime `json:"warned_at"`
+	ExpiredAt    sql.NullTime `json:"expired_at"`
+	UserID       int64        `json:"user_id"`
+	ProjectID    int64        `json:"project_id"`
+	ProviderType string       `json:"provider_type"`
+}
+
+func (q *Queries) ListDueAPIKeyExpirations(ctx context.Context, expiresAt time.Time) ([]ListDueAPIKeyExpirationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDueAPIKeyExpirations, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDueAPIKeyExpirationsRow{}
+	for rows.Next() {
+		var i ListDueAPIKeyExpirationsRow
+		if err := rows.Scan(
+			&i.ApiKeyID,
+			&i.KeyHash,
+			&i.ExpiresAt,
+			&i.WarnedAt,
+			&i.ExpiredAt,
+			&i.UserID,
+			&i.ProjectID,
+			&i.ProviderType,
 		); err != nil {
 			return nil, err
 		}
@@ -248,15 +645,139 @@ func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]ApiKey
 	return items, nil
 }
 
+const markAPIKeyExpirationWarned = `-- name: MarkAPIKeyExpirationWarned :exec
+UPDATE api_key_expirations
+SET warned_at = ?2
+WHERE api_key_id = ?1
+`
+
+type MarkAPIKeyExpirationWarnedParams struct {
+	ApiKeyID int64        `json:"api_key_id"`
+	WarnedAt sql.NullTime `json:"warned_at"`
+}
+
+func (q *Queries) MarkAPIKeyExpirationWarned(ctx context.Context, arg MarkAPIKeyExpirationWarnedParams) error {
+	_, err := q.db.ExecContext(ctx, markAPIKeyExpirationWarned, arg.ApiKeyID, arg.WarnedAt)
+	return err
+}
+
+const markAPIKeyExpired = `-- name: MarkAPIKeyExpired :exec
+UPDATE api_key_expirations
+SET expired_at = ?2
+WHERE api_key_id = ?1
+`
+
+type MarkAPIKeyExpiredParams struct {
+	ApiKeyID  int64        `json:"api_key_id"`
+	ExpiredAt sql.NullTime `json:"expired_at"`
+}
+
+func (q *Queries) MarkAPIKeyExpired(ctx context.Context, arg MarkAPIKeyExpiredParams) error {
+	_, err := q.db.ExecContext(ctx, markAPIKeyExpired, arg.ApiKeyID, arg.ExpiredAt)
+	return err
+}
+
+const purgeDeletedAPIKey = `-- name: PurgeDeletedAPIKey :exec
+DELETE FROM api_keys
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NOT NULL
+`
+
+type PurgeDeletedAPIKeyParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+func (q *Queries) PurgeDeletedAPIKey(ctx context.Context, arg PurgeDeletedAPIKeyParams) error {
+	_, err := q.db.ExecContext(ctx, purgeDeletedAPIKey, arg.UserID, arg.ProjectID, arg.ProviderType)
+	return err
+}
+
+const restoreAPIKey = `-- name: RestoreAPIKey :one
+UPDATE api_keys
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NOT NULL
+RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at
+`
+
+type RestoreAPIKeyParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+func (q *Queries) RestoreAPIKey(ctx context.Context, arg RestoreAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, restoreAPIKey, arg.UserID, arg.ProjectID, arg.ProviderType)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ProjectID,
+		&i.ProviderType,
+		&i.EncryptedKey,
+		&i.KeyHash,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AllowedModels,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const retireAPIKeyVersions = `-- name: RetireAPIKeyVersions :exec
+UPDATE api_key_versions
+SET rotated_at = CURRENT_TIMESTAMP, grace_expires_at = ?4
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND rotated_at IS NULL
+`
+
+type RetireAPIKeyVersionsParams struct {
+	UserID         int64        `json:"user_id"`
+	ProjectID      int64        `json:"project_id"`
+	ProviderType   string       `json:"provider_type"`
+	GraceExpiresAt sql.NullTime `json:"grace_expires_at"`
+}
+
+func (q *Queries) RetireAPIKeyVersions(ctx context.Context, arg RetireAPIKeyVersionsParams) error {
+	_, err := q.db.ExecContext(ctx, retireAPIKeyVersions,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ProviderType,
+		arg.GraceExpiresAt,
+	)
+	return err
+}
+
+const softDeleteAPIKey = `-- name: SoftDeleteAPIKey :execrows
+UPDATE api_keys
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND deleted_at IS NULL
+`
+
+type SoftDeleteAPIKeyParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+func (q *Queries) SoftDeleteAPIKey(ctx context.Context, arg SoftDeleteAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteAPIKey, arg.UserID, arg.ProjectID, arg.ProviderType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateAPIKey = `-- name: UpdateAPIKey :one
 UPDATE api_keys 
-SET encrypted_key = ?3, key_hash = ?4, updated_at = CURRENT_TIMESTAMP
-WHERE user_id = ?1 AND provider_type = ?2 AND is_active = TRUE
-RETURNING id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at
+SET encrypted_key = ?4, key_hash = ?5, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
+RETURNING id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at, allowed_models, deleted_at
 `
 
 type UpdateAPIKeyParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"`
 	ProviderType string `json:"provider_type"`
 	EncryptedKey string `json:"encrypted_key"`
 	KeyHash      string `json:"key_hash"`
@@ -265,6 +786,7 @@ type UpdateAPIKeyParams struct {
 func (q *Queries) UpdateAPIKey(ctx context.Context, arg UpdateAPIKeyParams) (ApiKey, error) {
 	row := q.db.QueryRowContext(ctx, updateAPIKey,
 		arg.UserID,
+		arg.ProjectID,
 		arg.ProviderType,
 		arg.EncryptedKey,
 		arg.KeyHash,
@@ -273,12 +795,97 @@ func (q *Queries) UpdateAPIKey(ctx context.Context, arg UpdateAPIKeyParams) (Api
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.ProjectID,
 		&i.ProviderType,
 		&i.EncryptedKey,
 		&i.KeyHash,
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AllowedModels,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const updateAPIKeyAllowedModels = `-- name: UpdateAPIKeyAllowedModels :execrows
+UPDATE api_keys 
+SET allowed_models = ?4, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3 AND is_active = TRUE AND deleted_at IS NULL
+`
+
+type UpdateAPIKeyAllowedModelsParams struct {
+	UserID        int64  `json:"user_id"`
+	ProjectID     int64  `json:"project_id"`
+	ProviderType  string `json:"provider_type"`
+	AllowedModels string `json:"allowed_models"`
+}
+
+func (q *Queries) UpdateAPIKeyAllowedModels(ctx context.Context, arg UpdateAPIKeyAllowedModelsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAPIKeyAllowedModels,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ProviderType,
+		arg.AllowedModels,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertAPIKeyExpiration = `-- name: UpsertAPIKeyExpiration :exec
+INSERT INTO api_key_expirations (
+    api_key_id, key_hash, expires_at, warned_at, expired_at
+) VALUES (
+    ?1, ?2, ?3, NULL, NULL
+)
+ON CONFLICT (api_key_id) DO UPDATE SET
+    key_hash = excluded.key_hash,
+    expires_at = excluded.expires_at,
+    warned_at = NULL,
+    expired_at = NULL
+`
+
+type UpsertAPIKeyExpirationParams struct {
+	ApiKeyID  int64     `json:"api_key_id"`
+	KeyHash   string    `json:"key_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) UpsertAPIKeyExpiration(ctx context.Context, arg UpsertAPIKeyExpirationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAPIKeyExpiration, arg.ApiKeyID, arg.KeyHash, arg.ExpiresAt)
+	return err
+}
+
+const upsertAPIKeyValidation = `-- name: UpsertAPIKeyValidation :exec
+INSERT INTO api_key_validations (
+    api_key_id, key_hash, valid, error, validated_at
+) VALUES (
+    ?1, ?2, ?3, ?4, ?5
+)
+ON CONFLICT (api_key_id) DO UPDATE SET
+    key_hash = excluded.key_hash,
+    valid = excluded.valid,
+    error = excluded.error,
+    validated_at = excluded.validated_at
+`
+
+type UpsertAPIKeyValidationParams struct {
+	ApiKeyID    int64     `json:"api_key_id"`
+	KeyHash     string    `json:"key_hash"`
+	Valid       bool      `json:"valid"`
+	Error       string    `json:"error"`
+	ValidatedAt time.Time `json:"validated_at"`
+}
+
+func (q *Queries) UpsertAPIKeyValidation(ctx context.Context, arg UpsertAPIKeyValidationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAPIKeyValidation,
+		arg.ApiKeyID,
+		arg.KeyHash,
+		arg.Valid,
+		arg.Error,
+		arg.ValidatedAt,
+	)
+	return err
+}
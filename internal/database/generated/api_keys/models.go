@@ -6,15 +6,48 @@ package api_keys
 
 import (
 	"database/sql"
+	"time"
 )
 
 type ApiKey struct {
-	ID           int64        `json:"id"`
-	UserID       int64        `json:"user_id"`
-	ProviderType string       `json:"provider_type"`
-	EncryptedKey string       `json:"encrypted_key"`
-	KeyHash      string       `json:"key_hash"`
-	IsActive     sql.NullBool `json:"is_active"`
-	CreatedAt    sql.NullTime `json:"created_at"`
-	UpdatedAt    sql.NullTime `json:"updated_at"`
+	ID            int64        `json:"id"`
+	UserID        int64        `json:"user_id"`
+	ProjectID     int64        `json:"project_id"`
+	ProviderType  string       `json:"provider_type"`
+	EncryptedKey  string       `json:"encrypted_key"`
+	KeyHash       string       `json:"key_hash"`
+	IsActive      sql.NullBool `json:"is_active"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	UpdatedAt     sql.NullTime `json:"updated_at"`
+	AllowedModels string       `json:"allowed_models"`
+	DeletedAt     sql.NullTime `json:"deleted_at"`
+}
+
+type ApiKeyExpiration struct {
+	ApiKeyID  int64        `json:"api_key_id"`
+	KeyHash   string       `json:"key_hash"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	WarnedAt  sql.NullTime `json:"warned_at"`
+	ExpiredAt sql.NullTime `json:"expired_at"`
+}
+
+type ApiKeyValidation struct {
+	ApiKeyID    int64     `json:"api_key_id"`
+	KeyHash     string    `json:"key_hash"`
+	Valid       bool      `json:"valid"`
+	Error       string    `json:"error"`
+	ValidatedAt time.Time `json:"validated_at"`
+}
+
+type ApiKeyVersion struct {
+	ID             int64        `json:"id"`
+	UserID         int64        `json:"user_id"`
+	ProjectID      int64        `json:"project_id"`
+	ProviderType   string       `json:"provider_type"`
+	Version        int64        `json:"version"`
+	EncryptedKey   string       `json:"encrypted_key"`
+	KeyHash        string       `json:"key_hash"`
+	CreatedAt      sql.NullTime `json:"created_at"`
+	RotatedAt      sql.NullTime `json:"rotated_at"`
+	GraceExpiresAt sql.NullTime `json:"grace_expires_at"`
 }
@@ -6,6 +6,7 @@ package api_keys
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
@@ -13,13 +14,33 @@ type Querier interface {
 	CountAPIKeysByProvider(ctx context.Context, providerType string) (int64, error)
 	CountAPIKeysByUser(ctx context.Context, userID int64) (int64, error)
 	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAPIKeyVersion(ctx context.Context, arg CreateAPIKeyVersionParams) (ApiKeyVersion, error)
 	DeactivateAPIKey(ctx context.Context, arg DeactivateAPIKeyParams) error
-	DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) error
+	DeleteAPIKeyExpiration(ctx context.Context, apiKeyID int64) error
+	DeleteAPIKeyVersionsByProject(ctx context.Context, arg DeleteAPIKeyVersionsByProjectParams) error
+	ExpireAPIKeyVersionGrace(ctx context.Context, arg ExpireAPIKeyVersionGraceParams) error
 	GetAPIKey(ctx context.Context, arg GetAPIKeyParams) (ApiKey, error)
 	GetAPIKeyByID(ctx context.Context, id int64) (ApiKey, error)
+	GetAPIKeyExpiration(ctx context.Context, apiKeyID int64) (ApiKeyExpiration, error)
+	GetAPIKeyValidation(ctx context.Context, apiKeyID int64) (ApiKeyValidation, error)
+	GetLatestAPIKeyVersion(ctx context.Context, arg GetLatestAPIKeyVersionParams) (ApiKeyVersion, error)
+	GetPreviousAPIKeyVersion(ctx context.Context, arg GetPreviousAPIKeyVersionParams) (ApiKeyVersion, error)
+	ListAPIKeyVersions(ctx context.Context, arg ListAPIKeyVersionsParams) ([]ApiKeyVersion, error)
+	ListAPIKeysByProject(ctx context.Context, arg ListAPIKeysByProjectParams) ([]ApiKey, error)
 	ListAPIKeysByProvider(ctx context.Context, providerType string) ([]ApiKey, error)
-	ListAPIKeysByUser(ctx context.Context, userID int64) ([]ApiKey, error)
+	ListAPIKeysByUser(ctx context.Context, arg ListAPIKeysByUserParams) ([]ApiKey, error)
+	ListActiveAPIKeys(ctx context.Context) ([]ApiKey, error)
+	ListDueAPIKeyExpirations(ctx context.Context, expiresAt time.Time) ([]ListDueAPIKeyExpirationsRow, error)
+	MarkAPIKeyExpirationWarned(ctx context.Context, arg MarkAPIKeyExpirationWarnedParams) error
+	MarkAPIKeyExpired(ctx context.Context, arg MarkAPIKeyExpiredParams) error
+	PurgeDeletedAPIKey(ctx context.Context, arg PurgeDeletedAPIKeyParams) error
+	RestoreAPIKey(ctx context.Context, arg RestoreAPIKeyParams) (ApiKey, error)
+	RetireAPIKeyVersions(ctx context.Context, arg RetireAPIKeyVersionsParams) error
+	SoftDeleteAPIKey(ctx context.Context, arg SoftDeleteAPIKeyParams) (int64, error)
 	UpdateAPIKey(ctx context.Context, arg UpdateAPIKeyParams) (ApiKey, error)
+	UpdateAPIKeyAllowedModels(ctx context.Context, arg UpdateAPIKeyAllowedModelsParams) (int64, error)
+	UpsertAPIKeyExpiration(ctx context.Context, arg UpsertAPIKeyExpirationParams) error
+	UpsertAPIKeyValidation(ctx context.Context, arg UpsertAPIKeyValidationParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -19,4 +19,5 @@ type User struct {
 	IsAdmin      sql.NullBool   `json:"is_admin"`
 	CreatedAt    sql.NullTime   `json:"created_at"`
 	UpdatedAt    sql.NullTime   `json:"updated_at"`
+	DeletedAt    sql.NullTime   `json:"deleted_at"`
 }
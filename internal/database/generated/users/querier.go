@@ -6,20 +6,25 @@ package users
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	CountFilteredUsers(ctx context.Context, arg CountFilteredUsersParams) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
 	CountUsersByEmail(ctx context.Context, email string) (int64, error)
 	CountUsersByUsername(ctx context.Context, username string) (int64, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id int64) error
 	GetActiveUsers(ctx context.Context, arg GetActiveUsersParams) ([]User, error)
 	GetAdminUsers(ctx context.Context) ([]User, error)
 	GetUser(ctx context.Context, id int64) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error)
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	RestoreUser(ctx context.Context, id int64) (User, error)
+	SoftDeleteUser(ctx context.Context, id int64) (int64, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 }
 
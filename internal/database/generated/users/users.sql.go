@@ -10,8 +10,39 @@ import (
 	"database/sql"
 )
 
+const countFilteredUsers = `-- name: CountFilteredUsers :one
+SELECT COUNT(*) FROM users
+WHERE (CAST(?1 AS TEXT) IS NULL OR (username LIKE '%' || CAST(?1 AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(?1 AS TEXT) || '%' ESCAPE '\'))
+    AND (?2 IS NULL OR is_active = ?2)
+    AND (?3 IS NULL OR created_at >= datetime(?3))
+    AND (?4 IS NULL OR created_at < datetime(?4))
+    AND (deleted_at IS NULL OR CAST(?5 AS BOOLEAN))
+`
+
+type CountFilteredUsersParams struct {
+	Search         sql.NullString `json:"search"`
+	IsActive       interface{}    `json:"is_active"`
+	CreatedFrom    interface{}    `json:"created_from"`
+	CreatedBefore  interface{}    `json:"created_before"`
+	IncludeDeleted bool           `json:"include_deleted"`
+}
+
+func (q *Queries) CountFilteredUsers(ctx context.Context, arg CountFilteredUsersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilteredUsers,
+		arg.Search,
+		arg.IsActive,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.IncludeDeleted,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUsers = `-- name: CountUsers :one
 SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
@@ -50,7 +81,7 @@ INSERT INTO users (
     username, email, password_hash, first_name, last_name
 ) VALUES (
     ?1, ?2, ?3, ?4, ?5
-) RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at
+) RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
 `
 
 type CreateUserParams struct {
@@ -81,23 +112,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = ?1
-`
-
-func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
-}
-
 const getActiveUsers = `-- name: GetActiveUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE is_active = TRUE
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE is_active = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT ?1 OFFSET ?2
 `
@@ -127,6 +149,7 @@ func (q *Queries) GetActiveUsers(ctx context.Context, arg GetActiveUsersParams)
 			&i.IsAdmin,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -142,8 +165,8 @@ func (q *Queries) GetActiveUsers(ctx context.Context, arg GetActiveUsersParams)
 }
 
 const getAdminUsers = `-- name: GetAdminUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE is_admin = TRUE
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE is_admin = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -167,6 +190,7 @@ func (q *Queries) GetAdminUsers(ctx context.Context) ([]User, error) {
 			&i.IsAdmin,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -182,8 +206,8 @@ func (q *Queries) GetAdminUsers(ctx context.Context) ([]User, error) {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE id = ?1 LIMIT 1
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE id = ?1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
@@ -200,13 +224,14 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE email = ?1 LIMIT 1
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE email = ?1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -223,13 +248,14 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-WHERE username = ?1 LIMIT 1
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at FROM users
+WHERE username = ?1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -246,23 +272,152 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at FROM users
-ORDER BY created_at DESC
-LIMIT ?1 OFFSET ?2
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
+FROM users, (SELECT CAST(?1 AS TEXT) AS sort_by, CAST(?2 AS TEXT) AS sort_order) AS sort_opts
+WHERE (CAST(?3 AS TEXT) IS NULL OR (username LIKE '%' || CAST(?3 AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(?3 AS TEXT) || '%' ESCAPE '\'))
+    AND (?4 IS NULL OR is_active = ?4)
+    AND (?5 IS NULL OR created_at >= datetime(?5))
+    AND (?6 IS NULL OR created_at < datetime(?6))
+    AND (deleted_at IS NULL OR CAST(?7 AS BOOLEAN))
+ORDER BY
+    CASE WHEN sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'asc' THEN id END ASC,
+    CASE WHEN sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'desc' THEN id END DESC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' THEN username END ASC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' THEN username END DESC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' THEN email END ASC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' THEN email END DESC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' THEN created_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' THEN created_at END DESC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' THEN updated_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' THEN updated_at END DESC,
+    id DESC
+LIMIT ?9 OFFSET ?8
 `
 
 type ListUsersParams struct {
-	Limit  int64 `json:"limit"`
-	Offset int64 `json:"offset"`
+	SortBy         string         `json:"sort_by"`
+	SortOrder      string         `json:"sort_order"`
+	Search         sql.NullString `json:"search"`
+	IsActive       interface{}    `json:"is_active"`
+	CreatedFrom    interface{}    `json:"created_from"`
+	CreatedBefore  interface{}    `json:"created_before"`
+	IncludeDeleted bool           `json:"include_deleted"`
+	Offset         int64          `json:"offset"`
+	Limit          int64          `json:"limit"`
 }
 
 func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listUsers,
+		arg.SortBy,
+		arg.SortOrder,
+		arg.Search,
+		arg.IsActive,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.IncludeDeleted,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.PasswordHash,
+			&i.FirstName,
+			&i.LastName,
+			&i.IsActive,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
+FROM users, (SELECT CAST(?1 AS TEXT) AS sort_by, CAST(?2 AS TEXT) AS sort_order) AS sort_opts
+WHERE (CAST(?3 AS TEXT) IS NULL OR (username LIKE '%' || CAST(?3 AS TEXT) || '%' ESCAPE '\') OR (email LIKE '%' || CAST(?3 AS TEXT) || '%' ESCAPE '\'))
+    AND (?4 IS NULL OR is_active = ?4)
+    AND (?5 IS NULL OR created_at >= datetime(?5))
+    AND (?6 IS NULL OR created_at < datetime(?6))
+    AND (deleted_at IS NULL OR CAST(?7 AS BOOLEAN))
+    AND (?8 IS NULL
+        OR (sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'asc' AND id > ?8)
+        OR (sort_opts.sort_by = 'id' AND sort_opts.sort_order = 'desc' AND id < ?8)
+        OR (sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' AND (username > ?9 OR (username = ?9 AND id > ?8)))
+        OR (sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' AND (username < ?9 OR (username = ?9 AND id < ?8)))
+        OR (sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' AND (email > ?9 OR (email = ?9 AND id > ?8)))
+        OR (sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' AND (email < ?9 OR (email = ?9 AND id < ?8)))
+        OR (sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' AND (created_at > datetime(?10) OR (created_at = datetime(?10) AND id > ?8)))
+        OR (sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' AND (created_at < datetime(?10) OR (created_at = datetime(?10) AND id < ?8)))
+        OR (sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' AND (updated_at > datetime(?10) OR (updated_at = datetime(?10) AND id > ?8)))
+        OR (sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' AND (updated_at < datetime(?10) OR (updated_at = datetime(?10) AND id < ?8))))
+ORDER BY
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'asc' THEN username END ASC,
+    CASE WHEN sort_opts.sort_by = 'username' AND sort_opts.sort_order = 'desc' THEN username END DESC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'asc' THEN email END ASC,
+    CASE WHEN sort_opts.sort_by = 'email' AND sort_opts.sort_order = 'desc' THEN email END DESC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'asc' THEN created_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'created_at' AND sort_opts.sort_order = 'desc' THEN created_at END DESC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'asc' THEN updated_at END ASC,
+    CASE WHEN sort_opts.sort_by = 'updated_at' AND sort_opts.sort_order = 'desc' THEN updated_at END DESC,
+    CASE WHEN sort_opts.sort_order = 'asc' THEN id END ASC,
+    id DESC
+LIMIT ?11
+`
+
+type ListUsersAfterParams struct {
+	SortBy         string         `json:"sort_by"`
+	SortOrder      string         `json:"sort_order"`
+	Search         sql.NullString `json:"search"`
+	IsActive       interface{}    `json:"is_active"`
+	CreatedFrom    interface{}    `json:"created_from"`
+	CreatedBefore  interface{}    `json:"created_before"`
+	IncludeDeleted bool           `json:"include_deleted"`
+	AfterID        interface{}    `json:"after_id"`
+	AfterText      sql.NullString `json:"after_text"`
+	AfterTime      interface{}    `json:"after_time"`
+	Limit          int64          `json:"limit"`
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersAfter,
+		arg.SortBy,
+		arg.SortOrder,
+		arg.Search,
+		arg.IsActive,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.IncludeDeleted,
+		arg.AfterID,
+		arg.AfterText,
+		arg.AfterTime,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +436,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.IsAdmin,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -295,6 +451,63 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at < ?1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND deleted_at IS NOT NULL
+RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.FirstName,
+		&i.LastName,
+		&i.IsActive,
+		&i.IsAdmin,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET 
@@ -303,8 +516,8 @@ SET
     last_name = COALESCE(?4, last_name),
     is_active = COALESCE(?5, is_active),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = ?1
-RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at
+WHERE id = ?1 AND deleted_at IS NULL
+RETURNING id, username, email, password_hash, first_name, last_name, is_active, is_admin, created_at, updated_at, deleted_at
 `
 
 type UpdateUserParams struct {
@@ -335,6 +548,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrationDirs 迁移目录的执行顺序，被外键引用的表在前
+var migrationDirs = []string{
+	"users",
+	"api_keys",
+	"refresh_tokens",
+	"personal_access_tokens",
+	"ai_usage",
+	"user_preferences",
+	"user_permissions",
+	"projects",
+	"webhooks",
+	"scheduled_jobs",
+	"notification_channels",
+	"model_metadata",
+	"ai_spend",
+	"fine_tuning_jobs",
+	"evals",
+	"experiments",
+	"feedback",
+	"personas",
+	"memories",
+}
+
+// migrationsTable 记录已执行的迁移版本
+const migrationsTable = "schema_migrations"
+
+// Migration 一个迁移版本
+type Migration struct {
+	Version   string // <表>/<文件名>，如 users/001_create_users_table
+	Up        string
+	Down      string // 为空表示不能回滚
+	AppliedAt *time.Time
+}
+
+// LoadMigrations 按执行顺序读取方言对应的迁移文件
+func LoadMigrations(fsys fs.FS, dialect Dialect) ([]Migration, error) {
+	var migrations []Migration
+	for _, table := range migrationDirs {
+		dir := table
+		if dialect != DialectSQLite {
+			dir = path.Join(table, string(dialect))
+		}
+		files, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			if strings.HasSuffix(file, ".down.sql") {
+				continue
+			}
+			up, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("read migration %s: %w", file, err)
+			}
+			down, err := fs.ReadFile(fsys, strings.TrimSuffix(file, ".sql")+".down.sql")
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("read migration %s: %w", file, err)
+			}
+			migrations = append(migrations, Migration{
+				Version: table + "/" + strings.TrimSuffix(path.Base(file), ".sql"),
+				Up:      string(up),
+				Down:    string(down),
+			})
+		}
+	}
+	return migrations, nil
+}
+
+// Migrator 执行迁移并在 schema_migrations 表中记录版本
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+}
+
+// NewMigrator 创建迁移器
+func NewMigrator(db *DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys, db.dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status 返回全部迁移及其执行时间
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]Migration, len(m.migrations))
+	for i, migration := range m.migrations {
+		if appliedAt, ok := applied[migration.Version]; ok {
+			migration.AppliedAt = &appliedAt
+		}
+		status[i] = migration
+	}
+	return status, nil
+}
+
+// Up 按顺序执行未执行的迁移，steps 不大于 0 时执行全部，返回执行的版本
+func (m *Migrator) Up(ctx context.Context, steps int) ([]string, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	for _, migration := range status {
+		if migration.AppliedAt != nil {
+			continue
+		}
+		if steps > 0 && len(done) == steps {
+			break
+		}
+		if err := m.run(ctx, migration.Version, migration.Up, true); err != nil {
+			return done, err
+		}
+		done = append(done, migration.Version)
+	}
+	return done, nil
+}
+
+// Down 按执行时间从新到旧回滚最近执行的 steps 个迁移，同一时间执行的按迁移顺序倒序回滚，返回回滚的版本。
+// 后加入靠前目录的迁移比目录中靠后的已执行迁移更晚执行，需要先回滚
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var applied []Migration
+	for _, migration := range status {
+		if migration.AppliedAt != nil {
+			applied = append(applied, migration)
+		}
+	}
+	// Status 按迁移顺序排列，稳定排序保留同一时间执行的迁移之间的顺序
+	sort.SliceStable(applied, func(i, j int) bool {
+		return applied[i].AppliedAt.Before(*applied[j].AppliedAt)
+	})
+
+	var done []string
+	for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := applied[i]
+		if strings.TrimSpace(migration.Down) == "" {
+			return done, fmt.Errorf("migration %s has no down migration", migration.Version)
+		}
+		if err := m.run(ctx, migration.Version, migration.Down, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration.Version)
+	}
+	return done, nil
+}
+
+// Baseline 将 version 及之前的迁移标记为已执行但不执行，用于手动执行过迁移的数据库，
+// version 为空时标记全部
+func (m *Migrator) Baseline(ctx context.Context, version string) ([]string, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" && !m.exists(version) {
+		return nil, fmt.Errorf("unknown migration version %q", version)
+	}
+	var done []string
+	for _, migration := range status {
+		if migration.AppliedAt == nil {
+			if err := m.run(ctx, migration.Version, "", true); err != nil {
+				return done, err
+			}
+			done = append(done, migration.Version)
+		}
+		if migration.Version == version {
+			break
+		}
+	}
+	return done, nil
+}
+
+func (m *Migrator) exists(version string) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// run 在事务中执行迁移语句并更新版本记录，MySQL 的DDL会隐式提交，失败时可能需要手动清理
+func (m *Migrator) run(ctx context.Context, version, script string, up bool) error {
+	tx, err := m.db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %s: %w", version, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+	}
+
+	db := m.db.dialect.wrap(tx)
+	if up {
+		_, err = db.ExecContext(ctx, "INSERT INTO "+migrationsTable+" (version, applied_at) VALUES (?1, ?2)", version, time.Now().UTC())
+	} else {
+		_, err = db.ExecContext(ctx, "DELETE FROM "+migrationsTable+" WHERE version = ?1", version)
+	}
+	if err != nil {
+		return fmt.Errorf("record migration %s: %w", version, err)
+	}
+	return tx.Commit()
+}
+
+// applied 读取已执行的版本，首次使用时创建版本表
+func (m *Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	timestamp := "DATETIME"
+	if m.db.dialect == DialectPostgres {
+		timestamp = "TIMESTAMPTZ"
+	}
+	_, err := m.db.conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationsTable+
+		" (version VARCHAR(255) PRIMARY KEY, applied_at "+timestamp+" NOT NULL)")
+	if err != nil {
+		return nil, fmt.Errorf("create %s table: %w", migrationsTable, err)
+	}
+
+	rows, err := m.db.conn.QueryContext(ctx, "SELECT version, applied_at FROM "+migrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", migrationsTable, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("read %s: %w", migrationsTable, err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// splitStatements 按分号拆分迁移脚本，跳过字符串和注释中的分号，丢弃只有注释的语句
+func splitStatements(script string) []string {
+	var statements []string
+	start, hasCode := 0, false
+	flush := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start, hasCode = end+1, false
+	}
+
+	for i := 0; i < len(script); i++ {
+		switch ch := script[i]; {
+		case ch == '\'' || ch == '"':
+			hasCode = true
+			if end := strings.IndexByte(script[i+1:], ch); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(script)
+			}
+		case ch == '-' && i+1 < len(script) && script[i+1] == '-':
+			if end := strings.IndexByte(script[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case ch == ';':
+			flush(i)
+		case ch != ' ' && ch != '\t' && ch != '\n' && ch != '\r':
+			hasCode = true
+		}
+	}
+	if start < len(script) {
+		flush(len(script))
+	}
+	return statements
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"go-springAi/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	dirs, err := fs.Glob(schemas.FS, "*")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, migrationDirs, dirs, "every schema directory must be listed in migrationDirs")
+
+	sqlite, err := LoadMigrations(schemas.FS, DialectSQLite)
+	require.NoError(t, err)
+	require.NotEmpty(t, sqlite)
+	assert.Equal(t, "users/001_create_users_table", sqlite[0].Version)
+
+	for _, dialect := range []Dialect{DialectSQLite, DialectPostgres, DialectMySQL} {
+		t.Run(string(dialect), func(t *testing.T) {
+			migrations, err := LoadMigrations(schemas.FS, dialect)
+			require.NoError(t, err)
+			require.Len(t, migrations, len(sqlite))
+			for i, migration := range migrations {
+				assert.Equal(t, sqlite[i].Version, migration.Version)
+				assert.NotEmpty(t, migration.Down, "%s has no down migration", migration.Version)
+			}
+		})
+	}
+}
+
+func TestMigratorSQLite(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), Options{SQLite: SQLiteOptions{ForeignKeys: true}})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+	total := len(migrator.migrations)
+
+	applied, err := migrator.Up(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users/001_create_users_table", "users/002_add_users_deleted_at"}, applied)
+
+	applied, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, applied, total-2)
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	for _, migration := range status {
+		assert.NotNil(t, migration.AppliedAt, migration.Version)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories/002_create_user_memory_settings_table"}, rolledBack)
+
+	rolledBack, err = migrator.Down(ctx, total)
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, total-1)
+
+	// 回滚后可以重新执行全部迁移
+	applied, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	assert.Len(t, applied, total)
+}
+
+func TestMigratorDownByAppliedTime(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), Options{})
+	require.NoError(t, err)
+	defer db.Close()
+
+	fsys := fstest.MapFS{
+		"users/001_create_users.sql":            {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"users/001_create_users.down.sql":       {Data: []byte("DROP TABLE users;")},
+		"memories/002_create_memories.sql":      {Data: []byte("CREATE TABLE memories (id INTEGER PRIMARY KEY);")},
+		"memories/002_create_memories.down.sql": {Data: []byte("DROP TABLE memories;")},
+	}
+	migrator, err := NewMigrator(db, fsys)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+
+	// 新版本在靠前的目录中加入迁移，执行时间晚于目录中靠后的迁移
+	fsys["api_keys/007_create_api_keys.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE api_keys (id INTEGER PRIMARY KEY);")}
+	fsys["api_keys/007_create_api_keys.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE api_keys;")}
+	migrator, err = NewMigrator(db, fsys)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api_keys/007_create_api_keys"}, applied)
+
+	rolledBack, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api_keys/007_create_api_keys"}, rolledBack)
+
+	// 同一批执行的迁移按迁移顺序倒序回滚
+	rolledBack, err = migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memories/002_create_memories", "users/001_create_users"}, rolledBack)
+}
+
+func TestMigratorBaseline(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), Options{SQLite: SQLiteOptions{ForeignKeys: true}})
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+
+	marked, err := migrator.Baseline(ctx, "users/002_add_users_deleted_at")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users/001_create_users_table", "users/002_add_users_deleted_at"}, marked)
+
+	_, err = migrator.Baseline(ctx, "users/999_missing")
+	assert.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "Multiple statements", script: "CREATE TABLE a (id INT);\nCREATE INDEX i ON a(id);\n", want: []string{"CREATE TABLE a (id INT)", "CREATE INDEX i ON a(id)"}},
+		{name: "Semicolon in string and comment", script: "-- a; b\nINSERT INTO a VALUES ('x;y'); -- trailing; comment\n", want: []string{"-- a; b\nINSERT INTO a VALUES ('x;y')"}},
+		{name: "Missing final semicolon", script: "DROP TABLE a", want: []string{"DROP TABLE a"}},
+		{name: "Comments only", script: "-- nothing here\n", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStatements(tt.script))
+		})
+	}
+}
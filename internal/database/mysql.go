@@ -0,0 +1,347 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlSQLMode 会话SQL模式：ANSI 使 || 表示字符串拼接，NO_BACKSLASH_ESCAPES 使 ESCAPE '\' 与 SQLite 一致
+const mysqlSQLMode = "'ANSI,TRADITIONAL,NO_BACKSLASH_ESCAPES'"
+
+// mysqlReturning 带 RETURNING 的查询在MySQL上先执行语句，再按这里的表和条件读取结果行，
+// 条件中的 ?N 与原查询参数对应
+var mysqlReturning = map[string]string{
+	"CreateUser":                "users WHERE id = LAST_INSERT_ID()",
+	"UpdateUser":                "users WHERE id = ?1",
+	"RestoreUser":               "users WHERE id = ?1",
+	"CreateAPIKey":              "api_keys WHERE id = LAST_INSERT_ID()",
+	"UpdateAPIKey":              "api_keys WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3",
+	"RestoreAPIKey":             "api_keys WHERE user_id = ?1 AND project_id = ?2 AND provider_type = ?3",
+	"CreateAPIKeyVersion":       "api_key_versions WHERE id = LAST_INSERT_ID()",
+	"CreatePersonalAccessToken": "personal_access_tokens WHERE id = LAST_INSERT_ID()",
+	"CreateProject":             "projects WHERE id = LAST_INSERT_ID()",
+	"CreateRefreshToken":        "refresh_tokens WHERE id = LAST_INSERT_ID()",
+	"UpsertUserPreferences":     "user_preferences WHERE user_id = ?1",
+	"CreateWebhookDelivery":     "webhook_deliveries WHERE id = LAST_INSERT_ID()",
+	"CreateWebhookEndpoint":     "webhook_endpoints WHERE id = LAST_INSERT_ID()",
+	"UpdateWebhookEndpoint":     "webhook_endpoints WHERE id = ?1",
+	"CreateScheduledJob":        "scheduled_jobs WHERE id = LAST_INSERT_ID()",
+	"UpdateScheduledJob":        "scheduled_jobs WHERE id = ?1",
+	"UpsertNotificationChannel": "notification_channels WHERE user_id = ?1 AND channel = ?2",
+	"UpsertModelMetadata":       "model_metadata WHERE provider = ?1 AND model = ?2",
+	"CreateFineTuningJob":       "fine_tuning_jobs WHERE id = LAST_INSERT_ID()",
+	"CreateEvalSuite":           "eval_suites WHERE id = LAST_INSERT_ID()",
+	"UpdateEvalSuite":           "eval_suites WHERE id = ?1",
+	"CreateEvalRun":             "eval_runs WHERE id = LAST_INSERT_ID()",
+	"CreateExperiment":          "experiments WHERE id = LAST_INSERT_ID()",
+	"UpdateExperiment":          "experiments WHERE id = ?1",
+	"UpdateExperimentStatus":    "experiments WHERE id = ?1",
+	"UpsertResponseFeedback":    "response_feedback WHERE response_id = ?1 AND user_id = ?2",
+	"CreatePersona":             "personas WHERE id = LAST_INSERT_ID()",
+	"UpdatePersona":             "personas WHERE id = ?1",
+	"UpsertUserMemory":          "user_memories WHERE user_id = ?1 AND memory_key = ?3",
+	"UpsertUserMemorySettings":  "user_memory_settings WHERE user_id = ?1",
+}
+
+var (
+	mysqlQueryName       = regexp.MustCompile(`^-- name: (\w+)`)
+	mysqlReturningClause = regexp.MustCompile(`(?is)\s+RETURNING\s+(.+?);?\s*$`)
+
+	// mysqlRewrites 将 SQLite 语法改写为 MySQL 语法
+	mysqlRewrites = []struct {
+		pattern *regexp.Regexp
+		repl    string
+	}{
+		{regexp.MustCompile(`(?i)ON CONFLICT\s*\(\s*(\w+)[^)]*\)\s*DO NOTHING`), "ON DUPLICATE KEY UPDATE $1 = $1"},
+		{regexp.MustCompile(`(?i)ON CONFLICT\s*\([^)]*\)\s*DO UPDATE SET`), "ON DUPLICATE KEY UPDATE"},
+		{regexp.MustCompile(`(?i)\bexcluded\.(\w+)`), "VALUES($1)"},
+		{regexp.MustCompile(`(?i)\bdatetime\((\?\d*)\)`), "$1"},
+		{regexp.MustCompile(`(?i)\bAS TEXT\)`), "AS CHAR)"},
+		{regexp.MustCompile(`(?i)\bAS BIGINT\)`), "AS SIGNED)"},
+		{regexp.MustCompile(`(?i)\bAS BOOLEAN\)`), "AS SIGNED)"},
+	}
+
+	// mysqlStatements 改写结果缓存，生成代码中的查询数量固定
+	mysqlStatements sync.Map
+)
+
+// openMySQL 打开MySQL/MariaDB连接池，查询在连接层改写
+func openMySQL(dsn string) (*sql.DB, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mysql dsn: %w", err)
+	}
+	cfg.ParseTime = true
+	// 受影响行数按匹配行计算，与 SQLite 一致
+	cfg.ClientFoundRows = true
+	if cfg.Params == nil {
+		cfg.Params = map[string]string{}
+	}
+	cfg.Params["sql_mode"] = mysqlSQLMode
+	if _, ok := cfg.Params["time_zone"]; !ok {
+		cfg.Params["time_zone"] = "'+00:00'"
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(mysqlConnector{connector}), nil
+}
+
+// mysqlStatement 改写后的语句，args 为每个 ? 对应的原参数序号
+type mysqlStatement struct {
+	query     string
+	args      []int
+	fetch     string // RETURNING 对应的查询，为空表示不需要模拟
+	fetchArgs []int
+	err       error
+}
+
+// rewriteMySQL 改写 sqlc 生成的 SQLite 查询
+func rewriteMySQL(query string) *mysqlStatement {
+	if cached, ok := mysqlStatements.Load(query); ok {
+		return cached.(*mysqlStatement)
+	}
+
+	st := &mysqlStatement{}
+	text := query
+	if m := mysqlReturningClause.FindStringSubmatchIndex(text); m != nil {
+		columns := text[m[2]:m[3]]
+		text = text[:m[0]]
+
+		name := ""
+		if n := mysqlQueryName.FindStringSubmatch(query); n != nil {
+			name = n[1]
+		}
+		source, ok := mysqlReturning[name]
+		if !ok {
+			st.err = fmt.Errorf("query %q uses RETURNING, which is not supported on MySQL", name)
+		}
+		st.fetch, st.fetchArgs = bindMySQL("SELECT " + columns + " FROM " + source)
+	}
+	for _, r := range mysqlRewrites {
+		text = r.pattern.ReplaceAllString(text, r.repl)
+	}
+	st.query, st.args = bindMySQL(text)
+
+	mysqlStatements.Store(query, st)
+	return st
+}
+
+// bindMySQL 将 ?N 改写为 ?，并记录每个 ? 对应的原参数序号
+func bindMySQL(query string) (string, []int) {
+	var args []int
+	query = rebind(query, func(index int) string {
+		args = append(args, index)
+		return "?"
+	})
+	return query, args
+}
+
+// bindArgs 按序号重排参数，同一参数可能被多次引用
+func bindArgs(indexes []int, args []driver.NamedValue) ([]driver.NamedValue, error) {
+	bound := make([]driver.NamedValue, len(indexes))
+	for i, index := range indexes {
+		if index < 1 || index > len(args) {
+			return nil, fmt.Errorf("missing query argument %d", index)
+		}
+		bound[i] = driver.NamedValue{Ordinal: i + 1, Value: args[index-1].Value}
+	}
+	return bound, nil
+}
+
+// mysqlConnector 为每个连接包装查询改写
+type mysqlConnector struct {
+	driver.Connector
+}
+
+func (c mysqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlConn{Conn: conn}, nil
+}
+
+// mysqlConn 执行前改写查询，RETURNING 在同一连接上先执行语句再读取结果行
+type mysqlConn struct {
+	driver.Conn
+}
+
+func (c *mysqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	st := rewriteMySQL(query)
+	bound, err := bindArgs(st.args, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.exec(ctx, st.query, bound)
+}
+
+func (c *mysqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	st := rewriteMySQL(query)
+	if st.err != nil {
+		return nil, st.err
+	}
+	bound, err := bindArgs(st.args, args)
+	if err != nil {
+		return nil, err
+	}
+	if st.fetch == "" {
+		return c.query(ctx, st.query, bound)
+	}
+
+	result, err := c.exec(ctx, st.query, bound)
+	if err != nil {
+		return nil, err
+	}
+	// 没有匹配行时与 RETURNING 一样返回空结果
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return emptyRows{}, err
+	}
+	fetchArgs, err := bindArgs(st.fetchArgs, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.query(ctx, st.fetch, fetchArgs)
+}
+
+func (c *mysqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	st := rewriteMySQL(query)
+	if st.err != nil {
+		return nil, st.err
+	}
+	stmt, err := c.prepare(ctx, st.query)
+	if err != nil {
+		return nil, err
+	}
+	return &mysqlStmt{Stmt: stmt, args: st.args}, nil
+}
+
+func (c *mysqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *mysqlConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *mysqlConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *mysqlConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *mysqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *mysqlConn) prepare(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+// exec 未开启 interpolateParams 时驱动返回 ErrSkip，改用预处理语句执行
+func (c *mysqlConn) exec(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		return result, err
+	}
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	return stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+}
+
+// query 同 exec，预处理语句在结果集关闭时关闭
+func (c *mysqlConn) query(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		return rows, err
+	}
+	stmt, err := c.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	rows, err = stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	return &stmtRows{Rows: rows, stmt: stmt}, nil
+}
+
+// mysqlStmt 预处理语句，执行时按序号重排参数
+type mysqlStmt struct {
+	driver.Stmt
+	args []int
+}
+
+func (s *mysqlStmt) NumInput() int {
+	return -1
+}
+
+func (s *mysqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	bound, err := bindArgs(s.args, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Stmt.(driver.StmtExecContext).ExecContext(ctx, bound)
+}
+
+func (s *mysqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	bound, err := bindArgs(s.args, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, bound)
+}
+
+// stmtRows 关闭结果集时一并关闭预处理语句
+type stmtRows struct {
+	driver.Rows
+	stmt driver.Stmt
+}
+
+func (r *stmtRows) Close() error {
+	err := r.Rows.Close()
+	if stmtErr := r.stmt.Close(); err == nil {
+		err = stmtErr
+	}
+	return err
+}
+
+// emptyRows 不含任何行的结果集
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
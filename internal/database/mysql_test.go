@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteMySQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantQuery string
+		wantArgs  []int
+		wantFetch string
+	}{
+		{
+			name:      "Reused numbered placeholders",
+			query:     "-- name: CountFilteredUsers :one\nSELECT COUNT(*) FROM users WHERE (CAST(?1 AS TEXT) IS NULL OR username LIKE '%' || CAST(?1 AS TEXT) || '%' ESCAPE '\\') AND (?2 IS NULL OR created_at >= datetime(?2))",
+			wantQuery: "-- name: CountFilteredUsers :one\nSELECT COUNT(*) FROM users WHERE (CAST(? AS CHAR) IS NULL OR username LIKE '%' || CAST(? AS CHAR) || '%' ESCAPE '\\') AND (? IS NULL OR created_at >= ?)",
+			wantArgs:  []int{1, 1, 2, 2},
+		},
+		{
+			name:      "Boolean cast",
+			query:     "-- name: ListAPIKeysByUser :many\nSELECT id FROM api_keys WHERE user_id = ?1 AND (deleted_at IS NULL OR CAST(?2 AS BOOLEAN))",
+			wantQuery: "-- name: ListAPIKeysByUser :many\nSELECT id FROM api_keys WHERE user_id = ? AND (deleted_at IS NULL OR CAST(? AS SIGNED))",
+			wantArgs:  []int{1, 2},
+		},
+		{
+			name:      "Upsert",
+			query:     "-- name: RecordAIUsage :exec\nINSERT INTO ai_usage (user_id, usage_date, request_count, token_count) VALUES (?1, ?2, 1, ?3)\nON CONFLICT (user_id, usage_date) DO UPDATE SET\n    token_count = ai_usage.token_count + excluded.token_count",
+			wantQuery: "-- name: RecordAIUsage :exec\nINSERT INTO ai_usage (user_id, usage_date, request_count, token_count) VALUES (?, ?, 1, ?)\nON DUPLICATE KEY UPDATE\n    token_count = ai_usage.token_count + VALUES(token_count)",
+			wantArgs:  []int{1, 2, 3},
+		},
+		{
+			name:      "Insert ignore",
+			query:     "-- name: GrantUserPermission :exec\nINSERT INTO user_permissions (user_id, permission) VALUES (?1, ?2)\nON CONFLICT (user_id, permission) DO NOTHING",
+			wantQuery: "-- name: GrantUserPermission :exec\nINSERT INTO user_permissions (user_id, permission) VALUES (?, ?)\nON DUPLICATE KEY UPDATE user_id = user_id",
+			wantArgs:  []int{1, 2},
+		},
+		{
+			name:      "Update returning",
+			query:     "-- name: RestoreUser :one\nUPDATE users SET deleted_at = NULL WHERE id = ?1 AND deleted_at IS NOT NULL\nRETURNING id, username\n",
+			wantQuery: "-- name: RestoreUser :one\nUPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL",
+			wantArgs:  []int{1},
+			wantFetch: "SELECT id, username FROM users WHERE id = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := rewriteMySQL(tt.query)
+			require.NoError(t, st.err)
+			assert.Equal(t, tt.wantQuery, st.query)
+			assert.Equal(t, tt.wantArgs, st.args)
+			assert.Equal(t, tt.wantFetch, st.fetch)
+		})
+	}
+
+	assert.Error(t, rewriteMySQL("-- name: Unknown :one\nDELETE FROM users RETURNING id").err)
+}
+
+func TestMySQLReturning(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+		wantSQL  []string
+	}{
+		{name: "Row updated", affected: 1, wantSQL: []string{"-- name: RestoreUser :one\nUPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", "SELECT id FROM users WHERE id = ?"}},
+		{name: "No matching row", affected: 0, wantErr: sql.ErrNoRows, wantSQL: []string{"-- name: RestoreUser :one\nUPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{affected: tt.affected}
+			db := sql.OpenDB(mysqlConnector{fakeConnector{conn}})
+			defer db.Close()
+
+			var id int64
+			err := db.QueryRowContext(context.Background(),
+				"-- name: RestoreUser :one\nUPDATE users SET deleted_at = NULL WHERE id = ?1 AND deleted_at IS NOT NULL RETURNING id", int64(7)).Scan(&id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(7), id)
+			}
+			assert.Equal(t, tt.wantSQL, conn.queries)
+		})
+	}
+}
+
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+// fakeConn 记录执行的语句，查询返回第一个参数
+type fakeConn struct {
+	affected int64
+	queries  []string
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.queries = append(c.queries, query)
+	return driver.RowsAffected(c.affected), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	return &fakeRows{value: args[0].Value}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
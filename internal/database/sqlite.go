@@ -0,0 +1,71 @@
+package database
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLiteOptions 每个连接打开时设置的 PRAGMA，空值使用驱动默认值
+type SQLiteOptions struct {
+	JournalMode string // WAL 允许读写并发，减少 SQLITE_BUSY
+	BusyTimeout time.Duration
+	ForeignKeys bool
+	Synchronous string
+}
+
+// sqlitePragmas go-sqlite3 连接参数及其别名
+var sqlitePragmas = []struct {
+	key     string
+	aliases []string
+	value   func(SQLiteOptions) string
+}{
+	{"_journal_mode", []string{"_journal"}, func(o SQLiteOptions) string { return o.JournalMode }},
+	{"_busy_timeout", []string{"_timeout"}, func(o SQLiteOptions) string {
+		if o.BusyTimeout <= 0 {
+			return ""
+		}
+		return strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10)
+	}},
+	{"_foreign_keys", []string{"_fk"}, func(o SQLiteOptions) string {
+		if !o.ForeignKeys {
+			return ""
+		}
+		return "1"
+	}},
+	{"_synchronous", []string{"_sync"}, func(o SQLiteOptions) string { return o.Synchronous }},
+}
+
+// sqliteDSN 将 PRAGMA 追加为连接参数，使连接池中的每个连接都生效，dsn 中已有的参数优先
+func sqliteDSN(dsn string, opts SQLiteOptions) string {
+	query := ""
+	if i := strings.IndexByte(dsn, '?'); i >= 0 {
+		query = dsn[i+1:]
+	}
+	existing, _ := url.ParseQuery(query)
+
+	params := url.Values{}
+	for _, pragma := range sqlitePragmas {
+		value := pragma.value(opts)
+		if value == "" || existing.Has(pragma.key) {
+			continue
+		}
+		skip := false
+		for _, alias := range pragma.aliases {
+			skip = skip || existing.Has(alias)
+		}
+		if !skip {
+			params.Set(pragma.key, value)
+		}
+	}
+	if len(params) == 0 {
+		return dsn
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + params.Encode()
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDSN(t *testing.T) {
+	opts := SQLiteOptions{JournalMode: "WAL", BusyTimeout: 5 * time.Second, ForeignKeys: true, Synchronous: "NORMAL"}
+	tests := []struct {
+		name string
+		dsn  string
+		opts SQLiteOptions
+		want string
+	}{
+		{name: "Plain path", dsn: "./data/app.db", opts: opts, want: "./data/app.db?_busy_timeout=5000&_foreign_keys=1&_journal_mode=WAL&_synchronous=NORMAL"},
+		{name: "Existing parameters win", dsn: "file:app.db?_fk=0&_journal_mode=DELETE", opts: opts, want: "file:app.db?_fk=0&_journal_mode=DELETE&_busy_timeout=5000&_synchronous=NORMAL"},
+		{name: "Zero options", dsn: "./data/app.db", want: "./data/app.db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sqliteDSN(tt.dsn, tt.opts))
+		})
+	}
+}
+
+func TestSQLitePragmas(t *testing.T) {
+	db, err := NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), Options{
+		MaxOpenConns: 4,
+		SQLite:       SQLiteOptions{JournalMode: "WAL", BusyTimeout: 3 * time.Second, ForeignKeys: true},
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var journalMode string
+	var busyTimeout, foreignKeys int
+	require.NoError(t, db.conn.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	require.NoError(t, db.conn.QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout))
+	require.NoError(t, db.conn.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	assert.Equal(t, "wal", journalMode)
+	assert.Equal(t, 3000, busyTimeout)
+	assert.Equal(t, 1, foreignKeys)
+	assert.Equal(t, 4, db.conn.Stats().MaxOpenConnections)
+}
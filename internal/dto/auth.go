@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"time"
+)
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshTokenRequest 刷新令牌请求（刷新与登出共用）
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse 令牌响应
+type TokenResponse struct {
+	AccessToken      string           `json:"access_token"`
+	RefreshToken     string           `json:"refresh_token"`
+	TokenType        string           `json:"token_type"`
+	ExpiresIn        int64            `json:"expires_in"` // 访问令牌有效期（秒）
+	RefreshExpiresAt time.Time        `json:"refresh_expires_at"`
+	User             *UserResponse    `json:"user,omitempty"`
+	Preferences      *UserPreferences `json:"preferences,omitempty"` // 仅登录时返回
+}
+
+// APITokenPrefix 个人访问令牌前缀，用于和JWT区分
+const APITokenPrefix = "gsa_"
+
+// 个人访问令牌权限范围
+const (
+	APITokenScopeMCP   = "mcp"
+	APITokenScopeStock = "stock"
+	APITokenScopeAI    = "ai"
+)
+
+// APITokenScopes 所有可授予的权限范围
+var APITokenScopes = []string{APITokenScopeMCP, APITokenScopeStock, APITokenScopeAI}
+
+// CreateAPITokenRequest 创建个人访问令牌请求
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // 为空表示永不过期
+}
+
+// APITokenResponse 个人访问令牌信息（不含明文）
+type APITokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPITokenResponse 创建个人访问令牌响应，明文令牌仅返回这一次
+type CreateAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+// APITokenPrincipal 个人访问令牌认证后的身份信息
+type APITokenPrincipal struct {
+	TokenID  int64    `json:"token_id"`
+	UserID   int64    `json:"user_id"`
+	Username string   `json:"username"`
+	Scopes   []string `json:"scopes"`
+}
+
+// ImpersonateRequest 管理员模拟登录请求
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ImpersonationResponse 模拟登录响应，不包含刷新令牌
+type ImpersonationResponse struct {
+	AccessToken string        `json:"access_token"`
+	TokenType   string        `json:"token_type"`
+	ExpiresIn   int64         `json:"expires_in"` // 有效期（秒）
+	ExpiresAt   time.Time     `json:"expires_at"`
+	Banner      string        `json:"banner"`
+	User        *UserResponse `json:"user"`
+}
+
+// 用户权限，管理员身份之外还需单独授予
+const (
+	PermissionRevealAPIKeys = "api_keys:reveal" // 查看明文API密钥
+)
+
+// Permissions 所有可授予的用户权限
+var Permissions = []string{PermissionRevealAPIKeys}
+
+// GrantPermissionRequest 授予用户权限请求
+type GrantPermissionRequest struct {
+	Permission string `json:"permission" binding:"required"`
+}
+
+// UserPermissionResponse 用户权限信息
+type UserPermissionResponse struct {
+	Permission string    `json:"permission"`
+	GrantedBy  *int64    `json:"granted_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReauthRequest 重新认证请求
+type ReauthRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// ReauthResponse 重新认证响应，新访问令牌在 ReauthExpiresAt 之前可用于敏感操作
+type ReauthResponse struct {
+	AccessToken     string    `json:"access_token"`
+	TokenType       string    `json:"token_type"`
+	ExpiresIn       int64     `json:"expires_in"` // 访问令牌有效期（秒）
+	ReauthExpiresAt time.Time `json:"reauth_expires_at"`
+}
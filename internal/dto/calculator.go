@@ -0,0 +1,8 @@
+package dto
+
+// CalculatorResult 计算器工具的结构化结果
+type CalculatorResult struct {
+	Expression string `json:"expression"`
+	Result     string `json:"result"`   // 十进制字符串，避免 JSON 数字丢失精度
+	Decimals   int    `json:"decimals"` // 结果保留的小数位数
+}
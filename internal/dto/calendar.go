@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// CalendarEvent 日历事件，全天事件的 End 为结束日期的次日零点
+type CalendarEvent struct {
+	ID              string    `json:"id"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	AllDay          bool      `json:"all_day"`
+	ReminderMinutes int       `json:"reminder_minutes,omitempty"` // 开始前多少分钟提醒，0 表示没有单独设置提醒
+	URL             string    `json:"url,omitempty"`
+}
+
+// CalendarListResult calendar 工具 list 操作的结构化结果
+type CalendarListResult struct {
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Events    []CalendarEvent `json:"events"`
+	Truncated bool            `json:"truncated"`
+}
+
+// CalendarEarningsReminder calendar 工具 earnings_reminder 操作的结构化结果
+type CalendarEarningsReminder struct {
+	Symbol       string        `json:"symbol"`
+	EarningsDate string        `json:"earnings_date"` // YYYY-MM-DD
+	Estimate     bool          `json:"estimate"`      // 财报日期是否为预估
+	Created      bool          `json:"created"`       // 是否新建了事件，否则日历中已有同名事件
+	Event        CalendarEvent `json:"event"`
+}
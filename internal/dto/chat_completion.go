@@ -0,0 +1,200 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go-springAi/internal/openai"
+)
+
+// OpenAI 兼容接口的对象类型
+const (
+	ChatCompletionObject      = "chat.completion"
+	ChatCompletionChunkObject = "chat.completion.chunk"
+	ModelListObject           = "list"
+	ModelObject               = "model"
+)
+
+// 工具选择模式
+const (
+	ToolChoiceNone     = "none"
+	ToolChoiceAuto     = "auto"
+	ToolChoiceRequired = "required"
+)
+
+// ChatCompletionRequest OpenAI 兼容的聊天补全请求
+type ChatCompletionRequest struct {
+	Model               string                  `json:"model" binding:"required"`
+	Messages            []ChatCompletionMessage `json:"messages" binding:"required,min=1,dive"`
+	MaxTokens           *int                    `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int                    `json:"max_completion_tokens,omitempty"` // 新版SDK使用，优先于 max_tokens
+	Temperature         *float32                `json:"temperature,omitempty"`
+	Stream              bool                    `json:"stream,omitempty"`
+	Tools               []ChatCompletionTool    `json:"tools,omitempty" binding:"omitempty,dive"`
+	ToolChoice          *ToolChoice             `json:"tool_choice,omitempty"`
+	User                string                  `json:"user,omitempty"`
+}
+
+// ChatCompletionMessage 请求中的消息，assistant 消息可带 tool_calls，tool 消息通过 tool_call_id 对应调用
+type ChatCompletionMessage struct {
+	Role       string                   `json:"role" binding:"required,oneof=system developer user assistant tool"`
+	Content    MessageContent           `json:"content"`
+	Name       string                   `json:"name,omitempty"`
+	ToolCalls  []ChatCompletionToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string                   `json:"tool_call_id,omitempty"`
+}
+
+// MessageContent 消息内容，接受字符串、null 或文本分段数组，分段按顺序拼接
+type MessageContent string
+
+// UnmarshalJSON 解析字符串或 [{"type": "text", "text": "..."}] 形式的内容
+func (m *MessageContent) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*m = ""
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = MessageContent(text)
+		return nil
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("content must be a string or an array of content parts")
+	}
+	var sb strings.Builder
+	for _, part := range parts {
+		if part.Type != "text" {
+			return fmt.Errorf("unsupported content part type %q", part.Type)
+		}
+		sb.WriteString(part.Text)
+	}
+	*m = MessageContent(sb.String())
+	return nil
+}
+
+// ChatCompletionTool 客户端定义的函数工具
+type ChatCompletionTool struct {
+	Type     string                 `json:"type" binding:"required,eq=function"`
+	Function ChatCompletionFunction `json:"function"`
+}
+
+// ChatCompletionFunction 函数定义，Parameters 为 JSON Schema
+type ChatCompletionFunction struct {
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolChoice 工具选择，可以是 none、auto、required 或 {"type": "function", "function": {"name": "..."}}
+type ToolChoice struct {
+	Mode     string // none、auto 或 required
+	Function string // 指定的函数，非空时 Mode 为 required
+}
+
+// UnmarshalJSON 解析字符串或指定函数的对象
+func (t *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		switch mode {
+		case ToolChoiceNone, ToolChoiceAuto, ToolChoiceRequired:
+			t.Mode = mode
+			return nil
+		}
+		return fmt.Errorf("unsupported tool_choice %q", mode)
+	}
+
+	var named struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil || named.Type != "function" || named.Function.Name == "" {
+		return fmt.Errorf("tool_choice must be none, auto, required or a function")
+	}
+	t.Mode = ToolChoiceRequired
+	t.Function = named.Function.Name
+	return nil
+}
+
+// ChatCompletionToolCall 模型发起的函数调用，Arguments 为 JSON 字符串；Index 只在流式分块中出现
+type ChatCompletionToolCall struct {
+	Index    *int                       `json:"index,omitempty"`
+	ID       string                     `json:"id"`
+	Type     string                     `json:"type"`
+	Function ChatCompletionFunctionCall `json:"function"`
+}
+
+// ChatCompletionFunctionCall 函数调用的名称和参数
+type ChatCompletionFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletionResponse OpenAI 兼容的聊天补全响应
+type ChatCompletionResponse struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []ChatCompletionChoice `json:"choices"`
+	Usage   openai.Usage           `json:"usage"`
+}
+
+// ChatCompletionChoice 响应中的回复，返回 tool_calls 时 content 为 null
+type ChatCompletionChoice struct {
+	Index        int                 `json:"index"`
+	Message      ChatCompletionReply `json:"message"`
+	FinishReason string              `json:"finish_reason"`
+}
+
+// ChatCompletionReply 模型回复
+type ChatCompletionReply struct {
+	Role      string                   `json:"role"`
+	Content   *string                  `json:"content"`
+	ToolCalls []ChatCompletionToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChatCompletionChunk 流式响应分块
+type ChatCompletionChunk struct {
+	ID      string                      `json:"id"`
+	Object  string                      `json:"object"`
+	Created int64                       `json:"created"`
+	Model   string                      `json:"model"`
+	Choices []ChatCompletionChunkChoice `json:"choices"`
+}
+
+// ChatCompletionChunkChoice 分块中的增量回复，FinishReason 只在最后一个分块中出现
+type ChatCompletionChunkChoice struct {
+	Index        int                 `json:"index"`
+	Delta        ChatCompletionDelta `json:"delta"`
+	FinishReason *string             `json:"finish_reason"`
+}
+
+// ChatCompletionDelta 增量回复，角色只在第一个分块中出现
+type ChatCompletionDelta struct {
+	Role      string                   `json:"role,omitempty"`
+	Content   string                   `json:"content,omitempty"`
+	ToolCalls []ChatCompletionToolCall `json:"tool_calls,omitempty"`
+}
+
+// ModelList OpenAI 兼容的模型列表
+type ModelList struct {
+	Object string      `json:"object"`
+	Data   []ModelInfo `json:"data"`
+}
+
+// ModelInfo 模型信息，OwnedBy 为提供商类型
+type ModelInfo struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+// DateTimeResult 日期时间工具的结构化结果，字段按 action 填充
+type DateTimeResult struct {
+	Action   string         `json:"action"`             // now, convert, market, add, diff
+	Time     string         `json:"time,omitempty"`     // RFC3339，now/convert/add 的结果时间
+	Timezone string         `json:"timezone,omitempty"` // 结果时间所在时区
+	Weekday  string         `json:"weekday,omitempty"`
+	Markets  []MarketStatus `json:"markets,omitempty"`
+	Diff     *DateDiff      `json:"diff,omitempty"`
+}
+
+// MarketStatus 交易所在某一时刻的开闭市状态
+type MarketStatus struct {
+	Exchange  string `json:"exchange"`
+	Name      string `json:"name"`
+	Timezone  string `json:"timezone"`
+	LocalTime string `json:"local_time"` // RFC3339，交易所当地时间
+	Open      bool   `json:"open"`
+	// Reason 休市原因: weekend, holiday, before_open, lunch_break, after_close
+	Reason          string `json:"reason,omitempty"`
+	NextOpen        string `json:"next_open,omitempty"`
+	NextClose       string `json:"next_close,omitempty"`
+	HolidaysChecked bool   `json:"holidays_checked"` // 为 false 时只按周末判断休市
+}
+
+// DateDiff 两个时间之间的间隔
+type DateDiff struct {
+	Start        string  `json:"start"`
+	End          string  `json:"end"`
+	Seconds      int64   `json:"seconds"`
+	Hours        float64 `json:"hours"`
+	CalendarDays int     `json:"calendar_days"` // 日期之差，不足一天的部分不计
+	BusinessDays int     `json:"business_days"` // [start, end) 之间的工作日或交易日数量
+}
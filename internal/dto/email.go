@@ -0,0 +1,14 @@
+package dto
+
+// EmailFailure 发送失败的收件人
+type EmailFailure struct {
+	Recipient string `json:"recipient"`
+	Error     string `json:"error"`
+}
+
+// EmailSendResult send_email 工具的结构化结果
+type EmailSendResult struct {
+	Subject string         `json:"subject"`
+	Sent    []string       `json:"sent"`
+	Failed  []EmailFailure `json:"failed"`
+}
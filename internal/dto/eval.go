@@ -0,0 +1,134 @@
+package dto
+
+import "time"
+
+// EvalCriteria 评测用例的预期标准，启发式检查和 LLM 评审可以同时使用
+type EvalCriteria struct {
+	Contains     []string `json:"contains,omitempty"`     // 回复必须包含的文本，不区分大小写
+	NotContains  []string `json:"not_contains,omitempty"` // 回复不能包含的文本，不区分大小写
+	Regex        string   `json:"regex,omitempty"`        // 回复必须匹配的正则表达式
+	MaxLatencyMs int64    `json:"max_latency_ms,omitempty" binding:"omitempty,min=1"`
+	Rubric       string   `json:"rubric,omitempty" binding:"omitempty,max=4000"` // LLM 评审使用的评分标准，运行时指定了评审模型才生效
+}
+
+// EvalCase 评测用例
+type EvalCase struct {
+	Name     string       `json:"name" binding:"required,max=128"`
+	System   string       `json:"system,omitempty" binding:"omitempty,max=8000"`
+	Prompt   string       `json:"prompt" binding:"required,max=16000"`
+	Criteria EvalCriteria `json:"criteria"`
+}
+
+// CreateEvalSuiteRequest 创建评测套件请求
+type CreateEvalSuiteRequest struct {
+	Name        string     `json:"name" binding:"required,max=128"`
+	Description string     `json:"description" binding:"max=1000"`
+	Cases       []EvalCase `json:"cases" binding:"required,min=1,max=200,dive"`
+}
+
+// UpdateEvalSuiteRequest 更新评测套件请求，未提供的字段保持不变
+type UpdateEvalSuiteRequest struct {
+	Name        *string    `json:"name" binding:"omitempty,min=1,max=128"`
+	Description *string    `json:"description" binding:"omitempty,max=1000"`
+	Cases       []EvalCase `json:"cases" binding:"omitempty,min=1,max=200,dive"`
+}
+
+// EvalSuiteResponse 评测套件信息
+type EvalSuiteResponse struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Cases       []EvalCase `json:"cases"`
+	CreatedBy   int64      `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// EvalSuiteListResponse 评测套件列表
+type EvalSuiteListResponse struct {
+	Suites []EvalSuiteResponse `json:"suites"`
+}
+
+// EvalTarget 被评测或用于评审的模型，未指定提供商时按模型名称选择
+type EvalTarget struct {
+	Provider string `json:"provider,omitempty" binding:"omitempty,max=64"`
+	Model    string `json:"model" binding:"required,max=128"`
+}
+
+// CreateEvalRunRequest 运行评测套件请求
+type CreateEvalRunRequest struct {
+	Targets     []EvalTarget `json:"targets" binding:"required,min=1,max=10,dive"`
+	Judge       *EvalTarget  `json:"judge,omitempty"` // 评审模型，为空时只使用启发式评分
+	MaxTokens   *int         `json:"max_tokens,omitempty" binding:"omitempty,min=1,max=32000"`
+	Temperature *float32     `json:"temperature,omitempty" binding:"omitempty,min=0,max=2"`
+}
+
+// EvalCheck 一项启发式检查的结果
+type EvalCheck struct {
+	Name   string `json:"name"` // contains / not_contains / regex / max_latency_ms
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// EvalCaseResult 一个用例在一个模型上的评测结果
+type EvalCaseResult struct {
+	Case             string      `json:"case"`
+	Provider         string      `json:"provider"`
+	Model            string      `json:"model"`
+	Output           string      `json:"output,omitempty"`
+	LatencyMs        int64       `json:"latency_ms"`
+	PromptTokens     int         `json:"prompt_tokens"`
+	CompletionTokens int         `json:"completion_tokens"`
+	Checks           []EvalCheck `json:"checks,omitempty"`
+	HeuristicScore   *float64    `json:"heuristic_score,omitempty"` // 通过的检查项比例，没有检查项时为空
+	JudgeScore       *float64    `json:"judge_score,omitempty"`     // 评审模型给出的 0~1 分，未评审时为空
+	JudgeReason      string      `json:"judge_reason,omitempty"`
+	Score            float64     `json:"score"` // 启发式和评审分数的平均值
+	Passed           bool        `json:"passed"`
+	Error            string      `json:"error,omitempty"`
+}
+
+// EvalTargetSummary 一个模型在整个套件上的汇总
+type EvalTargetSummary struct {
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Cases        int     `json:"cases"`
+	Passed       int     `json:"passed"`
+	Errors       int     `json:"errors"`
+	PassRate     float64 `json:"pass_rate"`
+	AvgScore     float64 `json:"avg_score"`
+	AvgLatencyMs int64   `json:"avg_latency_ms"`
+}
+
+// EvalRunResponse 评测运行记录，status 为 running / completed / failed
+type EvalRunResponse struct {
+	ID         int64               `json:"id"`
+	SuiteID    int64               `json:"suite_id"`
+	Status     string              `json:"status"`
+	Targets    []EvalTarget        `json:"targets"`
+	Judge      *EvalTarget         `json:"judge,omitempty"`
+	Summary    []EvalTargetSummary `json:"summary,omitempty"`
+	Results    []EvalCaseResult    `json:"results,omitempty"` // 列表接口不返回
+	Error      string              `json:"error,omitempty"`
+	CreatedBy  int64               `json:"created_by"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// EvalRunListResponse 评测运行记录列表
+type EvalRunListResponse struct {
+	Runs []EvalRunResponse `json:"runs"`
+}
+
+// EvalComparisonEntry 对比中的一个运行和模型组合
+type EvalComparisonEntry struct {
+	RunID int64 `json:"run_id"`
+	EvalTargetSummary
+	CaseScores map[string]float64 `json:"case_scores"` // 用例名称到分数
+}
+
+// EvalComparisonResponse 多次运行的结果对比，entries 按平均分从高到低排列
+type EvalComparisonResponse struct {
+	Entries []EvalComparisonEntry `json:"entries"`
+	Best    *EvalComparisonEntry  `json:"best,omitempty"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import "time"
+
+// ExperimentVariant A/B 实验的一个变体，未设置的字段沿用请求本身的值
+type ExperimentVariant struct {
+	Provider     string   `json:"provider,omitempty" binding:"omitempty,max=64"`
+	Model        string   `json:"model,omitempty" binding:"omitempty,max=128"`
+	SystemPrompt string   `json:"system_prompt,omitempty" binding:"omitempty,max=16000"` // 替换请求中的系统消息，没有时添加到开头
+	Temperature  *float32 `json:"temperature,omitempty" binding:"omitempty,min=0,max=2"`
+}
+
+// CreateExperimentRequest 创建 A/B 实验请求
+type CreateExperimentRequest struct {
+	Name         string            `json:"name" binding:"required,max=128"`
+	Description  string            `json:"description" binding:"max=1000"`
+	Persona      string            `json:"persona" binding:"max=64"`                       // 参与实验的助手角色，为空时为 default
+	TrafficSplit *int              `json:"traffic_split" binding:"omitempty,min=1,max=99"` // 分配到变体 B 的流量百分比，默认 50
+	VariantA     ExperimentVariant `json:"variant_a"`
+	VariantB     ExperimentVariant `json:"variant_b"`
+}
+
+// UpdateExperimentRequest 更新 A/B 实验请求，未提供的字段保持不变
+type UpdateExperimentRequest struct {
+	Name         *string            `json:"name" binding:"omitempty,min=1,max=128"`
+	Description  *string            `json:"description" binding:"omitempty,max=1000"`
+	Persona      *string            `json:"persona" binding:"omitempty,max=64"`
+	TrafficSplit *int               `json:"traffic_split" binding:"omitempty,min=1,max=99"`
+	VariantA     *ExperimentVariant `json:"variant_a"`
+	VariantB     *ExperimentVariant `json:"variant_b"`
+}
+
+// ExperimentResponse A/B 实验信息，status 为 draft / running / stopped
+type ExperimentResponse struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Persona      string            `json:"persona"`
+	Status       string            `json:"status"`
+	TrafficSplit int               `json:"traffic_split"`
+	VariantA     ExperimentVariant `json:"variant_a"`
+	VariantB     ExperimentVariant `json:"variant_b"`
+	CreatedBy    int64             `json:"created_by"`
+	StartedAt    *time.Time        `json:"started_at,omitempty"`
+	StoppedAt    *time.Time        `json:"stopped_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// ExperimentListResponse A/B 实验列表
+type ExperimentListResponse struct {
+	Experiments []ExperimentResponse `json:"experiments"`
+}
+
+// ExperimentVariantStats 一个变体的结果汇总，比率在没有样本时为空
+type ExperimentVariantStats struct {
+	Variant          string   `json:"variant"` // a / b
+	Requests         int64    `json:"requests"`
+	Errors           int64    `json:"errors"`
+	SuccessRate      *float64 `json:"success_rate,omitempty"`
+	AvgLatencyMs     int64    `json:"avg_latency_ms"` // 只统计成功的请求
+	ToolCalls        int64    `json:"tool_calls"`
+	ToolFailures     int64    `json:"tool_failures"`
+	ToolSuccessRate  *float64 `json:"tool_success_rate,omitempty"`
+	FeedbackUp       int64    `json:"feedback_up"`
+	FeedbackDown     int64    `json:"feedback_down"`
+	SatisfactionRate *float64 `json:"satisfaction_rate,omitempty"` // 好评占所有评价的比例
+}
+
+// ExperimentReport A/B 实验报告，winner 为空时 reason 说明原因
+type ExperimentReport struct {
+	Experiment ExperimentResponse       `json:"experiment"`
+	Variants   []ExperimentVariantStats `json:"variants"`
+	Winner     string                   `json:"winner,omitempty"` // a / b
+	Metric     string                   `json:"metric,omitempty"` // 决定胜出的指标：feedback / tool_success_rate / latency
+	Reason     string                   `json:"reason"`
+}
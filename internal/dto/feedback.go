@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"go-springAi/internal/openai"
+)
+
+// 回复评价
+const (
+	FeedbackRatingUp   = "up"
+	FeedbackRatingDown = "down"
+)
+
+// ResponseFeedbackRequest 评价助手回复请求
+type ResponseFeedbackRequest struct {
+	Rating   string `json:"rating" binding:"required,oneof=up down"`
+	Category string `json:"category" binding:"omitempty,oneof=inaccurate unhelpful tool_failure unsafe formatting other"`
+	Comment  string `json:"comment" binding:"max=2000"`
+}
+
+// ResponseFeedbackResponse 回复评价信息，conversation 为请求消息加上助手的回复
+type ResponseFeedbackResponse struct {
+	ID           int64            `json:"id"`
+	ResponseID   string           `json:"response_id"`
+	UserID       int64            `json:"user_id"`
+	Rating       string           `json:"rating"`
+	Category     string           `json:"category,omitempty"`
+	Comment      string           `json:"comment,omitempty"`
+	Provider     string           `json:"provider"`
+	Model        string           `json:"model"`
+	Persona      string           `json:"persona"`
+	Conversation []openai.Message `json:"conversation,omitempty"` // 提交评价时不返回
+	InExperiment bool             `json:"in_experiment"`          // 回复是否参与了 A/B 实验，评价已计入实验结果
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// ResponseFeedbackListResponse 回复评价列表
+type ResponseFeedbackListResponse struct {
+	Feedback []ResponseFeedbackResponse `json:"feedback"`
+}
+
+// ExportFeedbackRequest 把差评导出为评测用例请求
+type ExportFeedbackRequest struct {
+	SuiteID  int64  `json:"suite_id" binding:"required,min=1"`
+	Category string `json:"category" binding:"omitempty,oneof=inaccurate unhelpful tool_failure unsafe formatting other"`
+	Limit    int    `json:"limit" binding:"omitempty,min=1,max=200"` // 最多检查最近多少条差评，默认 50
+}
+
+// ExportFeedbackResponse 导出结果，已在套件中的评价不会重复添加
+type ExportFeedbackResponse struct {
+	SuiteID int64    `json:"suite_id"`
+	Added   []string `json:"added"` // 新增的用例名称
+}
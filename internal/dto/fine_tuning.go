@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// CreateFineTuningJobRequest 创建 OpenAI 微调任务请求
+type CreateFineTuningJobRequest struct {
+	Model          string `json:"model" binding:"required,max=128"`         // 基础模型，如 gpt-4o-mini-2024-07-18
+	TrainingFile   string `json:"training_file" binding:"required,max=128"` // 上传训练文件返回的文件ID
+	ValidationFile string `json:"validation_file" binding:"omitempty,max=128"`
+	Suffix         string `json:"suffix" binding:"omitempty,max=64"` // 微调后模型名称中的自定义部分
+}
+
+// FineTuningFileResponse 已上传的训练文件
+type FineTuningFileResponse struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Bytes     int64     `json:"bytes"`
+	Purpose   string    `json:"purpose"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FineTuningJobResponse 微调任务信息
+type FineTuningJobResponse struct {
+	ID              string     `json:"id"` // OpenAI 任务ID
+	UserID          int64      `json:"user_id"`
+	Model           string     `json:"model"`
+	TrainingFile    string     `json:"training_file"`
+	ValidationFile  string     `json:"validation_file,omitempty"`
+	Suffix          string     `json:"suffix,omitempty"`
+	Status          string     `json:"status"` // validating_files / queued / running / succeeded / failed / cancelled
+	FineTunedModel  string     `json:"fine_tuned_model,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	ModelRegistered bool       `json:"model_registered"` // 微调后的模型已以禁用状态添加到模型配置
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// FineTuningJobListResponse 微调任务列表
+type FineTuningJobListResponse struct {
+	Jobs []FineTuningJobResponse `json:"jobs"`
+}
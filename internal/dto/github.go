@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// GitHubIssue GitHub issue 摘要
+type GitHubIssue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Author    string    `json:"author"`
+	Labels    []string  `json:"labels"`
+	Assignees []string  `json:"assignees"`
+	Comments  int       `json:"comments"`
+	URL       string    `json:"url"`
+	Body      string    `json:"body,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GitHubIssueList github 工具 list_issues 操作的结构化结果
+type GitHubIssueList struct {
+	Repo   string        `json:"repo"`
+	State  string        `json:"state"`
+	Issues []GitHubIssue `json:"issues"`
+}
+
+// GitHubCheck PR 的一项 CI 检查（check run 或 commit status）
+type GitHubCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`               // queued / in_progress / completed
+	Conclusion string `json:"conclusion,omitempty"` // success / failure / neutral / cancelled / skipped / timed_out / action_required
+	URL        string `json:"url,omitempty"`
+}
+
+// GitHubReview 评审者的最新评审结论
+type GitHubReview struct {
+	Reviewer string `json:"reviewer"`
+	State    string `json:"state"` // APPROVED / CHANGES_REQUESTED / COMMENTED / DISMISSED
+}
+
+// GitHubPRStatus github 工具 pr_status 操作的结构化结果
+type GitHubPRStatus struct {
+	Repo           string         `json:"repo"`
+	Number         int            `json:"number"`
+	Title          string         `json:"title"`
+	State          string         `json:"state"`
+	Draft          bool           `json:"draft"`
+	Merged         bool           `json:"merged"`
+	Mergeable      *bool          `json:"mergeable"` // GitHub 尚未计算时为 null
+	MergeableState string         `json:"mergeable_state"`
+	Author         string         `json:"author"`
+	Head           string         `json:"head"`
+	Base           string         `json:"base"`
+	HeadSHA        string         `json:"head_sha"`
+	Additions      int            `json:"additions"`
+	Deletions      int            `json:"deletions"`
+	ChangedFiles   int            `json:"changed_files"`
+	CIState        string         `json:"ci_state"` // success / failure / pending / none
+	Checks         []GitHubCheck  `json:"checks"`
+	Reviews        []GitHubReview `json:"reviews"`
+	URL            string         `json:"url"`
+}
+
+// GitHubFile github 工具 get_file 操作的结构化结果，path 为目录时 entries 为目录内容
+type GitHubFile struct {
+	Repo      string   `json:"repo"`
+	Path      string   `json:"path"`
+	Ref       string   `json:"ref,omitempty"`
+	Type      string   `json:"type"` // file / dir
+	Size      int      `json:"size"`
+	SHA       string   `json:"sha,omitempty"`
+	Content   string   `json:"content,omitempty"`
+	Truncated bool     `json:"truncated"`
+	Entries   []string `json:"entries,omitempty"`
+	URL       string   `json:"url,omitempty"`
+}
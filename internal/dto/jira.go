@@ -0,0 +1,37 @@
+package dto
+
+// JiraIssue Jira issue 摘要
+type JiraIssue struct {
+	Key       string   `json:"key"`
+	Summary   string   `json:"summary"`
+	Status    string   `json:"status"`
+	Type      string   `json:"type"`
+	Priority  string   `json:"priority,omitempty"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Reporter  string   `json:"reporter,omitempty"`
+	Labels    []string `json:"labels"`
+	UpdatedAt string   `json:"updated_at,omitempty"` // Jira 返回的原始时间
+	URL       string   `json:"url"`
+}
+
+// JiraSearchResult jira 工具 search 操作的结构化结果
+type JiraSearchResult struct {
+	JQL       string      `json:"jql"` // 实际执行的 JQL，包含项目限制
+	Issues    []JiraIssue `json:"issues"`
+	Truncated bool        `json:"truncated"`
+}
+
+// JiraTransition issue 可执行的状态流转
+type JiraTransition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   string `json:"to"`
+}
+
+// JiraTransitionResult jira 工具 transition 操作的结构化结果，未指定流转时只返回可用流转
+type JiraTransitionResult struct {
+	Key         string           `json:"key"`
+	Applied     *JiraTransition  `json:"applied,omitempty"`
+	Transitions []JiraTransition `json:"transitions"`
+	URL         string           `json:"url"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// K8sPod Pod 摘要
+type K8sPod struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Phase     string    `json:"phase"`
+	Ready     string    `json:"ready"` // 就绪容器数/容器总数
+	Restarts  int32     `json:"restarts"`
+	Node      string    `json:"node,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// K8sDeployment Deployment 摘要
+type K8sDeployment struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Replicas  int32     `json:"replicas"`
+	Ready     int32     `json:"ready"`
+	Updated   int32     `json:"updated"`
+	Available int32     `json:"available"`
+	Images    []string  `json:"images"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// K8sListResult k8s 工具列表操作的结构化结果
+type K8sListResult struct {
+	Cluster     string          `json:"cluster"`
+	Namespace   string          `json:"namespace"` // 所有命名空间时为 "*"
+	Kind        string          `json:"kind"`
+	Pods        []K8sPod        `json:"pods,omitempty"`
+	Deployments []K8sDeployment `json:"deployments,omitempty"`
+	Truncated   bool            `json:"truncated"`
+}
+
+// K8sLogResult k8s 工具日志操作的结构化结果
+type K8sLogResult struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Previous  bool   `json:"previous"`
+	Truncated bool   `json:"truncated"`
+	Log       string `json:"log"`
+}
+
+// K8sEvent 资源相关的事件
+type K8sEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// K8sDescribeResult k8s 工具 describe 操作的结构化结果
+type K8sDescribeResult struct {
+	Cluster   string                 `json:"cluster"`
+	Namespace string                 `json:"namespace,omitempty"` // 节点等集群级资源为空
+	Kind      string                 `json:"kind"`
+	Name      string                 `json:"name"`
+	Details   map[string]interface{} `json:"details"`
+	Events    []K8sEvent             `json:"events"`
+}
@@ -31,9 +31,10 @@ type MCPExecuteResponse struct {
 
 // MCPContent MCP内容结构
 type MCPContent struct {
-	Type string      `json:"type"`
-	Text string      `json:"text,omitempty"`
-	Data interface{} `json:"data,omitempty"`
+	Type     string      `json:"type"`
+	Text     string      `json:"text,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	MimeType string      `json:"mimeType,omitempty"` // image 类型内容的MIME类型
 }
 
 // MCPMessage MCP消息结构（用于SSE）
@@ -67,6 +68,7 @@ type MCPCapabilities struct {
 	Resources *MCPResourcesCapability `json:"resources,omitempty"`
 	Prompts   *MCPPromptsCapability   `json:"prompts,omitempty"`
 	Logging   *MCPLoggingCapability   `json:"logging,omitempty"`
+	Sampling  *MCPSamplingCapability  `json:"sampling,omitempty"`
 }
 
 // MCPToolsCapability 工具能力
@@ -88,6 +90,42 @@ type MCPPromptsCapability struct {
 // MCPLoggingCapability 日志能力
 type MCPLoggingCapability struct{}
 
+// MCPSamplingCapability 采样能力（工具可请求LLM生成内容）
+type MCPSamplingCapability struct{}
+
+// MCPSamplingMessage 采样消息
+type MCPSamplingMessage struct {
+	Role    string     `json:"role"` // user, assistant
+	Content MCPContent `json:"content"`
+}
+
+// MCPModelHint 模型提示
+type MCPModelHint struct {
+	Name string `json:"name"`
+}
+
+// MCPModelPreferences 模型偏好
+type MCPModelPreferences struct {
+	Hints []MCPModelHint `json:"hints,omitempty"`
+}
+
+// MCPCreateMessageRequest 采样请求 (sampling/createMessage)
+type MCPCreateMessageRequest struct {
+	Messages         []MCPSamplingMessage `json:"messages"`
+	ModelPreferences *MCPModelPreferences `json:"modelPreferences,omitempty"`
+	SystemPrompt     string               `json:"systemPrompt,omitempty"`
+	MaxTokens        int                  `json:"maxTokens"`
+	Temperature      *float32             `json:"temperature,omitempty"`
+}
+
+// MCPCreateMessageResponse 采样响应
+type MCPCreateMessageResponse struct {
+	Role       string     `json:"role"`
+	Content    MCPContent `json:"content"`
+	Model      string     `json:"model"`
+	StopReason string     `json:"stopReason,omitempty"`
+}
+
 // MCPClientInfo 客户端信息
 type MCPClientInfo struct {
 	Name    string `json:"name"`
@@ -129,4 +167,59 @@ type MCPToolExecutionLog struct {
 	Duration    *time.Duration         `json:"duration,omitempty"`
 	UserID      *string                `json:"userId,omitempty"`
 	RequestID   string                 `json:"requestId"`
-}
\ No newline at end of file
+}
+
+// MCPExecutionLogPage 执行日志列表的一页，从新到旧排列
+type MCPExecutionLogPage struct {
+	Logs  []*MCPToolExecutionLog `json:"logs"`
+	Count int                    `json:"count"`
+	Limit int                    `json:"limit"`
+	PageInfo
+}
+
+// MCPExecutionLogArchive 执行日志归档文件
+type MCPExecutionLogArchive struct {
+	Name  string    `json:"name"`
+	From  time.Time `json:"from"` // 归档中最早一条日志的开始时间
+	To    time.Time `json:"to"`   // 归档中最晚一条日志的开始时间
+	Count int       `json:"count"`
+	Size  int64     `json:"size"` // 压缩后的字节数
+}
+
+// MCPExecutionLogArchiveQuery 归档执行日志查询参数，时间为 RFC3339 格式，范围为 [from, to)
+type MCPExecutionLogArchiveQuery struct {
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+	UserID   string     `form:"user_id"`
+	ToolName string     `form:"tool"`
+	Limit    int        `form:"limit" binding:"omitempty,min=1,max=1000"` // 默认 100
+}
+
+// 协议自检结果状态
+const (
+	MCPSelfTestPassed  = "passed"
+	MCPSelfTestFailed  = "failed"
+	MCPSelfTestSkipped = "skipped"
+)
+
+// MCPSelfTestRequest 协议自检请求，Tool 为空时跳过成功调用和通知格式检查
+type MCPSelfTestRequest struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// MCPSelfTestCheck 一项自检的结果
+type MCPSelfTestCheck struct {
+	Name       string   `json:"name"`
+	Status     string   `json:"status"` // passed / failed / skipped
+	Violations []string `json:"violations,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"` // 规范允许但部分客户端不接受的情况，不影响结果
+	Detail     string   `json:"detail,omitempty"`   // 跳过的原因等说明
+}
+
+// MCPSelfTestReport 协议自检报告
+type MCPSelfTestReport struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Passed          bool               `json:"passed"` // 没有失败的检查项
+	Checks          []MCPSelfTestCheck `json:"checks"`
+}
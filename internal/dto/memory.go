@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// 用户记忆类别
+const (
+	MemoryCategoryRiskTolerance     = "risk_tolerance"
+	MemoryCategoryInvestmentHorizon = "investment_horizon"
+	MemoryCategoryFavoriteTicker    = "favorite_ticker"
+	MemoryCategoryHolding           = "holding"
+	MemoryCategoryPreference        = "preference"
+)
+
+// MemoryResponse 从对话中提取的一条用户记忆
+type MemoryResponse struct {
+	ID               int64     `json:"id"`
+	Category         string    `json:"category"`
+	Content          string    `json:"content"`
+	SourceResponseID string    `json:"source_response_id,omitempty"` // 提取出该记忆的那次对话的回复ID
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// MemoryListResponse 用户记忆列表，最近更新的在前
+type MemoryListResponse struct {
+	Enabled  bool             `json:"enabled"`
+	Memories []MemoryResponse `json:"memories"`
+}
+
+// MemorySettings 用户记忆设置，关闭后不再提取新的记忆，也不在对话中使用已有记忆
+type MemorySettings struct {
+	Enabled bool `json:"enabled"`
+}
+
+// UpdateMemorySettingsRequest 更新用户记忆设置请求
+type UpdateMemorySettingsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// DeleteMemoriesResponse 删除用户记忆结果
+type DeleteMemoriesResponse struct {
+	Deleted int64 `json:"deleted"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// SaveNotificationChannelRequest 保存通知渠道请求，整体覆盖已有设置
+type SaveNotificationChannelRequest struct {
+	Target  string   `json:"target" binding:"max=512"`                       // 邮箱地址（为空时使用账号邮箱）、Slack Webhook 地址或 Telegram 会话ID
+	Events  []string `json:"events" binding:"omitempty,min=1,dive,required"` // 订阅的事件类型，支持 * 和 quota.* 形式的通配，默认全部
+	Enabled *bool    `json:"enabled"`                                        // 默认启用
+}
+
+// NotificationChannelResponse 用户的通知渠道
+type NotificationChannelResponse struct {
+	Channel   string    `json:"channel"` // email / slack / telegram
+	Target    string    `json:"target"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationChannelListResponse 用户的通知渠道列表
+type NotificationChannelListResponse struct {
+	Channels          []NotificationChannelResponse `json:"channels"`
+	AvailableChannels []string                      `json:"available_channels"` // 服务端已配置的渠道
+	EventTypes        []string                      `json:"event_types"`        // 可订阅的事件类型
+}
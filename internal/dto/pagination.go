@@ -0,0 +1,9 @@
+package dto
+
+// PageInfo 游标分页信息，next/prev 为带游标的请求地址，没有更多数据的方向为空
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// PersonaRequest 创建或更新助手角色请求，更新时覆盖所有字段
+type PersonaRequest struct {
+	Name            string   `json:"name" binding:"required,max=64"` // 聊天请求中 persona 的取值，不区分大小写
+	Description     string   `json:"description" binding:"max=1000"`
+	SystemPrompt    string   `json:"system_prompt" binding:"required,max=16000"`            // Go 模板，可使用 {{.Persona}}、{{.Model}}、{{.Date}}
+	AllowedTools    []string `json:"allowed_tools" binding:"max=100,dive,required,max=128"` // 为空时不能使用工具，"*" 表示所有工具
+	DefaultProvider string   `json:"default_provider" binding:"omitempty,max=64"`
+	DefaultModel    string   `json:"default_model" binding:"omitempty,max=128"`
+	Temperature     *float32 `json:"temperature" binding:"omitempty,min=0,max=2"`
+	MaxTokens       *int     `json:"max_tokens" binding:"omitempty,min=1,max=200000"`
+}
+
+// PersonaResponse 助手角色信息
+type PersonaResponse struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	SystemPrompt    string    `json:"system_prompt"`
+	AllowedTools    []string  `json:"allowed_tools"`
+	DefaultProvider string    `json:"default_provider,omitempty"`
+	DefaultModel    string    `json:"default_model,omitempty"`
+	Temperature     *float32  `json:"temperature,omitempty"`
+	MaxTokens       *int      `json:"max_tokens,omitempty"`
+	CreatedBy       int64     `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PersonaListResponse 助手角色列表
+type PersonaListResponse struct {
+	Personas []PersonaResponse `json:"personas"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// CreateProjectRequest 创建项目请求
+type CreateProjectRequest struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// ProjectResponse 项目信息
+type ProjectResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Providers   []string  `json:"providers"` // 已配置密钥的提供商
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProjectUsageDay 项目单日AI用量
+type ProjectUsageDay struct {
+	Date     string `json:"date"` // UTC日期，格式 YYYY-MM-DD
+	Requests int64  `json:"requests"`
+	Tokens   int64  `json:"tokens"`
+}
+
+// ProjectUsageResponse 项目AI用量统计
+type ProjectUsageResponse struct {
+	ProjectID     int64             `json:"project_id"`
+	Since         string            `json:"since"`
+	TotalRequests int64             `json:"total_requests"`
+	TotalTokens   int64             `json:"total_tokens"`
+	Days          []ProjectUsageDay `json:"days"`
+}
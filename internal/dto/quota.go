@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// QuotaUsage 单项AI用量配额
+type QuotaUsage struct {
+	Limit     int64     `json:"limit"`     // 0 表示不限制
+	Used      int64     `json:"used"`      // 当前周期已用量
+	Remaining int64     `json:"remaining"` // 不限制时为 -1
+	ResetAt   time.Time `json:"reset_at"`  // 配额重置时间(UTC)
+}
+
+// QuotaStatusResponse AI用量配额状态响应
+type QuotaStatusResponse struct {
+	Enabled        bool       `json:"enabled"`
+	Role           string     `json:"role"`
+	RequestsPerDay QuotaUsage `json:"requests_per_day"`
+	TokensPerMonth QuotaUsage `json:"tokens_per_month"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// RSSItem 订阅源中的一条新闻
+type RSSItem struct {
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Summary   string    `json:"summary,omitempty"`
+	Published time.Time `json:"published"`
+	Source    string    `json:"source"`            // 最先收录该新闻的订阅源
+	AlsoIn    []string  `json:"also_in,omitempty"` // 同一新闻出现在的其他订阅源
+}
+
+// RSSFeedError 获取失败的订阅源
+type RSSFeedError struct {
+	Feed  string `json:"feed"`
+	Error string `json:"error"`
+}
+
+// RSSFeedResult rss_feed 工具的结构化结果
+type RSSFeedResult struct {
+	Since     time.Time      `json:"since"`
+	Feeds     []string       `json:"feeds"`
+	Items     []RSSItem      `json:"items"`
+	Truncated bool           `json:"truncated"`
+	Errors    []RSSFeedError `json:"errors,omitempty"`
+}
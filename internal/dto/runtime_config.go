@@ -0,0 +1,60 @@
+package dto
+
+// RuntimeConfigVersion 导出配置的格式版本
+const RuntimeConfigVersion = 1
+
+// RedactedSecret 导出时替代密钥的占位符
+const RedactedSecret = "[REDACTED]"
+
+// RuntimeConfig 可在环境间迁移的运行时配置，密钥已脱敏
+type RuntimeConfig struct {
+	Version   int                     `json:"version" yaml:"version"`
+	Providers []RuntimeProviderConfig `json:"providers" yaml:"providers"`
+	Tools     RuntimeToolsConfig      `json:"tools" yaml:"tools"`
+}
+
+// RuntimeProviderConfig 提供商配置及其模型状态
+type RuntimeProviderConfig struct {
+	Type         string               `json:"type" yaml:"type"`
+	APIKey       string               `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	BaseURL      string               `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	ProjectID    string               `json:"project_id,omitempty" yaml:"project_id,omitempty"`
+	Location     string               `json:"location,omitempty" yaml:"location,omitempty"`
+	DefaultModel string               `json:"default_model,omitempty" yaml:"default_model,omitempty"`
+	Timeout      int                  `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxRetries   int                  `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	Models       []RuntimeModelConfig `json:"models" yaml:"models"`
+}
+
+// RuntimeModelConfig 模型配置
+type RuntimeModelConfig struct {
+	Name        string  `json:"name" yaml:"name"`
+	DisplayName string  `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	MaxTokens   int     `json:"max_tokens" yaml:"max_tokens"`
+	Temperature float32 `json:"temperature" yaml:"temperature"`
+	TopP        float32 `json:"top_p" yaml:"top_p"`
+	TopK        int     `json:"top_k,omitempty" yaml:"top_k,omitempty"`
+	Enabled     bool    `json:"enabled" yaml:"enabled"`
+}
+
+// RuntimeToolsConfig MCP工具相关配置
+type RuntimeToolsConfig struct {
+	Available        []string `json:"available" yaml:"available"`
+	SamplingModel    string   `json:"sampling_model,omitempty" yaml:"sampling_model,omitempty"`
+	RiskFreeRate     float64  `json:"risk_free_rate" yaml:"risk_free_rate"`
+	TranscriptAPIKey string   `json:"transcript_api_key,omitempty" yaml:"transcript_api_key,omitempty"`
+}
+
+// RuntimeConfigChange 导入配置与当前配置的一处差异
+type RuntimeConfigChange struct {
+	Path     string      `json:"path"`
+	Current  interface{} `json:"current"`
+	Imported interface{} `json:"imported"`
+	Live     bool        `json:"live"` // 导入时立即生效，false 表示需要修改配置文件并重启
+}
+
+// RuntimeConfigImportResult 导入结果
+type RuntimeConfigImportResult struct {
+	DryRun  bool                  `json:"dry_run"`
+	Changes []RuntimeConfigChange `json:"changes"`
+}
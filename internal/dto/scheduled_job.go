@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateScheduledJobRequest 创建定时任务请求
+type CreateScheduledJobRequest struct {
+	Name     string          `json:"name" binding:"required,max=100"`
+	JobType  string          `json:"job_type" binding:"required"`
+	Schedule string          `json:"schedule" binding:"required,max=128"` // cron 表达式，如 "0 3 * * *" 或 "@every 6h"
+	Params   json.RawMessage `json:"params"`                              // 任务类型的参数，默认 {}
+	Enabled  *bool           `json:"enabled"`                             // 默认启用
+}
+
+// UpdateScheduledJobRequest 更新定时任务请求，未提供的字段保持不变，任务类型不能修改
+type UpdateScheduledJobRequest struct {
+	Name     *string         `json:"name" binding:"omitempty,min=1,max=100"`
+	Schedule *string         `json:"schedule" binding:"omitempty,max=128"`
+	Params   json.RawMessage `json:"params"`
+	Enabled  *bool           `json:"enabled"`
+}
+
+// ScheduledJobResponse 定时任务信息
+type ScheduledJobResponse struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	JobType        string          `json:"job_type"`
+	Schedule       string          `json:"schedule"`
+	Params         json.RawMessage `json:"params"`
+	Enabled        bool            `json:"enabled"`
+	Running        bool            `json:"running"`               // 是否正在本实例上执行
+	NextRunAt      *time.Time      `json:"next_run_at,omitempty"` // 仅启用的任务
+	LastRunAt      *time.Time      `json:"last_run_at,omitempty"`
+	LastStatus     string          `json:"last_status,omitempty"` // succeeded / failed
+	LastResult     string          `json:"last_result,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastDurationMs int64           `json:"last_duration_ms"`
+	RunCount       int64           `json:"run_count"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ScheduledJobTypeResponse 可用的任务类型
+type ScheduledJobTypeResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ScheduledJobListResponse 定时任务列表
+type ScheduledJobListResponse struct {
+	Jobs             []ScheduledJobResponse     `json:"jobs"`
+	JobTypes         []ScheduledJobTypeResponse `json:"job_types"`
+	SchedulerEnabled bool                       `json:"scheduler_enabled"` // 为 false 时任务只能手动执行
+	Timezone         string                     `json:"timezone"`          // 解析 cron 表达式使用的时区
+}
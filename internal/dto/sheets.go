@@ -0,0 +1,15 @@
+package dto
+
+// SheetsExportResult sheets_export 工具的结构化结果
+type SheetsExportResult struct {
+	SpreadsheetID string   `json:"spreadsheet_id"`
+	Title         string   `json:"title"`
+	Sheet         string   `json:"sheet"`
+	SheetID       int64    `json:"sheet_id"`
+	URL           string   `json:"url"`
+	Created       bool     `json:"created"` // 是否新建了表格，否则是在已有表格中新增了工作表
+	Rows          int      `json:"rows"`    // 写入的数据行数，不含表头
+	Columns       int      `json:"columns"`
+	SharedWith    []string `json:"shared_with"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// ShellResult 沙箱命令工具的结构化结果
+type ShellResult struct {
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+	ExitCode   int      `json:"exit_code"` // 超时被结束时为 -1
+	Stdout     string   `json:"stdout"`
+	Stderr     string   `json:"stderr"`
+	Truncated  bool     `json:"truncated"` // 输出超过上限被截断
+	TimedOut   bool     `json:"timed_out"`
+	DurationMs int64    `json:"duration_ms"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// SlackPostResult slack_post 工具的结构化结果
+type SlackPostResult struct {
+	Channel   string `json:"channel"`
+	Via       string `json:"via"`                 // bot 或 webhook
+	Timestamp string `json:"timestamp,omitempty"` // 消息的 ts，仅 bot 方式返回
+}
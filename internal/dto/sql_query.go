@@ -0,0 +1,40 @@
+package dto
+
+// SQLDatabase 可查询的外部数据源
+type SQLDatabase struct {
+	Name        string `json:"name"`
+	Driver      string `json:"driver"`
+	Description string `json:"description,omitempty"`
+}
+
+// SQLColumn 表结构中的列，被脱敏的列不能在查询中引用
+type SQLColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
+// SQLTable 表结构
+type SQLTable struct {
+	Name    string      `json:"name"`
+	Columns []SQLColumn `json:"columns"`
+}
+
+// SQLSchemaResult sql_query 工具 schema 操作的结构化结果
+type SQLSchemaResult struct {
+	Database    string     `json:"database"`
+	Driver      string     `json:"driver"`
+	Description string     `json:"description,omitempty"`
+	Tables      []SQLTable `json:"tables"`
+}
+
+// SQLQueryResult sql_query 工具 query 操作的结构化结果
+type SQLQueryResult struct {
+	Database   string          `json:"database"`
+	Columns    []string        `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	RowCount   int             `json:"row_count"`
+	Truncated  bool            `json:"truncated"`          // 行数超过上限，只返回了前 max_rows 行
+	Redacted   []string        `json:"redacted,omitempty"` // 值被替换为 [REDACTED] 的列
+	DurationMs int64           `json:"duration_ms"`
+}
@@ -6,13 +6,19 @@ import "time"
 type StockAnalysisRequest struct {
 	Symbol     string `json:"symbol" binding:"required"`     // 股票代码
 	Period     string `json:"period,omitempty"`              // 分析周期 (1d, 5d, 1mo, 3mo, 6mo, 1y, 2y, 5y, 10y, ytd, max)
-	AnalysisType string `json:"analysis_type,omitempty"`     // 分析类型 (technical, fundamental, risk, all)
+	AnalysisType string `json:"analysis_type,omitempty"`     // 分析类型 (technical, fundamental, risk, benchmark, all)
+	Benchmark  string `json:"benchmark,omitempty"`           // 基准指数代码 (默认 ^GSPC)
+	Language   string `json:"language,omitempty"`            // 输出语言 (en, zh, ja, es, de)，默认跟随 Accept-Language
+	RiskFreeRate *float64 `json:"risk_free_rate,omitempty" binding:"omitempty,gte=-1,lte=1"` // 年化无风险利率 (默认取配置 stock.risk_free_rate)
 }
 
 // StockCompareRequest 股票对比请求
 type StockCompareRequest struct {
 	Symbols []string `json:"symbols" binding:"required,min=2,max=5"` // 要对比的股票代码列表
 	Period  string   `json:"period,omitempty"`                       // 对比周期
+	Benchmark string `json:"benchmark,omitempty"`                    // 基准指数代码 (默认 ^GSPC)
+	BaseCurrency string `json:"base_currency,omitempty"`             // 对比使用的基准货币 (默认 USD)
+	Language     string `json:"language,omitempty"`                  // 输出语言 (en, zh, ja, es, de)，默认跟随 Accept-Language
 }
 
 // StockAnalysisResponse 股票分析响应
@@ -26,6 +32,141 @@ type StockAnalysisResponse struct {
 	FundamentalAnalysis *FundamentalAnalysis `json:"fundamental_analysis,omitempty"`
 	RiskAssessment   *RiskAssessment       `json:"risk_assessment,omitempty"`
 	InvestmentAdvice *InvestmentAdvice     `json:"investment_advice,omitempty"`
+	BenchmarkComparison *BenchmarkComparison `json:"benchmark_comparison,omitempty"`
+}
+
+// StockQuote 结构化股票报价
+type StockQuote struct {
+	Symbol        string  `json:"symbol"`
+	Currency      string  `json:"currency"`
+	Exchange      string  `json:"exchange"`
+	Price         float64 `json:"price"`
+	PreviousClose float64 `json:"previous_close"`
+	DayHigh       float64 `json:"day_high"`
+	DayLow        float64 `json:"day_low"`
+	Volume        int64   `json:"volume"`
+	MarketTime    int64   `json:"market_time"`
+}
+
+// CompanyProfile 公司概况及关键指标（未提供的数值为0）
+type CompanyProfile struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name"`
+	Industry      string  `json:"industry"`
+	Sector        string  `json:"sector"`
+	Country       string  `json:"country"`
+	Website       string  `json:"website"`
+	Employees     int64   `json:"employees"`
+	MarketCap     float64 `json:"market_cap"`
+	PE            float64 `json:"pe"`
+	DividendYield float64 `json:"dividend_yield"`
+	Beta          float64 `json:"beta"`
+}
+
+// PriceSeries 历史价格序列
+type PriceSeries struct {
+	Symbol     string    `json:"symbol"`
+	Currency   string    `json:"currency"`
+	Timestamps []int64   `json:"timestamps"`
+	Closes     []float64 `json:"closes"`
+	Volumes    []float64 `json:"volumes,omitempty"`
+}
+
+// EarningsSummary 财报电话会议结构化摘要
+type EarningsSummary struct {
+	Symbol         string   `json:"symbol,omitempty"`
+	Year           int      `json:"year,omitempty"`
+	Quarter        int      `json:"quarter,omitempty"`
+	Date           string   `json:"date,omitempty"`
+	Summary        string   `json:"summary"`
+	Guidance       []string `json:"guidance"`
+	Risks          []string `json:"risks"`
+	Highlights     []string `json:"highlights"`
+	Sentiment      string   `json:"sentiment"`       // positive, neutral, negative
+	SentimentScore float64  `json:"sentiment_score"` // -1 (消极) 到 1 (积极)
+	Model          string   `json:"model"`
+	Chunks         int      `json:"chunks"`
+}
+
+// StockAnalysisReport 股票分析工具的结构化结果 (format=json)
+type StockAnalysisReport struct {
+	Symbol        string          `json:"symbol"`
+	AnalysisType  string          `json:"analysis_type"`
+	Period        string          `json:"period"`
+	Quote         *StockQuote     `json:"quote"`
+	Profile       *CompanyProfile `json:"profile,omitempty"`
+	ChangePercent float64         `json:"change_percent"`
+	Trend         string          `json:"trend"`      // up, down, flat
+	RiskLevel     string          `json:"risk_level"` // medium_low, medium, medium_high
+	Rating        string          `json:"rating"`     // buy, hold, watch
+}
+
+// StockAdviceReport 投资建议工具的结构化结果 (format=json)
+type StockAdviceReport struct {
+	Symbol               string          `json:"symbol"`
+	InvestmentHorizon    string          `json:"investment_horizon"`
+	RiskTolerance        string          `json:"risk_tolerance"`
+	Quote                *StockQuote     `json:"quote"`
+	Profile              *CompanyProfile `json:"profile,omitempty"`
+	ChangePercent        float64         `json:"change_percent"`
+	Score                int             `json:"score"`
+	Overall              string          `json:"overall"`                // strong_recommend, recommend, neutral, cautious, not_recommended
+	BuySignal            string          `json:"buy_signal"`             // strong_buy, buy, watch, cautious_buy, avoid
+	RiskLevel            string          `json:"risk_level"`             // low, medium_low, medium, medium_high, high
+	HorizonOutlook       string          `json:"horizon_outlook"`        // positive, negative
+	RiskToleranceOutlook string          `json:"risk_tolerance_outlook"` // positive, negative
+	Position             *PositionAdvice `json:"position,omitempty"`
+	Warnings             []string        `json:"warnings"` // volatility, liquidity, sector
+	Action               string          `json:"action"`   // act, observe, wait
+}
+
+// PositionAdvice 仓位建议
+type PositionAdvice struct {
+	InvestmentAmount float64 `json:"investment_amount"`
+	Shares           int     `json:"shares"`
+	ActualAmount     float64 `json:"actual_amount"`
+}
+
+// StockCompareReport 股票对比工具的结构化结果 (format=json)
+type StockCompareReport struct {
+	CompareType    string              `json:"compare_type"`
+	Period         string              `json:"period"`
+	Stocks         []StockCompareEntry `json:"stocks"` // 与请求中的顺序一致
+	BestPerformer  string              `json:"best_performer"`
+	WorstPerformer string              `json:"worst_performer"`
+}
+
+// StockCompareEntry 参与对比的单只股票（估值指标未提供时为0）
+type StockCompareEntry struct {
+	Symbol         string  `json:"symbol"`
+	Currency       string  `json:"currency"`
+	Price          float64 `json:"price"`
+	PreviousClose  float64 `json:"previous_close"`
+	Change         float64 `json:"change"`
+	ChangePercent  float64 `json:"change_percent"`
+	Volume         int64   `json:"volume"`
+	MarketCap      float64 `json:"market_cap"`
+	PE             float64 `json:"pe"`
+	Industry       string  `json:"industry"`
+	Sector         string  `json:"sector"`
+	Risk           string  `json:"risk"`           // high_volatility, sharp_decline, medium, stable
+	Liquidity      string  `json:"liquidity"`      // good, fair, poor
+	Recommendation string  `json:"recommendation"` // cautious, consider_buy, buy_dip, high_risk
+}
+
+// BenchmarkComparison 基准对比
+type BenchmarkComparison struct {
+	Benchmark       string  `json:"benchmark"`        // 基准指数代码
+	Period          string  `json:"period"`           // 对比周期
+	DataPoints      int     `json:"data_points"`      // 对齐后的交易日数量
+	StockReturn     float64 `json:"stock_return"`     // 区间收益率
+	BenchmarkReturn float64 `json:"benchmark_return"` // 基准区间收益率
+	ExcessReturn    float64 `json:"excess_return"`    // 超额收益率
+	Alpha           float64 `json:"alpha"`            // 区间Alpha (相对Beta调整后的超额收益)
+	Beta            float64 `json:"beta"`             // 相对基准的Beta
+	UpCapture       float64 `json:"up_capture"`       // 上行捕获率 (%)
+	DownCapture     float64 `json:"down_capture"`     // 下行捕获率 (%)
+	Outperformed    bool    `json:"outperformed"`     // 是否跑赢基准
 }
 
 // TechnicalAnalysis 技术分析
@@ -83,10 +224,47 @@ type RiskAssessment struct {
 	Volatility   float64 `json:"volatility"`    // 波动率
 	Beta         float64 `json:"beta"`          // 贝塔系数
 	MaxDrawdown  float64 `json:"max_drawdown"`  // 最大回撤
-	VaR          float64 `json:"var"`           // 风险价值
+	VaR          float64 `json:"var"`           // 风险价值 (95% 单日历史VaR)
+	Metrics      *RiskMetrics `json:"metrics,omitempty"` // 风险收益指标
 	RiskFactors  []string `json:"risk_factors"` // 风险因素
 }
 
+// RiskMetrics 基于日收益率的风险收益指标（VaR/CVaR 为单日损失比例，正值表示亏损）
+type RiskMetrics struct {
+	DataPoints           int     `json:"data_points"`           // 日收益率样本数
+	VaR95                float64 `json:"var_95"`                // 95% 历史VaR
+	VaR99                float64 `json:"var_99"`                // 99% 历史VaR
+	CVaR95               float64 `json:"cvar_95"`               // 95% 条件VaR (预期亏损)
+	CVaR99               float64 `json:"cvar_99"`               // 99% 条件VaR (预期亏损)
+	AnnualizedReturn     float64 `json:"annualized_return"`     // 年化收益率
+	AnnualizedVolatility float64 `json:"annualized_volatility"` // 年化波动率
+	SharpeRatio          float64 `json:"sharpe_ratio"`          // 年化夏普比率
+	SortinoRatio         float64 `json:"sortino_ratio"`         // 年化索提诺比率
+	RiskFreeRate         float64 `json:"risk_free_rate"`        // 使用的年化无风险利率
+}
+
+// PortfolioHolding 投资组合持仓
+type PortfolioHolding struct {
+	Symbol string  `json:"symbol" binding:"required"` // 股票代码
+	Weight float64 `json:"weight" binding:"gt=0"`     // 持仓权重 (按比例归一化)
+}
+
+// PortfolioRiskRequest 投资组合风险分析请求
+type PortfolioRiskRequest struct {
+	Holdings     []PortfolioHolding `json:"holdings" binding:"required,min=1,max=20,dive"`         // 持仓列表
+	Period       string             `json:"period,omitempty"`                                      // 分析周期 (默认 1y)
+	RiskFreeRate *float64           `json:"risk_free_rate,omitempty" binding:"omitempty,gte=-1,lte=1"` // 年化无风险利率 (默认取配置 stock.risk_free_rate)
+}
+
+// PortfolioRiskResponse 投资组合风险分析响应
+type PortfolioRiskResponse struct {
+	Holdings    []PortfolioHolding      `json:"holdings"`     // 归一化后的持仓
+	Period      string                  `json:"period"`       // 分析周期
+	MaxDrawdown float64                 `json:"max_drawdown"` // 组合最大回撤
+	Portfolio   *RiskMetrics            `json:"portfolio"`    // 组合风险指标
+	Individual  map[string]*RiskMetrics `json:"individual"`   // 各持仓风险指标
+}
+
 // InvestmentAdvice 投资建议
 type InvestmentAdvice struct {
 	Recommendation string   `json:"recommendation"` // 推荐操作 (强烈买入/买入/持有/卖出/强烈卖出)
@@ -103,10 +281,14 @@ type StockCompareResponse struct {
 	Comparison  *StockComparison        `json:"comparison"`
 	Individual  []StockAnalysisResponse `json:"individual"`
 	Recommendation string               `json:"recommendation"` // 对比后的推荐
+	Warnings    []string                `json:"warnings,omitempty"` // 对比过程中的警告（如汇率获取失败）
 }
 
 // StockComparison 股票对比
 type StockComparison struct {
+	BaseCurrency string                `json:"base_currency"`            // 对比使用的基准货币
+	FXRates      map[string]float64    `json:"fx_rates,omitempty"`       // 各股票原始货币到基准货币的汇率
+	Prices       map[string]float64    `json:"prices,omitempty"`         // 折算为基准货币后的当前价格
 	Performance *PerformanceComparison `json:"performance,omitempty"`
 	Valuation   *ValuationComparison   `json:"valuation,omitempty"`
 	Risk        *RiskComparison        `json:"risk,omitempty"`
@@ -120,6 +302,9 @@ type PerformanceComparison struct {
 	Returns3M  map[string]float64 `json:"returns_3m"`  // 3月收益率
 	Returns1Y  map[string]float64 `json:"returns_1y"`  // 1年收益率
 	BestPerformer string           `json:"best_performer"` // 最佳表现者
+	Benchmark     string             `json:"benchmark,omitempty"`     // 基准指数代码
+	ExcessReturn  map[string]float64 `json:"excess_return,omitempty"` // 相对基准的超额收益率
+	Alpha         map[string]float64 `json:"alpha,omitempty"`         // 相对基准的Alpha
 }
 
 // ValuationComparison 估值对比
@@ -0,0 +1,10 @@
+package dto
+
+// TranslationResult 翻译工具的结构化结果
+type TranslationResult struct {
+	SourceLanguage string `json:"source_language,omitempty"` // 调用方指定的原文语言，自动识别时为空
+	TargetLanguage string `json:"target_language"`
+	Text           string `json:"text"`
+	Model          string `json:"model,omitempty"` // 原文与目标语言相同时未调用模型
+	Chunks         int    `json:"chunks"`
+}
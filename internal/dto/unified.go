@@ -1,6 +1,10 @@
 package dto
 
-import "go-springAi/internal/types"
+import (
+	"time"
+
+	"go-springAi/internal/types"
+)
 
 // UnifiedMessage 统一的消息结构
 type UnifiedMessage struct {
@@ -10,7 +14,32 @@ type UnifiedMessage struct {
 
 // SetAPIKeyRequest 设置API密钥请求
 type SetAPIKeyRequest struct {
-	APIKey string `json:"api_key" binding:"required"`
+	APIKey        string     `json:"api_key" binding:"required"`
+	AllowedModels []string   `json:"allowed_models,omitempty"` // 限制密钥只能访问这些模型，为空表示不限制
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`     // 密钥的过期时间，为空表示不过期
+}
+
+// APIKeyVersionResponse API密钥版本响应
+type APIKeyVersionResponse struct {
+	Version        int64      `json:"version"`
+	MaskedKey      string     `json:"masked_key"`
+	Status         string     `json:"status"` // current / grace / retired
+	CreatedAt      time.Time  `json:"created_at"`
+	RotatedAt      *time.Time `json:"rotated_at,omitempty"`
+	GraceExpiresAt *time.Time `json:"grace_expires_at,omitempty"`
+}
+
+// APIKeyValidationStatus API密钥最近一次定期验证的结果
+type APIKeyValidationStatus struct {
+	Valid       bool      `json:"valid"`
+	Error       string    `json:"error,omitempty"`
+	ValidatedAt time.Time `json:"validated_at"`
+}
+
+// APIKeyExpirationStatus API密钥的过期时间及是否已被定期检查标记为过期
+type APIKeyExpirationStatus struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
 }
 
 // ProvidersResponse 提供商列表响应
@@ -34,6 +63,26 @@ type ModelConfigResponse struct {
 	Config   interface{} `json:"config"`
 }
 
+// ModelEnabledChange 批量变更中单个模型的目标状态
+type ModelEnabledChange struct {
+	Model   string `json:"model" binding:"required"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+// BulkModelUpdateRequest 批量启用/禁用模型请求
+type BulkModelUpdateRequest struct {
+	Changes []ModelEnabledChange `json:"changes" binding:"required,min=1,max=100,dive"`
+}
+
+// UpdateModelMetadataRequest 覆盖保存模型元数据请求，价格为每百万令牌的美元价格，省略时清除
+type UpdateModelMetadataRequest struct {
+	ContextWindow  int      `json:"context_window" binding:"min=0,max=10000000"`
+	InputPrice     *float64 `json:"input_price" binding:"omitempty,min=0"`
+	OutputPrice    *float64 `json:"output_price" binding:"omitempty,min=0"`
+	SupportsTools  bool     `json:"supports_tools"`
+	SupportsVision bool     `json:"supports_vision"`
+}
+
 // ValidationResponse API密钥验证响应
 type ValidationResponse struct {
 	Provider string `json:"provider"`
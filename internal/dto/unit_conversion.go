@@ -0,0 +1,12 @@
+package dto
+
+// UnitConversionResult 单位换算工具的结构化结果，数值均为十进制字符串以保留精度
+type UnitConversionResult struct {
+	Category string `json:"category"` // length, mass, volume, area, temperature, ratio, rate, currency
+	Value    string `json:"value"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Result   string `json:"result"`
+	Decimals int    `json:"decimals"`
+	Rate     string `json:"rate,omitempty"` // 货币换算使用的汇率，1 from = rate to
+}
@@ -21,11 +21,54 @@ type UpdateUserRequest struct {
 
 // UserResponse 用户响应
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	FullName  *string   `json:"full_name"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64      `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email"`
+	FullName  *string    `json:"full_name"`
+	IsActive  bool       `json:"is_active"`
+	IsAdmin   bool       `json:"is_admin"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // 已软删除时返回删除时间
+}
+
+// UserListQuery 用户列表查询参数
+type UserListQuery struct {
+	Page           int64      `form:"page"`
+	Limit          int64      `form:"limit"`
+	Search         string     `form:"search" binding:"max=100"`                                                  // 用户名或邮箱子串
+	IsActive       *bool      `form:"is_active"`                                                                 // 激活状态过滤
+	CreatedFrom    *time.Time `form:"created_from" time_format:"2006-01-02"`                                     // 创建日期起（含）
+	CreatedTo      *time.Time `form:"created_to" time_format:"2006-01-02"`                                       // 创建日期止（含）
+	SortBy         string     `form:"sort_by" binding:"omitempty,oneof=id username email created_at updated_at"` // 默认 created_at
+	SortOrder      string     `form:"sort_order" binding:"omitempty,oneof=asc desc"`                             // 默认 desc
+	IncludeDeleted bool       `form:"include_deleted"`                                                           // 同时返回已软删除的用户
+	Cursor         string     `form:"cursor"`                                                                    // 上一页响应中的游标，优先于 page
+}
+
+// UserListResponse 用户列表响应，未指定 page 时按游标分页
+type UserListResponse struct {
+	Users []*UserResponse `json:"users"`
+	Total int64           `json:"total"`
+	Page  int64           `json:"page,omitempty"` // 只在按 page 分页时返回
+	Limit int64           `json:"limit"`
+	PageInfo
+}
+
+// UserPreferences 用户偏好设置，空值表示未设置
+type UserPreferences struct {
+	DefaultProvider string   `json:"default_provider"` // 默认AI提供商
+	DefaultModel    string   `json:"default_model"`    // 默认模型
+	Temperature     *float32 `json:"temperature"`      // 默认温度
+	Locale          string   `json:"locale"`           // 输出语言 (en, zh, ja, es, de)
+	DefaultPeriod   string   `json:"default_period"`   // 股票分析默认周期
+}
+
+// UpdateUserPreferencesRequest 更新用户偏好设置请求，整体覆盖已有设置
+type UpdateUserPreferencesRequest struct {
+	DefaultProvider string   `json:"default_provider" binding:"omitempty,oneof=openai googleai mock"`
+	DefaultModel    string   `json:"default_model" binding:"max=100"`
+	Temperature     *float32 `json:"temperature" binding:"omitempty,gte=0,lte=2"`
+	Locale          string   `json:"locale" binding:"omitempty,oneof=en zh ja es de"`
+	DefaultPeriod   string   `json:"default_period" binding:"omitempty,oneof=1d 5d 1mo 3mo 6mo 1y 2y 5y 10y ytd max"`
 }
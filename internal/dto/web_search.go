@@ -0,0 +1,16 @@
+package dto
+
+// WebSearchResult 单条网页搜索结果
+type WebSearchResult struct {
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Snippet   string `json:"snippet"`
+	Published string `json:"published,omitempty"` // 后端提供的发布时间或页面年龄，格式因后端而异
+}
+
+// WebSearchResponse 网页搜索工具的结构化结果
+type WebSearchResponse struct {
+	Query   string            `json:"query"`
+	Backend string            `json:"backend"` // brave, bing, serpapi
+	Results []WebSearchResult `json:"results"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookRequest 创建Webhook端点请求，secret 为空时自动生成
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1,dive,required"`
+	Description string   `json:"description" binding:"max=500"`
+	Enabled     *bool    `json:"enabled"` // 默认启用
+	Secret      string   `json:"secret" binding:"omitempty,min=16,max=128"`
+}
+
+// UpdateWebhookRequest 更新Webhook端点请求，未提供的字段保持不变
+type UpdateWebhookRequest struct {
+	URL          *string  `json:"url" binding:"omitempty,url,max=2048"`
+	EventTypes   []string `json:"event_types" binding:"omitempty,min=1,dive,required"`
+	Description  *string  `json:"description" binding:"omitempty,max=500"`
+	Enabled      *bool    `json:"enabled"`
+	RotateSecret bool     `json:"rotate_secret"` // 生成新的签名密钥并在响应中返回
+}
+
+// WebhookEndpointResponse Webhook端点信息，secret 只在创建和轮换密钥时返回
+type WebhookEndpointResponse struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"event_types"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	Secret      string    `json:"secret,omitempty"`
+	SecretHint  string    `json:"secret_hint"` // 密钥末4位
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookEndpointListResponse Webhook端点列表
+type WebhookEndpointListResponse struct {
+	Endpoints  []WebhookEndpointResponse `json:"endpoints"`
+	EventTypes []string                  `json:"event_types"` // 可订阅的事件类型
+}
+
+// WebhookDeliveryResponse Webhook投递记录
+type WebhookDeliveryResponse struct {
+	ID            int64           `json:"id"`
+	EndpointID    int64           `json:"endpoint_id"`
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Status        string          `json:"status"` // pending / succeeded / failed
+	Attempts      int64           `json:"attempts"`
+	ResponseCode  int64           `json:"response_code,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"` // 仅待投递记录
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// WorkspaceFile 工作区中的文件或目录
+type WorkspaceFile struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	IsDir      bool      `json:"is_dir"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// WorkspaceReadResult file_read 工具的结构化结果
+type WorkspaceReadResult struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Content string `json:"content"`
+}
+
+// WorkspaceWriteResult file_write 工具的结构化结果
+type WorkspaceWriteResult struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	Appended   bool   `json:"appended"`
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes int64  `json:"quota_bytes"`
+}
+
+// WorkspaceListResult file_list 工具的结构化结果
+type WorkspaceListResult struct {
+	Dir        string          `json:"dir"`
+	Files      []WorkspaceFile `json:"files"`
+	UsedBytes  int64           `json:"used_bytes"`
+	QuotaBytes int64           `json:"quota_bytes"`
+}
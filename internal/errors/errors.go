@@ -23,12 +23,13 @@ const (
 // 定义错误码常量
 const (
 	// 通用错误码
-	ErrCodeInternal   ErrorCode = "INTERNAL_ERROR"
-	ErrCodeConflict   ErrorCode = "CONFLICT"
-	ErrCodeNotFound   ErrorCode = "NOT_FOUND"
-	ErrCodeBadRequest ErrorCode = "BAD_REQUEST"
-	ErrCodeTimeout    ErrorCode = "TIMEOUT"
-	ErrCodeRateLimit  ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrCodeInternal     ErrorCode = "INTERNAL_ERROR"
+	ErrCodeConflict     ErrorCode = "CONFLICT"
+	ErrCodeNotFound     ErrorCode = "NOT_FOUND"
+	ErrCodeBadRequest   ErrorCode = "BAD_REQUEST"
+	ErrCodeTimeout      ErrorCode = "TIMEOUT"
+	ErrCodeRateLimit    ErrorCode = "RATE_LIMIT_EXCEEDED"
+	ErrCodeClientClosed ErrorCode = "CLIENT_CLOSED_REQUEST"
 
 	// 认证和授权相关错误码
 	ErrCodeUnauthorized     ErrorCode = "UNAUTHORIZED"
@@ -76,6 +77,7 @@ const (
 	ErrCodeFileUploadFailed ErrorCode = "FILE_UPLOAD_FAILED"
 	ErrCodeStorageError     ErrorCode = "STORAGE_ERROR"
 	ErrCodeFileTooLarge     ErrorCode = "FILE_TOO_LARGE"
+	ErrCodeRequestTooLarge  ErrorCode = "REQUEST_TOO_LARGE"
 
 	// MCP相关错误码
 	ErrCodeMCPInitFailed    ErrorCode = "MCP_INIT_FAILED"
@@ -92,7 +94,8 @@ type AppError struct {
 	Severity   ErrorSeverity `json:"severity"`
 	HTTPStatus int           `json:"-"`
 	Timestamp  time.Time     `json:"timestamp"`
-	StackTrace []string      `json:"stack_trace,omitempty"`
+	StackTrace []string      `json:"-"` // 只写入日志和错误上报，不随响应返回
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
 	Cause      error         `json:"-"`
 }
 
@@ -121,6 +124,15 @@ func (e *AppError) WithDetails(details string) *AppError {
 	return e
 }
 
+// WithMetadata 添加返回给客户端的附加信息
+func (e *AppError) WithMetadata(key string, value interface{}) *AppError {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]interface{})
+	}
+	e.Metadata[key] = value
+	return e
+}
+
 // WithStackTrace 添加堆栈跟踪
 func (e *AppError) WithStackTrace() *AppError {
 	e.StackTrace = getStackTrace()
@@ -170,6 +182,20 @@ func NewTimeoutError(operation string) *AppError {
 	return NewAppError(ErrCodeTimeout, fmt.Sprintf("%s timeout", operation), SeverityMedium, http.StatusRequestTimeout)
 }
 
+// NewRequestTimeoutError 创建请求处理超时错误
+func NewRequestTimeoutError(timeout time.Duration) *AppError {
+	return NewAppError(ErrCodeTimeout, fmt.Sprintf("Request timed out after %s", timeout), SeverityMedium, http.StatusGatewayTimeout).
+		WithMetadata("timeout_seconds", timeout.Seconds())
+}
+
+// StatusClientClosedRequest 客户端断开连接的状态码，沿用nginx的499
+const StatusClientClosedRequest = 499
+
+// NewClientClosedRequestError 创建客户端断开连接错误
+func NewClientClosedRequestError() *AppError {
+	return NewAppError(ErrCodeClientClosed, "Client closed request", SeverityLow, StatusClientClosedRequest)
+}
+
 // NewRateLimitError 创建限流错误
 func NewRateLimitError() *AppError {
 	return NewAppError(ErrCodeRateLimit, "Rate limit exceeded", SeverityMedium, http.StatusTooManyRequests)
@@ -296,6 +322,26 @@ func NewResourceBusyError(resource string) *AppError {
 		SeverityMedium, http.StatusConflict)
 }
 
+// NewQuotaExceededError 创建配额超限错误，resetAt 为配额重置时间
+func NewQuotaExceededError(quota string, limit int64, resetAt time.Time) *AppError {
+	return NewAppError(ErrCodeQuotaExceeded,
+		fmt.Sprintf("Quota %s exceeded", quota),
+		SeverityLow, http.StatusTooManyRequests).
+		WithMetadata("quota", quota).
+		WithMetadata("limit", limit).
+		WithMetadata("reset_at", resetAt.UTC())
+}
+
+// NewBudgetExceededError 创建预算超限错误，与配额超限使用相同错误码，resetAt 为预算重置时间
+func NewBudgetExceededError(budget string, limitUSD float64, resetAt time.Time) *AppError {
+	return NewAppError(ErrCodeQuotaExceeded,
+		fmt.Sprintf("Budget %s exceeded", budget),
+		SeverityLow, http.StatusTooManyRequests).
+		WithMetadata("budget", budget).
+		WithMetadata("limit_usd", limitUSD).
+		WithMetadata("reset_at", resetAt.UTC())
+}
+
 // 网络和外部服务相关错误
 
 // NewNetworkError 创建网络错误
@@ -335,6 +381,14 @@ func NewFileTooLargeError(maxSize string) *AppError {
 		SeverityLow, http.StatusRequestEntityTooLarge)
 }
 
+// NewRequestTooLargeError 创建请求体过大错误，maxBytes 为允许的最大字节数
+func NewRequestTooLargeError(maxBytes int64) *AppError {
+	return NewAppError(ErrCodeRequestTooLarge,
+		fmt.Sprintf("Request body exceeds maximum allowed size of %d bytes", maxBytes),
+		SeverityLow, http.StatusRequestEntityTooLarge).
+		WithMetadata("max_bytes", maxBytes)
+}
+
 // MCP相关错误
 
 // NewMCPInitFailedError 创建MCP初始化失败错误
@@ -1,17 +1,48 @@
 package errors
 
 import (
+	"context"
 	"fmt"
+	"time"
+
+	"go-springAi/internal/requestid"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+// BodyTooLargeKey 请求体超过大小限制时由中间件写入上下文，值为允许的最大字节数
+const BodyTooLargeKey = "body_too_large"
+
+// RequestTimeoutKey 超时中间件写入上下文，值为请求的处理时限
+const RequestTimeoutKey = "request_timeout"
+
+// RequestContextError 请求因处理超时或客户端断开而取消时返回对应的错误，否则返回nil
+func RequestContextError(c *gin.Context) *AppError {
+	switch c.Request.Context().Err() {
+	case context.DeadlineExceeded:
+		if timeout, ok := c.Get(RequestTimeoutKey); ok {
+			if d, ok := timeout.(time.Duration); ok {
+				return NewRequestTimeoutError(d)
+			}
+		}
+	case context.Canceled:
+		return NewClientClosedRequestError()
+	}
+	return nil
+}
+
 // ErrorHandler 统一的错误处理器
 type ErrorHandler struct {
-	i18nManager I18nManager
+	i18nManager        I18nManager
+	reporter           Reporter
+	problemDetails     bool   // 默认以 problem+json 返回错误
+	problemTypeBaseURI string // problem type 前缀
 }
 
+// Reporter 将错误上报到Sentry等外部服务
+type Reporter func(c *gin.Context, appErr *AppError)
+
 // I18nManager 国际化管理器接口
 type I18nManager interface {
 	GetErrorMessage(lang string, appErr *AppError) string
@@ -25,11 +56,28 @@ func NewErrorHandler(i18nManager I18nManager) *ErrorHandler {
 	}
 }
 
+// SetReporter 设置错误上报函数
+func (h *ErrorHandler) SetReporter(reporter Reporter) {
+	h.reporter = reporter
+}
+
 // HandleError 统一的错误处理方法
 func (h *ErrorHandler) HandleError(c *gin.Context, err error) {
 	// 获取语言设置
 	lang := h.getLanguage(c)
 
+	// 读取请求体时超过大小限制，绑定失败等错误统一返回413
+	if maxBytes, ok := c.Get(BodyTooLargeKey); ok {
+		if limit, ok := maxBytes.(int64); ok {
+			err = NewRequestTooLargeError(limit)
+		}
+	}
+
+	// 处理超时或客户端断开导致的下游错误不作为内部错误处理
+	if ctxErr := RequestContextError(c); ctxErr != nil {
+		err = ctxErr
+	}
+
 	// 检查是否为应用程序错误
 	if appErr, ok := IsAppError(err); ok {
 		h.handleAppError(c, appErr, lang)
@@ -48,6 +96,15 @@ func (h *ErrorHandler) HandleError(c *gin.Context, err error) {
 
 // handleAppError 处理应用程序错误
 func (h *ErrorHandler) handleAppError(c *gin.Context, appErr *AppError, lang string) {
+	if h.reporter != nil {
+		h.reporter(c, appErr)
+	}
+
+	if h.ProblemRequested(c) {
+		h.writeProblem(c, appErr, lang)
+		return
+	}
+
 	// 获取国际化消息
 	message := appErr.Message
 	if h.i18nManager != nil {
@@ -63,7 +120,17 @@ func (h *ErrorHandler) handleAppError(c *gin.Context, appErr *AppError, lang str
 		},
 	}
 
-	// 在开发环境下添加详细信息
+	if c.Request != nil {
+		if requestID := requestid.FromContext(c.Request.Context()); requestID != "" {
+			response["error"].(gin.H)["request_id"] = requestID
+		}
+	}
+
+	if len(appErr.Metadata) > 0 {
+		response["error"].(gin.H)["metadata"] = appErr.Metadata
+	}
+
+	// 在开发环境下添加详细信息，发布模式下不返回堆栈
 	if gin.Mode() == gin.DebugMode {
 		if appErr.Details != "" {
 			response["error"].(gin.H)["details"] = appErr.Details
@@ -99,7 +166,12 @@ func (h *ErrorHandler) handleGenericError(c *gin.Context, err error, lang string
 
 // getLanguage 获取请求的语言设置
 func (h *ErrorHandler) getLanguage(c *gin.Context) string {
-	// 优先从查询参数获取
+	// 优先使用国际化中间件协商的语言
+	if lang := c.GetString("language"); lang != "" {
+		return lang
+	}
+
+	// 从查询参数获取
 	if lang := c.Query("lang"); lang != "" {
 		return lang
 	}
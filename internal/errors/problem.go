@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-springAi/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType RFC 7807 错误响应的内容类型
+const ProblemContentType = "application/problem+json"
+
+// 错误响应格式
+const (
+	FormatDefault = "default"
+	FormatProblem = "problem"
+)
+
+// DefaultProblemTypeBaseURI 未配置时 problem type 的前缀
+const DefaultProblemTypeBaseURI = "/problems/"
+
+// Problem RFC 7807 问题详情，code、timestamp、metadata 等为扩展字段
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       ErrorCode              `json:"code"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Details    string                 `json:"details,omitempty"`
+	StackTrace []string               `json:"stack_trace,omitempty"`
+}
+
+// ProblemType 由错误码生成 problem type，如 USER_NOT_FOUND 对应 <baseURI>user-not-found
+func ProblemType(baseURI string, code ErrorCode) string {
+	return baseURI + strings.ToLower(strings.ReplaceAll(string(code), "_", "-"))
+}
+
+// SetProblemDetails 设置默认是否返回 problem+json 及 type 前缀；关闭时客户端仍可通过 Accept 请求该格式
+func (h *ErrorHandler) SetProblemDetails(enabled bool, typeBaseURI string) {
+	if typeBaseURI == "" {
+		typeBaseURI = DefaultProblemTypeBaseURI
+	}
+	h.problemDetails = enabled
+	h.problemTypeBaseURI = typeBaseURI
+}
+
+// ProblemRequested 当前请求的错误是否以 problem+json 返回
+func (h *ErrorHandler) ProblemRequested(c *gin.Context) bool {
+	if h.problemDetails {
+		return true
+	}
+	if c.Request == nil {
+		return false
+	}
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(accept); err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeProblem 以 problem+json 返回应用错误，title 为错误码的本地化名称，detail 为本次错误的消息
+func (h *ErrorHandler) writeProblem(c *gin.Context, appErr *AppError, lang string) {
+	baseURI := h.problemTypeBaseURI
+	if baseURI == "" {
+		baseURI = DefaultProblemTypeBaseURI
+	}
+
+	// 错误码没有翻译时使用HTTP状态描述
+	title := http.StatusText(appErr.HTTPStatus)
+	if h.i18nManager != nil {
+		title = h.i18nManager.GetErrorMessage(lang, &AppError{Code: appErr.Code, Message: title})
+	}
+
+	problem := Problem{
+		Type:      ProblemType(baseURI, appErr.Code),
+		Title:     title,
+		Status:    appErr.HTTPStatus,
+		Detail:    appErr.Message,
+		Code:      appErr.Code,
+		Timestamp: appErr.Timestamp,
+		Metadata:  appErr.Metadata,
+	}
+	if c.Request != nil {
+		problem.Instance = requestid.FromContext(c.Request.Context())
+	}
+
+	// 在开发环境下添加详细信息，发布模式下不返回堆栈
+	if gin.Mode() == gin.DebugMode {
+		problem.Details = appErr.Details
+		problem.StackTrace = appErr.StackTrace
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(appErr.HTTPStatus, problem)
+}
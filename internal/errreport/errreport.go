@@ -0,0 +1,120 @@
+package errreport
+
+import (
+	"fmt"
+	"time"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/requestid"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+// Options 错误上报配置，DSN 为空表示关闭上报
+type Options struct {
+	DSN         string
+	Environment string
+	Release     string  // 为空时由SDK从 SENTRY_RELEASE 或构建信息推断
+	SampleRate  float64 // 0 表示全部上报
+	Transport   sentry.Transport
+}
+
+// enabled 是否已启用上报
+var enabled bool
+
+// Init 初始化Sentry（或兼容服务）客户端
+func Init(opts Options) error {
+	enabled = false
+	if opts.DSN == "" && opts.Transport == nil {
+		return nil
+	}
+
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              opts.DSN,
+		Environment:      opts.Environment,
+		Release:          opts.Release,
+		SampleRate:       opts.SampleRate,
+		AttachStacktrace: true,
+		Transport:        opts.Transport,
+	})
+	if err != nil {
+		return fmt.Errorf("init error reporting: %w", err)
+	}
+	enabled = true
+	return nil
+}
+
+// Flush 等待已上报事件发送完成，用于服务关闭
+func Flush(timeout time.Duration) {
+	if enabled {
+		sentry.Flush(timeout)
+	}
+}
+
+// ReportAppError 上报高级别和严重级别的应用错误，低级别错误只记录日志
+func ReportAppError(c *gin.Context, appErr *errors.AppError) {
+	if !enabled || (appErr.Severity != errors.SeverityHigh && appErr.Severity != errors.SeverityCritical) {
+		return
+	}
+
+	level := sentry.LevelError
+	if appErr.Severity == errors.SeverityCritical {
+		level = sentry.LevelFatal
+	}
+
+	hub := sentry.CurrentHub().Clone()
+	hub.WithScope(func(scope *sentry.Scope) {
+		applyRequestScope(scope, c)
+		scope.SetLevel(level)
+		scope.SetTag("error_code", string(appErr.Code))
+		scope.SetTag("severity", string(appErr.Severity))
+		scope.SetFingerprint([]string{"{{ default }}", string(appErr.Code)})
+
+		details := sentry.Context{
+			"http_status": appErr.HTTPStatus,
+		}
+		if appErr.Details != "" {
+			details["details"] = appErr.Details
+		}
+		if len(appErr.Metadata) > 0 {
+			details["metadata"] = appErr.Metadata
+		}
+		if len(appErr.StackTrace) > 0 {
+			details["stack_trace"] = appErr.StackTrace
+		}
+		scope.SetContext("app_error", details)
+
+		hub.CaptureException(appErr)
+	})
+}
+
+// ReportPanic 上报请求处理中的panic
+func ReportPanic(c *gin.Context, recovered interface{}) {
+	if !enabled {
+		return
+	}
+
+	hub := sentry.CurrentHub().Clone()
+	hub.WithScope(func(scope *sentry.Scope) {
+		applyRequestScope(scope, c)
+		scope.SetLevel(sentry.LevelFatal)
+		scope.SetTag("error_code", "PANIC")
+		hub.Recover(recovered)
+	})
+}
+
+// applyRequestScope 添加请求上下文，敏感请求头由SDK过滤
+func applyRequestScope(scope *sentry.Scope, c *gin.Context) {
+	if c == nil || c.Request == nil {
+		return
+	}
+	scope.SetRequest(c.Request)
+	scope.SetTag("route", c.FullPath())
+	if requestID := requestid.FromContext(c.Request.Context()); requestID != "" {
+		scope.SetTag("request_id", requestID)
+	}
+	if userID := c.GetString("user_id"); userID != "" {
+		scope.SetUser(sentry.User{ID: userID})
+	}
+}
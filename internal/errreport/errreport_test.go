@@ -0,0 +1,71 @@
+package errreport
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/requestid"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTransport 记录发送的事件
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Flush(time.Duration) bool       { return true }
+func (t *fakeTransport) Configure(sentry.ClientOptions) {}
+func (t *fakeTransport) Close()                         {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestReportAppError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *errors.AppError
+		reported bool
+	}{
+		{name: "Low severity skipped", err: errors.NewValidationError("bad input")},
+		{name: "High severity reported", err: errors.NewInternalError("boom"), reported: true},
+		{name: "Critical severity reported", err: errors.NewDatabaseConnectionError(nil), reported: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{}
+			if err := Init(Options{Transport: transport, Release: "v1.2.3"}); err != nil {
+				t.Fatalf("init: %v", err)
+			}
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/v1/assistant/chat", nil)
+			c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), "req-1"))
+			c.Set("user_id", "42")
+
+			ReportAppError(c, tt.err)
+
+			if !tt.reported {
+				if len(transport.events) != 0 {
+					t.Fatalf("expected no events, got %d", len(transport.events))
+				}
+				return
+			}
+			if len(transport.events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(transport.events))
+			}
+			event := transport.events[0]
+			if event.Tags["error_code"] != string(tt.err.Code) || event.Tags["request_id"] != "req-1" || event.User.ID != "42" || event.Release != "v1.2.3" {
+				t.Errorf("unexpected event tags %v, user %q, release %q", event.Tags, event.User.ID, event.Release)
+			}
+		})
+	}
+}
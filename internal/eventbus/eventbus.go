@@ -0,0 +1,219 @@
+// Package eventbus 将服务端的领域事件发布到 NATS 或 Kafka，供下游分析管道消费。
+//
+// 事件先进入内存队列，由后台协程按 <topic_prefix><事件类型> 发布到对应主题，
+// 队列满或发送失败时丢弃并记录日志，不影响请求处理；未配置后端时发布为空操作。
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 支持的消息后端
+const (
+	BackendNone  = "" // 默认：不发布
+	BackendNATS  = "nats"
+	BackendKafka = "kafka"
+)
+
+// 默认值
+const (
+	defaultBufferSize = 1024
+	defaultTimeout    = 5 * time.Second
+	closeTimeout      = 10 * time.Second
+)
+
+// Publisher 发布事件，实现需保证不阻塞调用方也不返回错误
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{})
+}
+
+// Bus 事件总线，关闭时尽量发送完队列中的事件
+type Bus interface {
+	Publisher
+	Close() error
+}
+
+// Message 发布到主题的消息，序列化后即为消息体
+type Message struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Source    string      `json:"source,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Config 事件总线配置
+type Config struct {
+	Backend     string
+	TopicPrefix string // 主题前缀，如 go-springai.，主题为前缀加事件类型
+	Source      string // 写入消息的 source 字段，用于区分实例
+	BufferSize  int    // 内存队列长度
+	Timeout     time.Duration
+	NATS        NATSConfig
+	Kafka       KafkaConfig
+}
+
+// sink 消息后端，key 用于去重或分区
+type sink interface {
+	send(ctx context.Context, topic string, key, value []byte) error
+	close() error
+}
+
+// New 根据配置创建事件总线，未配置后端时返回空操作实现
+func New(cfg Config, logger *zap.Logger) (Bus, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	var s sink
+	var err error
+	switch cfg.Backend {
+	case BackendNone:
+		return Noop{}, nil
+	case BackendNATS:
+		s, err = newNATSSink(cfg.NATS, cfg.Timeout, logger)
+	case BackendKafka:
+		s, err = newKafkaSink(cfg.Kafka, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported event bus backend: %s", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newAsyncBus(s, cfg, logger), nil
+}
+
+// Noop 不发布任何事件
+type Noop struct{}
+
+// Publish 忽略事件
+func (Noop) Publish(ctx context.Context, eventType string, data interface{}) {}
+
+// Close 无需释放资源
+func (Noop) Close() error { return nil }
+
+// Fanout 将事件依次交给多个发布者，忽略 nil
+func Fanout(publishers ...Publisher) Publisher {
+	var targets fanout
+	for _, p := range publishers {
+		if p != nil {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+type fanout []Publisher
+
+func (f fanout) Publish(ctx context.Context, eventType string, data interface{}) {
+	for _, p := range f {
+		p.Publish(ctx, eventType, data)
+	}
+}
+
+// envelope 等待发送的消息
+type envelope struct {
+	topic string
+	key   []byte
+	value []byte
+}
+
+// asyncBus 通过内存队列异步发送消息，单个协程按发布顺序发送
+type asyncBus struct {
+	sink    sink
+	prefix  string
+	source  string
+	timeout time.Duration
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan envelope
+	done   chan struct{}
+}
+
+func newAsyncBus(s sink, cfg Config, logger *zap.Logger) *asyncBus {
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	b := &asyncBus{
+		sink:    s,
+		prefix:  cfg.TopicPrefix,
+		source:  cfg.Source,
+		timeout: cfg.Timeout,
+		logger:  logger,
+		now:     time.Now,
+		queue:   make(chan envelope, size),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Publish 序列化事件并放入队列，队列已满或总线已关闭时丢弃
+func (b *asyncBus) Publish(ctx context.Context, eventType string, data interface{}) {
+	msg := Message{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		Source:    b.source,
+		CreatedAt: b.now().UTC(),
+		Data:      data,
+	}
+	value, err := json.Marshal(msg)
+	if err != nil {
+		b.logger.Error("序列化事件失败", zap.String("event_type", eventType), zap.Error(err))
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.queue <- envelope{topic: b.prefix + eventType, key: []byte(msg.ID), value: value}:
+	default:
+		b.logger.Warn("事件队列已满，丢弃事件", zap.String("event_type", eventType), zap.String("event_id", msg.ID))
+	}
+}
+
+// Close 停止接收事件，等待队列中的事件发送完毕后关闭后端连接
+func (b *asyncBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(closeTimeout):
+		b.logger.Warn("等待事件发送超时，剩余事件被丢弃", zap.Int("pending", len(b.queue)))
+	}
+	return b.sink.close()
+}
+
+// run 按顺序发送队列中的消息，失败时只记录日志
+func (b *asyncBus) run() {
+	defer close(b.done)
+	for env := range b.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+		err := b.sink.send(ctx, env.topic, env.key, env.value)
+		cancel()
+		if err != nil {
+			b.logger.Warn("发布事件失败", zap.String("topic", env.topic), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingSink 记录发送的消息，fail 为 true 时返回错误
+type recordingSink struct {
+	mu       sync.Mutex
+	messages []envelope
+	fail     bool
+	closed   bool
+}
+
+func (s *recordingSink) send(ctx context.Context, topic string, key, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("unavailable")
+	}
+	s.messages = append(s.messages, envelope{topic: topic, key: key, value: value})
+	return nil
+}
+
+func (s *recordingSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestAsyncBusPublish(t *testing.T) {
+	sink := &recordingSink{}
+	bus := newAsyncBus(sink, Config{TopicPrefix: "go-springai.", Source: "node-1", Timeout: time.Second}, zap.NewNop())
+
+	bus.Publish(context.Background(), "user.created", map[string]int64{"user_id": 7})
+	bus.Publish(context.Background(), "chat.completed", map[string]string{"model": "gpt-4o"})
+	require.NoError(t, bus.Close())
+
+	// 关闭后发布的事件被丢弃
+	bus.Publish(context.Background(), "user.created", nil)
+
+	require.Len(t, sink.messages, 2)
+	assert.True(t, sink.closed)
+	assert.Equal(t, "go-springai.user.created", sink.messages[0].topic)
+	assert.Equal(t, "go-springai.chat.completed", sink.messages[1].topic)
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(sink.messages[0].value, &msg))
+	assert.Equal(t, "user.created", msg.Type)
+	assert.Equal(t, "node-1", msg.Source)
+	assert.Equal(t, string(sink.messages[0].key), msg.ID)
+	assert.Equal(t, map[string]interface{}{"user_id": float64(7)}, msg.Data)
+}
+
+func TestAsyncBusSendFailureDoesNotBlock(t *testing.T) {
+	sink := &recordingSink{fail: true}
+	bus := newAsyncBus(sink, Config{BufferSize: 1, Timeout: time.Second}, zap.NewNop())
+
+	for i := 0; i < 10; i++ {
+		bus.Publish(context.Background(), "tool.executed", nil)
+	}
+	require.NoError(t, bus.Close())
+	assert.Empty(t, sink.messages)
+}
+
+func TestNew(t *testing.T) {
+	bus, err := New(Config{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, Noop{}, bus)
+
+	_, err = New(Config{Backend: "rabbitmq"}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,464 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// KafkaConfig Kafka 生产者配置，仅支持明文连接
+type KafkaConfig struct {
+	Brokers  []string // 启动时连接的 broker 地址，如 localhost:9092
+	ClientID string
+	Acks     int // 1：leader 写入即确认；-1：所有同步副本写入后确认
+}
+
+// Kafka 协议的 API 编号和使用的版本，Produce v3 与 Metadata v4 自 Kafka 1.0 起可用
+const (
+	kafkaAPIProduce       int16 = 0
+	kafkaAPIMetadata      int16 = 3
+	kafkaProduceVersion   int16 = 3
+	kafkaMetadataVersion  int16 = 4
+	kafkaRecordBatchMagic int8  = 2
+	kafkaMaxResponseSize        = 64 << 20
+	kafkaDefaultClientID        = "go-springai"
+	kafkaErrNone          int16 = 0
+	kafkaNoLeader         int32 = -1
+	kafkaNullLength       int32 = -1
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaError broker 返回的错误码
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	return "kafka error code " + strconv.Itoa(int(e))
+}
+
+// kafkaSink 最小化的 Kafka 生产者：按消息 key 的哈希选择分区，每条消息一个未压缩的 RecordBatch。
+// 发送失败时清空元数据和连接后重试一次
+type kafkaSink struct {
+	brokers  []string
+	clientID string
+	acks     int16
+	timeout  time.Duration
+	dialer   net.Dialer
+
+	mu            sync.Mutex
+	correlationID int32
+	nodes         map[int32]string   // broker ID -> 地址
+	leaders       map[string][]int32 // 主题 -> 按分区号排列的 leader broker ID
+	conns         map[int32]net.Conn
+}
+
+func newKafkaSink(cfg KafkaConfig, timeout time.Duration) (*kafkaSink, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("kafka brokers are required")
+	}
+	acks := cfg.Acks
+	if acks == 0 {
+		acks = 1
+	}
+	if acks != 1 && acks != -1 {
+		return nil, fmt.Errorf("unsupported kafka acks: %d", cfg.Acks)
+	}
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = kafkaDefaultClientID
+	}
+	return &kafkaSink{
+		brokers:  cfg.Brokers,
+		clientID: clientID,
+		acks:     int16(acks),
+		timeout:  timeout,
+		dialer:   net.Dialer{Timeout: timeout},
+		nodes:    make(map[int32]string),
+		leaders:  make(map[string][]int32),
+		conns:    make(map[int32]net.Conn),
+	}, nil
+}
+
+func (s *kafkaSink) send(ctx context.Context, topic string, key, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.produce(ctx, topic, key, value)
+	if err != nil && ctx.Err() == nil {
+		s.reset()
+		err = s.produce(ctx, topic, key, value)
+	}
+	return err
+}
+
+func (s *kafkaSink) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+	return nil
+}
+
+// reset 关闭所有连接并清空元数据缓存
+func (s *kafkaSink) reset() {
+	for id, conn := range s.conns {
+		conn.Close()
+		delete(s.conns, id)
+	}
+	s.nodes = make(map[int32]string)
+	s.leaders = make(map[string][]int32)
+}
+
+// produce 向分区 leader 写入一条消息并检查响应中的错误码
+func (s *kafkaSink) produce(ctx context.Context, topic string, key, value []byte) error {
+	leaders, err := s.partitionLeaders(ctx, topic)
+	if err != nil {
+		return err
+	}
+	partition := int32(crc32.ChecksumIEEE(key) % uint32(len(leaders)))
+	leader := leaders[partition]
+	addr, ok := s.nodes[leader]
+	if !ok {
+		return fmt.Errorf("kafka broker %d not found in metadata", leader)
+	}
+	conn, err := s.conn(ctx, leader, addr)
+	if err != nil {
+		return err
+	}
+
+	var w kafkaWriter
+	w.int16(-1) // transactional_id: null
+	w.int16(s.acks)
+	w.int32(int32(s.timeout / time.Millisecond))
+	w.int32(1)
+	w.string(topic)
+	w.int32(1)
+	w.int32(partition)
+	w.bytes(encodeRecordBatch(key, value, time.Now()))
+
+	resp, err := s.roundTrip(ctx, conn, kafkaAPIProduce, kafkaProduceVersion, w.buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	r := kafkaReader{buf: resp}
+	for i, topics := 0, r.int32(); i < int(topics); i++ {
+		r.string()
+		for j, partitions := 0, r.int32(); j < int(partitions); j++ {
+			r.int32()
+			if code := r.int16(); code != kafkaErrNone && r.err == nil {
+				return fmt.Errorf("produce to %s[%d]: %w", topic, partition, kafkaError(code))
+			}
+			r.int64() // base_offset
+			r.int64() // log_append_time
+		}
+	}
+	if r.err != nil {
+		return fmt.Errorf("decode kafka produce response: %w", r.err)
+	}
+	return nil
+}
+
+// partitionLeaders 返回主题各分区的 leader，缓存中没有时向启动 broker 请求元数据
+func (s *kafkaSink) partitionLeaders(ctx context.Context, topic string) ([]int32, error) {
+	if leaders, ok := s.leaders[topic]; ok {
+		return leaders, nil
+	}
+
+	var lastErr error
+	for _, addr := range s.brokers {
+		leaders, err := s.fetchMetadata(ctx, addr, topic)
+		if err == nil {
+			s.leaders[topic] = leaders
+			return leaders, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("fetch kafka metadata for %s: %w", topic, lastErr)
+}
+
+// fetchMetadata 请求单个主题的元数据，记录 broker 地址并返回各分区 leader
+func (s *kafkaSink) fetchMetadata(ctx context.Context, addr, topic string) ([]int32, error) {
+	conn, err := s.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var w kafkaWriter
+	w.int32(1)
+	w.string(topic)
+	w.int8(1) // allow_auto_topic_creation
+	resp, err := s.roundTrip(ctx, conn, kafkaAPIMetadata, kafkaMetadataVersion, w.buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	r := kafkaReader{buf: resp}
+	r.int32() // throttle_time_ms
+	nodes := make(map[int32]string)
+	for i, n := 0, r.int32(); i < int(n); i++ {
+		id := r.int32()
+		host := r.string()
+		port := r.int32()
+		r.string() // rack
+		nodes[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	r.string() // cluster_id
+	r.int32()  // controller_id
+
+	var leaders []int32
+	for i, n := 0, r.int32(); i < int(n); i++ {
+		topicErr := r.int16()
+		name := r.string()
+		r.int8() // is_internal
+		partitions := make(map[int32]int32)
+		for j, m := 0, r.int32(); j < int(m); j++ {
+			r.int16() // 分区错误码，以 leader 是否存在为准
+			index := r.int32()
+			partitions[index] = r.int32()
+			r.int32Array() // replica_nodes
+			r.int32Array() // isr_nodes
+		}
+		if r.err != nil || name != topic {
+			continue
+		}
+		if topicErr != kafkaErrNone {
+			return nil, kafkaError(topicErr)
+		}
+		leaders = make([]int32, len(partitions))
+		for index, leader := range partitions {
+			if index < 0 || int(index) >= len(leaders) || leader == kafkaNoLeader {
+				return nil, fmt.Errorf("kafka partition %d of %s has no leader", index, topic)
+			}
+			leaders[index] = leader
+		}
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("decode kafka metadata response: %w", r.err)
+	}
+	if len(leaders) == 0 {
+		return nil, fmt.Errorf("kafka topic %s has no partitions", topic)
+	}
+
+	for id, nodeAddr := range nodes {
+		s.nodes[id] = nodeAddr
+	}
+	return leaders, nil
+}
+
+// conn 返回到 broker 的复用连接
+func (s *kafkaSink) conn(ctx context.Context, id int32, addr string) (net.Conn, error) {
+	if conn, ok := s.conns[id]; ok {
+		return conn, nil
+	}
+	conn, err := s.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[id] = conn
+	return conn, nil
+}
+
+func (s *kafkaSink) dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := s.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to kafka broker %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// roundTrip 发送请求并读取对应的响应，返回去掉响应头后的内容
+func (s *kafkaSink) roundTrip(ctx context.Context, conn net.Conn, apiKey, version int16, body []byte) ([]byte, error) {
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	s.correlationID++
+	correlationID := s.correlationID
+
+	var w kafkaWriter
+	w.int32(0) // 长度占位
+	w.int16(apiKey)
+	w.int16(version)
+	w.int32(correlationID)
+	w.string(s.clientID)
+	w.buf.Write(body)
+	req := w.buf.Bytes()
+	binary.BigEndian.PutUint32(req, uint32(len(req)-4))
+	if _, err := conn.Write(req); err != nil {
+		return nil, fmt.Errorf("write kafka request: %w", err)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, fmt.Errorf("read kafka response: %w", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > kafkaMaxResponseSize {
+		return nil, fmt.Errorf("invalid kafka response size %d", n)
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, fmt.Errorf("read kafka response: %w", err)
+	}
+	if got := int32(binary.BigEndian.Uint32(resp)); got != correlationID {
+		return nil, fmt.Errorf("kafka correlation id mismatch: got %d, want %d", got, correlationID)
+	}
+	return resp[4:], nil
+}
+
+// encodeRecordBatch 编码只含一条记录的 v2 RecordBatch
+func encodeRecordBatch(key, value []byte, now time.Time) []byte {
+	var record kafkaWriter
+	record.int8(0)   // attributes
+	record.varint(0) // timestamp_delta
+	record.varint(0) // offset_delta
+	record.varbytes(key)
+	record.varbytes(value)
+	record.varint(0) // headers
+
+	var body kafkaWriter // 从 attributes 开始，为 CRC 的计算范围
+	timestamp := now.UnixMilli()
+	body.int16(0) // attributes：不压缩
+	body.int32(0) // last_offset_delta
+	body.int64(timestamp)
+	body.int64(timestamp)
+	body.int64(-1) // producer_id
+	body.int16(-1) // producer_epoch
+	body.int32(-1) // base_sequence
+	body.int32(1)
+	body.varint(int64(record.buf.Len()))
+	body.buf.Write(record.buf.Bytes())
+
+	var batch kafkaWriter
+	batch.int64(0) // base_offset
+	batch.int32(int32(4 + 1 + 4 + body.buf.Len()))
+	batch.int32(-1) // partition_leader_epoch
+	batch.int8(kafkaRecordBatchMagic)
+	batch.int32(int32(crc32.Checksum(body.buf.Bytes(), castagnoli)))
+	batch.buf.Write(body.buf.Bytes())
+	return batch.buf.Bytes()
+}
+
+// kafkaWriter 按 Kafka 协议的大端格式写入基本类型
+type kafkaWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *kafkaWriter) int8(v int8) { w.buf.WriteByte(byte(v)) }
+
+func (w *kafkaWriter) int16(v int16) {
+	w.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (w *kafkaWriter) int32(v int32) {
+	w.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(v)))
+}
+
+func (w *kafkaWriter) int64(v int64) {
+	w.buf.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+}
+
+func (w *kafkaWriter) string(v string) {
+	w.int16(int16(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *kafkaWriter) bytes(v []byte) {
+	w.int32(int32(len(v)))
+	w.buf.Write(v)
+}
+
+// varint zigzag 编码的变长整数，用于记录内的字段
+func (w *kafkaWriter) varint(v int64) {
+	w.buf.Write(binary.AppendVarint(nil, v))
+}
+
+func (w *kafkaWriter) varbytes(v []byte) {
+	if v == nil {
+		w.varint(int64(kafkaNullLength))
+		return
+	}
+	w.varint(int64(len(v)))
+	w.buf.Write(v)
+}
+
+var errKafkaShortBuffer = errors.New("short buffer")
+
+// kafkaReader 读取 Kafka 响应，出错后后续读取均返回零值并保留第一个错误
+type kafkaReader struct {
+	buf []byte
+	err error
+}
+
+func (r *kafkaReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf) < n {
+		r.err = errKafkaShortBuffer
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *kafkaReader) int8() int8 {
+	if b := r.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (r *kafkaReader) int16() int16 {
+	if b := r.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int32() int32 {
+	if b := r.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int64() int64 {
+	if b := r.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string 读取可为 null 的字符串，null 返回空字符串
+func (r *kafkaReader) string() string {
+	n := r.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(r.next(int(n)))
+}
+
+func (r *kafkaReader) int32Array() {
+	n := r.int32()
+	if n > 0 {
+		r.next(int(n) * 4)
+	}
+}
@@ -0,0 +1,216 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker 只有一个分区的单节点 Kafka，记录收到的消息
+type fakeBroker struct {
+	t          *testing.T
+	listener   net.Listener
+	topicErr   int16
+	produceErr int16
+
+	mu      sync.Mutex
+	records []fakeRecord
+}
+
+type fakeRecord struct {
+	topic string
+	key   []byte
+	value []byte
+}
+
+func newFakeBroker(t *testing.T, topicErr, produceErr int16) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &fakeBroker{t: t, listener: listener, topicErr: topicErr, produceErr: produceErr}
+	t.Cleanup(func() { listener.Close() })
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		r := kafkaReader{buf: req}
+		apiKey := r.int16()
+		r.int16() // version
+		correlationID := r.int32()
+		r.string() // client_id
+
+		var w kafkaWriter
+		w.int32(0)
+		w.int32(correlationID)
+		switch apiKey {
+		case kafkaAPIMetadata:
+			b.metadata(&r, &w)
+		case kafkaAPIProduce:
+			b.produce(&r, &w)
+		default:
+			b.t.Errorf("unexpected api key %d", apiKey)
+			return
+		}
+		resp := w.buf.Bytes()
+		binary.BigEndian.PutUint32(resp, uint32(len(resp)-4))
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) metadata(r *kafkaReader, w *kafkaWriter) {
+	r.int32()
+	topic := r.string()
+
+	host, port, _ := net.SplitHostPort(b.listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	w.int32(0) // throttle_time_ms
+	w.int32(1)
+	w.int32(7) // node_id
+	w.string(host)
+	w.int32(int32(portNum))
+	w.int16(-1) // rack
+	w.int16(-1) // cluster_id
+	w.int32(7)  // controller_id
+	w.int32(1)
+	w.int16(b.topicErr)
+	w.string(topic)
+	w.int8(0)
+	w.int32(1)
+	w.int16(0)
+	w.int32(0) // partition_index
+	w.int32(7) // leader_id
+	w.int32(1)
+	w.int32(7)
+	w.int32(1)
+	w.int32(7)
+}
+
+func (b *fakeBroker) produce(r *kafkaReader, w *kafkaWriter) {
+	r.string() // transactional_id
+	r.int16()  // acks
+	r.int32()  // timeout_ms
+	r.int32()
+	topic := r.string()
+	r.int32()
+	partition := r.int32()
+	batch := r.next(int(r.int32()))
+	require.NoError(b.t, r.err)
+
+	// RecordBatch 头部共 61 字节，CRC 覆盖 attributes 之后的全部内容
+	require.GreaterOrEqual(b.t, len(batch), 61)
+	assert.Equal(b.t, byte(kafkaRecordBatchMagic), batch[16])
+	assert.Equal(b.t, binary.BigEndian.Uint32(batch[17:21]), crc32.Checksum(batch[21:], castagnoli), "crc")
+	assert.Equal(b.t, int32(len(batch)-12), int32(binary.BigEndian.Uint32(batch[8:12])), "batch length")
+
+	record := batch[61:]
+	varint := func() int64 {
+		v, n := binary.Varint(record)
+		record = record[n:]
+		return v
+	}
+	varint()            // length
+	record = record[1:] // attributes
+	varint()            // timestamp_delta
+	varint()            // offset_delta
+	keyLen := varint()
+	key := record[:keyLen]
+	record = record[keyLen:]
+	valueLen := varint()
+	value := record[:valueLen]
+
+	if b.produceErr == kafkaErrNone {
+		b.mu.Lock()
+		b.records = append(b.records, fakeRecord{topic: topic, key: append([]byte(nil), key...), value: append([]byte(nil), value...)})
+		b.mu.Unlock()
+	}
+
+	w.int32(1)
+	w.string(topic)
+	w.int32(1)
+	w.int32(partition)
+	w.int16(b.produceErr)
+	w.int64(0)  // base_offset
+	w.int64(-1) // log_append_time
+	w.int32(0)  // throttle_time_ms
+}
+
+func TestKafkaSinkSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		topicErr   int16
+		produceErr int16
+		wantErr    string
+	}{
+		{name: "delivers record"},
+		{name: "topic error", topicErr: 3, wantErr: "kafka error code 3"},
+		{name: "produce error", produceErr: 6, wantErr: "kafka error code 6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newFakeBroker(t, tt.topicErr, tt.produceErr)
+			sink, err := newKafkaSink(KafkaConfig{Brokers: []string{broker.listener.Addr().String()}}, time.Second)
+			require.NoError(t, err)
+			defer sink.close()
+
+			ctx := context.Background()
+			for _, value := range []string{`{"n":1}`, `{"n":2}`} {
+				err = sink.send(ctx, "go-springai.tool.executed", []byte("event-id"), []byte(value))
+				if tt.wantErr != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+			}
+
+			broker.mu.Lock()
+			defer broker.mu.Unlock()
+			require.Len(t, broker.records, 2)
+			assert.Equal(t, "go-springai.tool.executed", broker.records[0].topic)
+			assert.Equal(t, "event-id", string(broker.records[0].key))
+			assert.Equal(t, `{"n":1}`, string(broker.records[0].value))
+			assert.Equal(t, `{"n":2}`, string(broker.records[1].value))
+		})
+	}
+}
+
+func TestNewKafkaSinkRejectsInvalidConfig(t *testing.T) {
+	_, err := newKafkaSink(KafkaConfig{}, time.Second)
+	assert.Error(t, err)
+
+	_, err = newKafkaSink(KafkaConfig{Brokers: []string{"localhost:9092"}, Acks: 2}, time.Second)
+	assert.Error(t, err)
+}
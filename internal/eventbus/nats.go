@@ -0,0 +1,78 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// NATSConfig NATS 连接配置，用户名密码和令牌二选一
+type NATSConfig struct {
+	URL      string // 多个地址用逗号分隔
+	Username string
+	Password string
+	Token    string
+}
+
+// natsSink 以主题为 subject 发布消息，消息ID写入 Nats-Msg-Id 头，JetStream 可据此去重
+type natsSink struct {
+	conn    *nats.Conn
+	timeout time.Duration
+}
+
+// newNATSSink 连接 NATS，服务器暂不可用时在后台重连，期间的消息由客户端缓冲
+func newNATSSink(cfg NATSConfig, timeout time.Duration, logger *zap.Logger) (*natsSink, error) {
+	url := cfg.URL
+	if url == "" {
+		url = nats.DefaultURL
+	}
+
+	opts := []nats.Option{
+		nats.Name("go-springai"),
+		nats.Timeout(timeout),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("NATS连接断开", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.Info("NATS已重新连接", zap.String("url", nc.ConnectedUrl()))
+		}),
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.Token != "" {
+		opts = append(opts, nats.Token(cfg.Token))
+	}
+
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+	return &natsSink{conn: conn, timeout: timeout}, nil
+}
+
+func (s *natsSink) send(ctx context.Context, topic string, key, value []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Data = value
+	if len(key) > 0 {
+		msg.Header.Set(nats.MsgIdHdr, string(key))
+	}
+	return s.conn.PublishMsg(msg)
+}
+
+// close 发送客户端缓冲的消息后断开连接
+func (s *natsSink) close() error {
+	err := s.conn.FlushTimeout(s.timeout)
+	s.conn.Close()
+	if err != nil && err != nats.ErrConnectionClosed {
+		return fmt.Errorf("flush nats: %w", err)
+	}
+	return nil
+}
@@ -2,11 +2,19 @@ package googleai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
+	"go-springAi/internal/capture"
+	"go-springAi/internal/chaos"
+	"go-springAi/internal/requestid"
+	"go-springAi/internal/types"
+	"go-springAi/internal/vcr"
+
 	"google.golang.org/genai"
 )
 
@@ -49,8 +57,9 @@ func (c *HTTPClient) ensureClient(ctx context.Context) error {
 
 	// 创建 Google AI 客户端
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: newHTTPClient(),
 	})
 	if err != nil {
 		return fmt.Errorf("create Google AI client: %w", err)
@@ -60,6 +69,46 @@ func (c *HTTPClient) ensureClient(ctx context.Context) error {
 	return nil
 }
 
+// clientFor 获取本次请求使用的客户端，上下文中指定了API密钥时创建临时客户端
+func (c *HTTPClient) clientFor(ctx context.Context) (*genai.Client, error) {
+	if apiKey, ok := types.APIKeyFromContext(ctx); ok {
+		client, err := genai.NewClient(ctx, &genai.ClientConfig{
+			APIKey:     apiKey,
+			Backend:    genai.BackendGeminiAPI,
+			HTTPClient: newHTTPClient(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create Google AI client: %w", err)
+		}
+		return client, nil
+	}
+
+	if err := c.ensureClient(ctx); err != nil {
+		return nil, err
+	}
+	return c.client, nil
+}
+
+// newHTTPClient 创建转发请求ID、支持故障注入和录制回放的HTTP客户端
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: &chaos.Transport{Base: &capture.Transport{Provider: "googleai", Base: &vcr.Transport{Base: &requestid.Transport{}}}}}
+}
+
+// wrapAPIError 鉴权失败时包装 types.ErrProviderUnauthorized，限流时包装 types.ErrProviderRateLimited
+func wrapAPIError(err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.Code == http.StatusUnauthorized, apiErr.Code == http.StatusForbidden,
+			apiErr.Code == http.StatusBadRequest && strings.Contains(apiErr.Message, "API key"):
+			return fmt.Errorf("%w: %w", types.ErrProviderUnauthorized, err)
+		case apiErr.Code == http.StatusTooManyRequests:
+			return fmt.Errorf("%w: %w", types.ErrProviderRateLimited, err)
+		}
+	}
+	return err
+}
+
 // ResetClient 重置客户端，强制重新初始化
 func (c *HTTPClient) ResetClient() {
 	c.client = nil
@@ -68,8 +117,9 @@ func (c *HTTPClient) ResetClient() {
 
 // ChatCompletion 实现聊天完成
 func (c *HTTPClient) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	// 确保客户端已初始化
-	if err := c.ensureClient(ctx); err != nil {
+	// 获取客户端
+	client, err := c.clientFor(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -110,9 +160,9 @@ func (c *HTTPClient) ChatCompletion(ctx context.Context, req *ChatRequest) (*Cha
 	}
 
 	// 生成内容
-	resp, err := c.client.Models.GenerateContent(ctx, req.Model, contents, config)
+	resp, err := client.Models.GenerateContent(ctx, req.Model, contents, config)
 	if err != nil {
-		return nil, fmt.Errorf("generate content: %w", err)
+		return nil, fmt.Errorf("generate content: %w", wrapAPIError(err))
 	}
 
 	// 转换响应格式
@@ -156,8 +206,9 @@ func (c *HTTPClient) ChatCompletion(ctx context.Context, req *ChatRequest) (*Cha
 
 // ChatCompletionStream 实现流式聊天完成
 func (c *HTTPClient) ChatCompletionStream(ctx context.Context, req *ChatRequest) (io.ReadCloser, error) {
-	// 确保客户端已初始化
-	if err := c.ensureClient(ctx); err != nil {
+	// 获取客户端
+	client, err := c.clientFor(ctx)
+	if err != nil {
 		return nil, err
 	}
 
@@ -198,7 +249,7 @@ func (c *HTTPClient) ChatCompletionStream(ctx context.Context, req *ChatRequest)
 	}
 
 	// 生成流式内容
-	iter := c.client.Models.GenerateContentStream(ctx, req.Model, contents, config)
+	iter := client.Models.GenerateContentStream(ctx, req.Model, contents, config)
 	
 	// 创建流式读取器
 	return NewStreamReader(iter, req.Model), nil
@@ -247,18 +298,19 @@ func (c *HTTPClient) ListModels(ctx context.Context) ([]string, error) {
 	return models, nil
 }
 
-// ValidateAPIKey 验证API密钥
+// ValidateAPIKey 验证API密钥，上下文中指定的密钥优先
 func (c *HTTPClient) ValidateAPIKey(ctx context.Context) error {
-	// 确保客户端已初始化
-	if err := c.ensureClient(ctx); err != nil {
+	// 获取客户端
+	client, err := c.clientFor(ctx)
+	if err != nil {
 		return err
 	}
 
 	// 尝试调用实际的Google AI API来验证密钥
 	// 使用一个简单的模型列表请求来测试API密钥的有效性
-	_, err := c.client.Models.List(ctx, &genai.ListModelsConfig{})
+	_, err = client.Models.List(ctx, &genai.ListModelsConfig{})
 	if err != nil {
-		return fmt.Errorf("API key validation failed: %w", err)
+		return fmt.Errorf("API key validation failed: %w", wrapAPIError(err))
 	}
 	
 	return nil
@@ -79,6 +79,7 @@ type ModelConfig struct {
 	TopP        float32 `json:"top_p"`
 	TopK        int     `json:"top_k"`
 	Enabled     bool    `json:"enabled"`
+	Unavailable bool    `json:"unavailable,omitempty"` // 提供商已不再提供该模型
 }
 
 // Client Google AI 客户端接口
@@ -0,0 +1,201 @@
+// Package googleauth 使用服务账号密钥获取 Google API 的访问令牌。
+//
+// 按 OAuth 2.0 JWT Bearer 流程用服务账号私钥签名断言并换取访问令牌，令牌在过期前缓存复用。
+package googleauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/vcr"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultTokenURI 服务账号密钥未指定 token_uri 时使用的令牌地址
+const DefaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// 常用的授权范围
+const (
+	ScopeSpreadsheets = "https://www.googleapis.com/auth/spreadsheets"
+	ScopeDriveFile    = "https://www.googleapis.com/auth/drive.file"
+	ScopeCalendar     = "https://www.googleapis.com/auth/calendar.events"
+)
+
+// tokenRefreshMargin 令牌剩余有效期小于该值时重新获取
+const tokenRefreshMargin = time.Minute
+
+// serviceAccountKey 服务账号 JSON 密钥中用到的字段
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// TokenSource 服务账号访问令牌来源，可并发使用
+type TokenSource struct {
+	clientEmail string
+	subject     string
+	tokenURI    string
+	scopes      []string
+	signer      interface{}
+	httpClient  *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewTokenSource 解析服务账号 JSON 密钥，subject 不为空时以域范围授权代表该用户访问
+func NewTokenSource(credentials []byte, scopes []string, subject string) (*TokenSource, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal(credentials, &key); err != nil {
+		return nil, fmt.Errorf("googleauth: parse credentials: %w", err)
+	}
+	if key.Type != "" && key.Type != "service_account" {
+		return nil, fmt.Errorf("googleauth: credentials type %q is not service_account", key.Type)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, errors.New("googleauth: credentials missing client_email or private_key")
+	}
+	signer, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("googleauth: parse private key: %w", err)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = DefaultTokenURI
+	}
+
+	return &TokenSource{
+		clientEmail: key.ClientEmail,
+		subject:     subject,
+		tokenURI:    key.TokenURI,
+		scopes:      scopes,
+		signer:      signer,
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}, nil
+}
+
+// ClientEmail 服务账号的邮箱
+func (s *TokenSource) ClientEmail() string {
+	return s.clientEmail
+}
+
+// WithSubject 返回以域范围授权代表 subject 访问的令牌来源，与当前来源共用密钥但单独缓存令牌
+func (s *TokenSource) WithSubject(subject string) *TokenSource {
+	return &TokenSource{
+		clientEmail: s.clientEmail,
+		subject:     subject,
+		tokenURI:    s.tokenURI,
+		scopes:      s.scopes,
+		signer:      s.signer,
+		httpClient:  s.httpClient,
+	}
+}
+
+// Token 返回有效的访问令牌，缓存的令牌快过期时重新获取
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.token != "" && now.Add(tokenRefreshMargin).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   s.clientEmail,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   s.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	if s.subject != "" {
+		claims["sub"] = s.subject
+	}
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.signer)
+	if err != nil {
+		return "", fmt.Errorf("googleauth: sign assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("googleauth: create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("googleauth: token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("googleauth: read token response: %w", err)
+	}
+	if err := json.Unmarshal(data, &payload); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("googleauth: parse token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || payload.AccessToken == "" {
+		message := strings.TrimSpace(payload.Error + ": " + payload.ErrorDescription)
+		if payload.Error == "" {
+			message = strings.TrimSpace(string(data))
+		}
+		return "", fmt.Errorf("googleauth: token endpoint returned %d: %s", resp.StatusCode, message)
+	}
+
+	s.token = payload.AccessToken
+	s.expiresAt = now.Add(time.Duration(payload.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// SubjectTokens 按 subject 缓存令牌来源，用于以域范围授权分别代表不同用户访问
+type SubjectTokens struct {
+	base *TokenSource
+
+	mu      sync.Mutex
+	sources map[string]*TokenSource
+}
+
+// NewSubjectTokens 创建按 subject 缓存的令牌来源
+func NewSubjectTokens(base *TokenSource) *SubjectTokens {
+	return &SubjectTokens{base: base, sources: make(map[string]*TokenSource)}
+}
+
+// Token 返回代表 subject 的访问令牌，subject 为空时使用服务账号自身
+func (t *SubjectTokens) Token(ctx context.Context, subject string) (string, error) {
+	if subject == "" {
+		return t.base.Token(ctx)
+	}
+	t.mu.Lock()
+	source, ok := t.sources[subject]
+	if !ok {
+		source = t.base.WithSubject(subject)
+		t.sources[subject] = source
+	}
+	t.mu.Unlock()
+	return source.Token(ctx)
+}
@@ -0,0 +1,90 @@
+package googleauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	requests := 0
+	var subjects []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"RS256"}))
+		require.NoError(t, err)
+		assert.Equal(t, "bot@project.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, ScopeSpreadsheets+" "+ScopeDriveFile, claims["scope"])
+		assert.Equal(t, "http://"+r.Host+"/token", claims["aud"])
+		subjects = append(subjects, claims["sub"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"ya29.token","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "bot@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	source, err := NewTokenSource(credentials, []string{ScopeSpreadsheets, ScopeDriveFile}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "bot@project.iam.gserviceaccount.com", source.ClientEmail())
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "ya29.token", token)
+	}
+	assert.Equal(t, 1, requests, "token should be cached until it is about to expire")
+
+	delegated := NewSubjectTokens(source)
+	for _, subject := range []string{"ann@acme.com", "", "ann@acme.com", "bob@acme.com"} {
+		_, err := delegated.Token(context.Background(), subject)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []interface{}{nil, "ann@acme.com", "bob@acme.com"}, subjects, "each subject gets its own cached token")
+}
+
+func TestNewTokenSourceInvalidCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials string
+	}{
+		{"not json", "not json"},
+		{"wrong type", `{"type":"authorized_user","client_email":"a@b.c","private_key":"x"}`},
+		{"missing key", `{"type":"service_account","client_email":"a@b.c"}`},
+		{"bad key", `{"type":"service_account","client_email":"a@b.c","private_key":"not a pem"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenSource([]byte(tt.credentials), []string{ScopeSpreadsheets}, "")
+			assert.Error(t, err)
+		})
+	}
+}
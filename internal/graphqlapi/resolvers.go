@@ -0,0 +1,400 @@
+package graphqlapi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/provider"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// 执行日志的默认和最大条数，与HTTP接口一致
+const (
+	defaultExecutionLogLimit = 50
+	maxExecutionLogLimit     = 100
+)
+
+// queryResolver 根查询解析器
+type queryResolver struct {
+	services Services
+}
+
+// Me 当前用户
+func (r *queryResolver) Me(ctx context.Context) (*userResolver, error) {
+	return r.user(ctx, stateFromContext(ctx).userID)
+}
+
+// Users 按条件分页查询用户
+func (r *queryResolver) Users(ctx context.Context, args struct {
+	Page           int32
+	Limit          int32
+	Search         *string
+	IsActive       *bool
+	IncludeDeleted bool
+	SortBy         string
+	SortOrder      string
+}) (*userConnectionResolver, error) {
+	query := &dto.UserListQuery{
+		Page:           int64(args.Page),
+		Limit:          int64(args.Limit),
+		IsActive:       args.IsActive,
+		IncludeDeleted: args.IncludeDeleted,
+		SortBy:         strings.ToLower(args.SortBy),
+		SortOrder:      strings.ToLower(args.SortOrder),
+	}
+	if args.Search != nil {
+		query.Search = *args.Search
+	}
+
+	resp, err := r.services.Users.ListUsers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &userConnectionResolver{resp: resp, services: r.services}, nil
+}
+
+// User 按ID查询用户
+func (r *queryResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.user(ctx, id)
+}
+
+// user 查询用户，同一请求内的结果会被缓存，用户不存在时返回 nil
+func (r *queryResolver) user(ctx context.Context, id int64) (*userResolver, error) {
+	state := stateFromContext(ctx)
+	state.mu.Lock()
+	result, ok := state.users[id]
+	if !ok {
+		user, err := r.services.UserLookup.GetByID(ctx, id)
+		result = &userResult{user: user, err: err}
+		state.users[id] = result
+	}
+	state.mu.Unlock()
+
+	if result.err != nil {
+		if appErr, ok := errors.IsAppError(result.err); ok && appErr.Code == errors.ErrCodeUserNotFound {
+			return nil, nil
+		}
+		return nil, result.err
+	}
+	return &userResolver{user: result.user, services: r.services}, nil
+}
+
+// Providers 已注册的AI提供商
+func (r *queryResolver) Providers(ctx context.Context) []*providerResolver {
+	infos := r.services.Providers.ListProviders(ctx)
+	providers := make([]*providerResolver, len(infos))
+	for i, info := range infos {
+		providers[i] = &providerResolver{info: info, manager: r.services.Providers}
+	}
+	return providers
+}
+
+// Provider 按类型查询提供商，未注册时返回 nil
+func (r *queryResolver) Provider(ctx context.Context, args struct{ Type string }) *providerResolver {
+	for _, info := range r.services.Providers.ListProviders(ctx) {
+		if string(info.Type) == args.Type {
+			return &providerResolver{info: info, manager: r.services.Providers}
+		}
+	}
+	return nil
+}
+
+// ExecutionLogs 最近的执行日志
+func (r *queryResolver) ExecutionLogs(ctx context.Context, args struct {
+	UserID *graphql.ID
+	Limit  int32
+}) ([]*executionLogResolver, error) {
+	var userID *string
+	if args.UserID != nil {
+		id := string(*args.UserID)
+		userID = &id
+	}
+	return r.executionLogs(ctx, userID, args.Limit)
+}
+
+// executionLogs 查询执行日志，limit 超出范围时使用默认值
+func (r *queryResolver) executionLogs(ctx context.Context, userID *string, limit int32) ([]*executionLogResolver, error) {
+	n := defaultExecutionLogLimit
+	if limit > 0 && limit <= maxExecutionLogLimit {
+		n = int(limit)
+	}
+	logs, err := r.services.MCP.ListExecutionLogs(ctx, userID, n)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*executionLogResolver, len(logs))
+	for i, log := range logs {
+		resolvers[i] = &executionLogResolver{log: log, query: r}
+	}
+	return resolvers, nil
+}
+
+// ExecutionLog 按ID查询执行日志，不存在时返回 nil
+func (r *queryResolver) ExecutionLog(ctx context.Context, args struct{ ID graphql.ID }) *executionLogResolver {
+	log, err := r.services.MCP.GetExecutionLog(ctx, string(args.ID))
+	if err != nil {
+		return nil
+	}
+	return &executionLogResolver{log: log, query: r}
+}
+
+// userResolver 用户
+type userResolver struct {
+	user     *dto.UserResponse
+	services Services
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.user.ID, 10))
+}
+
+func (r *userResolver) Username() string {
+	return r.user.Username
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) FullName() *string {
+	return r.user.FullName
+}
+
+func (r *userResolver) IsActive() bool {
+	return r.user.IsActive
+}
+
+func (r *userResolver) IsAdmin() bool {
+	return r.user.IsAdmin
+}
+
+func (r *userResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.user.CreatedAt}
+}
+
+func (r *userResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.user.UpdatedAt}
+}
+
+func (r *userResolver) DeletedAt() *graphql.Time {
+	if r.user.DeletedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.user.DeletedAt}
+}
+
+// ExecutionLogs 该用户最近的执行日志
+func (r *userResolver) ExecutionLogs(ctx context.Context, args struct{ Limit int32 }) ([]*executionLogResolver, error) {
+	userID := strconv.FormatInt(r.user.ID, 10)
+	return (&queryResolver{services: r.services}).executionLogs(ctx, &userID, args.Limit)
+}
+
+// userConnectionResolver 用户分页结果
+type userConnectionResolver struct {
+	resp     *dto.UserListResponse
+	services Services
+}
+
+func (r *userConnectionResolver) Users() []*userResolver {
+	users := make([]*userResolver, len(r.resp.Users))
+	for i, user := range r.resp.Users {
+		users[i] = &userResolver{user: user, services: r.services}
+	}
+	return users
+}
+
+func (r *userConnectionResolver) Total() int32 {
+	return int32(r.resp.Total)
+}
+
+func (r *userConnectionResolver) Page() int32 {
+	return int32(r.resp.Page)
+}
+
+func (r *userConnectionResolver) Limit() int32 {
+	return int32(r.resp.Limit)
+}
+
+// providerResolver 提供商
+type providerResolver struct {
+	info    provider.ProviderInfo
+	manager *provider.Manager
+}
+
+func (r *providerResolver) Type() string {
+	return string(r.info.Type)
+}
+
+func (r *providerResolver) Name() string {
+	return r.info.Name
+}
+
+func (r *providerResolver) Description() string {
+	return r.info.Description
+}
+
+func (r *providerResolver) Healthy() bool {
+	return r.info.Healthy
+}
+
+func (r *providerResolver) ModelCount() int32 {
+	return int32(r.info.ModelCount)
+}
+
+// Models 按名称排序的模型
+func (r *providerResolver) Models(ctx context.Context, args struct{ IncludeDisabled bool }) ([]*modelResolver, error) {
+	prov, err := r.manager.GetProvider(r.info.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var models map[string]*provider.ModelConfig
+	if args.IncludeDisabled {
+		models, err = prov.ListAllModels(ctx)
+	} else {
+		models, err = prov.ListModels(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*modelResolver, 0, len(models))
+	for _, model := range models {
+		resolvers = append(resolvers, &modelResolver{model: model})
+	}
+	sort.Slice(resolvers, func(i, j int) bool { return resolvers[i].model.Name < resolvers[j].model.Name })
+	return resolvers, nil
+}
+
+// modelResolver 模型配置
+type modelResolver struct {
+	model *provider.ModelConfig
+}
+
+func (r *modelResolver) Name() string {
+	return r.model.Name
+}
+
+func (r *modelResolver) DisplayName() string {
+	return r.model.DisplayName
+}
+
+func (r *modelResolver) MaxTokens() int32 {
+	return int32(r.model.MaxTokens)
+}
+
+func (r *modelResolver) Temperature() float64 {
+	return float64(r.model.Temperature)
+}
+
+func (r *modelResolver) TopP() float64 {
+	return float64(r.model.TopP)
+}
+
+func (r *modelResolver) TopK() int32 {
+	return int32(r.model.TopK)
+}
+
+func (r *modelResolver) Enabled() bool {
+	return r.model.Enabled
+}
+
+// executionLogResolver 执行日志
+type executionLogResolver struct {
+	log   *dto.MCPToolExecutionLog
+	query *queryResolver
+}
+
+func (r *executionLogResolver) ID() graphql.ID {
+	return graphql.ID(r.log.ID)
+}
+
+func (r *executionLogResolver) ToolName() string {
+	return r.log.ToolName
+}
+
+func (r *executionLogResolver) Arguments() *JSON {
+	if r.log.Arguments == nil {
+		return nil
+	}
+	return &JSON{Value: r.log.Arguments}
+}
+
+func (r *executionLogResolver) Result() *JSON {
+	if r.log.Result == nil {
+		return nil
+	}
+	return &JSON{Value: r.log.Result}
+}
+
+func (r *executionLogResolver) Error() *toolErrorResolver {
+	if r.log.Error == nil {
+		return nil
+	}
+	return &toolErrorResolver{err: r.log.Error}
+}
+
+func (r *executionLogResolver) StartTime() graphql.Time {
+	return graphql.Time{Time: r.log.StartTime}
+}
+
+func (r *executionLogResolver) EndTime() *graphql.Time {
+	if r.log.EndTime == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.log.EndTime}
+}
+
+func (r *executionLogResolver) DurationMs() *float64 {
+	if r.log.Duration == nil {
+		return nil
+	}
+	ms := float64(*r.log.Duration) / 1e6
+	return &ms
+}
+
+func (r *executionLogResolver) UserID() *graphql.ID {
+	if r.log.UserID == nil {
+		return nil
+	}
+	id := graphql.ID(*r.log.UserID)
+	return &id
+}
+
+// User 执行工具的用户，匿名执行时返回 nil
+func (r *executionLogResolver) User(ctx context.Context) (*userResolver, error) {
+	if r.log.UserID == nil {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(*r.log.UserID, 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	return r.query.user(ctx, id)
+}
+
+func (r *executionLogResolver) RequestID() string {
+	return r.log.RequestID
+}
+
+// toolErrorResolver 工具执行错误
+type toolErrorResolver struct {
+	err *dto.MCPError
+}
+
+func (r *toolErrorResolver) Code() int32 {
+	return int32(r.err.Code)
+}
+
+func (r *toolErrorResolver) Message() string {
+	return r.err.Message
+}
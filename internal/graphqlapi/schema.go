@@ -0,0 +1,112 @@
+// Package graphqlapi 管理后台使用的 GraphQL 查询接口，按字段选择组合用户、模型、提供商和执行日志，
+// 只解析查询中选择的字段
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+// 查询限制
+const (
+	maxQueryDepth  = 8
+	maxQueryLength = 8192
+)
+
+// Services 查询使用的业务服务，与HTTP控制器共用
+type Services struct {
+	Users      service.UserAdminService
+	UserLookup middleware.UserLookup
+	Providers  *provider.Manager
+	MCP        service.MCPService
+}
+
+// Schema 可执行的 GraphQL schema
+type Schema struct {
+	schema *graphql.Schema
+}
+
+// NewSchema 解析 schema 并绑定解析器
+func NewSchema(services Services) (*Schema, error) {
+	schema, err := graphql.ParseSchema(schemaString, &queryResolver{services: services},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxQueryLength(maxQueryLength),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse GraphQL schema: %w", err)
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Exec 以 userID 的身份执行查询
+func (s *Schema) Exec(ctx context.Context, userID int64, req *dto.GraphQLRequest) *graphql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &requestState{userID: userID, users: map[int64]*userResult{}})
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+type requestKey struct{}
+
+// requestState 单次请求的状态，缓存已查询的用户以免执行日志逐条查询同一用户
+type requestState struct {
+	userID int64
+	mu     sync.Mutex
+	users  map[int64]*userResult
+}
+
+type userResult struct {
+	user *dto.UserResponse
+	err  error
+}
+
+func stateFromContext(ctx context.Context) *requestState {
+	state, _ := ctx.Value(requestKey{}).(*requestState)
+	if state == nil {
+		return &requestState{users: map[int64]*userResult{}}
+	}
+	return state
+}
+
+// JSON 任意JSON值的标量
+type JSON struct {
+	Value interface{}
+}
+
+// ImplementsGraphQLType 对应 schema 中的 JSON 标量
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL 接受任意输入值
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	j.Value = input
+	return nil
+}
+
+// MarshalJSON 输出原始值
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
+
+// parseID 解析数字ID
+func parseID(id graphql.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return n, nil
+}
@@ -0,0 +1,145 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/logger"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeUsers 用户1和2存在，记录按ID查询的次数
+type fakeUsers struct {
+	service.UserAdminService
+	lookups int
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	f.lookups++
+	if id > 2 {
+		return nil, errors.NewUserNotFoundError()
+	}
+	return &dto.UserResponse{ID: id, Username: map[int64]string{1: "admin", 2: "bob"}[id], IsAdmin: id == 1}, nil
+}
+
+func (f *fakeUsers) ListUsers(ctx context.Context, query *dto.UserListQuery) (*dto.UserListResponse, error) {
+	return &dto.UserListResponse{
+		Users: []*dto.UserResponse{{ID: 2, Username: "bob"}},
+		Total: 1,
+		Page:  query.Page,
+		Limit: query.Limit,
+	}, nil
+}
+
+// fakeMCP 返回两条由用户2执行的日志
+type fakeMCP struct {
+	service.MCPService
+}
+
+func (fakeMCP) ListExecutionLogs(ctx context.Context, userID *string, limit int) ([]*dto.MCPToolExecutionLog, error) {
+	bob := "2"
+	duration := 1500 * time.Microsecond
+	return []*dto.MCPToolExecutionLog{
+		{ID: "log-1", ToolName: "echo", UserID: &bob, Duration: &duration, Result: &dto.MCPExecuteResponse{}},
+		{ID: "log-2", ToolName: "echo", UserID: &bob},
+	}, nil
+}
+
+func newTestSchema(t *testing.T) (*Schema, *fakeUsers) {
+	providers := provider.NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+	require.NoError(t, providers.RegisterProvider(provider.NewMockProvider("mock", types.ProviderTypeMock)))
+
+	users := &fakeUsers{}
+	schema, err := NewSchema(Services{Users: users, UserLookup: users, Providers: providers, MCP: fakeMCP{}})
+	require.NoError(t, err)
+	return schema, users
+}
+
+func TestSchemaExec(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "Me",
+			query: `{ me { id username isAdmin } }`,
+			want:  `{"me":{"id":"1","username":"admin","isAdmin":true}}`,
+		},
+		{
+			name:  "Unknown user is null",
+			query: `{ user(id: "42") { username } }`,
+			want:  `{"user":null}`,
+		},
+		{
+			name:  "Users with enum arguments",
+			query: `{ users(limit: 5, sortBy: USERNAME, sortOrder: ASC) { total limit users { username } } }`,
+			want:  `{"users":{"total":1,"limit":5,"users":[{"username":"bob"}]}}`,
+		},
+		{
+			name:  "Unknown provider is null",
+			query: `{ provider(type: "unknown") { name } }`,
+			want:  `{"provider":null}`,
+		},
+		{
+			name:  "Execution logs with user",
+			query: `{ executionLogs { id durationMs user { username } } }`,
+			want:  `{"executionLogs":[{"id":"log-1","durationMs":1.5,"user":{"username":"bob"}},{"id":"log-2","durationMs":null,"user":{"username":"bob"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, _ := newTestSchema(t)
+			resp := schema.Exec(context.Background(), 1, &dto.GraphQLRequest{Query: tt.query})
+			require.Empty(t, resp.Errors)
+			assert.JSONEq(t, tt.want, string(resp.Data))
+		})
+	}
+}
+
+func TestSchemaExecCachesUsers(t *testing.T) {
+	schema, users := newTestSchema(t)
+	resp := schema.Exec(context.Background(), 1, &dto.GraphQLRequest{Query: `{ executionLogs { user { id } } }`})
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, 1, users.lookups, "the same user is looked up once per request")
+}
+
+func TestSchemaExecModels(t *testing.T) {
+	schema, _ := newTestSchema(t)
+	resp := schema.Exec(context.Background(), 1, &dto.GraphQLRequest{
+		Query:     `query($type: String!) { provider(type: $type) { modelCount models { name } } }`,
+		Variables: map[string]interface{}{"type": "mock"},
+	})
+	require.Empty(t, resp.Errors)
+
+	var data struct {
+		Provider struct {
+			ModelCount int
+			Models     []struct{ Name string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	require.NotEmpty(t, data.Provider.Models)
+	assert.Len(t, data.Provider.Models, data.Provider.ModelCount)
+	for i := 1; i < len(data.Provider.Models); i++ {
+		assert.Less(t, data.Provider.Models[i-1].Name, data.Provider.Models[i].Name)
+	}
+}
+
+func TestSchemaExecRejectsDeepQueries(t *testing.T) {
+	schema, _ := newTestSchema(t)
+	resp := schema.Exec(context.Background(), 1, &dto.GraphQLRequest{
+		Query: `{ me { executionLogs { user { executionLogs { user { executionLogs { user { executionLogs { id } } } } } } } } }`,
+	})
+	assert.NotEmpty(t, resp.Errors)
+}
@@ -0,0 +1,142 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/utils"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Principal 通过认证的调用方
+type Principal struct {
+	UserID   int64
+	Username string
+	AuthType string   // jwt 或 api_token
+	Scopes   []string // 个人访问令牌的权限范围，JWT 为空
+}
+
+// HasScope 个人访问令牌是否具备指定权限范围，JWT 不受限制
+func (p *Principal) HasScope(scope string) bool {
+	if p.AuthType != "api_token" {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext 读取当前调用方
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// serviceScopes 各服务要求的个人访问令牌权限范围
+var serviceScopes = map[string]string{
+	"/springai.v1.ChatService/":  dto.APITokenScopeAI,
+	"/springai.v1.ModelService/": dto.APITokenScopeAI,
+	"/springai.v1.ToolService/":  dto.APITokenScopeMCP,
+}
+
+// Authenticator 校验 authorization 元数据中的 Bearer 令牌，与HTTP认证中间件一样接受JWT和个人访问令牌
+type Authenticator struct {
+	jwtManager *utils.JWTManager
+	apiTokens  middleware.APITokenAuthenticator
+	logger     *zap.Logger
+}
+
+// NewAuthenticator 创建gRPC认证器
+func NewAuthenticator(jwtManager *utils.JWTManager, apiTokens middleware.APITokenAuthenticator, logger *zap.Logger) *Authenticator {
+	return &Authenticator{jwtManager: jwtManager, apiTokens: apiTokens, logger: logger}
+}
+
+// UnaryInterceptor 一元调用认证拦截器
+func (a *Authenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor 流式调用认证拦截器
+func (a *Authenticator) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate 校验令牌和权限范围，返回带有调用方的上下文
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata with a Bearer token is required")
+	}
+
+	var principal *Principal
+	if strings.HasPrefix(token, dto.APITokenPrefix) {
+		if a.apiTokens == nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		p, err := a.apiTokens.Authenticate(ctx, token)
+		if err != nil {
+			a.logger.Warn("gRPC API token validation failed", zap.String("method", method), zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		principal = &Principal{UserID: p.UserID, Username: p.Username, AuthType: "api_token", Scopes: p.Scopes}
+	} else {
+		claims, err := a.jwtManager.ValidateToken(token)
+		if err != nil {
+			a.logger.Warn("gRPC token validation failed", zap.String("method", method), zap.Error(err))
+			if strings.Contains(err.Error(), "expired") {
+				return nil, status.Error(codes.Unauthenticated, "token expired")
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		principal = &Principal{UserID: claims.UserID, Username: claims.Username, AuthType: "jwt"}
+	}
+
+	for prefix, scope := range serviceScopes {
+		if strings.HasPrefix(method, prefix) && !principal.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient token scope, required scope: "+scope)
+		}
+	}
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+// bearerToken 从 authorization 元数据读取 Bearer 令牌
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		parts := strings.SplitN(value, " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" && parts[1] != "" {
+			return parts[1], true
+		}
+	}
+	return "", false
+}
+
+// contextStream 替换流的上下文，使处理函数能读取拦截器写入的值
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,120 @@
+package grpcapi
+
+import (
+	"context"
+
+	"go-springAi/internal/openai"
+	"go-springAi/internal/service"
+	springaiv1 "go-springAi/proto/springai/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chatServer 对话服务，与 AIAssistantController 共用 AIAssistantService
+type chatServer struct {
+	springaiv1.UnimplementedChatServiceServer
+	assistant *service.AIAssistantService
+}
+
+// Chat 一次性返回完整回复
+func (s *chatServer) Chat(ctx context.Context, req *springaiv1.ChatRequest) (*springaiv1.ChatResponse, error) {
+	chatReq, err := toChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.assistant.Chat(ctx, chatReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	out := &springaiv1.ChatResponse{
+		Id:       resp.ID,
+		Created:  resp.Created,
+		Model:    resp.Model,
+		Provider: resp.Provider,
+		Usage: &springaiv1.Usage{
+			PromptTokens:     int32(resp.Usage.PromptTokens),
+			CompletionTokens: int32(resp.Usage.CompletionTokens),
+			TotalTokens:      int32(resp.Usage.TotalTokens),
+		},
+	}
+	if len(resp.Choices) > 0 {
+		choice := resp.Choices[0]
+		out.Message = &springaiv1.Message{Role: choice.Message.Role, Content: choice.Message.Content}
+		out.FinishReason = choice.FinishReason
+		out.ToolCalls = toToolCalls(choice.ToolCalls)
+	}
+	return out, nil
+}
+
+// StreamChat 按增量返回回复
+func (s *chatServer) StreamChat(req *springaiv1.ChatRequest, stream springaiv1.ChatService_StreamChatServer) error {
+	ctx := stream.Context()
+	chatReq, err := toChatRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	err = s.assistant.ChatStream(ctx, chatReq, func(chunk *service.ChatStreamChunk) error {
+		return stream.Send(&springaiv1.ChatChunk{
+			Id:           chunk.ID,
+			Model:        chunk.Model,
+			Provider:     chunk.Provider,
+			Content:      chunk.Content,
+			FinishReason: chunk.FinishReason,
+			ToolCalls:    toToolCalls(chunk.ToolCalls),
+		})
+	})
+	return toStatus(err)
+}
+
+// toChatRequest 转换对话请求，用户取自认证信息
+func toChatRequest(ctx context.Context, req *springaiv1.ChatRequest) (*service.ChatRequest, error) {
+	if len(req.GetMessages()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "messages is required")
+	}
+
+	chatReq := &service.ChatRequest{
+		Messages:     make([]openai.Message, len(req.GetMessages())),
+		Model:        req.GetModel(),
+		UseTools:     req.GetUseTools(),
+		Provider:     req.GetProvider(),
+		SelectedTool: req.GetSelectedTool(),
+		Project:      req.GetProject(),
+	}
+	for i, msg := range req.GetMessages() {
+		chatReq.Messages[i] = openai.Message{Role: msg.GetRole(), Content: msg.GetContent()}
+	}
+	if req.MaxTokens != nil {
+		maxTokens := int(req.GetMaxTokens())
+		chatReq.MaxTokens = &maxTokens
+	}
+	if req.Temperature != nil {
+		temperature := req.GetTemperature()
+		chatReq.Temperature = &temperature
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		chatReq.UserID = principal.UserID
+	}
+	return chatReq, nil
+}
+
+// toToolCalls 转换回复中的工具调用
+func toToolCalls(executions []service.ToolCallExecution) []*springaiv1.ToolCall {
+	if len(executions) == 0 {
+		return nil
+	}
+	calls := make([]*springaiv1.ToolCall, len(executions))
+	for i, execution := range executions {
+		calls[i] = &springaiv1.ToolCall{
+			ToolName:    execution.ToolName,
+			Arguments:   toStruct(execution.Arguments),
+			Result:      toExecuteResponse(execution.Result),
+			Error:       execution.Error,
+			ExecutionId: execution.ExecutionID,
+		}
+	}
+	return calls
+}
@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"go-springAi/internal/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus 将服务返回的错误转换为gRPC状态，应用错误按HTTP状态码映射
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	message := appErr.Message
+	if appErr.Details != "" {
+		message += ": " + appErr.Details
+	}
+	return status.Error(httpStatusCode(appErr.HTTPStatus), message)
+}
+
+// httpStatusCode HTTP状态码对应的gRPC状态码
+func httpStatusCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	}
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
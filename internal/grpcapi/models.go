@@ -0,0 +1,154 @@
+package grpcapi
+
+import (
+	"context"
+	"sort"
+
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/provider"
+	springaiv1 "go-springAi/proto/springai/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// modelServer 模型管理服务，与 AIController 共用提供商管理器
+type modelServer struct {
+	springaiv1.UnimplementedModelServiceServer
+	providers *provider.Manager
+	users     middleware.UserLookup
+	logger    *zap.Logger
+}
+
+// ListProviders 列出已注册的提供商
+func (s *modelServer) ListProviders(ctx context.Context, _ *springaiv1.ListProvidersRequest) (*springaiv1.ListProvidersResponse, error) {
+	infos := s.providers.ListProviders(ctx)
+	providers := make([]*springaiv1.Provider, len(infos))
+	for i, info := range infos {
+		providers[i] = &springaiv1.Provider{
+			Type:        string(info.Type),
+			Name:        info.Name,
+			Description: info.Description,
+			Healthy:     info.Healthy,
+			ModelCount:  int32(info.ModelCount),
+		}
+	}
+	return &springaiv1.ListProvidersResponse{Providers: providers}, nil
+}
+
+// ListModels 按名称排序列出提供商的模型
+func (s *modelServer) ListModels(ctx context.Context, req *springaiv1.ListModelsRequest) (*springaiv1.ListModelsResponse, error) {
+	prov, err := s.provider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+
+	var models map[string]*provider.ModelConfig
+	if req.GetIncludeDisabled() {
+		models, err = prov.ListAllModels(ctx)
+	} else {
+		models, err = prov.ListModels(ctx)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	out := make([]*springaiv1.Model, 0, len(models))
+	for _, model := range models {
+		out = append(out, toModel(req.GetProvider(), model))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return &springaiv1.ListModelsResponse{Models: out}, nil
+}
+
+// GetModel 获取模型配置
+func (s *modelServer) GetModel(ctx context.Context, req *springaiv1.GetModelRequest) (*springaiv1.Model, error) {
+	prov, err := s.provider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+	return s.model(prov, req.GetProvider(), req.GetModel())
+}
+
+// EnableModel 启用模型
+func (s *modelServer) EnableModel(ctx context.Context, req *springaiv1.SetModelEnabledRequest) (*springaiv1.Model, error) {
+	return s.setEnabled(ctx, req, true)
+}
+
+// DisableModel 禁用模型
+func (s *modelServer) DisableModel(ctx context.Context, req *springaiv1.SetModelEnabledRequest) (*springaiv1.Model, error) {
+	return s.setEnabled(ctx, req, false)
+}
+
+// setEnabled 管理员启用或禁用模型，返回修改后的配置
+func (s *modelServer) setEnabled(ctx context.Context, req *springaiv1.SetModelEnabledRequest, enabled bool) (*springaiv1.Model, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	prov, err := s.provider(req.GetProvider())
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		err = prov.EnableModel(req.GetModel())
+	} else {
+		err = prov.DisableModel(req.GetModel())
+	}
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	s.logger.Info("Model availability changed via gRPC",
+		zap.String("provider", req.GetProvider()),
+		zap.String("model", req.GetModel()),
+		zap.Bool("enabled", enabled))
+	return s.model(prov, req.GetProvider(), req.GetModel())
+}
+
+// requireAdmin 要求调用方为管理员
+func (s *modelServer) requireAdmin(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	user, err := s.users.GetByID(ctx, principal.UserID)
+	if err != nil || !user.IsAdmin {
+		s.logger.Warn("gRPC admin access denied", zap.Int64("user_id", principal.UserID), zap.Error(err))
+		return status.Error(codes.PermissionDenied, "admin privileges required")
+	}
+	return nil
+}
+
+// provider 获取提供商，类型无效时返回 InvalidArgument
+func (s *modelServer) provider(providerType string) (provider.Provider, error) {
+	prov, err := s.providers.GetProvider(provider.ProviderType(providerType))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return prov, nil
+}
+
+// model 获取模型配置，模型不存在时返回 NotFound
+func (s *modelServer) model(prov provider.Provider, providerType, name string) (*springaiv1.Model, error) {
+	config, err := prov.GetModelConfig(name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return toModel(providerType, config), nil
+}
+
+// toModel 转换模型配置
+func toModel(providerType string, config *provider.ModelConfig) *springaiv1.Model {
+	return &springaiv1.Model{
+		Provider:    providerType,
+		Name:        config.Name,
+		DisplayName: config.DisplayName,
+		MaxTokens:   int32(config.MaxTokens),
+		Temperature: config.Temperature,
+		TopP:        config.TopP,
+		TopK:        int32(config.TopK),
+		Enabled:     config.Enabled,
+	}
+}
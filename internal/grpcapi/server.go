@@ -0,0 +1,189 @@
+// Package grpcapi 与HTTP并行运行的gRPC服务，供内部服务调用，和HTTP控制器共用同一组业务服务
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"go-springAi/internal/config"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/requestid"
+	"go-springAi/internal/service"
+	springaiv1 "go-springAi/proto/springai/v1"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey 请求ID的元数据键，与HTTP的 X-Request-ID 对应
+const requestIDKey = "x-request-id"
+
+// Services gRPC服务使用的业务服务
+type Services struct {
+	Assistant *service.AIAssistantService
+	MCP       service.MCPService
+	Providers *provider.Manager
+	Users     middleware.UserLookup
+}
+
+// Server gRPC服务
+type Server struct {
+	server *grpc.Server
+	addr   string
+	logger *zap.Logger
+}
+
+// NewServer 创建gRPC服务并注册对话、工具和模型服务，未启用时返回的服务不会监听端口
+func NewServer(cfg config.GRPCConfig, services Services, auth *Authenticator, logger *zap.Logger) (*Server, error) {
+	if !cfg.Enabled {
+		return &Server{logger: logger}, nil
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor(logger), loggingUnaryInterceptor(logger), auth.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(recoveryStreamInterceptor(logger), loggingStreamInterceptor(logger), auth.StreamInterceptor),
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load gRPC TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(opts...)
+	Register(server, services, logger)
+	if cfg.Reflection {
+		reflection.Register(server)
+	}
+
+	return &Server{server: server, addr: ":" + cfg.Port, logger: logger}, nil
+}
+
+// Register 在 server 上注册对话、工具和模型服务
+func Register(server *grpc.Server, services Services, logger *zap.Logger) {
+	springaiv1.RegisterChatServiceServer(server, &chatServer{assistant: services.Assistant})
+	springaiv1.RegisterToolServiceServer(server, &toolServer{mcp: services.MCP})
+	springaiv1.RegisterModelServiceServer(server, &modelServer{providers: services.Providers, users: services.Users, logger: logger})
+}
+
+// Enabled 是否启用gRPC服务
+func (s *Server) Enabled() bool {
+	return s.server != nil
+}
+
+// Addr 监听地址
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// ListenAndServe 监听端口并处理请求，服务停止后返回 nil
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("gRPC server listening", zap.String("addr", s.addr))
+	return s.server.Serve(lis)
+}
+
+// Shutdown 停止接受新调用并等待进行中的调用结束，超过 ctx 期限后强制关闭
+func (s *Server) Shutdown(ctx context.Context) {
+	if s.server == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Warn("gRPC graceful shutdown timed out, closing open streams")
+		s.server.Stop()
+	}
+}
+
+// withRequestID 沿用调用方传入的请求ID，没有或无效时生成新ID，并在响应头中返回
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := ""
+	if values := md.Get(requestIDKey); len(values) > 0 && requestid.Valid(values[0]) {
+		id = values[0]
+	} else {
+		id = requestid.New()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return requestid.WithContext(ctx, id)
+}
+
+// logCall 记录一次调用的结果
+func logCall(ctx context.Context, logger *zap.Logger, method string, start time.Time, err error) {
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", status.Code(err).String()),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("request_id", requestid.FromContext(ctx)),
+	}
+	if err != nil {
+		logger.Warn("gRPC call failed", append(fields, zap.Error(err))...)
+		return
+	}
+	logger.Info("gRPC call", fields...)
+}
+
+func loggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = withRequestID(ctx)
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func loggingStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withRequestID(ss.Context())
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// recovered 将处理函数中的 panic 记录日志并转换为 Internal 错误
+func recovered(logger *zap.Logger, method string, err *error) {
+	if r := recover(); r != nil {
+		logger.Error("gRPC handler panic",
+			zap.String("method", method),
+			zap.Any("panic", r),
+			zap.ByteString("stack", debug.Stack()))
+		*err = status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func recoveryUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer recovered(logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func recoveryStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recovered(logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,220 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/logger"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+	"go-springAi/internal/types"
+	"go-springAi/internal/utils"
+	springaiv1 "go-springAi/proto/springai/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeProvider 返回固定回复的流式提供商
+type fakeProvider struct{}
+
+func (fakeProvider) GetType() string { return "fake" }
+func (fakeProvider) GetName() string { return "fake" }
+
+func (fakeProvider) ChatCompletion(ctx context.Context, req *service.ProviderChatRequest) (*service.ProviderChatResponse, error) {
+	return &service.ProviderChatResponse{
+		ID:      "chat-1",
+		Model:   req.Model,
+		Choices: []service.ProviderChoice{{Message: service.ProviderMessage{Role: "assistant", Content: "hello there"}, FinishReason: "stop"}},
+		Usage:   service.ProviderUsage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}, nil
+}
+
+func (fakeProvider) ChatCompletionStream(ctx context.Context, req *service.ProviderChatRequest) (io.ReadCloser, error) {
+	var sb strings.Builder
+	for _, part := range []string{"hel", "lo ", "there"} {
+		fmt.Fprintf(&sb, "data: {\"id\":\"chat-1\",\"model\":%q,\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", req.Model, part)
+	}
+	sb.WriteString("data: {\"id\":\"chat-1\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+	return io.NopCloser(strings.NewReader(sb.String())), nil
+}
+
+// fakeProviderManager 所有模型都由 fakeProvider 处理
+type fakeProviderManager struct{}
+
+func (fakeProviderManager) GetProviderByModel(string) (service.ProviderInterface, error) {
+	return fakeProvider{}, nil
+}
+func (fakeProviderManager) GetProviderByName(string) (service.ProviderInterface, error) {
+	return fakeProvider{}, nil
+}
+func (fakeProviderManager) ValidateModelForProvider(context.Context, string, string) error {
+	return nil
+}
+func (fakeProviderManager) GetProviderByModelWithValidation(context.Context, string) (service.ProviderInterface, error) {
+	return fakeProvider{}, nil
+}
+
+// fakeUsers 用户1是管理员
+type fakeUsers struct{}
+
+func (fakeUsers) GetByID(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	return &dto.UserResponse{ID: id, IsAdmin: id == 1}, nil
+}
+
+// fakeTokens 接受 sk_ai 和 sk_mcp 两个只有单一权限范围的个人访问令牌
+type fakeTokens struct{}
+
+func (fakeTokens) Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error) {
+	scope := strings.TrimPrefix(token, dto.APITokenPrefix)
+	if scope != dto.APITokenScopeAI && scope != dto.APITokenScopeMCP {
+		return nil, fmt.Errorf("unknown token")
+	}
+	return &dto.APITokenPrincipal{UserID: 2, Scopes: []string{scope}}, nil
+}
+
+func newTestClient(t *testing.T) (*grpc.ClientConn, *utils.JWTManager) {
+	zapLogger := zap.NewNop()
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+	providers := provider.NewManager(logger.NewLoggerFromZap(zapLogger))
+	require.NoError(t, providers.RegisterProvider(provider.NewMockProvider("mock", types.ProviderTypeMock)))
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor(zapLogger), loggingUnaryInterceptor(zapLogger), NewAuthenticator(jwtManager, fakeTokens{}, zapLogger).UnaryInterceptor),
+		grpc.ChainStreamInterceptor(recoveryStreamInterceptor(zapLogger), loggingStreamInterceptor(zapLogger), NewAuthenticator(jwtManager, fakeTokens{}, zapLogger).StreamInterceptor),
+	)
+	Register(server, Services{
+		Assistant: service.NewAIAssistantService(nil, nil, fakeProviderManager{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zapLogger),
+		MCP:       service.NewMCPService(nil, nil, "", nil, nil, zapLogger),
+		Providers: providers,
+		Users:     fakeUsers{},
+	}, zapLogger)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, jwtManager
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuthentication(t *testing.T) {
+	conn, jwtManager := newTestClient(t)
+	jwt, err := jwtManager.GenerateToken(2, "bob")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		call func(ctx context.Context) error
+		want codes.Code
+	}{
+		{name: "Missing token", ctx: context.Background(), call: listTools(conn), want: codes.Unauthenticated},
+		{name: "Invalid JWT", ctx: withToken("not-a-jwt"), call: listTools(conn), want: codes.Unauthenticated},
+		{name: "JWT", ctx: withToken(jwt), call: listProviders(conn), want: codes.OK},
+		{name: "Token with scope", ctx: withToken(dto.APITokenPrefix + dto.APITokenScopeAI), call: listProviders(conn), want: codes.OK},
+		{name: "Token without scope", ctx: withToken(dto.APITokenPrefix + dto.APITokenScopeAI), call: listTools(conn), want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(tt.call(tt.ctx)))
+		})
+	}
+}
+
+func listTools(conn *grpc.ClientConn) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := springaiv1.NewToolServiceClient(conn).ListExecutionLogs(ctx, &springaiv1.ListExecutionLogsRequest{})
+		return err
+	}
+}
+
+func listProviders(conn *grpc.ClientConn) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := springaiv1.NewModelServiceClient(conn).ListProviders(ctx, &springaiv1.ListProvidersRequest{})
+		return err
+	}
+}
+
+func TestChat(t *testing.T) {
+	conn, jwtManager := newTestClient(t)
+	jwt, err := jwtManager.GenerateToken(2, "bob")
+	require.NoError(t, err)
+	client := springaiv1.NewChatServiceClient(conn)
+	req := &springaiv1.ChatRequest{Model: "fake-model", Messages: []*springaiv1.Message{{Role: "user", Content: "hi"}}}
+
+	resp, err := client.Chat(withToken(jwt), req)
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", resp.GetMessage().GetContent())
+	assert.Equal(t, int32(5), resp.GetUsage().GetTotalTokens())
+
+	stream, err := client.StreamChat(withToken(jwt), req)
+	require.NoError(t, err)
+	var content []string
+	var finishReason string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content = append(content, chunk.GetContent())
+		finishReason = chunk.GetFinishReason()
+	}
+	assert.Equal(t, []string{"hel", "lo ", "there", ""}, content)
+	assert.Equal(t, "stop", finishReason)
+
+	_, err = client.Chat(withToken(jwt), &springaiv1.ChatRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestModelManagement(t *testing.T) {
+	conn, jwtManager := newTestClient(t)
+	client := springaiv1.NewModelServiceClient(conn)
+	admin, err := jwtManager.GenerateToken(1, "admin")
+	require.NoError(t, err)
+	user, err := jwtManager.GenerateToken(2, "bob")
+	require.NoError(t, err)
+	req := &springaiv1.SetModelEnabledRequest{Provider: "mock", Model: "mock-gpt-3.5-turbo"}
+
+	_, err = client.DisableModel(withToken(user), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	model, err := client.DisableModel(withToken(admin), req)
+	require.NoError(t, err)
+	assert.False(t, model.GetEnabled())
+
+	models, err := client.ListModels(withToken(user), &springaiv1.ListModelsRequest{Provider: "mock"})
+	require.NoError(t, err)
+	for _, m := range models.GetModels() {
+		assert.NotEqual(t, req.Model, m.GetName(), "disabled models are hidden by default")
+	}
+	all, err := client.ListModels(withToken(user), &springaiv1.ListModelsRequest{Provider: "mock", IncludeDisabled: true})
+	require.NoError(t, err)
+	assert.Len(t, all.GetModels(), len(models.GetModels())+1)
+
+	_, err = client.GetModel(withToken(user), &springaiv1.GetModelRequest{Provider: "mock", Model: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.ListModels(withToken(user), &springaiv1.ListModelsRequest{Provider: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
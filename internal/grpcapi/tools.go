@@ -0,0 +1,212 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/service"
+	springaiv1 "go-springAi/proto/springai/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 执行日志列表的默认和最大条数，与HTTP接口一致
+const (
+	defaultExecutionLogLimit = 50
+	maxExecutionLogLimit     = 100
+)
+
+// eventSource 工具执行事件来源，由 MCPServiceImpl 实现
+type eventSource interface {
+	AddSSEClient(clientID string) chan *dto.MCPSSEEvent
+	RemoveSSEClient(clientID string)
+}
+
+// toolServer 工具服务，与 MCPController 共用 MCPService
+type toolServer struct {
+	springaiv1.UnimplementedToolServiceServer
+	mcp service.MCPService
+}
+
+// ListTools 列出可用工具
+func (s *toolServer) ListTools(ctx context.Context, _ *springaiv1.ListToolsRequest) (*springaiv1.ListToolsResponse, error) {
+	resp, err := s.mcp.ListTools(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	tools := make([]*springaiv1.Tool, len(resp.Tools))
+	for i, tool := range resp.Tools {
+		tools[i] = &springaiv1.Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: toStruct(tool.InputSchema),
+		}
+	}
+	return &springaiv1.ListToolsResponse{Tools: tools}, nil
+}
+
+// ExecuteTool 执行工具，执行日志记录调用方的用户ID
+func (s *toolServer) ExecuteTool(ctx context.Context, req *springaiv1.ExecuteToolRequest) (*springaiv1.ExecuteToolResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		ctx = service.WithCaller(ctx, strconv.FormatInt(principal.UserID, 10))
+	}
+
+	resp, err := s.mcp.ExecuteTool(ctx, &dto.MCPExecuteRequest{
+		Name:      req.GetName(),
+		Arguments: req.GetArguments().AsMap(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toExecuteResponse(resp), nil
+}
+
+// GetExecutionLog 获取执行日志
+func (s *toolServer) GetExecutionLog(ctx context.Context, req *springaiv1.GetExecutionLogRequest) (*springaiv1.ExecutionLog, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	log, err := s.mcp.GetExecutionLog(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return toExecutionLog(log), nil
+}
+
+// ListExecutionLogs 列出执行日志
+func (s *toolServer) ListExecutionLogs(ctx context.Context, req *springaiv1.ListExecutionLogsRequest) (*springaiv1.ListExecutionLogsResponse, error) {
+	var userID *string
+	if req.GetUserId() != "" {
+		id := req.GetUserId()
+		userID = &id
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > maxExecutionLogLimit {
+		limit = defaultExecutionLogLimit
+	}
+
+	logs, err := s.mcp.ListExecutionLogs(ctx, userID, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := make([]*springaiv1.ExecutionLog, len(logs))
+	for i, log := range logs {
+		out[i] = toExecutionLog(log)
+	}
+	return &springaiv1.ListExecutionLogsResponse{Logs: out}, nil
+}
+
+// WatchEvents 推送工具执行事件，直到调用方取消或服务关闭
+func (s *toolServer) WatchEvents(_ *springaiv1.WatchEventsRequest, stream springaiv1.ToolService_WatchEventsServer) error {
+	source, ok := s.mcp.(eventSource)
+	if !ok {
+		return status.Error(codes.Unimplemented, "tool events are not available")
+	}
+
+	clientID := "grpc-" + uuid.NewString()
+	events := source.AddSSEClient(clientID)
+	defer source.RemoveSSEClient(clientID)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&springaiv1.Event{Id: event.ID, Event: event.Event, Data: event.Data}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toExecuteResponse 转换工具执行结果
+func toExecuteResponse(resp *dto.MCPExecuteResponse) *springaiv1.ExecuteToolResponse {
+	if resp == nil {
+		return nil
+	}
+	out := &springaiv1.ExecuteToolResponse{
+		Content: make([]*springaiv1.Content, len(resp.Content)),
+		IsError: resp.IsError,
+	}
+	for i, content := range resp.Content {
+		out.Content[i] = &springaiv1.Content{
+			Type:     content.Type,
+			Text:     content.Text,
+			MimeType: content.MimeType,
+		}
+		if content.Data != nil {
+			out.Content[i].Data = toValue(content.Data)
+		}
+	}
+	return out
+}
+
+// toExecutionLog 转换执行日志
+func toExecutionLog(log *dto.MCPToolExecutionLog) *springaiv1.ExecutionLog {
+	out := &springaiv1.ExecutionLog{
+		Id:        log.ID,
+		ToolName:  log.ToolName,
+		Arguments: toStruct(log.Arguments),
+		Result:    toExecuteResponse(log.Result),
+		StartTime: timestamppb.New(log.StartTime),
+		RequestId: log.RequestID,
+	}
+	if log.Error != nil {
+		out.Error = &springaiv1.ToolError{Code: int32(log.Error.Code), Message: log.Error.Message}
+	}
+	if log.EndTime != nil {
+		out.EndTime = timestamppb.New(*log.EndTime)
+	}
+	if log.Duration != nil {
+		out.Duration = durationpb.New(*log.Duration)
+	}
+	if log.UserID != nil {
+		out.UserId = *log.UserID
+	}
+	return out
+}
+
+// toStruct 经JSON转换为 Struct，工具参数和结果中可能含有非JSON原生类型
+func toStruct(m map[string]interface{}) *structpb.Struct {
+	if m == nil {
+		return nil
+	}
+	out := &structpb.Struct{}
+	if !fromJSON(m, out) {
+		return nil
+	}
+	return out
+}
+
+// toValue 经JSON转换为 Value，无法编码时返回 nil
+func toValue(v interface{}) *structpb.Value {
+	out := &structpb.Value{}
+	if !fromJSON(v, out) {
+		return nil
+	}
+	return out
+}
+
+func fromJSON(v interface{}, m proto.Message) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	return protojson.Unmarshal(data, m) == nil
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import "context"
+
+// languageContextKey 语言在 context.Context 中的键类型
+type languageContextKey struct{}
+
+// WithLanguage 将语言写入上下文，供非Gin调用链（服务、MCP工具）使用
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// LanguageFromContext 从上下文读取语言，未设置时返回空字符串
+func LanguageFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if lang, ok := ctx.Value(languageContextKey{}).(string); ok {
+		return lang
+	}
+	return ""
+}
+
+// ResolveLanguage 解析输出语言：优先使用显式指定的语言（支持 Accept-Language 格式），其次使用上下文中的语言，最后回退到默认语言
+func (m *Manager) ResolveLanguage(ctx context.Context, lang string) string {
+	if lang != "" {
+		if normalized := m.NormalizeLanguage(lang); normalized != "" {
+			return normalized
+		}
+		if parsedLang := m.parseAcceptLanguage(lang); parsedLang != "" {
+			return parsedLang
+		}
+	}
+	if ctxLang := LanguageFromContext(ctx); m.isSupportedLanguage(ctxLang) {
+		return ctxLang
+	}
+	return m.defaultLang
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestResolveLanguage(t *testing.T) {
+	manager, err := NewManager("en", []string{"en", "zh"})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		lang     string
+		expected string
+	}{
+		{name: "Explicit language", ctx: context.Background(), lang: "zh", expected: "zh"},
+		{name: "Accept-Language format", ctx: context.Background(), lang: "zh-CN,zh;q=0.9,en;q=0.8", expected: "zh"},
+		{name: "Context language", ctx: WithLanguage(context.Background(), "zh"), expected: "zh"},
+		{name: "Explicit overrides context", ctx: WithLanguage(context.Background(), "zh"), lang: "en", expected: "en"},
+		{name: "Unsupported falls back to context", ctx: WithLanguage(context.Background(), "zh"), lang: "fr", expected: "zh"},
+		{name: "Default language", ctx: context.Background(), expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.ResolveLanguage(tt.ctx, tt.lang); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLocaleCatalogsHaveSameKeys(t *testing.T) {
+	langs := []string{"en", "zh", "ja", "es", "de"}
+	catalogs := make(map[string]map[string]interface{})
+	for _, lang := range langs {
+		data, err := localeFS.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			t.Fatalf("failed to read %s catalog: %v", lang, err)
+		}
+		var messages map[string]interface{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			t.Fatalf("failed to parse %s catalog: %v", lang, err)
+		}
+		catalogs[lang] = messages
+	}
+
+	for _, lang := range langs[1:] {
+		for key := range catalogs["en"] {
+			if _, ok := catalogs[lang][key]; !ok {
+				t.Errorf("message %q missing from %s catalog", key, lang)
+			}
+		}
+		for key := range catalogs[lang] {
+			if _, ok := catalogs["en"][key]; !ok {
+				t.Errorf("message %q in %s catalog missing from en catalog", key, lang)
+			}
+		}
+	}
+}
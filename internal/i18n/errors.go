@@ -8,12 +8,13 @@ import (
 // ErrorMessageMap 错误码到消息ID的映射
 var ErrorMessageMap = map[errors.ErrorCode]string{
 	// 通用错误码
-	errors.ErrCodeInternal:   "error.internal",
-	errors.ErrCodeConflict:   "error.conflict",
-	errors.ErrCodeNotFound:   "error.not.found",
-	errors.ErrCodeBadRequest: "error.bad.request",
-	errors.ErrCodeTimeout:    "error.timeout",
-	errors.ErrCodeRateLimit:  "error.rate.limit",
+	errors.ErrCodeInternal:     "error.internal",
+	errors.ErrCodeConflict:     "error.conflict",
+	errors.ErrCodeNotFound:     "error.not.found",
+	errors.ErrCodeBadRequest:   "error.bad.request",
+	errors.ErrCodeTimeout:      "error.timeout",
+	errors.ErrCodeRateLimit:    "error.rate.limit",
+	errors.ErrCodeClientClosed: "error.client.closed",
 
 	// 认证和授权相关错误码
 	errors.ErrCodeUnauthorized:     "error.unauthorized",
@@ -61,6 +62,7 @@ var ErrorMessageMap = map[errors.ErrorCode]string{
 	errors.ErrCodeFileUploadFailed: "error.file.upload.failed",
 	errors.ErrCodeStorageError:     "error.storage",
 	errors.ErrCodeFileTooLarge:     "error.file.too.large",
+	errors.ErrCodeRequestTooLarge:  "error.request.too.large",
 }
 
 // GetErrorMessage 获取错误的国际化消息
@@ -86,8 +86,8 @@ func (m *Manager) T(lang, messageID string, templateData map[string]interface{})
 		MessageID:    messageID,
 		TemplateData: templateData,
 	})
-	if err != nil {
-		// 如果翻译失败，返回消息ID
+	if err != nil && msg == "" {
+		// 如果翻译失败，返回消息ID；缺少译文时 go-i18n 会回退到英文并同时返回错误
 		return messageID
 	}
 	return msg
@@ -102,16 +102,16 @@ func (m *Manager) TWithDefault(lang, messageID, defaultMessage string, templateD
 		DefaultMessage: &i18n.Message{ID: messageID, Other: defaultMessage},
 		TemplateData:   templateData,
 	})
-	if err != nil {
+	if err != nil && msg == "" {
 		return defaultMessage
 	}
 	return msg
 }
 
-// GetLanguageFromContext 从上下文获取语言
+// GetLanguageFromContext 从上下文获取语言，优先级：查询参数 > Accept-Language > Cookie > 默认语言
 func (m *Manager) GetLanguageFromContext(c *gin.Context) string {
 	// 1. 从查询参数获取
-	if lang := c.Query("lang"); lang != "" && m.isSupportedLanguage(lang) {
+	if lang := m.NormalizeLanguage(c.Query("lang")); lang != "" {
 		return lang
 	}
 
@@ -123,8 +123,10 @@ func (m *Manager) GetLanguageFromContext(c *gin.Context) string {
 	}
 
 	// 3. 从Cookie获取
-	if lang, err := c.Cookie("language"); err == nil && m.isSupportedLanguage(lang) {
-		return lang
+	if lang, err := c.Cookie("language"); err == nil {
+		if lang = m.NormalizeLanguage(lang); lang != "" {
+			return lang
+		}
 	}
 
 	// 4. 返回默认语言
@@ -151,25 +153,47 @@ func (m *Manager) isSupportedLanguage(lang string) bool {
 	return false
 }
 
-// parseAcceptLanguage 解析Accept-Language头
+// NormalizeLanguage 将语言标签（如 "ja-JP"、"DE"）规范为支持的语言代码，不支持时返回空字符串
+func (m *Manager) NormalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return ""
+	}
+	if m.isSupportedLanguage(lang) {
+		return lang
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return ""
+	}
+	return m.matchTag(tag)
+}
+
+// matchTag 按完整标签、再按基础语言匹配支持的语言
+func (m *Manager) matchTag(tag language.Tag) string {
+	if lang := tag.String(); m.isSupportedLanguage(lang) {
+		return lang
+	}
+	base, confidence := tag.Base()
+	if confidence < language.High {
+		return ""
+	}
+	if m.isSupportedLanguage(base.String()) {
+		return base.String()
+	}
+	return ""
+}
+
+// parseAcceptLanguage 解析Accept-Language头，按权重从高到低取第一个支持的语言，q=0 的语言不会被选中
 func (m *Manager) parseAcceptLanguage(acceptLang string) string {
-	// 简单解析Accept-Language头，取第一个支持的语言
-	langs := strings.Split(acceptLang, ",")
-	for _, lang := range langs {
-		// 移除权重信息 (如 "en-US;q=0.9")
-		lang = strings.TrimSpace(strings.Split(lang, ";")[0])
-		
-		// 检查完整匹配
-		if m.isSupportedLanguage(lang) {
+	tags, _, err := language.ParseAcceptLanguage(acceptLang)
+	if err != nil {
+		return ""
+	}
+	for _, tag := range tags {
+		if lang := m.matchTag(tag); lang != "" {
 			return lang
 		}
-		
-		// 检查语言前缀匹配 (如 "en-US" -> "en")
-		if parts := strings.Split(lang, "-"); len(parts) > 1 {
-			if m.isSupportedLanguage(parts[0]) {
-				return parts[0]
-			}
-		}
 	}
 	return ""
 }
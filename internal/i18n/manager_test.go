@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLanguageFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager, err := NewManager("en", []string{"en", "zh", "ja", "es", "de"})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		cookie         string
+		expected       string
+	}{
+		{name: "Query parameter", query: "?lang=ja", acceptLanguage: "de", expected: "ja"},
+		{name: "Query parameter with region", query: "?lang=es-MX", expected: "es"},
+		{name: "Unsupported query falls back to header", query: "?lang=fr", acceptLanguage: "de-DE", expected: "de"},
+		{name: "Highest weight wins", acceptLanguage: "en;q=0.5, ja;q=0.9, es;q=0.7", expected: "ja"},
+		{name: "Unsupported languages skipped", acceptLanguage: "fr-FR, it;q=0.9, es;q=0.8", expected: "es"},
+		{name: "Zero weight excluded", acceptLanguage: "de;q=0, zh-CN;q=0.1", expected: "zh"},
+		{name: "Cookie", acceptLanguage: "fr", cookie: "de", expected: "de"},
+		{name: "Default language", acceptLanguage: "*", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			if tt.cookie != "" {
+				c.Request.AddCookie(&http.Cookie{Name: "language", Value: tt.cookie})
+			}
+			if got := manager.GetLanguageFromContext(c); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslateFallsBackToDefaultLanguage(t *testing.T) {
+	manager, err := NewManager("en", []string{"en", "ja"})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	manager.bundle.MustParseMessageFileBytes([]byte(`{"only.english": "English only"}`), "extra.en.json")
+
+	if got := manager.T("ja", "response.models.retrieved", nil); got != "モデルを取得しました" {
+		t.Errorf("unexpected ja translation: %s", got)
+	}
+	if got := manager.T("ja", "only.english", nil); got != "English only" {
+		t.Errorf("expected fallback to default language, got %s", got)
+	}
+}
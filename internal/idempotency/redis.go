@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore 基于Redis的幂等记录存储，多个实例共享
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// NewRedisStore 创建Redis幂等记录存储
+func NewRedisStore(client redis.UniversalClient, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+// Begin 使用 SET NX 占用幂等键，键已存在时读取已有记录
+func (s *RedisStore) Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, error) {
+	pending, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("marshal idempotency record: %w", err)
+	}
+
+	// 读取时记录恰好过期则重新占用一次
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := s.client.SetNX(ctx, s.keyPrefix+key, pending, lockTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("acquire idempotency key: %w", err)
+		}
+		if acquired {
+			return nil, nil
+		}
+
+		data, err := s.client.Get(ctx, s.keyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read idempotency record: %w", err)
+		}
+
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("unmarshal idempotency record: %w", err)
+		}
+		return &record, nil
+	}
+	return nil, fmt.Errorf("acquire idempotency key: record keeps expiring")
+}
+
+// Complete 保存响应
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal idempotency record: %w", err)
+	}
+	if err := s.client.Set(ctx, s.keyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("save idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release 释放幂等键
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.keyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭Redis连接
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
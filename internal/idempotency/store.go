@@ -0,0 +1,25 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record 幂等键对应的请求记录，Completed 为 false 表示首个请求仍在处理中
+type Record struct {
+	Fingerprint string `json:"fingerprint"` // 请求方法、路径和请求体的摘要，用于识别同一个键被用于不同请求
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Store 幂等记录存储，单实例使用内存，多实例部署使用Redis共享
+type Store interface {
+	// Begin 占用幂等键，成功占用时返回 nil，键已存在时返回已有记录
+	Begin(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, error)
+	// Complete 保存首个请求的响应，ttl 内的重试直接重放该响应
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release 释放未保存响应的幂等键，允许客户端重试
+	Release(ctx context.Context, key string) error
+}
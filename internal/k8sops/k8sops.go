@@ -0,0 +1,538 @@
+// Package k8sops 对配置的 Kubernetes 集群执行只读运维操作：列出 Pod 和 Deployment、读取日志、
+// 查看资源详情和相关事件。不提供任何修改操作，也不读取 Secret；每个集群可以限制允许访问的命名空间
+package k8sops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// 支持 describe 的资源类型
+const (
+	KindPod        = "pod"
+	KindDeployment = "deployment"
+	KindService    = "service"
+	KindNode       = "node"
+)
+
+// AllNamespaces 表示所有命名空间
+const AllNamespaces = "*"
+
+// 默认限制
+const (
+	DefaultTimeout     = 15 * time.Second
+	DefaultMaxLogBytes = 64 * 1024
+	// maxListItems 列表操作返回的最大条目数
+	maxListItems = 200
+	// maxEvents describe 返回的最近事件数
+	maxEvents = 20
+)
+
+var (
+	// ErrUnknownCluster 集群不存在
+	ErrUnknownCluster = errors.New("k8sops: unknown cluster")
+	// ErrNamespaceNotAllowed 命名空间不在集群的允许列表中
+	ErrNamespaceNotAllowed = errors.New("k8sops: namespace not allowed")
+	// ErrUnsupportedKind 不支持的资源类型
+	ErrUnsupportedKind = errors.New("k8sops: unsupported kind")
+)
+
+// ClusterConfig 集群配置
+type ClusterConfig struct {
+	Name       string
+	Kubeconfig string   // kubeconfig 文件路径，为空且 InCluster 为 false 时使用默认加载规则
+	Context    string   // kubeconfig 中的上下文，为空时使用当前上下文
+	InCluster  bool     // 使用 Pod 的服务账号访问所在集群
+	Namespaces []string // 允许访问的命名空间，为空表示不限制
+}
+
+type cluster struct {
+	client     kubernetes.Interface
+	namespaces []string
+}
+
+// Manager 管理集群客户端
+type Manager struct {
+	clusters    map[string]*cluster
+	names       []string
+	timeout     time.Duration
+	maxLogBytes int64
+}
+
+// New 根据配置创建集群客户端，timeout 和 maxLogBytes 为 0 时使用默认值
+func New(configs []ClusterConfig, timeout time.Duration, maxLogBytes int64) (*Manager, error) {
+	m := NewManager(timeout, maxLogBytes)
+	for _, cfg := range configs {
+		restConfig, err := restConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("k8sops: cluster %s: %w", cfg.Name, err)
+		}
+		client, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("k8sops: cluster %s: %w", cfg.Name, err)
+		}
+		if err := m.AddCluster(cfg.Name, client, cfg.Namespaces); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// NewManager 创建没有集群的管理器，集群通过 AddCluster 添加
+func NewManager(timeout time.Duration, maxLogBytes int64) *Manager {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if maxLogBytes <= 0 {
+		maxLogBytes = DefaultMaxLogBytes
+	}
+	return &Manager{clusters: make(map[string]*cluster), timeout: timeout, maxLogBytes: maxLogBytes}
+}
+
+// AddCluster 添加集群客户端
+func (m *Manager) AddCluster(name string, client kubernetes.Interface, namespaces []string) error {
+	if name == "" {
+		return errors.New("k8sops: cluster name is required")
+	}
+	if _, exists := m.clusters[name]; exists {
+		return fmt.Errorf("k8sops: duplicate cluster %q", name)
+	}
+	m.clusters[name] = &cluster{client: client, namespaces: namespaces}
+	m.names = append(m.names, name)
+	sort.Strings(m.names)
+	return nil
+}
+
+// restConfig 从 kubeconfig 或服务账号构建客户端配置
+func restConfig(cfg ClusterConfig) (*rest.Config, error) {
+	if cfg.InCluster {
+		return rest.InClusterConfig()
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if cfg.Kubeconfig != "" {
+		rules.ExplicitPath = cfg.Kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.Context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+}
+
+// Names 返回按名称排序的集群名称
+func (m *Manager) Names() []string {
+	return m.names
+}
+
+// DefaultNamespace 返回集群的默认命名空间：允许列表中的第一个，不限制时为 default
+func (m *Manager) DefaultNamespace(clusterName string) string {
+	if c, ok := m.clusters[clusterName]; ok && len(c.namespaces) > 0 {
+		return c.namespaces[0]
+	}
+	return metav1.NamespaceDefault
+}
+
+// resolve 查找集群并检查命名空间权限，namespace 为空时使用默认命名空间
+func (m *Manager) resolve(clusterName, namespace string) (*cluster, string, error) {
+	c, ok := m.clusters[clusterName]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownCluster, clusterName)
+	}
+	if namespace == "" {
+		namespace = m.DefaultNamespace(clusterName)
+	}
+	if len(c.namespaces) == 0 {
+		return c, namespace, nil
+	}
+	for _, allowed := range c.namespaces {
+		if allowed == namespace {
+			return c, namespace, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: %s (allowed: %s)", ErrNamespaceNotAllowed, namespace, strings.Join(c.namespaces, ", "))
+}
+
+// listNamespace 将 AllNamespaces 转换为 client-go 的全部命名空间参数
+func listNamespace(namespace string) string {
+	if namespace == AllNamespaces {
+		return metav1.NamespaceAll
+	}
+	return namespace
+}
+
+// ListPods 列出命名空间中的 Pod，namespace 为 AllNamespaces 时列出所有命名空间
+func (m *Manager) ListPods(ctx context.Context, clusterName, namespace, labelSelector string) (*dto.K8sListResult, error) {
+	c, namespace, err := m.resolve(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	pods, err := c.client.CoreV1().Pods(listNamespace(namespace)).List(ctx, metav1.ListOptions{LabelSelector: labelSelector, Limit: maxListItems})
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.K8sListResult{Cluster: clusterName, Namespace: namespace, Kind: KindPod, Pods: []dto.K8sPod{}, Truncated: pods.Continue != ""}
+	for i, pod := range pods.Items {
+		if i == maxListItems {
+			result.Truncated = true
+			break
+		}
+		result.Pods = append(result.Pods, podSummary(&pod))
+	}
+	return result, nil
+}
+
+// ListDeployments 列出命名空间中的 Deployment
+func (m *Manager) ListDeployments(ctx context.Context, clusterName, namespace, labelSelector string) (*dto.K8sListResult, error) {
+	c, namespace, err := m.resolve(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	deployments, err := c.client.AppsV1().Deployments(listNamespace(namespace)).List(ctx, metav1.ListOptions{LabelSelector: labelSelector, Limit: maxListItems})
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.K8sListResult{Cluster: clusterName, Namespace: namespace, Kind: KindDeployment, Deployments: []dto.K8sDeployment{}, Truncated: deployments.Continue != ""}
+	for i, deployment := range deployments.Items {
+		if i == maxListItems {
+			result.Truncated = true
+			break
+		}
+		result.Deployments = append(result.Deployments, deploymentSummary(&deployment))
+	}
+	return result, nil
+}
+
+// Logs 读取 Pod 日志的最后 tailLines 行，总大小不超过 maxLogBytes
+func (m *Manager) Logs(ctx context.Context, clusterName, namespace, pod, container string, tailLines int64, previous bool) (*dto.K8sLogResult, error) {
+	c, namespace, err := m.resolve(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == AllNamespaces {
+		return nil, errors.New("k8sops: logs require a namespace")
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	limitBytes := m.maxLogBytes
+	options := &corev1.PodLogOptions{Container: container, Previous: previous, LimitBytes: &limitBytes}
+	if tailLines > 0 {
+		options.TailLines = &tailLines
+	}
+	stream, err := c.client.CoreV1().Pods(namespace).GetLogs(pod, options).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(io.LimitReader(stream, m.maxLogBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.K8sLogResult{Cluster: clusterName, Namespace: namespace, Pod: pod, Container: container, Previous: previous}
+	if int64(len(data)) > m.maxLogBytes {
+		data = data[:m.maxLogBytes]
+		result.Truncated = true
+	}
+	result.Log = strings.ToValidUTF8(string(data), "�")
+	return result, nil
+}
+
+// Describe 返回资源详情和最近的相关事件，节点为集群级资源，忽略 namespace
+func (m *Manager) Describe(ctx context.Context, clusterName, namespace, kind, name string) (*dto.K8sDescribeResult, error) {
+	if kind == KindNode {
+		// 节点不属于任何命名空间，但仍需检查集群是否存在
+		namespace = ""
+	}
+	c, namespace, err := m.resolve(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if kind == KindNode {
+		namespace = ""
+	} else if namespace == AllNamespaces {
+		return nil, errors.New("k8sops: describe requires a namespace")
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	result := &dto.K8sDescribeResult{Cluster: clusterName, Namespace: namespace, Kind: kind, Name: name, Events: []dto.K8sEvent{}}
+	var eventKind string
+	switch kind {
+	case KindPod:
+		pod, err := c.client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		result.Details, eventKind = describePod(pod), "Pod"
+	case KindDeployment:
+		deployment, err := c.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		result.Details, eventKind = describeDeployment(deployment), "Deployment"
+	case KindService:
+		service, err := c.client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		result.Details, eventKind = describeService(service), "Service"
+	case KindNode:
+		node, err := c.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		result.Details, eventKind = describeNode(node), "Node"
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKind, kind)
+	}
+
+	events, err := c.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=" + eventKind + ",involvedObject.name=" + name,
+	})
+	if err != nil {
+		// 没有事件读取权限时仍返回资源详情
+		return result, nil
+	}
+	result.Events = recentEvents(events.Items, eventKind, name)
+	return result, nil
+}
+
+func podSummary(pod *corev1.Pod) dto.K8sPod {
+	ready, restarts := 0, int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+	}
+	return dto.K8sPod{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Phase:     string(pod.Status.Phase),
+		Ready:     fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+		Restarts:  restarts,
+		Node:      pod.Spec.NodeName,
+		IP:        pod.Status.PodIP,
+		CreatedAt: pod.CreationTimestamp.UTC(),
+	}
+}
+
+func deploymentSummary(deployment *appsv1.Deployment) dto.K8sDeployment {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	images := make([]string, 0, len(deployment.Spec.Template.Spec.Containers))
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return dto.K8sDeployment{
+		Name:      deployment.Name,
+		Namespace: deployment.Namespace,
+		Replicas:  replicas,
+		Ready:     deployment.Status.ReadyReplicas,
+		Updated:   deployment.Status.UpdatedReplicas,
+		Available: deployment.Status.AvailableReplicas,
+		Images:    images,
+		CreatedAt: deployment.CreationTimestamp.UTC(),
+	}
+}
+
+func describePod(pod *corev1.Pod) map[string]interface{} {
+	containers := make([]map[string]interface{}, 0, len(pod.Spec.Containers))
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, status := range pod.Status.ContainerStatuses {
+		statuses[status.Name] = status
+	}
+	for _, container := range pod.Spec.Containers {
+		entry := map[string]interface{}{"name": container.Name, "image": container.Image}
+		if status, ok := statuses[container.Name]; ok {
+			entry["ready"] = status.Ready
+			entry["restarts"] = status.RestartCount
+			entry["state"] = containerState(status.State)
+			if status.LastTerminationState.Terminated != nil {
+				entry["last_state"] = containerState(status.LastTerminationState)
+			}
+		}
+		if len(container.Resources.Requests) > 0 || len(container.Resources.Limits) > 0 {
+			entry["requests"] = resourceList(container.Resources.Requests)
+			entry["limits"] = resourceList(container.Resources.Limits)
+		}
+		containers = append(containers, entry)
+	}
+	return map[string]interface{}{
+		"labels":     pod.Labels,
+		"node":       pod.Spec.NodeName,
+		"phase":      string(pod.Status.Phase),
+		"reason":     pod.Status.Reason,
+		"ip":         pod.Status.PodIP,
+		"qos_class":  string(pod.Status.QOSClass),
+		"conditions": podConditions(pod.Status.Conditions),
+		"containers": containers,
+		"created_at": pod.CreationTimestamp.UTC(),
+	}
+}
+
+func describeDeployment(deployment *appsv1.Deployment) map[string]interface{} {
+	summary := deploymentSummary(deployment)
+	conditions := make([]map[string]interface{}, 0, len(deployment.Status.Conditions))
+	for _, condition := range deployment.Status.Conditions {
+		conditions = append(conditions, map[string]interface{}{
+			"type":    string(condition.Type),
+			"status":  string(condition.Status),
+			"reason":  condition.Reason,
+			"message": condition.Message,
+		})
+	}
+	return map[string]interface{}{
+		"labels":     deployment.Labels,
+		"selector":   metav1.FormatLabelSelector(deployment.Spec.Selector),
+		"strategy":   string(deployment.Spec.Strategy.Type),
+		"replicas":   summary.Replicas,
+		"ready":      summary.Ready,
+		"updated":    summary.Updated,
+		"available":  summary.Available,
+		"images":     summary.Images,
+		"conditions": conditions,
+		"created_at": summary.CreatedAt,
+	}
+}
+
+func describeService(service *corev1.Service) map[string]interface{} {
+	ports := make([]string, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		entry := fmt.Sprintf("%d→%s/%s", port.Port, port.TargetPort.String(), port.Protocol)
+		if port.Name != "" {
+			entry = port.Name + " " + entry
+		}
+		if port.NodePort != 0 {
+			entry += fmt.Sprintf(" (node port %d)", port.NodePort)
+		}
+		ports = append(ports, entry)
+	}
+	var ingress []string
+	for _, lb := range service.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			ingress = append(ingress, lb.IP)
+		} else {
+			ingress = append(ingress, lb.Hostname)
+		}
+	}
+	return map[string]interface{}{
+		"labels":        service.Labels,
+		"type":          string(service.Spec.Type),
+		"cluster_ip":    service.Spec.ClusterIP,
+		"ports":         ports,
+		"selector":      service.Spec.Selector,
+		"load_balancer": ingress,
+		"created_at":    service.CreationTimestamp.UTC(),
+	}
+}
+
+func describeNode(node *corev1.Node) map[string]interface{} {
+	conditions := make([]map[string]interface{}, 0, len(node.Status.Conditions))
+	for _, condition := range node.Status.Conditions {
+		conditions = append(conditions, map[string]interface{}{
+			"type":    string(condition.Type),
+			"status":  string(condition.Status),
+			"reason":  condition.Reason,
+			"message": condition.Message,
+		})
+	}
+	taints := make([]string, 0, len(node.Spec.Taints))
+	for _, taint := range node.Spec.Taints {
+		taints = append(taints, taint.ToString())
+	}
+	return map[string]interface{}{
+		"labels":            node.Labels,
+		"unschedulable":     node.Spec.Unschedulable,
+		"taints":            taints,
+		"conditions":        conditions,
+		"capacity":          resourceList(node.Status.Capacity),
+		"allocatable":       resourceList(node.Status.Allocatable),
+		"kubelet_version":   node.Status.NodeInfo.KubeletVersion,
+		"os_image":          node.Status.NodeInfo.OSImage,
+		"container_runtime": node.Status.NodeInfo.ContainerRuntimeVersion,
+		"created_at":        node.CreationTimestamp.UTC(),
+	}
+}
+
+func podConditions(conditions []corev1.PodCondition) map[string]string {
+	result := make(map[string]string, len(conditions))
+	for _, condition := range conditions {
+		result[string(condition.Type)] = string(condition.Status)
+	}
+	return result
+}
+
+// containerState 将容器状态描述为 kubectl describe 风格的字符串
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running since " + state.Running.StartedAt.UTC().Format(time.RFC3339)
+	case state.Waiting != nil:
+		return strings.TrimSpace("Waiting: " + state.Waiting.Reason + " " + state.Waiting.Message)
+	case state.Terminated != nil:
+		return strings.TrimSpace(fmt.Sprintf("Terminated: %s (exit code %d) %s", state.Terminated.Reason, state.Terminated.ExitCode, state.Terminated.Message))
+	}
+	return "Unknown"
+}
+
+func resourceList(resources corev1.ResourceList) map[string]string {
+	result := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
+
+// recentEvents 过滤出属于资源的事件，按最后发生时间从新到旧排序
+func recentEvents(events []corev1.Event, kind, name string) []dto.K8sEvent {
+	result := []dto.K8sEvent{}
+	for _, event := range events {
+		if event.InvolvedObject.Kind != kind || event.InvolvedObject.Name != name {
+			continue
+		}
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = event.CreationTimestamp.Time
+		}
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		result = append(result, dto.K8sEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    count,
+			LastSeen: lastSeen.UTC(),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].LastSeen.After(result[j].LastSeen) })
+	if len(result) > maxEvents {
+		result = result[:maxEvents]
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package k8sops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	replicas := int32(2)
+	client := fake.NewClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "prod", Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Name: "web", Image: "web:1.2"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "web", Ready: true, RestartCount: 3, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Namespace: "prod", Labels: map[string]string{"app": "worker"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "secret-job", Namespace: "kube-system"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod"},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas, Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "web:1.2"}}},
+			}},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 2, AvailableReplicas: 1},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "prod"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "prod"},
+			Type:           corev1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container",
+			Count: 5, LastTimestamp: metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "worker-1.1", Namespace: "prod"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker-1", Namespace: "prod"},
+			Type:           corev1.EventTypeNormal, Reason: "Pulled",
+		},
+	)
+
+	m := NewManager(time.Second, 0)
+	require.NoError(t, m.AddCluster("prod", client, []string{"prod", "staging"}))
+	return m
+}
+
+func TestManager(t *testing.T) {
+	m := newTestManager(t)
+	ctx := context.Background()
+
+	pods, err := m.ListPods(ctx, "prod", "", "app=web")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", pods.Namespace)
+	require.Len(t, pods.Pods, 1)
+	assert.Equal(t, "web-1", pods.Pods[0].Name)
+	assert.Equal(t, "1/1", pods.Pods[0].Ready)
+	assert.Equal(t, int32(3), pods.Pods[0].Restarts)
+
+	deployments, err := m.ListDeployments(ctx, "prod", "prod", "")
+	require.NoError(t, err)
+	require.Len(t, deployments.Deployments, 1)
+	assert.Equal(t, int32(2), deployments.Deployments[0].Replicas)
+	assert.Equal(t, []string{"web:1.2"}, deployments.Deployments[0].Images)
+
+	described, err := m.Describe(ctx, "prod", "prod", KindPod, "web-1")
+	require.NoError(t, err)
+	assert.Equal(t, "node-a", described.Details["node"])
+	require.Len(t, described.Events, 1)
+	assert.Equal(t, "BackOff", described.Events[0].Reason)
+	assert.Equal(t, int32(5), described.Events[0].Count)
+
+	logs, err := m.Logs(ctx, "prod", "prod", "web-1", "web", 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, "web-1", logs.Pod)
+	assert.False(t, logs.Truncated)
+
+	_, err = m.ListPods(ctx, "prod", "kube-system", "")
+	assert.ErrorIs(t, err, ErrNamespaceNotAllowed)
+	_, err = m.ListPods(ctx, "prod", AllNamespaces, "")
+	assert.ErrorIs(t, err, ErrNamespaceNotAllowed)
+	_, err = m.ListPods(ctx, "staging-cluster", "", "")
+	assert.ErrorIs(t, err, ErrUnknownCluster)
+	_, err = m.Describe(ctx, "prod", "prod", "secret", "db-password")
+	assert.ErrorIs(t, err, ErrUnsupportedKind)
+	_, err = m.Describe(ctx, "prod", "prod", KindPod, "missing")
+	assert.Error(t, err)
+}
+
+func TestManagerUnrestrictedNamespaces(t *testing.T) {
+	m := NewManager(0, 0)
+	require.NoError(t, m.AddCluster("dev", fake.NewClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "kube-system"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+	), nil))
+	assert.Error(t, m.AddCluster("dev", fake.NewClientset(), nil))
+
+	pods, err := m.ListPods(context.Background(), "dev", AllNamespaces, "")
+	require.NoError(t, err)
+	assert.Len(t, pods.Pods, 2)
+
+	node, err := m.Describe(context.Background(), "dev", "ignored", KindNode, "node-a")
+	require.NoError(t, err)
+	assert.Empty(t, node.Namespace)
+	assert.Equal(t, true, node.Details["unschedulable"])
+}
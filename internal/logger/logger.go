@@ -3,6 +3,8 @@ package logger
 import (
 	"context"
 
+	"go-springAi/internal/requestid"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -191,10 +193,8 @@ func (l *zapLogger) extractContextFields(ctx context.Context) []LogField {
 	var fields []LogField
 	
 	// 提取请求ID
-	if requestID := ctx.Value("request_id"); requestID != nil {
-		if id, ok := requestID.(string); ok {
-			fields = append(fields, RequestID(id))
-		}
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, RequestID(id))
 	}
 	
 	// 提取用户ID
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigureFileSinks(t *testing.T) {
+	dir := t.TempDir()
+	appFile := filepath.Join(dir, "app.log")
+	accessFile := filepath.Join(dir, "access.log")
+
+	err := Configure(Options{
+		Mode:      "release",
+		File:      RotationConfig{Filename: appFile, MaxSizeMB: 1},
+		AccessLog: RotationConfig{Filename: accessFile, MaxSizeMB: 1},
+	})
+	if err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	t.Cleanup(func() {
+		fileCore = nil
+		accessLogger = nil
+		globalLogger = nil
+	})
+
+	Info("app message")
+	AccessLogger().InfoCtx(context.Background(), "access message")
+
+	tests := []struct {
+		file    string
+		want    string
+		notWant string
+	}{
+		{file: appFile, want: "app message", notWant: "access message"},
+		{file: accessFile, want: "access message", notWant: "app message"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("read %s: %v", tt.file, err)
+		}
+		if !strings.Contains(string(data), tt.want) || strings.Contains(string(data), tt.notWant) {
+			t.Errorf("%s: expected only %q, got %s", filepath.Base(tt.file), tt.want, data)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package markethours 主要证券交易所的常规交易时段和开闭市判断。
+// 只覆盖常规交易时段，不含盘前盘后和提前收市；节假日目前只计入美国交易所
+package markethours
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // 内置时区数据，容器镜像缺少 zoneinfo 时也能加载交易所时区
+)
+
+// Session 一段连续交易时间，用当地时间距零点的分钟数表示
+type Session struct {
+	Open  int
+	Close int
+}
+
+// Exchange 交易所定义
+type Exchange struct {
+	Code     string
+	Name     string
+	Timezone string
+	Sessions []Session // 按时间顺序排列，多段表示有午休
+	// Holidays 判断当地日期是否为休市日，为 nil 时只按周末休市
+	Holidays func(date time.Time) bool
+}
+
+// hm 将时、分转换为分钟数
+func hm(hour, minute int) int {
+	return hour*60 + minute
+}
+
+var exchanges = map[string]*Exchange{
+	"NYSE":     {Code: "NYSE", Name: "New York Stock Exchange", Timezone: "America/New_York", Sessions: []Session{{hm(9, 30), hm(16, 0)}}, Holidays: IsUSHoliday},
+	"NASDAQ":   {Code: "NASDAQ", Name: "Nasdaq", Timezone: "America/New_York", Sessions: []Session{{hm(9, 30), hm(16, 0)}}, Holidays: IsUSHoliday},
+	"TSX":      {Code: "TSX", Name: "Toronto Stock Exchange", Timezone: "America/Toronto", Sessions: []Session{{hm(9, 30), hm(16, 0)}}},
+	"LSE":      {Code: "LSE", Name: "London Stock Exchange", Timezone: "Europe/London", Sessions: []Session{{hm(8, 0), hm(16, 30)}}},
+	"XETRA":    {Code: "XETRA", Name: "Xetra (Frankfurt)", Timezone: "Europe/Berlin", Sessions: []Session{{hm(9, 0), hm(17, 30)}}},
+	"EURONEXT": {Code: "EURONEXT", Name: "Euronext Paris", Timezone: "Europe/Paris", Sessions: []Session{{hm(9, 0), hm(17, 30)}}},
+	"TSE":      {Code: "TSE", Name: "Tokyo Stock Exchange", Timezone: "Asia/Tokyo", Sessions: []Session{{hm(9, 0), hm(11, 30)}, {hm(12, 30), hm(15, 30)}}},
+	"HKEX":     {Code: "HKEX", Name: "Hong Kong Exchanges", Timezone: "Asia/Hong_Kong", Sessions: []Session{{hm(9, 30), hm(12, 0)}, {hm(13, 0), hm(16, 0)}}},
+	"SSE":      {Code: "SSE", Name: "Shanghai Stock Exchange", Timezone: "Asia/Shanghai", Sessions: []Session{{hm(9, 30), hm(11, 30)}, {hm(13, 0), hm(15, 0)}}},
+	"SZSE":     {Code: "SZSE", Name: "Shenzhen Stock Exchange", Timezone: "Asia/Shanghai", Sessions: []Session{{hm(9, 30), hm(11, 30)}, {hm(13, 0), hm(15, 0)}}},
+	"ASX":      {Code: "ASX", Name: "Australian Securities Exchange", Timezone: "Australia/Sydney", Sessions: []Session{{hm(10, 0), hm(16, 0)}}},
+	"NSE":      {Code: "NSE", Name: "National Stock Exchange of India", Timezone: "Asia/Kolkata", Sessions: []Session{{hm(9, 15), hm(15, 30)}}},
+}
+
+// Codes 返回按字母排序的交易所代码
+func Codes() []string {
+	codes := make([]string, 0, len(exchanges))
+	for code := range exchanges {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Lookup 按代码查找交易所，不区分大小写
+func Lookup(code string) (*Exchange, bool) {
+	exchange, ok := exchanges[strings.ToUpper(strings.TrimSpace(code))]
+	return exchange, ok
+}
+
+// Status 某一时刻的交易所状态
+type Status struct {
+	Exchange  *Exchange
+	LocalTime time.Time
+	Open      bool
+	// Reason 休市原因: weekend、holiday、before_open、lunch_break、after_close，开市时为空
+	Reason    string
+	NextOpen  time.Time // 下一次开盘时间（当地时间），开市时为零值
+	NextClose time.Time // 当前时段的收盘时间，休市时为下一交易时段的收盘时间
+}
+
+// searchDays 查找下一个交易日时最多向后查找的天数
+const searchDays = 14
+
+// Status 计算交易所在 at 时刻的状态
+func (e *Exchange) Status(at time.Time) (*Status, error) {
+	location, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone %s: %w", e.Timezone, err)
+	}
+	local := at.In(location)
+	status := &Status{Exchange: e, LocalTime: local}
+
+	switch {
+	case isWeekend(local):
+		status.Reason = "weekend"
+	case e.Holidays != nil && e.Holidays(local):
+		status.Reason = "holiday"
+	default:
+		minute := local.Hour()*60 + local.Minute()
+		for i, session := range e.Sessions {
+			if minute < session.Open {
+				status.Reason = "before_open"
+				if i > 0 {
+					status.Reason = "lunch_break"
+				}
+				status.NextOpen = atMinute(local, session.Open)
+				status.NextClose = atMinute(local, session.Close)
+				return status, nil
+			}
+			if minute < session.Close {
+				status.Open = true
+				status.NextClose = atMinute(local, session.Close)
+				return status, nil
+			}
+		}
+		status.Reason = "after_close"
+	}
+
+	// 今天已无交易时段，向后查找下一个交易日
+	day := local
+	for i := 0; i < searchDays; i++ {
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, location)
+		if e.IsTradingDay(day) {
+			status.NextOpen = atMinute(day, e.Sessions[0].Open)
+			status.NextClose = atMinute(day, e.Sessions[0].Close)
+			break
+		}
+	}
+	return status, nil
+}
+
+// IsTradingDay 判断当地日期是否为交易日
+func (e *Exchange) IsTradingDay(date time.Time) bool {
+	return !isWeekend(date) && (e.Holidays == nil || !e.Holidays(date))
+}
+
+// HolidaysChecked 是否计入了节假日
+func (e *Exchange) HolidaysChecked() bool {
+	return e.Holidays != nil
+}
+
+// atMinute 返回与 day 同一天、指定分钟数的时间
+func atMinute(day time.Time, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, day.Location())
+}
+
+func isWeekend(date time.Time) bool {
+	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+}
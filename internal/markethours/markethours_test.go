@@ -0,0 +1,79 @@
+package markethours
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUSHolidays(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-01-01", true},  // 元旦
+		{"2025-01-20", true},  // 马丁·路德·金纪念日
+		{"2025-04-18", true},  // 耶稣受难日
+		{"2025-05-26", true},  // 阵亡将士纪念日
+		{"2025-06-19", true},  // 六月节
+		{"2026-07-03", true},  // 独立日周六，周五补休
+		{"2025-11-27", true},  // 感恩节
+		{"2022-12-26", true},  // 圣诞节周日，周一补休
+		{"2022-01-03", false}, // 2022 元旦为周六，不补休
+		{"2021-06-18", false}, // 2022 年前不休六月节
+		{"2025-07-03", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			day, err := time.Parse("2006-01-02", tt.date)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, IsUSHoliday(day))
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		exchange  string
+		at        string // UTC
+		open      bool
+		reason    string
+		nextOpen  string // 交易所当地时间
+		nextClose string
+	}{
+		{"NYSE", "2025-03-12T14:00:00Z", true, "", "", "2025-03-12 16:00"}, // 夏令时 10:00 ET
+		{"NYSE", "2025-03-12T13:00:00Z", false, "before_open", "2025-03-12 09:30", "2025-03-12 16:00"},
+		{"NYSE", "2025-03-14T21:00:00Z", false, "after_close", "2025-03-17 09:30", "2025-03-17 16:00"}, // 周五收盘后
+		{"nasdaq", "2025-04-18T15:00:00Z", false, "holiday", "2025-04-21 09:30", "2025-04-21 16:00"},
+		{"HKEX", "2025-03-12T04:30:00Z", false, "lunch_break", "2025-03-12 13:00", "2025-03-12 16:00"},
+		{"TSE", "2025-03-15T01:00:00Z", false, "weekend", "2025-03-17 09:00", "2025-03-17 11:30"},
+		{"LSE", "2025-07-01T15:00:00Z", true, "", "", "2025-07-01 16:30"}, // BST 16:00
+	}
+	for _, tt := range tests {
+		t.Run(tt.exchange+" "+tt.at, func(t *testing.T) {
+			exchange, ok := Lookup(tt.exchange)
+			require.True(t, ok)
+			at, err := time.Parse(time.RFC3339, tt.at)
+			require.NoError(t, err)
+
+			status, err := exchange.Status(at)
+			require.NoError(t, err)
+			assert.Equal(t, tt.open, status.Open)
+			assert.Equal(t, tt.reason, status.Reason)
+			assert.Equal(t, tt.nextOpen, formatLocal(status.NextOpen))
+			assert.Equal(t, tt.nextClose, formatLocal(status.NextClose))
+		})
+	}
+
+	_, ok := Lookup("MOEX")
+	assert.False(t, ok)
+}
+
+func formatLocal(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,84 @@
+package markethours
+
+import "time"
+
+// IsUSHoliday 判断当地日期是否为纽交所/纳斯达克全天休市日（NYSE Rule 7.2）。
+// 周六的节日提前到周五、周日的节日顺延到周一；元旦落在周六时不补休
+func IsUSHoliday(date time.Time) bool {
+	year, month, day := date.Date()
+	for _, holiday := range usHolidays(year) {
+		if holiday.Month() == month && holiday.Day() == day {
+			return true
+		}
+	}
+	return false
+}
+
+// usHolidays 返回某年的休市日期
+func usHolidays(year int) []time.Time {
+	holidays := []time.Time{
+		observed(date(year, time.January, 1), false),
+		nthWeekday(year, time.January, time.Monday, 3),  // 马丁·路德·金纪念日
+		nthWeekday(year, time.February, time.Monday, 3), // 华盛顿诞辰纪念日
+		easter(year).AddDate(0, 0, -2),                  // 耶稣受难日
+		lastWeekday(year, time.May, time.Monday),        // 阵亡将士纪念日
+		observed(date(year, time.July, 4), true),
+		nthWeekday(year, time.September, time.Monday, 1),  // 劳动节
+		nthWeekday(year, time.November, time.Thursday, 4), // 感恩节
+		observed(date(year, time.December, 25), true),
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observed(date(year, time.June, 19), true)) // 六月节
+	}
+	return holidays
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// observed 返回节日的实际休市日期，saturdayToFriday 为 false 时周六的节日不补休
+func observed(holiday time.Time, saturdayToFriday bool) time.Time {
+	switch holiday.Weekday() {
+	case time.Saturday:
+		if saturdayToFriday {
+			return holiday.AddDate(0, 0, -1)
+		}
+	case time.Sunday:
+		return holiday.AddDate(0, 0, 1)
+	}
+	return holiday
+}
+
+// nthWeekday 某月第 n 个星期几
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday 某月最后一个星期几
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easter 公历复活节日期（匿名格里高利算法）
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
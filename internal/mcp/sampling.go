@@ -0,0 +1,13 @@
+package mcp
+
+import (
+	"context"
+
+	"go-springAi/internal/dto"
+)
+
+// Sampler MCP采样接口，供工具通过服务端配置的AI提供商生成内容
+type Sampler interface {
+	// CreateMessage 根据消息生成一条回复
+	CreateMessage(ctx context.Context, req *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error)
+}
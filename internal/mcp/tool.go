@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"sort"
 
 	"go-springAi/internal/dto"
 )
@@ -46,6 +47,8 @@ func (tr *ToolRegistry) ListTools() []dto.MCPTool {
 	for _, tool := range tr.tools {
 		tools = append(tools, tool.GetDefinition())
 	}
+	// 按名称排序，保证列表顺序稳定
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
 	return tools
 }
 
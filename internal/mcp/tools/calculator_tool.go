@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-springAi/internal/calc"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+)
+
+// CalculatorToolName 计算器工具注册名称
+const CalculatorToolName = "calculator"
+
+// 结果保留的小数位数范围
+const (
+	defaultCalculatorDecimals = 10
+	maxCalculatorDecimals     = calc.Precision
+)
+
+// CalculatorTool 任意精度计算器，用于仓位、收益率等计算，避免模型心算出错
+type CalculatorTool struct {
+	*mcp.BaseTool
+}
+
+// NewCalculatorTool 创建计算器工具
+func NewCalculatorTool() *CalculatorTool {
+	return &CalculatorTool{
+		BaseTool: &mcp.BaseTool{
+			Name: CalculatorToolName,
+			Description: "任意精度十进制计算器。支持 + - * / ^、括号和百分号 (x% 等于 x/100，不是取模)，" +
+				"常量 pi、e，函数: " + strings.Join(calc.FunctionUsages(), ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"expression": map[string]interface{}{
+						"type":        "string",
+						"description": "数学表达式 (例如: '10000 * 2% / (150.25 - 142.80)', 'compound(10000, 7%, 10)', 'cagr(120, 185, 3)')",
+						"maxLength":   calc.MaxExpressionLength,
+					},
+					"decimals": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("结果保留的小数位数 (0-%d)", maxCalculatorDecimals),
+						"minimum":     0,
+						"maximum":     maxCalculatorDecimals,
+						"default":     defaultCalculatorDecimals,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"expression"},
+			},
+		},
+	}
+}
+
+// Execute 计算表达式
+func (ct *CalculatorTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := ct.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	expression := strings.TrimSpace(args["expression"].(string))
+	decimals := intArg(args, "decimals", defaultCalculatorDecimals)
+
+	value, err := calc.Evaluate(expression)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("计算失败: %v", err)), nil
+	}
+
+	result := &dto.CalculatorResult{
+		Expression: expression,
+		Result:     value.Round(int32(decimals)).String(),
+		Decimals:   decimals,
+	}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("🧮 %s = %s", result.Expression, result.Result),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (ct *CalculatorTool) Validate(args map[string]interface{}) error {
+	expression, ok := args["expression"].(string)
+	if !ok {
+		return fmt.Errorf("expression 参数是必需的且必须是字符串")
+	}
+	if strings.TrimSpace(expression) == "" {
+		return fmt.Errorf("expression 不能为空")
+	}
+
+	if _, exists := args["decimals"]; exists {
+		if decimals := intArg(args, "decimals", -1); decimals < 0 || decimals > maxCalculatorDecimals {
+			return fmt.Errorf("decimals 必须在 0 到 %d 之间", maxCalculatorDecimals)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatorTool(t *testing.T) {
+	tool := NewCalculatorTool()
+
+	resp, err := tool.Execute(context.Background(), map[string]interface{}{
+		"expression": " 10000 * 2% / (150.25 - 142.80) ",
+		"decimals":   float64(2),
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, "🧮 10000 * 2% / (150.25 - 142.80) = 26.85", resp.Content[0].Text)
+
+	resp, err = tool.Execute(context.Background(), map[string]interface{}{
+		"expression": "1/3",
+		"format":     "json",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"expression":"1/3","result":"0.3333333333","decimals":10}`, resp.Content[0].Text)
+	assert.Equal(t, &dto.CalculatorResult{Expression: "1/3", Result: "0.3333333333", Decimals: 10}, resp.Content[0].Data)
+
+	resp, err = tool.Execute(context.Background(), map[string]interface{}{"expression": "1 / 0"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "division by zero")
+
+	assert.Error(t, tool.Validate(map[string]interface{}{"expression": " "}))
+	assert.Error(t, tool.Validate(map[string]interface{}{"expression": "1", "decimals": float64(35)}))
+}
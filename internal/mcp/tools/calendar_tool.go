@@ -0,0 +1,483 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-springAi/internal/calendar"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// CalendarToolName 日历工具注册名称
+const CalendarToolName = "calendar"
+
+const (
+	// maxCalendarDays list 操作最多查询的天数
+	maxCalendarDays = 90
+	// maxCalendarResults list 操作最多返回的事件数
+	maxCalendarResults = 50
+	// maxCalendarTitleLength 事件标题的最大长度
+	maxCalendarTitleLength = 200
+	// maxCalendarDescriptionLength 事件描述的最大长度
+	maxCalendarDescriptionLength = 4000
+	// maxEarningsDaysBefore 财报提醒最多提前的天数
+	maxEarningsDaysBefore = 7
+	// earningsReminderHour 财报提醒在提前若干天的几点触发
+	earningsReminderHour = 9
+
+	yahooFinanceBaseURL = "https://query1.finance.yahoo.com"
+)
+
+// calendarActions 支持的操作
+var calendarActions = []string{"list", "create", "earnings_reminder"}
+
+// CalendarTool 日历工具，列出即将到来的日程、创建带提醒的事件，
+// 并可根据 Yahoo Finance 的财报日历为股票的下一次财报创建提醒
+type CalendarTool struct {
+	*mcp.BaseTool
+	calendar   calendar.Calendar
+	location   *time.Location
+	caller     CallerEmailFunc
+	yahooURL   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewCalendarTool 创建日历工具，location 用于解析不带时区的时间和显示日程
+func NewCalendarTool(cal calendar.Calendar, location *time.Location, caller CallerEmailFunc) *CalendarTool {
+	return &CalendarTool{
+		BaseTool: &mcp.BaseTool{
+			Name: CalendarToolName,
+			Description: "查看即将到来的日程、创建带提醒的日历事件，或在股票下一次财报发布前提醒 (例如 '在 NVDA 财报前提醒我')。" +
+				"不带时区的时间按 " + location.String() + " 解释",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: list 列出日程, create 创建事件, earnings_reminder 为下一次财报创建提醒",
+						"enum":        calendarActions,
+					},
+					"days": map[string]interface{}{
+						"type":        "integer",
+						"description": "list: 从现在起查询的天数",
+						"minimum":     1,
+						"maximum":     maxCalendarDays,
+						"default":     14,
+					},
+					"max_results": map[string]interface{}{
+						"type":        "integer",
+						"description": "list: 最多返回的事件数",
+						"minimum":     1,
+						"maximum":     maxCalendarResults,
+						"default":     20,
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "create: 事件标题; earnings_reminder: 可选，默认 '<代码> earnings'",
+						"maxLength":   maxCalendarTitleLength,
+					},
+					"start": map[string]interface{}{
+						"type":        "string",
+						"description": "create: 开始时间，'2024-05-22T09:30' 或带时区的 RFC3339；只写日期 '2024-05-22' 时创建全天事件",
+					},
+					"duration_minutes": map[string]interface{}{
+						"type":        "integer",
+						"description": "create: 持续时间 (分钟)，全天事件忽略",
+						"minimum":     1,
+						"maximum":     24 * 60,
+						"default":     30,
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "create: 事件描述",
+						"maxLength":   maxCalendarDescriptionLength,
+					},
+					"location": map[string]interface{}{
+						"type":        "string",
+						"description": "create: 地点或会议链接",
+					},
+					"reminder_minutes": map[string]interface{}{
+						"type":        "integer",
+						"description": "create: 开始前多少分钟提醒，0 表示使用日历的默认提醒",
+						"minimum":     0,
+						"maximum":     calendar.MaxReminderMinutes,
+					},
+					"symbol": map[string]interface{}{
+						"type":        "string",
+						"description": "earnings_reminder: 股票代码 (例如: NVDA)",
+					},
+					"days_before": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("earnings_reminder: 提前几天提醒，在当天 %d:00 触发", earningsReminderHour),
+						"minimum":     1,
+						"maximum":     maxEarningsDaysBefore,
+						"default":     1,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"action"},
+			},
+		},
+		calendar: cal,
+		location: location,
+		caller:   caller,
+		yahooURL: yahooFinanceBaseURL,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+		now: time.Now,
+	}
+}
+
+// Execute 执行日历操作
+func (ct *CalendarTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := ct.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	_, email, err := ct.caller(ctx)
+	if err != nil {
+		return textErrorResponse("使用日历需要登录后使用"), nil
+	}
+
+	switch args["action"].(string) {
+	case "list":
+		return ct.list(ctx, email, args)
+	case "create":
+		return ct.create(ctx, email, args)
+	default:
+		return ct.earningsReminder(ctx, email, args)
+	}
+}
+
+// Validate 验证参数
+func (ct *CalendarTool) Validate(args map[string]interface{}) error {
+	action, ok := args["action"].(string)
+	if !ok || !containsString(calendarActions, action) {
+		return fmt.Errorf("action 必须是 %s 之一", strings.Join(calendarActions, ", "))
+	}
+	if days := intArg(args, "days", 14); days < 1 || days > maxCalendarDays {
+		return fmt.Errorf("days 必须在 1 到 %d 之间", maxCalendarDays)
+	}
+	if max := intArg(args, "max_results", 20); max < 1 || max > maxCalendarResults {
+		return fmt.Errorf("max_results 必须在 1 到 %d 之间", maxCalendarResults)
+	}
+	if title := stringArg(args, "title", ""); len([]rune(title)) > maxCalendarTitleLength || strings.ContainsAny(title, "\r\n") {
+		return fmt.Errorf("title 必须是不超过 %d 个字符的单行字符串", maxCalendarTitleLength)
+	}
+
+	switch action {
+	case "create":
+		if strings.TrimSpace(stringArg(args, "title", "")) == "" {
+			return fmt.Errorf("create 需要 title 参数")
+		}
+		if _, _, err := ct.parseStart(stringArg(args, "start", "")); err != nil {
+			return err
+		}
+		if duration := intArg(args, "duration_minutes", 30); duration < 1 || duration > 24*60 {
+			return fmt.Errorf("duration_minutes 必须在 1 到 %d 之间", 24*60)
+		}
+		if reminder := intArg(args, "reminder_minutes", 0); reminder < 0 || reminder > calendar.MaxReminderMinutes {
+			return fmt.Errorf("reminder_minutes 必须在 0 到 %d 之间", calendar.MaxReminderMinutes)
+		}
+		if len([]rune(stringArg(args, "description", ""))) > maxCalendarDescriptionLength {
+			return fmt.Errorf("description 不能超过 %d 个字符", maxCalendarDescriptionLength)
+		}
+	case "earnings_reminder":
+		if strings.TrimSpace(stringArg(args, "symbol", "")) == "" {
+			return fmt.Errorf("earnings_reminder 需要 symbol 参数")
+		}
+		if days := intArg(args, "days_before", 1); days < 1 || days > maxEarningsDaysBefore {
+			return fmt.Errorf("days_before 必须在 1 到 %d 之间", maxEarningsDaysBefore)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// list 列出从现在起若干天内的事件
+func (ct *CalendarTool) list(ctx context.Context, owner string, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	from := ct.now()
+	to := from.AddDate(0, 0, intArg(args, "days", 14))
+	events, truncated, err := ct.calendar.Events(ctx, owner, from, to, intArg(args, "max_results", 20))
+	if err != nil {
+		return ct.errorResponse("读取日程失败", err), nil
+	}
+	result := &dto.CalendarListResult{From: from, To: to, Events: events, Truncated: truncated}
+	if result.Events == nil {
+		result.Events = []dto.CalendarEvent{}
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	var sb strings.Builder
+	if len(events) == 0 {
+		sb.WriteString(fmt.Sprintf("📅 未来 %d 天没有日程", intArg(args, "days", 14)))
+	} else {
+		sb.WriteString(fmt.Sprintf("📅 未来 %d 天的日程 (%d 个):\n", intArg(args, "days", 14), len(events)))
+		for _, event := range events {
+			sb.WriteString("- " + ct.formatEvent(event) + "\n")
+		}
+		if truncated {
+			sb.WriteString("… 还有更多日程，可缩小 days 或增大 max_results\n")
+		}
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: strings.TrimRight(sb.String(), "\n"),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// create 创建事件
+func (ct *CalendarTool) create(ctx context.Context, owner string, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	start, allDay, _ := ct.parseStart(stringArg(args, "start", ""))
+	event := dto.CalendarEvent{
+		Title:           strings.TrimSpace(stringArg(args, "title", "")),
+		Description:     strings.TrimSpace(stringArg(args, "description", "")),
+		Location:        strings.TrimSpace(stringArg(args, "location", "")),
+		Start:           start,
+		End:             start.Add(time.Duration(intArg(args, "duration_minutes", 30)) * time.Minute),
+		AllDay:          allDay,
+		ReminderMinutes: intArg(args, "reminder_minutes", 0),
+	}
+	if allDay {
+		event.End = start.AddDate(0, 0, 1)
+	}
+
+	created, err := ct.calendar.Create(ctx, owner, event)
+	if err != nil {
+		return ct.errorResponse("创建事件失败", err), nil
+	}
+	return ct.eventResponse(args, "✅ 已创建事件: "+ct.formatEvent(*created), created), nil
+}
+
+// earningsReminder 为股票的下一次财报创建全天事件，日历中当天已有同名事件时不重复创建
+func (ct *CalendarTool) earningsReminder(ctx context.Context, owner string, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(stringArg(args, "symbol", "")))
+	date, estimate, err := ct.nextEarningsDate(ctx, symbol)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("获取 %s 的财报日期失败: %v", symbol, err)), nil
+	}
+	result := &dto.CalendarEarningsReminder{
+		Symbol:       symbol,
+		EarningsDate: date.Format(time.DateOnly),
+		Estimate:     estimate,
+	}
+
+	title := strings.TrimSpace(stringArg(args, "title", ""))
+	if title == "" {
+		title = symbol + " earnings"
+	}
+	existing, _, err := ct.calendar.Events(ctx, owner, date, date.AddDate(0, 0, 1), maxCalendarResults)
+	if err != nil {
+		return ct.errorResponse("读取日程失败", err), nil
+	}
+	for _, event := range existing {
+		if strings.EqualFold(event.Title, title) {
+			result.Event = event
+			return ct.eventResponse(args, fmt.Sprintf("ℹ️ 日历中已有 %s 的财报提醒: %s", symbol, ct.formatEvent(event)), result), nil
+		}
+	}
+
+	description := fmt.Sprintf("%s 财报发布日期 %s (来源: Yahoo Finance)", symbol, result.EarningsDate)
+	if estimate {
+		description += "，日期为预估，可能变动"
+	}
+	daysBefore := intArg(args, "days_before", 1)
+	created, err := ct.calendar.Create(ctx, owner, dto.CalendarEvent{
+		Title:           title,
+		Description:     description,
+		Start:           date,
+		End:             date.AddDate(0, 0, 1),
+		AllDay:          true,
+		ReminderMinutes: daysBefore*24*60 - earningsReminderHour*60,
+	})
+	if err != nil {
+		return ct.errorResponse("创建事件失败", err), nil
+	}
+	result.Created = true
+	result.Event = *created
+
+	text := fmt.Sprintf("✅ 已为 %s 的财报 (%s%s) 创建提醒，将在 %d 天前 %d:00 提醒",
+		symbol, result.EarningsDate, estimateNote(estimate), daysBefore, earningsReminderHour)
+	return ct.eventResponse(args, text, result), nil
+}
+
+// nextEarningsDate 从 Yahoo Finance 的 calendarEvents 模块获取今天及以后的下一次财报日期
+func (ct *CalendarTool) nextEarningsDate(ctx context.Context, symbol string) (time.Time, bool, error) {
+	endpoint := fmt.Sprintf("%s/v10/finance/quoteSummary/%s?modules=calendarEvents", ct.yahooURL, url.PathEscape(symbol))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+
+	resp, err := ct.httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	var payload struct {
+		QuoteSummary struct {
+			Result []struct {
+				CalendarEvents struct {
+					Earnings struct {
+						EarningsDate []struct {
+							Raw int64  `json:"raw"`
+							Fmt string `json:"fmt"`
+						} `json:"earningsDate"`
+						IsEarningsDateEstimate *struct {
+							Raw bool `json:"raw"`
+						} `json:"isEarningsDateEstimate"`
+					} `json:"earnings"`
+				} `json:"calendarEvents"`
+			} `json:"result"`
+			Error *struct {
+				Description string `json:"description"`
+			} `json:"error"`
+		} `json:"quoteSummary"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return time.Time{}, false, fmt.Errorf("解析响应失败 (状态码 %d): %w", resp.StatusCode, err)
+	}
+	if payload.QuoteSummary.Error != nil {
+		return time.Time{}, false, fmt.Errorf("Yahoo Finance API 错误: %s", payload.QuoteSummary.Error.Description)
+	}
+	if len(payload.QuoteSummary.Result) == 0 {
+		return time.Time{}, false, fmt.Errorf("未找到 %s", symbol)
+	}
+
+	earnings := payload.QuoteSummary.Result[0].CalendarEvents.Earnings
+	// 日期未确定时 Yahoo 返回一个日期区间，取最早的日期
+	estimate := len(earnings.EarningsDate) > 1 ||
+		(earnings.IsEarningsDateEstimate != nil && earnings.IsEarningsDateEstimate.Raw)
+	today := dayIn(ct.now(), ct.location)
+	for _, candidate := range earnings.EarningsDate {
+		date, err := time.ParseInLocation(time.DateOnly, candidate.Fmt, ct.location)
+		if err != nil {
+			date = dayIn(time.Unix(candidate.Raw, 0), ct.location)
+		}
+		if !date.Before(today) {
+			return date, estimate, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("暂无即将发布的财报日期")
+}
+
+// parseStart 解析开始时间，第二个返回值表示是否为全天事件
+func (ct *CalendarTool) parseStart(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, fmt.Errorf("create 需要 start 参数")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, ct.location); err == nil {
+			return t, false, nil
+		}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, ct.location); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("无法解析 start %q，请使用 2006-01-02T15:04、RFC3339 或 2006-01-02", value)
+}
+
+// formatEvent 格式化单个事件
+func (ct *CalendarTool) formatEvent(event dto.CalendarEvent) string {
+	var sb strings.Builder
+	start := event.Start.In(ct.location)
+	if event.AllDay {
+		sb.WriteString(start.Format("2006-01-02") + " (全天)")
+	} else {
+		sb.WriteString(start.Format("2006-01-02 15:04") + "-" + event.End.In(ct.location).Format("15:04"))
+	}
+	sb.WriteString(" " + event.Title)
+	if event.Location != "" {
+		sb.WriteString(" @ " + event.Location)
+	}
+	if event.ReminderMinutes > 0 {
+		sb.WriteString(fmt.Sprintf(" ⏰ 提前 %s", formatMinutes(event.ReminderMinutes)))
+	}
+	if event.URL != "" {
+		sb.WriteString("\n  " + event.URL)
+	}
+	return sb.String()
+}
+
+// eventResponse 返回单个事件的结果
+func (ct *CalendarTool) eventResponse(args map[string]interface{}, text string, data interface{}) *dto.MCPExecuteResponse {
+	if wantsJSON(args) {
+		return jsonResponse(data)
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: data,
+			},
+		},
+		IsError: false,
+	}
+}
+
+// errorResponse 把日历后端的错误转为工具错误
+func (ct *CalendarTool) errorResponse(message string, err error) *dto.MCPExecuteResponse {
+	if errors.Is(err, calendar.ErrOwnerRequired) {
+		return textErrorResponse(message + ": 账号没有邮箱，无法访问个人日历")
+	}
+	return textErrorResponse(fmt.Sprintf("%s: %v", message, err))
+}
+
+// formatMinutes 把分钟数格式化为天、小时和分钟
+func formatMinutes(minutes int) string {
+	var parts []string
+	if days := minutes / (24 * 60); days > 0 {
+		parts = append(parts, fmt.Sprintf("%d 天", days))
+	}
+	if hours := minutes % (24 * 60) / 60; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d 小时", hours))
+	}
+	if rest := minutes % 60; rest > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d 分钟", rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// estimateNote 预估日期的提示
+func estimateNote(estimate bool) string {
+	if estimate {
+		return "，预估"
+	}
+	return ""
+}
+
+// dayIn 返回 t 在 loc 中当天的零点
+func dayIn(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
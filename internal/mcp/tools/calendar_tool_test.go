@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCalendar 内存日历
+type fakeCalendar struct {
+	owners []string
+	events []dto.CalendarEvent
+}
+
+func (f *fakeCalendar) Events(ctx context.Context, owner string, from, to time.Time, max int) ([]dto.CalendarEvent, bool, error) {
+	f.owners = append(f.owners, owner)
+	var events []dto.CalendarEvent
+	for _, event := range f.events {
+		if event.Start.Before(to) && event.End.After(from) {
+			events = append(events, event)
+		}
+	}
+	if len(events) > max {
+		return events[:max], true, nil
+	}
+	return events, false, nil
+}
+
+func (f *fakeCalendar) Create(ctx context.Context, owner string, event dto.CalendarEvent) (*dto.CalendarEvent, error) {
+	f.owners = append(f.owners, owner)
+	event.ID = "evt-" + event.Title
+	f.events = append(f.events, event)
+	return &event, nil
+}
+
+func TestCalendarTool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "calendarEvents", r.URL.Query().Get("modules"))
+		switch r.URL.Path {
+		case "/v10/finance/quoteSummary/NVDA":
+			w.Write([]byte(`{"quoteSummary":{"result":[{"calendarEvents":{"earnings":{"earningsDate":[{"raw":1716336000,"fmt":"2024-05-22"},{"raw":1716768000,"fmt":"2024-05-27"}],"isEarningsDateEstimate":{"raw":true}}}}],"error":null}}`))
+		case "/v10/finance/quoteSummary/OLD":
+			w.Write([]byte(`{"quoteSummary":{"result":[{"calendarEvents":{"earnings":{"earningsDate":[{"raw":1704067200,"fmt":"2024-01-01"}]}}}],"error":null}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"quoteSummary":{"result":null,"error":{"code":"Not Found","description":"Quote not found for ticker symbol: NOPE"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	cal := &fakeCalendar{}
+	authenticated := true
+	caller := func(ctx context.Context) (string, string, error) {
+		if !authenticated {
+			return "", "", errors.New("authentication required")
+		}
+		return "7", "ann@acme.com", nil
+	}
+	tool := NewCalendarTool(cal, ny, caller)
+	tool.yahooURL = server.URL
+	tool.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, ny) }
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{
+		"action":           "create",
+		"title":            "Portfolio review",
+		"start":            "2024-05-03T09:30",
+		"duration_minutes": 45.0,
+		"reminder_minutes": 90.0,
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	created := resp.Content[0].Data.(*dto.CalendarEvent)
+	assert.Equal(t, time.Date(2024, 5, 3, 9, 30, 0, 0, ny), created.Start)
+	assert.Equal(t, 45*time.Minute, created.End.Sub(created.Start))
+	assert.Equal(t, "✅ 已创建事件: 2024-05-03 09:30-10:15 Portfolio review ⏰ 提前 1 小时 30 分钟", resp.Content[0].Text)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "earnings_reminder", "symbol": "nvda", "days_before": 2.0})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	reminder := resp.Content[0].Data.(*dto.CalendarEarningsReminder)
+	assert.True(t, reminder.Created)
+	assert.True(t, reminder.Estimate)
+	assert.Equal(t, "2024-05-22", reminder.EarningsDate)
+	assert.True(t, reminder.Event.AllDay)
+	assert.Equal(t, "NVDA earnings", reminder.Event.Title)
+	assert.Equal(t, time.Date(2024, 5, 22, 0, 0, 0, 0, ny), reminder.Event.Start)
+	assert.Equal(t, 2*24*60-9*60, reminder.Event.ReminderMinutes)
+	assert.Equal(t, "✅ 已为 NVDA 的财报 (2024-05-22，预估) 创建提醒，将在 2 天前 9:00 提醒", resp.Content[0].Text)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "earnings_reminder", "symbol": "NVDA"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.False(t, resp.Content[0].Data.(*dto.CalendarEarningsReminder).Created, "an existing reminder is not duplicated")
+	assert.Len(t, cal.events, 2)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "list", "days": 30.0, "max_results": 1.0})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	list := resp.Content[0].Data.(*dto.CalendarListResult)
+	assert.True(t, list.Truncated)
+	require.Len(t, list.Events, 1)
+	assert.Contains(t, resp.Content[0].Text, "Portfolio review")
+	assert.Contains(t, resp.Content[0].Text, "还有更多日程")
+
+	for _, owner := range cal.owners {
+		assert.Equal(t, "ann@acme.com", owner)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"unknown action", map[string]interface{}{"action": "delete"}, "action"},
+		{"bad start", map[string]interface{}{"action": "create", "title": "x", "start": "tomorrow"}, "无法解析 start"},
+		{"missing symbol", map[string]interface{}{"action": "earnings_reminder"}, "symbol"},
+		{"past earnings only", map[string]interface{}{"action": "earnings_reminder", "symbol": "OLD"}, "暂无即将发布的财报日期"},
+		{"unknown symbol", map[string]interface{}{"action": "earnings_reminder", "symbol": "NOPE"}, "Quote not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	authenticated = false
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "list"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "登录")
+}
@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/units"
+
+	"github.com/shopspring/decimal"
+)
+
+// ConvertUnitsToolName 单位换算工具注册名称
+const ConvertUnitsToolName = "convert_units"
+
+// 结果保留的小数位数范围
+const (
+	defaultConvertDecimals = 6
+	maxConvertDecimals     = units.Precision
+)
+
+// categoryCurrency 货币类别，汇率由 FXRateFunc 提供
+const categoryCurrency = "currency"
+
+// currencyCodePattern 货币代码，包括 GBp 等交易所辅币代码
+var currencyCodePattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// maxConvertValue 待换算数值的绝对值上限
+var maxConvertValue = decimal.New(1, 30)
+
+// FXRateFunc 获取汇率，返回 1 单位 from 货币等于多少 to 货币
+type FXRateFunc func(ctx context.Context, from, to string) (float64, error)
+
+// ConvertUnitsTool 单位换算工具：货币、公制/英制单位和基点、年化利率等金融单位
+type ConvertUnitsTool struct {
+	*mcp.BaseTool
+	rates FXRateFunc
+}
+
+// NewConvertUnitsTool 创建单位换算工具，rates 为 nil 时不支持货币换算
+func NewConvertUnitsTool(rates FXRateFunc) *ConvertUnitsTool {
+	return &ConvertUnitsTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        ConvertUnitsToolName,
+			Description: "精确的单位换算：货币（实时汇率）、长度/重量/体积/面积/温度的公制与英制单位，以及小数/百分比/基点和不同计息周期的利率（如月利率换算为年化利率）",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"value": map[string]interface{}{
+						"type":        []string{"number", "string"},
+						"description": "待换算的数值，需要保留全部精度时可以传字符串 (例如: '1234.5678')；利率单位的数值为百分比",
+					},
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "原单位，例如 'mi', 'lb', 'troy_oz', 'bbl', 'F', 'bps', 'monthly_rate'，或 ISO 货币代码 'USD'",
+					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "目标单位，必须与原单位属于同一类别。支持的单位: " + unitNamesDescription(),
+					},
+					"compounding": map[string]interface{}{
+						"type":        "string",
+						"description": "利率换算方式: 'compound' (复利，默认), 'simple' (单利，按期数线性换算)",
+						"enum":        []string{units.CompoundingCompound, units.CompoundingSimple},
+						"default":     units.CompoundingCompound,
+					},
+					"decimals": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("结果保留的小数位数 (0-%d)", maxConvertDecimals),
+						"minimum":     0,
+						"maximum":     maxConvertDecimals,
+						"default":     defaultConvertDecimals,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"value", "from", "to"},
+			},
+		},
+		rates: rates,
+	}
+}
+
+// Execute 执行单位换算
+func (cu *ConvertUnitsTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := cu.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	value, _ := decimalArg(args, "value")
+	from := strings.TrimSpace(args["from"].(string))
+	to := strings.TrimSpace(args["to"].(string))
+	decimals := intArg(args, "decimals", defaultConvertDecimals)
+
+	result := &dto.UnitConversionResult{Value: value.String()}
+	var converted decimal.Decimal
+
+	fromUnit, fromOK := units.Lookup(from)
+	toUnit, toOK := units.Lookup(to)
+	switch {
+	case fromOK && toOK:
+		var err error
+		converted, err = units.Convert(value, fromUnit, toUnit, stringArg(args, "compounding", units.CompoundingCompound))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("换算失败: %v", err)), nil
+		}
+		result.Category, result.From, result.To = fromUnit.Category, fromUnit.Name, toUnit.Name
+	case !fromOK && !toOK && currencyCodePattern.MatchString(from) && currencyCodePattern.MatchString(to):
+		if cu.rates == nil {
+			return textErrorResponse("货币换算不可用：未配置汇率服务"), nil
+		}
+		rate, err := cu.rates(ctx, from, to)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("获取汇率失败: %v", err)), nil
+		}
+		fxRate := decimal.NewFromFloat(rate)
+		converted = value.Mul(fxRate)
+		result.Category, result.From, result.To = categoryCurrency, currencyDisplay(from), currencyDisplay(to)
+		result.Rate = fxRate.String()
+	case !fromOK && !currencyCodePattern.MatchString(from):
+		return textErrorResponse(fmt.Sprintf("未知单位: %s", from)), nil
+	case !toOK && !currencyCodePattern.MatchString(to):
+		return textErrorResponse(fmt.Sprintf("未知单位: %s", to)), nil
+	default:
+		return textErrorResponse(fmt.Sprintf("无法在货币和其他单位之间换算: %s → %s", from, to)), nil
+	}
+
+	result.Result = converted.Round(int32(decimals)).String()
+	result.Decimals = decimals
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+
+	text := fmt.Sprintf("📏 %s %s = %s %s", result.Value, result.From, result.Result, result.To)
+	if result.Category == categoryCurrency {
+		text = fmt.Sprintf("💱 %s %s = %s %s (1 %s = %s %s)", result.Value, result.From, result.Result, result.To, result.From, result.Rate, result.To)
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (cu *ConvertUnitsTool) Validate(args map[string]interface{}) error {
+	value, err := decimalArg(args, "value")
+	if err != nil {
+		return err
+	}
+	if value.Abs().GreaterThan(maxConvertValue) || value.Exponent() < -2*units.Precision {
+		return fmt.Errorf("value 超出范围")
+	}
+
+	for _, name := range []string{"from", "to"} {
+		str, ok := args[name].(string)
+		if !ok || strings.TrimSpace(str) == "" {
+			return fmt.Errorf("%s 参数是必需的且必须是字符串", name)
+		}
+	}
+
+	if compounding, exists := args["compounding"]; exists {
+		if str, ok := compounding.(string); !ok || (str != units.CompoundingCompound && str != units.CompoundingSimple) {
+			return fmt.Errorf("compounding 必须是以下值之一: %v", []string{units.CompoundingCompound, units.CompoundingSimple})
+		}
+	}
+
+	if _, exists := args["decimals"]; exists {
+		if decimals := intArg(args, "decimals", -1); decimals < 0 || decimals > maxConvertDecimals {
+			return fmt.Errorf("decimals 必须在 0 到 %d 之间", maxConvertDecimals)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// decimalArg 读取十进制数参数，支持 JSON 数字和数字字符串
+func decimalArg(args map[string]interface{}, name string) (decimal.Decimal, error) {
+	switch value := args[name].(type) {
+	case float64:
+		return decimal.NewFromFloat(value), nil
+	case int:
+		return decimal.NewFromInt(int64(value)), nil
+	case int64:
+		return decimal.NewFromInt(value), nil
+	case string:
+		d, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil {
+			return decimal.Decimal{}, fmt.Errorf("%s 不是有效的数字: %q", name, value)
+		}
+		return d, nil
+	case nil:
+		return decimal.Decimal{}, fmt.Errorf("%s 参数是必需的", name)
+	}
+	return decimal.Decimal{}, fmt.Errorf("%s 必须是数字或数字字符串", name)
+}
+
+// currencyDisplay 货币代码统一为大写，GBp 等大小写敏感的辅币代码保持原样
+func currencyDisplay(code string) string {
+	if code != strings.ToUpper(code) && code != strings.ToLower(code) {
+		return code
+	}
+	return strings.ToUpper(code)
+}
+
+// unitNamesDescription 按类别列出支持的单位，用于参数说明
+func unitNamesDescription() string {
+	names := units.Names()
+	categories := make([]string, 0, len(names))
+	for category := range names {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	parts := make([]string, 0, len(categories)+1)
+	for _, category := range categories {
+		parts = append(parts, fmt.Sprintf("%s (%s)", category, strings.Join(names[category], ", ")))
+	}
+	parts = append(parts, "currency (ISO 4217 代码，如 USD, EUR, JPY)")
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertUnitsTool(t *testing.T) {
+	var requested []string
+	tool := NewConvertUnitsTool(func(ctx context.Context, from, to string) (float64, error) {
+		requested = append(requested, from+"/"+to)
+		if from == "XXX" {
+			return 0, fmt.Errorf("no quote")
+		}
+		return 0.9123, nil
+	})
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want *dto.UnitConversionResult
+		text string
+	}{
+		{
+			name: "imperial to metric",
+			args: map[string]interface{}{"value": float64(26.2), "from": "miles", "to": "km", "decimals": float64(3)},
+			want: &dto.UnitConversionResult{Category: "length", Value: "26.2", From: "mi", To: "km", Result: "42.165", Decimals: 3},
+			text: "📏 26.2 mi = 42.165 km",
+		},
+		{
+			name: "basis points keep string precision",
+			args: map[string]interface{}{"value": "12.5", "from": "bps", "to": "percent"},
+			want: &dto.UnitConversionResult{Category: "ratio", Value: "12.5", From: "bp", To: "percent", Result: "0.125", Decimals: 6},
+		},
+		{
+			name: "annualized rate",
+			args: map[string]interface{}{"value": float64(1), "from": "monthly_rate", "to": "annual_rate", "decimals": float64(4)},
+			want: &dto.UnitConversionResult{Category: "rate", Value: "1", From: "monthly_rate", To: "annual_rate", Result: "12.6825", Decimals: 4},
+		},
+		{
+			name: "currency via fx layer",
+			args: map[string]interface{}{"value": float64(100), "from": "usd", "to": "EUR", "decimals": float64(2)},
+			want: &dto.UnitConversionResult{Category: "currency", Value: "100", From: "USD", To: "EUR", Result: "91.23", Decimals: 2, Rate: "0.9123"},
+			text: "💱 100 USD = 91.23 EUR (1 USD = 0.9123 EUR)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(context.Background(), tt.args)
+			require.NoError(t, err)
+			require.False(t, resp.IsError, resp.Content[0].Text)
+			assert.Equal(t, tt.want, resp.Content[0].Data)
+			if tt.text != "" {
+				assert.Equal(t, tt.text, resp.Content[0].Text)
+			}
+		})
+	}
+	assert.Equal(t, []string{"usd/EUR"}, requested)
+
+	errorCases := []struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"value": float64(1), "from": "kg", "to": "m"}, "cannot convert"},
+		{map[string]interface{}{"value": float64(1), "from": "furlong", "to": "m"}, "未知单位: furlong"},
+		{map[string]interface{}{"value": float64(1), "from": "USD", "to": "kg"}, "无法在货币和其他单位之间换算"},
+		{map[string]interface{}{"value": float64(1), "from": "XXX", "to": "USD"}, "获取汇率失败"},
+		{map[string]interface{}{"value": "abc", "from": "m", "to": "ft"}, "不是有效的数字"},
+		{map[string]interface{}{"value": "1e40", "from": "m", "to": "ft"}, "超出范围"},
+	}
+	for _, tt := range errorCases {
+		resp, err := tool.Execute(context.Background(), tt.args)
+		require.NoError(t, err)
+		assert.True(t, resp.IsError)
+		assert.Contains(t, resp.Content[0].Text, tt.wantErr)
+	}
+
+	resp, err := NewConvertUnitsTool(nil).Execute(context.Background(), map[string]interface{}{"value": float64(1), "from": "USD", "to": "EUR"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+}
@@ -0,0 +1,428 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/markethours"
+	"go-springAi/internal/mcp"
+)
+
+// DateTimeToolName 日期时间工具注册名称
+const DateTimeToolName = "datetime"
+
+// 日期时间工具支持的操作
+const (
+	dateTimeActionNow     = "now"
+	dateTimeActionConvert = "convert"
+	dateTimeActionMarket  = "market"
+	dateTimeActionAdd     = "add"
+	dateTimeActionDiff    = "diff"
+)
+
+var (
+	dateTimeActions = []string{dateTimeActionNow, dateTimeActionConvert, dateTimeActionMarket, dateTimeActionAdd, dateTimeActionDiff}
+	dateTimeUnits   = []string{"minutes", "hours", "days", "weeks", "months", "years", "business_days"}
+)
+
+// timezoneAliases 常用时区缩写。缩写本身不区分夏令时，统一映射到对应的地区时区
+var timezoneAliases = map[string]string{
+	"ET": "America/New_York", "EST": "America/New_York", "EDT": "America/New_York",
+	"CT": "America/Chicago", "CDT": "America/Chicago",
+	"MT": "America/Denver", "MDT": "America/Denver",
+	"PT": "America/Los_Angeles", "PST": "America/Los_Angeles", "PDT": "America/Los_Angeles",
+	"BST": "Europe/London", "CET": "Europe/Berlin", "CEST": "Europe/Berlin",
+	"JST": "Asia/Tokyo", "HKT": "Asia/Hong_Kong", "SGT": "Asia/Singapore",
+	"IST": "Asia/Kolkata", "BJT": "Asia/Shanghai",
+}
+
+// dateTimeLayouts 可解析的时间格式，不带时区的格式按 timezone 参数解释
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// marketClosedReasons 休市原因的中文说明
+var marketClosedReasons = map[string]string{
+	"weekend":     "周末休市",
+	"holiday":     "节假日休市",
+	"before_open": "尚未开盘",
+	"lunch_break": "午间休市",
+	"after_close": "已收盘",
+}
+
+// DateTimeTool 日期时间工具：任意时区的当前时间、交易所开闭市状态和日期计算
+type DateTimeTool struct {
+	*mcp.BaseTool
+	now func() time.Time
+}
+
+// NewDateTimeTool 创建日期时间工具
+func NewDateTimeTool() *DateTimeTool {
+	return &DateTimeTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        DateTimeToolName,
+			Description: "查询任意时区的当前时间、转换时区、判断交易所是否开市（含下次开盘/收盘时间），以及日期加减和间隔计算。回答“现在美股开盘了吗”之类的问题时请调用本工具，不要自行推算",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: 'now' (当前时间), 'convert' (时区转换), 'market' (交易所开闭市状态), 'add' (日期加减), 'diff' (两个时间的间隔)",
+						"enum":        dateTimeActions,
+						"default":     dateTimeActionNow,
+					},
+					"timezone": map[string]interface{}{
+						"type":        "string",
+						"description": "IANA 时区 (例如: 'America/New_York', 'Asia/Shanghai')、常用缩写 (ET, PT, JST, HKT) 或交易所代码，用于显示结果并解释不带时区的时间，默认 UTC",
+						"default":     "UTC",
+					},
+					"to_timezone": map[string]interface{}{
+						"type":        "string",
+						"description": "convert 的目标时区",
+					},
+					"time": map[string]interface{}{
+						"type":        "string",
+						"description": "输入时间，RFC3339 或 'YYYY-MM-DD[ HH:MM[:SS]]'，默认为当前时间；diff 时为起始时间",
+					},
+					"end": map[string]interface{}{
+						"type":        "string",
+						"description": "diff 的结束时间，格式同 time，默认为当前时间",
+					},
+					"exchange": map[string]interface{}{
+						"type":        "string",
+						"description": "交易所代码。market 不指定时返回全部交易所；add/diff 指定时 business_days 按该交易所的交易日计算",
+						"enum":        markethours.Codes(),
+					},
+					"amount": map[string]interface{}{
+						"type":        "integer",
+						"description": "add 的数量，可以为负数",
+					},
+					"unit": map[string]interface{}{
+						"type":        "string",
+						"description": "add 的单位",
+						"enum":        dateTimeUnits,
+						"default":     "days",
+					},
+					"format": formatProperty(),
+				},
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Execute 执行日期时间操作
+func (dt *DateTimeTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := dt.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	location, _ := resolveLocation(stringArg(args, "timezone", "UTC"))
+	start, err := dt.timeArg(args, "time", location)
+	if err != nil {
+		return textErrorResponse(err.Error()), nil
+	}
+	var exchange *markethours.Exchange
+	if code := stringArg(args, "exchange", ""); code != "" {
+		exchange, _ = markethours.Lookup(code)
+	}
+
+	action := stringArg(args, "action", dateTimeActionNow)
+	result := &dto.DateTimeResult{Action: action}
+	var text string
+
+	switch action {
+	case dateTimeActionNow:
+		setResultTime(result, start.In(location))
+		text = "🕐 当前时间: " + formatResultTime(start.In(location))
+	case dateTimeActionConvert:
+		target, _ := resolveLocation(stringArg(args, "to_timezone", ""))
+		setResultTime(result, start.In(target))
+		text = fmt.Sprintf("🕐 %s\n➡️ %s", formatResultTime(start.In(location)), formatResultTime(start.In(target)))
+	case dateTimeActionMarket:
+		targets := []*markethours.Exchange{exchange}
+		if exchange == nil {
+			targets = targets[:0]
+			for _, code := range markethours.Codes() {
+				e, _ := markethours.Lookup(code)
+				targets = append(targets, e)
+			}
+		}
+		for _, e := range targets {
+			status, err := e.Status(start)
+			if err != nil {
+				return textErrorResponse(fmt.Sprintf("计算 %s 开闭市状态失败: %v", e.Code, err)), nil
+			}
+			result.Markets = append(result.Markets, marketStatus(status))
+		}
+		text = formatMarketStatuses(result.Markets, start)
+	case dateTimeActionAdd:
+		amount := intArg(args, "amount", 0)
+		unit := stringArg(args, "unit", "days")
+		end := addDuration(start.In(location), amount, unit, exchange)
+		setResultTime(result, end)
+		text = fmt.Sprintf("🕐 %s %+d %s\n➡️ %s", formatResultTime(start.In(location)), amount, unit, formatResultTime(end))
+	case dateTimeActionDiff:
+		end, err := dt.timeArg(args, "end", location)
+		if err != nil {
+			return textErrorResponse(err.Error()), nil
+		}
+		result.Diff = dateDiff(start.In(location), end.In(location), exchange)
+		text = formatDateDiff(result.Diff, exchange)
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (dt *DateTimeTool) Validate(args map[string]interface{}) error {
+	enums := map[string][]string{
+		"action":   dateTimeActions,
+		"unit":     dateTimeUnits,
+		"exchange": markethours.Codes(),
+	}
+	for name, validValues := range enums {
+		value, exists := args[name]
+		if !exists {
+			continue
+		}
+		str, ok := value.(string)
+		if name == "exchange" {
+			str = strings.ToUpper(str)
+		}
+		if !ok || !containsString(validValues, str) {
+			return fmt.Errorf("%s 必须是以下值之一: %v", name, validValues)
+		}
+	}
+
+	for _, name := range []string{"timezone", "to_timezone"} {
+		if value, exists := args[name]; exists {
+			str, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s 必须是字符串", name)
+			}
+			if _, err := resolveLocation(str); err != nil {
+				return err
+			}
+		}
+	}
+
+	action := stringArg(args, "action", dateTimeActionNow)
+	if action == dateTimeActionConvert && stringArg(args, "to_timezone", "") == "" {
+		return fmt.Errorf("convert 需要 to_timezone 参数")
+	}
+	if action == dateTimeActionAdd {
+		if _, exists := args["amount"]; !exists {
+			return fmt.Errorf("add 需要 amount 参数")
+		}
+		if amount := intArg(args, "amount", 0); amount < -100000 || amount > 100000 {
+			return fmt.Errorf("amount 必须在 -100000 到 100000 之间")
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// timeArg 读取时间参数，未提供时返回当前时间
+func (dt *DateTimeTool) timeArg(args map[string]interface{}, name string, location *time.Location) (time.Time, error) {
+	value := strings.TrimSpace(stringArg(args, name, ""))
+	if value == "" || strings.EqualFold(value, "now") {
+		return dt.now(), nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s 格式无效: %q，应为 RFC3339 或 'YYYY-MM-DD[ HH:MM[:SS]]'", name, value)
+}
+
+// resolveLocation 解析时区名称，支持 IANA 名称、常用缩写和交易所代码
+func resolveLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if alias, ok := timezoneAliases[strings.ToUpper(name)]; ok {
+		name = alias
+	} else if exchange, ok := markethours.Lookup(name); ok {
+		name = exchange.Timezone
+	}
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("时区不能为空或为 Local，请使用 IANA 时区名称")
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("未知时区: %s", name)
+	}
+	return location, nil
+}
+
+// setResultTime 写入结果时间
+func setResultTime(result *dto.DateTimeResult, t time.Time) {
+	result.Time = t.Format(time.RFC3339)
+	result.Timezone = t.Location().String()
+	result.Weekday = t.Weekday().String()
+}
+
+// formatResultTime 格式化时间，例如 2025-03-12 10:00:00 EDT (America/New_York, Wednesday, UTC-04:00)
+func formatResultTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s, %s, UTC%s)", t.Format("2006-01-02 15:04:05 MST"), t.Location(), t.Weekday(), t.Format("-07:00"))
+}
+
+// marketStatus 转换为结构化结果
+func marketStatus(status *markethours.Status) dto.MarketStatus {
+	result := dto.MarketStatus{
+		Exchange:        status.Exchange.Code,
+		Name:            status.Exchange.Name,
+		Timezone:        status.Exchange.Timezone,
+		LocalTime:       status.LocalTime.Format(time.RFC3339),
+		Open:            status.Open,
+		Reason:          status.Reason,
+		HolidaysChecked: status.Exchange.HolidaysChecked(),
+	}
+	if !status.NextOpen.IsZero() {
+		result.NextOpen = status.NextOpen.Format(time.RFC3339)
+	}
+	if !status.NextClose.IsZero() {
+		result.NextClose = status.NextClose.Format(time.RFC3339)
+	}
+	return result
+}
+
+// formatMarketStatuses 格式化交易所状态
+func formatMarketStatuses(markets []dto.MarketStatus, at time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🏛️ 交易所状态 (%s)\n", at.UTC().Format("2006-01-02 15:04 MST"))
+	for _, m := range markets {
+		local, _ := time.Parse(time.RFC3339, m.LocalTime)
+		if m.Open {
+			next, _ := time.Parse(time.RFC3339, m.NextClose)
+			fmt.Fprintf(&b, "\n🟢 %s 交易中，当地时间 %s，%s 收盘（还有 %s）", m.Exchange, local.Format("01-02 15:04"), next.Format("15:04"), humanDuration(next.Sub(local)))
+		} else {
+			fmt.Fprintf(&b, "\n🔴 %s %s，当地时间 %s", m.Exchange, marketClosedReasons[m.Reason], local.Format("01-02 15:04 Mon"))
+			if m.NextOpen != "" {
+				next, _ := time.Parse(time.RFC3339, m.NextOpen)
+				fmt.Fprintf(&b, "，下次开盘 %s（还有 %s）", next.Format("01-02 15:04 Mon"), humanDuration(next.Sub(local)))
+			}
+		}
+		if !m.HolidaysChecked {
+			b.WriteString(" ⚠️ 未计入节假日")
+		}
+	}
+	return b.String()
+}
+
+// addDuration 时间加减，business_days 跳过周末（指定交易所时跳过其休市日）
+func addDuration(t time.Time, amount int, unit string, exchange *markethours.Exchange) time.Time {
+	switch unit {
+	case "minutes":
+		return t.Add(time.Duration(amount) * time.Minute)
+	case "hours":
+		return t.Add(time.Duration(amount) * time.Hour)
+	case "weeks":
+		return t.AddDate(0, 0, 7*amount)
+	case "months":
+		return t.AddDate(0, amount, 0)
+	case "years":
+		return t.AddDate(amount, 0, 0)
+	case "business_days":
+		step := 1
+		if amount < 0 {
+			step, amount = -1, -amount
+		}
+		for amount > 0 {
+			t = t.AddDate(0, 0, step)
+			if isBusinessDay(t, exchange) {
+				amount--
+			}
+		}
+		return t
+	}
+	return t.AddDate(0, 0, amount)
+}
+
+// dateDiff 计算两个时间的间隔
+func dateDiff(start, end time.Time, exchange *markethours.Exchange) *dto.DateDiff {
+	duration := end.Sub(start)
+	startDate := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	diff := &dto.DateDiff{
+		Start:        start.Format(time.RFC3339),
+		End:          end.Format(time.RFC3339),
+		Seconds:      int64(duration / time.Second),
+		Hours:        math.Round(duration.Hours()*100) / 100,
+		CalendarDays: int(endDate.Sub(startDate).Hours() / 24),
+	}
+
+	from, to, sign := startDate, endDate, 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if isBusinessDay(day, exchange) {
+			diff.BusinessDays += sign
+		}
+	}
+	return diff
+}
+
+// formatDateDiff 格式化时间间隔
+func formatDateDiff(diff *dto.DateDiff, exchange *markethours.Exchange) string {
+	dayKind := "工作日"
+	if exchange != nil {
+		dayKind = exchange.Code + " 交易日"
+	}
+	start, _ := time.Parse(time.RFC3339, diff.Start)
+	end, _ := time.Parse(time.RFC3339, diff.End)
+	return fmt.Sprintf("⏱️ %s → %s\n相差 %s（%.2f 小时）\n日历日: %d 天\n%s: %d 天",
+		start.Format("2006-01-02 15:04 MST"), end.Format("2006-01-02 15:04 MST"),
+		humanDuration(time.Duration(diff.Seconds)*time.Second), diff.Hours, diff.CalendarDays, dayKind, diff.BusinessDays)
+}
+
+// isBusinessDay 判断是否为工作日，指定交易所时按其交易日判断
+func isBusinessDay(t time.Time, exchange *markethours.Exchange) bool {
+	if exchange != nil {
+		return exchange.IsTradingDay(t)
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// humanDuration 将时长格式化为 "2天 3小时 15分钟"
+func humanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	minutes := int64(d / time.Minute)
+	days, hours, minutes := minutes/(24*60), minutes/60%24, minutes%60
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d天", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d小时", hours))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d分钟", minutes))
+	}
+	return sign + strings.Join(parts, " ")
+}
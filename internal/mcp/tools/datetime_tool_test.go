@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTimeTool(t *testing.T) {
+	tool := NewDateTimeTool()
+	// 2025-04-17 (周四) 14:00 UTC = 10:00 EDT，次日为耶稣受难日
+	tool.now = func() time.Time { return time.Date(2025, 4, 17, 14, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		check func(t *testing.T, result *dto.DateTimeResult)
+	}{
+		{
+			name: "now in alias timezone",
+			args: map[string]interface{}{"timezone": "ET"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Equal(t, "2025-04-17T10:00:00-04:00", result.Time)
+				assert.Equal(t, "America/New_York", result.Timezone)
+				assert.Equal(t, "Thursday", result.Weekday)
+			},
+		},
+		{
+			name: "convert naive time",
+			args: map[string]interface{}{"action": "convert", "time": "2025-01-15 09:30", "timezone": "America/New_York", "to_timezone": "Asia/Shanghai"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Equal(t, "2025-01-15T22:30:00+08:00", result.Time)
+			},
+		},
+		{
+			name: "us market open now",
+			args: map[string]interface{}{"action": "market", "exchange": "nyse"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				require.Len(t, result.Markets, 1)
+				assert.True(t, result.Markets[0].Open)
+				assert.Equal(t, "2025-04-17T16:00:00-04:00", result.Markets[0].NextClose)
+				assert.True(t, result.Markets[0].HolidaysChecked)
+			},
+		},
+		{
+			name: "us market closed on good friday",
+			args: map[string]interface{}{"action": "market", "exchange": "NASDAQ", "time": "2025-04-18T15:00:00Z"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.False(t, result.Markets[0].Open)
+				assert.Equal(t, "holiday", result.Markets[0].Reason)
+				assert.Equal(t, "2025-04-21T09:30:00-04:00", result.Markets[0].NextOpen)
+			},
+		},
+		{
+			name: "all exchanges",
+			args: map[string]interface{}{"action": "market"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Len(t, result.Markets, 12)
+			},
+		},
+		{
+			name: "add business days skips exchange holiday",
+			args: map[string]interface{}{"action": "add", "amount": float64(2), "unit": "business_days", "exchange": "NYSE", "timezone": "NYSE"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Equal(t, "2025-04-22T10:00:00-04:00", result.Time)
+			},
+		},
+		{
+			name: "add months",
+			args: map[string]interface{}{"action": "add", "time": "2025-01-31", "amount": float64(-1), "unit": "months"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Equal(t, "2024-12-31T00:00:00Z", result.Time)
+			},
+		},
+		{
+			name: "diff",
+			args: map[string]interface{}{"action": "diff", "time": "2025-04-14", "end": "2025-04-21 12:00", "exchange": "NYSE"},
+			check: func(t *testing.T, result *dto.DateTimeResult) {
+				assert.Equal(t, &dto.DateDiff{
+					Start: "2025-04-14T00:00:00Z", End: "2025-04-21T12:00:00Z",
+					Seconds: 648000, Hours: 180, CalendarDays: 7, BusinessDays: 4,
+				}, result.Diff)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(context.Background(), tt.args)
+			require.NoError(t, err)
+			require.False(t, resp.IsError, resp.Content[0].Text)
+			result, ok := resp.Content[0].Data.(*dto.DateTimeResult)
+			require.True(t, ok)
+			tt.check(t, result)
+		})
+	}
+
+	assert.Error(t, tool.Validate(map[string]interface{}{"timezone": "Mars/Olympus"}))
+	assert.Error(t, tool.Validate(map[string]interface{}{"action": "convert"}))
+	assert.Error(t, tool.Validate(map[string]interface{}{"action": "add"}))
+	assert.Error(t, tool.Validate(map[string]interface{}{"action": "market", "exchange": "MOEX"}))
+
+	resp, err := tool.Execute(context.Background(), map[string]interface{}{"action": "now", "time": "next tuesday"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+}
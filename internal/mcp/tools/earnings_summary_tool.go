@@ -0,0 +1,439 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+const (
+	// transcriptChunkRunes 每段会议记录的最大字符数
+	transcriptChunkRunes = 12000
+	// maxTranscriptChunks 单次摘要允许的最大分段数
+	maxTranscriptChunks = 10
+	// earningsSummaryMaxTokens 每次采样的最大生成token数
+	earningsSummaryMaxTokens = 1200
+)
+
+// fmpBaseURL Financial Modeling Prep API 地址
+const fmpBaseURL = "https://financialmodelingprep.com/api"
+
+// EarningsSummaryTool 财报电话会议摘要工具
+type EarningsSummaryTool struct {
+	*mcp.BaseTool
+	sampler          mcp.Sampler
+	transcriptAPIKey string
+	i18nManager      *i18n.Manager
+	httpClient       *http.Client
+}
+
+// NewEarningsSummaryTool 创建财报电话会议摘要工具，transcriptAPIKey 为空时只能处理传入的会议记录
+func NewEarningsSummaryTool(sampler mcp.Sampler, transcriptAPIKey string, i18nManager *i18n.Manager) *EarningsSummaryTool {
+	return &EarningsSummaryTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        "earnings_summary",
+			Description: "获取最新财报电话会议记录（或使用提供的文本），分段后通过AI生成结构化摘要：业绩指引、风险和管理层情绪",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbol": map[string]interface{}{
+						"type":        "string",
+						"description": "股票代码 (例如: AAPL)，未提供 transcript 时用于获取会议记录",
+					},
+					"transcript": map[string]interface{}{
+						"type":        "string",
+						"description": "会议记录全文，提供时不再远程获取",
+					},
+					"year": map[string]interface{}{
+						"type":        "integer",
+						"description": "财年，与 quarter 一起指定时获取对应季度的会议记录，默认最新",
+					},
+					"quarter": map[string]interface{}{
+						"type":        "integer",
+						"description": "季度 (1-4)",
+						"minimum":     1,
+						"maximum":     4,
+					},
+					"model": map[string]interface{}{
+						"type":        "string",
+						"description": "优先使用的模型 (例如: 'gpt-4o-mini')，默认使用服务端配置",
+					},
+					"language": languageProperty(i18nManager),
+					"format":   formatProperty(),
+				},
+			},
+		},
+		sampler:          sampler,
+		transcriptAPIKey: transcriptAPIKey,
+		i18nManager:      i18nManager,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// earningsTranscript 财报电话会议记录
+type earningsTranscript struct {
+	Symbol  string `json:"symbol"`
+	Year    int    `json:"year"`
+	Quarter int    `json:"quarter"`
+	Date    string `json:"date"`
+	Content string `json:"content"`
+}
+
+// Execute 执行会议记录摘要
+func (es *EarningsSummaryTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	t := newToolTranslator(ctx, es.i18nManager, args)
+
+	if err := es.Validate(args); err != nil {
+		return t.errorResponse("stock.error.validation", map[string]interface{}{"Error": err.Error()}), nil
+	}
+	if es.sampler == nil {
+		return t.errorResponse("stock.earnings.error.no_sampler", nil), nil
+	}
+
+	transcript := &earningsTranscript{
+		Symbol:  strings.ToUpper(strings.TrimSpace(stringArg(args, "symbol", ""))),
+		Year:    intArg(args, "year", 0),
+		Quarter: intArg(args, "quarter", 0),
+		Content: strings.TrimSpace(stringArg(args, "transcript", "")),
+	}
+	if transcript.Content == "" {
+		if es.transcriptAPIKey == "" {
+			return t.errorResponse("stock.earnings.error.no_source", nil), nil
+		}
+		fetched, err := es.fetchTranscript(ctx, transcript.Symbol, transcript.Year, transcript.Quarter)
+		if err != nil {
+			return t.errorResponse("stock.earnings.error.fetch", map[string]interface{}{"Error": err.Error()}), nil
+		}
+		transcript = fetched
+	}
+
+	chunks := chunkTranscript(transcript.Content, transcriptChunkRunes)
+	if len(chunks) > maxTranscriptChunks {
+		return t.errorResponse("stock.earnings.error.too_long", map[string]interface{}{
+			"Chunks": len(chunks),
+			"Max":    maxTranscriptChunks,
+		}), nil
+	}
+
+	summary, err := es.summarize(ctx, chunks, stringArg(args, "model", ""), t.lang)
+	if err != nil {
+		return t.errorResponse("stock.earnings.error.sampling", map[string]interface{}{"Error": err.Error()}), nil
+	}
+	summary.Symbol = transcript.Symbol
+	summary.Year = transcript.Year
+	summary.Quarter = transcript.Quarter
+	summary.Date = transcript.Date
+
+	if wantsJSON(args) {
+		return jsonResponse(summary), nil
+	}
+
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatEarningsSummary(t, summary),
+				Data: summary,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (es *EarningsSummaryTool) Validate(args map[string]interface{}) error {
+	symbol := strings.TrimSpace(stringArg(args, "symbol", ""))
+	transcript := strings.TrimSpace(stringArg(args, "transcript", ""))
+	if symbol == "" && transcript == "" {
+		return fmt.Errorf("symbol 和 transcript 至少需要提供一个")
+	}
+
+	quarter := intArg(args, "quarter", 0)
+	if _, ok := args["quarter"]; ok && (quarter < 1 || quarter > 4) {
+		return fmt.Errorf("quarter 必须在1到4之间")
+	}
+	if year := intArg(args, "year", 0); (year != 0) != (quarter != 0) {
+		return fmt.Errorf("year 和 quarter 需要同时指定")
+	}
+
+	return validateOutputFormat(args)
+}
+
+// fetchTranscript 从 Financial Modeling Prep 获取会议记录，未指定季度时获取最新一期
+func (es *EarningsSummaryTool) fetchTranscript(ctx context.Context, symbol string, year, quarter int) (*earningsTranscript, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("缺少股票代码")
+	}
+
+	if year == 0 || quarter == 0 {
+		// 可用会议记录列表，按时间倒序: [[quarter, year, date], ...]
+		var available [][]interface{}
+		params := url.Values{"symbol": {symbol}}
+		if err := es.getJSON(ctx, "/v4/earning_call_transcript", params, &available); err != nil {
+			return nil, err
+		}
+		if len(available) == 0 || len(available[0]) < 2 {
+			return nil, fmt.Errorf("未找到 %s 的会议记录", symbol)
+		}
+		q, qok := available[0][0].(float64)
+		y, yok := available[0][1].(float64)
+		if !qok || !yok {
+			return nil, fmt.Errorf("会议记录列表格式无效")
+		}
+		year, quarter = int(y), int(q)
+	}
+
+	var transcripts []earningsTranscript
+	params := url.Values{
+		"year":    {fmt.Sprintf("%d", year)},
+		"quarter": {fmt.Sprintf("%d", quarter)},
+	}
+	if err := es.getJSON(ctx, "/v3/earning_call_transcript/"+url.PathEscape(symbol), params, &transcripts); err != nil {
+		return nil, err
+	}
+	if len(transcripts) == 0 || strings.TrimSpace(transcripts[0].Content) == "" {
+		return nil, fmt.Errorf("未找到 %s %d年Q%d 的会议记录", symbol, year, quarter)
+	}
+
+	transcript := transcripts[0]
+	transcript.Symbol = symbol
+	return &transcript, nil
+}
+
+// getJSON 请求 Financial Modeling Prep 接口并解码JSON
+func (es *EarningsSummaryTool) getJSON(ctx context.Context, path string, params url.Values, out interface{}) error {
+	params.Set("apikey", es.transcriptAPIKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmpBaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	resp, err := es.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API返回错误状态码 %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+	return nil
+}
+
+// summarize 逐段提取要点，再合并为最终摘要
+func (es *EarningsSummaryTool) summarize(ctx context.Context, chunks []string, model, lang string) (*dto.EarningsSummary, error) {
+	var modelPreferences *dto.MCPModelPreferences
+	if model != "" {
+		modelPreferences = &dto.MCPModelPreferences{Hints: []dto.MCPModelHint{{Name: model}}}
+	}
+	temperature := float32(0.2)
+	systemPrompt := earningsSystemPrompt(lang)
+
+	sample := func(prompt string) (*dto.EarningsSummary, string, error) {
+		resp, err := es.sampler.CreateMessage(ctx, &dto.MCPCreateMessageRequest{
+			Messages: []dto.MCPSamplingMessage{
+				{Role: "user", Content: dto.MCPContent{Type: "text", Text: prompt}},
+			},
+			ModelPreferences: modelPreferences,
+			SystemPrompt:     systemPrompt,
+			MaxTokens:        earningsSummaryMaxTokens,
+			Temperature:      &temperature,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		summary, err := parseEarningsSummary(resp.Content.Text)
+		if err != nil {
+			return nil, "", err
+		}
+		return summary, resp.Model, nil
+	}
+
+	if len(chunks) == 1 {
+		summary, usedModel, err := sample("Earnings call transcript:\n\n" + chunks[0])
+		if err != nil {
+			return nil, err
+		}
+		summary.Model = usedModel
+		summary.Chunks = 1
+		return summary, nil
+	}
+
+	partials := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		prompt := fmt.Sprintf("Earnings call transcript, part %d of %d:\n\n%s", i+1, len(chunks), chunk)
+		partial, _, err := sample(prompt)
+		if err != nil {
+			return nil, fmt.Errorf("第%d段: %w", i+1, err)
+		}
+		encoded, _ := json.Marshal(partial)
+		partials[i] = string(encoded)
+	}
+
+	prompt := "Merge these partial summaries of consecutive parts of one earnings call into a single summary, " +
+		"removing duplicates and keeping the most material points:\n\n" + strings.Join(partials, "\n")
+	summary, usedModel, err := sample(prompt)
+	if err != nil {
+		return nil, err
+	}
+	summary.Model = usedModel
+	summary.Chunks = len(chunks)
+	return summary, nil
+}
+
+// earningsSystemPrompt 生成要求模型输出JSON的系统提示词
+func earningsSystemPrompt(lang string) string {
+	language := "English"
+	switch lang {
+	case "zh":
+		language = "Simplified Chinese"
+	case "en", "":
+	default:
+		language = lang
+	}
+
+	return "You are an equity research analyst summarizing earnings calls. " +
+		"Respond with a single JSON object and nothing else, using exactly these keys: " +
+		`"summary" (string, 2-3 sentences), "guidance" (array of strings: forward-looking targets and outlook), ` +
+		`"risks" (array of strings: risks and headwinds mentioned), "highlights" (array of strings: notable results), ` +
+		`"sentiment" ("positive", "neutral" or "negative": management tone), ` +
+		`"sentiment_score" (number from -1 to 1). ` +
+		"Only use information stated in the transcript. Write all text values in " + language + "."
+}
+
+// parseEarningsSummary 解析模型返回的JSON摘要，兼容代码块包裹
+func parseEarningsSummary(text string) (*dto.EarningsSummary, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("模型未返回JSON摘要")
+	}
+
+	var summary dto.EarningsSummary
+	if err := json.Unmarshal([]byte(text[start:end+1]), &summary); err != nil {
+		return nil, fmt.Errorf("解析模型摘要失败: %v", err)
+	}
+
+	summary.Sentiment = strings.ToLower(strings.TrimSpace(summary.Sentiment))
+	switch summary.Sentiment {
+	case "positive", "neutral", "negative":
+	default:
+		summary.Sentiment = "neutral"
+	}
+	summary.SentimentScore = math.Max(-1, math.Min(1, summary.SentimentScore))
+
+	return &summary, nil
+}
+
+// chunkTranscript 按段落将会议记录切分为不超过 maxRunes 个字符的片段
+func chunkTranscript(text string, maxRunes int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentRunes := 0
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+		currentRunes = 0
+	}
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		runes := []rune(paragraph)
+		// 超长段落直接按字符数切分
+		for len(runes) > maxRunes {
+			flush()
+			chunks = append(chunks, string(runes[:maxRunes]))
+			runes = runes[maxRunes:]
+		}
+
+		if currentRunes > 0 && currentRunes+1+len(runes) > maxRunes {
+			flush()
+		}
+		if currentRunes > 0 {
+			current.WriteString("\n")
+			currentRunes++
+		}
+		current.WriteString(string(runes))
+		currentRunes += len(runes)
+	}
+	flush()
+
+	return chunks
+}
+
+// formatEarningsSummary 格式化摘要文本
+func formatEarningsSummary(t *toolTranslator, summary *dto.EarningsSummary) string {
+	var b strings.Builder
+
+	if summary.Symbol != "" {
+		b.WriteString(t.T("stock.earnings.title", map[string]interface{}{"Symbol": summary.Symbol}) + "\n")
+	} else {
+		b.WriteString(t.T("stock.earnings.title_generic", nil) + "\n")
+	}
+	if summary.Year > 0 && summary.Quarter > 0 {
+		b.WriteString(t.T("stock.earnings.period", map[string]interface{}{
+			"Year":    summary.Year,
+			"Quarter": summary.Quarter,
+			"Date":    summary.Date,
+		}) + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(t.T("stock.earnings.section.summary", nil) + "\n")
+	b.WriteString(summary.Summary + "\n\n")
+
+	writeList := func(sectionID string, items []string) {
+		b.WriteString(t.T(sectionID, nil) + "\n")
+		if len(items) == 0 {
+			b.WriteString(t.T("stock.earnings.none", nil) + "\n")
+		}
+		for _, item := range items {
+			b.WriteString("• " + item + "\n")
+		}
+		b.WriteString("\n")
+	}
+	writeList("stock.earnings.section.guidance", summary.Guidance)
+	writeList("stock.earnings.section.risks", summary.Risks)
+	writeList("stock.earnings.section.highlights", summary.Highlights)
+
+	b.WriteString(t.T("stock.earnings.sentiment", map[string]interface{}{
+		"Sentiment": t.T("stock.earnings.sentiment."+summary.Sentiment, nil),
+		"Score":     fmt.Sprintf("%+.2f", summary.SentimentScore),
+	}) + "\n")
+	b.WriteString(t.T("stock.earnings.footer", map[string]interface{}{
+		"Model":  summary.Model,
+		"Chunks": summary.Chunks,
+	}) + "\n\n")
+	b.WriteString(t.T("stock.common.disclaimer", nil))
+
+	return b.String()
+}
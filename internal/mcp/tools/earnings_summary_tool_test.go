@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkTranscript(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		expected []string
+	}{
+		{
+			name:     "Empty text",
+			text:     "  \n\n ",
+			maxRunes: 10,
+			expected: nil,
+		},
+		{
+			name:     "Paragraphs packed into chunks",
+			text:     "abc\n\ndef\nghijk",
+			maxRunes: 8,
+			expected: []string{"abc\ndef", "ghijk"},
+		},
+		{
+			name:     "Long paragraph split by runes",
+			text:     "季度收入增长强劲",
+			maxRunes: 3,
+			expected: []string{"季度收", "入增长", "强劲"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkTranscript(tt.text, tt.maxRunes)
+			if strings.Join(chunks, "|") != strings.Join(tt.expected, "|") || len(chunks) != len(tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, chunks)
+			}
+			for _, chunk := range chunks {
+				if utf8.RuneCountInString(chunk) > tt.maxRunes {
+					t.Errorf("chunk %q exceeds %d runes", chunk, tt.maxRunes)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEarningsSummary(t *testing.T) {
+	text := "```json\n{\"summary\":\"Solid quarter\",\"guidance\":[\"Revenue up 5%\"],\"risks\":[],\"highlights\":[\"Record margin\"],\"sentiment\":\"Positive\",\"sentiment_score\":1.7}\n```"
+
+	summary, err := parseEarningsSummary(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Summary != "Solid quarter" || len(summary.Guidance) != 1 || len(summary.Highlights) != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Sentiment != "positive" {
+		t.Errorf("expected sentiment positive, got %s", summary.Sentiment)
+	}
+	if summary.SentimentScore != 1 {
+		t.Errorf("expected sentiment score clamped to 1, got %.2f", summary.SentimentScore)
+	}
+
+	if summary, err := parseEarningsSummary(`{"summary":"x","sentiment":"bullish"}`); err != nil || summary.Sentiment != "neutral" {
+		t.Errorf("expected unknown sentiment to fall back to neutral, got %+v (err %v)", summary, err)
+	}
+	if _, err := parseEarningsSummary("no json here"); err == nil {
+		t.Error("expected error for non-JSON response")
+	}
+}
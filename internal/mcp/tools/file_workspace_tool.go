@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/workspace"
+)
+
+// 文件工作区工具注册名称
+const (
+	FileReadToolName  = "file_read"
+	FileWriteToolName = "file_write"
+	FileListToolName  = "file_list"
+)
+
+// UserIDFunc 返回工具调用者的用户ID，未认证时为空
+type UserIDFunc func(ctx context.Context) string
+
+// pathProperty 工作区路径参数的 JSON Schema 定义
+func pathProperty(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": description + "，相对于调用者的工作区，例如 'extracts/aapl.csv'",
+		"maxLength":   workspace.MaxPathLength,
+	}
+}
+
+// FileReadTool 读取调用者工作区中的文本文件
+type FileReadTool struct {
+	*mcp.BaseTool
+	workspace *workspace.Manager
+	userID    UserIDFunc
+}
+
+// NewFileReadTool 创建文件读取工具
+func NewFileReadTool(manager *workspace.Manager, userID UserIDFunc) *FileReadTool {
+	return &FileReadTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        FileReadToolName,
+			Description: "读取当前用户文件工作区中的文本文件，例如之前步骤用 file_write 保存的 CSV 导出",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path":   pathProperty("文件路径"),
+					"format": formatProperty(),
+				},
+				"required": []string{"path"},
+			},
+		},
+		workspace: manager,
+		userID:    userID,
+	}
+}
+
+// Execute 读取文件
+func (fr *FileReadTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := fr.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	userID := fr.userID(ctx)
+	if userID == "" {
+		return textErrorResponse("文件工作区需要登录后使用"), nil
+	}
+
+	path, _ := workspace.CleanPath(args["path"].(string))
+	data, err := fr.workspace.Read(userID, path)
+	if err != nil {
+		return textErrorResponse(workspaceErrorMessage("读取文件失败", err)), nil
+	}
+	if !utf8.Valid(data) {
+		return textErrorResponse(fmt.Sprintf("读取文件失败: %s 不是 UTF-8 文本文件", path)), nil
+	}
+
+	result := &dto.WorkspaceReadResult{Path: path, Size: int64(len(data)), Content: string(data)}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: result.Content,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (fr *FileReadTool) Validate(args map[string]interface{}) error {
+	if err := validateWorkspacePath(args, "path", true); err != nil {
+		return err
+	}
+	return validateOutputFormat(args)
+}
+
+// FileWriteTool 向调用者工作区写入文本文件
+type FileWriteTool struct {
+	*mcp.BaseTool
+	workspace *workspace.Manager
+	userID    UserIDFunc
+}
+
+// NewFileWriteTool 创建文件写入工具
+func NewFileWriteTool(manager *workspace.Manager, userID UserIDFunc) *FileWriteTool {
+	return &FileWriteTool{
+		BaseTool: &mcp.BaseTool{
+			Name: FileWriteToolName,
+			Description: fmt.Sprintf("向当前用户的文件工作区写入文本文件，用于在多步骤会话中保存中间结果（如 CSV 导出）。"+
+				"单个文件最大 %d 字节，工作区总大小最大 %d 字节", manager.MaxFileBytes(), manager.QuotaBytes()),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": pathProperty("文件路径，父目录不存在时自动创建"),
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "文件内容",
+					},
+					"append": map[string]interface{}{
+						"type":        "boolean",
+						"description": "是否追加到文件末尾，默认覆盖",
+						"default":     false,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"path", "content"},
+			},
+		},
+		workspace: manager,
+		userID:    userID,
+	}
+}
+
+// Execute 写入文件
+func (fw *FileWriteTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := fw.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	userID := fw.userID(ctx)
+	if userID == "" {
+		return textErrorResponse("文件工作区需要登录后使用"), nil
+	}
+
+	appendData, _ := args["append"].(bool)
+	entry, err := fw.workspace.Write(userID, args["path"].(string), []byte(args["content"].(string)), appendData)
+	if err != nil {
+		return textErrorResponse(workspaceErrorMessage("写入文件失败", err)), nil
+	}
+	usage, err := fw.workspace.Usage(userID)
+	if err != nil {
+		return textErrorResponse(workspaceErrorMessage("写入文件失败", err)), nil
+	}
+
+	result := &dto.WorkspaceWriteResult{
+		Path:       entry.Path,
+		Size:       entry.Size,
+		Appended:   appendData,
+		UsedBytes:  usage.Bytes,
+		QuotaBytes: fw.workspace.QuotaBytes(),
+	}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("💾 已写入 %s (%d 字节)，工作区已用 %d / %d 字节", result.Path, result.Size, result.UsedBytes, result.QuotaBytes),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (fw *FileWriteTool) Validate(args map[string]interface{}) error {
+	if err := validateWorkspacePath(args, "path", true); err != nil {
+		return err
+	}
+	if _, ok := args["content"].(string); !ok {
+		return fmt.Errorf("content 参数是必需的且必须是字符串")
+	}
+	if value, exists := args["append"]; exists {
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("append 必须是布尔值")
+		}
+	}
+	return validateOutputFormat(args)
+}
+
+// FileListTool 列出调用者工作区中的文件
+type FileListTool struct {
+	*mcp.BaseTool
+	workspace *workspace.Manager
+	userID    UserIDFunc
+}
+
+// NewFileListTool 创建文件列表工具
+func NewFileListTool(manager *workspace.Manager, userID UserIDFunc) *FileListTool {
+	return &FileListTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        FileListToolName,
+			Description: "列出当前用户文件工作区中的文件和目录，以及工作区的用量",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"dir": pathProperty("目录路径，默认为工作区根目录"),
+					"recursive": map[string]interface{}{
+						"type":        "boolean",
+						"description": "是否包含子目录中的文件",
+						"default":     false,
+					},
+					"format": formatProperty(),
+				},
+			},
+		},
+		workspace: manager,
+		userID:    userID,
+	}
+}
+
+// Execute 列出文件
+func (fl *FileListTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := fl.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	userID := fl.userID(ctx)
+	if userID == "" {
+		return textErrorResponse("文件工作区需要登录后使用"), nil
+	}
+
+	dir, _ := workspace.CleanPath(stringArg(args, "dir", ""))
+	recursive, _ := args["recursive"].(bool)
+	entries, err := fl.workspace.List(userID, dir, recursive)
+	if err != nil {
+		return textErrorResponse(workspaceErrorMessage("列出文件失败", err)), nil
+	}
+	usage, err := fl.workspace.Usage(userID)
+	if err != nil {
+		return textErrorResponse(workspaceErrorMessage("列出文件失败", err)), nil
+	}
+
+	result := &dto.WorkspaceListResult{
+		Dir:        dir,
+		Files:      make([]dto.WorkspaceFile, 0, len(entries)),
+		UsedBytes:  usage.Bytes,
+		QuotaBytes: fl.workspace.QuotaBytes(),
+	}
+	for _, entry := range entries {
+		result.Files = append(result.Files, dto.WorkspaceFile{
+			Path:       entry.Path,
+			Size:       entry.Size,
+			IsDir:      entry.IsDir,
+			ModifiedAt: entry.ModifiedAt.UTC(),
+		})
+	}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatWorkspaceList(result),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (fl *FileListTool) Validate(args map[string]interface{}) error {
+	if err := validateWorkspacePath(args, "dir", false); err != nil {
+		return err
+	}
+	if value, exists := args["recursive"]; exists {
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("recursive 必须是布尔值")
+		}
+	}
+	return validateOutputFormat(args)
+}
+
+// validateWorkspacePath 验证工作区路径参数
+func validateWorkspacePath(args map[string]interface{}, name string, required bool) error {
+	value, exists := args[name]
+	if !exists && !required {
+		return nil
+	}
+	str, ok := value.(string)
+	if !ok || (required && strings.TrimSpace(str) == "") {
+		return fmt.Errorf("%s 参数是必需的且必须是字符串", name)
+	}
+	if _, err := workspace.CleanPath(str); err != nil {
+		return fmt.Errorf("%s 必须是工作区内的相对路径，不能包含 '..'", name)
+	}
+	return nil
+}
+
+// workspaceErrorMessage 将工作区错误转换为面向用户的消息
+func workspaceErrorMessage(action string, err error) string {
+	switch {
+	case errors.Is(err, workspace.ErrNotFound):
+		return action + ": 文件或目录不存在"
+	case errors.Is(err, workspace.ErrInvalidPath):
+		return action + ": 路径超出工作区"
+	case errors.Is(err, workspace.ErrQuotaExceeded), errors.Is(err, workspace.ErrFileTooLarge):
+		return fmt.Sprintf("%s: 超出工作区配额 (%v)", action, err)
+	}
+	return fmt.Sprintf("%s: %v", action, err)
+}
+
+// formatWorkspaceList 格式化文件列表
+func formatWorkspaceList(result *dto.WorkspaceListResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📁 %s (已用 %d / %d 字节)\n", result.Dir, result.UsedBytes, result.QuotaBytes))
+	if len(result.Files) == 0 {
+		sb.WriteString("(空)")
+		return sb.String()
+	}
+	for _, file := range result.Files {
+		if file.IsDir {
+			sb.WriteString(fmt.Sprintf("  %s/\n", file.Path))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s  %d 字节  %s\n", file.Path, file.Size, file.ModifiedAt.Format("2006-01-02 15:04:05")))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/workspace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type workspaceUserKey struct{}
+
+func TestFileWorkspaceTools(t *testing.T) {
+	manager, err := workspace.New(workspace.Config{Root: t.TempDir(), QuotaBytes: 48, MaxFileBytes: 32})
+	require.NoError(t, err)
+	userID := func(ctx context.Context) string {
+		id, _ := ctx.Value(workspaceUserKey{}).(string)
+		return id
+	}
+	read := NewFileReadTool(manager, userID)
+	write := NewFileWriteTool(manager, userID)
+	list := NewFileListTool(manager, userID)
+
+	alice := context.WithValue(context.Background(), workspaceUserKey{}, "1")
+	bob := context.WithValue(context.Background(), workspaceUserKey{}, "2")
+
+	resp, err := write.Execute(alice, map[string]interface{}{"path": "extracts/aapl.csv", "content": "date,close\n"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	resp, err = write.Execute(alice, map[string]interface{}{"path": "extracts/aapl.csv", "content": "2024-01-02,185.64\n", "append": true, "format": "json"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"path":"extracts/aapl.csv","size":29,"appended":true,"used_bytes":29,"quota_bytes":48}`, resp.Content[0].Text)
+
+	resp, err = read.Execute(alice, map[string]interface{}{"path": "./extracts/aapl.csv"})
+	require.NoError(t, err)
+	assert.Equal(t, "date,close\n2024-01-02,185.64\n", resp.Content[0].Text)
+
+	resp, err = list.Execute(alice, map[string]interface{}{"recursive": true})
+	require.NoError(t, err)
+	files := resp.Content[0].Data.(*dto.WorkspaceListResult).Files
+	require.Len(t, files, 2)
+	assert.Equal(t, "extracts", files[0].Path)
+	assert.True(t, files[0].IsDir)
+	assert.Equal(t, int64(29), files[1].Size)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		tool interface {
+			Execute(context.Context, map[string]interface{}) (*dto.MCPExecuteResponse, error)
+		}
+		args    map[string]interface{}
+		message string
+	}{
+		{"other user", bob, read, map[string]interface{}{"path": "extracts/aapl.csv"}, "不存在"},
+		{"unauthenticated", context.Background(), list, map[string]interface{}{}, "需要登录"},
+		{"path traversal", alice, read, map[string]interface{}{"path": "../2/extracts/aapl.csv"}, "相对路径"},
+		{"absolute path", alice, write, map[string]interface{}{"path": "/etc/cron.d/x", "content": "x"}, "相对路径"},
+		{"file too large", alice, write, map[string]interface{}{"path": "big.txt", "content": string(make([]byte, 33))}, "超出工作区配额"},
+		{"quota", alice, write, map[string]interface{}{"path": "more.txt", "content": string(make([]byte, 32))}, "超出工作区配额"},
+		{"read directory", alice, read, map[string]interface{}{"path": "extracts"}, "is a directory"},
+		{"invalid append", alice, write, map[string]interface{}{"path": "x.bin", "content": "ok", "append": "yes"}, "append"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.tool.Execute(tt.ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go-springAi/internal/dto"
+)
+
+// 工具输出格式
+const (
+	outputFormatText = "text" // 面向用户的格式化报告（默认）
+	outputFormatJSON = "json" // 规范 JSON，用于黄金文件测试和下游解析
+)
+
+// formatProperty 输出格式参数的 JSON Schema 定义
+func formatProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "输出格式: 'text' (格式化报告), 'json' (结构化结果写入 content[].data，text 为其规范 JSON 编码)",
+		"enum":        []string{outputFormatText, outputFormatJSON},
+		"default":     outputFormatText,
+	}
+}
+
+// validateOutputFormat 验证 format 参数
+func validateOutputFormat(args map[string]interface{}) error {
+	value, exists := args["format"]
+	if !exists {
+		return nil
+	}
+	if format, ok := value.(string); !ok || (format != outputFormatText && format != outputFormatJSON) {
+		return fmt.Errorf("format 必须是以下值之一: %v", []string{outputFormatText, outputFormatJSON})
+	}
+	return nil
+}
+
+// wantsJSON 判断调用方是否要求 JSON 输出
+func wantsJSON(args map[string]interface{}) bool {
+	format, _ := args["format"].(string)
+	return format == outputFormatJSON
+}
+
+// jsonResponse 构建 JSON 格式的工具响应。结果不含生成时间等易变字段，
+// 结构体字段按声明顺序、map 键按字典序编码，相同输入得到逐字节相同的输出
+func jsonResponse(data interface{}) *dto.MCPExecuteResponse {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("编码JSON结果失败: %v", err))
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: string(encoded),
+				Data: data,
+			},
+		},
+		IsError: false,
+	}
+}
+
+// textErrorResponse 构建纯文本的工具错误响应
+func textErrorResponse(message string) *dto.MCPExecuteResponse {
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: true,
+	}
+}
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "重新生成 testdata/golden 下的黄金文件")
+
+// stubQuotes 桩行情: 价格, 前收盘价, 成交量, 市值, 市盈率, 板块, 币种
+var stubQuotes = map[string]struct {
+	price, previousClose float64
+	volume               int64
+	marketCap, pe        float64
+	sector, currency     string
+}{
+	"AAPL":   {189.5, 187.25, 51234567, 2.95e12, 29.4, "Technology", "USD"},
+	"KO":     {58.1, 61.9, 812345, 2.5e11, 23.1, "Consumer Defensive", "USD"},
+	"SAP.DE": {231.4, 228.9, 1534000, 2.7e11, 41.2, "Technology", "EUR"},
+}
+
+// stubYahoo 按路径返回固定行情的上游，历史数据使用固定时间戳，与请求的时间范围无关
+func stubYahoo(t *testing.T) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		symbol := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		quote, ok := stubQuotes[symbol]
+		require.True(t, ok, "unexpected symbol %s", symbol)
+
+		var body string
+		switch {
+		case strings.Contains(req.URL.Path, "/v8/finance/chart/"):
+			body = fmt.Sprintf(`{"chart":{"result":[{"meta":{"currency":%[7]q,"symbol":%[1]q,"exchangeName":"NMS","regularMarketPrice":%g,"previousClose":%g,"regularMarketDayHigh":%g,"regularMarketDayLow":%g,"regularMarketVolume":%d,"regularMarketTime":1767225600},"timestamp":[1767052800,1767139200,1767225600],"indicators":{"quote":[{"open":[%[3]g,%[3]g,%[3]g],"high":[%[4]g,%[4]g,%[4]g],"low":[%[5]g,%[5]g,%[5]g],"close":[%[3]g,%[3]g,%[2]g],"volume":[1000,2000,3000]}]}}],"error":null}}`,
+				symbol, quote.price, quote.previousClose, quote.price+1, quote.previousClose-1, quote.volume, quote.currency)
+		case strings.Contains(req.URL.Path, "/v10/finance/quoteSummary/"):
+			body = fmt.Sprintf(`{"quoteSummary":{"result":[{"summaryProfile":{"longName":"%s Inc.","industry":"Industry","sector":%q,"country":"United States","website":"https://example.com","fullTimeEmployees":1000},"summaryDetail":{"marketCap":{"raw":%g},"trailingPE":{"raw":%g}}}],"error":null}}`,
+				symbol, quote.sector, quote.marketCap, quote.pe)
+		default:
+			t.Fatalf("unexpected request %s", req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestJSONOutputGolden(t *testing.T) {
+	transport := stubYahoo(t)
+	yahoo := NewYahooFinanceTool()
+	yahoo.httpClient.Transport = transport
+	analysis := NewStockAnalysisTool(nil)
+	analysis.yahooTool.httpClient.Transport = transport
+	compare := NewStockCompareTool(nil)
+	compare.yahooTool.httpClient.Transport = transport
+	advice := NewStockAdviceTool(nil)
+	advice.yahooTool.httpClient.Transport = transport
+
+	cases := []struct {
+		golden  string
+		execute func(context.Context, map[string]interface{}) (*dto.MCPExecuteResponse, error)
+		args    map[string]interface{}
+	}{
+		{"yahoo_quote", yahoo.Execute, map[string]interface{}{"action": "quote", "symbol": "aapl", "format": "json"}},
+		{"yahoo_info", yahoo.Execute, map[string]interface{}{"action": "info", "symbol": "AAPL", "format": "json"}},
+		{"stock_analysis", analysis.Execute, map[string]interface{}{"symbol": "AAPL", "format": "json"}},
+		{"stock_compare", compare.Execute, map[string]interface{}{"symbols": []interface{}{"KO", "AAPL"}, "format": "json"}},
+		{"stock_advice", advice.Execute, map[string]interface{}{"symbol": "AAPL", "investment_amount": float64(1000), "format": "json"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			first, err := tc.execute(context.Background(), tc.args)
+			require.NoError(t, err)
+			require.False(t, first.IsError, first.Content[0].Text)
+			require.Len(t, first.Content, 1)
+
+			// Text 是 Data 的规范编码，重复调用逐字节相同
+			encoded, err := json.Marshal(first.Content[0].Data)
+			require.NoError(t, err)
+			assert.Equal(t, string(encoded), first.Content[0].Text)
+			second, err := tc.execute(context.Background(), tc.args)
+			require.NoError(t, err)
+			assert.Equal(t, first.Content[0].Text, second.Content[0].Text)
+
+			var got bytes.Buffer
+			require.NoError(t, json.Indent(&got, []byte(first.Content[0].Text), "", "  "))
+			got.WriteString("\n")
+			path := filepath.Join("testdata", "golden", tc.golden+".json")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, got.Bytes(), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "run go test ./internal/mcp/tools -run TestJSONOutputGolden -update to create it")
+			assert.Equal(t, string(want), got.String())
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	yahoo := NewYahooFinanceTool()
+	assert.NoError(t, yahoo.Validate(map[string]interface{}{"action": "quote", "symbol": "AAPL", "format": "text"}))
+	assert.Error(t, yahoo.Validate(map[string]interface{}{"action": "quote", "symbol": "AAPL", "format": "xml"}))
+	assert.Error(t, NewStockAnalysisTool(nil).Validate(map[string]interface{}{"symbol": "AAPL", "format": 1}))
+
+	chart := NewStockChartTool()
+	assert.NoError(t, chart.Validate(map[string]interface{}{"symbol": "AAPL", "format": "json"}))
+}
@@ -0,0 +1,733 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// GitHubToolName GitHub 工具注册名称
+const GitHubToolName = "github"
+
+// GitHubKeyProvider GitHub 令牌在 API 密钥服务中的提供商类型
+const GitHubKeyProvider = "github"
+
+// DefaultGitHubAPIURL GitHub REST API 地址，GitHub Enterprise 使用 https://<host>/api/v3
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// github 工具支持的操作
+const (
+	githubActionListIssues  = "list_issues"
+	githubActionCreateIssue = "create_issue"
+	githubActionPRStatus    = "pr_status"
+	githubActionGetFile     = "get_file"
+)
+
+var githubActions = []string{githubActionListIssues, githubActionCreateIssue, githubActionPRStatus, githubActionGetFile}
+
+const (
+	// defaultGitHubIssues 默认列出的 issue 数量
+	defaultGitHubIssues = 20
+	// maxGitHubIssues 单次列出的最大 issue 数量
+	maxGitHubIssues = 100
+	// DefaultGitHubMaxFileBytes 默认返回的最大文件字节数
+	DefaultGitHubMaxFileBytes = 256 * 1024
+	// maxGitHubBodyLength 创建 issue 时正文的最大长度
+	maxGitHubBodyLength = 65536
+)
+
+// TokenFunc 获取调用者在外部服务上的访问令牌，未配置时返回空字符串
+type TokenFunc func(ctx context.Context) (string, error)
+
+// GitHubTool GitHub 工具，在配置的仓库中列出和创建 issue、查看 PR 状态、读取文件内容，
+// 让助手参与工程流程。令牌优先使用调用者通过 API 密钥接口保存的密钥
+type GitHubTool struct {
+	*mcp.BaseTool
+	baseURL      string
+	repos        []string
+	maxFileBytes int
+	token        TokenFunc
+	userID       UserIDFunc
+	httpClient   *http.Client
+}
+
+// NewGitHubTool 创建 GitHub 工具，repos 为允许访问的仓库（owner/name），baseURL 为空时使用 github.com
+func NewGitHubTool(baseURL string, repos []string, maxFileBytes int, token TokenFunc, userID UserIDFunc) *GitHubTool {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultGitHubMaxFileBytes
+	}
+	return &GitHubTool{
+		BaseTool: &mcp.BaseTool{
+			Name: GitHubToolName,
+			Description: "访问 GitHub 仓库：列出 issue、创建 issue、查看 PR 的 CI 和评审状态、读取文件或目录内容。可用的仓库: " +
+				strings.Join(repos, ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: 'list_issues', 'create_issue', 'pr_status' (PR 状态), 'get_file' (读取文件或目录)",
+						"enum":        githubActions,
+					},
+					"repo": map[string]interface{}{
+						"type":        "string",
+						"description": "仓库 (owner/name)，只配置了一个仓库时可省略",
+						"enum":        repos,
+					},
+					"state": map[string]interface{}{
+						"type":        "string",
+						"description": "list_issues 的状态过滤",
+						"enum":        []string{"open", "closed", "all"},
+						"default":     "open",
+					},
+					"labels": map[string]interface{}{
+						"type":        "array",
+						"description": "list_issues 的标签过滤（同时具有所有标签），或 create_issue 添加的标签",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("list_issues 返回的数量 (1-%d)", maxGitHubIssues),
+						"minimum":     1,
+						"maximum":     maxGitHubIssues,
+						"default":     defaultGitHubIssues,
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "create_issue 的标题",
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "create_issue 的正文 (Markdown)",
+						"maxLength":   maxGitHubBodyLength,
+					},
+					"number": map[string]interface{}{
+						"type":        "integer",
+						"description": "pr_status 的 PR 编号",
+						"minimum":     1,
+					},
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "get_file 的文件或目录路径，相对于仓库根目录",
+					},
+					"ref": map[string]interface{}{
+						"type":        "string",
+						"description": "get_file 的分支、标签或提交，默认为仓库默认分支",
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"action"},
+			},
+		},
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		repos:        repos,
+		maxFileBytes: maxFileBytes,
+		token:        token,
+		userID:       userID,
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// Execute 执行 GitHub 操作
+func (gt *GitHubTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := gt.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	action := args["action"].(string)
+	if action == githubActionCreateIssue && gt.userID(ctx) == "" {
+		return textErrorResponse("创建 issue 需要登录后使用"), nil
+	}
+	token, err := gt.token(ctx)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("获取 GitHub 令牌失败: %v", err)), nil
+	}
+	if token == "" {
+		return textErrorResponse("未配置 GitHub 令牌"), nil
+	}
+
+	repo := gt.repo(args)
+	var result interface{}
+	var text string
+	switch action {
+	case githubActionListIssues:
+		list, err := gt.listIssues(ctx, token, repo, stringArg(args, "state", "open"), intArg(args, "limit", defaultGitHubIssues), args)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("列出 issue 失败: %v", err)), nil
+		}
+		result, text = list, formatGitHubIssues(list)
+	case githubActionCreateIssue:
+		issue, err := gt.createIssue(ctx, token, repo, args)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("创建 issue 失败: %v", err)), nil
+		}
+		result, text = issue, fmt.Sprintf("✅ 已在 %s 创建 issue #%d: %s\n%s", repo, issue.Number, issue.Title, issue.URL)
+	case githubActionPRStatus:
+		status, err := gt.prStatus(ctx, token, repo, intArg(args, "number", 0))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("获取 PR 状态失败: %v", err)), nil
+		}
+		result, text = status, formatGitHubPRStatus(status)
+	default:
+		file, err := gt.getFile(ctx, token, repo, strings.Trim(args["path"].(string), "/"), stringArg(args, "ref", ""))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("读取文件失败: %v", err)), nil
+		}
+		result, text = file, formatGitHubFile(file)
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (gt *GitHubTool) Validate(args map[string]interface{}) error {
+	action, ok := args["action"].(string)
+	if !ok || !containsString(githubActions, action) {
+		return fmt.Errorf("action 必须是以下值之一: %v", githubActions)
+	}
+
+	for _, name := range []string{"repo", "state", "title", "body", "path", "ref"} {
+		if value, exists := args[name]; exists {
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s 必须是字符串", name)
+			}
+		}
+	}
+	if _, exists := args["repo"]; !exists && len(gt.repos) > 1 {
+		return fmt.Errorf("配置了多个仓库时 repo 参数是必需的，可用: %v", gt.repos)
+	}
+	if repo := gt.repo(args); !containsString(gt.repos, repo) {
+		return fmt.Errorf("仓库 %s 不在允许列表中，可用: %v", repo, gt.repos)
+	}
+	if _, err := stringSliceArg(args, "labels"); err != nil {
+		return err
+	}
+
+	switch action {
+	case githubActionListIssues:
+		if state := stringArg(args, "state", "open"); !containsString([]string{"open", "closed", "all"}, state) {
+			return fmt.Errorf("state 必须是 open、closed 或 all")
+		}
+		if _, exists := args["limit"]; exists {
+			if limit := intArg(args, "limit", 0); limit < 1 || limit > maxGitHubIssues {
+				return fmt.Errorf("limit 必须在 1 到 %d 之间", maxGitHubIssues)
+			}
+		}
+	case githubActionCreateIssue:
+		if strings.TrimSpace(stringArg(args, "title", "")) == "" {
+			return fmt.Errorf("create_issue 操作需要 title 参数")
+		}
+		if len(stringArg(args, "body", "")) > maxGitHubBodyLength {
+			return fmt.Errorf("body 不能超过 %d 个字符", maxGitHubBodyLength)
+		}
+	case githubActionPRStatus:
+		if intArg(args, "number", 0) < 1 {
+			return fmt.Errorf("pr_status 操作需要正整数 number 参数")
+		}
+	case githubActionGetFile:
+		path, ok := args["path"].(string)
+		if !ok {
+			return fmt.Errorf("get_file 操作需要 path 参数")
+		}
+		for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+			if segment == ".." || segment == "." || strings.ContainsAny(segment, "\\\x00") {
+				return fmt.Errorf("path 不能包含 '.'、'..'、反斜杠或 NUL 字符")
+			}
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// repo 返回请求的仓库，只配置了一个仓库时可省略
+func (gt *GitHubTool) repo(args map[string]interface{}) string {
+	if len(gt.repos) == 1 {
+		return stringArg(args, "repo", gt.repos[0])
+	}
+	return stringArg(args, "repo", "")
+}
+
+// githubIssuePayload GitHub issue 响应
+type githubIssuePayload struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Body   string `json:"body"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Comments    int             `json:"comments"`
+	HTMLURL     string          `json:"html_url"`
+	PullRequest json.RawMessage `json:"pull_request"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (p *githubIssuePayload) issue() dto.GitHubIssue {
+	issue := dto.GitHubIssue{
+		Number:    p.Number,
+		Title:     p.Title,
+		State:     p.State,
+		Author:    p.User.Login,
+		Labels:    []string{},
+		Assignees: []string{},
+		Comments:  p.Comments,
+		URL:       p.HTMLURL,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+	for _, label := range p.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	for _, assignee := range p.Assignees {
+		issue.Assignees = append(issue.Assignees, assignee.Login)
+	}
+	return issue
+}
+
+// listIssues 列出 issue，GitHub 的 issue 接口同时返回 PR，这里过滤掉
+func (gt *GitHubTool) listIssues(ctx context.Context, token, repo, state string, limit int, args map[string]interface{}) (*dto.GitHubIssueList, error) {
+	params := url.Values{}
+	params.Set("state", state)
+	params.Set("per_page", strconv.Itoa(limit))
+	if labels, _ := stringSliceArg(args, "labels"); len(labels) > 0 {
+		params.Set("labels", strings.Join(labels, ","))
+	}
+
+	var payload []githubIssuePayload
+	if err := gt.do(ctx, token, http.MethodGet, "/repos/"+repo+"/issues?"+params.Encode(), nil, &payload); err != nil {
+		return nil, err
+	}
+	result := &dto.GitHubIssueList{Repo: repo, State: state, Issues: []dto.GitHubIssue{}}
+	for i := range payload {
+		if payload[i].PullRequest != nil {
+			continue
+		}
+		result.Issues = append(result.Issues, payload[i].issue())
+	}
+	return result, nil
+}
+
+// createIssue 创建 issue
+func (gt *GitHubTool) createIssue(ctx context.Context, token, repo string, args map[string]interface{}) (*dto.GitHubIssue, error) {
+	request := map[string]interface{}{
+		"title": strings.TrimSpace(args["title"].(string)),
+		"body":  stringArg(args, "body", ""),
+	}
+	if labels, _ := stringSliceArg(args, "labels"); len(labels) > 0 {
+		request["labels"] = labels
+	}
+
+	var payload githubIssuePayload
+	if err := gt.do(ctx, token, http.MethodPost, "/repos/"+repo+"/issues", request, &payload); err != nil {
+		return nil, err
+	}
+	issue := payload.issue()
+	return &issue, nil
+}
+
+// prStatus 汇总 PR 的合并状态、CI 检查和评审结论
+func (gt *GitHubTool) prStatus(ctx context.Context, token, repo string, number int) (*dto.GitHubPRStatus, error) {
+	var pr struct {
+		Number         int    `json:"number"`
+		Title          string `json:"title"`
+		State          string `json:"state"`
+		Draft          bool   `json:"draft"`
+		Merged         bool   `json:"merged"`
+		Mergeable      *bool  `json:"mergeable"`
+		MergeableState string `json:"mergeable_state"`
+		HTMLURL        string `json:"html_url"`
+		Additions      int    `json:"additions"`
+		Deletions      int    `json:"deletions"`
+		ChangedFiles   int    `json:"changed_files"`
+		User           struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	}
+	prPath := fmt.Sprintf("/repos/%s/pulls/%d", repo, number)
+	if err := gt.do(ctx, token, http.MethodGet, prPath, nil, &pr); err != nil {
+		return nil, err
+	}
+
+	var checkRuns struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := gt.do(ctx, token, http.MethodGet, "/repos/"+repo+"/commits/"+pr.Head.SHA+"/check-runs?per_page=100", nil, &checkRuns); err != nil {
+		return nil, err
+	}
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := gt.do(ctx, token, http.MethodGet, "/repos/"+repo+"/commits/"+pr.Head.SHA+"/status", nil, &combined); err != nil {
+		return nil, err
+	}
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	if err := gt.do(ctx, token, http.MethodGet, prPath+"/reviews?per_page=100", nil, &reviews); err != nil {
+		return nil, err
+	}
+
+	status := &dto.GitHubPRStatus{
+		Repo:           repo,
+		Number:         pr.Number,
+		Title:          pr.Title,
+		State:          pr.State,
+		Draft:          pr.Draft,
+		Merged:         pr.Merged,
+		Mergeable:      pr.Mergeable,
+		MergeableState: pr.MergeableState,
+		Author:         pr.User.Login,
+		Head:           pr.Head.Ref,
+		Base:           pr.Base.Ref,
+		HeadSHA:        pr.Head.SHA,
+		Additions:      pr.Additions,
+		Deletions:      pr.Deletions,
+		ChangedFiles:   pr.ChangedFiles,
+		Checks:         []dto.GitHubCheck{},
+		Reviews:        []dto.GitHubReview{},
+		URL:            pr.HTMLURL,
+	}
+	for _, run := range checkRuns.CheckRuns {
+		status.Checks = append(status.Checks, dto.GitHubCheck{Name: run.Name, Status: run.Status, Conclusion: run.Conclusion, URL: run.HTMLURL})
+	}
+	// commit status 的 state 为 success / failure / error / pending，统一为 check run 的表示
+	for _, s := range combined.Statuses {
+		check := dto.GitHubCheck{Name: s.Context, Status: "completed", Conclusion: s.State, URL: s.TargetURL}
+		switch s.State {
+		case "pending":
+			check.Status, check.Conclusion = "in_progress", ""
+		case "error":
+			check.Conclusion = "failure"
+		}
+		status.Checks = append(status.Checks, check)
+	}
+	status.CIState = githubCIState(status.Checks)
+
+	// 每个评审者只保留最新的结论，之后的普通评论不覆盖批准或要求修改
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		if review.State == "COMMENTED" && latest[review.User.Login] != "" {
+			continue
+		}
+		latest[review.User.Login] = review.State
+	}
+	for reviewer, state := range latest {
+		status.Reviews = append(status.Reviews, dto.GitHubReview{Reviewer: reviewer, State: state})
+	}
+	sort.Slice(status.Reviews, func(i, j int) bool { return status.Reviews[i].Reviewer < status.Reviews[j].Reviewer })
+	return status, nil
+}
+
+// githubCIState 汇总所有检查的结果
+func githubCIState(checks []dto.GitHubCheck) string {
+	if len(checks) == 0 {
+		return "none"
+	}
+	state := "success"
+	for _, check := range checks {
+		switch {
+		case containsString([]string{"failure", "cancelled", "timed_out", "action_required", "startup_failure"}, check.Conclusion):
+			return "failure"
+		case check.Status != "completed":
+			state = "pending"
+		}
+	}
+	return state
+}
+
+// getFile 读取文件内容或目录列表
+func (gt *GitHubTool) getFile(ctx context.Context, token, repo, path, ref string) (*dto.GitHubFile, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	contentsPath := "/repos/" + repo + "/contents/" + strings.Join(segments, "/")
+	if ref != "" {
+		contentsPath += "?ref=" + url.QueryEscape(ref)
+	}
+
+	var raw json.RawMessage
+	if err := gt.do(ctx, token, http.MethodGet, contentsPath, nil, &raw); err != nil {
+		return nil, err
+	}
+	file := &dto.GitHubFile{Repo: repo, Path: path, Ref: ref}
+
+	// 目录返回数组
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		var entries []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("解析响应失败: %w", err)
+		}
+		file.Type, file.Entries = "dir", make([]string, 0, len(entries))
+		for _, entry := range entries {
+			name := entry.Name
+			if entry.Type == "dir" {
+				name += "/"
+			}
+			file.Entries = append(file.Entries, name)
+		}
+		return file, nil
+	}
+
+	var payload struct {
+		Type     string `json:"type"`
+		Size     int    `json:"size"`
+		SHA      string `json:"sha"`
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if payload.Type != "file" {
+		return nil, fmt.Errorf("%s 是 %s，不是文件", path, payload.Type)
+	}
+	file.Type, file.Size, file.SHA, file.URL = "file", payload.Size, payload.SHA, payload.HTMLURL
+
+	var content []byte
+	if payload.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(payload.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("解码文件内容失败: %w", err)
+		}
+		content = decoded
+	} else {
+		// 超过 1MB 的文件不随响应返回内容，改为读取原始内容
+		data, err := gt.raw(ctx, token, contentsPath)
+		if err != nil {
+			return nil, err
+		}
+		content = data
+	}
+
+	if len(content) > gt.maxFileBytes {
+		content = content[:gt.maxFileBytes]
+		// 不在多字节字符中间截断
+		for len(content) > 0 && !utf8.Valid(content) {
+			content = content[:len(content)-1]
+		}
+		file.Truncated = true
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		file.Content = fmt.Sprintf("<binary %d bytes>", file.Size)
+	} else {
+		file.Content = string(content)
+	}
+	return file, nil
+}
+
+// raw 以原始媒体类型读取文件内容，最多读取 maxFileBytes+1 字节
+func (gt *GitHubTool) raw(ctx context.Context, token, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gt.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	gt.setHeaders(req, token)
+	req.Header.Set("Accept", "application/vnd.github.raw+json")
+
+	resp, err := gt.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub 返回状态码 %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(gt.maxFileBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	return data, nil
+}
+
+// do 调用 GitHub REST API 并解析 JSON 响应
+func (gt *GitHubTool) do(ctx context.Context, token, method, path string, request, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("编码请求失败: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, gt.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	gt.setHeaders(req, token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := gt.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		message := truncateRunes(strings.TrimSpace(string(data)), 200)
+		if json.Unmarshal(data, &apiError) == nil && apiError.Message != "" {
+			message = apiError.Message
+		}
+		// 私有仓库没有权限时 GitHub 也返回 404
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("GitHub 返回 404 (不存在或令牌没有访问权限): %s", message)
+		}
+		return fmt.Errorf("GitHub 返回状态码 %d: %s", resp.StatusCode, message)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+func (gt *GitHubTool) setHeaders(req *http.Request, token string) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+}
+
+// formatGitHubIssues 格式化 issue 列表
+func formatGitHubIssues(list *dto.GitHubIssueList) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🐙 %s 的 issue (%s, %d)\n", list.Repo, list.State, len(list.Issues)))
+	if len(list.Issues) == 0 {
+		sb.WriteString("(没有匹配的 issue)")
+		return sb.String()
+	}
+	for _, issue := range list.Issues {
+		sb.WriteString(fmt.Sprintf("- #%d %s [%s] @%s", issue.Number, issue.Title, issue.State, issue.Author))
+		if len(issue.Labels) > 0 {
+			sb.WriteString(" 标签: " + strings.Join(issue.Labels, ", "))
+		}
+		sb.WriteString(fmt.Sprintf(" 更新于 %s\n", issue.UpdatedAt.Format("2006-01-02")))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatGitHubPRStatus 格式化 PR 状态
+func formatGitHubPRStatus(status *dto.GitHubPRStatus) string {
+	var sb strings.Builder
+	state := status.State
+	switch {
+	case status.Merged:
+		state = "merged"
+	case status.Draft:
+		state += ", draft"
+	}
+	sb.WriteString(fmt.Sprintf("🐙 %s#%d %s [%s]\n", status.Repo, status.Number, status.Title, state))
+	sb.WriteString(fmt.Sprintf("@%s: %s → %s，+%d/-%d，%d 个文件\n", status.Author, status.Head, status.Base,
+		status.Additions, status.Deletions, status.ChangedFiles))
+	if !status.Merged && status.State == "open" {
+		mergeable := "计算中"
+		if status.Mergeable != nil {
+			mergeable = strconv.FormatBool(*status.Mergeable)
+		}
+		sb.WriteString(fmt.Sprintf("可合并: %s (%s)\n", mergeable, status.MergeableState))
+	}
+
+	sb.WriteString(fmt.Sprintf("CI: %s\n", status.CIState))
+	for _, check := range status.Checks {
+		result := check.Conclusion
+		if result == "" {
+			result = check.Status
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", check.Name, result))
+	}
+	if len(status.Reviews) == 0 {
+		sb.WriteString("评审: (无)")
+	} else {
+		reviews := make([]string, 0, len(status.Reviews))
+		for _, review := range status.Reviews {
+			reviews = append(reviews, review.Reviewer+" "+review.State)
+		}
+		sb.WriteString("评审: " + strings.Join(reviews, ", "))
+	}
+	return sb.String()
+}
+
+// formatGitHubFile 格式化文件内容或目录列表
+func formatGitHubFile(file *dto.GitHubFile) string {
+	location := file.Repo + "/" + file.Path
+	if file.Ref != "" {
+		location += "@" + file.Ref
+	}
+	if file.Type == "dir" {
+		return fmt.Sprintf("🐙 %s/\n%s", strings.TrimSuffix(location, "/"), strings.Join(file.Entries, "\n"))
+	}
+	text := fmt.Sprintf("🐙 %s (%d 字节)\n%s", location, file.Size, file.Content)
+	if file.Truncated {
+		text += "\n— 文件超过大小上限已截断"
+	}
+	return text
+}
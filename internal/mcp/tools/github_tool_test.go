@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubTool(t *testing.T) {
+	var created map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/api/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer user-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "bug,p1", r.URL.Query().Get("labels"))
+		w.Write([]byte(`[{"number":7,"title":"Crash on login","state":"open","user":{"login":"ann"},"labels":[{"name":"bug"}],"updated_at":"2026-10-01T00:00:00Z"},
+			{"number":8,"title":"Fix crash","state":"open","user":{"login":"bob"},"pull_request":{"url":"x"}}]`))
+	})
+	mux.HandleFunc("POST /repos/acme/api/issues", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":9,"title":"Flaky test","state":"open","html_url":"https://github.com/acme/api/issues/9","user":{"login":"bot"}}`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/pulls/8", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"number":8,"title":"Fix crash","state":"open","mergeable":true,"mergeable_state":"blocked","user":{"login":"bob"},
+			"head":{"ref":"fix","sha":"abc"},"base":{"ref":"main"},"additions":10,"deletions":2,"changed_files":1}`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"check_runs":[{"name":"test","status":"completed","conclusion":"success"},{"name":"lint","status":"in_progress"}]}`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/commits/abc/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"statuses":[{"context":"ci/legacy","state":"success"}]}`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/pulls/8/reviews", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"user":{"login":"carol"},"state":"CHANGES_REQUESTED"},{"user":{"login":"carol"},"state":"APPROVED"},{"user":{"login":"carol"},"state":"COMMENTED"}]`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/contents/docs/README.md", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "v1", r.URL.Query().Get("ref"))
+		content := base64.StdEncoding.EncodeToString([]byte("# Hello world"))
+		w.Write([]byte(`{"type":"file","size":13,"sha":"f1","encoding":"base64","content":"` + content + `"}`))
+	})
+	mux.HandleFunc("GET /repos/acme/api/contents/docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"README.md","type":"file"},{"name":"img","type":"dir"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	userID := "1"
+	tool := NewGitHubTool(server.URL, []string{"acme/api"}, 8, func(ctx context.Context) (string, error) {
+		return "user-token", nil
+	}, func(ctx context.Context) string { return userID })
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{"action": "list_issues", "labels": []interface{}{"bug", "p1"}})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, "🐙 acme/api 的 issue (open, 1)\n- #7 Crash on login [open] @ann 标签: bug 更新于 2026-10-01", resp.Content[0].Text)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "create_issue", "title": " Flaky test ", "body": "Seen twice", "labels": []interface{}{"ci"}})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, map[string]interface{}{"title": "Flaky test", "body": "Seen twice", "labels": []interface{}{"ci"}}, created)
+	assert.Equal(t, 9, resp.Content[0].Data.(*dto.GitHubIssue).Number)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "pr_status", "number": 8})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	status := resp.Content[0].Data.(*dto.GitHubPRStatus)
+	assert.Equal(t, "pending", status.CIState)
+	assert.Len(t, status.Checks, 3)
+	assert.Equal(t, []dto.GitHubReview{{Reviewer: "carol", State: "APPROVED"}}, status.Reviews)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "get_file", "path": "/docs/README.md", "ref": "v1"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	file := resp.Content[0].Data.(*dto.GitHubFile)
+	assert.Equal(t, "# Hello ", file.Content)
+	assert.True(t, file.Truncated)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "get_file", "path": "docs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "img/"}, resp.Content[0].Data.(*dto.GitHubFile).Entries)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"repo not allowed", map[string]interface{}{"action": "list_issues", "repo": "acme/secret"}, "不在允许列表中"},
+		{"path traversal", map[string]interface{}{"action": "get_file", "path": "docs/../../x"}, "path"},
+		{"missing title", map[string]interface{}{"action": "create_issue"}, "title"},
+		{"missing number", map[string]interface{}{"action": "pr_status"}, "number"},
+		{"not found", map[string]interface{}{"action": "pr_status", "number": 404}, "404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	userID = ""
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "create_issue", "title": "x"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "登录")
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
+)
+
+// toolTranslator 工具报告文本翻译器
+type toolTranslator struct {
+	manager *i18n.Manager
+	lang    string
+}
+
+// newToolTranslator 创建翻译器，输出语言优先取 language 参数，其次取上下文中的请求语言
+func newToolTranslator(ctx context.Context, manager *i18n.Manager, args map[string]interface{}) *toolTranslator {
+	lang, _ := args["language"].(string)
+	if manager != nil {
+		lang = manager.ResolveLanguage(ctx, lang)
+	}
+	return &toolTranslator{manager: manager, lang: lang}
+}
+
+// T 翻译消息，未配置国际化管理器时返回消息ID
+func (t *toolTranslator) T(messageID string, templateData map[string]interface{}) string {
+	if t.manager == nil {
+		return messageID
+	}
+	return t.manager.T(t.lang, messageID, templateData)
+}
+
+// errorResponse 构建本地化的工具错误响应
+func (t *toolTranslator) errorResponse(messageID string, templateData map[string]interface{}) *dto.MCPExecuteResponse {
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: t.T(messageID, templateData),
+			},
+		},
+		IsError: true,
+	}
+}
+
+// languageProperty 输出语言参数的 JSON Schema 定义
+func languageProperty(manager *i18n.Manager) map[string]interface{} {
+	property := map[string]interface{}{
+		"type":        "string",
+		"description": "报告输出语言 (例如: 'en', 'zh')，默认跟随请求的 Accept-Language",
+	}
+	if manager != nil {
+		property["enum"] = manager.GetSupportedLanguages()
+	}
+	return property
+}
+
+// responseErrorDetail 提取工具调用失败的原因
+func responseErrorDetail(err error, resp *dto.MCPExecuteResponse) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp != nil && len(resp.Content) > 0 {
+		return resp.Content[0].Text
+	}
+	return "unknown error"
+}
+
+// quoteFromResponse 提取报价响应中的结构化报价
+func quoteFromResponse(resp *dto.MCPExecuteResponse) *dto.StockQuote {
+	if resp == nil {
+		return nil
+	}
+	for _, content := range resp.Content {
+		if quote, ok := content.Data.(*dto.StockQuote); ok && quote != nil {
+			return quote
+		}
+	}
+	return nil
+}
+
+// profileFromResponse 提取公司信息响应中的结构化公司概况
+func profileFromResponse(resp *dto.MCPExecuteResponse) *dto.CompanyProfile {
+	if resp == nil {
+		return nil
+	}
+	for _, content := range resp.Content {
+		if profile, ok := content.Data.(*dto.CompanyProfile); ok && profile != nil {
+			return profile
+		}
+	}
+	return nil
+}
+
+// quoteChangePercent 计算报价相对前收盘价的涨跌幅（百分比）
+func quoteChangePercent(quote *dto.StockQuote) float64 {
+	if quote == nil || quote.PreviousClose <= 0 {
+		return 0
+	}
+	return (quote.Price - quote.PreviousClose) / quote.PreviousClose * 100
+}
+
+// quoteTrend 根据涨跌幅判断短期趋势: up, down 或 flat
+func quoteTrend(quote *dto.StockQuote) string {
+	change := quoteChangePercent(quote)
+	switch {
+	case change > 0:
+		return "up"
+	case change < 0:
+		return "down"
+	}
+	return "flat"
+}
+
+// formatMoney 格式化金额，附带货币代码
+func formatMoney(amount float64, currency string) string {
+	if currency == "" || currency == "USD" {
+		return fmt.Sprintf("$%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
@@ -0,0 +1,606 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// JiraToolName Jira 工具注册名称
+const JiraToolName = "jira"
+
+// JiraKeyProvider Jira 令牌在 API 密钥服务中的提供商类型
+const JiraKeyProvider = "jira"
+
+// jira 工具支持的操作
+const (
+	jiraActionSearch     = "search"
+	jiraActionCreate     = "create"
+	jiraActionTransition = "transition"
+)
+
+var jiraActions = []string{jiraActionSearch, jiraActionCreate, jiraActionTransition}
+
+const (
+	// defaultJiraResults 默认返回的 issue 数量
+	defaultJiraResults = 20
+	// maxJiraResults 单次搜索返回的最大 issue 数量
+	maxJiraResults = 50
+	// maxJiraJQLLength JQL 的最大长度
+	maxJiraJQLLength = 2000
+	// maxJiraDescriptionLength 创建 issue 时描述的最大长度
+	maxJiraDescriptionLength = 32767
+)
+
+// jiraIssueKeyPattern issue key 格式，例如 OPS-123
+var jiraIssueKeyPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]+)-[0-9]+$`)
+
+// jiraSearchFields 搜索时请求的字段
+var jiraSearchFields = []string{"summary", "status", "issuetype", "priority", "assignee", "reporter", "labels", "updated"}
+
+// JiraTool Jira 工具，支持 JQL 搜索、创建 issue 和状态流转，可以在对话中直接为事故创建工单。
+// 令牌按调用者获取：包含冒号的令牌视为 "邮箱:API token" 使用 Basic 认证（Jira Cloud），
+// 否则作为 Bearer 令牌（OAuth 访问令牌或 Data Center 个人访问令牌）
+type JiraTool struct {
+	*mcp.BaseTool
+	baseURL        string
+	projects       []string
+	defaultProject string
+	token          TokenFunc
+	userID         UserIDFunc
+	httpClient     *http.Client
+}
+
+// NewJiraTool 创建 Jira 工具，projects 为允许访问的项目 key，为空表示不限制，第一个项目为创建 issue 的默认项目
+func NewJiraTool(baseURL string, projects []string, token TokenFunc, userID UserIDFunc) *JiraTool {
+	description := "访问 Jira：用 JQL 搜索 issue、创建 issue (例如为事故创建工单)、执行状态流转 (不指定流转时列出可用流转)"
+	var defaultProject string
+	if len(projects) > 0 {
+		description += "。可用的项目: " + strings.Join(projects, ", ")
+		defaultProject = projects[0]
+	}
+	projectProperty := map[string]interface{}{
+		"type":        "string",
+		"description": "create 的项目 key，默认为第一个配置的项目",
+	}
+	if len(projects) > 0 {
+		projectProperty["enum"] = projects
+	}
+
+	return &JiraTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        JiraToolName,
+			Description: description,
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: 'search' (JQL 搜索), 'create' (创建 issue), 'transition' (状态流转)",
+						"enum":        jiraActions,
+					},
+					"jql": map[string]interface{}{
+						"type":        "string",
+						"description": "search 的 JQL，例如 'status = \"In Progress\" AND assignee = currentUser() ORDER BY updated DESC'",
+						"maxLength":   maxJiraJQLLength,
+					},
+					"max_results": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("search 返回的数量 (1-%d)", maxJiraResults),
+						"minimum":     1,
+						"maximum":     maxJiraResults,
+						"default":     defaultJiraResults,
+					},
+					"project": projectProperty,
+					"summary": map[string]interface{}{
+						"type":        "string",
+						"description": "create 的标题",
+					},
+					"description": map[string]interface{}{
+						"type":        "string",
+						"description": "create 的描述 (Jira wiki 标记)",
+						"maxLength":   maxJiraDescriptionLength,
+					},
+					"issue_type": map[string]interface{}{
+						"type":        "string",
+						"description": "create 的 issue 类型名称，例如 Task、Bug、Incident",
+						"default":     "Task",
+					},
+					"priority": map[string]interface{}{
+						"type":        "string",
+						"description": "create 的优先级名称，例如 High",
+					},
+					"labels": map[string]interface{}{
+						"type":        "array",
+						"description": "create 的标签，不能包含空格",
+						"items":       map[string]interface{}{"type": "string"},
+					},
+					"key": map[string]interface{}{
+						"type":        "string",
+						"description": "transition 的 issue key，例如 OPS-123",
+					},
+					"transition": map[string]interface{}{
+						"type":        "string",
+						"description": "transition 的流转名称、ID 或目标状态，省略时只列出可用流转",
+					},
+					"comment": map[string]interface{}{
+						"type":        "string",
+						"description": "transition 时附加的评论",
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"action"},
+			},
+		},
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		projects:       projects,
+		defaultProject: defaultProject,
+		token:          token,
+		userID:         userID,
+		httpClient: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// Execute 执行 Jira 操作
+func (jt *JiraTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := jt.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	if jt.userID(ctx) == "" {
+		return textErrorResponse("Jira 工具需要登录后使用"), nil
+	}
+	token, err := jt.token(ctx)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("获取 Jira 令牌失败: %v", err)), nil
+	}
+	if token == "" {
+		return textErrorResponse("未配置 Jira 令牌"), nil
+	}
+
+	var result interface{}
+	var text string
+	switch args["action"].(string) {
+	case jiraActionSearch:
+		search, err := jt.search(ctx, token, strings.TrimSpace(args["jql"].(string)), intArg(args, "max_results", defaultJiraResults))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("搜索失败: %v", err)), nil
+		}
+		result, text = search, formatJiraSearch(search)
+	case jiraActionCreate:
+		issue, err := jt.create(ctx, token, args)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("创建 issue 失败: %v", err)), nil
+		}
+		result, text = issue, fmt.Sprintf("✅ 已创建 %s: %s\n%s", issue.Key, issue.Summary, issue.URL)
+	default:
+		transition, err := jt.transition(ctx, token, args["key"].(string), stringArg(args, "transition", ""), stringArg(args, "comment", ""))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("状态流转失败: %v", err)), nil
+		}
+		result, text = transition, formatJiraTransition(transition)
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (jt *JiraTool) Validate(args map[string]interface{}) error {
+	action, ok := args["action"].(string)
+	if !ok || !containsString(jiraActions, action) {
+		return fmt.Errorf("action 必须是以下值之一: %v", jiraActions)
+	}
+	for _, name := range []string{"jql", "project", "summary", "description", "issue_type", "priority", "key", "transition", "comment"} {
+		if value, exists := args[name]; exists {
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s 必须是字符串", name)
+			}
+		}
+	}
+
+	switch action {
+	case jiraActionSearch:
+		jql := strings.TrimSpace(stringArg(args, "jql", ""))
+		if jql == "" {
+			return fmt.Errorf("search 操作需要 jql 参数")
+		}
+		if len(jql) > maxJiraJQLLength {
+			return fmt.Errorf("jql 不能超过 %d 个字符", maxJiraJQLLength)
+		}
+		if _, exists := args["max_results"]; exists {
+			if n := intArg(args, "max_results", 0); n < 1 || n > maxJiraResults {
+				return fmt.Errorf("max_results 必须在 1 到 %d 之间", maxJiraResults)
+			}
+		}
+	case jiraActionCreate:
+		project := stringArg(args, "project", jt.defaultProject)
+		if project == "" {
+			return fmt.Errorf("create 操作需要 project 参数")
+		}
+		if !jt.projectAllowed(project) {
+			return fmt.Errorf("项目 %s 不在允许列表中，可用: %v", project, jt.projects)
+		}
+		if strings.TrimSpace(stringArg(args, "summary", "")) == "" {
+			return fmt.Errorf("create 操作需要 summary 参数")
+		}
+		if len(stringArg(args, "description", "")) > maxJiraDescriptionLength {
+			return fmt.Errorf("description 不能超过 %d 个字符", maxJiraDescriptionLength)
+		}
+		labels, err := stringSliceArg(args, "labels")
+		if err != nil {
+			return err
+		}
+		for _, label := range labels {
+			if label == "" || strings.ContainsAny(label, " \t\n") {
+				return fmt.Errorf("标签不能为空或包含空格: %q", label)
+			}
+		}
+	case jiraActionTransition:
+		match := jiraIssueKeyPattern.FindStringSubmatch(stringArg(args, "key", ""))
+		if match == nil {
+			return fmt.Errorf("transition 操作需要 key 参数，格式如 OPS-123")
+		}
+		if !jt.projectAllowed(match[1]) {
+			return fmt.Errorf("项目 %s 不在允许列表中，可用: %v", match[1], jt.projects)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// projectAllowed 判断项目是否在允许列表中，未配置列表时不限制
+func (jt *JiraTool) projectAllowed(project string) bool {
+	return len(jt.projects) == 0 || containsString(jt.projects, project)
+}
+
+// restrictJQL 将 JQL 限制在允许的项目内，ORDER BY 子句保留在最后
+func (jt *JiraTool) restrictJQL(jql string) string {
+	if len(jt.projects) == 0 {
+		return jql
+	}
+	quoted := make([]string, len(jt.projects))
+	for i, project := range jt.projects {
+		quoted[i] = `"` + project + `"`
+	}
+	restriction := "project in (" + strings.Join(quoted, ", ") + ")"
+
+	where, orderBy := splitJQLOrderBy(jql)
+	if strings.TrimSpace(where) == "" {
+		return strings.TrimSpace(restriction + " " + orderBy)
+	}
+	return strings.TrimSpace(restriction + " AND (" + strings.TrimSpace(where) + ") " + orderBy)
+}
+
+// splitJQLOrderBy 在引号外最后一个 ORDER BY 处拆分 JQL
+func splitJQLOrderBy(jql string) (string, string) {
+	lower := strings.ToLower(jql)
+	index := -1
+	var quote byte
+	for i := 0; i < len(jql); i++ {
+		c := jql[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(lower[i:], "order") && (i == 0 || !isJQLWordChar(jql[i-1])):
+			rest := strings.TrimLeft(lower[i+len("order"):], " \t\r\n")
+			if len(rest) < len(lower[i+len("order"):]) && strings.HasPrefix(rest, "by") &&
+				(len(rest) == 2 || !isJQLWordChar(rest[2])) {
+				index = i
+			}
+		}
+	}
+	if index < 0 {
+		return jql, ""
+	}
+	return jql[:index], jql[index:]
+}
+
+func isJQLWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// jiraIssuePayload Jira issue 响应
+type jiraIssuePayload struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Assignee *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Reporter *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"reporter"`
+		Labels  []string `json:"labels"`
+		Updated string   `json:"updated"`
+	} `json:"fields"`
+}
+
+func (jt *JiraTool) issue(p *jiraIssuePayload) dto.JiraIssue {
+	issue := dto.JiraIssue{
+		Key:       p.Key,
+		Summary:   p.Fields.Summary,
+		Status:    p.Fields.Status.Name,
+		Type:      p.Fields.IssueType.Name,
+		Labels:    p.Fields.Labels,
+		UpdatedAt: p.Fields.Updated,
+		URL:       jt.browseURL(p.Key),
+	}
+	if issue.Labels == nil {
+		issue.Labels = []string{}
+	}
+	if p.Fields.Priority != nil {
+		issue.Priority = p.Fields.Priority.Name
+	}
+	if p.Fields.Assignee != nil {
+		issue.Assignee = p.Fields.Assignee.DisplayName
+	}
+	if p.Fields.Reporter != nil {
+		issue.Reporter = p.Fields.Reporter.DisplayName
+	}
+	return issue
+}
+
+func (jt *JiraTool) browseURL(key string) string {
+	return jt.baseURL + "/browse/" + key
+}
+
+// search 执行 JQL 搜索。Jira Cloud 使用 /search/jql，Data Center 没有该接口时回退到 /search
+func (jt *JiraTool) search(ctx context.Context, token, jql string, maxResults int) (*dto.JiraSearchResult, error) {
+	restricted := jt.restrictJQL(jql)
+	request := map[string]interface{}{
+		"jql":        restricted,
+		"maxResults": maxResults,
+		"fields":     jiraSearchFields,
+	}
+	var payload struct {
+		Issues        []jiraIssuePayload `json:"issues"`
+		Total         *int               `json:"total"`
+		IsLast        *bool              `json:"isLast"`
+		NextPageToken string             `json:"nextPageToken"`
+	}
+	status, err := jt.do(ctx, token, http.MethodPost, "/rest/api/2/search/jql", request, &payload)
+	if status == http.StatusNotFound {
+		status, err = jt.do(ctx, token, http.MethodPost, "/rest/api/2/search", request, &payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.JiraSearchResult{JQL: restricted, Issues: []dto.JiraIssue{}}
+	for i := range payload.Issues {
+		result.Issues = append(result.Issues, jt.issue(&payload.Issues[i]))
+	}
+	switch {
+	case payload.Total != nil:
+		result.Truncated = *payload.Total > len(payload.Issues)
+	case payload.IsLast != nil:
+		result.Truncated = !*payload.IsLast
+	default:
+		result.Truncated = payload.NextPageToken != ""
+	}
+	return result, nil
+}
+
+// create 创建 issue
+func (jt *JiraTool) create(ctx context.Context, token string, args map[string]interface{}) (*dto.JiraIssue, error) {
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": stringArg(args, "project", jt.defaultProject)},
+		"summary":   strings.TrimSpace(args["summary"].(string)),
+		"issuetype": map[string]string{"name": stringArg(args, "issue_type", "Task")},
+	}
+	if description := stringArg(args, "description", ""); description != "" {
+		fields["description"] = description
+	}
+	if priority := stringArg(args, "priority", ""); priority != "" {
+		fields["priority"] = map[string]string{"name": priority}
+	}
+	if labels, _ := stringSliceArg(args, "labels"); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	if _, err := jt.do(ctx, token, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, err
+	}
+	return &dto.JiraIssue{
+		Key:     created.Key,
+		Summary: fields["summary"].(string),
+		Type:    stringArg(args, "issue_type", "Task"),
+		Labels:  []string{},
+		URL:     jt.browseURL(created.Key),
+	}, nil
+}
+
+// transition 按名称、ID 或目标状态执行流转，name 为空时只返回可用流转
+func (jt *JiraTool) transition(ctx context.Context, token, key, name, comment string) (*dto.JiraTransitionResult, error) {
+	var payload struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if _, err := jt.do(ctx, token, http.MethodGet, "/rest/api/2/issue/"+key+"/transitions", nil, &payload); err != nil {
+		return nil, err
+	}
+	result := &dto.JiraTransitionResult{Key: key, Transitions: []dto.JiraTransition{}, URL: jt.browseURL(key)}
+	for _, t := range payload.Transitions {
+		result.Transitions = append(result.Transitions, dto.JiraTransition{ID: t.ID, Name: t.Name, To: t.To.Name})
+	}
+	if name == "" {
+		return result, nil
+	}
+
+	for i, t := range result.Transitions {
+		if t.ID == name || strings.EqualFold(t.Name, name) || strings.EqualFold(t.To, name) {
+			result.Applied = &result.Transitions[i]
+			break
+		}
+	}
+	if result.Applied == nil {
+		available := make([]string, 0, len(result.Transitions))
+		for _, t := range result.Transitions {
+			available = append(available, t.Name)
+		}
+		return nil, fmt.Errorf("%s 没有名为 %q 的流转，可用: %s", key, name, strings.Join(available, ", "))
+	}
+
+	request := map[string]interface{}{"transition": map[string]string{"id": result.Applied.ID}}
+	if comment != "" {
+		request["update"] = map[string]interface{}{
+			"comment": []interface{}{map[string]interface{}{"add": map[string]string{"body": comment}}},
+		}
+	}
+	if _, err := jt.do(ctx, token, http.MethodPost, "/rest/api/2/issue/"+key+"/transitions", request, nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// do 调用 Jira REST API，返回 HTTP 状态码以便调用方按状态回退
+func (jt *JiraTool) do(ctx context.Context, token, method, path string, request, response interface{}) (int, error) {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return 0, fmt.Errorf("编码请求失败: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, jt.baseURL+path, body)
+	if err != nil {
+		return 0, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if strings.Contains(token, ":") {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(token)))
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := jt.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("Jira 返回状态码 %d: %s", resp.StatusCode, jiraErrorMessage(data))
+	}
+	if response == nil || len(bytes.TrimSpace(data)) == 0 {
+		return resp.StatusCode, nil
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return resp.StatusCode, fmt.Errorf("解析响应失败: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// jiraErrorMessage 提取 Jira 错误响应中的 errorMessages 和字段错误
+func jiraErrorMessage(data []byte) string {
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(data, &payload) != nil || len(payload.ErrorMessages)+len(payload.Errors) == 0 {
+		return truncateRunes(strings.TrimSpace(string(data)), 200)
+	}
+	messages := payload.ErrorMessages
+	for field, message := range payload.Errors {
+		messages = append(messages, field+": "+message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// formatJiraSearch 格式化搜索结果
+func formatJiraSearch(result *dto.JiraSearchResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 Jira 搜索结果 (%d)\nJQL: %s\n", len(result.Issues), result.JQL))
+	if len(result.Issues) == 0 {
+		sb.WriteString("(没有匹配的 issue)")
+		return sb.String()
+	}
+	for _, issue := range result.Issues {
+		sb.WriteString(fmt.Sprintf("- %s [%s] %s (%s", issue.Key, issue.Status, issue.Summary, issue.Type))
+		if issue.Priority != "" {
+			sb.WriteString(", " + issue.Priority)
+		}
+		assignee := issue.Assignee
+		if assignee == "" {
+			assignee = "未分配"
+		}
+		sb.WriteString(", " + assignee + ")\n")
+	}
+	if result.Truncated {
+		sb.WriteString("(还有更多结果，请缩小 JQL 范围)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatJiraTransition 格式化流转结果或可用流转
+func formatJiraTransition(result *dto.JiraTransitionResult) string {
+	if result.Applied != nil {
+		return fmt.Sprintf("✅ %s 已执行流转 %s → %s\n%s", result.Key, result.Applied.Name, result.Applied.To, result.URL)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📋 %s 可用的流转\n", result.Key))
+	if len(result.Transitions) == 0 {
+		sb.WriteString("(无)")
+		return sb.String()
+	}
+	for _, t := range result.Transitions {
+		sb.WriteString(fmt.Sprintf("- %s (id %s) → %s\n", t.Name, t.ID, t.To))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitJQLOrderBy(t *testing.T) {
+	tests := []struct {
+		jql     string
+		where   string
+		orderBy string
+	}{
+		{"status = Open ORDER BY updated DESC", "status = Open ", "ORDER BY updated DESC"},
+		{"order by created", "", "order by created"},
+		{`summary ~ "order by" AND reorder = 1`, `summary ~ "order by" AND reorder = 1`, ""},
+		{"status = Open", "status = Open", ""},
+	}
+	for _, tt := range tests {
+		where, orderBy := splitJQLOrderBy(tt.jql)
+		assert.Equal(t, tt.where, where, tt.jql)
+		assert.Equal(t, tt.orderBy, orderBy, tt.jql)
+	}
+}
+
+func TestJiraTool(t *testing.T) {
+	var searched, created, transitioned map[string]interface{}
+	mux := http.NewServeMux()
+	// 模拟 Data Center：没有 /search/jql
+	mux.HandleFunc("POST /rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("ann@acme.com:secret")), r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&searched))
+		w.Write([]byte(`{"total":3,"issues":[{"key":"OPS-1","fields":{"summary":"DB down","status":{"name":"Open"},"issuetype":{"name":"Incident"},
+			"priority":{"name":"High"},"assignee":null,"labels":["db"],"updated":"2026-10-01T10:00:00.000+0000"}}]}`))
+	})
+	mux.HandleFunc("POST /rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"10001","key":"OPS-2"}`))
+	})
+	mux.HandleFunc("GET /rest/api/2/issue/OPS-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"transitions":[{"id":"21","name":"Start Progress","to":{"name":"In Progress"}},{"id":"31","name":"Resolve","to":{"name":"Done"}}]}`))
+	})
+	mux.HandleFunc("POST /rest/api/2/issue/OPS-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&transitioned))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	userID := "1"
+	tool := NewJiraTool(server.URL, []string{"OPS", "SEC"}, func(ctx context.Context) (string, error) {
+		return "ann@acme.com:secret", nil
+	}, func(ctx context.Context) string { return userID })
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{"action": "search", "jql": "priority = High ORDER BY updated DESC", "max_results": 1})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, `project in ("OPS", "SEC") AND (priority = High) ORDER BY updated DESC`, searched["jql"])
+	assert.Equal(t, float64(1), searched["maxResults"])
+	search := resp.Content[0].Data.(*dto.JiraSearchResult)
+	assert.True(t, search.Truncated)
+	assert.Equal(t, "📋 Jira 搜索结果 (1)\nJQL: "+search.JQL+"\n- OPS-1 [Open] DB down (Incident, High, 未分配)\n(还有更多结果，请缩小 JQL 范围)", resp.Content[0].Text)
+	assert.Equal(t, server.URL+"/browse/OPS-1", search.Issues[0].URL)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "create", "summary": "Checkout latency spike", "issue_type": "Incident",
+		"priority": "High", "labels": []interface{}{"oncall"}, "description": "p99 above 2s since 10:00"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, map[string]interface{}{"fields": map[string]interface{}{
+		"project": map[string]interface{}{"key": "OPS"}, "summary": "Checkout latency spike", "issuetype": map[string]interface{}{"name": "Incident"},
+		"priority": map[string]interface{}{"name": "High"}, "labels": []interface{}{"oncall"}, "description": "p99 above 2s since 10:00",
+	}}, created)
+	assert.Equal(t, "OPS-2", resp.Content[0].Data.(*dto.JiraIssue).Key)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "transition", "key": "OPS-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "📋 OPS-1 可用的流转\n- Start Progress (id 21) → In Progress\n- Resolve (id 31) → Done", resp.Content[0].Text)
+	assert.Nil(t, transitioned)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "transition", "key": "OPS-1", "transition": "done", "comment": "Fixed by rollback"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, map[string]interface{}{"id": "31"}, transitioned["transition"])
+	assert.Contains(t, resp.Content[0].Text, "Resolve → Done")
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"project not allowed", map[string]interface{}{"action": "create", "project": "HR", "summary": "x"}, "不在允许列表中"},
+		{"transition other project", map[string]interface{}{"action": "transition", "key": "HR-1"}, "不在允许列表中"},
+		{"invalid key", map[string]interface{}{"action": "transition", "key": "ops 1"}, "key"},
+		{"missing jql", map[string]interface{}{"action": "search"}, "jql"},
+		{"label with space", map[string]interface{}{"action": "create", "summary": "x", "labels": []interface{}{"a b"}}, "标签"},
+		{"unknown transition", map[string]interface{}{"action": "transition", "key": "OPS-1", "transition": "Reopen"}, "Start Progress, Resolve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	userID = ""
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "search", "jql": "status = Open"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+}
@@ -0,0 +1,346 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/k8sops"
+	"go-springAi/internal/mcp"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// K8sToolName Kubernetes 运维工具注册名称
+const K8sToolName = "k8s"
+
+// k8s 工具支持的操作
+const (
+	k8sActionListPods        = "list_pods"
+	k8sActionListDeployments = "list_deployments"
+	k8sActionLogs            = "logs"
+	k8sActionDescribe        = "describe"
+)
+
+const (
+	// defaultK8sTailLines 默认读取的日志行数
+	defaultK8sTailLines = 200
+	// maxK8sTailLines 单次读取的最大日志行数
+	maxK8sTailLines = 1000
+)
+
+var k8sActions = []string{k8sActionListPods, k8sActionListDeployments, k8sActionLogs, k8sActionDescribe}
+
+var k8sKinds = []string{k8sops.KindPod, k8sops.KindDeployment, k8sops.KindService, k8sops.KindNode}
+
+// K8sTool Kubernetes 只读运维工具（仅限管理员），列出 Pod 和 Deployment、读取日志、查看资源详情，
+// 每次调用无论成功与否都记录审计日志
+type K8sTool struct {
+	*mcp.BaseTool
+	manager *k8sops.Manager
+	admin   AdminCheckFunc
+	logger  *zap.Logger
+}
+
+// NewK8sTool 创建 Kubernetes 运维工具
+func NewK8sTool(manager *k8sops.Manager, admin AdminCheckFunc, logger *zap.Logger) *K8sTool {
+	return &K8sTool{
+		BaseTool: &mcp.BaseTool{
+			Name: K8sToolName,
+			Description: "查看 Kubernetes 集群状态（仅限管理员，只读）：列出 Pod 和 Deployment、读取容器日志、" +
+				"查看 Pod/Deployment/Service/Node 的详情和最近事件。可用的集群: " + strings.Join(manager.Names(), ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: 'list_pods', 'list_deployments', 'logs' (读取 Pod 日志), 'describe' (查看资源详情)",
+						"enum":        k8sActions,
+					},
+					"cluster": map[string]interface{}{
+						"type":        "string",
+						"description": "集群名称，只配置了一个集群时可省略",
+						"enum":        manager.Names(),
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "命名空间，默认为集群配置的第一个命名空间或 default；列表操作可用 '*' 查询所有命名空间（仅限未限制命名空间的集群）",
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "资源名称，logs 和 describe 操作必需",
+					},
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "describe 的资源类型",
+						"enum":        k8sKinds,
+						"default":     k8sops.KindPod,
+					},
+					"container": map[string]interface{}{
+						"type":        "string",
+						"description": "读取日志的容器名称，Pod 只有一个容器时可省略",
+					},
+					"tail_lines": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("读取最后多少行日志 (1-%d)", maxK8sTailLines),
+						"minimum":     1,
+						"maximum":     maxK8sTailLines,
+						"default":     defaultK8sTailLines,
+					},
+					"previous": map[string]interface{}{
+						"type":        "boolean",
+						"description": "读取上一次崩溃的容器实例的日志",
+						"default":     false,
+					},
+					"label_selector": map[string]interface{}{
+						"type":        "string",
+						"description": "列表操作的标签选择器，例如 app=web,tier!=cache",
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"action"},
+			},
+		},
+		manager: manager,
+		admin:   admin,
+		logger:  logger,
+	}
+}
+
+// Execute 校验权限后执行只读运维操作
+func (kt *K8sTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	action := stringArg(args, "action", "")
+	cluster := kt.cluster(args)
+	namespace := stringArg(args, "namespace", "")
+	name := stringArg(args, "name", "")
+
+	userID, err := kt.admin(ctx)
+	if err != nil {
+		kt.audit("denied", userID, action, cluster, namespace, name, err)
+		return textErrorResponse("仅管理员可以使用 k8s 工具"), nil
+	}
+	if err := kt.Validate(args); err != nil {
+		kt.audit("rejected", userID, action, cluster, namespace, name, err)
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	var result interface{}
+	var text string
+	switch action {
+	case k8sActionListPods, k8sActionListDeployments:
+		var list *dto.K8sListResult
+		if action == k8sActionListPods {
+			list, err = kt.manager.ListPods(ctx, cluster, namespace, stringArg(args, "label_selector", ""))
+		} else {
+			list, err = kt.manager.ListDeployments(ctx, cluster, namespace, stringArg(args, "label_selector", ""))
+		}
+		if err == nil {
+			result, text = list, formatK8sList(list)
+		}
+	case k8sActionLogs:
+		var logs *dto.K8sLogResult
+		logs, err = kt.manager.Logs(ctx, cluster, namespace, name, stringArg(args, "container", ""),
+			int64(intArg(args, "tail_lines", defaultK8sTailLines)), args["previous"] == true)
+		if err == nil {
+			result, text = logs, formatK8sLogs(logs)
+		}
+	default:
+		var described *dto.K8sDescribeResult
+		described, err = kt.manager.Describe(ctx, cluster, namespace, stringArg(args, "kind", k8sops.KindPod), name)
+		if err == nil {
+			result, text = described, formatK8sDescribe(described)
+		}
+	}
+	if err != nil {
+		kt.audit("failed", userID, action, cluster, namespace, name, err)
+		return textErrorResponse(k8sErrorMessage(err)), nil
+	}
+	kt.audit("executed", userID, action, cluster, namespace, name, nil)
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (kt *K8sTool) Validate(args map[string]interface{}) error {
+	action, ok := args["action"].(string)
+	if !ok || !containsString(k8sActions, action) {
+		return fmt.Errorf("action 必须是以下值之一: %v", k8sActions)
+	}
+
+	for _, name := range []string{"cluster", "namespace", "name", "kind", "container", "label_selector"} {
+		if value, exists := args[name]; exists {
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s 必须是字符串", name)
+			}
+		}
+	}
+	if _, exists := args["cluster"]; !exists && len(kt.manager.Names()) > 1 {
+		return fmt.Errorf("配置了多个集群时 cluster 参数是必需的，可用: %v", kt.manager.Names())
+	}
+	if cluster := kt.cluster(args); !containsString(kt.manager.Names(), cluster) {
+		return fmt.Errorf("未知的集群 %s，可用: %v", cluster, kt.manager.Names())
+	}
+
+	namespace := stringArg(args, "namespace", "")
+	if action == k8sActionLogs || action == k8sActionDescribe {
+		if stringArg(args, "name", "") == "" {
+			return fmt.Errorf("%s 操作需要 name 参数", action)
+		}
+		if namespace == k8sops.AllNamespaces {
+			return fmt.Errorf("%s 操作需要指定命名空间", action)
+		}
+	}
+	if kind := stringArg(args, "kind", k8sops.KindPod); !containsString(k8sKinds, kind) {
+		return fmt.Errorf("kind 必须是以下值之一: %v", k8sKinds)
+	}
+	if _, exists := args["tail_lines"]; exists {
+		if lines := intArg(args, "tail_lines", 0); lines < 1 || lines > maxK8sTailLines {
+			return fmt.Errorf("tail_lines 必须在 1 到 %d 之间", maxK8sTailLines)
+		}
+	}
+	if value, exists := args["previous"]; exists {
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("previous 必须是布尔值")
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// cluster 返回请求的集群，只配置了一个集群时可省略
+func (kt *K8sTool) cluster(args map[string]interface{}) string {
+	if names := kt.manager.Names(); len(names) == 1 {
+		return stringArg(args, "cluster", names[0])
+	}
+	return stringArg(args, "cluster", "")
+}
+
+// audit 记录审计日志
+func (kt *K8sTool) audit(result, userID, action, cluster, namespace, name string, err error) {
+	fields := []zap.Field{
+		zap.String("audit", "mcp.k8s"),
+		zap.String("result", result),
+		zap.String("user_id", userID),
+		zap.String("action", action),
+		zap.String("cluster", cluster),
+		zap.String("namespace", namespace),
+		zap.String("name", name),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	kt.logger.Warn("K8s tool invoked", fields...)
+}
+
+// k8sErrorMessage 将集群错误转换为面向用户的消息
+func k8sErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, k8sops.ErrNamespaceNotAllowed):
+		return fmt.Sprintf("命名空间不允许访问: %v", err)
+	case apierrors.IsNotFound(err):
+		return fmt.Sprintf("资源不存在: %v", err)
+	case apierrors.IsForbidden(err):
+		return fmt.Sprintf("集群拒绝访问，请检查服务账号权限: %v", err)
+	}
+	return fmt.Sprintf("操作失败: %v", err)
+}
+
+// formatK8sList 格式化 Pod 或 Deployment 列表
+func formatK8sList(list *dto.K8sListResult) string {
+	var sb strings.Builder
+	allNamespaces := list.Namespace == k8sops.AllNamespaces
+	if list.Kind == k8sops.KindPod {
+		sb.WriteString(fmt.Sprintf("☸️ %s/%s 中的 Pod (%d)\n", list.Cluster, list.Namespace, len(list.Pods)))
+		for _, pod := range list.Pods {
+			name := pod.Name
+			if allNamespaces {
+				name = pod.Namespace + "/" + pod.Name
+			}
+			sb.WriteString(fmt.Sprintf("- %s  %s  就绪 %s  重启 %d", name, pod.Phase, pod.Ready, pod.Restarts))
+			if pod.Node != "" {
+				sb.WriteString("  节点 " + pod.Node)
+			}
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("☸️ %s/%s 中的 Deployment (%d)\n", list.Cluster, list.Namespace, len(list.Deployments)))
+		for _, deployment := range list.Deployments {
+			name := deployment.Name
+			if allNamespaces {
+				name = deployment.Namespace + "/" + deployment.Name
+			}
+			sb.WriteString(fmt.Sprintf("- %s  就绪 %d/%d  已更新 %d  可用 %d  %s\n", name, deployment.Ready, deployment.Replicas,
+				deployment.Updated, deployment.Available, strings.Join(deployment.Images, ", ")))
+		}
+	}
+	if list.Truncated {
+		sb.WriteString("(结果过多已截断，请使用 label_selector 或指定命名空间缩小范围)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatK8sLogs 格式化容器日志
+func formatK8sLogs(logs *dto.K8sLogResult) string {
+	header := fmt.Sprintf("☸️ %s/%s/%s", logs.Cluster, logs.Namespace, logs.Pod)
+	if logs.Container != "" {
+		header += " [" + logs.Container + "]"
+	}
+	if logs.Previous {
+		header += " (上一个实例)"
+	}
+	body := logs.Log
+	if body == "" {
+		body = "(没有日志)"
+	}
+	text := header + "\n" + strings.TrimRight(body, "\n")
+	if logs.Truncated {
+		text += "\n— 日志超过大小上限已截断"
+	}
+	return text
+}
+
+// formatK8sDescribe 格式化资源详情和事件
+func formatK8sDescribe(described *dto.K8sDescribeResult) string {
+	var sb strings.Builder
+	location := described.Cluster
+	if described.Namespace != "" {
+		location += "/" + described.Namespace
+	}
+	sb.WriteString(fmt.Sprintf("☸️ %s %s (%s)\n", described.Kind, described.Name, location))
+
+	keys := make([]string, 0, len(described.Details))
+	for key := range described.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf("%s: %v\n", key, described.Details[key]))
+	}
+
+	if len(described.Events) == 0 {
+		sb.WriteString("事件: (无)")
+		return sb.String()
+	}
+	sb.WriteString("事件:\n")
+	for _, event := range described.Events {
+		sb.WriteString(fmt.Sprintf("- %s %s %s (x%d): %s\n", event.LastSeen.Format("2006-01-02 15:04:05"),
+			event.Type, event.Reason, event.Count, event.Message))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
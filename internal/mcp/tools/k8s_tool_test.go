@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/k8sops"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestK8sTool(t *testing.T) {
+	manager := k8sops.NewManager(0, 0)
+	require.NoError(t, manager.AddCluster("prod", fake.NewClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "prod"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	), []string{"prod"}))
+
+	admin := true
+	core, logs := observer.New(zap.WarnLevel)
+	tool := NewK8sTool(manager, func(ctx context.Context) (string, error) {
+		if !admin {
+			return "2", errors.New("admin privileges required")
+		}
+		return "1", nil
+	}, zap.New(core))
+
+	resp, err := tool.Execute(context.Background(), map[string]interface{}{"action": "list_pods"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, "☸️ prod/prod 中的 Pod (1)\n- web-1  Running  就绪 0/1  重启 0", resp.Content[0].Text)
+	assert.Len(t, resp.Content[0].Data.(*dto.K8sListResult).Pods, 1)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "executed", entries[0].ContextMap()["result"])
+
+	resp, err = tool.Execute(context.Background(), map[string]interface{}{"action": "describe", "name": "web-1", "format": "json"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Contains(t, resp.Content[0].Text, `"kind":"pod","name":"web-1"`)
+	logs.TakeAll()
+
+	tests := []struct {
+		name    string
+		admin   bool
+		args    map[string]interface{}
+		message string
+		result  string
+	}{
+		{"non-admin", false, map[string]interface{}{"action": "list_pods"}, "仅管理员", "denied"},
+		{"unknown action", true, map[string]interface{}{"action": "delete"}, "action", "rejected"},
+		{"logs without name", true, map[string]interface{}{"action": "logs"}, "name", "rejected"},
+		{"unknown cluster", true, map[string]interface{}{"action": "list_pods", "cluster": "dev"}, "未知的集群", "rejected"},
+		{"secrets", true, map[string]interface{}{"action": "describe", "kind": "secret", "name": "db"}, "kind", "rejected"},
+		{"namespace not allowed", true, map[string]interface{}{"action": "list_pods", "namespace": "kube-system"}, "命名空间不允许访问", "failed"},
+		{"missing pod", true, map[string]interface{}{"action": "describe", "name": "nope"}, "资源不存在", "failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin = tt.admin
+			resp, err := tool.Execute(context.Background(), tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+
+			entries := logs.TakeAll()
+			require.Len(t, entries, 1)
+			assert.Equal(t, "mcp.k8s", entries[0].ContextMap()["audit"])
+			assert.Equal(t, tt.result, entries[0].ContextMap()["result"])
+		})
+	}
+}
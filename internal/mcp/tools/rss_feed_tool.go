@@ -0,0 +1,570 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// RSSFeedToolName 订阅源聚合工具注册名称
+const RSSFeedToolName = "rss_feed"
+
+const (
+	// DefaultRSSMaxItems 默认返回的新闻条数
+	DefaultRSSMaxItems = 20
+	// maxRSSItems 单次最多返回的新闻条数
+	maxRSSItems = 50
+	// DefaultRSSCacheTTL 订阅源内容的默认缓存时间
+	DefaultRSSCacheTTL = 10 * time.Minute
+	// maxRSSSinceHours 最多回溯的小时数
+	maxRSSSinceHours = 30 * 24
+	// maxRSSSummaryRunes 摘要的最大字符数
+	maxRSSSummaryRunes = 280
+	// maxRSSFeedBytes 单个订阅源的最大字节数
+	maxRSSFeedBytes = 5 << 20
+)
+
+// htmlTagPattern 匹配摘要中的 HTML 标签
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// trackingParams 规范化链接时去掉的跟踪参数
+var trackingParams = []string{"fbclid", "gclid", "mc_cid", "mc_eid", "ref", "cmpid"}
+
+// RSSFeed 一个订阅源
+type RSSFeed struct {
+	Name     string
+	URL      string
+	Category string
+}
+
+// RSSFeedConfig 订阅源聚合工具配置，只读取部署配置的订阅源
+type RSSFeedConfig struct {
+	Feeds    []RSSFeed
+	CacheTTL time.Duration // 订阅源内容的缓存时间
+	MaxItems int           // 默认返回的新闻条数
+}
+
+// cachedFeed 缓存的订阅源内容
+type cachedFeed struct {
+	items     []dto.RSSItem
+	fetchedAt time.Time
+}
+
+// RSSFeedTool 订阅源聚合工具，从配置的 RSS/Atom 订阅源获取近期新闻并去重，
+// 作为行情数据之外的综合新闻来源
+type RSSFeedTool struct {
+	*mcp.BaseTool
+	cfg        RSSFeedConfig
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+}
+
+// NewRSSFeedTool 创建订阅源聚合工具
+func NewRSSFeedTool(cfg RSSFeedConfig) *RSSFeedTool {
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultRSSCacheTTL
+	}
+	if cfg.MaxItems <= 0 || cfg.MaxItems > maxRSSItems {
+		cfg.MaxItems = DefaultRSSMaxItems
+	}
+	var names, categories []string
+	for _, feed := range cfg.Feeds {
+		names = append(names, feed.Name)
+		if feed.Category != "" && !containsString(categories, feed.Category) {
+			categories = append(categories, feed.Category)
+		}
+	}
+
+	properties := map[string]interface{}{
+		"feeds": map[string]interface{}{
+			"type":        "array",
+			"description": "只读取这些订阅源，默认读取全部",
+			"items":       map[string]interface{}{"type": "string", "enum": names},
+		},
+		"since_hours": map[string]interface{}{
+			"type":        "integer",
+			"description": "只返回最近多少小时内发布的新闻",
+			"minimum":     1,
+			"maximum":     maxRSSSinceHours,
+			"default":     24,
+		},
+		"query": map[string]interface{}{
+			"type":        "string",
+			"description": "关键词，标题或摘要需包含全部关键词 (不区分大小写)",
+		},
+		"limit": map[string]interface{}{
+			"type":        "integer",
+			"description": "返回的新闻条数",
+			"minimum":     1,
+			"maximum":     maxRSSItems,
+			"default":     cfg.MaxItems,
+		},
+		"format": formatProperty(),
+	}
+	if len(categories) > 0 {
+		properties["category"] = map[string]interface{}{
+			"type":        "string",
+			"description": "只读取该分类的订阅源",
+			"enum":        categories,
+		}
+	}
+	return &RSSFeedTool{
+		BaseTool: &mcp.BaseTool{
+			Name: RSSFeedToolName,
+			Description: "从配置的新闻订阅源 (RSS/Atom) 获取近期新闻，跨来源去重后按时间倒序返回，用于了解综合新闻动态。可用订阅源: " +
+				strings.Join(names, ", "),
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": properties,
+			},
+		},
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+		now:   time.Now,
+		cache: make(map[string]cachedFeed),
+	}
+}
+
+// Execute 获取订阅源并按条件筛选
+func (rt *RSSFeedTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := rt.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	feeds := rt.selectFeeds(args)
+	if len(feeds) == 0 {
+		return textErrorResponse("没有符合条件的订阅源"), nil
+	}
+	since := rt.now().Add(-time.Duration(intArg(args, "since_hours", 24)) * time.Hour)
+	result := &dto.RSSFeedResult{Since: since, Feeds: []string{}, Items: []dto.RSSItem{}}
+
+	fetched := make([][]dto.RSSItem, len(feeds))
+	errs := make([]error, len(feeds))
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		wg.Add(1)
+		go func(i int, feed RSSFeed) {
+			defer wg.Done()
+			fetched[i], errs[i] = rt.fetch(ctx, feed)
+		}(i, feed)
+	}
+	wg.Wait()
+
+	var items []dto.RSSItem
+	for i, feed := range feeds {
+		result.Feeds = append(result.Feeds, feed.Name)
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, dto.RSSFeedError{Feed: feed.Name, Error: errs[i].Error()})
+			continue
+		}
+		items = append(items, fetched[i]...)
+	}
+
+	keywords := strings.Fields(strings.ToLower(stringArg(args, "query", "")))
+	var recent []dto.RSSItem
+	for _, item := range items {
+		if item.Published.IsZero() || item.Published.Before(since) || item.Published.After(rt.now().Add(time.Hour)) {
+			continue
+		}
+		if !matchesKeywords(item, keywords) {
+			continue
+		}
+		recent = append(recent, item)
+	}
+	result.Items = dedupeRSSItems(recent)
+	if limit := intArg(args, "limit", rt.cfg.MaxItems); len(result.Items) > limit {
+		result.Items = result.Items[:limit]
+		result.Truncated = true
+	}
+
+	allFailed := len(result.Errors) == len(feeds)
+	if wantsJSON(args) {
+		response := jsonResponse(result)
+		response.IsError = allFailed
+		return response, nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatRSSFeedResult(result, intArg(args, "since_hours", 24)),
+				Data: result,
+			},
+		},
+		IsError: allFailed,
+	}, nil
+}
+
+// Validate 验证参数
+func (rt *RSSFeedTool) Validate(args map[string]interface{}) error {
+	names, err := stringSliceArg(args, "feeds")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if rt.feed(name) == nil {
+			return fmt.Errorf("未知的订阅源 %q", name)
+		}
+	}
+	if category, ok := args["category"]; ok {
+		if _, ok := category.(string); !ok {
+			return fmt.Errorf("category 必须是字符串")
+		}
+	}
+	if query, ok := args["query"]; ok {
+		if _, ok := query.(string); !ok {
+			return fmt.Errorf("query 必须是字符串")
+		}
+	}
+	if hours := intArg(args, "since_hours", 24); hours < 1 || hours > maxRSSSinceHours {
+		return fmt.Errorf("since_hours 必须在 1 到 %d 之间", maxRSSSinceHours)
+	}
+	if limit := intArg(args, "limit", rt.cfg.MaxItems); limit < 1 || limit > maxRSSItems {
+		return fmt.Errorf("limit 必须在 1 到 %d 之间", maxRSSItems)
+	}
+	return validateOutputFormat(args)
+}
+
+// feed 按名称查找订阅源，不区分大小写
+func (rt *RSSFeedTool) feed(name string) *RSSFeed {
+	for i := range rt.cfg.Feeds {
+		if strings.EqualFold(rt.cfg.Feeds[i].Name, strings.TrimSpace(name)) {
+			return &rt.cfg.Feeds[i]
+		}
+	}
+	return nil
+}
+
+// selectFeeds 按名称和分类选择订阅源
+func (rt *RSSFeedTool) selectFeeds(args map[string]interface{}) []RSSFeed {
+	names, _ := stringSliceArg(args, "feeds")
+	category := stringArg(args, "category", "")
+	var feeds []RSSFeed
+	for _, feed := range rt.cfg.Feeds {
+		if category != "" && !strings.EqualFold(feed.Category, category) {
+			continue
+		}
+		if len(names) > 0 && !containsFold(names, feed.Name) {
+			continue
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+// fetch 获取并解析订阅源，缓存未过期时直接返回缓存内容
+func (rt *RSSFeedTool) fetch(ctx context.Context, feed RSSFeed) ([]dto.RSSItem, error) {
+	rt.mu.Lock()
+	cached, ok := rt.cache[feed.URL]
+	rt.mu.Unlock()
+	if ok && rt.now().Sub(cached.fetchedAt) < rt.cfg.CacheTTL {
+		return cached.items, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	req.Header.Set("User-Agent", "go-springAi rss_feed")
+
+	resp, err := rt.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("返回状态码 %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRSSFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	items, err := parseFeed(data, feed.Name)
+	if err != nil {
+		return nil, err
+	}
+	rt.mu.Lock()
+	rt.cache[feed.URL] = cachedFeed{items: items, fetchedAt: rt.now()}
+	rt.mu.Unlock()
+	return items, nil
+}
+
+// feedDocument RSS 2.0、RSS 1.0 (RDF) 和 Atom 文档中用到的字段
+type feedDocument struct {
+	Channel struct {
+		Items []rssEntry `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssEntry  `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// rssEntry RSS 条目
+type rssEntry struct {
+	Title       string   `xml:"title"`
+	Links       []string `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// atomEntry Atom 条目
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// parseFeed 解析 RSS 或 Atom 文档
+func parseFeed(data []byte, source string) ([]dto.RSSItem, error) {
+	var doc feedDocument
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = latin1Reader
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("解析订阅源失败: %w", err)
+	}
+
+	var items []dto.RSSItem
+	for _, entry := range append(doc.Channel.Items, doc.Items...) {
+		link := ""
+		for _, candidate := range entry.Links {
+			if candidate = strings.TrimSpace(candidate); candidate != "" {
+				link = candidate
+				break
+			}
+		}
+		if link == "" && strings.HasPrefix(entry.GUID, "http") {
+			link = strings.TrimSpace(entry.GUID)
+		}
+		published := parseFeedTime(entry.PubDate)
+		if published.IsZero() {
+			published = parseFeedTime(entry.Date)
+		}
+		items = append(items, newRSSItem(entry.Title, link, entry.Description, published, source))
+	}
+	for _, entry := range doc.Entries {
+		link := ""
+		for _, candidate := range entry.Links {
+			if candidate.Rel == "" || candidate.Rel == "alternate" {
+				link = strings.TrimSpace(candidate.Href)
+				break
+			}
+		}
+		summary := entry.Summary
+		if summary == "" {
+			summary = entry.Content
+		}
+		published := parseFeedTime(entry.Published)
+		if published.IsZero() {
+			published = parseFeedTime(entry.Updated)
+		}
+		items = append(items, newRSSItem(entry.Title, link, summary, published, source))
+	}
+	return items, nil
+}
+
+// newRSSItem 创建条目，标题和摘要去掉 HTML 标签
+func newRSSItem(title, link, summary string, published time.Time, source string) dto.RSSItem {
+	return dto.RSSItem{
+		Title:     plainText(title),
+		URL:       link,
+		Summary:   truncateRunes(plainText(summary), maxRSSSummaryRunes),
+		Published: published,
+		Source:    source,
+	}
+}
+
+// latin1Reader 把 ISO-8859-1 / Windows-1252 编码的订阅源转为 UTF-8，其他编码不支持
+func latin1Reader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252", "us-ascii":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return strings.NewReader(string(runes)), nil
+	}
+	return nil, fmt.Errorf("不支持的编码 %s", charset)
+}
+
+// feedTimeLayouts 订阅源中常见的时间格式
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// parseFeedTime 解析发布时间，无法解析时返回零值
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// plainText 去掉 HTML 标签和实体并合并空白
+func plainText(text string) string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// matchesKeywords 判断标题或摘要是否包含全部关键词
+func matchesKeywords(item dto.RSSItem, keywords []string) bool {
+	text := strings.ToLower(item.Title + " " + item.Summary)
+	for _, keyword := range keywords {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupeRSSItems 按规范化链接或标题去重，保留最早发布的一条并记录其他来源，结果按发布时间倒序
+func dedupeRSSItems(items []dto.RSSItem) []dto.RSSItem {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Published.Before(items[j].Published) })
+
+	var unique []dto.RSSItem
+	seen := make(map[string]int)
+	for _, item := range items {
+		keys := []string{"title:" + titleKey(item.Title)}
+		if item.URL != "" {
+			keys = append(keys, "url:"+canonicalURL(item.URL))
+		}
+		index, duplicate := -1, false
+		for _, key := range keys {
+			if i, ok := seen[key]; ok {
+				index, duplicate = i, true
+				break
+			}
+		}
+		if !duplicate {
+			index = len(unique)
+			unique = append(unique, item)
+		} else if first := &unique[index]; first.Source != item.Source && !containsString(first.AlsoIn, item.Source) {
+			first.AlsoIn = append(first.AlsoIn, item.Source)
+		}
+		for _, key := range keys {
+			if key != "title:" {
+				seen[key] = index
+			}
+		}
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool { return unique[i].Published.After(unique[j].Published) })
+	return unique
+}
+
+// canonicalURL 规范化链接：去掉 www.、片段、跟踪参数和结尾的斜杠
+func canonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(name, "utm_") || containsString(trackingParams, name) {
+			query.Del(name)
+		}
+	}
+	canonical := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+	return canonical
+}
+
+// titleKey 标题去重键：只保留字母和数字并转为小写
+func titleKey(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// containsFold 判断切片是否包含字符串，不区分大小写
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRSSFeedResult 格式化新闻列表
+func formatRSSFeedResult(result *dto.RSSFeedResult, sinceHours int) string {
+	var sb strings.Builder
+	if len(result.Items) == 0 {
+		sb.WriteString(fmt.Sprintf("📰 最近 %d 小时没有符合条件的新闻\n", sinceHours))
+	} else {
+		sb.WriteString(fmt.Sprintf("📰 最近 %d 小时的新闻 (%d 条):\n", sinceHours, len(result.Items)))
+	}
+	for i, item := range result.Items {
+		sources := item.Source
+		if len(item.AlsoIn) > 0 {
+			sources += ", " + strings.Join(item.AlsoIn, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s [%s] %s\n", i+1, item.Title, sources, item.Published.UTC().Format("2006-01-02 15:04 UTC")))
+		if item.URL != "" {
+			sb.WriteString("   " + item.URL + "\n")
+		}
+		if item.Summary != "" {
+			sb.WriteString("   " + item.Summary + "\n")
+		}
+	}
+	if result.Truncated {
+		sb.WriteString("… 还有更多新闻，可缩短 since_hours 或使用 query 过滤\n")
+	}
+	for _, failure := range result.Errors {
+		sb.WriteString(fmt.Sprintf("⚠️ 获取订阅源 %s 失败: %s\n", failure.Feed, failure.Error))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
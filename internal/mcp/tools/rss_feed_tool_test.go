@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Wire</title>
+  <atom:link href="https://wire.example.com/rss" rel="self"/>
+  <item>
+    <title>Fed holds rates steady</title>
+    <link>https://www.news.example.com/fed-holds/?utm_source=rss</link>
+    <description><![CDATA[<p>The Fed kept rates <b>unchanged</b> &amp; signaled patience.</p>]]></description>
+    <pubDate>Wed, 01 May 2024 18:00:00 GMT</pubDate>
+  </item>
+  <item>
+    <title>Chip stocks rally</title>
+    <link>https://wire.example.com/chips</link>
+    <pubDate>Wed, 01 May 2024 10:00:00 +0000</pubDate>
+  </item>
+  <item>
+    <title>Old story</title>
+    <link>https://wire.example.com/old</link>
+    <pubDate>Mon, 01 Apr 2024 10:00:00 +0000</pubDate>
+  </item>
+  <item>
+    <title>Undated story</title>
+    <link>https://wire.example.com/undated</link>
+  </item>
+</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Daily</title>
+  <entry>
+    <title>Fed Holds Rates Steady!</title>
+    <link rel="alternate" href="https://daily.example.com/markets/fed"/>
+    <summary>Policy makers kept the benchmark range unchanged.</summary>
+    <published>2024-05-01T18:30:00Z</published>
+  </entry>
+  <entry>
+    <title>Oil slips on demand worries</title>
+    <link href="https://daily.example.com/oil"/>
+    <updated>2024-05-01T15:00:00Z</updated>
+  </entry>
+</feed>`
+
+func TestRSSFeedTool(t *testing.T) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wire.xml", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testRSSFeed))
+	})
+	mux.HandleFunc("/daily.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testAtomFeed))
+	})
+	mux.HandleFunc("/broken.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tool := NewRSSFeedTool(RSSFeedConfig{
+		Feeds: []RSSFeed{
+			{Name: "Wire", URL: server.URL + "/wire.xml", Category: "markets"},
+			{Name: "Daily", URL: server.URL + "/daily.xml", Category: "markets"},
+			{Name: "Broken", URL: server.URL + "/broken.xml", Category: "tech"},
+		},
+	})
+	tool.now = func() time.Time { return time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{"category": "markets"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	result := resp.Content[0].Data.(*dto.RSSFeedResult)
+	assert.Equal(t, []string{"Wire", "Daily"}, result.Feeds)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, "Fed holds rates steady", result.Items[0].Title, "the earliest copy of a duplicate is kept")
+	assert.Equal(t, "Wire", result.Items[0].Source)
+	assert.Equal(t, []string{"Daily"}, result.Items[0].AlsoIn)
+	assert.Equal(t, "The Fed kept rates unchanged & signaled patience.", result.Items[0].Summary)
+	assert.Equal(t, "Oil slips on demand worries", result.Items[1].Title)
+	assert.Equal(t, "Chip stocks rally", result.Items[2].Title)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"feeds": []interface{}{"wire", "broken"}, "query": "FED", "since_hours": 720.0})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	result = resp.Content[0].Data.(*dto.RSSFeedResult)
+	require.Len(t, result.Items, 1)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "Broken", result.Errors[0].Feed)
+	assert.Contains(t, resp.Content[0].Text, "⚠️ 获取订阅源 Broken 失败: 返回状态码 502")
+	assert.Equal(t, 1, requests, "feeds are cached between calls")
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"feeds": []interface{}{"Wire"}, "since_hours": 720.0, "limit": 1.0})
+	require.NoError(t, err)
+	result = resp.Content[0].Data.(*dto.RSSFeedResult)
+	assert.Len(t, result.Items, 1)
+	assert.True(t, result.Truncated)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"category": "tech"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError, "all selected feeds failed")
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"feeds": []interface{}{"unknown"}})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "未知的订阅源")
+}
+
+func TestCanonicalURL(t *testing.T) {
+	assert.Equal(t, canonicalURL("https://news.example.com/a?id=1"), canonicalURL("http://www.news.example.com/a/?utm_medium=rss&id=1#top"))
+	assert.NotEqual(t, canonicalURL("https://news.example.com/a?id=1"), canonicalURL("https://news.example.com/a?id=2"))
+}
@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/ratelimit"
+
+	"go.uber.org/zap"
+)
+
+// SendEmailToolName 邮件发送工具注册名称
+const SendEmailToolName = "send_email"
+
+const (
+	// maxEmailSubjectLength 邮件主题的最大长度
+	maxEmailSubjectLength = 200
+	// DefaultEmailMaxRecipients 单次发送的默认最大收件人数
+	DefaultEmailMaxRecipients = 5
+	// DefaultEmailMaxBodyBytes 邮件正文的默认最大字节数
+	DefaultEmailMaxBodyBytes = 100 * 1024
+)
+
+// CallerEmailFunc 返回调用者的用户ID和账号邮箱，未认证时返回错误
+type CallerEmailFunc func(ctx context.Context) (string, string, error)
+
+// SendMailFunc 向一个收件人发送纯文本邮件
+type SendMailFunc func(ctx context.Context, to, subject, body string) error
+
+// SendEmailConfig 邮件发送工具的限制
+type SendEmailConfig struct {
+	AllowedRecipients []string       // 允许的收件人：完整地址或 "@example.com" 表示整个域名
+	AllowSelf         bool           // 始终允许发送到调用者自己的账号邮箱
+	MaxRecipients     int            // 单次发送的最大收件人数
+	MaxBodyBytes      int            // 正文的最大字节数
+	RateLimit         ratelimit.Rule // 每个用户的发送频率，未启用时不限制
+}
+
+// SendEmailTool 邮件发送工具，通过通知服务的 SMTP 通道把定时分析或临时报告发送给允许的收件人，
+// 每个用户单独限流，每次发送都记录审计日志
+type SendEmailTool struct {
+	*mcp.BaseTool
+	cfg    SendEmailConfig
+	send   SendMailFunc
+	caller CallerEmailFunc
+	limits ratelimit.Store
+	logger *zap.Logger
+}
+
+// NewSendEmailTool 创建邮件发送工具
+func NewSendEmailTool(cfg SendEmailConfig, send SendMailFunc, caller CallerEmailFunc, limits ratelimit.Store, logger *zap.Logger) *SendEmailTool {
+	if cfg.MaxRecipients <= 0 {
+		cfg.MaxRecipients = DefaultEmailMaxRecipients
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultEmailMaxBodyBytes
+	}
+	recipients := make([]string, 0, len(cfg.AllowedRecipients))
+	for _, recipient := range cfg.AllowedRecipients {
+		recipients = append(recipients, strings.ToLower(strings.TrimSpace(recipient)))
+	}
+	cfg.AllowedRecipients = recipients
+
+	var allowed []string
+	if cfg.AllowSelf {
+		allowed = append(allowed, "调用者自己的账号邮箱")
+	}
+	allowed = append(allowed, cfg.AllowedRecipients...)
+	return &SendEmailTool{
+		BaseTool: &mcp.BaseTool{
+			Name: SendEmailToolName,
+			Description: "发送纯文本邮件，例如把分析结果或报告发给自己或同事。只能发送到允许的收件人: " +
+				strings.Join(allowed, ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"to": map[string]interface{}{
+						"type":        "array",
+						"description": fmt.Sprintf("收件人邮箱地址，最多 %d 个，省略时发送到调用者自己的账号邮箱", cfg.MaxRecipients),
+						"items":       map[string]interface{}{"type": "string"},
+						"maxItems":    cfg.MaxRecipients,
+					},
+					"subject": map[string]interface{}{
+						"type":        "string",
+						"description": "邮件主题",
+						"maxLength":   maxEmailSubjectLength,
+					},
+					"body": map[string]interface{}{
+						"type":        "string",
+						"description": "邮件正文 (纯文本)",
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"subject", "body"},
+			},
+		},
+		cfg:    cfg,
+		send:   send,
+		caller: caller,
+		limits: limits,
+		logger: logger,
+	}
+}
+
+// Execute 校验收件人和频率后逐个发送
+func (se *SendEmailTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := se.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	userID, email, err := se.caller(ctx)
+	if err != nil {
+		return textErrorResponse("发送邮件需要登录后使用"), nil
+	}
+
+	subject := strings.TrimSpace(args["subject"].(string))
+	recipients, _ := stringSliceArg(args, "to")
+	if len(recipients) == 0 {
+		if email == "" {
+			return textErrorResponse("账号没有邮箱，请指定收件人"), nil
+		}
+		recipients = []string{email}
+	}
+	addresses := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		addr, _ := mail.ParseAddress(recipient)
+		if !se.allowed(addr.Address, email) {
+			se.audit("rejected", userID, subject, []string{addr.Address}, nil)
+			return textErrorResponse(fmt.Sprintf("收件人 %s 不在允许列表中", addr.Address)), nil
+		}
+		if !containsString(addresses, addr.Address) {
+			addresses = append(addresses, addr.Address)
+		}
+	}
+
+	if se.limits != nil && se.cfg.RateLimit.Enabled() {
+		limit, err := se.limits.Take(ctx, "mcp:send_email:"+userID, se.cfg.RateLimit)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("检查发送频率失败: %v", err)), nil
+		}
+		if !limit.Allowed {
+			se.audit("rate_limited", userID, subject, addresses, nil)
+			return textErrorResponse(fmt.Sprintf("发送过于频繁，请在 %d 秒后重试", int(math.Ceil(limit.RetryAfter.Seconds())))), nil
+		}
+	}
+
+	result := &dto.EmailSendResult{Subject: subject, Sent: []string{}, Failed: []dto.EmailFailure{}}
+	body := args["body"].(string)
+	for _, address := range addresses {
+		if err := se.send(ctx, address, subject, body); err != nil {
+			result.Failed = append(result.Failed, dto.EmailFailure{Recipient: address, Error: err.Error()})
+			continue
+		}
+		result.Sent = append(result.Sent, address)
+	}
+	se.audit("sent", userID, subject, result.Sent, result.Failed)
+
+	if wantsJSON(args) {
+		response := jsonResponse(result)
+		response.IsError = len(result.Sent) == 0
+		return response, nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatEmailSendResult(result),
+				Data: result,
+			},
+		},
+		IsError: len(result.Sent) == 0,
+	}, nil
+}
+
+// Validate 验证参数
+func (se *SendEmailTool) Validate(args map[string]interface{}) error {
+	subject, ok := args["subject"].(string)
+	if !ok || strings.TrimSpace(subject) == "" {
+		return fmt.Errorf("subject 参数是必需的且必须是字符串")
+	}
+	if len([]rune(subject)) > maxEmailSubjectLength || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("subject 不能超过 %d 个字符且不能换行", maxEmailSubjectLength)
+	}
+	body, ok := args["body"].(string)
+	if !ok || strings.TrimSpace(body) == "" {
+		return fmt.Errorf("body 参数是必需的且必须是字符串")
+	}
+	if len(body) > se.cfg.MaxBodyBytes {
+		return fmt.Errorf("body 不能超过 %d 字节", se.cfg.MaxBodyBytes)
+	}
+
+	recipients, err := stringSliceArg(args, "to")
+	if err != nil {
+		return err
+	}
+	if len(recipients) > se.cfg.MaxRecipients {
+		return fmt.Errorf("to 最多 %d 个收件人", se.cfg.MaxRecipients)
+	}
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("无效的邮箱地址 %q", recipient)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// allowed 判断收件人是否允许，self 为调用者的账号邮箱
+func (se *SendEmailTool) allowed(address, self string) bool {
+	address = strings.ToLower(address)
+	if se.cfg.AllowSelf && self != "" && strings.EqualFold(address, self) {
+		return true
+	}
+	for _, entry := range se.cfg.AllowedRecipients {
+		if strings.HasPrefix(entry, "@") {
+			if strings.HasSuffix(address, entry) {
+				return true
+			}
+		} else if address == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// audit 记录审计日志
+func (se *SendEmailTool) audit(result, userID, subject string, recipients []string, failed []dto.EmailFailure) {
+	fields := []zap.Field{
+		zap.String("audit", "mcp.send_email"),
+		zap.String("result", result),
+		zap.String("user_id", userID),
+		zap.String("subject", subject),
+		zap.Strings("recipients", recipients),
+	}
+	if len(failed) > 0 {
+		fields = append(fields, zap.Int("failed", len(failed)))
+	}
+	se.logger.Info("Send email tool invoked", fields...)
+}
+
+// formatEmailSendResult 格式化发送结果
+func formatEmailSendResult(result *dto.EmailSendResult) string {
+	var sb strings.Builder
+	if len(result.Sent) > 0 {
+		sb.WriteString(fmt.Sprintf("📧 已发送「%s」到 %s\n", result.Subject, strings.Join(result.Sent, ", ")))
+	}
+	for _, failure := range result.Failed {
+		sb.WriteString(fmt.Sprintf("❌ 发送到 %s 失败: %s\n", failure.Recipient, failure.Error))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSendEmailTool(t *testing.T) {
+	var sent []string
+	send := func(ctx context.Context, to, subject, body string) error {
+		if strings.HasPrefix(to, "bounce@") {
+			return errors.New("550 mailbox unavailable")
+		}
+		sent = append(sent, to+"|"+subject+"|"+body)
+		return nil
+	}
+	userID := "7"
+	caller := func(ctx context.Context) (string, string, error) {
+		if userID == "" {
+			return "", "", errors.New("authentication required")
+		}
+		return userID, "Ann@Acme.com", nil
+	}
+	core, logs := observer.New(zap.InfoLevel)
+	tool := NewSendEmailTool(SendEmailConfig{
+		AllowedRecipients: []string{"@Team.acme.com", "cfo@acme.com"},
+		AllowSelf:         true,
+		MaxRecipients:     3,
+		MaxBodyBytes:      64,
+		RateLimit:         ratelimit.Rule{Rate: 0.001, Burst: 3},
+	}, send, caller, ratelimit.NewMemoryStore(), zap.New(core))
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{"subject": "Daily report", "body": "AAPL +2%"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, []string{"Ann@Acme.com|Daily report|AAPL +2%"}, sent)
+	assert.Equal(t, "📧 已发送「Daily report」到 Ann@Acme.com", resp.Content[0].Text)
+
+	sent = nil
+	resp, err = tool.Execute(ctx, map[string]interface{}{"subject": "Q3", "body": "x", "to": []interface{}{"Bob <bob@team.acme.com>", "bounce@team.acme.com", "bob@team.acme.com"}})
+	require.NoError(t, err)
+	assert.False(t, resp.IsError)
+	result := resp.Content[0].Data.(*dto.EmailSendResult)
+	assert.Equal(t, []string{"bob@team.acme.com"}, result.Sent)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "bounce@team.acme.com", result.Failed[0].Recipient)
+	assert.Len(t, sent, 1)
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "mcp.send_email", entries[1].ContextMap()["audit"])
+	assert.Equal(t, "sent", entries[1].ContextMap()["result"])
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"not allowlisted", map[string]interface{}{"subject": "x", "body": "x", "to": []interface{}{"eve@evil.com"}}, "不在允许列表中"},
+		{"lookalike domain", map[string]interface{}{"subject": "x", "body": "x", "to": []interface{}{"eve@evilteam.acme.com"}}, "不在允许列表中"},
+		{"header injection", map[string]interface{}{"subject": "x\r\nBcc: eve@evil.com", "body": "x"}, "subject"},
+		{"too many recipients", map[string]interface{}{"subject": "x", "body": "x", "to": []interface{}{"a@x.com", "b@x.com", "c@x.com", "d@x.com"}}, "最多 3 个"},
+		{"body too large", map[string]interface{}{"subject": "x", "body": strings.Repeat("a", 65)}, "body"},
+		{"invalid address", map[string]interface{}{"subject": "x", "body": "x", "to": []interface{}{"not an email"}}, "无效的邮箱地址"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	// 第三次发送用完令牌桶，第四次被限流
+	resp, err = tool.Execute(ctx, map[string]interface{}{"subject": "x", "body": "x", "to": []interface{}{"cfo@acme.com"}})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	resp, err = tool.Execute(ctx, map[string]interface{}{"subject": "x", "body": "x"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "发送过于频繁")
+
+	userID = ""
+	resp, err = tool.Execute(ctx, map[string]interface{}{"subject": "x", "body": "x"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Content[0].Text, "登录")
+}
@@ -0,0 +1,456 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// SheetsExportToolName Google Sheets 导出工具注册名称
+const SheetsExportToolName = "sheets_export"
+
+const (
+	// DefaultSheetsMaxRows 单次导出的默认最大数据行数
+	DefaultSheetsMaxRows = 5000
+	// maxSheetsColumns 单次导出的最大列数
+	maxSheetsColumns = 100
+	// maxSheetTitleLength 表格和工作表名称的最大长度
+	maxSheetTitleLength = 100
+	// maxSheetsCellLength 单元格内容的最大长度，与 Google Sheets 的限制一致
+	maxSheetsCellLength = 50000
+
+	sheetsAPIBase = "https://sheets.googleapis.com/v4"
+	driveAPIBase  = "https://www.googleapis.com/drive/v3"
+)
+
+// sheetNameReplacer 替换工作表名称中不允许的字符
+var sheetNameReplacer = strings.NewReplacer("[", "(", "]", ")", "*", "-", "?", "-", ":", "-", "/", "-", "\\", "-")
+
+// SheetsExportConfig Google Sheets 导出工具配置
+type SheetsExportConfig struct {
+	ShareWith       []string // 新建表格总是共享给这些邮箱（编辑权限）
+	ShareWithCaller bool     // 新建表格共享给调用者的账号邮箱
+	MaxRows         int      // 单次导出的最大数据行数
+}
+
+// SheetsExportTool Google Sheets 导出工具，用服务账号把分析或对比结果写入新表格或已有表格的新工作表，
+// 返回表格链接。服务账号创建的表格只有共享后其他人才能打开，共享对象由配置决定，不接受模型指定
+type SheetsExportTool struct {
+	*mcp.BaseTool
+	cfg        SheetsExportConfig
+	token      TokenFunc
+	caller     CallerEmailFunc
+	sheetsURL  string
+	driveURL   string
+	httpClient *http.Client
+}
+
+// NewSheetsExportTool 创建 Google Sheets 导出工具，token 返回服务账号的访问令牌
+func NewSheetsExportTool(cfg SheetsExportConfig, token TokenFunc, caller CallerEmailFunc) *SheetsExportTool {
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultSheetsMaxRows
+	}
+	return &SheetsExportTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        SheetsExportToolName,
+			Description: "把结构化的分析或对比结果写入 Google Sheets 表格并返回链接。默认新建表格，指定 spreadsheet_id 时在已有表格中新增工作表",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "新建表格的标题，例如 'AAPL vs MSFT 2024Q3 对比'",
+						"maxLength":   maxSheetTitleLength,
+					},
+					"columns": map[string]interface{}{
+						"type":        "array",
+						"description": fmt.Sprintf("表头，最多 %d 列", maxSheetsColumns),
+						"items":       map[string]interface{}{"type": "string"},
+						"minItems":    1,
+						"maxItems":    maxSheetsColumns,
+					},
+					"rows": map[string]interface{}{
+						"type":        "array",
+						"description": fmt.Sprintf("数据行，每行是与表头对应的单元格数组 (字符串、数字、布尔或 null)，最多 %d 行", cfg.MaxRows),
+						"items":       map[string]interface{}{"type": "array"},
+						"maxItems":    cfg.MaxRows,
+					},
+					"spreadsheet_id": map[string]interface{}{
+						"type":        "string",
+						"description": "已有表格的 ID (链接中 /d/ 后的部分)，需已共享给服务账号编辑权限；省略时新建表格",
+					},
+					"sheet": map[string]interface{}{
+						"type":        "string",
+						"description": "工作表名称，默认使用 title",
+						"maxLength":   maxSheetTitleLength,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"title", "columns", "rows"},
+			},
+		},
+		cfg:       cfg,
+		token:     token,
+		caller:    caller,
+		sheetsURL: sheetsAPIBase,
+		driveURL:  driveAPIBase,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// Execute 创建表格或工作表并写入数据
+func (st *SheetsExportTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := st.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	_, email, err := st.caller(ctx)
+	if err != nil {
+		return textErrorResponse("导出到 Google Sheets 需要登录后使用"), nil
+	}
+	token, err := st.token(ctx)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("获取 Google 访问令牌失败: %v", err)), nil
+	}
+
+	title := strings.TrimSpace(args["title"].(string))
+	sheet := sanitizeSheetName(stringArg(args, "sheet", title))
+	columns, _ := stringSliceArg(args, "columns")
+	values := sheetValues(columns, args["rows"].([]interface{}))
+
+	result := &dto.SheetsExportResult{Title: title, Sheet: sheet, SharedWith: []string{}}
+	if spreadsheetID := strings.TrimSpace(stringArg(args, "spreadsheet_id", "")); spreadsheetID != "" {
+		err = st.addSheet(ctx, token, spreadsheetID, result)
+	} else {
+		err = st.createSpreadsheet(ctx, token, result)
+	}
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("创建表格失败: %v", err)), nil
+	}
+
+	if err := st.writeValues(ctx, token, result.SpreadsheetID, sheet, values); err != nil {
+		return textErrorResponse(fmt.Sprintf("写入数据失败: %v (表格: %s)", err, result.URL)), nil
+	}
+	result.Rows = len(values) - 1
+	result.Columns = len(columns)
+
+	// 只有新建的表格需要共享，已有表格的访问权限由其所有者管理
+	if result.Created {
+		for _, address := range st.shareTargets(email) {
+			if err := st.share(ctx, token, result.SpreadsheetID, address); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("共享给 %s 失败: %v", address, err))
+				continue
+			}
+			result.SharedWith = append(result.SharedWith, address)
+		}
+		if len(result.SharedWith) == 0 {
+			result.Warnings = append(result.Warnings, "表格未共享给任何人，只有服务账号可以访问")
+		}
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatSheetsExportResult(result),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (st *SheetsExportTool) Validate(args map[string]interface{}) error {
+	title, ok := args["title"].(string)
+	if !ok || strings.TrimSpace(title) == "" {
+		return fmt.Errorf("title 参数是必需的且必须是字符串")
+	}
+	if len([]rune(title)) > maxSheetTitleLength {
+		return fmt.Errorf("title 不能超过 %d 个字符", maxSheetTitleLength)
+	}
+	if sheet, ok := args["sheet"]; ok {
+		name, ok := sheet.(string)
+		if !ok || len([]rune(name)) > maxSheetTitleLength {
+			return fmt.Errorf("sheet 必须是不超过 %d 个字符的字符串", maxSheetTitleLength)
+		}
+	}
+	if id, ok := args["spreadsheet_id"]; ok {
+		if _, ok := id.(string); !ok {
+			return fmt.Errorf("spreadsheet_id 必须是字符串")
+		}
+	}
+
+	columns, err := stringSliceArg(args, "columns")
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 || len(columns) > maxSheetsColumns {
+		return fmt.Errorf("columns 需要 1 到 %d 列", maxSheetsColumns)
+	}
+
+	rows, ok := args["rows"].([]interface{})
+	if !ok {
+		return fmt.Errorf("rows 参数是必需的且必须是数组")
+	}
+	if len(rows) > st.cfg.MaxRows {
+		return fmt.Errorf("rows 最多 %d 行", st.cfg.MaxRows)
+	}
+	for i, row := range rows {
+		cells, ok := row.([]interface{})
+		if !ok {
+			return fmt.Errorf("rows[%d] 必须是数组", i)
+		}
+		if len(cells) > len(columns) {
+			return fmt.Errorf("rows[%d] 有 %d 个单元格，多于 %d 列", i, len(cells), len(columns))
+		}
+		for j, cell := range cells {
+			switch value := cell.(type) {
+			case nil, bool, float64, int, int64:
+			case string:
+				if len([]rune(value)) > maxSheetsCellLength {
+					return fmt.Errorf("rows[%d][%d] 不能超过 %d 个字符", i, j, maxSheetsCellLength)
+				}
+			default:
+				return fmt.Errorf("rows[%d][%d] 必须是字符串、数字、布尔或 null", i, j)
+			}
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// shareTargets 新建表格需要共享的邮箱，去除重复
+func (st *SheetsExportTool) shareTargets(callerEmail string) []string {
+	var targets []string
+	add := func(address string) {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			return
+		}
+		for _, existing := range targets {
+			if strings.EqualFold(existing, address) {
+				return
+			}
+		}
+		targets = append(targets, address)
+	}
+	if st.cfg.ShareWithCaller {
+		add(callerEmail)
+	}
+	for _, address := range st.cfg.ShareWith {
+		add(address)
+	}
+	return targets
+}
+
+// createSpreadsheet 新建只包含一个工作表的表格
+func (st *SheetsExportTool) createSpreadsheet(ctx context.Context, token string, result *dto.SheetsExportResult) error {
+	request := map[string]interface{}{
+		"properties": map[string]interface{}{"title": result.Title},
+		"sheets": []interface{}{
+			map[string]interface{}{"properties": map[string]interface{}{"title": result.Sheet}},
+		},
+	}
+	var response struct {
+		SpreadsheetID  string `json:"spreadsheetId"`
+		SpreadsheetURL string `json:"spreadsheetUrl"`
+		Sheets         []struct {
+			Properties struct {
+				SheetID int64 `json:"sheetId"`
+			} `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := st.do(ctx, token, http.MethodPost, st.sheetsURL+"/spreadsheets", request, &response); err != nil {
+		return err
+	}
+
+	result.SpreadsheetID = response.SpreadsheetID
+	result.URL = response.SpreadsheetURL
+	result.Created = true
+	if len(response.Sheets) > 0 {
+		result.SheetID = response.Sheets[0].Properties.SheetID
+	}
+	return nil
+}
+
+// addSheet 在已有表格中新增工作表，表格标题以已有表格为准
+func (st *SheetsExportTool) addSheet(ctx context.Context, token, spreadsheetID string, result *dto.SheetsExportResult) error {
+	request := map[string]interface{}{
+		"requests": []interface{}{
+			map[string]interface{}{
+				"addSheet": map[string]interface{}{
+					"properties": map[string]interface{}{"title": result.Sheet},
+				},
+			},
+		},
+		"includeSpreadsheetInResponse": true,
+		"responseIncludeGridData":      false,
+	}
+	var response struct {
+		Replies []struct {
+			AddSheet struct {
+				Properties struct {
+					SheetID int64 `json:"sheetId"`
+				} `json:"properties"`
+			} `json:"addSheet"`
+		} `json:"replies"`
+		UpdatedSpreadsheet struct {
+			Properties struct {
+				Title string `json:"title"`
+			} `json:"properties"`
+		} `json:"updatedSpreadsheet"`
+	}
+	path := st.sheetsURL + "/spreadsheets/" + url.PathEscape(spreadsheetID) + ":batchUpdate"
+	if err := st.do(ctx, token, http.MethodPost, path, request, &response); err != nil {
+		return err
+	}
+
+	result.SpreadsheetID = spreadsheetID
+	if title := response.UpdatedSpreadsheet.Properties.Title; title != "" {
+		result.Title = title
+	}
+	if len(response.Replies) > 0 {
+		result.SheetID = response.Replies[0].AddSheet.Properties.SheetID
+	}
+	result.URL = fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit#gid=%d", spreadsheetID, result.SheetID)
+	return nil
+}
+
+// writeValues 从工作表左上角写入表头和数据，值按原样保存，不解析公式
+func (st *SheetsExportTool) writeValues(ctx context.Context, token, spreadsheetID, sheet string, values [][]interface{}) error {
+	cellRange := "'" + strings.ReplaceAll(sheet, "'", "''") + "'!A1"
+	path := fmt.Sprintf("%s/spreadsheets/%s/values/%s?valueInputOption=RAW",
+		st.sheetsURL, url.PathEscape(spreadsheetID), url.PathEscape(cellRange))
+	request := map[string]interface{}{
+		"range":          cellRange,
+		"majorDimension": "ROWS",
+		"values":         values,
+	}
+	return st.do(ctx, token, http.MethodPut, path, request, nil)
+}
+
+// share 以编辑权限共享表格，不发送 Google 的通知邮件
+func (st *SheetsExportTool) share(ctx context.Context, token, spreadsheetID, address string) error {
+	path := st.driveURL + "/files/" + url.PathEscape(spreadsheetID) + "/permissions?sendNotificationEmail=false"
+	request := map[string]interface{}{
+		"type":         "user",
+		"role":         "writer",
+		"emailAddress": address,
+	}
+	return st.do(ctx, token, http.MethodPost, path, request, nil)
+}
+
+// do 调用 Google API
+func (st *SheetsExportTool) do(ctx context.Context, token, method, endpoint string, request, response interface{}) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("编码请求失败: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := st.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Google API 返回状态码 %d: %s", resp.StatusCode, googleErrorMessage(body))
+	}
+	if response == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// googleErrorMessage 提取 Google API 错误响应中的 error.message
+func googleErrorMessage(data []byte) string {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &payload) != nil || payload.Error.Message == "" {
+		return truncateRunes(strings.TrimSpace(string(data)), 200)
+	}
+	return payload.Error.Message
+}
+
+// sanitizeSheetName 替换工作表名称中不允许的字符并截断
+func sanitizeSheetName(name string) string {
+	name = strings.TrimSpace(sheetNameReplacer.Replace(name))
+	if name == "" {
+		return "Sheet1"
+	}
+	return truncateRunes(name, maxSheetTitleLength)
+}
+
+// sheetValues 组合表头和数据行，null 写为空单元格，不足的列补空
+func sheetValues(columns []string, rows []interface{}) [][]interface{} {
+	values := make([][]interface{}, 0, len(rows)+1)
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	values = append(values, header)
+
+	for _, row := range rows {
+		cells := row.([]interface{})
+		line := make([]interface{}, len(columns))
+		for i := range line {
+			line[i] = ""
+			if i < len(cells) && cells[i] != nil {
+				line[i] = cells[i]
+			}
+		}
+		values = append(values, line)
+	}
+	return values
+}
+
+// formatSheetsExportResult 格式化导出结果
+func formatSheetsExportResult(result *dto.SheetsExportResult) string {
+	var sb strings.Builder
+	if result.Created {
+		sb.WriteString(fmt.Sprintf("📊 已新建表格「%s」", result.Title))
+	} else {
+		sb.WriteString(fmt.Sprintf("📊 已在表格「%s」中新增工作表「%s」", result.Title, result.Sheet))
+	}
+	sb.WriteString(fmt.Sprintf("，写入 %d 行 × %d 列\n%s\n", result.Rows, result.Columns, result.URL))
+	if len(result.SharedWith) > 0 {
+		sb.WriteString(fmt.Sprintf("已共享给: %s\n", strings.Join(result.SharedWith, ", ")))
+	}
+	for _, warning := range result.Warnings {
+		sb.WriteString(fmt.Sprintf("⚠️ %s\n", warning))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
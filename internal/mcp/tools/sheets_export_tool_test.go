@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSheetsExportTool(t *testing.T) {
+	var created, written, added map[string]interface{}
+	var writtenPath string
+	var shared []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v4/spreadsheets", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.Write([]byte(`{"spreadsheetId":"abc123","spreadsheetUrl":"https://docs.google.com/spreadsheets/d/abc123/edit","sheets":[{"properties":{"sheetId":0}}]}`))
+	})
+	mux.HandleFunc("POST /v4/spreadsheets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "existing:batchUpdate", r.PathValue("id"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&added))
+		w.Write([]byte(`{"replies":[{"addSheet":{"properties":{"sheetId":42}}}],"updatedSpreadsheet":{"properties":{"title":"Team dashboard"}}}`))
+	})
+	mux.HandleFunc("PUT /v4/spreadsheets/{id}/values/{range}", func(w http.ResponseWriter, r *http.Request) {
+		writtenPath = r.PathValue("id") + " " + r.PathValue("range") + " " + r.URL.Query().Get("valueInputOption")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("POST /drive/v3/files/abc123/permissions", func(w http.ResponseWriter, r *http.Request) {
+		var permission map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&permission))
+		if permission["emailAddress"] == "gone@acme.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"Invalid sharing request"}}`))
+			return
+		}
+		assert.Equal(t, "writer", permission["role"])
+		assert.Equal(t, "false", r.URL.Query().Get("sendNotificationEmail"))
+		shared = append(shared, permission["emailAddress"])
+		w.Write([]byte(`{"id":"p1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	authenticated := true
+	caller := func(ctx context.Context) (string, string, error) {
+		if !authenticated {
+			return "", "", errors.New("authentication required")
+		}
+		return "7", "ann@acme.com", nil
+	}
+	token := func(ctx context.Context) (string, error) { return "ya29.token", nil }
+	tool := NewSheetsExportTool(SheetsExportConfig{
+		ShareWith:       []string{"analysts@acme.com", "ANN@acme.com", "gone@acme.com"},
+		ShareWithCaller: true,
+		MaxRows:         3,
+	}, token, caller)
+	tool.sheetsURL = server.URL + "/v4"
+	tool.driveURL = server.URL + "/drive/v3"
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{
+		"title":   "AAPL vs MSFT",
+		"columns": []interface{}{"Symbol", "P/E", "Dividend"},
+		"rows": []interface{}{
+			[]interface{}{"AAPL", 29.5, true},
+			[]interface{}{"MSFT", nil},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	result := resp.Content[0].Data.(*dto.SheetsExportResult)
+	assert.True(t, result.Created)
+	assert.Equal(t, "https://docs.google.com/spreadsheets/d/abc123/edit", result.URL)
+	assert.Equal(t, 2, result.Rows)
+	assert.Equal(t, 3, result.Columns)
+	assert.Equal(t, []string{"ann@acme.com", "analysts@acme.com"}, result.SharedWith)
+	assert.Equal(t, shared, result.SharedWith)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "Invalid sharing request")
+	assert.Equal(t, "AAPL vs MSFT", created["properties"].(map[string]interface{})["title"])
+	assert.Equal(t, "abc123 'AAPL vs MSFT'!A1 RAW", writtenPath)
+	assert.Equal(t, []interface{}{
+		[]interface{}{"Symbol", "P/E", "Dividend"},
+		[]interface{}{"AAPL", 29.5, true},
+		[]interface{}{"MSFT", "", ""},
+	}, written["values"])
+
+	shared = nil
+	resp, err = tool.Execute(ctx, map[string]interface{}{
+		"title":          "ignored",
+		"spreadsheet_id": "existing",
+		"sheet":          "Q3: peers",
+		"columns":        []interface{}{"Symbol"},
+		"rows":           []interface{}{},
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	result = resp.Content[0].Data.(*dto.SheetsExportResult)
+	assert.False(t, result.Created)
+	assert.Equal(t, "Team dashboard", result.Title)
+	assert.Equal(t, "Q3- peers", result.Sheet)
+	assert.Equal(t, "https://docs.google.com/spreadsheets/d/existing/edit#gid=42", result.URL)
+	assert.Empty(t, shared, "existing spreadsheets are not reshared")
+	assert.Equal(t, "📊 已在表格「Team dashboard」中新增工作表「Q3- peers」，写入 0 行 × 1 列\nhttps://docs.google.com/spreadsheets/d/existing/edit#gid=42", resp.Content[0].Text)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"missing columns", map[string]interface{}{"title": "x", "rows": []interface{}{}}, "columns"},
+		{"too many rows", map[string]interface{}{"title": "x", "columns": []interface{}{"a"}, "rows": []interface{}{[]interface{}{1.0}, []interface{}{2.0}, []interface{}{3.0}, []interface{}{4.0}}}, "最多 3 行"},
+		{"row wider than header", map[string]interface{}{"title": "x", "columns": []interface{}{"a"}, "rows": []interface{}{[]interface{}{1.0, 2.0}}}, "多于 1 列"},
+		{"nested cell", map[string]interface{}{"title": "x", "columns": []interface{}{"a"}, "rows": []interface{}{[]interface{}{map[string]interface{}{}}}}, "rows[0][0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	authenticated = false
+	resp, err = tool.Execute(ctx, map[string]interface{}{"title": "x", "columns": []interface{}{"a"}, "rows": []interface{}{}})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "登录")
+}
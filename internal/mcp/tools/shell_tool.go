@@ -0,0 +1,238 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/cmdsandbox"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+
+	"go.uber.org/zap"
+)
+
+// ShellToolName 沙箱命令工具注册名称
+const ShellToolName = "shell"
+
+const (
+	// maxShellArgs 单次调用允许的最大参数个数
+	maxShellArgs = 64
+	// maxShellArgLength 单个参数的最大长度
+	maxShellArgLength = 4096
+)
+
+// AdminCheckFunc 校验调用者是否为管理员，返回调用者的用户ID（用于审计，未认证时为空），
+// 非管理员时返回错误
+type AdminCheckFunc func(ctx context.Context) (string, error)
+
+// ShellTool 沙箱命令工具，供管理员通过助手执行运维命令。只允许白名单中的程序，
+// 在无网络、限制 CPU/内存/时间的环境中运行，每次调用无论成功与否都记录审计日志
+type ShellTool struct {
+	*mcp.BaseTool
+	sandbox *cmdsandbox.Sandbox
+	admin   AdminCheckFunc
+	logger  *zap.Logger
+}
+
+// NewShellTool 创建沙箱命令工具
+func NewShellTool(sandbox *cmdsandbox.Sandbox, admin AdminCheckFunc, logger *zap.Logger) *ShellTool {
+	return &ShellTool{
+		BaseTool: &mcp.BaseTool{
+			Name: ShellToolName,
+			Description: "在受限沙箱中执行白名单中的运维命令（仅限管理员）。命令不经过 shell 解析，" +
+				"不支持管道、重定向和变量展开；没有网络访问，并限制 CPU、内存和执行时间。允许的命令: " +
+				strings.Join(sandbox.Allowed(), ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"command": map[string]interface{}{
+						"type":        "string",
+						"description": "要执行的命令名称，必须在白名单中",
+						"enum":        sandbox.Allowed(),
+					},
+					"args": map[string]interface{}{
+						"type":        "array",
+						"description": "命令参数，每个元素原样传给命令",
+						"items":       map[string]interface{}{"type": "string", "maxLength": maxShellArgLength},
+						"maxItems":    maxShellArgs,
+					},
+					"timeout_seconds": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("执行超时秒数，不能超过服务端上限 %d", int(sandbox.Timeout().Seconds())),
+						"minimum":     1,
+						"maximum":     int(sandbox.Timeout().Seconds()),
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"command"},
+			},
+		},
+		sandbox: sandbox,
+		admin:   admin,
+		logger:  logger,
+	}
+}
+
+// Execute 校验权限后在沙箱中执行命令
+func (st *ShellTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	command, _ := args["command"].(string)
+	commandArgs, _ := stringSliceArg(args, "args")
+
+	userID, err := st.admin(ctx)
+	if err != nil {
+		st.audit("denied", userID, command, commandArgs, nil, err)
+		return textErrorResponse("仅管理员可以执行 shell 工具"), nil
+	}
+	if err := st.Validate(args); err != nil {
+		st.audit("rejected", userID, command, commandArgs, nil, err)
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	timeout := time.Duration(intArg(args, "timeout_seconds", 0)) * time.Second
+	run, err := st.sandbox.Run(ctx, command, commandArgs, timeout)
+	if err != nil {
+		if errors.Is(err, cmdsandbox.ErrCommandNotAllowed) {
+			st.audit("rejected", userID, command, commandArgs, nil, err)
+			return textErrorResponse(fmt.Sprintf("命令不在白名单中: %s", command)), nil
+		}
+		st.audit("failed", userID, command, commandArgs, run, err)
+		return textErrorResponse(fmt.Sprintf("执行失败: %v", err)), nil
+	}
+	st.audit("executed", userID, command, commandArgs, run, nil)
+
+	result := &dto.ShellResult{
+		Command:    command,
+		Args:       commandArgs,
+		ExitCode:   run.ExitCode,
+		Stdout:     run.Stdout,
+		Stderr:     run.Stderr,
+		Truncated:  run.Truncated,
+		TimedOut:   run.TimedOut,
+		DurationMs: run.Duration.Milliseconds(),
+	}
+	if result.Args == nil {
+		result.Args = []string{}
+	}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatShellResult(result),
+				Data: result,
+			},
+		},
+		IsError: result.ExitCode != 0,
+	}, nil
+}
+
+// Validate 验证参数
+func (st *ShellTool) Validate(args map[string]interface{}) error {
+	command, ok := args["command"].(string)
+	if !ok || strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command 参数是必需的且必须是字符串")
+	}
+
+	commandArgs, err := stringSliceArg(args, "args")
+	if err != nil {
+		return err
+	}
+	if len(commandArgs) > maxShellArgs {
+		return fmt.Errorf("args 最多 %d 个", maxShellArgs)
+	}
+	for _, arg := range commandArgs {
+		if len(arg) > maxShellArgLength || strings.ContainsRune(arg, 0) {
+			return fmt.Errorf("args 中的参数过长或包含 NUL 字符")
+		}
+	}
+
+	if _, exists := args["timeout_seconds"]; exists {
+		limit := int(st.sandbox.Timeout().Seconds())
+		if timeout := intArg(args, "timeout_seconds", 0); timeout < 1 || timeout > limit {
+			return fmt.Errorf("timeout_seconds 必须在 1 到 %d 之间", limit)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// audit 记录审计日志，result 为 executed 以外的值时 run 可能为 nil
+func (st *ShellTool) audit(result, userID, command string, args []string, run *cmdsandbox.Result, err error) {
+	fields := []zap.Field{
+		zap.String("audit", "mcp.shell"),
+		zap.String("result", result),
+		zap.String("user_id", userID),
+		zap.String("command", command),
+		zap.Strings("args", args),
+	}
+	if run != nil {
+		fields = append(fields,
+			zap.String("path", run.Path),
+			zap.Int("exit_code", run.ExitCode),
+			zap.Duration("duration", run.Duration),
+			zap.Bool("timed_out", run.TimedOut),
+			zap.Bool("truncated", run.Truncated))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	st.logger.Warn("Shell tool invoked", fields...)
+}
+
+// stringSliceArg 读取字符串数组参数，参数不存在时返回 nil
+func stringSliceArg(args map[string]interface{}, name string) ([]string, error) {
+	switch value := args[name].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return value, nil
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			str, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s 必须是字符串数组", name)
+			}
+			values = append(values, str)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("%s 必须是字符串数组", name)
+}
+
+// formatShellResult 格式化命令输出
+func formatShellResult(result *dto.ShellResult) string {
+	var sb strings.Builder
+	sb.WriteString("$ " + result.Command)
+	for _, arg := range result.Args {
+		sb.WriteString(" " + arg)
+	}
+	sb.WriteString("\n")
+	if result.Stdout != "" {
+		sb.WriteString(result.Stdout)
+		if !strings.HasSuffix(result.Stdout, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	if result.Stderr != "" {
+		sb.WriteString("[stderr]\n" + result.Stderr)
+		if !strings.HasSuffix(result.Stderr, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	status := fmt.Sprintf("退出码 %d，耗时 %dms", result.ExitCode, result.DurationMs)
+	if result.TimedOut {
+		status = fmt.Sprintf("执行超时被终止，耗时 %dms", result.DurationMs)
+	}
+	if result.Truncated {
+		status += "，输出已截断"
+	}
+	sb.WriteString("— " + status)
+	return sb.String()
+}
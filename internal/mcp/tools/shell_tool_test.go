@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/cmdsandbox"
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestShellTool(t *testing.T) {
+	sandbox, err := cmdsandbox.New(cmdsandbox.Config{AllowedCommands: []string{"echo"}, WorkDir: t.TempDir()})
+	require.NoError(t, err)
+
+	admin := true
+	core, logs := observer.New(zap.WarnLevel)
+	tool := NewShellTool(sandbox, func(ctx context.Context) (string, error) {
+		if !admin {
+			return "2", errors.New("admin privileges required")
+		}
+		return "1", nil
+	}, zap.New(core))
+
+	resp, err := tool.Execute(context.Background(), map[string]interface{}{"command": "echo", "args": []interface{}{"hello;", "$(id)"}})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.True(t, strings.HasPrefix(resp.Content[0].Text, "$ echo hello; $(id)\nhello; $(id)\n— 退出码 0"), resp.Content[0].Text)
+	result := resp.Content[0].Data.(*dto.ShellResult)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Equal(t, "hello; $(id)\n", result.Stdout)
+
+	tests := []struct {
+		name    string
+		admin   bool
+		args    map[string]interface{}
+		message string
+		result  string
+	}{
+		{"non-admin", false, map[string]interface{}{"command": "echo"}, "仅管理员", "denied"},
+		{"not allowlisted", true, map[string]interface{}{"command": "rm", "args": []interface{}{"-rf", "/"}}, "命令不在白名单中", "rejected"},
+		{"invalid args", true, map[string]interface{}{"command": "echo", "args": "hello"}, "参数验证失败", "rejected"},
+		{"timeout above limit", true, map[string]interface{}{"command": "echo", "timeout_seconds": 3600}, "timeout_seconds", "rejected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin = tt.admin
+			resp, err := tool.Execute(context.Background(), tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+
+			entries := logs.TakeAll()
+			require.NotEmpty(t, entries)
+			fields := entries[len(entries)-1].ContextMap()
+			assert.Equal(t, "mcp.shell", fields["audit"])
+			assert.Equal(t, tt.result, fields["result"])
+		})
+	}
+
+	// 每次调用都有审计记录，包括成功执行
+	admin = true
+	_, err = tool.Execute(context.Background(), map[string]interface{}{"command": "echo", "format": "json"})
+	require.NoError(t, err)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "executed", fields["result"])
+	assert.Equal(t, "1", fields["user_id"])
+	assert.Equal(t, int64(0), fields["exit_code"])
+}
@@ -0,0 +1,406 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+
+	"go.uber.org/zap"
+)
+
+// SlackPostToolName Slack 消息工具注册名称
+const SlackPostToolName = "slack_post"
+
+const (
+	// maxSlackTextLength 正文的最大长度，与 Slack section 区块的限制一致
+	maxSlackTextLength = 3000
+	// maxSlackTitleLength 标题的最大长度，与 Slack header 区块的限制一致
+	maxSlackTitleLength = 150
+	// maxSlackFields 字段的最大个数，与 Slack section 区块的限制一致
+	maxSlackFields = 10
+	// maxSlackFieldLength 单个字段值的最大长度
+	maxSlackFieldLength = 1900
+
+	slackAPIBase = "https://slack.com/api"
+)
+
+// slackLevels 消息级别及其标记
+var slackLevels = map[string]string{
+	"info":     "ℹ️",
+	"success":  "✅",
+	"warning":  "⚠️",
+	"critical": "🚨",
+}
+
+// SlackPostConfig Slack 消息工具配置，可发送的频道由部署配置决定，不接受模型指定其他频道
+type SlackPostConfig struct {
+	BotToken string            // Bot 令牌 (xoxb-...)，为空时只能通过 Webhook 发送
+	Channels []string          // 通过 Bot 令牌可发送的频道名称 (#alerts) 或ID
+	Webhooks map[string]string // 频道名称到 Incoming Webhook 地址，优先于 Bot 令牌
+}
+
+// slackTarget 允许发送的频道
+type slackTarget struct {
+	channel string // Bot 方式使用的频道名称或ID
+	webhook string // Webhook 方式使用的地址
+}
+
+// SlackPostTool Slack 消息工具，把格式化的分析摘要或告警发送到允许的频道，每次发送都记录审计日志
+type SlackPostTool struct {
+	*mcp.BaseTool
+	botToken   string
+	targets    map[string]slackTarget
+	names      []string
+	userID     UserIDFunc
+	logger     *zap.Logger
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewSlackPostTool 创建 Slack 消息工具，第一个允许的频道为默认频道
+func NewSlackPostTool(cfg SlackPostConfig, userID UserIDFunc, logger *zap.Logger) *SlackPostTool {
+	targets := make(map[string]slackTarget)
+	var names []string
+	if cfg.BotToken != "" {
+		for _, channel := range cfg.Channels {
+			channel = strings.TrimSpace(channel)
+			name := normalizeSlackChannel(channel)
+			if name == "" {
+				continue
+			}
+			if _, ok := targets[name]; !ok {
+				names = append(names, name)
+			}
+			targets[name] = slackTarget{channel: channel}
+		}
+	}
+	webhookNames := make([]string, 0, len(cfg.Webhooks))
+	for channel := range cfg.Webhooks {
+		webhookNames = append(webhookNames, channel)
+	}
+	sort.Strings(webhookNames)
+	for _, channel := range webhookNames {
+		name := normalizeSlackChannel(channel)
+		webhook := strings.TrimSpace(cfg.Webhooks[channel])
+		if name == "" || webhook == "" {
+			continue
+		}
+		if _, ok := targets[name]; !ok {
+			names = append(names, name)
+		}
+		targets[name] = slackTarget{webhook: webhook}
+	}
+
+	levels := make([]string, 0, len(slackLevels))
+	for level := range slackLevels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	return &SlackPostTool{
+		BaseTool: &mcp.BaseTool{
+			Name: SlackPostToolName,
+			Description: "向 Slack 频道发送格式化消息，例如分析摘要或告警通知。只能发送到允许的频道: " +
+				strings.Join(names, ", "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "频道名称，省略时发送到默认频道",
+						"enum":        names,
+						"default":     firstString(names),
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "消息标题",
+						"maxLength":   maxSlackTitleLength,
+					},
+					"text": map[string]interface{}{
+						"type":        "string",
+						"description": "消息正文，支持 Slack mrkdwn (*粗体*、_斜体_、`代码`、<url|链接>)",
+						"maxLength":   maxSlackTextLength,
+					},
+					"level": map[string]interface{}{
+						"type":        "string",
+						"description": "消息级别，决定标题前的标记",
+						"enum":        levels,
+						"default":     "info",
+					},
+					"fields": map[string]interface{}{
+						"type":        "array",
+						"description": fmt.Sprintf("以两列显示的关键指标，最多 %d 个", maxSlackFields),
+						"maxItems":    maxSlackFields,
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"title": map[string]interface{}{"type": "string"},
+								"value": map[string]interface{}{"type": "string"},
+							},
+							"required": []string{"title", "value"},
+						},
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"text"},
+			},
+		},
+		botToken: cfg.BotToken,
+		targets:  targets,
+		names:    names,
+		userID:   userID,
+		logger:   logger,
+		apiURL:   slackAPIBase,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// Execute 校验频道后发送消息
+func (sp *SlackPostTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := sp.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	userID := sp.userID(ctx)
+	if userID == "" {
+		return textErrorResponse("发送 Slack 消息需要登录后使用"), nil
+	}
+
+	name := normalizeSlackChannel(stringArg(args, "channel", firstString(sp.names)))
+	target, ok := sp.targets[name]
+	if !ok {
+		sp.audit("rejected", userID, name, nil)
+		return textErrorResponse(fmt.Sprintf("频道 %s 不在允许列表中，可用频道: %s", name, strings.Join(sp.names, ", "))), nil
+	}
+
+	text, blocks := slackMessage(args, userID)
+	result := &dto.SlackPostResult{Channel: name}
+	var err error
+	if target.webhook != "" {
+		result.Via = "webhook"
+		err = sp.postWebhook(ctx, target.webhook, text, blocks)
+	} else {
+		result.Via = "bot"
+		result.Timestamp, err = sp.postMessage(ctx, target.channel, text, blocks)
+	}
+	if err != nil {
+		sp.audit("failed", userID, name, err)
+		return textErrorResponse(fmt.Sprintf("发送到 Slack 频道 %s 失败: %v", name, err)), nil
+	}
+	sp.audit("sent", userID, name, nil)
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("💬 已发送到 Slack 频道 #%s", name),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (sp *SlackPostTool) Validate(args map[string]interface{}) error {
+	text, ok := args["text"].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return fmt.Errorf("text 参数是必需的且必须是字符串")
+	}
+	if len([]rune(text)) > maxSlackTextLength {
+		return fmt.Errorf("text 不能超过 %d 个字符", maxSlackTextLength)
+	}
+	if title, ok := args["title"]; ok {
+		value, ok := title.(string)
+		if !ok || len([]rune(value)) > maxSlackTitleLength || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("title 必须是不超过 %d 个字符的单行字符串", maxSlackTitleLength)
+		}
+	}
+	if channel, ok := args["channel"]; ok {
+		if _, ok := channel.(string); !ok {
+			return fmt.Errorf("channel 必须是字符串")
+		}
+	}
+	if level := stringArg(args, "level", "info"); slackLevels[level] == "" {
+		return fmt.Errorf("level 必须是 info、success、warning 或 critical")
+	}
+
+	if raw, ok := args["fields"]; ok && raw != nil {
+		fields, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("fields 必须是数组")
+		}
+		if len(fields) > maxSlackFields {
+			return fmt.Errorf("fields 最多 %d 个", maxSlackFields)
+		}
+		for i, raw := range fields {
+			field, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("fields[%d] 必须是包含 title 和 value 的对象", i)
+			}
+			title, ok := field["title"].(string)
+			if !ok || strings.TrimSpace(title) == "" {
+				return fmt.Errorf("fields[%d].title 是必需的且必须是字符串", i)
+			}
+			value, ok := field["value"].(string)
+			if !ok {
+				return fmt.Errorf("fields[%d].value 必须是字符串", i)
+			}
+			if len([]rune(title))+len([]rune(value)) > maxSlackFieldLength {
+				return fmt.Errorf("fields[%d] 不能超过 %d 个字符", i, maxSlackFieldLength)
+			}
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// postMessage 通过 Bot 令牌调用 chat.postMessage，返回消息的 ts
+func (sp *SlackPostTool) postMessage(ctx context.Context, channel, text string, blocks []interface{}) (string, error) {
+	request := map[string]interface{}{
+		"channel":      channel,
+		"text":         text,
+		"blocks":       blocks,
+		"unfurl_links": false,
+	}
+	body, err := sp.post(ctx, sp.apiURL+"/chat.postMessage", request, sp.botToken)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		OK    bool   `json:"ok"`
+		TS    string `json:"ts"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("解析响应失败: %w", err)
+	}
+	if !response.OK {
+		return "", fmt.Errorf("Slack API 返回错误: %s", response.Error)
+	}
+	return response.TS, nil
+}
+
+// postWebhook 通过 Incoming Webhook 发送消息
+func (sp *SlackPostTool) postWebhook(ctx context.Context, webhook, text string, blocks []interface{}) error {
+	_, err := sp.post(ctx, webhook, map[string]interface{}{"text": text, "blocks": blocks}, "")
+	return err
+}
+
+// post 发送 JSON 请求，token 不为空时作为 Bearer 令牌
+func (sp *SlackPostTool) post(ctx context.Context, endpoint string, request interface{}, token string) ([]byte, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("编码请求失败: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := sp.httpClient.Do(req)
+	if err != nil {
+		// Webhook 地址本身就是凭据，不写入错误信息
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Slack 返回状态码 %d: %s", resp.StatusCode, truncateRunes(string(bytes.TrimSpace(body)), 200))
+	}
+	return body, nil
+}
+
+// audit 记录审计日志
+func (sp *SlackPostTool) audit(result, userID, channel string, err error) {
+	fields := []zap.Field{
+		zap.String("audit", "mcp.slack_post"),
+		zap.String("result", result),
+		zap.String("user_id", userID),
+		zap.String("channel", channel),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	sp.logger.Info("Slack post tool invoked", fields...)
+}
+
+// slackMessage 构造 Block Kit 消息，返回通知中显示的纯文本和区块
+func slackMessage(args map[string]interface{}, userID string) (string, []interface{}) {
+	mark := slackLevels[stringArg(args, "level", "info")]
+	text := strings.TrimSpace(args["text"].(string))
+	title := strings.TrimSpace(stringArg(args, "title", ""))
+
+	var blocks []interface{}
+	fallback := mark + " " + text
+	if title != "" {
+		fallback = mark + " " + title + "\n" + text
+		blocks = append(blocks, map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": mark + " " + title, "emoji": true},
+		})
+	}
+	blocks = append(blocks, map[string]interface{}{
+		"type": "section",
+		"text": map[string]interface{}{"type": "mrkdwn", "text": text},
+	})
+
+	if raw, ok := args["fields"].([]interface{}); ok && len(raw) > 0 {
+		fields := make([]interface{}, 0, len(raw))
+		for _, item := range raw {
+			field := item.(map[string]interface{})
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", field["title"], field["value"]),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+
+	blocks = append(blocks, map[string]interface{}{
+		"type": "context",
+		"elements": []interface{}{
+			map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("由 AI 助手代用户 %s 发送", userID)},
+		},
+	})
+	return fallback, blocks
+}
+
+// normalizeSlackChannel 规范化频道名称：去掉开头的 # 并转为小写
+func normalizeSlackChannel(channel string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(channel), "#"))
+}
+
+// firstString 返回第一个元素，切片为空时返回空字符串
+func firstString(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSlackPostTool(t *testing.T) {
+	var posted, hooked map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		if posted["channel"] == "#archived" {
+			w.Write([]byte(`{"ok":false,"error":"is_archived"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1714550400.000100"}`))
+	})
+	mux.HandleFunc("POST /hooks/alerts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&hooked))
+		w.Write([]byte("ok"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	userID := "7"
+	tool := NewSlackPostTool(SlackPostConfig{
+		BotToken: "xoxb-test",
+		Channels: []string{"#Research", "#archived", "#alerts"},
+		Webhooks: map[string]string{"alerts": server.URL + "/hooks/alerts"},
+	}, func(ctx context.Context) string { return userID }, zap.NewNop())
+	tool.apiURL = server.URL + "/api"
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{
+		"title":  "AAPL 财报摘要",
+		"text":   "营收 *同比 +6%*",
+		"fields": []interface{}{map[string]interface{}{"title": "EPS", "value": "1.53"}},
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	result := resp.Content[0].Data.(*dto.SlackPostResult)
+	assert.Equal(t, &dto.SlackPostResult{Channel: "research", Via: "bot", Timestamp: "1714550400.000100"}, result)
+	assert.Equal(t, "#Research", posted["channel"])
+	assert.Equal(t, "ℹ️ AAPL 财报摘要\n营收 *同比 +6%*", posted["text"])
+	blocks := posted["blocks"].([]interface{})
+	require.Len(t, blocks, 4)
+	assert.Equal(t, "header", blocks[0].(map[string]interface{})["type"])
+	assert.Equal(t, "*EPS*\n1.53", blocks[2].(map[string]interface{})["fields"].([]interface{})[0].(map[string]interface{})["text"])
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"channel": "#ALERTS", "text": "VIX 突破 30", "level": "critical"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, "💬 已发送到 Slack 频道 #alerts", resp.Content[0].Text)
+	assert.Equal(t, "webhook", resp.Content[0].Data.(*dto.SlackPostResult).Via)
+	assert.Equal(t, "🚨 VIX 突破 30", hooked["text"])
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"channel not allowed", map[string]interface{}{"channel": "general", "text": "hi"}, "不在允许列表中"},
+		{"slack error", map[string]interface{}{"channel": "archived", "text": "hi"}, "is_archived"},
+		{"missing text", map[string]interface{}{"channel": "research"}, "text"},
+		{"unknown level", map[string]interface{}{"text": "hi", "level": "debug"}, "level"},
+		{"field without title", map[string]interface{}{"text": "hi", "fields": []interface{}{map[string]interface{}{"value": "1"}}}, "fields[0].title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	userID = ""
+	resp, err = tool.Execute(ctx, map[string]interface{}{"text": "hi"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "登录")
+}
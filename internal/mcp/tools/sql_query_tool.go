@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/sqlquery"
+)
+
+// SQLQueryToolName SQL 分析工具注册名称
+const SQLQueryToolName = "sql_query"
+
+// sql_query 工具支持的操作
+const (
+	sqlActionDatabases = "databases"
+	sqlActionSchema    = "schema"
+	sqlActionQuery     = "query"
+)
+
+// maxSQLQueryLength 查询语句的最大长度
+const maxSQLQueryLength = 10000
+
+// maxSQLTextRows 文本报告中展示的最大行数，完整结果在 data 中
+const maxSQLTextRows = 50
+
+// SQLQueryTool 只读 SQL 分析工具，对配置的外部业务数据库执行查询并提供表结构供模型编写 SQL
+type SQLQueryTool struct {
+	*mcp.BaseTool
+	manager *sqlquery.Manager
+	userID  UserIDFunc
+}
+
+// NewSQLQueryTool 创建 SQL 分析工具
+func NewSQLQueryTool(manager *sqlquery.Manager, userID UserIDFunc) *SQLQueryTool {
+	var databases []string
+	for _, db := range manager.Databases() {
+		entry := fmt.Sprintf("%s (%s)", db.Name, db.Driver)
+		if db.Description != "" {
+			entry = fmt.Sprintf("%s (%s): %s", db.Name, db.Driver, db.Description)
+		}
+		databases = append(databases, entry)
+	}
+
+	return &SQLQueryTool{
+		BaseTool: &mcp.BaseTool{
+			Name: SQLQueryToolName,
+			Description: "对业务数据库执行只读 SQL 分析查询。先用 action='schema' 查看表结构，再用 action='query' 执行单条 SELECT 语句；" +
+				"标记为 redacted 的列不能在查询中引用。可用的数据库: " + strings.Join(databases, "; "),
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"description": "操作: 'databases' (列出数据库), 'schema' (查看表结构), 'query' (执行查询，默认)",
+						"enum":        []string{sqlActionDatabases, sqlActionSchema, sqlActionQuery},
+						"default":     sqlActionQuery,
+					},
+					"database": map[string]interface{}{
+						"type":        "string",
+						"description": "数据库名称，schema 和 query 操作必需",
+						"enum":        manager.Names(),
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "单条 SELECT 或 WITH 查询语句，不支持写入、DDL 和多条语句",
+						"maxLength":   maxSQLQueryLength,
+					},
+					"table": map[string]interface{}{
+						"type":        "string",
+						"description": "只查看该表的结构，默认返回所有表",
+					},
+					"max_rows": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("最多返回的行数 (1-%d)", manager.MaxRows()),
+						"minimum":     1,
+						"maximum":     manager.MaxRows(),
+						"default":     manager.MaxRows(),
+					},
+					"format": formatProperty(),
+				},
+			},
+		},
+		manager: manager,
+		userID:  userID,
+	}
+}
+
+// Execute 执行 SQL 分析操作
+func (sq *SQLQueryTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := sq.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	if sq.userID(ctx) == "" {
+		return textErrorResponse("SQL 分析需要登录后使用"), nil
+	}
+
+	database := stringArg(args, "database", "")
+	var result interface{}
+	var text string
+	switch stringArg(args, "action", sqlActionQuery) {
+	case sqlActionDatabases:
+		databases := make([]dto.SQLDatabase, 0)
+		for _, db := range sq.manager.Databases() {
+			databases = append(databases, dto.SQLDatabase{Name: db.Name, Driver: db.Driver, Description: db.Description})
+		}
+		result, text = databases, formatSQLDatabases(databases)
+	case sqlActionSchema:
+		columns, err := sq.manager.Schema(ctx, database, stringArg(args, "table", ""))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("获取表结构失败: %v", err)), nil
+		}
+		schema := sq.schemaResult(database, columns)
+		result, text = schema, formatSQLSchema(schema)
+	default:
+		query := strings.TrimSpace(args["query"].(string))
+		rows, err := sq.manager.Query(ctx, database, query, intArg(args, "max_rows", 0))
+		if err != nil {
+			return textErrorResponse(sqlErrorMessage(err)), nil
+		}
+		queryResult := &dto.SQLQueryResult{
+			Database:   database,
+			Columns:    rows.Columns,
+			Rows:       rows.Rows,
+			RowCount:   len(rows.Rows),
+			Truncated:  rows.Truncated,
+			Redacted:   rows.Redacted,
+			DurationMs: rows.Duration.Milliseconds(),
+		}
+		result, text = queryResult, formatSQLQueryResult(queryResult)
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (sq *SQLQueryTool) Validate(args map[string]interface{}) error {
+	action := sqlActionQuery
+	if value, exists := args["action"]; exists {
+		str, ok := value.(string)
+		if !ok || !containsString([]string{sqlActionDatabases, sqlActionSchema, sqlActionQuery}, str) {
+			return fmt.Errorf("action 必须是以下值之一: %v", []string{sqlActionDatabases, sqlActionSchema, sqlActionQuery})
+		}
+		action = str
+	}
+
+	if action != sqlActionDatabases {
+		database, ok := args["database"].(string)
+		if !ok || database == "" {
+			return fmt.Errorf("database 参数是必需的且必须是字符串")
+		}
+		if !containsString(sq.manager.Names(), database) {
+			return fmt.Errorf("未知的数据库 %s，可用: %v", database, sq.manager.Names())
+		}
+	}
+
+	if action == sqlActionQuery {
+		query, ok := args["query"].(string)
+		if !ok || strings.TrimSpace(query) == "" {
+			return fmt.Errorf("query 参数是必需的且必须是字符串")
+		}
+		if len(query) > maxSQLQueryLength {
+			return fmt.Errorf("query 不能超过 %d 个字符", maxSQLQueryLength)
+		}
+	}
+
+	if value, exists := args["table"]; exists {
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("table 必须是字符串")
+		}
+	}
+	if _, exists := args["max_rows"]; exists {
+		if maxRows := intArg(args, "max_rows", 0); maxRows < 1 || maxRows > sq.manager.MaxRows() {
+			return fmt.Errorf("max_rows 必须在 1 到 %d 之间", sq.manager.MaxRows())
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// schemaResult 将列按表分组
+func (sq *SQLQueryTool) schemaResult(database string, columns []sqlquery.Column) *dto.SQLSchemaResult {
+	result := &dto.SQLSchemaResult{Database: database, Tables: []dto.SQLTable{}}
+	for _, db := range sq.manager.Databases() {
+		if db.Name == database {
+			result.Driver, result.Description = db.Driver, db.Description
+		}
+	}
+	for _, column := range columns {
+		if n := len(result.Tables); n == 0 || result.Tables[n-1].Name != column.Table {
+			result.Tables = append(result.Tables, dto.SQLTable{Name: column.Table})
+		}
+		table := &result.Tables[len(result.Tables)-1]
+		table.Columns = append(table.Columns, dto.SQLColumn{Name: column.Name, Type: column.Type, Redacted: column.Redacted})
+	}
+	return result
+}
+
+// sqlErrorMessage 将查询错误转换为面向用户的消息
+func sqlErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, sqlquery.ErrRedacted):
+		return fmt.Sprintf("查询被拒绝: 引用了脱敏列 (%v)", err)
+	case errors.Is(err, sqlquery.ErrNotReadOnly):
+		return fmt.Sprintf("查询被拒绝: 只允许单条只读 SELECT 语句 (%v)", err)
+	}
+	return fmt.Sprintf("查询失败: %v", err)
+}
+
+// formatSQLDatabases 格式化数据库列表
+func formatSQLDatabases(databases []dto.SQLDatabase) string {
+	var sb strings.Builder
+	sb.WriteString("🗄️ 可查询的数据库\n")
+	for _, db := range databases {
+		sb.WriteString(fmt.Sprintf("- %s (%s)", db.Name, db.Driver))
+		if db.Description != "" {
+			sb.WriteString(": " + db.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatSQLSchema 格式化表结构
+func formatSQLSchema(schema *dto.SQLSchemaResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🗄️ %s (%s)\n", schema.Database, schema.Driver))
+	if len(schema.Tables) == 0 {
+		sb.WriteString("(没有表)")
+		return sb.String()
+	}
+	for _, table := range schema.Tables {
+		columns := make([]string, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			entry := column.Name + " " + column.Type
+			if column.Redacted {
+				entry += " [redacted]"
+			}
+			columns = append(columns, entry)
+		}
+		sb.WriteString(fmt.Sprintf("- %s(%s)\n", table.Name, strings.Join(columns, ", ")))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatSQLQueryResult 将查询结果格式化为 Markdown 表格
+func formatSQLQueryResult(result *dto.SQLQueryResult) string {
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(escapeSQLCells(result.Columns), " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(result.Columns)) + "\n")
+	for i, row := range result.Rows {
+		if i == maxSQLTextRows {
+			break
+		}
+		cells := make([]string, len(row))
+		for j, value := range row {
+			if value == nil {
+				cells[j] = "NULL"
+			} else {
+				cells[j] = fmt.Sprint(value)
+			}
+		}
+		sb.WriteString("| " + strings.Join(escapeSQLCells(cells), " | ") + " |\n")
+	}
+
+	summary := fmt.Sprintf("%d 行，耗时 %dms", result.RowCount, result.DurationMs)
+	if result.RowCount > maxSQLTextRows {
+		summary += fmt.Sprintf("，仅展示前 %d 行", maxSQLTextRows)
+	}
+	if result.Truncated {
+		summary += "，结果超过行数上限已截断"
+	}
+	if len(result.Redacted) > 0 {
+		summary += "，已脱敏: " + strings.Join(result.Redacted, ", ")
+	}
+	sb.WriteString("\n" + summary)
+	return sb.String()
+}
+
+// escapeSQLCells 转义 Markdown 表格中的竖线和换行
+func escapeSQLCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	replacer := strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+	for i, cell := range cells {
+		escaped[i] = replacer.Replace(cell)
+	}
+	return escaped
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/sqlquery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLQueryTool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, region TEXT, amount REAL, email TEXT);
+		INSERT INTO orders (region, amount, email) VALUES ('EU', 120.5, 'a@x.com'), ('US', 80, 'b@x.com'), ('EU', 40, NULL);`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	manager, err := sqlquery.New(sqlquery.Config{
+		Sources: []sqlquery.Source{{Name: "sales", Driver: "sqlite3", DSN: path, Description: "Orders", Redact: []string{"email"}}},
+		MaxRows: 10,
+	})
+	require.NoError(t, err)
+	defer manager.Close()
+
+	tool := NewSQLQueryTool(manager, func(ctx context.Context) string { return "1" })
+	ctx := context.Background()
+
+	resp, err := tool.Execute(ctx, map[string]interface{}{
+		"database": "sales",
+		"query":    "SELECT region, SUM(amount) AS total FROM orders GROUP BY region ORDER BY region",
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Contains(t, resp.Content[0].Text, "| region | total |\n| --- | --- |\n| EU | 160.5 |\n| US | 80 |\n")
+	result := resp.Content[0].Data.(*dto.SQLQueryResult)
+	assert.Equal(t, 2, result.RowCount)
+	assert.False(t, result.Truncated)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "schema", "database": "sales", "format": "json"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"database":"sales","driver":"sqlite3","description":"Orders","tables":[{"name":"orders","columns":[`+
+		`{"name":"id","type":"INTEGER"},{"name":"region","type":"TEXT"},{"name":"amount","type":"REAL"},{"name":"email","type":"TEXT","redacted":true}]}]}`,
+		resp.Content[0].Text)
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"action": "databases"})
+	require.NoError(t, err)
+	assert.Equal(t, "🗄️ 可查询的数据库\n- sales (sqlite3): Orders", resp.Content[0].Text)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		message string
+	}{
+		{"write", map[string]interface{}{"database": "sales", "query": "DELETE FROM orders"}, "只允许单条只读"},
+		{"redacted column", map[string]interface{}{"database": "sales", "query": "SELECT email FROM orders"}, "脱敏列"},
+		{"unknown database", map[string]interface{}{"database": "hr", "query": "SELECT 1"}, "未知的数据库"},
+		{"missing query", map[string]interface{}{"database": "sales"}, "query 参数是必需的"},
+		{"max rows above limit", map[string]interface{}{"database": "sales", "query": "SELECT 1", "max_rows": 11}, "max_rows"},
+		{"sql error", map[string]interface{}{"database": "sales", "query": "SELECT nope FROM orders"}, "查询失败"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tool.Execute(ctx, tt.args)
+			require.NoError(t, err)
+			assert.True(t, resp.IsError)
+			assert.Contains(t, resp.Content[0].Text, tt.message)
+		})
+	}
+
+	anonymous := NewSQLQueryTool(manager, func(ctx context.Context) string { return "" })
+	resp, err = anonymous.Execute(ctx, map[string]interface{}{"action": "databases"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+}
@@ -3,22 +3,24 @@ package tools
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"math"
 	"strings"
 	"time"
 
 	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
 	"go-springAi/internal/mcp"
 )
 
 // StockAdviceTool 股票投资建议工具
 type StockAdviceTool struct {
 	*mcp.BaseTool
-	yahooTool *YahooFinanceTool
+	yahooTool   *YahooFinanceTool
+	i18nManager *i18n.Manager
 }
 
 // NewStockAdviceTool 创建新的股票投资建议工具
-func NewStockAdviceTool() *StockAdviceTool {
+func NewStockAdviceTool(i18nManager *i18n.Manager) *StockAdviceTool {
 	return &StockAdviceTool{
 		BaseTool: &mcp.BaseTool{
 			Name:        "股票投资建议",
@@ -47,11 +49,14 @@ func NewStockAdviceTool() *StockAdviceTool {
 						"description": "投资金额 (美元)",
 						"minimum":     100,
 					},
+					"language": languageProperty(i18nManager),
+					"format":   formatProperty(),
 				},
 				"required": []string{"symbol"},
 			},
 		},
-		yahooTool: NewYahooFinanceTool(),
+		yahooTool:   NewYahooFinanceTool(),
+		i18nManager: i18nManager,
 	}
 }
 
@@ -81,7 +86,7 @@ func (sa *StockAdviceTool) Execute(ctx context.Context, args map[string]interfac
 	}
 
 	// 获取股票基础数据
-	quoteArgs := map[string]interface{}{"symbol": symbol}
+	quoteArgs := map[string]interface{}{"symbol": symbol, "action": "quote"}
 	quoteResp, err := sa.yahooTool.Execute(ctx, quoteArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stock quote: %v", err)
@@ -100,13 +105,19 @@ func (sa *StockAdviceTool) Execute(ctx context.Context, args map[string]interfac
 		"action": "history",
 		"period": "3mo",
 	}
-	historyResp, err := sa.yahooTool.Execute(ctx, historyArgs)
-	if err != nil {
+	if _, err := sa.yahooTool.Execute(ctx, historyArgs); err != nil {
 		return nil, fmt.Errorf("failed to get stock history: %v", err)
 	}
 
+	quote := quoteFromResponse(quoteResp)
+	profile := profileFromResponse(infoResp)
+	if wantsJSON(args) {
+		return jsonResponse(sa.buildAdviceReport(symbol, quote, profile, horizon, riskTolerance, investmentAmount)), nil
+	}
+
 	// 生成投资建议
-	advice := sa.generateInvestmentAdvice(symbol, quoteResp, infoResp, historyResp, horizon, riskTolerance, investmentAmount)
+	t := newToolTranslator(ctx, sa.i18nManager, args)
+	advice := sa.generateInvestmentAdvice(t, symbol, quote, profile, horizon, riskTolerance, investmentAmount)
 
 	return &dto.MCPExecuteResponse{
 		Content: []dto.MCPContent{
@@ -167,62 +178,115 @@ func (sa *StockAdviceTool) Validate(args map[string]interface{}) error {
 		}
 	}
 
-	return nil
+	return validateOutputFormat(args)
+}
+
+// buildAdviceReport 构建结构化投资建议，评级与文本报告使用相同的规则
+func (sa *StockAdviceTool) buildAdviceReport(symbol string, quote *dto.StockQuote, profile *dto.CompanyProfile, horizon, riskTolerance string, investmentAmount float64) *dto.StockAdviceReport {
+	var currentPrice, pe float64
+	var volume int64
+	if quote != nil {
+		currentPrice = quote.Price
+		volume = quote.Volume
+	}
+	if profile != nil {
+		pe = profile.PE
+	}
+	changePercent := quoteChangePercent(quote)
+	score := investmentScore(changePercent, pe, horizon)
+	level := investmentRatingLevel(score)
+
+	report := &dto.StockAdviceReport{
+		Symbol:               symbol,
+		InvestmentHorizon:    horizon,
+		RiskTolerance:        riskTolerance,
+		Quote:                quote,
+		Profile:              profile,
+		ChangePercent:        changePercent,
+		Score:                score,
+		Overall:              level.overall,
+		BuySignal:            level.buySignal,
+		RiskLevel:            level.riskLevel,
+		HorizonOutlook:       adviceOutlook(horizonThresholds, horizon, score),
+		RiskToleranceOutlook: adviceOutlook(riskToleranceThresholds, riskTolerance, score),
+		Warnings:             adviceWarnings(changePercent, volume, profile),
+		Action:               adviceAction(score),
+	}
+	if investmentAmount > 0 && currentPrice > 0 {
+		shares := int(investmentAmount / currentPrice)
+		report.Position = &dto.PositionAdvice{
+			InvestmentAmount: investmentAmount,
+			Shares:           shares,
+			ActualAmount:     float64(shares) * currentPrice,
+		}
+	}
+	return report
 }
 
 // 生成投资建议
-func (sa *StockAdviceTool) generateInvestmentAdvice(symbol string, quoteResp, infoResp, historyResp *dto.MCPExecuteResponse, horizon, riskTolerance string, investmentAmount float64) string {
-	advice := fmt.Sprintf("📊 %s 股票投资建议报告\n", symbol)
-	advice += fmt.Sprintf("生成时间: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+func (sa *StockAdviceTool) generateInvestmentAdvice(t *toolTranslator, symbol string, quote *dto.StockQuote, profile *dto.CompanyProfile, horizon, riskTolerance string, investmentAmount float64) string {
+	advice := t.T("stock.advice.title", map[string]interface{}{"Symbol": symbol}) + "\n"
+	advice += t.T("stock.common.generated_at", map[string]interface{}{"Time": time.Now().Format("2006-01-02 15:04:05")}) + "\n\n"
 
 	// 提取关键数据
-	quoteData := sa.extractResponseText(quoteResp)
-	infoData := sa.extractResponseText(infoResp)
-	historyData := sa.extractResponseText(historyResp)
-
-	currentPrice := sa.extractPrice(quoteData, "当前价格")
-	changePercent := sa.extractChangePercent(quoteData)
-	volume := sa.extractVolumeFromText(quoteData)
-	marketCap := sa.extractInfo(infoData, "市值")
-	pe := sa.extractInfo(infoData, "市盈率")
-	sector := sa.extractInfo(infoData, "行业")
-
-	// 从历史数据中提取趋势信息
-	_ = historyData // 使用历史数据进行趋势分析（简化处理）
+	var currentPrice float64
+	var volume int64
+	currency := ""
+	if quote != nil {
+		currentPrice = quote.Price
+		volume = quote.Volume
+		currency = quote.Currency
+	}
+	changePercent := quoteChangePercent(quote)
+
+	marketCap, pe, sector := "N/A", "N/A", "N/A"
+	var peValue float64
+	if profile != nil {
+		if profile.MarketCap > 0 {
+			marketCap = "$" + formatLargeNumber(profile.MarketCap)
+		}
+		if profile.PE > 0 {
+			peValue = profile.PE
+			pe = fmt.Sprintf("%.2f", profile.PE)
+		}
+		if profile.Sector != "" {
+			sector = profile.Sector
+		}
+	}
 
 	// 基本信息
-	advice += "📈 基本信息:\n"
-	advice += fmt.Sprintf("• 当前价格: $%.2f\n", currentPrice)
-	advice += fmt.Sprintf("• 涨跌幅: %.2f%%\n", changePercent)
-	advice += fmt.Sprintf("• 市值: %s\n", marketCap)
-	advice += fmt.Sprintf("• 市盈率: %s\n", pe)
-	advice += fmt.Sprintf("• 行业: %s\n\n", sector)
+	advice += t.T("stock.advice.section.basic", nil) + "\n"
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.current_price", nil), formatMoney(currentPrice, currency))
+	advice += fmt.Sprintf("• %s: %.2f%%\n", t.T("stock.field.change_percent", nil), changePercent)
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.market_cap", nil), marketCap)
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.pe", nil), pe)
+	advice += fmt.Sprintf("• %s: %s\n\n", t.T("stock.field.sector", nil), sector)
 
 	// 投资建议评级
-	advice += "🎯 投资建议评级:\n"
-	rating := sa.calculateInvestmentRating(currentPrice, changePercent, pe, horizon, riskTolerance)
-	advice += fmt.Sprintf("• 综合评级: %s\n", rating.Overall)
-	advice += fmt.Sprintf("• 买入信号: %s\n", rating.BuySignal)
-	advice += fmt.Sprintf("• 风险等级: %s\n\n", rating.RiskLevel)
+	rating := sa.calculateInvestmentRating(t, changePercent, peValue, horizon)
+	advice += t.T("stock.advice.section.rating", nil) + "\n"
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.overall_rating", nil), rating.Overall)
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.buy_signal", nil), rating.BuySignal)
+	advice += fmt.Sprintf("• %s: %s\n\n", t.T("stock.field.risk_level", nil), rating.RiskLevel)
 
 	// 基于投资期限的建议
-	advice += sa.generateHorizonSpecificAdvice(symbol, horizon, rating)
+	advice += sa.generateHorizonSpecificAdvice(t, horizon, rating)
 
 	// 基于风险承受能力的建议
-	advice += sa.generateRiskBasedAdvice(symbol, riskTolerance, rating)
+	advice += sa.generateRiskBasedAdvice(t, riskTolerance, rating)
 
 	// 仓位建议
-	if investmentAmount > 0 {
-		advice += sa.generatePositionAdvice(symbol, currentPrice, investmentAmount, riskTolerance)
+	if investmentAmount > 0 && currentPrice > 0 {
+		advice += sa.generatePositionAdvice(t, currentPrice, currency, investmentAmount, riskTolerance)
 	}
 
 	// 风险提示
-	advice += sa.generateRiskWarnings(symbol, changePercent, volume, sector)
+	advice += sa.generateRiskWarnings(t, changePercent, volume, profile)
 
 	// 操作建议
-	advice += sa.generateActionPlan(symbol, rating, horizon)
+	advice += sa.generateActionPlan(t, rating)
 
-	advice += "\n⚠️ 重要声明: 本建议仅供参考，不构成投资建议。投资有风险，请根据自身情况谨慎决策。"
+	advice += "\n" + t.T("stock.advice.disclaimer", nil)
 
 	return advice
 }
@@ -235,8 +299,37 @@ type InvestmentRating struct {
 	Score     int
 }
 
+// investmentRatingLevelDef 评级分档定义
+type investmentRatingLevelDef struct {
+	minScore  int
+	overall   string
+	buySignal string
+	riskLevel string
+}
+
+// investmentRatingLevels 评级分档（按最低分数从高到低排列）对应的消息ID后缀
+var investmentRatingLevels = []investmentRatingLevelDef{
+	{minScore: 70, overall: "strong_recommend", buySignal: "strong_buy", riskLevel: "low"},
+	{minScore: 60, overall: "recommend", buySignal: "buy", riskLevel: "medium_low"},
+	{minScore: 50, overall: "neutral", buySignal: "watch", riskLevel: "medium"},
+	{minScore: 40, overall: "cautious", buySignal: "cautious_buy", riskLevel: "medium_high"},
+	{minScore: math.MinInt, overall: "not_recommended", buySignal: "avoid", riskLevel: "high"},
+}
+
 // 计算投资评级
-func (sa *StockAdviceTool) calculateInvestmentRating(price, changePercent float64, pe, horizon, riskTolerance string) *InvestmentRating {
+func (sa *StockAdviceTool) calculateInvestmentRating(t *toolTranslator, changePercent, pe float64, horizon string) *InvestmentRating {
+	score := investmentScore(changePercent, pe, horizon)
+	level := investmentRatingLevel(score)
+	return &InvestmentRating{
+		Overall:   t.T("stock.advice.overall."+level.overall, nil),
+		BuySignal: t.T("stock.advice.signal."+level.buySignal, nil),
+		RiskLevel: t.T("stock.risk_level."+level.riskLevel, nil),
+		Score:     score,
+	}
+}
+
+// investmentScore 根据涨跌幅、市盈率和投资期限计算评分
+func investmentScore(changePercent, pe float64, horizon string) int {
 	score := 50 // 基础分数
 
 	// 基于价格变化调整
@@ -253,15 +346,13 @@ func (sa *StockAdviceTool) calculateInvestmentRating(price, changePercent float6
 	}
 
 	// 基于PE调整
-	if pe != "N/A" && pe != "" {
-		if peValue, err := strconv.ParseFloat(pe, 64); err == nil {
-			if peValue < 15 {
-				score += 10 // 低估值
-			} else if peValue < 25 {
-				score += 5 // 合理估值
-			} else if peValue > 40 {
-				score -= 10 // 高估值
-			}
+	if pe > 0 {
+		if pe < 15 {
+			score += 10 // 低估值
+		} else if pe < 25 {
+			score += 5 // 合理估值
+		} else if pe > 40 {
+			score -= 10 // 高估值
 		}
 	}
 
@@ -273,285 +364,155 @@ func (sa *StockAdviceTool) calculateInvestmentRating(price, changePercent float6
 		score -= 5 // 短期投资风险更高
 	}
 
-	// 确定评级
-	var overall, buySignal, riskLevel string
+	return score
+}
 
-	if score >= 70 {
-		overall = "强烈推荐"
-		buySignal = "强烈买入"
-		riskLevel = "低风险"
-	} else if score >= 60 {
-		overall = "推荐"
-		buySignal = "买入"
-		riskLevel = "中低风险"
-	} else if score >= 50 {
-		overall = "中性"
-		buySignal = "观望"
-		riskLevel = "中等风险"
-	} else if score >= 40 {
-		overall = "谨慎"
-		buySignal = "谨慎买入"
-		riskLevel = "中高风险"
-	} else {
-		overall = "不推荐"
-		buySignal = "避免买入"
-		riskLevel = "高风险"
+// investmentRatingLevel 返回评分所在的评级分档
+func investmentRatingLevel(score int) investmentRatingLevelDef {
+	for _, level := range investmentRatingLevels {
+		if score >= level.minScore {
+			return level
+		}
 	}
+	return investmentRatingLevels[len(investmentRatingLevels)-1]
+}
 
-	return &InvestmentRating{
-		Overall:   overall,
-		BuySignal: buySignal,
-		RiskLevel: riskLevel,
-		Score:     score,
+// horizonThresholds 各投资期限给出积极展望的最低评分
+var horizonThresholds = map[string]int{"short_term": 60, "medium_term": 50, "long_term": 45}
+
+// riskToleranceThresholds 各风险承受能力给出积极展望的最低评分
+var riskToleranceThresholds = map[string]int{"conservative": 65, "moderate": 55, "aggressive": 45}
+
+// adviceOutlook 根据阈值返回 positive 或 negative，未知选项返回空字符串
+func adviceOutlook(thresholds map[string]int, option string, score int) string {
+	threshold, ok := thresholds[option]
+	if !ok {
+		return ""
 	}
+	if score >= threshold {
+		return "positive"
+	}
+	return "negative"
 }
 
 // 生成基于投资期限的建议
-func (sa *StockAdviceTool) generateHorizonSpecificAdvice(symbol, horizon string, rating *InvestmentRating) string {
-	advice := "⏰ 投资期限建议:\n"
-
-	switch horizon {
-	case "short_term":
-		advice += "• 短期投资 (1-6个月):\n"
-		if rating.Score >= 60 {
-			advice += "  - 适合短期交易，关注技术指标\n"
-			advice += "  - 设置止损位在5-8%\n"
-			advice += "  - 密切关注市场情绪变化\n"
-		} else {
-			advice += "  - 短期风险较高，建议观望\n"
-			advice += "  - 如需交易，严格控制仓位\n"
-		}
-	case "medium_term":
-		advice += "• 中期投资 (6个月-2年):\n"
-		if rating.Score >= 50 {
-			advice += "  - 适合中期持有，关注基本面\n"
-			advice += "  - 可分批建仓，降低成本\n"
-			advice += "  - 关注季度财报表现\n"
-		} else {
-			advice += "  - 等待更好的入场时机\n"
-			advice += "  - 关注行业趋势变化\n"
-		}
-	case "long_term":
-		advice += "• 长期投资 (2年以上):\n"
-		if rating.Score >= 45 {
-			advice += "  - 适合长期价值投资\n"
-			advice += "  - 关注公司发展战略\n"
-			advice += "  - 可忽略短期波动\n"
-		} else {
-			advice += "  - 需要深入研究公司基本面\n"
-			advice += "  - 考虑行业长期前景\n"
-		}
+func (sa *StockAdviceTool) generateHorizonSpecificAdvice(t *toolTranslator, horizon string, rating *InvestmentRating) string {
+	outlook := adviceOutlook(horizonThresholds, horizon, rating.Score)
+	if outlook == "" {
+		return ""
 	}
 
+	advice := t.T("stock.advice.section.horizon", nil) + "\n"
+	advice += t.T("stock.advice.horizon."+horizon+".title", nil) + "\n"
+	advice += t.T("stock.advice.horizon."+horizon+"."+outlook, nil) + "\n"
+
 	return advice + "\n"
 }
 
 // 生成基于风险承受能力的建议
-func (sa *StockAdviceTool) generateRiskBasedAdvice(symbol, riskTolerance string, rating *InvestmentRating) string {
-	advice := "🎲 风险承受能力建议:\n"
-
-	switch riskTolerance {
-	case "conservative":
-		advice += "• 保守型投资者:\n"
-		if rating.Score >= 65 {
-			advice += "  - 可适量配置，不超过总资产5%\n"
-			advice += "  - 建议分散投资，降低风险\n"
-		} else {
-			advice += "  - 当前风险偏高，建议观望\n"
-			advice += "  - 考虑更稳健的投资选择\n"
-		}
-	case "moderate":
-		advice += "• 稳健型投资者:\n"
-		if rating.Score >= 55 {
-			advice += "  - 可配置10-15%的资产\n"
-			advice += "  - 结合其他资产平衡风险\n"
-		} else {
-			advice += "  - 谨慎投资，控制仓位\n"
-			advice += "  - 等待更好的投资机会\n"
-		}
-	case "aggressive":
-		advice += "• 激进型投资者:\n"
-		if rating.Score >= 45 {
-			advice += "  - 可配置20-30%的资产\n"
-			advice += "  - 可考虑杠杆操作（谨慎）\n"
-		} else {
-			advice += "  - 高风险高收益，需谨慎评估\n"
-			advice += "  - 严格设置止损策略\n"
-		}
+func (sa *StockAdviceTool) generateRiskBasedAdvice(t *toolTranslator, riskTolerance string, rating *InvestmentRating) string {
+	outlook := adviceOutlook(riskToleranceThresholds, riskTolerance, rating.Score)
+	if outlook == "" {
+		return ""
 	}
 
+	advice := t.T("stock.advice.section.risk_tolerance", nil) + "\n"
+	advice += t.T("stock.advice.risk_tolerance."+riskTolerance+".title", nil) + "\n"
+	advice += t.T("stock.advice.risk_tolerance."+riskTolerance+"."+outlook, nil) + "\n"
+
 	return advice + "\n"
 }
 
 // 生成仓位建议
-func (sa *StockAdviceTool) generatePositionAdvice(symbol string, currentPrice, investmentAmount float64, riskTolerance string) string {
-	advice := "💰 仓位建议:\n"
-	advice += fmt.Sprintf("• 投资金额: $%.2f\n", investmentAmount)
-	advice += fmt.Sprintf("• 当前股价: $%.2f\n", currentPrice)
-
+func (sa *StockAdviceTool) generatePositionAdvice(t *toolTranslator, currentPrice float64, currency string, investmentAmount float64, riskTolerance string) string {
 	shares := int(investmentAmount / currentPrice)
 	actualAmount := float64(shares) * currentPrice
 
-	advice += fmt.Sprintf("• 建议股数: %d 股\n", shares)
-	advice += fmt.Sprintf("• 实际投资: $%.2f\n", actualAmount)
+	advice := t.T("stock.advice.section.position", nil) + "\n"
+	advice += fmt.Sprintf("• %s: $%.2f\n", t.T("stock.field.investment_amount", nil), investmentAmount)
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.current_price", nil), formatMoney(currentPrice, currency))
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.suggested_shares", nil), t.T("stock.advice.shares", map[string]interface{}{"Count": shares}))
+	advice += fmt.Sprintf("• %s: %s\n", t.T("stock.field.actual_investment", nil), formatMoney(actualAmount, currency))
 
 	// 分批建仓建议
 	switch riskTolerance {
-	case "conservative":
-		advice += "• 建仓策略: 分3批建仓，每批33%\n"
-		advice += "• 时间间隔: 每周一次\n"
-	case "moderate":
-		advice += "• 建仓策略: 分2批建仓，每批50%\n"
-		advice += "• 时间间隔: 每两周一次\n"
-	case "aggressive":
-		advice += "• 建仓策略: 可一次性建仓\n"
-		advice += "• 或分2批，快速建仓\n"
+	case "conservative", "moderate", "aggressive":
+		advice += t.T("stock.advice.position."+riskTolerance, nil) + "\n"
 	}
 
 	return advice + "\n"
 }
 
-// 生成风险提示
-func (sa *StockAdviceTool) generateRiskWarnings(symbol string, changePercent, volume float64, sector string) string {
-	warnings := "⚠️ 风险提示:\n"
-
-	// 波动性风险
-	if changePercent > 10 || changePercent < -10 {
-		warnings += "• 高波动性: 股价波动较大，注意风险控制\n"
-	}
+// riskySectorKeywords 波动性较高的行业关键词（匹配 Yahoo Finance 的板块与行业名称）
+var riskySectorKeywords = []string{"Technology", "Biotechnology", "Crypto", "Solar", "Renewable"}
 
-	// 流动性风险
-	if volume < 1000000 {
-		warnings += "• 流动性风险: 成交量较低，可能影响买卖\n"
-	}
+// 生成风险提示
+func (sa *StockAdviceTool) generateRiskWarnings(t *toolTranslator, changePercent float64, volume int64, profile *dto.CompanyProfile) string {
+	warnings := t.T("stock.advice.section.warnings", nil) + "\n"
 
-	// 行业风险
-	riskySectors := []string{"科技", "生物技术", "加密货币", "新能源"}
-	for _, rs := range riskySectors {
-		if strings.Contains(sector, rs) {
-			warnings += fmt.Sprintf("• 行业风险: %s行业波动性较高\n", rs)
-			break
+	for _, warning := range adviceWarnings(changePercent, volume, profile) {
+		if warning == "sector" {
+			warnings += t.T("stock.advice.warning.sector", map[string]interface{}{"Sector": riskySector(profile)}) + "\n"
+			continue
 		}
+		warnings += t.T("stock.advice.warning."+warning, nil) + "\n"
 	}
 
 	// 通用风险
-	warnings += "• 市场风险: 受整体市场环境影响\n"
-	warnings += "• 汇率风险: 如为外币计价，需关注汇率变化\n"
-	warnings += "• 政策风险: 关注相关政策法规变化\n"
+	warnings += t.T("stock.advice.warning.general", nil) + "\n"
 
 	return warnings + "\n"
 }
 
-// 生成操作建议
-func (sa *StockAdviceTool) generateActionPlan(symbol string, rating *InvestmentRating, horizon string) string {
-	plan := "📋 操作建议:\n"
-
-	if rating.Score >= 60 {
-		plan += "• 立即行动:\n"
-		plan += "  1. 确认投资金额和风险承受能力\n"
-		plan += "  2. 设置买入价格区间\n"
-		plan += "  3. 制定止损和止盈策略\n"
-		plan += "  4. 开始分批建仓\n"
-	} else if rating.Score >= 50 {
-		plan += "• 谨慎观察:\n"
-		plan += "  1. 继续关注股价走势\n"
-		plan += "  2. 等待更好的入场时机\n"
-		plan += "  3. 关注公司最新动态\n"
-		plan += "  4. 准备资金，随时行动\n"
-	} else {
-		plan += "• 暂时观望:\n"
-		plan += "  1. 深入研究公司基本面\n"
-		plan += "  2. 关注行业发展趋势\n"
-		plan += "  3. 等待风险降低\n"
-		plan += "  4. 考虑其他投资选择\n"
+// adviceWarnings 返回触发的风险提示: volatility (波动性), liquidity (流动性), sector (行业)
+func adviceWarnings(changePercent float64, volume int64, profile *dto.CompanyProfile) []string {
+	warnings := []string{}
+	if changePercent > 10 || changePercent < -10 {
+		warnings = append(warnings, "volatility")
 	}
-
-	// 监控指标
-	plan += "\n📊 关键监控指标:\n"
-	plan += "• 股价支撑位和阻力位\n"
-	plan += "• 成交量变化\n"
-	plan += "• 财报发布时间\n"
-	plan += "• 行业新闻和政策\n"
-	plan += "• 技术指标 (RSI, MACD, 移动平均线)\n"
-
-	return plan + "\n"
-}
-
-// 辅助函数
-
-func (sa *StockAdviceTool) extractPrice(text, keyword string) float64 {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, keyword) {
-			parts := strings.Split(line, "$")
-			if len(parts) > 1 {
-				priceStr := strings.Fields(parts[1])[0]
-				if price, err := strconv.ParseFloat(priceStr, 64); err == nil {
-					return price
-				}
-			}
-		}
+	if volume < 1000000 {
+		warnings = append(warnings, "liquidity")
 	}
-	return 0
-}
-
-func (sa *StockAdviceTool) extractChangePercent(text string) float64 {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "涨跌幅") || strings.Contains(line, "变化") {
-			if strings.Contains(line, "%") {
-				parts := strings.Split(line, "%")
-				if len(parts) > 0 {
-					percentStr := strings.Fields(parts[0])
-					if len(percentStr) > 0 {
-						if percent, err := strconv.ParseFloat(percentStr[len(percentStr)-1], 64); err == nil {
-							return percent
-						}
-					}
-				}
-			}
-		}
+	if riskySector(profile) != "" {
+		warnings = append(warnings, "sector")
 	}
-	return 0
+	return warnings
 }
 
-func (sa *StockAdviceTool) extractVolumeFromText(text string) float64 {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "成交量") {
-			// 简单提取，实际应该解析具体数值
-			return 1000000 // 默认值
-		}
+// riskySector 返回波动性较高的板块（或行业）名称，不属于高风险行业时返回空字符串
+func riskySector(profile *dto.CompanyProfile) string {
+	if profile == nil {
+		return ""
 	}
-	return 0
-}
-
-func (sa *StockAdviceTool) extractInfo(text, keyword string) string {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, keyword) {
-			parts := strings.Split(line, ":")
-			if len(parts) > 1 {
-				return strings.TrimSpace(parts[1])
+	for _, keyword := range riskySectorKeywords {
+		if strings.Contains(profile.Sector, keyword) || strings.Contains(profile.Industry, keyword) {
+			if profile.Sector != "" {
+				return profile.Sector
 			}
+			return profile.Industry
 		}
 	}
-	return "N/A"
+	return ""
 }
 
-// extractResponseText 从MCPExecuteResponse中提取文本内容
-func (sa *StockAdviceTool) extractResponseText(resp *dto.MCPExecuteResponse) string {
-	if resp == nil || len(resp.Content) == 0 {
-		return ""
-	}
+// 生成操作建议
+func (sa *StockAdviceTool) generateActionPlan(t *toolTranslator, rating *InvestmentRating) string {
+	plan := t.T("stock.advice.section.action", nil) + "\n"
+	plan += t.T("stock.advice.action."+adviceAction(rating.Score), nil) + "\n"
 
-	var text strings.Builder
-	for _, content := range resp.Content {
-		if content.Type == "text" && content.Text != "" {
-			text.WriteString(content.Text)
-			text.WriteString("\n")
-		}
-	}
+	// 监控指标
+	plan += "\n" + t.T("stock.advice.section.monitor", nil) + "\n"
+	plan += t.T("stock.advice.monitor", nil) + "\n"
 
-	return text.String()
+	return plan + "\n"
+}
+
+// adviceAction 根据评分确定操作建议: act, observe 或 wait
+func adviceAction(score int) string {
+	if score >= 60 {
+		return "act"
+	} else if score >= 50 {
+		return "observe"
+	}
+	return "wait"
 }
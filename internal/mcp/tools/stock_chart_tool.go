@@ -0,0 +1,379 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// StockChartTool 股票图表生成工具
+type StockChartTool struct {
+	*mcp.BaseTool
+	yahooTool *YahooFinanceTool
+}
+
+// NewStockChartTool 创建股票图表生成工具
+func NewStockChartTool() *StockChartTool {
+	return &StockChartTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        "stock_chart",
+			Description: "生成股票价格/成交量及技术指标图表，以图片形式返回 (PNG/SVG)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"symbol": map[string]interface{}{
+						"type":        "string",
+						"description": "股票代码 (例如: AAPL, TSLA, MSFT)",
+					},
+					"chart_type": map[string]interface{}{
+						"type":        "string",
+						"description": "图表类型: 'price' (价格与均线), 'volume' (价格与成交量), 'bollinger' (布林带), 'rsi' (RSI指标)",
+						"enum":        []string{"price", "volume", "bollinger", "rsi"},
+						"default":     "price",
+					},
+					"period": map[string]interface{}{
+						"type":        "string",
+						"description": "数据周期: '1mo', '3mo', '6mo', '1y', '2y', '5y'",
+						"enum":        []string{"1mo", "3mo", "6mo", "1y", "2y", "5y"},
+						"default":     "6mo",
+					},
+					"format": map[string]interface{}{
+						"type":        "string",
+						"description": "输出格式: 'png' 或 'svg' 图片，'json' 返回绘图使用的价格序列而不渲染图片",
+						"enum":        []string{"png", "svg", outputFormatJSON},
+						"default":     "png",
+					},
+					"width": map[string]interface{}{
+						"type":        "integer",
+						"description": "图片宽度 (像素, 320-2048)",
+						"default":     1024,
+					},
+					"height": map[string]interface{}{
+						"type":        "integer",
+						"description": "图片高度 (像素, 240-1536)",
+						"default":     512,
+					},
+				},
+				"required": []string{"symbol"},
+			},
+		},
+		yahooTool: NewYahooFinanceTool(),
+	}
+}
+
+// Execute 执行图表生成
+func (sc *StockChartTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := sc.Validate(args); err != nil {
+		return chartErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	symbol := strings.ToUpper(args["symbol"].(string))
+	chartType := stringArg(args, "chart_type", "price")
+	period := stringArg(args, "period", "6mo")
+	format := stringArg(args, "format", "png")
+	width := intArg(args, "width", 1024)
+	height := intArg(args, "height", 512)
+
+	historyResp, err := sc.yahooTool.Execute(ctx, map[string]interface{}{
+		"action":   "history",
+		"symbol":   symbol,
+		"period":   period,
+		"interval": "1d",
+	})
+	if err != nil || historyResp.IsError {
+		return chartErrorResponse(fmt.Sprintf("获取历史数据失败: %v", err)), nil
+	}
+
+	var series *dto.PriceSeries
+	for _, content := range historyResp.Content {
+		if s, ok := content.Data.(*dto.PriceSeries); ok && s != nil {
+			series = s
+			break
+		}
+	}
+	if series == nil || len(series.Closes) < 2 {
+		return chartErrorResponse(fmt.Sprintf("股票 %s 的历史数据不足，无法生成图表", symbol)), nil
+	}
+	if format == outputFormatJSON {
+		return jsonResponse(series), nil
+	}
+
+	graph := sc.buildChart(symbol, period, chartType, series)
+	graph.Width = width
+	graph.Height = height
+
+	var buf bytes.Buffer
+	mimeType := "image/png"
+	renderer := chart.PNG
+	if format == "svg" {
+		mimeType = "image/svg+xml"
+		renderer = chart.SVG
+	}
+	if err := graph.Render(renderer, &buf); err != nil {
+		return chartErrorResponse(fmt.Sprintf("渲染图表失败: %v", err)), nil
+	}
+
+	first := series.Closes[0]
+	last := series.Closes[len(series.Closes)-1]
+	summary := fmt.Sprintf("📊 %s %s 图表 (%s, %d 个交易日)\n区间: %s ~ %s\n收盘价: %.2f → %.2f (%.2f%%)",
+		symbol, chartType, period, len(series.Closes),
+		time.Unix(series.Timestamps[0], 0).Format("2006-01-02"),
+		time.Unix(series.Timestamps[len(series.Timestamps)-1], 0).Format("2006-01-02"),
+		first, last, (last-first)/first*100)
+
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type:     "image",
+				Data:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+				MimeType: mimeType,
+			},
+			{
+				Type: "text",
+				Text: summary,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (sc *StockChartTool) Validate(args map[string]interface{}) error {
+	symbol, ok := args["symbol"].(string)
+	if !ok {
+		return fmt.Errorf("symbol 参数是必需的且必须是字符串")
+	}
+	if symbol == "" {
+		return fmt.Errorf("symbol 不能为空")
+	}
+
+	enums := map[string][]string{
+		"chart_type": {"price", "volume", "bollinger", "rsi"},
+		"period":     {"1mo", "3mo", "6mo", "1y", "2y", "5y"},
+		"format":     {"png", "svg", outputFormatJSON},
+	}
+	for name, validValues := range enums {
+		value, exists := args[name]
+		if !exists {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok || !containsString(validValues, str) {
+			return fmt.Errorf("%s 必须是以下值之一: %v", name, validValues)
+		}
+	}
+
+	if width := intArg(args, "width", 1024); width < 320 || width > 2048 {
+		return fmt.Errorf("width 必须在 320 到 2048 之间")
+	}
+	if height := intArg(args, "height", 512); height < 240 || height > 1536 {
+		return fmt.Errorf("height 必须在 240 到 1536 之间")
+	}
+
+	return nil
+}
+
+// buildChart 根据图表类型构建图表
+func (sc *StockChartTool) buildChart(symbol, period, chartType string, series *dto.PriceSeries) chart.Chart {
+	xValues := make([]time.Time, len(series.Timestamps))
+	for i, ts := range series.Timestamps {
+		xValues[i] = time.Unix(ts, 0)
+	}
+
+	priceSeries := chart.TimeSeries{
+		Name:    symbol,
+		Style:   chart.Style{StrokeColor: chart.ColorBlue, StrokeWidth: 2},
+		XValues: xValues,
+		YValues: series.Closes,
+	}
+
+	graph := chart.Chart{
+		Title: fmt.Sprintf("%s (%s)", symbol, period),
+		Background: chart.Style{
+			Padding: chart.Box{Top: 40, Left: 10, Right: 10, Bottom: 10},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeValueFormatterWithFormat("2006-01-02"),
+		},
+		YAxis: chart.YAxis{
+			Name: series.Currency,
+		},
+	}
+
+	switch chartType {
+	case "volume":
+		volumes := series.Volumes
+		if len(volumes) != len(xValues) {
+			volumes = make([]float64, len(xValues))
+		}
+		graph.YAxisSecondary = chart.YAxis{Name: "Volume"}
+		graph.Series = []chart.Series{
+			chart.TimeSeries{
+				Name:    "Volume",
+				YAxis:   chart.YAxisSecondary,
+				Style:   chart.Style{StrokeColor: chart.ColorLightGray, FillColor: chart.ColorLightGray.WithAlpha(160)},
+				XValues: xValues,
+				YValues: volumes,
+			},
+			priceSeries,
+		}
+	case "bollinger":
+		graph.Series = []chart.Series{
+			&chart.BollingerBandsSeries{
+				Name:        "Bollinger (20, 2)",
+				Style:       chart.Style{StrokeColor: chart.ColorOrange.WithAlpha(160), FillColor: chart.ColorOrange.WithAlpha(32)},
+				Period:      20,
+				K:           2,
+				InnerSeries: priceSeries,
+			},
+			priceSeries,
+		}
+	case "rsi":
+		graph.Title = fmt.Sprintf("%s RSI(14) (%s)", symbol, period)
+		graph.YAxis = chart.YAxis{
+			Name:  "RSI",
+			Range: &chart.ContinuousRange{Min: 0, Max: 100},
+		}
+		graph.Series = []chart.Series{
+			chart.TimeSeries{
+				Name:    "RSI(14)",
+				Style:   chart.Style{StrokeColor: chart.ColorBlue, StrokeWidth: 2},
+				XValues: xValues,
+				YValues: calculateRSISeries(series.Closes, 14),
+			},
+			constantSeries("Overbought (70)", xValues, 70, chart.ColorRed),
+			constantSeries("Oversold (30)", xValues, 30, chart.ColorGreen),
+		}
+	default:
+		graph.Series = []chart.Series{
+			priceSeries,
+			chart.SMASeries{
+				Name:        "MA20",
+				Style:       chart.Style{StrokeColor: chart.ColorOrange, StrokeDashArray: []float64{5, 5}},
+				Period:      20,
+				InnerSeries: priceSeries,
+			},
+			chart.SMASeries{
+				Name:        "MA50",
+				Style:       chart.Style{StrokeColor: chart.ColorRed, StrokeDashArray: []float64{5, 5}},
+				Period:      50,
+				InnerSeries: priceSeries,
+			},
+		}
+	}
+
+	graph.Elements = []chart.Renderable{chart.Legend(&graph)}
+	return graph
+}
+
+// calculateRSISeries 计算RSI序列（Wilder平滑），数据不足的位置填充50
+func calculateRSISeries(closes []float64, period int) []float64 {
+	rsi := make([]float64, len(closes))
+	for i := range rsi {
+		rsi[i] = 50
+	}
+	if len(closes) <= period {
+		return rsi
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			avgGain += change
+		} else {
+			avgLoss -= change
+		}
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	for i := period; i < len(closes); i++ {
+		if i > period {
+			change := closes[i] - closes[i-1]
+			gain, loss := 0.0, 0.0
+			if change > 0 {
+				gain = change
+			} else {
+				loss = -change
+			}
+			avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+			avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		}
+
+		if avgLoss == 0 {
+			rsi[i] = 100
+		} else {
+			rsi[i] = 100 - 100/(1+avgGain/avgLoss)
+		}
+	}
+
+	return rsi
+}
+
+// constantSeries 创建水平参考线
+func constantSeries(name string, xValues []time.Time, value float64, color drawing.Color) chart.TimeSeries {
+	yValues := make([]float64, len(xValues))
+	for i := range yValues {
+		yValues[i] = value
+	}
+	return chart.TimeSeries{
+		Name:    name,
+		Style:   chart.Style{StrokeColor: color, StrokeDashArray: []float64{4, 4}},
+		XValues: xValues,
+		YValues: yValues,
+	}
+}
+
+// chartErrorResponse 构建图表工具错误响应
+func chartErrorResponse(message string) *dto.MCPExecuteResponse {
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: message,
+			},
+		},
+		IsError: true,
+	}
+}
+
+// stringArg 读取字符串参数
+func stringArg(args map[string]interface{}, name, defaultValue string) string {
+	if value, ok := args[name].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// intArg 读取整数参数（兼容JSON解码后的float64）
+func intArg(args map[string]interface{}, name string, defaultValue int) int {
+	switch value := args[name].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	}
+	return defaultValue
+}
+
+// containsString 判断切片是否包含字符串
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockChartToolRendersImages(t *testing.T) {
+	chartTool := NewStockChartTool()
+	chartTool.yahooTool.httpClient.Transport = stubYahoo(t)
+
+	tests := []struct {
+		chartType string
+		format    string
+		mimeType  string
+	}{
+		{chartType: "price", format: "png", mimeType: "image/png"},
+		{chartType: "volume", format: "png", mimeType: "image/png"},
+		{chartType: "bollinger", format: "svg", mimeType: "image/svg+xml"},
+		{chartType: "rsi", format: "svg", mimeType: "image/svg+xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.chartType+"_"+tt.format, func(t *testing.T) {
+			resp, err := chartTool.Execute(context.Background(), map[string]interface{}{
+				"symbol":     "aapl",
+				"chart_type": tt.chartType,
+				"format":     tt.format,
+				"width":      float64(640),
+				"height":     float64(320),
+			})
+			require.NoError(t, err)
+			require.False(t, resp.IsError, resp.Content[0].Text)
+			require.Len(t, resp.Content, 2)
+
+			image := resp.Content[0]
+			assert.Equal(t, "image", image.Type)
+			assert.Equal(t, tt.mimeType, image.MimeType)
+			encoded, ok := image.Data.(string)
+			require.True(t, ok)
+			raw, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+			if tt.format == "png" {
+				config, err := png.DecodeConfig(bytes.NewReader(raw))
+				require.NoError(t, err)
+				assert.Equal(t, 640, config.Width)
+				assert.Equal(t, 320, config.Height)
+			} else {
+				assert.Contains(t, string(raw), "<svg")
+			}
+
+			assert.Equal(t, "text", resp.Content[1].Type)
+			assert.Contains(t, resp.Content[1].Text, "AAPL "+tt.chartType)
+			assert.Contains(t, resp.Content[1].Text, "187.25 → 189.50")
+		})
+	}
+
+	// json 格式返回绘图使用的价格序列
+	resp, err := chartTool.Execute(context.Background(), map[string]interface{}{"symbol": "AAPL", "format": "json"})
+	require.NoError(t, err)
+	series, ok := resp.Content[0].Data.(*dto.PriceSeries)
+	require.True(t, ok)
+	assert.Equal(t, []float64{187.25, 187.25, 189.5}, series.Closes)
+	assert.Equal(t, []float64{1000, 2000, 3000}, series.Volumes)
+
+	// 参数不合法时返回错误内容而不请求行情
+	resp, err = chartTool.Execute(context.Background(), map[string]interface{}{"symbol": "AAPL", "chart_type": "candles"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+}
+
+func TestCalculateRSISeries(t *testing.T) {
+	rising := []float64{1, 2, 3, 4, 5, 6}
+	assert.Equal(t, []float64{50, 50, 50, 100, 100, 100}, calculateRSISeries(rising, 3))
+
+	// 涨跌幅相同时 RSI 为 50
+	flat := calculateRSISeries([]float64{10, 11, 10, 11, 10}, 2)
+	assert.InDelta(t, 50, flat[2], 1e-9)
+	assert.Equal(t, []float64{50, 50}, calculateRSISeries([]float64{1, 2}, 14))
+}
@@ -3,22 +3,23 @@ package tools
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
 	"go-springAi/internal/mcp"
 )
 
 // StockCompareTool 股票对比工具
 type StockCompareTool struct {
 	*mcp.BaseTool
-	yahooTool *YahooFinanceTool
+	yahooTool   *YahooFinanceTool
+	i18nManager *i18n.Manager
 }
 
 // NewStockCompareTool 创建股票对比工具
-func NewStockCompareTool() *StockCompareTool {
+func NewStockCompareTool(i18nManager *i18n.Manager) *StockCompareTool {
 	return &StockCompareTool{
 		BaseTool: &mcp.BaseTool{
 			Name:        "股票对比",
@@ -47,27 +48,24 @@ func NewStockCompareTool() *StockCompareTool {
 						"enum":        []string{"1mo", "3mo", "6mo", "1y"},
 						"default":     "3mo",
 					},
+					"language": languageProperty(i18nManager),
+					"format":   formatProperty(),
 				},
 				"required": []string{"symbols"},
 			},
 		},
-		yahooTool: NewYahooFinanceTool(),
+		yahooTool:   NewYahooFinanceTool(),
+		i18nManager: i18nManager,
 	}
 }
 
 // Execute 执行股票对比
 func (sc *StockCompareTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	t := newToolTranslator(ctx, sc.i18nManager, args)
+
 	// 验证参数
 	if err := sc.Validate(args); err != nil {
-		return &dto.MCPExecuteResponse{
-			Content: []dto.MCPContent{
-				{
-					Type: "text",
-					Text: fmt.Sprintf("参数验证失败: %v", err),
-				},
-			},
-			IsError: true,
-		}, nil
+		return t.errorResponse("stock.error.validation", map[string]interface{}{"Error": err.Error()}), nil
 	}
 
 	symbolsInterface := args["symbols"].([]interface{})
@@ -91,32 +89,26 @@ func (sc *StockCompareTool) Execute(ctx context.Context, args map[string]interfa
 	for _, symbol := range symbols {
 		data, err := sc.getStockData(ctx, symbol, period)
 		if err != nil {
-			return &dto.MCPExecuteResponse{
-				Content: []dto.MCPContent{
-					{
-						Type: "text",
-						Text: fmt.Sprintf("获取股票 %s 数据失败: %v", symbol, err),
-					},
-				},
-				IsError: true,
-			}, nil
+			return t.errorResponse("stock.error.stock_data", map[string]interface{}{"Symbol": symbol, "Error": err.Error()}), nil
 		}
 		stockData[symbol] = data
 	}
 
+	if wantsJSON(args) {
+		return jsonResponse(sc.buildCompareReport(symbols, stockData, compareType, period)), nil
+	}
+
 	// 根据对比类型生成报告
 	var compareText string
 	switch compareType {
 	case "performance":
-		compareText = sc.generatePerformanceComparison(symbols, stockData, period)
+		compareText = sc.generatePerformanceComparison(t, symbols, stockData, period)
 	case "valuation":
-		compareText = sc.generateValuationComparison(symbols, stockData)
+		compareText = sc.generateValuationComparison(t, symbols, stockData)
 	case "risk":
-		compareText = sc.generateRiskComparison(symbols, stockData)
-	case "comprehensive":
-		compareText = sc.generateComprehensiveComparison(symbols, stockData, period)
+		compareText = sc.generateRiskComparison(t, symbols, stockData)
 	default:
-		compareText = sc.generateComprehensiveComparison(symbols, stockData, period)
+		compareText = sc.generateComprehensiveComparison(t, symbols, stockData, period)
 	}
 
 	return &dto.MCPExecuteResponse{
@@ -183,19 +175,51 @@ func (sc *StockCompareTool) Validate(args map[string]interface{}) error {
 		}
 	}
 
-	return nil
+	return validateOutputFormat(args)
+}
+
+// buildCompareReport 构建结构化对比结果，股票按请求顺序排列
+func (sc *StockCompareTool) buildCompareReport(symbols []string, stockData map[string]*StockData, compareType, period string) *dto.StockCompareReport {
+	report := &dto.StockCompareReport{
+		CompareType:    compareType,
+		Period:         period,
+		Stocks:         make([]dto.StockCompareEntry, 0, len(symbols)),
+		BestPerformer:  sc.findBestPerformer(symbols, stockData),
+		WorstPerformer: sc.findWorstPerformer(symbols, stockData),
+	}
+	for _, symbol := range symbols {
+		data := stockData[symbol]
+		report.Stocks = append(report.Stocks, dto.StockCompareEntry{
+			Symbol:         data.Symbol,
+			Currency:       data.Currency,
+			Price:          data.CurrentPrice,
+			PreviousClose:  data.PreviousClose,
+			Change:         data.Change,
+			ChangePercent:  data.ChangePercent,
+			Volume:         data.Volume,
+			MarketCap:      data.MarketCap,
+			PE:             data.PE,
+			Industry:       data.Industry,
+			Sector:         data.Sector,
+			Risk:           stockRiskLevel(data),
+			Liquidity:      liquidityLevel(data.Volume),
+			Recommendation: compareRecommendation(data),
+		})
+	}
+	return report
 }
 
-// StockData 股票数据结构
+// StockData 股票数据结构（估值指标未提供时为0）
 type StockData struct {
 	Symbol        string
+	Currency      string
 	CurrentPrice  float64
 	PreviousClose float64
 	Change        float64
 	ChangePercent float64
 	Volume        int64
-	MarketCap     string
-	PE            string
+	MarketCap     float64
+	PE            float64
 	Industry      string
 	Sector        string
 }
@@ -208,7 +232,22 @@ func (sc *StockCompareTool) getStockData(ctx context.Context, symbol, period str
 		"symbol": symbol,
 	})
 	if err != nil || quoteResp.IsError {
-		return nil, fmt.Errorf("获取报价失败: %v", err)
+		return nil, fmt.Errorf("获取报价失败: %s", responseErrorDetail(err, quoteResp))
+	}
+
+	quote := quoteFromResponse(quoteResp)
+	if quote == nil {
+		return nil, fmt.Errorf("报价响应缺少结构化数据")
+	}
+
+	data := &StockData{
+		Symbol:        symbol,
+		Currency:      quote.Currency,
+		CurrentPrice:  quote.Price,
+		PreviousClose: quote.PreviousClose,
+		Change:        quote.Price - quote.PreviousClose,
+		ChangePercent: quoteChangePercent(quote),
+		Volume:        quote.Volume,
 	}
 
 	// 获取公司信息（可选，失败时继续执行）
@@ -216,52 +255,25 @@ func (sc *StockCompareTool) getStockData(ctx context.Context, symbol, period str
 		"action": "info",
 		"symbol": symbol,
 	})
-	// 如果获取公司信息失败，创建一个空的响应继续执行
-	if err != nil || infoResp.IsError {
-		infoResp = &dto.MCPExecuteResponse{
-			Content: []dto.MCPContent{
-				{
-					Type: "text",
-					Text: "公司信息暂时无法获取",
-				},
-			},
-			IsError: false,
+	if err == nil && !infoResp.IsError {
+		if profile := profileFromResponse(infoResp); profile != nil {
+			data.Industry = profile.Industry
+			data.Sector = profile.Sector
+			data.MarketCap = profile.MarketCap
+			data.PE = profile.PE
 		}
 	}
 
-	// 解析数据
-	data := &StockData{
-		Symbol: symbol,
-	}
-
-	quoteText := quoteResp.Content[0].Text
-	infoText := infoResp.Content[0].Text
-
-	// 解析价格信息
-	data.CurrentPrice = sc.extractPrice(quoteText, "当前价格")
-	data.PreviousClose = sc.extractPrice(quoteText, "前收盘价")
-	data.Change = data.CurrentPrice - data.PreviousClose
-	if data.PreviousClose > 0 {
-		data.ChangePercent = (data.Change / data.PreviousClose) * 100
-	}
-	data.Volume = sc.extractVolume(quoteText)
-
-	// 解析公司信息
-	data.Industry = sc.extractInfo(infoText, "行业")
-	data.Sector = sc.extractInfo(infoText, "板块")
-	data.MarketCap = sc.extractInfo(infoText, "市值")
-	data.PE = sc.extractInfo(infoText, "市盈率")
-
 	return data, nil
 }
 
 // generatePerformanceComparison 生成表现对比
-func (sc *StockCompareTool) generatePerformanceComparison(symbols []string, stockData map[string]*StockData, period string) string {
-	comparison := fmt.Sprintf("📊 股票表现对比 (%s)\n", period)
-	comparison += fmt.Sprintf("📅 对比时间: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+func (sc *StockCompareTool) generatePerformanceComparison(t *toolTranslator, symbols []string, stockData map[string]*StockData, period string) string {
+	comparison := t.T("stock.compare.performance.title", map[string]interface{}{"Period": period}) + "\n"
+	comparison += t.T("stock.common.generated_at", map[string]interface{}{"Time": time.Now().Format("2006-01-02 15:04:05")}) + "\n\n"
 
 	// 表现排行榜
-	comparison += "🏆 涨跌幅排行:\n"
+	comparison += t.T("stock.compare.section.ranking", nil) + "\n"
 
 	// 按涨跌幅排序
 	sortedSymbols := make([]string, len(symbols))
@@ -284,18 +296,18 @@ func (sc *StockCompareTool) generatePerformanceComparison(symbols []string, stoc
 			emoji = "➡️"
 		}
 
-		comparison += fmt.Sprintf("%d. %s %s: $%.2f (%+.2f%%)\n",
-			i+1, emoji, symbol, data.CurrentPrice, data.ChangePercent)
+		comparison += fmt.Sprintf("%d. %s %s: %s (%+.2f%%)\n",
+			i+1, emoji, symbol, formatMoney(data.CurrentPrice, data.Currency), data.ChangePercent)
 	}
 
-	comparison += "\n💰 价格对比:\n"
+	comparison += "\n" + t.T("stock.compare.section.prices", nil) + "\n"
 	for _, symbol := range symbols {
 		data := stockData[symbol]
-		comparison += fmt.Sprintf("• %s: $%.2f (前收盘: $%.2f)\n",
-			symbol, data.CurrentPrice, data.PreviousClose)
+		comparison += fmt.Sprintf("• %s: %s (%s: %s)\n",
+			symbol, formatMoney(data.CurrentPrice, data.Currency), t.T("stock.field.previous_close", nil), formatMoney(data.PreviousClose, data.Currency))
 	}
 
-	comparison += "\n📊 成交量对比:\n"
+	comparison += "\n" + t.T("stock.compare.section.volume", nil) + "\n"
 	for _, symbol := range symbols {
 		data := stockData[symbol]
 		comparison += fmt.Sprintf("• %s: %s\n", symbol, formatVolumeCompare(data.Volume))
@@ -305,20 +317,21 @@ func (sc *StockCompareTool) generatePerformanceComparison(symbols []string, stoc
 }
 
 // generateValuationComparison 生成估值对比
-func (sc *StockCompareTool) generateValuationComparison(symbols []string, stockData map[string]*StockData) string {
-	comparison := "💰 估值对比分析\n\n"
+func (sc *StockCompareTool) generateValuationComparison(t *toolTranslator, symbols []string, stockData map[string]*StockData) string {
+	comparison := t.T("stock.compare.valuation.title", nil) + "\n\n"
 
-	comparison += "📊 关键估值指标:\n"
-	comparison += fmt.Sprintf("%-8s %-12s %-15s %-10s\n", "股票", "当前价格", "市值", "市盈率")
+	comparison += t.T("stock.compare.section.valuation_metrics", nil) + "\n"
+	comparison += fmt.Sprintf("%-8s %-12s %-15s %-10s\n", t.T("stock.field.stock", nil),
+		t.T("stock.field.current_price", nil), t.T("stock.field.market_cap", nil), t.T("stock.field.pe", nil))
 	comparison += strings.Repeat("-", 50) + "\n"
 
 	for _, symbol := range symbols {
 		data := stockData[symbol]
-		comparison += fmt.Sprintf("%-8s $%-11.2f %-15s %-10s\n",
-			symbol, data.CurrentPrice, data.MarketCap, data.PE)
+		comparison += fmt.Sprintf("%-8s %-12s %-15s %-10s\n",
+			symbol, formatMoney(data.CurrentPrice, data.Currency), formatCompareMarketCap(data.MarketCap, data.Currency), formatComparePE(data.PE))
 	}
 
-	comparison += "\n🏭 行业分布:\n"
+	comparison += "\n" + t.T("stock.compare.section.sectors", nil) + "\n"
 	sectorMap := make(map[string][]string)
 	for _, symbol := range symbols {
 		data := stockData[symbol]
@@ -331,26 +344,24 @@ func (sc *StockCompareTool) generateValuationComparison(symbols []string, stockD
 		comparison += fmt.Sprintf("• %s: %s\n", sector, strings.Join(stocks, ", "))
 	}
 
-	comparison += "\n💡 估值分析:\n"
-	comparison += "• 对比各股票的估值水平和投资价值\n"
-	comparison += "• 考虑行业特点和成长性\n"
-	comparison += "• 建议关注估值合理且有成长潜力的股票\n"
+	comparison += "\n" + t.T("stock.compare.section.valuation_analysis", nil) + "\n"
+	comparison += t.T("stock.compare.valuation.analysis", nil) + "\n"
 
 	return comparison
 }
 
 // generateRiskComparison 生成风险对比
-func (sc *StockCompareTool) generateRiskComparison(symbols []string, stockData map[string]*StockData) string {
-	comparison := "⚠️ 风险对比分析\n\n"
+func (sc *StockCompareTool) generateRiskComparison(t *toolTranslator, symbols []string, stockData map[string]*StockData) string {
+	comparison := t.T("stock.compare.risk.title", nil) + "\n\n"
 
-	comparison += "📊 风险指标对比:\n"
+	comparison += t.T("stock.compare.section.risk_metrics", nil) + "\n"
 	for _, symbol := range symbols {
 		data := stockData[symbol]
-		riskLevel := sc.assessStockRisk(data)
+		riskLevel := sc.assessStockRisk(t, data)
 		comparison += fmt.Sprintf("• %s: %s\n", symbol, riskLevel)
 	}
 
-	comparison += "\n🌍 行业风险分析:\n"
+	comparison += "\n" + t.T("stock.compare.section.industry_risk", nil) + "\n"
 	industryRisks := make(map[string][]string)
 	for _, symbol := range symbols {
 		data := stockData[symbol]
@@ -363,42 +374,45 @@ func (sc *StockCompareTool) generateRiskComparison(symbols []string, stockData m
 		comparison += fmt.Sprintf("• %s: %s\n", industry, strings.Join(stocks, ", "))
 	}
 
-	comparison += "\n💧 流动性风险:\n"
+	comparison += "\n" + t.T("stock.section.liquidity", nil) + "\n"
 	for _, symbol := range symbols {
 		data := stockData[symbol]
-		liquidityRisk := sc.assessLiquidityRisk(data.Volume)
-		comparison += fmt.Sprintf("• %s: %s (成交量: %s)\n",
-			symbol, liquidityRisk, formatVolumeCompare(data.Volume))
+		liquidityRisk := sc.assessLiquidityRisk(t, data.Volume)
+		comparison += fmt.Sprintf("• %s: %s (%s: %s)\n",
+			symbol, liquidityRisk, t.T("stock.field.volume", nil), formatVolumeCompare(data.Volume))
 	}
 
-	comparison += "\n🛡️ 风险管理建议:\n"
-	comparison += "• 分散投资于不同行业和风险等级的股票\n"
-	comparison += "• 根据个人风险承受能力配置仓位\n"
-	comparison += "• 定期评估和调整投资组合\n"
+	comparison += "\n" + t.T("stock.section.risk_management", nil) + "\n"
+	comparison += t.T("stock.compare.risk_management", nil) + "\n"
 
 	return comparison
 }
 
 // generateComprehensiveComparison 生成综合对比
-func (sc *StockCompareTool) generateComprehensiveComparison(symbols []string, stockData map[string]*StockData, period string) string {
-	comparison := fmt.Sprintf("📋 股票综合对比分析\n")
-	comparison += fmt.Sprintf("📅 分析时间: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	comparison += fmt.Sprintf("⏰ 对比周期: %s\n\n", period)
+func (sc *StockCompareTool) generateComprehensiveComparison(t *toolTranslator, symbols []string, stockData map[string]*StockData, period string) string {
+	comparison := t.T("stock.compare.comprehensive.title", nil) + "\n"
+	comparison += t.T("stock.common.generated_at", map[string]interface{}{"Time": time.Now().Format("2006-01-02 15:04:05")}) + "\n"
+	comparison += t.T("stock.compare.period", map[string]interface{}{"Period": period}) + "\n\n"
 
 	// 执行摘要
-	comparison += "📊 执行摘要:\n"
+	comparison += t.T("stock.section.summary", nil) + "\n"
 	bestPerformer := sc.findBestPerformer(symbols, stockData)
 	worstPerformer := sc.findWorstPerformer(symbols, stockData)
-	comparison += fmt.Sprintf("• 最佳表现: %s (%+.2f%%)\n",
-		bestPerformer, stockData[bestPerformer].ChangePercent)
-	comparison += fmt.Sprintf("• 最差表现: %s (%+.2f%%)\n",
-		worstPerformer, stockData[worstPerformer].ChangePercent)
-	comparison += fmt.Sprintf("• 对比股票数量: %d只\n\n", len(symbols))
+	comparison += t.T("stock.compare.best", map[string]interface{}{
+		"Symbol": bestPerformer,
+		"Change": fmt.Sprintf("%+.2f%%", stockData[bestPerformer].ChangePercent),
+	}) + "\n"
+	comparison += t.T("stock.compare.worst", map[string]interface{}{
+		"Symbol": worstPerformer,
+		"Change": fmt.Sprintf("%+.2f%%", stockData[worstPerformer].ChangePercent),
+	}) + "\n"
+	comparison += t.T("stock.compare.count", map[string]interface{}{"Count": len(symbols)}) + "\n\n"
 
 	// 详细对比表格
-	comparison += "📊 详细对比:\n"
+	comparison += t.T("stock.compare.section.details", nil) + "\n"
 	comparison += fmt.Sprintf("%-8s %-12s %-10s %-15s %-12s\n",
-		"股票", "当前价格", "涨跌幅", "成交量", "行业")
+		t.T("stock.field.stock", nil), t.T("stock.field.current_price", nil), t.T("stock.field.change_percent", nil),
+		t.T("stock.field.volume", nil), t.T("stock.field.industry", nil))
 	comparison += strings.Repeat("-", 65) + "\n"
 
 	for _, symbol := range symbols {
@@ -406,85 +420,59 @@ func (sc *StockCompareTool) generateComprehensiveComparison(symbols []string, st
 		changeStr := fmt.Sprintf("%+.2f%%", data.ChangePercent)
 		volumeStr := formatVolumeShort(data.Volume)
 		industryStr := data.Industry
-		if len(industryStr) > 12 {
+		if industryStr == "" {
+			industryStr = "N/A"
+		} else if len(industryStr) > 12 {
 			industryStr = industryStr[:12]
 		}
 
-		comparison += fmt.Sprintf("%-8s $%-11.2f %-10s %-15s %-12s\n",
-			symbol, data.CurrentPrice, changeStr, volumeStr, industryStr)
+		comparison += fmt.Sprintf("%-8s %-12s %-10s %-15s %-12s\n",
+			symbol, formatMoney(data.CurrentPrice, data.Currency), changeStr, volumeStr, industryStr)
 	}
 
 	// 投资建议
-	comparison += "\n💡 投资建议:\n"
-	comparison += sc.generateInvestmentRecommendations(symbols, stockData)
+	comparison += "\n" + t.T("stock.section.advice", nil) + "\n"
+	comparison += sc.generateInvestmentRecommendations(t, symbols, stockData)
 
-	comparison += "\n📝 免责声明: 本对比分析仅供参考，不构成投资建议。投资有风险，请谨慎决策。"
+	comparison += "\n" + t.T("stock.common.disclaimer", nil)
 
 	return comparison
 }
 
 // 辅助函数
 
-func (sc *StockCompareTool) extractPrice(text, keyword string) float64 {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, keyword) {
-			// 提取价格数字
-			parts := strings.Split(line, "$")
-			if len(parts) > 1 {
-				priceStr := strings.Fields(parts[1])[0]
-				if price, err := strconv.ParseFloat(priceStr, 64); err == nil {
-					return price
-				}
-			}
-		}
-	}
-	return 0
-}
-
-func (sc *StockCompareTool) extractVolume(text string) int64 {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "成交量") {
-			// 简单提取，实际应该解析具体数值
-			return 1000000 // 默认值
-		}
+func (sc *StockCompareTool) assessStockRisk(t *toolTranslator, data *StockData) string {
+	level := stockRiskLevel(data)
+	if level == "medium" {
+		return t.T("stock.risk_level.medium", nil)
 	}
-	return 0
+	return t.T("stock.compare.risk."+level, nil)
 }
 
-func (sc *StockCompareTool) extractInfo(text, keyword string) string {
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, keyword) {
-			parts := strings.Split(line, ":")
-			if len(parts) > 1 {
-				return strings.TrimSpace(parts[1])
-			}
-		}
-	}
-	return "N/A"
+func (sc *StockCompareTool) assessLiquidityRisk(t *toolTranslator, volume int64) string {
+	return t.T("stock.compare.liquidity."+liquidityLevel(volume), nil)
 }
 
-func (sc *StockCompareTool) assessStockRisk(data *StockData) string {
-	// 简单的风险评估逻辑
+// stockRiskLevel 简单的风险评估: high_volatility, sharp_decline, medium 或 stable
+func stockRiskLevel(data *StockData) string {
 	if data.ChangePercent > 5 {
-		return "高风险 (高波动)"
+		return "high_volatility"
 	} else if data.ChangePercent < -5 {
-		return "高风险 (大幅下跌)"
+		return "sharp_decline"
 	} else if data.ChangePercent > 2 || data.ChangePercent < -2 {
-		return "中等风险"
+		return "medium"
 	}
-	return "低风险 (相对稳定)"
+	return "stable"
 }
 
-func (sc *StockCompareTool) assessLiquidityRisk(volume int64) string {
+// liquidityLevel 根据成交量评估流动性: good, fair 或 poor
+func liquidityLevel(volume int64) string {
 	if volume > 10000000 {
-		return "流动性良好"
+		return "good"
 	} else if volume > 1000000 {
-		return "流动性一般"
+		return "fair"
 	}
-	return "流动性较差"
+	return "poor"
 }
 
 func (sc *StockCompareTool) findBestPerformer(symbols []string, stockData map[string]*StockData) string {
@@ -507,29 +495,29 @@ func (sc *StockCompareTool) findWorstPerformer(symbols []string, stockData map[s
 	return worst
 }
 
-func (sc *StockCompareTool) generateInvestmentRecommendations(symbols []string, stockData map[string]*StockData) string {
+func (sc *StockCompareTool) generateInvestmentRecommendations(t *toolTranslator, symbols []string, stockData map[string]*StockData) string {
 	recommendations := ""
 
 	for _, symbol := range symbols {
-		data := stockData[symbol]
-		var recommendation string
-
-		if data.ChangePercent > 3 {
-			recommendation = "谨慎观望 (涨幅较大)"
-		} else if data.ChangePercent > 0 {
-			recommendation = "可考虑买入"
-		} else if data.ChangePercent > -3 {
-			recommendation = "逢低买入机会"
-		} else {
-			recommendation = "高风险，谨慎投资"
-		}
-
+		recommendation := t.T("stock.compare.recommendation."+compareRecommendation(stockData[symbol]), nil)
 		recommendations += fmt.Sprintf("• %s: %s\n", symbol, recommendation)
 	}
 
 	return recommendations
 }
 
+// compareRecommendation 根据涨跌幅给出建议: cautious, consider_buy, buy_dip 或 high_risk
+func compareRecommendation(data *StockData) string {
+	if data.ChangePercent > 3 {
+		return "cautious"
+	} else if data.ChangePercent > 0 {
+		return "consider_buy"
+	} else if data.ChangePercent > -3 {
+		return "buy_dip"
+	}
+	return "high_risk"
+}
+
 func formatVolumeCompare(volume int64) string {
 	if volume >= 1000000000 {
 		return fmt.Sprintf("%.1fB", float64(volume)/1000000000)
@@ -549,3 +537,22 @@ func formatVolumeShort(volume int64) string {
 	}
 	return fmt.Sprintf("%.0fK", float64(volume)/1000)
 }
+
+// formatCompareMarketCap 格式化市值，未提供时返回 N/A
+func formatCompareMarketCap(marketCap float64, currency string) string {
+	if marketCap <= 0 {
+		return "N/A"
+	}
+	if currency == "" || currency == "USD" {
+		return "$" + formatLargeNumber(marketCap)
+	}
+	return formatLargeNumber(marketCap) + " " + currency
+}
+
+// formatComparePE 格式化市盈率，未提供时返回 N/A
+func formatComparePE(pe float64) string {
+	if pe <= 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.2f", pe)
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCompareToolUsesQuoteData(t *testing.T) {
+	compare := NewStockCompareTool(nil)
+	compare.yahooTool.httpClient.Transport = stubYahoo(t)
+
+	tests := []struct {
+		name        string
+		compareType string
+		contains    []string
+	}{
+		{
+			name:        "Performance",
+			compareType: "performance",
+			contains:    []string{"$189.50", "$187.25", "231.40 EUR", "228.90 EUR"},
+		},
+		{
+			name:        "Valuation",
+			compareType: "valuation",
+			contains:    []string{"$2.95T", "29.40", "270.00B EUR", "41.20"},
+		},
+		{
+			name:        "Comprehensive",
+			compareType: "comprehensive",
+			contains:    []string{"$189.50", "51.2M", "231.40 EUR", "1.5M"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := compare.Execute(context.Background(), map[string]interface{}{
+				"symbols":      []interface{}{"aapl", "SAP.DE"},
+				"compare_type": tt.compareType,
+			})
+			require.NoError(t, err)
+			require.False(t, resp.IsError, resp.Content[0].Text)
+			for _, want := range tt.contains {
+				assert.Contains(t, resp.Content[0].Text, want)
+			}
+			// 非美元股票不使用 $ 符号
+			assert.NotContains(t, resp.Content[0].Text, "$231.40")
+		})
+	}
+
+	// 结构化结果保留各自的币种和真实成交量、估值
+	resp, err := compare.Execute(context.Background(), map[string]interface{}{
+		"symbols": []interface{}{"AAPL", "SAP.DE"},
+		"format":  "json",
+	})
+	require.NoError(t, err)
+	report, ok := resp.Content[0].Data.(*dto.StockCompareReport)
+	require.True(t, ok)
+	require.Len(t, report.Stocks, 2)
+	sap := report.Stocks[1]
+	assert.Equal(t, "SAP.DE", sap.Symbol)
+	assert.Equal(t, "EUR", sap.Currency)
+	assert.Equal(t, int64(1534000), sap.Volume)
+	assert.Equal(t, 2.7e11, sap.MarketCap)
+	assert.Equal(t, 41.2, sap.PE)
+	assert.Equal(t, "USD", report.Stocks[0].Currency)
+}
@@ -0,0 +1,281 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
+	"go-springAi/internal/mcp"
+)
+
+// TranslateToolName 翻译工具注册名称
+const TranslateToolName = "translate"
+
+const (
+	// translateChunkRunes 每次采样翻译的最大字符数
+	translateChunkRunes = 6000
+	// maxTranslateChunks 单次翻译允许的最大分段数
+	maxTranslateChunks = 10
+	// translateMaxTokens 每次采样的最大生成token数
+	translateMaxTokens = 8000
+)
+
+// languageCodePattern BCP 47 语言标签，例如 en、zh-TW、pt-BR
+var languageCodePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// languageNames 常用语言代码对应的英文名称，用于提示词
+var languageNames = map[string]string{
+	"en":    "English",
+	"zh":    "Simplified Chinese",
+	"zh-cn": "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+	"zh-hk": "Traditional Chinese (Hong Kong)",
+	"ja":    "Japanese",
+	"ko":    "Korean",
+	"es":    "Spanish",
+	"de":    "German",
+	"fr":    "French",
+	"it":    "Italian",
+	"pt":    "Portuguese",
+	"pt-br": "Brazilian Portuguese",
+	"ru":    "Russian",
+	"ar":    "Arabic",
+	"hi":    "Hindi",
+	"nl":    "Dutch",
+	"vi":    "Vietnamese",
+	"th":    "Thai",
+	"id":    "Indonesian",
+}
+
+// TranslateTool 翻译工具，通过 MCP 采样由已配置的 AI 提供商翻译文本，
+// 用于将某种语言生成的报告按用户语言交付
+type TranslateTool struct {
+	*mcp.BaseTool
+	sampler     mcp.Sampler
+	i18nManager *i18n.Manager
+}
+
+// NewTranslateTool 创建翻译工具
+func NewTranslateTool(sampler mcp.Sampler, i18nManager *i18n.Manager) *TranslateTool {
+	return &TranslateTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        TranslateToolName,
+			Description: "将文本（例如其他工具生成的报告）翻译为目标语言，保留 Markdown 格式、emoji、数字、金额和股票代码",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"text": map[string]interface{}{
+						"type":        "string",
+						"description": "待翻译的文本",
+						"maxLength":   translateChunkRunes * maxTranslateChunks,
+					},
+					"target_language": map[string]interface{}{
+						"type":        "string",
+						"description": "目标语言代码 (例如: 'en', 'zh', 'ja', 'pt-BR')，默认跟随请求的 Accept-Language",
+					},
+					"source_language": map[string]interface{}{
+						"type":        "string",
+						"description": "原文语言代码，默认自动识别",
+					},
+					"model": map[string]interface{}{
+						"type":        "string",
+						"description": "优先使用的模型 (例如: 'gpt-4o-mini')，默认使用服务端配置",
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"text"},
+			},
+		},
+		sampler:     sampler,
+		i18nManager: i18nManager,
+	}
+}
+
+// Execute 执行翻译
+func (tt *TranslateTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := tt.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+	if tt.sampler == nil {
+		return textErrorResponse("翻译不可用：未配置 AI 提供商"), nil
+	}
+
+	text := args["text"].(string)
+	chunks := chunkText(text, translateChunkRunes)
+	if len(chunks) > maxTranslateChunks {
+		return textErrorResponse(fmt.Sprintf("文本过长：需要 %d 段，最多 %d 段", len(chunks), maxTranslateChunks)), nil
+	}
+
+	result := &dto.TranslationResult{
+		SourceLanguage: stringArg(args, "source_language", ""),
+		TargetLanguage: tt.targetLanguage(ctx, args),
+		Chunks:         len(chunks),
+	}
+
+	if strings.EqualFold(result.SourceLanguage, result.TargetLanguage) {
+		result.Text = text
+	} else {
+		translated, model, err := tt.translate(ctx, chunks, result.SourceLanguage, result.TargetLanguage, stringArg(args, "model", ""))
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("翻译失败: %v", err)), nil
+		}
+		result.Text = translated
+		result.Model = model
+	}
+
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: result.Text,
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (tt *TranslateTool) Validate(args map[string]interface{}) error {
+	text, ok := args["text"].(string)
+	if !ok {
+		return fmt.Errorf("text 参数是必需的且必须是字符串")
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("text 不能为空")
+	}
+
+	for _, name := range []string{"target_language", "source_language"} {
+		value, exists := args[name]
+		if !exists {
+			continue
+		}
+		if str, ok := value.(string); !ok || !languageCodePattern.MatchString(str) {
+			return fmt.Errorf("%s 必须是语言代码，例如 'en'、'zh-TW'", name)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// targetLanguage 目标语言：优先取参数，其次取请求语言
+func (tt *TranslateTool) targetLanguage(ctx context.Context, args map[string]interface{}) string {
+	if lang := stringArg(args, "target_language", ""); lang != "" {
+		return lang
+	}
+	if tt.i18nManager != nil {
+		return tt.i18nManager.ResolveLanguage(ctx, "")
+	}
+	return "en"
+}
+
+// translate 逐段翻译并按原顺序拼接
+func (tt *TranslateTool) translate(ctx context.Context, chunks []string, source, target, model string) (string, string, error) {
+	var modelPreferences *dto.MCPModelPreferences
+	if model != "" {
+		modelPreferences = &dto.MCPModelPreferences{Hints: []dto.MCPModelHint{{Name: model}}}
+	}
+	temperature := float32(0)
+	systemPrompt := translateSystemPrompt(source, target)
+
+	translated := make([]string, len(chunks))
+	var usedModel string
+	for i, chunk := range chunks {
+		// 首尾空行不发送给模型，翻译后按原文补回以保持段落间距
+		body := strings.Trim(chunk, "\n")
+		if strings.TrimSpace(body) == "" {
+			translated[i] = chunk
+			continue
+		}
+		leading := chunk[:len(chunk)-len(strings.TrimLeft(chunk, "\n"))]
+		trailing := chunk[len(strings.TrimRight(chunk, "\n")):]
+
+		resp, err := tt.sampler.CreateMessage(ctx, &dto.MCPCreateMessageRequest{
+			Messages: []dto.MCPSamplingMessage{
+				{Role: "user", Content: dto.MCPContent{Type: "text", Text: body}},
+			},
+			ModelPreferences: modelPreferences,
+			SystemPrompt:     systemPrompt,
+			MaxTokens:        translateMaxTokens,
+			Temperature:      &temperature,
+		})
+		if err != nil {
+			if len(chunks) > 1 {
+				return "", "", fmt.Errorf("第%d段: %w", i+1, err)
+			}
+			return "", "", err
+		}
+		translated[i] = leading + strings.Trim(resp.Content.Text, "\n") + trailing
+		usedModel = resp.Model
+	}
+	return strings.Join(translated, "\n"), usedModel, nil
+}
+
+// translateSystemPrompt 生成翻译的系统提示词
+func translateSystemPrompt(source, target string) string {
+	from := "the source language"
+	if source != "" {
+		from = languageName(source)
+	}
+	return "You are a professional translator for financial reports. Translate the user's text from " + from +
+		" into " + languageName(target) + ". Preserve Markdown formatting, line breaks, emoji, numbers, percentages, " +
+		"currency amounts, dates, ticker symbols and URLs exactly as written. Use standard financial terminology " +
+		"of the target language. Output only the translation, without explanations or quotes."
+}
+
+// languageName 语言代码对应的英文名称，未知代码原样返回
+func languageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	if base, _, found := strings.Cut(strings.ToLower(code), "-"); found {
+		if name, ok := languageNames[base]; ok {
+			return name + " (" + code + ")"
+		}
+	}
+	return code
+}
+
+// chunkText 按行将文本切分为不超过 maxRunes 个字符的片段，保留行内空白和空行，
+// 除超长行被强制切开外，片段用换行符拼接即可还原原文
+func chunkText(text string, maxRunes int) []string {
+	var chunks []string
+	var current []string
+	currentRunes := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, "\n"))
+		}
+		current = nil
+		currentRunes = 0
+	}
+
+	for _, line := range strings.Split(strings.Trim(text, "\n"), "\n") {
+		runes := []rune(line)
+		// 超长行直接按字符数切分
+		for len(runes) > maxRunes {
+			flush()
+			chunks = append(chunks, string(runes[:maxRunes]))
+			runes = runes[maxRunes:]
+		}
+		if len(runes) == 0 && line != "" {
+			continue
+		}
+
+		if len(current) > 0 && currentRunes+1+len(runes) > maxRunes {
+			flush()
+		}
+		current = append(current, string(runes))
+		currentRunes += len(runes) + 1
+	}
+	flush()
+
+	return chunks
+}
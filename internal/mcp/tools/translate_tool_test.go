@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samplerFunc 以函数实现 mcp.Sampler
+type samplerFunc func(ctx context.Context, req *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error)
+
+func (f samplerFunc) CreateMessage(ctx context.Context, req *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error) {
+	return f(ctx, req)
+}
+
+func TestTranslateTool(t *testing.T) {
+	manager, err := i18n.NewManager("en", []string{"en", "zh"})
+	require.NoError(t, err)
+
+	var prompts []string
+	tool := NewTranslateTool(samplerFunc(func(ctx context.Context, req *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error) {
+		prompts = append(prompts, req.SystemPrompt)
+		return &dto.MCPCreateMessageResponse{
+			Role:    "assistant",
+			Content: dto.MCPContent{Type: "text", Text: "[" + req.Messages[0].Content.Text + "]\n"},
+			Model:   "gpt-4o-mini",
+		}, nil
+	}), manager)
+
+	// 未指定目标语言时跟随请求语言
+	ctx := i18n.WithLanguage(context.Background(), "zh")
+	resp, err := tool.Execute(ctx, map[string]interface{}{"text": "📈 **AAPL** up 2.5%"})
+	require.NoError(t, err)
+	require.False(t, resp.IsError, resp.Content[0].Text)
+	assert.Equal(t, "[📈 **AAPL** up 2.5%]", resp.Content[0].Text)
+	assert.Equal(t, &dto.TranslationResult{TargetLanguage: "zh", Text: "[📈 **AAPL** up 2.5%]", Model: "gpt-4o-mini", Chunks: 1}, resp.Content[0].Data)
+	assert.Contains(t, prompts[0], "from the source language into Simplified Chinese")
+
+	resp, err = tool.Execute(ctx, map[string]interface{}{"text": "Hola", "source_language": "es", "target_language": "pt-BR", "format": "json"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"source_language":"es","target_language":"pt-BR","text":"[Hola]","model":"gpt-4o-mini","chunks":1}`, resp.Content[0].Text)
+	assert.Contains(t, prompts[1], "from Spanish into Brazilian Portuguese")
+
+	// 原文与目标语言相同时不调用模型
+	resp, err = tool.Execute(ctx, map[string]interface{}{"text": "Hello", "source_language": "en", "target_language": "EN"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Content[0].Text)
+	assert.Len(t, prompts, 2)
+
+	// 多段翻译按原顺序拼接并保留段落间的空行
+	long := strings.Repeat("a", translateChunkRunes) + "\n\n" + "b"
+	resp, err = tool.Execute(ctx, map[string]interface{}{"text": long, "target_language": "ja"})
+	require.NoError(t, err)
+	assert.Equal(t, "["+strings.Repeat("a", translateChunkRunes)+"]\n\n[b]", resp.Content[0].Text)
+	assert.Equal(t, 2, resp.Content[0].Data.(*dto.TranslationResult).Chunks)
+
+	assert.Error(t, tool.Validate(map[string]interface{}{"text": "  "}))
+	assert.Error(t, tool.Validate(map[string]interface{}{"text": "x", "target_language": "Chinese please"}))
+
+	failing := NewTranslateTool(samplerFunc(func(context.Context, *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error) {
+		return nil, fmt.Errorf("no provider")
+	}), nil)
+	resp, err = failing.Execute(context.Background(), map[string]interface{}{"text": "Hello"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "no provider")
+}
+
+func TestChunkText(t *testing.T) {
+	text := "# Title\n\n  - item one\n  - item two\n\nfooter"
+	chunks := chunkText(text, 12)
+	assert.Equal(t, []string{"# Title\n", "  - item one", "  - item two", "\nfooter"}, chunks)
+	assert.Equal(t, text, strings.Join(chunks, "\n"))
+
+	assert.Equal(t, []string{"季度收", "入增长"}, chunkText("季度收入增长", 3))
+}
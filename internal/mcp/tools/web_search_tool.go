@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
+)
+
+// WebSearchToolName 网页搜索工具注册名称
+const WebSearchToolName = "web_search"
+
+// 网页搜索后端，名称同时作为 API 密钥的提供商类型
+const (
+	SearchBackendBrave   = "brave"
+	SearchBackendBing    = "bing"
+	SearchBackendSerpAPI = "serpapi"
+)
+
+// SearchBackends 支持的网页搜索后端
+var SearchBackends = []string{SearchBackendBrave, SearchBackendBing, SearchBackendSerpAPI}
+
+// IsSearchBackend 判断名称是否为支持的网页搜索后端
+func IsSearchBackend(name string) bool {
+	return containsString(SearchBackends, name)
+}
+
+// IsToolKeyProvider 判断名称是否为工具使用的非AI提供商密钥类型（网页搜索后端、GitHub、Jira）
+func IsToolKeyProvider(name string) bool {
+	return IsSearchBackend(name) || name == GitHubKeyProvider || name == JiraKeyProvider
+}
+
+// 单次搜索返回的结果数量范围
+const (
+	defaultSearchResults = 5
+	maxSearchResults     = 20
+)
+
+// SearchKeyFunc 获取搜索后端的 API 密钥，未配置时返回空字符串
+type SearchKeyFunc func(ctx context.Context, backend string) (string, error)
+
+// searchEndpoints 各后端的搜索接口地址
+var searchEndpoints = map[string]string{
+	SearchBackendBrave:   "https://api.search.brave.com/res/v1/web/search",
+	SearchBackendBing:    "https://api.bing.microsoft.com/v7.0/search",
+	SearchBackendSerpAPI: "https://serpapi.com/search.json",
+}
+
+// searchFreshness 各后端表示时间范围的参数值
+var searchFreshness = map[string]map[string]string{
+	SearchBackendBrave:   {"day": "pd", "week": "pw", "month": "pm"},
+	SearchBackendBing:    {"day": "Day", "week": "Week", "month": "Month"},
+	SearchBackendSerpAPI: {"day": "qdr:d", "week": "qdr:w", "month": "qdr:m"},
+}
+
+// WebSearchTool 网页搜索工具，为助手提供行情数据以外的近期事件信息
+type WebSearchTool struct {
+	*mcp.BaseTool
+	backend    string
+	maxResults int
+	keys       SearchKeyFunc
+	endpoints  map[string]string
+	httpClient *http.Client
+}
+
+// NewWebSearchTool 创建网页搜索工具，backend 为默认后端，maxResults 为默认结果数量
+func NewWebSearchTool(backend string, maxResults int, keys SearchKeyFunc) *WebSearchTool {
+	if !IsSearchBackend(backend) {
+		backend = SearchBackendBrave
+	}
+	if maxResults <= 0 || maxResults > maxSearchResults {
+		maxResults = defaultSearchResults
+	}
+	return &WebSearchTool{
+		BaseTool: &mcp.BaseTool{
+			Name:        WebSearchToolName,
+			Description: "搜索网页，返回标题、链接和摘要，用于回答行情数据以外的近期事件和新闻",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "搜索关键词 (例如: 'Apple earnings guidance')",
+					},
+					"count": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("返回的结果数量 (1-%d)", maxSearchResults),
+						"minimum":     1,
+						"maximum":     maxSearchResults,
+						"default":     maxResults,
+					},
+					"freshness": map[string]interface{}{
+						"type":        "string",
+						"description": "只返回指定时间范围内的网页: 'day', 'week', 'month'",
+						"enum":        []string{"day", "week", "month"},
+					},
+					"backend": map[string]interface{}{
+						"type":        "string",
+						"description": "搜索后端，默认使用服务端配置",
+						"enum":        SearchBackends,
+						"default":     backend,
+					},
+					"format": formatProperty(),
+				},
+				"required": []string{"query"},
+			},
+		},
+		backend:    backend,
+		maxResults: maxResults,
+		keys:       keys,
+		endpoints:  searchEndpoints,
+		httpClient: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: &vcr.Transport{},
+		},
+	}
+}
+
+// Execute 执行网页搜索
+func (ws *WebSearchTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if err := ws.Validate(args); err != nil {
+		return textErrorResponse(fmt.Sprintf("参数验证失败: %v", err)), nil
+	}
+
+	query := strings.TrimSpace(args["query"].(string))
+	backend := stringArg(args, "backend", ws.backend)
+	count := intArg(args, "count", ws.maxResults)
+	freshness := stringArg(args, "freshness", "")
+
+	var apiKey string
+	if ws.keys != nil {
+		key, err := ws.keys(ctx, backend)
+		if err != nil {
+			return textErrorResponse(fmt.Sprintf("获取 %s 的API密钥失败: %v", backend, err)), nil
+		}
+		apiKey = key
+	}
+	if apiKey == "" {
+		return textErrorResponse(fmt.Sprintf("未配置 %s 的API密钥", backend)), nil
+	}
+
+	results, err := ws.search(ctx, backend, apiKey, query, count, freshness)
+	if err != nil {
+		return textErrorResponse(fmt.Sprintf("搜索失败: %v", err)), nil
+	}
+	if len(results) > count {
+		results = results[:count]
+	}
+
+	result := &dto.WebSearchResponse{Query: query, Backend: backend, Results: results}
+	if wantsJSON(args) {
+		return jsonResponse(result), nil
+	}
+
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{
+			{
+				Type: "text",
+				Text: formatSearchResults(result),
+				Data: result,
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// Validate 验证参数
+func (ws *WebSearchTool) Validate(args map[string]interface{}) error {
+	query, ok := args["query"].(string)
+	if !ok {
+		return fmt.Errorf("query 参数是必需的且必须是字符串")
+	}
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("query 不能为空")
+	}
+
+	if _, exists := args["count"]; exists {
+		if count := intArg(args, "count", 0); count < 1 || count > maxSearchResults {
+			return fmt.Errorf("count 必须在 1 到 %d 之间", maxSearchResults)
+		}
+	}
+
+	enums := map[string][]string{
+		"freshness": {"day", "week", "month"},
+		"backend":   SearchBackends,
+	}
+	for name, validValues := range enums {
+		value, exists := args[name]
+		if !exists {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok || !containsString(validValues, str) {
+			return fmt.Errorf("%s 必须是以下值之一: %v", name, validValues)
+		}
+	}
+
+	return validateOutputFormat(args)
+}
+
+// search 调用搜索后端并统一结果格式
+func (ws *WebSearchTool) search(ctx context.Context, backend, apiKey, query string, count int, freshness string) ([]dto.WebSearchResult, error) {
+	params := url.Values{}
+	header := http.Header{}
+	switch backend {
+	case SearchBackendBrave:
+		params.Set("q", query)
+		params.Set("count", strconv.Itoa(count))
+		if freshness != "" {
+			params.Set("freshness", searchFreshness[backend][freshness])
+		}
+		header.Set("X-Subscription-Token", apiKey)
+	case SearchBackendBing:
+		params.Set("q", query)
+		params.Set("count", strconv.Itoa(count))
+		params.Set("responseFilter", "Webpages")
+		if freshness != "" {
+			params.Set("freshness", searchFreshness[backend][freshness])
+		}
+		header.Set("Ocp-Apim-Subscription-Key", apiKey)
+	case SearchBackendSerpAPI:
+		params.Set("engine", "google")
+		params.Set("q", query)
+		params.Set("num", strconv.Itoa(count))
+		if freshness != "" {
+			params.Set("tbs", searchFreshness[backend][freshness])
+		}
+		params.Set("api_key", apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ws.endpoints[backend]+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header = header
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ws.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s 返回状态码 %d: %s", backend, resp.StatusCode, truncateRunes(strings.TrimSpace(string(body)), 200))
+	}
+
+	switch backend {
+	case SearchBackendBrave:
+		return parseBraveResults(body)
+	case SearchBackendBing:
+		return parseBingResults(body)
+	default:
+		return parseSerpAPIResults(body)
+	}
+}
+
+// parseBraveResults 解析 Brave Search API 响应
+func parseBraveResults(body []byte) ([]dto.WebSearchResult, error) {
+	var payload struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+				Age         string `json:"age"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	results := make([]dto.WebSearchResult, 0, len(payload.Web.Results))
+	for _, r := range payload.Web.Results {
+		results = append(results, dto.WebSearchResult{Title: r.Title, URL: r.URL, Snippet: stripSearchMarkup(r.Description), Published: r.Age})
+	}
+	return results, nil
+}
+
+// parseBingResults 解析 Bing Web Search API 响应
+func parseBingResults(body []byte) ([]dto.WebSearchResult, error) {
+	var payload struct {
+		WebPages struct {
+			Value []struct {
+				Name            string `json:"name"`
+				URL             string `json:"url"`
+				Snippet         string `json:"snippet"`
+				DatePublished   string `json:"datePublished"`
+				DateLastCrawled string `json:"dateLastCrawled"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	results := make([]dto.WebSearchResult, 0, len(payload.WebPages.Value))
+	for _, r := range payload.WebPages.Value {
+		published := r.DatePublished
+		if published == "" {
+			published = r.DateLastCrawled
+		}
+		results = append(results, dto.WebSearchResult{Title: r.Name, URL: r.URL, Snippet: r.Snippet, Published: published})
+	}
+	return results, nil
+}
+
+// parseSerpAPIResults 解析 SerpAPI (Google) 响应
+func parseSerpAPIResults(body []byte) ([]dto.WebSearchResult, error) {
+	var payload struct {
+		Error          string `json:"error"`
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+			Date    string `json:"date"`
+		} `json:"organic_results"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	// 没有结果时 SerpAPI 也以 error 字段说明，按空结果处理
+	if payload.Error != "" && !strings.Contains(payload.Error, "hasn't returned any results") {
+		return nil, fmt.Errorf("serpapi: %s", payload.Error)
+	}
+	results := make([]dto.WebSearchResult, 0, len(payload.OrganicResults))
+	for _, r := range payload.OrganicResults {
+		results = append(results, dto.WebSearchResult{Title: r.Title, URL: r.Link, Snippet: r.Snippet, Published: r.Date})
+	}
+	return results, nil
+}
+
+// stripSearchMarkup 去除 Brave 摘要中用于高亮关键词的 <strong> 标签
+func stripSearchMarkup(text string) string {
+	return strings.NewReplacer("<strong>", "", "</strong>", "").Replace(text)
+}
+
+// truncateRunes 截断过长的文本
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
+
+// formatSearchResults 格式化搜索结果文本
+func formatSearchResults(result *dto.WebSearchResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔍 搜索结果: %s (%s)\n\n", result.Query, result.Backend)
+	if len(result.Results) == 0 {
+		b.WriteString("未找到相关网页")
+		return b.String()
+	}
+	for i, r := range result.Results {
+		fmt.Fprintf(&b, "%d. %s\n   🔗 %s\n", i+1, r.Title, r.URL)
+		if r.Published != "" {
+			fmt.Fprintf(&b, "   🕒 %s\n", r.Published)
+		}
+		if r.Snippet != "" {
+			fmt.Fprintf(&b, "   %s\n", r.Snippet)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
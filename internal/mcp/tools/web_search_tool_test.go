@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSearchToolBackends(t *testing.T) {
+	tests := []struct {
+		backend   string
+		response  string
+		checkAuth func(t *testing.T, r *http.Request)
+		freshness string // 请求中 freshness=week 对应的后端参数
+	}{
+		{
+			backend:  SearchBackendBrave,
+			response: `{"web":{"results":[{"title":"Apple Q4","url":"https://example.com/a","description":"Record <strong>revenue</strong>","age":"2 days ago"},{"title":"Other","url":"https://example.com/b","description":"x"}]}}`,
+			checkAuth: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "secret-key", r.Header.Get("X-Subscription-Token"))
+				assert.Equal(t, "pw", r.URL.Query().Get("freshness"))
+			},
+		},
+		{
+			backend:  SearchBackendBing,
+			response: `{"webPages":{"value":[{"name":"Apple Q4","url":"https://example.com/a","snippet":"Record revenue","datePublished":"2 days ago"},{"name":"Other","url":"https://example.com/b","snippet":"x"}]}}`,
+			checkAuth: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "secret-key", r.Header.Get("Ocp-Apim-Subscription-Key"))
+				assert.Equal(t, "Week", r.URL.Query().Get("freshness"))
+			},
+		},
+		{
+			backend:  SearchBackendSerpAPI,
+			response: `{"organic_results":[{"title":"Apple Q4","link":"https://example.com/a","snippet":"Record revenue","date":"2 days ago"},{"title":"Other","link":"https://example.com/b","snippet":"x"}]}`,
+			checkAuth: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "secret-key", r.URL.Query().Get("api_key"))
+				assert.Equal(t, "qdr:w", r.URL.Query().Get("tbs"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.checkAuth(t, r)
+				assert.Equal(t, "apple earnings", r.URL.Query().Get("q"))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			var requestedBackend string
+			tool := NewWebSearchTool(SearchBackendBrave, 5, func(ctx context.Context, backend string) (string, error) {
+				requestedBackend = backend
+				return "secret-key", nil
+			})
+			tool.endpoints = map[string]string{tt.backend: server.URL}
+			tool.httpClient = server.Client()
+
+			resp, err := tool.Execute(context.Background(), map[string]interface{}{
+				"query":     " apple earnings ",
+				"backend":   tt.backend,
+				"count":     float64(1),
+				"freshness": "week",
+			})
+			require.NoError(t, err)
+			require.False(t, resp.IsError, resp.Content[0].Text)
+			assert.Equal(t, tt.backend, requestedBackend)
+
+			result, ok := resp.Content[0].Data.(*dto.WebSearchResponse)
+			require.True(t, ok)
+			assert.Equal(t, &dto.WebSearchResponse{
+				Query:   "apple earnings",
+				Backend: tt.backend,
+				Results: []dto.WebSearchResult{{Title: "Apple Q4", URL: "https://example.com/a", Snippet: "Record revenue", Published: "2 days ago"}},
+			}, result, "results are trimmed to count and normalized")
+			assert.Contains(t, resp.Content[0].Text, "https://example.com/a")
+		})
+	}
+}
+
+func TestWebSearchToolErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid token"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	noKey := NewWebSearchTool(SearchBackendBing, 0, func(context.Context, string) (string, error) { return "", nil })
+	resp, err := noKey.Execute(context.Background(), map[string]interface{}{"query": "news"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "bing")
+
+	rejected := NewWebSearchTool(SearchBackendBrave, 0, func(context.Context, string) (string, error) { return "bad", nil })
+	rejected.endpoints = map[string]string{SearchBackendBrave: server.URL}
+	rejected.httpClient = server.Client()
+	resp, err = rejected.Execute(context.Background(), map[string]interface{}{"query": "news"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsError)
+	assert.Contains(t, resp.Content[0].Text, "401")
+
+	assert.Error(t, rejected.Validate(map[string]interface{}{"query": " "}))
+	assert.Error(t, rejected.Validate(map[string]interface{}{"query": "x", "count": float64(21)}))
+	assert.Error(t, rejected.Validate(map[string]interface{}{"query": "x", "backend": "google"}))
+}
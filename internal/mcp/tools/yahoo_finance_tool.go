@@ -13,8 +13,12 @@ import (
 
 	"go-springAi/internal/dto"
 	"go-springAi/internal/mcp"
+	"go-springAi/internal/vcr"
 )
 
+// YahooFinanceToolName Yahoo Finance 工具注册名称
+const YahooFinanceToolName = "雅虎财经"
+
 // YahooFinanceTool Yahoo Finance 股票数据工具
 type YahooFinanceTool struct {
 	*mcp.BaseTool
@@ -25,7 +29,7 @@ type YahooFinanceTool struct {
 func NewYahooFinanceTool() *YahooFinanceTool {
 	return &YahooFinanceTool{
 		BaseTool: &mcp.BaseTool{
-			Name:        "雅虎财经",
+			Name:        YahooFinanceToolName,
 			Description: "获取股票数据",
 			InputSchema: map[string]interface{}{
 				"type": "object",
@@ -51,12 +55,14 @@ func NewYahooFinanceTool() *YahooFinanceTool {
 						"enum":        []string{"1m", "2m", "5m", "15m", "30m", "60m", "90m", "1h", "1d", "5d", "1wk", "1mo", "3mo"},
 						"default":     "1d",
 					},
+					"format": formatProperty(),
 				},
 				"required": []string{"action", "symbol"},
 			},
 		},
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: &vcr.Transport{},
 		},
 	}
 }
@@ -79,9 +85,11 @@ func (yf *YahooFinanceTool) Execute(ctx context.Context, args map[string]interfa
 	action := args["action"].(string)
 	symbol := strings.ToUpper(args["symbol"].(string))
 
+	var resp *dto.MCPExecuteResponse
+	var err error
 	switch action {
 	case "quote":
-		return yf.getQuote(ctx, symbol)
+		resp, err = yf.getQuote(ctx, symbol)
 	case "history":
 		period := "1mo"
 		interval := "1d"
@@ -91,9 +99,9 @@ func (yf *YahooFinanceTool) Execute(ctx context.Context, args map[string]interfa
 		if i, ok := args["interval"].(string); ok {
 			interval = i
 		}
-		return yf.getHistory(ctx, symbol, period, interval)
+		resp, err = yf.getHistory(ctx, symbol, period, interval)
 	case "info":
-		return yf.getInfo(ctx, symbol)
+		resp, err = yf.getInfo(ctx, symbol)
 	default:
 		return &dto.MCPExecuteResponse{
 			Content: []dto.MCPContent{
@@ -105,6 +113,12 @@ func (yf *YahooFinanceTool) Execute(ctx context.Context, args map[string]interfa
 			IsError: true,
 		}, nil
 	}
+
+	// JSON 格式只保留结构化数据
+	if err == nil && !resp.IsError && wantsJSON(args) {
+		return jsonResponse(resp.Content[0].Data), nil
+	}
+	return resp, err
 }
 
 // Validate 验证参数
@@ -137,7 +151,7 @@ func (yf *YahooFinanceTool) Validate(args map[string]interface{}) error {
 		return fmt.Errorf("action 必须是以下值之一: %v", validActions)
 	}
 
-	return nil
+	return validateOutputFormat(args)
 }
 
 // getQuote 获取股票实时报价
@@ -269,6 +283,17 @@ func (yf *YahooFinanceTool) getQuote(ctx context.Context, symbol string) (*dto.M
 			{
 				Type: "text",
 				Text: quoteText,
+				Data: &dto.StockQuote{
+					Symbol:        meta.Symbol,
+					Currency:      meta.Currency,
+					Exchange:      meta.ExchangeName,
+					Price:         meta.RegularMarketPrice,
+					PreviousClose: meta.PreviousClose,
+					DayHigh:       meta.RegularMarketDayHigh,
+					DayLow:        meta.RegularMarketDayLow,
+					Volume:        meta.RegularMarketVolume,
+					MarketTime:    meta.RegularMarketTime,
+				},
 			},
 		},
 		IsError: false,
@@ -403,9 +428,28 @@ func (yf *YahooFinanceTool) getHistory(ctx context.Context, symbol, period, inte
 	// 格式化历史数据
 	historyText := fmt.Sprintf("📊 %s 历史数据 (%s, %s)\n\n", symbol, period, interval)
 
+	// 完整的收盘价序列，供服务层做结构化计算
+	series := &dto.PriceSeries{
+		Symbol:   symbol,
+		Currency: result.Meta.Currency,
+	}
+
 	if len(result.Timestamp) > 0 && result.Indicators.Quote != nil && len(result.Indicators.Quote) > 0 {
 		quote := result.Indicators.Quote[0]
 
+		for i, ts := range result.Timestamp {
+			// 跳过缺失的数据点（Yahoo 以 null 表示）
+			if i < len(quote.Close) && quote.Close[i] > 0 {
+				series.Timestamps = append(series.Timestamps, ts)
+				series.Closes = append(series.Closes, quote.Close[i])
+				if i < len(quote.Volume) {
+					series.Volumes = append(series.Volumes, quote.Volume[i])
+				} else {
+					series.Volumes = append(series.Volumes, 0)
+				}
+			}
+		}
+
 		// 显示最近几个数据点
 		maxPoints := 10
 		if len(result.Timestamp) < maxPoints {
@@ -429,6 +473,7 @@ func (yf *YahooFinanceTool) getHistory(ctx context.Context, symbol, period, inte
 			{
 				Type: "text",
 				Text: historyText,
+				Data: series,
 			},
 		},
 		IsError: false,
@@ -525,9 +570,16 @@ func (yf *YahooFinanceTool) getInfo(ctx context.Context, symbol string) (*dto.MC
 
 	// 格式化公司信息
 	infoText := fmt.Sprintf("🏢 %s 公司信息\n\n", symbol)
+	companyProfile := &dto.CompanyProfile{Symbol: symbol}
 
 	if result.SummaryProfile != nil {
 		profile := result.SummaryProfile
+		companyProfile.Name = profile.LongName
+		companyProfile.Industry = profile.Industry
+		companyProfile.Sector = profile.Sector
+		companyProfile.Country = profile.Country
+		companyProfile.Website = profile.Website
+		companyProfile.Employees = profile.FullTimeEmployees
 		infoText += fmt.Sprintf("📝 公司名称: %s\n", profile.LongName)
 		infoText += fmt.Sprintf("🏭 行业: %s\n", profile.Industry)
 		infoText += fmt.Sprintf("🏢 板块: %s\n", profile.Sector)
@@ -548,15 +600,19 @@ func (yf *YahooFinanceTool) getInfo(ctx context.Context, symbol string) (*dto.MC
 		detail := result.SummaryDetail
 		infoText += "📊 关键指标:\n"
 		if detail.MarketCap != nil {
+			companyProfile.MarketCap = detail.MarketCap.Raw
 			infoText += fmt.Sprintf("💰 市值: $%s\n", formatLargeNumber(detail.MarketCap.Raw))
 		}
 		if detail.PeRatio != nil {
+			companyProfile.PE = detail.PeRatio.Raw
 			infoText += fmt.Sprintf("📈 市盈率: %.2f\n", detail.PeRatio.Raw)
 		}
 		if detail.DividendYield != nil {
+			companyProfile.DividendYield = detail.DividendYield.Raw
 			infoText += fmt.Sprintf("💵 股息收益率: %.2f%%\n", detail.DividendYield.Raw*100)
 		}
 		if detail.Beta != nil {
+			companyProfile.Beta = detail.Beta.Raw
 			infoText += fmt.Sprintf("📊 Beta: %.2f\n", detail.Beta.Raw)
 		}
 	}
@@ -566,6 +622,7 @@ func (yf *YahooFinanceTool) getInfo(ctx context.Context, symbol string) (*dto.MC
 			{
 				Type: "text",
 				Text: infoText,
+				Data: companyProfile,
 			},
 		},
 		IsError: false,
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/vcr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc 用函数实现 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+const chartResponse = `{"chart":{"result":[{"meta":{"currency":"USD","symbol":"AAPL","exchangeName":"NMS","regularMarketPrice":189.5,"previousClose":187.25,"regularMarketDayHigh":190.1,"regularMarketDayLow":186.9,"regularMarketVolume":51234567,"regularMarketTime":1767225600}}],"error":null}}`
+
+func TestYahooFinanceToolReplay(t *testing.T) {
+	dir := t.TempDir()
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(chartResponse)),
+			Request:    req,
+		}, nil
+	})
+	args := map[string]interface{}{"action": "quote", "symbol": "AAPL"}
+
+	recorder, err := vcr.New(vcr.Options{Mode: vcr.ModeRecord, Dir: dir})
+	require.NoError(t, err)
+	tool := NewYahooFinanceTool()
+	tool.httpClient.Transport = &vcr.Transport{Base: upstream, Recorder: recorder}
+	recorded, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	require.False(t, recorded.IsError, recorded.Content[0].Text)
+
+	// 回放不再访问上游
+	recorder, err = vcr.New(vcr.Options{Mode: vcr.ModeReplay, Dir: dir})
+	require.NoError(t, err)
+	tool.httpClient.Transport = &vcr.Transport{Base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("replay must not call upstream")
+		return nil, nil
+	}), Recorder: recorder}
+	replayed, err := tool.Execute(context.Background(), args)
+	require.NoError(t, err)
+	assert.False(t, replayed.IsError)
+	assert.Equal(t, recorded.Content[0].Text, replayed.Content[0].Text)
+	assert.Contains(t, replayed.Content[0].Text, "$189.50")
+
+	missing, err := tool.Execute(context.Background(), map[string]interface{}{"action": "quote", "symbol": "MSFT"})
+	require.NoError(t, err)
+	assert.True(t, missing.IsError)
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// usageLabels AI用量指标的标签，user 为用户ID（匿名为 anonymous），project 为项目名称
+var usageLabels = []string{"provider", "model", "user", "project"}
+
+// ModelPrice 模型每百万令牌的美元价格，Model 按最长前缀匹配响应中的模型名称
+type ModelPrice struct {
+	Model      string
+	Prompt     float64
+	Completion float64
+}
+
+// PriceLookup 按提供商和模型名称精确查询价格，优先于按前缀匹配的配置价格
+type PriceLookup func(provider, model string) (ModelPrice, bool)
+
+// AIUsageMetrics 按提供商、模型、用户和项目统计令牌用量和估算费用的Prometheus指标
+type AIUsageMetrics struct {
+	registry         *prometheus.Registry
+	promptTokens     *prometheus.CounterVec
+	completionTokens *prometheus.CounterVec
+	cost             *prometheus.CounterVec
+	prices           []ModelPrice
+	lookup           PriceLookup
+}
+
+// NewAIUsageMetrics 创建AI用量指标，同时注册Go运行时和进程指标
+func NewAIUsageMetrics(prices []ModelPrice) *AIUsageMetrics {
+	m := &AIUsageMetrics{
+		registry: prometheus.NewRegistry(),
+		promptTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ai_prompt_tokens_total",
+			Help: "Prompt tokens consumed by AI chat requests.",
+		}, usageLabels),
+		completionTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ai_completion_tokens_total",
+			Help: "Completion tokens produced by AI chat requests.",
+		}, usageLabels),
+		cost: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ai_cost_usd_total",
+			Help: "Estimated cost of AI chat requests in USD, based on configured model prices.",
+		}, usageLabels),
+		prices: prices,
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.promptTokens,
+		m.completionTokens,
+		m.cost,
+	)
+	return m
+}
+
+// SetPriceLookup 设置精确价格查询，如管理员为模型保存的价格
+func (m *AIUsageMetrics) SetPriceLookup(lookup PriceLookup) {
+	m.lookup = lookup
+}
+
+// RecordChat 记录一次聊天请求的令牌用量，未配置价格的模型不计费用
+func (m *AIUsageMetrics) RecordChat(provider, model, user, project string, promptTokens, completionTokens int) {
+	if promptTokens < 0 {
+		promptTokens = 0
+	}
+	if completionTokens < 0 {
+		completionTokens = 0
+	}
+
+	labels := prometheus.Labels{"provider": provider, "model": model, "user": user, "project": project}
+	m.promptTokens.With(labels).Add(float64(promptTokens))
+	m.completionTokens.With(labels).Add(float64(completionTokens))
+	if cost, ok := m.EstimateCost(provider, model, promptTokens, completionTokens); ok {
+		m.cost.With(labels).Add(cost)
+	}
+}
+
+// Handler 返回Prometheus抓取端点
+func (m *AIUsageMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// EstimateCost 按模型价格估算一次请求的美元费用，未配置价格的模型返回 false
+func (m *AIUsageMetrics) EstimateCost(provider, model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := m.price(provider, model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1e6, true
+}
+
+// price 优先使用精确查询到的价格，否则按最长前缀匹配配置的价格
+func (m *AIUsageMetrics) price(provider, model string) (ModelPrice, bool) {
+	if m.lookup != nil {
+		if price, ok := m.lookup(provider, model); ok {
+			return price, true
+		}
+	}
+	var price *ModelPrice
+	for i := range m.prices {
+		p := &m.prices[i]
+		if strings.HasPrefix(model, p.Model) && (price == nil || len(p.Model) > len(price.Model)) {
+			price = p
+		}
+	}
+	if price == nil {
+		return ModelPrice{}, false
+	}
+	return *price, true
+}
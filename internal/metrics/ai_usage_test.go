@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	m := NewAIUsageMetrics([]ModelPrice{
+		{Model: "gpt-4o", Prompt: 2.5, Completion: 10},
+		{Model: "gpt-4o-mini", Prompt: 0.15, Completion: 0.6},
+	})
+	m.SetPriceLookup(func(provider, model string) (ModelPrice, bool) {
+		if provider == "openai" && model == "gpt-4.1" {
+			return ModelPrice{Model: model, Prompt: 1, Completion: 2}, true
+		}
+		return ModelPrice{}, false
+	})
+
+	tests := []struct {
+		name     string
+		model    string
+		expected float64
+		priced   bool
+	}{
+		{name: "Exact match", model: "gpt-4o", expected: 0.0035, priced: true},
+		{name: "Longest prefix wins", model: "gpt-4o-mini-2024-07-18", expected: 0.00021, priced: true},
+		{name: "Dated snapshot", model: "gpt-4o-2024-08-06", expected: 0.0035, priced: true},
+		{name: "Stored price", model: "gpt-4.1", expected: 0.0012, priced: true},
+		{name: "Unpriced model", model: "gemini-1.5-pro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, ok := m.EstimateCost("openai", tt.model, 1000, 100)
+			if ok != tt.priced {
+				t.Fatalf("expected priced %v, got %v", tt.priced, ok)
+			}
+			if math.Abs(cost-tt.expected) > 1e-12 {
+				t.Errorf("expected cost %v, got %v", tt.expected, cost)
+			}
+		})
+	}
+}
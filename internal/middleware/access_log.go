@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+
+	"go-springAi/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultMaxBodyBytes 未配置时请求和响应体的最大记录长度
+const defaultMaxBodyBytes = 4096
+
+// redactedValue 敏感字段脱敏后的值
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyParts 字段名包含这些片段时视为敏感字段（忽略大小写、下划线和连字符）
+var sensitiveKeyParts = []string{"apikey", "password", "token", "secret", "authorization"}
+
+// AccessLogOptions HTTP访问日志选项
+type AccessLogOptions struct {
+	LogBodies    bool // 是否记录JSON/表单请求体和JSON响应体，敏感字段自动脱敏
+	MaxBodyBytes int  // 请求体和响应体的最大记录长度，超出部分截断
+}
+
+// bodyCaptureWriter 在写出响应的同时保留响应体前 limit 个字节
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyCaptureWriter) capture(data []byte) {
+	if remaining := w.limit + 1 - w.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			data = data[:remaining]
+		}
+		w.body.Write(data)
+	}
+}
+
+// captureRequestBody 读取请求体前 limit 个字节用于记录，并恢复请求体供后续处理器读取
+func captureRequestBody(c *gin.Context, limit int) []byte {
+	if c.Request.Body == nil || !isLoggableContentType(c.ContentType()) {
+		return nil
+	}
+	head, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
+	if err != nil {
+		return nil
+	}
+	c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+	return head
+}
+
+// readCloser 组合已读取的部分和原始请求体
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// isLoggableContentType 只记录JSON和表单内容，文件上传和流式响应不记录
+func isLoggableContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// redactBody 对请求体或响应体做脱敏并按 limit 截断，无法解析时只记录长度
+func redactBody(body []byte, contentType string, limit int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	truncated := len(body) > limit
+	if truncated {
+		body = body[:limit]
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		return redactQuery(string(body))
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// 截断后的JSON无法完整解析，为避免泄露敏感字段不记录原文
+		if truncated {
+			return "[TRUNCATED]"
+		}
+		return "[UNPARSEABLE]"
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[UNPARSEABLE]"
+	}
+	return string(redacted)
+}
+
+// redactValue 递归替换JSON中敏感字段的值
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// redactQuery 替换查询字符串或表单中敏感参数的值
+func redactQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return "[UNPARSEABLE]"
+	}
+	for key := range values {
+		if isSensitiveKey(key) {
+			values[key] = []string{redactedValue}
+		}
+	}
+	return values.Encode()
+}
+
+// isSensitiveKey 判断字段名是否为敏感字段
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildBodyFields 构建请求体和响应体字段
+func buildBodyFields(c *gin.Context, requestBody []byte, writer *bodyCaptureWriter, limit int) []logger.LogField {
+	fields := []logger.LogField{}
+	if body := redactBody(requestBody, c.ContentType(), limit); body != "" {
+		fields = append(fields, logger.String("request_body", body))
+	}
+	if writer != nil {
+		contentType := writer.Header().Get("Content-Type")
+		if isLoggableContentType(contentType) {
+			if body := redactBody(writer.body.Bytes(), contentType, limit); body != "" {
+				fields = append(fields, logger.String("response_body", body))
+			}
+		}
+	}
+	return fields
+}
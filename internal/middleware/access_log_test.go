@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		limit       int
+		want        string
+	}{
+		{name: "Top-level secrets", body: `{"username":"alice","password":"p@ss","api_key":"sk-1"}`, contentType: "application/json", limit: 1024, want: `{"api_key":"[REDACTED]","password":"[REDACTED]","username":"alice"}`},
+		{name: "Nested token fields", body: `{"data":{"accessToken":"t","items":[{"Refresh-Token":"r","id":1}]}}`, contentType: "application/json; charset=utf-8", limit: 1024, want: `{"data":{"accessToken":"[REDACTED]","items":[{"Refresh-Token":"[REDACTED]","id":1}]}}`},
+		{name: "Form body", body: "username=alice&password=p%40ss", contentType: "application/x-www-form-urlencoded", limit: 1024, want: "password=%5BREDACTED%5D&username=alice"},
+		{name: "Truncated JSON is not logged", body: `{"api_key":"sk-1234567890"}`, contentType: "application/json", limit: 10, want: "[TRUNCATED]"},
+		{name: "Empty body", body: "", contentType: "application/json", limit: 1024, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redactBody([]byte(tt.body), tt.contentType, tt.limit))
+		})
+	}
+}
+
+func TestZapLoggerKeepsRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := `{"api_key":"sk-1","model":"gpt-4o"}`
+
+	r := gin.New()
+	r.Use(ZapLogger(zap.NewNop(), AccessLogOptions{LogBodies: true, MaxBodyBytes: 8}))
+	r.POST("/echo", func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "application/json", data)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo?token=abc", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, "token=%5BREDACTED%5D", redactQuery(req.URL.RawQuery))
+}
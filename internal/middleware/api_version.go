@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader 响应中标明处理请求的API版本
+const APIVersionHeader = "X-API-Version"
+
+// APIVersion 在响应头中标明处理请求的API版本
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// LegacyAPI 兼容未带版本的旧路径 /api/...，按 version 处理，
+// 并通过 Deprecation 和 Link 头提示客户端迁移到 /api/<version>/... 新路径
+func LegacyAPI(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		successor := "/api/" + version + strings.TrimPrefix(c.Request.URL.Path, "/api")
+		c.Header(APIVersionHeader, version)
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		c.Next()
+	}
+}
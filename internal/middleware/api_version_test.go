@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/v1/projects/:id", APIVersion("v1"), ok)
+	r.GET("/api/projects/:id", LegacyAPI("v1"), ok)
+
+	tests := []struct {
+		name           string
+		path           string
+		wantDeprecated bool
+		wantLink       string
+	}{
+		{name: "Versioned path", path: "/api/v1/projects/7"},
+		{name: "Legacy path", path: "/api/projects/7", wantDeprecated: true, wantLink: `</api/v1/projects/7>; rel="successor-version"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "v1", w.Header().Get(APIVersionHeader))
+			assert.Equal(t, tt.wantDeprecated, w.Header().Get("Deprecation") == "true")
+			assert.Equal(t, tt.wantLink, w.Header().Get("Link"))
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
 	"go-springAi/internal/response"
 	"go-springAi/internal/utils"
@@ -13,8 +16,13 @@ import (
 	"go.uber.org/zap"
 )
 
-// AuthMiddleware JWT认证中间件
-func AuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger) gin.HandlerFunc {
+// APITokenAuthenticator 个人访问令牌校验接口
+type APITokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error)
+}
+
+// AuthMiddleware 认证中间件，同时接受JWT和个人访问令牌
+func AuthMiddleware(jwtManager *utils.JWTManager, apiTokens APITokenAuthenticator, zapLogger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从请求头获取Authorization token
 		authHeader := c.GetHeader("Authorization")
@@ -46,6 +54,30 @@ func AuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger) gin.Han
 
 		token := tokenParts[1]
 
+		// 个人访问令牌
+		if strings.HasPrefix(token, dto.APITokenPrefix) {
+			principal, err := authenticateAPIToken(c, apiTokens, token)
+			if err != nil {
+				zapLogger.Warn("API token validation failed",
+					zap.String("module", "auth"),
+					zap.String("component", "middleware"),
+					zap.String("operation", "auth"),
+					zap.Error(err))
+
+				if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeTokenExpired {
+					response.Error(c, http.StatusUnauthorized, "Token expired", "")
+				} else {
+					response.Error(c, http.StatusUnauthorized, "Invalid token", "")
+				}
+				c.Abort()
+				return
+			}
+
+			setAPITokenContext(c, principal)
+			c.Next()
+			return
+		}
+
 		// 验证JWT token
 		claims, err := jwtManager.ValidateToken(token)
 		if err != nil {
@@ -66,9 +98,7 @@ func AuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger) gin.Han
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", strconv.FormatInt(claims.UserID, 10))
-		c.Set("username", claims.Username)
-		c.Set("auth_type", "jwt")
+		setJWTContext(c, claims, zapLogger)
 
 		zapLogger.Info("Token validated successfully",
 			zap.String("module", "auth"),
@@ -83,7 +113,7 @@ func AuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger) gin.Han
 
 // OptionalAuthMiddleware 可选认证中间件
 // 如果提供了token则验证，如果没有提供则继续执行但不设置用户信息
-func OptionalAuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger) gin.HandlerFunc {
+func OptionalAuthMiddleware(jwtManager *utils.JWTManager, apiTokens APITokenAuthenticator, zapLogger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -102,6 +132,25 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger)
 
 		token := tokenParts[1]
 
+		// 个人访问令牌
+		if strings.HasPrefix(token, dto.APITokenPrefix) {
+			principal, err := authenticateAPIToken(c, apiTokens, token)
+			if err != nil {
+				// token无效，继续执行但不设置用户信息
+				zapLogger.Warn("API token validation failed in optional auth",
+					zap.String("module", "auth"),
+					zap.String("component", "middleware"),
+					zap.String("operation", "optional_auth"),
+					zap.Error(err))
+				c.Next()
+				return
+			}
+
+			setAPITokenContext(c, principal)
+			c.Next()
+			return
+		}
+
 		// 验证JWT token
 		claims, err := jwtManager.ValidateToken(token)
 		if err != nil {
@@ -116,9 +165,7 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger)
 		}
 
 		// 将用户信息存储到上下文中
-		c.Set("user_id", strconv.FormatInt(claims.UserID, 10))
-		c.Set("username", claims.Username)
-		c.Set("auth_type", "jwt")
+		setJWTContext(c, claims, zapLogger)
 
 		zapLogger.Info("Token validated successfully in optional auth",
 			zap.String("module", "auth"),
@@ -131,6 +178,212 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager, zapLogger *zap.Logger)
 	}
 }
 
+// RequireScope 要求个人访问令牌具备指定权限范围，JWT和匿名请求不受影响
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_type") != "api_token" {
+			c.Next()
+			return
+		}
+
+		if !hasScope(c.GetStringSlice("token_scopes"), scope) {
+			response.Error(c, http.StatusForbidden, "Insufficient token scope", "required scope: "+scope)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSessionAuth 要求使用用户本人的JWT登录会话访问，拒绝个人访问令牌和模拟登录会话
+func RequireSessionAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_type") == "api_token" {
+			response.Error(c, http.StatusForbidden, "Personal access tokens cannot be used for this endpoint", "")
+			c.Abort()
+			return
+		}
+		if IsImpersonated(c) {
+			response.Error(c, http.StatusForbidden, "Impersonated sessions cannot be used for this endpoint", "")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsImpersonated 判断当前请求是否来自管理员模拟登录会话
+func IsImpersonated(c *gin.Context) bool {
+	_, exists := c.Get("impersonator_id")
+	return exists
+}
+
+// setJWTContext 将JWT身份存储到上下文中，模拟登录会话额外记录审计日志并返回提示头
+func setJWTContext(c *gin.Context, claims *utils.Claims, zapLogger *zap.Logger) {
+	c.Set("user_id", strconv.FormatInt(claims.UserID, 10))
+	c.Set("username", claims.Username)
+	c.Set("auth_type", "jwt")
+	if claims.AuthTime != nil {
+		c.Set("auth_time", claims.AuthTime.Time)
+	}
+	applyUserLocale(c, claims.UserID)
+
+	if claims.Impersonation == nil {
+		return
+	}
+
+	c.Set("impersonator_id", claims.Impersonation.AdminID)
+	c.Header("X-Impersonated-By", claims.Impersonation.AdminUsername)
+	c.Header("X-Impersonation-Banner", claims.Impersonation.Banner)
+
+	zapLogger.Warn("Impersonated request",
+		zap.String("audit", "impersonation"),
+		zap.String("event", "request"),
+		zap.Int64("admin_id", claims.Impersonation.AdminID),
+		zap.String("admin_username", claims.Impersonation.AdminUsername),
+		zap.Int64("user_id", claims.UserID),
+		zap.String("username", claims.Username),
+		zap.String("reason", claims.Impersonation.Reason),
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.Request.URL.Path))
+}
+
+// UserLookup 根据ID查询用户的接口
+type UserLookup interface {
+	GetByID(ctx context.Context, id int64) (*dto.UserResponse, error)
+}
+
+// RequireAdmin 要求当前用户为管理员，需在认证中间件之后使用
+func RequireAdmin(users UserLookup, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := GetUserIDFromContext(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, "Authentication required", "")
+			c.Abort()
+			return
+		}
+
+		user, err := users.GetByID(c.Request.Context(), userID)
+		if err != nil || !user.IsAdmin {
+			zapLogger.Warn("Admin access denied",
+				zap.String("module", "auth"),
+				zap.String("component", "middleware"),
+				zap.String("operation", "require_admin"),
+				zap.Int64("user_id", userID),
+				zap.Error(err))
+
+			response.Error(c, http.StatusForbidden, "Admin privileges required", "")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PermissionChecker 用户权限校验接口
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userID int64, permission string) (bool, error)
+}
+
+// RequirePermission 要求当前用户拥有指定权限，需在认证中间件之后使用
+func RequirePermission(permissions PermissionChecker, permission string, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := GetUserIDFromContext(c)
+		if err != nil {
+			response.Error(c, http.StatusUnauthorized, "Authentication required", "")
+			c.Abort()
+			return
+		}
+
+		has, err := permissions.HasPermission(c.Request.Context(), userID, permission)
+		if err != nil || !has {
+			zapLogger.Warn("Permission denied",
+				zap.String("module", "auth"),
+				zap.String("component", "middleware"),
+				zap.String("operation", "require_permission"),
+				zap.Int64("user_id", userID),
+				zap.String("permission", permission),
+				zap.Error(err))
+
+			response.Error(c, http.StatusForbidden, "Permission required", "required permission: "+permission)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRecentAuth 要求当前JWT会话在 maxAge 内通过 /api/v1/auth/reauth 重新认证
+func RequireRecentAuth(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("auth_time")
+		authTime, ok := value.(time.Time)
+		if !exists || !ok || time.Since(authTime) > maxAge {
+			response.Error(c, http.StatusForbidden, "Re-authentication required", "POST /api/v1/auth/reauth with your password, then retry with the returned access token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Audit 为敏感端点记录审计日志，无论请求被放行还是拒绝都会记录，应放在认证中间件之前
+func Audit(event string, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		result := "allowed"
+		if status >= http.StatusBadRequest {
+			result = "denied"
+		}
+
+		zapLogger.Warn("Sensitive endpoint accessed",
+			zap.String("audit", event),
+			zap.String("result", result),
+			zap.Int("status", status),
+			zap.String("user_id", c.GetString("user_id")),
+			zap.String("username", c.GetString("username")),
+			zap.String("auth_type", c.GetString("auth_type")),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("client_ip", c.ClientIP()))
+	}
+}
+
+// authenticateAPIToken 校验个人访问令牌
+func authenticateAPIToken(c *gin.Context, apiTokens APITokenAuthenticator, token string) (*dto.APITokenPrincipal, error) {
+	if apiTokens == nil {
+		return nil, errors.NewTokenInvalidError()
+	}
+	return apiTokens.Authenticate(c.Request.Context(), token)
+}
+
+// setAPITokenContext 将个人访问令牌身份存储到上下文中
+func setAPITokenContext(c *gin.Context, principal *dto.APITokenPrincipal) {
+	c.Set("user_id", strconv.FormatInt(principal.UserID, 10))
+	c.Set("username", principal.Username)
+	c.Set("auth_type", "api_token")
+	c.Set("token_id", principal.TokenID)
+	c.Set("token_scopes", principal.Scopes)
+	applyUserLocale(c, principal.UserID)
+}
+
+// hasScope 判断权限范围列表中是否包含指定范围
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserIDFromContext 从上下文中获取用户ID
 func GetUserIDFromContext(c *gin.Context) (int64, error) {
 	userIDStr, exists := c.Get("user_id")
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRequireRecentAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+
+	sessionToken, err := jwtManager.GenerateToken(1, "admin")
+	require.NoError(t, err)
+	reauthToken, _, err := jwtManager.GenerateReauthToken(1, "admin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		token      string
+		maxAge     time.Duration
+		wantStatus int
+	}{
+		{name: "Session token without re-auth", token: sessionToken, maxAge: 5 * time.Minute, wantStatus: http.StatusForbidden},
+		{name: "Fresh re-auth token", token: reauthToken, maxAge: 5 * time.Minute, wantStatus: http.StatusOK},
+		{name: "Stale re-auth token", token: reauthToken, maxAge: -time.Second, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/secret", AuthMiddleware(jwtManager, nil, zap.NewNop()), RequireRecentAuth(tt.maxAge), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/secret", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// staticAPITokens 任意个人访问令牌都认证为同一用户
+type staticAPITokens struct{}
+
+func (staticAPITokens) Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error) {
+	return &dto.APITokenPrincipal{TokenID: 1, UserID: 1, Username: "alice", Scopes: []string{dto.APITokenScopeAI}}, nil
+}
+
+func TestRequireSessionAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+
+	sessionToken, err := jwtManager.GenerateToken(1, "alice")
+	require.NoError(t, err)
+	impersonationToken, _, err := jwtManager.GenerateImpersonationToken(1, "alice", utils.ImpersonationClaim{
+		AdminID:       2,
+		AdminUsername: "root",
+		Reason:        "support ticket",
+		Banner:        "Impersonated session: root is acting as alice",
+	}, time.Minute)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "Session token", token: sessionToken, wantStatus: http.StatusOK},
+		{name: "Impersonated session", token: impersonationToken, wantStatus: http.StatusForbidden},
+		{name: "Personal access token", token: dto.APITokenPrefix + "test", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.PUT("/password", AuthMiddleware(jwtManager, staticAPITokens{}, zap.NewNop()), RequireSessionAuth(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/password", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"go-springAi/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PathBodyLimit 路径前缀下的请求体大小限制
+type PathBodyLimit struct {
+	Prefix   string
+	MaxBytes int64
+}
+
+// BodyLimitOptions 请求体大小限制选项，按最长路径前缀匹配，未匹配的请求使用 Default，限制不大于0表示不限制
+type BodyLimitOptions struct {
+	Default int64
+	Paths   []PathBodyLimit
+}
+
+// limitFor 获取路径对应的请求体大小限制
+func (o BodyLimitOptions) limitFor(path string) int64 {
+	limit, matched := o.Default, ""
+	for _, p := range o.Paths {
+		if strings.HasPrefix(path, p.Prefix) && len(p.Prefix) > len(matched) {
+			limit, matched = p.MaxBytes, p.Prefix
+		}
+	}
+	return limit
+}
+
+// BodyLimit 限制请求体大小，声明的 Content-Length 超限时直接返回413，
+// 分块传输的请求在读取超限时由错误处理器返回413
+func BodyLimit(opts BodyLimitOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := opts.limitFor(c.Request.URL.Path)
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			AbortWithAppError(c, errors.NewRequestTooLargeError(limit))
+			return
+		}
+
+		c.Request.Body = &limitedBody{
+			ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, limit),
+			c:          c,
+			limit:      limit,
+		}
+		c.Next()
+	}
+}
+
+// limitedBody 读取超限时在上下文中标记，便于错误处理器返回413
+type limitedBody struct {
+	io.ReadCloser
+	c     *gin.Context
+	limit int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if isBodyTooLarge(err) {
+		b.c.Set(errors.BodyTooLargeKey, b.limit)
+	}
+	return n, err
+}
+
+// isBodyTooLarge 判断是否为请求体超限错误
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return stderrors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		path       string
+		bodySize   int
+		chunked    bool
+		wantStatus int
+	}{
+		{name: "Within default limit", path: "/api/chat", bodySize: 10, wantStatus: http.StatusOK},
+		{name: "Declared length over default limit", path: "/api/chat", bodySize: 20, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "Chunked body over default limit", path: "/api/chat", bodySize: 20, chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "Longer prefix allows larger body", path: "/api/upload/doc", bodySize: 50, wantStatus: http.StatusOK},
+		{name: "Longer prefix still enforced", path: "/api/upload/doc", bodySize: 101, chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	handler := errors.NewErrorHandler(nil)
+	r := gin.New()
+	r.Use(ErrorHandler(zap.NewNop()))
+	r.Use(BodyLimit(BodyLimitOptions{
+		Default: 16,
+		Paths:   []PathBodyLimit{{Prefix: "/api/upload", MaxBytes: 100}},
+	}))
+	r.POST("/*path", func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			handler.HandleError(c, errors.NewValidationError("请求参数无效").WithDetails(err.Error()))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(strings.Repeat("a", tt.bodySize))
+			if tt.chunked {
+				body = io.MultiReader(body) // 隐藏长度，模拟分块传输
+			}
+			req := httptest.NewRequest(http.MethodPost, tt.path, body)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, w.Body.String(), string(errors.ErrCodeRequestTooLarge))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"go-springAi/internal/chaos"
+	"go-springAi/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Chaos 按注入器的概率为请求增加延迟、返回5xx或直接断开连接，只应在测试和压测环境启用
+func Chaos(injector *chaos.Injector, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !injector.Applies(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		fault := injector.Next()
+		if fault.Delay > 0 {
+			c.Header(chaos.Header, chaos.FaultLatency)
+			timer := time.NewTimer(fault.Delay)
+			select {
+			case <-timer.C:
+			case <-c.Request.Context().Done():
+				timer.Stop()
+			}
+		}
+
+		// 只有 HTTP/1.x 连接可以接管，HTTP/2 请求改为返回502
+		if fault.Reset && c.Request.ProtoMajor == 1 {
+			if conn, _, err := c.Writer.Hijack(); err == nil {
+				logger.Debug("Chaos: resetting connection", zap.String("path", c.Request.URL.Path))
+				conn.Close()
+				c.Abort()
+				return
+			}
+		}
+		if fault.Reset {
+			fault.Status = http.StatusBadGateway
+		}
+
+		if fault.Status == 0 {
+			c.Next()
+			return
+		}
+		c.Header(chaos.Header, chaos.FaultError)
+		AbortWithAppError(c, errors.NewAppError(errors.ErrCodeServiceUnavailable, "Injected fault", errors.SeverityLow, fault.Status).
+			WithDetails("chaos testing is enabled on this server"))
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/chaos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestChaos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		opts       chaos.Options
+		path       string
+		wantStatus int
+		wantFault  string
+		wantReset  bool
+	}{
+		{name: "No fault", opts: chaos.Options{}, path: "/api/v1/ping", wantStatus: http.StatusOK},
+		{name: "Injected error", opts: chaos.Options{ErrorRate: 1, ErrorStatuses: []int{502}}, path: "/api/v1/ping", wantStatus: http.StatusBadGateway, wantFault: chaos.FaultError},
+		{name: "Injected latency", opts: chaos.Options{LatencyRate: 1, MaxLatency: time.Millisecond}, path: "/api/v1/ping", wantStatus: http.StatusOK, wantFault: chaos.FaultLatency},
+		{name: "Connection reset", opts: chaos.Options{ResetRate: 1}, path: "/api/v1/ping", wantReset: true},
+		{name: "Path not covered", opts: chaos.Options{ErrorRate: 1, Paths: []string{"/api/v1/ai"}}, path: "/api/v1/ping", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector, err := chaos.New(tt.opts)
+			require.NoError(t, err)
+			r := gin.New()
+			r.Use(ErrorHandler(zap.NewNop()))
+			r.Use(Chaos(injector, zap.NewNop()))
+			r.GET("/api/v1/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + tt.path)
+			if tt.wantReset {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantFault, resp.Header.Get(chaos.Header))
+		})
+	}
+}
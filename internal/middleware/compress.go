@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCompressMinLength 未配置时压缩的最小响应长度，更小的响应压缩收益不大
+const defaultCompressMinLength = 1024
+
+// CompressOptions 响应压缩选项
+type CompressOptions struct {
+	MinLength    int      // 响应体达到该长度才压缩
+	ExcludePaths []string // 不压缩的路径前缀
+}
+
+var (
+	gzipWriterPool   = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	brotliWriterPool = sync.Pool{New: func() interface{} { return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression) }}
+)
+
+// Compress 按 Accept-Encoding 使用brotli或gzip压缩响应，SSE等流式响应和已编码的响应不压缩
+func Compress(opts CompressOptions) gin.HandlerFunc {
+	if opts.MinLength <= 0 {
+		opts.MinLength = defaultCompressMinLength
+	}
+
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead ||
+			c.GetHeader("Accept") == "text/event-stream" || isExcludedPath(c.Request.URL.Path, opts.ExcludePaths) {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minLength: opts.MinLength}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding 选择客户端接受的编码，优先brotli
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	switch {
+	case accepted["br"]:
+		return "br"
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
+// isExcludedPath 判断路径是否在排除列表中
+func isExcludedPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompressibleContentType 只压缩文本类和 MessagePack 响应，图片、PDF等已压缩格式和SSE流不压缩
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/javascript", mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+xml"), mediaType == "image/svg+xml",
+		mediaType == "application/msgpack":
+		return true
+	default:
+		return false
+	}
+}
+
+// compressWriter 先缓冲响应直到达到最小长度再决定是否压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding  string
+	minLength int
+	buf       []byte
+	decided   bool
+	encoder   io.WriteCloser
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if !w.shouldCompress() {
+			w.decide(false)
+			return w.ResponseWriter.Write(data)
+		}
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minLength {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 流式写出时立即决定是否压缩，并把已压缩的数据刷新给客户端
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(w.shouldCompress() && len(w.buf) >= w.minLength)
+	}
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Written 缓冲中的响应尚未写出，但已经开始响应
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// shouldCompress 根据状态码和响应头判断是否压缩
+func (w *compressWriter) shouldCompress() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.Header()
+	return header.Get("Content-Encoding") == "" && isCompressibleContentType(header.Get("Content-Type"))
+}
+
+// decide 确定是否压缩并写出缓冲的数据
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	buffered := w.buf
+	w.buf = nil
+
+	if compress {
+		header := w.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		w.encoder = newEncoder(w.encoding, w.ResponseWriter)
+		if len(buffered) > 0 {
+			_, err := w.encoder.Write(buffered)
+			return err
+		}
+		return nil
+	}
+
+	if len(buffered) > 0 {
+		_, err := w.ResponseWriter.Write(buffered)
+		return err
+	}
+	return nil
+}
+
+// finish 写出剩余缓冲并结束压缩流
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.encoder != nil {
+		w.encoder.Close()
+		releaseEncoder(w.encoder)
+		w.encoder = nil
+	}
+}
+
+// newEncoder 从对象池获取压缩器
+func newEncoder(encoding string, dst io.Writer) io.WriteCloser {
+	if encoding == "br" {
+		bw := brotliWriterPool.Get().(*brotli.Writer)
+		bw.Reset(dst)
+		return bw
+	}
+	gw := gzipWriterPool.Get().(*gzip.Writer)
+	gw.Reset(dst)
+	return gw
+}
+
+// releaseEncoder 将压缩器放回对象池
+func releaseEncoder(encoder io.WriteCloser) {
+	switch e := encoder.(type) {
+	case *brotli.Writer:
+		e.Reset(io.Discard)
+		brotliWriterPool.Put(e)
+	case *gzip.Writer:
+		e.Reset(io.Discard)
+		gzipWriterPool.Put(e)
+	}
+}
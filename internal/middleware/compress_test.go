@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat("a", 2048)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		path           string
+		wantEncoding   string
+	}{
+		{name: "Brotli preferred", acceptEncoding: "gzip, deflate, br", path: "/json", wantEncoding: "br"},
+		{name: "Gzip", acceptEncoding: "gzip", path: "/json", wantEncoding: "gzip"},
+		{name: "Brotli refused by q=0", acceptEncoding: "br;q=0, gzip", path: "/json", wantEncoding: "gzip"},
+		{name: "No accepted encoding", acceptEncoding: "", path: "/json", wantEncoding: ""},
+		{name: "Small response", acceptEncoding: "gzip", path: "/small", wantEncoding: ""},
+		{name: "Binary response", acceptEncoding: "gzip", path: "/pdf", wantEncoding: ""},
+		{name: "SSE stream", acceptEncoding: "gzip", path: "/sse", wantEncoding: ""},
+		{name: "Excluded path", acceptEncoding: "gzip", path: "/skip/json", wantEncoding: ""},
+	}
+
+	r := gin.New()
+	r.Use(Compress(CompressOptions{ExcludePaths: []string{"/skip"}}))
+	jsonHandler := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": large}) }
+	r.GET("/json", jsonHandler)
+	r.GET("/skip/json", jsonHandler)
+	r.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	r.GET("/pdf", func(c *gin.Context) { c.Data(http.StatusOK, "application/pdf", []byte(large)) })
+	r.GET("/sse", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.WriteString("data: " + large + "\n\n")
+		c.Writer.Flush()
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+
+			var body io.Reader = w.Body
+			switch tt.wantEncoding {
+			case "br":
+				body = brotli.NewReader(w.Body)
+			case "gzip":
+				gr, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				body = gr
+			}
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			if tt.path != "/small" {
+				assert.Contains(t, string(data), large)
+			}
+		})
+	}
+}
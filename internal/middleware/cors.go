@@ -1,26 +1,100 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-// CORS 跨域中间件
-func CORS() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		if origin != "" {
+// CORSOptions 跨域选项，AllowedOrigins 为空时不返回跨域响应头，只允许同源访问
+type CORSOptions struct {
+	AllowedOrigins   []string // 支持 "*" 和 "https://*.example.com" 形式的子域名通配
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool // 通过 "*" 匹配的来源不允许携带凭据
+	MaxAge           time.Duration
+}
+
+// CORS 跨域中间件，预检请求在此直接返回，不进入后续中间件
+func CORS(opts CORSOptions) gin.HandlerFunc {
+	allowMethods := strings.Join(opts.AllowedMethods, ", ")
+	allowHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowed, wildcard := opts.matchOrigin(origin)
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if wildcard {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
 			c.Header("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
 		}
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Authorization, Cache-Control, Pragma")
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type")
-		c.Header("Access-Control-Allow-Credentials", "true")
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			if opts.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
+		if exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		}
 		c.Next()
-	})
+	}
+}
+
+// matchOrigin 判断来源是否允许，wildcard 表示通过 "*" 匹配
+func (o CORSOptions) matchOrigin(origin string) (allowed, wildcard bool) {
+	origin = strings.ToLower(origin)
+	for _, pattern := range o.AllowedOrigins {
+		pattern = strings.ToLower(pattern)
+		switch {
+		case pattern == origin:
+			return true, false
+		case pattern == "*":
+			wildcard = true
+		case matchSubdomain(pattern, origin):
+			return true, false
+		}
+	}
+	return wildcard, wildcard
+}
+
+// matchSubdomain 匹配 "https://*.example.com" 形式的来源，不匹配裸域名
+func matchSubdomain(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix := scheme + "://"
+	return strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, "."+host) &&
+		len(origin) > len(prefix)+len(host)+1
 }
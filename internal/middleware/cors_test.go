@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name            string
+		opts            CORSOptions
+		method          string
+		origin          string
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials bool
+	}{
+		{name: "Allowed origin", opts: opts, method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllowOrigin: "https://app.example.com", wantCredentials: true},
+		{name: "Subdomain wildcard", opts: opts, method: http.MethodGet, origin: "https://admin.example.org", wantStatus: http.StatusOK, wantAllowOrigin: "https://admin.example.org", wantCredentials: true},
+		{name: "Bare domain not matched by subdomain wildcard", opts: opts, method: http.MethodGet, origin: "https://example.org", wantStatus: http.StatusOK},
+		{name: "Disallowed origin", opts: opts, method: http.MethodGet, origin: "https://evil.com", wantStatus: http.StatusOK},
+		{name: "Same-origin request", opts: opts, method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "Preflight allowed", opts: opts, method: http.MethodOptions, origin: "https://app.example.com", wantStatus: http.StatusNoContent, wantAllowOrigin: "https://app.example.com", wantCredentials: true},
+		{name: "Preflight disallowed", opts: opts, method: http.MethodOptions, origin: "https://evil.com", wantStatus: http.StatusForbidden},
+		{name: "Any origin without credentials", opts: CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}, method: http.MethodGet, origin: "https://evil.com", wantStatus: http.StatusOK, wantAllowOrigin: "*"},
+		{name: "No origins configured", opts: CORSOptions{}, method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(CORS(tt.opts))
+			r.GET("/api/projects", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/api/projects", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantAllowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantCredentials, w.Header().Get("Access-Control-Allow-Credentials") == "true")
+		})
+	}
+}
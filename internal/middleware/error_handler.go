@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"go-springAi/internal/errors"
+	"go-springAi/internal/errreport"
 	"go-springAi/internal/logger"
+	"go-springAi/internal/requestid"
 	"go-springAi/internal/response"
 	"context"
 	"strings"
@@ -20,9 +22,13 @@ func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
 		// 检查是否有错误
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last().Err
+			if ctxErr := errors.RequestContextError(c); ctxErr != nil {
+				err = ctxErr
+			}
 
-			// 记录错误日志
+			// 记录错误日志并上报严重错误
 			logError(logger, c, err)
+			reportError(c, err)
 
 			// 处理错误响应
 			handleErrorResponse(c, err)
@@ -40,7 +46,6 @@ func logError(zapLogger *zap.Logger, c *gin.Context, err error) {
 		zap.String("path", c.Request.URL.Path),
 		zap.String("client_ip", c.ClientIP()),
 		zap.String("user_agent", c.Request.UserAgent()),
-		zap.String("request_id", getRequestID(c)),
 		zap.String("trace_id", getTraceID(c)),
 		zap.Time("error_time", time.Now()),
 	}
@@ -130,6 +135,15 @@ func logError(zapLogger *zap.Logger, c *gin.Context, err error) {
 	}
 }
 
+// reportError 上报错误，非应用错误按内部错误上报
+func reportError(c *gin.Context, err error) {
+	appErr, ok := errors.IsAppError(err)
+	if !ok {
+		appErr = errors.NewInternalError(err.Error()).WithCause(err)
+	}
+	errreport.ReportAppError(c, appErr)
+}
+
 // handleErrorResponse 处理错误响应
 func handleErrorResponse(c *gin.Context, err error) {
 	if appErr, ok := errors.IsAppError(err); ok {
@@ -157,12 +171,7 @@ func AbortWithAppError(c *gin.Context, appErr *errors.AppError) {
 
 // getRequestID 获取请求ID
 func getRequestID(c *gin.Context) string {
-	if requestID, exists := c.Get("request_id"); exists {
-		if id, ok := requestID.(string); ok {
-			return id
-		}
-	}
-	return c.GetHeader("X-Request-ID")
+	return requestid.FromContext(c.Request.Context())
 }
 
 // getTraceID 获取追踪ID
@@ -239,7 +248,6 @@ func logSecurityEvent(ctx context.Context, appErr *errors.AppError, c *gin.Conte
 		logger.String("user_agent", c.Request.UserAgent()),
 		logger.String("path", c.Request.URL.Path),
 		logger.String("method", c.Request.Method),
-		logger.String("request_id", getRequestID(c)),
 		logger.Time("event_time", time.Now()),
 	}
 
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag 为 GET 请求的 200 响应生成弱 ETag，If-None-Match 匹配时返回 304 不带响应体。
+// 响应体在内存中缓冲后再写出，只用于列表这类较小的响应；
+// 使用弱 ETag 是因为压缩中间件会改变响应体的编码
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &etagWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// 处理器没有写响应体时，错误响应由错误中间件写出
+		if writer.body.Len() == 0 {
+			return
+		}
+		if writer.Status() != http.StatusOK {
+			writer.ResponseWriter.Write(writer.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		header := writer.Header()
+		header.Set("ETag", etag)
+		if header.Get("Cache-Control") == "" {
+			// 响应与用户相关，客户端每次使用前重新验证
+			header.Set("Cache-Control", "private, no-cache")
+		}
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			writer.ResponseWriter.WriteHeader(http.StatusNotModified)
+			writer.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		writer.ResponseWriter.Write(writer.body.Bytes())
+	}
+}
+
+// etagMatches If-None-Match 使用弱比较，忽略 W/ 前缀
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter 缓冲响应体，计算 ETag 后再写出
+type etagWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *etagWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *etagWriter) Written() bool {
+	return w.body.Len() > 0 || w.ResponseWriter.Written()
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ETag())
+	listHandler := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"models": []string{"gpt-4o", "gpt-4o-mini"}}) }
+	r.GET("/models", listHandler)
+	r.POST("/models", listHandler)
+	r.GET("/missing", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "not found"}) })
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/models", nil))
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch string
+		wantCode    int
+		wantBody    bool
+		wantETag    bool
+	}{
+		{name: "Matching ETag", method: http.MethodGet, path: "/models", ifNoneMatch: etag, wantCode: http.StatusNotModified, wantETag: true},
+		{name: "Strong form of weak ETag", method: http.MethodGet, path: "/models", ifNoneMatch: etag[2:], wantCode: http.StatusNotModified, wantETag: true},
+		{name: "One of several", method: http.MethodGet, path: "/models", ifNoneMatch: `"other", ` + etag, wantCode: http.StatusNotModified, wantETag: true},
+		{name: "Wildcard", method: http.MethodGet, path: "/models", ifNoneMatch: "*", wantCode: http.StatusNotModified, wantETag: true},
+		{name: "Stale ETag", method: http.MethodGet, path: "/models", ifNoneMatch: `W/"stale"`, wantCode: http.StatusOK, wantBody: true, wantETag: true},
+		{name: "Non-GET request", method: http.MethodPost, path: "/models", ifNoneMatch: etag, wantCode: http.StatusOK, wantBody: true},
+		{name: "Error response", method: http.MethodGet, path: "/missing", ifNoneMatch: "*", wantCode: http.StatusNotFound, wantBody: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantBody, w.Body.Len() > 0)
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag") != "")
+			if tt.wantBody && tt.wantETag {
+				assert.Equal(t, first.Body.String(), w.Body.String())
+			}
+		})
+	}
+}
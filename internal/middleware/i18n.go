@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"context"
+
+	"go-springAi/internal/dto"
 	"go-springAi/internal/i18n"
 
 	"github.com/gin-gonic/gin"
@@ -11,31 +14,64 @@ const (
 	LanguageContextKey = "language"
 	// I18nManagerContextKey 国际化管理器上下文键
 	I18nManagerContextKey = "i18n_manager"
+	// localePreferencesContextKey 用户偏好查询接口上下文键
+	localePreferencesContextKey = "i18n_locale_preferences"
 )
 
-// I18nMiddleware 国际化中间件
-func I18nMiddleware(manager *i18n.Manager) gin.HandlerFunc {
+// LocalePreferenceLookup 查询用户偏好设置的接口，认证后用于按用户偏好语言协商
+type LocalePreferenceLookup interface {
+	Get(ctx context.Context, userID int64) (*dto.UserPreferences, error)
+}
+
+// I18nMiddleware 国际化中间件，按查询参数 > 用户偏好 > Accept-Language > Cookie > 默认语言协商语言，
+// 用户偏好在认证中间件识别出用户后应用，preferences 为空时跳过
+func I18nMiddleware(manager *i18n.Manager, preferences LocalePreferenceLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 获取请求语言
-		lang := manager.GetLanguageFromContext(c)
-		
-		// 设置语言到上下文
-		c.Set(LanguageContextKey, lang)
 		c.Set(I18nManagerContextKey, manager)
-		
-		// 创建翻译函数并添加到上下文
+		if preferences != nil {
+			c.Set(localePreferencesContextKey, preferences)
+		}
+
+		// 获取请求语言并设置到上下文
+		setLanguage(c, manager.GetLanguageFromContext(c))
+
+		// 创建翻译函数并添加到上下文，语言在认证后可能被用户偏好覆盖，因此每次调用时读取
 		translateFunc := func(messageID string, templateData map[string]interface{}) string {
-			return manager.T(lang, messageID, templateData)
+			return manager.T(GetLanguageFromContext(c), messageID, templateData)
 		}
 		c.Set("i18n_translate", translateFunc)
-		
-		// 设置响应头
-		c.Header("Content-Language", lang)
-		
+
+		// 响应内容随 Accept-Language 变化，供缓存区分
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
 		c.Next()
 	}
 }
 
+// setLanguage 设置请求语言到Gin上下文、请求上下文和响应头
+func setLanguage(c *gin.Context, lang string) {
+	c.Set(LanguageContextKey, lang)
+	c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+	c.Header("Content-Language", lang)
+}
+
+// applyUserLocale 认证成功后使用用户偏好语言，显式的 lang 查询参数优先，读取失败时保留已协商的语言
+func applyUserLocale(c *gin.Context, userID int64) {
+	manager := GetI18nManagerFromContext(c)
+	preferences, ok := c.Value(localePreferencesContextKey).(LocalePreferenceLookup)
+	if manager == nil || !ok || manager.NormalizeLanguage(c.Query("lang")) != "" {
+		return
+	}
+
+	prefs, err := preferences.Get(c.Request.Context(), userID)
+	if err != nil || prefs == nil {
+		return
+	}
+	if lang := manager.NormalizeLanguage(prefs.Locale); lang != "" {
+		setLanguage(c, lang)
+	}
+}
+
 // GetLanguageFromContext 从上下文获取语言
 func GetLanguageFromContext(c *gin.Context) string {
 	if lang, exists := c.Get(LanguageContextKey); exists {
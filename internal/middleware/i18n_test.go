@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
+	"go-springAi/internal/response"
+	"go-springAi/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubLocalePreferences map[int64]string
+
+func (s stubLocalePreferences) Get(ctx context.Context, userID int64) (*dto.UserPreferences, error) {
+	return &dto.UserPreferences{Locale: s[userID]}, nil
+}
+
+func TestI18nMiddlewareUserLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager, err := i18n.NewManager("en", []string{"en", "zh", "ja", "es", "de"})
+	require.NoError(t, err)
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+	germanUser, err := jwtManager.GenerateToken(1, "hans")
+	require.NoError(t, err)
+	noPreferenceUser, err := jwtManager.GenerateToken(2, "alex")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		acceptLanguage string
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{name: "Anonymous uses Accept-Language", path: "/public", acceptLanguage: "ja,en;q=0.5", wantLanguage: "ja", wantMessage: "モデルを取得しました"},
+		{name: "User preference overrides Accept-Language", path: "/private", token: germanUser, acceptLanguage: "es", wantLanguage: "de", wantMessage: "Modelle erfolgreich abgerufen"},
+		{name: "Query parameter overrides user preference", path: "/private?lang=es", token: germanUser, wantLanguage: "es", wantMessage: "Modelos obtenidos correctamente"},
+		{name: "No preference keeps Accept-Language", path: "/private", token: noPreferenceUser, acceptLanguage: "zh-CN", wantLanguage: "zh", wantMessage: "模型列表获取成功"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(I18nMiddleware(manager, stubLocalePreferences{1: "de"}))
+			handler := func(c *gin.Context) {
+				response.I18nSuccess(c, http.StatusOK, "response.models.retrieved", nil, nil)
+			}
+			r.GET("/public", handler)
+			r.GET("/private", AuthMiddleware(jwtManager, nil, zap.NewNop()), handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantLanguage, w.Header().Get("Content-Language"))
+			var body response.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantMessage, body.Message)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader 客户端传入的幂等键请求头
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader 标记响应为重放结果
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength 幂等键最大长度
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL 首个请求处理中时占用幂等键的时长，进程异常退出后到期自动释放
+	idempotencyLockTTL = 10 * time.Minute
+	// maxIdempotentResponseBytes 超过该大小的响应不缓存
+	maxIdempotentResponseBytes = 1 << 20
+)
+
+// IdempotencyOptions 幂等键选项，Store 为空表示未启用
+type IdempotencyOptions struct {
+	Store idempotency.Store
+	TTL   time.Duration // 响应缓存时长
+}
+
+// Idempotency 按 Idempotency-Key 请求头缓存并重放首个响应，需在认证中间件之后使用，
+// 幂等键按用户（未登录时按IP）、方法和路径隔离
+func Idempotency(opts IdempotencyOptions, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if opts.Store == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			AbortWithAppError(c, errors.NewBadRequestError("Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			if maxBytes, ok := c.Get(errors.BodyTooLargeKey); ok {
+				AbortWithAppError(c, errors.NewRequestTooLargeError(maxBytes.(int64)))
+				return
+			}
+			AbortWithAppError(c, errors.NewBadRequestError("Failed to read request body").WithCause(err))
+			return
+		}
+
+		// 客户端断开后仍需保存或释放记录，不跟随请求取消
+		ctx := context.WithoutCancel(c.Request.Context())
+		storeKey := idempotencyScope(c) + ":" + c.Request.Method + " " + c.Request.URL.Path + ":" + key
+
+		existing, err := opts.Store.Begin(ctx, storeKey, fingerprint, idempotencyLockTTL)
+		if err != nil {
+			zapLogger.Warn("Idempotency check failed, processing request",
+				zap.String("module", "idempotency"),
+				zap.String("component", "middleware"),
+				zap.Error(err))
+			c.Next()
+			return
+		}
+		if existing != nil {
+			replayIdempotent(c, existing, fingerprint)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			if completed {
+				return
+			}
+			// 处理器panic或响应不可缓存时释放幂等键，允许重试
+			if err := opts.Store.Release(ctx, storeKey); err != nil {
+				zapLogger.Warn("Failed to release idempotency key", zap.String("module", "idempotency"), zap.Error(err))
+			}
+		}()
+
+		c.Next()
+
+		// 错误交给外层中间件写出的响应和过大的响应不缓存
+		if !writer.Written() || len(c.Errors) > 0 || writer.overflow || !isReplayableStatus(writer.Status()) {
+			return
+		}
+		record := idempotency.Record{
+			Fingerprint: fingerprint,
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := opts.Store.Complete(ctx, storeKey, record, opts.TTL); err != nil {
+			zapLogger.Warn("Failed to save idempotent response", zap.String("module", "idempotency"), zap.Error(err))
+			return
+		}
+		completed = true
+	}
+}
+
+// replayIdempotent 重放已保存的响应，首个请求仍在处理或请求内容不同时返回错误
+func replayIdempotent(c *gin.Context, record *idempotency.Record, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		AbortWithAppError(c, errors.NewAppError(errors.ErrCodeInvalidValue,
+			"Idempotency-Key has already been used for a different request",
+			errors.SeverityLow, http.StatusUnprocessableEntity))
+	case !record.Completed:
+		AbortWithAppError(c, errors.NewConflictError("A request with this Idempotency-Key is still being processed"))
+	default:
+		c.Header(idempotentReplayedHeader, "true")
+		c.Data(record.Status, record.ContentType, record.Body)
+		c.Abort()
+	}
+}
+
+// requestFingerprint 计算请求方法、路径、查询参数和请求体的摘要，并恢复请求体
+func requestFingerprint(c *gin.Context) (string, error) {
+	hash := sha256.New()
+	io.WriteString(hash, c.Request.Method+" "+c.Request.URL.RequestURI()+"\n")
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// idempotencyScope 幂等键所属的主体
+func idempotencyScope(c *gin.Context) string {
+	if userID := getUserID(c); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// isReplayableStatus 服务端错误、限流和客户端断开的响应不缓存，客户端可以使用同一个键重试
+func isReplayableStatus(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusTooManyRequests &&
+		status != errors.StatusClientClosedRequest
+}
+
+// idempotencyWriter 记录响应体用于重放
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *idempotencyWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > maxIdempotentResponseBytes {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
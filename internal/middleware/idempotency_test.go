@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-springAi/internal/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		key  string
+		body string
+	}
+	tests := []struct {
+		name         string
+		failStatus   int // 首次请求返回的状态码，0 表示成功
+		requests     []request
+		wantStatus   int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		{name: "Retry replays first response", requests: []request{{"k1", `{"a":1}`}, {"k1", `{"a":1}`}}, wantStatus: http.StatusOK, wantCalls: 1, wantReplayed: true},
+		{name: "Different keys execute twice", requests: []request{{"k1", `{"a":1}`}, {"k2", `{"a":1}`}}, wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "No key executes twice", requests: []request{{"", `{"a":1}`}, {"", `{"a":1}`}}, wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "Key reused with different body", requests: []request{{"k1", `{"a":1}`}, {"k1", `{"a":2}`}}, wantStatus: http.StatusUnprocessableEntity, wantCalls: 1},
+		{name: "Server error is not cached", failStatus: http.StatusBadGateway, requests: []request{{"k1", `{"a":1}`}, {"k1", `{"a":1}`}}, wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "Client error is replayed", failStatus: http.StatusBadRequest, requests: []request{{"k1", `{"a":1}`}, {"k1", `{"a":1}`}}, wantStatus: http.StatusBadRequest, wantCalls: 1, wantReplayed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := gin.New()
+			r.Use(ErrorHandler(zap.NewNop()))
+			r.POST("/execute", Idempotency(IdempotencyOptions{Store: idempotency.NewMemoryStore(), TTL: time.Hour}, zap.NewNop()), func(c *gin.Context) {
+				calls++
+				if calls == 1 && tt.failStatus != 0 {
+					c.JSON(tt.failStatus, gin.H{"error": "failed"})
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"call": calls})
+			})
+
+			var w *httptest.ResponseRecorder
+			for _, req := range tt.requests {
+				httpReq := httptest.NewRequest(http.MethodPost, "/execute", strings.NewReader(req.body))
+				if req.key != "" {
+					httpReq.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				w = httptest.NewRecorder()
+				r.ServeHTTP(w, httpReq)
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantReplayed, w.Header().Get(idempotentReplayedHeader) == "true")
+		})
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started, release := make(chan struct{}), make(chan struct{})
+
+	r := gin.New()
+	r.Use(ErrorHandler(zap.NewNop()))
+	r.POST("/execute", Idempotency(IdempotencyOptions{Store: idempotency.NewMemoryStore(), TTL: time.Hour}, zap.NewNop()), func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/execute", strings.NewReader("{}"))
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- send() }()
+	<-started
+
+	assert.Equal(t, http.StatusConflict, send().Code)
+	close(release)
+	assert.Equal(t, http.StatusOK, (<-first).Code)
+}
@@ -5,21 +5,34 @@ import (
 	"time"
 
 	"go-springAi/internal/logger"
+	"go-springAi/internal/requestid"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-// ZapLogger 基于zap的结构化日志中间件
-func ZapLogger(zapLogger *zap.Logger) gin.HandlerFunc {
+// ZapLogger 基于zap的结构化访问日志中间件，每个请求记录一条日志，查询参数和请求/响应体中的敏感字段自动脱敏
+func ZapLogger(zapLogger *zap.Logger, opts AccessLogOptions) gin.HandlerFunc {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
+		raw := redactQuery(c.Request.URL.RawQuery)
 
 		// 记录请求开始
 		logRequestStart(c, start)
 
+		var requestBody []byte
+		var writer *bodyCaptureWriter
+		if opts.LogBodies {
+			requestBody = captureRequestBody(c, opts.MaxBodyBytes)
+			writer = &bodyCaptureWriter{ResponseWriter: c.Writer, limit: opts.MaxBodyBytes}
+			c.Writer = writer
+		}
+
 		// 处理请求
 		c.Next()
 
@@ -48,22 +61,23 @@ func ZapLogger(zapLogger *zap.Logger) gin.HandlerFunc {
 		securityFields := buildSecurityFields(c)
 		baseFields = append(baseFields, securityFields...)
 
+		if opts.LogBodies {
+			baseFields = append(baseFields, buildBodyFields(c, requestBody, writer, opts.MaxBodyBytes)...)
+		}
+
 		// 根据状态码选择日志级别和消息
 		ctx := c.Request.Context()
 		logMessage := getResponseLogMessage(statusCode, latency)
 
+		accessLog := logger.AccessLogger()
+
 		switch {
 		case statusCode >= 500:
-			logger.ErrorCtx(ctx, logMessage, baseFields...)
+			accessLog.ErrorCtx(ctx, logMessage, baseFields...)
 		case statusCode >= 400:
-			logger.WarnCtx(ctx, logMessage, baseFields...)
-		case statusCode >= 300:
-			logger.InfoCtx(ctx, logMessage, baseFields...)
+			accessLog.WarnCtx(ctx, logMessage, baseFields...)
 		default:
-			// 只有在调试模式或慢请求时记录成功请求
-			if shouldLogSuccessRequest(latency) {
-				logger.InfoCtx(ctx, logMessage, baseFields...)
-			}
+			accessLog.InfoCtx(ctx, logMessage, baseFields...)
 		}
 
 		// 记录慢请求
@@ -76,13 +90,12 @@ func logRequestStart(c *gin.Context, start time.Time) {
 	// 只在调试模式下记录请求开始
 	if gin.Mode() == gin.DebugMode {
 		ctx := c.Request.Context()
-		logger.DebugCtx(ctx, "Request started",
+		logger.AccessLogger().DebugCtx(ctx, "Request started",
 			logger.Module(logger.ModuleMiddleware),
 			logger.Component("http"),
 			logger.String("method", c.Request.Method),
 			logger.String("path", c.Request.URL.Path),
 			logger.String("client_ip", c.ClientIP()),
-			logger.RequestID(getRequestID(c)),
 			logger.String("user_agent", c.Request.UserAgent()),
 			logger.Time("start_time", start))
 	}
@@ -100,7 +113,6 @@ func buildBaseLogFields(c *gin.Context, method, path string, statusCode int, lat
 		logger.String("client_ip", clientIP),
 		logger.String("user_agent", userAgent),
 		logger.Int("body_size", bodySize),
-		logger.RequestID(getRequestID(c)),
 	}
 
 	// 添加追踪ID
@@ -186,16 +198,6 @@ func getResponseLogMessage(statusCode int, latency time.Duration) string {
 	}
 }
 
-// shouldLogSuccessRequest 判断是否应该记录成功请求
-func shouldLogSuccessRequest(latency time.Duration) bool {
-	// 在调试模式下记录所有请求
-	if gin.Mode() == gin.DebugMode {
-		return true
-	}
-	// 在生产模式下只记录慢请求
-	return latency > 500*time.Millisecond
-}
-
 // logSlowRequest 记录慢请求
 func logSlowRequest(ctx context.Context, c *gin.Context, latency time.Duration, baseFields []logger.LogField) {
 	slowThreshold := time.Second
@@ -290,35 +292,20 @@ func checkSuspiciousActivity(c *gin.Context) bool {
 	return false
 }
 
-// RequestID 请求ID中间件
+// RequestID 请求ID中间件，沿用客户端传入的合法 X-Request-ID，否则生成新ID，
+// 写入响应头和请求上下文，日志、错误响应和下游HTTP调用从上下文读取
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = generateRequestID()
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
 		}
-		c.Header("X-Request-ID", requestID)
-		c.Set("request_id", requestID)
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), id))
 		c.Next()
 	}
 }
 
-// generateRequestID 生成请求ID
-func generateRequestID() string {
-	// 简单的时间戳+随机数生成请求ID
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
-}
-
-// randomString 生成随机字符串
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-	}
-	return string(b)
-}
-
 // StructuredLogger 结构化日志记录器
 type StructuredLogger struct {
 	logger *zap.Logger
@@ -338,7 +325,7 @@ func (sl *StructuredLogger) LogRequest(c *gin.Context, fields ...zap.Field) {
 		zap.String("user_agent", c.Request.UserAgent()),
 	}
 
-	if requestID := c.GetString("request_id"); requestID != "" {
+	if requestID := getRequestID(c); requestID != "" {
 		baseFields = append(baseFields, zap.String("request_id", requestID))
 	}
 
@@ -357,7 +344,7 @@ func (sl *StructuredLogger) LogResponse(c *gin.Context, latency time.Duration, f
 		zap.Int("body_size", c.Writer.Size()),
 	}
 
-	if requestID := c.GetString("request_id"); requestID != "" {
+	if requestID := getRequestID(c); requestID != "" {
 		baseFields = append(baseFields, zap.String("request_id", requestID))
 	}
 
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-springAi/internal/ratelimit"
+	"go-springAi/internal/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimit 全局和按客户端IP限流，limiter 为空表示未启用
+func RateLimit(limiter *ratelimit.Limiter, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		rules := limiter.Rules()
+		if !takeToken(c, limiter, "global", rules.Global, zapLogger) ||
+			!takeToken(c, limiter, "ip:"+c.ClientIP(), rules.PerIP, zapLogger) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitGroup 按登录用户和路由分组限流，需在认证中间件之后使用；未登录请求的分组限流按客户端IP计算
+func RateLimitGroup(limiter *ratelimit.Limiter, group string, zapLogger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		rules := limiter.Rules()
+		subject := "ip:" + c.ClientIP()
+		if userID := getUserID(c); userID != "" {
+			subject = "user:" + userID
+			if !takeToken(c, limiter, subject, rules.PerUser, zapLogger) {
+				return
+			}
+		}
+		if !takeToken(c, limiter, "group:"+group+":"+subject, rules.Groups[group], zapLogger) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// takeToken 从 key 对应的桶中取令牌，被限流时返回429并中止请求；存储不可用时放行
+func takeToken(c *gin.Context, limiter *ratelimit.Limiter, key string, rule ratelimit.Rule, zapLogger *zap.Logger) bool {
+	result, err := limiter.Take(c.Request.Context(), key, rule)
+	if err != nil {
+		zapLogger.Warn("Rate limit check failed, allowing request",
+			zap.String("module", "ratelimit"),
+			zap.String("component", "middleware"),
+			zap.String("key", key),
+			zap.Error(err))
+		return true
+	}
+	if !rule.Enabled() {
+		return true
+	}
+
+	SetRateLimitHeaders(c, int64(result.Limit), int64(result.Remaining), time.Now().Add(result.Reset))
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	response.Error(c, http.StatusTooManyRequests, "Rate limit exceeded", "retry after "+strconv.Itoa(retryAfter)+"s")
+	c.Abort()
+	return false
+}
+
+// SetRateLimitHeaders 设置 X-RateLimit-Limit/Remaining/Reset 响应头，Reset 为Unix时间戳（秒），
+// 同一请求受多个限流或配额约束时保留剩余量最少的一项
+func SetRateLimitHeaders(c *gin.Context, limit, remaining int64, reset time.Time) {
+	if current, err := strconv.ParseInt(c.Writer.Header().Get("X-RateLimit-Remaining"), 10, 64); err == nil && current < remaining {
+		return
+	}
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	resetAt := reset.Unix()
+	if reset.Nanosecond() > 0 {
+		resetAt++
+	}
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt, 10))
+}
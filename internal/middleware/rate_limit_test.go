@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		rules      ratelimit.Rules
+		userID     string
+		requests   int
+		wantStatus int
+	}{
+		{name: "Disabled rules allow all", rules: ratelimit.Rules{}, requests: 5, wantStatus: http.StatusOK},
+		{name: "Per IP limit", rules: ratelimit.Rules{PerIP: ratelimit.Rule{Rate: 1, Burst: 2}}, requests: 3, wantStatus: http.StatusTooManyRequests},
+		{name: "Per user limit", rules: ratelimit.Rules{PerUser: ratelimit.Rule{Rate: 1, Burst: 1}}, userID: "7", requests: 2, wantStatus: http.StatusTooManyRequests},
+		{name: "Per user limit skips anonymous", rules: ratelimit.Rules{PerUser: ratelimit.Rule{Rate: 1, Burst: 1}}, requests: 2, wantStatus: http.StatusOK},
+		{name: "Group limit", rules: ratelimit.Rules{Groups: map[string]ratelimit.Rule{"ai": {Rate: 1, Burst: 1}}}, requests: 2, wantStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), tt.rules)
+			r := gin.New()
+			r.Use(RateLimit(limiter, zap.NewNop()))
+			r.GET("/ai", func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set("user_id", tt.userID)
+				}
+			}, RateLimitGroup(limiter, "ai", zap.NewNop()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			var w *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				w = httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ai", nil))
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "1", w.Header().Get("Retry-After"))
+				assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+				assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+			}
+		})
+	}
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reset := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		limits        [][2]int64 // 依次设置的 {limit, remaining}
+		wantLimit     string
+		wantRemaining string
+	}{
+		{name: "Single limit", limits: [][2]int64{{10, 7}}, wantLimit: "10", wantRemaining: "7"},
+		{name: "Tighter limit wins", limits: [][2]int64{{40, 30}, {20, 3}}, wantLimit: "20", wantRemaining: "3"},
+		{name: "Looser limit ignored", limits: [][2]int64{{20, 3}, {40, 30}}, wantLimit: "20", wantRemaining: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			for _, l := range tt.limits {
+				SetRateLimitHeaders(c, l[0], l[1], reset)
+			}
+
+			assert.Equal(t, tt.wantLimit, w.Header().Get("X-RateLimit-Limit"))
+			assert.Equal(t, tt.wantRemaining, w.Header().Get("X-RateLimit-Remaining"))
+			assert.Equal(t, "1700000000", w.Header().Get("X-RateLimit-Reset"))
+		})
+	}
+}
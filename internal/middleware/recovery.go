@@ -3,15 +3,18 @@ package middleware
 import (
 	"net/http"
 
+	"go-springAi/internal/errreport"
 	"go-springAi/internal/response"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Recovery 自定义错误恢复中间件
+// Recovery 自定义错误恢复中间件，panic会上报到错误追踪服务，发布模式下不返回panic内容
 func Recovery() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(string); ok {
+		errreport.ReportPanic(c, recovered)
+
+		if err, ok := recovered.(string); ok && gin.Mode() != gin.ReleaseMode {
 			response.InternalServerError(c, "Internal Server Error", err)
 		} else {
 			response.InternalServerError(c, "Internal Server Error", "Unknown error")
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-springAi/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PathTimeout 路径前缀下的请求处理时限
+type PathTimeout struct {
+	Prefix  string
+	Timeout time.Duration
+}
+
+// TimeoutOptions 请求处理时限选项，按最长路径前缀匹配，未匹配的请求使用 Default，时限不大于0表示不限制
+type TimeoutOptions struct {
+	Default time.Duration
+	Paths   []PathTimeout
+}
+
+// timeoutFor 获取路径对应的处理时限
+func (o TimeoutOptions) timeoutFor(path string) time.Duration {
+	timeout, matched := o.Default, ""
+	for _, p := range o.Paths {
+		if strings.HasPrefix(path, p.Prefix) && len(p.Prefix) > len(matched) {
+			timeout, matched = p.Timeout, p.Prefix
+		}
+	}
+	return timeout
+}
+
+// Timeout 为请求上下文设置处理时限，超时后模型调用、工具执行和数据库查询随上下文取消，
+// 处理器返回的错误由错误处理器转换为504；处理器未写出响应时由本中间件返回504
+func Timeout(opts TimeoutOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := opts.timeoutFor(c.Request.URL.Path)
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		parent := c.Request.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(errors.RequestTimeoutKey, timeout)
+
+		c.Next()
+
+		// 恢复原上下文，外层的错误处理中间件只在客户端断开时看到上下文取消
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		c.Request = c.Request.WithContext(parent)
+		if timedOut && !c.Writer.Written() {
+			AbortWithAppError(c, errors.NewRequestTimeoutError(timeout))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-springAi/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	opts := TimeoutOptions{
+		Default: 20 * time.Millisecond,
+		Paths: []PathTimeout{
+			{Prefix: "/api/v1/assistant", Timeout: time.Second},
+			{Prefix: "/api/v1/mcp/sse"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		wait         bool // 处理器等待上下文结束并返回其错误
+		notFound     bool // 处理器返回业务错误
+		wantStatus   int
+		wantDeadline bool
+	}{
+		{name: "Fast handler", path: "/api/projects", wantStatus: http.StatusOK, wantDeadline: true},
+		{name: "Handler exceeds default", path: "/api/projects", wait: true, wantStatus: http.StatusGatewayTimeout, wantDeadline: true},
+		{name: "Handler error keeps its status", path: "/api/projects", notFound: true, wantStatus: http.StatusNotFound, wantDeadline: true},
+		{name: "Longer budget for path", path: "/api/v1/assistant/chat", wantStatus: http.StatusOK, wantDeadline: true},
+		{name: "Exempt path", path: "/api/v1/mcp/sse", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasDeadline := false
+			r := gin.New()
+			r.Use(ErrorHandler(zap.NewNop()), Timeout(opts))
+			r.GET("/*path", func(c *gin.Context) {
+				ctx := c.Request.Context()
+				_, hasDeadline = ctx.Deadline()
+				if tt.wait {
+					<-ctx.Done()
+					c.Error(ctx.Err())
+					return
+				}
+				if tt.notFound {
+					c.Error(errors.NewNotFoundError("project"))
+					return
+				}
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantDeadline, hasDeadline)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 
+	"go-springAi/internal/errors"
 	"go-springAi/internal/response"
 	"go-springAi/internal/utils"
 
@@ -24,6 +25,10 @@ func ValidateJSONFactory(objType interface{}) gin.HandlerFunc {
 		obj := createNewInstance(objType)
 
 		if err := c.ShouldBindJSON(obj); err != nil {
+			if maxBytes, ok := c.Get(errors.BodyTooLargeKey); ok {
+				AbortWithAppError(c, errors.NewRequestTooLargeError(maxBytes.(int64)))
+				return
+			}
 			if validationErrors, ok := err.(validator.ValidationErrors); ok {
 				errorMessages := formatValidationErrors(validationErrors)
 				response.BadRequest(c, "Validation failed", strings.Join(errorMessages, "; "))
@@ -66,8 +71,9 @@ type CustomValidationError struct {
 
 // ValidationErrorResponse 验证错误响应
 type ValidationErrorResponse struct {
-	Message string                  `json:"message"`
-	Errors  []CustomValidationError `json:"errors"`
+	Message   string                  `json:"message"`
+	Errors    []CustomValidationError `json:"errors"`
+	RequestID string                  `json:"request_id,omitempty"`
 }
 
 // HandleValidationError 处理验证错误的通用函数
@@ -84,8 +90,9 @@ func HandleValidationError(c *gin.Context, err error) {
 		}
 
 		response := ValidationErrorResponse{
-			Message: "Validation failed",
-			Errors:  errors,
+			Message:   "Validation failed",
+			Errors:    errors,
+			RequestID: getRequestID(c),
 		}
 
 		c.JSON(http.StatusBadRequest, response)
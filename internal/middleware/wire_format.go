@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"go-springAi/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WireFormat 按 Accept 协商成功响应的编码，客户端明确要求 application/msgpack 时使用 MessagePack，
+// 否则使用 JSON。用于数据量大的端点，错误响应始终为 JSON
+func WireFormat() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 响应体随 Accept 变化，供缓存区分
+		c.Writer.Header().Add("Vary", "Accept")
+		if prefersMsgPack(c.GetHeader("Accept")) {
+			response.UseMsgPack(c)
+		}
+		c.Next()
+	}
+}
+
+// prefersMsgPack 判断 Accept 是否优先选择 MessagePack。只有明确列出的 MessagePack 才会被选中，
+// 权重与明确列出的 application/json 相同时选择 JSON；*/* 等通配不参与比较
+func prefersMsgPack(accept string) bool {
+	msgpackQ, jsonQ := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if v, err := strconv.ParseFloat(value, 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case response.MIMEMsgPack, "application/x-msgpack":
+			msgpackQ = max(msgpackQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return msgpackQ > 0 && msgpackQ > jsonQ
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-springAi/internal/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+func TestPrefersMsgPack(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "*/*", want: false},
+		{accept: "application/json", want: false},
+		{accept: "application/msgpack", want: true},
+		{accept: "application/x-msgpack", want: true},
+		{accept: "application/msgpack, */*;q=0.8", want: true},
+		{accept: "application/json;q=0.5, application/msgpack", want: true},
+		{accept: "application/json, application/msgpack", want: false},
+		{accept: "application/msgpack;q=0.5, application/json", want: false},
+		{accept: "application/msgpack;q=0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, prefersMsgPack(tt.accept))
+		})
+	}
+}
+
+func TestWireFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type quote struct {
+		Symbol    string    `json:"symbol"`
+		Price     float64   `json:"price"`
+		Volume    int64     `json:"volume"`
+		Exchange  string    `json:"exchange,omitempty"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	data := gin.H{"quotes": []quote{{Symbol: "AAPL", Price: 189.5, Volume: 51234567, UpdatedAt: time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)}}}
+
+	r := gin.New()
+	r.GET("/quotes", WireFormat(), func(c *gin.Context) { response.Success(c, http.StatusOK, "ok", data) })
+
+	jsonResp := httptest.NewRecorder()
+	r.ServeHTTP(jsonResp, httptest.NewRequest(http.MethodGet, "/quotes", nil))
+	require.Equal(t, http.StatusOK, jsonResp.Code)
+	assert.Equal(t, "application/json; charset=utf-8", jsonResp.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", jsonResp.Header().Get("Vary"))
+
+	req := httptest.NewRequest(http.MethodGet, "/quotes", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	msgpackResp := httptest.NewRecorder()
+	r.ServeHTTP(msgpackResp, req)
+	require.Equal(t, http.StatusOK, msgpackResp.Code)
+	assert.Equal(t, response.MIMEMsgPack, msgpackResp.Header().Get("Content-Type"))
+	assert.Less(t, msgpackResp.Body.Len(), jsonResp.Body.Len())
+
+	// 解码后的文档与 JSON 响应一致
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+	handle.MapType = reflect.TypeOf(map[string]interface{}{})
+	var decoded map[string]interface{}
+	require.NoError(t, codec.NewDecoderBytes(msgpackResp.Body.Bytes(), handle).Decode(&decoded))
+	var expected map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonResp.Body.Bytes(), &expected))
+
+	item := decoded["data"].(map[string]interface{})["quotes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "AAPL", item["symbol"])
+	assert.EqualValues(t, 51234567, item["volume"])
+	assert.Equal(t, 189.5, item["price"])
+	assert.Equal(t, "2026-03-04T15:00:00Z", item["updated_at"])
+	assert.NotContains(t, item, "exchange")
+	assert.Equal(t, expected["message"], decoded["message"])
+	assert.EqualValues(t, expected["code"], decoded["code"])
+
+	// 相同数据的编码结果一致
+	again := httptest.NewRecorder()
+	r.ServeHTTP(again, req)
+	assert.Equal(t, msgpackResp.Body.Bytes(), again.Body.Bytes())
+}
@@ -14,6 +14,7 @@ import (
 	repository "go-springAi/internal/repository"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,21 @@ func (m *MockUserReader) EXPECT() *MockUserReaderMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockUserReader) Count(ctx context.Context, filter *repository.UserFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserReaderMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserReader)(nil).Count), ctx, filter)
+}
+
 // GetByEmail mocks base method.
 func (m *MockUserReader) GetByEmail(ctx context.Context, email string) (*dto.UserResponse, error) {
 	m.ctrl.T.Helper()
@@ -88,18 +104,33 @@ func (mr *MockUserReaderMockRecorder) GetByUsername(ctx, username any) *gomock.C
 }
 
 // List mocks base method.
-func (m *MockUserReader) List(ctx context.Context, params *repository.PaginationParams) ([]*dto.UserResponse, error) {
+func (m *MockUserReader) List(ctx context.Context, params *repository.PaginationParams, filter *repository.UserFilter) ([]*dto.UserResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret := m.ctrl.Call(m, "List", ctx, params, filter)
 	ret0, _ := ret[0].([]*dto.UserResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserReaderMockRecorder) List(ctx, params any) *gomock.Call {
+func (mr *MockUserReaderMockRecorder) List(ctx, params, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserReader)(nil).List), ctx, params, filter)
+}
+
+// ListAfter mocks base method.
+func (m *MockUserReader) ListAfter(ctx context.Context, filter *repository.UserFilter, after *repository.UserSortKey, limit int64) ([]*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockUserReaderMockRecorder) ListAfter(ctx, filter, after, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserReader)(nil).List), ctx, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockUserReader)(nil).ListAfter), ctx, filter, after, limit)
 }
 
 // MockUserWriter is a mock of UserWriter interface.
@@ -155,6 +186,36 @@ func (mr *MockUserWriterMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserWriter)(nil).Delete), ctx, id)
 }
 
+// PurgeDeleted mocks base method.
+func (m *MockUserWriter) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockUserWriterMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockUserWriter)(nil).PurgeDeleted), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockUserWriter) Restore(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserWriterMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserWriter)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockUserWriter) Update(ctx context.Context, id int64, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +285,21 @@ func (mr *MockUserValidatorMockRecorder) ExistsByUsername(ctx, username any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByUsername", reflect.TypeOf((*MockUserValidator)(nil).ExistsByUsername), ctx, username)
 }
 
+// VerifyPassword mocks base method.
+func (m *MockUserValidator) VerifyPassword(ctx context.Context, username, password string) (*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", ctx, username, password)
+	ret0, _ := ret[0].(*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockUserValidatorMockRecorder) VerifyPassword(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockUserValidator)(nil).VerifyPassword), ctx, username, password)
+}
+
 // MockUserRepository is a mock of UserRepository interface.
 type MockUserRepository struct {
 	ctrl     *gomock.Controller
@@ -248,6 +324,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockUserRepository) Count(ctx context.Context, filter *repository.UserFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepository)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
 	m.ctrl.T.Helper()
@@ -353,18 +444,63 @@ func (mr *MockUserRepositoryMockRecorder) GetByUsername(ctx, username any) *gomo
 }
 
 // List mocks base method.
-func (m *MockUserRepository) List(ctx context.Context, params *repository.PaginationParams) ([]*dto.UserResponse, error) {
+func (m *MockUserRepository) List(ctx context.Context, params *repository.PaginationParams, filter *repository.UserFilter) ([]*dto.UserResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret := m.ctrl.Call(m, "List", ctx, params, filter)
 	ret0, _ := ret[0].([]*dto.UserResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) List(ctx, params, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, params, filter)
+}
+
+// ListAfter mocks base method.
+func (m *MockUserRepository) ListAfter(ctx context.Context, filter *repository.UserFilter, after *repository.UserSortKey, limit int64) ([]*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockUserRepositoryMockRecorder) ListAfter(ctx, filter, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockUserRepository)(nil).ListAfter), ctx, filter, after, limit)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockUserRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockUserRepositoryMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockUserRepository)(nil).PurgeDeleted), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockUserRepository) Restore(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserRepository)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
@@ -382,6 +518,21 @@ func (mr *MockUserRepositoryMockRecorder) Update(ctx, id, req any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, id, req)
 }
 
+// VerifyPassword mocks base method.
+func (m *MockUserRepository) VerifyPassword(ctx context.Context, username, password string) (*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", ctx, username, password)
+	ret0, _ := ret[0].(*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockUserRepositoryMockRecorder) VerifyPassword(ctx, username, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockUserRepository)(nil).VerifyPassword), ctx, username, password)
+}
+
 // MockRepositoryManager is a mock of RepositoryManager interface.
 type MockRepositoryManager struct {
 	ctrl     *gomock.Controller
@@ -406,6 +557,62 @@ func (m *MockRepositoryManager) EXPECT() *MockRepositoryManagerMockRecorder {
 	return m.recorder
 }
 
+// AISpend mocks base method.
+func (m *MockRepositoryManager) AISpend() repository.AISpendRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AISpend")
+	ret0, _ := ret[0].(repository.AISpendRepository)
+	return ret0
+}
+
+// AISpend indicates an expected call of AISpend.
+func (mr *MockRepositoryManagerMockRecorder) AISpend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AISpend", reflect.TypeOf((*MockRepositoryManager)(nil).AISpend))
+}
+
+// AIUsage mocks base method.
+func (m *MockRepositoryManager) AIUsage() repository.AIUsageRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AIUsage")
+	ret0, _ := ret[0].(repository.AIUsageRepository)
+	return ret0
+}
+
+// AIUsage indicates an expected call of AIUsage.
+func (mr *MockRepositoryManagerMockRecorder) AIUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AIUsage", reflect.TypeOf((*MockRepositoryManager)(nil).AIUsage))
+}
+
+// APIKey mocks base method.
+func (m *MockRepositoryManager) APIKey() repository.APIKeyRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKey")
+	ret0, _ := ret[0].(repository.APIKeyRepository)
+	return ret0
+}
+
+// APIKey indicates an expected call of APIKey.
+func (mr *MockRepositoryManagerMockRecorder) APIKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKey", reflect.TypeOf((*MockRepositoryManager)(nil).APIKey))
+}
+
+// APIToken mocks base method.
+func (m *MockRepositoryManager) APIToken() repository.APITokenRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIToken")
+	ret0, _ := ret[0].(repository.APITokenRepository)
+	return ret0
+}
+
+// APIToken indicates an expected call of APIToken.
+func (mr *MockRepositoryManagerMockRecorder) APIToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIToken", reflect.TypeOf((*MockRepositoryManager)(nil).APIToken))
+}
+
 // Close mocks base method.
 func (m *MockRepositoryManager) Close() error {
 	m.ctrl.T.Helper()
@@ -420,6 +627,118 @@ func (mr *MockRepositoryManagerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepositoryManager)(nil).Close))
 }
 
+// Eval mocks base method.
+func (m *MockRepositoryManager) Eval() repository.EvalRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Eval")
+	ret0, _ := ret[0].(repository.EvalRepository)
+	return ret0
+}
+
+// Eval indicates an expected call of Eval.
+func (mr *MockRepositoryManagerMockRecorder) Eval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Eval", reflect.TypeOf((*MockRepositoryManager)(nil).Eval))
+}
+
+// Experiment mocks base method.
+func (m *MockRepositoryManager) Experiment() repository.ExperimentRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Experiment")
+	ret0, _ := ret[0].(repository.ExperimentRepository)
+	return ret0
+}
+
+// Experiment indicates an expected call of Experiment.
+func (mr *MockRepositoryManagerMockRecorder) Experiment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Experiment", reflect.TypeOf((*MockRepositoryManager)(nil).Experiment))
+}
+
+// Feedback mocks base method.
+func (m *MockRepositoryManager) Feedback() repository.FeedbackRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Feedback")
+	ret0, _ := ret[0].(repository.FeedbackRepository)
+	return ret0
+}
+
+// Feedback indicates an expected call of Feedback.
+func (mr *MockRepositoryManagerMockRecorder) Feedback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Feedback", reflect.TypeOf((*MockRepositoryManager)(nil).Feedback))
+}
+
+// FineTuningJob mocks base method.
+func (m *MockRepositoryManager) FineTuningJob() repository.FineTuningJobRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FineTuningJob")
+	ret0, _ := ret[0].(repository.FineTuningJobRepository)
+	return ret0
+}
+
+// FineTuningJob indicates an expected call of FineTuningJob.
+func (mr *MockRepositoryManagerMockRecorder) FineTuningJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FineTuningJob", reflect.TypeOf((*MockRepositoryManager)(nil).FineTuningJob))
+}
+
+// Memory mocks base method.
+func (m *MockRepositoryManager) Memory() repository.MemoryRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Memory")
+	ret0, _ := ret[0].(repository.MemoryRepository)
+	return ret0
+}
+
+// Memory indicates an expected call of Memory.
+func (mr *MockRepositoryManagerMockRecorder) Memory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Memory", reflect.TypeOf((*MockRepositoryManager)(nil).Memory))
+}
+
+// ModelMetadata mocks base method.
+func (m *MockRepositoryManager) ModelMetadata() repository.ModelMetadataRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModelMetadata")
+	ret0, _ := ret[0].(repository.ModelMetadataRepository)
+	return ret0
+}
+
+// ModelMetadata indicates an expected call of ModelMetadata.
+func (mr *MockRepositoryManagerMockRecorder) ModelMetadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelMetadata", reflect.TypeOf((*MockRepositoryManager)(nil).ModelMetadata))
+}
+
+// NotificationChannel mocks base method.
+func (m *MockRepositoryManager) NotificationChannel() repository.NotificationChannelRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotificationChannel")
+	ret0, _ := ret[0].(repository.NotificationChannelRepository)
+	return ret0
+}
+
+// NotificationChannel indicates an expected call of NotificationChannel.
+func (mr *MockRepositoryManagerMockRecorder) NotificationChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotificationChannel", reflect.TypeOf((*MockRepositoryManager)(nil).NotificationChannel))
+}
+
+// Persona mocks base method.
+func (m *MockRepositoryManager) Persona() repository.PersonaRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Persona")
+	ret0, _ := ret[0].(repository.PersonaRepository)
+	return ret0
+}
+
+// Persona indicates an expected call of Persona.
+func (mr *MockRepositoryManagerMockRecorder) Persona() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Persona", reflect.TypeOf((*MockRepositoryManager)(nil).Persona))
+}
+
 // Ping mocks base method.
 func (m *MockRepositoryManager) Ping(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -434,6 +753,48 @@ func (mr *MockRepositoryManagerMockRecorder) Ping(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRepositoryManager)(nil).Ping), ctx)
 }
 
+// Project mocks base method.
+func (m *MockRepositoryManager) Project() repository.ProjectRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Project")
+	ret0, _ := ret[0].(repository.ProjectRepository)
+	return ret0
+}
+
+// Project indicates an expected call of Project.
+func (mr *MockRepositoryManagerMockRecorder) Project() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Project", reflect.TypeOf((*MockRepositoryManager)(nil).Project))
+}
+
+// RefreshToken mocks base method.
+func (m *MockRepositoryManager) RefreshToken() repository.RefreshTokenRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken")
+	ret0, _ := ret[0].(repository.RefreshTokenRepository)
+	return ret0
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockRepositoryManagerMockRecorder) RefreshToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockRepositoryManager)(nil).RefreshToken))
+}
+
+// ScheduledJob mocks base method.
+func (m *MockRepositoryManager) ScheduledJob() repository.ScheduledJobRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduledJob")
+	ret0, _ := ret[0].(repository.ScheduledJobRepository)
+	return ret0
+}
+
+// ScheduledJob indicates an expected call of ScheduledJob.
+func (mr *MockRepositoryManagerMockRecorder) ScheduledJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduledJob", reflect.TypeOf((*MockRepositoryManager)(nil).ScheduledJob))
+}
+
 // User mocks base method.
 func (m *MockRepositoryManager) User() repository.UserRepository {
 	m.ctrl.T.Helper()
@@ -447,3 +808,59 @@ func (mr *MockRepositoryManagerMockRecorder) User() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "User", reflect.TypeOf((*MockRepositoryManager)(nil).User))
 }
+
+// UserPermission mocks base method.
+func (m *MockRepositoryManager) UserPermission() repository.UserPermissionRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserPermission")
+	ret0, _ := ret[0].(repository.UserPermissionRepository)
+	return ret0
+}
+
+// UserPermission indicates an expected call of UserPermission.
+func (mr *MockRepositoryManagerMockRecorder) UserPermission() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserPermission", reflect.TypeOf((*MockRepositoryManager)(nil).UserPermission))
+}
+
+// UserPreference mocks base method.
+func (m *MockRepositoryManager) UserPreference() repository.UserPreferenceRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserPreference")
+	ret0, _ := ret[0].(repository.UserPreferenceRepository)
+	return ret0
+}
+
+// UserPreference indicates an expected call of UserPreference.
+func (mr *MockRepositoryManagerMockRecorder) UserPreference() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserPreference", reflect.TypeOf((*MockRepositoryManager)(nil).UserPreference))
+}
+
+// Webhook mocks base method.
+func (m *MockRepositoryManager) Webhook() repository.WebhookRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Webhook")
+	ret0, _ := ret[0].(repository.WebhookRepository)
+	return ret0
+}
+
+// Webhook indicates an expected call of Webhook.
+func (mr *MockRepositoryManagerMockRecorder) Webhook() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Webhook", reflect.TypeOf((*MockRepositoryManager)(nil).Webhook))
+}
+
+// WithTx mocks base method.
+func (m *MockRepositoryManager) WithTx(ctx context.Context, fn func(repository.RepositoryManager) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryManagerMockRecorder) WithTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepositoryManager)(nil).WithTx), ctx, fn)
+}
@@ -0,0 +1,134 @@
+// Package notify 通过邮件、Slack 和 Telegram 向用户发送通知。
+//
+// 通知内容由按语言区分的模板渲染，模板以事件类型命名，可用自定义模板目录覆盖；
+// 各渠道的发送方实现 Sender 接口，未配置的渠道不可用。
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 通知渠道
+const (
+	ChannelEmail    = "email"
+	ChannelSlack    = "slack"
+	ChannelTelegram = "telegram"
+)
+
+// EventTest 测试通知使用的模板名
+const EventTest = "notification.test"
+
+// defaultTimeout 单次发送的默认超时
+const defaultTimeout = 10 * time.Second
+
+// ErrChannelUnavailable 渠道不存在或服务端未配置
+var ErrChannelUnavailable = errors.New("notification channel is not available")
+
+// Message 渲染后的通知内容
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// Sender 某个渠道的发送方
+type Sender interface {
+	// Validate 校验并规范化接收方，如邮箱地址、Slack Webhook 地址或 Telegram 会话ID
+	Validate(target string) (string, error)
+	// Send 向接收方发送通知
+	Send(ctx context.Context, target string, msg Message) error
+}
+
+// Config 通知配置，SMTP.Host 为空时不提供邮件渠道，Telegram.BotToken 为空时不提供 Telegram 渠道
+type Config struct {
+	Timeout     time.Duration
+	TemplateDir string // 自定义模板目录，文件名为 <语言>.tmpl，只需定义要覆盖的模板
+	SMTP        SMTPConfig
+	Slack       SlackConfig
+	Telegram    TelegramConfig
+}
+
+// Notifier 按渠道渲染并发送通知
+type Notifier struct {
+	senders   map[string]Sender
+	templates *Templates
+	timeout   time.Duration
+}
+
+// New 根据配置创建通知发送器
+func New(cfg Config) (*Notifier, error) {
+	templates, err := LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	n := &Notifier{
+		senders:   map[string]Sender{ChannelSlack: NewSlackSender(cfg.Slack, nil)},
+		templates: templates,
+		timeout:   cfg.Timeout,
+	}
+	if cfg.SMTP.Host != "" {
+		sender, err := NewSMTPSender(cfg.SMTP)
+		if err != nil {
+			return nil, err
+		}
+		n.senders[ChannelEmail] = sender
+	}
+	if cfg.Telegram.BotToken != "" {
+		n.senders[ChannelTelegram] = NewTelegramSender(cfg.Telegram, nil)
+	}
+	return n, nil
+}
+
+// Channels 返回可用的渠道
+func (n *Notifier) Channels() []string {
+	channels := make([]string, 0, len(n.senders))
+	for channel := range n.senders {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// ValidateTarget 校验并规范化渠道的接收方
+func (n *Notifier) ValidateTarget(channel, target string) (string, error) {
+	sender, ok := n.senders[channel]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrChannelUnavailable, channel)
+	}
+	return sender.Validate(target)
+}
+
+// Send 用 locale 对应的模板渲染事件并发送
+func (n *Notifier) Send(ctx context.Context, channel, target, event, locale string, data interface{}) error {
+	sender, ok := n.senders[channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChannelUnavailable, channel)
+	}
+	msg, err := n.templates.Render(event, locale, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	return sender.Send(ctx, target, msg)
+}
+
+// SendMessage 发送已生成的内容，不经过模板渲染
+func (n *Notifier) SendMessage(ctx context.Context, channel, target string, msg Message) error {
+	sender, ok := n.senders[channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChannelUnavailable, channel)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	return sender.Send(ctx, target, msg)
+}
@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type quotaData struct {
+	Quota   string
+	Limit   int64
+	Used    int64
+	ResetAt time.Time
+}
+
+func TestTemplatesRender(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.tmpl"), []byte(`{{define "quota.exceeded.subject"}}Custom {{.Limit}}{{end}}`), 0o644))
+	templates, err := LoadTemplates(dir)
+	require.NoError(t, err)
+
+	data := quotaData{Quota: "requests_per_day", Limit: 100, Used: 80, ResetAt: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name        string
+		event       string
+		locale      string
+		wantSubject string
+		wantText    string
+	}{
+		{
+			name:        "English",
+			event:       "quota.warning",
+			locale:      "en",
+			wantSubject: "You have used 80 of 100 in your daily request quota",
+			wantText:    "You have used 80 of your daily request quota of 100.\nThe quota resets at 2026-03-05 00:00 UTC.",
+		},
+		{
+			name:        "Region falls back to language",
+			event:       "quota.warning",
+			locale:      "zh-CN",
+			wantSubject: "每日请求配额已使用 80/100",
+			wantText:    "您的每日请求配额为 100，已使用 80。\n配额将于 2026-03-05 00:00 UTC 重置。",
+		},
+		{
+			name:        "Unknown locale falls back to English",
+			event:       "quota.warning",
+			locale:      "fr",
+			wantSubject: "You have used 80 of 100 in your daily request quota",
+			wantText:    "You have used 80 of your daily request quota of 100.\nThe quota resets at 2026-03-05 00:00 UTC.",
+		},
+		{
+			name:        "Override keeps other templates",
+			event:       "quota.exceeded",
+			locale:      "en",
+			wantSubject: "Custom 100",
+			wantText:    "You have reached your daily request quota of 100. AI requests are rejected until 2026-03-05 00:00 UTC.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(tt.event, tt.locale, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSubject, msg.Subject)
+			assert.Equal(t, tt.wantText, msg.Text)
+		})
+	}
+
+	_, err = templates.Render("unknown.event", "en", data)
+	assert.Error(t, err)
+}
+
+func TestSlackSender(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got["text"] == "*fail*\n" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("no_service"))
+		}
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender(SlackConfig{}, server.Client())
+	require.NoError(t, sender.Send(context.Background(), server.URL, Message{Subject: "Hi", Text: "there"}))
+	assert.Equal(t, "*Hi*\nthere", got["text"])
+	assert.EqualError(t, sender.Send(context.Background(), server.URL, Message{Subject: "fail"}), "slack responded with status 404: no_service")
+
+	target, err := sender.Validate("https://hooks.slack.com/services/T0/B0/xyz")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.slack.com/services/T0/B0/xyz", target)
+	_, err = sender.Validate("http://hooks.slack.com/services/T0/B0/xyz")
+	assert.Error(t, err)
+	_, err = sender.Validate("https://169.254.169.254/latest")
+	assert.Error(t, err)
+}
+
+func TestTelegramSender(t *testing.T) {
+	var path string
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got["chat_id"] == "42" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"ok":false,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	sender := NewTelegramSender(TelegramConfig{BotToken: "123:abc", APIURL: server.URL + "/"}, server.Client())
+	require.NoError(t, sender.Send(context.Background(), "-100200", Message{Subject: "Hi", Text: "there"}))
+	assert.Equal(t, "/bot123:abc/sendMessage", path)
+	assert.Equal(t, "-100200", got["chat_id"])
+	assert.Equal(t, "Hi\n\nthere", got["text"])
+	assert.EqualError(t, sender.Send(context.Background(), "42", Message{}), "telegram responded with status 403: Forbidden: bot was blocked by the user")
+
+	for _, target := range []string{"12345", "-100200", "@my_channel"} {
+		_, err := sender.Validate(target)
+		assert.NoError(t, err, target)
+	}
+	for _, target := range []string{"", "abc", "@ab", "1 2"} {
+		_, err := sender.Validate(target)
+		assert.Error(t, err, target)
+	}
+}
+
+func TestSMTPSenderBuildMessage(t *testing.T) {
+	sender, err := NewSMTPSender(SMTPConfig{Host: "smtp.example.com", From: "goAdmin <noreply@example.com>"})
+	require.NoError(t, err)
+	sender.now = func() time.Time { return time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC) }
+
+	target, err := sender.Validate("Alice <alice@example.com>")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", target)
+	_, err = sender.Validate("not an address")
+	assert.Error(t, err)
+
+	message := string(sender.buildMessage(&mail.Address{Address: target}, Message{Subject: "配额提醒", Text: "line one\nline two"}))
+	assert.Contains(t, message, "From: \"goAdmin\" <noreply@example.com>\r\n")
+	assert.Contains(t, message, "To: <alice@example.com>\r\n")
+	assert.Contains(t, message, "Subject: =?utf-8?q?")
+	assert.Contains(t, message, "Date: Wed, 04 Mar 2026 10:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(message, "\r\n\r\nline one\r\nline two"), message)
+
+	_, err = NewSMTPSender(SMTPConfig{Host: "smtp.example.com", From: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestNotifierChannels(t *testing.T) {
+	n, err := New(Config{Telegram: TelegramConfig{BotToken: "123:abc"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ChannelSlack, ChannelTelegram}, n.Channels())
+
+	_, err = n.ValidateTarget(ChannelEmail, "alice@example.com")
+	assert.ErrorIs(t, err, ErrChannelUnavailable)
+	assert.ErrorIs(t, n.Send(context.Background(), ChannelEmail, "alice@example.com", EventTest, "en", nil), ErrChannelUnavailable)
+	assert.ErrorIs(t, n.SendMessage(context.Background(), ChannelEmail, "alice@example.com", Message{Subject: "Report", Text: "x"}), ErrChannelUnavailable)
+}
+
+type budgetData struct {
+	Budget   string
+	Target   string
+	LimitUSD float64
+	SpentUSD float64
+	ResetAt  time.Time
+}
+
+func TestBudgetTemplatesRender(t *testing.T) {
+	templates, err := LoadTemplates("")
+	require.NoError(t, err)
+
+	data := budgetData{Budget: "project", Target: "alice/research", LimitUSD: 50, SpentUSD: 50.1, ResetAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+	msg, err := templates.Render("budget.exceeded", "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "The monthly AI budget of project alice/research is used up", msg.Subject)
+	assert.Equal(t, "The monthly AI budget of project alice/research of $50.00 has been reached. AI requests are rejected until 2026-04-01 00:00 UTC.", msg.Text)
+
+	data.Budget, data.Target, data.LimitUSD = "provider", "openai", 40
+	msg, err = templates.Render("budget.warning", "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "$50.10 spent in the monthly openai budget", msg.Subject)
+}
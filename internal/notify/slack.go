@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxErrorBodyBytes 错误响应最多读取的字节数
+const maxErrorBodyBytes = 512
+
+// defaultSlackHost Slack Incoming Webhook 地址的主机名
+const defaultSlackHost = "hooks.slack.com"
+
+// SlackConfig Slack 通知配置
+type SlackConfig struct {
+	AllowedHosts []string // 允许的 Webhook 主机名，默认只允许 hooks.slack.com，防止把服务端请求发往任意地址
+}
+
+// SlackSender 通过 Incoming Webhook 发送 Slack 消息，接收方为用户提供的 Webhook 地址
+type SlackSender struct {
+	client *http.Client
+	hosts  map[string]bool
+}
+
+// NewSlackSender 创建 Slack 发送方，client 为空时使用默认客户端
+func NewSlackSender(cfg SlackConfig, client *http.Client) *SlackSender {
+	if client == nil {
+		client = &http.Client{}
+	}
+	hosts := make(map[string]bool)
+	for _, host := range cfg.AllowedHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts[host] = true
+		}
+	}
+	if len(hosts) == 0 {
+		hosts[defaultSlackHost] = true
+	}
+	return &SlackSender{client: client, hosts: hosts}
+}
+
+// Validate 校验 Webhook 地址必须为 https 且主机在允许列表中
+func (s *SlackSender) Validate(target string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(target))
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("slack webhook url must be an https url")
+	}
+	if !s.hosts[strings.ToLower(u.Hostname())] {
+		return "", fmt.Errorf("slack webhook host %s is not allowed", u.Hostname())
+	}
+	return u.String(), nil
+}
+
+// Send 发送消息，主题加粗作为第一行
+func (s *SlackSender) Send(ctx context.Context, target string, msg Message) error {
+	body, err := json.Marshal(map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Webhook 地址本身就是凭据，不写入错误信息
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("send slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("slack responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return nil
+}
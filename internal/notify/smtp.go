@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// defaultSMTPPort 默认使用提交端口并在服务器支持时升级为 STARTTLS
+const defaultSMTPPort = 587
+
+// SMTPConfig SMTP 发信配置
+type SMTPConfig struct {
+	Host        string
+	Port        int    // 默认 587
+	Username    string // 为空时不认证
+	Password    string
+	From        string // 发件人，可带名称，如 "goAdmin <noreply@example.com>"
+	ImplicitTLS bool   // 直接建立 TLS 连接（通常为 465 端口），否则在服务器支持时使用 STARTTLS
+}
+
+// SMTPSender 通过 SMTP 发送纯文本邮件，接收方为邮箱地址
+type SMTPSender struct {
+	cfg  SMTPConfig
+	from *mail.Address
+	now  func() time.Time
+}
+
+// NewSMTPSender 创建 SMTP 发送方
+func NewSMTPSender(cfg SMTPConfig) (*SMTPSender, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address: %w", err)
+	}
+	if cfg.Port <= 0 {
+		cfg.Port = defaultSMTPPort
+	}
+	return &SMTPSender{cfg: cfg, from: from, now: time.Now}, nil
+}
+
+// Validate 校验邮箱地址，为空表示使用账号邮箱
+func (s *SMTPSender) Validate(target string) (string, error) {
+	if target == "" {
+		return "", nil
+	}
+	addr, err := mail.ParseAddress(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid email address: %w", err)
+	}
+	return addr.Address, nil
+}
+
+// Send 发送邮件，ctx 的截止时间作用于整个 SMTP 会话
+func (s *SMTPSender) Send(ctx context.Context, target string, msg Message) error {
+	to, err := mail.ParseAddress(target)
+	if err != nil {
+		return fmt.Errorf("invalid email address: %w", err)
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host}
+	var conn net.Conn
+	if s.cfg.ImplicitTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if !s.cfg.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp starttls: %w", err)
+			}
+		}
+	}
+	if s.cfg.Username != "" {
+		// PlainAuth 拒绝在未加密的连接上发送密码（本机除外）
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(s.buildMessage(to, msg)); err != nil {
+		w.Close()
+		return fmt.Errorf("smtp write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage 生成 UTF-8 纯文本邮件，正文使用 quoted-printable 编码
+func (s *SMTPSender) buildMessage(to *mail.Address, msg Message) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + s.from.String() + "\r\n")
+	buf.WriteString("To: " + to.String() + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + s.now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write(bytes.ReplaceAll([]byte(msg.Text), []byte("\n"), []byte("\r\n")))
+	qp.Close()
+	return buf.Bytes()
+}
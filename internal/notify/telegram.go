@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultTelegramAPIURL Telegram Bot API 地址
+const defaultTelegramAPIURL = "https://api.telegram.org"
+
+// maxTelegramResponseBytes 响应最多读取的字节数，成功时响应包含完整的消息对象
+const maxTelegramResponseBytes = 64 << 10
+
+// telegramChatPattern 会话ID（群组为负数）或公开频道用户名
+var telegramChatPattern = regexp.MustCompile(`^(-?[0-9]{1,20}|@[A-Za-z0-9_]{5,32})$`)
+
+// TelegramConfig Telegram 机器人配置
+type TelegramConfig struct {
+	BotToken string
+	APIURL   string // 默认 https://api.telegram.org，可指向自建的 Bot API 服务
+}
+
+// TelegramSender 通过机器人发送 Telegram 消息，接收方为会话ID
+type TelegramSender struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewTelegramSender 创建 Telegram 发送方，client 为空时使用默认客户端
+func NewTelegramSender(cfg TelegramConfig, client *http.Client) *TelegramSender {
+	if client == nil {
+		client = &http.Client{}
+	}
+	apiURL := strings.TrimRight(cfg.APIURL, "/")
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+	return &TelegramSender{client: client, endpoint: apiURL + "/bot" + cfg.BotToken + "/sendMessage"}
+}
+
+// Validate 校验会话ID，用户需先向机器人发送消息，机器人才能给用户发消息
+func (s *TelegramSender) Validate(target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if !telegramChatPattern.MatchString(target) {
+		return "", fmt.Errorf("telegram chat id must be a number or @channel")
+	}
+	return target, nil
+}
+
+// Send 发送纯文本消息，主题作为第一行
+func (s *TelegramSender) Send(ctx context.Context, target string, msg Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  target,
+		"text":                     msg.Subject + "\n\n" + msg.Text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// 请求地址中包含机器人令牌，不写入错误信息
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("send telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxTelegramResponseBytes))
+	if err := json.Unmarshal(data, &result); err != nil || !result.OK {
+		if result.Description == "" {
+			if len(data) > maxErrorBodyBytes {
+				data = data[:maxErrorBodyBytes]
+			}
+			result.Description = string(bytes.TrimSpace(data))
+		}
+		return fmt.Errorf("telegram responded with status %d: %s", resp.StatusCode, result.Description)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// defaultLocale 找不到用户语言的模板时使用的语言
+const defaultLocale = "en"
+
+// templateFuncs 模板可用的函数
+var templateFuncs = template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"formatUSD": func(v float64) string {
+		return fmt.Sprintf("$%.2f", v)
+	},
+}
+
+// Templates 按语言区分的通知模板，每个事件定义 <事件>.subject 和 <事件>.text 两个模板
+type Templates struct {
+	locales map[string]*template.Template
+}
+
+// LoadTemplates 加载内置模板，dir 不为空时再加载其中的 <语言>.tmpl 覆盖同名模板
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{locales: make(map[string]*template.Template)}
+
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := templateFS.ReadFile("templates/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := t.parse(file.Name(), data); err != nil {
+			return nil, err
+		}
+	}
+
+	if dir == "" {
+		return t, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read notification template: %w", err)
+		}
+		if err := t.parse(filepath.Base(path), data); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// parse 解析一个语言的模板文件，后解析的同名模板覆盖先前的定义
+func (t *Templates) parse(filename string, data []byte) error {
+	locale := strings.TrimSuffix(filename, ".tmpl")
+	tmpl, ok := t.locales[locale]
+	if !ok {
+		tmpl = template.New(locale).Funcs(templateFuncs).Option("missingkey=zero")
+		t.locales[locale] = tmpl
+	}
+	if _, err := tmpl.Parse(string(data)); err != nil {
+		return fmt.Errorf("parse notification template %s: %w", filename, err)
+	}
+	return nil
+}
+
+// Render 渲染事件通知，依次尝试 locale、其主语言（如 zh-CN 的 zh）和英文模板
+func (t *Templates) Render(event, locale string, data interface{}) (Message, error) {
+	tmpl := t.lookup(event, locale)
+	if tmpl == nil {
+		return Message{}, fmt.Errorf("no notification template for %s", event)
+	}
+
+	subject, err := execute(tmpl, event+".subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+	text, err := execute(tmpl, event+".text", data)
+	if err != nil {
+		return Message{}, err
+	}
+	// 主题用作邮件头，不能包含换行
+	subject = strings.Join(strings.Fields(subject), " ")
+	return Message{Subject: subject, Text: text}, nil
+}
+
+// lookup 查找定义了事件模板的语言
+func (t *Templates) lookup(event, locale string) *template.Template {
+	locale = strings.ToLower(locale)
+	candidates := []string{locale}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, defaultLocale)
+
+	for _, candidate := range candidates {
+		if tmpl, ok := t.locales[candidate]; ok && tmpl.Lookup(event+".subject") != nil && tmpl.Lookup(event+".text") != nil {
+			return tmpl
+		}
+	}
+	return nil
+}
+
+// execute 执行命名模板并去除首尾空白
+func execute(tmpl *template.Template, name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("render notification template %s: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
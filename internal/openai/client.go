@@ -9,6 +9,12 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"go-springAi/internal/capture"
+	"go-springAi/internal/chaos"
+	"go-springAi/internal/requestid"
+	"go-springAi/internal/types"
+	"go-springAi/internal/vcr"
 )
 
 // HTTPClient OpenAI HTTP 客户端实现
@@ -24,7 +30,8 @@ func NewHTTPClient(config *Config, keyManager KeyManager) *HTTPClient {
 		config:     config,
 		keyManager: keyManager,
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: &chaos.Transport{Base: &capture.Transport{Provider: "openai", Base: &vcr.Transport{Base: &requestid.Transport{}}}},
 		},
 	}
 }
@@ -36,8 +43,8 @@ func (c *HTTPClient) ChatCompletion(ctx context.Context, req *ChatRequest) (*Cha
 		req.Model = c.config.DefaultModel
 	}
 	
-	// 从密钥管理器获取API密钥
-	apiKey, err := c.keyManager.GetAPIKey()
+	// 获取API密钥
+	apiKey, err := c.apiKey(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get API key: %w", err)
 	}
@@ -73,11 +80,7 @@ func (c *HTTPClient) ChatCompletion(ctx context.Context, req *ChatRequest) (*Cha
 	
 	// 检查错误响应
 	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, fmt.Errorf("OpenAI API error: %s", errResp.Error.Message)
+		return nil, responseError(resp.StatusCode, respBody)
 	}
 	
 	// 解析成功响应
@@ -99,8 +102,8 @@ func (c *HTTPClient) ChatCompletionStream(ctx context.Context, req *ChatRequest)
 		req.Model = c.config.DefaultModel
 	}
 	
-	// 从密钥管理器获取API密钥
-	apiKey, err := c.keyManager.GetAPIKey()
+	// 获取API密钥
+	apiKey, err := c.apiKey(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get API key: %w", err)
 	}
@@ -132,16 +135,39 @@ func (c *HTTPClient) ChatCompletionStream(ctx context.Context, req *ChatRequest)
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		respBody, _ := io.ReadAll(resp.Body)
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
-		}
-		return nil, fmt.Errorf("OpenAI API error: %s", errResp.Error.Message)
+		return nil, responseError(resp.StatusCode, respBody)
 	}
 	
 	return resp.Body, nil
 }
 
+// apiKey 获取本次请求使用的API密钥，上下文中指定的密钥优先
+func (c *HTTPClient) apiKey(ctx context.Context) (string, error) {
+	if key, ok := types.APIKeyFromContext(ctx); ok {
+		return key, nil
+	}
+	return c.keyManager.GetAPIKey()
+}
+
+// responseError 将错误响应转换为 error，鉴权失败时包装 types.ErrProviderUnauthorized，限流时包装 types.ErrProviderRateLimited
+func responseError(statusCode int, body []byte) error {
+	var err error
+	var errResp ErrorResponse
+	if jsonErr := json.Unmarshal(body, &errResp); jsonErr != nil {
+		err = fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	} else {
+		err = fmt.Errorf("OpenAI API error: %s", errResp.Error.Message)
+	}
+	
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return fmt.Errorf("%w: %w", types.ErrProviderUnauthorized, err)
+	}
+	if statusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", types.ErrProviderRateLimited, err)
+	}
+	return err
+}
+
 // ListModels 列出可用模型
 func (c *HTTPClient) ListModels(ctx context.Context) ([]string, error) {
 	// 创建 HTTP 请求
@@ -194,10 +220,10 @@ func (c *HTTPClient) ListModels(ctx context.Context) ([]string, error) {
 	return models, nil
 }
 
-// ValidateAPIKey 验证 API 密钥
+// ValidateAPIKey 验证 API 密钥，上下文中指定的密钥优先
 func (c *HTTPClient) ValidateAPIKey(ctx context.Context) error {
-	// 从密钥管理器获取API密钥
-	apiKey, err := c.keyManager.GetAPIKey()
+	// 获取API密钥
+	apiKey, err := c.apiKey(ctx)
 	if err != nil {
 		return fmt.Errorf("get API key: %w", err)
 	}
@@ -219,13 +245,9 @@ func (c *HTTPClient) ValidateAPIKey(ctx context.Context) error {
 	defer resp.Body.Close()
 	
 	// 检查响应状态
-	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("invalid API key")
-	}
-	
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API validation failed: HTTP %d: %s", resp.StatusCode, string(respBody))
+		return fmt.Errorf("API validation failed: %w", responseError(resp.StatusCode, respBody))
 	}
 	
 	return nil
@@ -22,6 +22,7 @@ type ModelConfig struct {
 	FrequencyPenalty float32 `json:"frequency_penalty"`
 	PresencePenalty float32 `json:"presence_penalty"`
 	Enabled         bool    `json:"enabled"`
+	Unavailable     bool    `json:"unavailable,omitempty"` // 提供商已不再提供该模型
 }
 
 // DefaultConfig 返回默认配置
@@ -0,0 +1,201 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// 微调任务状态
+const (
+	FineTuningStatusValidatingFiles = "validating_files"
+	FineTuningStatusQueued          = "queued"
+	FineTuningStatusRunning         = "running"
+	FineTuningStatusSucceeded       = "succeeded"
+	FineTuningStatusFailed          = "failed"
+	FineTuningStatusCancelled       = "cancelled"
+)
+
+// FilePurposeFineTune 微调训练文件的用途
+const FilePurposeFineTune = "fine-tune"
+
+// File 上传到 OpenAI 的文件
+type File struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+}
+
+// FineTuningJobRequest 创建微调任务请求
+type FineTuningJobRequest struct {
+	Model          string `json:"model"`
+	TrainingFile   string `json:"training_file"`
+	ValidationFile string `json:"validation_file,omitempty"`
+	Suffix         string `json:"suffix,omitempty"`
+}
+
+// FineTuningJobError 微调任务失败原因
+type FineTuningJobError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FineTuningJob 微调任务
+type FineTuningJob struct {
+	ID             string              `json:"id"`
+	Object         string              `json:"object"`
+	Model          string              `json:"model"`
+	TrainingFile   string              `json:"training_file"`
+	ValidationFile string              `json:"validation_file,omitempty"`
+	Status         string              `json:"status"`
+	FineTunedModel string              `json:"fine_tuned_model,omitempty"`
+	Error          *FineTuningJobError `json:"error,omitempty"`
+	CreatedAt      int64               `json:"created_at"`
+	FinishedAt     *int64              `json:"finished_at,omitempty"`
+}
+
+// Finished 任务是否已结束
+func (j *FineTuningJob) Finished() bool {
+	switch j.Status {
+	case FineTuningStatusSucceeded, FineTuningStatusFailed, FineTuningStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// FineTuningClient OpenAI 文件和微调任务接口
+type FineTuningClient interface {
+	// UploadFile 上传微调训练文件
+	UploadFile(ctx context.Context, filename string, content io.Reader) (*File, error)
+
+	// CreateFineTuningJob 创建微调任务
+	CreateFineTuningJob(ctx context.Context, req *FineTuningJobRequest) (*FineTuningJob, error)
+
+	// GetFineTuningJob 获取微调任务
+	GetFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error)
+
+	// ListFineTuningJobs 按创建时间倒序列出微调任务
+	ListFineTuningJobs(ctx context.Context, limit int) ([]FineTuningJob, error)
+
+	// CancelFineTuningJob 取消微调任务
+	CancelFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error)
+}
+
+var _ FineTuningClient = (*HTTPClient)(nil)
+
+// UploadFile 以 multipart 表单上传微调训练文件
+func (c *HTTPClient) UploadFile(ctx context.Context, filename string, content io.Reader) (*File, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("purpose", FilePurposeFineTune); err != nil {
+		return nil, fmt.Errorf("write purpose: %w", err)
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("copy file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close form: %w", err)
+	}
+
+	var file File
+	if err := c.doJSON(ctx, http.MethodPost, "/files", writer.FormDataContentType(), &body, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// CreateFineTuningJob 创建微调任务
+func (c *HTTPClient) CreateFineTuningJob(ctx context.Context, req *FineTuningJobRequest) (*FineTuningJob, error) {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	var job FineTuningJob
+	if err := c.doJSON(ctx, http.MethodPost, "/fine_tuning/jobs", "application/json", bytes.NewReader(reqBody), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetFineTuningJob 获取微调任务
+func (c *HTTPClient) GetFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error) {
+	var job FineTuningJob
+	if err := c.doJSON(ctx, http.MethodGet, "/fine_tuning/jobs/"+url.PathEscape(id), "", nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListFineTuningJobs 按创建时间倒序列出微调任务
+func (c *HTTPClient) ListFineTuningJobs(ctx context.Context, limit int) ([]FineTuningJob, error) {
+	path := "/fine_tuning/jobs"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+
+	var list struct {
+		Data []FineTuningJob `json:"data"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, "", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// CancelFineTuningJob 取消微调任务
+func (c *HTTPClient) CancelFineTuningJob(ctx context.Context, id string) (*FineTuningJob, error) {
+	var job FineTuningJob
+	if err := c.doJSON(ctx, http.MethodPost, "/fine_tuning/jobs/"+url.PathEscape(id)+"/cancel", "", nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// doJSON 发送请求并把 JSON 响应解析到 out，上下文中指定的密钥优先
+func (c *HTTPClient) doJSON(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	apiKey, err := c.apiKey(ctx)
+	if err != nil {
+		return fmt.Errorf("get API key: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp.StatusCode, respBody)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
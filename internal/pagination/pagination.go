@@ -0,0 +1,128 @@
+// Package pagination 列表接口共用的游标分页。
+//
+// 游标记录上一页边界记录的排序键，下一页从该记录之后开始查询，
+// 翻页期间插入或删除数据不会导致记录重复或遗漏。
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+)
+
+// 每页条数
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// CursorParam 游标查询参数
+const CursorParam = "cursor"
+
+// Cursor 游标内容，编码后对客户端不透明
+type Cursor struct {
+	Backward bool            `json:"b,omitempty"` // 取 Key 之前的一页
+	Sort     string          `json:"s,omitempty"` // 生成游标时的排序，排序不同时游标无效
+	Key      json.RawMessage `json:"k"`           // 边界记录的排序键
+}
+
+// Encode 编码为URL安全的字符串
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode 解析游标并把排序键解析到 key，token 为空时返回 nil；
+// sort 为当前请求的排序，与生成游标时不同说明游标不属于这个列表
+func Decode(token, sort string, key interface{}) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalidCursor(err.Error())
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, invalidCursor(err.Error())
+	}
+	if cursor.Sort != sort {
+		return nil, invalidCursor("cursor was created for a different sort order")
+	}
+	if err := json.Unmarshal(cursor.Key, key); err != nil {
+		return nil, invalidCursor(err.Error())
+	}
+	return &cursor, nil
+}
+
+func invalidCursor(details string) *errors.AppError {
+	return errors.NewValidationError("Invalid cursor").WithDetails(details)
+}
+
+// Limit 每页条数，非正数使用 defaultLimit，最多 MaxLimit
+func Limit(limit, defaultLimit int) int {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return limit
+}
+
+// Page 整理按游标方向多取一条的查询结果，返回当前页和前后页游标。
+// 向前翻页时查询按相反顺序进行，结果在这里恢复为正常顺序
+func Page[T any](items []T, limit int, cursor *Cursor, sort string, keyOf func(T) interface{}) ([]T, dto.PageInfo) {
+	backward := cursor != nil && cursor.Backward
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	var info dto.PageInfo
+	if len(items) == 0 {
+		return items, info
+	}
+
+	// 带游标的请求来自相邻的一页，那个方向一定还有数据
+	hasNext, hasPrev := more, cursor != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		info.NextCursor = Cursor{Sort: sort, Key: marshalKey(keyOf(items[len(items)-1]))}.Encode()
+	}
+	if hasPrev {
+		info.PrevCursor = Cursor{Backward: true, Sort: sort, Key: marshalKey(keyOf(items[0]))}.Encode()
+	}
+	return items, info
+}
+
+func marshalKey(key interface{}) json.RawMessage {
+	data, _ := json.Marshal(key)
+	return data
+}
+
+// Links 根据请求地址生成上一页和下一页的链接，保留其他查询参数，去掉 page
+func Links(info *dto.PageInfo, requestURL *url.URL) {
+	link := func(cursor string) string {
+		query := requestURL.Query()
+		query.Del("page")
+		query.Set(CursorParam, cursor)
+		return requestURL.Path + "?" + query.Encode()
+	}
+	if info.NextCursor != "" {
+		info.Next = link(info.NextCursor)
+	}
+	if info.PrevCursor != "" {
+		info.Prev = link(info.PrevCursor)
+	}
+}
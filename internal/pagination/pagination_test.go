@@ -0,0 +1,110 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	valid := Cursor{Sort: "id asc", Key: []byte(`{"id": 7}`)}.Encode()
+
+	tests := []struct {
+		name    string
+		token   string
+		sort    string
+		wantErr bool
+		want    int64
+	}{
+		{name: "Empty token", token: "", sort: "id asc"},
+		{name: "Valid", token: valid, sort: "id asc", want: 7},
+		{name: "Not base64", token: "!!!", sort: "id asc", wantErr: true},
+		{name: "Not JSON", token: "bm90LWpzb24", sort: "id asc", wantErr: true},
+		{name: "Different sort", token: valid, sort: "id desc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key struct {
+				ID int64 `json:"id"`
+			}
+			cursor, err := Decode(tt.token, tt.sort, &key)
+			if tt.wantErr {
+				appErr, ok := errors.IsAppError(err)
+				require.True(t, ok)
+				assert.Equal(t, errors.ErrCodeValidationFailed, appErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.token == "", cursor == nil)
+			assert.Equal(t, tt.want, key.ID)
+		})
+	}
+}
+
+// fetch 模拟按ID升序的键集查询
+func fetch(rows []int, limit int, cursor *Cursor, after int) []int {
+	var out []int
+	if cursor != nil && cursor.Backward {
+		for i := len(rows) - 1; i >= 0 && len(out) <= limit; i-- {
+			if rows[i] < after {
+				out = append(out, rows[i])
+			}
+		}
+		return out
+	}
+	for _, row := range rows {
+		if (cursor == nil || row > after) && len(out) <= limit {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func TestPage(t *testing.T) {
+	rows := []int{1, 2, 3, 4, 5}
+	keyOf := func(row int) interface{} { return row }
+	page := func(token string) ([]int, string, string) {
+		var after int
+		cursor, err := Decode(token, "id", &after)
+		require.NoError(t, err)
+		items, info := Page(fetch(rows, 2, cursor, after), 2, cursor, "id", keyOf)
+		return items, info.NextCursor, info.PrevCursor
+	}
+
+	first, next, prev := page("")
+	assert.Equal(t, []int{1, 2}, first)
+	assert.Empty(t, prev)
+
+	// 翻页期间插入的记录不会让下一页重复已返回的记录
+	rows = []int{0, 1, 2, 3, 4, 5}
+	second, next, prev := page(next)
+	assert.Equal(t, []int{3, 4}, second)
+
+	last, lastNext, _ := page(next)
+	assert.Equal(t, []int{5}, last)
+	assert.Empty(t, lastNext)
+
+	back, _, backPrev := page(prev)
+	assert.Equal(t, []int{1, 2}, back)
+	assert.NotEmpty(t, backPrev, "the row inserted before the first page is reachable")
+
+	start, startNext, startPrev := page(backPrev)
+	assert.Equal(t, []int{0}, start)
+	assert.NotEmpty(t, startNext)
+	assert.Empty(t, startPrev)
+}
+
+func TestLinks(t *testing.T) {
+	info := dto.PageInfo{NextCursor: "next-token"}
+	requestURL, err := url.Parse("/api/v1/admin/users?page=2&search=al&limit=10")
+	require.NoError(t, err)
+
+	Links(&info, requestURL)
+	assert.Equal(t, "/api/v1/admin/users?cursor=next-token&limit=10&search=al", info.Next)
+	assert.Empty(t, info.Prev)
+}
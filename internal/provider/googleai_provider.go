@@ -2,7 +2,9 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"go-springAi/internal/googleai"
 	"go-springAi/internal/service"
@@ -11,7 +13,9 @@ import (
 
 // GoogleAIProvider GoogleAI提供商实现
 type GoogleAIProvider struct {
-	service *service.GoogleAIService
+	service  *service.GoogleAIService
+	rotation keyRotation
+	pool     keyPool
 }
 
 // NewGoogleAIProvider 创建GoogleAI Provider
@@ -46,7 +50,11 @@ func (p *GoogleAIProvider) ChatCompletion(ctx context.Context, req *ChatRequest)
 	}
 	
 	// 调用GoogleAI服务
-	resp, err := p.service.ChatCompletion(ctx, googleaiReq)
+	resp, err := withPreviousKey(ctx, &p.rotation, func(ctx context.Context) (*service.GoogleAIChatCompletionResponse, error) {
+		return withKeyPool(ctx, &p.pool, func(ctx context.Context) (*service.GoogleAIChatCompletionResponse, error) {
+			return p.service.ChatCompletion(ctx, googleaiReq)
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +89,11 @@ func (p *GoogleAIProvider) ChatCompletionStream(ctx context.Context, req *ChatRe
 	}
 	
 	// 调用GoogleAI服务
-	return p.service.ChatCompletionStream(ctx, googleaiReq)
+	return withPreviousKey(ctx, &p.rotation, func(ctx context.Context) (io.ReadCloser, error) {
+		return withKeyPool(ctx, &p.pool, func(ctx context.Context) (io.ReadCloser, error) {
+			return p.service.ChatCompletionStream(ctx, googleaiReq)
+		})
+	})
 }
 
 // ListModels 列出可用模型（仅启用的）
@@ -102,6 +114,7 @@ func (p *GoogleAIProvider) ListModels(ctx context.Context) (map[string]*ModelCon
 			TopP:        config.TopP,
 			TopK:        config.TopK,
 			Enabled:     config.Enabled,
+			Unavailable: config.Unavailable,
 		}
 	}
 	
@@ -126,12 +139,18 @@ func (p *GoogleAIProvider) ListAllModels(ctx context.Context) (map[string]*Model
 			TopP:        config.TopP,
 			TopK:        config.TopK,
 			Enabled:     config.Enabled,
+			Unavailable: config.Unavailable,
 		}
 	}
 	
 	return result, nil
 }
 
+// ListRemoteModels 从提供商接口获取当前提供的模型
+func (p *GoogleAIProvider) ListRemoteModels(ctx context.Context) ([]string, error) {
+	return p.service.ListRemoteModels(ctx)
+}
+
 // GetModelConfig 获取模型配置
 func (p *GoogleAIProvider) GetModelConfig(name string) (*ModelConfig, error) {
 	config, err := p.service.GetModelConfig(name)
@@ -147,6 +166,7 @@ func (p *GoogleAIProvider) GetModelConfig(name string) (*ModelConfig, error) {
 		TopP:        config.TopP,
 		TopK:        config.TopK,
 		Enabled:     config.Enabled,
+		Unavailable: config.Unavailable,
 	}, nil
 }
 
@@ -160,6 +180,31 @@ func (p *GoogleAIProvider) DisableModel(name string) error {
 	return p.service.DisableModel(name)
 }
 
+// AddModel 以默认参数添加禁用状态的模型
+func (p *GoogleAIProvider) AddModel(name string) error {
+	if _, err := p.service.GetModelConfig(name); err == nil {
+		return fmt.Errorf("model %s already exists", name)
+	}
+	return p.service.UpdateModelConfig(name, &googleai.ModelConfig{
+		Name:        name,
+		DisplayName: name,
+		MaxTokens:   8192,
+		Temperature: 0.7,
+		TopP:        0.9,
+		TopK:        40,
+	})
+}
+
+// SetModelUnavailable 标记模型是否已不再由提供商提供
+func (p *GoogleAIProvider) SetModelUnavailable(name string, unavailable bool) error {
+	config, err := p.service.GetModelConfig(name)
+	if err != nil {
+		return err
+	}
+	config.Unavailable = unavailable
+	return p.service.UpdateModelConfig(name, config)
+}
+
 // ValidateAPIKey 验证API密钥
 func (p *GoogleAIProvider) ValidateAPIKey(ctx context.Context) error {
 	return p.service.ValidateAPIKey(ctx)
@@ -170,6 +215,21 @@ func (p *GoogleAIProvider) SetAPIKey(key string) error {
 	return p.service.SetAPIKey(key)
 }
 
+// SetPreviousAPIKey 设置轮换宽限期内的上一版本密钥
+func (p *GoogleAIProvider) SetPreviousAPIKey(key string, expiresAt time.Time) {
+	p.rotation.set(key, expiresAt)
+}
+
+// SetKeyPool 设置与主密钥一起轮换使用的附加密钥
+func (p *GoogleAIProvider) SetKeyPool(cfg KeyPoolConfig) {
+	p.pool.configure(cfg)
+}
+
+// KeyHealth 获取各密钥的健康状态
+func (p *GoogleAIProvider) KeyHealth() []KeyHealth {
+	return p.pool.health(time.Now())
+}
+
 // IsHealthy 检查提供商健康状态
 func (p *GoogleAIProvider) IsHealthy(ctx context.Context) bool {
 	err := p.service.ValidateAPIKey(ctx)
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-springAi/internal/types"
+)
+
+// KeyStrategy 多密钥选择策略
+type KeyStrategy string
+
+const (
+	// KeyStrategyRoundRobin 每次请求轮流使用各个密钥
+	KeyStrategyRoundRobin KeyStrategy = "round_robin"
+	// KeyStrategyFailover 优先使用主密钥，限流或鉴权失败时才切换
+	KeyStrategyFailover KeyStrategy = "failover"
+)
+
+// DefaultKeyCooldown 密钥被限流或拒绝后暂停使用的默认时长
+const DefaultKeyCooldown = time.Minute
+
+// KeyPoolConfig 多密钥配置，Keys 为主密钥之外的附加密钥
+type KeyPoolConfig struct {
+	Keys     []string
+	Strategy KeyStrategy
+	Cooldown time.Duration
+}
+
+// KeyHealth 单个密钥的健康状态
+type KeyHealth struct {
+	Key           string     `json:"key"` // 脱敏后的密钥，主密钥为 primary
+	Primary       bool       `json:"primary"`
+	Healthy       bool       `json:"healthy"`
+	Requests      int64      `json:"requests"`
+	RateLimited   int64      `json:"rate_limited"`
+	Failures      int64      `json:"failures"`
+	CooldownUntil *time.Time `json:"cooldown_until,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// pooledKey 密钥池中的一个密钥，key 为空表示密钥管理器中的主密钥
+type pooledKey struct {
+	key           string
+	requests      int64
+	rateLimited   int64
+	failures      int64
+	cooldownUntil time.Time
+	lastError     string
+}
+
+// keyPool 在主密钥和附加密钥之间轮换，并记录每个密钥的健康状态
+type keyPool struct {
+	mu       sync.Mutex
+	keys     []*pooledKey
+	strategy KeyStrategy
+	cooldown time.Duration
+	next     int
+}
+
+// configure 设置附加密钥和选择策略，已存在密钥的统计信息会保留
+func (p *keyPool) configure(cfg KeyPoolConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	existing := make(map[string]*pooledKey, len(p.keys))
+	for _, k := range p.keys {
+		existing[k.key] = k
+	}
+
+	keys := []*pooledKey{existing[""]}
+	if keys[0] == nil {
+		keys[0] = &pooledKey{}
+	}
+	seen := map[string]bool{"": true}
+	for _, key := range cfg.Keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if k, ok := existing[key]; ok {
+			keys = append(keys, k)
+		} else {
+			keys = append(keys, &pooledKey{key: key})
+		}
+	}
+
+	p.keys = keys
+	p.strategy = cfg.Strategy
+	p.cooldown = cfg.Cooldown
+	if p.cooldown <= 0 {
+		p.cooldown = DefaultKeyCooldown
+	}
+	p.next = 0
+}
+
+// candidates 返回本次请求依次尝试的密钥：可用密钥按策略排序，全部冷却时返回最早恢复的密钥
+func (p *keyPool) candidates(now time.Time) []*pooledKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		p.keys = []*pooledKey{{}}
+	}
+
+	start := 0
+	if p.strategy != KeyStrategyFailover {
+		start = p.next % len(p.keys)
+		p.next++
+	}
+
+	var result []*pooledKey
+	var soonest *pooledKey
+	for i := range p.keys {
+		k := p.keys[(start+i)%len(p.keys)]
+		if !now.Before(k.cooldownUntil) {
+			result = append(result, k)
+		} else if soonest == nil || k.cooldownUntil.Before(soonest.cooldownUntil) {
+			soonest = k
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, soonest)
+	}
+	return result
+}
+
+// report 记录一次调用结果，限流或鉴权失败的密钥进入冷却
+func (p *keyPool) report(k *pooledKey, err error, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.requests++
+	switch {
+	case err == nil:
+		k.cooldownUntil = time.Time{}
+		k.lastError = ""
+	case errors.Is(err, types.ErrProviderRateLimited):
+		k.rateLimited++
+		k.cooldownUntil = now.Add(p.cooldown)
+		k.lastError = err.Error()
+	case errors.Is(err, types.ErrProviderUnauthorized):
+		k.failures++
+		k.cooldownUntil = now.Add(p.cooldown)
+		k.lastError = err.Error()
+	}
+}
+
+// health 获取所有密钥的健康状态
+func (p *keyPool) health(now time.Time) []KeyHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]KeyHealth, 0, len(p.keys))
+	for _, k := range p.keys {
+		h := KeyHealth{
+			Key:         maskKey(k.key),
+			Primary:     k.key == "",
+			Healthy:     !now.Before(k.cooldownUntil),
+			Requests:    k.requests,
+			RateLimited: k.rateLimited,
+			Failures:    k.failures,
+			LastError:   k.lastError,
+		}
+		if !h.Healthy {
+			until := k.cooldownUntil
+			h.CooldownUntil = &until
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+// withKeyPool 按密钥池策略选择密钥调用，限流或鉴权失败时换下一个可用密钥重试
+func withKeyPool[T any](ctx context.Context, p *keyPool, call func(context.Context) (T, error)) (T, error) {
+	// 调用方已指定密钥时不参与轮换
+	if _, ok := types.APIKeyFromContext(ctx); ok {
+		return call(ctx)
+	}
+
+	var result T
+	var err error
+	for _, k := range p.candidates(time.Now()) {
+		callCtx := ctx
+		if k.key != "" {
+			callCtx = types.WithAPIKey(ctx, k.key)
+		}
+
+		result, err = call(callCtx)
+		p.report(k, err, time.Now())
+		if err == nil || (!errors.Is(err, types.ErrProviderRateLimited) && !errors.Is(err, types.ErrProviderUnauthorized)) {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// maskKey 对密钥脱敏，主密钥显示为 primary
+func maskKey(key string) string {
+	if key == "" {
+		return "primary"
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return key[:4] + "****" + key[len(key)-4:]
+}
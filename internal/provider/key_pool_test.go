@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-springAi/internal/types"
+)
+
+func TestWithKeyPool(t *testing.T) {
+	rateLimited := fmt.Errorf("%w: slow down", types.ErrProviderRateLimited)
+
+	tests := []struct {
+		name     string
+		strategy KeyStrategy
+		limited  map[string]bool // 返回 429 的密钥，"" 为主密钥
+		calls    int
+		wantKeys []string
+		wantErr  bool
+	}{
+		{name: "Round robin spreads requests", strategy: KeyStrategyRoundRobin, calls: 3, wantKeys: []string{"", "k2", ""}},
+		{name: "Failover stays on primary", strategy: KeyStrategyFailover, calls: 2, wantKeys: []string{"", ""}},
+		{name: "Rate limited key is skipped while cooling", strategy: KeyStrategyFailover, limited: map[string]bool{"": true}, calls: 2, wantKeys: []string{"", "k2", "k2"}},
+		{name: "All keys rate limited", strategy: KeyStrategyRoundRobin, limited: map[string]bool{"": true, "k2": true}, calls: 1, wantKeys: []string{"", "k2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pool keyPool
+			pool.configure(KeyPoolConfig{Keys: []string{"k2", "k2", ""}, Strategy: tt.strategy})
+
+			var keys []string
+			var err error
+			for i := 0; i < tt.calls; i++ {
+				_, err = withKeyPool(context.Background(), &pool, func(ctx context.Context) (string, error) {
+					key, _ := types.APIKeyFromContext(ctx)
+					keys = append(keys, key)
+					if tt.limited[key] {
+						return "", rateLimited
+					}
+					return "ok", nil
+				})
+			}
+
+			if fmt.Sprint(keys) != fmt.Sprint(tt.wantKeys) {
+				t.Errorf("expected keys %v, got %v", tt.wantKeys, keys)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithKeyPoolContextKey(t *testing.T) {
+	var pool keyPool
+	pool.configure(KeyPoolConfig{Keys: []string{"k2"}})
+
+	ctx := types.WithAPIKey(context.Background(), "project-key")
+	key, _ := withKeyPool(ctx, &pool, func(ctx context.Context) (string, error) {
+		key, _ := types.APIKeyFromContext(ctx)
+		return key, nil
+	})
+	if key != "project-key" {
+		t.Errorf("expected project-key, got %s", key)
+	}
+	for _, h := range pool.health(time.Now()) {
+		if h.Requests != 0 {
+			t.Errorf("expected pool to be bypassed, got %d requests on %s", h.Requests, h.Key)
+		}
+	}
+}
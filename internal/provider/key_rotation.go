@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-springAi/internal/types"
+)
+
+// keyRotation 保存轮换宽限期内仍可使用的上一版本密钥
+type keyRotation struct {
+	mu        sync.RWMutex
+	previous  string
+	expiresAt time.Time
+}
+
+// set 设置上一版本密钥，key 为空时清除
+func (r *keyRotation) set(key string, expiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.previous = key
+	r.expiresAt = expiresAt
+}
+
+// previousKey 获取在 now 时仍处于宽限期的上一版本密钥
+func (r *keyRotation) previousKey(now time.Time) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.previous == "" || !now.Before(r.expiresAt) {
+		return "", false
+	}
+	return r.previous, true
+}
+
+// withPreviousKey 调用因鉴权失败出错时，使用宽限期内的上一版本密钥重试一次
+func withPreviousKey[T any](ctx context.Context, r *keyRotation, call func(context.Context) (T, error)) (T, error) {
+	result, err := call(ctx)
+	if err == nil || !errors.Is(err, types.ErrProviderUnauthorized) {
+		return result, err
+	}
+
+	// 调用方已指定密钥时不再替换
+	if _, ok := types.APIKeyFromContext(ctx); ok {
+		return result, err
+	}
+
+	previous, ok := r.previousKey(time.Now())
+	if !ok {
+		return result, err
+	}
+	return call(types.WithAPIKey(ctx, previous))
+}
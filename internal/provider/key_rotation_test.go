@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-springAi/internal/types"
+)
+
+func TestWithPreviousKey(t *testing.T) {
+	unauthorized := fmt.Errorf("%w: invalid key", types.ErrProviderUnauthorized)
+	other := errors.New("rate limited")
+
+	tests := []struct {
+		name      string
+		previous  string
+		expiresAt time.Time
+		err       error
+		wantKeys  []string
+		wantErr   error
+	}{
+		{name: "Success keeps current key", previous: "old", expiresAt: time.Now().Add(time.Hour), wantKeys: []string{""}},
+		{name: "Unauthorized retries with previous key", previous: "old", expiresAt: time.Now().Add(time.Hour), err: unauthorized, wantKeys: []string{"", "old"}},
+		{name: "Expired grace does not retry", previous: "old", expiresAt: time.Now().Add(-time.Minute), err: unauthorized, wantKeys: []string{""}, wantErr: types.ErrProviderUnauthorized},
+		{name: "Other errors do not retry", previous: "old", expiresAt: time.Now().Add(time.Hour), err: other, wantKeys: []string{""}, wantErr: other},
+		{name: "No previous key", err: unauthorized, wantKeys: []string{""}, wantErr: types.ErrProviderUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rotation keyRotation
+			rotation.set(tt.previous, tt.expiresAt)
+
+			var keys []string
+			_, err := withPreviousKey(context.Background(), &rotation, func(ctx context.Context) (string, error) {
+				key, _ := types.APIKeyFromContext(ctx)
+				keys = append(keys, key)
+				if key == "" {
+					return "", tt.err
+				}
+				return "ok", nil
+			})
+
+			if fmt.Sprint(keys) != fmt.Sprint(tt.wantKeys) {
+				t.Errorf("expected keys %v, got %v", tt.wantKeys, keys)
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/types"
+)
+
+// 首个token延迟的分布
+const (
+	LatencyFixed     = "fixed"     // 固定为 Mean
+	LatencyUniform   = "uniform"   // Min 到 Max 之间均匀分布
+	LatencyNormal    = "normal"    // 均值 Mean、标准差 StdDev 的正态分布
+	LatencyLogNormal = "lognormal" // 均值 Mean、标准差 StdDev 的对数正态分布，接近真实提供商的长尾
+)
+
+// loadTestWords 生成内容使用的词表，每个词计为一个token
+var loadTestWords = strings.Fields("the market data shows steady growth while analysts expect volatility in the coming quarter as " +
+	"revenue rises and margins hold near record levels across major sectors including technology energy and finance")
+
+// LatencyDistribution 延迟分布，结果限制在 Min 和 Max 之间（Max 为0时不限上限）
+type LatencyDistribution struct {
+	Distribution string
+	Mean         time.Duration
+	StdDev       time.Duration
+	Min          time.Duration
+	Max          time.Duration
+}
+
+// LoadTestOptions 压测提供商配置
+type LoadTestOptions struct {
+	Models            []string            // 提供的模型名称，为空时为 loadtest-1
+	FirstTokenLatency LatencyDistribution // 收到请求到输出首个token的延迟
+	TokensPerSecond   float64             // 首个token之后的输出速率，0 表示不等待
+	CompletionTokens  int                 // 每次响应的token数，请求的 max_tokens 更小时以其为准
+	Seed              int64               // 非0时延迟和内容可复现
+}
+
+// LoadTestProvider 压测提供商，按配置的延迟分布和token速率返回生成的内容，
+// 用于评估流式接口和SSE的容量而不产生API费用
+type LoadTestProvider struct {
+	opts   LoadTestOptions
+	models map[string]*ModelConfig
+
+	mu   sync.Mutex // 保护 rand 和 models
+	rand *rand.Rand
+}
+
+// NewLoadTestProvider 创建压测提供商
+func NewLoadTestProvider(opts LoadTestOptions) (*LoadTestProvider, error) {
+	if err := opts.FirstTokenLatency.validate(); err != nil {
+		return nil, err
+	}
+	if opts.TokensPerSecond < 0 {
+		return nil, fmt.Errorf("loadtest: tokens per second must not be negative")
+	}
+	if opts.CompletionTokens <= 0 {
+		return nil, fmt.Errorf("loadtest: completion tokens must be positive")
+	}
+	if len(opts.Models) == 0 {
+		opts.Models = []string{"loadtest-1"}
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	p := &LoadTestProvider{
+		opts:   opts,
+		models: make(map[string]*ModelConfig, len(opts.Models)),
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+	for _, name := range opts.Models {
+		p.models[name] = &ModelConfig{
+			Name:        name,
+			DisplayName: "Load Test " + name,
+			MaxTokens:   opts.CompletionTokens,
+			Temperature: 0.7,
+			TopP:        1,
+			Enabled:     true,
+		}
+	}
+	return p, nil
+}
+
+// validate 检查分布参数
+func (d LatencyDistribution) validate() error {
+	if d.Mean < 0 || d.StdDev < 0 || d.Min < 0 || d.Max < 0 {
+		return fmt.Errorf("loadtest: latency must not be negative")
+	}
+	if d.Max > 0 && d.Min > d.Max {
+		return fmt.Errorf("loadtest: latency min %s exceeds max %s", d.Min, d.Max)
+	}
+	switch d.Distribution {
+	case "", LatencyFixed, LatencyNormal:
+	case LatencyUniform:
+		if d.Max == 0 {
+			return fmt.Errorf("loadtest: uniform latency requires max")
+		}
+	case LatencyLogNormal:
+		if d.Mean == 0 {
+			return fmt.Errorf("loadtest: lognormal latency requires mean")
+		}
+	default:
+		return fmt.Errorf("loadtest: unknown latency distribution %q", d.Distribution)
+	}
+	return nil
+}
+
+// sample 按分布抽取一个延迟，调用方持有锁
+func (d LatencyDistribution) sample(r *rand.Rand) time.Duration {
+	var v float64
+	switch d.Distribution {
+	case LatencyUniform:
+		v = float64(d.Min) + r.Float64()*float64(d.Max-d.Min)
+	case LatencyNormal:
+		v = float64(d.Mean) + r.NormFloat64()*float64(d.StdDev)
+	case LatencyLogNormal:
+		// 由目标均值和标准差反推底层正态分布的参数
+		mean, sd := float64(d.Mean), float64(d.StdDev)
+		sigma2 := math.Log(1 + (sd*sd)/(mean*mean))
+		v = math.Exp(math.Log(mean) - sigma2/2 + r.NormFloat64()*math.Sqrt(sigma2))
+	default:
+		v = float64(d.Mean)
+	}
+	latency := time.Duration(v)
+	if latency < d.Min {
+		latency = d.Min
+	}
+	if d.Max > 0 && latency > d.Max {
+		latency = d.Max
+	}
+	return latency
+}
+
+// GetType 获取提供商类型
+func (p *LoadTestProvider) GetType() ProviderType {
+	return types.ProviderTypeLoadTest
+}
+
+// GetName 获取提供商名称
+func (p *LoadTestProvider) GetName() string {
+	return "loadtest"
+}
+
+// loadTestPlan 一次响应的延迟和内容
+type loadTestPlan struct {
+	id           string
+	created      int64
+	firstToken   time.Duration
+	tokens       []string
+	finishReason string
+}
+
+// plan 抽取延迟并生成内容
+func (p *LoadTestProvider) plan(req *ChatRequest) (*loadTestPlan, error) {
+	if _, err := p.GetModelConfig(req.Model); err != nil {
+		return nil, err
+	}
+
+	count, finishReason := p.opts.CompletionTokens, "stop"
+	if req.MaxTokens != nil && *req.MaxTokens > 0 && *req.MaxTokens < count {
+		count, finishReason = *req.MaxTokens, "length"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tokens := make([]string, count)
+	for i := range tokens {
+		tokens[i] = loadTestWords[p.rand.Intn(len(loadTestWords))]
+		if i > 0 {
+			tokens[i] = " " + tokens[i]
+		}
+	}
+	return &loadTestPlan{
+		id:           fmt.Sprintf("loadtest-%016x", p.rand.Uint64()),
+		created:      time.Now().Unix(),
+		firstToken:   p.opts.FirstTokenLatency.sample(p.rand),
+		tokens:       tokens,
+		finishReason: finishReason,
+	}, nil
+}
+
+// tokenInterval 相邻token之间的间隔
+func (p *LoadTestProvider) tokenInterval() time.Duration {
+	if p.opts.TokensPerSecond == 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / p.opts.TokensPerSecond)
+}
+
+// loadTestUsage 提示词按空白分隔的词数计为提示token
+func loadTestUsage(req *ChatRequest, completion int) Usage {
+	prompt := 0
+	for _, msg := range req.Messages {
+		prompt += len(strings.Fields(msg.Content))
+	}
+	return Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+}
+
+// ChatCompletion 等待完整生成所需的时间后返回全部内容
+func (p *LoadTestProvider) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	plan, err := p.plan(req)
+	if err != nil {
+		return nil, err
+	}
+	total := plan.firstToken + time.Duration(len(plan.tokens)-1)*p.tokenInterval()
+	if err := sleepContext(ctx, total); err != nil {
+		return nil, err
+	}
+
+	return &ChatResponse{
+		ID:      plan.id,
+		Object:  "chat.completion",
+		Created: plan.created,
+		Model:   req.Model,
+		Choices: []Choice{
+			{
+				Index:        0,
+				Message:      Message{Role: "assistant", Content: strings.Join(plan.tokens, "")},
+				FinishReason: plan.finishReason,
+			},
+		},
+		Usage: loadTestUsage(req, len(plan.tokens)),
+	}, nil
+}
+
+// ChatCompletionStream 按首个token延迟和token速率逐个输出 OpenAI 格式的 SSE 分块，
+// 调用方关闭响应或 ctx 取消时停止生成
+func (p *LoadTestProvider) ChatCompletionStream(ctx context.Context, req *ChatRequest) (io.ReadCloser, error) {
+	plan, err := p.plan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(p.stream(ctx, writer, req, plan))
+	}()
+	return reader, nil
+}
+
+// stream 写入全部分块，返回 nil 时读取方收到 EOF
+func (p *LoadTestProvider) stream(ctx context.Context, w io.Writer, req *ChatRequest, plan *loadTestPlan) error {
+	writeChunk := func(delta map[string]string, finishReason *string, usage *Usage) error {
+		chunk := map[string]interface{}{
+			"id":      plan.id,
+			"object":  "chat.completion.chunk",
+			"created": plan.created,
+			"model":   req.Model,
+			"choices": []map[string]interface{}{{"index": 0, "delta": delta, "finish_reason": finishReason}},
+		}
+		if usage != nil {
+			chunk["usage"] = usage
+		}
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+		return err
+	}
+
+	if err := sleepContext(ctx, plan.firstToken); err != nil {
+		return err
+	}
+	interval := p.tokenInterval()
+	for i, token := range plan.tokens {
+		if i > 0 {
+			if err := sleepContext(ctx, interval); err != nil {
+				return err
+			}
+		}
+		delta := map[string]string{"content": token}
+		if i == 0 {
+			delta["role"] = "assistant"
+		}
+		if err := writeChunk(delta, nil, nil); err != nil {
+			return err
+		}
+	}
+	usage := loadTestUsage(req, len(plan.tokens))
+	if err := writeChunk(map[string]string{}, &plan.finishReason, &usage); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "data: [DONE]\n\n")
+	return err
+}
+
+// sleepContext 等待 d 或 ctx 取消
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ListModels 列出模型（仅启用的）
+func (p *LoadTestProvider) ListModels(ctx context.Context) (map[string]*ModelConfig, error) {
+	return p.listModels(true), nil
+}
+
+// ListAllModels 列出所有模型（包括禁用的）
+func (p *LoadTestProvider) ListAllModels(ctx context.Context) (map[string]*ModelConfig, error) {
+	return p.listModels(false), nil
+}
+
+func (p *LoadTestProvider) listModels(enabledOnly bool) map[string]*ModelConfig {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	models := make(map[string]*ModelConfig, len(p.models))
+	for name, model := range p.models {
+		if enabledOnly && !model.Enabled {
+			continue
+		}
+		copied := *model
+		models[name] = &copied
+	}
+	return models
+}
+
+// GetModelConfig 获取模型配置
+func (p *LoadTestProvider) GetModelConfig(name string) (*ModelConfig, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	model, exists := p.models[name]
+	if !exists {
+		return nil, fmt.Errorf("model %s not found", name)
+	}
+	copied := *model
+	return &copied, nil
+}
+
+// EnableModel 启用模型
+func (p *LoadTestProvider) EnableModel(name string) error {
+	return p.setEnabled(name, true)
+}
+
+// DisableModel 禁用模型
+func (p *LoadTestProvider) DisableModel(name string) error {
+	return p.setEnabled(name, false)
+}
+
+func (p *LoadTestProvider) setEnabled(name string, enabled bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	model, exists := p.models[name]
+	if !exists {
+		return fmt.Errorf("model %s not found", name)
+	}
+	model.Enabled = enabled
+	return nil
+}
+
+// ValidateAPIKey 验证API密钥
+func (p *LoadTestProvider) ValidateAPIKey(ctx context.Context) error {
+	return nil // 压测提供商无需密钥
+}
+
+// SetAPIKey 设置API密钥
+func (p *LoadTestProvider) SetAPIKey(key string) error {
+	return nil
+}
+
+// SetPreviousAPIKey 设置轮换宽限期内的上一版本密钥
+func (p *LoadTestProvider) SetPreviousAPIKey(key string, expiresAt time.Time) {}
+
+// SetKeyPool 设置附加密钥
+func (p *LoadTestProvider) SetKeyPool(cfg KeyPoolConfig) {}
+
+// KeyHealth 获取各密钥的健康状态
+func (p *LoadTestProvider) KeyHealth() []KeyHealth {
+	return nil
+}
+
+// IsHealthy 检查健康状态
+func (p *LoadTestProvider) IsHealthy(ctx context.Context) bool {
+	return true
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyDistributionSample(t *testing.T) {
+	tests := []struct {
+		name     string
+		dist     LatencyDistribution
+		min, max time.Duration
+		mean     time.Duration // 为0时不检查样本均值
+	}{
+		{name: "fixed", dist: LatencyDistribution{Distribution: LatencyFixed, Mean: 200 * time.Millisecond}, min: 200 * time.Millisecond, max: 200 * time.Millisecond},
+		{name: "uniform", dist: LatencyDistribution{Distribution: LatencyUniform, Min: 100 * time.Millisecond, Max: 300 * time.Millisecond}, min: 100 * time.Millisecond, max: 300 * time.Millisecond, mean: 200 * time.Millisecond},
+		{name: "normal clamped", dist: LatencyDistribution{Distribution: LatencyNormal, Mean: 100 * time.Millisecond, StdDev: 200 * time.Millisecond, Min: 10 * time.Millisecond, Max: 150 * time.Millisecond}, min: 10 * time.Millisecond, max: 150 * time.Millisecond},
+		{name: "lognormal", dist: LatencyDistribution{Distribution: LatencyLogNormal, Mean: 500 * time.Millisecond, StdDev: 250 * time.Millisecond}, min: 0, max: time.Hour, mean: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.dist.validate())
+			r := rand.New(rand.NewSource(1))
+			var sum time.Duration
+			const n = 20000
+			for i := 0; i < n; i++ {
+				v := tt.dist.sample(r)
+				require.GreaterOrEqual(t, v, tt.min)
+				require.LessOrEqual(t, v, tt.max)
+				sum += v
+			}
+			if tt.mean > 0 {
+				assert.InDelta(t, float64(tt.mean), float64(sum/n), float64(tt.mean)/20)
+			}
+		})
+	}
+}
+
+func TestNewLoadTestProviderValidates(t *testing.T) {
+	tests := []struct {
+		name string
+		opts LoadTestOptions
+	}{
+		{name: "no tokens", opts: LoadTestOptions{}},
+		{name: "negative rate", opts: LoadTestOptions{CompletionTokens: 1, TokensPerSecond: -1}},
+		{name: "unknown distribution", opts: LoadTestOptions{CompletionTokens: 1, FirstTokenLatency: LatencyDistribution{Distribution: "pareto"}}},
+		{name: "uniform without max", opts: LoadTestOptions{CompletionTokens: 1, FirstTokenLatency: LatencyDistribution{Distribution: LatencyUniform}}},
+		{name: "min above max", opts: LoadTestOptions{CompletionTokens: 1, FirstTokenLatency: LatencyDistribution{Min: time.Second, Max: time.Millisecond}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoadTestProvider(tt.opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadTestProviderStreamPacing(t *testing.T) {
+	p, err := NewLoadTestProvider(LoadTestOptions{
+		FirstTokenLatency: LatencyDistribution{Distribution: LatencyFixed, Mean: 50 * time.Millisecond},
+		TokensPerSecond:   100,
+		CompletionTokens:  10,
+		Seed:              1,
+	})
+	require.NoError(t, err)
+	maxTokens := 5
+	req := &ChatRequest{Model: "loadtest-1", MaxTokens: &maxTokens, Messages: []Message{{Role: "user", Content: "two words"}}}
+
+	started := time.Now()
+	stream, err := p.ChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var arrivals []time.Duration
+	var content strings.Builder
+	var finishReason string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta        struct{ Content string } `json:"delta"`
+				FinishReason *string                  `json:"finish_reason"`
+			} `json:"choices"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		if chunk.Choices[0].FinishReason != nil {
+			finishReason = *chunk.Choices[0].FinishReason
+			continue
+		}
+		arrivals = append(arrivals, time.Since(started))
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, arrivals, 5, "max_tokens caps the reply")
+	assert.Equal(t, "length", finishReason)
+	assert.Len(t, strings.Fields(content.String()), 5)
+	assert.GreaterOrEqual(t, arrivals[0], 50*time.Millisecond, "first token waits for the sampled latency")
+	assert.GreaterOrEqual(t, arrivals[4]-arrivals[0], 40*time.Millisecond, "tokens follow at 100 per second")
+}
+
+func TestLoadTestProviderCancel(t *testing.T) {
+	p, err := NewLoadTestProvider(LoadTestOptions{
+		FirstTokenLatency: LatencyDistribution{Mean: time.Hour},
+		CompletionTokens:  1,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = p.ChatCompletion(ctx, &ChatRequest{Model: "loadtest-1"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stream, err := p.ChatCompletionStream(ctx, &ChatRequest{Model: "loadtest-1"})
+	require.NoError(t, err)
+	_, err = io.ReadAll(stream)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = p.ChatCompletion(context.Background(), &ChatRequest{Model: "gpt-4"})
+	assert.Error(t, err, "unknown models are rejected")
+}
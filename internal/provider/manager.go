@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -12,16 +13,19 @@ import (
 
 // Manager Provider管理器
 type Manager struct {
-	providers map[ProviderType]Provider
-	mu        sync.RWMutex
-	logger    logger.Logger
+	providers     map[ProviderType]Provider
+	allowedModels map[ProviderType][]string // 共享密钥的模型限制
+	mu            sync.RWMutex
+	modelsMu      sync.Mutex // 串行化批量模型变更
+	logger        logger.Logger
 }
 
 // NewManager 创建新的Provider管理器
 func NewManager(logger logger.Logger) *Manager {
 	return &Manager{
-		providers: make(map[ProviderType]Provider),
-		logger:    logger,
+		providers:     make(map[ProviderType]Provider),
+		allowedModels: make(map[ProviderType][]string),
+		logger:        logger,
 	}
 }
 
@@ -54,7 +58,7 @@ func (m *Manager) GetProvider(providerType ProviderType) (Provider, error) {
 		return nil, fmt.Errorf("provider %s not found", providerType)
 	}
 	
-	return provider, nil
+	return m.restrict(provider), nil
 }
 
 // GetProviderByName 根据名称获取Provider
@@ -64,7 +68,7 @@ func (m *Manager) GetProviderByName(name string) (Provider, error) {
 	
 	for _, provider := range m.providers {
 		if provider.GetName() == name {
-			return provider, nil
+			return m.restrict(provider), nil
 		}
 	}
 	
@@ -72,21 +76,21 @@ func (m *Manager) GetProviderByName(name string) (Provider, error) {
 }
 
 // ListProviders 列出所有注册的Provider
-func (m *Manager) ListProviders() []ProviderInfo {
+func (m *Manager) ListProviders(ctx context.Context) []ProviderInfo {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
 	var providers []ProviderInfo
 	for _, provider := range m.providers {
 		// 获取模型数量
-		models, err := provider.ListModels(context.Background())
+		models, err := provider.ListModels(ctx)
 		modelCount := 0
 		if err == nil {
 			modelCount = len(models)
 		}
 		
 		// 检查健康状态
-		healthy := provider.IsHealthy(context.Background())
+		healthy := provider.IsHealthy(ctx)
 		
 		providers = append(providers, ProviderInfo{
 			Type:        provider.GetType(),
@@ -96,6 +100,8 @@ func (m *Manager) ListProviders() []ProviderInfo {
 			ModelCount:  modelCount,
 		})
 	}
+	// 按类型排序，保证列表顺序稳定（ETag依赖响应内容）
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Type < providers[j].Type })
 	
 	return providers
 }
@@ -191,7 +197,7 @@ func (m *Manager) GetProviderByModelWithValidation(ctx context.Context, modelNam
 		}
 		
 		if _, exists := models[modelName]; exists {
-			return provider, nil
+			return m.restrict(provider), nil
 		}
 	}
 	
@@ -216,6 +222,8 @@ func (m *Manager) GetProviderByModel(modelName string) (Provider, error) {
 		return nil, fmt.Errorf("claude provider not implemented yet")
 	case strings.HasPrefix(modelName, "mock-"):
 		providerType = types.ProviderTypeMock
+	case strings.HasPrefix(modelName, "loadtest-"):
+		providerType = types.ProviderTypeLoadTest
 	default:
 		// 默认使用Mock提供商（免费）
 		providerType = types.ProviderTypeMock
@@ -226,7 +234,7 @@ func (m *Manager) GetProviderByModel(modelName string) (Provider, error) {
 		return nil, fmt.Errorf("provider %s not found for model %s", providerType, modelName)
 	}
 	
-	return provider, nil
+	return m.restrict(provider), nil
 }
 
 // getProviderByNameUnsafe 内部方法，不加锁获取提供商（调用者需要持有锁）
@@ -269,4 +277,49 @@ func (m *Manager) GetHealthStatus(ctx context.Context) map[ProviderType]bool {
 	}
 	
 	return status
+}
+
+// SetAllowedModels 限制Provider共享密钥可访问的模型，models 为空表示不限制
+func (m *Manager) SetAllowedModels(providerType ProviderType, models []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	if len(models) == 0 {
+		delete(m.allowedModels, providerType)
+		return
+	}
+	m.allowedModels[providerType] = append([]string(nil), models...)
+}
+
+// CheckModelAllowed 检查本次请求使用的密钥能否访问指定模型，
+// 上下文指定了密钥时使用上下文中的限制，否则使用共享密钥的限制
+func (m *Manager) CheckModelAllowed(ctx context.Context, providerType ProviderType, model string) error {
+	if _, ok := types.APIKeyFromContext(ctx); ok {
+		return checkAllowedModel(providerType, types.AllowedModelsFromContext(ctx), model)
+	}
+	
+	m.mu.RLock()
+	allowed := m.allowedModels[providerType]
+	m.mu.RUnlock()
+	return checkAllowedModel(providerType, allowed, model)
+}
+
+// restrict 包装Provider，在转发请求前检查模型限制
+func (m *Manager) restrict(provider Provider) Provider {
+	return &restrictedProvider{Provider: provider, manager: m}
+}
+
+// GetKeyHealth 获取各Provider每个密钥的健康状态，未使用密钥的Provider不包含在内
+func (m *Manager) GetKeyHealth() map[ProviderType][]KeyHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	health := make(map[ProviderType][]KeyHealth)
+	for providerType, provider := range m.providers {
+		if keys := provider.KeyHealth(); len(keys) > 0 {
+			health[providerType] = keys
+		}
+	}
+	
+	return health
 }
\ No newline at end of file
@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// mockFixedCreated 设置种子后响应使用的固定创建时间（2024-01-01T00:00:00Z）
+const mockFixedCreated = 1704067200
+
+// 未配置用量模板时的固定用量，与未设置种子时一致
+const (
+	defaultMockPromptTokens     = "50"
+	defaultMockCompletionTokens = "20"
+)
+
+// MockOptions 模拟提供商的确定性输出配置
+type MockOptions struct {
+	// Seed 非0时响应ID由该种子依次生成、创建时间固定，同样的请求序列得到完全相同的响应，便于黄金文件断言
+	Seed int64
+	// Usage 用量模板，为空的字段使用固定值 50 和 20
+	Usage MockUsage
+}
+
+// MockUsage 用量模板，使用 text/template 语法，渲染结果必须是非负整数，
+// 可用字段见 MockUsageData，可用函数 add、sub、mul、div
+type MockUsage struct {
+	PromptTokens     string `yaml:"prompt_tokens"`
+	CompletionTokens string `yaml:"completion_tokens"`
+}
+
+// MockUsageData 用量模板的数据
+type MockUsageData struct {
+	Model           string
+	Messages        int // 请求中的消息数
+	PromptChars     int // 请求消息内容的字符数
+	PromptWords     int // 请求消息内容按空白分隔的词数
+	CompletionChars int
+	CompletionWords int
+}
+
+// mockUsageTemplates 编译后的用量模板
+type mockUsageTemplates struct {
+	prompt     *template.Template
+	completion *template.Template
+}
+
+// mockUsageFuncs 用量模板可用的整数运算
+var mockUsageFuncs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+	"sub": func(a, b int) int { return a - b },
+	"mul": func(a, b int) int { return a * b },
+	"div": func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	},
+}
+
+// compileMockUsage 编译用量模板，为空的字段保持为 nil
+func compileMockUsage(usage MockUsage) (*mockUsageTemplates, error) {
+	parse := func(name, text string) (*template.Template, error) {
+		if text == "" {
+			return nil, nil
+		}
+		tmpl, err := template.New(name).Funcs(mockUsageFuncs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", name, err)
+		}
+		return tmpl, nil
+	}
+	prompt, err := parse("prompt_tokens", usage.PromptTokens)
+	if err != nil {
+		return nil, err
+	}
+	completion, err := parse("completion_tokens", usage.CompletionTokens)
+	if err != nil {
+		return nil, err
+	}
+	return &mockUsageTemplates{prompt: prompt, completion: completion}, nil
+}
+
+// compileProviderMockUsage 编译提供商的用量模板，为空的字段使用固定值
+func compileProviderMockUsage(usage MockUsage) (*mockUsageTemplates, error) {
+	if usage.PromptTokens == "" {
+		usage.PromptTokens = defaultMockPromptTokens
+	}
+	if usage.CompletionTokens == "" {
+		usage.CompletionTokens = defaultMockCompletionTokens
+	}
+	return compileMockUsage(usage)
+}
+
+// override 返回以 t 中已设置的模板覆盖 base 的结果
+func (t *mockUsageTemplates) override(base *mockUsageTemplates) *mockUsageTemplates {
+	merged := *base
+	if t.prompt != nil {
+		merged.prompt = t.prompt
+	}
+	if t.completion != nil {
+		merged.completion = t.completion
+	}
+	return &merged
+}
+
+// render 计算一次响应的用量
+func (t *mockUsageTemplates) render(req *ChatRequest, content string) (Usage, error) {
+	data := MockUsageData{
+		Model:           req.Model,
+		Messages:        len(req.Messages),
+		CompletionChars: len([]rune(content)),
+		CompletionWords: len(strings.Fields(content)),
+	}
+	for _, msg := range req.Messages {
+		data.PromptChars += len([]rune(msg.Content))
+		data.PromptWords += len(strings.Fields(msg.Content))
+	}
+
+	prompt, err := executeMockUsage(t.prompt, data)
+	if err != nil {
+		return Usage{}, err
+	}
+	completion, err := executeMockUsage(t.completion, data)
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}, nil
+}
+
+func executeMockUsage(tmpl *template.Template, data MockUsageData) (int, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return 0, fmt.Errorf("mock usage %s: %w", tmpl.Name(), err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(buf.String()))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("mock usage %s must render a non-negative integer, got %q", tmpl.Name(), buf.String())
+	}
+	return n, nil
+}
+
+// SetOptions 设置确定性输出配置并重置随机序列，模板无效时返回错误且不修改现有配置
+func (p *MockProvider) SetOptions(opts MockOptions) error {
+	usage, err := compileProviderMockUsage(opts.Usage)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usage = usage
+	p.rand = nil
+	if opts.Seed != 0 {
+		p.rand = rand.New(rand.NewSource(opts.Seed))
+	}
+	return nil
+}
+
+// responseMeta 返回下一个响应的ID和创建时间，设置种子时按种子生成
+func (p *MockProvider) responseMeta() (string, int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rand == nil {
+		now := time.Now()
+		return fmt.Sprintf("mock-%d", now.Unix()), now.Unix()
+	}
+	return fmt.Sprintf("mock-%016x", p.rand.Uint64()), mockFixedCreated
+}
+
+// newResponse 组装模拟响应，step 设置的用量模板覆盖提供商的模板
+func (p *MockProvider) newResponse(req *ChatRequest, content, finishReason string, step *MockStep) (*ChatResponse, error) {
+	p.mu.RLock()
+	usageTemplates := p.usage
+	p.mu.RUnlock()
+	if step != nil && step.usage != nil {
+		usageTemplates = step.usage.override(usageTemplates)
+	}
+	usage, err := usageTemplates.render(req, content)
+	if err != nil {
+		return nil, err
+	}
+
+	id, created := p.responseMeta()
+	return &ChatResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   req.Model,
+		Choices: []Choice{
+			{
+				Index:        0,
+				Message:      Message{Role: "assistant", Content: content},
+				FinishReason: finishReason,
+			},
+		},
+		Usage: usage,
+	}, nil
+}
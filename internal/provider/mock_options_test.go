@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockProviderSeed(t *testing.T) {
+	ctx := context.Background()
+	req := &ChatRequest{Model: "mock-gpt-3.5-turbo", Messages: []Message{{Role: "user", Content: "hello there"}}}
+
+	run := func() []*ChatResponse {
+		p := NewMockProvider("mock", types.ProviderTypeMock)
+		require.NoError(t, p.SetOptions(MockOptions{Seed: 42}))
+		var responses []*ChatResponse
+		for i := 0; i < 3; i++ {
+			resp, err := p.ChatCompletion(ctx, req)
+			require.NoError(t, err)
+			responses = append(responses, resp)
+		}
+		return responses
+	}
+
+	first, second := run(), run()
+	assert.Equal(t, first, second, "the same seed must produce identical responses")
+	assert.NotEqual(t, first[0].ID, first[1].ID, "IDs must still differ within a run")
+	assert.Equal(t, int64(mockFixedCreated), first[0].Created)
+	assert.Equal(t, Usage{PromptTokens: 50, CompletionTokens: 20, TotalTokens: 70}, first[0].Usage)
+
+	other := NewMockProvider("mock", types.ProviderTypeMock)
+	require.NoError(t, other.SetOptions(MockOptions{Seed: 7}))
+	resp, err := other.ChatCompletion(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, first[0].ID, resp.ID)
+}
+
+func TestMockProviderUsageTemplates(t *testing.T) {
+	req := &ChatRequest{Model: "mock-gpt-3.5-turbo", Messages: []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "analyze AAPL"},
+	}}
+
+	tests := []struct {
+		name    string
+		usage   MockUsage
+		want    Usage
+		wantErr bool
+	}{
+		{name: "Defaults", want: Usage{PromptTokens: 50, CompletionTokens: 20, TotalTokens: 70}},
+		{name: "Constant", usage: MockUsage{PromptTokens: "100", CompletionTokens: "5"}, want: Usage{PromptTokens: 100, CompletionTokens: 5, TotalTokens: 105}},
+		{name: "Derived from the request", usage: MockUsage{PromptTokens: "{{add .PromptWords .Messages}}"}, want: Usage{PromptTokens: 6, CompletionTokens: 20, TotalTokens: 26}},
+		{name: "Characters per token", usage: MockUsage{PromptTokens: "{{div .PromptChars 4}}"}, want: Usage{PromptTokens: 5, CompletionTokens: 20, TotalTokens: 25}},
+		{name: "Invalid template", usage: MockUsage{PromptTokens: "{{add"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMockProvider("mock", types.ProviderTypeMock)
+			err := p.SetOptions(MockOptions{Usage: tt.usage})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp, err := p.ChatCompletion(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Usage)
+		})
+	}
+
+	// 渲染结果不是整数时请求失败
+	p := NewMockProvider("mock", types.ProviderTypeMock)
+	require.NoError(t, p.SetOptions(MockOptions{Usage: MockUsage{CompletionTokens: "{{.Model}}"}}))
+	_, err := p.ChatCompletion(context.Background(), req)
+	assert.ErrorContains(t, err, "non-negative integer")
+}
+
+func TestMockScenarioStepUsage(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "usage.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+scenarios:
+  - steps:
+      - content: four words of reply
+        usage: {completion_tokens: "{{.CompletionWords}}"}
+`), 0o644))
+	scenarios, err := LoadMockScenarios([]string{file})
+	require.NoError(t, err)
+
+	p := NewMockProvider("mock", types.ProviderTypeMock)
+	require.NoError(t, p.SetOptions(MockOptions{Usage: MockUsage{PromptTokens: "7"}}))
+	p.SetScenarios(scenarios)
+	resp, err := p.ChatCompletion(context.Background(), &ChatRequest{Model: "mock-gpt-3.5-turbo", Messages: []Message{{Role: "user", Content: "hi"}}})
+	require.NoError(t, err)
+	assert.Equal(t, Usage{PromptTokens: 7, CompletionTokens: 4, TotalTokens: 11}, resp.Usage)
+}
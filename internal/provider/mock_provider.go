@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +17,9 @@ type MockProvider struct {
 	name string
 	providerType ProviderType
 	models map[string]*ModelConfig
+	scenarios []MockScenario
+	usage *mockUsageTemplates // 用量模板
+	rand *rand.Rand           // 设置种子时生成响应ID
 	mu sync.RWMutex
 }
 
@@ -27,6 +33,8 @@ func NewMockProvider(name string, providerType ProviderType) *MockProvider {
 	
 	// 初始化默认模型配置
 	p.initDefaultModels()
+	// 默认模板只包含常量，不会出错
+	p.usage, _ = compileProviderMockUsage(MockUsage{})
 	
 	return p
 }
@@ -55,8 +63,33 @@ func (p *MockProvider) GetName() string {
 	return p.name
 }
 
+// SetScenarios 设置脚本场景，匹配场景的请求不再使用内置的模拟响应
+func (p *MockProvider) SetScenarios(scenarios []MockScenario) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.scenarios = scenarios
+}
+
+// SetModels 替换模型列表，沙箱模式下模拟提供商以真实提供商的模型名称应答
+func (p *MockProvider) SetModels(models map[string]*ModelConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.models = make(map[string]*ModelConfig, len(models))
+	for name, model := range models {
+		copied := *model
+		p.models[name] = &copied
+	}
+}
+
 // ChatCompletion 模拟聊天完成
 func (p *MockProvider) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	p.mu.RLock()
+	_, step := findMockStep(p.scenarios, req)
+	p.mu.RUnlock()
+	if step != nil {
+		return p.scriptedResponse(ctx, req, step)
+	}
+
 	// 检查是否有系统消息包含工具信息
 	hasToolInfo := false
 	userMessage := ""
@@ -163,34 +196,55 @@ func (p *MockProvider) ChatCompletion(ctx context.Context, req *ChatRequest) (*C
 		responseContent = fmt.Sprintf("这是来自 %s 提供商的模拟响应，当前使用的模型是: %s。您的消息是: %s", p.name, req.Model, userMessage)
 	}
 	
-	response := &ChatResponse{
-		ID:      fmt.Sprintf("mock-%d", time.Now().Unix()),
-		Object:  "chat.completion",
-		Created: time.Now().Unix(),
-		Model:   req.Model,
-		Choices: []Choice{
-			{
-				Index: 0,
-				Message: Message{
-					Role:    "assistant",
-					Content: responseContent,
-				},
-				FinishReason: "stop",
-			},
-		},
-		Usage: Usage{
-			PromptTokens:     50,
-			CompletionTokens: 20,
-			TotalTokens:      70,
-		},
-	}
-
-	return response, nil
+	return p.newResponse(req, responseContent, "stop", nil)
 }
 
-// ChatCompletionStream 模拟流式聊天完成
+// mockStreamChunkSize 模拟流式响应每个分块的字符数
+const mockStreamChunkSize = 8
+
+// ChatCompletionStream 模拟流式聊天完成，将非流式响应按固定长度切分为 OpenAI 格式的 SSE 分块
 func (p *MockProvider) ChatCompletionStream(ctx context.Context, req *ChatRequest) (io.ReadCloser, error) {
-	return nil, fmt.Errorf("stream not implemented for mock provider")
+	resp, err := p.ChatCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writeChunk := func(delta map[string]string, finishReason *string) error {
+		data, err := json.Marshal(map[string]interface{}{
+			"id":      resp.ID,
+			"object":  "chat.completion.chunk",
+			"created": resp.Created,
+			"model":   resp.Model,
+			"choices": []map[string]interface{}{{"index": 0, "delta": delta, "finish_reason": finishReason}},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "data: %s\n\n", data)
+		return nil
+	}
+
+	content := []rune(resp.Choices[0].Message.Content)
+	for start := 0; start < len(content); start += mockStreamChunkSize {
+		end := start + mockStreamChunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		delta := map[string]string{"content": string(content[start:end])}
+		if start == 0 {
+			delta["role"] = "assistant"
+		}
+		if err := writeChunk(delta, nil); err != nil {
+			return nil, err
+		}
+	}
+	finishReason := resp.Choices[0].FinishReason
+	if err := writeChunk(map[string]string{}, &finishReason); err != nil {
+		return nil, err
+	}
+	buf.WriteString("data: [DONE]\n\n")
+	return io.NopCloser(&buf), nil
 }
 
 // ListModels 列出模型（仅启用的）
@@ -209,6 +263,7 @@ func (p *MockProvider) ListModels(ctx context.Context) (map[string]*ModelConfig,
 				Temperature: model.Temperature,
 				TopP:        model.TopP,
 				Enabled:     model.Enabled,
+				Unavailable: model.Unavailable,
 			}
 		}
 	}
@@ -230,6 +285,7 @@ func (p *MockProvider) ListAllModels(ctx context.Context) (map[string]*ModelConf
 			Temperature: model.Temperature,
 			TopP:        model.TopP,
 			Enabled:     model.Enabled,
+			Unavailable: model.Unavailable,
 		}
 	}
 	return allModels, nil
@@ -249,6 +305,7 @@ func (p *MockProvider) GetModelConfig(name string) (*ModelConfig, error) {
 			Temperature: model.Temperature,
 			TopP:        model.TopP,
 			Enabled:     model.Enabled,
+			Unavailable: model.Unavailable,
 		}, nil
 	}
 	return nil, fmt.Errorf("model %s not found", name)
@@ -278,6 +335,30 @@ func (p *MockProvider) DisableModel(name string) error {
 	return fmt.Errorf("model %s not found", name)
 }
 
+// AddModel 添加禁用状态的模型
+func (p *MockProvider) AddModel(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if _, exists := p.models[name]; exists {
+		return fmt.Errorf("model %s already exists", name)
+	}
+	p.models[name] = &ModelConfig{Name: name, DisplayName: name, MaxTokens: 4096, Temperature: 0.7, TopP: 1.0}
+	return nil
+}
+
+// SetModelUnavailable 标记模型是否已不再由提供商提供
+func (p *MockProvider) SetModelUnavailable(name string, unavailable bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if model, exists := p.models[name]; exists {
+		model.Unavailable = unavailable
+		return nil
+	}
+	return fmt.Errorf("model %s not found", name)
+}
+
 // ValidateAPIKey 验证API密钥
 func (p *MockProvider) ValidateAPIKey(ctx context.Context) error {
 	return nil // 模拟验证成功
@@ -288,6 +369,21 @@ func (p *MockProvider) SetAPIKey(key string) error {
 	return nil // 模拟设置成功
 }
 
+// SetPreviousAPIKey 设置轮换宽限期内的上一版本密钥
+func (p *MockProvider) SetPreviousAPIKey(key string, expiresAt time.Time) {
+	// 模拟提供商无需密钥
+}
+
+// SetKeyPool 设置附加密钥
+func (p *MockProvider) SetKeyPool(cfg KeyPoolConfig) {
+	// 模拟提供商无需密钥
+}
+
+// KeyHealth 获取各密钥的健康状态
+func (p *MockProvider) KeyHealth() []KeyHealth {
+	return nil
+}
+
 // IsHealthy 检查健康状态
 func (p *MockProvider) IsHealthy(ctx context.Context) bool {
 	return true // 模拟健康
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"go-springAi/internal/types"
+
+	"github.com/goccy/go-yaml"
+)
+
+// MockScenario 模拟提供商的脚本场景
+//
+// 用户消息匹配 Match 后，按该消息之后的助手消息数依次返回 Steps，
+// 超出步骤数时重复最后一步，因此工具调用后的最终回复会取到下一步。
+type MockScenario struct {
+	Name  string     `yaml:"name"`
+	Match string     `yaml:"match"` // 匹配用户消息的正则，为空时匹配任意消息
+	Model string     `yaml:"model"` // 非空时只对该模型生效
+	Steps []MockStep `yaml:"steps"`
+
+	pattern *regexp.Regexp
+}
+
+// MockStep 场景中的一轮响应
+type MockStep struct {
+	Content      string         `yaml:"content"`
+	ToolCalls    []MockToolCall `yaml:"tool_calls"`    // 以 {"tool_call": ...} 行追加在内容之后
+	LatencyMS    int            `yaml:"latency_ms"`    // 返回前等待的毫秒数
+	Error        *MockError     `yaml:"error"`         // 非空时返回错误而不是响应
+	FinishReason string         `yaml:"finish_reason"` // 默认 stop
+	Usage        *MockUsage     `yaml:"usage"`         // 覆盖提供商用量模板中设置的字段
+
+	usage *mockUsageTemplates
+}
+
+// MockToolCall 场景返回的工具调用
+type MockToolCall struct {
+	Name      string                 `yaml:"name"`
+	Arguments map[string]interface{} `yaml:"arguments"`
+}
+
+// MockError 场景注入的提供商错误
+type MockError struct {
+	Status  int    `yaml:"status"` // 401/403 视为密钥被拒绝，429 视为限流
+	Message string `yaml:"message"`
+}
+
+// Error 实现 error 接口
+func (e *MockError) Error() string {
+	return fmt.Sprintf("mock provider returned status %d: %s", e.Status, e.Message)
+}
+
+// Unwrap 返回与真实提供商一致的哨兵错误，密钥和限流处理逻辑可以识别
+func (e *MockError) Unwrap() error {
+	switch e.Status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return types.ErrProviderUnauthorized
+	case http.StatusTooManyRequests:
+		return types.ErrProviderRateLimited
+	}
+	return nil
+}
+
+// mockScenarioFile 场景文件格式，JSON 文件同样可以解析
+type mockScenarioFile struct {
+	Scenarios []MockScenario `yaml:"scenarios"`
+}
+
+// LoadMockScenarios 按顺序加载场景文件，支持通配符，先加载的场景优先匹配
+func LoadMockScenarios(patterns []string) ([]MockScenario, error) {
+	var scenarios []MockScenario
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mock scenario pattern %q: %w", pattern, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no mock scenario file matches %q", pattern)
+		}
+		for _, file := range files {
+			loaded, err := loadMockScenarioFile(file)
+			if err != nil {
+				return nil, err
+			}
+			scenarios = append(scenarios, loaded...)
+		}
+	}
+	return scenarios, nil
+}
+
+// loadMockScenarioFile 解析并校验单个场景文件
+func loadMockScenarioFile(file string) ([]MockScenario, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read mock scenario file: %w", err)
+	}
+	var parsed mockScenarioFile
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parse mock scenario file %s: %w", file, err)
+	}
+
+	for i := range parsed.Scenarios {
+		scenario := &parsed.Scenarios[i]
+		if scenario.Name == "" {
+			scenario.Name = fmt.Sprintf("%s#%d", filepath.Base(file), i+1)
+		}
+		if err := scenario.compile(); err != nil {
+			return nil, fmt.Errorf("mock scenario %s in %s: %w", scenario.Name, file, err)
+		}
+	}
+	return parsed.Scenarios, nil
+}
+
+// compile 校验场景并编译匹配正则
+func (s *MockScenario) compile() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	for i := range s.Steps {
+		step := &s.Steps[i]
+		if step.LatencyMS < 0 {
+			return fmt.Errorf("step %d: latency_ms must not be negative", i+1)
+		}
+		if step.Error != nil && (step.Error.Status < 400 || step.Error.Status > 599) {
+			return fmt.Errorf("step %d: error status must be between 400 and 599", i+1)
+		}
+		for _, call := range step.ToolCalls {
+			if call.Name == "" {
+				return fmt.Errorf("step %d: tool call name is required", i+1)
+			}
+		}
+		if step.Usage != nil {
+			usage, err := compileMockUsage(*step.Usage)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			step.usage = usage
+		}
+	}
+	pattern, err := regexp.Compile(s.Match)
+	if err != nil {
+		return fmt.Errorf("invalid match pattern: %w", err)
+	}
+	s.pattern = pattern
+	return nil
+}
+
+// findMockStep 从最后一条用户消息往前查找第一个匹配的场景，并按之后的助手消息数确定步骤
+func findMockStep(scenarios []MockScenario, req *ChatRequest) (*MockScenario, *MockStep) {
+	assistantTurns := 0
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		msg := req.Messages[i]
+		switch msg.Role {
+		case "assistant":
+			assistantTurns++
+			continue
+		case "user":
+		default:
+			continue
+		}
+
+		for j := range scenarios {
+			scenario := &scenarios[j]
+			if scenario.Model != "" && scenario.Model != req.Model {
+				continue
+			}
+			if scenario.pattern.MatchString(msg.Content) {
+				step := assistantTurns
+				if step >= len(scenario.Steps) {
+					step = len(scenario.Steps) - 1
+				}
+				return scenario, &scenario.Steps[step]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// scriptedResponse 按场景步骤等待并返回响应或错误
+func (p *MockProvider) scriptedResponse(ctx context.Context, req *ChatRequest, step *MockStep) (*ChatResponse, error) {
+	if step.LatencyMS > 0 {
+		timer := time.NewTimer(time.Duration(step.LatencyMS) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if step.Error != nil {
+		return nil, step.Error
+	}
+
+	content := step.Content
+	for _, call := range step.ToolCalls {
+		arguments := call.Arguments
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		line, err := json.Marshal(map[string]interface{}{
+			"tool_call": map[string]interface{}{"name": call.Name, "arguments": arguments},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("encode mock tool call %s: %w", call.Name, err)
+		}
+		if content != "" {
+			content += "\n"
+		}
+		content += string(line)
+	}
+
+	finishReason := step.FinishReason
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+	return p.newResponse(req, content, finishReason, step)
+}
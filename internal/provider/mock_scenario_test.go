@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenarios = `
+scenarios:
+  - name: analyze
+    match: "(?i)analy[sz]e (\\w+)"
+    steps:
+      - content: Let me look that up.
+        tool_calls:
+          - name: stock_analysis
+            arguments: {symbol: AAPL, period: 1y}
+      - content: AAPL looks stable.
+  - name: rate-limited
+    match: "^slow"
+    model: mock-gpt-3.5-turbo
+    steps:
+      - latency_ms: 50
+        error: {status: 429, message: slow down}
+`
+
+func TestMockProviderScenarios(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agent.yaml"), []byte(testScenarios), 0o644))
+	scenarios, err := LoadMockScenarios([]string{filepath.Join(dir, "*.yaml")})
+	require.NoError(t, err)
+	require.Len(t, scenarios, 2)
+
+	p := NewMockProvider("mock", types.ProviderTypeMock)
+	p.SetScenarios(scenarios)
+
+	tests := []struct {
+		name        string
+		model       string
+		messages    []Message
+		wantContent string
+		wantErr     error
+	}{
+		{
+			name:        "First step returns tool call",
+			model:       "mock-gpt-3.5-turbo",
+			messages:    []Message{{Role: "system", Content: "tools"}, {Role: "user", Content: "Please analyze AAPL"}},
+			wantContent: "Let me look that up.\n" + `{"tool_call":{"arguments":{"period":"1y","symbol":"AAPL"},"name":"stock_analysis"}}`,
+		},
+		{
+			name:  "Step follows assistant turns",
+			model: "mock-gpt-3.5-turbo",
+			messages: []Message{
+				{Role: "user", Content: "Please analyze AAPL"},
+				{Role: "assistant", Content: "tool results"},
+				{Role: "user", Content: "Write the final answer"},
+			},
+			wantContent: "AAPL looks stable.",
+		},
+		{
+			name:     "Injected error",
+			model:    "mock-gpt-3.5-turbo",
+			messages: []Message{{Role: "user", Content: "slow request"}},
+			wantErr:  types.ErrProviderRateLimited,
+		},
+		{
+			name:        "Scenario limited to another model",
+			model:       "mock-other",
+			messages:    []Message{{Role: "user", Content: "slow request"}},
+			wantContent: "这是来自 mock 提供商的模拟响应，当前使用的模型是: mock-other。您的消息是: slow request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.ChatCompletion(context.Background(), &ChatRequest{Model: tt.model, Messages: tt.messages})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContent, resp.Choices[0].Message.Content)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.ChatCompletion(ctx, &ChatRequest{Model: "mock-gpt-3.5-turbo", Messages: []Message{{Role: "user", Content: "slow"}}})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoadMockScenariosErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "No steps", content: "scenarios:\n  - match: hi\n"},
+		{name: "Invalid pattern", content: "scenarios:\n  - match: \"(\"\n    steps: [{content: hi}]\n"},
+		{name: "Invalid error status", content: "scenarios:\n  - steps: [{error: {status: 200}}]\n"},
+		{name: "Tool call without name", content: "scenarios:\n  - steps: [{tool_calls: [{arguments: {}}]}]\n"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "bad"+string(rune('a'+i))+".yaml")
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0o644))
+			_, err := LoadMockScenarios([]string{file})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadMockScenarios([]string{filepath.Join(dir, "missing-*.yaml")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-springAi/internal/types"
+)
+
+// restrictedProvider 在转发请求前检查密钥的模型限制
+type restrictedProvider struct {
+	Provider
+	manager *Manager
+}
+
+// ChatCompletion 聊天完成，模型不在密钥允许范围内时直接拒绝
+func (p *restrictedProvider) ChatCompletion(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := p.manager.CheckModelAllowed(ctx, p.GetType(), req.Model); err != nil {
+		return nil, err
+	}
+	return p.Provider.ChatCompletion(ctx, req)
+}
+
+// ChatCompletionStream 流式聊天完成，模型不在密钥允许范围内时直接拒绝
+func (p *restrictedProvider) ChatCompletionStream(ctx context.Context, req *ChatRequest) (io.ReadCloser, error) {
+	if err := p.manager.CheckModelAllowed(ctx, p.GetType(), req.Model); err != nil {
+		return nil, err
+	}
+	return p.Provider.ChatCompletionStream(ctx, req)
+}
+
+// checkAllowedModel 检查模型是否在允许列表中，allowed 为空表示不限制
+func checkAllowedModel(providerType ProviderType, allowed []string, model string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, m := range allowed {
+		if m == model {
+			return nil
+		}
+	}
+	if model == "" {
+		return fmt.Errorf("%w: %s API key is restricted to %s, a model must be specified",
+			types.ErrModelNotAllowed, providerType, strings.Join(allowed, ", "))
+	}
+	return fmt.Errorf("%w: %s API key is restricted to %s, %s is not allowed",
+		types.ErrModelNotAllowed, providerType, strings.Join(allowed, ", "), model)
+}
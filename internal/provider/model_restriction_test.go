@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-springAi/internal/logger"
+	"go-springAi/internal/types"
+
+	"go.uber.org/zap"
+)
+
+func TestCheckAllowedModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		model   string
+		wantErr bool
+	}{
+		{name: "No restriction", model: "gpt-4o"},
+		{name: "Allowed model", allowed: []string{"gpt-4o-mini"}, model: "gpt-4o-mini"},
+		{name: "Other model rejected", allowed: []string{"gpt-4o-mini"}, model: "gpt-4o", wantErr: true},
+		{name: "Empty model rejected when restricted", allowed: []string{"gpt-4o-mini"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAllowedModel(types.ProviderTypeOpenAI, tt.allowed, tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, types.ErrModelNotAllowed) {
+				t.Errorf("expected ErrModelNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestManagerCheckModelAllowedContext(t *testing.T) {
+	m := NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+	m.SetAllowedModels(types.ProviderTypeOpenAI, []string{"gpt-4o-mini"})
+
+	if err := m.CheckModelAllowed(context.Background(), types.ProviderTypeOpenAI, "gpt-4o"); err == nil {
+		t.Error("expected shared key restriction to reject gpt-4o")
+	}
+
+	// 请求携带自己的密钥时只使用该密钥的限制
+	ctx := types.WithAPIKey(context.Background(), "project-key")
+	if err := m.CheckModelAllowed(ctx, types.ProviderTypeOpenAI, "gpt-4o"); err != nil {
+		t.Errorf("expected unrestricted project key to allow gpt-4o, got %v", err)
+	}
+	ctx = types.WithAllowedModels(ctx, []string{"gpt-4o"})
+	if err := m.CheckModelAllowed(ctx, types.ProviderTypeOpenAI, "gpt-4o-mini"); err == nil {
+		t.Error("expected project key restriction to reject gpt-4o-mini")
+	}
+}
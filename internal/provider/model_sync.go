@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go-springAi/internal/logger"
+	"go-springAi/internal/types"
+)
+
+// RemoteModelLister 能从提供商接口获取当前可用模型的提供商
+type RemoteModelLister interface {
+	// ListRemoteModels 返回提供商当前提供的模型名称
+	ListRemoteModels(ctx context.Context) ([]string, error)
+}
+
+// ModelCatalog 能修改本地模型配置的提供商，模型同步据此新增和标记模型
+type ModelCatalog interface {
+	// AddModel 以默认参数添加禁用状态的模型
+	AddModel(name string) error
+	// SetModelUnavailable 标记模型是否已不再由提供商提供
+	SetModelUnavailable(name string, unavailable bool) error
+}
+
+// SyncModels 将各提供商当前提供的模型与本地配置对比，报告新增和下线的模型。
+// 实现 ModelCatalog 的提供商会以禁用状态添加新模型，并标记已下线的模型；
+// disableMissing 为 true 时禁用已下线但仍启用的模型。
+// 不支持获取远程模型的提供商被跳过，部分提供商失败时返回其余结果和合并后的错误
+func (m *Manager) SyncModels(ctx context.Context, disableMissing bool) ([]types.ModelSyncResult, error) {
+	m.mu.RLock()
+	providers := make([]Provider, 0, len(m.providers))
+	for _, p := range m.providers {
+		providers = append(providers, p)
+	}
+	m.mu.RUnlock()
+	sort.Slice(providers, func(i, j int) bool { return providers[i].GetType() < providers[j].GetType() })
+
+	var results []types.ModelSyncResult
+	var errs []error
+	for _, p := range providers {
+		lister, ok := p.(RemoteModelLister)
+		if !ok {
+			continue
+		}
+		result, err := m.syncProviderModels(ctx, p, lister, disableMissing)
+		if err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", p.GetType(), err))
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// syncProviderModels 同步单个提供商的模型
+func (m *Manager) syncProviderModels(ctx context.Context, p Provider, lister RemoteModelLister, disableMissing bool) (types.ModelSyncResult, error) {
+	result := types.ModelSyncResult{Provider: p.GetType(), New: []string{}, Missing: []string{}}
+
+	remote, err := lister.ListRemoteModels(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list remote models: %w", err)
+	}
+	local, err := p.ListAllModels(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list configured models: %w", err)
+	}
+
+	available := make(map[string]bool, len(remote))
+	for _, name := range remote {
+		available[name] = true
+	}
+	result.Remote = len(available)
+	for name := range available {
+		if _, ok := local[name]; !ok {
+			result.New = append(result.New, name)
+		}
+	}
+	for name := range local {
+		if !available[name] {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+	sort.Strings(result.New)
+	sort.Strings(result.Missing)
+
+	m.modelsMu.Lock()
+	defer m.modelsMu.Unlock()
+	if catalog, ok := p.(ModelCatalog); ok {
+		if err := m.reconcileCatalog(p, catalog, local, available, &result); err != nil {
+			return result, err
+		}
+	}
+	if !disableMissing {
+		return result, nil
+	}
+	for _, name := range result.Missing {
+		if !local[name].Enabled {
+			continue
+		}
+		if err := p.DisableModel(name); err != nil {
+			return result, fmt.Errorf("disable model %s: %w", name, err)
+		}
+		result.Disabled = append(result.Disabled, name)
+		m.logger.Info("Model no longer offered by provider, disabled",
+			logger.String("provider", string(p.GetType())),
+			logger.String("model", name))
+	}
+	return result, nil
+}
+
+// reconcileCatalog 以禁用状态添加新模型，并按远程列表更新模型的下线标记
+func (m *Manager) reconcileCatalog(p Provider, catalog ModelCatalog, local map[string]*ModelConfig, available map[string]bool, result *types.ModelSyncResult) error {
+	for _, name := range result.New {
+		if err := catalog.AddModel(name); err != nil {
+			return fmt.Errorf("add model %s: %w", name, err)
+		}
+		result.Added = append(result.Added, name)
+		m.logger.Info("Model offered by provider, added disabled",
+			logger.String("provider", string(p.GetType())),
+			logger.String("model", name))
+	}
+	for _, name := range result.Missing {
+		if local[name].Unavailable {
+			continue
+		}
+		if err := catalog.SetModelUnavailable(name, true); err != nil {
+			return fmt.Errorf("flag model %s: %w", name, err)
+		}
+		result.Flagged = append(result.Flagged, name)
+	}
+
+	var restored []string
+	for name, model := range local {
+		if model.Unavailable && available[name] {
+			restored = append(restored, name)
+		}
+	}
+	sort.Strings(restored)
+	for _, name := range restored {
+		if err := catalog.SetModelUnavailable(name, false); err != nil {
+			return fmt.Errorf("unflag model %s: %w", name, err)
+		}
+		result.Restored = append(result.Restored, name)
+	}
+	return nil
+}
+
+// AddModel 以禁用状态向提供商的模型配置添加模型，模型已配置时不做修改并返回 false，
+// 用于登记微调等方式产生的新模型，管理员启用后才能使用
+func (m *Manager) AddModel(ctx context.Context, providerType ProviderType, name string) (bool, error) {
+	m.mu.RLock()
+	p, ok := m.providers[providerType]
+	m.mu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("provider %s not found", providerType)
+	}
+	catalog, ok := p.(ModelCatalog)
+	if !ok {
+		return false, fmt.Errorf("provider %s does not support adding models", providerType)
+	}
+
+	m.modelsMu.Lock()
+	defer m.modelsMu.Unlock()
+	local, err := p.ListAllModels(ctx)
+	if err != nil {
+		return false, fmt.Errorf("list configured models: %w", err)
+	}
+	if _, exists := local[name]; exists {
+		return false, nil
+	}
+	if err := catalog.AddModel(name); err != nil {
+		return false, fmt.Errorf("add model %s: %w", name, err)
+	}
+	m.logger.Info("Model added disabled",
+		logger.String("provider", string(providerType)),
+		logger.String("model", name))
+	return true, nil
+}
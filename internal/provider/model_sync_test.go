@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"go-springAi/internal/logger"
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// remoteProvider 返回固定远程模型列表的提供商
+type remoteProvider struct {
+	*MockProvider
+	remote []string
+	err    error
+}
+
+func (p *remoteProvider) ListRemoteModels(ctx context.Context) ([]string, error) {
+	return p.remote, p.err
+}
+
+func TestManagerSyncModels(t *testing.T) {
+	tests := []struct {
+		name            string
+		remote          []string
+		remoteErr       error
+		unavailable     []string
+		disableMissing  bool
+		wantResult      types.ModelSyncResult
+		wantErr         bool
+		wantEnabled     map[string]bool
+		wantUnavailable []string
+	}{
+		{
+			name:            "Reconcile",
+			remote:          []string{"mock-gpt-4o", "mock-gpt-5"},
+			wantResult:      types.ModelSyncResult{Provider: types.ProviderTypeOpenAI, Remote: 2, New: []string{"mock-gpt-5"}, Missing: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"}, Added: []string{"mock-gpt-5"}, Flagged: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"}},
+			wantEnabled:     map[string]bool{"mock-gpt-3.5-turbo": true, "mock-gpt-4": false, "mock-gpt-4o": true, "mock-gpt-5": false},
+			wantUnavailable: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"},
+		},
+		{
+			name:            "Disable missing",
+			remote:          []string{"mock-gpt-4o", "mock-gpt-5"},
+			disableMissing:  true,
+			wantResult:      types.ModelSyncResult{Provider: types.ProviderTypeOpenAI, Remote: 2, New: []string{"mock-gpt-5"}, Missing: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"}, Added: []string{"mock-gpt-5"}, Flagged: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"}, Disabled: []string{"mock-gpt-3.5-turbo"}},
+			wantEnabled:     map[string]bool{"mock-gpt-3.5-turbo": false, "mock-gpt-4": false, "mock-gpt-4o": true, "mock-gpt-5": false},
+			wantUnavailable: []string{"mock-gpt-3.5-turbo", "mock-gpt-4"},
+		},
+		{
+			name:            "Flags kept and cleared",
+			remote:          []string{"mock-gpt-3.5-turbo", "mock-gpt-4o"},
+			unavailable:     []string{"mock-gpt-4", "mock-gpt-4o"},
+			wantResult:      types.ModelSyncResult{Provider: types.ProviderTypeOpenAI, Remote: 2, New: []string{}, Missing: []string{"mock-gpt-4"}, Restored: []string{"mock-gpt-4o"}},
+			wantEnabled:     map[string]bool{"mock-gpt-3.5-turbo": true, "mock-gpt-4": false, "mock-gpt-4o": true},
+			wantUnavailable: []string{"mock-gpt-4"},
+		},
+		{
+			name:        "Provider error",
+			remoteErr:   errors.New("unauthorized"),
+			wantResult:  types.ModelSyncResult{Provider: types.ProviderTypeOpenAI, New: []string{}, Missing: []string{}, Error: "list remote models: unauthorized"},
+			wantErr:     true,
+			wantEnabled: map[string]bool{"mock-gpt-3.5-turbo": true, "mock-gpt-4": false, "mock-gpt-4o": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockProvider("OpenAI", types.ProviderTypeOpenAI)
+			mock.models["mock-gpt-4"] = &ModelConfig{Name: "mock-gpt-4", Enabled: false}
+			mock.models["mock-gpt-4o"] = &ModelConfig{Name: "mock-gpt-4o", Enabled: true}
+			for _, name := range tt.unavailable {
+				mock.models[name].Unavailable = true
+			}
+			m := NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+			require.NoError(t, m.RegisterProvider(&remoteProvider{MockProvider: mock, remote: tt.remote, err: tt.remoteErr}))
+			// 不支持获取远程模型的提供商被跳过
+			require.NoError(t, m.RegisterProvider(NewMockProvider("Google AI", types.ProviderTypeGoogleAI)))
+
+			results, err := m.SyncModels(context.Background(), tt.disableMissing)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, []types.ModelSyncResult{tt.wantResult}, results)
+
+			enabled := make(map[string]bool)
+			var unavailable []string
+			for name, model := range mock.models {
+				enabled[name] = model.Enabled
+				if model.Unavailable {
+					unavailable = append(unavailable, name)
+				}
+			}
+			sort.Strings(unavailable)
+			assert.Equal(t, tt.wantEnabled, enabled)
+			assert.Equal(t, tt.wantUnavailable, unavailable)
+		})
+	}
+}
+
+func TestManagerAddModel(t *testing.T) {
+	mock := NewMockProvider("OpenAI", types.ProviderTypeOpenAI)
+	m := NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+	require.NoError(t, m.RegisterProvider(mock))
+	ctx := context.Background()
+
+	added, err := m.AddModel(ctx, types.ProviderTypeOpenAI, "ft:mock-gpt-4o:acme::abc123")
+	require.NoError(t, err)
+	assert.True(t, added)
+	require.Contains(t, mock.models, "ft:mock-gpt-4o:acme::abc123")
+	assert.False(t, mock.models["ft:mock-gpt-4o:acme::abc123"].Enabled)
+
+	// 已配置的模型保持不变
+	mock.models["ft:mock-gpt-4o:acme::abc123"].Enabled = true
+	added, err = m.AddModel(ctx, types.ProviderTypeOpenAI, "ft:mock-gpt-4o:acme::abc123")
+	require.NoError(t, err)
+	assert.False(t, added)
+	assert.True(t, mock.models["ft:mock-gpt-4o:acme::abc123"].Enabled)
+
+	_, err = m.AddModel(ctx, types.ProviderTypeGoogleAI, "tuned")
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"go-springAi/internal/logger"
+)
+
+// 批量模型变更中单个模型的处理状态
+const (
+	ModelChangeUpdated    = "updated"     // 已启用或禁用
+	ModelChangeUnchanged  = "unchanged"   // 模型已处于目标状态
+	ModelChangeInvalid    = "invalid"     // 模型不存在或重复出现
+	ModelChangeSkipped    = "skipped"     // 其他模型校验失败，未处理
+	ModelChangeFailed     = "failed"      // 变更失败
+	ModelChangeRolledBack = "rolled_back" // 已变更，但因其他模型失败而恢复
+)
+
+var (
+	// ErrModelChangesRejected 校验未通过，没有模型被修改
+	ErrModelChangesRejected = errors.New("model changes rejected")
+	// ErrModelChangesFailed 应用变更时失败，已变更的模型已恢复
+	ErrModelChangesFailed = errors.New("model changes failed")
+)
+
+// ModelChange 批量启用/禁用中的单个模型变更
+type ModelChange struct {
+	Model   string
+	Enabled bool
+}
+
+// ModelChangeResult 单个模型变更的处理结果
+type ModelChangeResult struct {
+	Model   string `json:"model"`
+	Enabled bool   `json:"enabled"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SetModelsEnabled 批量启用或禁用模型，要么全部生效要么全部不生效。
+// 先校验所有模型，任一无效则不做修改；应用中途失败时把已变更的模型恢复原状
+func (m *Manager) SetModelsEnabled(providerType ProviderType, changes []ModelChange) ([]ModelChangeResult, error) {
+	prov, err := m.GetProvider(providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	m.modelsMu.Lock()
+	defer m.modelsMu.Unlock()
+
+	results := make([]ModelChangeResult, len(changes))
+	previous := make([]bool, len(changes))
+	seen := make(map[string]bool, len(changes))
+	valid := true
+	for i, change := range changes {
+		results[i] = ModelChangeResult{Model: change.Model, Enabled: change.Enabled, Status: ModelChangeSkipped}
+		if seen[change.Model] {
+			results[i].Status = ModelChangeInvalid
+			results[i].Error = fmt.Sprintf("model %s is listed more than once", change.Model)
+			valid = false
+			continue
+		}
+		seen[change.Model] = true
+
+		config, err := prov.GetModelConfig(change.Model)
+		if err != nil {
+			results[i].Status = ModelChangeInvalid
+			results[i].Error = err.Error()
+			valid = false
+			continue
+		}
+		previous[i] = config.Enabled
+	}
+	if !valid {
+		return results, ErrModelChangesRejected
+	}
+
+	for i, change := range changes {
+		if previous[i] == change.Enabled {
+			results[i].Status = ModelChangeUnchanged
+			continue
+		}
+		if err := setModelEnabled(prov, change.Model, change.Enabled); err != nil {
+			results[i].Status = ModelChangeFailed
+			results[i].Error = err.Error()
+			m.rollbackModelChanges(prov, results[:i], previous)
+			return results, ErrModelChangesFailed
+		}
+		results[i].Status = ModelChangeUpdated
+	}
+	return results, nil
+}
+
+// rollbackModelChanges 恢复已变更模型的原状态
+func (m *Manager) rollbackModelChanges(prov Provider, results []ModelChangeResult, previous []bool) {
+	for i := range results {
+		if results[i].Status != ModelChangeUpdated {
+			continue
+		}
+		if err := setModelEnabled(prov, results[i].Model, previous[i]); err != nil {
+			m.logger.Error("Failed to roll back model change",
+				logger.String("model", results[i].Model),
+				logger.ZapError(err))
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Status = ModelChangeRolledBack
+	}
+}
+
+// setModelEnabled 启用或禁用单个模型
+func setModelEnabled(prov Provider, model string, enabled bool) error {
+	if enabled {
+		return prov.EnableModel(model)
+	}
+	return prov.DisableModel(model)
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"go-springAi/internal/logger"
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingProvider 禁用指定模型时返回错误
+type failingProvider struct {
+	*MockProvider
+	failModel string
+}
+
+func (p *failingProvider) DisableModel(name string) error {
+	if name == p.failModel {
+		return errors.New("disk full")
+	}
+	return p.MockProvider.DisableModel(name)
+}
+
+func TestManagerSetModelsEnabled(t *testing.T) {
+	newManager := func(failModel string) (*Manager, *MockProvider) {
+		mock := NewMockProvider("OpenAI", types.ProviderTypeOpenAI)
+		mock.models["mock-gpt-4"] = &ModelConfig{Name: "mock-gpt-4", Enabled: false}
+		mock.models["mock-gpt-4o"] = &ModelConfig{Name: "mock-gpt-4o", Enabled: true}
+		m := NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+		require.NoError(t, m.RegisterProvider(&failingProvider{MockProvider: mock, failModel: failModel}))
+		return m, mock
+	}
+	enabled := func(mock *MockProvider) map[string]bool {
+		out := make(map[string]bool)
+		for name, model := range mock.models {
+			out[name] = model.Enabled
+		}
+		return out
+	}
+	initial := map[string]bool{"mock-gpt-3.5-turbo": true, "mock-gpt-4": false, "mock-gpt-4o": true}
+
+	tests := []struct {
+		name        string
+		failModel   string
+		changes     []ModelChange
+		wantErr     error
+		wantStatus  []string
+		wantEnabled map[string]bool
+	}{
+		{
+			name:        "All applied",
+			changes:     []ModelChange{{Model: "mock-gpt-4", Enabled: true}, {Model: "mock-gpt-4o", Enabled: false}, {Model: "mock-gpt-3.5-turbo", Enabled: true}},
+			wantStatus:  []string{ModelChangeUpdated, ModelChangeUpdated, ModelChangeUnchanged},
+			wantEnabled: map[string]bool{"mock-gpt-3.5-turbo": true, "mock-gpt-4": true, "mock-gpt-4o": false},
+		},
+		{
+			name:        "Unknown model rejects the batch",
+			changes:     []ModelChange{{Model: "mock-gpt-4", Enabled: true}, {Model: "unknown", Enabled: true}},
+			wantErr:     ErrModelChangesRejected,
+			wantStatus:  []string{ModelChangeSkipped, ModelChangeInvalid},
+			wantEnabled: initial,
+		},
+		{
+			name:        "Duplicate model rejects the batch",
+			changes:     []ModelChange{{Model: "mock-gpt-4", Enabled: true}, {Model: "mock-gpt-4", Enabled: false}},
+			wantErr:     ErrModelChangesRejected,
+			wantStatus:  []string{ModelChangeSkipped, ModelChangeInvalid},
+			wantEnabled: initial,
+		},
+		{
+			name:        "Failure rolls back applied changes",
+			failModel:   "mock-gpt-4o",
+			changes:     []ModelChange{{Model: "mock-gpt-4", Enabled: true}, {Model: "mock-gpt-4o", Enabled: false}, {Model: "mock-gpt-3.5-turbo", Enabled: false}},
+			wantErr:     ErrModelChangesFailed,
+			wantStatus:  []string{ModelChangeRolledBack, ModelChangeFailed, ModelChangeSkipped},
+			wantEnabled: initial,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := newManager(tt.failModel)
+			results, err := m.SetModelsEnabled(types.ProviderTypeOpenAI, tt.changes)
+			assert.ErrorIs(t, err, tt.wantErr)
+			require.Len(t, results, len(tt.changes))
+			for i, result := range results {
+				assert.Equal(t, tt.changes[i].Model, result.Model)
+				assert.Equal(t, tt.wantStatus[i], result.Status, result.Model)
+			}
+			assert.Equal(t, tt.wantEnabled, enabled(mock))
+		})
+	}
+
+	_, err := NewManager(logger.NewLoggerFromZap(zap.NewNop())).SetModelsEnabled(types.ProviderTypeOpenAI, nil)
+	assert.Error(t, err)
+}
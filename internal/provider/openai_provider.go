@@ -2,7 +2,9 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"go-springAi/internal/openai"
 	"go-springAi/internal/service"
@@ -11,7 +13,9 @@ import (
 
 // OpenAIProvider OpenAI提供商实现
 type OpenAIProvider struct {
-	service *service.OpenAIService
+	service  *service.OpenAIService
+	rotation keyRotation
+	pool     keyPool
 }
 
 // NewOpenAIProvider 创建OpenAI Provider
@@ -45,7 +49,11 @@ func (p *OpenAIProvider) ChatCompletion(ctx context.Context, req *ChatRequest) (
 	}
 	
 	// 调用OpenAI服务
-	resp, err := p.service.ChatCompletion(ctx, openaiReq)
+	resp, err := withPreviousKey(ctx, &p.rotation, func(ctx context.Context) (*service.ChatCompletionResponse, error) {
+		return withKeyPool(ctx, &p.pool, func(ctx context.Context) (*service.ChatCompletionResponse, error) {
+			return p.service.ChatCompletion(ctx, openaiReq)
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +87,11 @@ func (p *OpenAIProvider) ChatCompletionStream(ctx context.Context, req *ChatRequ
 	}
 	
 	// 调用OpenAI服务
-	return p.service.ChatCompletionStream(ctx, openaiReq)
+	return withPreviousKey(ctx, &p.rotation, func(ctx context.Context) (io.ReadCloser, error) {
+		return withKeyPool(ctx, &p.pool, func(ctx context.Context) (io.ReadCloser, error) {
+			return p.service.ChatCompletionStream(ctx, openaiReq)
+		})
+	})
 }
 
 // ListModels 列出可用模型（仅启用的）
@@ -99,6 +111,7 @@ func (p *OpenAIProvider) ListModels(ctx context.Context) (map[string]*ModelConfi
 			Temperature: config.Temperature,
 			TopP:        config.TopP,
 			Enabled:     config.Enabled,
+			Unavailable: config.Unavailable,
 		}
 	}
 	
@@ -122,12 +135,18 @@ func (p *OpenAIProvider) ListAllModels(ctx context.Context) (map[string]*ModelCo
 			Temperature: config.Temperature,
 			TopP:        config.TopP,
 			Enabled:     config.Enabled,
+			Unavailable: config.Unavailable,
 		}
 	}
 	
 	return result, nil
 }
 
+// ListRemoteModels 从提供商接口获取当前提供的模型
+func (p *OpenAIProvider) ListRemoteModels(ctx context.Context) ([]string, error) {
+	return p.service.ListRemoteModels(ctx)
+}
+
 // GetModelConfig 获取模型配置
 func (p *OpenAIProvider) GetModelConfig(name string) (*ModelConfig, error) {
 	config, err := p.service.GetModelConfig(name)
@@ -142,6 +161,7 @@ func (p *OpenAIProvider) GetModelConfig(name string) (*ModelConfig, error) {
 		Temperature: config.Temperature,
 		TopP:        config.TopP,
 		Enabled:     config.Enabled,
+		Unavailable: config.Unavailable,
 	}, nil
 }
 
@@ -155,6 +175,29 @@ func (p *OpenAIProvider) DisableModel(name string) error {
 	return p.service.DisableModel(name)
 }
 
+// AddModel 以默认参数添加禁用状态的模型
+func (p *OpenAIProvider) AddModel(name string) error {
+	if _, err := p.service.GetModelConfig(name); err == nil {
+		return fmt.Errorf("model %s already exists", name)
+	}
+	return p.service.UpdateModelConfig(name, &openai.ModelConfig{
+		Name:        name,
+		MaxTokens:   4096,
+		Temperature: 0.7,
+		TopP:        1.0,
+	})
+}
+
+// SetModelUnavailable 标记模型是否已不再由提供商提供
+func (p *OpenAIProvider) SetModelUnavailable(name string, unavailable bool) error {
+	config, err := p.service.GetModelConfig(name)
+	if err != nil {
+		return err
+	}
+	config.Unavailable = unavailable
+	return p.service.UpdateModelConfig(name, config)
+}
+
 // ValidateAPIKey 验证API密钥
 func (p *OpenAIProvider) ValidateAPIKey(ctx context.Context) error {
 	return p.service.ValidateAPIKey(ctx)
@@ -165,6 +208,21 @@ func (p *OpenAIProvider) SetAPIKey(key string) error {
 	return p.service.SetAPIKey(key)
 }
 
+// SetPreviousAPIKey 设置轮换宽限期内的上一版本密钥
+func (p *OpenAIProvider) SetPreviousAPIKey(key string, expiresAt time.Time) {
+	p.rotation.set(key, expiresAt)
+}
+
+// SetKeyPool 设置与主密钥一起轮换使用的附加密钥
+func (p *OpenAIProvider) SetKeyPool(cfg KeyPoolConfig) {
+	p.pool.configure(cfg)
+}
+
+// KeyHealth 获取各密钥的健康状态
+func (p *OpenAIProvider) KeyHealth() []KeyHealth {
+	return p.pool.health(time.Now())
+}
+
 // IsHealthy 检查提供商健康状态
 func (p *OpenAIProvider) IsHealthy(ctx context.Context) bool {
 	err := p.service.ValidateAPIKey(ctx)
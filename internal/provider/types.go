@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"io"
+	"time"
 	"go-springAi/internal/types"
 )
 
@@ -22,6 +23,9 @@ type ModelConfig struct {
 	TopP         float32  `json:"top_p"`
 	TopK         int      `json:"top_k,omitempty"` // Google AI 特有
 	Enabled      bool     `json:"enabled"`
+	Unavailable  bool     `json:"unavailable,omitempty"` // 提供商已不再提供该模型
+
+	types.ModelMetadata // 管理员维护的元数据，由控制器从模型元数据服务填充
 }
 
 // Provider 统一的AI提供商接口
@@ -59,6 +63,15 @@ type Provider interface {
 	// SetAPIKey 设置API密钥
 	SetAPIKey(key string) error
 	
+	// SetPreviousAPIKey 设置轮换宽限期内的上一版本密钥，鉴权失败时自动使用其重试
+	SetPreviousAPIKey(key string, expiresAt time.Time)
+	
+	// SetKeyPool 设置与主密钥一起轮换使用的附加密钥，限流时自动切换
+	SetKeyPool(cfg KeyPoolConfig)
+	
+	// KeyHealth 获取各密钥的健康状态
+	KeyHealth() []KeyHealth
+	
 	// IsHealthy 检查提供商健康状态
 	IsHealthy(ctx context.Context) bool
 }
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval 清理已补满的桶的间隔
+const memorySweepInterval = time.Minute
+
+// bucket 内存令牌桶
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // 桶补满的时间，之后可以安全删除
+}
+
+// MemoryStore 进程内令牌桶存储，只适用于单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore 创建内存令牌桶存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take 从桶中取一个令牌
+func (s *MemoryStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	capacity := rule.capacity()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rule.Rate)
+		b.last = now
+	}
+
+	result := Result{Limit: int(capacity)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = retryAfter(b.tokens, rule)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = resetAfter(b.tokens, rule)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// sweep 定期删除已补满的桶，避免按IP或用户建的桶无限增长
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	rule := Rule{Rate: 2, Burst: 2}
+
+	tests := []struct {
+		name        string
+		advance     []time.Duration // 每次取令牌前前进的时间
+		wantAllowed []bool
+		wantRetry   time.Duration // 最后一次被拒绝时的重试时间
+		wantReset   time.Duration // 最后一次取令牌后距离桶补满的时间
+	}{
+		{name: "Burst then reject", advance: []time.Duration{0, 0, 0}, wantAllowed: []bool{true, true, false}, wantRetry: 500 * time.Millisecond, wantReset: time.Second},
+		{name: "Refill after wait", advance: []time.Duration{0, 0, 500 * time.Millisecond}, wantAllowed: []bool{true, true, true}, wantReset: time.Second},
+		{name: "Partial refill", advance: []time.Duration{0, 0, 250 * time.Millisecond}, wantAllowed: []bool{true, true, false}, wantRetry: 250 * time.Millisecond, wantReset: 750 * time.Millisecond},
+		{name: "Refill capped at burst", advance: []time.Duration{0, time.Hour, 0, 0}, wantAllowed: []bool{true, true, true, false}, wantRetry: 500 * time.Millisecond, wantReset: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			store := NewMemoryStore()
+			store.now = func() time.Time { return now }
+
+			var result Result
+			for i, d := range tt.advance {
+				now = now.Add(d)
+				var err error
+				result, err = store.Take(context.Background(), "k", rule)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Allowed != tt.wantAllowed[i] {
+					t.Errorf("take %d: expected allowed %v, got %v", i, tt.wantAllowed[i], result.Allowed)
+				}
+			}
+			if result.RetryAfter != tt.wantRetry {
+				t.Errorf("expected retry after %v, got %v", tt.wantRetry, result.RetryAfter)
+			}
+			if result.Reset != tt.wantReset {
+				t.Errorf("expected reset %v, got %v", tt.wantReset, result.Reset)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	rule := Rule{Rate: 1, Burst: 1}
+
+	store.Take(context.Background(), "idle", rule)
+	now = now.Add(2 * memorySweepInterval)
+	store.Take(context.Background(), "active", rule)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("expected refilled bucket to be swept")
+	}
+	if _, ok := store.buckets["active"]; !ok {
+		t.Error("expected active bucket to be kept")
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule 令牌桶规则，Rate 为每秒补充的令牌数，Burst 为桶容量
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled 规则是否生效，Rate 不大于0表示不限流
+func (r Rule) Enabled() bool {
+	return r.Rate > 0
+}
+
+// capacity 桶容量，未配置时至少允许一个请求
+func (r Rule) capacity() float64 {
+	if r.Burst < 1 {
+		return 1
+	}
+	return float64(r.Burst)
+}
+
+// Result 一次取令牌的结果
+type Result struct {
+	Allowed    bool
+	Limit      int           // 桶容量
+	Remaining  int           // 剩余令牌数
+	RetryAfter time.Duration // 被拒绝时距离下一个可用令牌的时间
+	Reset      time.Duration // 距离桶补满的时间
+}
+
+// Store 令牌桶存储，单实例使用内存，多实例部署使用Redis共享限流状态
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// Rules 各维度的限流规则
+type Rules struct {
+	Global  Rule            // 全部请求共享一个桶
+	PerIP   Rule            // 每个客户端IP一个桶
+	PerUser Rule            // 每个登录用户一个桶
+	Groups  map[string]Rule // 每个路由分组内按用户（未登录时按IP）一个桶
+}
+
+// Limiter 按规则限流
+type Limiter struct {
+	store Store
+	rules Rules
+}
+
+// NewLimiter 创建限流器
+func NewLimiter(store Store, rules Rules) *Limiter {
+	return &Limiter{store: store, rules: rules}
+}
+
+// Rules 获取限流规则
+func (l *Limiter) Rules() Rules {
+	return l.rules
+}
+
+// Take 从 key 对应的桶中取一个令牌，规则未启用时直接放行
+func (l *Limiter) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	if !rule.Enabled() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, key, rule)
+}
+
+// retryAfter 令牌数为 tokens 时距离下一个令牌可用的时间
+func retryAfter(tokens float64, rule Rule) time.Duration {
+	return time.Duration(math.Ceil((1 - tokens) / rule.Rate * float64(time.Second)))
+}
+
+// resetAfter 令牌数为 tokens 时距离桶补满的时间
+func resetAfter(tokens float64, rule Rule) time.Duration {
+	return time.Duration(math.Ceil((rule.capacity() - tokens) / rule.Rate * float64(time.Second)))
+}
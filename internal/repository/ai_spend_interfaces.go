@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/ai_spend"
+)
+
+// AISpendRepository AI费用统计数据访问层接口
+type AISpendRepository interface {
+	// Record 累加用户在项目和提供商上当日的费用
+	Record(ctx context.Context, userID, projectID int64, provider, usageDate string, costUSD float64) error
+
+	// Summary 获取自 monthStart 起用户、项目和提供商各自的费用
+	Summary(ctx context.Context, userID, projectID int64, provider, monthStart string) (*ai_spend.GetAISpendSummaryRow, error)
+}
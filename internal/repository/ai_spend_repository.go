@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/ai_spend"
+)
+
+// aiSpendRepository AI费用统计数据访问层实现
+type aiSpendRepository struct {
+	db *database.DB
+}
+
+// NewAISpendRepository 创建AI费用统计数据访问层
+func NewAISpendRepository(db *database.DB) AISpendRepository {
+	return &aiSpendRepository{
+		db: db,
+	}
+}
+
+// Record 累加用户在项目和提供商上当日的费用
+func (r *aiSpendRepository) Record(ctx context.Context, userID, projectID int64, provider, usageDate string, costUSD float64) error {
+	err := r.db.AISpend.RecordAISpend(ctx, ai_spend.RecordAISpendParams{
+		UserID:    userID,
+		ProjectID: projectID,
+		Provider:  provider,
+		UsageDate: usageDate,
+		CostUsd:   costUSD,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record ai spend: %w", err)
+	}
+	return nil
+}
+
+// Summary 获取自 monthStart 起用户、项目和提供商各自的费用
+func (r *aiSpendRepository) Summary(ctx context.Context, userID, projectID int64, provider, monthStart string) (*ai_spend.GetAISpendSummaryRow, error) {
+	summary, err := r.db.AISpend.GetAISpendSummary(ctx, ai_spend.GetAISpendSummaryParams{
+		UserID:     userID,
+		ProjectID:  projectID,
+		Provider:   provider,
+		MonthStart: monthStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ai spend summary: %w", err)
+	}
+	return &summary, nil
+}
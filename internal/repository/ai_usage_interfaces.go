@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/ai_usage"
+)
+
+// AIUsageRepository AI用量统计数据访问层接口
+type AIUsageRepository interface {
+	// Record 记录一次AI请求及其消耗的令牌数
+	Record(ctx context.Context, userID int64, usageDate string, tokens int64) error
+
+	// Summary 获取用户当日请求数和自 monthStart 起的令牌用量
+	Summary(ctx context.Context, userID int64, today, monthStart string) (*ai_usage.GetAIUsageSummaryRow, error)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/ai_usage"
+)
+
+// aiUsageRepository AI用量统计数据访问层实现
+type aiUsageRepository struct {
+	db *database.DB
+}
+
+// NewAIUsageRepository 创建AI用量统计数据访问层
+func NewAIUsageRepository(db *database.DB) AIUsageRepository {
+	return &aiUsageRepository{
+		db: db,
+	}
+}
+
+// Record 记录一次AI请求及其消耗的令牌数
+func (r *aiUsageRepository) Record(ctx context.Context, userID int64, usageDate string, tokens int64) error {
+	err := r.db.AIUsage.RecordAIUsage(ctx, ai_usage.RecordAIUsageParams{
+		UserID:     userID,
+		UsageDate:  usageDate,
+		TokenCount: tokens,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record ai usage: %w", err)
+	}
+	return nil
+}
+
+// Summary 获取用户当日请求数和自 monthStart 起的令牌用量
+func (r *aiUsageRepository) Summary(ctx context.Context, userID int64, today, monthStart string) (*ai_usage.GetAIUsageSummaryRow, error) {
+	summary, err := r.db.AIUsage.GetAIUsageSummary(ctx, ai_usage.GetAIUsageSummaryParams{
+		Today:      today,
+		UserID:     userID,
+		MonthStart: monthStart,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ai usage summary: %w", err)
+	}
+	return &summary, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go-springAi/internal/database/generated/api_keys"
 )
@@ -11,14 +12,17 @@ type APIKeyRepository interface {
 	// CreateAPIKey 创建API密钥
 	CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (*api_keys.ApiKey, error)
 	
-	// GetAPIKey 获取指定用户和提供商的API密钥
-	GetAPIKey(ctx context.Context, userID int64, providerType string) (*api_keys.ApiKey, error)
+	// GetAPIKey 获取指定用户、项目和提供商的API密钥，projectID 为 0 表示默认密钥
+	GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKey, error)
 	
 	// GetAPIKeyByID 根据ID获取API密钥
 	GetAPIKeyByID(ctx context.Context, id int64) (*api_keys.ApiKey, error)
 	
-	// ListAPIKeysByUser 获取指定用户的所有API密钥
-	ListAPIKeysByUser(ctx context.Context, userID int64) ([]api_keys.ApiKey, error)
+	// ListAPIKeysByUser 获取指定用户的所有API密钥，opts.IncludeDeleted 为 true 时包含已软删除的密钥
+	ListAPIKeysByUser(ctx context.Context, userID int64, opts SoftDeleteOptions) ([]api_keys.ApiKey, error)
+	
+	// ListAPIKeysByProject 获取指定项目的所有API密钥
+	ListAPIKeysByProject(ctx context.Context, userID, projectID int64) ([]api_keys.ApiKey, error)
 	
 	// ListAPIKeysByProvider 获取指定提供商的所有API密钥
 	ListAPIKeysByProvider(ctx context.Context, providerType string) ([]api_keys.ApiKey, error)
@@ -26,25 +30,80 @@ type APIKeyRepository interface {
 	// UpdateAPIKey 更新API密钥
 	UpdateAPIKey(ctx context.Context, params UpdateAPIKeyParams) (*api_keys.ApiKey, error)
 	
+	// UpdateAPIKeyAllowedModels 更新API密钥可访问的模型，逗号分隔，为空表示不限制
+	UpdateAPIKeyAllowedModels(ctx context.Context, userID, projectID int64, providerType, allowedModels string) error
+	
 	// DeactivateAPIKey 停用API密钥
-	DeactivateAPIKey(ctx context.Context, userID int64, providerType string) error
+	DeactivateAPIKey(ctx context.Context, userID, projectID int64, providerType string) error
+	
+	// DeleteAPIKey 软删除API密钥，可通过 RestoreAPIKey 恢复
+	DeleteAPIKey(ctx context.Context, userID, projectID int64, providerType string) error
+	
+	// RestoreAPIKey 恢复已软删除的API密钥
+	RestoreAPIKey(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKey, error)
 	
-	// DeleteAPIKey 删除API密钥
-	DeleteAPIKey(ctx context.Context, userID int64, providerType string) error
+	// DeleteAPIKeyVersionsByProject 删除指定项目的所有API密钥版本
+	DeleteAPIKeyVersionsByProject(ctx context.Context, userID, projectID int64) error
 	
 	// CheckAPIKeyExists 检查API密钥是否存在
-	CheckAPIKeyExists(ctx context.Context, userID int64, providerType string) (bool, error)
+	CheckAPIKeyExists(ctx context.Context, userID, projectID int64, providerType string) (bool, error)
 	
 	// CountAPIKeysByUser 统计用户的API密钥数量
 	CountAPIKeysByUser(ctx context.Context, userID int64) (int64, error)
 	
 	// CountAPIKeysByProvider 统计提供商的API密钥数量
 	CountAPIKeysByProvider(ctx context.Context, providerType string) (int64, error)
+
+	// CreateAPIKeyVersion 记录新的API密钥版本
+	CreateAPIKeyVersion(ctx context.Context, params CreateAPIKeyVersionParams) (*api_keys.ApiKeyVersion, error)
+
+	// ExpireAPIKeyVersionGrace 结束已轮换版本尚未到期的宽限期
+	ExpireAPIKeyVersionGrace(ctx context.Context, userID, projectID int64, providerType string, now time.Time) error
+
+	// GetLatestAPIKeyVersion 获取最新的API密钥版本
+	GetLatestAPIKeyVersion(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKeyVersion, error)
+
+	// GetPreviousAPIKeyVersion 获取在 now 时仍处于宽限期的上一版本密钥
+	GetPreviousAPIKeyVersion(ctx context.Context, userID, projectID int64, providerType string, now time.Time) (*api_keys.ApiKeyVersion, error)
+
+	// ListAPIKeyVersions 获取API密钥轮换历史，按版本倒序
+	ListAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string) ([]api_keys.ApiKeyVersion, error)
+
+	// RetireAPIKeyVersions 将当前版本标记为已轮换，并设置宽限期截止时间
+	RetireAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string, graceExpiresAt time.Time) error
+
+	// ListActiveAPIKeys 获取所有启用的API密钥，供定期验证使用
+	ListActiveAPIKeys(ctx context.Context) ([]api_keys.ApiKey, error)
+
+	// GetAPIKeyValidation 获取API密钥最近一次的验证结果
+	GetAPIKeyValidation(ctx context.Context, apiKeyID int64) (*api_keys.ApiKeyValidation, error)
+
+	// UpsertAPIKeyValidation 保存API密钥的验证结果
+	UpsertAPIKeyValidation(ctx context.Context, params UpsertAPIKeyValidationParams) error
+
+	// GetAPIKeyExpiration 获取API密钥的过期时间
+	GetAPIKeyExpiration(ctx context.Context, apiKeyID int64) (*api_keys.ApiKeyExpiration, error)
+
+	// SetAPIKeyExpiration 设置API密钥的过期时间，keyHash 为设置时的密钥哈希，密钥更换后不再适用
+	SetAPIKeyExpiration(ctx context.Context, apiKeyID int64, keyHash string, expiresAt time.Time) error
+
+	// DeleteAPIKeyExpiration 清除API密钥的过期时间
+	DeleteAPIKeyExpiration(ctx context.Context, apiKeyID int64) error
+
+	// ListDueAPIKeyExpirations 获取在 before 之前过期且尚未标记为过期的启用密钥，供定期检查使用
+	ListDueAPIKeyExpirations(ctx context.Context, before time.Time) ([]api_keys.ListDueAPIKeyExpirationsRow, error)
+
+	// MarkAPIKeyExpirationWarned 记录已向所有者发送过期提醒
+	MarkAPIKeyExpirationWarned(ctx context.Context, apiKeyID int64, warnedAt time.Time) error
+
+	// MarkAPIKeyExpired 将API密钥标记为已过期
+	MarkAPIKeyExpired(ctx context.Context, apiKeyID int64, expiredAt time.Time) error
 }
 
 // CreateAPIKeyParams 创建API密钥参数
 type CreateAPIKeyParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"` // 0 表示默认密钥
 	ProviderType string `json:"provider_type"`
 	EncryptedKey string `json:"encrypted_key"`
 	KeyHash      string `json:"key_hash"`
@@ -54,7 +113,27 @@ type CreateAPIKeyParams struct {
 // UpdateAPIKeyParams 更新API密钥参数
 type UpdateAPIKeyParams struct {
 	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"` // 0 表示默认密钥
+	ProviderType string `json:"provider_type"`
+	EncryptedKey string `json:"encrypted_key"`
+	KeyHash      string `json:"key_hash"`
+}
+
+// UpsertAPIKeyValidationParams 保存API密钥验证结果参数
+type UpsertAPIKeyValidationParams struct {
+	APIKeyID    int64     `json:"api_key_id"`
+	KeyHash     string    `json:"key_hash"` // 验证时的密钥哈希，密钥更换后结果失效
+	Valid       bool      `json:"valid"`
+	Error       string    `json:"error"`
+	ValidatedAt time.Time `json:"validated_at"`
+}
+
+// CreateAPIKeyVersionParams 创建API密钥版本参数
+type CreateAPIKeyVersionParams struct {
+	UserID       int64  `json:"user_id"`
+	ProjectID    int64  `json:"project_id"` // 0 表示默认密钥
 	ProviderType string `json:"provider_type"`
+	Version      int64  `json:"version"`
 	EncryptedKey string `json:"encrypted_key"`
 	KeyHash      string `json:"key_hash"`
-}
\ No newline at end of file
+}
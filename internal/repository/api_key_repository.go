@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"go-springAi/internal/database"
 	"go-springAi/internal/database/generated/api_keys"
@@ -22,14 +23,28 @@ func NewAPIKeyRepository(db *database.DB) APIKeyRepository {
 	}
 }
 
-// CreateAPIKey 创建API密钥
+// CreateAPIKey 创建API密钥，同一用户、项目和提供商已有软删除的密钥时先将其永久删除
 func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (*api_keys.ApiKey, error) {
-	apiKey, err := r.db.APIKeys.CreateAPIKey(ctx, api_keys.CreateAPIKeyParams{
-		UserID:       params.UserID,
-		ProviderType: params.ProviderType,
-		EncryptedKey: params.EncryptedKey,
-		KeyHash:      params.KeyHash,
-		IsActive:     sql.NullBool{Bool: params.IsActive, Valid: true},
+	var apiKey api_keys.ApiKey
+	err := r.db.WithTx(ctx, func(tx *database.DB) error {
+		if err := tx.APIKeys.PurgeDeletedAPIKey(ctx, api_keys.PurgeDeletedAPIKeyParams{
+			UserID:       params.UserID,
+			ProjectID:    params.ProjectID,
+			ProviderType: params.ProviderType,
+		}); err != nil {
+			return err
+		}
+
+		var err error
+		apiKey, err = tx.APIKeys.CreateAPIKey(ctx, api_keys.CreateAPIKeyParams{
+			UserID:       params.UserID,
+			ProjectID:    params.ProjectID,
+			ProviderType: params.ProviderType,
+			EncryptedKey: params.EncryptedKey,
+			KeyHash:      params.KeyHash,
+			IsActive:     sql.NullBool{Bool: params.IsActive, Valid: true},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API key: %w", err)
@@ -37,10 +52,11 @@ func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, params CreateAPIKey
 	return &apiKey, nil
 }
 
-// GetAPIKey 获取指定用户和提供商的API密钥
-func (r *apiKeyRepository) GetAPIKey(ctx context.Context, userID int64, providerType string) (*api_keys.ApiKey, error) {
+// GetAPIKey 获取指定用户、项目和提供商的API密钥
+func (r *apiKeyRepository) GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKey, error) {
 	apiKey, err := r.db.APIKeys.GetAPIKey(ctx, api_keys.GetAPIKeyParams{
 		UserID:       userID,
+		ProjectID:    projectID,
 		ProviderType: providerType,
 	})
 	if err != nil {
@@ -65,14 +81,29 @@ func (r *apiKeyRepository) GetAPIKeyByID(ctx context.Context, id int64) (*api_ke
 }
 
 // ListAPIKeysByUser 获取指定用户的所有API密钥
-func (r *apiKeyRepository) ListAPIKeysByUser(ctx context.Context, userID int64) ([]api_keys.ApiKey, error) {
-	apiKeys, err := r.db.APIKeys.ListAPIKeysByUser(ctx, userID)
+func (r *apiKeyRepository) ListAPIKeysByUser(ctx context.Context, userID int64, opts SoftDeleteOptions) ([]api_keys.ApiKey, error) {
+	apiKeys, err := r.db.APIKeys.ListAPIKeysByUser(ctx, api_keys.ListAPIKeysByUserParams{
+		UserID:         userID,
+		IncludeDeleted: opts.IncludeDeleted,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list API keys by user: %w", err)
 	}
 	return apiKeys, nil
 }
 
+// ListAPIKeysByProject 获取指定项目的所有API密钥
+func (r *apiKeyRepository) ListAPIKeysByProject(ctx context.Context, userID, projectID int64) ([]api_keys.ApiKey, error) {
+	apiKeys, err := r.db.APIKeys.ListAPIKeysByProject(ctx, api_keys.ListAPIKeysByProjectParams{
+		UserID:    userID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys by project: %w", err)
+	}
+	return apiKeys, nil
+}
+
 // ListAPIKeysByProvider 获取指定提供商的所有API密钥
 func (r *apiKeyRepository) ListAPIKeysByProvider(ctx context.Context, providerType string) ([]api_keys.ApiKey, error) {
 	apiKeys, err := r.db.APIKeys.ListAPIKeysByProvider(ctx, providerType)
@@ -86,6 +117,7 @@ func (r *apiKeyRepository) ListAPIKeysByProvider(ctx context.Context, providerTy
 func (r *apiKeyRepository) UpdateAPIKey(ctx context.Context, params UpdateAPIKeyParams) (*api_keys.ApiKey, error) {
 	apiKey, err := r.db.APIKeys.UpdateAPIKey(ctx, api_keys.UpdateAPIKeyParams{
 		UserID:       params.UserID,
+		ProjectID:    params.ProjectID,
 		ProviderType: params.ProviderType,
 		EncryptedKey: params.EncryptedKey,
 		KeyHash:      params.KeyHash,
@@ -99,10 +131,28 @@ func (r *apiKeyRepository) UpdateAPIKey(ctx context.Context, params UpdateAPIKey
 	return &apiKey, nil
 }
 
+// UpdateAPIKeyAllowedModels 更新API密钥可访问的模型
+func (r *apiKeyRepository) UpdateAPIKeyAllowedModels(ctx context.Context, userID, projectID int64, providerType, allowedModels string) error {
+	rows, err := r.db.APIKeys.UpdateAPIKeyAllowedModels(ctx, api_keys.UpdateAPIKeyAllowedModelsParams{
+		UserID:        userID,
+		ProjectID:     projectID,
+		ProviderType:  providerType,
+		AllowedModels: allowedModels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update API key allowed models: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFoundError("API key")
+	}
+	return nil
+}
+
 // DeactivateAPIKey 停用API密钥
-func (r *apiKeyRepository) DeactivateAPIKey(ctx context.Context, userID int64, providerType string) error {
+func (r *apiKeyRepository) DeactivateAPIKey(ctx context.Context, userID, projectID int64, providerType string) error {
 	err := r.db.APIKeys.DeactivateAPIKey(ctx, api_keys.DeactivateAPIKeyParams{
 		UserID:       userID,
+		ProjectID:    projectID,
 		ProviderType: providerType,
 	})
 	if err != nil {
@@ -111,22 +161,46 @@ func (r *apiKeyRepository) DeactivateAPIKey(ctx context.Context, userID int64, p
 	return nil
 }
 
-// DeleteAPIKey 删除API密钥
-func (r *apiKeyRepository) DeleteAPIKey(ctx context.Context, userID int64, providerType string) error {
-	err := r.db.APIKeys.DeleteAPIKey(ctx, api_keys.DeleteAPIKeyParams{
+// DeleteAPIKey 软删除API密钥，已删除的密钥不会出现在查询结果中
+func (r *apiKeyRepository) DeleteAPIKey(ctx context.Context, userID, projectID int64, providerType string) error {
+	rows, err := r.db.APIKeys.SoftDeleteAPIKey(ctx, api_keys.SoftDeleteAPIKeyParams{
 		UserID:       userID,
+		ProjectID:    projectID,
 		ProviderType: providerType,
 	})
+	return softDeleteResult(rows, err, "Failed to delete API key", errors.NewNotFoundError("API key"))
+}
+
+// RestoreAPIKey 恢复已软删除的API密钥
+func (r *apiKeyRepository) RestoreAPIKey(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKey, error) {
+	apiKey, err := r.db.APIKeys.RestoreAPIKey(ctx, api_keys.RestoreAPIKeyParams{
+		UserID:       userID,
+		ProjectID:    projectID,
+		ProviderType: providerType,
+	})
+	if err := restoreResult(err, "Failed to restore API key", errors.NewNotFoundError("API key")); err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// DeleteAPIKeyVersionsByProject 删除指定项目的所有API密钥版本
+func (r *apiKeyRepository) DeleteAPIKeyVersionsByProject(ctx context.Context, userID, projectID int64) error {
+	err := r.db.APIKeys.DeleteAPIKeyVersionsByProject(ctx, api_keys.DeleteAPIKeyVersionsByProjectParams{
+		UserID:    userID,
+		ProjectID: projectID,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete API key: %w", err)
+		return fmt.Errorf("failed to delete API key versions by project: %w", err)
 	}
 	return nil
 }
 
 // CheckAPIKeyExists 检查API密钥是否存在
-func (r *apiKeyRepository) CheckAPIKeyExists(ctx context.Context, userID int64, providerType string) (bool, error) {
+func (r *apiKeyRepository) CheckAPIKeyExists(ctx context.Context, userID, projectID int64, providerType string) (bool, error) {
 	count, err := r.db.APIKeys.CheckAPIKeyExists(ctx, api_keys.CheckAPIKeyExistsParams{
 		UserID:       userID,
+		ProjectID:    projectID,
 		ProviderType: providerType,
 	})
 	if err != nil {
@@ -151,4 +225,196 @@ func (r *apiKeyRepository) CountAPIKeysByProvider(ctx context.Context, providerT
 		return 0, fmt.Errorf("failed to count API keys by provider: %w", err)
 	}
 	return count, nil
-}
\ No newline at end of file
+}
+
+// CreateAPIKeyVersion 记录新的API密钥版本
+func (r *apiKeyRepository) CreateAPIKeyVersion(ctx context.Context, params CreateAPIKeyVersionParams) (*api_keys.ApiKeyVersion, error) {
+	version, err := r.db.APIKeys.CreateAPIKeyVersion(ctx, api_keys.CreateAPIKeyVersionParams{
+		UserID:       params.UserID,
+		ProjectID:    params.ProjectID,
+		ProviderType: params.ProviderType,
+		Version:      params.Version,
+		EncryptedKey: params.EncryptedKey,
+		KeyHash:      params.KeyHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key version: %w", err)
+	}
+	return &version, nil
+}
+
+// ExpireAPIKeyVersionGrace 结束已轮换版本尚未到期的宽限期
+func (r *apiKeyRepository) ExpireAPIKeyVersionGrace(ctx context.Context, userID, projectID int64, providerType string, now time.Time) error {
+	err := r.db.APIKeys.ExpireAPIKeyVersionGrace(ctx, api_keys.ExpireAPIKeyVersionGraceParams{
+		UserID:         userID,
+		ProjectID:      projectID,
+		ProviderType:   providerType,
+		GraceExpiresAt: sql.NullTime{Time: now.UTC(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to expire API key version grace: %w", err)
+	}
+	return nil
+}
+
+// GetLatestAPIKeyVersion 获取最新的API密钥版本
+func (r *apiKeyRepository) GetLatestAPIKeyVersion(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKeyVersion, error) {
+	version, err := r.db.APIKeys.GetLatestAPIKeyVersion(ctx, api_keys.GetLatestAPIKeyVersionParams{
+		UserID:       userID,
+		ProjectID:    projectID,
+		ProviderType: providerType,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("API key version")
+		}
+		return nil, fmt.Errorf("failed to get latest API key version: %w", err)
+	}
+	return &version, nil
+}
+
+// GetPreviousAPIKeyVersion 获取在 now 时仍处于宽限期的上一版本密钥
+func (r *apiKeyRepository) GetPreviousAPIKeyVersion(ctx context.Context, userID, projectID int64, providerType string, now time.Time) (*api_keys.ApiKeyVersion, error) {
+	version, err := r.db.APIKeys.GetPreviousAPIKeyVersion(ctx, api_keys.GetPreviousAPIKeyVersionParams{
+		UserID:         userID,
+		ProjectID:      projectID,
+		ProviderType:   providerType,
+		GraceExpiresAt: sql.NullTime{Time: now.UTC(), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("API key version")
+		}
+		return nil, fmt.Errorf("failed to get previous API key version: %w", err)
+	}
+	return &version, nil
+}
+
+// ListAPIKeyVersions 获取API密钥轮换历史
+func (r *apiKeyRepository) ListAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string) ([]api_keys.ApiKeyVersion, error) {
+	versions, err := r.db.APIKeys.ListAPIKeyVersions(ctx, api_keys.ListAPIKeyVersionsParams{
+		UserID:       userID,
+		ProjectID:    projectID,
+		ProviderType: providerType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API key versions: %w", err)
+	}
+	return versions, nil
+}
+
+// RetireAPIKeyVersions 将当前版本标记为已轮换
+func (r *apiKeyRepository) RetireAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string, graceExpiresAt time.Time) error {
+	err := r.db.APIKeys.RetireAPIKeyVersions(ctx, api_keys.RetireAPIKeyVersionsParams{
+		UserID:         userID,
+		ProjectID:      projectID,
+		ProviderType:   providerType,
+		GraceExpiresAt: sql.NullTime{Time: graceExpiresAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retire API key versions: %w", err)
+	}
+	return nil
+}
+
+// ListActiveAPIKeys 获取所有启用的API密钥
+func (r *apiKeyRepository) ListActiveAPIKeys(ctx context.Context) ([]api_keys.ApiKey, error) {
+	apiKeys, err := r.db.APIKeys.ListActiveAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active API keys: %w", err)
+	}
+	return apiKeys, nil
+}
+
+// GetAPIKeyValidation 获取API密钥最近一次的验证结果
+func (r *apiKeyRepository) GetAPIKeyValidation(ctx context.Context, apiKeyID int64) (*api_keys.ApiKeyValidation, error) {
+	validation, err := r.db.APIKeys.GetAPIKeyValidation(ctx, apiKeyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("API key validation")
+		}
+		return nil, fmt.Errorf("failed to get API key validation: %w", err)
+	}
+	return &validation, nil
+}
+
+// UpsertAPIKeyValidation 保存API密钥的验证结果
+func (r *apiKeyRepository) UpsertAPIKeyValidation(ctx context.Context, params UpsertAPIKeyValidationParams) error {
+	err := r.db.APIKeys.UpsertAPIKeyValidation(ctx, api_keys.UpsertAPIKeyValidationParams{
+		ApiKeyID:    params.APIKeyID,
+		KeyHash:     params.KeyHash,
+		Valid:       params.Valid,
+		Error:       params.Error,
+		ValidatedAt: params.ValidatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert API key validation: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyExpiration 获取API密钥的过期时间
+func (r *apiKeyRepository) GetAPIKeyExpiration(ctx context.Context, apiKeyID int64) (*api_keys.ApiKeyExpiration, error) {
+	expiration, err := r.db.APIKeys.GetAPIKeyExpiration(ctx, apiKeyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("API key expiration")
+		}
+		return nil, fmt.Errorf("failed to get API key expiration: %w", err)
+	}
+	return &expiration, nil
+}
+
+// SetAPIKeyExpiration 设置API密钥的过期时间，并清除已发送的提醒和过期标记
+func (r *apiKeyRepository) SetAPIKeyExpiration(ctx context.Context, apiKeyID int64, keyHash string, expiresAt time.Time) error {
+	err := r.db.APIKeys.UpsertAPIKeyExpiration(ctx, api_keys.UpsertAPIKeyExpirationParams{
+		ApiKeyID:  apiKeyID,
+		KeyHash:   keyHash,
+		ExpiresAt: expiresAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set API key expiration: %w", err)
+	}
+	return nil
+}
+
+// DeleteAPIKeyExpiration 清除API密钥的过期时间
+func (r *apiKeyRepository) DeleteAPIKeyExpiration(ctx context.Context, apiKeyID int64) error {
+	if err := r.db.APIKeys.DeleteAPIKeyExpiration(ctx, apiKeyID); err != nil {
+		return fmt.Errorf("failed to delete API key expiration: %w", err)
+	}
+	return nil
+}
+
+// ListDueAPIKeyExpirations 获取在 before 之前过期且尚未标记为过期的启用密钥
+func (r *apiKeyRepository) ListDueAPIKeyExpirations(ctx context.Context, before time.Time) ([]api_keys.ListDueAPIKeyExpirationsRow, error) {
+	expirations, err := r.db.APIKeys.ListDueAPIKeyExpirations(ctx, before.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due API key expirations: %w", err)
+	}
+	return expirations, nil
+}
+
+// MarkAPIKeyExpirationWarned 记录已向所有者发送过期提醒
+func (r *apiKeyRepository) MarkAPIKeyExpirationWarned(ctx context.Context, apiKeyID int64, warnedAt time.Time) error {
+	err := r.db.APIKeys.MarkAPIKeyExpirationWarned(ctx, api_keys.MarkAPIKeyExpirationWarnedParams{
+		ApiKeyID: apiKeyID,
+		WarnedAt: sql.NullTime{Time: warnedAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark API key expiration warned: %w", err)
+	}
+	return nil
+}
+
+// MarkAPIKeyExpired 将API密钥标记为已过期
+func (r *apiKeyRepository) MarkAPIKeyExpired(ctx context.Context, apiKeyID int64, expiredAt time.Time) error {
+	err := r.db.APIKeys.MarkAPIKeyExpired(ctx, api_keys.MarkAPIKeyExpiredParams{
+		ApiKeyID:  apiKeyID,
+		ExpiredAt: sql.NullTime{Time: expiredAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark API key expired: %w", err)
+	}
+	return nil
+}
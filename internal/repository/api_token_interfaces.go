@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-springAi/internal/database/generated/personal_access_tokens"
+)
+
+// APITokenRepository 个人访问令牌数据访问层接口
+type APITokenRepository interface {
+	// Create 保存个人访问令牌哈希
+	Create(ctx context.Context, params CreateAPITokenParams) (*personal_access_tokens.PersonalAccessToken, error)
+
+	// GetByHash 根据令牌哈希获取个人访问令牌
+	GetByHash(ctx context.Context, tokenHash string) (*personal_access_tokens.PersonalAccessToken, error)
+
+	// ListByUser 获取用户的所有个人访问令牌
+	ListByUser(ctx context.Context, userID int64) ([]personal_access_tokens.PersonalAccessToken, error)
+
+	// Revoke 吊销用户的个人访问令牌，令牌不存在或已吊销时返回 false
+	Revoke(ctx context.Context, id, userID int64) (bool, error)
+
+	// Touch 更新令牌最后使用时间
+	Touch(ctx context.Context, id int64) error
+}
+
+// CreateAPITokenParams 创建个人访问令牌参数
+type CreateAPITokenParams struct {
+	UserID      int64        `json:"user_id"`
+	Name        string       `json:"name"`
+	TokenHash   string       `json:"token_hash"`
+	TokenPrefix string       `json:"token_prefix"`
+	Scopes      string       `json:"scopes"`
+	ExpiresAt   sql.NullTime `json:"expires_at"`
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/personal_access_tokens"
+	"go-springAi/internal/errors"
+)
+
+// apiTokenRepository 个人访问令牌数据访问层实现
+type apiTokenRepository struct {
+	db *database.DB
+}
+
+// NewAPITokenRepository 创建个人访问令牌数据访问层
+func NewAPITokenRepository(db *database.DB) APITokenRepository {
+	return &apiTokenRepository{
+		db: db,
+	}
+}
+
+// Create 保存个人访问令牌哈希
+func (r *apiTokenRepository) Create(ctx context.Context, params CreateAPITokenParams) (*personal_access_tokens.PersonalAccessToken, error) {
+	token, err := r.db.PersonalAccessTokens.CreatePersonalAccessToken(ctx, personal_access_tokens.CreatePersonalAccessTokenParams{
+		UserID:      params.UserID,
+		Name:        params.Name,
+		TokenHash:   params.TokenHash,
+		TokenPrefix: params.TokenPrefix,
+		Scopes:      params.Scopes,
+		ExpiresAt:   params.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// GetByHash 根据令牌哈希获取个人访问令牌
+func (r *apiTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*personal_access_tokens.PersonalAccessToken, error) {
+	token, err := r.db.PersonalAccessTokens.GetPersonalAccessTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Personal access token")
+		}
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByUser 获取用户的所有个人访问令牌
+func (r *apiTokenRepository) ListByUser(ctx context.Context, userID int64) ([]personal_access_tokens.PersonalAccessToken, error) {
+	tokens, err := r.db.PersonalAccessTokens.ListUserPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke 吊销用户的个人访问令牌，令牌不存在或已吊销时返回 false
+func (r *apiTokenRepository) Revoke(ctx context.Context, id, userID int64) (bool, error) {
+	rows, err := r.db.PersonalAccessTokens.RevokePersonalAccessToken(ctx, personal_access_tokens.RevokePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke personal access token: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Touch 更新令牌最后使用时间
+func (r *apiTokenRepository) Touch(ctx context.Context, id int64) error {
+	if err := r.db.PersonalAccessTokens.TouchPersonalAccessToken(ctx, id); err != nil {
+		return fmt.Errorf("failed to update personal access token usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/evals"
+)
+
+// EvalRepository 评测套件和运行记录数据访问层接口
+type EvalRepository interface {
+	// CreateSuite 创建评测套件
+	CreateSuite(ctx context.Context, params evals.CreateEvalSuiteParams) (*evals.EvalSuite, error)
+
+	// UpdateSuite 更新评测套件
+	UpdateSuite(ctx context.Context, params evals.UpdateEvalSuiteParams) (*evals.EvalSuite, error)
+
+	// DeleteSuite 删除评测套件及其运行记录，返回是否确实删除了
+	DeleteSuite(ctx context.Context, id int64) (bool, error)
+
+	// GetSuite 获取评测套件
+	GetSuite(ctx context.Context, id int64) (*evals.EvalSuite, error)
+
+	// ListSuites 获取所有评测套件
+	ListSuites(ctx context.Context) ([]evals.EvalSuite, error)
+
+	// CreateRun 创建运行中的评测记录
+	CreateRun(ctx context.Context, params evals.CreateEvalRunParams) (*evals.EvalRun, error)
+
+	// FinishRun 保存评测结果并结束运行
+	FinishRun(ctx context.Context, params evals.FinishEvalRunParams) error
+
+	// GetRun 获取评测运行记录
+	GetRun(ctx context.Context, id int64) (*evals.EvalRun, error)
+
+	// ListRuns 获取套件最近的运行记录，按时间倒序
+	ListRuns(ctx context.Context, suiteID int64, limit int) ([]evals.EvalRun, error)
+}
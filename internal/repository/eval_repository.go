@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/evals"
+	"go-springAi/internal/errors"
+)
+
+// evalRepository 评测数据访问层实现
+type evalRepository struct {
+	db *database.DB
+}
+
+// NewEvalRepository 创建评测数据访问层
+func NewEvalRepository(db *database.DB) EvalRepository {
+	return &evalRepository{
+		db: db,
+	}
+}
+
+// CreateSuite 创建评测套件
+func (r *evalRepository) CreateSuite(ctx context.Context, params evals.CreateEvalSuiteParams) (*evals.EvalSuite, error) {
+	suite, err := r.db.Evals.CreateEvalSuite(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eval suite: %w", err)
+	}
+	return &suite, nil
+}
+
+// UpdateSuite 更新评测套件
+func (r *evalRepository) UpdateSuite(ctx context.Context, params evals.UpdateEvalSuiteParams) (*evals.EvalSuite, error) {
+	suite, err := r.db.Evals.UpdateEvalSuite(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Eval suite")
+		}
+		return nil, fmt.Errorf("failed to update eval suite: %w", err)
+	}
+	return &suite, nil
+}
+
+// DeleteSuite 删除评测套件及其运行记录，返回是否确实删除了
+func (r *evalRepository) DeleteSuite(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.Evals.DeleteEvalSuite(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete eval suite: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetSuite 获取评测套件
+func (r *evalRepository) GetSuite(ctx context.Context, id int64) (*evals.EvalSuite, error) {
+	suite, err := r.db.Evals.GetEvalSuite(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Eval suite")
+		}
+		return nil, fmt.Errorf("failed to get eval suite: %w", err)
+	}
+	return &suite, nil
+}
+
+// ListSuites 获取所有评测套件
+func (r *evalRepository) ListSuites(ctx context.Context) ([]evals.EvalSuite, error) {
+	suites, err := r.db.Evals.ListEvalSuites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eval suites: %w", err)
+	}
+	return suites, nil
+}
+
+// CreateRun 创建运行中的评测记录
+func (r *evalRepository) CreateRun(ctx context.Context, params evals.CreateEvalRunParams) (*evals.EvalRun, error) {
+	run, err := r.db.Evals.CreateEvalRun(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eval run: %w", err)
+	}
+	return &run, nil
+}
+
+// FinishRun 保存评测结果并结束运行
+func (r *evalRepository) FinishRun(ctx context.Context, params evals.FinishEvalRunParams) error {
+	if err := r.db.Evals.FinishEvalRun(ctx, params); err != nil {
+		return fmt.Errorf("failed to finish eval run: %w", err)
+	}
+	return nil
+}
+
+// GetRun 获取评测运行记录
+func (r *evalRepository) GetRun(ctx context.Context, id int64) (*evals.EvalRun, error) {
+	run, err := r.db.Evals.GetEvalRun(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Eval run")
+		}
+		return nil, fmt.Errorf("failed to get eval run: %w", err)
+	}
+	return &run, nil
+}
+
+// ListRuns 获取套件最近的运行记录，按时间倒序
+func (r *evalRepository) ListRuns(ctx context.Context, suiteID int64, limit int) ([]evals.EvalRun, error) {
+	runs, err := r.db.Evals.ListEvalRuns(ctx, evals.ListEvalRunsParams{
+		SuiteID: suiteID,
+		Limit:   int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list eval runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/experiments"
+)
+
+// ExperimentRepository A/B 实验及其结果数据访问层接口
+type ExperimentRepository interface {
+	// Create 创建草稿状态的实验
+	Create(ctx context.Context, params experiments.CreateExperimentParams) (*experiments.Experiment, error)
+
+	// Update 更新实验的配置
+	Update(ctx context.Context, params experiments.UpdateExperimentParams) (*experiments.Experiment, error)
+
+	// UpdateStatus 更新实验状态及开始、结束时间
+	UpdateStatus(ctx context.Context, params experiments.UpdateExperimentStatusParams) (*experiments.Experiment, error)
+
+	// Delete 删除实验及其结果，返回是否确实删除了
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	// Get 获取实验
+	Get(ctx context.Context, id int64) (*experiments.Experiment, error)
+
+	// GetRunningByPersona 获取角色正在运行的实验，没有时返回未找到错误
+	GetRunningByPersona(ctx context.Context, persona string) (*experiments.Experiment, error)
+
+	// List 获取所有实验
+	List(ctx context.Context) ([]experiments.Experiment, error)
+
+	// RecordOutcome 记录一个请求的结果
+	RecordOutcome(ctx context.Context, params experiments.CreateExperimentOutcomeParams) error
+
+	// SetFeedback 记录用户对回复的评价，返回是否有对应的实验结果
+	SetFeedback(ctx context.Context, responseID string, feedback int64) (bool, error)
+
+	// VariantStats 按变体汇总实验结果
+	VariantStats(ctx context.Context, experimentID int64) ([]experiments.ListExperimentVariantStatsRow, error)
+}
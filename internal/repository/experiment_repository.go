@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/experiments"
+	"go-springAi/internal/errors"
+)
+
+// experimentRepository A/B 实验数据访问层实现
+type experimentRepository struct {
+	db *database.DB
+}
+
+// NewExperimentRepository 创建 A/B 实验数据访问层
+func NewExperimentRepository(db *database.DB) ExperimentRepository {
+	return &experimentRepository{
+		db: db,
+	}
+}
+
+// Create 创建草稿状态的实验
+func (r *experimentRepository) Create(ctx context.Context, params experiments.CreateExperimentParams) (*experiments.Experiment, error) {
+	experiment, err := r.db.Experiments.CreateExperiment(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create experiment: %w", err)
+	}
+	return &experiment, nil
+}
+
+// Update 更新实验的配置
+func (r *experimentRepository) Update(ctx context.Context, params experiments.UpdateExperimentParams) (*experiments.Experiment, error) {
+	experiment, err := r.db.Experiments.UpdateExperiment(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Experiment")
+		}
+		return nil, fmt.Errorf("failed to update experiment: %w", err)
+	}
+	return &experiment, nil
+}
+
+// UpdateStatus 更新实验状态及开始、结束时间
+func (r *experimentRepository) UpdateStatus(ctx context.Context, params experiments.UpdateExperimentStatusParams) (*experiments.Experiment, error) {
+	experiment, err := r.db.Experiments.UpdateExperimentStatus(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Experiment")
+		}
+		return nil, fmt.Errorf("failed to update experiment status: %w", err)
+	}
+	return &experiment, nil
+}
+
+// Delete 删除实验及其结果，返回是否确实删除了
+func (r *experimentRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.Experiments.DeleteExperiment(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete experiment: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Get 获取实验
+func (r *experimentRepository) Get(ctx context.Context, id int64) (*experiments.Experiment, error) {
+	experiment, err := r.db.Experiments.GetExperiment(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Experiment")
+		}
+		return nil, fmt.Errorf("failed to get experiment: %w", err)
+	}
+	return &experiment, nil
+}
+
+// GetRunningByPersona 获取角色正在运行的实验，没有时返回未找到错误
+func (r *experimentRepository) GetRunningByPersona(ctx context.Context, persona string) (*experiments.Experiment, error) {
+	experiment, err := r.db.Experiments.GetRunningExperimentByPersona(ctx, persona)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Experiment")
+		}
+		return nil, fmt.Errorf("failed to get running experiment: %w", err)
+	}
+	return &experiment, nil
+}
+
+// List 获取所有实验
+func (r *experimentRepository) List(ctx context.Context) ([]experiments.Experiment, error) {
+	items, err := r.db.Experiments.ListExperiments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+	return items, nil
+}
+
+// RecordOutcome 记录一个请求的结果
+func (r *experimentRepository) RecordOutcome(ctx context.Context, params experiments.CreateExperimentOutcomeParams) error {
+	if err := r.db.Experiments.CreateExperimentOutcome(ctx, params); err != nil {
+		return fmt.Errorf("failed to record experiment outcome: %w", err)
+	}
+	return nil
+}
+
+// SetFeedback 记录用户对回复的评价，返回是否有对应的实验结果
+func (r *experimentRepository) SetFeedback(ctx context.Context, responseID string, feedback int64) (bool, error) {
+	rows, err := r.db.Experiments.SetExperimentOutcomeFeedback(ctx, experiments.SetExperimentOutcomeFeedbackParams{
+		ResponseID: responseID,
+		Feedback:   feedback,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to set experiment feedback: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// VariantStats 按变体汇总实验结果
+func (r *experimentRepository) VariantStats(ctx context.Context, experimentID int64) ([]experiments.ListExperimentVariantStatsRow, error) {
+	stats, err := r.db.Experiments.ListExperimentVariantStats(ctx, experimentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiment stats: %w", err)
+	}
+	return stats, nil
+}
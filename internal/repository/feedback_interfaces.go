@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/feedback"
+)
+
+// FeedbackRepository 回复评价数据访问层接口
+type FeedbackRepository interface {
+	// Upsert 保存评价，同一用户对同一回复重复评价时更新评分、分类和备注
+	Upsert(ctx context.Context, params feedback.UpsertResponseFeedbackParams) (*feedback.ResponseFeedback, error)
+
+	// List 获取最近的评价
+	List(ctx context.Context, limit int) ([]feedback.ResponseFeedback, error)
+
+	// ListByRating 获取指定评分最近的评价
+	ListByRating(ctx context.Context, rating int64, limit int) ([]feedback.ResponseFeedback, error)
+}
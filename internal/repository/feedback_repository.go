@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/feedback"
+)
+
+// feedbackRepository 回复评价数据访问层实现
+type feedbackRepository struct {
+	db *database.DB
+}
+
+// NewFeedbackRepository 创建回复评价数据访问层
+func NewFeedbackRepository(db *database.DB) FeedbackRepository {
+	return &feedbackRepository{
+		db: db,
+	}
+}
+
+// Upsert 保存评价，同一用户对同一回复重复评价时更新评分、分类和备注
+func (r *feedbackRepository) Upsert(ctx context.Context, params feedback.UpsertResponseFeedbackParams) (*feedback.ResponseFeedback, error) {
+	item, err := r.db.Feedback.UpsertResponseFeedback(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save response feedback: %w", err)
+	}
+	return &item, nil
+}
+
+// List 获取最近的评价
+func (r *feedbackRepository) List(ctx context.Context, limit int) ([]feedback.ResponseFeedback, error) {
+	items, err := r.db.Feedback.ListResponseFeedback(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list response feedback: %w", err)
+	}
+	return items, nil
+}
+
+// ListByRating 获取指定评分最近的评价
+func (r *feedbackRepository) ListByRating(ctx context.Context, rating int64, limit int) ([]feedback.ResponseFeedback, error) {
+	items, err := r.db.Feedback.ListResponseFeedbackByRating(ctx, feedback.ListResponseFeedbackByRatingParams{
+		Rating: rating,
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list response feedback: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/fine_tuning_jobs"
+)
+
+// FineTuningJobRepository 微调任务数据访问层接口
+type FineTuningJobRepository interface {
+	// Create 保存新创建的微调任务
+	Create(ctx context.Context, params fine_tuning_jobs.CreateFineTuningJobParams) (*fine_tuning_jobs.FineTuningJob, error)
+
+	// Get 按 OpenAI 任务ID获取微调任务
+	Get(ctx context.Context, jobID string) (*fine_tuning_jobs.FineTuningJob, error)
+
+	// List 按创建时间倒序获取最近的微调任务
+	List(ctx context.Context, limit int) ([]fine_tuning_jobs.FineTuningJob, error)
+
+	// ListPending 获取未结束，或已成功但模型尚未添加到模型配置的任务
+	ListPending(ctx context.Context) ([]fine_tuning_jobs.FineTuningJob, error)
+
+	// UpdateStatus 保存任务的最新状态
+	UpdateStatus(ctx context.Context, params fine_tuning_jobs.UpdateFineTuningJobStatusParams) error
+
+	// MarkModelRegistered 记录微调后的模型已添加到模型配置
+	MarkModelRegistered(ctx context.Context, jobID string) error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/fine_tuning_jobs"
+	"go-springAi/internal/errors"
+)
+
+// fineTuningJobRepository 微调任务数据访问层实现
+type fineTuningJobRepository struct {
+	db *database.DB
+}
+
+// NewFineTuningJobRepository 创建微调任务数据访问层
+func NewFineTuningJobRepository(db *database.DB) FineTuningJobRepository {
+	return &fineTuningJobRepository{
+		db: db,
+	}
+}
+
+// Create 保存新创建的微调任务
+func (r *fineTuningJobRepository) Create(ctx context.Context, params fine_tuning_jobs.CreateFineTuningJobParams) (*fine_tuning_jobs.FineTuningJob, error) {
+	job, err := r.db.FineTuningJobs.CreateFineTuningJob(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fine-tuning job: %w", err)
+	}
+	return &job, nil
+}
+
+// Get 按 OpenAI 任务ID获取微调任务
+func (r *fineTuningJobRepository) Get(ctx context.Context, jobID string) (*fine_tuning_jobs.FineTuningJob, error) {
+	job, err := r.db.FineTuningJobs.GetFineTuningJob(ctx, jobID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Fine-tuning job")
+		}
+		return nil, fmt.Errorf("failed to get fine-tuning job: %w", err)
+	}
+	return &job, nil
+}
+
+// List 按创建时间倒序获取最近的微调任务
+func (r *fineTuningJobRepository) List(ctx context.Context, limit int) ([]fine_tuning_jobs.FineTuningJob, error) {
+	jobs, err := r.db.FineTuningJobs.ListFineTuningJobs(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fine-tuning jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListPending 获取未结束，或已成功但模型尚未添加到模型配置的任务
+func (r *fineTuningJobRepository) ListPending(ctx context.Context) ([]fine_tuning_jobs.FineTuningJob, error) {
+	jobs, err := r.db.FineTuningJobs.ListPendingFineTuningJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending fine-tuning jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// UpdateStatus 保存任务的最新状态
+func (r *fineTuningJobRepository) UpdateStatus(ctx context.Context, params fine_tuning_jobs.UpdateFineTuningJobStatusParams) error {
+	if err := r.db.FineTuningJobs.UpdateFineTuningJobStatus(ctx, params); err != nil {
+		return fmt.Errorf("failed to update fine-tuning job: %w", err)
+	}
+	return nil
+}
+
+// MarkModelRegistered 记录微调后的模型已添加到模型配置
+func (r *fineTuningJobRepository) MarkModelRegistered(ctx context.Context, jobID string) error {
+	if err := r.db.FineTuningJobs.MarkFineTuningModelRegistered(ctx, jobID); err != nil {
+		return fmt.Errorf("failed to mark fine-tuned model registered: %w", err)
+	}
+	return nil
+}
@@ -8,17 +8,51 @@ import (
 
 // repositoryManager 数据访问层管理器实现
 type repositoryManager struct {
-	db         *database.DB
-	userRepo   UserRepository
-	apiKeyRepo APIKeyRepository
+	db               *database.DB
+	userRepo         UserRepository
+	apiKeyRepo       APIKeyRepository
+	refreshTokenRepo RefreshTokenRepository
+	apiTokenRepo     APITokenRepository
+	aiUsageRepo      AIUsageRepository
+	preferenceRepo   UserPreferenceRepository
+	permissionRepo   UserPermissionRepository
+	projectRepo      ProjectRepository
+	webhookRepo      WebhookRepository
+	scheduledJobRepo ScheduledJobRepository
+	notificationRepo NotificationChannelRepository
+	modelMetaRepo    ModelMetadataRepository
+	aiSpendRepo      AISpendRepository
+	fineTuningRepo   FineTuningJobRepository
+	evalRepo         EvalRepository
+	experimentRepo   ExperimentRepository
+	feedbackRepo     FeedbackRepository
+	personaRepo      PersonaRepository
+	memoryRepo       MemoryRepository
 }
 
 // NewRepositoryManager 创建数据访问层管理器
 func NewRepositoryManager(db *database.DB) RepositoryManager {
 	return &repositoryManager{
-		db:         db,
-		userRepo:   NewUserRepository(db),
-		apiKeyRepo: NewAPIKeyRepository(db),
+		db:               db,
+		userRepo:         NewUserRepository(db),
+		apiKeyRepo:       NewAPIKeyRepository(db),
+		refreshTokenRepo: NewRefreshTokenRepository(db),
+		apiTokenRepo:     NewAPITokenRepository(db),
+		aiUsageRepo:      NewAIUsageRepository(db),
+		preferenceRepo:   NewUserPreferenceRepository(db),
+		permissionRepo:   NewUserPermissionRepository(db),
+		projectRepo:      NewProjectRepository(db),
+		webhookRepo:      NewWebhookRepository(db),
+		scheduledJobRepo: NewScheduledJobRepository(db),
+		notificationRepo: NewNotificationChannelRepository(db),
+		modelMetaRepo:    NewModelMetadataRepository(db),
+		aiSpendRepo:      NewAISpendRepository(db),
+		fineTuningRepo:   NewFineTuningJobRepository(db),
+		evalRepo:         NewEvalRepository(db),
+		experimentRepo:   NewExperimentRepository(db),
+		feedbackRepo:     NewFeedbackRepository(db),
+		personaRepo:      NewPersonaRepository(db),
+		memoryRepo:       NewMemoryRepository(db),
 	}
 }
 
@@ -32,7 +66,99 @@ func (rm *repositoryManager) APIKey() APIKeyRepository {
 	return rm.apiKeyRepo
 }
 
-// Close 关闭数据库连接
+// RefreshToken 获取刷新令牌数据访问层
+func (rm *repositoryManager) RefreshToken() RefreshTokenRepository {
+	return rm.refreshTokenRepo
+}
+
+// APIToken 获取个人访问令牌数据访问层
+func (rm *repositoryManager) APIToken() APITokenRepository {
+	return rm.apiTokenRepo
+}
+
+// AIUsage 获取AI用量统计数据访问层
+func (rm *repositoryManager) AIUsage() AIUsageRepository {
+	return rm.aiUsageRepo
+}
+
+// UserPreference 获取用户偏好设置数据访问层
+func (rm *repositoryManager) UserPreference() UserPreferenceRepository {
+	return rm.preferenceRepo
+}
+
+// UserPermission 获取用户权限数据访问层
+func (rm *repositoryManager) UserPermission() UserPermissionRepository {
+	return rm.permissionRepo
+}
+
+// Project 获取项目数据访问层
+func (rm *repositoryManager) Project() ProjectRepository {
+	return rm.projectRepo
+}
+
+// Webhook 获取 Webhook 数据访问层
+func (rm *repositoryManager) Webhook() WebhookRepository {
+	return rm.webhookRepo
+}
+
+// ScheduledJob 获取定时任务数据访问层
+func (rm *repositoryManager) ScheduledJob() ScheduledJobRepository {
+	return rm.scheduledJobRepo
+}
+
+// NotificationChannel 获取用户通知渠道数据访问层
+func (rm *repositoryManager) NotificationChannel() NotificationChannelRepository {
+	return rm.notificationRepo
+}
+
+// ModelMetadata 获取模型元数据数据访问层
+func (rm *repositoryManager) ModelMetadata() ModelMetadataRepository {
+	return rm.modelMetaRepo
+}
+
+// AISpend 获取AI费用统计数据访问层
+func (rm *repositoryManager) AISpend() AISpendRepository {
+	return rm.aiSpendRepo
+}
+
+// FineTuningJob 获取微调任务数据访问层
+func (rm *repositoryManager) FineTuningJob() FineTuningJobRepository {
+	return rm.fineTuningRepo
+}
+
+// Eval 获取评测数据访问层
+func (rm *repositoryManager) Eval() EvalRepository {
+	return rm.evalRepo
+}
+
+// Experiment 获取 A/B 实验数据访问层
+func (rm *repositoryManager) Experiment() ExperimentRepository {
+	return rm.experimentRepo
+}
+
+// Feedback 获取回复评价数据访问层
+func (rm *repositoryManager) Feedback() FeedbackRepository {
+	return rm.feedbackRepo
+}
+
+// Persona 获取助手角色数据访问层
+func (rm *repositoryManager) Persona() PersonaRepository {
+	return rm.personaRepo
+}
+
+// Memory 获取用户长期记忆数据访问层
+func (rm *repositoryManager) Memory() MemoryRepository {
+	return rm.memoryRepo
+}
+
+// WithTx 在同一事务中执行 fn，嵌套调用时加入外层事务
+func (rm *repositoryManager) WithTx(ctx context.Context, fn func(tx RepositoryManager) error) error {
+	return rm.db.WithTx(ctx, func(tx *database.DB) error {
+		return fn(NewRepositoryManager(tx))
+	})
+}
+
+// Close 关闭数据库连接，事务中的管理器不会关闭连接
 func (rm *repositoryManager) Close() error {
 	return rm.db.Close()
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/memories"
+)
+
+// MemoryRepository 用户长期记忆数据访问层接口
+type MemoryRepository interface {
+	// Upsert 保存记忆，同一用户相同 memory_key 的记忆被覆盖
+	Upsert(ctx context.Context, params memories.UpsertUserMemoryParams) (*memories.UserMemory, error)
+
+	// List 获取用户的所有记忆，最近更新的在前
+	List(ctx context.Context, userID int64) ([]memories.UserMemory, error)
+
+	// Delete 删除用户的一条记忆，返回是否确实删除了
+	Delete(ctx context.Context, userID, id int64) (bool, error)
+
+	// DeleteAll 删除用户的所有记忆，返回删除的数量
+	DeleteAll(ctx context.Context, userID int64) (int64, error)
+
+	// GetSettings 获取用户的记忆设置，没有时返回未找到错误
+	GetSettings(ctx context.Context, userID int64) (*memories.UserMemorySetting, error)
+
+	// SaveSettings 保存用户的记忆设置
+	SaveSettings(ctx context.Context, userID int64, enabled bool) (*memories.UserMemorySetting, error)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/memories"
+	"go-springAi/internal/errors"
+)
+
+// memoryRepository 用户长期记忆数据访问层实现
+type memoryRepository struct {
+	db *database.DB
+}
+
+// NewMemoryRepository 创建用户长期记忆数据访问层
+func NewMemoryRepository(db *database.DB) MemoryRepository {
+	return &memoryRepository{
+		db: db,
+	}
+}
+
+// Upsert 保存记忆
+func (r *memoryRepository) Upsert(ctx context.Context, params memories.UpsertUserMemoryParams) (*memories.UserMemory, error) {
+	memory, err := r.db.Memories.UpsertUserMemory(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert user memory: %w", err)
+	}
+	return &memory, nil
+}
+
+// List 获取用户的所有记忆
+func (r *memoryRepository) List(ctx context.Context, userID int64) ([]memories.UserMemory, error) {
+	items, err := r.db.Memories.ListUserMemories(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user memories: %w", err)
+	}
+	return items, nil
+}
+
+// Delete 删除用户的一条记忆，返回是否确实删除了
+func (r *memoryRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	rows, err := r.db.Memories.DeleteUserMemory(ctx, memories.DeleteUserMemoryParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user memory: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteAll 删除用户的所有记忆
+func (r *memoryRepository) DeleteAll(ctx context.Context, userID int64) (int64, error) {
+	rows, err := r.db.Memories.DeleteUserMemories(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user memories: %w", err)
+	}
+	return rows, nil
+}
+
+// GetSettings 获取用户的记忆设置
+func (r *memoryRepository) GetSettings(ctx context.Context, userID int64) (*memories.UserMemorySetting, error) {
+	settings, err := r.db.Memories.GetUserMemorySettings(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("User memory settings")
+		}
+		return nil, fmt.Errorf("failed to get user memory settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveSettings 保存用户的记忆设置
+func (r *memoryRepository) SaveSettings(ctx context.Context, userID int64, enabled bool) (*memories.UserMemorySetting, error) {
+	settings, err := r.db.Memories.UpsertUserMemorySettings(ctx, memories.UpsertUserMemorySettingsParams{UserID: userID, Enabled: enabled})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user memory settings: %w", err)
+	}
+	return &settings, nil
+}
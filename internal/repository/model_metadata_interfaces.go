@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/model_metadata"
+)
+
+// ModelMetadataRepository 模型元数据数据访问层接口
+type ModelMetadataRepository interface {
+	// List 获取所有模型元数据，按提供商和模型排序
+	List(ctx context.Context) ([]model_metadata.ModelMetadatum, error)
+
+	// Upsert 创建或覆盖某个模型的元数据
+	Upsert(ctx context.Context, params model_metadata.UpsertModelMetadataParams) (*model_metadata.ModelMetadatum, error)
+
+	// Delete 删除某个模型的元数据，返回是否确实删除了
+	Delete(ctx context.Context, provider, model string) (bool, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/model_metadata"
+)
+
+// modelMetadataRepository 模型元数据数据访问层实现
+type modelMetadataRepository struct {
+	db *database.DB
+}
+
+// NewModelMetadataRepository 创建模型元数据数据访问层
+func NewModelMetadataRepository(db *database.DB) ModelMetadataRepository {
+	return &modelMetadataRepository{
+		db: db,
+	}
+}
+
+// List 获取所有模型元数据
+func (r *modelMetadataRepository) List(ctx context.Context) ([]model_metadata.ModelMetadatum, error) {
+	items, err := r.db.ModelMetadata.ListModelMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model metadata: %w", err)
+	}
+	return items, nil
+}
+
+// Upsert 创建或覆盖某个模型的元数据
+func (r *modelMetadataRepository) Upsert(ctx context.Context, params model_metadata.UpsertModelMetadataParams) (*model_metadata.ModelMetadatum, error) {
+	item, err := r.db.ModelMetadata.UpsertModelMetadata(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save model metadata: %w", err)
+	}
+	return &item, nil
+}
+
+// Delete 删除某个模型的元数据
+func (r *modelMetadataRepository) Delete(ctx context.Context, provider, model string) (bool, error) {
+	rows, err := r.db.ModelMetadata.DeleteModelMetadata(ctx, model_metadata.DeleteModelMetadataParams{
+		Provider: provider,
+		Model:    model,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete model metadata: %w", err)
+	}
+	return rows > 0, nil
+}
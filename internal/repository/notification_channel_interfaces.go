@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/notification_channels"
+)
+
+// NotificationChannelRepository 用户通知渠道数据访问层接口
+type NotificationChannelRepository interface {
+	// Upsert 创建或覆盖用户的某个渠道
+	Upsert(ctx context.Context, params notification_channels.UpsertNotificationChannelParams) (*notification_channels.NotificationChannel, error)
+
+	// Delete 删除用户的某个渠道，返回是否确实删除了
+	Delete(ctx context.Context, userID int64, channel string) (bool, error)
+
+	// Get 获取用户的某个渠道
+	Get(ctx context.Context, userID int64, channel string) (*notification_channels.NotificationChannel, error)
+
+	// ListByUser 获取用户的所有渠道
+	ListByUser(ctx context.Context, userID int64) ([]notification_channels.NotificationChannel, error)
+
+	// ListEnabled 获取所有用户启用的渠道
+	ListEnabled(ctx context.Context) ([]notification_channels.NotificationChannel, error)
+}
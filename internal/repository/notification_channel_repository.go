@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/notification_channels"
+	"go-springAi/internal/errors"
+)
+
+// notificationChannelRepository 用户通知渠道数据访问层实现
+type notificationChannelRepository struct {
+	db *database.DB
+}
+
+// NewNotificationChannelRepository 创建用户通知渠道数据访问层
+func NewNotificationChannelRepository(db *database.DB) NotificationChannelRepository {
+	return &notificationChannelRepository{
+		db: db,
+	}
+}
+
+// Upsert 创建或覆盖用户的某个渠道
+func (r *notificationChannelRepository) Upsert(ctx context.Context, params notification_channels.UpsertNotificationChannelParams) (*notification_channels.NotificationChannel, error) {
+	channel, err := r.db.NotificationChannels.UpsertNotificationChannel(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notification channel: %w", err)
+	}
+	return &channel, nil
+}
+
+// Delete 删除用户的某个渠道
+func (r *notificationChannelRepository) Delete(ctx context.Context, userID int64, channel string) (bool, error) {
+	rows, err := r.db.NotificationChannels.DeleteNotificationChannel(ctx, notification_channels.DeleteNotificationChannelParams{
+		UserID:  userID,
+		Channel: channel,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Get 获取用户的某个渠道
+func (r *notificationChannelRepository) Get(ctx context.Context, userID int64, channel string) (*notification_channels.NotificationChannel, error) {
+	item, err := r.db.NotificationChannels.GetNotificationChannel(ctx, notification_channels.GetNotificationChannelParams{
+		UserID:  userID,
+		Channel: channel,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Notification channel")
+		}
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+	return &item, nil
+}
+
+// ListByUser 获取用户的所有渠道
+func (r *notificationChannelRepository) ListByUser(ctx context.Context, userID int64) ([]notification_channels.NotificationChannel, error) {
+	items, err := r.db.NotificationChannels.ListUserNotificationChannels(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	return items, nil
+}
+
+// ListEnabled 获取所有用户启用的渠道
+func (r *notificationChannelRepository) ListEnabled(ctx context.Context) ([]notification_channels.NotificationChannel, error) {
+	items, err := r.db.NotificationChannels.ListEnabledNotificationChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enabled notification channels: %w", err)
+	}
+	return items, nil
+}
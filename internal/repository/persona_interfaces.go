@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/personas"
+)
+
+// PersonaRepository 助手角色数据访问层接口
+type PersonaRepository interface {
+	// Create 创建助手角色
+	Create(ctx context.Context, params personas.CreatePersonaParams) (*personas.Persona, error)
+
+	// Update 覆盖保存助手角色
+	Update(ctx context.Context, params personas.UpdatePersonaParams) (*personas.Persona, error)
+
+	// Delete 删除助手角色，返回是否确实删除了
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	// Get 获取助手角色
+	Get(ctx context.Context, id int64) (*personas.Persona, error)
+
+	// GetByName 按名称获取助手角色，没有时返回未找到错误
+	GetByName(ctx context.Context, name string) (*personas.Persona, error)
+
+	// List 获取所有助手角色
+	List(ctx context.Context) ([]personas.Persona, error)
+}
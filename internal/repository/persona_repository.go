@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/personas"
+	"go-springAi/internal/errors"
+)
+
+// personaRepository 助手角色数据访问层实现
+type personaRepository struct {
+	db *database.DB
+}
+
+// NewPersonaRepository 创建助手角色数据访问层
+func NewPersonaRepository(db *database.DB) PersonaRepository {
+	return &personaRepository{
+		db: db,
+	}
+}
+
+// Create 创建助手角色
+func (r *personaRepository) Create(ctx context.Context, params personas.CreatePersonaParams) (*personas.Persona, error) {
+	persona, err := r.db.Personas.CreatePersona(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create persona: %w", err)
+	}
+	return &persona, nil
+}
+
+// Update 覆盖保存助手角色
+func (r *personaRepository) Update(ctx context.Context, params personas.UpdatePersonaParams) (*personas.Persona, error) {
+	persona, err := r.db.Personas.UpdatePersona(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Persona")
+		}
+		return nil, fmt.Errorf("failed to update persona: %w", err)
+	}
+	return &persona, nil
+}
+
+// Delete 删除助手角色，返回是否确实删除了
+func (r *personaRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.Personas.DeletePersona(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete persona: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Get 获取助手角色
+func (r *personaRepository) Get(ctx context.Context, id int64) (*personas.Persona, error) {
+	persona, err := r.db.Personas.GetPersona(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Persona")
+		}
+		return nil, fmt.Errorf("failed to get persona: %w", err)
+	}
+	return &persona, nil
+}
+
+// GetByName 按名称获取助手角色，没有时返回未找到错误
+func (r *personaRepository) GetByName(ctx context.Context, name string) (*personas.Persona, error) {
+	persona, err := r.db.Personas.GetPersonaByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Persona")
+		}
+		return nil, fmt.Errorf("failed to get persona by name: %w", err)
+	}
+	return &persona, nil
+}
+
+// List 获取所有助手角色
+func (r *personaRepository) List(ctx context.Context) ([]personas.Persona, error) {
+	items, err := r.db.Personas.ListPersonas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personas: %w", err)
+	}
+	return items, nil
+}
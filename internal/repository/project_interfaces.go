@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/projects"
+)
+
+// ProjectRepository 项目数据访问层接口
+type ProjectRepository interface {
+	// Create 创建项目
+	Create(ctx context.Context, userID int64, name, description string) (*projects.Project, error)
+
+	// Delete 删除用户的项目，返回是否确实删除了
+	Delete(ctx context.Context, userID, projectID int64) (bool, error)
+
+	// DeleteUsage 删除项目的用量记录
+	DeleteUsage(ctx context.Context, projectID int64) error
+
+	// Get 获取用户的项目
+	Get(ctx context.Context, userID, projectID int64) (*projects.Project, error)
+
+	// GetByName 按名称获取用户的项目
+	GetByName(ctx context.Context, userID int64, name string) (*projects.Project, error)
+
+	// List 获取用户的所有项目
+	List(ctx context.Context, userID int64) ([]projects.Project, error)
+
+	// ListUsage 获取项目自 since（含）起的每日用量，since 为 YYYY-MM-DD
+	ListUsage(ctx context.Context, projectID int64, since string) ([]projects.AiProjectUsage, error)
+
+	// RecordUsage 记录项目的一次AI请求及其消耗的令牌数
+	RecordUsage(ctx context.Context, projectID int64, usageDate string, tokens int64) error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/projects"
+	"go-springAi/internal/errors"
+)
+
+// projectRepository 项目数据访问层实现
+type projectRepository struct {
+	db *database.DB
+}
+
+// NewProjectRepository 创建项目数据访问层
+func NewProjectRepository(db *database.DB) ProjectRepository {
+	return &projectRepository{
+		db: db,
+	}
+}
+
+// Create 创建项目
+func (r *projectRepository) Create(ctx context.Context, userID int64, name, description string) (*projects.Project, error) {
+	project, err := r.db.Projects.CreateProject(ctx, projects.CreateProjectParams{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	return &project, nil
+}
+
+// Delete 删除用户的项目，返回是否确实删除了
+func (r *projectRepository) Delete(ctx context.Context, userID, projectID int64) (bool, error) {
+	rows, err := r.db.Projects.DeleteProject(ctx, projects.DeleteProjectParams{
+		ID:     projectID,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete project: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteUsage 删除项目的用量记录
+func (r *projectRepository) DeleteUsage(ctx context.Context, projectID int64) error {
+	if err := r.db.Projects.DeleteProjectUsage(ctx, projectID); err != nil {
+		return fmt.Errorf("failed to delete project usage: %w", err)
+	}
+	return nil
+}
+
+// Get 获取用户的项目
+func (r *projectRepository) Get(ctx context.Context, userID, projectID int64) (*projects.Project, error) {
+	project, err := r.db.Projects.GetProject(ctx, projects.GetProjectParams{
+		ID:     projectID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Project")
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return &project, nil
+}
+
+// GetByName 按名称获取用户的项目
+func (r *projectRepository) GetByName(ctx context.Context, userID int64, name string) (*projects.Project, error) {
+	project, err := r.db.Projects.GetProjectByName(ctx, projects.GetProjectByNameParams{
+		UserID: userID,
+		Name:   name,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Project")
+		}
+		return nil, fmt.Errorf("failed to get project by name: %w", err)
+	}
+	return &project, nil
+}
+
+// List 获取用户的所有项目
+func (r *projectRepository) List(ctx context.Context, userID int64) ([]projects.Project, error) {
+	items, err := r.db.Projects.ListProjectsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	return items, nil
+}
+
+// ListUsage 获取项目自 since（含）起的每日用量
+func (r *projectRepository) ListUsage(ctx context.Context, projectID int64, since string) ([]projects.AiProjectUsage, error) {
+	usage, err := r.db.Projects.ListProjectUsage(ctx, projects.ListProjectUsageParams{
+		ProjectID: projectID,
+		UsageDate: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project usage: %w", err)
+	}
+	return usage, nil
+}
+
+// RecordUsage 记录项目的一次AI请求及其消耗的令牌数
+func (r *projectRepository) RecordUsage(ctx context.Context, projectID int64, usageDate string, tokens int64) error {
+	err := r.db.Projects.RecordProjectUsage(ctx, projects.RecordProjectUsageParams{
+		ProjectID:  projectID,
+		UsageDate:  usageDate,
+		TokenCount: tokens,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record project usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-springAi/internal/database/generated/refresh_tokens"
+)
+
+// RefreshTokenRepository 刷新令牌数据访问层接口
+type RefreshTokenRepository interface {
+	// Create 保存刷新令牌哈希
+	Create(ctx context.Context, params CreateRefreshTokenParams) (*refresh_tokens.RefreshToken, error)
+
+	// GetByHash 根据令牌哈希获取刷新令牌
+	GetByHash(ctx context.Context, tokenHash string) (*refresh_tokens.RefreshToken, error)
+
+	// Revoke 吊销刷新令牌，令牌已被吊销时返回 false
+	Revoke(ctx context.Context, id int64) (bool, error)
+
+	// RevokeFamily 吊销同一令牌族中的所有刷新令牌
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeByUser 吊销用户的所有刷新令牌
+	RevokeByUser(ctx context.Context, userID int64) error
+
+	// DeleteExpired 删除在指定时间前过期的刷新令牌
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// CreateRefreshTokenParams 创建刷新令牌参数
+type CreateRefreshTokenParams struct {
+	UserID    int64     `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	FamilyID  string    `json:"family_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
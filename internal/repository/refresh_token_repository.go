@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/refresh_tokens"
+	"go-springAi/internal/errors"
+)
+
+// refreshTokenRepository 刷新令牌数据访问层实现
+type refreshTokenRepository struct {
+	db *database.DB
+}
+
+// NewRefreshTokenRepository 创建刷新令牌数据访问层
+func NewRefreshTokenRepository(db *database.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+// Create 保存刷新令牌哈希
+func (r *refreshTokenRepository) Create(ctx context.Context, params CreateRefreshTokenParams) (*refresh_tokens.RefreshToken, error) {
+	token, err := r.db.RefreshTokens.CreateRefreshToken(ctx, refresh_tokens.CreateRefreshTokenParams{
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		FamilyID:  params.FamilyID,
+		ExpiresAt: params.ExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// GetByHash 根据令牌哈希获取刷新令牌
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*refresh_tokens.RefreshToken, error) {
+	token, err := r.db.RefreshTokens.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Refresh token")
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// Revoke 吊销刷新令牌，令牌已被吊销时返回 false
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.RefreshTokens.RevokeRefreshToken(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RevokeFamily 吊销同一令牌族中的所有刷新令牌
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	if err := r.db.RefreshTokens.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeByUser 吊销用户的所有刷新令牌
+func (r *refreshTokenRepository) RevokeByUser(ctx context.Context, userID int64) error {
+	if err := r.db.RefreshTokens.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired 删除在指定时间前过期的刷新令牌
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	if err := r.db.RefreshTokens.DeleteExpiredRefreshTokens(ctx, before); err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	return nil
+}
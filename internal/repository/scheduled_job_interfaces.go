@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-springAi/internal/database/generated/scheduled_jobs"
+)
+
+// ScheduledJobRepository 定时任务数据访问层接口
+type ScheduledJobRepository interface {
+	// Create 创建任务
+	Create(ctx context.Context, params scheduled_jobs.CreateScheduledJobParams) (*scheduled_jobs.ScheduledJob, error)
+
+	// Update 更新任务的名称、计划、参数和启用状态
+	Update(ctx context.Context, params scheduled_jobs.UpdateScheduledJobParams) (*scheduled_jobs.ScheduledJob, error)
+
+	// Delete 删除任务，返回是否确实删除了
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	// Get 获取任务
+	Get(ctx context.Context, id int64) (*scheduled_jobs.ScheduledJob, error)
+
+	// GetByName 按名称获取任务
+	GetByName(ctx context.Context, name string) (*scheduled_jobs.ScheduledJob, error)
+
+	// List 获取所有任务
+	List(ctx context.Context) ([]scheduled_jobs.ScheduledJob, error)
+
+	// ListDue 获取 now 之前到期的启用任务
+	ListDue(ctx context.Context, now time.Time, limit int) ([]scheduled_jobs.ScheduledJob, error)
+
+	// Claim 将到期任务的下一次执行时间推进到 next，返回是否抢到本次执行；
+	// 多个实例同时检查时只有一个能成功
+	Claim(ctx context.Context, id int64, now, next time.Time) (bool, error)
+
+	// RecordRun 保存一次执行的结果
+	RecordRun(ctx context.Context, params scheduled_jobs.RecordScheduledJobRunParams) error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/scheduled_jobs"
+	"go-springAi/internal/errors"
+)
+
+// scheduledJobRepository 定时任务数据访问层实现
+type scheduledJobRepository struct {
+	db *database.DB
+}
+
+// NewScheduledJobRepository 创建定时任务数据访问层
+func NewScheduledJobRepository(db *database.DB) ScheduledJobRepository {
+	return &scheduledJobRepository{
+		db: db,
+	}
+}
+
+// Create 创建任务
+func (r *scheduledJobRepository) Create(ctx context.Context, params scheduled_jobs.CreateScheduledJobParams) (*scheduled_jobs.ScheduledJob, error) {
+	job, err := r.db.ScheduledJobs.CreateScheduledJob(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduled job: %w", err)
+	}
+	return &job, nil
+}
+
+// Update 更新任务
+func (r *scheduledJobRepository) Update(ctx context.Context, params scheduled_jobs.UpdateScheduledJobParams) (*scheduled_jobs.ScheduledJob, error) {
+	job, err := r.db.ScheduledJobs.UpdateScheduledJob(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Scheduled job")
+		}
+		return nil, fmt.Errorf("failed to update scheduled job: %w", err)
+	}
+	return &job, nil
+}
+
+// Delete 删除任务
+func (r *scheduledJobRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.ScheduledJobs.DeleteScheduledJob(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete scheduled job: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Get 获取任务
+func (r *scheduledJobRepository) Get(ctx context.Context, id int64) (*scheduled_jobs.ScheduledJob, error) {
+	job, err := r.db.ScheduledJobs.GetScheduledJob(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Scheduled job")
+		}
+		return nil, fmt.Errorf("failed to get scheduled job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetByName 按名称获取任务
+func (r *scheduledJobRepository) GetByName(ctx context.Context, name string) (*scheduled_jobs.ScheduledJob, error) {
+	job, err := r.db.ScheduledJobs.GetScheduledJobByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Scheduled job")
+		}
+		return nil, fmt.Errorf("failed to get scheduled job: %w", err)
+	}
+	return &job, nil
+}
+
+// List 获取所有任务
+func (r *scheduledJobRepository) List(ctx context.Context) ([]scheduled_jobs.ScheduledJob, error) {
+	items, err := r.db.ScheduledJobs.ListScheduledJobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled jobs: %w", err)
+	}
+	return items, nil
+}
+
+// ListDue 获取 now 之前到期的启用任务
+func (r *scheduledJobRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]scheduled_jobs.ScheduledJob, error) {
+	items, err := r.db.ScheduledJobs.ListDueScheduledJobs(ctx, scheduled_jobs.ListDueScheduledJobsParams{
+		NextRunAt: now,
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled jobs: %w", err)
+	}
+	return items, nil
+}
+
+// Claim 推进到期任务的下一次执行时间
+func (r *scheduledJobRepository) Claim(ctx context.Context, id int64, now, next time.Time) (bool, error) {
+	rows, err := r.db.ScheduledJobs.ClaimScheduledJob(ctx, scheduled_jobs.ClaimScheduledJobParams{
+		ID:          id,
+		NextRunAt:   next,
+		NextRunAt_2: now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim scheduled job: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RecordRun 保存一次执行的结果
+func (r *scheduledJobRepository) RecordRun(ctx context.Context, params scheduled_jobs.RecordScheduledJobRunParams) error {
+	if err := r.db.ScheduledJobs.RecordScheduledJobRun(ctx, params); err != nil {
+		return fmt.Errorf("failed to record scheduled job run: %w", err)
+	}
+	return nil
+}
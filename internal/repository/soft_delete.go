@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"go-springAi/internal/errors"
+)
+
+// 软删除约定：表中 deleted_at 非空表示记录已删除。默认查询只返回未删除的记录，
+// 删除语句只更新 deleted_at，恢复语句将其清空，需要永久删除时单独提供 Purge 方法。
+// 新增支持软删除的仓库（如会话）应沿用这些辅助函数和 SoftDeleteOptions。
+
+// SoftDeleteOptions 软删除记录的查询选项，零值时已删除的记录不出现在结果中
+type SoftDeleteOptions struct {
+	IncludeDeleted bool `json:"include_deleted"` // 同时返回已软删除的记录，用于恢复和审计
+}
+
+// deletedAt 将 deleted_at 列转换为响应字段，未删除时返回 nil
+func deletedAt(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// softDeleteResult 检查软删除语句的结果，没有命中未删除的记录时返回 notFound
+func softDeleteResult(rows int64, err error, operation string, notFound *errors.AppError) error {
+	if err != nil {
+		return errors.NewDatabaseError(operation, err)
+	}
+	if rows == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// restoreResult 检查恢复语句的错误，记录不存在或未被删除时返回 notFound
+func restoreResult(err error, operation string, notFound *errors.AppError) error {
+	if err == sql.ErrNoRows {
+		return notFound
+	}
+	if err != nil {
+		return errors.NewDatabaseError(operation, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T) *database.DB {
+	ctx := context.Background()
+	db, err := database.NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), database.Options{SQLite: database.SQLiteOptions{ForeignKeys: true}})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	return db
+}
+
+func TestUserSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	alice, err := repo.Create(ctx, dto.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, dto.CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(ctx, alice.ID))
+	appErr, ok := errors.IsAppError(repo.Delete(ctx, alice.ID))
+	require.True(t, ok)
+	assert.Equal(t, errors.NewUserNotFoundError().Code, appErr.Code, "deleting twice must report not found")
+
+	tests := []struct {
+		name   string
+		filter *UserFilter
+		want   []string
+	}{
+		{name: "Default", filter: &UserFilter{SortBy: UserSortByUsername, SortOrder: "asc"}, want: []string{"bob"}},
+		{name: "Include deleted", filter: &UserFilter{SortBy: UserSortByUsername, SortOrder: "asc", SoftDeleteOptions: SoftDeleteOptions{IncludeDeleted: true}}, want: []string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.List(ctx, NewPaginationParams(1, 10), tt.filter)
+			require.NoError(t, err)
+			var names []string
+			for _, user := range list {
+				names = append(names, user.Username)
+				assert.Equal(t, user.Username == "alice", user.DeletedAt != nil)
+			}
+			assert.Equal(t, tt.want, names)
+
+			count, err := repo.Count(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+
+	_, err = repo.GetByID(ctx, alice.ID)
+	require.Error(t, err, "deleted users must not be returned by default")
+
+	restored, err := repo.Restore(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+
+	// 恢复后重新出现在默认查询中
+	_, err = repo.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	list, err := repo.List(ctx, NewPaginationParams(1, 10), &UserFilter{SortBy: UserSortByUsername, SortOrder: "asc"})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "alice", list[0].Username)
+}
+
+func TestAPIKeySoftDelete(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	user, err := NewUserRepository(db).Create(ctx, dto.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	repo := NewAPIKeyRepository(db)
+
+	create := func(hash string) {
+		_, err := repo.CreateAPIKey(ctx, CreateAPIKeyParams{UserID: user.ID, ProviderType: "openai", EncryptedKey: "enc-" + hash, KeyHash: hash, IsActive: true})
+		require.NoError(t, err)
+	}
+	create("v1")
+
+	require.NoError(t, repo.DeleteAPIKey(ctx, user.ID, 0, "openai"))
+	_, err = repo.GetAPIKey(ctx, user.ID, 0, "openai")
+	assert.Error(t, err)
+	exists, err := repo.CheckAPIKeyExists(ctx, user.ID, 0, "openai")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	keys, err := repo.ListAPIKeysByUser(ctx, user.ID, SoftDeleteOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	keys, err = repo.ListAPIKeysByUser(ctx, user.ID, SoftDeleteOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, keys[0].DeletedAt.Valid)
+
+	restored, err := repo.RestoreAPIKey(ctx, user.ID, 0, "openai")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", restored.KeyHash)
+	assert.False(t, restored.DeletedAt.Valid)
+	_, err = repo.RestoreAPIKey(ctx, user.ID, 0, "openai")
+	assert.Error(t, err, "restoring a key that is not deleted must fail")
+
+	// 重新创建同一密钥时永久删除软删除的旧记录
+	require.NoError(t, repo.DeleteAPIKey(ctx, user.ID, 0, "openai"))
+	create("v2")
+	keys, err = repo.ListAPIKeysByUser(ctx, user.ID, SoftDeleteOptions{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "v2", keys[0].KeyHash)
+}
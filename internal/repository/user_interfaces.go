@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go-springAi/internal/dto"
 )
@@ -33,13 +34,58 @@ func NewPaginationParams(page, limit int64) *PaginationParams {
 	}
 }
 
+// 用户列表排序字段
+const (
+	UserSortByID        = "id"
+	UserSortByUsername  = "username"
+	UserSortByEmail     = "email"
+	UserSortByCreatedAt = "created_at"
+	UserSortByUpdatedAt = "updated_at"
+)
+
+// UserFilter 用户列表过滤和排序条件，零值字段不参与过滤
+type UserFilter struct {
+	Search        string     `json:"search"`         // 用户名或邮箱子串
+	IsActive      *bool      `json:"is_active"`      // 激活状态
+	CreatedFrom   *time.Time `json:"created_from"`   // 创建时间下限（含）
+	CreatedBefore *time.Time `json:"created_before"` // 创建时间上限（不含）
+	SortBy        string     `json:"sort_by"`        // 默认 created_at
+	SortOrder     string     `json:"sort_order"`     // asc 或 desc，默认 desc
+	SoftDeleteOptions
+}
+
+// UserSortKey 用户在排序中的位置，用于游标分页
+type UserSortKey struct {
+	ID   int64      `json:"id"`
+	Text string     `json:"text,omitempty"` // 按 username 或 email 排序时的值
+	Time *time.Time `json:"time,omitempty"` // 按 created_at 或 updated_at 排序时的值
+}
+
+// UserSortKeyOf 返回用户在 sortBy 排序中的位置
+func UserSortKeyOf(user *dto.UserResponse, sortBy string) UserSortKey {
+	key := UserSortKey{ID: user.ID}
+	switch sortBy {
+	case UserSortByUsername:
+		key.Text = user.Username
+	case UserSortByEmail:
+		key.Text = user.Email
+	case UserSortByCreatedAt:
+		key.Time = &user.CreatedAt
+	case UserSortByUpdatedAt:
+		key.Time = &user.UpdatedAt
+	}
+	return key
+}
+
 // UserReader 用户读取接口
 type UserReader interface {
 	// 基础查询方法
 	GetByID(ctx context.Context, id int64) (*dto.UserResponse, error)
 	GetByUsername(ctx context.Context, username string) (*dto.UserResponse, error)
 	GetByEmail(ctx context.Context, email string) (*dto.UserResponse, error)
-	List(ctx context.Context, params *PaginationParams) ([]*dto.UserResponse, error)
+	List(ctx context.Context, params *PaginationParams, filter *UserFilter) ([]*dto.UserResponse, error)
+	ListAfter(ctx context.Context, filter *UserFilter, after *UserSortKey, limit int64) ([]*dto.UserResponse, error)
+	Count(ctx context.Context, filter *UserFilter) (int64, error)
 }
 
 // UserWriter 用户写入接口
@@ -48,6 +94,8 @@ type UserWriter interface {
 	Create(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error)
 	Update(ctx context.Context, id int64, req dto.UpdateUserRequest) (*dto.UserResponse, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (*dto.UserResponse, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 // UserValidator 用户验证接口
@@ -55,6 +103,7 @@ type UserValidator interface {
 	// 业务验证方法
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	VerifyPassword(ctx context.Context, username, password string) (*dto.UserResponse, error)
 }
 
 // UserRepository 用户数据访问接口（组合所有功能接口）
@@ -68,6 +117,26 @@ type UserRepository interface {
 type RepositoryManager interface {
 	User() UserRepository
 	APIKey() APIKeyRepository
+	RefreshToken() RefreshTokenRepository
+	APIToken() APITokenRepository
+	AIUsage() AIUsageRepository
+	UserPreference() UserPreferenceRepository
+	UserPermission() UserPermissionRepository
+	Project() ProjectRepository
+	Webhook() WebhookRepository
+	ScheduledJob() ScheduledJobRepository
+	NotificationChannel() NotificationChannelRepository
+	ModelMetadata() ModelMetadataRepository
+	AISpend() AISpendRepository
+	FineTuningJob() FineTuningJobRepository
+	Eval() EvalRepository
+	Experiment() ExperimentRepository
+	Feedback() FeedbackRepository
+	Persona() PersonaRepository
+	Memory() MemoryRepository
+	// WithTx 在同一事务中执行 fn，fn 中通过 tx 获取的数据访问层共享该事务，
+	// fn 返回错误时回滚，否则提交
+	WithTx(ctx context.Context, fn func(tx RepositoryManager) error) error
 	Close() error
 	Ping(ctx context.Context) error
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"go-springAi/internal/database/generated/user_permissions"
+)
+
+// UserPermissionRepository 用户权限数据访问层接口
+type UserPermissionRepository interface {
+	// Grant 授予用户权限，已拥有时忽略
+	Grant(ctx context.Context, userID int64, permission string, grantedBy int64) error
+
+	// Has 判断用户是否拥有指定权限
+	Has(ctx context.Context, userID int64, permission string) (bool, error)
+
+	// List 获取用户的所有权限
+	List(ctx context.Context, userID int64) ([]user_permissions.UserPermission, error)
+
+	// Revoke 撤销用户权限，返回是否确实撤销了
+	Revoke(ctx context.Context, userID int64, permission string) (bool, error)
+}
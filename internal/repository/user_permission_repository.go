@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/user_permissions"
+)
+
+// userPermissionRepository 用户权限数据访问层实现
+type userPermissionRepository struct {
+	db *database.DB
+}
+
+// NewUserPermissionRepository 创建用户权限数据访问层
+func NewUserPermissionRepository(db *database.DB) UserPermissionRepository {
+	return &userPermissionRepository{
+		db: db,
+	}
+}
+
+// Grant 授予用户权限，已拥有时忽略
+func (r *userPermissionRepository) Grant(ctx context.Context, userID int64, permission string, grantedBy int64) error {
+	err := r.db.UserPermissions.GrantUserPermission(ctx, user_permissions.GrantUserPermissionParams{
+		UserID:     userID,
+		Permission: permission,
+		GrantedBy:  sql.NullInt64{Int64: grantedBy, Valid: grantedBy > 0},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to grant user permission: %w", err)
+	}
+	return nil
+}
+
+// Has 判断用户是否拥有指定权限
+func (r *userPermissionRepository) Has(ctx context.Context, userID int64, permission string) (bool, error) {
+	count, err := r.db.UserPermissions.HasUserPermission(ctx, user_permissions.HasUserPermissionParams{
+		UserID:     userID,
+		Permission: permission,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check user permission: %w", err)
+	}
+	return count > 0, nil
+}
+
+// List 获取用户的所有权限
+func (r *userPermissionRepository) List(ctx context.Context, userID int64) ([]user_permissions.UserPermission, error) {
+	permissions, err := r.db.UserPermissions.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+// Revoke 撤销用户权限，返回是否确实撤销了
+func (r *userPermissionRepository) Revoke(ctx context.Context, userID int64, permission string) (bool, error) {
+	rows, err := r.db.UserPermissions.RevokeUserPermission(ctx, user_permissions.RevokeUserPermissionParams{
+		UserID:     userID,
+		Permission: permission,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke user permission: %w", err)
+	}
+	return rows > 0, nil
+}
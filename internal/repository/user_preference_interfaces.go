@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-springAi/internal/database/generated/user_preferences"
+)
+
+// UserPreferenceRepository 用户偏好设置数据访问层接口
+type UserPreferenceRepository interface {
+	// Get 获取用户偏好设置，未设置时返回 NotFound 错误
+	Get(ctx context.Context, userID int64) (*user_preferences.UserPreference, error)
+
+	// Upsert 保存用户偏好设置
+	Upsert(ctx context.Context, params UpsertUserPreferencesParams) (*user_preferences.UserPreference, error)
+}
+
+// UpsertUserPreferencesParams 保存用户偏好设置参数
+type UpsertUserPreferencesParams struct {
+	UserID          int64           `json:"user_id"`
+	DefaultProvider string          `json:"default_provider"`
+	DefaultModel    string          `json:"default_model"`
+	Temperature     sql.NullFloat64 `json:"temperature"`
+	Locale          string          `json:"locale"`
+	DefaultPeriod   string          `json:"default_period"`
+}
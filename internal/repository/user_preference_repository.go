@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/user_preferences"
+	"go-springAi/internal/errors"
+)
+
+// userPreferenceRepository 用户偏好设置数据访问层实现
+type userPreferenceRepository struct {
+	db *database.DB
+}
+
+// NewUserPreferenceRepository 创建用户偏好设置数据访问层
+func NewUserPreferenceRepository(db *database.DB) UserPreferenceRepository {
+	return &userPreferenceRepository{
+		db: db,
+	}
+}
+
+// Get 获取用户偏好设置
+func (r *userPreferenceRepository) Get(ctx context.Context, userID int64) (*user_preferences.UserPreference, error) {
+	prefs, err := r.db.UserPreferences.GetUserPreferences(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("User preferences")
+		}
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// Upsert 保存用户偏好设置
+func (r *userPreferenceRepository) Upsert(ctx context.Context, params UpsertUserPreferencesParams) (*user_preferences.UserPreference, error) {
+	prefs, err := r.db.UserPreferences.UpsertUserPreferences(ctx, user_preferences.UpsertUserPreferencesParams{
+		UserID:          params.UserID,
+		DefaultProvider: params.DefaultProvider,
+		DefaultModel:    params.DefaultModel,
+		Temperature:     params.Temperature,
+		Locale:          params.Locale,
+		DefaultPeriod:   params.DefaultPeriod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user preferences: %w", err)
+	}
+	return &prefs, nil
+}
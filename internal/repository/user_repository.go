@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"strings"
+	"time"
 
 	"go-springAi/internal/database"
 	"go-springAi/internal/database/generated/users"
@@ -104,11 +105,24 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*dto.Use
 	return r.toUserResponse(user), nil
 }
 
-// List 获取用户列表
-func (r *userRepository) List(ctx context.Context, params *PaginationParams) ([]*dto.UserResponse, error) {
+// List 获取用户列表，filter 为空时按创建时间倒序返回全部用户
+func (r *userRepository) List(ctx context.Context, params *PaginationParams, filter *UserFilter) ([]*dto.UserResponse, error) {
+	if filter == nil {
+		filter = &UserFilter{}
+	}
+	where := toUserFilterParams(filter)
+
+	sortBy, sortOrder := NormalizeUserSort(filter.SortBy, filter.SortOrder)
 	userList, err := r.db.Users.ListUsers(ctx, users.ListUsersParams{
-		Limit:  params.Limit,
-		Offset: params.Offset,
+		Search:         where.Search,
+		IsActive:       where.IsActive,
+		CreatedFrom:    where.CreatedFrom,
+		CreatedBefore:  where.CreatedBefore,
+		IncludeDeleted: where.IncludeDeleted,
+		SortBy:         sortBy,
+		SortOrder:      sortOrder,
+		Limit:          params.Limit,
+		Offset:         params.Offset,
 	})
 	if err != nil {
 		return nil, errors.NewDatabaseError("Failed to list users", err)
@@ -122,6 +136,57 @@ func (r *userRepository) List(ctx context.Context, params *PaginationParams) ([]
 	return responses, nil
 }
 
+// ListAfter 按 filter 的排序返回排在 after 之后的用户，after 为空时从第一条开始；排序值相同的用户按ID同方向排序
+func (r *userRepository) ListAfter(ctx context.Context, filter *UserFilter, after *UserSortKey, limit int64) ([]*dto.UserResponse, error) {
+	if filter == nil {
+		filter = &UserFilter{}
+	}
+	where := toUserFilterParams(filter)
+
+	sortBy, sortOrder := NormalizeUserSort(filter.SortBy, filter.SortOrder)
+	params := users.ListUsersAfterParams{
+		Search:         where.Search,
+		IsActive:       where.IsActive,
+		CreatedFrom:    where.CreatedFrom,
+		CreatedBefore:  where.CreatedBefore,
+		IncludeDeleted: where.IncludeDeleted,
+		SortBy:         sortBy,
+		SortOrder:      sortOrder,
+		Limit:          limit,
+	}
+	if after != nil {
+		params.AfterID = sql.NullInt64{Int64: after.ID, Valid: true}
+		params.AfterText = sql.NullString{String: after.Text, Valid: true}
+		if after.Time != nil {
+			params.AfterTime = sql.NullTime{Time: after.Time.UTC(), Valid: true}
+		}
+	}
+
+	userList, err := r.db.Users.ListUsersAfter(ctx, params)
+	if err != nil {
+		return nil, errors.NewDatabaseError("Failed to list users", err)
+	}
+
+	responses := make([]*dto.UserResponse, 0, len(userList))
+	for _, user := range userList {
+		responses = append(responses, r.toUserResponse(user))
+	}
+	return responses, nil
+}
+
+// Count 统计符合条件的用户数量
+func (r *userRepository) Count(ctx context.Context, filter *UserFilter) (int64, error) {
+	if filter == nil {
+		filter = &UserFilter{}
+	}
+
+	count, err := r.db.Users.CountFilteredUsers(ctx, toUserFilterParams(filter))
+	if err != nil {
+		return 0, errors.NewDatabaseError("Failed to count users", err)
+	}
+	return count, nil
+}
+
 // Update 更新用户
 func (r *userRepository) Update(ctx context.Context, id int64, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
 	// 先获取当前用户信息
@@ -181,24 +246,29 @@ func (r *userRepository) Update(ctx context.Context, id int64, req dto.UpdateUse
 	return r.toUserResponse(user), nil
 }
 
-// Delete 删除用户
+// Delete 软删除用户，已删除的用户不会出现在查询结果中
 func (r *userRepository) Delete(ctx context.Context, id int64) error {
-	// 首先检查用户是否存在
-	_, err := r.db.Users.GetUser(ctx, id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return errors.NewUserNotFoundError()
-		}
-		return errors.NewDatabaseError("Failed to check user existence", err)
+	rows, err := r.db.Users.SoftDeleteUser(ctx, id)
+	return softDeleteResult(rows, err, "Failed to delete user", errors.NewUserNotFoundError())
+}
+
+// Restore 恢复已软删除的用户
+func (r *userRepository) Restore(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	user, err := r.db.Users.RestoreUser(ctx, id)
+	if err := restoreResult(err, "Failed to restore user", errors.NewUserNotFoundError()); err != nil {
+		return nil, err
 	}
 
-	// 删除用户
-	err = r.db.Users.DeleteUser(ctx, id)
+	return r.toUserResponse(user), nil
+}
+
+// PurgeDeleted 永久删除在指定时间前软删除的用户，返回删除数量
+func (r *userRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	rows, err := r.db.Users.PurgeDeletedUsers(ctx, sql.NullTime{Time: before, Valid: true})
 	if err != nil {
-		return errors.NewDatabaseError("Failed to delete user", err)
+		return 0, errors.NewDatabaseError("Failed to purge deleted users", err)
 	}
-
-	return nil
+	return rows, nil
 }
 
 // ExistsByUsername 检查用户名是否存在
@@ -219,6 +289,23 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 	return count > 0, nil
 }
 
+// VerifyPassword 校验用户名和密码，用户不存在或密码错误时返回登录失败错误
+func (r *userRepository) VerifyPassword(ctx context.Context, username, password string) (*dto.UserResponse, error) {
+	user, err := r.db.Users.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewLoginFailedError()
+		}
+		return nil, errors.NewDatabaseError("Failed to get user by username", err)
+	}
+
+	if !utils.CheckPassword(user.PasswordHash, password) {
+		return nil, errors.NewLoginFailedError()
+	}
+
+	return r.toUserResponse(user), nil
+}
+
 // toUserResponse 将数据库用户模型转换为响应模型
 func (r *userRepository) toUserResponse(user users.User) *dto.UserResponse {
 	var fullName *string
@@ -247,9 +334,48 @@ func (r *userRepository) toUserResponse(user users.User) *dto.UserResponse {
 		Email:     user.Email,
 		FullName:  fullName,
 		IsActive:  user.IsActive.Bool,
+		IsAdmin:   user.IsAdmin.Bool,
 		CreatedAt: user.CreatedAt.Time,
 		UpdatedAt: user.UpdatedAt.Time,
+		DeletedAt: deletedAt(user.DeletedAt),
+	}
+}
+
+// toUserFilterParams 将过滤条件转换为查询参数
+func toUserFilterParams(filter *UserFilter) users.CountFilteredUsersParams {
+	params := users.CountFilteredUsersParams{IncludeDeleted: filter.IncludeDeleted}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		params.Search = sql.NullString{String: escapeLike(search), Valid: true}
+	}
+	if filter.IsActive != nil {
+		params.IsActive = sql.NullBool{Bool: *filter.IsActive, Valid: true}
+	}
+	if filter.CreatedFrom != nil {
+		params.CreatedFrom = sql.NullTime{Time: filter.CreatedFrom.UTC(), Valid: true}
 	}
+	if filter.CreatedBefore != nil {
+		params.CreatedBefore = sql.NullTime{Time: filter.CreatedBefore.UTC(), Valid: true}
+	}
+	return params
+}
+
+// NormalizeUserSort 校验排序字段和方向，非法值回退为创建时间倒序
+func NormalizeUserSort(sortBy, sortOrder string) (string, string) {
+	switch sortBy {
+	case UserSortByID, UserSortByUsername, UserSortByEmail, UserSortByCreatedAt, UserSortByUpdatedAt:
+	default:
+		sortBy = UserSortByCreatedAt
+	}
+	sortOrder = strings.ToLower(sortOrder)
+	if sortOrder != "asc" {
+		sortOrder = "desc"
+	}
+	return sortBy, sortOrder
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
 
 // splitFullName 将全名分割为名和姓
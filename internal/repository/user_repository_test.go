@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserListAfter(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(newTestDB(t))
+
+	create := func(name string) {
+		_, err := repo.Create(ctx, dto.CreateUserRequest{Username: name, Email: name + "@example.com", Password: "password123"})
+		require.NoError(t, err)
+	}
+	for _, name := range []string{"carol", "alice", "erin", "bob", "dave"} {
+		create(name)
+	}
+
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		want      []string
+	}{
+		{name: "Username ascending", sortBy: UserSortByUsername, sortOrder: "asc", want: []string{"alice", "bob", "carol", "dave", "erin"}},
+		{name: "Email descending", sortBy: UserSortByEmail, sortOrder: "desc", want: []string{"erin", "dave", "carol", "bob", "alice"}},
+		// 同一秒内创建的用户按ID排序
+		{name: "Created at descending", sortBy: UserSortByCreatedAt, sortOrder: "desc", want: []string{"dave", "bob", "erin", "alice", "carol"}},
+		{name: "ID ascending", sortBy: UserSortByID, sortOrder: "asc", want: []string{"carol", "alice", "erin", "bob", "dave"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &UserFilter{SortBy: tt.sortBy, SortOrder: tt.sortOrder}
+			var names []string
+			var after *UserSortKey
+			for page := 0; page < 5; page++ {
+				users, err := repo.ListAfter(ctx, filter, after, 2)
+				require.NoError(t, err)
+				if len(users) == 0 {
+					break
+				}
+				for _, user := range users {
+					names = append(names, user.Username)
+				}
+				key := UserSortKeyOf(users[len(users)-1], tt.sortBy)
+				after = &key
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+
+	t.Run("Rows inserted mid-iteration", func(t *testing.T) {
+		filter := &UserFilter{SortBy: UserSortByUsername, SortOrder: "asc"}
+		first, err := repo.ListAfter(ctx, filter, nil, 2)
+		require.NoError(t, err)
+		create("aaron")
+
+		key := UserSortKeyOf(first[len(first)-1], UserSortByUsername)
+		next, err := repo.ListAfter(ctx, filter, &key, 2)
+		require.NoError(t, err)
+		require.Len(t, next, 2)
+		assert.Equal(t, []string{"carol", "dave"}, []string{next[0].Username, next[1].Username})
+	})
+}
+
+func TestUserListFilters(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+
+	ids := make(map[string]int64)
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		user, err := repo.Create(ctx, dto.CreateUserRequest{Username: name, Email: name + "@example.com", Password: "password123"})
+		require.NoError(t, err)
+		ids[name] = user.ID
+	}
+	inactive := false
+	_, err := repo.Update(ctx, ids["bob"], dto.UpdateUserRequest{IsActive: &inactive})
+	require.NoError(t, err)
+	_, err = db.GetConnection().ExecContext(ctx, "UPDATE users SET created_at = datetime('now', '-30 days') WHERE id IN (?, ?)", ids["alice"], ids["bob"])
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, ids["dave"]))
+
+	active := true
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	tests := []struct {
+		name   string
+		filter UserFilter
+		want   []string
+	}{
+		{name: "No filter", want: []string{"alice", "bob", "carol"}},
+		{name: "Search username", filter: UserFilter{Search: "aro"}, want: []string{"carol"}},
+		{name: "Search email", filter: UserFilter{Search: "bob@example"}, want: []string{"bob"}},
+		// 通配符按字面匹配
+		{name: "Search wildcard", filter: UserFilter{Search: "%"}, want: nil},
+		{name: "Active", filter: UserFilter{IsActive: &active}, want: []string{"alice", "carol"}},
+		{name: "Inactive", filter: UserFilter{IsActive: &inactive}, want: []string{"bob"}},
+		{name: "Created from", filter: UserFilter{CreatedFrom: &lastWeek}, want: []string{"carol"}},
+		{name: "Created before", filter: UserFilter{CreatedBefore: &lastWeek}, want: []string{"alice", "bob"}},
+		{name: "Combined", filter: UserFilter{Search: "example.com", IsActive: &active, CreatedBefore: &lastWeek}, want: []string{"alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.SortBy, filter.SortOrder = UserSortByUsername, "asc"
+			list, err := repo.List(ctx, NewPaginationParams(1, 10), &filter)
+			require.NoError(t, err)
+			var names []string
+			for _, user := range list {
+				names = append(names, user.Username)
+			}
+			assert.Equal(t, tt.want, names)
+
+			count, err := repo.Count(ctx, &filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-springAi/internal/database/generated/webhooks"
+)
+
+// WebhookRepository Webhook 端点和投递记录数据访问层接口
+type WebhookRepository interface {
+	// CreateEndpoint 创建端点
+	CreateEndpoint(ctx context.Context, params webhooks.CreateWebhookEndpointParams) (*webhooks.WebhookEndpoint, error)
+
+	// UpdateEndpoint 更新端点
+	UpdateEndpoint(ctx context.Context, params webhooks.UpdateWebhookEndpointParams) (*webhooks.WebhookEndpoint, error)
+
+	// DeleteEndpoint 删除端点及其投递记录，返回是否确实删除了
+	DeleteEndpoint(ctx context.Context, id int64) (bool, error)
+
+	// GetEndpoint 获取端点
+	GetEndpoint(ctx context.Context, id int64) (*webhooks.WebhookEndpoint, error)
+
+	// ListEndpoints 获取所有端点
+	ListEndpoints(ctx context.Context) ([]webhooks.WebhookEndpoint, error)
+
+	// ListEnabledEndpoints 获取所有启用的端点
+	ListEnabledEndpoints(ctx context.Context) ([]webhooks.WebhookEndpoint, error)
+
+	// CreateDelivery 创建待投递记录
+	CreateDelivery(ctx context.Context, params webhooks.CreateWebhookDeliveryParams) (*webhooks.WebhookDelivery, error)
+
+	// GetDelivery 获取投递记录
+	GetDelivery(ctx context.Context, id int64) (*webhooks.WebhookDelivery, error)
+
+	// ListDeliveries 获取端点最近的投递记录，按时间倒序
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]webhooks.WebhookDelivery, error)
+
+	// ListDueDeliveries 获取 now 之前到期的待投递记录
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhooks.WebhookDelivery, error)
+
+	// UpdateDeliveryAttempt 保存一次投递尝试的结果
+	UpdateDeliveryAttempt(ctx context.Context, params webhooks.UpdateWebhookDeliveryAttemptParams) error
+
+	// RequeueDelivery 将投递记录重新置为待投递，返回记录是否存在
+	RequeueDelivery(ctx context.Context, id int64, at time.Time) (bool, error)
+
+	// DeleteFinishedDeliveries 删除 before 之前已结束的投递记录，返回删除条数
+	DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/webhooks"
+	"go-springAi/internal/errors"
+)
+
+// webhookRepository Webhook 数据访问层实现
+type webhookRepository struct {
+	db *database.DB
+}
+
+// NewWebhookRepository 创建 Webhook 数据访问层
+func NewWebhookRepository(db *database.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// CreateEndpoint 创建端点
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, params webhooks.CreateWebhookEndpointParams) (*webhooks.WebhookEndpoint, error) {
+	endpoint, err := r.db.Webhooks.CreateWebhookEndpoint(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// UpdateEndpoint 更新端点
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, params webhooks.UpdateWebhookEndpointParams) (*webhooks.WebhookEndpoint, error) {
+	endpoint, err := r.db.Webhooks.UpdateWebhookEndpoint(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Webhook endpoint")
+		}
+		return nil, fmt.Errorf("failed to update webhook endpoint: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// DeleteEndpoint 删除端点及其投递记录
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id int64) (bool, error) {
+	rows, err := r.db.Webhooks.DeleteWebhookEndpoint(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook endpoint: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetEndpoint 获取端点
+func (r *webhookRepository) GetEndpoint(ctx context.Context, id int64) (*webhooks.WebhookEndpoint, error) {
+	endpoint, err := r.db.Webhooks.GetWebhookEndpoint(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Webhook endpoint")
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// ListEndpoints 获取所有端点
+func (r *webhookRepository) ListEndpoints(ctx context.Context) ([]webhooks.WebhookEndpoint, error) {
+	items, err := r.db.Webhooks.ListWebhookEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return items, nil
+}
+
+// ListEnabledEndpoints 获取所有启用的端点
+func (r *webhookRepository) ListEnabledEndpoints(ctx context.Context) ([]webhooks.WebhookEndpoint, error) {
+	items, err := r.db.Webhooks.ListEnabledWebhookEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enabled webhook endpoints: %w", err)
+	}
+	return items, nil
+}
+
+// CreateDelivery 创建待投递记录
+func (r *webhookRepository) CreateDelivery(ctx context.Context, params webhooks.CreateWebhookDeliveryParams) (*webhooks.WebhookDelivery, error) {
+	delivery, err := r.db.Webhooks.CreateWebhookDelivery(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// GetDelivery 获取投递记录
+func (r *webhookRepository) GetDelivery(ctx context.Context, id int64) (*webhooks.WebhookDelivery, error) {
+	delivery, err := r.db.Webhooks.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Webhook delivery")
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries 获取端点最近的投递记录
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]webhooks.WebhookDelivery, error) {
+	items, err := r.db.Webhooks.ListWebhookDeliveries(ctx, webhooks.ListWebhookDeliveriesParams{
+		EndpointID: endpointID,
+		Limit:      int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return items, nil
+}
+
+// ListDueDeliveries 获取 now 之前到期的待投递记录
+func (r *webhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhooks.WebhookDelivery, error) {
+	items, err := r.db.Webhooks.ListDueWebhookDeliveries(ctx, webhooks.ListDueWebhookDeliveriesParams{
+		NextAttemptAt: now,
+		Limit:         int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+	return items, nil
+}
+
+// UpdateDeliveryAttempt 保存一次投递尝试的结果
+func (r *webhookRepository) UpdateDeliveryAttempt(ctx context.Context, params webhooks.UpdateWebhookDeliveryAttemptParams) error {
+	if err := r.db.Webhooks.UpdateWebhookDeliveryAttempt(ctx, params); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// RequeueDelivery 将投递记录重新置为待投递
+func (r *webhookRepository) RequeueDelivery(ctx context.Context, id int64, at time.Time) (bool, error) {
+	rows, err := r.db.Webhooks.RequeueWebhookDelivery(ctx, webhooks.RequeueWebhookDeliveryParams{
+		ID:            id,
+		NextAttemptAt: at,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue webhook delivery: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteFinishedDeliveries 删除 before 之前已结束的投递记录
+func (r *webhookRepository) DeleteFinishedDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	rows, err := r.db.Webhooks.DeleteFinishedWebhookDeliveries(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished webhook deliveries: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,79 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Header 请求ID的HTTP头
+const Header = "X-Request-ID"
+
+// maxLength 接受客户端传入请求ID的最大长度
+const maxLength = 128
+
+// contextKey 请求ID在 context.Context 中的键类型
+type contextKey struct{}
+
+// WithContext 将请求ID写入上下文
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 从上下文读取请求ID，未设置时返回空字符串
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New 生成新的请求ID
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid 判断客户端传入的请求ID是否可用，只接受字母、数字和 -_.: 以免污染日志
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Transport 将上下文中的请求ID转发给下游HTTP服务
+type Transport struct {
+	Base http.RoundTripper // 为空时使用 http.DefaultTransport
+}
+
+// NewClient 创建转发请求ID的HTTP客户端
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &Transport{}}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	// RoundTripper 不能修改原请求
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return base.RoundTrip(req)
+}
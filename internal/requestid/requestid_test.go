@@ -0,0 +1,69 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "UUID", id: "3f2b8c1e-6f0a-4c55-9d3e-1b2a3c4d5e6f", want: true},
+		{name: "Trace style", id: "edge.req_42:1", want: true},
+		{name: "Empty", id: "", want: false},
+		{name: "Too long", id: strings.Repeat("a", maxLength+1), want: false},
+		{name: "Newline", id: "abc\nforged log line", want: false},
+		{name: "Space", id: "abc def", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Valid(tt.id))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctxID  string
+		header string
+		want   string
+	}{
+		{name: "Forwards context ID", ctxID: "req-1", want: "req-1"},
+		{name: "Keeps explicit header", ctxID: "req-1", header: "upstream", want: "upstream"},
+		{name: "No ID in context", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(Header)
+			}))
+			defer srv.Close()
+
+			ctx := context.Background()
+			if tt.ctxID != "" {
+				ctx = WithContext(ctx, tt.ctxID)
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			resp, err := NewClient(0).Do(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+)
+
+// MIMEMsgPack MessagePack 响应的媒体类型，请求也接受 application/x-msgpack
+const MIMEMsgPack = "application/msgpack"
+
+// formatKey 上下文中记录响应编码的键
+const formatKey = "response_format"
+
+// msgpackHandle 使用新规范（区分字符串和二进制），按键排序使相同数据编码结果一致，ETag 才稳定
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.Canonical = true
+	return h
+}()
+
+// UseMsgPack 标记本次请求的成功响应使用 MessagePack 编码，由内容协商中间件调用
+func UseMsgPack(c *gin.Context) {
+	c.Set(formatKey, MIMEMsgPack)
+}
+
+// render 按协商结果写出响应体，MessagePack 编码失败（即对象无法序列化为 JSON）时交给 JSON 渲染处理
+func render(c *gin.Context, code int, obj interface{}) {
+	if c.GetString(formatKey) == MIMEMsgPack {
+		if body, err := EncodeMsgPack(obj); err == nil {
+			c.Data(code, MIMEMsgPack, body)
+			return
+		}
+	}
+	c.JSON(code, obj)
+}
+
+// EncodeMsgPack 将对象编码为 MessagePack。对象先按 JSON 规则序列化，
+// 因此字段名、omitempty 和自定义 MarshalJSON 与 JSON 响应完全一致，时间为 RFC3339 字符串
+func EncodeMsgPack(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, msgpackHandle).Encode(msgpackValue(value)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// msgpackValue 将 JSON 数字转换为整数或浮点数，其余值保持不变
+func msgpackValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = msgpackValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = msgpackValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
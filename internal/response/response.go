@@ -3,20 +3,23 @@ package response
 import (
 	"net/http"
 
+	"go-springAi/internal/requestid"
+
 	"github.com/gin-gonic/gin"
 )
 
 // Response 统一响应结构
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty"` // 错误响应携带请求ID，便于与日志关联
 }
 
-// Success 成功响应
+// Success 成功响应，路由启用内容协商且客户端接受时使用 MessagePack 编码
 func Success(c *gin.Context, code int, message string, data interface{}) {
-	c.JSON(code, Response{
+	render(c, code, Response{
 		Code:    code,
 		Message: message,
 		Data:    data,
@@ -26,12 +29,21 @@ func Success(c *gin.Context, code int, message string, data interface{}) {
 // Error 错误响应
 func Error(c *gin.Context, code int, message string, err string) {
 	c.JSON(code, Response{
-		Code:    code,
-		Message: message,
-		Error:   err,
+		Code:      code,
+		Message:   message,
+		Error:     err,
+		RequestID: requestIDOf(c),
 	})
 }
 
+// requestIDOf 获取请求ID
+func requestIDOf(c *gin.Context) string {
+	if c.Request == nil {
+		return ""
+	}
+	return requestid.FromContext(c.Request.Context())
+}
+
 // BadRequest 400错误
 func BadRequest(c *gin.Context, message string, err string) {
 	Error(c, http.StatusBadRequest, message, err)
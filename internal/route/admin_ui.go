@@ -0,0 +1,17 @@
+package route
+
+import (
+	"net/http"
+
+	"go-springAi/internal/webui"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerAdminUIRoute 注册内嵌管理后台，/admin 会被重定向到 /admin/。
+// 页面本身不需要认证，所有数据都通过管理员会话调用 /api/v1 获取
+func registerAdminUIRoute(r *gin.Engine, handler http.Handler) {
+	serve := gin.WrapH(handler)
+	r.GET(webui.Prefix+"/*filepath", serve)
+	r.HEAD(webui.Prefix+"/*filepath", serve)
+}
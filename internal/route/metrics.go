@@ -0,0 +1,19 @@
+package route
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerMetricsRoute 注册Prometheus抓取端点，token 不为空时要求 Bearer 令牌
+func registerMetricsRoute(r *gin.Engine, handler http.Handler, token string) {
+	r.GET("/metrics", func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	})
+}
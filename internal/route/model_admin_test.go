@@ -0,0 +1,73 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// scopedAPITokens 个人访问令牌 gsa_ai 具备 ai 权限范围，其他令牌只有 stock 权限范围，都属于管理员用户 1
+type scopedAPITokens struct{}
+
+func (scopedAPITokens) Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error) {
+	scopes := []string{dto.APITokenScopeStock}
+	if token == dto.APITokenPrefix+"ai" {
+		scopes = []string{dto.APITokenScopeAI}
+	}
+	return &dto.APITokenPrincipal{TokenID: 1, UserID: 1, Username: "admin", Scopes: scopes}, nil
+}
+
+func TestModelAdminRoutesRequireAIScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+	userToken, err := jwtManager.GenerateToken(2, "alice")
+	require.NoError(t, err)
+
+	r := gin.New()
+	modelAdmin := modelAdminGroup(r.Group("/api/v1/ai"), jwtManager, scopedAPITokens{}, staticUsers{}, func(c *gin.Context) { c.Next() }, zap.NewNop())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	modelAdmin.PATCH("/:provider/models", ok)
+	modelAdmin.PUT("/:provider/models/:model/metadata", ok)
+	modelAdmin.DELETE("/:provider/models/:model/metadata", ok)
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPatch, "/api/v1/ai/openai/models"},
+		{http.MethodPut, "/api/v1/ai/openai/models/gpt-4o/metadata"},
+		{http.MethodDelete, "/api/v1/ai/openai/models/gpt-4o/metadata"},
+	}
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "Anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "Token without ai scope", token: dto.APITokenPrefix + "stock", wantStatus: http.StatusForbidden},
+		{name: "Non-admin", token: userToken, wantStatus: http.StatusForbidden},
+		{name: "Admin token with ai scope", token: dto.APITokenPrefix + "ai", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, request := range requests {
+				req := httptest.NewRequest(request.method, request.path, nil)
+				if tt.token != "" {
+					req.Header.Set("Authorization", "Bearer "+tt.token)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				assert.Equal(t, tt.wantStatus, w.Code, "%s %s", request.method, request.path)
+			}
+		})
+	}
+}
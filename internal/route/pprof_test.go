@@ -0,0 +1,72 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// staticUsers 用户 1 为管理员，用户 2 为普通用户
+type staticUsers struct{}
+
+func (staticUsers) GetByID(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	switch id {
+	case 1:
+		return &dto.UserResponse{ID: 1, Username: "admin", IsActive: true, IsAdmin: true}, nil
+	case 2:
+		return &dto.UserResponse{ID: 2, Username: "alice", IsActive: true}, nil
+	}
+	return nil, errors.NewUserNotFoundError()
+}
+
+func TestPprofRoutesRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtManager := utils.NewJWTManager("test-secret", 1)
+	adminToken, err := jwtManager.GenerateToken(1, "admin")
+	require.NoError(t, err)
+	userToken, err := jwtManager.GenerateToken(2, "alice")
+	require.NoError(t, err)
+
+	// 与 SetupRoutes 中管理员分组使用相同的中间件
+	r := gin.New()
+	admin := r.Group("/api/v1/admin", middleware.AuthMiddleware(jwtManager, nil, zap.NewNop()), middleware.RequireSessionAuth(), middleware.RequireAdmin(staticUsers{}, zap.NewNop()))
+	registerPprofRoutes(admin.Group("/debug/pprof"))
+
+	tests := []struct {
+		name         string
+		path         string
+		token        string
+		wantStatus   int
+		wantContains string
+	}{
+		{name: "Anonymous", path: "/api/v1/admin/debug/pprof/", wantStatus: http.StatusUnauthorized},
+		{name: "Non-admin", path: "/api/v1/admin/debug/pprof/heap", token: userToken, wantStatus: http.StatusForbidden},
+		{name: "Index", path: "/api/v1/admin/debug/pprof/", token: adminToken, wantStatus: http.StatusOK, wantContains: "goroutine"},
+		{name: "Named profile", path: "/api/v1/admin/debug/pprof/goroutine?debug=1", token: adminToken, wantStatus: http.StatusOK, wantContains: "goroutine profile"},
+		{name: "Cmdline", path: "/api/v1/admin/debug/pprof/cmdline", token: adminToken, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantContains)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package route
 
 import (
+	"net/http"
+	"time"
+
+	"go-springAi/internal/chaos"
 	"go-springAi/internal/controllers"
 	"go-springAi/internal/dto"
 
 	"go-springAi/internal/i18n"
 	"go-springAi/internal/middleware"
+	"go-springAi/internal/ratelimit"
 	"go-springAi/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -13,17 +18,28 @@ import (
 )
 
 // SetupRoutes 设置路由
-func SetupRoutes(logger *zap.Logger, jwtManager *utils.JWTManager, mcpController *controllers.MCPController, aiController *controllers.AIController, aiAssistantController *controllers.AIAssistantController, stockController *controllers.StockController, testI18nController *controllers.TestI18nController, i18nManager *i18n.Manager) *gin.Engine {
+func SetupRoutes(logger *zap.Logger, trustedProxies []string, accessLog middleware.AccessLogOptions, cors middleware.CORSOptions, compress *middleware.CompressOptions, bodyLimit middleware.BodyLimitOptions, timeout middleware.TimeoutOptions, chaosInjector *chaos.Injector, limiter *ratelimit.Limiter, idempotent middleware.IdempotencyOptions, jwtManager *utils.JWTManager, apiTokens middleware.APITokenAuthenticator, users middleware.UserLookup, permissions middleware.PermissionChecker, localePreferences middleware.LocalePreferenceLookup, reauthMaxAge time.Duration, authController *controllers.AuthController, apiTokenController *controllers.APITokenController, userPreferenceController *controllers.UserPreferenceController, notificationController *controllers.NotificationController, projectController *controllers.ProjectController, adminUserController *controllers.AdminUserController, executionLogArchiveController *controllers.ExecutionLogArchiveController, providerCaptureController *controllers.ProviderCaptureController, adminConfigController *controllers.AdminConfigController, webhookController *controllers.WebhookController, schedulerController *controllers.SchedulerController, fineTuningController *controllers.FineTuningController, evalController *controllers.EvalController, experimentController *controllers.ExperimentController, feedbackController *controllers.FeedbackController, personaController *controllers.PersonaController, memoryController *controllers.MemoryController, graphqlController *controllers.GraphQLController, mcpController *controllers.MCPController, aiController *controllers.AIController, aiAssistantController *controllers.AIAssistantController, chatCompletionController *controllers.ChatCompletionController, stockController *controllers.StockController, testI18nController *controllers.TestI18nController, i18nManager *i18n.Manager, metricsHandler http.Handler, metricsToken string, adminUI http.Handler) *gin.Engine {
 	// 创建Gin引擎
-	r := gin.New()
+	r := newEngine(trustedProxies, logger)
 
 	// 添加中间件
 	r.Use(middleware.RequestID())          // 请求ID中间件
-	r.Use(middleware.ZapLogger(logger))    // zap结构化日志中间件
+	r.Use(middleware.ZapLogger(logger, accessLog)) // zap结构化访问日志中间件
 	r.Use(middleware.ErrorHandler(logger)) // 错误处理中间件
 	r.Use(middleware.Recovery())           // 恢复中间件
-	r.Use(middleware.CORS())               // 跨域中间件
-	r.Use(middleware.I18nMiddleware(i18nManager)) // 国际化中间件
+	r.Use(middleware.CORS(cors))           // 跨域中间件，放在限流之前以便错误响应也带跨域头
+	if chaosInjector != nil {
+		r.Use(middleware.Chaos(chaosInjector, logger)) // 故障注入中间件，仅测试和压测环境启用
+	}
+	r.Use(middleware.RateLimit(limiter, logger)) // 全局和按IP限流
+	if compress != nil {
+		r.Use(middleware.Compress(*compress)) // 响应压缩中间件
+	}
+	r.Use(middleware.BodyLimit(bodyLimit)) // 请求体大小限制中间件
+	// SSE流和pprof采样自行控制时长，不设处理时限
+	timeout.Paths = append(timeout.Paths, middleware.PathTimeout{Prefix: "/api/v1/mcp/sse"}, middleware.PathTimeout{Prefix: "/api/v1/admin/debug/pprof"}, middleware.PathTimeout{Prefix: "/api/admin/debug/pprof"})
+	r.Use(middleware.Timeout(timeout)) // 请求处理时限中间件
+	r.Use(middleware.I18nMiddleware(i18nManager, localePreferences)) // 国际化中间件，认证后按用户偏好语言覆盖
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
@@ -33,77 +49,272 @@ func SetupRoutes(logger *zap.Logger, jwtManager *utils.JWTManager, mcpController
 		})
 	})
 
-	// API版本分组
-	v1 := r.Group("/api/v1")
+	// Prometheus指标，metricsHandler 为空表示未启用
+	if metricsHandler != nil {
+		registerMetricsRoute(r, metricsHandler, metricsToken)
+	}
+
+	// 内嵌管理后台，adminUI 为空表示未启用
+	if adminUI != nil {
+		registerAdminUIRoute(r, adminUI)
+	}
+
+	// 账号、令牌、项目和管理员端点，同时注册在 /api/v1 和旧的未带版本路径下
+	registerAccountRoutes := func(api *gin.RouterGroup) {
+		// 认证端点
+		authGroup := api.Group("/auth", middleware.RateLimitGroup(limiter, "auth", logger))
+		{
+			authGroup.POST("/login", authController.Login)
+			authGroup.POST("/refresh", authController.Refresh)
+			authGroup.POST("/logout", authController.Logout)
+			authGroup.POST("/reauth", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), authController.Reauthenticate)
+		}
+
+		// 个人访问令牌管理端点（仅限登录会话）
+		tokenGroup := api.Group("/tokens", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RateLimitGroup(limiter, "tokens", logger))
+		{
+			tokenGroup.POST("", middleware.Idempotency(idempotent, logger), apiTokenController.CreateToken)
+			tokenGroup.GET("", apiTokenController.ListTokens)
+			tokenGroup.DELETE("/:id", apiTokenController.RevokeToken)
+		}
+
+		// 用户偏好设置
+		preferenceGroup := api.Group("/preferences", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RateLimitGroup(limiter, "preferences", logger))
+		{
+			preferenceGroup.GET("", userPreferenceController.GetPreferences)
+			preferenceGroup.PUT("", userPreferenceController.UpdatePreferences)
+		}
+
+		// 用户通知渠道：邮件、Slack、Telegram
+		notificationGroup := api.Group("/notifications", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RateLimitGroup(limiter, "notifications", logger))
+		{
+			notificationGroup.GET("/channels", notificationController.ListChannels)
+			notificationGroup.PUT("/channels/:channel", notificationController.SaveChannel)
+			notificationGroup.DELETE("/channels/:channel", notificationController.DeleteChannel)
+			notificationGroup.POST("/channels/:channel/test", notificationController.TestChannel)
+		}
+
+		// 用户长期记忆：查看、删除从对话中提取的记忆，开启或关闭记忆
+		memoryGroup := api.Group("/memory", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RateLimitGroup(limiter, "memory", logger))
+		{
+			memoryGroup.GET("", memoryController.ListMemories)
+			memoryGroup.DELETE("", memoryController.DeleteMemories)
+			memoryGroup.DELETE("/:id", memoryController.DeleteMemory)
+			memoryGroup.GET("/settings", memoryController.GetSettings)
+			memoryGroup.PUT("/settings", memoryController.UpdateSettings)
+		}
+
+		// 项目：按应用或团队区分API密钥和用量
+		projectGroup := api.Group("/projects", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RateLimitGroup(limiter, "projects", logger))
+		{
+			projectGroup.GET("", projectController.ListProjects)
+			projectGroup.POST("", middleware.Idempotency(idempotent, logger), projectController.CreateProject)
+			projectGroup.DELETE("/:id", projectController.DeleteProject)
+			projectGroup.GET("/:id/usage", projectController.GetProjectUsage)
+		}
+
+		// 提示词评测：套件管理、在多个模型上运行并对比结果（仅限管理员）
+		evalGroup := api.Group("/evals", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger))
+		{
+			evalGroup.GET("/suites", evalController.ListSuites)
+			evalGroup.POST("/suites", middleware.Idempotency(idempotent, logger), evalController.CreateSuite)
+			evalGroup.GET("/suites/:id", evalController.GetSuite)
+			evalGroup.PUT("/suites/:id", evalController.UpdateSuite)
+			evalGroup.DELETE("/suites/:id", evalController.DeleteSuite)
+			evalGroup.GET("/suites/:id/runs", evalController.ListRuns)
+			evalGroup.POST("/suites/:id/runs", middleware.Idempotency(idempotent, logger), evalController.StartRun)
+			evalGroup.GET("/runs/compare", evalController.CompareRuns)
+			evalGroup.GET("/runs/:id", evalController.GetRun)
+		}
+
+		// A/B 实验：按角色在两个提示词或模型变体间分配流量并比较结果（仅限管理员）
+		experimentGroup := api.Group("/experiments", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger))
+		{
+			experimentGroup.GET("", experimentController.ListExperiments)
+			experimentGroup.POST("", middleware.Idempotency(idempotent, logger), experimentController.CreateExperiment)
+			experimentGroup.GET("/:id", experimentController.GetExperiment)
+			experimentGroup.PUT("/:id", experimentController.UpdateExperiment)
+			experimentGroup.DELETE("/:id", experimentController.DeleteExperiment)
+			experimentGroup.POST("/:id/start", experimentController.StartExperiment)
+			experimentGroup.POST("/:id/stop", experimentController.StopExperiment)
+			experimentGroup.GET("/:id/report", experimentController.GetReport)
+		}
+
+		// 回复评价管理
+		feedbackGroup := api.Group("/feedback", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger))
+		{
+			feedbackGroup.GET("", feedbackController.ListFeedback)
+			feedbackGroup.POST("/eval-cases", middleware.Idempotency(idempotent, logger), feedbackController.ExportToEval)
+		}
+
+		// 助手角色管理
+		personaGroup := api.Group("/personas", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger))
+		{
+			personaGroup.GET("", personaController.ListPersonas)
+			personaGroup.POST("", middleware.Idempotency(idempotent, logger), personaController.CreatePersona)
+			personaGroup.GET("/:id", personaController.GetPersona)
+			personaGroup.PUT("/:id", personaController.UpdatePersona)
+			personaGroup.DELETE("/:id", personaController.DeletePersona)
+		}
+
+		// 管理员端点
+		adminGroup := api.Group("/admin", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger))
+		{
+			adminGroup.GET("/users", adminUserController.ListUsers)
+			adminGroup.POST("/users", middleware.Idempotency(idempotent, logger), adminUserController.CreateUser)
+			adminGroup.DELETE("/users/:id", adminUserController.DeleteUser)
+			adminGroup.POST("/users/:id/restore", adminUserController.RestoreUser)
+			adminGroup.POST("/users/:id/impersonate", adminUserController.ImpersonateUser)
+			adminGroup.GET("/users/:id/permissions", adminUserController.ListPermissions)
+			adminGroup.POST("/users/:id/permissions", adminUserController.GrantPermission)
+			adminGroup.DELETE("/users/:id/permissions/:permission", adminUserController.RevokePermission)
+			adminGroup.GET("/ai/keys", aiController.GetKeyHealth)
+
+			// 已归档的MCP执行日志
+			adminGroup.GET("/mcp/logs/archives", executionLogArchiveController.ListArchives)
+			adminGroup.POST("/mcp/logs/archives", executionLogArchiveController.ArchiveNow)
+			adminGroup.GET("/mcp/logs/archived", middleware.WireFormat(), executionLogArchiveController.QueryArchivedLogs)
+
+			// AI提供商调用的调试捕获
+			adminGroup.GET("/providers/captures", providerCaptureController.ListCaptures)
+			adminGroup.GET("/providers/captures/:id", providerCaptureController.GetCapture)
+			adminGroup.DELETE("/providers/captures", providerCaptureController.ClearCaptures)
+
+			// 运行时配置导出导入
+			adminGroup.GET("/config/export", adminConfigController.ExportConfig)
+			adminGroup.POST("/config/import", adminConfigController.ImportConfig)
+
+			// 出站 Webhook 端点和投递记录
+			adminGroup.GET("/webhooks", webhookController.ListWebhooks)
+			adminGroup.POST("/webhooks", middleware.Idempotency(idempotent, logger), webhookController.CreateWebhook)
+			adminGroup.PUT("/webhooks/:id", webhookController.UpdateWebhook)
+			adminGroup.DELETE("/webhooks/:id", webhookController.DeleteWebhook)
+			adminGroup.GET("/webhooks/:id/deliveries", webhookController.ListDeliveries)
+			adminGroup.POST("/webhooks/:id/ping", webhookController.PingWebhook)
+			adminGroup.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookController.RedeliverWebhook)
+
+			// 定时任务
+			adminGroup.GET("/scheduler/jobs", schedulerController.ListJobs)
+			adminGroup.POST("/scheduler/jobs", middleware.Idempotency(idempotent, logger), schedulerController.CreateJob)
+			adminGroup.PUT("/scheduler/jobs/:id", schedulerController.UpdateJob)
+			adminGroup.DELETE("/scheduler/jobs/:id", schedulerController.DeleteJob)
+			adminGroup.POST("/scheduler/jobs/:id/pause", schedulerController.PauseJob)
+			adminGroup.POST("/scheduler/jobs/:id/resume", schedulerController.ResumeJob)
+			adminGroup.POST("/scheduler/jobs/:id/run", schedulerController.RunJob)
+
+			// OpenAI 微调：训练文件和任务，完成的模型以禁用状态添加，启用后可用
+			adminGroup.POST("/ai/fine-tuning/files", fineTuningController.UploadFile)
+			adminGroup.GET("/ai/fine-tuning/jobs", fineTuningController.ListJobs)
+			adminGroup.POST("/ai/fine-tuning/jobs", middleware.Idempotency(idempotent, logger), fineTuningController.CreateJob)
+			adminGroup.GET("/ai/fine-tuning/jobs/:id", fineTuningController.GetJob)
+			adminGroup.POST("/ai/fine-tuning/jobs/:id/cancel", fineTuningController.CancelJob)
+
+			// 性能分析：CPU、堆、goroutine 等 profile
+			registerPprofRoutes(adminGroup.Group("/debug/pprof"))
+		}
+	}
+
+	// 管理后台 GraphQL 查询，一次请求组合用户、模型、提供商和执行日志
+	r.POST("/graphql", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RateLimitGroup(limiter, "admin", logger), graphqlController.Query)
+
+	// API版本分组，不兼容的变更放在新版本分组（如 /api/v2）中发布
+	v1 := r.Group("/api/v1", middleware.APIVersion("v1"))
 	{
+		registerAccountRoutes(v1)
 
 		// MCP相关路由
-		mcp := v1.Group("/mcp")
+		mcp := v1.Group("/mcp", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeMCP), middleware.RateLimitGroup(limiter, "mcp", logger))
 		{
 			// MCP初始化端点
 			mcp.POST("/initialize", middleware.ValidateJSONFactory(&dto.MCPInitializeRequest{}), mcpController.Initialize)
 			
 			// MCP状态端点
 			mcp.GET("/status", mcpController.GetStatus)
+
+			// 协议自检端点，会执行工具，仅限管理员
+			mcp.POST("/selftest", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireAdmin(users, logger), mcpController.SelfTest)
 			
 			// 工具管理端点
-			mcp.GET("/tools", mcpController.ListTools)
-			mcp.POST("/execute", middleware.ValidateJSONFactory(&dto.MCPExecuteRequest{}), mcpController.ExecuteTool)
+			mcp.GET("/tools", middleware.ETag(), mcpController.ListTools)
+			mcp.POST("/execute", middleware.Idempotency(idempotent, logger), middleware.ValidateJSONFactory(&dto.MCPExecuteRequest{}), mcpController.ExecuteTool)
 			
 			// SSE流式端点
 			mcp.GET("/sse", mcpController.StreamSSE)
 			
 			// 执行日志端点
-			mcp.GET("/logs", mcpController.ListExecutionLogs)
-			mcp.GET("/logs/:id", mcpController.GetExecutionLog)
+			mcp.GET("/logs", middleware.WireFormat(), mcpController.ListExecutionLogs)
+			mcp.GET("/logs/:id", middleware.WireFormat(), mcpController.GetExecutionLog)
 		}
 
 
 
 		// 统一AI API端点
+		// 分组内按路由认证，限流放在认证之后以便按用户计算
+		aiLimit := middleware.RateLimitGroup(limiter, "ai", logger)
 		aiGroup := v1.Group("/ai")
 		{
 			// 模型管理端点
-			aiGroup.GET("/:provider/models", aiController.ListModels)
-			aiGroup.GET("/:provider/models/all", aiController.ListAllModels) // 新增：获取所有模型（包括禁用的）
-			aiGroup.GET("/:provider/config/:model", aiController.GetModelConfig)
-			aiGroup.PUT("/:provider/models/:model/enable", aiController.EnableModel)
-			aiGroup.PUT("/:provider/models/:model/disable", aiController.DisableModel)
+			aiGroup.GET("/:provider/models", aiLimit, middleware.ETag(), aiController.ListModels)
+			aiGroup.GET("/:provider/models/all", aiLimit, middleware.ETag(), aiController.ListAllModels) // 新增：获取所有模型（包括禁用的）
+			aiGroup.GET("/:provider/config/:model", aiLimit, aiController.GetModelConfig)
+			aiGroup.PUT("/:provider/models/:model/enable", aiLimit, aiController.EnableModel)
+			aiGroup.PUT("/:provider/models/:model/disable", aiLimit, aiController.DisableModel)
+			modelAdmin := modelAdminGroup(aiGroup, jwtManager, apiTokens, users, aiLimit, logger)
+			modelAdmin.PATCH("/:provider/models", aiController.UpdateModels)
+			modelAdmin.PUT("/:provider/models/:model/metadata", aiController.UpdateModelMetadata)
+			modelAdmin.DELETE("/:provider/models/:model/metadata", aiController.DeleteModelMetadata)
 			
 			// API密钥管理端点（可选认证）
-			aiGroup.POST("/:provider/api-key", middleware.OptionalAuthMiddleware(jwtManager, logger), aiController.SetAPIKey)
-			aiGroup.POST("/:provider/validate", middleware.OptionalAuthMiddleware(jwtManager, logger), aiController.ValidateAPIKey)
-			aiGroup.GET("/api-keys/status", middleware.OptionalAuthMiddleware(jwtManager, logger), aiController.GetAPIKeyStatus)
-			aiGroup.GET("/:provider/api-key/plain", middleware.OptionalAuthMiddleware(jwtManager, logger), aiController.GetPlainAPIKey)
+			aiGroup.POST("/:provider/api-key", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiController.SetAPIKey)
+			aiGroup.POST("/:provider/validate", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiController.ValidateAPIKey)
+			aiGroup.GET("/api-keys/status", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiController.GetAPIKeyStatus)
+			// 明文密钥：管理员 + 专用权限 + 近期重新认证，所有尝试都记录审计日志
+			aiGroup.GET("/:provider/api-key/plain", middleware.Audit("api_key_reveal", logger), middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireSessionAuth(), middleware.RequireAdmin(users, logger), middleware.RequirePermission(permissions, dto.PermissionRevealAPIKeys, logger), middleware.RequireRecentAuth(reauthMaxAge), aiLimit, aiController.GetPlainAPIKey)
+			aiGroup.GET("/:provider/api-key/versions", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiController.ListAPIKeyVersions)
 			
 			// 提供商管理端点
-			aiGroup.GET("/providers", aiController.ListProviders)
+			aiGroup.GET("/providers", aiLimit, middleware.ETag(), aiController.ListProviders)
+
+			// 当前用户的AI用量配额
+			aiGroup.GET("/quota", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiAssistantController.GetQuota)
+
+			// 发送前估算聊天请求在各模型上的令牌数和费用
+			aiGroup.POST("/estimate", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), aiLimit, aiAssistantController.EstimateCost)
 		}
 
 		// AI助手端点
-		assistantGroup := v1.Group("/assistant")
+		assistantGroup := v1.Group("/assistant", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), middleware.RateLimitGroup(limiter, "assistant", logger))
 		{
 			// 初始化AI助手
 			assistantGroup.POST("/initialize", aiAssistantController.Initialize)
 			
 			// AI助手聊天端点
-			assistantGroup.POST("/chat", aiAssistantController.Chat)
+			assistantGroup.POST("/chat", middleware.Idempotency(idempotent, logger), aiAssistantController.Chat)
+
+			// 评价助手回复
+			assistantGroup.POST("/responses/:id/feedback", feedbackController.SubmitFeedback)
 		}
 
 		// 股票分析端点
-		stockGroup := v1.Group("/stock")
+		stockGroup := v1.Group("/stock", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeStock), middleware.RateLimitGroup(limiter, "stock", logger))
 		{
 			// 股票分析
 			stockGroup.POST("/analyze", stockController.AnalyzeStock)
 			
 			// 股票比较
-			stockGroup.POST("/compare", stockController.CompareStocks)
+			stockGroup.POST("/compare", middleware.WireFormat(), stockController.CompareStocks)
+			
+			// 投资组合风险指标
+			stockGroup.POST("/portfolio/risk", stockController.AnalyzePortfolioRisk)
 			
 			// 股票报价
-			stockGroup.GET("/quote/:symbol", stockController.GetStockQuote)
+			stockGroup.GET("/quote/:symbol", middleware.WireFormat(), stockController.GetStockQuote)
 			
 			// 股票历史数据
-			stockGroup.GET("/history/:symbol", stockController.GetStockHistory)
+			stockGroup.GET("/history/:symbol", middleware.WireFormat(), stockController.GetStockHistory)
+			
+			// 股票分析PDF报告
+			stockGroup.GET("/:symbol/report.pdf", stockController.GetStockReportPDF)
 			
 			// 市场摘要
 			stockGroup.GET("/market/summary", stockController.GetMarketSummary)
@@ -123,5 +334,31 @@ func SetupRoutes(logger *zap.Logger, jwtManager *utils.JWTManager, mcpController
 		}
 	}
 
+	// OpenAI兼容接口，SDK 的 base_url 设为 http://<host>:<port>/v1 即可接入
+	openaiGroup := r.Group("/v1", middleware.OptionalAuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), middleware.RateLimitGroup(limiter, "assistant", logger))
+	{
+		openaiGroup.POST("/chat/completions", chatCompletionController.ChatCompletions)
+		openaiGroup.GET("/models", middleware.ETag(), chatCompletionController.ListModels)
+	}
+
+	// 兼容旧的未带版本路径，按 v1 处理并在响应头中提示迁移
+	registerAccountRoutes(r.Group("/api", middleware.LegacyAPI("v1")))
+
+	return r
+}
+
+// newEngine 创建Gin引擎，只有来自 trustedProxies 的请求才按转发头识别客户端IP，
+// 否则客户端可以伪造 X-Forwarded-For 绕过按IP限流和幂等键隔离。配置无效时不信任任何代理
+func newEngine(trustedProxies []string, logger *zap.Logger) *gin.Engine {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		logger.Error("Invalid trusted proxies, ignoring forwarded client IPs", zap.Strings("trusted_proxies", trustedProxies), zap.Error(err))
+		_ = r.SetTrustedProxies(nil)
+	}
 	return r
 }
+
+// modelAdminGroup 修改模型列表和元数据的路由分组，需要管理员，个人访问令牌还需要 ai 权限范围
+func modelAdminGroup(aiGroup *gin.RouterGroup, jwtManager *utils.JWTManager, apiTokens middleware.APITokenAuthenticator, users middleware.UserLookup, aiLimit gin.HandlerFunc, logger *zap.Logger) *gin.RouterGroup {
+	return aiGroup.Group("", middleware.AuthMiddleware(jwtManager, apiTokens, logger), middleware.RequireScope(dto.APITokenScopeAI), middleware.RequireAdmin(users, logger), aiLimit)
+}
@@ -0,0 +1,54 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestNewEngineTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantStatus     int
+	}{
+		// 伪造的转发头不能换到新的限流桶
+		{name: "No trusted proxies", wantStatus: http.StatusTooManyRequests},
+		{name: "Invalid proxies trust nobody", trustedProxies: []string{"not-an-ip"}, wantStatus: http.StatusTooManyRequests},
+		{name: "Request from other address", trustedProxies: []string{"10.0.0.0/8"}, wantStatus: http.StatusTooManyRequests},
+		// 可信代理转发的不同客户端各自限流
+		{name: "Request from trusted proxy", trustedProxies: []string{"192.0.2.0/24"}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rules{
+				PerIP:  ratelimit.Rule{Rate: 0.001, Burst: 1},
+				Groups: map[string]ratelimit.Rule{"auth": {Rate: 0.001, Burst: 1}},
+			})
+			r := newEngine(tt.trustedProxies, zap.NewNop())
+			r.Use(middleware.RateLimit(limiter, zap.NewNop()))
+			r.GET("/login", middleware.RateLimitGroup(limiter, "auth", zap.NewNop()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			var w *httptest.ResponseRecorder
+			for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+				// httptest 请求的连接地址为 192.0.2.1
+				req := httptest.NewRequest(http.MethodGet, "/login", nil)
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w = httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+			}
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package runtimeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/provider"
+)
+
+// providerModelChanges 一个提供商需要应用的模型启用状态
+type providerModelChanges struct {
+	providerType provider.ProviderType
+	changes      []provider.ModelChange
+}
+
+// plan 导入配置与当前配置的差异，models 是导入时立即应用的部分
+type plan struct {
+	changes []dto.RuntimeConfigChange
+	models  []providerModelChanges
+}
+
+// add 记录一处差异，值相同时忽略
+func (p *plan) add(path string, current, imported interface{}) {
+	if current == imported {
+		return
+	}
+	p.changes = append(p.changes, dto.RuntimeConfigChange{Path: path, Current: current, Imported: imported})
+}
+
+// validate 检查导入的配置能否用于当前环境，返回所有问题
+func validate(current, imported *dto.RuntimeConfig) []string {
+	var problems []string
+	if imported.Version != dto.RuntimeConfigVersion {
+		problems = append(problems, fmt.Sprintf("unsupported version %d, expected %d", imported.Version, dto.RuntimeConfigVersion))
+	}
+	checkSecret := func(path, value string) {
+		if value != "" && value != dto.RedactedSecret {
+			problems = append(problems, fmt.Sprintf("%s must be empty or %s, secrets are not imported", path, dto.RedactedSecret))
+		}
+	}
+
+	seenProviders := make(map[string]bool)
+	for _, entry := range imported.Providers {
+		if seenProviders[entry.Type] {
+			problems = append(problems, fmt.Sprintf("provider %s is listed more than once", entry.Type))
+			continue
+		}
+		seenProviders[entry.Type] = true
+
+		existing := findProvider(current, entry.Type)
+		if existing == nil {
+			problems = append(problems, fmt.Sprintf("provider %s is not available", entry.Type))
+			continue
+		}
+		checkSecret("providers."+entry.Type+".api_key", entry.APIKey)
+
+		seenModels := make(map[string]bool)
+		for _, model := range entry.Models {
+			prefix := "providers." + entry.Type + ".models." + model.Name
+			switch {
+			case seenModels[model.Name]:
+				problems = append(problems, fmt.Sprintf("model %s of provider %s is listed more than once", model.Name, entry.Type))
+			case findModel(existing, model.Name) == nil:
+				problems = append(problems, fmt.Sprintf("model %s is not available for provider %s", model.Name, entry.Type))
+			case model.MaxTokens < 0:
+				problems = append(problems, prefix+".max_tokens must not be negative")
+			case model.Temperature < 0 || model.Temperature > 2:
+				problems = append(problems, prefix+".temperature must be between 0 and 2")
+			case model.TopP < 0 || model.TopP > 1:
+				problems = append(problems, prefix+".top_p must be between 0 and 1")
+			}
+			seenModels[model.Name] = true
+		}
+	}
+	checkSecret("tools.transcript_api_key", imported.Tools.TranscriptAPIKey)
+	return problems
+}
+
+// compare 比较导入的配置与当前配置。导入中未列出的提供商和模型保持不变，
+// 只有模型启用状态会立即应用，其余设置来自配置文件，需要修改后重启
+func compare(current, imported *dto.RuntimeConfig) plan {
+	var p plan
+	for _, entry := range imported.Providers {
+		existing := findProvider(current, entry.Type)
+		prefix := "providers." + entry.Type + "."
+		p.add(prefix+"base_url", existing.BaseURL, entry.BaseURL)
+		p.add(prefix+"project_id", existing.ProjectID, entry.ProjectID)
+		p.add(prefix+"location", existing.Location, entry.Location)
+		p.add(prefix+"default_model", existing.DefaultModel, entry.DefaultModel)
+		p.add(prefix+"timeout", existing.Timeout, entry.Timeout)
+		p.add(prefix+"max_retries", existing.MaxRetries, entry.MaxRetries)
+
+		var modelChanges []provider.ModelChange
+		for _, model := range entry.Models {
+			was := findModel(existing, model.Name)
+			modelPrefix := prefix + "models." + model.Name + "."
+			p.add(modelPrefix+"display_name", was.DisplayName, model.DisplayName)
+			p.add(modelPrefix+"max_tokens", was.MaxTokens, model.MaxTokens)
+			p.add(modelPrefix+"temperature", was.Temperature, model.Temperature)
+			p.add(modelPrefix+"top_p", was.TopP, model.TopP)
+			p.add(modelPrefix+"top_k", was.TopK, model.TopK)
+			if was.Enabled != model.Enabled {
+				p.changes = append(p.changes, dto.RuntimeConfigChange{Path: modelPrefix + "enabled", Current: was.Enabled, Imported: model.Enabled, Live: true})
+				modelChanges = append(modelChanges, provider.ModelChange{Model: model.Name, Enabled: model.Enabled})
+			}
+		}
+		if len(modelChanges) > 0 {
+			p.models = append(p.models, providerModelChanges{providerType: provider.ProviderType(entry.Type), changes: modelChanges})
+		}
+	}
+
+	p.add("tools.sampling_model", current.Tools.SamplingModel, imported.Tools.SamplingModel)
+	p.add("tools.risk_free_rate", current.Tools.RiskFreeRate, imported.Tools.RiskFreeRate)
+	if imported.Tools.Available != nil {
+		available := append([]string(nil), imported.Tools.Available...)
+		sort.Strings(available)
+		// 工具由代码注册，差异说明两个环境的版本不同
+		p.add("tools.available", strings.Join(current.Tools.Available, ","), strings.Join(available, ","))
+	}
+	return p
+}
+
+// findProvider 按类型查找提供商配置
+func findProvider(cfg *dto.RuntimeConfig, providerType string) *dto.RuntimeProviderConfig {
+	for i := range cfg.Providers {
+		if cfg.Providers[i].Type == providerType {
+			return &cfg.Providers[i]
+		}
+	}
+	return nil
+}
+
+// findModel 按名称查找模型配置
+func findModel(entry *dto.RuntimeProviderConfig, name string) *dto.RuntimeModelConfig {
+	for i := range entry.Models {
+		if entry.Models[i].Name == name {
+			return &entry.Models[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package runtimeconfig 导出和导入运行时配置（提供商、模型状态、工具设置），用于在环境间迁移
+package runtimeconfig
+
+import (
+	"context"
+	"sort"
+
+	"go-springAi/internal/config"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+	"go-springAi/internal/types"
+
+	"go.uber.org/zap"
+)
+
+// Service 运行时配置导出导入服务
+type Service struct {
+	cfg        *config.Config
+	providers  *provider.Manager
+	mcpService service.MCPService
+	logger     *zap.Logger
+}
+
+// NewService 创建运行时配置服务
+func NewService(cfg *config.Config, providers *provider.Manager, mcpService service.MCPService, logger *zap.Logger) *Service {
+	return &Service{
+		cfg:        cfg,
+		providers:  providers,
+		mcpService: mcpService,
+		logger:     logger,
+	}
+}
+
+// Export 导出当前生效的配置，密钥替换为 dto.RedactedSecret
+func (s *Service) Export(ctx context.Context) (*dto.RuntimeConfig, error) {
+	exported := &dto.RuntimeConfig{Version: dto.RuntimeConfigVersion}
+	for _, providerType := range s.providerTypes() {
+		prov, err := s.providers.GetProvider(providerType)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to export configuration").WithCause(err)
+		}
+		models, err := prov.ListAllModels(ctx)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to export configuration").WithCause(err)
+		}
+
+		entry := s.providerSettings(providerType)
+		entry.Models = make([]dto.RuntimeModelConfig, 0, len(models))
+		for _, model := range models {
+			entry.Models = append(entry.Models, dto.RuntimeModelConfig{
+				Name:        model.Name,
+				DisplayName: model.DisplayName,
+				MaxTokens:   model.MaxTokens,
+				Temperature: model.Temperature,
+				TopP:        model.TopP,
+				TopK:        model.TopK,
+				Enabled:     model.Enabled,
+			})
+		}
+		sort.Slice(entry.Models, func(i, j int) bool { return entry.Models[i].Name < entry.Models[j].Name })
+		exported.Providers = append(exported.Providers, entry)
+	}
+
+	tools, err := s.mcpService.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exported.Tools = dto.RuntimeToolsConfig{
+		Available:        make([]string, 0, len(tools.Tools)),
+		SamplingModel:    s.cfg.MCP.SamplingModel,
+		RiskFreeRate:     s.cfg.Stock.RiskFreeRate,
+		TranscriptAPIKey: redact(s.cfg.Stock.TranscriptAPIKey),
+	}
+	for _, tool := range tools.Tools {
+		exported.Tools.Available = append(exported.Tools.Available, tool.Name)
+	}
+	sort.Strings(exported.Tools.Available)
+	return exported, nil
+}
+
+// Import 校验导入的配置并与当前配置比较，dryRun 为 false 时应用模型启用状态。
+// 其余差异来自配置文件，只在结果中列出；密钥不会被导入
+func (s *Service) Import(ctx context.Context, imported *dto.RuntimeConfig, dryRun bool) (*dto.RuntimeConfigImportResult, error) {
+	current, err := s.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if problems := validate(current, imported); len(problems) > 0 {
+		return nil, errors.NewValidationError("Invalid configuration").WithMetadata("errors", problems)
+	}
+
+	p := compare(current, imported)
+	result := &dto.RuntimeConfigImportResult{DryRun: dryRun, Changes: p.changes}
+	if dryRun {
+		return result, nil
+	}
+	if err := s.apply(p.models); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Runtime configuration imported", zap.Int("changes", len(p.changes)))
+	return result, nil
+}
+
+// apply 按提供商批量应用模型启用状态，某个提供商失败时恢复之前已应用的提供商
+func (s *Service) apply(models []providerModelChanges) error {
+	for i, entry := range models {
+		results, err := s.providers.SetModelsEnabled(entry.providerType, entry.changes)
+		if err == nil {
+			continue
+		}
+		for _, applied := range models[:i] {
+			undo := make([]provider.ModelChange, len(applied.changes))
+			for j, change := range applied.changes {
+				undo[j] = provider.ModelChange{Model: change.Model, Enabled: !change.Enabled}
+			}
+			if _, err := s.providers.SetModelsEnabled(applied.providerType, undo); err != nil {
+				s.logger.Error("Failed to roll back imported model states",
+					zap.String("provider", string(applied.providerType)),
+					zap.Error(err))
+			}
+		}
+		return errors.NewOperationFailedError("import configuration").
+			WithCause(err).
+			WithMetadata("provider", string(entry.providerType)).
+			WithMetadata("results", results)
+	}
+	return nil
+}
+
+// providerTypes 已注册的提供商类型，按名称排序
+func (s *Service) providerTypes() []provider.ProviderType {
+	providerTypes := s.providers.GetProviderTypes()
+	sort.Slice(providerTypes, func(i, j int) bool { return providerTypes[i] < providerTypes[j] })
+	return providerTypes
+}
+
+// providerSettings 提供商在配置文件中的设置
+func (s *Service) providerSettings(providerType provider.ProviderType) dto.RuntimeProviderConfig {
+	entry := dto.RuntimeProviderConfig{Type: string(providerType)}
+	switch providerType {
+	case types.ProviderTypeOpenAI:
+		entry.APIKey = redact(s.cfg.OpenAI.APIKey)
+		entry.BaseURL = s.cfg.OpenAI.BaseURL
+		entry.DefaultModel = s.cfg.OpenAI.DefaultModel
+		entry.Timeout = s.cfg.OpenAI.Timeout
+		entry.MaxRetries = s.cfg.OpenAI.MaxRetries
+	case types.ProviderTypeGoogleAI:
+		entry.APIKey = redact(s.cfg.GoogleAI.APIKey)
+		entry.ProjectID = s.cfg.GoogleAI.ProjectID
+		entry.Location = s.cfg.GoogleAI.Location
+		entry.DefaultModel = s.cfg.GoogleAI.DefaultModel
+		entry.Timeout = s.cfg.GoogleAI.Timeout
+		entry.MaxRetries = s.cfg.GoogleAI.MaxRetries
+	}
+	return entry
+}
+
+// redact 已设置的密钥替换为占位符
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return dto.RedactedSecret
+}
@@ -0,0 +1,130 @@
+package runtimeconfig
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/config"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/logger"
+	"go-springAi/internal/provider"
+	"go-springAi/internal/service"
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T) *Service {
+	manager := provider.NewManager(logger.NewLoggerFromZap(zap.NewNop()))
+	require.NoError(t, manager.RegisterProvider(provider.NewMockProvider("mock", types.ProviderTypeMock)))
+	cfg := &config.Config{
+		MCP:   config.MCPConfig{SamplingModel: "mock-gpt-3.5-turbo"},
+		Stock: config.StockConfig{RiskFreeRate: 0.04, TranscriptAPIKey: "secret-key"},
+	}
+	return NewService(cfg, manager, service.NewMCPService(nil, nil, "", nil, nil, zap.NewNop()), zap.NewNop())
+}
+
+func TestServiceExport(t *testing.T) {
+	exported, err := newTestService(t).Export(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.RuntimeConfigVersion, exported.Version)
+	require.Len(t, exported.Providers, 1)
+	assert.Equal(t, "mock", exported.Providers[0].Type)
+	require.Len(t, exported.Providers[0].Models, 1)
+	assert.True(t, exported.Providers[0].Models[0].Enabled)
+	assert.Equal(t, dto.RedactedSecret, exported.Tools.TranscriptAPIKey)
+	assert.NotEmpty(t, exported.Tools.Available)
+}
+
+func TestServiceImport(t *testing.T) {
+	ctx := context.Background()
+	modified := func(edit func(cfg *dto.RuntimeConfig)) *dto.RuntimeConfig {
+		cfg, err := newTestService(t).Export(ctx)
+		require.NoError(t, err)
+		edit(cfg)
+		return cfg
+	}
+
+	tests := []struct {
+		name        string
+		imported    *dto.RuntimeConfig
+		wantErrors  int
+		wantPaths   []string
+		wantEnabled bool
+	}{
+		{
+			name:        "Unchanged",
+			imported:    modified(func(cfg *dto.RuntimeConfig) {}),
+			wantEnabled: true,
+		},
+		{
+			name: "Model disabled and settings changed",
+			imported: modified(func(cfg *dto.RuntimeConfig) {
+				cfg.Providers[0].Models[0].Enabled = false
+				cfg.Providers[0].Models[0].MaxTokens = 1024
+				cfg.Tools.RiskFreeRate = 0.05
+			}),
+			wantPaths: []string{
+				"providers.mock.models.mock-gpt-3.5-turbo.max_tokens",
+				"providers.mock.models.mock-gpt-3.5-turbo.enabled",
+				"tools.risk_free_rate",
+			},
+		},
+		{
+			name: "Partial import leaves other providers alone",
+			imported: modified(func(cfg *dto.RuntimeConfig) {
+				cfg.Providers = nil
+			}),
+			wantEnabled: true,
+		},
+		{
+			name: "Invalid documents",
+			imported: modified(func(cfg *dto.RuntimeConfig) {
+				cfg.Version = 2
+				cfg.Providers[0].Models = append(cfg.Providers[0].Models, dto.RuntimeModelConfig{Name: "unknown"})
+				cfg.Providers = append(cfg.Providers, dto.RuntimeProviderConfig{Type: "azure"})
+				cfg.Tools.TranscriptAPIKey = "plaintext"
+			}),
+			wantErrors:  4,
+			wantEnabled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			preview, err := s.Import(ctx, tt.imported, true)
+			if tt.wantErrors > 0 {
+				appErr, ok := errors.IsAppError(err)
+				require.True(t, ok)
+				assert.Equal(t, errors.ErrCodeValidationFailed, appErr.Code)
+				assert.Len(t, appErr.Metadata["errors"], tt.wantErrors)
+				return
+			}
+			require.NoError(t, err)
+			var paths []string
+			for _, change := range preview.Changes {
+				paths = append(paths, change.Path)
+				assert.Equal(t, change.Path == "providers.mock.models.mock-gpt-3.5-turbo.enabled", change.Live)
+			}
+			assert.Equal(t, tt.wantPaths, paths)
+
+			// 预览不修改模型状态
+			prov, err := s.providers.GetProvider(types.ProviderTypeMock)
+			require.NoError(t, err)
+			config, err := prov.GetModelConfig("mock-gpt-3.5-turbo")
+			require.NoError(t, err)
+			assert.True(t, config.Enabled)
+
+			result, err := s.Import(ctx, tt.imported, false)
+			require.NoError(t, err)
+			assert.Equal(t, preview.Changes, result.Changes)
+			config, err = prov.GetModelConfig("mock-gpt-3.5-turbo")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, config.Enabled)
+		})
+	}
+}
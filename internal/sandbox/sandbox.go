@@ -0,0 +1,59 @@
+// Package sandbox 实现沙箱运行模式：AI提供商由模拟提供商代替，行情接口只从录制文件回放，
+// 其余所有出站HTTP请求在发出前被拒绝，演示和CI无需任何凭据和网络即可运行完整API
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrBlocked 沙箱模式下被拒绝的出站请求
+var ErrBlocked = errors.New("sandbox: outbound request blocked")
+
+// Transport 拒绝所有请求的 http.RoundTripper
+type Transport struct{}
+
+// RoundTrip 实现 http.RoundTripper，只报告主机和路径，不包含可能带密钥的查询参数
+func (Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%w: %s %s://%s%s", ErrBlocked, req.Method, req.URL.Scheme, req.URL.Host, req.URL.Path)
+}
+
+var (
+	mu       sync.Mutex
+	original http.RoundTripper // Enable 之前的 http.DefaultTransport
+)
+
+// Enable 用 Transport 替换 http.DefaultTransport。项目中的HTTP客户端都以它为底层传输，
+// 因此未被 mock 或回放层处理的请求不会到达网络。重复调用无效果
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	if original != nil {
+		return
+	}
+	original = http.DefaultTransport
+	http.DefaultTransport = Transport{}
+}
+
+// Disable 恢复 Enable 之前的 http.DefaultTransport，供测试使用
+func Disable() {
+	mu.Lock()
+	defer mu.Unlock()
+	if original == nil {
+		return
+	}
+	http.DefaultTransport = original
+	original = nil
+}
+
+// Enabled 返回是否已启用沙箱模式
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return original != nil
+}
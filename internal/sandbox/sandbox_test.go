@@ -0,0 +1,39 @@
+package sandbox
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableBlocksDefaultTransport(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer server.Close()
+
+	Enable()
+	Enable()
+	require.True(t, Enabled())
+
+	_, err := http.Get(server.URL + "/v1/chat")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrBlocked))
+	assert.Zero(t, hits)
+
+	// 自带传输层的客户端不受影响，测试服务器等本地客户端可以继续使用
+	resp, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, hits)
+
+	Disable()
+	assert.False(t, Enabled())
+	resp, err = http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, hits)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNext(t *testing.T) {
+	// 2026-03-04 是周三
+	after := time.Date(2026, 3, 4, 10, 17, 42, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{spec: "5/20 * * * *", want: time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		{spec: "0 9-17/4 * * *", want: time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{spec: "30 2 * * *", want: time.Date(2026, 3, 5, 2, 30, 0, 0, time.UTC)},
+		{spec: "0 0 1,15 * *", want: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 8 * * 1-5", want: time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC)},
+		{spec: "0 8 * * 7", want: time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC)},
+		// 日和周都受限时满足其一即可
+		{spec: "0 0 20 * 5", want: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@every 90m", want: time.Date(2026, 3, 4, 11, 47, 42, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(after))
+		})
+	}
+}
+
+func TestParseNextInLocation(t *testing.T) {
+	loc := time.FixedZone("IST", 5*3600+1800)
+	schedule, err := Parse("0 9 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2026, 3, 4, 9, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 3, 5, 9, 0, 0, 0, loc), next)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 10s",
+		"@every soon",
+		"@often",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+
+	// 不可能出现的日期
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
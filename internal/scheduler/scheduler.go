@@ -0,0 +1,376 @@
+// Package scheduler 按 cron 表达式执行保存在数据库中的定时任务。
+//
+// 任务类型（如密钥验证、日志清理）在启动时注册，管理员通过接口创建任务并指定类型、
+// 计划和参数。调度器定期检查到期任务，先以条件更新推进下一次执行时间抢占本次执行，
+// 多实例部署时同一次执行只会在一个实例上运行；错过的执行不会补跑。
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-springAi/internal/database/generated/scheduled_jobs"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// 执行状态
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// 调度默认参数
+const (
+	DefaultPollInterval = 15 * time.Second
+	DefaultTimeout      = 30 * time.Minute
+)
+
+// dueBatch 每次检查最多取出的到期任务数
+const dueBatch = 100
+
+// maxResultLength 保存的执行结果和错误信息长度上限
+const maxResultLength = 1000
+
+var (
+	// ErrUnknownType 任务类型未注册
+	ErrUnknownType = errors.New("unknown job type")
+	// ErrRunning 任务正在执行
+	ErrRunning = errors.New("job is already running")
+)
+
+// JobType 任务类型
+type JobType struct {
+	Name        string
+	Description string
+	// Validate 校验任务参数，为 nil 时只要求参数是 JSON 对象
+	Validate func(params json.RawMessage) error
+	// Run 执行一次任务，返回写入执行记录的简要结果
+	Run func(ctx context.Context, params json.RawMessage) (string, error)
+}
+
+// Options 调度配置，零值使用默认参数
+type Options struct {
+	Enabled      bool
+	PollInterval time.Duration  // 检查到期任务的间隔
+	Timeout      time.Duration  // 单次执行超时
+	Location     *time.Location // 解析 cron 表达式使用的时区，默认 UTC
+}
+
+// Scheduler 定时任务调度器
+type Scheduler struct {
+	repo   repository.ScheduledJobRepository
+	opts   Options
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	types   map[string]JobType
+	running map[int64]bool
+
+	wake chan struct{}
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New 创建调度器
+func New(repo repository.ScheduledJobRepository, opts Options, logger *zap.Logger) *Scheduler {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &Scheduler{
+		repo:    repo,
+		opts:    opts,
+		logger:  logger,
+		now:     time.Now,
+		types:   make(map[string]JobType),
+		running: make(map[int64]bool),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Enabled 是否启用后台调度
+func (s *Scheduler) Enabled() bool {
+	return s.opts.Enabled
+}
+
+// Location 解析 cron 表达式使用的时区
+func (s *Scheduler) Location() *time.Location {
+	return s.opts.Location
+}
+
+// Register 注册任务类型，同名类型会被替换
+func (s *Scheduler) Register(t JobType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types[t.Name] = t
+}
+
+// Types 按名称排序返回已注册的任务类型
+func (s *Scheduler) Types() []JobType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]JobType, 0, len(s.types))
+	for _, t := range s.types {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Validate 校验任务类型、计划和参数
+func (s *Scheduler) Validate(jobType, spec string, params json.RawMessage) error {
+	s.mu.Lock()
+	t, ok := s.types[jobType]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, jobType)
+	}
+	if _, err := Parse(spec); err != nil {
+		return err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(params, &object); err != nil || object == nil {
+		return errors.New("params must be a JSON object")
+	}
+	if t.Validate != nil {
+		return t.Validate(params)
+	}
+	return nil
+}
+
+// NextRun 按调度时区计算 after 之后的下一次执行时间，返回 UTC 时间
+func (s *Scheduler) NextRun(spec string, after time.Time) (time.Time, error) {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := schedule.Next(after.In(s.opts.Location))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule %q never runs", spec)
+	}
+	return next.UTC(), nil
+}
+
+// Running 任务是否正在本实例上执行
+func (s *Scheduler) Running(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[id]
+}
+
+// Wake 立即检查到期任务，不等待下一次轮询
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台调度
+func (s *Scheduler) Start() {
+	if !s.opts.Enabled {
+		s.logger.Info("Job scheduler disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.runDue()
+			select {
+			case <-ticker.C:
+			case <-s.wake:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	s.logger.Info("Job scheduler started",
+		zap.Duration("poll_interval", s.opts.PollInterval),
+		zap.String("timezone", s.opts.Location.String()))
+}
+
+// Stop 停止调度并等待执行中的任务结束，执行中的任务会收到取消信号
+func (s *Scheduler) Stop() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.wg.Wait()
+}
+
+// RunNow 立即在后台执行一次任务，不改变下一次计划执行时间
+func (s *Scheduler) RunNow(job *scheduled_jobs.ScheduledJob) error {
+	s.mu.Lock()
+	t, ok := s.types[job.JobType]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, job.JobType)
+	}
+	if !s.start(job.ID) {
+		return ErrRunning
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(job, t)
+	}()
+	return nil
+}
+
+// runDue 抢占并执行所有到期任务
+func (s *Scheduler) runDue() {
+	now := s.now().UTC()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	due, err := s.repo.ListDue(ctx, now, dueBatch)
+	if err != nil {
+		s.logger.Error("获取到期定时任务失败", zap.Error(err))
+		return
+	}
+
+	for i := range due {
+		job := &due[i]
+		next, err := s.NextRun(job.Schedule, now)
+		if err != nil {
+			// 计划已无法解析或不会再执行，推迟一天避免每次轮询都重复报错
+			s.logger.Error("定时任务计划无效", zap.Int64("job_id", job.ID), zap.String("schedule", job.Schedule), zap.Error(err))
+			next = now.Add(24 * time.Hour)
+		}
+		claimed, err := s.repo.Claim(ctx, job.ID, now, next)
+		if err != nil {
+			s.logger.Error("抢占定时任务失败", zap.Int64("job_id", job.ID), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.mu.Lock()
+		t, ok := s.types[job.JobType]
+		s.mu.Unlock()
+		if !ok {
+			s.record(job, now, 0, "", fmt.Errorf("%w: %s", ErrUnknownType, job.JobType))
+			continue
+		}
+		if !s.start(job.ID) {
+			s.logger.Warn("上一次执行尚未结束，跳过本次定时任务",
+				zap.Int64("job_id", job.ID),
+				zap.String("name", job.Name))
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(job *scheduled_jobs.ScheduledJob) {
+			defer s.wg.Done()
+			s.execute(job, t)
+		}(job)
+	}
+}
+
+// start 标记任务开始执行，任务已在执行时返回 false
+func (s *Scheduler) start(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[id] {
+		return false
+	}
+	s.running[id] = true
+	return true
+}
+
+// execute 执行任务并保存结果，调度器停止时取消执行
+func (s *Scheduler) execute(job *scheduled_jobs.ScheduledJob, t JobType) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	startedAt := s.now().UTC()
+	result, err := s.invoke(ctx, t, json.RawMessage(job.Params))
+	s.record(job, startedAt, s.now().Sub(startedAt), result, err)
+}
+
+// invoke 调用任务类型的 Run，panic 视为执行失败
+func (s *Scheduler) invoke(ctx context.Context, t JobType, params json.RawMessage) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return t.Run(ctx, params)
+}
+
+// record 保存执行结果
+func (s *Scheduler) record(job *scheduled_jobs.ScheduledJob, startedAt time.Time, duration time.Duration, result string, runErr error) {
+	params := scheduled_jobs.RecordScheduledJobRunParams{
+		ID:             job.ID,
+		LastRunAt:      sql.NullTime{Time: startedAt, Valid: true},
+		LastStatus:     StatusSucceeded,
+		LastResult:     truncate(result),
+		LastDurationMs: duration.Milliseconds(),
+	}
+	fields := []zap.Field{
+		zap.Int64("job_id", job.ID),
+		zap.String("name", job.Name),
+		zap.String("job_type", job.JobType),
+		zap.Duration("duration", duration),
+	}
+	if runErr != nil {
+		params.LastStatus = StatusFailed
+		params.LastError = truncate(runErr.Error())
+		s.logger.Error("定时任务执行失败", append(fields, zap.Error(runErr))...)
+	} else {
+		s.logger.Info("Scheduled job finished", append(fields, zap.String("result", params.LastResult))...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.repo.RecordRun(ctx, params); err != nil {
+		s.logger.Error("保存定时任务执行结果失败", zap.Int64("job_id", job.ID), zap.Error(err))
+	}
+}
+
+// truncate 截断过长的结果，避免超出列长度
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxResultLength {
+		return s
+	}
+	return string(runes[:maxResultLength-3]) + "..."
+}
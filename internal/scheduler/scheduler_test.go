@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/scheduled_jobs"
+	"go-springAi/internal/repository"
+	"go-springAi/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestRepository(t *testing.T) repository.ScheduledJobRepository {
+	ctx := context.Background()
+	db, err := database.NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	return repository.NewScheduledJobRepository(db)
+}
+
+func TestSchedulerRunDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 10, 17, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		jobType    string
+		run        func(ctx context.Context, params json.RawMessage) (string, error)
+		wantStatus string
+		wantResult string
+		wantError  string
+	}{
+		{
+			name:    "Succeeded",
+			jobType: "echo",
+			run: func(ctx context.Context, params json.RawMessage) (string, error) {
+				return string(params), nil
+			},
+			wantStatus: StatusSucceeded,
+			wantResult: `{"n":1}`,
+		},
+		{
+			name:    "Failed",
+			jobType: "echo",
+			run: func(ctx context.Context, params json.RawMessage) (string, error) {
+				return "partial", errors.New("boom")
+			},
+			wantStatus: StatusFailed,
+			wantResult: "partial",
+			wantError:  "boom",
+		},
+		{
+			name:    "Panicked",
+			jobType: "echo",
+			run: func(ctx context.Context, params json.RawMessage) (string, error) {
+				panic("nil map")
+			},
+			wantStatus: StatusFailed,
+			wantError:  "job panicked: nil map",
+		},
+		{
+			name:       "Unknown type",
+			jobType:    "removed",
+			wantStatus: StatusFailed,
+			wantError:  "unknown job type: removed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			job, err := repo.Create(ctx, scheduled_jobs.CreateScheduledJobParams{
+				Name: "job", JobType: tt.jobType, Schedule: "*/15 * * * *", Params: `{"n":1}`, Enabled: true, NextRunAt: now.Add(-time.Minute),
+			})
+			require.NoError(t, err)
+
+			// 两个实例同时检查到期任务，只有一个执行
+			var runs int32
+			var schedulers []*Scheduler
+			for i := 0; i < 2; i++ {
+				s := New(repo, Options{Enabled: true}, zap.NewNop())
+				s.now = func() time.Time { return now }
+				if tt.run != nil {
+					s.Register(JobType{Name: "echo", Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+						atomic.AddInt32(&runs, 1)
+						return tt.run(ctx, params)
+					}})
+				}
+				schedulers = append(schedulers, s)
+			}
+			for _, s := range schedulers {
+				s.runDue()
+				s.Stop()
+			}
+
+			if tt.run != nil {
+				assert.Equal(t, int32(1), runs)
+			}
+			got, err := repo.Get(ctx, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.LastStatus)
+			assert.Equal(t, tt.wantResult, got.LastResult)
+			assert.Equal(t, tt.wantError, got.LastError)
+			assert.Equal(t, int64(1), got.RunCount)
+			assert.True(t, got.LastRunAt.Valid)
+			assert.True(t, time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC).Equal(got.NextRunAt), got.NextRunAt)
+		})
+	}
+}
+
+func TestSchedulerRunNow(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	job, err := repo.Create(ctx, scheduled_jobs.CreateScheduledJobParams{
+		Name: "job", JobType: "wait", Schedule: "@daily", Params: `{}`, Enabled: false, NextRunAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	s := New(repo, Options{}, zap.NewNop())
+	s.Register(JobType{Name: "wait", Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+		<-release
+		return "done", nil
+	}})
+
+	require.NoError(t, s.RunNow(job))
+	assert.True(t, s.Running(job.ID))
+	assert.ErrorIs(t, s.RunNow(job), ErrRunning)
+
+	close(release)
+	s.Stop()
+	assert.False(t, s.Running(job.ID))
+
+	got, err := repo.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, got.LastStatus)
+	assert.Equal(t, "done", got.LastResult)
+	assert.True(t, job.NextRunAt.Equal(got.NextRunAt))
+
+	assert.ErrorIs(t, s.RunNow(&scheduled_jobs.ScheduledJob{ID: 99, JobType: "missing"}), ErrUnknownType)
+}
+
+func TestSchedulerValidate(t *testing.T) {
+	s := New(nil, Options{}, zap.NewNop())
+	s.Register(JobType{Name: "report", Validate: func(params json.RawMessage) error {
+		var p struct {
+			Symbol string `json:"symbol"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.Symbol == "" {
+			return errors.New("symbol is required")
+		}
+		return nil
+	}})
+
+	assert.NoError(t, s.Validate("report", "@daily", json.RawMessage(`{"symbol":"AAPL"}`)))
+	assert.ErrorIs(t, s.Validate("unknown", "@daily", json.RawMessage(`{}`)), ErrUnknownType)
+	assert.Error(t, s.Validate("report", "not a schedule", json.RawMessage(`{"symbol":"AAPL"}`)))
+	assert.EqualError(t, s.Validate("report", "@daily", json.RawMessage(`[]`)), "params must be a JSON object")
+	assert.EqualError(t, s.Validate("report", "@daily", json.RawMessage(`{}`)), "symbol is required")
+}
@@ -0,0 +1,176 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// AWSConfig AWS Secrets Manager 配置，凭证为空时读取标准 AWS_* 环境变量
+type AWSConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string // 自定义端点（如 LocalStack），为空时使用区域默认端点
+	Prefix          string // 密钥名称前缀，如 go-springAi
+}
+
+// AWSStore 基于 Secrets Manager HTTP API 的密钥存储
+type AWSStore struct {
+	cfg        AWSConfig
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// awsError Secrets Manager 错误响应
+type awsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+	Status  int    `json:"-"`
+}
+
+func (e *awsError) Error() string {
+	return fmt.Sprintf("aws secrets manager error (HTTP %d): %s: %s", e.Status, e.Type, e.Message)
+}
+
+// is 判断错误类型，__type 可能带有命名空间前缀
+func (e *awsError) is(errType string) bool {
+	return e.Type == errType || strings.HasSuffix(e.Type, "#"+errType)
+}
+
+// NewAWSStore 创建 AWS Secrets Manager 密钥存储
+func NewAWSStore(cfg AWSConfig, httpClient *http.Client) (*AWSStore, error) {
+	if cfg.Region == "" {
+		cfg.Region = firstNonEmpty(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+	}
+	if cfg.AccessKeyID == "" && cfg.SecretAccessKey == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("aws region and credentials are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+
+	return &AWSStore{cfg: cfg, httpClient: httpClient, now: time.Now}, nil
+}
+
+// Get 读取密钥当前版本
+func (s *AWSStore) Get(ctx context.Context, name string) (string, error) {
+	var resp struct {
+		SecretString string `json:"SecretString"`
+	}
+	err := s.call(ctx, "GetSecretValue", map[string]interface{}{"SecretId": s.secretID(name)}, &resp)
+	if err != nil {
+		if awsErr, ok := err.(*awsError); ok && awsErr.is("ResourceNotFoundException") {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return resp.SecretString, nil
+}
+
+// Put 写入新版本，密钥不存在时创建
+func (s *AWSStore) Put(ctx context.Context, name, value string) error {
+	secretID := s.secretID(name)
+	err := s.call(ctx, "PutSecretValue", map[string]interface{}{
+		"SecretId":     secretID,
+		"SecretString": value,
+	}, nil)
+	if awsErr, ok := err.(*awsError); ok && awsErr.is("ResourceNotFoundException") {
+		return s.call(ctx, "CreateSecret", map[string]interface{}{
+			"Name":         secretID,
+			"SecretString": value,
+		}, nil)
+	}
+	return err
+}
+
+// Delete 立即删除密钥，不保留恢复窗口
+func (s *AWSStore) Delete(ctx context.Context, name string) error {
+	err := s.call(ctx, "DeleteSecret", map[string]interface{}{
+		"SecretId":                   s.secretID(name),
+		"ForceDeleteWithoutRecovery": true,
+	}, nil)
+	if awsErr, ok := err.(*awsError); ok && awsErr.is("ResourceNotFoundException") {
+		return nil
+	}
+	return err
+}
+
+// secretID 拼接带前缀的密钥名称
+func (s *AWSStore) secretID(name string) string {
+	name = strings.Trim(name, "/")
+	if s.cfg.Prefix == "" {
+		return name
+	}
+	return s.cfg.Prefix + "/" + name
+}
+
+// call 调用 Secrets Manager JSON 接口
+func (s *AWSStore) call(ctx context.Context, action string, input interface{}, output interface{}) error {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("encode aws request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create aws request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager."+action)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	SignV4(req, payload, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, "secretsmanager", s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send aws request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read aws response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		awsErr := &awsError{Status: resp.StatusCode}
+		if err := json.Unmarshal(body, awsErr); err != nil || awsErr.Type == "" {
+			awsErr.Type = "UnknownError"
+			awsErr.Message = string(body)
+		}
+		return awsErr
+	}
+
+	if output != nil {
+		if err := json.Unmarshal(body, output); err != nil {
+			return fmt.Errorf("decode aws response: %w", err)
+		}
+	}
+	return nil
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 支持的密钥存储后端
+const (
+	BackendDatabase = "database" // 默认：密钥加密后存入数据库，不使用外部存储
+	BackendVault    = "vault"
+	BackendAWS      = "aws"
+)
+
+// ErrNotFound 密钥不存在
+var ErrNotFound = errors.New("secret not found")
+
+// Store 外部密钥存储接口，name 为不含前缀的逻辑名称，如 "api-keys/1/openai"
+type Store interface {
+	// Get 读取密钥，不存在时返回 ErrNotFound
+	Get(ctx context.Context, name string) (string, error)
+	// Put 写入密钥，已存在时覆盖
+	Put(ctx context.Context, name, value string) error
+	// Delete 删除密钥，不存在时忽略
+	Delete(ctx context.Context, name string) error
+}
+
+// Config 密钥存储配置
+type Config struct {
+	Backend string
+	Timeout time.Duration
+	Vault   VaultConfig
+	AWS     AWSConfig
+}
+
+// New 根据配置创建密钥存储，database 后端返回 nil 表示继续使用数据库存储
+func New(cfg Config) (Store, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	switch cfg.Backend {
+	case "", BackendDatabase:
+		return nil, nil
+	case BackendVault:
+		return NewVaultStore(cfg.Vault, httpClient)
+	case BackendAWS:
+		return NewAWSStore(cfg.AWS, httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported secrets backend: %s", cfg.Backend)
+	}
+}
@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// vaultValueField KV 条目中保存密钥值的字段名
+const vaultValueField = "value"
+
+// VaultConfig HashiCorp Vault KV v2 配置
+type VaultConfig struct {
+	Address   string // 如 https://vault.example.com:8200
+	Token     string
+	Namespace string // Vault Enterprise 命名空间，可为空
+	Mount     string // KV v2 挂载路径，默认 secret
+	Prefix    string // 密钥路径前缀，如 go-springAi
+}
+
+// VaultStore 基于 Vault KV v2 HTTP API 的密钥存储
+type VaultStore struct {
+	cfg        VaultConfig
+	httpClient *http.Client
+}
+
+// NewVaultStore 创建 Vault 密钥存储
+func NewVaultStore(cfg VaultConfig, httpClient *http.Client) (*VaultStore, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+
+	return &VaultStore{cfg: cfg, httpClient: httpClient}, nil
+}
+
+// Get 读取密钥最新版本
+func (s *VaultStore) Get(ctx context.Context, name string) (string, error) {
+	body, status, err := s.do(ctx, http.MethodGet, s.url("data", name), nil)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+	if status != http.StatusOK {
+		return "", vaultError(status, body)
+	}
+
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode vault response: %w", err)
+	}
+
+	// 已被软删除的版本 data 为 null
+	value, ok := resp.Data.Data[vaultValueField]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Put 写入新版本
+func (s *VaultStore) Put(ctx context.Context, name, value string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{vaultValueField: value},
+	})
+	if err != nil {
+		return fmt.Errorf("encode vault request: %w", err)
+	}
+
+	body, status, err := s.do(ctx, http.MethodPost, s.url("data", name), payload)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusNoContent {
+		return vaultError(status, body)
+	}
+	return nil
+}
+
+// Delete 删除密钥的所有版本和元数据
+func (s *VaultStore) Delete(ctx context.Context, name string) error {
+	body, status, err := s.do(ctx, http.MethodDelete, s.url("metadata", name), nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusNotFound {
+		return vaultError(status, body)
+	}
+	return nil
+}
+
+// url 构造 KV v2 接口地址，kind 为 data 或 metadata
+func (s *VaultStore) url(kind, name string) string {
+	path := strings.Trim(name, "/")
+	if s.cfg.Prefix != "" {
+		path = s.cfg.Prefix + "/" + path
+	}
+	return fmt.Sprintf("%s/v1/%s/%s/%s", s.cfg.Address, s.cfg.Mount, kind, path)
+}
+
+// do 发送请求并返回响应体和状态码
+func (s *VaultStore) do(ctx context.Context, method, url string, payload []byte) ([]byte, int, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.cfg.Token)
+	if s.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.cfg.Namespace)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("send vault request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read vault response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// vaultError 转换 Vault 错误响应
+func vaultError(status int, body []byte) error {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && len(resp.Errors) > 0 {
+		return fmt.Errorf("vault error (HTTP %d): %s", status, strings.Join(resp.Errors, "; "))
+	}
+	return fmt.Errorf("vault error (HTTP %d): %s", status, string(body))
+}
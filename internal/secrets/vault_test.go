@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeVault 内存中的 KV v2 接口
+func fakeVault(t *testing.T) *httptest.Server {
+	data := map[string]string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			data[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")] = body.Data["value"]
+			w.Write([]byte(`{"data":{"version":1}}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+			value, ok := data[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[]}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"data": map[string]string{"value": value}},
+			})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/"):
+			delete(data, strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestVaultStore(t *testing.T) {
+	server := fakeVault(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	store, err := NewVaultStore(VaultConfig{Address: server.URL + "/", Token: "root", Prefix: "/app/"}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(ctx, "api-keys/1/openai"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.Put(ctx, "api-keys/1/openai", "sk-test"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get(ctx, "api-keys/1/openai"); err != nil || value != "sk-test" {
+		t.Fatalf("expected sk-test, got %q (%v)", value, err)
+	}
+	if err := store.Delete(ctx, "api-keys/1/openai"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "api-keys/1/openai"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+
+	denied, _ := NewVaultStore(VaultConfig{Address: server.URL, Token: "wrong"}, server.Client())
+	if _, err := denied.Get(ctx, "jwt-secret"); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("expected permission denied error, got %v", err)
+	}
+}
@@ -3,15 +3,21 @@ package service
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
 	"go-springAi/internal/mcp"
 	"go-springAi/internal/openai"
 	"go-springAi/internal/types"
+	"go-springAi/internal/webhook"
 
 	"go.uber.org/zap"
 )
@@ -31,6 +37,11 @@ type ProviderInterface interface {
 	ChatCompletion(ctx context.Context, request *ProviderChatRequest) (*ProviderChatResponse, error)
 }
 
+// StreamingProvider 支持流式聊天的提供商，返回 OpenAI 格式的 SSE 流
+type StreamingProvider interface {
+	ChatCompletionStream(ctx context.Context, request *ProviderChatRequest) (io.ReadCloser, error)
+}
+
 // 使用共享的通用类型定义
 type ProviderChatRequest = types.CommonChatRequest
 type ProviderChatResponse = types.CommonChatResponse
@@ -38,48 +49,96 @@ type ProviderMessage = types.CommonMessage
 type ProviderChoice = types.CommonChoice
 type ProviderUsage = types.CommonUsage
 
+// AIUsageRecorder 记录AI请求的令牌用量和估算费用指标
+type AIUsageRecorder interface {
+	RecordChat(provider, model, user, project string, promptTokens, completionTokens int)
+}
+
 // AIAssistantService AI助手服务，集成MCP客户端和Provider管理器
 type AIAssistantService struct {
 	mcpClient       mcp.InternalMCPClient
 	openaiService   *OpenAIService
 	providerManager ProviderManager
+	quotaService    QuotaService
+	budgets         BudgetService
+	preferences     UserPreferenceService
+	projects        ProjectService
+	modelMetadata   ModelMetadataLookup
+	usageMetrics    AIUsageRecorder
+	events          webhook.Publisher
+	experiments     ExperimentAssigner
+	responses       ResponseRecorder
+	personas        PersonaResolver
+	memories        MemoryKeeper
 	logger          *zap.Logger
 }
 
-// NewAIAssistantService 创建AI助手服务
+// NewAIAssistantService 创建AI助手服务，events 为空时不发布对话完成事件，
+// modelMetadata 为空时不按上下文窗口截断历史消息，budgets 为空时不检查费用预算，
+// experiments 为空时请求不参与 A/B 实验，responses 为空时不保存回复，用户无法评价，
+// personas 为空时不应用助手角色，memories 为空时不提取和使用用户记忆
 func NewAIAssistantService(
 	mcpClient mcp.InternalMCPClient,
 	openaiService *OpenAIService,
 	providerManager ProviderManager,
+	quotaService QuotaService,
+	budgets BudgetService,
+	preferences UserPreferenceService,
+	projects ProjectService,
+	modelMetadata ModelMetadataLookup,
+	usageMetrics AIUsageRecorder,
+	events webhook.Publisher,
+	experiments ExperimentAssigner,
+	responses ResponseRecorder,
+	personas PersonaResolver,
+	memories MemoryKeeper,
 	logger *zap.Logger,
 ) *AIAssistantService {
 	return &AIAssistantService{
 		mcpClient:       mcpClient,
 		openaiService:   openaiService,
 		providerManager: providerManager,
+		quotaService:    quotaService,
+		budgets:         budgets,
+		preferences:     preferences,
+		projects:        projects,
+		modelMetadata:   modelMetadata,
+		usageMetrics:    usageMetrics,
+		events:          events,
+		experiments:     experiments,
+		responses:       responses,
+		personas:        personas,
+		memories:        memories,
 		logger:          logger,
 	}
 }
 
 // ChatRequest AI助手聊天请求
 type ChatRequest struct {
-	Messages     []openai.Message `json:"messages"`
-	Model        string           `json:"model,omitempty"`
-	MaxTokens    *int             `json:"max_tokens,omitempty"`
-	Temperature  *float32         `json:"temperature,omitempty"`
-	UseTools     bool             `json:"use_tools,omitempty"`
-	Provider     string           `json:"provider,omitempty"`     // 指定提供商
-	SelectedTool string           `json:"selected_tool,omitempty"` // 指定要使用的工具
+	Messages      []openai.Message     `json:"messages"`
+	Model         string               `json:"model,omitempty"`
+	MaxTokens     *int                 `json:"max_tokens,omitempty"`
+	Temperature   *float32             `json:"temperature,omitempty"`
+	UseTools      bool                 `json:"use_tools,omitempty"`
+	Provider      string               `json:"provider,omitempty"`      // 指定提供商
+	SelectedTool  string               `json:"selected_tool,omitempty"` // 指定要使用的工具
+	Project       string               `json:"project,omitempty"`       // 指定项目，使用项目的API密钥并按项目统计用量
+	Persona       string               `json:"persona,omitempty"`       // 助手角色，为空时为 default，角色有运行中的 A/B 实验时按实验变体覆盖请求
+	UserID        int64                `json:"-"`                       // 请求用户，0 表示匿名，用于配额统计
+	ProjectID     int64                `json:"-"`                       // 由 Project 解析得到，0 表示使用默认密钥
+	PersonaConfig *dto.PersonaResponse `json:"-"`                       // 由 Persona 解析得到，角色未配置时为空
+	Memories      []string             `json:"-"`                       // 召回的用户记忆，发送前追加到系统消息
 }
 
 // ChatResponse AI助手聊天响应
 type ChatResponse struct {
-	ID      string                `json:"id"`
-	Object  string                `json:"object"`
-	Created int64                 `json:"created"`
-	Model   string                `json:"model"`
-	Choices []ChatChoice          `json:"choices"`
-	Usage   openai.Usage          `json:"usage"`
+	ID       string       `json:"id"`
+	Object   string       `json:"object"`
+	Created  int64        `json:"created"`
+	Model    string       `json:"model"`
+	Choices  []ChatChoice `json:"choices"`
+	Usage    openai.Usage `json:"usage"`
+	Provider string       `json:"-"` // 实际处理请求的提供商，用于用量指标
 }
 
 // ChatChoice 聊天选择
@@ -99,86 +158,480 @@ type ToolCallExecution struct {
 	ExecutionID string                 `json:"execution_id,omitempty"`
 }
 
-// Chat 进行AI对话，支持动态提供商选择和工具调用
+// ChatStreamChunk 流式聊天的一段增量回复
+type ChatStreamChunk struct {
+	ID           string              `json:"id"`
+	Model        string              `json:"model"`
+	Provider     string              `json:"provider"`
+	Content      string              `json:"content,omitempty"`
+	FinishReason string              `json:"finish_reason,omitempty"`
+	ToolCalls    []ToolCallExecution `json:"tool_calls,omitempty"`
+}
+
+// Chat 进行AI对话，请求前检查用户配额并应用用户偏好，成功后记录用户和项目用量及用量指标
 func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	s.logger.Info("AI assistant chat request",
-		zap.String("model", req.Model),
-		zap.String("provider", req.Provider),
-		zap.Int("message_count", len(req.Messages)),
-		zap.Bool("use_tools", req.UseTools),
-		zap.String("selected_tool", req.SelectedTool))
+	if err := s.prepareChat(ctx, req); err != nil {
+		return nil, err
+	}
 
-	// 1. 动态提供商选择和模型验证
-	var provider ProviderInterface
-	var err error
-	
-	if req.Provider != "" {
-		// 如果明确指定了提供商，尝试通过提供商名称获取
-		s.logger.Info("Using explicitly specified provider", zap.String("provider", req.Provider))
-		provider, err = s.providerManager.GetProviderByName(req.Provider)
+	assignment := s.assignExperiment(ctx, req)
+	start := time.Now()
+	resp, err := s.chat(ctx, req)
+	if err == nil && resp.ID == "" {
+		resp.ID = newCompletionID()
+	}
+	s.recordExperimentOutcome(ctx, assignment, req, resp, err, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUsage(ctx, req, resp)
+	s.rememberResponse(req, resp)
+	s.learnMemories(ctx, req, resp)
+	return resp, nil
+}
+
+// ChatStream 进行流式AI对话，每收到一段增量回复调用一次 send，send 返回错误时停止。
+// 使用工具或提供商不支持流式时整段回复作为一个分块返回。
+// 流式响应不含令牌用量，结束后按提示和回复长度估算用量并记录。
+func (s *AIAssistantService) ChatStream(ctx context.Context, req *ChatRequest, send func(*ChatStreamChunk) error) (err error) {
+	if err := s.prepareChat(ctx, req); err != nil {
+		return err
+	}
+
+	assignment := s.assignExperiment(ctx, req)
+	start := time.Now()
+	var resp *ChatResponse
+	defer func() {
+		s.recordExperimentOutcome(ctx, assignment, req, resp, err, time.Since(start))
+		if err == nil {
+			s.rememberResponse(req, resp)
+			s.learnMemories(ctx, req, resp)
+		}
+	}()
+
+	provider, err := s.selectProvider(ctx, req)
+	streaming, ok := provider.(StreamingProvider)
+	if err != nil || !ok || req.UseTools || req.SelectedTool != "" {
+		if resp, err = s.chat(ctx, req); err != nil {
+			return err
+		}
+		if resp.ID == "" {
+			resp.ID = newCompletionID()
+		}
+		if len(resp.Choices) == 0 {
+			return fmt.Errorf("no response from provider")
+		}
+		s.recordUsage(ctx, req, resp)
+		choice := resp.Choices[0]
+		return send(&ChatStreamChunk{
+			ID:           resp.ID,
+			Model:        resp.Model,
+			Provider:     resp.Provider,
+			Content:      choice.Message.Content,
+			FinishReason: choice.FinishReason,
+			ToolCalls:    choice.ToolCalls,
+		})
+	}
+
+	ctx, err = s.projectContext(ctx, req, provider)
+	if err != nil {
+		return err
+	}
+	if err := s.checkBudget(ctx, req, provider.GetType()); err != nil {
+		return err
+	}
+
+	providerReq := &ProviderChatRequest{
+		Model:       req.Model,
+		Messages:    s.fitContextWindow(provider.GetType(), req.Model, withMemoryContext(toProviderMessages(req.Messages), req.Memories), req.MaxTokens),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      true,
+	}
+	stream, err := streaming.ChatCompletionStream(ctx, providerReq)
+	if err != nil {
+		return s.providerError(err)
+	}
+	reader := openai.NewStreamReader(stream)
+	defer reader.Close()
+
+	resp = &ChatResponse{Model: req.Model, Provider: provider.GetType()}
+	var content strings.Builder
+	for {
+		chunk, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			s.logger.Error("Failed to get provider by name", 
-				zap.String("provider", req.Provider), zap.Error(err))
-			return nil, fmt.Errorf("provider %s not found", req.Provider)
+			s.logger.Error("Provider chat stream failed", zap.Error(err))
+			return fmt.Errorf("provider chat stream failed: %w", err)
 		}
-		
-		// 验证模型是否存在于指定的提供商中
-		if req.Model != "" {
-			if validateErr := s.providerManager.ValidateModelForProvider(ctx, req.Provider, req.Model); validateErr != nil {
-				s.logger.Error("Model validation failed", 
-					zap.String("provider", req.Provider),
-					zap.String("model", req.Model),
-					zap.Error(validateErr))
-				return nil, fmt.Errorf("model %s not supported by provider %s", req.Model, req.Provider)
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if resp.ID == "" {
+			resp.ID = chunk.ID
+		}
+
+		for _, choice := range chunk.Choices {
+			out := &ChatStreamChunk{ID: chunk.ID, Model: resp.Model, Provider: resp.Provider, Content: choice.Delta.Content}
+			if choice.FinishReason != nil {
+				out.FinishReason = *choice.FinishReason
+			}
+			if out.Content == "" && out.FinishReason == "" {
+				continue
+			}
+			content.WriteString(out.Content)
+			if err := send(out); err != nil {
+				return err
 			}
 		}
-	} else {
-		// 根据模型名称自动选择提供商（使用验证版本）
-		if req.Model != "" {
-			provider, err = s.providerManager.GetProviderByModelWithValidation(ctx, req.Model)
-			if err != nil {
-				s.logger.Warn("Failed to find provider with model validation, falling back to prefix matching", 
-					zap.String("model", req.Model), zap.Error(err))
-				// 回退到原有的前缀匹配方式
-				provider, err = s.providerManager.GetProviderByModel(req.Model)
+	}
+
+	resp.Choices = []ChatChoice{{Message: openai.Message{Role: "assistant", Content: content.String()}}}
+	resp.Usage = estimateUsage(providerReq.Messages, content.String())
+	s.recordUsage(ctx, req, resp)
+	return nil
+}
+
+// prepareChat 解析项目、检查用户配额并应用助手角色和用户偏好，角色的默认模型和参数优先于用户偏好，
+// 最后召回与最新消息相关的用户记忆
+func (s *AIAssistantService) prepareChat(ctx context.Context, req *ChatRequest) error {
+	if req.Project != "" && s.projects != nil {
+		projectID, err := s.projects.Resolve(ctx, req.UserID, req.Project)
+		if err != nil {
+			return err
+		}
+		req.ProjectID = projectID
+	}
+
+	if s.quotaService != nil {
+		if err := s.quotaService.Check(ctx, req.UserID); err != nil {
+			return err
+		}
+	}
+
+	if s.personas != nil {
+		persona, err := s.personas.Resolve(ctx, req.Persona)
+		if err != nil {
+			return err
+		}
+		if persona != nil {
+			if err := applyPersona(req, persona, time.Now()); err != nil {
+				return err
 			}
-		} else {
-			// 如果没有指定模型，使用Mock提供商作为默认提供商
-			s.logger.Info("No model specified, using default mock provider")
-			provider, err = s.providerManager.GetProviderByName("mock")
-			if err != nil {
-				s.logger.Warn("Failed to get mock provider, falling back to mock-gpt-3.5-turbo", zap.Error(err))
-				provider, err = s.providerManager.GetProviderByModel("mock-gpt-3.5-turbo") // 回退到免费的mock模型
-			} else {
-				// 为Mock提供商设置默认模型
-				if req.Model == "" {
-					req.Model = "mock-gpt-3.5-turbo"
+		}
+	}
+
+	if s.preferences != nil && req.UserID > 0 {
+		prefs, err := s.preferences.Get(ctx, req.UserID)
+		if err != nil {
+			s.logger.Warn("Failed to load user preferences", zap.Int64("user_id", req.UserID), zap.Error(err))
+		}
+		applyChatPreferences(req, prefs)
+	}
+
+	if s.memories != nil && req.UserID > 0 {
+		recalled, err := s.memories.Recall(ctx, req.UserID, lastUserMessage(req.Messages))
+		if err != nil {
+			s.logger.Warn("Failed to recall user memories", zap.Int64("user_id", req.UserID), zap.Error(err))
+		}
+		req.Memories = recalled
+	}
+	return nil
+}
+
+// assignExperiment 为请求分配角色正在运行的 A/B 实验变体并覆盖请求，查询失败时不参与实验
+func (s *AIAssistantService) assignExperiment(ctx context.Context, req *ChatRequest) *ExperimentAssignment {
+	if s.experiments == nil {
+		return nil
+	}
+	assignment, err := s.experiments.Assign(ctx, req.Persona, req.UserID)
+	if err != nil {
+		s.logger.Warn("Failed to assign experiment variant", zap.String("persona", req.Persona), zap.Error(err))
+		return nil
+	}
+	if assignment == nil {
+		return nil
+	}
+	applyExperimentVariant(req, assignment.Config)
+	return assignment
+}
+
+// recordExperimentOutcome 记录参与实验的请求的延迟、工具调用和是否成功。
+// 配额、预算、权限等业务错误和客户端取消与变体无关，不记录
+func (s *AIAssistantService) recordExperimentOutcome(ctx context.Context, assignment *ExperimentAssignment, req *ChatRequest, resp *ChatResponse, err error, latency time.Duration) {
+	if assignment == nil {
+		return
+	}
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok || stderrors.Is(err, context.Canceled) {
+			return
+		}
+	}
+
+	outcome := ExperimentOutcome{
+		ExperimentID: assignment.ExperimentID,
+		Variant:      assignment.Variant,
+		UserID:       req.UserID,
+		Success:      err == nil,
+		LatencyMs:    latency.Milliseconds(),
+	}
+	if resp != nil {
+		outcome.ResponseID = resp.ID
+		for _, choice := range resp.Choices {
+			for _, call := range choice.ToolCalls {
+				outcome.ToolCalls++
+				if call.Error != "" || (call.Result != nil && call.Result.IsError) {
+					outcome.ToolFailures++
 				}
 			}
 		}
 	}
-	
+	if err := s.experiments.RecordOutcome(context.WithoutCancel(ctx), outcome); err != nil {
+		s.logger.Warn("Failed to record experiment outcome",
+			zap.Int64("experiment_id", assignment.ExperimentID),
+			zap.Error(err))
+	}
+}
+
+// rememberResponse 保存回复及请求消息，供用户之后评价
+func (s *AIAssistantService) rememberResponse(req *ChatRequest, resp *ChatResponse) {
+	if s.responses == nil || resp == nil {
+		return
+	}
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	reply := ""
+	if len(resp.Choices) > 0 {
+		reply = resp.Choices[0].Message.Content
+	}
+	s.responses.RememberResponse(ServedResponse{
+		ID:       resp.ID,
+		UserID:   req.UserID,
+		Provider: resp.Provider,
+		Model:    model,
+		Persona:  NormalizePersona(req.Persona),
+		Messages: req.Messages,
+		Reply:    reply,
+	})
+}
+
+// learnMemories 从最后一条用户消息中提取用户记忆，失败时只记录日志
+func (s *AIAssistantService) learnMemories(ctx context.Context, req *ChatRequest, resp *ChatResponse) {
+	if s.memories == nil || req.UserID <= 0 || resp == nil {
+		return
+	}
+	message := lastUserMessage(req.Messages)
+	if message == "" {
+		return
+	}
+	if err := s.memories.Learn(ctx, req.UserID, message, resp.ID); err != nil {
+		s.logger.Warn("Failed to learn user memories", zap.Int64("user_id", req.UserID), zap.Error(err))
+	}
+}
+
+// recordUsage 记录用户和项目用量及用量指标并发布对话完成事件，失败时只记录日志
+func (s *AIAssistantService) recordUsage(ctx context.Context, req *ChatRequest, resp *ChatResponse) {
+	if s.quotaService != nil {
+		if err := s.quotaService.Record(ctx, req.UserID, resp.Usage.TotalTokens); err != nil {
+			s.logger.Warn("Failed to record AI usage",
+				zap.Int64("user_id", req.UserID),
+				zap.Error(err))
+		}
+	}
+	if req.ProjectID > 0 {
+		if err := s.projects.RecordUsage(ctx, req.ProjectID, resp.Usage.TotalTokens); err != nil {
+			s.logger.Warn("Failed to record project AI usage",
+				zap.Int64("project_id", req.ProjectID),
+				zap.Error(err))
+		}
+	}
+	if s.budgets != nil {
+		model := resp.Model
+		if model == "" {
+			model = req.Model
+		}
+		if err := s.budgets.Record(ctx, budgetRequest(req, resp.Provider), model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens); err != nil {
+			s.logger.Warn("Failed to record AI spend",
+				zap.Int64("user_id", req.UserID),
+				zap.Error(err))
+		}
+	}
+	s.recordUsageMetrics(req, resp)
+	s.publishChatCompleted(ctx, req, resp)
+}
+
+// checkBudget 选定提供商后检查用户、项目和提供商的费用预算
+func (s *AIAssistantService) checkBudget(ctx context.Context, req *ChatRequest, providerType string) error {
+	if s.budgets == nil {
+		return nil
+	}
+	return s.budgets.Check(ctx, budgetRequest(req, providerType))
+}
+
+// budgetRequest 构建聊天请求对应的预算请求
+func budgetRequest(req *ChatRequest, providerType string) BudgetRequest {
+	return BudgetRequest{
+		UserID:    req.UserID,
+		ProjectID: req.ProjectID,
+		Project:   req.Project,
+		Provider:  providerType,
+	}
+}
+
+// publishChatCompleted 发布对话完成事件
+func (s *AIAssistantService) publishChatCompleted(ctx context.Context, req *ChatRequest, resp *ChatResponse) {
+	if s.events == nil {
+		return
+	}
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	s.events.Publish(ctx, webhook.EventChatCompleted, webhook.ChatCompletedData{
+		UserID:           req.UserID,
+		ProjectID:        req.ProjectID,
+		Provider:         resp.Provider,
+		Model:            model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		TotalTokens:      resp.Usage.TotalTokens,
+	})
+}
+
+// estimateTokens 按约4个字符一个令牌估算文本的令牌数
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// estimateUsage 估算流式请求的令牌用量
+func estimateUsage(messages []ProviderMessage, completion string) openai.Usage {
+	var usage openai.Usage
+	for _, msg := range messages {
+		usage.PromptTokens += estimateTokens(msg.Content)
+	}
+	usage.CompletionTokens = estimateTokens(completion)
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
+
+// fitContextWindow 估算的提示令牌数加上 max_tokens 超过模型上下文窗口时，从最早的非系统消息开始丢弃，
+// 系统消息和最后一条消息始终保留；未设置上下文窗口的模型不截断
+func (s *AIAssistantService) fitContextWindow(providerType, model string, messages []ProviderMessage, maxTokens *int) []ProviderMessage {
+	contextWindow := s.contextWindow(providerType, model)
+	kept, dropped := trimToContextWindow(messages, contextWindow, maxTokens)
+	if dropped > 0 {
+		s.logger.Info("Dropped oldest messages to fit the model context window",
+			zap.String("provider", providerType),
+			zap.String("model", model),
+			zap.Int("context_window", contextWindow),
+			zap.Int("dropped", dropped))
+	}
+	return kept
+}
+
+// contextWindow 返回模型的上下文窗口令牌数，未知时返回 0
+func (s *AIAssistantService) contextWindow(providerType, model string) int {
+	if s.modelMetadata == nil {
+		return 0
+	}
+	metadata, ok := s.modelMetadata.Get(providerType, model)
+	if !ok {
+		return 0
+	}
+	return metadata.ContextWindow
+}
+
+// trimToContextWindow 按上下文窗口丢弃最早的非系统消息，返回保留的消息和丢弃的条数
+func trimToContextWindow(messages []ProviderMessage, contextWindow int, maxTokens *int) ([]ProviderMessage, int) {
+	if contextWindow <= 0 || len(messages) < 2 {
+		return messages, 0
+	}
+
+	budget := contextWindow
+	if maxTokens != nil {
+		budget -= *maxTokens
+	}
+	total := 0
+	for _, msg := range messages {
+		total += estimateTokens(msg.Content)
+	}
+	if total <= budget {
+		return messages, 0
+	}
+
+	kept := make([]ProviderMessage, 0, len(messages))
+	dropped := 0
+	last := len(messages) - 1
+	for i, msg := range messages {
+		if total > budget && i < last && msg.Role != "system" {
+			total -= estimateTokens(msg.Content)
+			dropped++
+			continue
+		}
+		kept = append(kept, msg)
+	}
+	return kept, dropped
+}
+
+// recordUsageMetrics 按提供商、模型、用户和项目记录令牌用量指标
+func (s *AIAssistantService) recordUsageMetrics(req *ChatRequest, resp *ChatResponse) {
+	if s.usageMetrics == nil {
+		return
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	user := "anonymous"
+	if req.UserID > 0 {
+		user = strconv.FormatInt(req.UserID, 10)
+	}
+	project := ""
+	if req.ProjectID > 0 {
+		project = strings.ToLower(strings.TrimSpace(req.Project))
+	}
+	s.usageMetrics.RecordChat(resp.Provider, model, user, project, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+}
+
+// chat 进行AI对话，支持动态提供商选择和工具调用
+func (s *AIAssistantService) chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	s.logger.Info("AI assistant chat request",
+		zap.String("model", req.Model),
+		zap.String("provider", req.Provider),
+		zap.Int("message_count", len(req.Messages)),
+		zap.Bool("use_tools", req.UseTools),
+		zap.String("selected_tool", req.SelectedTool))
+
+	// 1. 动态提供商选择和模型验证
+	provider, err := s.selectProvider(ctx, req)
 	if err != nil {
 		s.logger.Error("Failed to get provider", zap.Error(err))
+		// 项目请求不能回退到共享密钥
+		if req.ProjectID > 0 {
+			return nil, err
+		}
 		// 回退到原有的OpenAI实现
 		return s.chatWithOpenAI(ctx, req)
 	}
 
+	ctx, err = s.projectContext(ctx, req, provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkBudget(ctx, req, provider.GetType()); err != nil {
+		return nil, err
+	}
+
 	// 2. 工具过滤和获取
-	var availableTools []dto.MCPTool
-	if req.UseTools || req.SelectedTool != "" {
-		toolsResp, err := s.mcpClient.ListTools(ctx)
-		if err != nil {
-			s.logger.Error("Failed to get available tools", zap.Error(err))
-			return nil, fmt.Errorf("failed to get available tools: %w", err)
-		}
-		
-		// 根据SelectedTool过滤工具
-		if req.SelectedTool != "" {
-			availableTools = s.filterTool(toolsResp.Tools, req.SelectedTool)
-		} else {
-			availableTools = toolsResp.Tools
-		}
+	availableTools, err := s.requestTools(ctx, req.UseTools, req.SelectedTool, req.PersonaConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. 使用动态选择的提供商进行聊天
@@ -188,33 +641,15 @@ func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatR
 		zap.Int("available_tools", len(availableTools)))
 
 	// 构建提供商聊天请求
-	providerMessages := make([]ProviderMessage, len(req.Messages))
-	for i, msg := range req.Messages {
-		providerMessages[i] = ProviderMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
+	providerMessages := toProviderMessages(req.Messages)
 
 	// 检查是否需要添加工具信息到系统消息
-	if len(availableTools) > 0 {
-		toolsInfo := s.buildToolsSystemMessage(availableTools)
-		systemMsg := ProviderMessage{
-			Role:    "system",
-			Content: toolsInfo,
-		}
-		
-		// 如果第一条消息已经是系统消息，则替换；否则添加到开头
-		if len(providerMessages) > 0 && providerMessages[0].Role == "system" {
-			providerMessages[0] = systemMsg
-		} else {
-			providerMessages = append([]ProviderMessage{systemMsg}, providerMessages...)
-		}
-	}
+	providerMessages = s.withToolsSystemMessage(providerMessages, availableTools, req.PersonaConfig)
+	providerMessages = withMemoryContext(providerMessages, req.Memories)
 
 	providerReq := &ProviderChatRequest{
 		Model:       req.Model,
-		Messages:    providerMessages,
+		Messages:    s.fitContextWindow(provider.GetType(), req.Model, providerMessages, req.MaxTokens),
 		MaxTokens:   req.MaxTokens,
 		Temperature: req.Temperature,
 	}
@@ -222,8 +657,7 @@ func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatR
 	// 调用提供商
 	providerResp, err := provider.ChatCompletion(ctx, providerReq)
 	if err != nil {
-		s.logger.Error("Provider chat failed", zap.Error(err))
-		return nil, fmt.Errorf("provider chat failed: %w", err)
+		return nil, s.providerError(err)
 	}
 
 	// 转换响应格式
@@ -252,6 +686,7 @@ func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatR
 			CompletionTokens: providerResp.Usage.CompletionTokens,
 			TotalTokens:      providerResp.Usage.TotalTokens,
 		},
+		Provider: provider.GetType(),
 	}
 
 	// 4. 处理工具调用（如果需要）
@@ -263,7 +698,11 @@ func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatR
 			
 			executions := make([]ToolCallExecution, 0, len(toolCalls))
 			for _, toolCall := range toolCalls {
-				execution := s.executeToolCall(ctx, toolCall)
+				// 请求已超时或客户端已断开时不再执行剩余工具
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				execution := s.executeToolCall(ctx, req.UserID, toolCall)
 				executions = append(executions, execution)
 			}
 			
@@ -284,6 +723,136 @@ func (s *AIAssistantService) Chat(ctx context.Context, req *ChatRequest) (*ChatR
 	return response, nil
 }
 
+// requestTools 获取请求可用的工具，指定 selectedTool 时只保留该工具，配置了角色时只保留角色允许的工具
+func (s *AIAssistantService) requestTools(ctx context.Context, useTools bool, selectedTool string, persona *dto.PersonaResponse) ([]dto.MCPTool, error) {
+	if !useTools && selectedTool == "" {
+		return nil, nil
+	}
+	if persona != nil {
+		if selectedTool != "" && !personaAllowsTool(persona, selectedTool) {
+			return nil, errors.NewValidationError(fmt.Sprintf("角色 %s 不能使用工具 %s", persona.Name, selectedTool))
+		}
+		if len(persona.AllowedTools) == 0 {
+			return nil, nil
+		}
+	}
+	toolsResp, err := s.mcpClient.ListTools(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get available tools", zap.Error(err))
+		return nil, fmt.Errorf("failed to get available tools: %w", err)
+	}
+	if selectedTool != "" {
+		return s.filterTool(toolsResp.Tools, selectedTool), nil
+	}
+	return allowedPersonaTools(toolsResp.Tools, persona), nil
+}
+
+// withToolsSystemMessage 把工具说明作为系统消息：第一条消息已是系统消息时替换，否则添加到开头。
+// 配置了角色时保留角色的系统提示，只在其后追加通用的工具调用说明
+func (s *AIAssistantService) withToolsSystemMessage(messages []ProviderMessage, tools []dto.MCPTool, persona *dto.PersonaResponse) []ProviderMessage {
+	if len(tools) == 0 {
+		return messages
+	}
+	systemMsg := ProviderMessage{
+		Role:    "system",
+		Content: s.buildToolsSystemMessage(tools),
+	}
+	if persona != nil && len(messages) > 0 && messages[0].Role == "system" {
+		systemMsg.Content = messages[0].Content + "\n\n" + s.buildToolInstructions(tools)
+	}
+	if len(messages) > 0 && messages[0].Role == "system" {
+		messages[0] = systemMsg
+		return messages
+	}
+	return append([]ProviderMessage{systemMsg}, messages...)
+}
+
+// selectProvider 按指定的提供商或模型选择提供商，都未指定时使用Mock提供商
+func (s *AIAssistantService) selectProvider(ctx context.Context, req *ChatRequest) (ProviderInterface, error) {
+	var provider ProviderInterface
+	var err error
+	
+	if req.Provider != "" {
+		// 如果明确指定了提供商，尝试通过提供商名称获取
+		s.logger.Info("Using explicitly specified provider", zap.String("provider", req.Provider))
+		provider, err = s.providerManager.GetProviderByName(req.Provider)
+		if err != nil {
+			s.logger.Error("Failed to get provider by name", 
+				zap.String("provider", req.Provider), zap.Error(err))
+			return nil, fmt.Errorf("provider %s not found", req.Provider)
+		}
+		
+		// 验证模型是否存在于指定的提供商中
+		if req.Model != "" {
+			if validateErr := s.providerManager.ValidateModelForProvider(ctx, req.Provider, req.Model); validateErr != nil {
+				s.logger.Error("Model validation failed", 
+					zap.String("provider", req.Provider),
+					zap.String("model", req.Model),
+					zap.Error(validateErr))
+				return nil, fmt.Errorf("model %s not supported by provider %s", req.Model, req.Provider)
+			}
+		}
+	} else {
+		// 根据模型名称自动选择提供商（使用验证版本）
+		if req.Model != "" {
+			provider, err = s.providerManager.GetProviderByModelWithValidation(ctx, req.Model)
+			if err != nil {
+				s.logger.Warn("Failed to find provider with model validation, falling back to prefix matching", 
+					zap.String("model", req.Model), zap.Error(err))
+				// 回退到原有的前缀匹配方式
+				provider, err = s.providerManager.GetProviderByModel(req.Model)
+			}
+		} else {
+			// 如果没有指定模型，使用Mock提供商作为默认提供商
+			s.logger.Info("No model specified, using default mock provider")
+			provider, err = s.providerManager.GetProviderByName("mock")
+			if err != nil {
+				s.logger.Warn("Failed to get mock provider, falling back to mock-gpt-3.5-turbo", zap.Error(err))
+				provider, err = s.providerManager.GetProviderByModel("mock-gpt-3.5-turbo") // 回退到免费的mock模型
+			} else {
+				// 为Mock提供商设置默认模型
+				if req.Model == "" {
+					req.Model = "mock-gpt-3.5-turbo"
+				}
+			}
+		}
+	}
+	return provider, err
+}
+
+// projectContext 项目请求使用项目自己的上游密钥
+func (s *AIAssistantService) projectContext(ctx context.Context, req *ChatRequest, provider ProviderInterface) (context.Context, error) {
+	if req.ProjectID == 0 || provider.GetType() == "mock" {
+		return ctx, nil
+	}
+	key, allowedModels, err := s.projects.APIKey(ctx, req.UserID, req.ProjectID, provider.GetType())
+	if err != nil {
+		return nil, err
+	}
+	return types.WithAllowedModels(types.WithAPIKey(ctx, key), allowedModels), nil
+}
+
+// providerError 转换提供商调用错误，密钥不允许的模型返回禁止访问
+func (s *AIAssistantService) providerError(err error) error {
+	if stderrors.Is(err, types.ErrModelNotAllowed) {
+		return errors.NewForbiddenError("API密钥不允许访问该模型").WithDetails(err.Error())
+	}
+	s.logger.Error("Provider chat failed", zap.Error(err))
+	return fmt.Errorf("provider chat failed: %w", err)
+}
+
+// toProviderMessages 转换为提供商消息格式
+func toProviderMessages(messages []openai.Message) []ProviderMessage {
+	providerMessages := make([]ProviderMessage, len(messages))
+	for i, msg := range messages {
+		providerMessages[i] = ProviderMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+	return providerMessages
+}
+
 // filterTools 根据选择的工具名称过滤工具列表
 func (s *AIAssistantService) filterTools(allTools []dto.MCPTool, selectedTools []string) []dto.MCPTool {
 	if len(selectedTools) == 0 {
@@ -335,22 +904,14 @@ func (s *AIAssistantService) filterTool(allTools []dto.MCPTool, selectedTool str
 // chatWithOpenAI 回退到原有的OpenAI实现（向后兼容）
 func (s *AIAssistantService) chatWithOpenAI(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
 	s.logger.Info("Falling back to OpenAI implementation")
+	if err := s.checkBudget(ctx, req, "openai"); err != nil {
+		return nil, err
+	}
 	
 	// 如果启用工具或指定了工具，先获取可用工具列表
-	var availableTools []dto.MCPTool
-	if req.UseTools || req.SelectedTool != "" {
-		toolsResp, err := s.mcpClient.ListTools(ctx)
-		if err != nil {
-			s.logger.Error("Failed to get available tools", zap.Error(err))
-			return nil, fmt.Errorf("failed to get available tools: %w", err)
-		}
-		
-		// 根据SelectedTool过滤工具
-		if req.SelectedTool != "" {
-			availableTools = s.filterTool(toolsResp.Tools, req.SelectedTool)
-		} else {
-			availableTools = toolsResp.Tools
-		}
+	availableTools, err := s.requestTools(ctx, req.UseTools, req.SelectedTool, req.PersonaConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	// 构建OpenAI请求
@@ -364,8 +925,20 @@ func (s *AIAssistantService) chatWithOpenAI(ctx context.Context, req *ChatReques
 	// 如果有可用工具，添加工具信息到系统消息
 	if len(availableTools) > 0 {
 		toolsInfo := s.buildToolsSystemMessage(availableTools)
+		// 配置了角色时保留角色的系统提示，只追加工具调用说明
+		if req.PersonaConfig != nil && len(openaiReq.Messages) > 0 && openaiReq.Messages[0].Role == "system" {
+			toolsInfo = openaiReq.Messages[0].Content + "\n\n" + s.buildToolInstructions(availableTools)
+		}
 		openaiReq.Messages = s.addSystemMessage(openaiReq.Messages, toolsInfo)
 	}
+	// 用户记忆追加到系统消息，复制消息以免修改原请求
+	if memory := memoryContext(req.Memories); memory != "" {
+		messages := append([]openai.Message(nil), openaiReq.Messages...)
+		if len(messages) > 0 && messages[0].Role == "system" {
+			memory = messages[0].Content + "\n\n" + memory
+		}
+		openaiReq.Messages = s.addSystemMessage(messages, memory)
+	}
 
 	// 调用OpenAI
 	openaiResp, err := s.openaiService.ChatCompletion(ctx, openaiReq)
@@ -391,7 +964,8 @@ func (s *AIAssistantService) chatWithOpenAI(ctx context.Context, req *ChatReques
 				FinishReason: choice.FinishReason,
 			},
 		},
-		Usage: openaiResp.Usage,
+		Usage:    openaiResp.Usage,
+		Provider: "openai",
 	}
 
 	// 检查是否需要执行工具调用
@@ -402,7 +976,11 @@ func (s *AIAssistantService) chatWithOpenAI(ctx context.Context, req *ChatReques
 			
 			executions := make([]ToolCallExecution, 0, len(toolCalls))
 			for _, toolCall := range toolCalls {
-				execution := s.executeToolCall(ctx, toolCall)
+				// 请求已超时或客户端已断开时不再执行剩余工具
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				execution := s.executeToolCall(ctx, req.UserID, toolCall)
 				executions = append(executions, execution)
 			}
 			
@@ -507,6 +1085,36 @@ func (s *AIAssistantService) buildToolsSystemMessage(tools []dto.MCPTool) string
 	builder.WriteString("Available tools:\n")
 	
 	// 工具已经在调用方过滤过了，这里直接使用
+	writeToolList(&builder, tools)
+	
+	return builder.String()
+}
+
+// buildToolInstructions 构建与角色无关的工具调用说明，追加在助手角色的系统提示之后
+func (s *AIAssistantService) buildToolInstructions(tools []dto.MCPTool) string {
+	var builder strings.Builder
+	builder.WriteString("## Tool Usage Instructions\n")
+	builder.WriteString("Use a tool when the answer depends on data or actions the tool provides. Otherwise answer directly.\n\n")
+
+	builder.WriteString("### Tool Call Format\n")
+	builder.WriteString("When you need to use a tool, respond with a JSON object in this exact format:\n")
+	builder.WriteString("```json\n")
+	builder.WriteString(`{"tool_call": {"name": "tool_name", "arguments": {...}}}`)
+	builder.WriteString("\n```\n\n")
+
+	builder.WriteString("### Critical Guidelines\n")
+	builder.WriteString("- **One tool per response**: Never call multiple tools simultaneously\n")
+	builder.WriteString("- **Single line JSON**: Provide the tool_call JSON in exactly one line\n")
+	builder.WriteString("- **Complete arguments**: Include all required parameters with valid values\n")
+	builder.WriteString("- **Be transparent**: If a tool fails, say which data is missing\n\n")
+
+	builder.WriteString("Available tools:\n")
+	writeToolList(&builder, tools)
+	return builder.String()
+}
+
+// writeToolList 写入工具的名称、描述和参数结构
+func writeToolList(builder *strings.Builder, tools []dto.MCPTool) {
 	for _, tool := range tools {
 		builder.WriteString(fmt.Sprintf("### %s\n", tool.Name))
 		builder.WriteString(fmt.Sprintf("Description: %s\n", tool.Description))
@@ -514,8 +1122,6 @@ func (s *AIAssistantService) buildToolsSystemMessage(tools []dto.MCPTool) string
 			builder.WriteString(fmt.Sprintf("Schema: %s\n\n", string(schemaBytes)))
 		}
 	}
-	
-	return builder.String()
 }
 
 // addSystemMessage 添加系统消息
@@ -783,7 +1389,7 @@ func (s *AIAssistantService) truncateString(str string, maxLen int) string {
 }
 
 // executeToolCall 执行工具调用
-func (s *AIAssistantService) executeToolCall(ctx context.Context, toolCall ToolCall) ToolCallExecution {
+func (s *AIAssistantService) executeToolCall(ctx context.Context, userID int64, toolCall ToolCall) ToolCallExecution {
 	execution := ToolCallExecution{
 		ToolName:  toolCall.Name,
 		Arguments: toolCall.Arguments,
@@ -795,7 +1401,7 @@ func (s *AIAssistantService) executeToolCall(ctx context.Context, toolCall ToolC
 		Arguments: toolCall.Arguments,
 	}
 	
-	result, err := s.executeToolWithRetry(ctx, mcpReq, toolCall.Name)
+	result, err := s.executeToolWithRetry(ctx, userID, mcpReq, toolCall.Name)
 	if err != nil {
 		execution.Error = err.Error()
 		s.logger.Error("Tool execution failed after retries",
@@ -811,8 +1417,8 @@ func (s *AIAssistantService) executeToolCall(ctx context.Context, toolCall ToolC
 	return execution
 }
 
-// executeToolWithRetry 执行工具调用，带有超时控制和重试机制
-func (s *AIAssistantService) executeToolWithRetry(ctx context.Context, req *dto.MCPExecuteRequest, toolName string) (*dto.MCPExecuteResponse, error) {
+// executeToolWithRetry 执行工具调用，带有超时控制和重试机制，userID 为发起对话的用户，0 表示匿名
+func (s *AIAssistantService) executeToolWithRetry(ctx context.Context, userID int64, req *dto.MCPExecuteRequest, toolName string) (*dto.MCPExecuteResponse, error) {
 	const (
 		maxRetries = 3
 		baseDelay  = 1 * time.Second
@@ -820,6 +1426,11 @@ func (s *AIAssistantService) executeToolWithRetry(ctx context.Context, req *dto.
 		timeout    = 30 * time.Second
 	)
 	
+	// 工具按调用者校验权限和隔离数据，与直接调用 MCP 接口时一致
+	if userID > 0 {
+		ctx = WithCaller(ctx, strconv.FormatInt(userID, 10))
+	}
+
 	var lastErr error
 	
 	for attempt := 0; attempt < maxRetries; attempt++ {
@@ -860,6 +1471,11 @@ func (s *AIAssistantService) executeToolWithRetry(ctx context.Context, req *dto.
 		
 		lastErr = err
 		
+		// 请求上下文已结束时不再重试
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		
 		// 检查是否应该重试
 		if !s.shouldRetryError(err) {
 			s.logger.Warn("Error is not retryable, stopping attempts",
@@ -996,12 +1612,14 @@ func (s *AIAssistantService) generateFinalResponse(ctx context.Context, provider
 	// 构建提供商请求的消息格式
 	providerMessages := make([]ProviderMessage, 0, len(originalReq.Messages)+3)
 	
-	// 添加系统消息，定义分析师角色
-	systemPrompt := s.buildAnalysisSystemPrompt(successCount, errorCount)
-	providerMessages = append(providerMessages, ProviderMessage{
-		Role:    "system",
-		Content: systemPrompt,
-	})
+	// 添加系统消息，定义分析师角色；配置了助手角色时沿用原始消息中角色的系统提示
+	if originalReq.PersonaConfig == nil {
+		systemPrompt := s.buildAnalysisSystemPrompt(successCount, errorCount)
+		providerMessages = append(providerMessages, ProviderMessage{
+			Role:    "system",
+			Content: systemPrompt,
+		})
+	}
 	
 	// 转换原始消息
 	for _, msg := range originalReq.Messages {
@@ -1019,6 +1637,9 @@ func (s *AIAssistantService) generateFinalResponse(ctx context.Context, provider
 	
 	// 添加生成最终回复的详细指令
 	analysisPrompt := s.buildAnalysisPrompt(executions)
+	if originalReq.PersonaConfig != nil {
+		analysisPrompt = buildPersonaFollowUpPrompt(errorCount)
+	}
 	providerMessages = append(providerMessages, ProviderMessage{
 		Role:    "user",
 		Content: analysisPrompt,
@@ -1068,6 +1689,15 @@ func (s *AIAssistantService) buildAnalysisSystemPrompt(successCount, errorCount
 	return builder.String()
 }
 
+// buildPersonaFollowUpPrompt 构建配置了助手角色时根据工具结果生成最终回复的提示
+func buildPersonaFollowUpPrompt(errorCount int) string {
+	prompt := "Based on the tool execution results above, answer my previous message. Stay in your role and do not call tools again."
+	if errorCount > 0 {
+		prompt += " Some tools failed; say which information is missing."
+	}
+	return prompt
+}
+
 // buildAnalysisPrompt 构建分析提示
 func (s *AIAssistantService) buildAnalysisPrompt(executions []ToolCallExecution) string {
 	var builder strings.Builder
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"strings"
 	"testing"
 
+	"go-springAi/internal/types"
+
 	"go.uber.org/zap"
 )
 
@@ -187,4 +190,62 @@ type testError struct {
 
 func (e *testError) Error() string {
 	return e.msg
-}
\ No newline at end of file
+}
+
+// fixedModelMetadata 固定的模型元数据
+type fixedModelMetadata map[string]types.ModelMetadata
+
+func (m fixedModelMetadata) Get(provider, model string) (types.ModelMetadata, bool) {
+	metadata, ok := m[provider+"/"+model]
+	return metadata, ok
+}
+
+func TestFitContextWindow(t *testing.T) {
+	service := &AIAssistantService{
+		modelMetadata: fixedModelMetadata{"openai/small": {ContextWindow: 130}},
+		logger:        zap.NewNop(),
+	}
+	// 每条消息约 40 个令牌
+	text := strings.Repeat("x", 160)
+	messages := []ProviderMessage{
+		{Role: "system", Content: text},
+		{Role: "user", Content: "first " + text},
+		{Role: "assistant", Content: "second " + text},
+		{Role: "user", Content: "latest " + text},
+	}
+	maxTokens := 20
+
+	tests := []struct {
+		name      string
+		provider  string
+		model     string
+		maxTokens *int
+		want      []string
+	}{
+		{name: "Unknown model", provider: "openai", model: "large", want: []string{"system", "first", "second", "latest"}},
+		{name: "Oldest dropped", provider: "openai", model: "small", want: []string{"system", "second", "latest"}},
+		{name: "Other provider", provider: "googleai", model: "small", want: []string{"system", "first", "second", "latest"}},
+		{name: "Reply budget", provider: "openai", model: "small", maxTokens: &maxTokens, want: []string{"system", "latest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := service.fitContextWindow(tt.provider, tt.model, messages, tt.maxTokens)
+			names := make([]string, len(got))
+			for i, msg := range got {
+				names[i] = msg.Role
+				if msg.Role != "system" {
+					names[i] = strings.Fields(msg.Content)[0]
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+
+	// 最后一条消息即使超出窗口也保留
+	got := service.fitContextWindow("openai", "small", []ProviderMessage{{Role: "user", Content: strings.Repeat("x", 1000)}}, nil)
+	if len(got) != 1 {
+		t.Errorf("the latest message must be kept, got %d messages", len(got))
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/cmdsandbox"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/ratelimit"
+	"go-springAi/internal/workspace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAIAssistantShellToolUsesCaller(t *testing.T) {
+	sandbox, err := cmdsandbox.New(cmdsandbox.Config{AllowedCommands: []string{"echo"}, WorkDir: t.TempDir()})
+	require.NoError(t, err)
+	users := &memoryAuthUserRepository{users: map[int64]*dto.UserResponse{
+		1: {ID: 1, Username: "alice", IsActive: true},
+		2: {ID: 2, Username: "root", IsActive: true, IsAdmin: true},
+	}}
+	assistant, provider := newToolAssistant(t, tools.NewShellTool(sandbox, NewAdminChecker(users), zap.NewNop()))
+	args := map[string]interface{}{"command": "echo", "args": []interface{}{"hello"}}
+
+	// 管理员在对话中执行命令，权限按发起对话的用户校验
+	execution := chatToolCall(t, assistant, provider, 2, tools.ShellToolName, args)
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	assert.Contains(t, execution.Result.Content[0].Text, "hello\n")
+
+	for _, userID := range []int64{1, 0} {
+		execution = chatToolCall(t, assistant, provider, userID, tools.ShellToolName, args)
+		assert.True(t, execution.Result.IsError, "user %d", userID)
+		assert.Contains(t, execution.Result.Content[0].Text, "仅管理员", "user %d", userID)
+	}
+}
+
+func TestAIAssistantWorkspaceToolsUseCaller(t *testing.T) {
+	manager, err := workspace.New(workspace.Config{Root: t.TempDir(), QuotaBytes: 1 << 10, MaxFileBytes: 1 << 10})
+	require.NoError(t, err)
+	assistant, provider := newToolAssistant(t,
+		tools.NewFileReadTool(manager, CallerUserID),
+		tools.NewFileWriteTool(manager, CallerUserID),
+		tools.NewFileListTool(manager, CallerUserID),
+	)
+
+	// 同一用户在不同对话中写入、列出和读取自己的工作区
+	execution := chatToolCall(t, assistant, provider, 1, tools.FileWriteToolName, map[string]interface{}{"path": "extracts/aapl.csv", "content": "date,close\n"})
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	execution = chatToolCall(t, assistant, provider, 1, tools.FileListToolName, map[string]interface{}{"recursive": true})
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	assert.Contains(t, execution.Result.Content[0].Text, "extracts/aapl.csv")
+	execution = chatToolCall(t, assistant, provider, 1, tools.FileReadToolName, map[string]interface{}{"path": "extracts/aapl.csv"})
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	assert.Equal(t, "date,close\n", execution.Result.Content[0].Text)
+
+	// 其他用户看不到该文件，匿名对话不能使用工作区
+	execution = chatToolCall(t, assistant, provider, 2, tools.FileReadToolName, map[string]interface{}{"path": "extracts/aapl.csv"})
+	assert.True(t, execution.Result.IsError)
+	execution = chatToolCall(t, assistant, provider, 0, tools.FileListToolName, map[string]interface{}{})
+	assert.True(t, execution.Result.IsError)
+	assert.Contains(t, execution.Result.Content[0].Text, "文件工作区需要登录后使用")
+}
+
+func TestAIAssistantSendEmailToolUsesCaller(t *testing.T) {
+	var sent []string
+	send := func(ctx context.Context, to, subject, body string) error {
+		sent = append(sent, to)
+		return nil
+	}
+	users := &memoryAuthUserRepository{users: map[int64]*dto.UserResponse{
+		1: {ID: 1, Username: "alice", Email: "alice@acme.com", IsActive: true},
+		2: {ID: 2, Username: "bob", Email: "bob@acme.com", IsActive: true},
+	}}
+	assistant, provider := newToolAssistant(t, tools.NewSendEmailTool(tools.SendEmailConfig{
+		AllowSelf:     true,
+		MaxRecipients: 1,
+		MaxBodyBytes:  1 << 10,
+		RateLimit:     ratelimit.Rule{Rate: 0.001, Burst: 1},
+	}, send, NewCallerEmailLookup(users), ratelimit.NewMemoryStore(), zap.NewNop()))
+	args := map[string]interface{}{"subject": "Daily report", "body": "AAPL +2%"}
+
+	// 未指定收件人时发到发起对话用户的账号邮箱
+	execution := chatToolCall(t, assistant, provider, 1, tools.SendEmailToolName, args)
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	assert.Equal(t, []string{"alice@acme.com"}, sent)
+
+	// 频率限制按用户计算，超出后不影响其他用户
+	execution = chatToolCall(t, assistant, provider, 1, tools.SendEmailToolName, args)
+	assert.True(t, execution.Result.IsError)
+	assert.Contains(t, execution.Result.Content[0].Text, "发送过于频繁")
+	execution = chatToolCall(t, assistant, provider, 2, tools.SendEmailToolName, args)
+	require.False(t, execution.Result.IsError, execution.Result.Content[0].Text)
+	assert.Equal(t, []string{"alice@acme.com", "bob@acme.com"}, sent)
+
+	execution = chatToolCall(t, assistant, provider, 0, tools.SendEmailToolName, args)
+	assert.True(t, execution.Result.IsError)
+	assert.Contains(t, execution.Result.Content[0].Text, "发送邮件需要登录后使用")
+}
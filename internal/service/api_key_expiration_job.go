@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// APIKeyExpirationJob 定期检查设置了过期时间的密钥，临近过期时提醒所有者，过期后标记并通知
+type APIKeyExpirationJob struct {
+	apiKeyService APIKeyService
+	interval      time.Duration
+	warnBefore    time.Duration
+	events        webhook.Publisher
+	logger        *zap.Logger
+	stop          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewAPIKeyExpirationJob 创建密钥过期检查任务，interval 不大于0时任务不会启动，
+// warnBefore 为过期前发送提醒的提前量，为0时只在过期后通知
+func NewAPIKeyExpirationJob(apiKeyService APIKeyService, interval, warnBefore time.Duration, events webhook.Publisher, logger *zap.Logger) *APIKeyExpirationJob {
+	return &APIKeyExpirationJob{
+		apiKeyService: apiKeyService,
+		interval:      interval,
+		warnBefore:    warnBefore,
+		events:        events,
+		logger:        logger,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start 启动检查任务，启动时立即执行一次
+func (j *APIKeyExpirationJob) Start() {
+	if j.interval <= 0 {
+		j.logger.Info("API key expiration job disabled")
+		return
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run()
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	j.logger.Info("API key expiration job started",
+		zap.Duration("interval", j.interval),
+		zap.Duration("warn_before", j.warnBefore))
+}
+
+// Stop 停止检查任务并等待当前执行结束
+func (j *APIKeyExpirationJob) Stop() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	j.wg.Wait()
+}
+
+// APIKeyExpirationSummary 一次检查的结果统计
+type APIKeyExpirationSummary struct {
+	Warned  int // 发送了过期提醒的密钥数
+	Expired int // 新标记为过期的密钥数
+}
+
+// run 执行一次检查
+func (j *APIKeyExpirationJob) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if _, err := j.RunOnce(ctx, time.Now()); err != nil {
+		j.logger.Error("检查API密钥过期失败", zap.Error(err))
+	}
+}
+
+// RunOnce 检查在 now 加提醒提前量之前过期的密钥：已过期的标记为过期并通知，
+// 尚未过期且未提醒过的发送一次提醒；标记保存成功后才发布事件，避免重复通知
+func (j *APIKeyExpirationJob) RunOnce(ctx context.Context, now time.Time) (*APIKeyExpirationSummary, error) {
+	due, err := j.apiKeyService.ListDueAPIKeyExpirations(ctx, now.Add(j.warnBefore))
+	if err != nil {
+		return nil, fmt.Errorf("list due API key expirations: %w", err)
+	}
+
+	summary := &APIKeyExpirationSummary{}
+	for _, key := range due {
+		data := webhook.APIKeyExpirationData{
+			APIKeyID:  key.ApiKeyID,
+			UserID:    key.UserID,
+			ProjectID: key.ProjectID,
+			Provider:  key.ProviderType,
+			ExpiresAt: key.ExpiresAt,
+		}
+
+		if !key.ExpiresAt.After(now) {
+			if err := j.apiKeyService.MarkAPIKeyExpired(ctx, key.ApiKeyID); err != nil {
+				j.logger.Error("标记API密钥过期失败", zap.Int64("api_key_id", key.ApiKeyID), zap.Error(err))
+				continue
+			}
+			j.publish(ctx, webhook.EventAPIKeyExpired, data)
+			summary.Expired++
+			continue
+		}
+
+		if key.WarnedAt.Valid {
+			continue
+		}
+		if err := j.apiKeyService.MarkAPIKeyExpirationWarned(ctx, key.ApiKeyID); err != nil {
+			j.logger.Error("记录API密钥过期提醒失败", zap.Int64("api_key_id", key.ApiKeyID), zap.Error(err))
+			continue
+		}
+		j.publish(ctx, webhook.EventAPIKeyExpiring, data)
+		summary.Warned++
+	}
+
+	if summary.Warned > 0 || summary.Expired > 0 {
+		j.logger.Info("API key expirations checked",
+			zap.Int("warned", summary.Warned),
+			zap.Int("expired", summary.Expired))
+	}
+	return summary, nil
+}
+
+// publish 发布过期事件，未配置事件发布器时忽略
+func (j *APIKeyExpirationJob) publish(ctx context.Context, eventType string, data webhook.APIKeyExpirationData) {
+	if j.events != nil {
+		j.events.Publish(ctx, eventType, data)
+	}
+}
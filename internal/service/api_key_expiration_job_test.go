@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// fakeExpirationKeyService 仅实现过期检查任务用到的方法
+type fakeExpirationKeyService struct {
+	APIKeyService
+	due     []api_keys.ListDueAPIKeyExpirationsRow
+	before  time.Time
+	warned  []int64
+	expired []int64
+}
+
+func (f *fakeExpirationKeyService) ListDueAPIKeyExpirations(ctx context.Context, before time.Time) ([]api_keys.ListDueAPIKeyExpirationsRow, error) {
+	f.before = before
+	return f.due, nil
+}
+
+func (f *fakeExpirationKeyService) MarkAPIKeyExpirationWarned(ctx context.Context, apiKeyID int64) error {
+	f.warned = append(f.warned, apiKeyID)
+	return nil
+}
+
+func (f *fakeExpirationKeyService) MarkAPIKeyExpired(ctx context.Context, apiKeyID int64) error {
+	f.expired = append(f.expired, apiKeyID)
+	return nil
+}
+
+// recordedEvents 记录发布的事件类型
+type recordedEvents []string
+
+func (r *recordedEvents) Publish(ctx context.Context, eventType string, data interface{}) {
+	*r = append(*r, eventType)
+}
+
+func TestAPIKeyExpirationJobRunOnce(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := &fakeExpirationKeyService{
+		due: []api_keys.ListDueAPIKeyExpirationsRow{
+			{ApiKeyID: 1, UserID: 7, ProviderType: "openai", ExpiresAt: now.Add(-time.Hour)},
+			{ApiKeyID: 2, UserID: 7, ProviderType: "openai", ExpiresAt: now.Add(48 * time.Hour)},
+			{ApiKeyID: 3, UserID: 7, ProviderType: "googleai", ExpiresAt: now.Add(24 * time.Hour),
+				WarnedAt: sql.NullTime{Time: now.Add(-24 * time.Hour), Valid: true}},
+		},
+	}
+	events := &recordedEvents{}
+
+	job := NewAPIKeyExpirationJob(svc, 0, 7*24*time.Hour, events, zap.NewNop())
+	summary, err := job.RunOnce(context.Background(), now)
+	if err != nil {
+		t.Fatalf("RunOnce returned error: %v", err)
+	}
+
+	if !svc.before.Equal(now.Add(7 * 24 * time.Hour)) {
+		t.Errorf("expected keys due before %v, got %v", now.Add(7*24*time.Hour), svc.before)
+	}
+	if summary.Expired != 1 || summary.Warned != 1 {
+		t.Errorf("expected 1 expired and 1 warned, got %+v", summary)
+	}
+	if len(svc.expired) != 1 || svc.expired[0] != 1 {
+		t.Errorf("expected key 1 marked expired, got %v", svc.expired)
+	}
+	if len(svc.warned) != 1 || svc.warned[0] != 2 {
+		t.Errorf("expected key 2 marked warned, got %v", svc.warned)
+	}
+	expected := []string{webhook.EventAPIKeyExpired, webhook.EventAPIKeyExpiring}
+	if len(*events) != len(expected) || (*events)[0] != expected[0] || (*events)[1] != expected[1] {
+		t.Errorf("expected events %v, got %v", expected, *events)
+	}
+}
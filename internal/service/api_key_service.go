@@ -3,71 +3,127 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
 	"go-springAi/internal/repository"
+	"go-springAi/internal/secrets"
 	"go-springAi/internal/database/generated/api_keys"
 )
 
+// API密钥版本状态
+const (
+	APIKeyVersionCurrent = "current"
+	APIKeyVersionGrace   = "grace"
+	APIKeyVersionRetired = "retired"
+)
+
 // APIKeyService API密钥服务接口
 type APIKeyService interface {
 	// SetAPIKey 设置用户的API密钥
-	SetAPIKey(ctx context.Context, userID int64, providerType, apiKey string) error
+	SetAPIKey(ctx context.Context, userID, projectID int64, providerType, apiKey string) error
 	
 	// GetAPIKey 获取用户的API密钥
-	GetAPIKey(ctx context.Context, userID int64, providerType string) (string, error)
+	GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error)
 	
 	// ValidateAPIKey 验证API密钥格式
 	ValidateAPIKey(providerType, apiKey string) error
 	
+	// SetAllowedModels 限制API密钥只能访问指定模型，models 为空表示不限制
+	SetAllowedModels(ctx context.Context, userID, projectID int64, providerType string, models []string) error
+	
+	// GetAllowedModels 获取API密钥可访问的模型，不限制时返回 nil
+	GetAllowedModels(ctx context.Context, userID, projectID int64, providerType string) ([]string, error)
+	
 	// ListUserAPIKeys 获取用户的所有API密钥
 	ListUserAPIKeys(ctx context.Context, userID int64) ([]api_keys.ApiKey, error)
 	
 	// DeactivateAPIKey 停用API密钥
-	DeactivateAPIKey(ctx context.Context, userID int64, providerType string) error
+	DeactivateAPIKey(ctx context.Context, userID, projectID int64, providerType string) error
 	
 	// DeleteAPIKey 删除API密钥
-	DeleteAPIKey(ctx context.Context, userID int64, providerType string) error
+	DeleteAPIKey(ctx context.Context, userID, projectID int64, providerType string) error
 	
 	// CheckAPIKeyExists 检查API密钥是否存在
-	CheckAPIKeyExists(ctx context.Context, userID int64, providerType string) (bool, error)
+	CheckAPIKeyExists(ctx context.Context, userID, projectID int64, providerType string) (bool, error)
 	
 	// GetMaskedAPIKey 获取脱敏的API密钥
-	GetMaskedAPIKey(ctx context.Context, userID int64, providerType string) (string, error)
+	GetMaskedAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error)
+	
+	// GetPreviousAPIKey 获取仍处于轮换宽限期的上一版本密钥及其失效时间
+	GetPreviousAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, time.Time, error)
+	
+	// ListAPIKeyVersions 获取API密钥轮换历史
+	ListAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string) ([]dto.APIKeyVersionResponse, error)
+	
+	// DeleteProjectAPIKeys 删除项目下的所有API密钥及其版本记录
+	DeleteProjectAPIKeys(ctx context.Context, userID, projectID int64) error
+	
+	// ListActiveAPIKeys 获取所有启用的API密钥
+	ListActiveAPIKeys(ctx context.Context) ([]api_keys.ApiKey, error)
+	
+	// RecordAPIKeyValidation 记录一次API密钥验证结果，validationErr 为空表示有效
+	RecordAPIKeyValidation(ctx context.Context, key *api_keys.ApiKey, validationErr error) error
+	
+	// GetAPIKeyValidation 获取当前密钥最近一次的验证结果，尚未验证时返回 nil
+	GetAPIKeyValidation(ctx context.Context, userID, projectID int64, providerType string) (*dto.APIKeyValidationStatus, error)
+	
+	// SetAPIKeyExpiration 设置当前密钥的过期时间，expiresAt 为空时清除
+	SetAPIKeyExpiration(ctx context.Context, userID, projectID int64, providerType string, expiresAt *time.Time) error
+	
+	// GetAPIKeyExpiration 获取当前密钥的过期时间，未设置时返回 nil
+	GetAPIKeyExpiration(ctx context.Context, userID, projectID int64, providerType string) (*dto.APIKeyExpirationStatus, error)
+	
+	// ListDueAPIKeyExpirations 获取在 before 之前过期且尚未标记为过期的启用密钥
+	ListDueAPIKeyExpirations(ctx context.Context, before time.Time) ([]api_keys.ListDueAPIKeyExpirationsRow, error)
+	
+	// MarkAPIKeyExpirationWarned 记录已向所有者发送过期提醒
+	MarkAPIKeyExpirationWarned(ctx context.Context, apiKeyID int64) error
+	
+	// MarkAPIKeyExpired 将密钥标记为已过期
+	MarkAPIKeyExpired(ctx context.Context, apiKeyID int64) error
 	
 	// GetKeyManager 获取密钥管理器
-	GetKeyManager(userID int64, providerType string) *DatabaseKeyManager
+	GetKeyManager(userID, projectID int64, providerType string) *DatabaseKeyManager
 }
 
 // apiKeyService API密钥服务实现
 type apiKeyService struct {
-	repo repository.APIKeyRepository
+	repo          repository.APIKeyRepository
+	rotationGrace time.Duration
+	store         secrets.Store
 }
 
-// NewAPIKeyService 创建新的API密钥服务
-func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+// NewAPIKeyService 创建新的API密钥服务，rotationGrace 为轮换后旧密钥的宽限期，
+// store 不为 nil 时密钥保存在外部存储中，数据库只保留元数据
+func NewAPIKeyService(repo repository.APIKeyRepository, rotationGrace time.Duration, store secrets.Store) APIKeyService {
 	return &apiKeyService{
-		repo: repo,
+		repo:          repo,
+		rotationGrace: rotationGrace,
+		store:         store,
 	}
 }
 
 // SetAPIKey 设置用户的API密钥
-func (s *apiKeyService) SetAPIKey(ctx context.Context, userID int64, providerType, apiKey string) error {
+func (s *apiKeyService) SetAPIKey(ctx context.Context, userID, projectID int64, providerType, apiKey string) error {
 	// 验证API密钥格式
 	if err := s.ValidateAPIKey(providerType, apiKey); err != nil {
 		return fmt.Errorf("invalid API key: %w", err)
 	}
 	
 	// 创建密钥管理器
-	keyManager := NewDatabaseKeyManager(userID, providerType, s.repo)
+	keyManager := s.GetKeyManager(userID, projectID, providerType)
 	
 	// 设置API密钥
 	return keyManager.SetAPIKey(apiKey)
 }
 
 // GetAPIKey 获取用户的API密钥
-func (s *apiKeyService) GetAPIKey(ctx context.Context, userID int64, providerType string) (string, error) {
+func (s *apiKeyService) GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error) {
 	// 创建密钥管理器
-	keyManager := NewDatabaseKeyManager(userID, providerType, s.repo)
+	keyManager := s.GetKeyManager(userID, projectID, providerType)
 	
 	// 获取API密钥
 	return keyManager.GetAPIKey()
@@ -112,30 +168,81 @@ func (s *apiKeyService) ValidateAPIKey(providerType, apiKey string) error {
 	return nil
 }
 
+// SetAllowedModels 限制API密钥只能访问指定模型
+func (s *apiKeyService) SetAllowedModels(ctx context.Context, userID, projectID int64, providerType string, models []string) error {
+	return s.repo.UpdateAPIKeyAllowedModels(ctx, userID, projectID, providerType, joinAllowedModels(models))
+}
+
+// GetAllowedModels 获取API密钥可访问的模型
+func (s *apiKeyService) GetAllowedModels(ctx context.Context, userID, projectID int64, providerType string) ([]string, error) {
+	key, err := s.repo.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		return nil, err
+	}
+	return splitAllowedModels(key.AllowedModels), nil
+}
+
+// joinAllowedModels 去除空白和重复后以逗号拼接模型名称
+func joinAllowedModels(models []string) string {
+	seen := make(map[string]bool, len(models))
+	result := make([]string, 0, len(models))
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model == "" || seen[model] {
+			continue
+		}
+		seen[model] = true
+		result = append(result, model)
+	}
+	return strings.Join(result, ",")
+}
+
+// splitAllowedModels 解析逗号分隔的模型名称，为空时返回 nil
+func splitAllowedModels(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
 // ListUserAPIKeys 获取用户的所有API密钥
 func (s *apiKeyService) ListUserAPIKeys(ctx context.Context, userID int64) ([]api_keys.ApiKey, error) {
-	return s.repo.ListAPIKeysByUser(ctx, userID)
+	return s.repo.ListAPIKeysByUser(ctx, userID, repository.SoftDeleteOptions{})
 }
 
 // DeactivateAPIKey 停用API密钥
-func (s *apiKeyService) DeactivateAPIKey(ctx context.Context, userID int64, providerType string) error {
-	return s.repo.DeactivateAPIKey(ctx, userID, providerType)
+func (s *apiKeyService) DeactivateAPIKey(ctx context.Context, userID, projectID int64, providerType string) error {
+	return s.repo.DeactivateAPIKey(ctx, userID, projectID, providerType)
 }
 
 // DeleteAPIKey 删除API密钥
-func (s *apiKeyService) DeleteAPIKey(ctx context.Context, userID int64, providerType string) error {
-	return s.repo.DeleteAPIKey(ctx, userID, providerType)
+func (s *apiKeyService) DeleteAPIKey(ctx context.Context, userID, projectID int64, providerType string) error {
+	return s.GetKeyManager(userID, projectID, providerType).Delete()
+}
+
+// DeleteProjectAPIKeys 删除项目下的所有API密钥及其版本记录
+func (s *apiKeyService) DeleteProjectAPIKeys(ctx context.Context, userID, projectID int64) error {
+	keys, err := s.repo.ListAPIKeysByProject(ctx, userID, projectID)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.GetKeyManager(userID, projectID, key.ProviderType).Delete(); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteAPIKeyVersionsByProject(ctx, userID, projectID)
 }
 
 // CheckAPIKeyExists 检查API密钥是否存在
-func (s *apiKeyService) CheckAPIKeyExists(ctx context.Context, userID int64, providerType string) (bool, error) {
-	return s.repo.CheckAPIKeyExists(ctx, userID, providerType)
+func (s *apiKeyService) CheckAPIKeyExists(ctx context.Context, userID, projectID int64, providerType string) (bool, error) {
+	return s.repo.CheckAPIKeyExists(ctx, userID, projectID, providerType)
 }
 
 // GetMaskedAPIKey 获取脱敏的API密钥
-func (s *apiKeyService) GetMaskedAPIKey(ctx context.Context, userID int64, providerType string) (string, error) {
-	// 获取完整的API密钥
-	apiKey, err := s.GetAPIKey(ctx, userID, providerType)
+func (s *apiKeyService) GetMaskedAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error) {
+	// 获取完整的API密钥，已过期的密钥也要展示
+	apiKey, err := s.GetKeyManager(userID, projectID, providerType).GetStoredAPIKey()
 	if err != nil {
 		return "", err
 	}
@@ -144,6 +251,155 @@ func (s *apiKeyService) GetMaskedAPIKey(ctx context.Context, userID int64, provi
 	return maskAPIKey(apiKey), nil
 }
 
+// GetPreviousAPIKey 获取仍处于轮换宽限期的上一版本密钥及其失效时间
+func (s *apiKeyService) GetPreviousAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, time.Time, error) {
+	return s.GetKeyManager(userID, projectID, providerType).GetPreviousAPIKey()
+}
+
+// ListAPIKeyVersions 获取API密钥轮换历史
+func (s *apiKeyService) ListAPIKeyVersions(ctx context.Context, userID, projectID int64, providerType string) ([]dto.APIKeyVersionResponse, error) {
+	versions, err := s.repo.ListAPIKeyVersions(ctx, userID, projectID, providerType)
+	if err != nil {
+		return nil, err
+	}
+	
+	keyManager := s.GetKeyManager(userID, projectID, providerType)
+	now := time.Now()
+	result := make([]dto.APIKeyVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		item := dto.APIKeyVersionResponse{
+			Version:   v.Version,
+			MaskedKey: "****",
+			Status:    apiKeyVersionStatus(v, now),
+			CreatedAt: v.CreatedAt.Time,
+		}
+		if plain, err := keyManager.openKey(ctx, v.EncryptedKey); err == nil {
+			item.MaskedKey = maskAPIKey(plain)
+		}
+		if v.RotatedAt.Valid {
+			rotatedAt := v.RotatedAt.Time
+			item.RotatedAt = &rotatedAt
+		}
+		if v.GraceExpiresAt.Valid {
+			graceExpiresAt := v.GraceExpiresAt.Time
+			item.GraceExpiresAt = &graceExpiresAt
+		}
+		result = append(result, item)
+	}
+	
+	return result, nil
+}
+
+// ListActiveAPIKeys 获取所有启用的API密钥
+func (s *apiKeyService) ListActiveAPIKeys(ctx context.Context) ([]api_keys.ApiKey, error) {
+	return s.repo.ListActiveAPIKeys(ctx)
+}
+
+// RecordAPIKeyValidation 记录一次API密钥验证结果
+func (s *apiKeyService) RecordAPIKeyValidation(ctx context.Context, key *api_keys.ApiKey, validationErr error) error {
+	params := repository.UpsertAPIKeyValidationParams{
+		APIKeyID:    key.ID,
+		KeyHash:     key.KeyHash,
+		Valid:       validationErr == nil,
+		ValidatedAt: time.Now(),
+	}
+	if validationErr != nil {
+		params.Error = validationErr.Error()
+	}
+	return s.repo.UpsertAPIKeyValidation(ctx, params)
+}
+
+// GetAPIKeyValidation 获取当前密钥最近一次的验证结果
+func (s *apiKeyService) GetAPIKeyValidation(ctx context.Context, userID, projectID int64, providerType string) (*dto.APIKeyValidationStatus, error) {
+	key, err := s.repo.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		return nil, err
+	}
+	
+	validation, err := s.repo.GetAPIKeyValidation(ctx, key.ID)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return apiKeyValidationStatus(key, validation), nil
+}
+
+// apiKeyValidationStatus 转换验证结果，密钥更换后旧结果不再适用
+func apiKeyValidationStatus(key *api_keys.ApiKey, v *api_keys.ApiKeyValidation) *dto.APIKeyValidationStatus {
+	if v.KeyHash != key.KeyHash {
+		return nil
+	}
+	return &dto.APIKeyValidationStatus{
+		Valid:       v.Valid,
+		Error:       v.Error,
+		ValidatedAt: v.ValidatedAt,
+	}
+}
+
+// SetAPIKeyExpiration 设置当前密钥的过期时间
+func (s *apiKeyService) SetAPIKeyExpiration(ctx context.Context, userID, projectID int64, providerType string, expiresAt *time.Time) error {
+	key, err := s.repo.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		return err
+	}
+	if expiresAt == nil {
+		return s.repo.DeleteAPIKeyExpiration(ctx, key.ID)
+	}
+	return s.repo.SetAPIKeyExpiration(ctx, key.ID, key.KeyHash, *expiresAt)
+}
+
+// GetAPIKeyExpiration 获取当前密钥的过期时间
+func (s *apiKeyService) GetAPIKeyExpiration(ctx context.Context, userID, projectID int64, providerType string) (*dto.APIKeyExpirationStatus, error) {
+	key, err := s.repo.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		return nil, err
+	}
+	
+	expiration, err := s.repo.GetAPIKeyExpiration(ctx, key.ID)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// 密钥更换后旧的过期时间不再适用
+	if expiration.KeyHash != key.KeyHash {
+		return nil, nil
+	}
+	return &dto.APIKeyExpirationStatus{
+		ExpiresAt: expiration.ExpiresAt,
+		Expired:   expiration.ExpiredAt.Valid,
+	}, nil
+}
+
+// ListDueAPIKeyExpirations 获取在 before 之前过期且尚未标记为过期的启用密钥
+func (s *apiKeyService) ListDueAPIKeyExpirations(ctx context.Context, before time.Time) ([]api_keys.ListDueAPIKeyExpirationsRow, error) {
+	return s.repo.ListDueAPIKeyExpirations(ctx, before)
+}
+
+// MarkAPIKeyExpirationWarned 记录已向所有者发送过期提醒
+func (s *apiKeyService) MarkAPIKeyExpirationWarned(ctx context.Context, apiKeyID int64) error {
+	return s.repo.MarkAPIKeyExpirationWarned(ctx, apiKeyID, time.Now())
+}
+
+// MarkAPIKeyExpired 将密钥标记为已过期
+func (s *apiKeyService) MarkAPIKeyExpired(ctx context.Context, apiKeyID int64) error {
+	return s.repo.MarkAPIKeyExpired(ctx, apiKeyID, time.Now())
+}
+
+// apiKeyVersionStatus 计算密钥版本在 now 时的状态
+func apiKeyVersionStatus(v api_keys.ApiKeyVersion, now time.Time) string {
+	if !v.RotatedAt.Valid {
+		return APIKeyVersionCurrent
+	}
+	if v.GraceExpiresAt.Valid && v.GraceExpiresAt.Time.After(now) {
+		return APIKeyVersionGrace
+	}
+	return APIKeyVersionRetired
+}
+
 // maskAPIKey 对API密钥进行脱敏处理
 func maskAPIKey(apiKey string) string {
 	if len(apiKey) <= 8 {
@@ -159,6 +415,6 @@ func maskAPIKey(apiKey string) string {
 }
 
 // GetKeyManager 获取密钥管理器
-func (s *apiKeyService) GetKeyManager(userID int64, providerType string) *DatabaseKeyManager {
-	return NewDatabaseKeyManager(userID, providerType, s.repo)
+func (s *apiKeyService) GetKeyManager(userID, projectID int64, providerType string) *DatabaseKeyManager {
+	return NewDatabaseKeyManager(userID, projectID, providerType, s.repo, s.rotationGrace, s.store)
 }
\ No newline at end of file
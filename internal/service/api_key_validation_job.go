@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/types"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// apiKeyValidationTimeout 单个密钥验证的超时时间
+const apiKeyValidationTimeout = 30 * time.Second
+
+// APIKeyValidator 使用指定密钥调用提供商接口验证其有效性
+type APIKeyValidator interface {
+	ValidateProviderAPIKey(ctx context.Context, providerType, apiKey string) error
+}
+
+// APIKeyValidationJob 定期验证所有已保存API密钥的后台任务
+type APIKeyValidationJob struct {
+	apiKeyService APIKeyService
+	validator     APIKeyValidator
+	interval      time.Duration
+	events        webhook.Publisher
+	logger        *zap.Logger
+	stop          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewAPIKeyValidationJob 创建密钥验证任务，interval 不大于0时任务不会启动，
+// events 不为空时在密钥变为无效时发布告警事件
+func NewAPIKeyValidationJob(apiKeyService APIKeyService, validator APIKeyValidator, interval time.Duration, events webhook.Publisher, logger *zap.Logger) *APIKeyValidationJob {
+	return &APIKeyValidationJob{
+		apiKeyService: apiKeyService,
+		validator:     validator,
+		interval:      interval,
+		events:        events,
+		logger:        logger,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start 启动验证任务，启动时立即执行一次
+func (j *APIKeyValidationJob) Start() {
+	if j.interval <= 0 {
+		j.logger.Info("API key validation job disabled")
+		return
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run()
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	j.logger.Info("API key validation job started", zap.Duration("interval", j.interval))
+}
+
+// Stop 停止验证任务并等待当前执行结束
+func (j *APIKeyValidationJob) Stop() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	j.wg.Wait()
+}
+
+// APIKeyValidationSummary 一次验证的结果统计
+type APIKeyValidationSummary struct {
+	Checked int // 完成验证的密钥数
+	Invalid int // 被提供商拒绝的密钥数
+	Skipped int // 因临时错误未完成验证的密钥数
+}
+
+// run 执行一次验证，任务停止时中断
+func (j *APIKeyValidationJob) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if _, err := j.RunOnce(ctx); err != nil {
+		j.logger.Error("验证API密钥失败", zap.Error(err))
+	}
+}
+
+// RunOnce 依次验证所有启用的密钥，只有验证通过或被提供商拒绝时才更新结果，
+// 限流、超时等临时错误保留上一次的结果；ctx 取消时停止验证剩余的密钥
+func (j *APIKeyValidationJob) RunOnce(ctx context.Context) (*APIKeyValidationSummary, error) {
+	listCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	keys, err := j.apiKeyService.ListActiveAPIKeys(listCtx)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("list API keys: %w", err)
+	}
+
+	summary := &APIKeyValidationSummary{}
+	for i := range keys {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		key := &keys[i]
+		keyCtx, cancel := context.WithTimeout(ctx, apiKeyValidationTimeout)
+		err := j.validate(keyCtx, key.UserID, key.ProjectID, key.ProviderType)
+		if err == nil || errors.Is(err, types.ErrProviderUnauthorized) {
+			if err != nil {
+				j.alertInvalid(keyCtx, key, err)
+			}
+			if recordErr := j.apiKeyService.RecordAPIKeyValidation(keyCtx, key, err); recordErr != nil {
+				j.logger.Error("保存API密钥验证结果失败", zap.Int64("api_key_id", key.ID), zap.Error(recordErr))
+			}
+			summary.Checked++
+			if err != nil {
+				summary.Invalid++
+			}
+		} else {
+			summary.Skipped++
+			j.logger.Warn("API密钥验证未完成，保留上一次结果",
+				zap.Int64("api_key_id", key.ID),
+				zap.String("provider", key.ProviderType),
+				zap.Error(err))
+		}
+		cancel()
+	}
+
+	j.logger.Info("API keys validated",
+		zap.Int("count", len(keys)),
+		zap.Int("invalid", summary.Invalid),
+		zap.Int("skipped", summary.Skipped))
+	return summary, nil
+}
+
+// alertInvalid 密钥由有效或未验证变为被拒绝时发布告警事件，需在保存本次结果前调用
+func (j *APIKeyValidationJob) alertInvalid(ctx context.Context, key *api_keys.ApiKey, validationErr error) {
+	if j.events == nil {
+		return
+	}
+	previous, err := j.apiKeyService.GetAPIKeyValidation(ctx, key.UserID, key.ProjectID, key.ProviderType)
+	if err != nil {
+		j.logger.Warn("获取API密钥上一次验证结果失败", zap.Int64("api_key_id", key.ID), zap.Error(err))
+		return
+	}
+	if previous != nil && !previous.Valid {
+		return
+	}
+	j.events.Publish(ctx, webhook.EventAPIKeyInvalid, webhook.APIKeyInvalidData{
+		APIKeyID:  key.ID,
+		UserID:    key.UserID,
+		ProjectID: key.ProjectID,
+		Provider:  key.ProviderType,
+		Error:     validationErr.Error(),
+	})
+}
+
+// validate 读取并验证单个密钥
+func (j *APIKeyValidationJob) validate(ctx context.Context, userID, projectID int64, providerType string) error {
+	apiKey, err := j.apiKeyService.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		return fmt.Errorf("read API key: %w", err)
+	}
+	return j.validator.ValidateProviderAPIKey(ctx, providerType, apiKey)
+}
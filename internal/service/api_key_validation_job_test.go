@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/types"
+
+	"go.uber.org/zap"
+)
+
+// fakeValidationKeyService 仅实现验证任务用到的方法
+type fakeValidationKeyService struct {
+	APIKeyService
+	keys     []api_keys.ApiKey
+	recorded map[string]bool
+}
+
+func (f *fakeValidationKeyService) ListActiveAPIKeys(ctx context.Context) ([]api_keys.ApiKey, error) {
+	return f.keys, nil
+}
+
+func (f *fakeValidationKeyService) GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error) {
+	return providerType + "-key", nil
+}
+
+func (f *fakeValidationKeyService) RecordAPIKeyValidation(ctx context.Context, key *api_keys.ApiKey, validationErr error) error {
+	f.recorded[key.ProviderType] = validationErr == nil
+	return nil
+}
+
+type fakeKeyValidator map[string]error
+
+func (f fakeKeyValidator) ValidateProviderAPIKey(ctx context.Context, providerType, apiKey string) error {
+	return f[providerType]
+}
+
+func TestAPIKeyValidationJobRun(t *testing.T) {
+	svc := &fakeValidationKeyService{
+		keys: []api_keys.ApiKey{
+			{ID: 1, ProviderType: "valid"},
+			{ID: 2, ProviderType: "rejected"},
+			{ID: 3, ProviderType: "limited"},
+			{ID: 4, ProviderType: "unreachable"},
+		},
+		recorded: map[string]bool{},
+	}
+	validator := fakeKeyValidator{
+		"rejected":    fmt.Errorf("%w: bad key", types.ErrProviderUnauthorized),
+		"limited":     fmt.Errorf("%w: slow down", types.ErrProviderRateLimited),
+		"unreachable": fmt.Errorf("send request: connection refused"),
+	}
+
+	NewAPIKeyValidationJob(svc, validator, 0, nil, zap.NewNop()).run()
+
+	expected := map[string]bool{"valid": true, "rejected": false}
+	if fmt.Sprint(svc.recorded) != fmt.Sprint(expected) {
+		t.Errorf("expected recorded %v, got %v", expected, svc.recorded)
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/personal_access_tokens"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// apiTokenBytes 个人访问令牌的随机字节数
+const apiTokenBytes = 32
+
+// apiTokenDisplayLength 保存用于展示的令牌前缀长度
+const apiTokenDisplayLength = 12
+
+// APITokenService 个人访问令牌服务接口
+type APITokenService interface {
+	// Create 为用户创建个人访问令牌，明文令牌只在此时返回
+	Create(ctx context.Context, userID int64, req *dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error)
+	// List 获取用户的个人访问令牌
+	List(ctx context.Context, userID int64) ([]*dto.APITokenResponse, error)
+	// Revoke 吊销用户的个人访问令牌
+	Revoke(ctx context.Context, userID, tokenID int64) error
+	// Authenticate 校验个人访问令牌并返回对应身份
+	Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error)
+}
+
+// apiTokenService 个人访问令牌服务实现
+type apiTokenService struct {
+	userRepo     repository.UserRepository
+	apiTokenRepo repository.APITokenRepository
+	logger       *zap.Logger
+}
+
+// NewAPITokenService 创建个人访问令牌服务
+func NewAPITokenService(repoManager repository.RepositoryManager, logger *zap.Logger) APITokenService {
+	return &apiTokenService{
+		userRepo:     repoManager.User(),
+		apiTokenRepo: repoManager.APIToken(),
+		logger:       logger,
+	}
+}
+
+// Create 为用户创建个人访问令牌，明文令牌只在此时返回
+func (s *apiTokenService) Create(ctx context.Context, userID int64, req *dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("令牌名称不能为空")
+	}
+	scopes, err := normalizeAPITokenScopes(req.Scopes)
+	if err != nil {
+		return nil, errors.NewValidationError("令牌权限范围无效").WithDetails(err.Error())
+	}
+
+	secret, err := utils.GenerateRandomToken(apiTokenBytes)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate personal access token").WithCause(err)
+	}
+	token := dto.APITokenPrefix + secret
+
+	var expiresAt sql.NullTime
+	if req.ExpiresInDays > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().AddDate(0, 0, req.ExpiresInDays).UTC(), Valid: true}
+	}
+
+	stored, err := s.apiTokenRepo.Create(ctx, repository.CreateAPITokenParams{
+		UserID:      userID,
+		Name:        name,
+		TokenHash:   utils.HashToken(token),
+		TokenPrefix: token[:apiTokenDisplayLength],
+		Scopes:      strings.Join(scopes, ","),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to store personal access token").WithCause(err)
+	}
+
+	s.logger.Info("Personal access token created",
+		zap.Int64("user_id", userID),
+		zap.Int64("token_id", stored.ID),
+		zap.Strings("scopes", scopes))
+
+	return &dto.CreateAPITokenResponse{
+		APITokenResponse: *toAPITokenResponse(stored),
+		Token:            token,
+	}, nil
+}
+
+// List 获取用户的个人访问令牌
+func (s *apiTokenService) List(ctx context.Context, userID int64) ([]*dto.APITokenResponse, error) {
+	tokens, err := s.apiTokenRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list personal access tokens").WithCause(err)
+	}
+
+	result := make([]*dto.APITokenResponse, 0, len(tokens))
+	for i := range tokens {
+		result = append(result, toAPITokenResponse(&tokens[i]))
+	}
+	return result, nil
+}
+
+// Revoke 吊销用户的个人访问令牌
+func (s *apiTokenService) Revoke(ctx context.Context, userID, tokenID int64) error {
+	revoked, err := s.apiTokenRepo.Revoke(ctx, tokenID, userID)
+	if err != nil {
+		return errors.NewInternalError("Failed to revoke personal access token").WithCause(err)
+	}
+	if !revoked {
+		return errors.NewNotFoundError("Personal access token")
+	}
+
+	s.logger.Info("Personal access token revoked", zap.Int64("user_id", userID), zap.Int64("token_id", tokenID))
+	return nil
+}
+
+// Authenticate 校验个人访问令牌并返回对应身份
+func (s *apiTokenService) Authenticate(ctx context.Context, token string) (*dto.APITokenPrincipal, error) {
+	if !strings.HasPrefix(token, dto.APITokenPrefix) {
+		return nil, errors.NewTokenInvalidError()
+	}
+
+	stored, err := s.apiTokenRepo.GetByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, errors.NewTokenInvalidError()
+		}
+		return nil, errors.NewInternalError("Failed to load personal access token").WithCause(err)
+	}
+	if stored.RevokedAt.Valid {
+		return nil, errors.NewTokenInvalidError()
+	}
+	if stored.ExpiresAt.Valid && time.Now().After(stored.ExpiresAt.Time) {
+		return nil, errors.NewTokenExpiredError()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.NewAccountDisabledError()
+	}
+
+	if err := s.apiTokenRepo.Touch(ctx, stored.ID); err != nil {
+		s.logger.Warn("更新个人访问令牌使用时间失败", zap.Int64("token_id", stored.ID), zap.Error(err))
+	}
+
+	return &dto.APITokenPrincipal{
+		TokenID:  stored.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Scopes:   splitAPITokenScopes(stored.Scopes),
+	}, nil
+}
+
+// normalizeAPITokenScopes 校验并去重权限范围
+func normalizeAPITokenScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		if !isKnownAPITokenScope(scope) {
+			return nil, fmt.Errorf("未知的权限范围: %s（可选: %s）", scope, strings.Join(dto.APITokenScopes, ", "))
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("至少需要一个权限范围")
+	}
+	return result, nil
+}
+
+// isKnownAPITokenScope 判断是否为可授予的权限范围
+func isKnownAPITokenScope(scope string) bool {
+	for _, known := range dto.APITokenScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAPITokenScopes 解析逗号分隔的权限范围
+func splitAPITokenScopes(scopes string) []string {
+	result := []string{}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// toAPITokenResponse 转换为不含明文的令牌信息
+func toAPITokenResponse(token *personal_access_tokens.PersonalAccessToken) *dto.APITokenResponse {
+	resp := &dto.APITokenResponse{
+		ID:     token.ID,
+		Name:   token.Name,
+		Prefix: token.TokenPrefix,
+		Scopes: splitAPITokenScopes(token.Scopes),
+	}
+	if token.ExpiresAt.Valid {
+		resp.ExpiresAt = &token.ExpiresAt.Time
+	}
+	if token.LastUsedAt.Valid {
+		resp.LastUsedAt = &token.LastUsedAt.Time
+	}
+	if token.RevokedAt.Valid {
+		resp.RevokedAt = &token.RevokedAt.Time
+	}
+	if token.CreatedAt.Valid {
+		resp.CreatedAt = token.CreatedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeAPITokenScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "Trimmed, lowercased and deduplicated", scopes: []string{" Stock", "mcp", "stock"}, expected: []string{"stock", "mcp"}},
+		{name: "Unknown scope", scopes: []string{"stock", "admin"}, wantErr: true},
+		{name: "Only blanks", scopes: []string{"", "  "}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, err := normalizeAPITokenScopes(tt.scopes)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", scopes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(scopes, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, scopes)
+			}
+		})
+	}
+}
+
+func TestSplitAPITokenScopes(t *testing.T) {
+	if scopes := splitAPITokenScopes("stock, mcp,,"); strings.Join(scopes, ",") != "stock,mcp" {
+		t.Errorf("unexpected scopes: %v", scopes)
+	}
+	if scopes := splitAPITokenScopes(""); scopes == nil || len(scopes) != 0 {
+		t.Errorf("expected empty non-nil slice, got %#v", scopes)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/utils"
+	"go-springAi/internal/webhook"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// refreshTokenBytes 刷新令牌的随机字节数
+const refreshTokenBytes = 32
+
+// AuthService 认证服务接口
+type AuthService interface {
+	// Login 校验用户名密码并签发访问令牌和刷新令牌
+	Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error)
+	// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+	Refresh(ctx context.Context, refreshToken string) (*dto.TokenResponse, error)
+	// Logout 吊销刷新令牌所在的令牌族
+	Logout(ctx context.Context, refreshToken string) error
+	// Impersonate 管理员获取目标用户的短期模拟登录令牌
+	Impersonate(ctx context.Context, adminID, userID int64, reason string) (*dto.ImpersonationResponse, error)
+	// Reauthenticate 校验当前用户密码并签发可用于敏感操作的访问令牌
+	Reauthenticate(ctx context.Context, userID int64, password string) (*dto.ReauthResponse, error)
+}
+
+// authService 认证服务实现
+type authService struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	preferenceRepo   repository.UserPreferenceRepository
+	jwtManager       *utils.JWTManager
+	refreshTTL       time.Duration
+	impersonationTTL time.Duration
+	reauthMaxAge     time.Duration
+	events           webhook.Publisher
+	logger           *zap.Logger
+}
+
+// NewAuthService 创建认证服务，refreshExpireHours 为刷新令牌有效期（小时），impersonationMinutes 为模拟登录令牌有效期（分钟），
+// reauthMinutes 为重新认证后可执行敏感操作的时长（分钟），events 为空时不发布审计事件
+func NewAuthService(repoManager repository.RepositoryManager, jwtManager *utils.JWTManager, refreshExpireHours, impersonationMinutes, reauthMinutes int, events webhook.Publisher, logger *zap.Logger) AuthService {
+	return &authService{
+		userRepo:         repoManager.User(),
+		refreshTokenRepo: repoManager.RefreshToken(),
+		preferenceRepo:   repoManager.UserPreference(),
+		jwtManager:       jwtManager,
+		refreshTTL:       time.Duration(refreshExpireHours) * time.Hour,
+		impersonationTTL: time.Duration(impersonationMinutes) * time.Minute,
+		reauthMaxAge:     time.Duration(reauthMinutes) * time.Minute,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+// Login 校验用户名密码并签发访问令牌和刷新令牌
+func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.TokenResponse, error) {
+	user, err := s.userRepo.VerifyPassword(ctx, req.Username, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.NewAccountDisabledError()
+	}
+
+	// 顺带清理已过期的刷新令牌
+	if err := s.refreshTokenRepo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+		s.logger.Warn("清理过期刷新令牌失败", zap.Error(err))
+	}
+
+	tokens, err := s.issueTokens(ctx, user, uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
+	// 偏好设置读取失败不影响登录
+	prefs, err := loadUserPreferences(ctx, s.preferenceRepo, user.ID)
+	if err != nil {
+		s.logger.Warn("读取用户偏好设置失败", zap.Int64("user_id", user.ID), zap.Error(err))
+	}
+	tokens.Preferences = prefs
+
+	s.logger.Info("User login successful", zap.Int64("user_id", user.ID), zap.String("username", user.Username))
+	return tokens, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*dto.TokenResponse, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, errors.NewTokenInvalidError()
+		}
+		return nil, errors.NewInternalError("Failed to load refresh token").WithCause(err)
+	}
+
+	// 已吊销的令牌再次出现视为泄露，吊销整个令牌族
+	if stored.RevokedAt.Valid {
+		s.revokeFamily(ctx, stored.FamilyID, "reuse_detected")
+		return nil, errors.NewTokenInvalidError()
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.NewTokenExpiredError()
+	}
+
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, stored.ID)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to revoke refresh token").WithCause(err)
+	}
+	if !revoked {
+		// 并发请求已轮换该令牌
+		s.revokeFamily(ctx, stored.FamilyID, "concurrent_reuse")
+		return nil, errors.NewTokenInvalidError()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		s.revokeFamily(ctx, stored.FamilyID, "account_disabled")
+		return nil, errors.NewAccountDisabledError()
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout 吊销刷新令牌所在的令牌族，未知令牌直接忽略
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil
+		}
+		return errors.NewInternalError("Failed to load refresh token").WithCause(err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return errors.NewInternalError("Failed to revoke refresh token").WithCause(err)
+	}
+
+	s.logger.Info("User logged out", zap.Int64("user_id", stored.UserID))
+	return nil
+}
+
+// Impersonate 管理员获取目标用户的短期模拟登录令牌
+func (s *authService) Impersonate(ctx context.Context, adminID, userID int64, reason string) (*dto.ImpersonationResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.NewValidationError("模拟登录原因不能为空")
+	}
+	if adminID == userID {
+		return nil, errors.NewValidationError("不能模拟登录自己")
+	}
+
+	admin, err := s.userRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin.IsAdmin {
+		return nil, errors.NewForbiddenError("Admin privileges required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin {
+		return nil, errors.NewForbiddenError("Cannot impersonate another admin")
+	}
+
+	banner := fmt.Sprintf("Impersonated session: %s is acting as %s", admin.Username, user.Username)
+	token, expiresAt, err := s.jwtManager.GenerateImpersonationToken(user.ID, user.Username, utils.ImpersonationClaim{
+		AdminID:       admin.ID,
+		AdminUsername: admin.Username,
+		Reason:        reason,
+		Banner:        banner,
+	}, s.impersonationTTL)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate impersonation token").WithCause(err)
+	}
+
+	s.logger.Warn("Impersonation token issued",
+		zap.String("audit", "impersonation"),
+		zap.String("event", "issued"),
+		zap.Int64("admin_id", admin.ID),
+		zap.String("admin_username", admin.Username),
+		zap.Int64("user_id", user.ID),
+		zap.String("username", user.Username),
+		zap.String("reason", reason),
+		zap.Time("expires_at", expiresAt))
+	if s.events != nil {
+		s.events.Publish(ctx, webhook.EventImpersonationIssued, webhook.ImpersonationData{
+			AdminID:       admin.ID,
+			AdminUsername: admin.Username,
+			UserID:        user.ID,
+			Username:      user.Username,
+			Reason:        reason,
+			ExpiresAt:     expiresAt,
+		})
+	}
+
+	return &dto.ImpersonationResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.impersonationTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+		Banner:      banner,
+		User:        user,
+	}, nil
+}
+
+// Reauthenticate 校验当前用户密码并签发可用于敏感操作的访问令牌
+func (s *authService) Reauthenticate(ctx context.Context, userID int64, password string) (*dto.ReauthResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, errors.NewAccountDisabledError()
+	}
+
+	if _, err := s.userRepo.VerifyPassword(ctx, user.Username, password); err != nil {
+		s.logger.Warn("Re-authentication failed",
+			zap.String("audit", "reauth"),
+			zap.String("event", "failed"),
+			zap.Int64("user_id", user.ID),
+			zap.String("username", user.Username),
+			zap.String("method", "password"))
+		return nil, err
+	}
+
+	token, authTime, err := s.jwtManager.GenerateReauthToken(user.ID, user.Username)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate access token").WithCause(err)
+	}
+
+	s.logger.Warn("Re-authentication succeeded",
+		zap.String("audit", "reauth"),
+		zap.String("event", "succeeded"),
+		zap.Int64("user_id", user.ID),
+		zap.String("username", user.Username),
+		zap.String("method", "password"))
+
+	return &dto.ReauthResponse{
+		AccessToken:     token,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(s.jwtManager.ExpireTime().Seconds()),
+		ReauthExpiresAt: authTime.Add(s.reauthMaxAge).UTC(),
+	}, nil
+}
+
+// issueTokens 签发访问令牌并保存新的刷新令牌
+func (s *authService) issueTokens(ctx context.Context, user *dto.UserResponse, familyID string) (*dto.TokenResponse, error) {
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Username)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate access token").WithCause(err)
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate refresh token").WithCause(err)
+	}
+
+	expiresAt := time.Now().Add(s.refreshTTL).UTC()
+	if _, err := s.refreshTokenRepo.Create(ctx, repository.CreateRefreshTokenParams{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, errors.NewInternalError("Failed to store refresh token").WithCause(err)
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.jwtManager.ExpireTime().Seconds()),
+		RefreshExpiresAt: expiresAt,
+		User:             user,
+	}, nil
+}
+
+// revokeFamily 吊销令牌族并记录原因
+func (s *authService) revokeFamily(ctx context.Context, familyID, reason string) {
+	s.logger.Warn("Revoking refresh token family", zap.String("family_id", familyID), zap.String("reason", reason))
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		s.logger.Error("吊销刷新令牌族失败", zap.String("family_id", familyID), zap.Error(err))
+	}
+}
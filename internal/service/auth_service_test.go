@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database/generated/refresh_tokens"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRefreshTokenRepository 内存中的刷新令牌，getBarrier 不为空时 GetByHash 等待所有调用方读到令牌后再返回
+type memoryRefreshTokenRepository struct {
+	mu         sync.Mutex
+	tokens     []*refresh_tokens.RefreshToken
+	getBarrier *sync.WaitGroup
+}
+
+func (r *memoryRefreshTokenRepository) Create(ctx context.Context, params repository.CreateRefreshTokenParams) (*refresh_tokens.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token := &refresh_tokens.RefreshToken{
+		ID:        int64(len(r.tokens) + 1),
+		UserID:    params.UserID,
+		TokenHash: params.TokenHash,
+		FamilyID:  params.FamilyID,
+		ExpiresAt: params.ExpiresAt,
+	}
+	r.tokens = append(r.tokens, token)
+	copied := *token
+	return &copied, nil
+}
+
+func (r *memoryRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*refresh_tokens.RefreshToken, error) {
+	r.mu.Lock()
+	var found *refresh_tokens.RefreshToken
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			found = &copied
+		}
+	}
+	r.mu.Unlock()
+
+	if r.getBarrier != nil {
+		r.getBarrier.Done()
+		r.getBarrier.Wait()
+	}
+	if found == nil {
+		return nil, errors.NewNotFoundError("Refresh token")
+	}
+	return found, nil
+}
+
+func (r *memoryRefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID == id && !token.RevokedAt.Valid {
+			token.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && !token.RevokedAt.Valid {
+			token.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// activeFamilyTokens 令牌族中未吊销的令牌数
+func (r *memoryRefreshTokenRepository) activeFamilyTokens(familyID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	active := 0
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && !token.RevokedAt.Valid {
+			active++
+		}
+	}
+	return active
+}
+
+// memoryAuthUserRepository 只支持按 ID 获取用户
+type memoryAuthUserRepository struct {
+	repository.UserRepository
+	users map[int64]*dto.UserResponse
+}
+
+func (r *memoryAuthUserRepository) GetByID(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("User")
+	}
+	return user, nil
+}
+
+// newTestAuthService 创建使用内存仓库的认证服务，用户 1 为普通用户
+func newTestAuthService(users ...*dto.UserResponse) (*authService, *memoryRefreshTokenRepository) {
+	userRepo := &memoryAuthUserRepository{users: map[int64]*dto.UserResponse{
+		1: {ID: 1, Username: "alice", IsActive: true},
+	}}
+	for _, user := range users {
+		userRepo.users[user.ID] = user
+	}
+	tokens := &memoryRefreshTokenRepository{}
+	return &authService{
+		userRepo:         userRepo,
+		refreshTokenRepo: tokens,
+		jwtManager:       utils.NewJWTManager("test-secret", 1),
+		refreshTTL:       time.Hour,
+		impersonationTTL: 15 * time.Minute,
+		logger:           zap.NewNop(),
+	}, tokens
+}
+
+func TestAuthServiceRefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	service, tokens := newTestAuthService()
+	issued, err := service.issueTokens(ctx, &dto.UserResponse{ID: 1, Username: "alice", IsActive: true}, "family-1")
+	require.NoError(t, err)
+
+	rotated, err := service.Refresh(ctx, issued.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, issued.RefreshToken, rotated.RefreshToken)
+	assert.Equal(t, 1, tokens.activeFamilyTokens("family-1"))
+
+	// 已轮换的令牌再次使用时吊销整个令牌族，新令牌也随之失效
+	_, err = service.Refresh(ctx, issued.RefreshToken)
+	assertAppErrorCode(t, err, errors.ErrCodeTokenInvalid)
+	assert.Zero(t, tokens.activeFamilyTokens("family-1"))
+	_, err = service.Refresh(ctx, rotated.RefreshToken)
+	assertAppErrorCode(t, err, errors.ErrCodeTokenInvalid)
+}
+
+func TestAuthServiceConcurrentRefresh(t *testing.T) {
+	ctx := context.Background()
+	service, tokens := newTestAuthService()
+	issued, err := service.issueTokens(ctx, &dto.UserResponse{ID: 1, Username: "alice", IsActive: true}, "family-1")
+	require.NoError(t, err)
+
+	// 两个请求都读到未吊销的令牌后再轮换
+	const callers = 2
+	tokens.getBarrier = &sync.WaitGroup{}
+	tokens.getBarrier.Add(callers)
+
+	var wg sync.WaitGroup
+	results := make([]*dto.TokenResponse, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = service.Refresh(ctx, issued.RefreshToken)
+		}(i)
+	}
+	wg.Wait()
+	tokens.getBarrier = nil
+
+	// 只有一个请求成功，另一个视为重用并吊销令牌族
+	succeeded := 0
+	for i := range errs {
+		if errs[i] == nil {
+			succeeded++
+			_, err := service.Refresh(ctx, results[i].RefreshToken)
+			assertAppErrorCode(t, err, errors.ErrCodeTokenInvalid)
+		} else {
+			assertAppErrorCode(t, errs[i], errors.ErrCodeTokenInvalid)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Zero(t, tokens.activeFamilyTokens("family-1"))
+}
+
+func TestAuthServiceRefreshAfterLogout(t *testing.T) {
+	ctx := context.Background()
+	service, tokens := newTestAuthService()
+	issued, err := service.issueTokens(ctx, &dto.UserResponse{ID: 1, Username: "alice", IsActive: true}, "family-1")
+	require.NoError(t, err)
+	other, err := service.issueTokens(ctx, &dto.UserResponse{ID: 1, Username: "alice", IsActive: true}, "family-2")
+	require.NoError(t, err)
+
+	require.NoError(t, service.Logout(ctx, issued.RefreshToken))
+	_, err = service.Refresh(ctx, issued.RefreshToken)
+	assertAppErrorCode(t, err, errors.ErrCodeTokenInvalid)
+
+	// 只吊销当前令牌族，其他设备的会话不受影响；重复登出和未知令牌直接忽略
+	assert.Equal(t, 1, tokens.activeFamilyTokens("family-2"))
+	_, err = service.Refresh(ctx, other.RefreshToken)
+	require.NoError(t, err)
+	require.NoError(t, service.Logout(ctx, issued.RefreshToken))
+	require.NoError(t, service.Logout(ctx, "unknown"))
+}
+
+func TestAuthServiceImpersonate(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestAuthService(
+		&dto.UserResponse{ID: 2, Username: "root", IsActive: true, IsAdmin: true},
+		&dto.UserResponse{ID: 3, Username: "ops", IsActive: true, IsAdmin: true},
+	)
+
+	tests := []struct {
+		name     string
+		adminID  int64
+		userID   int64
+		reason   string
+		wantCode errors.ErrorCode
+	}{
+		{name: "non-admin is refused", adminID: 1, userID: 2, reason: "support ticket", wantCode: errors.ErrCodeForbidden},
+		{name: "admin cannot be impersonated", adminID: 2, userID: 3, reason: "support ticket", wantCode: errors.ErrCodeForbidden},
+		{name: "reason is required", adminID: 2, userID: 1, reason: "  ", wantCode: errors.ErrCodeValidationFailed},
+		{name: "cannot impersonate self", adminID: 2, userID: 2, reason: "support ticket", wantCode: errors.ErrCodeValidationFailed},
+		{name: "unknown user", adminID: 2, userID: 9, reason: "support ticket", wantCode: errors.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.Impersonate(ctx, tt.adminID, tt.userID, tt.reason)
+			assert.Nil(t, resp)
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+
+	// 模拟登录令牌携带管理员身份和原因
+	resp, err := service.Impersonate(ctx, 2, 1, " support ticket ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.User.ID)
+	assert.Equal(t, int64((15 * time.Minute).Seconds()), resp.ExpiresIn)
+	claims, err := service.jwtManager.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), claims.UserID)
+	require.NotNil(t, claims.Impersonation)
+	assert.Equal(t, int64(2), claims.Impersonation.AdminID)
+	assert.Equal(t, "support ticket", claims.Impersonation.Reason)
+	assert.Equal(t, resp.Banner, claims.Impersonation.Banner)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// 预算范围
+const (
+	BudgetScopeUser     = "user"
+	BudgetScopeProject  = "project"
+	BudgetScopeProvider = "provider"
+)
+
+// BudgetLimit 每月费用阈值(美元)，0 表示不限制
+type BudgetLimit struct {
+	SoftUSD float64 // 达到时发送通知
+	HardUSD float64 // 达到时拒绝请求
+}
+
+// BudgetPolicy 每月AI费用预算，用户预算优先使用 Users 中的配置，否则使用 Default
+type BudgetPolicy struct {
+	Enabled   bool
+	Default   BudgetLimit
+	Users     map[string]BudgetLimit // 键为小写用户名
+	Projects  map[string]BudgetLimit // 键为小写的 所有者用户名/项目名
+	Providers map[string]BudgetLimit // 键为提供商类型
+}
+
+// BudgetRequest 预算涉及的一次AI请求
+type BudgetRequest struct {
+	UserID    int64  // 0 表示匿名请求
+	ProjectID int64  // 0 表示未指定项目
+	Project   string // 项目名称
+	Provider  string // 提供商类型
+}
+
+// BudgetService AI费用预算服务接口
+type BudgetService interface {
+	// Check 检查请求涉及的用户、项目和提供商预算，达到硬性上限时返回配额超限错误，达到提醒阈值时发送通知
+	Check(ctx context.Context, req BudgetRequest) error
+	// Record 按模型价格记录一次请求的费用，未配置价格的模型不记录
+	Record(ctx context.Context, req BudgetRequest, model string, promptTokens, completionTokens int) error
+	// Enabled 是否启用预算
+	Enabled() bool
+}
+
+// budgetService AI费用预算服务实现
+type budgetService struct {
+	userRepo  repository.UserRepository
+	spendRepo repository.AISpendRepository
+	estimator CostEstimator
+	policy    BudgetPolicy
+	now       func() time.Time
+	events    webhook.Publisher
+	logger    *zap.Logger
+
+	// notified 已发送通知的预算，键为 范围:目标:事件，值为预算重置时间
+	notified   map[string]time.Time
+	notifiedMu sync.Mutex
+}
+
+// appliedBudget 请求适用的一项预算
+type appliedBudget struct {
+	scope  string
+	target string
+	userID int64 // 接收通知的用户，0 表示通知管理员
+	limit  BudgetLimit
+	spent  float64
+}
+
+// NewBudgetService 创建AI费用预算服务，events 为空时不发送预算通知
+func NewBudgetService(repoManager repository.RepositoryManager, estimator CostEstimator, policy BudgetPolicy, events webhook.Publisher, logger *zap.Logger) BudgetService {
+	return &budgetService{
+		userRepo:  repoManager.User(),
+		spendRepo: repoManager.AISpend(),
+		estimator: estimator,
+		policy:    policy,
+		now:       time.Now,
+		events:    events,
+		logger:    logger,
+		notified:  make(map[string]time.Time),
+	}
+}
+
+// Check 检查请求涉及的用户、项目和提供商预算
+func (s *budgetService) Check(ctx context.Context, req BudgetRequest) error {
+	if !s.policy.Enabled {
+		return nil
+	}
+
+	budgets, err := s.resolveBudgets(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	summary, err := s.spendRepo.Summary(ctx, req.UserID, req.ProjectID, req.Provider, monthStart(now).Format(usageDateLayout))
+	if err != nil {
+		return errors.NewDatabaseError("ai spend summary", err)
+	}
+	resetAt := monthReset(now)
+	for i := range budgets {
+		switch budgets[i].scope {
+		case BudgetScopeUser:
+			budgets[i].spent = summary.UserSpend
+		case BudgetScopeProject:
+			budgets[i].spent = summary.ProjectSpend
+		case BudgetScopeProvider:
+			budgets[i].spent = summary.ProviderSpend
+		}
+	}
+
+	for _, b := range budgets {
+		if b.limit.HardUSD > 0 && b.spent >= b.limit.HardUSD {
+			s.logger.Info("AI budget exceeded",
+				zap.String("budget", b.scope),
+				zap.String("target", b.target),
+				zap.Float64("limit_usd", b.limit.HardUSD),
+				zap.Float64("spent_usd", b.spent))
+			s.notify(ctx, webhook.EventBudgetExceeded, b, b.limit.HardUSD, resetAt)
+			return errors.NewBudgetExceededError(b.scope, b.limit.HardUSD, resetAt).WithMetadata("target", b.target)
+		}
+	}
+	for _, b := range budgets {
+		if b.limit.SoftUSD > 0 && b.spent >= b.limit.SoftUSD {
+			s.notify(ctx, webhook.EventBudgetWarning, b, b.limit.SoftUSD, resetAt)
+		}
+	}
+	return nil
+}
+
+// Record 按模型价格记录一次请求的费用
+func (s *budgetService) Record(ctx context.Context, req BudgetRequest, model string, promptTokens, completionTokens int) error {
+	if !s.policy.Enabled || s.estimator == nil {
+		return nil
+	}
+	cost, ok := s.estimator.EstimateCost(req.Provider, model, max(promptTokens, 0), max(completionTokens, 0))
+	if !ok || cost <= 0 {
+		return nil
+	}
+	return s.spendRepo.Record(ctx, req.UserID, req.ProjectID, req.Provider, s.now().UTC().Format(usageDateLayout), cost)
+}
+
+// Enabled 是否启用预算
+func (s *budgetService) Enabled() bool {
+	return s.policy.Enabled
+}
+
+// resolveBudgets 确定请求适用的预算，未设置任何阈值的预算被忽略
+func (s *budgetService) resolveBudgets(ctx context.Context, req BudgetRequest) ([]appliedBudget, error) {
+	username := QuotaRoleAnonymous
+	if req.UserID > 0 {
+		user, err := s.userRepo.GetByID(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		username = strings.ToLower(user.Username)
+	}
+
+	var budgets []appliedBudget
+	userLimit, ok := s.policy.Users[username]
+	if !ok || req.UserID <= 0 {
+		userLimit = s.policy.Default
+	}
+	budgets = appendBudget(budgets, BudgetScopeUser, username, req.UserID, userLimit)
+	if req.ProjectID > 0 && req.UserID > 0 {
+		target := username + "/" + strings.ToLower(strings.TrimSpace(req.Project))
+		budgets = appendBudget(budgets, BudgetScopeProject, target, req.UserID, s.policy.Projects[target])
+	}
+	budgets = appendBudget(budgets, BudgetScopeProvider, req.Provider, 0, s.policy.Providers[req.Provider])
+	return budgets, nil
+}
+
+// appendBudget 添加设置了阈值的预算
+func appendBudget(budgets []appliedBudget, scope, target string, userID int64, limit BudgetLimit) []appliedBudget {
+	if limit.SoftUSD <= 0 && limit.HardUSD <= 0 {
+		return budgets
+	}
+	return append(budgets, appliedBudget{scope: scope, target: target, userID: userID, limit: limit})
+}
+
+// notify 发布预算通知，同一预算的同一事件在重置前只发布一次
+func (s *budgetService) notify(ctx context.Context, eventType string, b appliedBudget, limit float64, resetAt time.Time) {
+	if !s.markNotified(b.scope+":"+b.target+":"+eventType, resetAt) {
+		return
+	}
+	s.events.Publish(ctx, eventType, webhook.BudgetAlertData{
+		UserID:   b.userID,
+		Budget:   b.scope,
+		Target:   b.target,
+		LimitUSD: limit,
+		SpentUSD: b.spent,
+		ResetAt:  resetAt,
+	})
+}
+
+// markNotified 记录本月已发送的通知，返回 false 表示无需发送
+func (s *budgetService) markNotified(key string, resetAt time.Time) bool {
+	if s.events == nil {
+		return false
+	}
+
+	s.notifiedMu.Lock()
+	defer s.notifiedMu.Unlock()
+	if s.notified[key].Equal(resetAt) {
+		return false
+	}
+	s.notified[key] = resetAt
+	// 清理已过重置时间的记录，避免长时间运行后无限增长
+	now := s.now()
+	for k, reset := range s.notified {
+		if !reset.After(now) {
+			delete(s.notified, k)
+		}
+	}
+	return true
+}
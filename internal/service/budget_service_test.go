@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database/generated/ai_spend"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeSpendRepository 固定的费用汇总，并记录写入的费用
+type fakeSpendRepository struct {
+	summary  ai_spend.GetAISpendSummaryRow
+	recorded []float64
+}
+
+func (r *fakeSpendRepository) Record(ctx context.Context, userID, projectID int64, provider, usageDate string, costUSD float64) error {
+	r.recorded = append(r.recorded, costUSD)
+	return nil
+}
+
+func (r *fakeSpendRepository) Summary(ctx context.Context, userID, projectID int64, provider, monthStart string) (*ai_spend.GetAISpendSummaryRow, error) {
+	summary := r.summary
+	return &summary, nil
+}
+
+// recordingPublisher 记录发布的事件类型
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, eventType string, data interface{}) {
+	p.events = append(p.events, eventType)
+}
+
+func newTestBudgetService(spend *fakeSpendRepository, events webhook.Publisher) *budgetService {
+	return &budgetService{
+		spendRepo: spend,
+		estimator: perTokenEstimator{},
+		policy: BudgetPolicy{
+			Enabled:   true,
+			Default:   BudgetLimit{SoftUSD: 5, HardUSD: 10},
+			Providers: map[string]BudgetLimit{"openai": {HardUSD: 100}},
+		},
+		now:      func() time.Time { return time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC) },
+		events:   events,
+		logger:   zap.NewNop(),
+		notified: make(map[string]time.Time),
+	}
+}
+
+func TestBudgetCheck(t *testing.T) {
+	ctx := context.Background()
+	req := BudgetRequest{Provider: "openai"}
+
+	tests := []struct {
+		name       string
+		summary    ai_spend.GetAISpendSummaryRow
+		wantBudget string
+		wantEvents []string
+	}{
+		{name: "Under budget", summary: ai_spend.GetAISpendSummaryRow{UserSpend: 4.99, ProviderSpend: 50}},
+		{name: "Soft threshold", summary: ai_spend.GetAISpendSummaryRow{UserSpend: 5, ProviderSpend: 50}, wantEvents: []string{webhook.EventBudgetWarning}},
+		{name: "User hard limit", summary: ai_spend.GetAISpendSummaryRow{UserSpend: 10, ProviderSpend: 50}, wantBudget: BudgetScopeUser, wantEvents: []string{webhook.EventBudgetExceeded}},
+		{name: "Provider hard limit", summary: ai_spend.GetAISpendSummaryRow{UserSpend: 1, ProviderSpend: 100}, wantBudget: BudgetScopeProvider, wantEvents: []string{webhook.EventBudgetExceeded}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &recordingPublisher{}
+			service := newTestBudgetService(&fakeSpendRepository{summary: tt.summary}, events)
+
+			// 同一预算在一个月内只通知一次
+			for i := 0; i < 2; i++ {
+				err := service.Check(ctx, req)
+				if tt.wantBudget == "" {
+					require.NoError(t, err)
+					continue
+				}
+				appErr, ok := errors.IsAppError(err)
+				require.True(t, ok)
+				assert.Equal(t, errors.ErrCodeQuotaExceeded, appErr.Code)
+				assert.Equal(t, tt.wantBudget, appErr.Metadata["budget"])
+			}
+			assert.Equal(t, tt.wantEvents, events.events)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		service := newTestBudgetService(&fakeSpendRepository{summary: ai_spend.GetAISpendSummaryRow{UserSpend: 1000}}, nil)
+		service.policy.Enabled = false
+		assert.NoError(t, service.Check(ctx, req))
+	})
+}
+
+func TestBudgetRecord(t *testing.T) {
+	spend := &fakeSpendRepository{}
+	service := newTestBudgetService(spend, nil)
+
+	require.NoError(t, service.Record(context.Background(), BudgetRequest{Provider: "openai"}, "gpt-4o", 10, 5))
+	require.NoError(t, service.Record(context.Background(), BudgetRequest{Provider: "mock"}, "mock-gpt", 10, 5))
+	assert.Equal(t, []float64{20}, spend.recorded)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/repository"
+)
+
+// NewCallerEmailLookup 创建 send_email 工具的调用者查询函数，返回上下文中用户的ID和账号邮箱
+func NewCallerEmailLookup(users repository.UserRepository) tools.CallerEmailFunc {
+	return func(ctx context.Context) (string, string, error) {
+		userID := getUserIDFromContext(ctx)
+		id, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil {
+			return "", "", errors.New("authentication required")
+		}
+		user, err := users.GetByID(ctx, id)
+		if err != nil {
+			return userID, "", err
+		}
+		return userID, user.Email, nil
+	}
+}
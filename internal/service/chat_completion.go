@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/openai"
+
+	"github.com/google/uuid"
+)
+
+// ChatCompletion OpenAI 兼容的聊天补全。
+// 请求带 tools 时通过系统提示让模型按约定格式输出函数调用，解析出的调用以 tool_calls 返回给客户端执行，服务端不执行
+func (s *AIAssistantService) ChatCompletion(ctx context.Context, req *dto.ChatCompletionRequest, userID int64, project string) (*dto.ChatCompletionResponse, error) {
+	tools := offeredTools(req)
+	resp, err := s.Chat(ctx, toCompletionChatRequest(req, tools, userID, project))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from provider")
+	}
+
+	out := &dto.ChatCompletionResponse{
+		ID:      resp.ID,
+		Object:  dto.ChatCompletionObject,
+		Created: resp.Created,
+		Model:   resp.Model,
+		Usage:   resp.Usage,
+	}
+	if out.ID == "" {
+		out.ID = newCompletionID()
+	}
+	if out.Created == 0 {
+		out.Created = time.Now().Unix()
+	}
+	if out.Model == "" {
+		out.Model = req.Model
+	}
+
+	choice := resp.Choices[0]
+	content := choice.Message.Content
+	reply := dto.ChatCompletionChoice{
+		Message:      dto.ChatCompletionReply{Role: "assistant", Content: &content},
+		FinishReason: choice.FinishReason,
+	}
+	if calls := s.completionToolCalls(content, tools); len(calls) > 0 {
+		reply.Message.Content = nil
+		reply.Message.ToolCalls = calls
+		reply.FinishReason = "tool_calls"
+	}
+	if reply.FinishReason == "" {
+		reply.FinishReason = "stop"
+	}
+	out.Choices = []dto.ChatCompletionChoice{reply}
+	return out, nil
+}
+
+// ChatCompletionStream 流式的 OpenAI 兼容聊天补全，每个分块调用一次 send。
+// 带 tools 的请求需要完整回复才能解析函数调用，整段回复拆成内容或调用分块和结束分块返回
+func (s *AIAssistantService) ChatCompletionStream(ctx context.Context, req *dto.ChatCompletionRequest, userID int64, project string, send func(*dto.ChatCompletionChunk) error) error {
+	if tools := offeredTools(req); len(tools) > 0 {
+		resp, err := s.ChatCompletion(ctx, req, userID, project)
+		if err != nil {
+			return err
+		}
+		return sendCompletionChunks(resp, send)
+	}
+
+	var id string
+	created := time.Now().Unix()
+	first := true
+	return s.ChatStream(ctx, toCompletionChatRequest(req, nil, userID, project), func(chunk *ChatStreamChunk) error {
+		if id == "" {
+			id = chunk.ID
+			if id == "" {
+				id = newCompletionID()
+			}
+		}
+		choice := dto.ChatCompletionChunkChoice{Delta: dto.ChatCompletionDelta{Content: chunk.Content}}
+		if first {
+			choice.Delta.Role = "assistant"
+			first = false
+		}
+		if chunk.FinishReason != "" {
+			finishReason := chunk.FinishReason
+			choice.FinishReason = &finishReason
+		}
+		model := chunk.Model
+		if model == "" {
+			model = req.Model
+		}
+		return send(&dto.ChatCompletionChunk{
+			ID:      id,
+			Object:  dto.ChatCompletionChunkObject,
+			Created: created,
+			Model:   model,
+			Choices: []dto.ChatCompletionChunkChoice{choice},
+		})
+	})
+}
+
+// sendCompletionChunks 把完整回复按流式格式发送：先发内容或函数调用，再发结束原因
+func sendCompletionChunks(resp *dto.ChatCompletionResponse, send func(*dto.ChatCompletionChunk) error) error {
+	chunk := func(delta dto.ChatCompletionDelta, finishReason *string) *dto.ChatCompletionChunk {
+		return &dto.ChatCompletionChunk{
+			ID:      resp.ID,
+			Object:  dto.ChatCompletionChunkObject,
+			Created: resp.Created,
+			Model:   resp.Model,
+			Choices: []dto.ChatCompletionChunkChoice{{Delta: delta, FinishReason: finishReason}},
+		}
+	}
+
+	reply := resp.Choices[0]
+	delta := dto.ChatCompletionDelta{Role: "assistant", ToolCalls: reply.Message.ToolCalls}
+	if reply.Message.Content != nil {
+		delta.Content = *reply.Message.Content
+	}
+	for i := range delta.ToolCalls {
+		index := i
+		delta.ToolCalls[i].Index = &index
+	}
+	if err := send(chunk(delta, nil)); err != nil {
+		return err
+	}
+	finishReason := reply.FinishReason
+	return send(chunk(dto.ChatCompletionDelta{}, &finishReason))
+}
+
+// offeredTools 按 tool_choice 确定提供给模型的函数，none 或未定义工具时为空
+func offeredTools(req *dto.ChatCompletionRequest) []dto.ChatCompletionTool {
+	if len(req.Tools) == 0 || (req.ToolChoice != nil && req.ToolChoice.Mode == dto.ToolChoiceNone) {
+		return nil
+	}
+	if req.ToolChoice != nil && req.ToolChoice.Function != "" {
+		for _, tool := range req.Tools {
+			if tool.Function.Name == req.ToolChoice.Function {
+				return []dto.ChatCompletionTool{tool}
+			}
+		}
+		return nil
+	}
+	return req.Tools
+}
+
+// toCompletionChatRequest 转换为助手聊天请求，不使用服务端的MCP工具
+func toCompletionChatRequest(req *dto.ChatCompletionRequest, tools []dto.ChatCompletionTool, userID int64, project string) *ChatRequest {
+	maxTokens := req.MaxTokens
+	if req.MaxCompletionTokens != nil {
+		maxTokens = req.MaxCompletionTokens
+	}
+
+	messages := toCompletionMessages(req.Messages)
+	if len(tools) > 0 {
+		required := req.ToolChoice != nil && req.ToolChoice.Mode == dto.ToolChoiceRequired
+		prompt := buildFunctionsSystemMessage(tools, required)
+		if len(messages) > 0 && messages[0].Role == "system" {
+			messages[0].Content = messages[0].Content + "\n\n" + prompt
+		} else {
+			messages = append([]openai.Message{{Role: "system", Content: prompt}}, messages...)
+		}
+	}
+
+	return &ChatRequest{
+		Messages:    messages,
+		Model:       req.Model,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		Project:     project,
+		UserID:      userID,
+	}
+}
+
+// toCompletionMessages 把函数调用相关的消息转换为提供商都支持的纯文本消息：
+// assistant 的 tool_calls 写成约定的调用格式，tool 消息写成带函数名的结果
+func toCompletionMessages(messages []dto.ChatCompletionMessage) []openai.Message {
+	callNames := make(map[string]string)
+	out := make([]openai.Message, 0, len(messages))
+	for _, msg := range messages {
+		content := string(msg.Content)
+		switch msg.Role {
+		case "developer":
+			out = append(out, openai.Message{Role: "system", Content: content})
+		case "assistant":
+			lines := make([]string, 0, len(msg.ToolCalls)+1)
+			if content != "" {
+				lines = append(lines, content)
+			}
+			for _, call := range msg.ToolCalls {
+				callNames[call.ID] = call.Function.Name
+				lines = append(lines, formatFunctionCall(call.Function))
+			}
+			out = append(out, openai.Message{Role: "assistant", Content: strings.Join(lines, "\n")})
+		case "tool":
+			name := callNames[msg.ToolCallID]
+			if name == "" {
+				name = msg.Name
+			}
+			out = append(out, openai.Message{Role: "user", Content: fmt.Sprintf("Result of function %s:\n%s", name, content)})
+		default:
+			out = append(out, openai.Message{Role: msg.Role, Content: content})
+		}
+	}
+	return out
+}
+
+// formatFunctionCall 按系统提示约定的格式写出函数调用
+func formatFunctionCall(call dto.ChatCompletionFunctionCall) string {
+	arguments := json.RawMessage(call.Arguments)
+	if !json.Valid(arguments) {
+		arguments = json.RawMessage("{}")
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"tool_call": map[string]interface{}{"name": call.Name, "arguments": arguments},
+	})
+	return string(data)
+}
+
+// buildFunctionsSystemMessage 构建客户端函数的系统提示，调用格式与MCP工具相同以便复用解析逻辑
+func buildFunctionsSystemMessage(tools []dto.ChatCompletionTool, required bool) string {
+	var builder strings.Builder
+	builder.WriteString("You can call the functions listed below. To call a function, reply with only a JSON object in this exact format, one line per call:\n")
+	builder.WriteString(`{"tool_call": {"name": "function_name", "arguments": {...}}}`)
+	builder.WriteString("\n")
+	if required {
+		builder.WriteString("You must call one of these functions in this reply.\n")
+	} else {
+		builder.WriteString("If no function is needed, answer normally without JSON.\n")
+	}
+	builder.WriteString("\nFunctions:\n")
+	for _, tool := range tools {
+		builder.WriteString(fmt.Sprintf("### %s\n", tool.Function.Name))
+		if tool.Function.Description != "" {
+			builder.WriteString(fmt.Sprintf("Description: %s\n", tool.Function.Description))
+		}
+		if len(tool.Function.Parameters) > 0 {
+			builder.WriteString(fmt.Sprintf("Parameters: %s\n", string(tool.Function.Parameters)))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// completionToolCalls 解析回复中的函数调用，只保留请求中提供的函数
+func (s *AIAssistantService) completionToolCalls(content string, tools []dto.ChatCompletionTool) []dto.ChatCompletionToolCall {
+	if len(tools) == 0 {
+		return nil
+	}
+	offered := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		offered[tool.Function.Name] = true
+	}
+
+	var calls []dto.ChatCompletionToolCall
+	for _, call := range s.parseToolCalls(content) {
+		if !offered[call.Name] {
+			continue
+		}
+		arguments := call.Arguments
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		data, err := json.Marshal(arguments)
+		if err != nil {
+			continue
+		}
+		calls = append(calls, dto.ChatCompletionToolCall{
+			ID:       "call_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:24],
+			Type:     "function",
+			Function: dto.ChatCompletionFunctionCall{Name: call.Name, Arguments: string(data)},
+		})
+	}
+	return calls
+}
+
+// newCompletionID 生成提供商未返回ID时使用的补全ID
+func newCompletionID() string {
+	return "chatcmpl-" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompletionService(reply string) (*AIAssistantService, *scriptedProvider) {
+	provider := &scriptedProvider{reply: reply}
+	return newTestAssistant(scriptedProviderManager{provider: provider}, testAssistantDeps{}), provider
+}
+
+// decodeCompletionRequest 按客户端发送的JSON解析请求
+func decodeCompletionRequest(t *testing.T, body string) *dto.ChatCompletionRequest {
+	var req dto.ChatCompletionRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &req))
+	return &req
+}
+
+const weatherTools = `"tools": [{"type": "function", "function": {"name": "get_weather", "description": "Current weather", "parameters": {"type": "object", "properties": {"city": {"type": "string"}}}}}]`
+
+func TestChatCompletion(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		reply            string
+		wantContent      *string
+		wantCall         string
+		wantFinishReason string
+		wantPrompt       bool
+	}{
+		{
+			name:             "Plain reply",
+			body:             `{"model": "m", "messages": [{"role": "user", "content": "hi"}]}`,
+			reply:            "hello",
+			wantContent:      strPtr("hello"),
+			wantFinishReason: "stop",
+		},
+		{
+			name:             "Function call",
+			body:             `{"model": "m", "messages": [{"role": "user", "content": "weather in Paris?"}], ` + weatherTools + `}`,
+			reply:            `{"tool_call": {"name": "get_weather", "arguments": {"city": "Paris"}}}`,
+			wantCall:         `get_weather {"city":"Paris"}`,
+			wantFinishReason: "tool_calls",
+			wantPrompt:       true,
+		},
+		{
+			name:             "Call to a function that was not offered",
+			body:             `{"model": "m", "messages": [{"role": "user", "content": "hi"}], ` + weatherTools + `}`,
+			reply:            `{"tool_call": {"name": "delete_files", "arguments": {}}}`,
+			wantContent:      strPtr(`{"tool_call": {"name": "delete_files", "arguments": {}}}`),
+			wantFinishReason: "stop",
+			wantPrompt:       true,
+		},
+		{
+			name:             "Tool choice none",
+			body:             `{"model": "m", "messages": [{"role": "user", "content": "hi"}], ` + weatherTools + `, "tool_choice": "none"}`,
+			reply:            `{"tool_call": {"name": "get_weather", "arguments": {"city": "Paris"}}}`,
+			wantContent:      strPtr(`{"tool_call": {"name": "get_weather", "arguments": {"city": "Paris"}}}`),
+			wantFinishReason: "stop",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, provider := newCompletionService(tt.reply)
+			resp, err := svc.ChatCompletion(context.Background(), decodeCompletionRequest(t, tt.body), 0, "")
+			require.NoError(t, err)
+
+			assert.Equal(t, dto.ChatCompletionObject, resp.Object)
+			assert.Equal(t, 5, resp.Usage.TotalTokens)
+			require.Len(t, resp.Choices, 1)
+			choice := resp.Choices[0]
+			assert.Equal(t, tt.wantFinishReason, choice.FinishReason)
+			assert.Equal(t, tt.wantContent, choice.Message.Content)
+			if tt.wantCall != "" {
+				require.Len(t, choice.Message.ToolCalls, 1)
+				call := choice.Message.ToolCalls[0]
+				assert.Equal(t, "function", call.Type)
+				assert.True(t, strings.HasPrefix(call.ID, "call_"))
+				assert.Equal(t, tt.wantCall, call.Function.Name+" "+call.Function.Arguments)
+			} else {
+				assert.Empty(t, choice.Message.ToolCalls)
+			}
+
+			hasPrompt := provider.messages[0].Role == "system" && strings.Contains(provider.messages[0].Content, "### get_weather")
+			assert.Equal(t, tt.wantPrompt, hasPrompt)
+		})
+	}
+}
+
+func TestChatCompletionToolResults(t *testing.T) {
+	svc, provider := newCompletionService("It is sunny in Paris.")
+	req := decodeCompletionRequest(t, `{"model": "m", `+weatherTools+`, "messages": [
+		{"role": "system", "content": "Be brief."},
+		{"role": "user", "content": [{"type": "text", "text": "weather in "}, {"type": "text", "text": "Paris?"}]},
+		{"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}]},
+		{"role": "tool", "tool_call_id": "call_1", "content": "sunny, 24C"}
+	]}`)
+
+	resp, err := svc.ChatCompletion(context.Background(), req, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny in Paris.", *resp.Choices[0].Message.Content)
+
+	require.Len(t, provider.messages, 4)
+	assert.True(t, strings.HasPrefix(provider.messages[0].Content, "Be brief.\n\n"), "the function prompt is appended to the client's system message")
+	assert.Equal(t, "weather in Paris?", provider.messages[1].Content)
+	assert.Equal(t, ProviderMessage{Role: "assistant", Content: `{"tool_call":{"arguments":{"city":"Paris"},"name":"get_weather"}}`}, provider.messages[2])
+	assert.Equal(t, ProviderMessage{Role: "user", Content: "Result of function get_weather:\nsunny, 24C"}, provider.messages[3])
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		reply            string
+		wantContent      []string
+		wantCalls        int
+		wantFinishReason string
+	}{
+		{
+			name:             "Streams provider chunks",
+			body:             `{"model": "m", "stream": true, "messages": [{"role": "user", "content": "hi"}]}`,
+			reply:            "hello there",
+			wantContent:      []string{"hello ", "there", ""},
+			wantFinishReason: "stop",
+		},
+		{
+			name:             "Function call as a single delta",
+			body:             `{"model": "m", "stream": true, "messages": [{"role": "user", "content": "hi"}], ` + weatherTools + `}`,
+			reply:            `{"tool_call": {"name": "get_weather", "arguments": {"city": "Paris"}}}`,
+			wantContent:      []string{"", ""},
+			wantCalls:        1,
+			wantFinishReason: "tool_calls",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newCompletionService(tt.reply)
+			var chunks []*dto.ChatCompletionChunk
+			err := svc.ChatCompletionStream(context.Background(), decodeCompletionRequest(t, tt.body), 0, "", func(chunk *dto.ChatCompletionChunk) error {
+				chunks = append(chunks, chunk)
+				return nil
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, chunks)
+
+			var content []string
+			var calls int
+			for i, chunk := range chunks {
+				assert.Equal(t, dto.ChatCompletionChunkObject, chunk.Object)
+				assert.Equal(t, chunks[0].ID, chunk.ID)
+				delta := chunk.Choices[0].Delta
+				assert.Equal(t, i == 0, delta.Role == "assistant", "only the first chunk carries the role")
+				content = append(content, delta.Content)
+				for _, call := range delta.ToolCalls {
+					require.NotNil(t, call.Index)
+					calls++
+				}
+			}
+			assert.Equal(t, tt.wantContent, content)
+			assert.Equal(t, tt.wantCalls, calls)
+			last := chunks[len(chunks)-1].Choices[0].FinishReason
+			require.NotNil(t, last)
+			assert.Equal(t, tt.wantFinishReason, *last)
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+)
+
+// defaultEstimateCompletionTokens 请求未设置 max_tokens 时按此回复长度估算费用
+const defaultEstimateCompletionTokens = 500
+
+// EnabledModelLister 按提供商类型列出启用的模型，费用估算未指定候选模型时使用
+type EnabledModelLister interface {
+	ListEnabledModels(ctx context.Context) map[string][]string
+}
+
+// CostEstimator 按模型价格估算一次请求的美元费用，未配置价格的模型返回 false
+type CostEstimator interface {
+	EstimateCost(provider, model string, promptTokens, completionTokens int) (float64, bool)
+}
+
+// CostEstimateCandidate 费用估算的候选模型，Provider 为提供商类型
+type CostEstimateCandidate struct {
+	Provider string `json:"provider" binding:"required"`
+	Model    string `json:"model" binding:"required"`
+}
+
+// CostEstimateRequest 费用估算请求，消息和工具选项与聊天请求相同；未指定候选模型时估算所有启用的模型
+type CostEstimateRequest struct {
+	Messages     []openai.Message        `json:"messages" binding:"required,min=1"`
+	MaxTokens    *int                    `json:"max_tokens,omitempty" binding:"omitempty,min=1"`
+	UseTools     bool                    `json:"use_tools,omitempty"`
+	SelectedTool string                  `json:"selected_tool,omitempty"`
+	Candidates   []CostEstimateCandidate `json:"candidates,omitempty" binding:"max=50,dive"`
+}
+
+// ModelCostEstimate 单个模型的令牌和费用估算，未配置价格时不返回费用
+type ModelCostEstimate struct {
+	Provider         string   `json:"provider"`
+	Model            string   `json:"model"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	EstimatedCost    *float64 `json:"estimated_cost_usd,omitempty"`
+	ContextWindow    int      `json:"context_window,omitempty"`
+	DroppedMessages  int      `json:"dropped_messages,omitempty"`
+}
+
+// CostEstimateResponse 费用估算响应
+type CostEstimateResponse struct {
+	Estimates []ModelCostEstimate `json:"estimates"`
+}
+
+// EstimateCost 估算聊天请求在各候选模型上的提示令牌数和费用，不调用提供商。
+// 提示令牌按发送前的实际消息估算，包括工具说明和上下文窗口截断；回复令牌取 max_tokens，未设置时使用默认值
+func (s *AIAssistantService) EstimateCost(ctx context.Context, req *CostEstimateRequest) (*CostEstimateResponse, error) {
+	candidates, err := s.estimateCandidates(ctx, req.Candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := s.requestTools(ctx, req.UseTools, req.SelectedTool, nil)
+	if err != nil {
+		return nil, err
+	}
+	messages := s.withToolsSystemMessage(toProviderMessages(req.Messages), tools, nil)
+
+	completionTokens := defaultEstimateCompletionTokens
+	if req.MaxTokens != nil {
+		completionTokens = *req.MaxTokens
+	}
+	estimator, _ := s.usageMetrics.(CostEstimator)
+
+	estimates := make([]ModelCostEstimate, 0, len(candidates))
+	for _, candidate := range candidates {
+		contextWindow := s.contextWindow(candidate.Provider, candidate.Model)
+		kept, dropped := trimToContextWindow(messages, contextWindow, req.MaxTokens)
+		estimate := ModelCostEstimate{
+			Provider:         candidate.Provider,
+			Model:            candidate.Model,
+			CompletionTokens: completionTokens,
+			ContextWindow:    contextWindow,
+			DroppedMessages:  dropped,
+		}
+		for _, msg := range kept {
+			estimate.PromptTokens += estimateTokens(msg.Content)
+		}
+		if estimator != nil {
+			if cost, ok := estimator.EstimateCost(candidate.Provider, candidate.Model, estimate.PromptTokens, completionTokens); ok {
+				estimate.EstimatedCost = &cost
+			}
+		}
+		estimates = append(estimates, estimate)
+	}
+	return &CostEstimateResponse{Estimates: estimates}, nil
+}
+
+// estimateCandidates 校验指定的候选模型，未指定时返回所有启用的模型
+func (s *AIAssistantService) estimateCandidates(ctx context.Context, candidates []CostEstimateCandidate) ([]CostEstimateCandidate, error) {
+	lister, ok := s.providerManager.(EnabledModelLister)
+	if !ok {
+		if len(candidates) == 0 {
+			return nil, errors.NewValidationError("候选模型不能为空")
+		}
+		return candidates, nil
+	}
+
+	enabled := lister.ListEnabledModels(ctx)
+	if len(candidates) > 0 {
+		for _, candidate := range candidates {
+			if !slices.Contains(enabled[candidate.Provider], candidate.Model) {
+				return nil, errors.NewValidationError("候选模型不存在或未启用").
+					WithDetails(fmt.Sprintf("%s/%s", candidate.Provider, candidate.Model))
+			}
+		}
+		return candidates, nil
+	}
+
+	providers := make([]string, 0, len(enabled))
+	for providerType := range enabled {
+		providers = append(providers, providerType)
+	}
+	sort.Strings(providers)
+	for _, providerType := range providers {
+		models := append([]string(nil), enabled[providerType]...)
+		sort.Strings(models)
+		for _, model := range models {
+			candidates = append(candidates, CostEstimateCandidate{Provider: providerType, Model: model})
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listingProviderManager 在 scriptedProviderManager 基础上列出启用的模型
+type listingProviderManager struct {
+	scriptedProviderManager
+	enabled map[string][]string
+}
+
+func (m listingProviderManager) ListEnabledModels(context.Context) map[string][]string {
+	return m.enabled
+}
+
+// perTokenEstimator 按每令牌固定单价估算，只为 openai 计价
+type perTokenEstimator struct{}
+
+func (perTokenEstimator) RecordChat(string, string, string, string, int, int) {}
+
+func (perTokenEstimator) EstimateCost(provider, model string, promptTokens, completionTokens int) (float64, bool) {
+	if provider != "openai" {
+		return 0, false
+	}
+	return float64(promptTokens) + 2*float64(completionTokens), true
+}
+
+func TestEstimateCost(t *testing.T) {
+	manager := listingProviderManager{enabled: map[string][]string{
+		"openai": {"large", "small"},
+		"mock":   {"mock-gpt"},
+	}}
+	metadata := fixedModelMetadata{"openai/small": {ContextWindow: 100}}
+	service := newTestAssistant(manager, testAssistantDeps{modelMetadata: metadata, usageMetrics: perTokenEstimator{}})
+
+	// 每条消息约 40 个令牌
+	text := strings.Repeat("x", 160)
+	req := &CostEstimateRequest{Messages: []openai.Message{
+		{Role: "user", Content: text},
+		{Role: "assistant", Content: text},
+		{Role: "user", Content: text},
+	}}
+
+	t.Run("All enabled models", func(t *testing.T) {
+		resp, err := service.EstimateCost(context.Background(), req)
+		require.NoError(t, err)
+		require.Len(t, resp.Estimates, 3)
+
+		mock, large, small := resp.Estimates[0], resp.Estimates[1], resp.Estimates[2]
+		assert.Equal(t, "mock/mock-gpt", mock.Provider+"/"+mock.Model)
+		assert.Nil(t, mock.EstimatedCost)
+
+		assert.Equal(t, "large", large.Model)
+		assert.Equal(t, 120, large.PromptTokens)
+		assert.Equal(t, defaultEstimateCompletionTokens, large.CompletionTokens)
+		require.NotNil(t, large.EstimatedCost)
+		assert.Equal(t, float64(120+2*defaultEstimateCompletionTokens), *large.EstimatedCost)
+
+		assert.Equal(t, "small", small.Model)
+		assert.Equal(t, 100, small.ContextWindow)
+		assert.Equal(t, 1, small.DroppedMessages)
+		assert.Equal(t, 80, small.PromptTokens)
+	})
+
+	t.Run("Selected candidates", func(t *testing.T) {
+		maxTokens := 10
+		resp, err := service.EstimateCost(context.Background(), &CostEstimateRequest{
+			Messages:   req.Messages,
+			MaxTokens:  &maxTokens,
+			Candidates: []CostEstimateCandidate{{Provider: "openai", Model: "large"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Estimates, 1)
+		assert.Equal(t, 10, resp.Estimates[0].CompletionTokens)
+		require.NotNil(t, resp.Estimates[0].EstimatedCost)
+		assert.Equal(t, float64(140), *resp.Estimates[0].EstimatedCost)
+	})
+
+	t.Run("Unknown candidate", func(t *testing.T) {
+		_, err := service.EstimateCost(context.Background(), &CostEstimateRequest{
+			Messages:   req.Messages,
+			Candidates: []CostEstimateCandidate{{Provider: "openai", Model: "missing"}},
+		})
+		appErr, ok := errors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.ErrCodeValidationFailed, appErr.Code)
+	})
+}
@@ -9,22 +9,35 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
+	"time"
 
+	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/errors"
 	"go-springAi/internal/repository"
+	"go-springAi/internal/secrets"
 )
 
+// secretRefPrefix 密钥存于外部存储时，数据库中只保存该前缀加存储名称的引用
+const secretRefPrefix = "secret:"
+
 // DatabaseKeyManager 基于数据库的密钥管理器
 type DatabaseKeyManager struct {
 	mu           sync.RWMutex
 	userID       int64
+	projectID    int64 // 0 表示用户的默认密钥
 	providerType string
 	encryptKey   []byte
 	repo         repository.APIKeyRepository
+	// 轮换后旧密钥仍可使用的时长
+	rotationGrace time.Duration
+	// 外部密钥存储，为 nil 时密钥加密后存入数据库
+	store secrets.Store
 }
 
 // NewDatabaseKeyManager 创建新的数据库密钥管理器
-func NewDatabaseKeyManager(userID int64, providerType string, repo repository.APIKeyRepository) *DatabaseKeyManager {
+func NewDatabaseKeyManager(userID, projectID int64, providerType string, repo repository.APIKeyRepository, rotationGrace time.Duration, store secrets.Store) *DatabaseKeyManager {
 	// 使用固定的加密密钥（实际应用中应该从配置中获取）
 	// 这里使用SHA256哈希生成固定的32字节密钥
 	fixedSeed := "go-springAi-encryption-key-v1.0"
@@ -32,10 +45,13 @@ func NewDatabaseKeyManager(userID int64, providerType string, repo repository.AP
 	encryptKey := hash[:]
 	
 	return &DatabaseKeyManager{
-		userID:       userID,
-		providerType: providerType,
-		encryptKey:   encryptKey,
-		repo:         repo,
+		userID:        userID,
+		projectID:     projectID,
+		providerType:  providerType,
+		encryptKey:    encryptKey,
+		repo:          repo,
+		rotationGrace: rotationGrace,
+		store:         store,
 	}
 }
 
@@ -50,25 +66,43 @@ func (km *DatabaseKeyManager) SetAPIKey(key string) error {
 	
 	ctx := context.Background()
 	
-	// 加密密钥
-	encryptedKey, err := km.EncryptKey(key)
-	if err != nil {
-		return fmt.Errorf("failed to encrypt key: %w", err)
-	}
-	
 	// 生成密钥哈希
 	keyHash := km.generateKeyHash(key)
 	
 	// 检查是否已存在
-	exists, err := km.repo.CheckAPIKeyExists(ctx, km.userID, km.providerType)
+	exists, err := km.repo.CheckAPIKeyExists(ctx, km.userID, km.projectID, km.providerType)
 	if err != nil {
 		return fmt.Errorf("failed to check key existence: %w", err)
 	}
 	
+	var previous *api_keys.ApiKey
+	if exists {
+		previous, err = km.repo.GetAPIKey(ctx, km.userID, km.projectID, km.providerType)
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+	}
+	
+	// 外部存储中的当前密钥会被覆盖，先读出旧值用于补记版本
+	var previousKey string
+	if previous != nil && previous.KeyHash != keyHash {
+		previousKey, err = km.openKey(ctx, previous.EncryptedKey)
+		if err != nil {
+			return fmt.Errorf("failed to read previous key: %w", err)
+		}
+	}
+	
+	// 加密密钥，启用外部存储时写入存储并只保留引用
+	encryptedKey, err := km.sealKey(ctx, km.secretName("current"), key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	
 	if exists {
 		// 更新现有密钥
 		_, err = km.repo.UpdateAPIKey(ctx, repository.UpdateAPIKeyParams{
 			UserID:       km.userID,
+			ProjectID:    km.projectID,
 			ProviderType: km.providerType,
 			EncryptedKey: encryptedKey,
 			KeyHash:      keyHash,
@@ -80,6 +114,7 @@ func (km *DatabaseKeyManager) SetAPIKey(key string) error {
 		// 创建新密钥
 		_, err = km.repo.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
 			UserID:       km.userID,
+			ProjectID:    km.projectID,
 			ProviderType: km.providerType,
 			EncryptedKey: encryptedKey,
 			KeyHash:      keyHash,
@@ -90,7 +125,89 @@ func (km *DatabaseKeyManager) SetAPIKey(key string) error {
 		}
 	}
 	
-	return nil
+	// 与当前密钥相同时不产生新版本
+	if previous != nil && previous.KeyHash == keyHash {
+		return nil
+	}
+	
+	return km.recordVersion(ctx, previous, previousKey, key, keyHash)
+}
+
+// recordVersion 记录新的密钥版本，并让上一版本进入宽限期
+func (km *DatabaseKeyManager) recordVersion(ctx context.Context, previous *api_keys.ApiKey, previousKey, key, keyHash string) error {
+	var version int64
+	latest, err := km.repo.GetLatestAPIKeyVersion(ctx, km.userID, km.projectID, km.providerType)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+			return err
+		}
+		// 启用版本记录之前设置的密钥补记为第一个版本
+		if previous != nil {
+			sealed, err := km.sealKey(ctx, km.secretName("v1"), previousKey)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt key: %w", err)
+			}
+			if _, err := km.repo.CreateAPIKeyVersion(ctx, repository.CreateAPIKeyVersionParams{
+				UserID:       km.userID,
+				ProjectID:    km.projectID,
+				ProviderType: km.providerType,
+				Version:      1,
+				EncryptedKey: sealed,
+				KeyHash:      previous.KeyHash,
+			}); err != nil {
+				return err
+			}
+			version = 1
+		}
+	} else {
+		version = latest.Version
+	}
+	
+	if version > 0 {
+		// 只有刚被替换的版本进入宽限期，更早的版本立即失效
+		now := time.Now()
+		if err := km.repo.ExpireAPIKeyVersionGrace(ctx, km.userID, km.projectID, km.providerType, now); err != nil {
+			return err
+		}
+		if err := km.repo.RetireAPIKeyVersions(ctx, km.userID, km.projectID, km.providerType, now.Add(km.rotationGrace)); err != nil {
+			return err
+		}
+	}
+	
+	sealed, err := km.sealKey(ctx, km.secretName(fmt.Sprintf("v%d", version+1)), key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	
+	_, err = km.repo.CreateAPIKeyVersion(ctx, repository.CreateAPIKeyVersionParams{
+		UserID:       km.userID,
+		ProjectID:    km.projectID,
+		ProviderType: km.providerType,
+		Version:      version + 1,
+		EncryptedKey: sealed,
+		KeyHash:      keyHash,
+	})
+	return err
+}
+
+// GetPreviousAPIKey 获取仍处于宽限期的上一版本密钥及其失效时间
+func (km *DatabaseKeyManager) GetPreviousAPIKey() (string, time.Time, error) {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	
+	ctx := context.Background()
+	
+	version, err := km.repo.GetPreviousAPIKeyVersion(ctx, km.userID, km.projectID, km.providerType, time.Now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	
+	decryptedKey, err := km.openKey(ctx, version.EncryptedKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decrypt key: %w", err)
+	}
+	
+	return decryptedKey, version.GraceExpiresAt.Time, nil
 }
 
 // GetAPIKey 获取 API 密钥
@@ -100,7 +217,7 @@ func (km *DatabaseKeyManager) GetAPIKey() (string, error) {
 	
 	ctx := context.Background()
 	
-	apiKey, err := km.repo.GetAPIKey(ctx, km.userID, km.providerType)
+	apiKey, err := km.repo.GetAPIKey(ctx, km.userID, km.projectID, km.providerType)
 	if err != nil {
 		return "", fmt.Errorf("failed to get API key: %w", err)
 	}
@@ -109,8 +226,13 @@ func (km *DatabaseKeyManager) GetAPIKey() (string, error) {
 		return "", fmt.Errorf("API key is inactive")
 	}
 	
+	// 过期的密钥不再用于调用提供商
+	if err := km.checkExpiration(ctx, apiKey); err != nil {
+		return "", err
+	}
+	
 	// 解密密钥
-	decryptedKey, err := km.DecryptKey(apiKey.EncryptedKey)
+	decryptedKey, err := km.openKey(ctx, apiKey.EncryptedKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt key: %w", err)
 	}
@@ -118,6 +240,44 @@ func (km *DatabaseKeyManager) GetAPIKey() (string, error) {
 	return decryptedKey, nil
 }
 
+// GetStoredAPIKey 获取当前保存的密钥，不检查是否过期，用于脱敏展示
+func (km *DatabaseKeyManager) GetStoredAPIKey() (string, error) {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	
+	ctx := context.Background()
+	
+	apiKey, err := km.repo.GetAPIKey(ctx, km.userID, km.projectID, km.providerType)
+	if err != nil {
+		return "", fmt.Errorf("failed to get API key: %w", err)
+	}
+	
+	decryptedKey, err := km.openKey(ctx, apiKey.EncryptedKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt key: %w", err)
+	}
+	
+	return decryptedKey, nil
+}
+
+// checkExpiration 密钥已被标记为过期或已到过期时间时返回错误，密钥更换后旧的过期时间不再适用
+func (km *DatabaseKeyManager) checkExpiration(ctx context.Context, apiKey *api_keys.ApiKey) error {
+	expiration, err := km.repo.GetAPIKeyExpiration(ctx, apiKey.ID)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get API key expiration: %w", err)
+	}
+	if expiration.KeyHash != apiKey.KeyHash {
+		return nil
+	}
+	if expiration.ExpiredAt.Valid || !expiration.ExpiresAt.After(time.Now()) {
+		return errors.NewBadRequestError(fmt.Sprintf("API key expired at %s", expiration.ExpiresAt.UTC().Format(time.RFC3339)))
+	}
+	return nil
+}
+
 // ValidateKey 验证 API 密钥格式
 func (km *DatabaseKeyManager) ValidateKey(key string) error {
 	if key == "" {
@@ -212,6 +372,37 @@ func (km *DatabaseKeyManager) DecryptKey(encryptedKey string) (string, error) {
 	return string(plaintext), nil
 }
 
+// secretName 返回当前用户、项目和提供商下某个密钥在外部存储中的名称
+func (km *DatabaseKeyManager) secretName(slot string) string {
+	if km.projectID > 0 {
+		return fmt.Sprintf("api-keys/%d/projects/%d/%s/%s", km.userID, km.projectID, km.providerType, slot)
+	}
+	return fmt.Sprintf("api-keys/%d/%s/%s", km.userID, km.providerType, slot)
+}
+
+// sealKey 保存密钥，返回写入数据库的内容：外部存储的引用或加密后的密钥
+func (km *DatabaseKeyManager) sealKey(ctx context.Context, name, key string) (string, error) {
+	if km.store == nil {
+		return km.EncryptKey(key)
+	}
+	if err := km.store.Put(ctx, name, key); err != nil {
+		return "", err
+	}
+	return secretRefPrefix + name, nil
+}
+
+// openKey 还原 sealKey 保存的密钥，未使用外部存储时写入的旧数据仍可解密
+func (km *DatabaseKeyManager) openKey(ctx context.Context, stored string) (string, error) {
+	name, ok := strings.CutPrefix(stored, secretRefPrefix)
+	if !ok {
+		return km.DecryptKey(stored)
+	}
+	if km.store == nil {
+		return "", fmt.Errorf("key %s is kept in an external secrets store that is not configured", name)
+	}
+	return km.store.Get(ctx, name)
+}
+
 // generateKeyHash 生成密钥哈希
 func (km *DatabaseKeyManager) generateKeyHash(key string) string {
 	hash := sha256.Sum256([]byte(key))
@@ -225,7 +416,7 @@ func (km *DatabaseKeyManager) IsActive() (bool, error) {
 	
 	ctx := context.Background()
 	
-	apiKey, err := km.repo.GetAPIKey(ctx, km.userID, km.providerType)
+	apiKey, err := km.repo.GetAPIKey(ctx, km.userID, km.projectID, km.providerType)
 	if err != nil {
 		return false, err
 	}
@@ -240,15 +431,16 @@ func (km *DatabaseKeyManager) Deactivate() error {
 	
 	ctx := context.Background()
 	
-	return km.repo.DeactivateAPIKey(ctx, km.userID, km.providerType)
+	return km.repo.DeactivateAPIKey(ctx, km.userID, km.projectID, km.providerType)
 }
 
-// Delete 删除密钥
+// Delete 软删除密钥，可通过仓库层恢复
 func (km *DatabaseKeyManager) Delete() error {
 	km.mu.Lock()
 	defer km.mu.Unlock()
 	
 	ctx := context.Background()
 	
-	return km.repo.DeleteAPIKey(ctx, km.userID, km.providerType)
+	// 外部存储中的密钥和历史版本一并保留，恢复后仍可使用，重新设置密钥时覆盖
+	return km.repo.DeleteAPIKey(ctx, km.userID, km.projectID, km.providerType)
 }
\ No newline at end of file
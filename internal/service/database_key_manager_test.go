@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database/generated/api_keys"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExpiringKeyRepository 只保存一个当前密钥及其过期时间
+type fakeExpiringKeyRepository struct {
+	repository.APIKeyRepository
+	key        *api_keys.ApiKey
+	expiration *api_keys.ApiKeyExpiration
+}
+
+func (f *fakeExpiringKeyRepository) GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (*api_keys.ApiKey, error) {
+	return f.key, nil
+}
+
+func (f *fakeExpiringKeyRepository) GetAPIKeyExpiration(ctx context.Context, apiKeyID int64) (*api_keys.ApiKeyExpiration, error) {
+	if f.expiration == nil {
+		return nil, errors.NewNotFoundError("API key expiration")
+	}
+	return f.expiration, nil
+}
+
+func TestDatabaseKeyManagerRejectsExpiredKeys(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		expiration *api_keys.ApiKeyExpiration
+		expired    bool
+	}{
+		{name: "no expiry"},
+		{name: "expires later", expiration: &api_keys.ApiKeyExpiration{KeyHash: "current", ExpiresAt: now.Add(time.Hour)}},
+		{name: "past expiry not yet marked", expiration: &api_keys.ApiKeyExpiration{KeyHash: "current", ExpiresAt: now.Add(-time.Minute)}, expired: true},
+		{name: "marked expired", expiration: &api_keys.ApiKeyExpiration{KeyHash: "current", ExpiresAt: now.Add(time.Hour),
+			ExpiredAt: sql.NullTime{Time: now, Valid: true}}, expired: true},
+		// 密钥更换后旧的过期时间不再适用
+		{name: "expiry of replaced key", expiration: &api_keys.ApiKeyExpiration{KeyHash: "previous", ExpiresAt: now.Add(-time.Hour),
+			ExpiredAt: sql.NullTime{Time: now, Valid: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeExpiringKeyRepository{expiration: tt.expiration}
+			km := NewDatabaseKeyManager(7, 0, "openai", repo, 0, nil)
+			encrypted, err := km.EncryptKey("sk-test-1234567890")
+			require.NoError(t, err)
+			repo.key = &api_keys.ApiKey{ID: 1, UserID: 7, ProviderType: "openai", EncryptedKey: encrypted, KeyHash: "current",
+				IsActive: sql.NullBool{Bool: true, Valid: true}}
+
+			key, err := km.GetAPIKey()
+			if tt.expired {
+				appErr, ok := errors.IsAppError(err)
+				require.True(t, ok)
+				assert.Equal(t, errors.ErrCodeBadRequest, appErr.Code)
+				assert.Empty(t, key)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "sk-test-1234567890", key)
+			}
+
+			// 过期的密钥仍可脱敏展示
+			stored, err := km.GetStoredAPIKey()
+			require.NoError(t, err)
+			assert.Equal(t, "sk-test-1234567890", stored)
+		})
+	}
+}
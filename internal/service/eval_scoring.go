@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-springAi/internal/dto"
+)
+
+// evalJudgePassScore 评审分数不低于该值时用例才算通过
+const evalJudgePassScore = 0.6
+
+// defaultEvalRubric 用例未提供评分标准时评审使用的标准
+const defaultEvalRubric = "The response answers the prompt correctly, completely and concisely, without fabricated facts."
+
+// judgeSystemPrompt 评审模型的系统提示，要求只返回 JSON
+const judgeSystemPrompt = `You are a strict evaluator of AI assistant responses. Score the response against the rubric on a scale from 0 to 10 and reply with JSON only, for example {"score": 7, "reason": "one short sentence"}.`
+
+var judgeNumberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// runEvalChecks 按预期标准对回复执行启发式检查，文本匹配不区分大小写
+func runEvalChecks(criteria dto.EvalCriteria, output string, latencyMs int64) []dto.EvalCheck {
+	var checks []dto.EvalCheck
+	lower := strings.ToLower(output)
+	for _, want := range criteria.Contains {
+		checks = append(checks, dto.EvalCheck{
+			Name:   "contains",
+			Passed: strings.Contains(lower, strings.ToLower(want)),
+			Detail: want,
+		})
+	}
+	for _, unwanted := range criteria.NotContains {
+		checks = append(checks, dto.EvalCheck{
+			Name:   "not_contains",
+			Passed: !strings.Contains(lower, strings.ToLower(unwanted)),
+			Detail: unwanted,
+		})
+	}
+	if criteria.Regex != "" {
+		check := dto.EvalCheck{Name: "regex", Detail: criteria.Regex}
+		if re, err := regexp.Compile(criteria.Regex); err == nil {
+			check.Passed = re.MatchString(output)
+		}
+		checks = append(checks, check)
+	}
+	if criteria.MaxLatencyMs > 0 {
+		checks = append(checks, dto.EvalCheck{
+			Name:   "max_latency_ms",
+			Passed: latencyMs <= criteria.MaxLatencyMs,
+			Detail: fmt.Sprintf("%dms / %dms", latencyMs, criteria.MaxLatencyMs),
+		})
+	}
+	return checks
+}
+
+// scoreEvalResult 计算启发式分数和总分，总分为启发式和评审分数的平均值，都没有时成功回复记 1 分
+func scoreEvalResult(result *dto.EvalCaseResult) {
+	var scores []float64
+	passed := true
+	if len(result.Checks) > 0 {
+		ok := 0
+		for _, check := range result.Checks {
+			if check.Passed {
+				ok++
+			} else {
+				passed = false
+			}
+		}
+		heuristic := roundEvalScore(float64(ok) / float64(len(result.Checks)))
+		result.HeuristicScore = &heuristic
+		scores = append(scores, heuristic)
+	}
+	if result.JudgeScore != nil {
+		scores = append(scores, *result.JudgeScore)
+		if *result.JudgeScore < evalJudgePassScore {
+			passed = false
+		}
+	}
+
+	result.Score = 1
+	if len(scores) > 0 {
+		total := 0.0
+		for _, score := range scores {
+			total += score
+		}
+		result.Score = roundEvalScore(total / float64(len(scores)))
+	}
+	result.Passed = passed
+}
+
+// summarizeEvalResults 汇总一个模型在所有用例上的结果，出错的用例记 0 分且不计入平均延迟
+func summarizeEvalResults(provider, model string, results []dto.EvalCaseResult) dto.EvalTargetSummary {
+	summary := dto.EvalTargetSummary{Provider: provider, Model: model, Cases: len(results)}
+	if len(results) == 0 {
+		return summary
+	}
+
+	totalScore := 0.0
+	var totalLatency int64
+	for _, r := range results {
+		if r.Error != "" {
+			summary.Errors++
+			continue
+		}
+		if r.Passed {
+			summary.Passed++
+		}
+		totalScore += r.Score
+		totalLatency += r.LatencyMs
+	}
+	summary.PassRate = roundEvalScore(float64(summary.Passed) / float64(len(results)))
+	summary.AvgScore = roundEvalScore(totalScore / float64(len(results)))
+	if answered := len(results) - summary.Errors; answered > 0 {
+		summary.AvgLatencyMs = totalLatency / int64(answered)
+	}
+	return summary
+}
+
+// buildJudgeMessages 构造评审请求
+func buildJudgeMessages(c dto.EvalCase, output string) []ProviderMessage {
+	rubric := c.Criteria.Rubric
+	if rubric == "" {
+		rubric = defaultEvalRubric
+	}
+	var prompt strings.Builder
+	prompt.WriteString("Rubric:\n" + rubric + "\n\n")
+	if c.System != "" {
+		prompt.WriteString("System prompt:\n" + c.System + "\n\n")
+	}
+	prompt.WriteString("User prompt:\n" + c.Prompt + "\n\n")
+	prompt.WriteString("Response:\n" + output)
+
+	return []ProviderMessage{
+		{Role: "system", Content: judgeSystemPrompt},
+		{Role: "user", Content: prompt.String()},
+	}
+}
+
+// parseJudgeScore 解析评审回复中的 0~10 分并归一化为 0~1，JSON 解析失败时取第一个数字
+func parseJudgeScore(content string) (float64, string, error) {
+	var verdict struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end <= start || json.Unmarshal([]byte(content[start:end+1]), &verdict) != nil {
+		match := judgeNumberPattern.FindString(content)
+		if match == "" {
+			return 0, "", fmt.Errorf("judge reply contains no score: %q", content)
+		}
+		verdict.Score, _ = strconv.ParseFloat(match, 64)
+		verdict.Reason = strings.TrimSpace(content)
+	}
+
+	score := math.Max(0, math.Min(10, verdict.Score)) / 10
+	return roundEvalScore(score), verdict.Reason, nil
+}
+
+// roundEvalScore 分数保留三位小数
+func roundEvalScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/database/generated/evals"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// 评测运行状态
+const (
+	EvalRunStatusRunning   = "running"
+	EvalRunStatusCompleted = "completed"
+	EvalRunStatusFailed    = "failed"
+)
+
+const (
+	// evalCallTimeout 单次模型调用（包括评审）的超时时间
+	evalCallTimeout = 2 * time.Minute
+	// defaultEvalRunLimit、maxEvalRunLimit 运行记录列表的默认和最大条数
+	defaultEvalRunLimit = 20
+	maxEvalRunLimit     = 100
+	// maxEvalCompareRuns 一次最多对比的运行数
+	maxEvalCompareRuns = 10
+)
+
+// EvalService 提示词评测服务接口
+type EvalService interface {
+	// ListSuites 获取所有评测套件
+	ListSuites(ctx context.Context) (*dto.EvalSuiteListResponse, error)
+	// GetSuite 获取评测套件
+	GetSuite(ctx context.Context, id int64) (*dto.EvalSuiteResponse, error)
+	// CreateSuite 创建评测套件
+	CreateSuite(ctx context.Context, userID int64, req *dto.CreateEvalSuiteRequest) (*dto.EvalSuiteResponse, error)
+	// UpdateSuite 更新评测套件的名称、描述或用例
+	UpdateSuite(ctx context.Context, id int64, req *dto.UpdateEvalSuiteRequest) (*dto.EvalSuiteResponse, error)
+	// DeleteSuite 删除评测套件及其运行记录
+	DeleteSuite(ctx context.Context, id int64) error
+	// StartRun 校验模型后在后台运行评测套件，立即返回运行中的记录
+	StartRun(ctx context.Context, suiteID, userID int64, req *dto.CreateEvalRunRequest) (*dto.EvalRunResponse, error)
+	// ListRuns 获取套件最近的运行记录，不包含逐用例结果
+	ListRuns(ctx context.Context, suiteID int64, limit int) (*dto.EvalRunListResponse, error)
+	// GetRun 获取运行记录及逐用例结果
+	GetRun(ctx context.Context, id int64) (*dto.EvalRunResponse, error)
+	// Compare 对比同一套件的多次已完成运行
+	Compare(ctx context.Context, runIDs []int64) (*dto.EvalComparisonResponse, error)
+}
+
+// evalService 提示词评测服务实现
+type evalService struct {
+	repo      repository.EvalRepository
+	providers ProviderManager
+	now       func() time.Time
+	logger    *zap.Logger
+	wg        sync.WaitGroup // 后台运行中的评测
+}
+
+// NewEvalService 创建提示词评测服务
+func NewEvalService(repoManager repository.RepositoryManager, providers ProviderManager, logger *zap.Logger) EvalService {
+	return &evalService{
+		repo:      repoManager.Eval(),
+		providers: providers,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// ListSuites 获取所有评测套件
+func (s *evalService) ListSuites(ctx context.Context) (*dto.EvalSuiteListResponse, error) {
+	suites, err := s.repo.ListSuites(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list eval suites", err)
+	}
+
+	result := &dto.EvalSuiteListResponse{Suites: make([]dto.EvalSuiteResponse, 0, len(suites))}
+	for i := range suites {
+		result.Suites = append(result.Suites, *toEvalSuiteResponse(&suites[i]))
+	}
+	return result, nil
+}
+
+// GetSuite 获取评测套件
+func (s *evalService) GetSuite(ctx context.Context, id int64) (*dto.EvalSuiteResponse, error) {
+	suite, err := s.repo.GetSuite(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEvalSuiteResponse(suite), nil
+}
+
+// CreateSuite 创建评测套件
+func (s *evalService) CreateSuite(ctx context.Context, userID int64, req *dto.CreateEvalSuiteRequest) (*dto.EvalSuiteResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkSuiteName(ctx, 0, name); err != nil {
+		return nil, err
+	}
+	cases, err := encodeEvalCases(req.Cases)
+	if err != nil {
+		return nil, err
+	}
+
+	suite, err := s.repo.CreateSuite(ctx, evals.CreateEvalSuiteParams{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Cases:       cases,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create eval suite", err)
+	}
+
+	s.logger.Info("Eval suite created",
+		zap.Int64("suite_id", suite.ID),
+		zap.String("name", suite.Name),
+		zap.Int("cases", len(req.Cases)))
+	return toEvalSuiteResponse(suite), nil
+}
+
+// UpdateSuite 更新评测套件的名称、描述或用例
+func (s *evalService) UpdateSuite(ctx context.Context, id int64, req *dto.UpdateEvalSuiteRequest) (*dto.EvalSuiteResponse, error) {
+	suite, err := s.repo.GetSuite(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := evals.UpdateEvalSuiteParams{
+		ID:          suite.ID,
+		Name:        suite.Name,
+		Description: suite.Description,
+		Cases:       suite.Cases,
+	}
+	if req.Name != nil {
+		params.Name = strings.TrimSpace(*req.Name)
+		if err := s.checkSuiteName(ctx, suite.ID, params.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		params.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Cases != nil {
+		if params.Cases, err = encodeEvalCases(req.Cases); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.UpdateSuite(ctx, params)
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewDatabaseError("update eval suite", err)
+	}
+
+	s.logger.Info("Eval suite updated", zap.Int64("suite_id", updated.ID), zap.String("name", updated.Name))
+	return toEvalSuiteResponse(updated), nil
+}
+
+// DeleteSuite 删除评测套件及其运行记录
+func (s *evalService) DeleteSuite(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteSuite(ctx, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete eval suite", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Eval suite")
+	}
+
+	s.logger.Info("Eval suite deleted", zap.Int64("suite_id", id))
+	return nil
+}
+
+// StartRun 校验模型后在后台运行评测套件，立即返回运行中的记录
+func (s *evalService) StartRun(ctx context.Context, suiteID, userID int64, req *dto.CreateEvalRunRequest) (*dto.EvalRunResponse, error) {
+	suite, err := s.repo.GetSuite(ctx, suiteID)
+	if err != nil {
+		return nil, err
+	}
+	var cases []dto.EvalCase
+	if err := json.Unmarshal([]byte(suite.Cases), &cases); err != nil {
+		return nil, errors.NewInternalError("评测用例格式错误").WithCause(err)
+	}
+
+	targets := append([]dto.EvalTarget{}, req.Targets...)
+	if req.Judge != nil {
+		targets = append(targets, *req.Judge)
+	}
+	for _, target := range targets {
+		if _, err := s.resolveTarget(ctx, target); err != nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("模型 %s 不可用", target.Model)).WithDetails(err.Error())
+		}
+	}
+
+	targetsJSON, err := json.Marshal(req.Targets)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to encode eval targets").WithCause(err)
+	}
+	judgeJSON := ""
+	if req.Judge != nil {
+		data, err := json.Marshal(req.Judge)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to encode eval judge").WithCause(err)
+		}
+		judgeJSON = string(data)
+	}
+
+	run, err := s.repo.CreateRun(ctx, evals.CreateEvalRunParams{
+		SuiteID:   suite.ID,
+		Status:    EvalRunStatusRunning,
+		Targets:   string(targetsJSON),
+		Judge:     judgeJSON,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create eval run", err)
+	}
+
+	s.logger.Info("Eval run started",
+		zap.Int64("run_id", run.ID),
+		zap.Int64("suite_id", suite.ID),
+		zap.Int("cases", len(cases)),
+		zap.Int("targets", len(req.Targets)))
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(context.Background(), run.ID, cases, req)
+	}()
+
+	return toEvalRunResponse(run, false), nil
+}
+
+// ListRuns 获取套件最近的运行记录，不包含逐用例结果
+func (s *evalService) ListRuns(ctx context.Context, suiteID int64, limit int) (*dto.EvalRunListResponse, error) {
+	if _, err := s.repo.GetSuite(ctx, suiteID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultEvalRunLimit
+	}
+	if limit > maxEvalRunLimit {
+		limit = maxEvalRunLimit
+	}
+
+	runs, err := s.repo.ListRuns(ctx, suiteID, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list eval runs", err)
+	}
+
+	result := &dto.EvalRunListResponse{Runs: make([]dto.EvalRunResponse, 0, len(runs))}
+	for i := range runs {
+		result.Runs = append(result.Runs, *toEvalRunResponse(&runs[i], false))
+	}
+	return result, nil
+}
+
+// GetRun 获取运行记录及逐用例结果
+func (s *evalService) GetRun(ctx context.Context, id int64) (*dto.EvalRunResponse, error) {
+	run, err := s.repo.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toEvalRunResponse(run, true), nil
+}
+
+// Compare 对比同一套件的多次已完成运行，每个运行中的每个模型为一项
+func (s *evalService) Compare(ctx context.Context, runIDs []int64) (*dto.EvalComparisonResponse, error) {
+	if len(runIDs) == 0 || len(runIDs) > maxEvalCompareRuns {
+		return nil, errors.NewValidationError(fmt.Sprintf("对比的运行数必须在 1 到 %d 之间", maxEvalCompareRuns))
+	}
+
+	result := &dto.EvalComparisonResponse{Entries: []dto.EvalComparisonEntry{}}
+	var suiteID int64
+	for _, id := range runIDs {
+		run, err := s.repo.GetRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Status != EvalRunStatusCompleted {
+			return nil, errors.NewValidationError(fmt.Sprintf("评测运行 %d 尚未完成", id))
+		}
+		if suiteID != 0 && run.SuiteID != suiteID {
+			return nil, errors.NewValidationError("只能对比同一评测套件的运行")
+		}
+		suiteID = run.SuiteID
+
+		resp := toEvalRunResponse(run, true)
+		for _, summary := range resp.Summary {
+			entry := dto.EvalComparisonEntry{
+				RunID:             run.ID,
+				EvalTargetSummary: summary,
+				CaseScores:        make(map[string]float64),
+			}
+			for _, r := range resp.Results {
+				if r.Provider == summary.Provider && r.Model == summary.Model {
+					entry.CaseScores[r.Case] = r.Score
+				}
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		return result.Entries[i].AvgScore > result.Entries[j].AvgScore
+	})
+	if len(result.Entries) > 0 {
+		best := result.Entries[0]
+		result.Best = &best
+	}
+	return result, nil
+}
+
+// execute 依次在每个模型上运行所有用例并保存结果
+func (s *evalService) execute(ctx context.Context, runID int64, cases []dto.EvalCase, req *dto.CreateEvalRunRequest) {
+	results := make([]dto.EvalCaseResult, 0, len(cases)*len(req.Targets))
+	summaries := make([]dto.EvalTargetSummary, 0, len(req.Targets))
+
+	var judge ProviderInterface
+	if req.Judge != nil {
+		var err error
+		if judge, err = s.resolveTarget(ctx, *req.Judge); err != nil {
+			s.logger.Warn("评审模型不可用，只使用启发式评分", zap.Int64("run_id", runID), zap.Error(err))
+		}
+	}
+
+	for _, target := range req.Targets {
+		provider, resolveErr := s.resolveTarget(ctx, target)
+		providerName := target.Provider
+		if provider != nil && providerName == "" {
+			providerName = provider.GetName()
+		}
+
+		targetResults := make([]dto.EvalCaseResult, 0, len(cases))
+		for _, c := range cases {
+			result := dto.EvalCaseResult{Case: c.Name, Provider: providerName, Model: target.Model}
+			if resolveErr != nil {
+				result.Error = resolveErr.Error()
+			} else {
+				s.evaluate(ctx, provider, judge, req, c, &result)
+			}
+			targetResults = append(targetResults, result)
+		}
+		summaries = append(summaries, summarizeEvalResults(providerName, target.Model, targetResults))
+		results = append(results, targetResults...)
+	}
+
+	status := EvalRunStatusCompleted
+	runErr := ""
+	if failed := firstEvalError(results); failed != "" {
+		status = EvalRunStatusFailed
+		runErr = failed
+	}
+
+	resultsJSON, _ := json.Marshal(results)
+	summaryJSON, _ := json.Marshal(summaries)
+	if err := s.repo.FinishRun(ctx, evals.FinishEvalRunParams{
+		ID:         runID,
+		Status:     status,
+		Results:    string(resultsJSON),
+		Summary:    string(summaryJSON),
+		Error:      runErr,
+		FinishedAt: sql.NullTime{Time: s.now(), Valid: true},
+	}); err != nil {
+		s.logger.Error("保存评测结果失败", zap.Int64("run_id", runID), zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Eval run finished", zap.Int64("run_id", runID), zap.String("status", status), zap.Int("results", len(results)))
+}
+
+// evaluate 调用模型回答一个用例，并按启发式检查和评审模型打分
+func (s *evalService) evaluate(ctx context.Context, provider, judge ProviderInterface, req *dto.CreateEvalRunRequest, c dto.EvalCase, result *dto.EvalCaseResult) {
+	var messages []ProviderMessage
+	if c.System != "" {
+		messages = append(messages, ProviderMessage{Role: "system", Content: c.System})
+	}
+	messages = append(messages, ProviderMessage{Role: "user", Content: c.Prompt})
+
+	callCtx, cancel := context.WithTimeout(ctx, evalCallTimeout)
+	defer cancel()
+	start := s.now()
+	resp, err := provider.ChatCompletion(callCtx, &ProviderChatRequest{
+		Model:       result.Model,
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	})
+	result.LatencyMs = s.now().Sub(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if len(resp.Choices) > 0 {
+		result.Output = resp.Choices[0].Message.Content
+	}
+	result.PromptTokens = resp.Usage.PromptTokens
+	result.CompletionTokens = resp.Usage.CompletionTokens
+
+	result.Checks = runEvalChecks(c.Criteria, result.Output, result.LatencyMs)
+	if judge != nil {
+		score, reason, err := s.judge(ctx, judge, req.Judge.Model, c, result.Output)
+		if err != nil {
+			result.JudgeReason = "评审失败: " + err.Error()
+		} else {
+			result.JudgeScore = &score
+			result.JudgeReason = reason
+		}
+	}
+	scoreEvalResult(result)
+}
+
+// judge 让评审模型按评分标准给回复打分，返回 0~1 的分数
+func (s *evalService) judge(ctx context.Context, judge ProviderInterface, model string, c dto.EvalCase, output string) (float64, string, error) {
+	callCtx, cancel := context.WithTimeout(ctx, evalCallTimeout)
+	defer cancel()
+
+	temperature := float32(0)
+	resp, err := judge.ChatCompletion(callCtx, &ProviderChatRequest{
+		Model:       model,
+		Messages:    buildJudgeMessages(c, output),
+		Temperature: &temperature,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if len(resp.Choices) == 0 {
+		return 0, "", fmt.Errorf("judge returned no choices")
+	}
+	return parseJudgeScore(resp.Choices[0].Message.Content)
+}
+
+// resolveTarget 按提供商名称或模型名称选择提供商，并校验模型可用
+func (s *evalService) resolveTarget(ctx context.Context, target dto.EvalTarget) (ProviderInterface, error) {
+	return resolveProviderModel(ctx, s.providers, target.Provider, target.Model)
+}
+
+// resolveProviderModel 按提供商名称或模型名称选择提供商，并校验模型可用
+func resolveProviderModel(ctx context.Context, providers ProviderManager, providerName, model string) (ProviderInterface, error) {
+	if providerName == "" {
+		return providers.GetProviderByModelWithValidation(ctx, model)
+	}
+	provider, err := providers.GetProviderByName(providerName)
+	if err != nil {
+		return nil, err
+	}
+	if err := providers.ValidateModelForProvider(ctx, providerName, model); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// checkSuiteName 校验套件名称不为空且不与其他套件重复
+func (s *evalService) checkSuiteName(ctx context.Context, id int64, name string) error {
+	if name == "" {
+		return errors.NewValidationError("评测套件名称不能为空")
+	}
+	suites, err := s.repo.ListSuites(ctx)
+	if err != nil {
+		return errors.NewDatabaseError("list eval suites", err)
+	}
+	for _, suite := range suites {
+		if suite.ID != id && strings.EqualFold(suite.Name, name) {
+			return errors.NewConflictError(fmt.Sprintf("评测套件 %s 已存在", name))
+		}
+	}
+	return nil
+}
+
+// encodeEvalCases 校验用例名称不重复、正则表达式有效，并编码为 JSON
+func encodeEvalCases(cases []dto.EvalCase) (string, error) {
+	seen := make(map[string]bool, len(cases))
+	normalized := make([]dto.EvalCase, len(cases))
+	for i, c := range cases {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" || strings.TrimSpace(c.Prompt) == "" {
+			return "", errors.NewValidationError("评测用例的名称和提示词不能为空")
+		}
+		if seen[c.Name] {
+			return "", errors.NewValidationError(fmt.Sprintf("评测用例名称 %s 重复", c.Name))
+		}
+		seen[c.Name] = true
+		if c.Criteria.Regex != "" {
+			if _, err := regexp.Compile(c.Criteria.Regex); err != nil {
+				return "", errors.NewValidationError(fmt.Sprintf("评测用例 %s 的正则表达式无效", c.Name)).WithDetails(err.Error())
+			}
+		}
+		normalized[i] = c
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to encode eval cases").WithCause(err)
+	}
+	return string(data), nil
+}
+
+// firstEvalError 所有结果都出错时返回第一个错误，否则返回空字符串
+func firstEvalError(results []dto.EvalCaseResult) string {
+	for _, r := range results {
+		if r.Error == "" {
+			return ""
+		}
+	}
+	if len(results) == 0 {
+		return ""
+	}
+	return results[0].Error
+}
+
+// toEvalSuiteResponse 转换为评测套件响应
+func toEvalSuiteResponse(suite *evals.EvalSuite) *dto.EvalSuiteResponse {
+	resp := &dto.EvalSuiteResponse{
+		ID:          suite.ID,
+		Name:        suite.Name,
+		Description: suite.Description,
+		Cases:       []dto.EvalCase{},
+		CreatedBy:   suite.CreatedBy,
+		CreatedAt:   suite.CreatedAt.Time,
+		UpdatedAt:   suite.UpdatedAt.Time,
+	}
+	_ = json.Unmarshal([]byte(suite.Cases), &resp.Cases)
+	return resp
+}
+
+// toEvalRunResponse 转换为评测运行响应，withResults 为 false 时不返回逐用例结果
+func toEvalRunResponse(run *evals.EvalRun, withResults bool) *dto.EvalRunResponse {
+	resp := &dto.EvalRunResponse{
+		ID:        run.ID,
+		SuiteID:   run.SuiteID,
+		Status:    run.Status,
+		Targets:   []dto.EvalTarget{},
+		Error:     run.Error,
+		CreatedBy: run.CreatedBy,
+		CreatedAt: run.CreatedAt.Time,
+	}
+	_ = json.Unmarshal([]byte(run.Targets), &resp.Targets)
+	if run.Judge != "" {
+		var judge dto.EvalTarget
+		if json.Unmarshal([]byte(run.Judge), &judge) == nil {
+			resp.Judge = &judge
+		}
+	}
+	if run.Summary != "" {
+		_ = json.Unmarshal([]byte(run.Summary), &resp.Summary)
+	}
+	if withResults && run.Results != "" {
+		_ = json.Unmarshal([]byte(run.Results), &resp.Results)
+	}
+	if run.FinishedAt.Valid {
+		finishedAt := run.FinishedAt.Time
+		resp.FinishedAt = &finishedAt
+	}
+	return resp
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/database/generated/evals"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryEvalRepository 内存中的评测套件和运行记录
+type memoryEvalRepository struct {
+	suites []*evals.EvalSuite
+	runs   []*evals.EvalRun
+}
+
+func (r *memoryEvalRepository) CreateSuite(ctx context.Context, params evals.CreateEvalSuiteParams) (*evals.EvalSuite, error) {
+	suite := &evals.EvalSuite{ID: int64(len(r.suites) + 1), Name: params.Name, Description: params.Description, Cases: params.Cases, CreatedBy: params.CreatedBy}
+	r.suites = append(r.suites, suite)
+	copied := *suite
+	return &copied, nil
+}
+
+func (r *memoryEvalRepository) UpdateSuite(ctx context.Context, params evals.UpdateEvalSuiteParams) (*evals.EvalSuite, error) {
+	return updateRow(r.suites, "Eval suite", suiteByID(params.ID), func(suite *evals.EvalSuite) {
+		suite.Name, suite.Description, suite.Cases = params.Name, params.Description, params.Cases
+	})
+}
+
+func (r *memoryEvalRepository) DeleteSuite(ctx context.Context, id int64) (bool, error) {
+	return deleteRow(&r.suites, suiteByID(id)), nil
+}
+
+func (r *memoryEvalRepository) GetSuite(ctx context.Context, id int64) (*evals.EvalSuite, error) {
+	return findRow(r.suites, "Eval suite", suiteByID(id))
+}
+
+func (r *memoryEvalRepository) ListSuites(ctx context.Context) ([]evals.EvalSuite, error) {
+	return listRows(r.suites, anyRow[evals.EvalSuite]), nil
+}
+
+func (r *memoryEvalRepository) CreateRun(ctx context.Context, params evals.CreateEvalRunParams) (*evals.EvalRun, error) {
+	run := &evals.EvalRun{ID: int64(len(r.runs) + 1), SuiteID: params.SuiteID, Status: params.Status, Targets: params.Targets, Judge: params.Judge, CreatedBy: params.CreatedBy}
+	r.runs = append(r.runs, run)
+	copied := *run
+	return &copied, nil
+}
+
+func (r *memoryEvalRepository) FinishRun(ctx context.Context, params evals.FinishEvalRunParams) error {
+	updateRow(r.runs, "Eval run", runByID(params.ID), func(run *evals.EvalRun) {
+		run.Status, run.Results, run.Summary, run.Error, run.FinishedAt = params.Status, params.Results, params.Summary, params.Error, params.FinishedAt
+	})
+	return nil
+}
+
+func (r *memoryEvalRepository) GetRun(ctx context.Context, id int64) (*evals.EvalRun, error) {
+	return findRow(r.runs, "Eval run", runByID(id))
+}
+
+func (r *memoryEvalRepository) ListRuns(ctx context.Context, suiteID int64, limit int) ([]evals.EvalRun, error) {
+	return latestRows(r.runs, limit, func(run *evals.EvalRun) bool { return run.SuiteID == suiteID }), nil
+}
+
+func suiteByID(id int64) func(*evals.EvalSuite) bool {
+	return func(suite *evals.EvalSuite) bool { return suite.ID == id }
+}
+
+func runByID(id int64) func(*evals.EvalRun) bool {
+	return func(run *evals.EvalRun) bool { return run.ID == id }
+}
+
+func TestEvalService(t *testing.T) {
+	ctx := context.Background()
+	service := &evalService{
+		repo: &memoryEvalRepository{},
+		providers: newModelRepliesManager(map[string]string{
+			"good-model":  "Paris is the capital of France.",
+			"bad-model":   "I am not sure, maybe Lyon.",
+			"judge-model": `Verdict: {"score": 8, "reason": "accurate"}`,
+		}),
+		now:    fixedNow,
+		logger: zap.NewNop(),
+	}
+
+	_, err := service.CreateSuite(ctx, 1, &dto.CreateEvalSuiteRequest{
+		Name:  "geo",
+		Cases: []dto.EvalCase{{Name: "capital", Prompt: "Capital of France?", Criteria: dto.EvalCriteria{Regex: "("}}},
+	})
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	suite, err := service.CreateSuite(ctx, 1, &dto.CreateEvalSuiteRequest{
+		Name: "geo",
+		Cases: []dto.EvalCase{{
+			Name:     "capital",
+			Prompt:   "Capital of France?",
+			Criteria: dto.EvalCriteria{Contains: []string{"paris"}, NotContains: []string{"not sure"}, MaxLatencyMs: 1000},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, suite.Cases, 1)
+
+	_, err = service.CreateSuite(ctx, 1, &dto.CreateEvalSuiteRequest{Name: "GEO", Cases: suite.Cases})
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	// 不可用的模型在启动前被拒绝
+	_, err = service.StartRun(ctx, suite.ID, 1, &dto.CreateEvalRunRequest{Targets: []dto.EvalTarget{{Model: "missing"}}})
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	run, err := service.StartRun(ctx, suite.ID, 1, &dto.CreateEvalRunRequest{
+		Targets: []dto.EvalTarget{{Model: "good-model"}, {Provider: "mock", Model: "bad-model"}},
+		Judge:   &dto.EvalTarget{Model: "judge-model"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, EvalRunStatusRunning, run.Status)
+	service.wg.Wait()
+
+	run, err = service.GetRun(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, EvalRunStatusCompleted, run.Status)
+	require.NotNil(t, run.FinishedAt)
+	require.Len(t, run.Results, 2)
+
+	good := run.Results[0]
+	assert.Equal(t, "mock", good.Provider)
+	assert.True(t, good.Passed)
+	assert.Equal(t, 1.0, *good.HeuristicScore)
+	assert.Equal(t, 0.8, *good.JudgeScore)
+	assert.Equal(t, 0.9, good.Score)
+
+	bad := run.Results[1]
+	assert.False(t, bad.Passed)
+	assert.Equal(t, 0.333, *bad.HeuristicScore)
+	assert.Len(t, bad.Checks, 3)
+
+	require.Len(t, run.Summary, 2)
+	assert.Equal(t, 1.0, run.Summary[0].PassRate)
+	assert.Equal(t, 0.0, run.Summary[1].PassRate)
+
+	list, err := service.ListRuns(ctx, suite.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, list.Runs, 1)
+	assert.Empty(t, list.Runs[0].Results)
+
+	comparison, err := service.Compare(ctx, []int64{run.ID})
+	require.NoError(t, err)
+	require.Len(t, comparison.Entries, 2)
+	require.NotNil(t, comparison.Best)
+	assert.Equal(t, "good-model", comparison.Best.Model)
+	assert.Equal(t, 0.9, comparison.Best.CaseScores["capital"])
+
+	require.NoError(t, service.DeleteSuite(ctx, suite.ID))
+	_, err = service.GetSuite(ctx, suite.ID)
+	assertAppErrorCode(t, err, errors.ErrCodeNotFound)
+}
+
+func TestParseJudgeScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+		wantErr bool
+	}{
+		{name: "JSON", content: `{"score": 7, "reason": "ok"}`, want: 0.7},
+		{name: "JSON in prose", content: "Here you go: {\"score\": 10}\nThanks", want: 1},
+		{name: "Plain number", content: "Score: 4/10", want: 0.4},
+		{name: "Clamped", content: `{"score": 15}`, want: 1},
+		{name: "No score", content: "looks good", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _, err := parseJudgeScore(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, score)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/archive"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+
+	"go.uber.org/zap"
+)
+
+// 执行日志归档文件名为 execution-logs/<最早开始时间>_<最晚开始时间>_<条数>.jsonl.gz
+const (
+	executionLogArchivePrefix = "execution-logs/"
+	executionLogArchiveSuffix = ".jsonl.gz"
+	executionLogArchiveTime   = "20060102T150405.000000000Z"
+)
+
+// 归档查询返回的日志条数
+const (
+	DefaultArchivedLogLimit = 100
+	MaxArchivedLogLimit     = 1000
+)
+
+// ExecutionLogArchiveService 执行日志归档服务接口
+type ExecutionLogArchiveService interface {
+	// Archive 将超过保留期的执行日志压缩写入归档存储并从内存中删除，没有可归档的日志时返回 nil
+	Archive(ctx context.Context) (*dto.MCPExecutionLogArchive, error)
+	// ListArchives 列出与 [from, to) 有交集的归档，参数为 nil 表示不限
+	ListArchives(ctx context.Context, from, to *time.Time) ([]dto.MCPExecutionLogArchive, error)
+	// QueryArchived 按时间范围、用户和工具查询已归档的执行日志
+	QueryArchived(ctx context.Context, query *dto.MCPExecutionLogArchiveQuery) ([]*dto.MCPToolExecutionLog, error)
+}
+
+// executionLogArchiveService 执行日志归档服务实现
+type executionLogArchiveService struct {
+	mcpService MCPService
+	store      archive.Store
+	retention  time.Duration
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// NewExecutionLogArchiveService 创建执行日志归档服务，store 为 nil 时归档未启用，retentionDays 为内存中保留的天数
+func NewExecutionLogArchiveService(mcpService MCPService, store archive.Store, retentionDays int, logger *zap.Logger) ExecutionLogArchiveService {
+	return &executionLogArchiveService{
+		mcpService: mcpService,
+		store:      store,
+		retention:  time.Duration(retentionDays) * 24 * time.Hour,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// Archive 将超过保留期的执行日志压缩写入归档存储，写入成功后才从内存中删除
+func (s *executionLogArchiveService) Archive(ctx context.Context) (*dto.MCPExecutionLogArchive, error) {
+	if s.store == nil {
+		return nil, errors.NewServiceUnavailableError("execution log archive")
+	}
+
+	logs := s.mcpService.ExpiredExecutionLogs(s.now().Add(-s.retention))
+	if len(logs) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, log := range logs {
+		if err := encoder.Encode(log); err != nil {
+			return nil, fmt.Errorf("encode execution log %s: %w", log.ID, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress execution logs: %w", err)
+	}
+
+	result := &dto.MCPExecutionLogArchive{
+		From:  logs[0].StartTime.UTC(),
+		To:    logs[len(logs)-1].StartTime.UTC(),
+		Count: len(logs),
+		Size:  int64(buf.Len()),
+	}
+	result.Name = executionLogArchiveName(result)
+	if err := s.store.Put(ctx, result.Name, buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("write execution log archive: %w", err)
+	}
+
+	ids := make([]string, len(logs))
+	for i, log := range logs {
+		ids[i] = log.ID
+	}
+	s.mcpService.DeleteExecutionLogs(ids)
+
+	s.logger.Info("Archived execution logs",
+		zap.String("archive", result.Name),
+		zap.Int("count", result.Count),
+		zap.Int64("size", result.Size))
+	return result, nil
+}
+
+// ListArchives 列出与 [from, to) 有交集的归档，按开始时间排序
+func (s *executionLogArchiveService) ListArchives(ctx context.Context, from, to *time.Time) ([]dto.MCPExecutionLogArchive, error) {
+	if s.store == nil {
+		return nil, errors.NewServiceUnavailableError("execution log archive")
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, errors.NewValidationError("时间范围无效").WithDetails("to must be after from")
+	}
+
+	objects, err := s.store.List(ctx, executionLogArchivePrefix)
+	if err != nil {
+		return nil, errors.NewNetworkError("list execution log archives", err)
+	}
+
+	archives := []dto.MCPExecutionLogArchive{}
+	for _, object := range objects {
+		archive, ok := parseExecutionLogArchiveName(object.Name)
+		if !ok {
+			continue
+		}
+		if (from != nil && archive.To.Before(*from)) || (to != nil && !archive.From.Before(*to)) {
+			continue
+		}
+		archive.Size = object.Size
+		archives = append(archives, archive)
+	}
+	return archives, nil
+}
+
+// QueryArchived 依次读取与时间范围有交集的归档，返回符合条件的前 limit 条日志
+func (s *executionLogArchiveService) QueryArchived(ctx context.Context, query *dto.MCPExecutionLogArchiveQuery) ([]*dto.MCPToolExecutionLog, error) {
+	archives, err := s.ListArchives(ctx, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultArchivedLogLimit
+	}
+	if limit > MaxArchivedLogLimit {
+		limit = MaxArchivedLogLimit
+	}
+
+	logs := []*dto.MCPToolExecutionLog{}
+	for _, archive := range archives {
+		data, err := s.store.Get(ctx, archive.Name)
+		if err != nil {
+			return nil, errors.NewNetworkError("read execution log archive", err)
+		}
+		entries, err := decodeExecutionLogArchive(data)
+		if err != nil {
+			return nil, errors.NewInternalError("执行日志归档已损坏").WithDetails(archive.Name).WithCause(err)
+		}
+
+		for _, log := range entries {
+			if query.From != nil && log.StartTime.Before(*query.From) {
+				continue
+			}
+			if query.To != nil && !log.StartTime.Before(*query.To) {
+				continue
+			}
+			if query.UserID != "" && (log.UserID == nil || *log.UserID != query.UserID) {
+				continue
+			}
+			if query.ToolName != "" && log.ToolName != query.ToolName {
+				continue
+			}
+			logs = append(logs, log)
+			if len(logs) == limit {
+				return logs, nil
+			}
+		}
+	}
+	return logs, nil
+}
+
+// executionLogArchiveName 生成归档文件名，时间精确到纳秒以免与相邻归档重名
+func executionLogArchiveName(a *dto.MCPExecutionLogArchive) string {
+	return executionLogArchivePrefix + a.From.Format(executionLogArchiveTime) + "_" +
+		a.To.Format(executionLogArchiveTime) + "_" + strconv.Itoa(a.Count) + executionLogArchiveSuffix
+}
+
+// parseExecutionLogArchiveName 从归档文件名解析时间范围和条数
+func parseExecutionLogArchiveName(name string) (dto.MCPExecutionLogArchive, bool) {
+	base := strings.TrimPrefix(name, executionLogArchivePrefix)
+	if !strings.HasSuffix(base, executionLogArchiveSuffix) {
+		return dto.MCPExecutionLogArchive{}, false
+	}
+	parts := strings.Split(strings.TrimSuffix(base, executionLogArchiveSuffix), "_")
+	if len(parts) != 3 {
+		return dto.MCPExecutionLogArchive{}, false
+	}
+	from, err1 := time.Parse(executionLogArchiveTime, parts[0])
+	to, err2 := time.Parse(executionLogArchiveTime, parts[1])
+	count, err3 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return dto.MCPExecutionLogArchive{}, false
+	}
+	return dto.MCPExecutionLogArchive{Name: name, From: from, To: to, Count: count}, true
+}
+
+// decodeExecutionLogArchive 解压并逐行解析归档
+func decodeExecutionLogArchive(data []byte) ([]*dto.MCPToolExecutionLog, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var logs []*dto.MCPToolExecutionLog
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var log dto.MCPToolExecutionLog
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			return nil, err
+		}
+		logs = append(logs, &log)
+	}
+	return logs, scanner.Err()
+}
+
+// ExecutionLogArchiveJob 定期归档执行日志的后台任务
+type ExecutionLogArchiveJob struct {
+	archiveService ExecutionLogArchiveService
+	interval       time.Duration
+	logger         *zap.Logger
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewExecutionLogArchiveJob 创建执行日志归档任务，interval 不大于0时任务不会启动
+func NewExecutionLogArchiveJob(archiveService ExecutionLogArchiveService, interval time.Duration, logger *zap.Logger) *ExecutionLogArchiveJob {
+	return &ExecutionLogArchiveJob{
+		archiveService: archiveService,
+		interval:       interval,
+		logger:         logger,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start 启动归档任务，启动时立即执行一次
+func (j *ExecutionLogArchiveJob) Start() {
+	if j.interval <= 0 {
+		j.logger.Info("Execution log archive job disabled")
+		return
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run()
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	j.logger.Info("Execution log archive job started", zap.Duration("interval", j.interval))
+}
+
+// Stop 停止归档任务并等待当前执行结束
+func (j *ExecutionLogArchiveJob) Stop() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	j.wg.Wait()
+}
+
+// run 执行一次归档
+func (j *ExecutionLogArchiveJob) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if _, err := j.archiveService.Archive(ctx); err != nil {
+		j.logger.Error("归档执行日志失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-springAi/internal/archive"
+	"go-springAi/internal/dto"
+
+	"go.uber.org/zap"
+)
+
+func TestExecutionLogArchive(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	store, err := archive.NewLocalStore(archive.LocalConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop()).(*MCPServiceImpl)
+	alice := "1"
+	addLog := func(id, tool string, start time.Time, finished bool, userID *string) {
+		log := &dto.MCPToolExecutionLog{ID: id, ToolName: tool, StartTime: start, UserID: userID}
+		if finished {
+			end := start.Add(time.Second)
+			log.EndTime = &end
+		}
+		mcpService.executionLogs[id] = log
+	}
+	addLog("old-1", "stock_quote", now.AddDate(0, 0, -10), true, &alice)
+	addLog("old-2", "stock_analysis", now.AddDate(0, 0, -9), true, nil)
+	addLog("running", "stock_quote", now.AddDate(0, 0, -9), false, nil)
+	addLog("recent", "stock_quote", now.AddDate(0, 0, -1), true, nil)
+
+	svc := NewExecutionLogArchiveService(mcpService, store, 7, zap.NewNop()).(*executionLogArchiveService)
+	svc.now = func() time.Time { return now }
+
+	created, err := svc.Archive(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created == nil || created.Count != 2 || !created.From.Equal(now.AddDate(0, 0, -10)) || !created.To.Equal(now.AddDate(0, 0, -9)) {
+		t.Fatalf("unexpected archive %+v", created)
+	}
+	for id, want := range map[string]bool{"old-1": false, "old-2": false, "running": true, "recent": true} {
+		if _, ok := mcpService.executionLogs[id]; ok != want {
+			t.Errorf("log %s kept in memory = %v, want %v", id, ok, want)
+		}
+	}
+
+	// 没有新的过期日志时不生成归档
+	if again, err := svc.Archive(ctx); err != nil || again != nil {
+		t.Fatalf("second archive: got %+v, %v", again, err)
+	}
+
+	from := now.AddDate(0, 0, -30)
+	archives, err := svc.ListArchives(ctx, &from, nil)
+	if err != nil || len(archives) != 1 || archives[0].Name != created.Name || archives[0].Size == 0 {
+		t.Fatalf("ListArchives: got %+v, %v", archives, err)
+	}
+	to := now.AddDate(0, 0, -11)
+	if archives, err := svc.ListArchives(ctx, nil, &to); err != nil || len(archives) != 0 {
+		t.Fatalf("ListArchives before archive: got %+v, %v", archives, err)
+	}
+
+	tests := []struct {
+		name  string
+		query dto.MCPExecutionLogArchiveQuery
+		want  []string
+	}{
+		{name: "All", want: []string{"old-1", "old-2"}},
+		{name: "By user", query: dto.MCPExecutionLogArchiveQuery{UserID: "1"}, want: []string{"old-1"}},
+		{name: "By tool", query: dto.MCPExecutionLogArchiveQuery{ToolName: "stock_analysis"}, want: []string{"old-2"}},
+		{name: "Limit", query: dto.MCPExecutionLogArchiveQuery{Limit: 1}, want: []string{"old-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, err := svc.QueryArchived(ctx, &tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, log := range logs {
+				ids = append(ids, log.ID)
+			}
+			if len(ids) != len(tt.want) || (len(ids) > 0 && ids[0] != tt.want[0]) || (len(ids) > 1 && ids[1] != tt.want[1]) {
+				t.Errorf("expected %v, got %v", tt.want, ids)
+			}
+		})
+	}
+}
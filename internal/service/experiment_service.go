@@ -0,0 +1,564 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/experiments"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// A/B 实验状态
+const (
+	ExperimentStatusDraft   = "draft"
+	ExperimentStatusRunning = "running"
+	ExperimentStatusStopped = "stopped"
+)
+
+// DefaultPersona 请求未指定助手角色时使用的角色
+const DefaultPersona = "default"
+
+const (
+	// defaultExperimentTrafficSplit 默认分配到变体 B 的流量百分比
+	defaultExperimentTrafficSplit = 50
+	// experimentMinSamples 每个变体至少需要的样本数，不足时不比较该指标
+	experimentMinSamples = 20
+	// experimentSignificanceZ 双比例 z 检验 95% 置信度的临界值
+	experimentSignificanceZ = 1.96
+	// experimentLatencyMargin 平均延迟至少快这个比例才算胜出
+	experimentLatencyMargin = 0.2
+)
+
+// ExperimentAssignment 请求被分配到的实验变体
+type ExperimentAssignment struct {
+	ExperimentID int64
+	Variant      string // a / b
+	Config       dto.ExperimentVariant
+}
+
+// ExperimentOutcome 一个参与实验的请求的结果
+type ExperimentOutcome struct {
+	ExperimentID int64
+	Variant      string
+	ResponseID   string
+	UserID       int64
+	Success      bool
+	LatencyMs    int64
+	ToolCalls    int
+	ToolFailures int
+}
+
+// ExperimentAssigner 为聊天请求分配实验变体并记录结果
+type ExperimentAssigner interface {
+	// Assign 为角色正在运行的实验分配变体，没有运行中的实验时返回 nil
+	Assign(ctx context.Context, persona string, userID int64) (*ExperimentAssignment, error)
+	// RecordOutcome 记录请求的结果
+	RecordOutcome(ctx context.Context, outcome ExperimentOutcome) error
+}
+
+// ExperimentService A/B 实验服务接口
+type ExperimentService interface {
+	ExperimentAssigner
+	// List 获取所有实验
+	List(ctx context.Context) (*dto.ExperimentListResponse, error)
+	// Get 获取实验
+	Get(ctx context.Context, id int64) (*dto.ExperimentResponse, error)
+	// Create 创建草稿状态的实验
+	Create(ctx context.Context, userID int64, req *dto.CreateExperimentRequest) (*dto.ExperimentResponse, error)
+	// Update 更新草稿状态的实验
+	Update(ctx context.Context, id int64, req *dto.UpdateExperimentRequest) (*dto.ExperimentResponse, error)
+	// Delete 删除实验及其结果
+	Delete(ctx context.Context, id int64) error
+	// Start 校验变体的模型后开始实验，同一角色同时只能运行一个实验
+	Start(ctx context.Context, id int64) (*dto.ExperimentResponse, error)
+	// Stop 停止运行中的实验，已记录的结果保留
+	Stop(ctx context.Context, id int64) (*dto.ExperimentResponse, error)
+	// Report 汇总各变体的结果并判断胜出的变体
+	Report(ctx context.Context, id int64) (*dto.ExperimentReport, error)
+	// RecordFeedback 记录用户对回复的评价，返回回复是否参与了实验
+	RecordFeedback(ctx context.Context, responseID string, positive bool) (bool, error)
+}
+
+// experimentService A/B 实验服务实现
+type experimentService struct {
+	repo      repository.ExperimentRepository
+	providers ProviderManager
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewExperimentService 创建 A/B 实验服务
+func NewExperimentService(repoManager repository.RepositoryManager, providers ProviderManager, logger *zap.Logger) ExperimentService {
+	return &experimentService{
+		repo:      repoManager.Experiment(),
+		providers: providers,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// List 获取所有实验
+func (s *experimentService) List(ctx context.Context) (*dto.ExperimentListResponse, error) {
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list experiments", err)
+	}
+
+	result := &dto.ExperimentListResponse{Experiments: make([]dto.ExperimentResponse, 0, len(items))}
+	for i := range items {
+		result.Experiments = append(result.Experiments, *toExperimentResponse(&items[i]))
+	}
+	return result, nil
+}
+
+// Get 获取实验
+func (s *experimentService) Get(ctx context.Context, id int64) (*dto.ExperimentResponse, error) {
+	experiment, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toExperimentResponse(experiment), nil
+}
+
+// Create 创建草稿状态的实验
+func (s *experimentService) Create(ctx context.Context, userID int64, req *dto.CreateExperimentRequest) (*dto.ExperimentResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkName(ctx, 0, name); err != nil {
+		return nil, err
+	}
+	variantA, variantB, err := encodeExperimentVariants(req.VariantA, req.VariantB)
+	if err != nil {
+		return nil, err
+	}
+	split := defaultExperimentTrafficSplit
+	if req.TrafficSplit != nil {
+		split = *req.TrafficSplit
+	}
+
+	experiment, err := s.repo.Create(ctx, experiments.CreateExperimentParams{
+		Name:         name,
+		Description:  strings.TrimSpace(req.Description),
+		Persona:      NormalizePersona(req.Persona),
+		TrafficSplit: int64(split),
+		VariantA:     variantA,
+		VariantB:     variantB,
+		CreatedBy:    userID,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create experiment", err)
+	}
+
+	s.logger.Info("Experiment created",
+		zap.Int64("experiment_id", experiment.ID),
+		zap.String("name", experiment.Name),
+		zap.String("persona", experiment.Persona))
+	return toExperimentResponse(experiment), nil
+}
+
+// Update 更新草稿状态的实验，已开始的实验修改变体会让结果无法比较
+func (s *experimentService) Update(ctx context.Context, id int64, req *dto.UpdateExperimentRequest) (*dto.ExperimentResponse, error) {
+	experiment, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status != ExperimentStatusDraft {
+		return nil, errors.NewConflictError("只能修改草稿状态的实验")
+	}
+
+	params := experiments.UpdateExperimentParams{
+		ID:           experiment.ID,
+		Name:         experiment.Name,
+		Description:  experiment.Description,
+		Persona:      experiment.Persona,
+		TrafficSplit: experiment.TrafficSplit,
+		VariantA:     experiment.VariantA,
+		VariantB:     experiment.VariantB,
+	}
+	if req.Name != nil {
+		params.Name = strings.TrimSpace(*req.Name)
+		if err := s.checkName(ctx, experiment.ID, params.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		params.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Persona != nil {
+		params.Persona = NormalizePersona(*req.Persona)
+	}
+	if req.TrafficSplit != nil {
+		params.TrafficSplit = int64(*req.TrafficSplit)
+	}
+	if req.VariantA != nil || req.VariantB != nil {
+		current := toExperimentResponse(experiment)
+		variantA, variantB := current.VariantA, current.VariantB
+		if req.VariantA != nil {
+			variantA = *req.VariantA
+		}
+		if req.VariantB != nil {
+			variantB = *req.VariantB
+		}
+		if params.VariantA, params.VariantB, err = encodeExperimentVariants(variantA, variantB); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, params)
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewDatabaseError("update experiment", err)
+	}
+
+	s.logger.Info("Experiment updated", zap.Int64("experiment_id", updated.ID), zap.String("name", updated.Name))
+	return toExperimentResponse(updated), nil
+}
+
+// Delete 删除实验及其结果
+func (s *experimentService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete experiment", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Experiment")
+	}
+
+	s.logger.Info("Experiment deleted", zap.Int64("experiment_id", id))
+	return nil
+}
+
+// Start 校验变体的模型后开始实验，同一角色同时只能运行一个实验
+func (s *experimentService) Start(ctx context.Context, id int64) (*dto.ExperimentResponse, error) {
+	experiment, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status == ExperimentStatusRunning {
+		return nil, errors.NewConflictError("实验已在运行")
+	}
+
+	running, err := s.repo.GetRunningByPersona(ctx, experiment.Persona)
+	if err == nil {
+		return nil, errors.NewConflictError(fmt.Sprintf("角色 %s 已有运行中的实验 %s", experiment.Persona, running.Name))
+	}
+	if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+		return nil, errors.NewDatabaseError("get running experiment", err)
+	}
+
+	current := toExperimentResponse(experiment)
+	for _, variant := range []dto.ExperimentVariant{current.VariantA, current.VariantB} {
+		if variant.Model == "" {
+			continue
+		}
+		if _, err := resolveProviderModel(ctx, s.providers, variant.Provider, variant.Model); err != nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("模型 %s 不可用", variant.Model)).WithDetails(err.Error())
+		}
+	}
+
+	startedAt := experiment.StartedAt
+	if !startedAt.Valid {
+		startedAt = sql.NullTime{Time: s.now(), Valid: true}
+	}
+	updated, err := s.repo.UpdateStatus(ctx, experiments.UpdateExperimentStatusParams{
+		ID:        experiment.ID,
+		Status:    ExperimentStatusRunning,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("start experiment", err)
+	}
+
+	s.logger.Info("Experiment started", zap.Int64("experiment_id", updated.ID), zap.String("persona", updated.Persona))
+	return toExperimentResponse(updated), nil
+}
+
+// Stop 停止运行中的实验，已记录的结果保留
+func (s *experimentService) Stop(ctx context.Context, id int64) (*dto.ExperimentResponse, error) {
+	experiment, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status != ExperimentStatusRunning {
+		return nil, errors.NewConflictError("实验未在运行")
+	}
+
+	updated, err := s.repo.UpdateStatus(ctx, experiments.UpdateExperimentStatusParams{
+		ID:        experiment.ID,
+		Status:    ExperimentStatusStopped,
+		StartedAt: experiment.StartedAt,
+		StoppedAt: sql.NullTime{Time: s.now(), Valid: true},
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("stop experiment", err)
+	}
+
+	s.logger.Info("Experiment stopped", zap.Int64("experiment_id", updated.ID))
+	return toExperimentResponse(updated), nil
+}
+
+// Report 汇总各变体的结果并判断胜出的变体
+func (s *experimentService) Report(ctx context.Context, id int64) (*dto.ExperimentReport, error) {
+	experiment, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.VariantStats(ctx, experiment.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("get experiment stats", err)
+	}
+
+	stats := map[string]dto.ExperimentVariantStats{"a": {Variant: "a"}, "b": {Variant: "b"}}
+	for _, row := range rows {
+		stats[row.Variant] = toExperimentVariantStats(row)
+	}
+	report := &dto.ExperimentReport{
+		Experiment: *toExperimentResponse(experiment),
+		Variants:   []dto.ExperimentVariantStats{stats["a"], stats["b"]},
+	}
+	report.Winner, report.Metric, report.Reason = decideExperimentWinner(stats["a"], stats["b"])
+	return report, nil
+}
+
+// Assign 为角色正在运行的实验分配变体，没有运行中的实验时返回 nil。
+// 登录用户按实验和用户ID哈希分组，同一用户始终看到同一变体；匿名请求随机分组
+func (s *experimentService) Assign(ctx context.Context, persona string, userID int64) (*ExperimentAssignment, error) {
+	experiment, err := s.repo.GetRunningByPersona(ctx, NormalizePersona(persona))
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	bucket := rand.Intn(100)
+	if userID > 0 {
+		h := fnv.New32a()
+		fmt.Fprintf(h, "%d:%d", experiment.ID, userID)
+		bucket = int(h.Sum32() % 100)
+	}
+
+	current := toExperimentResponse(experiment)
+	assignment := &ExperimentAssignment{ExperimentID: experiment.ID, Variant: "a", Config: current.VariantA}
+	if bucket < current.TrafficSplit {
+		assignment.Variant = "b"
+		assignment.Config = current.VariantB
+	}
+	return assignment, nil
+}
+
+// RecordOutcome 记录请求的结果
+func (s *experimentService) RecordOutcome(ctx context.Context, outcome ExperimentOutcome) error {
+	return s.repo.RecordOutcome(ctx, experiments.CreateExperimentOutcomeParams{
+		ExperimentID: outcome.ExperimentID,
+		Variant:      outcome.Variant,
+		ResponseID:   outcome.ResponseID,
+		UserID:       outcome.UserID,
+		Success:      outcome.Success,
+		LatencyMs:    outcome.LatencyMs,
+		ToolCalls:    int64(outcome.ToolCalls),
+		ToolFailures: int64(outcome.ToolFailures),
+	})
+}
+
+// RecordFeedback 记录用户对回复的评价，返回回复是否参与了实验
+func (s *experimentService) RecordFeedback(ctx context.Context, responseID string, positive bool) (bool, error) {
+	if responseID == "" {
+		return false, nil
+	}
+	feedback := int64(-1)
+	if positive {
+		feedback = 1
+	}
+	found, err := s.repo.SetFeedback(ctx, responseID, feedback)
+	if err != nil {
+		return false, errors.NewDatabaseError("record experiment feedback", err)
+	}
+	return found, nil
+}
+
+// checkName 校验实验名称不为空且不与其他实验重复
+func (s *experimentService) checkName(ctx context.Context, id int64, name string) error {
+	if name == "" {
+		return errors.NewValidationError("实验名称不能为空")
+	}
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return errors.NewDatabaseError("list experiments", err)
+	}
+	for _, item := range items {
+		if item.ID != id && strings.EqualFold(item.Name, name) {
+			return errors.NewConflictError(fmt.Sprintf("实验 %s 已存在", name))
+		}
+	}
+	return nil
+}
+
+// NormalizePersona 角色名称不区分大小写，为空时为 default
+func NormalizePersona(persona string) string {
+	persona = strings.ToLower(strings.TrimSpace(persona))
+	if persona == "" {
+		return DefaultPersona
+	}
+	return persona
+}
+
+// applyExperimentVariant 用变体的设置覆盖请求，系统提示替换第一条系统消息，没有时添加到开头
+func applyExperimentVariant(req *ChatRequest, variant dto.ExperimentVariant) {
+	if variant.Model != "" {
+		req.Provider = variant.Provider
+		req.Model = variant.Model
+	}
+	if variant.Temperature != nil {
+		temperature := *variant.Temperature
+		req.Temperature = &temperature
+	}
+	if variant.SystemPrompt != "" {
+		system := openai.Message{Role: "system", Content: variant.SystemPrompt}
+		if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+			req.Messages = append([]openai.Message{system}, req.Messages[1:]...)
+		} else {
+			req.Messages = append([]openai.Message{system}, req.Messages...)
+		}
+	}
+}
+
+// encodeExperimentVariants 校验两个变体都有设置且互不相同，并编码为 JSON
+func encodeExperimentVariants(a, b dto.ExperimentVariant) (string, string, error) {
+	for _, variant := range []dto.ExperimentVariant{a, b} {
+		if variant.Provider != "" && variant.Model == "" {
+			return "", "", errors.NewValidationError("指定提供商的变体必须同时指定模型")
+		}
+		if variant.Model == "" && variant.SystemPrompt == "" && variant.Temperature == nil {
+			return "", "", errors.NewValidationError("变体至少需要设置模型、系统提示或温度之一")
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return "", "", errors.NewValidationError("两个变体的设置不能相同")
+	}
+
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to encode experiment variant").WithCause(err)
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to encode experiment variant").WithCause(err)
+	}
+	return string(dataA), string(dataB), nil
+}
+
+// toExperimentVariantStats 计算变体的各项比率
+func toExperimentVariantStats(row experiments.ListExperimentVariantStatsRow) dto.ExperimentVariantStats {
+	stats := dto.ExperimentVariantStats{
+		Variant:      row.Variant,
+		Requests:     row.Requests,
+		Errors:       row.Errors,
+		ToolCalls:    row.ToolCalls,
+		ToolFailures: row.ToolFailures,
+		FeedbackUp:   row.FeedbackUp,
+		FeedbackDown: row.FeedbackDown,
+	}
+	stats.SuccessRate = experimentRate(row.Requests-row.Errors, row.Requests)
+	stats.ToolSuccessRate = experimentRate(row.ToolCalls-row.ToolFailures, row.ToolCalls)
+	stats.SatisfactionRate = experimentRate(row.FeedbackUp, row.FeedbackUp+row.FeedbackDown)
+	if succeeded := row.Requests - row.Errors; succeeded > 0 {
+		stats.AvgLatencyMs = row.TotalLatencyMs / succeeded
+	}
+	return stats
+}
+
+// experimentRate 计算比率，total 为 0 时返回 nil
+func experimentRate(count, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	rate := roundEvalScore(float64(count) / float64(total))
+	return &rate
+}
+
+// decideExperimentWinner 依次按用户评价、工具成功率和延迟判断胜出的变体。
+// 比率指标使用双比例 z 检验，只有差异在 95% 置信度下显著时才判定胜出
+func decideExperimentWinner(a, b dto.ExperimentVariantStats) (winner, metric, reason string) {
+	if winner, ok := significantWinner(a.FeedbackUp, a.FeedbackUp+a.FeedbackDown, b.FeedbackUp, b.FeedbackUp+b.FeedbackDown); ok {
+		return winner, "feedback", fmt.Sprintf("变体 %s 的好评率显著更高", winner)
+	}
+	if winner, ok := significantWinner(a.ToolCalls-a.ToolFailures, a.ToolCalls, b.ToolCalls-b.ToolFailures, b.ToolCalls); ok {
+		return winner, "tool_success_rate", fmt.Sprintf("变体 %s 的工具调用成功率显著更高", winner)
+	}
+
+	succeededA, succeededB := a.Requests-a.Errors, b.Requests-b.Errors
+	if succeededA < experimentMinSamples || succeededB < experimentMinSamples {
+		return "", "", fmt.Sprintf("样本不足，每个变体至少需要 %d 个成功的请求", experimentMinSamples)
+	}
+	fast, slow, winner := a.AvgLatencyMs, b.AvgLatencyMs, "a"
+	if b.AvgLatencyMs < a.AvgLatencyMs {
+		fast, slow, winner = b.AvgLatencyMs, a.AvgLatencyMs, "b"
+	}
+	if slow > 0 && float64(slow-fast)/float64(slow) >= experimentLatencyMargin {
+		return winner, "latency", fmt.Sprintf("评价和工具成功率没有显著差异，变体 %s 的平均延迟低 %d%% 以上", winner, int(experimentLatencyMargin*100))
+	}
+	return "", "", "各项指标没有显著差异"
+}
+
+// significantWinner 对两个变体的成功比例做双比例 z 检验，样本不足或差异不显著时返回 false
+func significantWinner(successA, totalA, successB, totalB int64) (string, bool) {
+	if totalA < experimentMinSamples || totalB < experimentMinSamples {
+		return "", false
+	}
+	pA := float64(successA) / float64(totalA)
+	pB := float64(successB) / float64(totalB)
+	pooled := float64(successA+successB) / float64(totalA+totalB)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(totalA) + 1/float64(totalB)))
+	if se == 0 {
+		return "", false
+	}
+	z := (pB - pA) / se
+	switch {
+	case z >= experimentSignificanceZ:
+		return "b", true
+	case z <= -experimentSignificanceZ:
+		return "a", true
+	}
+	return "", false
+}
+
+// toExperimentResponse 转换为实验响应
+func toExperimentResponse(experiment *experiments.Experiment) *dto.ExperimentResponse {
+	resp := &dto.ExperimentResponse{
+		ID:           experiment.ID,
+		Name:         experiment.Name,
+		Description:  experiment.Description,
+		Persona:      experiment.Persona,
+		Status:       experiment.Status,
+		TrafficSplit: int(experiment.TrafficSplit),
+		CreatedBy:    experiment.CreatedBy,
+		CreatedAt:    experiment.CreatedAt.Time,
+		UpdatedAt:    experiment.UpdatedAt.Time,
+	}
+	_ = json.Unmarshal([]byte(experiment.VariantA), &resp.VariantA)
+	_ = json.Unmarshal([]byte(experiment.VariantB), &resp.VariantB)
+	if experiment.StartedAt.Valid {
+		startedAt := experiment.StartedAt.Time
+		resp.StartedAt = &startedAt
+	}
+	if experiment.StoppedAt.Valid {
+		stoppedAt := experiment.StoppedAt.Time
+		resp.StoppedAt = &stoppedAt
+	}
+	return resp
+}
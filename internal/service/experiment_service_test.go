@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go-springAi/internal/database/generated/experiments"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryExperimentRepository 内存中的实验和实验结果
+type memoryExperimentRepository struct {
+	experiments []*experiments.Experiment
+	outcomes    []experiments.ExperimentOutcome
+}
+
+func (r *memoryExperimentRepository) Create(ctx context.Context, params experiments.CreateExperimentParams) (*experiments.Experiment, error) {
+	experiment := &experiments.Experiment{
+		ID:           int64(len(r.experiments) + 1),
+		Name:         params.Name,
+		Description:  params.Description,
+		Persona:      params.Persona,
+		Status:       ExperimentStatusDraft,
+		TrafficSplit: params.TrafficSplit,
+		VariantA:     params.VariantA,
+		VariantB:     params.VariantB,
+		CreatedBy:    params.CreatedBy,
+	}
+	r.experiments = append(r.experiments, experiment)
+	copied := *experiment
+	return &copied, nil
+}
+
+func (r *memoryExperimentRepository) Update(ctx context.Context, params experiments.UpdateExperimentParams) (*experiments.Experiment, error) {
+	return updateRow(r.experiments, "Experiment", experimentByID(params.ID), func(e *experiments.Experiment) {
+		e.Name, e.Description, e.Persona, e.TrafficSplit = params.Name, params.Description, params.Persona, params.TrafficSplit
+		e.VariantA, e.VariantB = params.VariantA, params.VariantB
+	})
+}
+
+func (r *memoryExperimentRepository) UpdateStatus(ctx context.Context, params experiments.UpdateExperimentStatusParams) (*experiments.Experiment, error) {
+	return updateRow(r.experiments, "Experiment", experimentByID(params.ID), func(e *experiments.Experiment) {
+		e.Status, e.StartedAt, e.StoppedAt = params.Status, params.StartedAt, params.StoppedAt
+	})
+}
+
+func (r *memoryExperimentRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	return deleteRow(&r.experiments, experimentByID(id)), nil
+}
+
+func (r *memoryExperimentRepository) Get(ctx context.Context, id int64) (*experiments.Experiment, error) {
+	return findRow(r.experiments, "Experiment", experimentByID(id))
+}
+
+func (r *memoryExperimentRepository) GetRunningByPersona(ctx context.Context, persona string) (*experiments.Experiment, error) {
+	return findRow(r.experiments, "Experiment", func(e *experiments.Experiment) bool {
+		return e.Persona == persona && e.Status == ExperimentStatusRunning
+	})
+}
+
+func (r *memoryExperimentRepository) List(ctx context.Context) ([]experiments.Experiment, error) {
+	return listRows(r.experiments, anyRow[experiments.Experiment]), nil
+}
+
+func (r *memoryExperimentRepository) RecordOutcome(ctx context.Context, params experiments.CreateExperimentOutcomeParams) error {
+	r.outcomes = append(r.outcomes, experiments.ExperimentOutcome{
+		ExperimentID: params.ExperimentID,
+		Variant:      params.Variant,
+		ResponseID:   params.ResponseID,
+		UserID:       params.UserID,
+		Success:      params.Success,
+		LatencyMs:    params.LatencyMs,
+		ToolCalls:    params.ToolCalls,
+		ToolFailures: params.ToolFailures,
+	})
+	return nil
+}
+
+func (r *memoryExperimentRepository) SetFeedback(ctx context.Context, responseID string, feedback int64) (bool, error) {
+	found := false
+	for i := range r.outcomes {
+		if r.outcomes[i].ResponseID == responseID {
+			r.outcomes[i].Feedback = feedback
+			found = true
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryExperimentRepository) VariantStats(ctx context.Context, experimentID int64) ([]experiments.ListExperimentVariantStatsRow, error) {
+	var rows []experiments.ListExperimentVariantStatsRow
+	for _, variant := range []string{"a", "b"} {
+		row := experiments.ListExperimentVariantStatsRow{Variant: variant}
+		for _, o := range r.outcomes {
+			if o.ExperimentID != experimentID || o.Variant != variant {
+				continue
+			}
+			row.Requests++
+			if o.Success {
+				row.TotalLatencyMs += o.LatencyMs
+			} else {
+				row.Errors++
+			}
+			row.ToolCalls += o.ToolCalls
+			row.ToolFailures += o.ToolFailures
+			if o.Feedback > 0 {
+				row.FeedbackUp++
+			} else if o.Feedback < 0 {
+				row.FeedbackDown++
+			}
+		}
+		if row.Requests > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func experimentByID(id int64) func(*experiments.Experiment) bool {
+	return func(e *experiments.Experiment) bool { return e.ID == id }
+}
+
+func TestExperimentService(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryExperimentRepository{}
+	service := &experimentService{
+		repo:      repo,
+		providers: newModelRepliesManager(map[string]string{"model-b": "ok"}),
+		now:       fixedNow,
+		logger:    zap.NewNop(),
+	}
+
+	variantA := dto.ExperimentVariant{SystemPrompt: "Be brief."}
+	_, err := service.Create(ctx, 1, &dto.CreateExperimentRequest{Name: "tone", VariantA: variantA, VariantB: variantA})
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	experiment, err := service.Create(ctx, 1, &dto.CreateExperimentRequest{
+		Name:     "tone",
+		Persona:  " Analyst ",
+		VariantA: variantA,
+		VariantB: dto.ExperimentVariant{Model: "model-b", SystemPrompt: "Be thorough."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "analyst", experiment.Persona)
+	assert.Equal(t, ExperimentStatusDraft, experiment.Status)
+	assert.Equal(t, 50, experiment.TrafficSplit)
+
+	_, err = service.Create(ctx, 1, &dto.CreateExperimentRequest{Name: "TONE", VariantA: variantA, VariantB: experiment.VariantB})
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	// 变体的模型不可用时不能开始
+	missing, err := service.Create(ctx, 1, &dto.CreateExperimentRequest{
+		Name:     "models",
+		Persona:  "analyst",
+		VariantA: variantA,
+		VariantB: dto.ExperimentVariant{Model: "missing"},
+	})
+	require.NoError(t, err)
+	_, err = service.Start(ctx, missing.ID)
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	started, err := service.Start(ctx, experiment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ExperimentStatusRunning, started.Status)
+	require.NotNil(t, started.StartedAt)
+
+	// 同一角色同时只能运行一个实验，运行中的实验不能修改
+	_, err = service.Update(ctx, missing.ID, &dto.UpdateExperimentRequest{VariantB: &dto.ExperimentVariant{Model: "model-b"}})
+	require.NoError(t, err)
+	_, err = service.Start(ctx, missing.ID)
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+	name := "renamed"
+	_, err = service.Update(ctx, experiment.ID, &dto.UpdateExperimentRequest{Name: &name})
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	// 没有运行中实验的角色不参与实验
+	assignment, err := service.Assign(ctx, "", 1)
+	require.NoError(t, err)
+	assert.Nil(t, assignment)
+
+	// 同一用户始终分到同一变体，两个变体都有用户
+	variantOf := make(map[int64]string)
+	for userID := int64(1); userID <= 100; userID++ {
+		assignment, err := service.Assign(ctx, "ANALYST", userID)
+		require.NoError(t, err)
+		require.NotNil(t, assignment)
+		again, err := service.Assign(ctx, "analyst", userID)
+		require.NoError(t, err)
+		assert.Equal(t, assignment.Variant, again.Variant)
+		variantOf[userID] = assignment.Variant
+	}
+	var userA, userB int64
+	for userID, variant := range variantOf {
+		if variant == "a" {
+			userA = userID
+		} else {
+			userB = userID
+		}
+	}
+	require.NotZero(t, userA)
+	require.NotZero(t, userB)
+
+	// 聊天请求按变体覆盖模型和系统提示，并记录结果
+	provider := &scriptedProvider{reply: "Hello"}
+	assistant := newTestAssistant(scriptedProviderManager{provider: provider}, testAssistantDeps{experiments: service})
+	resp, err := assistant.Chat(ctx, &ChatRequest{
+		Persona:  "analyst",
+		UserID:   userB,
+		Model:    "model-a",
+		Messages: []openai.Message{{Role: "system", Content: "Default prompt"}, {Role: "user", Content: "Hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "model-b", resp.Model)
+	require.Len(t, provider.messages, 2)
+	assert.Equal(t, "Be thorough.", provider.messages[0].Content)
+
+	_, err = assistant.Chat(ctx, &ChatRequest{Persona: "analyst", UserID: userA, Model: "model-a", Messages: []openai.Message{{Role: "user", Content: "Hi"}}})
+	require.NoError(t, err)
+	require.Len(t, provider.messages, 2)
+	assert.Equal(t, ProviderMessage{Role: "system", Content: "Be brief."}, provider.messages[0])
+
+	require.Len(t, repo.outcomes, 2)
+	assert.Equal(t, "b", repo.outcomes[0].Variant)
+	assert.Equal(t, "chat-1", repo.outcomes[0].ResponseID)
+	assert.True(t, repo.outcomes[0].Success)
+
+	found, err := service.RecordFeedback(ctx, "chat-1", true)
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = service.RecordFeedback(ctx, "unknown", false)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	report, err := service.Report(ctx, experiment.ID)
+	require.NoError(t, err)
+	require.Len(t, report.Variants, 2)
+	assert.Equal(t, int64(1), report.Variants[0].Requests)
+	assert.Equal(t, int64(1), report.Variants[0].FeedbackUp)
+	assert.Empty(t, report.Winner)
+	assert.NotEmpty(t, report.Reason)
+
+	stopped, err := service.Stop(ctx, experiment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ExperimentStatusStopped, stopped.Status)
+	require.NotNil(t, stopped.StoppedAt)
+	_, err = service.Stop(ctx, experiment.ID)
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	require.NoError(t, service.Delete(ctx, experiment.ID))
+	_, err = service.Get(ctx, experiment.ID)
+	assertAppErrorCode(t, err, errors.ErrCodeNotFound)
+}
+
+func TestDecideExperimentWinner(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       dto.ExperimentVariantStats
+		wantWinner string
+		wantMetric string
+	}{
+		{
+			name:       "Feedback",
+			a:          dto.ExperimentVariantStats{Requests: 100, FeedbackUp: 10, FeedbackDown: 30},
+			b:          dto.ExperimentVariantStats{Requests: 100, FeedbackUp: 30, FeedbackDown: 10},
+			wantWinner: "b",
+			wantMetric: "feedback",
+		},
+		{
+			name:       "Too few ratings falls through to tool success",
+			a:          dto.ExperimentVariantStats{Requests: 100, FeedbackUp: 1, FeedbackDown: 5, ToolCalls: 50, ToolFailures: 2},
+			b:          dto.ExperimentVariantStats{Requests: 100, FeedbackUp: 5, FeedbackDown: 1, ToolCalls: 50, ToolFailures: 20},
+			wantWinner: "a",
+			wantMetric: "tool_success_rate",
+		},
+		{
+			name:       "Latency",
+			a:          dto.ExperimentVariantStats{Requests: 40, AvgLatencyMs: 1000},
+			b:          dto.ExperimentVariantStats{Requests: 40, AvgLatencyMs: 700},
+			wantWinner: "b",
+			wantMetric: "latency",
+		},
+		{
+			name: "No significant difference",
+			a:    dto.ExperimentVariantStats{Requests: 40, AvgLatencyMs: 1000, FeedbackUp: 15, FeedbackDown: 10},
+			b:    dto.ExperimentVariantStats{Requests: 40, AvgLatencyMs: 900, FeedbackUp: 16, FeedbackDown: 9},
+		},
+		{
+			name: "Not enough samples",
+			a:    dto.ExperimentVariantStats{Requests: 5, AvgLatencyMs: 1000},
+			b:    dto.ExperimentVariantStats{Requests: 5, AvgLatencyMs: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, metric, reason := decideExperimentWinner(tt.a, tt.b)
+			assert.Equal(t, tt.wantWinner, winner)
+			assert.Equal(t, tt.wantMetric, metric)
+			assert.NotEmpty(t, reason)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// 测试共用的内存仓库辅助函数、提供商替身和断言
+
+// findRow 返回第一条满足条件的记录副本，不存在时返回 resource 的 NotFound 错误
+func findRow[T any](rows []*T, resource string, match func(*T) bool) (*T, error) {
+	for _, row := range rows {
+		if match(row) {
+			copied := *row
+			return &copied, nil
+		}
+	}
+	return nil, errors.NewNotFoundError(resource)
+}
+
+// updateRow 修改第一条满足条件的记录并返回副本，不存在时返回 resource 的 NotFound 错误
+func updateRow[T any](rows []*T, resource string, match func(*T) bool, apply func(*T)) (*T, error) {
+	for _, row := range rows {
+		if match(row) {
+			apply(row)
+			copied := *row
+			return &copied, nil
+		}
+	}
+	return nil, errors.NewNotFoundError(resource)
+}
+
+// deleteRow 删除第一条满足条件的记录，不存在时返回 false
+func deleteRow[T any](rows *[]*T, match func(*T) bool) bool {
+	for i, row := range *rows {
+		if match(row) {
+			*rows = append((*rows)[:i], (*rows)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// listRows 按插入顺序返回满足条件的记录副本
+func listRows[T any](rows []*T, match func(*T) bool) []T {
+	result := make([]T, 0, len(rows))
+	for _, row := range rows {
+		if match(row) {
+			result = append(result, *row)
+		}
+	}
+	return result
+}
+
+// latestRows 从最新的记录开始返回最多 limit 条满足条件的记录副本
+func latestRows[T any](rows []*T, limit int, match func(*T) bool) []T {
+	var result []T
+	for i := len(rows) - 1; i >= 0 && len(result) < limit; i-- {
+		if match(rows[i]) {
+			result = append(result, *rows[i])
+		}
+	}
+	return result
+}
+
+// anyRow 匹配所有记录
+func anyRow[T any](*T) bool { return true }
+
+// assertAppErrorCode 断言 err 是指定错误码的 AppError，msgAndArgs 与 testify 的用法相同
+func assertAppErrorCode(t *testing.T, err error, code errors.ErrorCode, msgAndArgs ...interface{}) {
+	t.Helper()
+	appErr, ok := errors.IsAppError(err)
+	require.True(t, ok, msgAndArgs...)
+	assert.Equal(t, code, appErr.Code, msgAndArgs...)
+}
+
+// fixedNow 测试使用的固定当前时间
+func fixedNow() time.Time {
+	return time.Unix(1760000000, 0)
+}
+
+// modelRepliesProvider 按模型名称返回固定回复的提供商
+type modelRepliesProvider struct {
+	replies map[string]string
+}
+
+func (p *modelRepliesProvider) GetType() string { return "mock" }
+func (p *modelRepliesProvider) GetName() string { return "mock" }
+
+func (p *modelRepliesProvider) ChatCompletion(ctx context.Context, req *ProviderChatRequest) (*ProviderChatResponse, error) {
+	reply, ok := p.replies[req.Model]
+	if !ok {
+		return nil, fmt.Errorf("model %s unavailable", req.Model)
+	}
+	return &ProviderChatResponse{
+		Model:   req.Model,
+		Choices: []ProviderChoice{{Message: ProviderMessage{Role: "assistant", Content: reply}}},
+		Usage:   ProviderUsage{PromptTokens: 10, CompletionTokens: 5},
+	}, nil
+}
+
+// modelRepliesManager 只接受提供商已知的模型
+type modelRepliesManager struct {
+	provider *modelRepliesProvider
+}
+
+func (m modelRepliesManager) GetProviderByModel(model string) (ProviderInterface, error) {
+	return m.GetProviderByModelWithValidation(context.Background(), model)
+}
+func (m modelRepliesManager) GetProviderByName(name string) (ProviderInterface, error) {
+	if name != "mock" {
+		return nil, fmt.Errorf("provider with name %s not found", name)
+	}
+	return m.provider, nil
+}
+func (m modelRepliesManager) ValidateModelForProvider(ctx context.Context, provider, model string) error {
+	_, err := m.GetProviderByModelWithValidation(ctx, model)
+	return err
+}
+func (m modelRepliesManager) GetProviderByModelWithValidation(ctx context.Context, model string) (ProviderInterface, error) {
+	if _, ok := m.provider.replies[model]; !ok {
+		return nil, fmt.Errorf("model %s not found", model)
+	}
+	return m.provider, nil
+}
+
+// newModelRepliesManager 创建只认识 replies 中模型的提供商管理器
+func newModelRepliesManager(replies map[string]string) modelRepliesManager {
+	return modelRepliesManager{provider: &modelRepliesProvider{replies: replies}}
+}
+
+// scriptedProvider 返回固定回复并记录收到的消息
+type scriptedProvider struct {
+	reply    string
+	messages []ProviderMessage
+}
+
+func (p *scriptedProvider) GetType() string { return "scripted" }
+func (p *scriptedProvider) GetName() string { return "scripted" }
+
+func (p *scriptedProvider) ChatCompletion(ctx context.Context, req *ProviderChatRequest) (*ProviderChatResponse, error) {
+	p.messages = req.Messages
+	return &ProviderChatResponse{
+		ID:      "chat-1",
+		Created: 1700000000,
+		Model:   req.Model,
+		Choices: []ProviderChoice{{Message: ProviderMessage{Role: "assistant", Content: p.reply}, FinishReason: "stop"}},
+		Usage:   ProviderUsage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+	}, nil
+}
+
+func (p *scriptedProvider) ChatCompletionStream(ctx context.Context, req *ProviderChatRequest) (io.ReadCloser, error) {
+	p.messages = req.Messages
+	var sb strings.Builder
+	for _, part := range strings.SplitAfter(p.reply, " ") {
+		fmt.Fprintf(&sb, "data: {\"id\":\"chat-1\",\"model\":%q,\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", req.Model, part)
+	}
+	sb.WriteString("data: {\"id\":\"chat-1\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+	return io.NopCloser(strings.NewReader(sb.String())), nil
+}
+
+// scriptedProviderManager 所有模型都由同一个提供商处理
+type scriptedProviderManager struct {
+	provider *scriptedProvider
+}
+
+func (m scriptedProviderManager) GetProviderByModel(string) (ProviderInterface, error) {
+	return m.provider, nil
+}
+func (m scriptedProviderManager) GetProviderByName(string) (ProviderInterface, error) {
+	return m.provider, nil
+}
+func (m scriptedProviderManager) ValidateModelForProvider(context.Context, string, string) error {
+	return nil
+}
+func (m scriptedProviderManager) GetProviderByModelWithValidation(context.Context, string) (ProviderInterface, error) {
+	return m.provider, nil
+}
+
+// testAssistantDeps 测试助手服务的可选依赖，未设置的依赖为空
+type testAssistantDeps struct {
+	mcpClient     mcp.InternalMCPClient
+	modelMetadata ModelMetadataLookup
+	usageMetrics  AIUsageRecorder
+	experiments   ExperimentAssigner
+	personas      PersonaResolver
+	memories      MemoryKeeper
+}
+
+// newTestAssistant 创建使用 providers 和 deps 的助手服务
+func newTestAssistant(providers ProviderManager, deps testAssistantDeps) *AIAssistantService {
+	return NewAIAssistantService(deps.mcpClient, nil, providers, nil, nil, nil, nil, deps.modelMetadata, deps.usageMetrics, nil, deps.experiments, nil, deps.personas, deps.memories, zap.NewNop())
+}
+
+// newToolAssistant 创建只注册 tools 的助手服务，工具经由真实的 MCP 服务和内部客户端执行
+func newToolAssistant(t *testing.T, tools ...mcp.Tool) (*AIAssistantService, *scriptedProvider) {
+	t.Helper()
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop()).(*MCPServiceImpl)
+	mcpService.toolRegistry = mcp.NewToolRegistry()
+	for _, tool := range tools {
+		require.NoError(t, mcpService.RegisterTool(tool))
+	}
+	client := mcp.NewInternalMCPClient(mcpService, dto.MCPClientInfo{Name: "test", Version: "1.0.0"})
+	_, err := client.Initialize(context.Background(), nil)
+	require.NoError(t, err)
+
+	provider := &scriptedProvider{}
+	return newTestAssistant(scriptedProviderManager{provider: provider}, testAssistantDeps{mcpClient: client}), provider
+}
+
+// chatToolCall 以 userID 的身份发起对话，模型回复调用 name 工具，返回该工具的执行结果
+func chatToolCall(t *testing.T, assistant *AIAssistantService, provider *scriptedProvider, userID int64, name string, args map[string]interface{}) ToolCallExecution {
+	t.Helper()
+	call, err := json.Marshal(ToolCall{Name: name, Arguments: args})
+	require.NoError(t, err)
+	provider.reply = string(call)
+
+	resp, err := assistant.Chat(context.Background(), &ChatRequest{
+		UserID:   userID,
+		UseTools: true,
+		Messages: []openai.Message{{Role: "user", Content: "Use " + name}},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Choices[0].ToolCalls, 1)
+	execution := resp.Choices[0].ToolCalls[0]
+	require.Empty(t, execution.Error)
+	require.NotNil(t, execution.Result)
+	return execution
+}
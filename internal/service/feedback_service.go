@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go-springAi/internal/database/generated/feedback"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// servedResponseTTL 回复在多长时间内可以评价
+	servedResponseTTL = 24 * time.Hour
+	// maxServedResponses 最多保留的回复数，超过时淘汰最早过期的
+	maxServedResponses = 10000
+	// servedResponseSweepInterval 清理过期回复的间隔
+	servedResponseSweepInterval = time.Minute
+	// defaultFeedbackListLimit、maxFeedbackListLimit 评价列表的默认和最大条数
+	defaultFeedbackListLimit = 50
+	maxFeedbackListLimit     = 200
+	// defaultFeedbackExportLimit 导出时默认检查的差评条数
+	defaultFeedbackExportLimit = 50
+	// maxEvalSuiteCases 评测套件的最大用例数，与创建套件的校验一致
+	maxEvalSuiteCases = 200
+	// feedbackEvalCasePrefix 由评价导出的评测用例名称前缀，后接评价ID
+	feedbackEvalCasePrefix = "feedback-"
+	// maxFeedbackRubricReply 评分标准中引用的原回复的最大字符数
+	maxFeedbackRubricReply = 1500
+)
+
+// ServedResponse 助手返回给用户的一次回复，用于之后的评价
+type ServedResponse struct {
+	ID       string
+	UserID   int64
+	Provider string
+	Model    string
+	Persona  string
+	Messages []openai.Message // 请求消息，不含回复
+	Reply    string
+}
+
+// ResponseRecorder 保存最近的回复，供用户评价时关联对话和模型
+type ResponseRecorder interface {
+	RememberResponse(resp ServedResponse)
+}
+
+// FeedbackService 回复评价服务接口
+type FeedbackService interface {
+	ResponseRecorder
+	// Submit 评价自己最近收到的回复，重复评价时覆盖，参与 A/B 实验的回复同时计入实验结果
+	Submit(ctx context.Context, userID int64, responseID string, req *dto.ResponseFeedbackRequest) (*dto.ResponseFeedbackResponse, error)
+	// List 获取最近的评价，rating 为空时不按评分过滤
+	List(ctx context.Context, rating string, limit int) (*dto.ResponseFeedbackListResponse, error)
+	// ExportToEval 把最近的差评作为用例添加到评测套件
+	ExportToEval(ctx context.Context, req *dto.ExportFeedbackRequest) (*dto.ExportFeedbackResponse, error)
+}
+
+type servedEntry struct {
+	response  ServedResponse
+	expiresAt time.Time
+}
+
+// feedbackService 回复评价服务实现，最近的回复只保存在进程内，多实例部署时需在返回回复的实例上评价
+type feedbackService struct {
+	repo        repository.FeedbackRepository
+	experiments ExperimentService
+	evals       EvalService
+	now         func() time.Time
+	logger      *zap.Logger
+
+	mu        sync.Mutex
+	served    map[string]*servedEntry
+	lastSweep time.Time
+}
+
+// NewFeedbackService 创建回复评价服务
+func NewFeedbackService(repoManager repository.RepositoryManager, experiments ExperimentService, evals EvalService, logger *zap.Logger) FeedbackService {
+	return &feedbackService{
+		repo:        repoManager.Feedback(),
+		experiments: experiments,
+		evals:       evals,
+		now:         time.Now,
+		logger:      logger,
+		served:      make(map[string]*servedEntry),
+	}
+}
+
+// RememberResponse 保存回复，没有ID的回复无法评价，不保存
+func (s *feedbackService) RememberResponse(resp ServedResponse) {
+	if resp.ID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	if _, ok := s.served[resp.ID]; !ok && len(s.served) >= maxServedResponses {
+		s.evictOldest()
+	}
+	s.served[resp.ID] = &servedEntry{response: resp, expiresAt: now.Add(servedResponseTTL)}
+}
+
+// Submit 评价自己最近收到的回复，重复评价时覆盖，参与 A/B 实验的回复同时计入实验结果
+func (s *feedbackService) Submit(ctx context.Context, userID int64, responseID string, req *dto.ResponseFeedbackRequest) (*dto.ResponseFeedbackResponse, error) {
+	served, ok := s.lookup(responseID)
+	// 不能评价其他用户的回复，按不存在处理以免泄露回复ID
+	if !ok || served.UserID != userID {
+		return nil, errors.NewNotFoundError("Response")
+	}
+
+	conversation := append(append([]openai.Message{}, served.Messages...), openai.Message{Role: "assistant", Content: served.Reply})
+	conversationJSON, err := json.Marshal(conversation)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to encode conversation").WithCause(err)
+	}
+	rating := int64(-1)
+	if req.Rating == dto.FeedbackRatingUp {
+		rating = 1
+	}
+
+	item, err := s.repo.Upsert(ctx, feedback.UpsertResponseFeedbackParams{
+		ResponseID:   served.ID,
+		UserID:       userID,
+		Rating:       rating,
+		Category:     req.Category,
+		Comment:      strings.TrimSpace(req.Comment),
+		Provider:     served.Provider,
+		Model:        served.Model,
+		Persona:      served.Persona,
+		Conversation: string(conversationJSON),
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("save response feedback", err)
+	}
+
+	resp := toResponseFeedbackResponse(item, false)
+	if s.experiments != nil {
+		inExperiment, err := s.experiments.RecordFeedback(ctx, served.ID, rating > 0)
+		if err != nil {
+			s.logger.Warn("Failed to record experiment feedback", zap.String("response_id", served.ID), zap.Error(err))
+		}
+		resp.InExperiment = inExperiment
+	}
+
+	s.logger.Info("Response feedback saved",
+		zap.String("response_id", served.ID),
+		zap.Int64("user_id", userID),
+		zap.String("rating", req.Rating),
+		zap.String("model", served.Model))
+	return resp, nil
+}
+
+// List 获取最近的评价，rating 为空时不按评分过滤
+func (s *feedbackService) List(ctx context.Context, rating string, limit int) (*dto.ResponseFeedbackListResponse, error) {
+	if limit <= 0 {
+		limit = defaultFeedbackListLimit
+	}
+	if limit > maxFeedbackListLimit {
+		limit = maxFeedbackListLimit
+	}
+
+	var items []feedback.ResponseFeedback
+	var err error
+	switch rating {
+	case "":
+		items, err = s.repo.List(ctx, limit)
+	case dto.FeedbackRatingUp:
+		items, err = s.repo.ListByRating(ctx, 1, limit)
+	case dto.FeedbackRatingDown:
+		items, err = s.repo.ListByRating(ctx, -1, limit)
+	default:
+		return nil, errors.NewValidationError("rating 必须为 up 或 down")
+	}
+	if err != nil {
+		return nil, errors.NewDatabaseError("list response feedback", err)
+	}
+
+	result := &dto.ResponseFeedbackListResponse{Feedback: make([]dto.ResponseFeedbackResponse, 0, len(items))}
+	for i := range items {
+		result.Feedback = append(result.Feedback, *toResponseFeedbackResponse(&items[i], true))
+	}
+	return result, nil
+}
+
+// ExportToEval 把最近的差评作为用例添加到评测套件：提示词为最后一条用户消息，
+// 评分标准包含用户的分类、备注和原回复，已导出过的评价不会重复添加
+func (s *feedbackService) ExportToEval(ctx context.Context, req *dto.ExportFeedbackRequest) (*dto.ExportFeedbackResponse, error) {
+	suite, err := s.evals.GetSuite(ctx, req.SuiteID)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultFeedbackExportLimit
+	}
+	items, err := s.repo.ListByRating(ctx, -1, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list response feedback", err)
+	}
+
+	existing := make(map[string]bool, len(suite.Cases))
+	for _, c := range suite.Cases {
+		existing[c.Name] = true
+	}
+	result := &dto.ExportFeedbackResponse{SuiteID: suite.ID, Added: []string{}}
+	cases := suite.Cases
+	for i := range items {
+		item := &items[i]
+		if req.Category != "" && item.Category != req.Category {
+			continue
+		}
+		c, ok := feedbackEvalCase(item)
+		if !ok || existing[c.Name] {
+			continue
+		}
+		cases = append(cases, c)
+		result.Added = append(result.Added, c.Name)
+	}
+	if len(result.Added) == 0 {
+		return result, nil
+	}
+	if len(cases) > maxEvalSuiteCases {
+		return nil, errors.NewValidationError(fmt.Sprintf("评测套件最多 %d 个用例", maxEvalSuiteCases))
+	}
+
+	if _, err := s.evals.UpdateSuite(ctx, suite.ID, &dto.UpdateEvalSuiteRequest{Cases: cases}); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Exported feedback to eval suite", zap.Int64("suite_id", suite.ID), zap.Int("added", len(result.Added)))
+	return result, nil
+}
+
+// lookup 获取未过期的回复
+func (s *feedbackService) lookup(responseID string) (ServedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.served[responseID]
+	if !ok || !s.now().Before(entry.expiresAt) {
+		return ServedResponse{}, false
+	}
+	return entry.response, true
+}
+
+// sweep 定期删除过期的回复
+func (s *feedbackService) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < servedResponseSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for id, entry := range s.served {
+		if !now.Before(entry.expiresAt) {
+			delete(s.served, id)
+		}
+	}
+}
+
+// evictOldest 删除最早过期的回复
+func (s *feedbackService) evictOldest() {
+	var oldestID string
+	var oldest time.Time
+	for id, entry := range s.served {
+		if oldestID == "" || entry.expiresAt.Before(oldest) {
+			oldestID, oldest = id, entry.expiresAt
+		}
+	}
+	delete(s.served, oldestID)
+}
+
+// feedbackEvalCase 由差评构造评测用例，对话中没有用户消息时返回 false
+func feedbackEvalCase(item *feedback.ResponseFeedback) (dto.EvalCase, bool) {
+	var conversation []openai.Message
+	if err := json.Unmarshal([]byte(item.Conversation), &conversation); err != nil {
+		return dto.EvalCase{}, false
+	}
+	c := dto.EvalCase{Name: fmt.Sprintf("%s%d", feedbackEvalCasePrefix, item.ID)}
+	var reply string
+	for _, msg := range conversation {
+		switch msg.Role {
+		case "system":
+			if c.System == "" {
+				c.System = msg.Content
+			}
+		case "user":
+			c.Prompt = msg.Content
+		case "assistant":
+			reply = msg.Content
+		}
+	}
+	if strings.TrimSpace(c.Prompt) == "" {
+		return dto.EvalCase{}, false
+	}
+
+	var rubric strings.Builder
+	rubric.WriteString("A user rated a previous answer to this prompt as bad")
+	if item.Category != "" {
+		rubric.WriteString(" (" + item.Category + ")")
+	}
+	rubric.WriteString(". The response must avoid the same problem and answer the prompt correctly.")
+	if item.Comment != "" {
+		rubric.WriteString("\nUser comment: " + item.Comment)
+	}
+	if utf8.RuneCountInString(reply) > maxFeedbackRubricReply {
+		reply = string([]rune(reply)[:maxFeedbackRubricReply]) + "..."
+	}
+	rubric.WriteString("\nPrevious answer:\n" + reply)
+	c.Criteria.Rubric = rubric.String()
+	return c, true
+}
+
+// toResponseFeedbackResponse 转换为回复评价响应，withConversation 为 false 时不返回对话
+func toResponseFeedbackResponse(item *feedback.ResponseFeedback, withConversation bool) *dto.ResponseFeedbackResponse {
+	resp := &dto.ResponseFeedbackResponse{
+		ID:         item.ID,
+		ResponseID: item.ResponseID,
+		UserID:     item.UserID,
+		Rating:     dto.FeedbackRatingDown,
+		Category:   item.Category,
+		Comment:    item.Comment,
+		Provider:   item.Provider,
+		Model:      item.Model,
+		Persona:    item.Persona,
+		CreatedAt:  item.CreatedAt.Time,
+		UpdatedAt:  item.UpdatedAt.Time,
+	}
+	if item.Rating > 0 {
+		resp.Rating = dto.FeedbackRatingUp
+	}
+	if withConversation {
+		_ = json.Unmarshal([]byte(item.Conversation), &resp.Conversation)
+	}
+	return resp
+}
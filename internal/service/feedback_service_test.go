@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database/generated/experiments"
+	"go-springAi/internal/database/generated/feedback"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryFeedbackRepository 内存中的回复评价
+type memoryFeedbackRepository struct {
+	items []*feedback.ResponseFeedback
+}
+
+func (r *memoryFeedbackRepository) Upsert(ctx context.Context, params feedback.UpsertResponseFeedbackParams) (*feedback.ResponseFeedback, error) {
+	existing, err := updateRow(r.items, "Response feedback", func(item *feedback.ResponseFeedback) bool {
+		return item.ResponseID == params.ResponseID && item.UserID == params.UserID
+	}, func(item *feedback.ResponseFeedback) {
+		item.Rating, item.Category, item.Comment = params.Rating, params.Category, params.Comment
+	})
+	if err == nil {
+		return existing, nil
+	}
+	item := &feedback.ResponseFeedback{
+		ID:           int64(len(r.items) + 1),
+		ResponseID:   params.ResponseID,
+		UserID:       params.UserID,
+		Rating:       params.Rating,
+		Category:     params.Category,
+		Comment:      params.Comment,
+		Provider:     params.Provider,
+		Model:        params.Model,
+		Persona:      params.Persona,
+		Conversation: params.Conversation,
+	}
+	r.items = append(r.items, item)
+	copied := *item
+	return &copied, nil
+}
+
+func (r *memoryFeedbackRepository) List(ctx context.Context, limit int) ([]feedback.ResponseFeedback, error) {
+	return latestRows(r.items, limit, anyRow[feedback.ResponseFeedback]), nil
+}
+
+func (r *memoryFeedbackRepository) ListByRating(ctx context.Context, rating int64, limit int) ([]feedback.ResponseFeedback, error) {
+	return latestRows(r.items, limit, func(item *feedback.ResponseFeedback) bool { return item.Rating == rating }), nil
+}
+
+func TestFeedbackService(t *testing.T) {
+	ctx := context.Background()
+	experimentRepo := &memoryExperimentRepository{outcomes: []experiments.ExperimentOutcome{{ExperimentID: 1, Variant: "b", ResponseID: "resp-2"}}}
+	evals := &evalService{repo: &memoryEvalRepository{}, now: fixedNow, logger: zap.NewNop()}
+	service := &feedbackService{
+		repo:        &memoryFeedbackRepository{},
+		experiments: &experimentService{repo: experimentRepo, now: fixedNow, logger: zap.NewNop()},
+		evals:       evals,
+		now:         fixedNow,
+		logger:      zap.NewNop(),
+		served:      make(map[string]*servedEntry),
+	}
+
+	messages := []openai.Message{{Role: "system", Content: "You are an analyst."}, {Role: "user", Content: "Is AAPL overvalued?"}}
+	service.RememberResponse(ServedResponse{ID: "resp-1", UserID: 1, Provider: "mock", Model: "model-a", Persona: DefaultPersona, Messages: messages, Reply: "Buy everything."})
+	service.RememberResponse(ServedResponse{ID: "resp-2", UserID: 1, Provider: "mock", Model: "model-b", Persona: DefaultPersona, Messages: messages, Reply: "It depends on growth."})
+
+	// 不能评价其他用户的回复
+	_, err := service.Submit(ctx, 2, "resp-1", &dto.ResponseFeedbackRequest{Rating: dto.FeedbackRatingUp})
+	assertAppErrorCode(t, err, errors.ErrCodeNotFound)
+
+	first, err := service.Submit(ctx, 1, "resp-1", &dto.ResponseFeedbackRequest{Rating: dto.FeedbackRatingUp})
+	require.NoError(t, err)
+	assert.Equal(t, dto.FeedbackRatingUp, first.Rating)
+	assert.False(t, first.InExperiment)
+
+	// 重复评价时覆盖
+	updated, err := service.Submit(ctx, 1, "resp-1", &dto.ResponseFeedbackRequest{Rating: dto.FeedbackRatingDown, Category: "inaccurate", Comment: " reckless advice "})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, updated.ID)
+	assert.Equal(t, dto.FeedbackRatingDown, updated.Rating)
+	assert.Equal(t, "reckless advice", updated.Comment)
+
+	// 参与实验的回复同时计入实验结果
+	inExperiment, err := service.Submit(ctx, 1, "resp-2", &dto.ResponseFeedbackRequest{Rating: dto.FeedbackRatingUp})
+	require.NoError(t, err)
+	assert.True(t, inExperiment.InExperiment)
+	assert.Equal(t, int64(1), experimentRepo.outcomes[0].Feedback)
+
+	list, err := service.List(ctx, dto.FeedbackRatingDown, 0)
+	require.NoError(t, err)
+	require.Len(t, list.Feedback, 1)
+	assert.Equal(t, "resp-1", list.Feedback[0].ResponseID)
+	require.Len(t, list.Feedback[0].Conversation, 3)
+	assert.Equal(t, "Buy everything.", list.Feedback[0].Conversation[2].Content)
+
+	_, err = service.List(ctx, "meh", 0)
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	// 差评导出为评测用例，重复导出不会重复添加
+	suite, err := evals.CreateSuite(ctx, 1, &dto.CreateEvalSuiteRequest{
+		Name:  "regressions",
+		Cases: []dto.EvalCase{{Name: "smoke", Prompt: "Hello"}},
+	})
+	require.NoError(t, err)
+	exported, err := service.ExportToEval(ctx, &dto.ExportFeedbackRequest{SuiteID: suite.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feedback-1"}, exported.Added)
+
+	suite, err = evals.GetSuite(ctx, suite.ID)
+	require.NoError(t, err)
+	require.Len(t, suite.Cases, 2)
+	assert.Equal(t, "You are an analyst.", suite.Cases[1].System)
+	assert.Equal(t, "Is AAPL overvalued?", suite.Cases[1].Prompt)
+	assert.True(t, strings.Contains(suite.Cases[1].Criteria.Rubric, "reckless advice"))
+	assert.True(t, strings.Contains(suite.Cases[1].Criteria.Rubric, "Buy everything."))
+
+	exported, err = service.ExportToEval(ctx, &dto.ExportFeedbackRequest{SuiteID: suite.ID})
+	require.NoError(t, err)
+	assert.Empty(t, exported.Added)
+
+	// 过期的回复无法评价
+	service.now = func() time.Time { return fixedNow().Add(servedResponseTTL) }
+	_, err = service.Submit(ctx, 1, "resp-2", &dto.ResponseFeedbackRequest{Rating: dto.FeedbackRatingDown})
+	assertAppErrorCode(t, err, errors.ErrCodeNotFound)
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/fine_tuning_jobs"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/types"
+
+	"go.uber.org/zap"
+)
+
+// 微调任务列表的默认和最大条数
+const (
+	defaultFineTuningJobLimit = 20
+	maxFineTuningJobLimit     = 100
+)
+
+// ModelRegistrar 以禁用状态把新模型添加到提供商的模型配置，模型已存在时返回 false
+type ModelRegistrar interface {
+	AddModel(ctx context.Context, providerType types.ProviderType, name string) (bool, error)
+}
+
+// FineTuningService OpenAI 微调任务管理服务接口
+type FineTuningService interface {
+	// UploadFile 上传 JSONL 格式的训练文件
+	UploadFile(ctx context.Context, filename string, content io.Reader) (*dto.FineTuningFileResponse, error)
+	// CreateJob 创建微调任务
+	CreateJob(ctx context.Context, userID int64, req *dto.CreateFineTuningJobRequest) (*dto.FineTuningJobResponse, error)
+	// GetJob 获取微调任务，未结束的任务先从 OpenAI 刷新状态
+	GetJob(ctx context.Context, jobID string) (*dto.FineTuningJobResponse, error)
+	// ListJobs 按创建时间倒序获取最近的微调任务
+	ListJobs(ctx context.Context, limit int) (*dto.FineTuningJobListResponse, error)
+	// CancelJob 取消微调任务
+	CancelJob(ctx context.Context, jobID string) (*dto.FineTuningJobResponse, error)
+	// Sync 刷新所有未结束的任务，并把成功任务产生的模型以禁用状态添加到模型配置
+	Sync(ctx context.Context) (*FineTuningSyncSummary, error)
+}
+
+// FineTuningSyncSummary 一次同步的结果统计
+type FineTuningSyncSummary struct {
+	Checked    int // 刷新的任务数
+	Finished   int // 本次同步中结束的任务数
+	Registered int // 以禁用状态添加到模型配置的模型数
+}
+
+// fineTuningService OpenAI 微调任务管理服务实现
+type fineTuningService struct {
+	jobRepo  repository.FineTuningJobRepository
+	client   openai.FineTuningClient
+	models   ModelRegistrar
+	notifier ModelsChangedNotifier
+	logger   *zap.Logger
+}
+
+// NewFineTuningService 创建微调任务管理服务，client 为空时（如沙箱模式）微调接口返回服务不可用，
+// notifier 不为空时在添加微调模型后通知 MCP 客户端
+func NewFineTuningService(repoManager repository.RepositoryManager, client openai.FineTuningClient, models ModelRegistrar, notifier ModelsChangedNotifier, logger *zap.Logger) FineTuningService {
+	return &fineTuningService{
+		jobRepo:  repoManager.FineTuningJob(),
+		client:   client,
+		models:   models,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// UploadFile 上传 JSONL 格式的训练文件
+func (s *fineTuningService) UploadFile(ctx context.Context, filename string, content io.Reader) (*dto.FineTuningFileResponse, error) {
+	if s.client == nil {
+		return nil, errors.NewServiceUnavailableError("fine-tuning")
+	}
+	if !strings.EqualFold(path.Ext(filename), ".jsonl") {
+		return nil, errors.NewFileUploadFailedError("training file must be a .jsonl file")
+	}
+
+	file, err := s.client.UploadFile(ctx, filename, content)
+	if err != nil {
+		return nil, errors.NewNetworkError("fine-tuning file upload", err)
+	}
+	s.logger.Info("上传微调训练文件", zap.String("file_id", file.ID), zap.String("filename", file.Filename), zap.Int64("bytes", file.Bytes))
+	return &dto.FineTuningFileResponse{
+		ID:        file.ID,
+		Filename:  file.Filename,
+		Bytes:     file.Bytes,
+		Purpose:   file.Purpose,
+		CreatedAt: time.Unix(file.CreatedAt, 0).UTC(),
+	}, nil
+}
+
+// CreateJob 创建微调任务
+func (s *fineTuningService) CreateJob(ctx context.Context, userID int64, req *dto.CreateFineTuningJobRequest) (*dto.FineTuningJobResponse, error) {
+	if s.client == nil {
+		return nil, errors.NewServiceUnavailableError("fine-tuning")
+	}
+
+	remote, err := s.client.CreateFineTuningJob(ctx, &openai.FineTuningJobRequest{
+		Model:          req.Model,
+		TrainingFile:   req.TrainingFile,
+		ValidationFile: req.ValidationFile,
+		Suffix:         req.Suffix,
+	})
+	if err != nil {
+		return nil, errors.NewNetworkError("fine-tuning job creation", err)
+	}
+
+	job, err := s.jobRepo.Create(ctx, fine_tuning_jobs.CreateFineTuningJobParams{
+		JobID:          remote.ID,
+		UserID:         userID,
+		BaseModel:      req.Model,
+		TrainingFile:   req.TrainingFile,
+		ValidationFile: req.ValidationFile,
+		Suffix:         req.Suffix,
+		Status:         remote.Status,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create fine-tuning job", err)
+	}
+	s.logger.Info("创建微调任务", zap.String("job_id", remote.ID), zap.String("model", req.Model), zap.Int64("user_id", userID))
+	return toFineTuningJobResponse(job), nil
+}
+
+// GetJob 获取微调任务，未结束的任务先从 OpenAI 刷新状态，刷新失败时返回已保存的状态
+func (s *fineTuningService) GetJob(ctx context.Context, jobID string) (*dto.FineTuningJobResponse, error) {
+	job, err := s.jobRepo.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if s.client != nil && !fineTuningJobFinished(job.Status) {
+		remote, err := s.client.GetFineTuningJob(ctx, jobID)
+		if err != nil {
+			s.logger.Warn("刷新微调任务状态失败", zap.String("job_id", jobID), zap.Error(err))
+			return toFineTuningJobResponse(job), nil
+		}
+		var model string
+		if job, model, err = s.apply(ctx, job, remote); err != nil {
+			return nil, err
+		}
+		if model != "" {
+			s.notifyModelsAdded([]string{model})
+		}
+	}
+	return toFineTuningJobResponse(job), nil
+}
+
+// ListJobs 按创建时间倒序获取最近的微调任务
+func (s *fineTuningService) ListJobs(ctx context.Context, limit int) (*dto.FineTuningJobListResponse, error) {
+	if limit <= 0 {
+		limit = defaultFineTuningJobLimit
+	}
+	limit = min(limit, maxFineTuningJobLimit)
+
+	jobs, err := s.jobRepo.List(ctx, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list fine-tuning jobs", err)
+	}
+	resp := &dto.FineTuningJobListResponse{Jobs: make([]dto.FineTuningJobResponse, len(jobs))}
+	for i := range jobs {
+		resp.Jobs[i] = *toFineTuningJobResponse(&jobs[i])
+	}
+	return resp, nil
+}
+
+// CancelJob 取消微调任务，已结束的任务返回冲突错误
+func (s *fineTuningService) CancelJob(ctx context.Context, jobID string) (*dto.FineTuningJobResponse, error) {
+	if s.client == nil {
+		return nil, errors.NewServiceUnavailableError("fine-tuning")
+	}
+	job, err := s.jobRepo.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if fineTuningJobFinished(job.Status) {
+		return nil, errors.NewConflictError(fmt.Sprintf("fine-tuning job %s has already %s", jobID, job.Status))
+	}
+
+	remote, err := s.client.CancelFineTuningJob(ctx, jobID)
+	if err != nil {
+		return nil, errors.NewNetworkError("fine-tuning job cancellation", err)
+	}
+	if job, _, err = s.apply(ctx, job, remote); err != nil {
+		return nil, err
+	}
+	s.logger.Info("取消微调任务", zap.String("job_id", jobID))
+	return toFineTuningJobResponse(job), nil
+}
+
+// Sync 刷新所有未结束的任务，并把成功任务产生的模型以禁用状态添加到模型配置。
+// 单个任务刷新失败时跳过，下次同步重试
+func (s *fineTuningService) Sync(ctx context.Context) (*FineTuningSyncSummary, error) {
+	summary := &FineTuningSyncSummary{}
+	if s.client == nil {
+		return summary, nil
+	}
+	jobs, err := s.jobRepo.ListPending(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list pending fine-tuning jobs", err)
+	}
+
+	var added []string
+	for i := range jobs {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		job := &jobs[i]
+		var remote *openai.FineTuningJob
+		if !fineTuningJobFinished(job.Status) {
+			if remote, err = s.client.GetFineTuningJob(ctx, job.JobID); err != nil {
+				s.logger.Warn("刷新微调任务状态失败", zap.String("job_id", job.JobID), zap.Error(err))
+				continue
+			}
+			summary.Checked++
+			if remote.Finished() {
+				summary.Finished++
+			}
+		}
+
+		_, model, err := s.apply(ctx, job, remote)
+		if err != nil {
+			return summary, err
+		}
+		if model != "" {
+			added = append(added, model)
+			summary.Registered++
+		}
+	}
+	s.notifyModelsAdded(added)
+	return summary, nil
+}
+
+// apply 保存 OpenAI 返回的任务状态（remote 为空时只登记模型），任务成功后以禁用状态添加微调模型。
+// 返回更新后的任务和本次新添加的模型名称
+func (s *fineTuningService) apply(ctx context.Context, job *fine_tuning_jobs.FineTuningJob, remote *openai.FineTuningJob) (*fine_tuning_jobs.FineTuningJob, string, error) {
+	updated := *job
+	if remote != nil {
+		updated.Status = remote.Status
+		updated.FineTunedModel = remote.FineTunedModel
+		updated.Error = ""
+		if remote.Error != nil {
+			updated.Error = remote.Error.Message
+		}
+		updated.FinishedAt = sql.NullTime{}
+		if remote.FinishedAt != nil {
+			updated.FinishedAt = sql.NullTime{Time: time.Unix(*remote.FinishedAt, 0).UTC(), Valid: true}
+		}
+		if updated.Status != job.Status || updated.FineTunedModel != job.FineTunedModel || updated.Error != job.Error {
+			err := s.jobRepo.UpdateStatus(ctx, fine_tuning_jobs.UpdateFineTuningJobStatusParams{
+				JobID:          updated.JobID,
+				Status:         updated.Status,
+				FineTunedModel: updated.FineTunedModel,
+				Error:          updated.Error,
+				FinishedAt:     updated.FinishedAt,
+			})
+			if err != nil {
+				return nil, "", errors.NewDatabaseError("update fine-tuning job", err)
+			}
+			s.logger.Info("微调任务状态变化", zap.String("job_id", updated.JobID), zap.String("from", job.Status), zap.String("to", updated.Status))
+		}
+	}
+
+	if updated.Status != openai.FineTuningStatusSucceeded || updated.FineTunedModel == "" || updated.ModelRegistered {
+		return &updated, "", nil
+	}
+	added, err := s.models.AddModel(ctx, types.ProviderTypeOpenAI, updated.FineTunedModel)
+	if err != nil {
+		// 保持未登记状态，下次同步重试
+		s.logger.Error("添加微调模型失败", zap.String("job_id", updated.JobID), zap.String("model", updated.FineTunedModel), zap.Error(err))
+		return &updated, "", nil
+	}
+	if err := s.jobRepo.MarkModelRegistered(ctx, updated.JobID); err != nil {
+		return nil, "", errors.NewDatabaseError("mark fine-tuned model registered", err)
+	}
+	updated.ModelRegistered = true
+	if !added {
+		return &updated, "", nil
+	}
+	s.logger.Info("微调模型已以禁用状态添加，启用后可用", zap.String("job_id", updated.JobID), zap.String("model", updated.FineTunedModel))
+	return &updated, updated.FineTunedModel, nil
+}
+
+// notifyModelsAdded 通知 MCP 客户端模型列表已变化
+func (s *fineTuningService) notifyModelsAdded(models []string) {
+	if len(models) == 0 || s.notifier == nil {
+		return
+	}
+	s.notifier.NotifyModelsChanged([]types.ModelSyncResult{{
+		Provider: types.ProviderTypeOpenAI,
+		New:      []string{},
+		Missing:  []string{},
+		Added:    models,
+	}})
+}
+
+// fineTuningJobFinished 任务是否已结束
+func fineTuningJobFinished(status string) bool {
+	return (&openai.FineTuningJob{Status: status}).Finished()
+}
+
+// toFineTuningJobResponse 转换为微调任务信息
+func toFineTuningJobResponse(job *fine_tuning_jobs.FineTuningJob) *dto.FineTuningJobResponse {
+	resp := &dto.FineTuningJobResponse{
+		ID:              job.JobID,
+		UserID:          job.UserID,
+		Model:           job.BaseModel,
+		TrainingFile:    job.TrainingFile,
+		ValidationFile:  job.ValidationFile,
+		Suffix:          job.Suffix,
+		Status:          job.Status,
+		FineTunedModel:  job.FineTunedModel,
+		Error:           job.Error,
+		ModelRegistered: job.ModelRegistered,
+		CreatedAt:       job.CreatedAt.Time,
+		UpdatedAt:       job.UpdatedAt.Time,
+	}
+	if job.FinishedAt.Valid {
+		finished := job.FinishedAt.Time
+		resp.FinishedAt = &finished
+	}
+	return resp
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/database/generated/fine_tuning_jobs"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryFineTuningRepository 内存中的微调任务
+type memoryFineTuningRepository struct {
+	jobs []*fine_tuning_jobs.FineTuningJob
+}
+
+func (r *memoryFineTuningRepository) Create(ctx context.Context, params fine_tuning_jobs.CreateFineTuningJobParams) (*fine_tuning_jobs.FineTuningJob, error) {
+	job := &fine_tuning_jobs.FineTuningJob{
+		ID:           int64(len(r.jobs) + 1),
+		JobID:        params.JobID,
+		UserID:       params.UserID,
+		BaseModel:    params.BaseModel,
+		TrainingFile: params.TrainingFile,
+		Status:       params.Status,
+	}
+	r.jobs = append(r.jobs, job)
+	copied := *job
+	return &copied, nil
+}
+
+func (r *memoryFineTuningRepository) Get(ctx context.Context, jobID string) (*fine_tuning_jobs.FineTuningJob, error) {
+	return findRow(r.jobs, "Fine-tuning job", byJobID(jobID))
+}
+
+func (r *memoryFineTuningRepository) List(ctx context.Context, limit int) ([]fine_tuning_jobs.FineTuningJob, error) {
+	return latestRows(r.jobs, limit, anyRow[fine_tuning_jobs.FineTuningJob]), nil
+}
+
+func (r *memoryFineTuningRepository) ListPending(ctx context.Context) ([]fine_tuning_jobs.FineTuningJob, error) {
+	return listRows(r.jobs, func(job *fine_tuning_jobs.FineTuningJob) bool {
+		return !fineTuningJobFinished(job.Status) || (job.Status == openai.FineTuningStatusSucceeded && !job.ModelRegistered)
+	}), nil
+}
+
+func (r *memoryFineTuningRepository) UpdateStatus(ctx context.Context, params fine_tuning_jobs.UpdateFineTuningJobStatusParams) error {
+	updateRow(r.jobs, "Fine-tuning job", byJobID(params.JobID), func(job *fine_tuning_jobs.FineTuningJob) {
+		job.Status, job.FineTunedModel, job.Error, job.FinishedAt = params.Status, params.FineTunedModel, params.Error, params.FinishedAt
+	})
+	return nil
+}
+
+func (r *memoryFineTuningRepository) MarkModelRegistered(ctx context.Context, jobID string) error {
+	updateRow(r.jobs, "Fine-tuning job", byJobID(jobID), func(job *fine_tuning_jobs.FineTuningJob) { job.ModelRegistered = true })
+	return nil
+}
+
+func byJobID(jobID string) func(*fine_tuning_jobs.FineTuningJob) bool {
+	return func(job *fine_tuning_jobs.FineTuningJob) bool { return job.JobID == jobID }
+}
+
+// fakeFineTuningClient 返回预设状态的 OpenAI 微调接口
+type fakeFineTuningClient struct {
+	remote map[string]*openai.FineTuningJob
+}
+
+func (c *fakeFineTuningClient) UploadFile(ctx context.Context, filename string, content io.Reader) (*openai.File, error) {
+	data, _ := io.ReadAll(content)
+	return &openai.File{ID: "file-1", Filename: filename, Bytes: int64(len(data)), Purpose: openai.FilePurposeFineTune}, nil
+}
+
+func (c *fakeFineTuningClient) CreateFineTuningJob(ctx context.Context, req *openai.FineTuningJobRequest) (*openai.FineTuningJob, error) {
+	job := &openai.FineTuningJob{ID: "ftjob-1", Model: req.Model, TrainingFile: req.TrainingFile, Status: openai.FineTuningStatusValidatingFiles}
+	c.remote[job.ID] = job
+	return job, nil
+}
+
+func (c *fakeFineTuningClient) GetFineTuningJob(ctx context.Context, id string) (*openai.FineTuningJob, error) {
+	return c.remote[id], nil
+}
+
+func (c *fakeFineTuningClient) ListFineTuningJobs(ctx context.Context, limit int) ([]openai.FineTuningJob, error) {
+	return nil, nil
+}
+
+func (c *fakeFineTuningClient) CancelFineTuningJob(ctx context.Context, id string) (*openai.FineTuningJob, error) {
+	c.remote[id].Status = openai.FineTuningStatusCancelled
+	return c.remote[id], nil
+}
+
+// recordingRegistrar 记录添加的模型
+type recordingRegistrar struct {
+	models map[string]bool
+}
+
+func (r *recordingRegistrar) AddModel(ctx context.Context, providerType types.ProviderType, name string) (bool, error) {
+	if r.models[name] {
+		return false, nil
+	}
+	r.models[name] = true
+	return true, nil
+}
+
+// recordingNotifier 记录模型变更通知
+type recordingNotifier struct {
+	changes [][]types.ModelSyncResult
+}
+
+func (n *recordingNotifier) NotifyModelsChanged(changes []types.ModelSyncResult) {
+	n.changes = append(n.changes, changes)
+}
+
+func TestFineTuningService(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeFineTuningClient{remote: make(map[string]*openai.FineTuningJob)}
+	registrar := &recordingRegistrar{models: make(map[string]bool)}
+	notifier := &recordingNotifier{}
+	service := &fineTuningService{
+		jobRepo:  &memoryFineTuningRepository{},
+		client:   client,
+		models:   registrar,
+		notifier: notifier,
+		logger:   zap.NewNop(),
+	}
+
+	_, err := service.UploadFile(ctx, "train.csv", strings.NewReader("a,b"))
+	assertAppErrorCode(t, err, errors.ErrCodeFileUploadFailed)
+
+	file, err := service.UploadFile(ctx, "train.jsonl", strings.NewReader(`{"messages":[]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "file-1", file.ID)
+
+	job, err := service.CreateJob(ctx, 7, &dto.CreateFineTuningJobRequest{Model: "gpt-4o-mini-2024-07-18", TrainingFile: "file-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "ftjob-1", job.ID)
+	assert.Equal(t, openai.FineTuningStatusValidatingFiles, job.Status)
+
+	// 运行中的任务只更新状态
+	client.remote["ftjob-1"].Status = openai.FineTuningStatusRunning
+	summary, err := service.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FineTuningSyncSummary{Checked: 1}, *summary)
+	assert.Empty(t, registrar.models)
+
+	// 成功后以禁用状态添加微调模型并通知
+	finishedAt := int64(1760000000)
+	client.remote["ftjob-1"].Status = openai.FineTuningStatusSucceeded
+	client.remote["ftjob-1"].FineTunedModel = "ft:gpt-4o-mini:acme::abc123"
+	client.remote["ftjob-1"].FinishedAt = &finishedAt
+	summary, err = service.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FineTuningSyncSummary{Checked: 1, Finished: 1, Registered: 1}, *summary)
+	assert.True(t, registrar.models["ft:gpt-4o-mini:acme::abc123"])
+	require.Len(t, notifier.changes, 1)
+	assert.Equal(t, []string{"ft:gpt-4o-mini:acme::abc123"}, notifier.changes[0][0].Added)
+
+	job, err = service.GetJob(ctx, "ftjob-1")
+	require.NoError(t, err)
+	assert.True(t, job.ModelRegistered)
+	require.NotNil(t, job.FinishedAt)
+	assert.Equal(t, finishedAt, job.FinishedAt.Unix())
+
+	// 已登记的任务不再同步
+	summary, err = service.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FineTuningSyncSummary{}, *summary)
+
+	// 已结束的任务不能取消
+	_, err = service.CancelJob(ctx, "ftjob-1")
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	list, err := service.ListJobs(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, int64(7), list.Jobs[0].UserID)
+}
+
+func TestFineTuningServiceUnavailable(t *testing.T) {
+	service := &fineTuningService{jobRepo: &memoryFineTuningRepository{}, logger: zap.NewNop()}
+
+	_, err := service.CreateJob(context.Background(), 1, &dto.CreateFineTuningJobRequest{Model: "gpt-4o-mini-2024-07-18", TrainingFile: "file-1"})
+	assertAppErrorCode(t, err, errors.ErrCodeServiceUnavailable)
+
+	summary, err := service.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, FineTuningSyncSummary{}, *summary)
+}
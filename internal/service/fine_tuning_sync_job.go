@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FineTuningSyncJob 定期刷新微调任务状态并登记成功任务产生的模型的后台任务
+type FineTuningSyncJob struct {
+	fineTuningService FineTuningService
+	interval          time.Duration
+	logger            *zap.Logger
+	stop              chan struct{}
+	wg                sync.WaitGroup
+}
+
+// NewFineTuningSyncJob 创建微调任务同步任务，interval 不大于0时任务不会启动
+func NewFineTuningSyncJob(fineTuningService FineTuningService, interval time.Duration, logger *zap.Logger) *FineTuningSyncJob {
+	return &FineTuningSyncJob{
+		fineTuningService: fineTuningService,
+		interval:          interval,
+		logger:            logger,
+		stop:              make(chan struct{}),
+	}
+}
+
+// Start 启动同步任务，启动时立即执行一次
+func (j *FineTuningSyncJob) Start() {
+	if j.interval <= 0 {
+		j.logger.Info("Fine-tuning sync job disabled")
+		return
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run()
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	j.logger.Info("Fine-tuning sync job started", zap.Duration("interval", j.interval))
+}
+
+// Stop 停止同步任务并等待当前执行结束
+func (j *FineTuningSyncJob) Stop() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	j.wg.Wait()
+}
+
+// run 执行一次同步，任务停止时中断
+func (j *FineTuningSyncJob) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	summary, err := j.fineTuningService.Sync(ctx)
+	if err != nil {
+		j.logger.Error("同步微调任务失败", zap.Error(err))
+		return
+	}
+	if summary.Finished > 0 || summary.Registered > 0 {
+		j.logger.Info("微调任务同步完成",
+			zap.Int("checked", summary.Checked),
+			zap.Int("finished", summary.Finished),
+			zap.Int("registered", summary.Registered))
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+	"go-springAi/internal/mcp/tools"
+
+	"go.uber.org/zap"
+)
+
+// defaultFXCacheTTL 汇率缓存有效期
+const defaultFXCacheTTL = 10 * time.Minute
+
+// minorCurrencyUnits 以辅币计价的交易所货币代码及其对应主币与换算比例
+var minorCurrencyUnits = map[string]struct {
+	major  string
+	factor float64
+}{
+	"GBp": {major: "GBP", factor: 0.01}, // 伦敦交易所便士报价
+	"GBX": {major: "GBP", factor: 0.01},
+	"ZAc": {major: "ZAR", factor: 0.01}, // 约翰内斯堡交易所分报价
+	"ILA": {major: "ILS", factor: 0.01}, // 特拉维夫交易所阿格拉报价
+}
+
+// fxCacheEntry 汇率缓存项
+type fxCacheEntry struct {
+	rate      float64
+	expiresAt time.Time
+}
+
+// FXService 汇率服务
+type FXService struct {
+	mcpClient mcp.InternalMCPClient
+	logger    *zap.Logger
+	ttl       time.Duration
+	mu        sync.RWMutex
+	cache     map[string]fxCacheEntry
+}
+
+// NewFXService 创建汇率服务
+func NewFXService(mcpClient mcp.InternalMCPClient, logger *zap.Logger) *FXService {
+	return &FXService{
+		mcpClient: mcpClient,
+		logger:    logger,
+		ttl:       defaultFXCacheTTL,
+		cache:     make(map[string]fxCacheEntry),
+	}
+}
+
+// GetRate 获取 from 货币到 to 货币的汇率（1 from = rate to）
+func (f *FXService) GetRate(ctx context.Context, from, to string) (float64, error) {
+	fromMajor, fromFactor := normalizeCurrency(from)
+	toMajor, toFactor := normalizeCurrency(to)
+	if fromMajor == "" || toMajor == "" {
+		return 0, fmt.Errorf("货币代码不能为空")
+	}
+
+	rate := 1.0
+	if fromMajor != toMajor {
+		var err error
+		rate, err = f.getMajorRate(ctx, fromMajor, toMajor)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return rate * fromFactor / toFactor, nil
+}
+
+// Convert 将金额从 from 货币换算为 to 货币
+func (f *FXService) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	rate, err := f.GetRate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// getMajorRate 获取主币之间的汇率，优先使用缓存
+func (f *FXService) getMajorRate(ctx context.Context, from, to string) (float64, error) {
+	pair := from + to
+
+	f.mu.RLock()
+	entry, ok := f.cache[pair]
+	f.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rate, nil
+	}
+
+	// Yahoo Finance 的外汇报价代码格式为 EURUSD=X
+	resp, err := f.mcpClient.ExecuteTool(ctx, &dto.MCPExecuteRequest{
+		Name: tools.YahooFinanceToolName,
+		Arguments: map[string]interface{}{
+			"action": "quote",
+			"symbol": pair + "=X",
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取汇率 %s/%s 失败: %w", from, to, err)
+	}
+	if resp == nil || resp.IsError {
+		return 0, fmt.Errorf("获取汇率 %s/%s 失败", from, to)
+	}
+
+	var rate float64
+	for _, content := range resp.Content {
+		if quote, ok := content.Data.(*dto.StockQuote); ok && quote != nil {
+			rate = quote.Price
+			break
+		}
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("汇率 %s/%s 数据无效", from, to)
+	}
+
+	f.mu.Lock()
+	f.cache[pair] = fxCacheEntry{rate: rate, expiresAt: time.Now().Add(f.ttl)}
+	f.mu.Unlock()
+
+	f.logger.Debug("汇率已更新", zap.String("pair", pair), zap.Float64("rate", rate))
+	return rate, nil
+}
+
+// normalizeCurrency 将辅币代码转换为主币代码，返回主币代码和换算比例
+func normalizeCurrency(currency string) (string, float64) {
+	currency = strings.TrimSpace(currency)
+	if unit, ok := minorCurrencyUnits[currency]; ok {
+		return unit.major, unit.factor
+	}
+	return strings.ToUpper(currency), 1
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"go.uber.org/zap"
+)
+
+// fakeQuoteClient 返回固定汇率报价的MCP客户端
+type fakeQuoteClient struct {
+	rates map[string]float64
+	calls int
+}
+
+func (f *fakeQuoteClient) Initialize(ctx context.Context, req *dto.MCPInitializeRequest) (*dto.MCPInitializeResponse, error) {
+	return &dto.MCPInitializeResponse{}, nil
+}
+
+func (f *fakeQuoteClient) ListTools(ctx context.Context) (*dto.MCPToolsResponse, error) {
+	return &dto.MCPToolsResponse{}, nil
+}
+
+func (f *fakeQuoteClient) ExecuteTool(ctx context.Context, req *dto.MCPExecuteRequest) (*dto.MCPExecuteResponse, error) {
+	f.calls++
+	symbol, _ := req.Arguments["symbol"].(string)
+	rate, ok := f.rates[symbol]
+	if !ok {
+		return &dto.MCPExecuteResponse{IsError: true}, nil
+	}
+	return &dto.MCPExecuteResponse{
+		Content: []dto.MCPContent{{Type: "text", Data: &dto.StockQuote{Symbol: symbol, Price: rate}}},
+	}, nil
+}
+
+func (f *fakeQuoteClient) GetExecutionLog(ctx context.Context, executionID string) (*dto.MCPToolExecutionLog, error) {
+	return nil, nil
+}
+
+func (f *fakeQuoteClient) ListExecutionLogs(ctx context.Context, userID *string, limit int) ([]*dto.MCPToolExecutionLog, error) {
+	return nil, nil
+}
+
+func TestFXServiceGetRate(t *testing.T) {
+	client := &fakeQuoteClient{rates: map[string]float64{"GBPUSD=X": 1.25, "EURUSD=X": 1.1}}
+	fx := NewFXService(client, zap.NewNop())
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		expected  float64
+		expectErr bool
+	}{
+		{name: "Same currency", from: "USD", to: "usd", expected: 1},
+		{name: "Major pair", from: "EUR", to: "USD", expected: 1.1},
+		{name: "Pence quoted stock", from: "GBp", to: "USD", expected: 0.0125},
+		{name: "Pence to pounds", from: "GBp", to: "GBP", expected: 0.01},
+		{name: "Unknown pair", from: "JPY", to: "USD", expectErr: true},
+		{name: "Empty currency", from: "", to: "USD", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := fx.GetRate(ctx, tt.from, tt.to)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got rate %f", rate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(rate-tt.expected) > 1e-12 {
+				t.Errorf("expected rate %f, got %f", tt.expected, rate)
+			}
+		})
+	}
+
+	// 相同货币对应命中缓存
+	before := client.calls
+	if _, err := fx.GetRate(ctx, "EUR", "USD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != before {
+		t.Errorf("expected cached rate, got %d new quote calls", client.calls-before)
+	}
+}
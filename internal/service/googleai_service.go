@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"go-springAi/internal/googleai"
@@ -206,6 +207,19 @@ func (s *GoogleAIService) ListAllModels(ctx context.Context) (map[string]*google
 	return models, nil
 }
 
+// ListRemoteModels 从Google AI接口获取当前提供的模型
+func (s *GoogleAIService) ListRemoteModels(ctx context.Context) ([]string, error) {
+	models, err := s.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 接口返回的名称形如 models/gemini-1.5-pro
+	for i, model := range models {
+		models[i] = strings.TrimPrefix(model, "models/")
+	}
+	return models, nil
+}
+
 // GetModelConfig 获取模型配置 (类型安全的包装方法)
 func (s *GoogleAIService) GetModelConfig(name string) (*googleai.ModelConfig, error) {
 	return s.modelManager.GetModel(name)
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"go-springAi/internal/mcp/tools"
+)
+
+// NewIntegrationTokenResolver 创建外部服务工具的令牌获取函数：优先使用调用者通过 API 密钥接口
+// 以 providerType 保存的令牌，其次使用配置文件中的令牌
+func NewIntegrationTokenResolver(apiKeys APIKeyService, providerType, fallback string) tools.TokenFunc {
+	resolve := NewSearchKeyResolver(apiKeys, map[string]string{providerType: fallback})
+	return func(ctx context.Context) (string, error) {
+		return resolve(ctx, providerType)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+
+	"go.uber.org/zap"
+)
+
+// ProviderSampler 基于AI提供商实现的MCP采样器
+type ProviderSampler struct {
+	providerManager ProviderManager
+	defaultModel    string
+	logger          *zap.Logger
+}
+
+// NewProviderSampler 创建MCP采样器，未指定模型提示时使用 defaultModel
+func NewProviderSampler(providerManager ProviderManager, defaultModel string, logger *zap.Logger) mcp.Sampler {
+	return &ProviderSampler{
+		providerManager: providerManager,
+		defaultModel:    defaultModel,
+		logger:          logger,
+	}
+}
+
+// CreateMessage 按模型提示依次选择可用的提供商并生成回复
+func (s *ProviderSampler) CreateMessage(ctx context.Context, req *dto.MCPCreateMessageRequest) (*dto.MCPCreateMessageResponse, error) {
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("采样消息不能为空")
+	}
+
+	var models []string
+	if req.ModelPreferences != nil {
+		for _, hint := range req.ModelPreferences.Hints {
+			if hint.Name != "" {
+				models = append(models, hint.Name)
+			}
+		}
+	}
+	if s.defaultModel != "" {
+		models = append(models, s.defaultModel)
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("未配置采样模型")
+	}
+
+	var provider ProviderInterface
+	var model string
+	for _, candidate := range models {
+		p, err := s.providerManager.GetProviderByModelWithValidation(ctx, candidate)
+		if err != nil {
+			s.logger.Debug("采样模型不可用", zap.String("model", candidate), zap.Error(err))
+			continue
+		}
+		provider, model = p, candidate
+		break
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("没有可用于采样的模型: %v", models)
+	}
+
+	messages := make([]ProviderMessage, 0, len(req.Messages)+1)
+	if req.SystemPrompt != "" {
+		messages = append(messages, ProviderMessage{Role: "system", Content: req.SystemPrompt})
+	}
+	for _, msg := range req.Messages {
+		if msg.Content.Type != "text" {
+			return nil, fmt.Errorf("不支持的采样内容类型: %s", msg.Content.Type)
+		}
+		messages = append(messages, ProviderMessage{Role: msg.Role, Content: msg.Content.Text})
+	}
+
+	chatReq := &ProviderChatRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		maxTokens := req.MaxTokens
+		chatReq.MaxTokens = &maxTokens
+	}
+
+	s.logger.Info("MCP sampling request",
+		zap.String("provider", provider.GetName()),
+		zap.String("model", model),
+		zap.Int("message_count", len(messages)))
+
+	resp, err := provider.ChatCompletion(ctx, chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("采样请求失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("采样响应为空")
+	}
+
+	choice := resp.Choices[0]
+	if resp.Model != "" {
+		model = resp.Model
+	}
+	return &dto.MCPCreateMessageResponse{
+		Role: "assistant",
+		Content: dto.MCPContent{
+			Type: "text",
+			Text: choice.Message.Content,
+		},
+		Model:      model,
+		StopReason: choice.FinishReason,
+	}, nil
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+// mcpToolNamePattern 客户端普遍接受的工具名称格式，协议本身不限制名称
+var mcpToolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// mcpContentTypes 工具结果中允许的内容类型
+var mcpContentTypes = map[string]bool{"text": true, "image": true, "resource": true}
+
+// mcpSelfTest 一次协议自检，逐项记录检查结果
+type mcpSelfTest struct {
+	service MCPService
+	report  dto.MCPSelfTestReport
+}
+
+// RunMCPSelfTest 按MCP规范检查初始化、工具列表、工具调用和错误路径的响应，
+// 返回违反规范的项目，便于在对接外部MCP客户端前发现问题。
+// req.Tool 不为空时会真实执行该工具以检查结果和通知格式
+func RunMCPSelfTest(ctx context.Context, mcpService MCPService, req *dto.MCPSelfTestRequest) *dto.MCPSelfTestReport {
+	st := &mcpSelfTest{
+		service: mcpService,
+		report:  dto.MCPSelfTestReport{ProtocolVersion: MCPProtocolVersion, Passed: true},
+	}
+
+	violations, skipped := st.initialize(ctx)
+	st.record("initialize", violations, skipped)
+	violations, skipped = st.versionNegotiation(ctx)
+	st.record("version_negotiation", violations, skipped)
+	tools := st.listTools(ctx)
+	violations, skipped = st.callUnknownTool(ctx)
+	st.record("tools_call_unknown", violations, skipped)
+	violations, skipped = st.callWithInvalidParams(ctx, tools)
+	st.record("tools_call_invalid_params", violations, skipped)
+	st.callTool(ctx, req)
+
+	return &st.report
+}
+
+// record 记录一项检查的结果，没有违规且 skipped 不为空时记为跳过，返回的记录用于补充警告
+func (st *mcpSelfTest) record(name string, violations []string, skipped string) *dto.MCPSelfTestCheck {
+	result := dto.MCPSelfTestCheck{Name: name, Status: dto.MCPSelfTestPassed, Violations: violations}
+	switch {
+	case len(violations) > 0:
+		result.Status = dto.MCPSelfTestFailed
+		st.report.Passed = false
+	case skipped != "":
+		result.Status = dto.MCPSelfTestSkipped
+		result.Detail = skipped
+	}
+	st.report.Checks = append(st.report.Checks, result)
+	return &st.report.Checks[len(st.report.Checks)-1]
+}
+
+func (st *mcpSelfTest) initialize(ctx context.Context) ([]string, string) {
+	resp, err := st.service.Initialize(ctx, &dto.MCPInitializeRequest{
+		ProtocolVersion: MCPProtocolVersion,
+		ClientInfo:      dto.MCPClientInfo{Name: "mcp-selftest", Version: "1.0.0"},
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("initialize with the supported version failed: %v", err)}, ""
+	}
+
+	var violations []string
+	if resp.ProtocolVersion != MCPProtocolVersion {
+		violations = append(violations, fmt.Sprintf("protocolVersion must echo the requested %q, got %q", MCPProtocolVersion, resp.ProtocolVersion))
+	}
+	if resp.ServerInfo.Name == "" || resp.ServerInfo.Version == "" {
+		violations = append(violations, "serverInfo.name and serverInfo.version are required")
+	}
+	if resp.Capabilities.Tools == nil {
+		violations = append(violations, "capabilities.tools must be declared by a server that lists tools")
+	}
+	return violations, ""
+}
+
+// versionNegotiation 服务端不支持请求的版本时必须返回自己支持的版本而不是报错
+func (st *mcpSelfTest) versionNegotiation(ctx context.Context) ([]string, string) {
+	resp, err := st.service.Initialize(ctx, &dto.MCPInitializeRequest{
+		ProtocolVersion: "1970-01-01",
+		ClientInfo:      dto.MCPClientInfo{Name: "mcp-selftest", Version: "1.0.0"},
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("an unsupported protocolVersion must be answered with a supported version, got error: %v", err)}, ""
+	}
+	if resp.ProtocolVersion != MCPProtocolVersion {
+		return []string{fmt.Sprintf("an unsupported protocolVersion must be answered with a supported version, got %q", resp.ProtocolVersion)}, ""
+	}
+	return nil, ""
+}
+
+// listTools 检查 tools/list 的结构，返回客户端看到的工具定义
+func (st *mcpSelfTest) listTools(ctx context.Context) []dto.MCPTool {
+	resp, err := st.service.ListTools(ctx)
+	if err != nil {
+		st.record("tools_list", []string{fmt.Sprintf("tools/list failed: %v", err)}, "")
+		return nil
+	}
+
+	var violations, warnings []string
+	seen := make(map[string]bool, len(resp.Tools))
+	for _, tool := range resp.Tools {
+		if tool.Name == "" {
+			violations = append(violations, "tool name is required")
+		} else if !mcpToolNamePattern.MatchString(tool.Name) {
+			warnings = append(warnings, fmt.Sprintf("tool name %q does not match %s and is rejected by some clients", tool.Name, mcpToolNamePattern))
+		}
+		if seen[tool.Name] {
+			violations = append(violations, fmt.Sprintf("tool name %q is listed more than once", tool.Name))
+		}
+		seen[tool.Name] = true
+		if tool.Description == "" {
+			violations = append(violations, fmt.Sprintf("tool %q has no description", tool.Name))
+		}
+		for _, problem := range inputSchemaViolations(tool.InputSchema) {
+			violations = append(violations, fmt.Sprintf("tool %q inputSchema: %s", tool.Name, problem))
+		}
+	}
+	st.record("tools_list", violations, "").Warnings = warnings
+	return resp.Tools
+}
+
+// inputSchemaViolations 检查工具的 inputSchema 是否为 object 类型的 JSON Schema
+func inputSchemaViolations(schema map[string]interface{}) []string {
+	// 按客户端收到的JSON检查
+	var decoded map[string]interface{}
+	data, err := json.Marshal(schema)
+	if err == nil {
+		err = json.Unmarshal(data, &decoded)
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("not serializable as JSON: %v", err)}
+	}
+	if decoded == nil {
+		return []string{"is required"}
+	}
+
+	var violations []string
+	if decoded["type"] != "object" {
+		violations = append(violations, fmt.Sprintf(`type must be "object", got %v`, decoded["type"]))
+	}
+	properties, _ := decoded["properties"].(map[string]interface{})
+	if raw, ok := decoded["properties"]; ok && properties == nil {
+		violations = append(violations, fmt.Sprintf("properties must be an object, got %T", raw))
+	}
+	for name, property := range properties {
+		if _, ok := property.(map[string]interface{}); !ok {
+			violations = append(violations, fmt.Sprintf("property %q must be a schema object", name))
+		}
+	}
+	if raw, ok := decoded["required"]; ok {
+		required, isList := raw.([]interface{})
+		if !isList {
+			violations = append(violations, "required must be an array of property names")
+		}
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, exists := properties[key]; !exists {
+				violations = append(violations, fmt.Sprintf("required property %v is not defined in properties", name))
+			}
+		}
+	}
+	return violations
+}
+
+// callUnknownTool 调用不存在的工具必须返回协议错误 -32602
+func (st *mcpSelfTest) callUnknownTool(ctx context.Context) ([]string, string) {
+	name := "mcp-selftest-unknown-" + uuid.New().String()[:8]
+	resp, err := st.service.ExecuteTool(ctx, &dto.MCPExecuteRequest{Name: name, Arguments: map[string]interface{}{}})
+	if err == nil {
+		return []string{fmt.Sprintf("calling unknown tool %q must fail with a protocol error, got result isError=%v", name, resp.IsError)}, ""
+	}
+	return st.errorCodeViolations(ctx, name, -32602), ""
+}
+
+// callWithInvalidParams 缺少必填参数时必须返回协议错误 -32602，使用第一个声明了必填参数的工具
+func (st *mcpSelfTest) callWithInvalidParams(ctx context.Context, tools []dto.MCPTool) ([]string, string) {
+	for _, tool := range tools {
+		if !hasRequiredParams(tool.InputSchema) {
+			continue
+		}
+		resp, err := st.service.ExecuteTool(ctx, &dto.MCPExecuteRequest{Name: tool.Name, Arguments: map[string]interface{}{}})
+		if err == nil {
+			return []string{fmt.Sprintf("calling %q without its required arguments must fail with a protocol error, got result isError=%v", tool.Name, resp.IsError)}, ""
+		}
+		return st.errorCodeViolations(ctx, tool.Name, -32602), ""
+	}
+	return nil, "no tool declares required arguments"
+}
+
+func hasRequiredParams(schema map[string]interface{}) bool {
+	switch required := schema["required"].(type) {
+	case []string:
+		return len(required) > 0
+	case []interface{}:
+		return len(required) > 0
+	}
+	return false
+}
+
+// errorCodeViolations 检查工具最近一次执行记录的JSON-RPC错误码，检查后删除自检产生的日志
+func (st *mcpSelfTest) errorCodeViolations(ctx context.Context, toolName string, want int) []string {
+	logs, err := st.service.ListExecutionLogs(ctx, nil, 0)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot read execution logs: %v", err)}
+	}
+	var log *dto.MCPToolExecutionLog
+	for _, candidate := range logs {
+		if candidate.ToolName == toolName && candidate.Error != nil {
+			log = candidate
+			break
+		}
+	}
+	if log == nil {
+		return []string{fmt.Sprintf("no JSON-RPC error was recorded for %q", toolName)}
+	}
+	st.service.DeleteExecutionLogs([]string{log.ID})
+
+	var violations []string
+	if !validJSONRPCErrorCode(log.Error.Code) {
+		violations = append(violations, fmt.Sprintf("error code %d is outside the JSON-RPC reserved ranges", log.Error.Code))
+	}
+	if log.Error.Code != want {
+		violations = append(violations, fmt.Sprintf("error code must be %d, got %d", want, log.Error.Code))
+	}
+	if log.Error.Message == "" {
+		violations = append(violations, "error message is required")
+	}
+	return violations
+}
+
+// validJSONRPCErrorCode JSON-RPC 2.0 预定义错误码及实现定义的服务端错误码范围
+func validJSONRPCErrorCode(code int) bool {
+	return code == -32700 || (code >= -32603 && code <= -32600) || (code >= -32099 && code <= -32000)
+}
+
+// callTool 执行指定工具，检查结果结构和执行期间发出的通知
+func (st *mcpSelfTest) callTool(ctx context.Context, req *dto.MCPSelfTestRequest) {
+	if req == nil || req.Tool == "" {
+		st.record("tools_call", nil, "no tool given to call")
+		st.record("notifications", nil, "no tool given to call")
+		return
+	}
+
+	// 通知通过SSE广播，注册一个临时客户端接收
+	var events chan *dto.MCPSSEEvent
+	impl, _ := st.service.(*MCPServiceImpl)
+	if impl != nil {
+		clientID := "mcp-selftest-" + uuid.New().String()
+		events = impl.AddSSEClient(clientID)
+		defer impl.RemoveSSEClient(clientID)
+	}
+
+	arguments := req.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	resp, err := st.service.ExecuteTool(ctx, &dto.MCPExecuteRequest{Name: req.Tool, Arguments: arguments})
+	if err != nil {
+		st.record("tools_call", []string{fmt.Sprintf("tools/call %q failed: %v", req.Tool, err)}, "")
+		st.record("notifications", nil, "tool call failed")
+		return
+	}
+	st.record("tools_call", resultViolations(resp), "")
+
+	if events == nil {
+		st.record("notifications", nil, "notifications are only checked on the built-in MCP service")
+		return
+	}
+	var received []*dto.MCPSSEEvent
+	timeout := time.After(100 * time.Millisecond)
+	for len(received) == 0 {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-timeout:
+			st.record("notifications", []string{"no tool_execution notification was sent after tools/call"}, "")
+			return
+		}
+	}
+	for drained := false; !drained; {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		default:
+			drained = true
+		}
+	}
+	st.record("notifications", notificationViolations(received), "")
+}
+
+// resultViolations 检查 tools/call 结果的内容结构
+func resultViolations(resp *dto.MCPExecuteResponse) []string {
+	if resp == nil {
+		return []string{"result is empty"}
+	}
+	if resp.Content == nil {
+		return []string{"result.content must be an array"}
+	}
+	var violations []string
+	for i, content := range resp.Content {
+		switch {
+		case !mcpContentTypes[content.Type]:
+			violations = append(violations, fmt.Sprintf("content[%d].type %q must be text, image or resource", i, content.Type))
+		case content.Type == "text" && content.Text == "":
+			violations = append(violations, fmt.Sprintf("content[%d] of type text has no text", i))
+		case content.Type == "image" && (content.Data == nil || content.MimeType == ""):
+			violations = append(violations, fmt.Sprintf("content[%d] of type image needs data and mimeType", i))
+		}
+	}
+	return violations
+}
+
+// notificationViolations 检查通知事件：事件名不为空，数据为JSON对象，工具执行通知带有必需字段
+func notificationViolations(events []*dto.MCPSSEEvent) []string {
+	var violations []string
+	for _, event := range events {
+		if event.Event == "" {
+			violations = append(violations, "notification has no event name")
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+			violations = append(violations, fmt.Sprintf("%s notification data is not a JSON object: %v", event.Event, err))
+			continue
+		}
+		if event.Event != "tool_execution" {
+			continue
+		}
+		for _, field := range []string{"toolName", "executionId", "status"} {
+			if _, ok := data[field].(string); !ok {
+				violations = append(violations, fmt.Sprintf("tool_execution notification is missing %s", field))
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// echoTool 原样返回 text 参数的测试工具
+type echoTool struct {
+	mcp.BaseTool
+	content []dto.MCPContent
+}
+
+func (e *echoTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	if e.content != nil {
+		return &dto.MCPExecuteResponse{Content: e.content}, nil
+	}
+	text, _ := args["text"].(string)
+	return &dto.MCPExecuteResponse{Content: []dto.MCPContent{{Type: "text", Text: text}}}, nil
+}
+
+func (e *echoTool) Validate(args map[string]interface{}) error {
+	required, _ := e.InputSchema["required"].([]string)
+	for _, name := range required {
+		if _, ok := args[name]; !ok {
+			return fmt.Errorf("%s is required", name)
+		}
+	}
+	return nil
+}
+
+func newEchoTool(name string, schema map[string]interface{}) *echoTool {
+	return &echoTool{BaseTool: mcp.BaseTool{Name: name, Description: "Echo the text argument", InputSchema: schema}}
+}
+
+func checkStatuses(report *dto.MCPSelfTestReport) map[string]string {
+	statuses := make(map[string]string, len(report.Checks))
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestRunMCPSelfTest(t *testing.T) {
+	ctx := context.Background()
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop()).(*MCPServiceImpl)
+	// 只检查测试工具，默认工具的名称不是ASCII
+	mcpService.toolRegistry = mcp.NewToolRegistry()
+	require.NoError(t, mcpService.RegisterTool(newEchoTool("echo", map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string"}},
+		"required":   []string{"text"},
+	})))
+
+	report := RunMCPSelfTest(ctx, mcpService, &dto.MCPSelfTestRequest{Tool: "echo", Arguments: map[string]interface{}{"text": "hi"}})
+	assert.True(t, report.Passed, "%+v", report.Checks)
+	assert.Equal(t, map[string]string{
+		"initialize":                dto.MCPSelfTestPassed,
+		"version_negotiation":       dto.MCPSelfTestPassed,
+		"tools_list":                dto.MCPSelfTestPassed,
+		"tools_call_unknown":        dto.MCPSelfTestPassed,
+		"tools_call_invalid_params": dto.MCPSelfTestPassed,
+		"tools_call":                dto.MCPSelfTestPassed,
+		"notifications":             dto.MCPSelfTestPassed,
+	}, checkStatuses(report))
+
+	// 自检产生的错误日志不保留
+	logs, err := mcpService.ListExecutionLogs(ctx, nil, 0)
+	require.NoError(t, err)
+	for _, log := range logs {
+		assert.Nil(t, log.Error, "error log of %s was left behind", log.ToolName)
+	}
+
+	// 不指定工具时跳过调用检查
+	report = RunMCPSelfTest(ctx, mcpService, &dto.MCPSelfTestRequest{})
+	assert.True(t, report.Passed)
+	assert.Equal(t, dto.MCPSelfTestSkipped, checkStatuses(report)["tools_call"])
+
+	// 非ASCII工具名称只作为警告
+	require.NoError(t, mcpService.RegisterTool(newEchoTool("回显", map[string]interface{}{"type": "object"})))
+	report = RunMCPSelfTest(ctx, mcpService, nil)
+	assert.True(t, report.Passed)
+	for _, check := range report.Checks {
+		if check.Name == "tools_list" {
+			assert.NotEmpty(t, check.Warnings)
+		}
+	}
+}
+
+func TestRunMCPSelfTestReportsViolations(t *testing.T) {
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop())
+	require.NoError(t, mcpService.RegisterTool(newEchoTool("bad_schema", map[string]interface{}{"type": "string"})))
+	broken := newEchoTool("broken", map[string]interface{}{"type": "object"})
+	broken.content = []dto.MCPContent{{Type: "video"}}
+	require.NoError(t, mcpService.RegisterTool(broken))
+
+	report := RunMCPSelfTest(context.Background(), mcpService, &dto.MCPSelfTestRequest{Tool: "broken"})
+	assert.False(t, report.Passed)
+	statuses := checkStatuses(report)
+	assert.Equal(t, dto.MCPSelfTestFailed, statuses["tools_list"])
+	assert.Equal(t, dto.MCPSelfTestFailed, statuses["tools_call"])
+}
+
+func TestInputSchemaViolations(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		want   int
+	}{
+		{name: "Valid", schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"symbol": map[string]interface{}{"type": "string"}},
+			"required":   []string{"symbol"},
+		}},
+		{name: "No properties", schema: map[string]interface{}{"type": "object"}},
+		{name: "Missing", schema: nil, want: 1},
+		{name: "Not an object", schema: map[string]interface{}{"type": "array"}, want: 1},
+		{name: "Undefined required property", schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{"symbol"},
+		}, want: 1},
+		{name: "Property is not a schema", schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"symbol": "string"},
+		}, want: 1},
+		{name: "Unserializable", schema: map[string]interface{}{"type": "object", "default": func() {}}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, inputSchemaViolations(tt.schema), tt.want)
+		})
+	}
+}
@@ -2,15 +2,23 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/i18n"
 	"go-springAi/internal/logger"
 	"go-springAi/internal/mcp"
 	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/pagination"
 	"go-springAi/internal/repository"
+	"go-springAi/internal/requestid"
+	"go-springAi/internal/types"
+	"go-springAi/internal/webhook"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -55,7 +63,7 @@ func (a *UserServiceAdapter) GetUser(ctx context.Context, id int64) (*dto.UserRe
 // ListUsers 列出用户
 func (a *UserServiceAdapter) ListUsers(ctx context.Context, page, limit int64) ([]*dto.UserResponse, error) {
 	params := repository.NewPaginationParams(page, limit)
-	users, err := a.userRepo.List(ctx, params)
+	users, err := a.userRepo.List(ctx, params, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -77,31 +85,57 @@ type MCPService interface {
 	RegisterTool(tool mcp.Tool) error
 	// GetExecutionLog 获取执行日志
 	GetExecutionLog(ctx context.Context, executionID string) (*dto.MCPToolExecutionLog, error)
-	// ListExecutionLogs 列出执行日志
+	// ListExecutionLogs 按开始时间从新到旧列出执行日志
 	ListExecutionLogs(ctx context.Context, userID *string, limit int) ([]*dto.MCPToolExecutionLog, error)
+	// ListExecutionLogsPage 按游标分页列出执行日志，从新到旧
+	ListExecutionLogsPage(ctx context.Context, userID *string, cursor string, limit int) (*dto.MCPExecutionLogPage, error)
+	// ExpiredExecutionLogs 返回开始时间早于 before 且已结束的执行日志，按开始时间排序
+	ExpiredExecutionLogs(before time.Time) []*dto.MCPToolExecutionLog
+	// DeleteExecutionLogs 删除执行日志，用于归档后释放内存
+	DeleteExecutionLogs(ids []string)
+	// CloseSSEClients 通知并断开所有SSE客户端，用于服务关闭
+	CloseSSEClients()
+	// NotifyModelsChanged 向SSE客户端广播模型同步带来的变更
+	NotifyModelsChanged(changes []types.ModelSyncResult)
+	// WaitForExecutions 拒绝新的工具执行并等待进行中的执行结束，ctx 到期时返回错误
+	WaitForExecutions(ctx context.Context) error
 }
 
+// MCPProtocolVersion 支持的MCP协议版本
+const MCPProtocolVersion = "2024-11-05"
+
 // MCPServiceImpl MCP服务实现
 type MCPServiceImpl struct {
-	toolRegistry    *mcp.ToolRegistry
-	userService     MCPUserService
-	executionLogs   map[string]*dto.MCPToolExecutionLog
-	executionMutex  sync.RWMutex
-	sseClients      map[string]chan *dto.MCPSSEEvent
-	sseClientsMutex sync.RWMutex
-	initialized     bool
-	initMutex       sync.RWMutex
-	logger          *zap.Logger
-}
-
-// NewMCPService 创建MCP服务
-func NewMCPService(userService MCPUserService, logger *zap.Logger) MCPService {
+	toolRegistry     *mcp.ToolRegistry
+	userService      MCPUserService
+	executionLogs    map[string]*dto.MCPToolExecutionLog
+	executionMutex   sync.RWMutex
+	executions       sync.WaitGroup
+	closing          bool
+	closingMutex     sync.Mutex
+	sseClients       map[string]chan *dto.MCPSSEEvent
+	sseClientsMutex  sync.RWMutex
+	initialized      bool
+	initMutex        sync.RWMutex
+	sampler          mcp.Sampler
+	transcriptAPIKey string
+	i18nManager      *i18n.Manager
+	events           webhook.Publisher
+	logger           *zap.Logger
+}
+
+// NewMCPService 创建MCP服务，sampler 为空时不提供采样能力，events 为空时不发布工具执行事件
+func NewMCPService(userService MCPUserService, sampler mcp.Sampler, transcriptAPIKey string, i18nManager *i18n.Manager, events webhook.Publisher, logger *zap.Logger) MCPService {
 	service := &MCPServiceImpl{
-		toolRegistry:  mcp.NewToolRegistry(),
-		userService:   userService,
-		executionLogs: make(map[string]*dto.MCPToolExecutionLog),
-		sseClients:    make(map[string]chan *dto.MCPSSEEvent),
-		logger:        logger,
+		toolRegistry:     mcp.NewToolRegistry(),
+		userService:      userService,
+		executionLogs:    make(map[string]*dto.MCPToolExecutionLog),
+		sseClients:       make(map[string]chan *dto.MCPSSEEvent),
+		sampler:          sampler,
+		transcriptAPIKey: transcriptAPIKey,
+		i18nManager:      i18nManager,
+		events:           events,
+		logger:           logger,
 	}
 
 	// 注册默认工具
@@ -117,17 +151,38 @@ func (s *MCPServiceImpl) registerDefaultTools() {
 	s.toolRegistry.Register(yahooFinanceTool)
 
 	// 注册股票分析工具
-	stockAnalysisTool := tools.NewStockAnalysisTool()
+	stockAnalysisTool := tools.NewStockAnalysisTool(s.i18nManager)
 	s.toolRegistry.Register(stockAnalysisTool)
 
 	// 注册股票对比工具
-	stockCompareTool := tools.NewStockCompareTool()
+	stockCompareTool := tools.NewStockCompareTool(s.i18nManager)
 	s.toolRegistry.Register(stockCompareTool)
 
 	// 注册股票投资建议工具
-	stockAdviceTool := tools.NewStockAdviceTool()
+	stockAdviceTool := tools.NewStockAdviceTool(s.i18nManager)
 	s.toolRegistry.Register(stockAdviceTool)
 
+	// 注册股票图表工具
+	stockChartTool := tools.NewStockChartTool()
+	s.toolRegistry.Register(stockChartTool)
+
+	// 注册计算器工具
+	calculatorTool := tools.NewCalculatorTool()
+	s.toolRegistry.Register(calculatorTool)
+
+	// 注册日期时间工具
+	dateTimeTool := tools.NewDateTimeTool()
+	s.toolRegistry.Register(dateTimeTool)
+
+	// 注册财报电话会议摘要和翻译工具（依赖采样能力）
+	if s.sampler != nil {
+		earningsSummaryTool := tools.NewEarningsSummaryTool(s.sampler, s.transcriptAPIKey, s.i18nManager)
+		s.toolRegistry.Register(earningsSummaryTool)
+
+		translateTool := tools.NewTranslateTool(s.sampler, s.i18nManager)
+		s.toolRegistry.Register(translateTool)
+	}
+
 	s.logger.Info("Default MCP tools registered",
 		logger.Module(logger.ModuleService),
 		logger.Component("mcp"),
@@ -143,13 +198,8 @@ func (s *MCPServiceImpl) Initialize(ctx context.Context, req *dto.MCPInitializeR
 	if s.initialized {
 		s.logger.Info("MCP service already initialized, returning existing configuration")
 		return &dto.MCPInitializeResponse{
-			ProtocolVersion: "2024-11-05",
-			Capabilities: dto.MCPCapabilities{
-				Tools: &dto.MCPToolsCapability{
-					ListChanged: true,
-				},
-				Logging: &dto.MCPLoggingCapability{},
-			},
+			ProtocolVersion: MCPProtocolVersion,
+			Capabilities: s.capabilities(),
 			ServerInfo: dto.MCPServerInfo{
 				Name:    "Admin MCP Server",
 				Version: "1.0.0",
@@ -163,19 +213,16 @@ func (s *MCPServiceImpl) Initialize(ctx context.Context, req *dto.MCPInitializeR
 		zap.String("clientName", req.ClientInfo.Name),
 		zap.String("clientVersion", req.ClientInfo.Version))
 
-	// 验证协议版本
-	if req.ProtocolVersion != "2024-11-05" {
-		return nil, fmt.Errorf("unsupported protocol version: %s", req.ProtocolVersion)
+	// 版本协商：不支持客户端请求的版本时返回服务端支持的版本，由客户端决定是否断开
+	if req.ProtocolVersion != MCPProtocolVersion {
+		s.logger.Warn("Unsupported MCP protocol version requested, offering supported version",
+			zap.String("requested", req.ProtocolVersion),
+			zap.String("supported", MCPProtocolVersion))
 	}
 
 	response := &dto.MCPInitializeResponse{
-		ProtocolVersion: "2024-11-05",
-		Capabilities: dto.MCPCapabilities{
-			Tools: &dto.MCPToolsCapability{
-				ListChanged: true,
-			},
-			Logging: &dto.MCPLoggingCapability{},
-		},
+		ProtocolVersion: MCPProtocolVersion,
+		Capabilities: s.capabilities(),
 		ServerInfo: dto.MCPServerInfo{
 			Name:    "Admin MCP Server",
 			Version: "1.0.0",
@@ -193,6 +240,20 @@ func (s *MCPServiceImpl) Initialize(ctx context.Context, req *dto.MCPInitializeR
 	return response, nil
 }
 
+// capabilities 服务端能力声明
+func (s *MCPServiceImpl) capabilities() dto.MCPCapabilities {
+	capabilities := dto.MCPCapabilities{
+		Tools: &dto.MCPToolsCapability{
+			ListChanged: true,
+		},
+		Logging: &dto.MCPLoggingCapability{},
+	}
+	if s.sampler != nil {
+		capabilities.Sampling = &dto.MCPSamplingCapability{}
+	}
+	return capabilities
+}
+
 // IsInitialized 检查是否已初始化
 func (s *MCPServiceImpl) IsInitialized() bool {
 	s.initMutex.RLock()
@@ -217,6 +278,11 @@ func (s *MCPServiceImpl) ListTools(ctx context.Context) (*dto.MCPToolsResponse,
 
 // ExecuteTool 执行工具
 func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteRequest) (*dto.MCPExecuteResponse, error) {
+	if !s.beginExecution() {
+		return nil, errors.NewServiceUnavailableError("MCP")
+	}
+	defer s.executions.Done()
+
 	executionID := uuid.New().String()
 	startTime := time.Now()
 
@@ -231,7 +297,7 @@ func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteReq
 		ToolName:  req.Name,
 		Arguments: req.Arguments,
 		StartTime: startTime,
-		RequestID: getRequestIDFromContext(ctx),
+		RequestID: requestid.FromContext(ctx),
 	}
 
 	// 从上下文获取用户ID（如果有）
@@ -248,8 +314,9 @@ func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteReq
 	tool, exists := s.toolRegistry.GetTool(req.Name)
 	if !exists {
 		err := fmt.Errorf("tool not found: %s", req.Name)
+		// 规范要求未知工具按无效参数处理
 		s.updateExecutionLog(executionID, nil, &dto.MCPError{
-			Code:    -32601,
+			Code:    -32602,
 			Message: err.Error(),
 		})
 		return nil, err
@@ -279,6 +346,7 @@ func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteReq
 			zap.String("toolName", req.Name),
 			zap.Error(err),
 			zap.Duration("duration", duration))
+		s.publishToolExecuted(ctx, executionLog, err, duration)
 		return nil, err
 	}
 
@@ -290,6 +358,7 @@ func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteReq
 		zap.String("toolName", req.Name),
 		zap.Duration("duration", duration),
 		zap.Bool("isError", result.IsError))
+	s.publishToolExecuted(ctx, executionLog, nil, duration)
 
 	// 发送SSE事件
 	s.broadcastSSEEvent(&dto.MCPSSEEvent{
@@ -301,6 +370,28 @@ func (s *MCPServiceImpl) ExecuteTool(ctx context.Context, req *dto.MCPExecuteReq
 	return result, nil
 }
 
+// publishToolExecuted 发布工具执行完成事件
+func (s *MCPServiceImpl) publishToolExecuted(ctx context.Context, log *dto.MCPToolExecutionLog, execErr error, duration time.Duration) {
+	if s.events == nil {
+		return
+	}
+	data := webhook.ToolExecutedData{
+		ExecutionID: log.ID,
+		ToolName:    log.ToolName,
+		Status:      "completed",
+		DurationMs:  duration.Milliseconds(),
+		RequestID:   log.RequestID,
+	}
+	if log.UserID != nil {
+		data.UserID = *log.UserID
+	}
+	if execErr != nil {
+		data.Status = "failed"
+		data.Error = execErr.Error()
+	}
+	s.events.Publish(ctx, webhook.EventToolExecuted, data)
+}
+
 // RegisterTool 注册工具
 func (s *MCPServiceImpl) RegisterTool(tool mcp.Tool) error {
 	definition := tool.GetDefinition()
@@ -332,29 +423,116 @@ func (s *MCPServiceImpl) GetExecutionLog(ctx context.Context, executionID string
 	return log, nil
 }
 
-// ListExecutionLogs 列出执行日志
+// ListExecutionLogs 按开始时间从新到旧列出执行日志，limit 不大于0表示不限制
 func (s *MCPServiceImpl) ListExecutionLogs(ctx context.Context, userID *string, limit int) ([]*dto.MCPToolExecutionLog, error) {
+	logs := s.sortedExecutionLogs(userID)
+	if limit > 0 && len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+// executionLogSort 执行日志列表的排序，写入游标
+const executionLogSort = "start_time desc"
+
+// executionLogKey 执行日志在列表中的位置
+type executionLogKey struct {
+	StartTime time.Time `json:"start_time"`
+	ID        string    `json:"id"`
+}
+
+func executionLogKeyOf(log *dto.MCPToolExecutionLog) executionLogKey {
+	return executionLogKey{StartTime: log.StartTime, ID: log.ID}
+}
+
+// before 列表中排在 other 之前：开始时间较新，相同时ID较大
+func (k executionLogKey) before(other executionLogKey) bool {
+	if !k.StartTime.Equal(other.StartTime) {
+		return k.StartTime.After(other.StartTime)
+	}
+	return k.ID > other.ID
+}
+
+// ListExecutionLogsPage 按游标分页列出执行日志，从新到旧；翻页期间新增的日志不会使后续页面重复
+func (s *MCPServiceImpl) ListExecutionLogsPage(ctx context.Context, userID *string, cursor string, limit int) (*dto.MCPExecutionLogPage, error) {
+	var at executionLogKey
+	c, err := pagination.Decode(cursor, executionLogSort, &at)
+	if err != nil {
+		return nil, err
+	}
+	limit = pagination.Limit(limit, 50)
+
+	// 与数据库查询一致：沿翻页方向从游标位置起多取一条
+	logs := s.sortedExecutionLogs(userID)
+	switch {
+	case c == nil:
+	case c.Backward:
+		end := sort.Search(len(logs), func(i int) bool { return !executionLogKeyOf(logs[i]).before(at) })
+		start := end - limit - 1
+		if start < 0 {
+			start = 0
+		}
+		logs = logs[start:end]
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
+	default:
+		start := sort.Search(len(logs), func(i int) bool { return at.before(executionLogKeyOf(logs[i])) })
+		logs = logs[start:]
+	}
+	if len(logs) > limit+1 {
+		logs = logs[:limit+1]
+	}
+
+	logs, page := pagination.Page(logs, limit, c, executionLogSort, func(log *dto.MCPToolExecutionLog) interface{} {
+		return executionLogKeyOf(log)
+	})
+	return &dto.MCPExecutionLogPage{Logs: logs, Count: len(logs), Limit: limit, PageInfo: page}, nil
+}
+
+// sortedExecutionLogs 返回用户的执行日志（userID 为空时返回全部），按列表顺序排列
+func (s *MCPServiceImpl) sortedExecutionLogs(userID *string) []*dto.MCPToolExecutionLog {
 	s.executionMutex.RLock()
 	defer s.executionMutex.RUnlock()
 
 	var logs []*dto.MCPToolExecutionLog
-	count := 0
-
 	for _, log := range s.executionLogs {
-		if limit > 0 && count >= limit {
-			break
-		}
-
 		// 如果指定了用户ID，只返回该用户的日志
 		if userID != nil && (log.UserID == nil || *log.UserID != *userID) {
 			continue
 		}
-
 		logs = append(logs, log)
-		count++
 	}
 
-	return logs, nil
+	// 最近开始的在前
+	sort.Slice(logs, func(i, j int) bool { return executionLogKeyOf(logs[i]).before(executionLogKeyOf(logs[j])) })
+	return logs
+}
+
+// ExpiredExecutionLogs 返回开始时间早于 before 且已结束的执行日志，按开始时间排序
+func (s *MCPServiceImpl) ExpiredExecutionLogs(before time.Time) []*dto.MCPToolExecutionLog {
+	s.executionMutex.RLock()
+	defer s.executionMutex.RUnlock()
+
+	var logs []*dto.MCPToolExecutionLog
+	for _, log := range s.executionLogs {
+		// 执行中的日志还会被更新，留到结束后再归档
+		if log.EndTime != nil && log.StartTime.Before(before) {
+			logs = append(logs, log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].StartTime.Before(logs[j].StartTime) })
+	return logs
+}
+
+// DeleteExecutionLogs 删除执行日志，用于归档后释放内存
+func (s *MCPServiceImpl) DeleteExecutionLogs(ids []string) {
+	s.executionMutex.Lock()
+	defer s.executionMutex.Unlock()
+
+	for _, id := range ids {
+		delete(s.executionLogs, id)
+	}
 }
 
 // updateExecutionLog 更新执行日志
@@ -398,6 +576,71 @@ func (s *MCPServiceImpl) RemoveSSEClient(clientID string) {
 	}
 }
 
+// CloseSSEClients 向所有SSE客户端发送关闭事件并关闭其通道
+func (s *MCPServiceImpl) CloseSSEClients() {
+	s.sseClientsMutex.Lock()
+	defer s.sseClientsMutex.Unlock()
+
+	event := &dto.MCPSSEEvent{
+		Event: "shutdown",
+		Data:  fmt.Sprintf(`{"timestamp":"%s"}`, time.Now().Format(time.RFC3339)),
+	}
+	for clientID, eventChan := range s.sseClients {
+		select {
+		case eventChan <- event:
+		default:
+		}
+		close(eventChan)
+		delete(s.sseClients, clientID)
+	}
+
+	s.logger.Info("SSE clients closed for shutdown")
+}
+
+// beginExecution 登记一次工具执行，服务关闭后返回 false
+func (s *MCPServiceImpl) beginExecution() bool {
+	s.closingMutex.Lock()
+	defer s.closingMutex.Unlock()
+	if s.closing {
+		return false
+	}
+	s.executions.Add(1)
+	return true
+}
+
+// WaitForExecutions 拒绝新的工具执行并等待进行中的执行结束
+func (s *MCPServiceImpl) WaitForExecutions(ctx context.Context) error {
+	s.closingMutex.Lock()
+	s.closing = true
+	s.closingMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.executions.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for tool executions: %w", ctx.Err())
+	}
+}
+
+// NotifyModelsChanged 向SSE客户端广播模型同步带来的变更
+func (s *MCPServiceImpl) NotifyModelsChanged(changes []types.ModelSyncResult) {
+	data, err := json.Marshal(map[string]interface{}{"providers": changes})
+	if err != nil {
+		s.logger.Warn("Failed to encode models changed event", zap.Error(err))
+		return
+	}
+	s.broadcastSSEEvent(&dto.MCPSSEEvent{
+		Event: "models_list_changed",
+		Data:  string(data),
+	})
+}
+
 // broadcastSSEEvent 广播SSE事件
 func (s *MCPServiceImpl) broadcastSSEEvent(event *dto.MCPSSEEvent) {
 	s.sseClientsMutex.RLock()
@@ -415,22 +658,23 @@ func (s *MCPServiceImpl) broadcastSSEEvent(event *dto.MCPSSEEvent) {
 	}
 }
 
+// callerKey 工具调用者用户ID的上下文键
+type callerKey struct{}
+
+// WithCaller 返回携带工具调用者用户ID的上下文，HTTP、gRPC 和 AI 助手调用工具前都通过它传递调用者
+func WithCaller(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, callerKey{}, userID)
+}
+
 // getUserIDFromContext 从上下文获取用户ID
 func getUserIDFromContext(ctx context.Context) string {
-	if userID := ctx.Value("userID"); userID != nil {
-		if id, ok := userID.(string); ok {
-			return id
-		}
+	if id, ok := ctx.Value(callerKey{}).(string); ok {
+		return id
 	}
 	return ""
 }
 
-// getRequestIDFromContext 从上下文获取请求ID
-func getRequestIDFromContext(ctx context.Context) string {
-	if requestID := ctx.Value("request_id"); requestID != nil {
-		if id, ok := requestID.(string); ok {
-			return id
-		}
-	}
-	return ""
-}
\ No newline at end of file
+// CallerUserID 返回工具调用者的用户ID，未认证时为空，供按用户隔离数据的工具使用
+func CallerUserID(ctx context.Context) string {
+	return getUserIDFromContext(ctx)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestListExecutionLogsPage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop()).(*MCPServiceImpl)
+	addLog := func(id string, start time.Time) {
+		mcpService.executionLogs[id] = &dto.MCPToolExecutionLog{ID: id, ToolName: "stock_quote", StartTime: start}
+	}
+	// c 和 d 开始时间相同，按ID排序
+	addLog("a", now.Add(-4*time.Minute))
+	addLog("b", now.Add(-3*time.Minute))
+	addLog("c", now.Add(-2*time.Minute))
+	addLog("d", now.Add(-2*time.Minute))
+	addLog("e", now.Add(-time.Minute))
+
+	ids := func(page *dto.MCPExecutionLogPage) []string {
+		var out []string
+		for _, log := range page.Logs {
+			out = append(out, log.ID)
+		}
+		return out
+	}
+
+	first, err := mcpService.ListExecutionLogsPage(ctx, nil, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e", "d"}, ids(first))
+	assert.Empty(t, first.PrevCursor)
+
+	// 新日志不会让下一页重复已返回的日志
+	addLog("f", now)
+	second, err := mcpService.ListExecutionLogsPage(ctx, nil, first.NextCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, ids(second))
+
+	last, err := mcpService.ListExecutionLogsPage(ctx, nil, second.NextCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, ids(last))
+	assert.Empty(t, last.NextCursor)
+
+	back, err := mcpService.ListExecutionLogsPage(ctx, nil, second.PrevCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e", "d"}, ids(back))
+	require.NotEmpty(t, back.PrevCursor)
+
+	newest, err := mcpService.ListExecutionLogsPage(ctx, nil, back.PrevCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f"}, ids(newest))
+	assert.Empty(t, newest.PrevCursor)
+
+	_, err = mcpService.ListExecutionLogsPage(ctx, nil, "not-a-cursor", 2)
+	assert.Error(t, err)
+}
+
+// blockingTool 收到 release 信号前一直执行的测试工具
+type blockingTool struct {
+	echoTool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingTool) Execute(ctx context.Context, args map[string]interface{}) (*dto.MCPExecuteResponse, error) {
+	close(b.started)
+	<-b.release
+	return b.echoTool.Execute(ctx, args)
+}
+
+func TestWaitForExecutionsRejectsNewExecutions(t *testing.T) {
+	ctx := context.Background()
+	mcpService := NewMCPService(nil, nil, "", nil, nil, zap.NewNop()).(*MCPServiceImpl)
+	tool := &blockingTool{
+		echoTool: *newEchoTool("slow", map[string]interface{}{"type": "object"}),
+		started:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	require.NoError(t, mcpService.RegisterTool(tool))
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := mcpService.ExecuteTool(ctx, &dto.MCPExecuteRequest{Name: "slow", Arguments: map[string]interface{}{"text": "hi"}})
+		result <- err
+	}()
+	<-tool.started
+
+	// 进行中的执行未结束时等待超时
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mcpService.WaitForExecutions(waitCtx), context.DeadlineExceeded)
+
+	// 开始关闭后拒绝新的执行
+	_, err := mcpService.ExecuteTool(ctx, &dto.MCPExecuteRequest{Name: "slow"})
+	appErr, ok := errors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, errors.ErrCodeServiceUnavailable, appErr.Code)
+
+	close(tool.release)
+	require.NoError(t, mcpService.WaitForExecutions(ctx))
+	require.NoError(t, <-result)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go-springAi/internal/dto"
+)
+
+// extractedMemory 从一条用户消息中提取的记忆，key 相同的记忆互相覆盖
+type extractedMemory struct {
+	Category string
+	Key      string
+	Content  string
+}
+
+// maxMemoryKeyLength 与 user_memories.memory_key 的列宽一致，maxPreferenceLength 为偏好内容的最大长度
+const (
+	maxMemoryKeyLength  = 191
+	maxPreferenceLength = 160
+)
+
+// memoryTickerList 股票代码列表，如 "AAPL, MSFT and $NVDA"；列表中后续的代码至少两个字母，
+// 以免把 "AAPL and I bought ..." 中的 I 当作代码
+const memoryTickerList = `(\$?[A-Z]{1,5}(?:\.[A-Z]{1,2})?\b(?:\s*(?:,|&|(?i:and))\s*\$?[A-Z]{2,5}(?:\.[A-Z]{1,2})?\b)*)`
+
+var (
+	// memoryRiskPatterns 风险偏好，第一个分组为风险等级
+	memoryRiskPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\brisk\s+(?:tolerance|appetite|profile)\s+(?:is\s+|=\s*|:\s*)?(?:very\s+|fairly\s+|quite\s+|pretty\s+|rather\s+)?(low|medium|moderate|balanced|high|conservative|aggressive)\b`),
+		regexp.MustCompile(`(?i)\bI(?:'m|\s+am)\s+(?:an?\s+)?(?:very\s+|fairly\s+|quite\s+|pretty\s+|rather\s+)?(conservative|moderate|balanced|aggressive)\s+investor\b`),
+		regexp.MustCompile(`(?i)\bI(?:'m|\s+am)\s+(?:very\s+|quite\s+|pretty\s+|rather\s+)?(risk[- ]averse)\b`),
+		regexp.MustCompile(`风险(?:承受能力|承受度|偏好)\s*(?:是|为|比较|较|偏)?\s*(低|中等|中|高|保守|稳健|激进)`),
+	}
+
+	// memoryHorizonPatterns 投资期限，第一个分组为期限描述
+	memoryHorizonPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:investment\s+|time\s+)+horizon\s+is\s+(?:about\s+|around\s+|roughly\s+)?([^.!?\n,;]{2,40})`),
+		regexp.MustCompile(`(?i)\bI(?:'m|\s+am)\s+an?\s+((?:long|short)[- ]term)\s+investor\b`),
+	}
+
+	// memoryFavoritePatterns 喜欢的股票，第一个分组为股票代码列表
+	memoryFavoritePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?i:my\s+(?:favou?rite|preferred)\s+(?:stocks?|tickers?|companies|shares)\s+(?:is|are|include)\s+)` + memoryTickerList),
+		regexp.MustCompile(`我(?:最)?喜欢的股票(?:是|有)\s*` + memoryTickerList),
+	}
+
+	// memoryHoldingPatterns 持有的股票，第一个分组为股票代码列表
+	memoryHoldingPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?i:I\s+(?:own|hold|bought)\s+(?:some\s+)?(?:[\d,.]+\s+)?(?:shares\s+(?:of|in)\s+)?)` + memoryTickerList),
+		regexp.MustCompile(`我(?:持有|买了)\s*` + memoryTickerList),
+	}
+
+	// memoryPreferencePatterns 回答方式等偏好，第一个分组为偏好内容
+	memoryPreferencePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:please\s+)?(always\s+(?:answer|respond|reply|write|use|show|include|give|explain)\b[^.!?\n]{3,160})`),
+		regexp.MustCompile(`(?i)\bI\s+prefer\s+([^.!?\n]{3,160})`),
+	}
+
+	// memoryTickerPattern 股票代码列表中的单个代码
+	memoryTickerPattern = regexp.MustCompile(`[A-Z]{1,5}(?:\.[A-Z]{1,2})?`)
+
+	// memoryPortfolioPattern 询问自己的持仓或关注的股票，此时召回所有股票相关的记忆
+	memoryPortfolioPattern = regexp.MustCompile(`(?i)\b(?:favou?rites?|portfolio|holdings?|watch\s*list|positions?|my\s+(?:stocks?|tickers?|shares))\b|持仓|我的股票|自选`)
+)
+
+// memoryRiskLevels 风险等级归一化
+var memoryRiskLevels = map[string]string{
+	"low":          "low",
+	"conservative": "low",
+	"risk-averse":  "low",
+	"risk averse":  "low",
+	"低":            "low",
+	"保守":           "low",
+	"medium":       "moderate",
+	"moderate":     "moderate",
+	"balanced":     "moderate",
+	"中":            "moderate",
+	"中等":           "moderate",
+	"稳健":           "moderate",
+	"high":         "high",
+	"aggressive":   "high",
+	"高":            "high",
+	"激进":           "high",
+}
+
+// memoryTickerStopWords 股票代码列表中常见的非代码大写单词
+var memoryTickerStopWords = map[string]bool{
+	"I": true, "A": true, "AND": true, "OR": true, "THE": true, "ETF": true, "ETFS": true,
+}
+
+// extractMemories 按规则从用户消息中提取长期事实和偏好：风险偏好、投资期限、喜欢和持有的股票、回答方式偏好。
+// 只匹配明确的陈述，宁可漏掉也不误记
+func extractMemories(message string) []extractedMemory {
+	var result []extractedMemory
+	seen := make(map[string]bool)
+	add := func(memory extractedMemory) {
+		if seen[memory.Key] {
+			return
+		}
+		seen[memory.Key] = true
+		result = append(result, memory)
+	}
+
+	for _, pattern := range memoryRiskPatterns {
+		if m := pattern.FindStringSubmatch(message); m != nil {
+			if level, ok := memoryRiskLevels[strings.ToLower(m[1])]; ok {
+				add(extractedMemory{
+					Category: dto.MemoryCategoryRiskTolerance,
+					Key:      dto.MemoryCategoryRiskTolerance,
+					Content:  "Risk tolerance: " + level,
+				})
+				break
+			}
+		}
+	}
+
+	for _, pattern := range memoryHorizonPatterns {
+		if m := pattern.FindStringSubmatch(message); m != nil {
+			add(extractedMemory{
+				Category: dto.MemoryCategoryInvestmentHorizon,
+				Key:      dto.MemoryCategoryInvestmentHorizon,
+				Content:  "Investment horizon: " + strings.ToLower(strings.TrimSpace(m[1])),
+			})
+			break
+		}
+	}
+
+	for _, ticker := range matchMemoryTickers(memoryFavoritePatterns, message) {
+		add(extractedMemory{
+			Category: dto.MemoryCategoryFavoriteTicker,
+			Key:      dto.MemoryCategoryFavoriteTicker + ":" + ticker,
+			Content:  "Favorite ticker: " + ticker,
+		})
+	}
+	for _, ticker := range matchMemoryTickers(memoryHoldingPatterns, message) {
+		add(extractedMemory{
+			Category: dto.MemoryCategoryHolding,
+			Key:      dto.MemoryCategoryHolding + ":" + ticker,
+			Content:  "Holds " + ticker,
+		})
+	}
+
+	for _, pattern := range memoryPreferencePatterns {
+		for _, m := range pattern.FindAllStringSubmatch(message, -1) {
+			preference := truncateRunes(strings.TrimSpace(m[1]), maxPreferenceLength)
+			add(extractedMemory{
+				Category: dto.MemoryCategoryPreference,
+				Key:      truncateRunes(dto.MemoryCategoryPreference+":"+strings.ToLower(preference), maxMemoryKeyLength),
+				Content:  "Preference: " + preference,
+			})
+		}
+	}
+	return result
+}
+
+// matchMemoryTickers 提取所有匹配中的股票代码，去掉 $ 前缀和常见的非代码单词
+func matchMemoryTickers(patterns []*regexp.Regexp, message string) []string {
+	var tickers []string
+	for _, pattern := range patterns {
+		for _, m := range pattern.FindAllStringSubmatch(message, -1) {
+			for _, ticker := range memoryTickerPattern.FindAllString(m[1], -1) {
+				if !memoryTickerStopWords[ticker] {
+					tickers = append(tickers, ticker)
+				}
+			}
+		}
+	}
+	return tickers
+}
+
+// memoryMentioned 判断记忆是否与消息相关：风险偏好、投资期限和回答偏好总是相关，
+// 股票记忆在消息提到该股票或询问自己的持仓时相关
+func memoryMentioned(category, key, message string) bool {
+	switch category {
+	case dto.MemoryCategoryFavoriteTicker, dto.MemoryCategoryHolding:
+		ticker := strings.TrimPrefix(key, category+":")
+		if memoryPortfolioPattern.MatchString(message) {
+			return true
+		}
+		// 短代码容易与普通单词混淆，只按大写匹配
+		pattern := `\b` + regexp.QuoteMeta(ticker) + `\b`
+		if len(ticker) >= 3 {
+			pattern = `(?i)` + pattern
+		}
+		return regexp.MustCompile(pattern).MatchString(message)
+	default:
+		return true
+	}
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go-springAi/internal/database/generated/memories"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxUserMemories 每个用户最多保留的记忆数，超过时删除最久未更新的
+	maxUserMemories = 100
+	// maxRecalledMemories 一次对话最多注入的记忆数
+	maxRecalledMemories = 10
+)
+
+// MemoryKeeper 从对话中提取用户记忆，并在之后的对话中召回
+type MemoryKeeper interface {
+	// Recall 获取与消息相关的记忆内容，用户关闭记忆时返回空
+	Recall(ctx context.Context, userID int64, message string) ([]string, error)
+	// Learn 从用户消息中提取长期事实和偏好并保存，用户关闭记忆时不保存
+	Learn(ctx context.Context, userID int64, message, responseID string) error
+}
+
+// MemoryService 用户长期记忆服务接口
+type MemoryService interface {
+	MemoryKeeper
+	// List 获取用户的所有记忆和记忆开关
+	List(ctx context.Context, userID int64) (*dto.MemoryListResponse, error)
+	// Delete 删除用户的一条记忆
+	Delete(ctx context.Context, userID, id int64) error
+	// DeleteAll 删除用户的所有记忆
+	DeleteAll(ctx context.Context, userID int64) (*dto.DeleteMemoriesResponse, error)
+	// GetSettings 获取用户的记忆设置，未设置时默认开启
+	GetSettings(ctx context.Context, userID int64) (*dto.MemorySettings, error)
+	// UpdateSettings 开启或关闭记忆，关闭时保留已有记忆，用户可单独删除
+	UpdateSettings(ctx context.Context, userID int64, req *dto.UpdateMemorySettingsRequest) (*dto.MemorySettings, error)
+}
+
+// memoryService 用户长期记忆服务实现
+type memoryService struct {
+	repo   repository.MemoryRepository
+	logger *zap.Logger
+}
+
+// NewMemoryService 创建用户长期记忆服务
+func NewMemoryService(repoManager repository.RepositoryManager, logger *zap.Logger) MemoryService {
+	return &memoryService{
+		repo:   repoManager.Memory(),
+		logger: logger,
+	}
+}
+
+// Recall 获取与消息相关的记忆内容，最近更新的在前
+func (s *memoryService) Recall(ctx context.Context, userID int64, message string) ([]string, error) {
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	items, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list user memories", err)
+	}
+
+	var recalled []string
+	for _, item := range items {
+		if len(recalled) >= maxRecalledMemories {
+			break
+		}
+		if memoryMentioned(item.Category, item.MemoryKey, message) {
+			recalled = append(recalled, item.Content)
+		}
+	}
+	return recalled, nil
+}
+
+// Learn 从用户消息中提取长期事实和偏好并保存，超过上限时删除最久未更新的记忆
+func (s *memoryService) Learn(ctx context.Context, userID int64, message, responseID string) error {
+	extracted := extractMemories(message)
+	if len(extracted) == 0 {
+		return nil
+	}
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil || !enabled {
+		return err
+	}
+
+	for _, memory := range extracted {
+		_, err := s.repo.Upsert(ctx, memories.UpsertUserMemoryParams{
+			UserID:           userID,
+			Category:         memory.Category,
+			MemoryKey:        memory.Key,
+			Content:          memory.Content,
+			SourceResponseID: responseID,
+		})
+		if err != nil {
+			return errors.NewDatabaseError("save user memory", err)
+		}
+	}
+
+	items, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return errors.NewDatabaseError("list user memories", err)
+	}
+	for i := maxUserMemories; i < len(items); i++ {
+		if _, err := s.repo.Delete(ctx, userID, items[i].ID); err != nil {
+			return errors.NewDatabaseError("delete user memory", err)
+		}
+	}
+
+	s.logger.Debug("User memories updated", zap.Int64("user_id", userID), zap.Int("extracted", len(extracted)))
+	return nil
+}
+
+// List 获取用户的所有记忆和记忆开关
+func (s *memoryService) List(ctx context.Context, userID int64) (*dto.MemoryListResponse, error) {
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list user memories", err)
+	}
+
+	result := &dto.MemoryListResponse{Enabled: enabled, Memories: make([]dto.MemoryResponse, 0, len(items))}
+	for i := range items {
+		result.Memories = append(result.Memories, toMemoryResponse(&items[i]))
+	}
+	return result, nil
+}
+
+// Delete 删除用户的一条记忆，其他用户的记忆按不存在处理
+func (s *memoryService) Delete(ctx context.Context, userID, id int64) error {
+	deleted, err := s.repo.Delete(ctx, userID, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete user memory", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Memory")
+	}
+
+	s.logger.Info("User memory deleted", zap.Int64("user_id", userID), zap.Int64("memory_id", id))
+	return nil
+}
+
+// DeleteAll 删除用户的所有记忆
+func (s *memoryService) DeleteAll(ctx context.Context, userID int64) (*dto.DeleteMemoriesResponse, error) {
+	deleted, err := s.repo.DeleteAll(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("delete user memories", err)
+	}
+
+	s.logger.Info("User memories deleted", zap.Int64("user_id", userID), zap.Int64("deleted", deleted))
+	return &dto.DeleteMemoriesResponse{Deleted: deleted}, nil
+}
+
+// GetSettings 获取用户的记忆设置
+func (s *memoryService) GetSettings(ctx context.Context, userID int64) (*dto.MemorySettings, error) {
+	enabled, err := s.enabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MemorySettings{Enabled: enabled}, nil
+}
+
+// UpdateSettings 开启或关闭记忆
+func (s *memoryService) UpdateSettings(ctx context.Context, userID int64, req *dto.UpdateMemorySettingsRequest) (*dto.MemorySettings, error) {
+	settings, err := s.repo.SaveSettings(ctx, userID, *req.Enabled)
+	if err != nil {
+		return nil, errors.NewDatabaseError("save user memory settings", err)
+	}
+
+	s.logger.Info("User memory settings updated", zap.Int64("user_id", userID), zap.Bool("enabled", settings.Enabled))
+	return &dto.MemorySettings{Enabled: settings.Enabled}, nil
+}
+
+// enabled 用户是否开启了记忆，未设置时默认开启
+func (s *memoryService) enabled(ctx context.Context, userID int64) (bool, error) {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return true, nil
+		}
+		return false, errors.NewDatabaseError("get user memory settings", err)
+	}
+	return settings.Enabled, nil
+}
+
+// toMemoryResponse 转换为记忆响应
+func toMemoryResponse(item *memories.UserMemory) dto.MemoryResponse {
+	return dto.MemoryResponse{
+		ID:               item.ID,
+		Category:         item.Category,
+		Content:          item.Content,
+		SourceResponseID: item.SourceResponseID,
+		CreatedAt:        item.CreatedAt.Time,
+		UpdatedAt:        item.UpdatedAt.Time,
+	}
+}
+
+// lastUserMessage 获取最后一条用户消息，之前的消息已在之前的对话中处理过
+func lastUserMessage(messages []openai.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// memoryContext 把召回的用户记忆组织为系统提示，没有记忆时返回空
+func memoryContext(recalled []string) string {
+	if len(recalled) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("What you remember about this user from earlier conversations (use it when relevant; the user's latest message takes precedence):")
+	for _, memory := range recalled {
+		builder.WriteString("\n- ")
+		builder.WriteString(memory)
+	}
+	return builder.String()
+}
+
+// withMemoryContext 把用户记忆追加到第一条系统消息，没有系统消息时添加到开头
+func withMemoryContext(messages []ProviderMessage, recalled []string) []ProviderMessage {
+	memory := memoryContext(recalled)
+	if memory == "" {
+		return messages
+	}
+	if len(messages) > 0 && messages[0].Role == "system" {
+		messages[0].Content += "\n\n" + memory
+		return messages
+	}
+	return append([]ProviderMessage{{Role: "system", Content: memory}}, messages...)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/database/generated/memories"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryUserMemoryRepository 内存中的用户记忆，最近更新的在前
+type memoryUserMemoryRepository struct {
+	items    []*memories.UserMemory
+	settings map[int64]bool
+	nextID   int64
+}
+
+func (r *memoryUserMemoryRepository) Upsert(ctx context.Context, params memories.UpsertUserMemoryParams) (*memories.UserMemory, error) {
+	for i, m := range r.items {
+		if m.UserID == params.UserID && m.MemoryKey == params.MemoryKey {
+			m.Content, m.SourceResponseID = params.Content, params.SourceResponseID
+			r.items = append([]*memories.UserMemory{m}, append(r.items[:i:i], r.items[i+1:]...)...)
+			copied := *m
+			return &copied, nil
+		}
+	}
+	r.nextID++
+	memory := &memories.UserMemory{
+		ID:               r.nextID,
+		UserID:           params.UserID,
+		Category:         params.Category,
+		MemoryKey:        params.MemoryKey,
+		Content:          params.Content,
+		SourceResponseID: params.SourceResponseID,
+	}
+	r.items = append([]*memories.UserMemory{memory}, r.items...)
+	copied := *memory
+	return &copied, nil
+}
+
+func (r *memoryUserMemoryRepository) List(ctx context.Context, userID int64) ([]memories.UserMemory, error) {
+	return listRows(r.items, func(m *memories.UserMemory) bool { return m.UserID == userID }), nil
+}
+
+func (r *memoryUserMemoryRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	return deleteRow(&r.items, func(m *memories.UserMemory) bool { return m.ID == id && m.UserID == userID }), nil
+}
+
+func (r *memoryUserMemoryRepository) DeleteAll(ctx context.Context, userID int64) (int64, error) {
+	var kept []*memories.UserMemory
+	for _, m := range r.items {
+		if m.UserID != userID {
+			kept = append(kept, m)
+		}
+	}
+	deleted := int64(len(r.items) - len(kept))
+	r.items = kept
+	return deleted, nil
+}
+
+func (r *memoryUserMemoryRepository) GetSettings(ctx context.Context, userID int64) (*memories.UserMemorySetting, error) {
+	enabled, ok := r.settings[userID]
+	if !ok {
+		return nil, errors.NewNotFoundError("User memory settings")
+	}
+	return &memories.UserMemorySetting{UserID: userID, Enabled: enabled}, nil
+}
+
+func (r *memoryUserMemoryRepository) SaveSettings(ctx context.Context, userID int64, enabled bool) (*memories.UserMemorySetting, error) {
+	if r.settings == nil {
+		r.settings = make(map[int64]bool)
+	}
+	r.settings[userID] = enabled
+	return &memories.UserMemorySetting{UserID: userID, Enabled: enabled}, nil
+}
+
+func TestExtractMemories(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected []string
+	}{
+		{"My risk tolerance is fairly high, what should I buy?", []string{"Risk tolerance: high"}},
+		{"I'm a conservative investor and my time horizon is around 10 years.", []string{"Risk tolerance: low", "Investment horizon: 10 years"}},
+		{"I am a long-term investor", []string{"Investment horizon: long-term"}},
+		{"我的风险承受能力比较低", []string{"Risk tolerance: low"}},
+		{"My favorite stocks are AAPL, $MSFT and NVDA.", []string{"Favorite ticker: AAPL", "Favorite ticker: MSFT", "Favorite ticker: NVDA"}},
+		{"I own 100 shares of TSLA and I bought BRK.B last year", []string{"Holds TSLA", "Holds BRK.B"}},
+		{"Please always answer in Chinese. I prefer dividend stocks!", []string{"Preference: always answer in Chinese", "Preference: dividend stocks"}},
+		// 只匹配明确的陈述
+		{"What is the risk of AAPL? I own a house and I bought Tesla.", nil},
+		{"Analyze MSFT for me", nil},
+	}
+
+	for _, tt := range tests {
+		var contents []string
+		for _, memory := range extractMemories(tt.message) {
+			contents = append(contents, memory.Content)
+		}
+		assert.Equal(t, tt.expected, contents, tt.message)
+	}
+
+	// 风险偏好只保留一条，股票按代码去重
+	extracted := extractMemories("My risk appetite is aggressive")
+	require.Len(t, extracted, 1)
+	assert.Equal(t, dto.MemoryCategoryRiskTolerance, extracted[0].Key)
+	extracted = extractMemories("I hold AAPL")
+	require.Len(t, extracted, 1)
+	assert.Equal(t, "holding:AAPL", extracted[0].Key)
+}
+
+func TestMemoryService(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryUserMemoryRepository{}
+	service := &memoryService{repo: repo, logger: zap.NewNop()}
+
+	require.NoError(t, service.Learn(ctx, 1, "My risk tolerance is low. My favorite stocks are AAPL and MSFT.", "resp-1"))
+	require.NoError(t, service.Learn(ctx, 1, "Actually my risk tolerance is high", "resp-2"))
+	require.NoError(t, service.Learn(ctx, 2, "I hold NVDA", "resp-3"))
+	require.NoError(t, service.Learn(ctx, 1, "Hello", "resp-4"))
+
+	list, err := service.List(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, list.Enabled)
+	require.Len(t, list.Memories, 3)
+	assert.Equal(t, "Risk tolerance: high", list.Memories[0].Content)
+	assert.Equal(t, "resp-2", list.Memories[0].SourceResponseID)
+
+	// 股票记忆只在提到该股票或询问持仓时召回
+	recalled, err := service.Recall(ctx, 1, "Should I buy more aapl?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Risk tolerance: high", "Favorite ticker: AAPL"}, recalled)
+	recalled, err = service.Recall(ctx, 1, "How is my portfolio doing?")
+	require.NoError(t, err)
+	assert.Len(t, recalled, 3)
+	recalled, err = service.Recall(ctx, 2, "What about AMD?")
+	require.NoError(t, err)
+	assert.Empty(t, recalled)
+
+	// 不能删除其他用户的记忆
+	nvda, err := service.List(ctx, 2)
+	require.NoError(t, err)
+	assertAppErrorCode(t, service.Delete(ctx, 1, nvda.Memories[0].ID), errors.ErrCodeNotFound)
+	require.NoError(t, service.Delete(ctx, 1, list.Memories[0].ID))
+
+	// 关闭记忆后不再提取和召回，已有记忆保留
+	enabled := false
+	settings, err := service.UpdateSettings(ctx, 1, &dto.UpdateMemorySettingsRequest{Enabled: &enabled})
+	require.NoError(t, err)
+	assert.False(t, settings.Enabled)
+	require.NoError(t, service.Learn(ctx, 1, "I hold AMZN", "resp-5"))
+	recalled, err = service.Recall(ctx, 1, "my portfolio")
+	require.NoError(t, err)
+	assert.Empty(t, recalled)
+	list, err = service.List(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, list.Enabled)
+	assert.Len(t, list.Memories, 2)
+
+	deleted, err := service.DeleteAll(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted.Deleted)
+	list, err = service.List(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, list.Memories, 1)
+
+	// 超过上限时删除最久未更新的记忆
+	for i := 0; i < maxUserMemories+5; i++ {
+		require.NoError(t, service.Learn(ctx, 3, fmt.Sprintf("I prefer option %d", i), ""))
+	}
+	list, err = service.List(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, list.Memories, maxUserMemories)
+	assert.Equal(t, fmt.Sprintf("Preference: option %d", maxUserMemories+4), list.Memories[0].Content)
+}
+
+func TestAIAssistantServiceMemory(t *testing.T) {
+	ctx := context.Background()
+	keeper := &memoryService{repo: &memoryUserMemoryRepository{}, logger: zap.NewNop()}
+	provider := &scriptedProvider{reply: "Noted"}
+	assistant := newTestAssistant(scriptedProviderManager{provider: provider}, testAssistantDeps{memories: keeper})
+
+	resp, err := assistant.Chat(ctx, &ChatRequest{UserID: 1, Model: "model-a", Messages: []openai.Message{{Role: "user", Content: "My risk tolerance is low and I own AAPL"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderMessage{{Role: "user", Content: "My risk tolerance is low and I own AAPL"}}, provider.messages)
+
+	list, err := keeper.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list.Memories, 2)
+	assert.Equal(t, resp.ID, list.Memories[0].SourceResponseID)
+
+	// 之后的对话把相关记忆追加到系统消息，原请求不变
+	messages := []openai.Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Is AAPL a buy?"}}
+	err = assistant.ChatStream(ctx, &ChatRequest{UserID: 1, Model: "model-a", Messages: messages}, func(*ChatStreamChunk) error { return nil })
+	require.NoError(t, err)
+	require.Len(t, provider.messages, 2)
+	system := provider.messages[0].Content
+	assert.True(t, strings.HasPrefix(system, "Be brief.\n\n"))
+	assert.Contains(t, system, "- Holds AAPL")
+	assert.Contains(t, system, "- Risk tolerance: low")
+	assert.Equal(t, "Be brief.", messages[0].Content)
+
+	// 匿名请求不使用记忆
+	_, err = assistant.Chat(ctx, &ChatRequest{Model: "model-a", Messages: []openai.Message{{Role: "user", Content: "Is AAPL a buy?"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderMessage{{Role: "user", Content: "Is AAPL a buy?"}}, provider.messages)
+}
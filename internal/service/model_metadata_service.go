@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"go-springAi/internal/database/generated/model_metadata"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/types"
+
+	"go.uber.org/zap"
+)
+
+// ModelMetadataLookup 按提供商和模型查询元数据
+type ModelMetadataLookup interface {
+	// Get 获取模型元数据，未设置时第二个返回值为 false
+	Get(provider, model string) (types.ModelMetadata, bool)
+}
+
+// ModelMetadataService 模型元数据服务，元数据保存在数据库并缓存在内存中，
+// 供模型接口展示、费用估算和上下文截断查询
+type ModelMetadataService interface {
+	ModelMetadataLookup
+	// Load 从数据库重新加载全部元数据
+	Load(ctx context.Context) error
+	// Update 覆盖保存模型元数据
+	Update(ctx context.Context, provider, model string, metadata types.ModelMetadata) (types.ModelMetadata, error)
+	// Delete 删除模型元数据，未设置时返回 NotFound 错误
+	Delete(ctx context.Context, provider, model string) error
+}
+
+// modelMetadataKey 元数据缓存键
+type modelMetadataKey struct {
+	provider string
+	model    string
+}
+
+// modelMetadataService 模型元数据服务实现
+type modelMetadataService struct {
+	repo     repository.ModelMetadataRepository
+	mu       sync.RWMutex
+	metadata map[modelMetadataKey]types.ModelMetadata
+	logger   *zap.Logger
+}
+
+// NewModelMetadataService 创建模型元数据服务，需调用 Load 加载已保存的元数据
+func NewModelMetadataService(repoManager repository.RepositoryManager, logger *zap.Logger) ModelMetadataService {
+	return &modelMetadataService{
+		repo:     repoManager.ModelMetadata(),
+		metadata: make(map[modelMetadataKey]types.ModelMetadata),
+		logger:   logger,
+	}
+}
+
+// Load 从数据库重新加载全部元数据
+func (s *modelMetadataService) Load(ctx context.Context) error {
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return errors.NewDatabaseError("list model metadata", err)
+	}
+	metadata := make(map[modelMetadataKey]types.ModelMetadata, len(items))
+	for _, item := range items {
+		metadata[modelMetadataKey{item.Provider, item.Model}] = toModelMetadata(item)
+	}
+
+	s.mu.Lock()
+	s.metadata = metadata
+	s.mu.Unlock()
+	return nil
+}
+
+// Get 获取模型元数据
+func (s *modelMetadataService) Get(provider, model string) (types.ModelMetadata, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	metadata, ok := s.metadata[modelMetadataKey{provider, model}]
+	return metadata, ok
+}
+
+// Update 覆盖保存模型元数据
+func (s *modelMetadataService) Update(ctx context.Context, provider, model string, metadata types.ModelMetadata) (types.ModelMetadata, error) {
+	item, err := s.repo.Upsert(ctx, model_metadata.UpsertModelMetadataParams{
+		Provider:       provider,
+		Model:          model,
+		ContextWindow:  int64(metadata.ContextWindow),
+		InputPrice:     nullFloat(metadata.InputPrice),
+		OutputPrice:    nullFloat(metadata.OutputPrice),
+		SupportsTools:  metadata.SupportsTools,
+		SupportsVision: metadata.SupportsVision,
+	})
+	if err != nil {
+		return types.ModelMetadata{}, errors.NewDatabaseError("save model metadata", err)
+	}
+
+	saved := toModelMetadata(*item)
+	s.mu.Lock()
+	s.metadata[modelMetadataKey{provider, model}] = saved
+	s.mu.Unlock()
+
+	s.logger.Info("Model metadata updated", zap.String("provider", provider), zap.String("model", model))
+	return saved, nil
+}
+
+// Delete 删除模型元数据
+func (s *modelMetadataService) Delete(ctx context.Context, provider, model string) error {
+	deleted, err := s.repo.Delete(ctx, provider, model)
+	if err != nil {
+		return errors.NewDatabaseError("delete model metadata", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Model metadata")
+	}
+
+	s.mu.Lock()
+	delete(s.metadata, modelMetadataKey{provider, model})
+	s.mu.Unlock()
+
+	s.logger.Info("Model metadata deleted", zap.String("provider", provider), zap.String("model", model))
+	return nil
+}
+
+// toModelMetadata 转换数据库记录
+func toModelMetadata(item model_metadata.ModelMetadatum) types.ModelMetadata {
+	metadata := types.ModelMetadata{
+		ContextWindow:  int(item.ContextWindow),
+		SupportsTools:  item.SupportsTools,
+		SupportsVision: item.SupportsVision,
+	}
+	if item.InputPrice.Valid {
+		metadata.InputPrice = &item.InputPrice.Float64
+	}
+	if item.OutputPrice.Valid {
+		metadata.OutputPrice = &item.OutputPrice.Float64
+	}
+	return metadata
+}
+
+// nullFloat 将可选数值转换为可空列
+func nullFloat(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-springAi/internal/database/generated/notification_channels"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// 通知分发默认值
+const (
+	defaultNotificationWorkers   = 2
+	defaultNotificationQueueSize = 256
+	notificationLookupTimeout    = 10 * time.Second
+)
+
+// NotificationDispatcherOptions 通知分发配置
+type NotificationDispatcherOptions struct {
+	Enabled   bool
+	Workers   int // 并发发送数
+	QueueSize int // 待发送事件的队列长度，队列满时丢弃新事件
+}
+
+// notificationEvent 待发送的事件
+type notificationEvent struct {
+	eventType string
+	data      interface{}
+}
+
+// NotificationDispatcher 将事件发送到用户订阅的通知渠道，实现 webhook.Publisher
+//
+// 配额事件发送给对应用户，密钥失效和过期事件发送给密钥所有者（系统密钥时发送给管理员），
+// 报告生成事件发送给管理员。
+type NotificationDispatcher struct {
+	repo           repository.NotificationChannelRepository
+	userRepo       repository.UserRepository
+	preferenceRepo repository.UserPreferenceRepository
+	notifier       Notifier
+	opts           NotificationDispatcherOptions
+	logger         *zap.Logger
+
+	queue chan notificationEvent
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewNotificationDispatcher 创建通知分发器
+func NewNotificationDispatcher(repoManager repository.RepositoryManager, notifier Notifier, opts NotificationDispatcherOptions, logger *zap.Logger) *NotificationDispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultNotificationWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultNotificationQueueSize
+	}
+	return &NotificationDispatcher{
+		repo:           repoManager.NotificationChannel(),
+		userRepo:       repoManager.User(),
+		preferenceRepo: repoManager.UserPreference(),
+		notifier:       notifier,
+		opts:           opts,
+		logger:         logger,
+		queue:          make(chan notificationEvent, opts.QueueSize),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Publish 将事件放入发送队列，不阻塞调用方
+func (d *NotificationDispatcher) Publish(ctx context.Context, eventType string, data interface{}) {
+	if !d.opts.Enabled || !matchesAny(eventType, NotificationEventTypes) {
+		return
+	}
+
+	select {
+	case d.queue <- notificationEvent{eventType: eventType, data: data}:
+	default:
+		d.logger.Warn("通知队列已满，丢弃事件", zap.String("event_type", eventType))
+	}
+}
+
+// Start 启动后台发送协程
+func (d *NotificationDispatcher) Start() {
+	if !d.opts.Enabled {
+		d.logger.Info("Notification dispatcher disabled")
+		return
+	}
+
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case event := <-d.queue:
+					d.deliver(event)
+				case <-d.stop:
+					return
+				}
+			}
+		}()
+	}
+
+	d.logger.Info("Notification dispatcher started",
+		zap.Int("workers", d.opts.Workers),
+		zap.Strings("channels", d.notifier.Channels()))
+}
+
+// Stop 停止后台发送并等待进行中的发送结束，队列中未发送的事件被丢弃
+func (d *NotificationDispatcher) Stop() {
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	d.wg.Wait()
+}
+
+// deliver 向订阅了事件的渠道逐个发送，单个渠道失败不影响其他渠道
+func (d *NotificationDispatcher) deliver(event notificationEvent) {
+	userID, admins := notificationAudience(event.data)
+	if userID == 0 && !admins {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationLookupTimeout)
+	channels, err := d.subscribers(ctx, event.eventType, userID, admins)
+	cancel()
+	if err != nil {
+		d.logger.Error("获取通知渠道失败", zap.String("event_type", event.eventType), zap.Error(err))
+		return
+	}
+
+	for i := range channels {
+		channel := &channels[i]
+		ctx, cancel := context.WithTimeout(context.Background(), notificationLookupTimeout)
+		recipient, err := resolveNotificationRecipient(ctx, d.userRepo, d.preferenceRepo, channel)
+		cancel()
+		if err != nil {
+			d.logger.Warn("解析通知接收方失败",
+				zap.Int64("user_id", channel.UserID),
+				zap.String("channel", channel.Channel),
+				zap.Error(err))
+			continue
+		}
+
+		// 发送超时由 Notifier 控制
+		if err := d.notifier.Send(context.Background(), channel.Channel, recipient.target, event.eventType, recipient.locale, event.data); err != nil {
+			d.logger.Warn("发送通知失败",
+				zap.Int64("user_id", channel.UserID),
+				zap.String("channel", channel.Channel),
+				zap.String("event_type", event.eventType),
+				zap.Error(err))
+			continue
+		}
+		d.logger.Debug("通知已发送",
+			zap.Int64("user_id", channel.UserID),
+			zap.String("channel", channel.Channel),
+			zap.String("event_type", event.eventType))
+	}
+}
+
+// subscribers 查找接收事件的已启用渠道，admins 为 true 时只保留管理员的渠道
+func (d *NotificationDispatcher) subscribers(ctx context.Context, eventType string, userID int64, admins bool) ([]notification_channels.NotificationChannel, error) {
+	enabled, err := d.repo.ListEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	isAdmin := make(map[int64]bool)
+	var result []notification_channels.NotificationChannel
+	for _, channel := range enabled {
+		if !webhook.Matches(webhook.ParseEventTypes(channel.Events), eventType) {
+			continue
+		}
+		if admins {
+			admin, checked := isAdmin[channel.UserID]
+			if !checked {
+				user, err := d.userRepo.GetByID(ctx, channel.UserID)
+				admin = err == nil && user.IsActive && user.IsAdmin
+				isAdmin[channel.UserID] = admin
+			}
+			if !admin {
+				continue
+			}
+		} else if channel.UserID != userID {
+			continue
+		}
+		result = append(result, channel)
+	}
+	return result, nil
+}
+
+// notificationAudience 根据事件数据确定接收者，返回具体用户或是否发送给管理员
+func notificationAudience(data interface{}) (userID int64, admins bool) {
+	switch d := data.(type) {
+	case webhook.APIKeyInvalidData:
+		if d.UserID > 0 {
+			return d.UserID, false
+		}
+		return 0, true
+	case webhook.APIKeyExpirationData:
+		if d.UserID > 0 {
+			return d.UserID, false
+		}
+		return 0, true
+	case webhook.QuotaWarningData:
+		return d.UserID, false
+	case webhook.QuotaExceededData:
+		return d.UserID, false
+	case webhook.BudgetAlertData:
+		if d.UserID > 0 {
+			return d.UserID, false
+		}
+		return 0, true
+	case webhook.ReportGeneratedData:
+		return 0, true
+	}
+	return 0, false
+}
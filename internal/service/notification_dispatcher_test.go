@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"go-springAi/internal/webhook"
+)
+
+func TestNotificationAudience(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       interface{}
+		wantUserID int64
+		wantAdmins bool
+	}{
+		{name: "Quota warning goes to the user", data: webhook.QuotaWarningData{UserID: 7}, wantUserID: 7},
+		{name: "Quota exceeded goes to the user", data: webhook.QuotaExceededData{UserID: 7}, wantUserID: 7},
+		{name: "Anonymous quota is skipped", data: webhook.QuotaExceededData{}},
+		{name: "User budget goes to the user", data: webhook.BudgetAlertData{UserID: 7, Budget: "user"}, wantUserID: 7},
+		{name: "Provider budget goes to admins", data: webhook.BudgetAlertData{Budget: "provider"}, wantAdmins: true},
+		{name: "User API key goes to the owner", data: webhook.APIKeyInvalidData{UserID: 3}, wantUserID: 3},
+		{name: "System API key goes to admins", data: webhook.APIKeyInvalidData{}, wantAdmins: true},
+		{name: "Reports go to admins", data: webhook.ReportGeneratedData{Symbol: "AAPL"}, wantAdmins: true},
+		{name: "Other events are skipped", data: webhook.UserCreatedData{UserID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, admins := notificationAudience(tt.data)
+			if userID != tt.wantUserID || admins != tt.wantAdmins {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.wantUserID, tt.wantAdmins, userID, admins)
+			}
+		})
+	}
+}
+
+func TestNormalizeNotificationEvents(t *testing.T) {
+	events, err := normalizeNotificationEvents([]string{" Quota.* ", "quota.*", webhook.EventReportGenerated})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0] != "quota.*" || events[1] != webhook.EventReportGenerated {
+		t.Errorf("unexpected events %v", events)
+	}
+
+	for _, event := range []string{webhook.EventChatCompleted, "audit.*", "unknown"} {
+		if _, err := normalizeNotificationEvents([]string{event}); err == nil {
+			t.Errorf("expected %q to be rejected", event)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-springAi/internal/database/generated/notification_channels"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/notify"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// NotificationEventTypes 会发送给用户的事件类型，其余事件只投递到 Webhook 和事件总线
+var NotificationEventTypes = []string{
+	webhook.EventAPIKeyInvalid,
+	webhook.EventAPIKeyExpiring,
+	webhook.EventAPIKeyExpired,
+	webhook.EventQuotaWarning,
+	webhook.EventQuotaExceeded,
+	webhook.EventBudgetWarning,
+	webhook.EventBudgetExceeded,
+	webhook.EventReportGenerated,
+}
+
+// Notifier 渲染并发送通知，由 notify.Notifier 实现
+type Notifier interface {
+	// Channels 返回服务端已配置的渠道
+	Channels() []string
+	// ValidateTarget 校验并规范化渠道的接收方
+	ValidateTarget(channel, target string) (string, error)
+	// Send 用 locale 对应的模板渲染事件并发送
+	Send(ctx context.Context, channel, target, event, locale string, data interface{}) error
+}
+
+// NotificationService 用户通知渠道服务接口
+type NotificationService interface {
+	// List 获取用户的通知渠道及可用渠道和事件类型
+	List(ctx context.Context, userID int64) (*dto.NotificationChannelListResponse, error)
+	// Save 创建或覆盖用户的某个通知渠道
+	Save(ctx context.Context, userID int64, channel string, req *dto.SaveNotificationChannelRequest) (*dto.NotificationChannelResponse, error)
+	// Delete 删除用户的某个通知渠道
+	Delete(ctx context.Context, userID int64, channel string) error
+	// Test 向用户已保存的渠道同步发送一条测试通知
+	Test(ctx context.Context, userID int64, channel string) error
+}
+
+// notificationService 用户通知渠道服务实现
+type notificationService struct {
+	repo           repository.NotificationChannelRepository
+	userRepo       repository.UserRepository
+	preferenceRepo repository.UserPreferenceRepository
+	notifier       Notifier
+	logger         *zap.Logger
+}
+
+// NewNotificationService 创建用户通知渠道服务
+func NewNotificationService(repoManager repository.RepositoryManager, notifier Notifier, logger *zap.Logger) NotificationService {
+	return &notificationService{
+		repo:           repoManager.NotificationChannel(),
+		userRepo:       repoManager.User(),
+		preferenceRepo: repoManager.UserPreference(),
+		notifier:       notifier,
+		logger:         logger,
+	}
+}
+
+// List 获取用户的通知渠道
+func (s *notificationService) List(ctx context.Context, userID int64) (*dto.NotificationChannelListResponse, error) {
+	channels, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list notification channels", err)
+	}
+
+	result := &dto.NotificationChannelListResponse{
+		Channels:          make([]dto.NotificationChannelResponse, 0, len(channels)),
+		AvailableChannels: s.notifier.Channels(),
+		EventTypes:        NotificationEventTypes,
+	}
+	for i := range channels {
+		result.Channels = append(result.Channels, toNotificationChannelResponse(&channels[i]))
+	}
+	return result, nil
+}
+
+// Save 创建或覆盖用户的某个通知渠道
+func (s *notificationService) Save(ctx context.Context, userID int64, channel string, req *dto.SaveNotificationChannelRequest) (*dto.NotificationChannelResponse, error) {
+	channel = strings.ToLower(channel)
+	target, err := s.notifier.ValidateTarget(channel, req.Target)
+	if err != nil {
+		return nil, notificationTargetError(channel, err)
+	}
+	events := []string{"*"}
+	if len(req.Events) > 0 {
+		if events, err = normalizeNotificationEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	saved, err := s.repo.Upsert(ctx, notification_channels.UpsertNotificationChannelParams{
+		UserID:  userID,
+		Channel: channel,
+		Target:  target,
+		Events:  webhook.FormatEventTypes(events),
+		Enabled: enabled,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("save notification channel", err)
+	}
+
+	s.logger.Info("Notification channel saved",
+		zap.Int64("user_id", userID),
+		zap.String("channel", channel),
+		zap.Strings("events", events),
+		zap.Bool("enabled", enabled))
+	resp := toNotificationChannelResponse(saved)
+	return &resp, nil
+}
+
+// Delete 删除用户的某个通知渠道
+func (s *notificationService) Delete(ctx context.Context, userID int64, channel string) error {
+	deleted, err := s.repo.Delete(ctx, userID, strings.ToLower(channel))
+	if err != nil {
+		return errors.NewDatabaseError("delete notification channel", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Notification channel")
+	}
+
+	s.logger.Info("Notification channel deleted", zap.Int64("user_id", userID), zap.String("channel", channel))
+	return nil
+}
+
+// Test 向用户已保存的渠道发送测试通知，渠道未启用时也会发送
+func (s *notificationService) Test(ctx context.Context, userID int64, channel string) error {
+	saved, err := s.repo.Get(ctx, userID, strings.ToLower(channel))
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return err
+		}
+		return errors.NewDatabaseError("get notification channel", err)
+	}
+
+	recipient, err := resolveNotificationRecipient(ctx, s.userRepo, s.preferenceRepo, saved)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{"Channel": saved.Channel}
+	if err := s.notifier.Send(ctx, saved.Channel, recipient.target, notify.EventTest, recipient.locale, data); err != nil {
+		if stderrors.Is(err, notify.ErrChannelUnavailable) {
+			return notificationTargetError(saved.Channel, err)
+		}
+		return errors.NewAppError(errors.ErrCodeExternalService, "Failed to send test notification", errors.SeverityMedium, http.StatusBadGateway).
+			WithDetails(err.Error())
+	}
+
+	s.logger.Info("Test notification sent", zap.Int64("user_id", userID), zap.String("channel", saved.Channel))
+	return nil
+}
+
+// notificationRecipient 解析后的接收方
+type notificationRecipient struct {
+	target string
+	locale string
+}
+
+// resolveNotificationRecipient 解析渠道的接收方和语言，邮件渠道未填写地址时使用账号邮箱
+func resolveNotificationRecipient(ctx context.Context, userRepo repository.UserRepository, preferenceRepo repository.UserPreferenceRepository, channel *notification_channels.NotificationChannel) (*notificationRecipient, error) {
+	recipient := &notificationRecipient{target: channel.Target}
+	if recipient.target == "" && channel.Channel == notify.ChannelEmail {
+		user, err := userRepo.GetByID(ctx, channel.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Email == "" {
+			return nil, errors.NewValidationError("The account has no email address, set a target for the email channel")
+		}
+		recipient.target = user.Email
+	}
+
+	prefs, err := loadUserPreferences(ctx, preferenceRepo, channel.UserID)
+	if err != nil {
+		return nil, err
+	}
+	recipient.locale = prefs.Locale
+	return recipient, nil
+}
+
+// normalizeNotificationEvents 去除重复并校验订阅的事件类型
+func normalizeNotificationEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !matchesAny(event, NotificationEventTypes) {
+			return nil, errors.NewValidationError(fmt.Sprintf("unknown notification event type %q", event)).
+				WithDetails(fmt.Sprintf("supported: *, %s", strings.Join(NotificationEventTypes, ", ")))
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// matchesAny 判断订阅项是否匹配任一事件类型
+func matchesAny(pattern string, eventTypes []string) bool {
+	for _, eventType := range eventTypes {
+		if webhook.Matches([]string{pattern}, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationTargetError 将渠道或接收方校验失败转换为参数错误
+func notificationTargetError(channel string, err error) *errors.AppError {
+	if stderrors.Is(err, notify.ErrChannelUnavailable) {
+		return errors.NewValidationError(fmt.Sprintf("notification channel %q is not available", channel))
+	}
+	return errors.NewValidationError("Invalid notification target").WithDetails(err.Error())
+}
+
+// toNotificationChannelResponse 转换为通知渠道响应
+func toNotificationChannelResponse(channel *notification_channels.NotificationChannel) dto.NotificationChannelResponse {
+	return dto.NotificationChannelResponse{
+		Channel:   channel.Channel,
+		Target:    channel.Target,
+		Events:    webhook.ParseEventTypes(channel.Events),
+		Enabled:   channel.Enabled,
+		CreatedAt: channel.CreatedAt.Time,
+		UpdatedAt: channel.UpdatedAt.Time,
+	}
+}
@@ -205,6 +205,11 @@ func (s *OpenAIService) ListAllModels(ctx context.Context) (map[string]*openai.M
 	return models, nil
 }
 
+// ListRemoteModels 从OpenAI接口获取当前提供的模型
+func (s *OpenAIService) ListRemoteModels(ctx context.Context) ([]string, error) {
+	return s.client.ListModels(ctx)
+}
+
 // GetModelConfig 获取模型配置 (类型安全的包装方法)
 func (s *OpenAIService) GetModelConfig(name string) (*openai.ModelConfig, error) {
 	return s.modelManager.GetModel(name)
@@ -280,4 +285,10 @@ func (a *openaiModelManagerAdapter) UpdateModel(name string, config interface{})
 		return a.ModelManager.UpdateModel(name, openaiConfig)
 	}
 	return fmt.Errorf("invalid config type for OpenAI model")
-}
\ No newline at end of file
+}
+
+// FineTuning 返回管理训练文件和微调任务的客户端，客户端不支持时返回 false
+func (s *OpenAIService) FineTuning() (openai.FineTuningClient, bool) {
+	client, ok := s.client.(openai.FineTuningClient)
+	return client, ok
+}
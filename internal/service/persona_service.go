@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"go-springAi/internal/database/generated/personas"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// AllPersonaTools allowed_tools 中表示允许所有工具的取值
+const AllPersonaTools = "*"
+
+// personaNamePattern 角色名称只能包含小写字母、数字、下划线和连字符
+var personaNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// personaPromptData 系统提示模板可使用的变量
+type personaPromptData struct {
+	Persona string
+	Model   string // 请求使用的模型，由提供商决定时为空
+	Date    string // 当天日期，格式为 2006-01-02
+}
+
+// PersonaResolver 按名称获取聊天请求使用的助手角色
+type PersonaResolver interface {
+	// Resolve 获取角色，角色未配置时返回 nil
+	Resolve(ctx context.Context, name string) (*dto.PersonaResponse, error)
+}
+
+// PersonaService 助手角色服务接口
+type PersonaService interface {
+	PersonaResolver
+	// List 获取所有角色
+	List(ctx context.Context) (*dto.PersonaListResponse, error)
+	// Get 获取角色
+	Get(ctx context.Context, id int64) (*dto.PersonaResponse, error)
+	// Create 校验系统提示模板和默认模型后创建角色
+	Create(ctx context.Context, userID int64, req *dto.PersonaRequest) (*dto.PersonaResponse, error)
+	// Update 校验后覆盖保存角色
+	Update(ctx context.Context, id int64, req *dto.PersonaRequest) (*dto.PersonaResponse, error)
+	// Delete 删除角色，之后使用该角色的请求按未配置处理
+	Delete(ctx context.Context, id int64) error
+}
+
+// personaService 助手角色服务实现
+type personaService struct {
+	repo      repository.PersonaRepository
+	providers ProviderManager
+	logger    *zap.Logger
+}
+
+// NewPersonaService 创建助手角色服务
+func NewPersonaService(repoManager repository.RepositoryManager, providers ProviderManager, logger *zap.Logger) PersonaService {
+	return &personaService{
+		repo:      repoManager.Persona(),
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// List 获取所有角色
+func (s *personaService) List(ctx context.Context) (*dto.PersonaListResponse, error) {
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list personas", err)
+	}
+
+	result := &dto.PersonaListResponse{Personas: make([]dto.PersonaResponse, 0, len(items))}
+	for i := range items {
+		result.Personas = append(result.Personas, *toPersonaResponse(&items[i]))
+	}
+	return result, nil
+}
+
+// Get 获取角色
+func (s *personaService) Get(ctx context.Context, id int64) (*dto.PersonaResponse, error) {
+	persona, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toPersonaResponse(persona), nil
+}
+
+// Create 校验系统提示模板和默认模型后创建角色
+func (s *personaService) Create(ctx context.Context, userID int64, req *dto.PersonaRequest) (*dto.PersonaResponse, error) {
+	name, allowedTools, err := s.validate(ctx, 0, req)
+	if err != nil {
+		return nil, err
+	}
+
+	persona, err := s.repo.Create(ctx, personas.CreatePersonaParams{
+		Name:            name,
+		Description:     strings.TrimSpace(req.Description),
+		SystemPrompt:    req.SystemPrompt,
+		AllowedTools:    allowedTools,
+		DefaultProvider: req.DefaultProvider,
+		DefaultModel:    req.DefaultModel,
+		Temperature:     personaTemperature(req.Temperature),
+		MaxTokens:       personaMaxTokens(req.MaxTokens),
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create persona", err)
+	}
+
+	s.logger.Info("Persona created", zap.Int64("persona_id", persona.ID), zap.String("name", persona.Name))
+	return toPersonaResponse(persona), nil
+}
+
+// Update 校验后覆盖保存角色
+func (s *personaService) Update(ctx context.Context, id int64, req *dto.PersonaRequest) (*dto.PersonaResponse, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	name, allowedTools, err := s.validate(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	persona, err := s.repo.Update(ctx, personas.UpdatePersonaParams{
+		ID:              id,
+		Name:            name,
+		Description:     strings.TrimSpace(req.Description),
+		SystemPrompt:    req.SystemPrompt,
+		AllowedTools:    allowedTools,
+		DefaultProvider: req.DefaultProvider,
+		DefaultModel:    req.DefaultModel,
+		Temperature:     personaTemperature(req.Temperature),
+		MaxTokens:       personaMaxTokens(req.MaxTokens),
+	})
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewDatabaseError("update persona", err)
+	}
+
+	s.logger.Info("Persona updated", zap.Int64("persona_id", persona.ID), zap.String("name", persona.Name))
+	return toPersonaResponse(persona), nil
+}
+
+// Delete 删除角色，之后使用该角色的请求按未配置处理
+func (s *personaService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete persona", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Persona")
+	}
+
+	s.logger.Info("Persona deleted", zap.Int64("persona_id", id))
+	return nil
+}
+
+// Resolve 获取角色，角色未配置时返回 nil
+func (s *personaService) Resolve(ctx context.Context, name string) (*dto.PersonaResponse, error) {
+	persona, err := s.repo.GetByName(ctx, NormalizePersona(name))
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return nil, nil
+		}
+		return nil, errors.NewDatabaseError("get persona", err)
+	}
+	return toPersonaResponse(persona), nil
+}
+
+// validate 校验名称、系统提示模板和默认模型，返回规范化的名称和编码后的工具列表
+func (s *personaService) validate(ctx context.Context, id int64, req *dto.PersonaRequest) (string, string, error) {
+	name := NormalizePersona(req.Name)
+	if !personaNamePattern.MatchString(name) {
+		return "", "", errors.NewValidationError("角色名称只能包含小写字母、数字、下划线和连字符")
+	}
+	existing, err := s.repo.GetByName(ctx, name)
+	if err == nil && existing.ID != id {
+		return "", "", errors.NewConflictError(fmt.Sprintf("角色 %s 已存在", name))
+	}
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+			return "", "", errors.NewDatabaseError("get persona", err)
+		}
+	}
+
+	prompt, err := renderPersonaPrompt(req.SystemPrompt, personaPromptData{Persona: name, Model: req.DefaultModel, Date: "2006-01-02"})
+	if err != nil {
+		return "", "", errors.NewValidationError("系统提示模板无效").WithDetails(err.Error())
+	}
+	if prompt == "" {
+		return "", "", errors.NewValidationError("系统提示不能为空")
+	}
+
+	if req.DefaultProvider != "" && req.DefaultModel == "" {
+		return "", "", errors.NewValidationError("指定默认提供商时必须同时指定默认模型")
+	}
+	if req.DefaultModel != "" {
+		if _, err := resolveProviderModel(ctx, s.providers, req.DefaultProvider, req.DefaultModel); err != nil {
+			return "", "", errors.NewValidationError(fmt.Sprintf("模型 %s 不可用", req.DefaultModel)).WithDetails(err.Error())
+		}
+	}
+
+	data, err := json.Marshal(normalizePersonaTools(req.AllowedTools))
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to encode persona tools").WithCause(err)
+	}
+	return name, string(data), nil
+}
+
+// normalizePersonaTools 去除空白和重复的工具名称，包含 "*" 时只保留 "*"
+func normalizePersonaTools(tools []string) []string {
+	result := make([]string, 0, len(tools))
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		tool = strings.TrimSpace(tool)
+		if tool == AllPersonaTools {
+			return []string{AllPersonaTools}
+		}
+		if tool == "" || seen[tool] {
+			continue
+		}
+		seen[tool] = true
+		result = append(result, tool)
+	}
+	return result
+}
+
+// renderPersonaPrompt 渲染角色的系统提示模板
+func renderPersonaPrompt(prompt string, data personaPromptData) (string, error) {
+	tmpl, err := template.New("persona").Parse(prompt)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(builder.String()), nil
+}
+
+// applyPersona 用角色的默认模型和参数补全请求，并把渲染后的系统提示放在最前面，
+// 请求自带的系统消息接在角色提示之后
+func applyPersona(req *ChatRequest, persona *dto.PersonaResponse, now time.Time) error {
+	if req.Provider == "" && req.Model == "" {
+		req.Provider = persona.DefaultProvider
+		req.Model = persona.DefaultModel
+	}
+	if req.Temperature == nil && persona.Temperature != nil {
+		temperature := *persona.Temperature
+		req.Temperature = &temperature
+	}
+	if req.MaxTokens == nil && persona.MaxTokens != nil {
+		maxTokens := *persona.MaxTokens
+		req.MaxTokens = &maxTokens
+	}
+
+	prompt, err := renderPersonaPrompt(persona.SystemPrompt, personaPromptData{
+		Persona: persona.Name,
+		Model:   req.Model,
+		Date:    now.Format("2006-01-02"),
+	})
+	if err != nil {
+		return errors.NewInternalError("Failed to render persona prompt").WithCause(err)
+	}
+	if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+		system := req.Messages[0]
+		system.Content = prompt + "\n\n" + system.Content
+		req.Messages = append([]openai.Message{system}, req.Messages[1:]...)
+	} else {
+		req.Messages = append([]openai.Message{{Role: "system", Content: prompt}}, req.Messages...)
+	}
+	req.PersonaConfig = persona
+	return nil
+}
+
+// allowedPersonaTools 返回角色允许使用的工具，角色未配置时不限制
+func allowedPersonaTools(tools []dto.MCPTool, persona *dto.PersonaResponse) []dto.MCPTool {
+	if persona == nil {
+		return tools
+	}
+	var filtered []dto.MCPTool
+	for _, tool := range tools {
+		if personaAllowsTool(persona, tool.Name) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// personaAllowsTool 判断角色是否允许使用工具
+func personaAllowsTool(persona *dto.PersonaResponse, name string) bool {
+	for _, allowed := range persona.AllowedTools {
+		if allowed == AllPersonaTools || allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// personaTemperature 转换为可为空的温度
+func personaTemperature(temperature *float32) sql.NullFloat64 {
+	if temperature == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: float64(*temperature), Valid: true}
+}
+
+// personaMaxTokens 转换为可为空的最大令牌数
+func personaMaxTokens(maxTokens *int) sql.NullInt64 {
+	if maxTokens == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*maxTokens), Valid: true}
+}
+
+// toPersonaResponse 转换为角色响应
+func toPersonaResponse(persona *personas.Persona) *dto.PersonaResponse {
+	resp := &dto.PersonaResponse{
+		ID:              persona.ID,
+		Name:            persona.Name,
+		Description:     persona.Description,
+		SystemPrompt:    persona.SystemPrompt,
+		AllowedTools:    []string{},
+		DefaultProvider: persona.DefaultProvider,
+		DefaultModel:    persona.DefaultModel,
+		CreatedBy:       persona.CreatedBy,
+		CreatedAt:       persona.CreatedAt.Time,
+		UpdatedAt:       persona.UpdatedAt.Time,
+	}
+	_ = json.Unmarshal([]byte(persona.AllowedTools), &resp.AllowedTools)
+	if persona.Temperature.Valid {
+		temperature := float32(persona.Temperature.Float64)
+		resp.Temperature = &temperature
+	}
+	if persona.MaxTokens.Valid {
+		maxTokens := int(persona.MaxTokens.Int64)
+		resp.MaxTokens = &maxTokens
+	}
+	return resp
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/database/generated/personas"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryPersonaRepository 内存中的助手角色
+type memoryPersonaRepository struct {
+	items  []*personas.Persona
+	nextID int64
+}
+
+func (r *memoryPersonaRepository) Create(ctx context.Context, params personas.CreatePersonaParams) (*personas.Persona, error) {
+	r.nextID++
+	persona := &personas.Persona{
+		ID:              r.nextID,
+		Name:            params.Name,
+		Description:     params.Description,
+		SystemPrompt:    params.SystemPrompt,
+		AllowedTools:    params.AllowedTools,
+		DefaultProvider: params.DefaultProvider,
+		DefaultModel:    params.DefaultModel,
+		Temperature:     params.Temperature,
+		MaxTokens:       params.MaxTokens,
+		CreatedBy:       params.CreatedBy,
+	}
+	r.items = append(r.items, persona)
+	copied := *persona
+	return &copied, nil
+}
+
+func (r *memoryPersonaRepository) Update(ctx context.Context, params personas.UpdatePersonaParams) (*personas.Persona, error) {
+	return updateRow(r.items, "Persona", personaByID(params.ID), func(p *personas.Persona) {
+		p.Name, p.Description, p.SystemPrompt, p.AllowedTools = params.Name, params.Description, params.SystemPrompt, params.AllowedTools
+		p.DefaultProvider, p.DefaultModel, p.Temperature, p.MaxTokens = params.DefaultProvider, params.DefaultModel, params.Temperature, params.MaxTokens
+	})
+}
+
+func (r *memoryPersonaRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	return deleteRow(&r.items, personaByID(id)), nil
+}
+
+func (r *memoryPersonaRepository) Get(ctx context.Context, id int64) (*personas.Persona, error) {
+	return findRow(r.items, "Persona", personaByID(id))
+}
+
+func (r *memoryPersonaRepository) GetByName(ctx context.Context, name string) (*personas.Persona, error) {
+	return findRow(r.items, "Persona", func(p *personas.Persona) bool { return p.Name == name })
+}
+
+func (r *memoryPersonaRepository) List(ctx context.Context) ([]personas.Persona, error) {
+	return listRows(r.items, anyRow[personas.Persona]), nil
+}
+
+func personaByID(id int64) func(*personas.Persona) bool {
+	return func(p *personas.Persona) bool { return p.ID == id }
+}
+
+// toolListClient 只返回固定工具列表的MCP客户端
+type toolListClient struct {
+	fakeQuoteClient
+	tools []dto.MCPTool
+}
+
+func (c *toolListClient) ListTools(ctx context.Context) (*dto.MCPToolsResponse, error) {
+	return &dto.MCPToolsResponse{Tools: c.tools}, nil
+}
+
+func TestPersonaService(t *testing.T) {
+	ctx := context.Background()
+	service := &personaService{
+		repo:      &memoryPersonaRepository{},
+		providers: newModelRepliesManager(map[string]string{"model-b": "ok"}),
+		logger:    zap.NewNop(),
+	}
+
+	invalid := []*dto.PersonaRequest{
+		{Name: "dev ops", SystemPrompt: "You are helpful."},
+		{Name: "devops", SystemPrompt: "You are {{.Missing}}."},
+		{Name: "devops", SystemPrompt: "{{/* empty */}}"},
+		{Name: "devops", SystemPrompt: "You are helpful.", DefaultProvider: "mock"},
+		{Name: "devops", SystemPrompt: "You are helpful.", DefaultModel: "missing"},
+	}
+	for _, req := range invalid {
+		_, err := service.Create(ctx, 1, req)
+		assertAppErrorCode(t, err, errors.ErrCodeValidationFailed, req.SystemPrompt)
+	}
+
+	temperature := float32(0.2)
+	devops, err := service.Create(ctx, 1, &dto.PersonaRequest{
+		Name:         " DevOps ",
+		SystemPrompt: "You are the {{.Persona}} assistant. Today is {{.Date}}.",
+		AllowedTools: []string{" shell_exec ", "shell_exec", ""},
+		DefaultModel: "model-b",
+		Temperature:  &temperature,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "devops", devops.Name)
+	assert.Equal(t, []string{"shell_exec"}, devops.AllowedTools)
+	require.NotNil(t, devops.Temperature)
+	assert.Nil(t, devops.MaxTokens)
+
+	_, err = service.Create(ctx, 1, &dto.PersonaRequest{Name: "DEVOPS", SystemPrompt: "Duplicate."})
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+
+	general, err := service.Create(ctx, 1, &dto.PersonaRequest{Name: "general", SystemPrompt: "You are a general assistant.", AllowedTools: []string{"stock_analysis", "*"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{AllPersonaTools}, general.AllowedTools)
+
+	// 更新时不能改成其他角色的名称，覆盖保存会清空未提供的参数
+	_, err = service.Update(ctx, general.ID, &dto.PersonaRequest{Name: "devops", SystemPrompt: "Rename."})
+	assertAppErrorCode(t, err, errors.ErrCodeConflict)
+	updated, err := service.Update(ctx, devops.ID, &dto.PersonaRequest{Name: "devops", SystemPrompt: devops.SystemPrompt, AllowedTools: []string{"shell_exec"}})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Temperature)
+	assert.Empty(t, updated.DefaultModel)
+
+	resolved, err := service.Resolve(ctx, "DevOps")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, devops.ID, resolved.ID)
+	missing, err := service.Resolve(ctx, "analyst")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	list, err := service.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list.Personas, 2)
+
+	require.NoError(t, service.Delete(ctx, general.ID))
+	assertAppErrorCode(t, service.Delete(ctx, general.ID), errors.ErrCodeNotFound)
+}
+
+func TestAIAssistantServicePersona(t *testing.T) {
+	ctx := context.Background()
+	personaRepo := &memoryPersonaRepository{}
+	resolver := &personaService{
+		repo:      personaRepo,
+		providers: newModelRepliesManager(map[string]string{"model-b": "ok"}),
+		logger:    zap.NewNop(),
+	}
+	_, err := resolver.Create(ctx, 1, &dto.PersonaRequest{
+		Name:         "devops",
+		SystemPrompt: "You are the {{.Persona}} assistant using {{.Model}}.",
+		AllowedTools: []string{"shell_exec"},
+		DefaultModel: "model-b",
+	})
+	require.NoError(t, err)
+	_, err = resolver.Create(ctx, 1, &dto.PersonaRequest{Name: "general", SystemPrompt: "You are a general assistant."})
+	require.NoError(t, err)
+
+	client := &toolListClient{tools: []dto.MCPTool{{Name: "stock_analysis"}, {Name: "shell_exec", Description: "Run a shell command"}}}
+	provider := &scriptedProvider{reply: "Done"}
+	assistant := newTestAssistant(scriptedProviderManager{provider: provider}, testAssistantDeps{mcpClient: client, personas: resolver})
+
+	// 角色提示在前，请求自带的系统消息接在其后，工具说明追加在最后且只包含允许的工具
+	resp, err := assistant.Chat(ctx, &ChatRequest{
+		Persona:  "DevOps",
+		UseTools: true,
+		Messages: []openai.Message{{Role: "system", Content: "Cluster: prod"}, {Role: "user", Content: "Restart nginx"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "model-b", resp.Model)
+	require.Len(t, provider.messages, 2)
+	system := provider.messages[0].Content
+	assert.True(t, strings.HasPrefix(system, "You are the devops assistant using model-b.\n\nCluster: prod"))
+	assert.Contains(t, system, "### shell_exec")
+	assert.NotContains(t, system, "stock_analysis")
+	assert.NotContains(t, system, "Financial AI Assistant")
+
+	_, err = assistant.Chat(ctx, &ChatRequest{Persona: "devops", SelectedTool: "stock_analysis", Messages: []openai.Message{{Role: "user", Content: "AAPL?"}}})
+	assertAppErrorCode(t, err, errors.ErrCodeValidationFailed)
+
+	// 没有允许的工具时忽略 use_tools
+	_, err = assistant.Chat(ctx, &ChatRequest{Persona: "general", UseTools: true, Model: "model-a", Messages: []openai.Message{{Role: "user", Content: "Hi"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderMessage{{Role: "system", Content: "You are a general assistant."}, {Role: "user", Content: "Hi"}}, provider.messages)
+
+	// 未配置的角色保持原有行为
+	_, err = assistant.Chat(ctx, &ChatRequest{Persona: "analyst", Model: "model-a", Messages: []openai.Message{{Role: "user", Content: "Hi"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderMessage{{Role: "user", Content: "Hi"}}, provider.messages)
+}
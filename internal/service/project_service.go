@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/projects"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// 项目用量查询天数
+const (
+	DefaultProjectUsageDays = 30
+	MaxProjectUsageDays     = 366
+)
+
+// projectNamePattern 项目名称只允许小写字母、数字、短横线和下划线
+var projectNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ProjectService 项目服务接口，项目用于按应用或团队区分API密钥和用量
+type ProjectService interface {
+	// Create 创建项目
+	Create(ctx context.Context, userID int64, req *dto.CreateProjectRequest) (*dto.ProjectResponse, error)
+	// List 获取用户的所有项目
+	List(ctx context.Context, userID int64) ([]dto.ProjectResponse, error)
+	// Delete 删除项目及其API密钥和用量记录
+	Delete(ctx context.Context, userID, projectID int64) error
+	// Resolve 将项目名称解析为项目ID，名称为空时返回 0 表示默认密钥
+	Resolve(ctx context.Context, userID int64, name string) (int64, error)
+	// APIKey 获取项目在指定提供商下的API密钥及其允许访问的模型
+	APIKey(ctx context.Context, userID, projectID int64, providerType string) (string, []string, error)
+	// RecordUsage 记录项目的一次AI请求及其消耗的令牌数
+	RecordUsage(ctx context.Context, projectID int64, tokens int) error
+	// GetUsage 获取项目最近 days 天的每日用量
+	GetUsage(ctx context.Context, userID, projectID int64, days int) (*dto.ProjectUsageResponse, error)
+}
+
+// projectService 项目服务实现
+type projectService struct {
+	repoManager   repository.RepositoryManager
+	projectRepo   repository.ProjectRepository
+	apiKeyRepo    repository.APIKeyRepository
+	apiKeyService APIKeyService
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// NewProjectService 创建项目服务
+func NewProjectService(repoManager repository.RepositoryManager, apiKeyService APIKeyService, logger *zap.Logger) ProjectService {
+	return &projectService{
+		repoManager:   repoManager,
+		projectRepo:   repoManager.Project(),
+		apiKeyRepo:    repoManager.APIKey(),
+		apiKeyService: apiKeyService,
+		now:           time.Now,
+		logger:        logger,
+	}
+}
+
+// Create 创建项目
+func (s *projectService) Create(ctx context.Context, userID int64, req *dto.CreateProjectRequest) (*dto.ProjectResponse, error) {
+	name, err := normalizeProjectName(req.Name)
+	if err != nil {
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	if _, err := s.projectRepo.GetByName(ctx, userID, name); err == nil {
+		return nil, errors.NewConflictError(fmt.Sprintf("项目 %s 已存在", name))
+	} else if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+		return nil, errors.NewDatabaseError("get project", err)
+	}
+
+	project, err := s.projectRepo.Create(ctx, userID, name, strings.TrimSpace(req.Description))
+	if err != nil {
+		return nil, errors.NewDatabaseError("create project", err)
+	}
+
+	s.logger.Info("Project created",
+		zap.Int64("user_id", userID),
+		zap.Int64("project_id", project.ID),
+		zap.String("project", project.Name))
+	return toProjectResponse(project, []string{}), nil
+}
+
+// List 获取用户的所有项目
+func (s *projectService) List(ctx context.Context, userID int64) ([]dto.ProjectResponse, error) {
+	items, err := s.projectRepo.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list projects", err)
+	}
+
+	result := make([]dto.ProjectResponse, 0, len(items))
+	for i := range items {
+		keys, err := s.apiKeyRepo.ListAPIKeysByProject(ctx, userID, items[i].ID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("list project api keys", err)
+		}
+		providers := make([]string, 0, len(keys))
+		for _, key := range keys {
+			providers = append(providers, key.ProviderType)
+		}
+		result = append(result, *toProjectResponse(&items[i], providers))
+	}
+	return result, nil
+}
+
+// Delete 删除项目及其API密钥和用量记录
+func (s *projectService) Delete(ctx context.Context, userID, projectID int64) error {
+	if _, err := s.projectRepo.Get(ctx, userID, projectID); err != nil {
+		return err
+	}
+
+	if err := s.apiKeyService.DeleteProjectAPIKeys(ctx, userID, projectID); err != nil {
+		return errors.NewDatabaseError("delete project api keys", err)
+	}
+
+	// 用量记录和项目在同一事务中删除
+	err := s.repoManager.WithTx(ctx, func(tx repository.RepositoryManager) error {
+		if err := tx.Project().DeleteUsage(ctx, projectID); err != nil {
+			return errors.NewDatabaseError("delete project usage", err)
+		}
+		deleted, err := tx.Project().Delete(ctx, userID, projectID)
+		if err != nil {
+			return errors.NewDatabaseError("delete project", err)
+		}
+		if !deleted {
+			return errors.NewNotFoundError("Project")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Project deleted",
+		zap.Int64("user_id", userID),
+		zap.Int64("project_id", projectID))
+	return nil
+}
+
+// Resolve 将项目名称解析为项目ID，名称为空时返回 0 表示默认密钥
+func (s *projectService) Resolve(ctx context.Context, userID int64, name string) (int64, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, nil
+	}
+	if userID <= 0 {
+		return 0, errors.NewUnauthorizedError("使用项目需要登录")
+	}
+
+	project, err := s.projectRepo.GetByName(ctx, userID, name)
+	if err != nil {
+		return 0, err
+	}
+	return project.ID, nil
+}
+
+// APIKey 获取项目在指定提供商下的API密钥及其允许访问的模型
+func (s *projectService) APIKey(ctx context.Context, userID, projectID int64, providerType string) (string, []string, error) {
+	exists, err := s.apiKeyService.CheckAPIKeyExists(ctx, userID, projectID, providerType)
+	if err != nil {
+		return "", nil, errors.NewDatabaseError("check project api key", err)
+	}
+	if !exists {
+		return "", nil, errors.NewBadRequestError(fmt.Sprintf("项目未配置 %s 的API密钥", providerType))
+	}
+
+	key, err := s.apiKeyService.GetAPIKey(ctx, userID, projectID, providerType)
+	if err != nil {
+		// 密钥过期等业务错误原样返回
+		if _, ok := errors.IsAppError(err); ok {
+			return "", nil, err
+		}
+		return "", nil, errors.NewDatabaseError("get project api key", err)
+	}
+	allowedModels, err := s.apiKeyService.GetAllowedModels(ctx, userID, projectID, providerType)
+	if err != nil {
+		return "", nil, errors.NewDatabaseError("get project allowed models", err)
+	}
+	return key, allowedModels, nil
+}
+
+// RecordUsage 记录项目的一次AI请求及其消耗的令牌数
+func (s *projectService) RecordUsage(ctx context.Context, projectID int64, tokens int) error {
+	if tokens < 0 {
+		tokens = 0
+	}
+	return s.projectRepo.RecordUsage(ctx, projectID, s.now().UTC().Format(usageDateLayout), int64(tokens))
+}
+
+// GetUsage 获取项目最近 days 天（含今天）的每日用量
+func (s *projectService) GetUsage(ctx context.Context, userID, projectID int64, days int) (*dto.ProjectUsageResponse, error) {
+	if days <= 0 {
+		days = DefaultProjectUsageDays
+	}
+	if days > MaxProjectUsageDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("days 不能超过 %d", MaxProjectUsageDays))
+	}
+
+	if _, err := s.projectRepo.Get(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	since := s.now().UTC().AddDate(0, 0, -(days - 1)).Format(usageDateLayout)
+	usage, err := s.projectRepo.ListUsage(ctx, projectID, since)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list project usage", err)
+	}
+
+	resp := &dto.ProjectUsageResponse{
+		ProjectID: projectID,
+		Since:     since,
+		Days:      make([]dto.ProjectUsageDay, 0, len(usage)),
+	}
+	for _, u := range usage {
+		resp.TotalRequests += u.RequestCount
+		resp.TotalTokens += u.TokenCount
+		resp.Days = append(resp.Days, dto.ProjectUsageDay{
+			Date:     u.UsageDate,
+			Requests: u.RequestCount,
+			Tokens:   u.TokenCount,
+		})
+	}
+	return resp, nil
+}
+
+// normalizeProjectName 校验并规范化项目名称
+func normalizeProjectName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !projectNamePattern.MatchString(name) {
+		return "", fmt.Errorf("项目名称只能包含小写字母、数字、短横线和下划线，且以字母或数字开头")
+	}
+	return name, nil
+}
+
+// toProjectResponse 转换项目为响应模型
+func toProjectResponse(p *projects.Project, providers []string) *dto.ProjectResponse {
+	return &dto.ProjectResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Providers:   providers,
+		CreatedAt:   p.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeProjectName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "lowercased and trimmed", input: "  Team-A ", want: "team-a"},
+		{name: "digits and underscore", input: "app_2", want: "app_2"},
+		{name: "empty", input: "   ", wantErr: true},
+		{name: "space inside", input: "team a", wantErr: true},
+		{name: "leading dash", input: "-team", wantErr: true},
+		{name: "max length", input: strings.Repeat("a", 64), want: strings.Repeat("a", 64)},
+		{name: "too long", input: strings.Repeat("a", 65), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeProjectName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeProjectName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeProjectName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
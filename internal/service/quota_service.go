@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// 配额角色
+const (
+	QuotaRoleAdmin     = "admin"
+	QuotaRoleUser      = "user"
+	QuotaRoleAnonymous = "anonymous"
+)
+
+// usageDateLayout 用量统计日期格式
+const usageDateLayout = "2006-01-02"
+
+// QuotaLimits 配额上限，0 表示不限制
+type QuotaLimits struct {
+	RequestsPerDay int64
+	TokensPerMonth int64
+}
+
+// QuotaPolicy 配额策略，优先级为 Users > Roles > Default
+type QuotaPolicy struct {
+	Enabled        bool
+	WarningPercent int64 // 用量达到上限的该百分比时发布预警事件，0 表示不预警
+	Default        QuotaLimits
+	Roles          map[string]QuotaLimits
+	Users          map[string]QuotaLimits // 键为小写用户名
+}
+
+// QuotaService AI用量配额服务接口
+type QuotaService interface {
+	// Check 检查用户是否还有剩余配额，userID 为 0 表示匿名请求
+	Check(ctx context.Context, userID int64) error
+	// Record 记录一次请求及其消耗的令牌数
+	Record(ctx context.Context, userID int64, tokens int) error
+	// GetStatus 获取用户当前配额使用情况
+	GetStatus(ctx context.Context, userID int64) (*dto.QuotaStatusResponse, error)
+	// Enabled 是否启用配额限制
+	Enabled() bool
+}
+
+// quotaService AI用量配额服务实现
+type quotaService struct {
+	userRepo  repository.UserRepository
+	usageRepo repository.AIUsageRepository
+	policy    QuotaPolicy
+	now       func() time.Time
+	events    webhook.Publisher
+	logger    *zap.Logger
+
+	// notified 已发送通知的配额，键为 用户ID:配额:事件，值为配额重置时间
+	notified   map[string]time.Time
+	notifiedMu sync.Mutex
+}
+
+// NewQuotaService 创建AI用量配额服务，events 为空时不发布超额和预警事件
+func NewQuotaService(repoManager repository.RepositoryManager, policy QuotaPolicy, events webhook.Publisher, logger *zap.Logger) QuotaService {
+	return &quotaService{
+		userRepo:  repoManager.User(),
+		usageRepo: repoManager.AIUsage(),
+		policy:    policy,
+		now:       time.Now,
+		events:    events,
+		logger:    logger,
+		notified:  make(map[string]time.Time),
+	}
+}
+
+// Check 检查用户是否还有剩余配额
+func (s *quotaService) Check(ctx context.Context, userID int64) error {
+	if !s.policy.Enabled {
+		return nil
+	}
+
+	_, limits, err := s.resolveLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limits.RequestsPerDay <= 0 && limits.TokensPerMonth <= 0 {
+		return nil
+	}
+
+	now := s.now().UTC()
+	summary, err := s.usageRepo.Summary(ctx, userID, now.Format(usageDateLayout), monthStart(now).Format(usageDateLayout))
+	if err != nil {
+		return errors.NewDatabaseError("ai usage summary", err)
+	}
+
+	if limits.RequestsPerDay > 0 && summary.DayRequests >= limits.RequestsPerDay {
+		s.logger.Info("AI quota exceeded",
+			zap.Int64("user_id", userID),
+			zap.String("quota", "requests_per_day"),
+			zap.Int64("limit", limits.RequestsPerDay))
+		s.notifyExceeded(ctx, userID, "requests_per_day", limits.RequestsPerDay, dayReset(now))
+		return errors.NewQuotaExceededError("requests_per_day", limits.RequestsPerDay, dayReset(now))
+	}
+	if limits.TokensPerMonth > 0 && summary.MonthTokens >= limits.TokensPerMonth {
+		s.logger.Info("AI quota exceeded",
+			zap.Int64("user_id", userID),
+			zap.String("quota", "tokens_per_month"),
+			zap.Int64("limit", limits.TokensPerMonth))
+		s.notifyExceeded(ctx, userID, "tokens_per_month", limits.TokensPerMonth, monthReset(now))
+		return errors.NewQuotaExceededError("tokens_per_month", limits.TokensPerMonth, monthReset(now))
+	}
+
+	if quotaWarningReached(summary.DayRequests, limits.RequestsPerDay, s.policy.WarningPercent) {
+		s.notifyWarning(ctx, userID, "requests_per_day", limits.RequestsPerDay, summary.DayRequests, dayReset(now))
+	}
+	if quotaWarningReached(summary.MonthTokens, limits.TokensPerMonth, s.policy.WarningPercent) {
+		s.notifyWarning(ctx, userID, "tokens_per_month", limits.TokensPerMonth, summary.MonthTokens, monthReset(now))
+	}
+	return nil
+}
+
+// notifyExceeded 发布配额超额事件，同一配额在重置前只发布一次
+func (s *quotaService) notifyExceeded(ctx context.Context, userID int64, quota string, limit int64, resetAt time.Time) {
+	if !s.markNotified(userID, quota, webhook.EventQuotaExceeded, resetAt) {
+		return
+	}
+	s.events.Publish(ctx, webhook.EventQuotaExceeded, webhook.QuotaExceededData{
+		UserID:  userID,
+		Quota:   quota,
+		Limit:   limit,
+		ResetAt: resetAt,
+	})
+}
+
+// notifyWarning 发布配额预警事件，同一配额在重置前只发布一次
+func (s *quotaService) notifyWarning(ctx context.Context, userID int64, quota string, limit, used int64, resetAt time.Time) {
+	if !s.markNotified(userID, quota, webhook.EventQuotaWarning, resetAt) {
+		return
+	}
+	s.events.Publish(ctx, webhook.EventQuotaWarning, webhook.QuotaWarningData{
+		UserID:  userID,
+		Quota:   quota,
+		Limit:   limit,
+		Used:    used,
+		ResetAt: resetAt,
+	})
+}
+
+// markNotified 记录本周期已发送的通知，返回 false 表示无需发送
+func (s *quotaService) markNotified(userID int64, quota, eventType string, resetAt time.Time) bool {
+	if s.events == nil {
+		return false
+	}
+
+	key := strconv.FormatInt(userID, 10) + ":" + quota + ":" + eventType
+	s.notifiedMu.Lock()
+	defer s.notifiedMu.Unlock()
+	if s.notified[key].Equal(resetAt) {
+		return false
+	}
+	s.notified[key] = resetAt
+	// 清理已过重置时间的记录，避免长时间运行后无限增长
+	now := s.now()
+	for k, reset := range s.notified {
+		if !reset.After(now) {
+			delete(s.notified, k)
+		}
+	}
+	return true
+}
+
+// Record 记录一次请求及其消耗的令牌数
+func (s *quotaService) Record(ctx context.Context, userID int64, tokens int) error {
+	if !s.policy.Enabled {
+		return nil
+	}
+	if tokens < 0 {
+		tokens = 0
+	}
+	return s.usageRepo.Record(ctx, userID, s.now().UTC().Format(usageDateLayout), int64(tokens))
+}
+
+// GetStatus 获取用户当前配额使用情况
+func (s *quotaService) GetStatus(ctx context.Context, userID int64) (*dto.QuotaStatusResponse, error) {
+	role, limits, err := s.resolveLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	summary, err := s.usageRepo.Summary(ctx, userID, now.Format(usageDateLayout), monthStart(now).Format(usageDateLayout))
+	if err != nil {
+		return nil, errors.NewDatabaseError("ai usage summary", err)
+	}
+
+	if !s.policy.Enabled {
+		limits = QuotaLimits{}
+	}
+	return &dto.QuotaStatusResponse{
+		Enabled:        s.policy.Enabled,
+		Role:           role,
+		RequestsPerDay: quotaUsage(limits.RequestsPerDay, summary.DayRequests, dayReset(now)),
+		TokensPerMonth: quotaUsage(limits.TokensPerMonth, summary.MonthTokens, monthReset(now)),
+	}, nil
+}
+
+// Enabled 是否启用配额限制
+func (s *quotaService) Enabled() bool {
+	return s.policy.Enabled
+}
+
+// resolveLimits 确定用户的角色及适用的配额上限
+func (s *quotaService) resolveLimits(ctx context.Context, userID int64) (string, QuotaLimits, error) {
+	if userID <= 0 {
+		return QuotaRoleAnonymous, s.policy.limitsFor(QuotaRoleAnonymous, ""), nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", QuotaLimits{}, err
+	}
+	role := QuotaRoleUser
+	if user.IsAdmin {
+		role = QuotaRoleAdmin
+	}
+	return role, s.policy.limitsFor(role, user.Username), nil
+}
+
+// limitsFor 按用户名、角色、默认值的顺序查找配额上限
+func (p QuotaPolicy) limitsFor(role, username string) QuotaLimits {
+	if username != "" {
+		if limits, ok := p.Users[strings.ToLower(username)]; ok {
+			return limits
+		}
+	}
+	if limits, ok := p.Roles[role]; ok {
+		return limits
+	}
+	return p.Default
+}
+
+// quotaUsage 计算单项配额的剩余量
+func quotaUsage(limit, used int64, resetAt time.Time) dto.QuotaUsage {
+	usage := dto.QuotaUsage{Limit: limit, Used: used, Remaining: -1, ResetAt: resetAt}
+	if limit > 0 {
+		usage.Remaining = limit - used
+		if usage.Remaining < 0 {
+			usage.Remaining = 0
+		}
+	}
+	return usage
+}
+
+// quotaWarningReached 判断用量是否达到预警比例，未设置上限或预警比例时不预警
+func quotaWarningReached(used, limit, percent int64) bool {
+	if limit <= 0 || percent <= 0 || percent >= 100 {
+		return false
+	}
+	return used*100 >= limit*percent
+}
+
+// monthStart 返回当月第一天零点(UTC)
+func monthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// dayReset 返回每日配额的重置时间，即次日零点(UTC)
+func dayReset(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthReset 返回每月配额的重置时间，即下月第一天零点(UTC)
+func monthReset(now time.Time) time.Time {
+	return monthStart(now).AddDate(0, 1, 0)
+}
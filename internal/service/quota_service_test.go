@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuotaPolicyLimitsFor(t *testing.T) {
+	policy := QuotaPolicy{
+		Default: QuotaLimits{RequestsPerDay: 100, TokensPerMonth: 1000},
+		Roles:   map[string]QuotaLimits{QuotaRoleAdmin: {}},
+		Users:   map[string]QuotaLimits{"alice": {RequestsPerDay: 5}},
+	}
+
+	tests := []struct {
+		name     string
+		role     string
+		username string
+		expected QuotaLimits
+	}{
+		{name: "User override is case insensitive", role: QuotaRoleUser, username: "Alice", expected: QuotaLimits{RequestsPerDay: 5}},
+		{name: "Role override", role: QuotaRoleAdmin, username: "bob", expected: QuotaLimits{}},
+		{name: "Default", role: QuotaRoleUser, username: "bob", expected: QuotaLimits{RequestsPerDay: 100, TokensPerMonth: 1000}},
+		{name: "Anonymous falls back to default", role: QuotaRoleAnonymous, expected: QuotaLimits{RequestsPerDay: 100, TokensPerMonth: 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.limitsFor(tt.role, tt.username); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQuotaResetTimes(t *testing.T) {
+	now := time.Date(2024, time.December, 31, 18, 30, 0, 0, time.UTC)
+
+	if got, want := dayReset(now), time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("dayReset: expected %v, got %v", want, got)
+	}
+	if got, want := monthReset(now), time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("monthReset: expected %v, got %v", want, got)
+	}
+	if got, want := monthStart(now), time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("monthStart: expected %v, got %v", want, got)
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	if usage := quotaUsage(0, 42, time.Time{}); usage.Remaining != -1 {
+		t.Errorf("unlimited quota should report remaining -1, got %d", usage.Remaining)
+	}
+	if usage := quotaUsage(10, 4, time.Time{}); usage.Remaining != 6 {
+		t.Errorf("expected remaining 6, got %d", usage.Remaining)
+	}
+	if usage := quotaUsage(10, 15, time.Time{}); usage.Remaining != 0 {
+		t.Errorf("expected remaining 0 when over limit, got %d", usage.Remaining)
+	}
+}
+
+func TestQuotaWarningReached(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     int64
+		limit    int64
+		percent  int64
+		expected bool
+	}{
+		{name: "Below threshold", used: 79, limit: 100, percent: 80, expected: false},
+		{name: "At threshold", used: 80, limit: 100, percent: 80, expected: true},
+		{name: "Unlimited quota", used: 1000, limit: 0, percent: 80, expected: false},
+		{name: "Warning disabled", used: 99, limit: 100, percent: 0, expected: false},
+		{name: "Full usage is left to the exceeded event", used: 100, limit: 100, percent: 100, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quotaWarningReached(tt.used, tt.limit, tt.percent); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-springAi/internal/archive"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/scheduler"
+	"go-springAi/internal/types"
+	"go-springAi/internal/webhook"
+)
+
+// 内置定时任务类型
+const (
+	JobTypeAPIKeyValidation = "api_key_validation"
+	JobTypeLogCleanup       = "log_cleanup"
+	JobTypeStockReport      = "stock_report"
+	JobTypeModelSync        = "model_sync"
+)
+
+// 日志清理任务默认和最大保留天数
+const (
+	DefaultLogCleanupMaxAgeDays = 7
+	MaxLogCleanupMaxAgeDays     = 3650
+)
+
+// reportSymbolPattern 报告任务允许的股票代码，如 AAPL、BRK.B、^GSPC、EURUSD=X
+var reportSymbolPattern = regexp.MustCompile(`^[A-Z0-9.^=_-]{1,20}$`)
+
+// ModelSyncer 将提供商当前提供的模型与本地配置同步
+type ModelSyncer interface {
+	SyncModels(ctx context.Context, disableMissing bool) ([]types.ModelSyncResult, error)
+}
+
+// decodeJobParams 解析任务参数，拒绝未知字段以便发现拼写错误
+func decodeJobParams(params json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
+
+// NewAPIKeyValidationJobType 验证所有已保存的API密钥，无参数
+func NewAPIKeyValidationJobType(job *APIKeyValidationJob) scheduler.JobType {
+	return scheduler.JobType{
+		Name:        JobTypeAPIKeyValidation,
+		Description: "Validate all stored API keys against their providers",
+		Validate: func(params json.RawMessage) error {
+			return decodeJobParams(params, &struct{}{})
+		},
+		Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+			summary, err := job.RunOnce(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("checked %d keys, %d invalid, %d skipped", summary.Checked, summary.Invalid, summary.Skipped), nil
+		},
+	}
+}
+
+// logCleanupParams 日志清理任务参数
+type logCleanupParams struct {
+	MaxAgeDays *int `json:"max_age_days"` // 删除开始时间早于该天数的已结束执行日志，默认 7
+}
+
+// maxAge 返回保留时长
+func (p *logCleanupParams) maxAge() time.Duration {
+	days := DefaultLogCleanupMaxAgeDays
+	if p.MaxAgeDays != nil {
+		days = *p.MaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// NewLogCleanupJobType 清理内存中的执行日志。archiveService 不为 nil 时先归档超过归档保留期的日志，
+// 再删除超过 max_age_days 的其余日志
+func NewLogCleanupJobType(mcpService MCPService, archiveService ExecutionLogArchiveService) scheduler.JobType {
+	return scheduler.JobType{
+		Name:        JobTypeLogCleanup,
+		Description: "Archive (when configured) and delete finished MCP execution logs older than max_age_days",
+		Validate: func(params json.RawMessage) error {
+			var p logCleanupParams
+			if err := decodeJobParams(params, &p); err != nil {
+				return err
+			}
+			if p.MaxAgeDays != nil && (*p.MaxAgeDays < 1 || *p.MaxAgeDays > MaxLogCleanupMaxAgeDays) {
+				return fmt.Errorf("max_age_days must be between 1 and %d", MaxLogCleanupMaxAgeDays)
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+			var p logCleanupParams
+			if err := decodeJobParams(params, &p); err != nil {
+				return "", err
+			}
+
+			archived := 0
+			if archiveService != nil {
+				result, err := archiveService.Archive(ctx)
+				if err != nil {
+					return "", fmt.Errorf("archive execution logs: %w", err)
+				}
+				if result != nil {
+					archived = result.Count
+				}
+			}
+
+			logs := mcpService.ExpiredExecutionLogs(time.Now().Add(-p.maxAge()))
+			ids := make([]string, len(logs))
+			for i, log := range logs {
+				ids[i] = log.ID
+			}
+			mcpService.DeleteExecutionLogs(ids)
+			return fmt.Sprintf("archived %d, deleted %d execution logs", archived, len(ids)), nil
+		},
+	}
+}
+
+// stockReportParams 定时报告任务参数
+type stockReportParams struct {
+	Symbol    string `json:"symbol"`
+	Period    string `json:"period"`    // 默认 3mo
+	Benchmark string `json:"benchmark"` // 默认 ^GSPC
+	Language  string `json:"language"`  // 默认 en
+}
+
+// parseStockReportParams 解析并规范化报告参数
+func parseStockReportParams(params json.RawMessage) (*stockReportParams, error) {
+	var p stockReportParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return nil, err
+	}
+	p.Symbol = strings.ToUpper(strings.TrimSpace(p.Symbol))
+	if !reportSymbolPattern.MatchString(p.Symbol) {
+		return nil, fmt.Errorf("symbol is required and may only contain letters, digits and . ^ = _ -")
+	}
+	return &p, nil
+}
+
+// NewStockReportJobType 生成股票分析PDF报告并保存到 store，路径为 reports/<代码>/<UTC时间>.pdf，
+// 保存后发布 report.generated 事件
+func NewStockReportJobType(reportService *StockReportService, store archive.Store, events webhook.Publisher) scheduler.JobType {
+	return scheduler.JobType{
+		Name:        JobTypeStockReport,
+		Description: "Generate a stock analysis PDF report and save it to report storage",
+		Validate: func(params json.RawMessage) error {
+			_, err := parseStockReportParams(params)
+			return err
+		},
+		Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+			p, err := parseStockReportParams(params)
+			if err != nil {
+				return "", err
+			}
+
+			data, err := reportService.GenerateReport(ctx, &dto.StockAnalysisRequest{
+				Symbol:    p.Symbol,
+				Period:    p.Period,
+				Benchmark: p.Benchmark,
+				Language:  p.Language,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			name := fmt.Sprintf("reports/%s/%s.pdf", p.Symbol, time.Now().UTC().Format("20060102T150405Z"))
+			if err := store.Put(ctx, name, data); err != nil {
+				return "", fmt.Errorf("save report: %w", err)
+			}
+			if events != nil {
+				events.Publish(ctx, webhook.EventReportGenerated, webhook.ReportGeneratedData{
+					Symbol: p.Symbol,
+					Name:   name,
+					Size:   len(data),
+				})
+			}
+			return fmt.Sprintf("saved %s (%d bytes)", name, len(data)), nil
+		},
+	}
+}
+
+// modelSyncParams 模型同步任务参数
+type modelSyncParams struct {
+	DisableMissing bool `json:"disable_missing"` // 禁用提供商已不再提供的模型
+}
+
+// ModelsChangedNotifier 接收模型同步带来的变更
+type ModelsChangedNotifier interface {
+	NotifyModelsChanged(changes []types.ModelSyncResult)
+}
+
+// NewModelSyncJobType 将提供商当前提供的模型同步到本地配置：新模型以禁用状态添加，
+// 已下线的模型被标记，可选禁用；有变更时通知 notifier，notifier 可为空
+func NewModelSyncJobType(syncer ModelSyncer, notifier ModelsChangedNotifier) scheduler.JobType {
+	return scheduler.JobType{
+		Name:        JobTypeModelSync,
+		Description: "Sync provider model lists into the configured models: add new models disabled, flag models that are gone and optionally disable them",
+		Validate: func(params json.RawMessage) error {
+			return decodeJobParams(params, &modelSyncParams{})
+		},
+		Run: func(ctx context.Context, params json.RawMessage) (string, error) {
+			var p modelSyncParams
+			if err := decodeJobParams(params, &p); err != nil {
+				return "", err
+			}
+
+			results, err := syncer.SyncModels(ctx, p.DisableMissing)
+			parts := make([]string, 0, len(results))
+			var changes []types.ModelSyncResult
+			for _, r := range results {
+				if r.Changed() {
+					changes = append(changes, r)
+				}
+				if r.Error != "" {
+					parts = append(parts, fmt.Sprintf("%s: failed", r.Provider))
+					continue
+				}
+				part := fmt.Sprintf("%s: %d new, %d missing", r.Provider, len(r.New), len(r.Missing))
+				if len(r.Missing) > 0 {
+					part += " (" + strings.Join(r.Missing, ", ") + ")"
+				}
+				if len(r.Added) > 0 {
+					part += fmt.Sprintf(", %d added disabled", len(r.Added))
+				}
+				if len(r.Restored) > 0 {
+					part += fmt.Sprintf(", %d back", len(r.Restored))
+				}
+				if len(r.Disabled) > 0 {
+					part += fmt.Sprintf(", %d disabled", len(r.Disabled))
+				}
+				parts = append(parts, part)
+			}
+			if len(changes) > 0 && notifier != nil {
+				notifier.NotifyModelsChanged(changes)
+			}
+			if len(parts) == 0 {
+				parts = append(parts, "no provider supports model listing")
+			}
+			return strings.Join(parts, "; "), err
+		},
+	}
+}
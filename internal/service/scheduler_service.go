@@ -0,0 +1,314 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/scheduled_jobs"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/scheduler"
+
+	"go.uber.org/zap"
+)
+
+// JobScheduler 管理接口用到的调度器方法
+type JobScheduler interface {
+	Enabled() bool
+	Location() *time.Location
+	Types() []scheduler.JobType
+	Validate(jobType, spec string, params json.RawMessage) error
+	NextRun(spec string, after time.Time) (time.Time, error)
+	RunNow(job *scheduled_jobs.ScheduledJob) error
+	Running(id int64) bool
+	Wake()
+}
+
+// SchedulerService 定时任务管理服务接口
+type SchedulerService interface {
+	// List 获取所有任务及可用的任务类型
+	List(ctx context.Context) (*dto.ScheduledJobListResponse, error)
+	// Create 创建任务
+	Create(ctx context.Context, req *dto.CreateScheduledJobRequest) (*dto.ScheduledJobResponse, error)
+	// Update 更新任务的名称、计划、参数或启用状态
+	Update(ctx context.Context, id int64, req *dto.UpdateScheduledJobRequest) (*dto.ScheduledJobResponse, error)
+	// SetEnabled 暂停或恢复任务，恢复时从当前时间重新计算下一次执行时间
+	SetEnabled(ctx context.Context, id int64, enabled bool) (*dto.ScheduledJobResponse, error)
+	// Delete 删除任务
+	Delete(ctx context.Context, id int64) error
+	// RunNow 立即在后台执行一次任务
+	RunNow(ctx context.Context, id int64) (*dto.ScheduledJobResponse, error)
+}
+
+// schedulerService 定时任务管理服务实现
+type schedulerService struct {
+	repo      repository.ScheduledJobRepository
+	scheduler JobScheduler
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewSchedulerService 创建定时任务管理服务
+func NewSchedulerService(repoManager repository.RepositoryManager, jobScheduler JobScheduler, logger *zap.Logger) SchedulerService {
+	return &schedulerService{
+		repo:      repoManager.ScheduledJob(),
+		scheduler: jobScheduler,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// List 获取所有任务及可用的任务类型
+func (s *schedulerService) List(ctx context.Context) (*dto.ScheduledJobListResponse, error) {
+	jobs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list scheduled jobs", err)
+	}
+
+	jobTypes := s.scheduler.Types()
+	result := &dto.ScheduledJobListResponse{
+		Jobs:             make([]dto.ScheduledJobResponse, 0, len(jobs)),
+		JobTypes:         make([]dto.ScheduledJobTypeResponse, 0, len(jobTypes)),
+		SchedulerEnabled: s.scheduler.Enabled(),
+		Timezone:         s.scheduler.Location().String(),
+	}
+	for i := range jobs {
+		result.Jobs = append(result.Jobs, s.toResponse(&jobs[i]))
+	}
+	for _, t := range jobTypes {
+		result.JobTypes = append(result.JobTypes, dto.ScheduledJobTypeResponse{Name: t.Name, Description: t.Description})
+	}
+	return result, nil
+}
+
+// Create 创建任务
+func (s *schedulerService) Create(ctx context.Context, req *dto.CreateScheduledJobRequest) (*dto.ScheduledJobResponse, error) {
+	name, err := s.checkName(ctx, req.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	schedule := strings.TrimSpace(req.Schedule)
+	params, err := s.validate(req.JobType, schedule, req.Params)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.nextRun(schedule)
+	if err != nil {
+		return nil, err
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	job, err := s.repo.Create(ctx, scheduled_jobs.CreateScheduledJobParams{
+		Name:      name,
+		JobType:   req.JobType,
+		Schedule:  schedule,
+		Params:    params,
+		Enabled:   enabled,
+		NextRunAt: next,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create scheduled job", err)
+	}
+	s.scheduler.Wake()
+
+	s.logger.Info("Scheduled job created",
+		zap.Int64("job_id", job.ID),
+		zap.String("name", job.Name),
+		zap.String("job_type", job.JobType),
+		zap.String("schedule", job.Schedule))
+
+	resp := s.toResponse(job)
+	return &resp, nil
+}
+
+// Update 更新任务，计划变更或由暂停变为启用时从当前时间重新计算下一次执行时间
+func (s *schedulerService) Update(ctx context.Context, id int64, req *dto.UpdateScheduledJobRequest) (*dto.ScheduledJobResponse, error) {
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := scheduled_jobs.UpdateScheduledJobParams{
+		ID:        job.ID,
+		Name:      job.Name,
+		Schedule:  job.Schedule,
+		Params:    job.Params,
+		Enabled:   job.Enabled,
+		NextRunAt: job.NextRunAt,
+	}
+	if req.Name != nil {
+		if params.Name, err = s.checkName(ctx, *req.Name, job.ID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Schedule != nil {
+		params.Schedule = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Params != nil {
+		params.Params = string(req.Params)
+	}
+	if req.Enabled != nil {
+		params.Enabled = *req.Enabled
+	}
+	if params.Params, err = s.validate(job.JobType, params.Schedule, json.RawMessage(params.Params)); err != nil {
+		return nil, err
+	}
+	if params.Schedule != job.Schedule || (params.Enabled && !job.Enabled) {
+		if params.NextRunAt, err = s.nextRun(params.Schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.save(ctx, params)
+}
+
+// SetEnabled 暂停或恢复任务
+func (s *schedulerService) SetEnabled(ctx context.Context, id int64, enabled bool) (*dto.ScheduledJobResponse, error) {
+	return s.Update(ctx, id, &dto.UpdateScheduledJobRequest{Enabled: &enabled})
+}
+
+// Delete 删除任务，执行中的任务会继续执行完
+func (s *schedulerService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete scheduled job", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Scheduled job")
+	}
+
+	s.logger.Info("Scheduled job deleted", zap.Int64("job_id", id))
+	return nil
+}
+
+// RunNow 立即在后台执行一次任务，暂停的任务也可以执行
+func (s *schedulerService) RunNow(ctx context.Context, id int64) (*dto.ScheduledJobResponse, error) {
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduler.RunNow(job); err != nil {
+		if stderrors.Is(err, scheduler.ErrRunning) {
+			return nil, errors.NewConflictError("Scheduled job is already running")
+		}
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	s.logger.Info("Scheduled job triggered", zap.Int64("job_id", job.ID), zap.String("name", job.Name))
+	resp := s.toResponse(job)
+	return &resp, nil
+}
+
+// save 保存更新后的任务
+func (s *schedulerService) save(ctx context.Context, params scheduled_jobs.UpdateScheduledJobParams) (*dto.ScheduledJobResponse, error) {
+	updated, err := s.repo.Update(ctx, params)
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewDatabaseError("update scheduled job", err)
+	}
+	s.scheduler.Wake()
+
+	s.logger.Info("Scheduled job updated",
+		zap.Int64("job_id", updated.ID),
+		zap.String("schedule", updated.Schedule),
+		zap.Bool("enabled", updated.Enabled))
+
+	resp := s.toResponse(updated)
+	return &resp, nil
+}
+
+// checkName 校验任务名称未被其他任务使用，返回去除首尾空白后的名称
+func (s *schedulerService) checkName(ctx context.Context, name string, id int64) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.NewValidationError("Scheduled job name is required")
+	}
+	existing, err := s.repo.GetByName(ctx, name)
+	if err == nil {
+		if existing.ID != id {
+			return "", errors.NewConflictError(fmt.Sprintf("Scheduled job %s already exists", name))
+		}
+		return name, nil
+	}
+	if appErr, ok := errors.IsAppError(err); !ok || appErr.Code != errors.ErrCodeNotFound {
+		return "", errors.NewDatabaseError("get scheduled job", err)
+	}
+	return name, nil
+}
+
+// validate 校验任务类型、计划和参数，返回压缩后的参数 JSON，参数为空时使用 {}
+func (s *schedulerService) validate(jobType, schedule string, params json.RawMessage) (string, error) {
+	if len(bytes.TrimSpace(params)) == 0 || string(bytes.TrimSpace(params)) == "null" {
+		params = json.RawMessage("{}")
+	}
+	if err := s.scheduler.Validate(jobType, schedule, params); err != nil {
+		appErr := errors.NewValidationError(err.Error())
+		if stderrors.Is(err, scheduler.ErrUnknownType) {
+			names := make([]string, 0)
+			for _, t := range s.scheduler.Types() {
+				names = append(names, t.Name)
+			}
+			appErr = appErr.WithDetails(fmt.Sprintf("available job types: %s", strings.Join(names, ", ")))
+		}
+		return "", appErr
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return "", errors.NewValidationError("params must be a JSON object")
+	}
+	return buf.String(), nil
+}
+
+// nextRun 计算从当前时间起的下一次执行时间
+func (s *schedulerService) nextRun(schedule string) (time.Time, error) {
+	next, err := s.scheduler.NextRun(schedule, s.now())
+	if err != nil {
+		return time.Time{}, errors.NewValidationError(err.Error())
+	}
+	return next, nil
+}
+
+// toResponse 转换任务信息
+func (s *schedulerService) toResponse(job *scheduled_jobs.ScheduledJob) dto.ScheduledJobResponse {
+	resp := dto.ScheduledJobResponse{
+		ID:             job.ID,
+		Name:           job.Name,
+		JobType:        job.JobType,
+		Schedule:       job.Schedule,
+		Params:         json.RawMessage(job.Params),
+		Enabled:        job.Enabled,
+		Running:        s.scheduler.Running(job.ID),
+		LastStatus:     job.LastStatus,
+		LastResult:     job.LastResult,
+		LastError:      job.LastError,
+		LastDurationMs: job.LastDurationMs,
+		RunCount:       job.RunCount,
+		CreatedAt:      job.CreatedAt.Time,
+		UpdatedAt:      job.UpdatedAt.Time,
+	}
+	if !json.Valid([]byte(job.Params)) {
+		resp.Params = json.RawMessage("{}")
+	}
+	if job.Enabled {
+		next := job.NextRunAt
+		resp.NextRunAt = &next
+	}
+	if job.LastRunAt.Valid {
+		last := job.LastRunAt.Time
+		resp.LastRunAt = &last
+	}
+	return resp
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/repository"
+)
+
+// NewAdminChecker 创建 shell 工具的管理员校验函数，按上下文中的用户ID查询用户是否为管理员
+func NewAdminChecker(users repository.UserRepository) tools.AdminCheckFunc {
+	return func(ctx context.Context) (string, error) {
+		userID := getUserIDFromContext(ctx)
+		id, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil {
+			return userID, errors.New("authentication required")
+		}
+		user, err := users.GetByID(ctx, id)
+		if err != nil {
+			return userID, err
+		}
+		if !user.IsAdmin {
+			return userID, errors.New("admin privileges required")
+		}
+		return userID, nil
+	}
+}
@@ -10,22 +10,36 @@ import (
 	"time"
 
 	"go-springAi/internal/dto"
+	"go-springAi/internal/i18n"
 	"go-springAi/internal/mcp"
+	"go-springAi/internal/mcp/tools"
 
 	"go.uber.org/zap"
 )
 
+// defaultBenchmarkSymbol 默认基准指数（标普500）
+const defaultBenchmarkSymbol = "^GSPC"
+
+// defaultBaseCurrency 多股票对比的默认基准货币
+const defaultBaseCurrency = "USD"
+
 // StockAnalysisService 股票分析服务
 type StockAnalysisService struct {
-	mcpClient mcp.InternalMCPClient
-	logger    *zap.Logger
+	mcpClient    mcp.InternalMCPClient
+	fxService    *FXService
+	i18nManager  *i18n.Manager
+	riskFreeRate float64
+	logger       *zap.Logger
 }
 
-// NewStockAnalysisService 创建股票分析服务
-func NewStockAnalysisService(mcpClient mcp.InternalMCPClient, logger *zap.Logger) *StockAnalysisService {
+// NewStockAnalysisService 创建股票分析服务，riskFreeRate 为计算夏普/索提诺比率使用的年化无风险利率
+func NewStockAnalysisService(mcpClient mcp.InternalMCPClient, fxService *FXService, i18nManager *i18n.Manager, riskFreeRate float64, logger *zap.Logger) *StockAnalysisService {
 	service := &StockAnalysisService{
-		mcpClient: mcpClient,
-		logger:    logger,
+		mcpClient:    mcpClient,
+		fxService:    fxService,
+		i18nManager:  i18nManager,
+		riskFreeRate: riskFreeRate,
+		logger:       logger,
 	}
 	
 	// 自动初始化MCP客户端
@@ -56,6 +70,7 @@ func NewStockAnalysisService(mcpClient mcp.InternalMCPClient, logger *zap.Logger
 // AnalyzeStock 分析单只股票
 func (s *StockAnalysisService) AnalyzeStock(ctx context.Context, req *dto.StockAnalysisRequest) (*dto.StockAnalysisResponse, error) {
 	s.logger.Info("开始分析股票", zap.String("symbol", req.Symbol), zap.String("analysis_type", req.AnalysisType))
+	lang := s.resolveLanguage(ctx, req.Language)
 
 	// 1. 获取股票基本信息
 	quote, err := s.getStockQuote(ctx, req.Symbol)
@@ -96,24 +111,37 @@ func (s *StockAnalysisService) AnalyzeStock(ctx context.Context, req *dto.StockA
 
 	if analysisType == "technical" || analysisType == "all" {
 		if history != nil {
-			response.TechnicalAnalysis = s.performTechnicalAnalysis(history)
+			response.TechnicalAnalysis = s.performTechnicalAnalysis(history, lang)
 		}
 	}
 
 	if analysisType == "fundamental" || analysisType == "all" {
 		if companyInfo != nil {
-			response.FundamentalAnalysis = s.performFundamentalAnalysis(companyInfo, quote)
+			response.FundamentalAnalysis = s.performFundamentalAnalysis(companyInfo, quote, lang)
 		}
 	}
 
 	if analysisType == "risk" || analysisType == "all" {
 		if history != nil {
-			response.RiskAssessment = s.performRiskAssessment(history)
+			riskFreeRate := s.riskFreeRate
+			if req.RiskFreeRate != nil {
+				riskFreeRate = *req.RiskFreeRate
+			}
+			response.RiskAssessment = s.performRiskAssessment(history, riskFreeRate, lang)
+		}
+	}
+
+	if analysisType == "benchmark" || analysisType == "all" {
+		if history != nil {
+			response.BenchmarkComparison = s.compareWithBenchmark(ctx, req, period, history)
+			if response.BenchmarkComparison != nil && response.RiskAssessment != nil {
+				response.RiskAssessment.Beta = response.BenchmarkComparison.Beta
+			}
 		}
 	}
 
 	if analysisType == "all" {
-		response.InvestmentAdvice = s.generateInvestmentAdvice(response)
+		response.InvestmentAdvice = s.generateInvestmentAdvice(response, lang)
 	}
 
 	return response, nil
@@ -122,6 +150,7 @@ func (s *StockAnalysisService) AnalyzeStock(ctx context.Context, req *dto.StockA
 // CompareStocks 对比多只股票
 func (s *StockAnalysisService) CompareStocks(ctx context.Context, req *dto.StockCompareRequest) (*dto.StockCompareResponse, error) {
 	s.logger.Info("开始对比股票", zap.Strings("symbols", req.Symbols))
+	lang := s.resolveLanguage(ctx, req.Language)
 
 	var individual []dto.StockAnalysisResponse
 	
@@ -131,6 +160,8 @@ func (s *StockAnalysisService) CompareStocks(ctx context.Context, req *dto.Stock
 			Symbol:       symbol,
 			Period:       req.Period,
 			AnalysisType: "all",
+			Benchmark:    req.Benchmark,
+			Language:     lang,
 		}
 		
 		analysis, err := s.AnalyzeStock(ctx, analysisReq)
@@ -146,22 +177,68 @@ func (s *StockAnalysisService) CompareStocks(ctx context.Context, req *dto.Stock
 		return nil, fmt.Errorf("没有成功分析任何股票")
 	}
 
+	// 将各股票货币统一换算为基准货币
+	baseCurrency := strings.ToUpper(req.BaseCurrency)
+	if baseCurrency == "" {
+		baseCurrency = defaultBaseCurrency
+	}
+	rates, warnings := s.resolveFXRates(ctx, individual, baseCurrency, lang)
+
 	// 执行对比分析
-	comparison := s.performStockComparison(individual)
-	recommendation := s.generateComparisonRecommendation(individual, comparison)
+	comparison := s.performStockComparison(individual, baseCurrency, rates)
+	recommendation := s.generateComparisonRecommendation(individual, comparison, lang)
 
 	return &dto.StockCompareResponse{
 		Symbols:        req.Symbols,
 		Comparison:     comparison,
 		Individual:     individual,
 		Recommendation: recommendation,
+		Warnings:       warnings,
 	}, nil
 }
 
+// resolveFXRates 获取每只股票原始货币到基准货币的汇率
+func (s *StockAnalysisService) resolveFXRates(ctx context.Context, stocks []dto.StockAnalysisResponse, baseCurrency, lang string) (map[string]float64, []string) {
+	rates := make(map[string]float64, len(stocks))
+	var warnings []string
+
+	for _, stock := range stocks {
+		rate, err := s.fxService.GetRate(ctx, stock.Currency, baseCurrency)
+		if err != nil {
+			s.logger.Warn("获取汇率失败", zap.String("symbol", stock.Symbol), zap.String("currency", stock.Currency), zap.Error(err))
+			warnings = append(warnings, s.t(lang, "stock.compare.fx_excluded", map[string]interface{}{
+				"Symbol":       stock.Symbol,
+				"Currency":     stock.Currency,
+				"BaseCurrency": baseCurrency,
+			}))
+			continue
+		}
+		rates[stock.Symbol] = rate
+	}
+
+	return rates, warnings
+}
+
+// resolveLanguage 确定分析结果的输出语言
+func (s *StockAnalysisService) resolveLanguage(ctx context.Context, lang string) string {
+	if s.i18nManager == nil {
+		return lang
+	}
+	return s.i18nManager.ResolveLanguage(ctx, lang)
+}
+
+// t 翻译分析结果中的文本，未配置国际化管理器时返回消息ID
+func (s *StockAnalysisService) t(lang, messageID string, templateData map[string]interface{}) string {
+	if s.i18nManager == nil {
+		return messageID
+	}
+	return s.i18nManager.T(lang, messageID, templateData)
+}
+
 // getStockQuote 获取股票报价
 func (s *StockAnalysisService) getStockQuote(ctx context.Context, symbol string) (*dto.MCPExecuteResponse, error) {
 	req := &dto.MCPExecuteRequest{
-		Name: "yahoo_finance",
+		Name: tools.YahooFinanceToolName,
 		Arguments: map[string]interface{}{
 			"action": "quote",
 			"symbol": symbol,
@@ -174,7 +251,7 @@ func (s *StockAnalysisService) getStockQuote(ctx context.Context, symbol string)
 // getStockHistory 获取股票历史数据
 func (s *StockAnalysisService) getStockHistory(ctx context.Context, symbol, period, interval string) (*dto.MCPExecuteResponse, error) {
 	req := &dto.MCPExecuteRequest{
-		Name: "yahoo_finance",
+		Name: tools.YahooFinanceToolName,
 		Arguments: map[string]interface{}{
 			"action":   "history",
 			"symbol":   symbol,
@@ -189,7 +266,7 @@ func (s *StockAnalysisService) getStockHistory(ctx context.Context, symbol, peri
 // getStockInfo 获取股票公司信息
 func (s *StockAnalysisService) getStockInfo(ctx context.Context, symbol string) (*dto.MCPExecuteResponse, error) {
 	req := &dto.MCPExecuteRequest{
-		Name: "yahoo_finance",
+		Name: tools.YahooFinanceToolName,
 		Arguments: map[string]interface{}{
 			"action": "info",
 			"symbol": symbol,
@@ -199,6 +276,174 @@ func (s *StockAnalysisService) getStockInfo(ctx context.Context, symbol string)
 	return s.mcpClient.ExecuteTool(ctx, req)
 }
 
+// compareWithBenchmark 获取基准指数历史数据并计算相对表现
+func (s *StockAnalysisService) compareWithBenchmark(ctx context.Context, req *dto.StockAnalysisRequest, period string, history *dto.MCPExecuteResponse) *dto.BenchmarkComparison {
+	benchmark := req.Benchmark
+	if benchmark == "" {
+		benchmark = defaultBenchmarkSymbol
+	}
+
+	stockSeries := s.extractPriceSeries(history)
+	if stockSeries == nil {
+		return nil
+	}
+
+	benchmarkHistory, err := s.getStockHistory(ctx, benchmark, period, "1d")
+	if err != nil {
+		s.logger.Warn("获取基准指数历史数据失败", zap.String("benchmark", benchmark), zap.Error(err))
+		return nil
+	}
+
+	benchmarkSeries := s.extractPriceSeries(benchmarkHistory)
+	if benchmarkSeries == nil {
+		s.logger.Warn("基准指数历史数据为空", zap.String("benchmark", benchmark))
+		return nil
+	}
+
+	comparison := s.calculateBenchmarkComparison(stockSeries, benchmarkSeries)
+	if comparison == nil {
+		return nil
+	}
+	comparison.Benchmark = benchmark
+	comparison.Period = period
+
+	return comparison
+}
+
+// historicalCloses 获取历史收盘价，优先使用结构化价格序列，否则回退到文本解析
+func (s *StockAnalysisService) historicalCloses(history *dto.MCPExecuteResponse) []float64 {
+	if series := s.extractPriceSeries(history); series != nil {
+		return series.Closes
+	}
+	if history == nil || len(history.Content) == 0 {
+		return nil
+	}
+	return s.parseHistoricalPrices(history.Content[0].Text)
+}
+
+// extractPriceSeries 从历史数据响应中提取结构化价格序列
+func (s *StockAnalysisService) extractPriceSeries(history *dto.MCPExecuteResponse) *dto.PriceSeries {
+	if history == nil || history.IsError {
+		return nil
+	}
+
+	for _, content := range history.Content {
+		if series, ok := content.Data.(*dto.PriceSeries); ok && series != nil && len(series.Closes) > 0 {
+			return series
+		}
+	}
+	return nil
+}
+
+// calculateBenchmarkComparison 按交易日对齐两条价格序列并计算相对表现
+func (s *StockAnalysisService) calculateBenchmarkComparison(stock, benchmark *dto.PriceSeries) *dto.BenchmarkComparison {
+	if stock == nil || benchmark == nil {
+		return nil
+	}
+
+	// 按日期对齐，只保留两者都有收盘价的交易日
+	benchmarkByDay := make(map[string]float64, len(benchmark.Timestamps))
+	for i, ts := range benchmark.Timestamps {
+		if i < len(benchmark.Closes) {
+			benchmarkByDay[time.Unix(ts, 0).UTC().Format("2006-01-02")] = benchmark.Closes[i]
+		}
+	}
+
+	var stockPrices, benchmarkPrices []float64
+	for i, ts := range stock.Timestamps {
+		if i >= len(stock.Closes) {
+			break
+		}
+		if benchClose, ok := benchmarkByDay[time.Unix(ts, 0).UTC().Format("2006-01-02")]; ok {
+			stockPrices = append(stockPrices, stock.Closes[i])
+			benchmarkPrices = append(benchmarkPrices, benchClose)
+		}
+	}
+
+	if len(stockPrices) < 2 {
+		return nil
+	}
+
+	stockReturn := stockPrices[len(stockPrices)-1]/stockPrices[0] - 1
+	benchmarkReturn := benchmarkPrices[len(benchmarkPrices)-1]/benchmarkPrices[0] - 1
+
+	stockReturns := s.calculateReturns(stockPrices)
+	benchmarkReturns := s.calculateReturns(benchmarkPrices)
+	beta := s.calculateBeta(stockReturns, benchmarkReturns)
+	upCapture, downCapture := s.calculateCaptureRatios(stockReturns, benchmarkReturns)
+
+	return &dto.BenchmarkComparison{
+		DataPoints:      len(stockPrices),
+		StockReturn:     stockReturn,
+		BenchmarkReturn: benchmarkReturn,
+		ExcessReturn:    stockReturn - benchmarkReturn,
+		Alpha:           stockReturn - beta*benchmarkReturn,
+		Beta:            beta,
+		UpCapture:       upCapture,
+		DownCapture:     downCapture,
+		Outperformed:    stockReturn > benchmarkReturn,
+	}
+}
+
+// calculateBeta 计算相对基准的Beta系数
+func (s *StockAnalysisService) calculateBeta(returns, benchmarkReturns []float64) float64 {
+	n := len(returns)
+	if len(benchmarkReturns) < n {
+		n = len(benchmarkReturns)
+	}
+	if n < 2 {
+		return 0
+	}
+
+	meanStock, meanBench := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		meanStock += returns[i]
+		meanBench += benchmarkReturns[i]
+	}
+	meanStock /= float64(n)
+	meanBench /= float64(n)
+
+	covariance, variance := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		covariance += (returns[i] - meanStock) * (benchmarkReturns[i] - meanBench)
+		variance += math.Pow(benchmarkReturns[i]-meanBench, 2)
+	}
+
+	if variance == 0 {
+		return 0
+	}
+	return covariance / variance
+}
+
+// calculateCaptureRatios 计算上行/下行捕获率（百分比）
+func (s *StockAnalysisService) calculateCaptureRatios(returns, benchmarkReturns []float64) (float64, float64) {
+	var upStock, upBench, downStock, downBench float64
+	var upDays, downDays int
+
+	for i := 0; i < len(returns) && i < len(benchmarkReturns); i++ {
+		switch {
+		case benchmarkReturns[i] > 0:
+			upStock += returns[i]
+			upBench += benchmarkReturns[i]
+			upDays++
+		case benchmarkReturns[i] < 0:
+			downStock += returns[i]
+			downBench += benchmarkReturns[i]
+			downDays++
+		}
+	}
+
+	upCapture, downCapture := 0.0, 0.0
+	if upDays > 0 && upBench != 0 {
+		upCapture = upStock / upBench * 100
+	}
+	if downDays > 0 && downBench != 0 {
+		downCapture = downStock / downBench * 100
+	}
+
+	return upCapture, downCapture
+}
+
 // extractCompanyName 从报价数据中提取公司名称
 func (s *StockAnalysisService) extractCompanyName(quote *dto.MCPExecuteResponse) string {
 	if quote == nil || len(quote.Content) == 0 {
@@ -218,8 +463,25 @@ func (s *StockAnalysisService) extractCompanyName(quote *dto.MCPExecuteResponse)
 	return ""
 }
 
+// extractQuoteData 从报价响应中提取结构化报价
+func (s *StockAnalysisService) extractQuoteData(quote *dto.MCPExecuteResponse) *dto.StockQuote {
+	if quote == nil || quote.IsError {
+		return nil
+	}
+
+	for _, content := range quote.Content {
+		if data, ok := content.Data.(*dto.StockQuote); ok && data != nil {
+			return data
+		}
+	}
+	return nil
+}
+
 // extractCurrentPrice 从报价数据中提取当前价格
 func (s *StockAnalysisService) extractCurrentPrice(quote *dto.MCPExecuteResponse) float64 {
+	if data := s.extractQuoteData(quote); data != nil {
+		return data.Price
+	}
 	if quote == nil || len(quote.Content) == 0 {
 		return 0
 	}
@@ -244,6 +506,9 @@ func (s *StockAnalysisService) extractCurrentPrice(quote *dto.MCPExecuteResponse
 
 // extractCurrency 从报价数据中提取货币
 func (s *StockAnalysisService) extractCurrency(quote *dto.MCPExecuteResponse) string {
+	if data := s.extractQuoteData(quote); data != nil && data.Currency != "" {
+		return data.Currency
+	}
 	if quote == nil || len(quote.Content) == 0 {
 		return "USD"
 	}
@@ -264,13 +529,13 @@ func (s *StockAnalysisService) extractCurrency(quote *dto.MCPExecuteResponse) st
 }
 
 // performTechnicalAnalysis 执行技术分析
-func (s *StockAnalysisService) performTechnicalAnalysis(history *dto.MCPExecuteResponse) *dto.TechnicalAnalysis {
+func (s *StockAnalysisService) performTechnicalAnalysis(history *dto.MCPExecuteResponse, lang string) *dto.TechnicalAnalysis {
 	if history == nil || len(history.Content) == 0 {
 		return nil
 	}
 
 	// 解析历史数据
-	prices := s.parseHistoricalPrices(history.Content[0].Text)
+	prices := s.historicalCloses(history)
 	if len(prices) < 20 {
 		return nil
 	}
@@ -284,13 +549,13 @@ func (s *StockAnalysisService) performTechnicalAnalysis(history *dto.MCPExecuteR
 	ma200 := s.calculateMA(prices, 200)
 
 	// 确定趋势
-	trend := s.determineTrend(prices, ma20)
+	trend := s.t(lang, "stock.trend."+s.determineTrend(prices, ma20), nil)
 	
 	// 计算支撑位和阻力位
 	support, resistance := s.calculateSupportResistance(prices)
 
 	// 生成技术信号
-	signals := s.generateTechnicalSignals(prices, rsi, ma5, ma20)
+	signals := s.generateTechnicalSignals(prices, rsi, ma5, ma20, lang)
 
 	return &dto.TechnicalAnalysis{
 		Trend:      trend,
@@ -309,7 +574,7 @@ func (s *StockAnalysisService) performTechnicalAnalysis(history *dto.MCPExecuteR
 }
 
 // performFundamentalAnalysis 执行基本面分析
-func (s *StockAnalysisService) performFundamentalAnalysis(info *dto.MCPExecuteResponse, quote *dto.MCPExecuteResponse) *dto.FundamentalAnalysis {
+func (s *StockAnalysisService) performFundamentalAnalysis(info *dto.MCPExecuteResponse, quote *dto.MCPExecuteResponse, lang string) *dto.FundamentalAnalysis {
 	// 这里应该解析公司信息，提取财务指标
 	// 由于Yahoo Finance API的限制，这里提供一个基础实现
 	return &dto.FundamentalAnalysis{
@@ -317,17 +582,17 @@ func (s *StockAnalysisService) performFundamentalAnalysis(info *dto.MCPExecuteRe
 		PE:            0, // 需要从API响应中解析
 		PB:            0, // 需要从API响应中解析
 		DividendYield: 0, // 需要从API响应中解析
-		Valuation:     "需要更多数据",
+		Valuation:     s.t(lang, "stock.valuation.more_data", nil),
 	}
 }
 
 // performRiskAssessment 执行风险评估
-func (s *StockAnalysisService) performRiskAssessment(history *dto.MCPExecuteResponse) *dto.RiskAssessment {
+func (s *StockAnalysisService) performRiskAssessment(history *dto.MCPExecuteResponse, riskFreeRate float64, lang string) *dto.RiskAssessment {
 	if history == nil || len(history.Content) == 0 {
 		return nil
 	}
 
-	prices := s.parseHistoricalPrices(history.Content[0].Text)
+	prices := s.historicalCloses(history)
 	if len(prices) < 30 {
 		return nil
 	}
@@ -342,66 +607,97 @@ func (s *StockAnalysisService) performRiskAssessment(history *dto.MCPExecuteResp
 	maxDrawdown := s.calculateMaxDrawdown(prices)
 	
 	// 确定风险等级
-	riskLevel := s.determineRiskLevel(volatility, maxDrawdown)
+	riskLevel := s.t(lang, "stock.level."+s.determineRiskLevel(volatility, maxDrawdown), nil)
+
+	// 计算VaR/CVaR、夏普比率和索提诺比率
+	metrics := calculateRiskMetrics(returns, riskFreeRate)
+	var valueAtRisk float64
+	if metrics != nil {
+		valueAtRisk = metrics.VaR95
+	}
 
 	return &dto.RiskAssessment{
 		RiskLevel:   riskLevel,
 		Volatility:  volatility,
 		Beta:        1.0, // 需要市场数据计算
+		VaR:         valueAtRisk,
 		MaxDrawdown: maxDrawdown,
-		RiskFactors: []string{"市场风险", "行业风险", "公司特定风险"},
+		Metrics:     metrics,
+		RiskFactors: []string{
+			s.t(lang, "stock.risk_factor.market", nil),
+			s.t(lang, "stock.risk_factor.industry", nil),
+			s.t(lang, "stock.risk_factor.company", nil),
+		},
 	}
 }
 
 // generateInvestmentAdvice 生成投资建议
-func (s *StockAnalysisService) generateInvestmentAdvice(analysis *dto.StockAnalysisResponse) *dto.InvestmentAdvice {
+func (s *StockAnalysisService) generateInvestmentAdvice(analysis *dto.StockAnalysisResponse, lang string) *dto.InvestmentAdvice {
 	var score float64 = 0.5 // 基础分数
 	var reasons []string
 	var risks []string
 
 	// 基于技术分析调整分数
 	if analysis.TechnicalAnalysis != nil {
-		if analysis.TechnicalAnalysis.Trend == "上升" {
+		if analysis.TechnicalAnalysis.Trend == s.t(lang, "stock.trend.up", nil) {
 			score += 0.2
-			reasons = append(reasons, "技术面显示上升趋势")
-		} else if analysis.TechnicalAnalysis.Trend == "下降" {
+			reasons = append(reasons, s.t(lang, "stock.reason.uptrend", nil))
+		} else if analysis.TechnicalAnalysis.Trend == s.t(lang, "stock.trend.down", nil) {
 			score -= 0.2
-			risks = append(risks, "技术面显示下降趋势")
+			risks = append(risks, s.t(lang, "stock.reason.downtrend", nil))
 		}
 
 		if analysis.TechnicalAnalysis.RSI < 30 {
 			score += 0.1
-			reasons = append(reasons, "RSI显示超卖状态")
+			reasons = append(reasons, s.t(lang, "stock.reason.rsi_oversold", nil))
 		} else if analysis.TechnicalAnalysis.RSI > 70 {
 			score -= 0.1
-			risks = append(risks, "RSI显示超买状态")
+			risks = append(risks, s.t(lang, "stock.reason.rsi_overbought", nil))
 		}
 	}
 
 	// 基于风险评估调整分数
 	if analysis.RiskAssessment != nil {
 		switch analysis.RiskAssessment.RiskLevel {
-		case "低":
+		case s.t(lang, "stock.level.low", nil):
 			score += 0.1
-			reasons = append(reasons, "风险水平较低")
-		case "高":
+			reasons = append(reasons, s.t(lang, "stock.reason.low_risk", nil))
+		case s.t(lang, "stock.level.high", nil):
 			score -= 0.1
-			risks = append(risks, "风险水平较高")
+			risks = append(risks, s.t(lang, "stock.reason.high_risk", nil))
+		}
+	}
+
+	// 基于基准对比调整分数
+	if analysis.BenchmarkComparison != nil {
+		bc := analysis.BenchmarkComparison
+		if bc.Outperformed {
+			score += 0.05
+			reasons = append(reasons, s.t(lang, "stock.reason.outperformed", map[string]interface{}{
+				"Benchmark": bc.Benchmark,
+				"Excess":    fmt.Sprintf("%.2f%%", bc.ExcessReturn*100),
+			}))
+		} else {
+			score -= 0.05
+			risks = append(risks, s.t(lang, "stock.reason.underperformed", map[string]interface{}{
+				"Benchmark": bc.Benchmark,
+				"Excess":    fmt.Sprintf("%.2f%%", -bc.ExcessReturn*100),
+			}))
 		}
 	}
 
 	// 确定推荐操作
 	var recommendation string
 	if score >= 0.8 {
-		recommendation = "强烈买入"
+		recommendation = s.t(lang, "stock.recommendation.strong_buy", nil)
 	} else if score >= 0.6 {
-		recommendation = "买入"
+		recommendation = s.t(lang, "stock.recommendation.buy", nil)
 	} else if score >= 0.4 {
-		recommendation = "持有"
+		recommendation = s.t(lang, "stock.recommendation.hold", nil)
 	} else if score >= 0.2 {
-		recommendation = "卖出"
+		recommendation = s.t(lang, "stock.recommendation.sell", nil)
 	} else {
-		recommendation = "强烈卖出"
+		recommendation = s.t(lang, "stock.recommendation.strong_sell", nil)
 	}
 
 	// 计算目标价格
@@ -410,7 +706,7 @@ func (s *StockAnalysisService) generateInvestmentAdvice(analysis *dto.StockAnaly
 	return &dto.InvestmentAdvice{
 		Recommendation: recommendation,
 		TargetPrice:    targetPrice,
-		TimeHorizon:    "3-6个月",
+		TimeHorizon:    s.t(lang, "stock.time_horizon.medium", nil),
 		Confidence:     score,
 		Reasons:        reasons,
 		Risks:          risks,
@@ -487,19 +783,19 @@ func (s *StockAnalysisService) calculateMA(prices []float64, period int) float64
 	return sum / float64(period)
 }
 
-// determineTrend 确定趋势
+// determineTrend 确定趋势，返回 up, down 或 sideways
 func (s *StockAnalysisService) determineTrend(prices []float64, ma20 float64) string {
 	if len(prices) == 0 {
-		return "横盘"
+		return "sideways"
 	}
 
 	currentPrice := prices[len(prices)-1]
 	if currentPrice > ma20*1.02 {
-		return "上升"
+		return "up"
 	} else if currentPrice < ma20*0.98 {
-		return "下降"
+		return "down"
 	}
-	return "横盘"
+	return "sideways"
 }
 
 // calculateSupportResistance 计算支撑位和阻力位
@@ -518,23 +814,23 @@ func (s *StockAnalysisService) calculateSupportResistance(prices []float64) (flo
 }
 
 // generateTechnicalSignals 生成技术信号
-func (s *StockAnalysisService) generateTechnicalSignals(prices []float64, rsi, ma5, ma20 float64) []dto.TechnicalSignal {
+func (s *StockAnalysisService) generateTechnicalSignals(prices []float64, rsi, ma5, ma20 float64, lang string) []dto.TechnicalSignal {
 	var signals []dto.TechnicalSignal
 
 	// RSI信号
 	if rsi < 30 {
 		signals = append(signals, dto.TechnicalSignal{
 			Type:        "RSI",
-			Signal:      "买入",
+			Signal:      s.t(lang, "stock.signal.buy", nil),
 			Strength:    0.8,
-			Description: "RSI显示超卖状态",
+			Description: s.t(lang, "stock.reason.rsi_oversold", nil),
 		})
 	} else if rsi > 70 {
 		signals = append(signals, dto.TechnicalSignal{
 			Type:        "RSI",
-			Signal:      "卖出",
+			Signal:      s.t(lang, "stock.signal.sell", nil),
 			Strength:    0.8,
-			Description: "RSI显示超买状态",
+			Description: s.t(lang, "stock.reason.rsi_overbought", nil),
 		})
 	}
 
@@ -542,16 +838,16 @@ func (s *StockAnalysisService) generateTechnicalSignals(prices []float64, rsi, m
 	if ma5 > ma20 {
 		signals = append(signals, dto.TechnicalSignal{
 			Type:        "MA",
-			Signal:      "买入",
+			Signal:      s.t(lang, "stock.signal.buy", nil),
 			Strength:    0.6,
-			Description: "短期均线上穿长期均线",
+			Description: s.t(lang, "stock.reason.ma_bullish", nil),
 		})
 	} else if ma5 < ma20 {
 		signals = append(signals, dto.TechnicalSignal{
 			Type:        "MA",
-			Signal:      "卖出",
+			Signal:      s.t(lang, "stock.signal.sell", nil),
 			Strength:    0.6,
-			Description: "短期均线下穿长期均线",
+			Description: s.t(lang, "stock.reason.ma_bearish", nil),
 		})
 	}
 
@@ -614,23 +910,26 @@ func (s *StockAnalysisService) calculateMaxDrawdown(prices []float64) float64 {
 	return maxDrawdown
 }
 
-// determineRiskLevel 确定风险等级
+// determineRiskLevel 确定风险等级，返回 low, medium 或 high
 func (s *StockAnalysisService) determineRiskLevel(volatility, maxDrawdown float64) string {
 	if volatility > 0.3 || maxDrawdown > 0.2 {
-		return "高"
+		return "high"
 	} else if volatility > 0.15 || maxDrawdown > 0.1 {
-		return "中"
+		return "medium"
 	}
-	return "低"
+	return "low"
 }
 
-// performStockComparison 执行股票对比
-func (s *StockAnalysisService) performStockComparison(stocks []dto.StockAnalysisResponse) *dto.StockComparison {
+// performStockComparison 执行股票对比，价格与市值按 rates 换算为基准货币
+func (s *StockAnalysisService) performStockComparison(stocks []dto.StockAnalysisResponse, baseCurrency string, rates map[string]float64) *dto.StockComparison {
 	if len(stocks) == 0 {
 		return nil
 	}
 
 	comparison := &dto.StockComparison{
+		BaseCurrency: baseCurrency,
+		FXRates:      make(map[string]float64),
+		Prices:       make(map[string]float64),
 		Performance: &dto.PerformanceComparison{
 			Returns1D: make(map[string]float64),
 			Returns1W: make(map[string]float64),
@@ -650,10 +949,18 @@ func (s *StockAnalysisService) performStockComparison(stocks []dto.StockAnalysis
 
 	// 填充对比数据
 	for _, stock := range stocks {
+		rate, hasRate := rates[stock.Symbol]
+		if hasRate {
+			comparison.FXRates[stock.Symbol] = rate
+			comparison.Prices[stock.Symbol] = stock.CurrentPrice * rate
+		}
+
 		if stock.FundamentalAnalysis != nil {
 			comparison.Valuation.PE[stock.Symbol] = stock.FundamentalAnalysis.PE
 			comparison.Valuation.PB[stock.Symbol] = stock.FundamentalAnalysis.PB
-			comparison.Valuation.MarketCap[stock.Symbol] = stock.FundamentalAnalysis.MarketCap
+			if hasRate {
+				comparison.Valuation.MarketCap[stock.Symbol] = stock.FundamentalAnalysis.MarketCap * rate
+			}
 		}
 
 		if stock.RiskAssessment != nil {
@@ -661,15 +968,25 @@ func (s *StockAnalysisService) performStockComparison(stocks []dto.StockAnalysis
 			comparison.Risk.Beta[stock.Symbol] = stock.RiskAssessment.Beta
 			comparison.Risk.MaxDrawdown[stock.Symbol] = stock.RiskAssessment.MaxDrawdown
 		}
+
+		if stock.BenchmarkComparison != nil {
+			if comparison.Performance.ExcessReturn == nil {
+				comparison.Performance.Benchmark = stock.BenchmarkComparison.Benchmark
+				comparison.Performance.ExcessReturn = make(map[string]float64)
+				comparison.Performance.Alpha = make(map[string]float64)
+			}
+			comparison.Performance.ExcessReturn[stock.Symbol] = stock.BenchmarkComparison.ExcessReturn
+			comparison.Performance.Alpha[stock.Symbol] = stock.BenchmarkComparison.Alpha
+		}
 	}
 
 	return comparison
 }
 
 // generateComparisonRecommendation 生成对比推荐
-func (s *StockAnalysisService) generateComparisonRecommendation(stocks []dto.StockAnalysisResponse, comparison *dto.StockComparison) string {
+func (s *StockAnalysisService) generateComparisonRecommendation(stocks []dto.StockAnalysisResponse, comparison *dto.StockComparison, lang string) string {
 	if len(stocks) == 0 {
-		return "无法生成推荐"
+		return s.t(lang, "stock.compare.no_recommendation", nil)
 	}
 
 	// 简单的推荐逻辑：选择风险调整后收益最好的股票
@@ -689,5 +1006,5 @@ func (s *StockAnalysisService) generateComparisonRecommendation(stocks []dto.Sto
 		}
 	}
 
-	return fmt.Sprintf("基于综合分析，推荐关注 %s，其风险调整后的投资价值相对较高", bestStock)
+	return s.t(lang, "stock.compare.best_pick", map[string]interface{}{"Symbol": bestStock})
 }
\ No newline at end of file
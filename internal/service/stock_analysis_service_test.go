@@ -0,0 +1,103 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"go-springAi/internal/dto"
+
+	"go.uber.org/zap"
+)
+
+func TestCalculateBenchmarkComparison(t *testing.T) {
+	service := &StockAnalysisService{
+		logger: zap.NewNop(),
+	}
+
+	day := int64(24 * 60 * 60)
+	benchmark := &dto.PriceSeries{
+		Symbol:     "^GSPC",
+		Timestamps: []int64{0, day, 2 * day, 3 * day, 4 * day},
+		Closes:     []float64{100, 110, 99, 108.9, 110},
+	}
+
+	tests := []struct {
+		name           string
+		stock          *dto.PriceSeries
+		expectNil      bool
+		expectPoints   int
+		expectReturn   float64
+		expectBeta     float64
+		expectOutperf  bool
+		expectUpCapt   float64
+		expectDownCapt float64
+	}{
+		{
+			name: "Double the benchmark moves",
+			stock: &dto.PriceSeries{
+				Timestamps: []int64{0, day, 2 * day, 3 * day},
+				Closes:     []float64{100, 120, 96, 115.2},
+			},
+			expectPoints:   4,
+			expectReturn:   0.152,
+			expectBeta:     2,
+			expectOutperf:  true,
+			expectUpCapt:   200,
+			expectDownCapt: 200,
+		},
+		{
+			name: "Misaligned days are skipped",
+			stock: &dto.PriceSeries{
+				Timestamps: []int64{0, 10 * day, 4 * day},
+				Closes:     []float64{100, 500, 105},
+			},
+			expectPoints:  2,
+			expectReturn:  0.05,
+			expectOutperf: false,
+		},
+		{
+			name: "Not enough overlapping data",
+			stock: &dto.PriceSeries{
+				Timestamps: []int64{10 * day},
+				Closes:     []float64{100},
+			},
+			expectNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := service.calculateBenchmarkComparison(tt.stock, benchmark)
+			if tt.expectNil {
+				if result != nil {
+					t.Fatalf("expected nil result, got %+v", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatal("expected result, got nil")
+			}
+			if result.DataPoints != tt.expectPoints {
+				t.Errorf("expected %d data points, got %d", tt.expectPoints, result.DataPoints)
+			}
+			if math.Abs(result.StockReturn-tt.expectReturn) > 1e-9 {
+				t.Errorf("expected stock return %f, got %f", tt.expectReturn, result.StockReturn)
+			}
+			if result.Outperformed != tt.expectOutperf {
+				t.Errorf("expected outperformed=%v, got %v", tt.expectOutperf, result.Outperformed)
+			}
+			if math.Abs(result.ExcessReturn-(result.StockReturn-result.BenchmarkReturn)) > 1e-9 {
+				t.Errorf("excess return %f does not match stock-benchmark difference", result.ExcessReturn)
+			}
+			if tt.expectBeta != 0 && math.Abs(result.Beta-tt.expectBeta) > 1e-9 {
+				t.Errorf("expected beta %f, got %f", tt.expectBeta, result.Beta)
+			}
+			if tt.expectUpCapt != 0 && math.Abs(result.UpCapture-tt.expectUpCapt) > 1e-9 {
+				t.Errorf("expected up capture %f, got %f", tt.expectUpCapt, result.UpCapture)
+			}
+			if tt.expectDownCapt != 0 && math.Abs(result.DownCapture-tt.expectDownCapt) > 1e-9 {
+				t.Errorf("expected down capture %f, got %f", tt.expectDownCapt, result.DownCapture)
+			}
+		})
+	}
+}
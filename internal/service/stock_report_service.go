@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/go-pdf/fpdf"
+	"go.uber.org/zap"
+)
+
+// reportFontFamily 报告使用的UTF-8字体族名称
+const reportFontFamily = "report"
+
+// reportDisclaimer 报告免责声明
+const reportDisclaimer = "This report is generated automatically from public market data for informational purposes only. " +
+	"It does not constitute investment advice, an offer or a solicitation to buy or sell any security. " +
+	"Past performance is not indicative of future results. Please consult a licensed financial advisor before making investment decisions."
+
+// StockReportService 股票分析报告服务
+type StockReportService struct {
+	stockAnalysisService *StockAnalysisService
+	fontPath             string
+	logger               *zap.Logger
+}
+
+// NewStockReportService 创建股票分析报告服务
+func NewStockReportService(stockAnalysisService *StockAnalysisService, fontPath string, logger *zap.Logger) *StockReportService {
+	return &StockReportService{
+		stockAnalysisService: stockAnalysisService,
+		fontPath:             fontPath,
+		logger:               logger,
+	}
+}
+
+// GenerateReport 生成股票综合分析PDF报告
+func (s *StockReportService) GenerateReport(ctx context.Context, req *dto.StockAnalysisRequest) ([]byte, error) {
+	analysisReq := *req
+	analysisReq.AnalysisType = "all"
+	// 内置字体仅支持拉丁字符，未配置UTF-8字体时以英文输出分析结果
+	if s.fontPath == "" {
+		analysisReq.Language = "en"
+	}
+	if analysisReq.Period == "" {
+		analysisReq.Period = "3mo"
+	}
+
+	analysis, err := s.stockAnalysisService.AnalyzeStock(ctx, &analysisReq)
+	if err != nil {
+		return nil, fmt.Errorf("股票分析失败: %w", err)
+	}
+
+	data, err := s.renderPDF(analysis, analysisReq.Period)
+	if err != nil {
+		return nil, fmt.Errorf("生成PDF报告失败: %w", err)
+	}
+
+	s.logger.Info("股票分析报告生成成功", zap.String("symbol", req.Symbol), zap.Int("size", len(data)))
+	return data, nil
+}
+
+// pdfReport PDF报告渲染上下文
+type pdfReport struct {
+	pdf    *fpdf.Fpdf
+	family string
+	text   func(string) string
+}
+
+// renderPDF 将分析结果渲染为PDF
+func (s *StockReportService) renderPDF(analysis *dto.StockAnalysisResponse, period string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("%s Stock Analysis Report", analysis.Symbol), true)
+	pdf.SetCreator("go-springAi", true)
+	pdf.AliasNbPages("")
+
+	report := &pdfReport{pdf: pdf}
+
+	// 配置了UTF-8字体时直接输出原文，否则使用内置字体并转换为cp1252编码
+	if s.fontPath != "" {
+		pdf.AddUTF8Font(reportFontFamily, "", s.fontPath)
+		pdf.AddUTF8Font(reportFontFamily, "B", s.fontPath)
+		report.family = reportFontFamily
+		report.text = func(str string) string { return str }
+	} else {
+		report.family = "Helvetica"
+		report.text = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont(report.family, "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	report.writeHeader(analysis, period)
+	report.writeTechnical(analysis.TechnicalAnalysis)
+	report.writeFundamental(analysis.FundamentalAnalysis)
+	report.writeRisk(analysis.RiskAssessment)
+	report.writeBenchmark(analysis.BenchmarkComparison)
+	report.writeAdvice(analysis.InvestmentAdvice)
+	report.writeDisclaimer()
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeHeader 输出报告标题和概要
+func (r *pdfReport) writeHeader(analysis *dto.StockAnalysisResponse, period string) {
+	r.pdf.SetFont(r.family, "B", 18)
+	r.pdf.CellFormat(0, 12, r.text(fmt.Sprintf("%s Stock Analysis Report", analysis.Symbol)), "", 1, "L", false, 0, "")
+
+	r.pdf.SetFont(r.family, "", 10)
+	r.pdf.SetTextColor(100, 100, 100)
+	r.pdf.CellFormat(0, 6, r.text(fmt.Sprintf("Generated at %s | Period: %s", time.Now().Format("2006-01-02 15:04:05"), period)), "", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.Ln(4)
+
+	r.writeSection("Overview")
+	r.writeRow("Symbol", analysis.Symbol)
+	if analysis.CompanyName != "" {
+		r.writeRow("Company", analysis.CompanyName)
+	}
+	r.writeRow("Current Price", fmt.Sprintf("%.2f %s", analysis.CurrentPrice, analysis.Currency))
+	r.writeRow("Last Updated", analysis.LastUpdated.Format("2006-01-02 15:04:05"))
+}
+
+// writeTechnical 输出技术分析
+func (r *pdfReport) writeTechnical(ta *dto.TechnicalAnalysis) {
+	if ta == nil {
+		return
+	}
+
+	r.writeSection("Technical Analysis")
+	r.writeRow("Trend", ta.Trend)
+	r.writeRow("Support", fmt.Sprintf("%.2f", ta.Support))
+	r.writeRow("Resistance", fmt.Sprintf("%.2f", ta.Resistance))
+	r.writeRow("RSI (14)", fmt.Sprintf("%.2f", ta.RSI))
+	if ta.MovingAverages != nil {
+		ma := ta.MovingAverages
+		r.writeRow("MA5 / MA10 / MA20", fmt.Sprintf("%.2f / %.2f / %.2f", ma.MA5, ma.MA10, ma.MA20))
+		r.writeRow("MA50 / MA200", fmt.Sprintf("%.2f / %.2f", ma.MA50, ma.MA200))
+	}
+	if ta.MACD != nil {
+		r.writeRow("MACD", fmt.Sprintf("%.4f (signal %.4f, histogram %.4f)", ta.MACD.MACD, ta.MACD.Signal, ta.MACD.Histogram))
+	}
+	for _, signal := range ta.TechnicalSignals {
+		r.writeRow(signal.Type+" Signal", fmt.Sprintf("%s (%.0f%%) - %s", signal.Signal, signal.Strength*100, signal.Description))
+	}
+}
+
+// writeFundamental 输出基本面分析
+func (r *pdfReport) writeFundamental(fa *dto.FundamentalAnalysis) {
+	if fa == nil {
+		return
+	}
+
+	r.writeSection("Fundamental Analysis")
+	r.writeRow("Market Cap", formatReportNumber(fa.MarketCap))
+	r.writeRow("P/E", fmt.Sprintf("%.2f", fa.PE))
+	r.writeRow("P/B", fmt.Sprintf("%.2f", fa.PB))
+	r.writeRow("Dividend Yield", fmt.Sprintf("%.2f%%", fa.DividendYield*100))
+	r.writeRow("Valuation", fa.Valuation)
+}
+
+// writeRisk 输出风险评估
+func (r *pdfReport) writeRisk(ra *dto.RiskAssessment) {
+	if ra == nil {
+		return
+	}
+
+	r.writeSection("Risk Assessment")
+	r.writeRow("Risk Level", ra.RiskLevel)
+	r.writeRow("Annualized Volatility", fmt.Sprintf("%.2f%%", ra.Volatility*100))
+	r.writeRow("Beta", fmt.Sprintf("%.2f", ra.Beta))
+	r.writeRow("Max Drawdown", fmt.Sprintf("%.2f%%", ra.MaxDrawdown*100))
+	if m := ra.Metrics; m != nil {
+		r.writeRow("VaR 95% / 99% (1 day)", fmt.Sprintf("%.2f%% / %.2f%%", m.VaR95*100, m.VaR99*100))
+		r.writeRow("CVaR 95% / 99% (1 day)", fmt.Sprintf("%.2f%% / %.2f%%", m.CVaR95*100, m.CVaR99*100))
+		r.writeRow("Sharpe Ratio", fmt.Sprintf("%.2f", m.SharpeRatio))
+		r.writeRow("Sortino Ratio", fmt.Sprintf("%.2f", m.SortinoRatio))
+	} else if ra.VaR != 0 {
+		r.writeRow("Value at Risk", fmt.Sprintf("%.2f%%", ra.VaR*100))
+	}
+	if len(ra.RiskFactors) > 0 {
+		r.writeList("Risk Factors", ra.RiskFactors)
+	}
+}
+
+// writeBenchmark 输出基准对比
+func (r *pdfReport) writeBenchmark(bc *dto.BenchmarkComparison) {
+	if bc == nil {
+		return
+	}
+
+	r.writeSection(fmt.Sprintf("Benchmark Comparison (%s)", bc.Benchmark))
+	r.writeRow("Stock Return", fmt.Sprintf("%.2f%%", bc.StockReturn*100))
+	r.writeRow("Benchmark Return", fmt.Sprintf("%.2f%%", bc.BenchmarkReturn*100))
+	r.writeRow("Excess Return", fmt.Sprintf("%.2f%%", bc.ExcessReturn*100))
+	r.writeRow("Alpha", fmt.Sprintf("%.2f%%", bc.Alpha*100))
+	r.writeRow("Beta", fmt.Sprintf("%.2f", bc.Beta))
+	r.writeRow("Up / Down Capture", fmt.Sprintf("%.1f%% / %.1f%%", bc.UpCapture, bc.DownCapture))
+}
+
+// writeAdvice 输出投资建议
+func (r *pdfReport) writeAdvice(advice *dto.InvestmentAdvice) {
+	if advice == nil {
+		return
+	}
+
+	r.writeSection("Investment Advice")
+	r.writeRow("Recommendation", advice.Recommendation)
+	r.writeRow("Target Price", fmt.Sprintf("%.2f", advice.TargetPrice))
+	r.writeRow("Time Horizon", advice.TimeHorizon)
+	r.writeRow("Confidence", fmt.Sprintf("%.0f%%", advice.Confidence*100))
+	if len(advice.Reasons) > 0 {
+		r.writeList("Reasons", advice.Reasons)
+	}
+	if len(advice.Risks) > 0 {
+		r.writeList("Risks", advice.Risks)
+	}
+}
+
+// writeDisclaimer 输出免责声明
+func (r *pdfReport) writeDisclaimer() {
+	r.pdf.Ln(6)
+	r.pdf.SetFont(r.family, "B", 9)
+	r.pdf.CellFormat(0, 6, "Disclaimer", "", 1, "L", false, 0, "")
+	r.pdf.SetFont(r.family, "", 8)
+	r.pdf.SetTextColor(100, 100, 100)
+	r.pdf.MultiCell(0, 4, r.text(reportDisclaimer), "", "L", false)
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+// writeSection 输出章节标题
+func (r *pdfReport) writeSection(title string) {
+	r.pdf.Ln(4)
+	r.pdf.SetFont(r.family, "B", 12)
+	r.pdf.SetFillColor(230, 236, 245)
+	r.pdf.CellFormat(0, 8, r.text(title), "", 1, "L", true, 0, "")
+	r.pdf.Ln(1)
+}
+
+// writeRow 输出键值表格行
+func (r *pdfReport) writeRow(label, value string) {
+	r.pdf.SetFont(r.family, "B", 10)
+	r.pdf.CellFormat(55, 7, r.text(label), "B", 0, "L", false, 0, "")
+	r.pdf.SetFont(r.family, "", 10)
+	r.pdf.CellFormat(0, 7, r.text(value), "B", 1, "L", false, 0, "")
+}
+
+// writeList 输出列表项
+func (r *pdfReport) writeList(label string, items []string) {
+	r.pdf.SetFont(r.family, "B", 10)
+	r.pdf.CellFormat(0, 7, r.text(label), "", 1, "L", false, 0, "")
+	r.pdf.SetFont(r.family, "", 10)
+	for _, item := range items {
+		r.pdf.MultiCell(0, 6, r.text("- "+item), "", "L", false)
+	}
+}
+
+// formatReportNumber 格式化报告中的大数字
+func formatReportNumber(num float64) string {
+	switch {
+	case num >= 1e12:
+		return fmt.Sprintf("%.2fT", num/1e12)
+	case num >= 1e9:
+		return fmt.Sprintf("%.2fB", num/1e9)
+	case num >= 1e6:
+		return fmt.Sprintf("%.2fM", num/1e6)
+	}
+	return fmt.Sprintf("%.0f", num)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStockReportRenderPDF(t *testing.T) {
+	// 关闭压缩以便检查页面内容
+	fpdf.SetDefaultCompression(false)
+	t.Cleanup(func() { fpdf.SetDefaultCompression(true) })
+	reports := NewStockReportService(nil, "", zap.NewNop())
+
+	tests := []struct {
+		name        string
+		analysis    *dto.StockAnalysisResponse
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "Full analysis",
+			analysis: &dto.StockAnalysisResponse{
+				Symbol:       "AAPL",
+				CompanyName:  "Apple Inc.",
+				CurrentPrice: 189.5,
+				Currency:     "USD",
+				LastUpdated:  time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+				TechnicalAnalysis: &dto.TechnicalAnalysis{
+					Trend: "bullish", Support: 180, Resistance: 195, RSI: 61.25,
+					MACD: &dto.MACDIndicator{MACD: 1.2345, Signal: 1.1, Histogram: 0.1345},
+				},
+				FundamentalAnalysis: &dto.FundamentalAnalysis{MarketCap: 2.95e12, PE: 29.4, Valuation: "fair"},
+				RiskAssessment:      &dto.RiskAssessment{RiskLevel: "medium", Volatility: 0.2512, RiskFactors: []string{"Supply chain"}},
+				InvestmentAdvice: &dto.InvestmentAdvice{
+					Recommendation: "buy", TargetPrice: 210, TimeHorizon: "12 months", Confidence: 0.7,
+					Reasons: []string{"Services growth"},
+				},
+			},
+			contains: []string{"(AAPL Stock Analysis Report)", "(Apple Inc.)", "(189.50 USD)", "(2026-01-02 15:04:05)",
+				"(Technical Analysis)", "(1.2345 \\(signal 1.1000, histogram 0.1345\\))", "(2.95T)", "(25.12%)",
+				"(- Supply chain)", "(Investment Advice)", "(- Services growth)", "(Disclaimer)"},
+		},
+		{
+			name:        "Missing sections are skipped",
+			analysis:    &dto.StockAnalysisResponse{Symbol: "SAP.DE", CurrentPrice: 231.4, Currency: "EUR"},
+			contains:    []string{"(SAP.DE Stock Analysis Report)", "(231.40 EUR)", "(Disclaimer)"},
+			notContains: []string{"(Company)", "(Technical Analysis)", "(Fundamental Analysis)", "(Investment Advice)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := reports.renderPDF(tt.analysis, "3mo")
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+			assert.True(t, bytes.HasSuffix(bytes.TrimSpace(data), []byte("%%EOF")))
+			for _, want := range tt.contains {
+				assert.Contains(t, string(data), want)
+			}
+			for _, unwanted := range tt.notContains {
+				assert.NotContains(t, string(data), unwanted)
+			}
+		})
+	}
+}
+
+func TestFormatReportNumber(t *testing.T) {
+	assert.Equal(t, "2.95T", formatReportNumber(2.95e12))
+	assert.Equal(t, "270.00B", formatReportNumber(2.7e11))
+	assert.Equal(t, "1.53M", formatReportNumber(1534000))
+	assert.Equal(t, "999999", formatReportNumber(999999))
+}
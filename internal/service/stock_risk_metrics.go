@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go-springAi/internal/dto"
+
+	"go.uber.org/zap"
+)
+
+// tradingDaysPerYear 年化使用的交易日数量
+const tradingDaysPerYear = 252
+
+// defaultPortfolioPeriod 组合风险分析的默认周期
+const defaultPortfolioPeriod = "1y"
+
+// AnalyzePortfolioRisk 计算投资组合（按权重每日再平衡）及各持仓的风险指标
+func (s *StockAnalysisService) AnalyzePortfolioRisk(ctx context.Context, req *dto.PortfolioRiskRequest) (*dto.PortfolioRiskResponse, error) {
+	if len(req.Holdings) == 0 {
+		return nil, fmt.Errorf("投资组合不能为空")
+	}
+
+	period := req.Period
+	if period == "" {
+		period = defaultPortfolioPeriod
+	}
+	riskFreeRate := s.riskFreeRate
+	if req.RiskFreeRate != nil {
+		riskFreeRate = *req.RiskFreeRate
+	}
+
+	holdings, err := normalizeHoldings(req.Holdings)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("开始分析投资组合风险", zap.Int("holdings", len(holdings)), zap.String("period", period))
+
+	series := make([]*dto.PriceSeries, len(holdings))
+	for i, holding := range holdings {
+		history, err := s.getStockHistory(ctx, holding.Symbol, period, "1d")
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s 历史数据失败: %w", holding.Symbol, err)
+		}
+		series[i] = s.extractPriceSeries(history)
+		if series[i] == nil {
+			return nil, fmt.Errorf("股票 %s 没有可用的历史数据", holding.Symbol)
+		}
+	}
+
+	// 按交易日对齐后计算各持仓及组合的日收益率
+	aligned := alignSeriesByDate(series)
+	if len(aligned) == 0 || len(aligned[0]) < 3 {
+		return nil, fmt.Errorf("持仓之间重叠的交易日不足，无法计算组合风险")
+	}
+
+	response := &dto.PortfolioRiskResponse{
+		Holdings:   holdings,
+		Period:     period,
+		Individual: make(map[string]*dto.RiskMetrics, len(holdings)),
+	}
+
+	portfolioReturns := make([]float64, len(aligned[0])-1)
+	for i, holding := range holdings {
+		returns := s.calculateReturns(aligned[i])
+		response.Individual[holding.Symbol] = calculateRiskMetrics(returns, riskFreeRate)
+		for day, ret := range returns {
+			portfolioReturns[day] += holding.Weight * ret
+		}
+	}
+
+	response.Portfolio = calculateRiskMetrics(portfolioReturns, riskFreeRate)
+	response.MaxDrawdown = s.calculateMaxDrawdown(cumulativeValues(portfolioReturns))
+
+	return response, nil
+}
+
+// normalizeHoldings 合并重复代码并将权重归一化为总和1
+func normalizeHoldings(holdings []dto.PortfolioHolding) ([]dto.PortfolioHolding, error) {
+	var normalized []dto.PortfolioHolding
+	index := make(map[string]int, len(holdings))
+	total := 0.0
+
+	for _, holding := range holdings {
+		symbol := strings.ToUpper(strings.TrimSpace(holding.Symbol))
+		if symbol == "" {
+			return nil, fmt.Errorf("股票代码不能为空")
+		}
+		if holding.Weight <= 0 || math.IsNaN(holding.Weight) || math.IsInf(holding.Weight, 0) {
+			return nil, fmt.Errorf("股票 %s 的权重必须为正数", symbol)
+		}
+		total += holding.Weight
+
+		if i, ok := index[symbol]; ok {
+			normalized[i].Weight += holding.Weight
+			continue
+		}
+		index[symbol] = len(normalized)
+		normalized = append(normalized, dto.PortfolioHolding{Symbol: symbol, Weight: holding.Weight})
+	}
+
+	for i := range normalized {
+		normalized[i].Weight /= total
+	}
+	return normalized, nil
+}
+
+// alignSeriesByDate 按UTC交易日对齐多个价格序列，只保留所有序列都有收盘价的日期
+func alignSeriesByDate(series []*dto.PriceSeries) [][]float64 {
+	if len(series) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	closesByDay := make([]map[string]float64, len(series))
+	for i, s := range series {
+		closesByDay[i] = make(map[string]float64, len(s.Timestamps))
+		for j, ts := range s.Timestamps {
+			if j >= len(s.Closes) {
+				break
+			}
+			day := time.Unix(ts, 0).UTC().Format("2006-01-02")
+			if _, seen := closesByDay[i][day]; !seen {
+				counts[day]++
+			}
+			closesByDay[i][day] = s.Closes[j]
+		}
+	}
+
+	var days []string
+	for day, count := range counts {
+		if count == len(series) {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+
+	aligned := make([][]float64, len(series))
+	for i := range series {
+		aligned[i] = make([]float64, len(days))
+		for j, day := range days {
+			aligned[i][j] = closesByDay[i][day]
+		}
+	}
+	return aligned
+}
+
+// cumulativeValues 将日收益率转换为以1为起点的净值序列
+func cumulativeValues(returns []float64) []float64 {
+	values := make([]float64, len(returns)+1)
+	values[0] = 1
+	for i, ret := range returns {
+		values[i+1] = values[i] * (1 + ret)
+	}
+	return values
+}
+
+// calculateRiskMetrics 根据日收益率计算历史VaR/CVaR、夏普比率和索提诺比率
+func calculateRiskMetrics(returns []float64, riskFreeRate float64) *dto.RiskMetrics {
+	if len(returns) < 2 {
+		return nil
+	}
+
+	n := float64(len(returns))
+	mean := 0.0
+	for _, ret := range returns {
+		mean += ret
+	}
+	mean /= n
+
+	variance := 0.0
+	for _, ret := range returns {
+		variance += (ret - mean) * (ret - mean)
+	}
+	stdDev := math.Sqrt(variance / (n - 1))
+
+	// 下行偏差只统计低于日无风险收益的部分
+	dailyRiskFree := riskFreeRate / tradingDaysPerYear
+	downside := 0.0
+	for _, ret := range returns {
+		if diff := ret - dailyRiskFree; diff < 0 {
+			downside += diff * diff
+		}
+	}
+	downsideDev := math.Sqrt(downside / n)
+
+	metrics := &dto.RiskMetrics{
+		DataPoints:           len(returns),
+		AnnualizedReturn:     mean * tradingDaysPerYear,
+		AnnualizedVolatility: stdDev * math.Sqrt(tradingDaysPerYear),
+		RiskFreeRate:         riskFreeRate,
+	}
+	metrics.VaR95, metrics.CVaR95 = historicalVaR(returns, 0.95)
+	metrics.VaR99, metrics.CVaR99 = historicalVaR(returns, 0.99)
+
+	if stdDev > 0 {
+		metrics.SharpeRatio = (mean - dailyRiskFree) / stdDev * math.Sqrt(tradingDaysPerYear)
+	}
+	if downsideDev > 0 {
+		metrics.SortinoRatio = (mean - dailyRiskFree) / downsideDev * math.Sqrt(tradingDaysPerYear)
+	}
+
+	return metrics
+}
+
+// historicalVaR 计算给定置信度下的历史VaR与CVaR（以正数表示损失）
+func historicalVaR(returns []float64, confidence float64) (float64, float64) {
+	if len(returns) == 0 {
+		return 0, 0
+	}
+
+	sorted := make([]float64, len(returns))
+	copy(sorted, returns)
+	sort.Float64s(sorted)
+
+	// 尾部样本数向上取整（扣除浮点误差），至少包含最差的一天
+	tail := int(math.Ceil(float64(len(sorted))*(1-confidence) - 1e-9))
+	if tail < 1 {
+		tail = 1
+	}
+
+	sum := 0.0
+	for _, ret := range sorted[:tail] {
+		sum += ret
+	}
+
+	return -sorted[tail-1], -sum / float64(tail)
+}
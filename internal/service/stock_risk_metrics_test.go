@@ -0,0 +1,114 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"go-springAi/internal/dto"
+)
+
+func TestHistoricalVaR(t *testing.T) {
+	returns := make([]float64, 100)
+	for i := range returns {
+		// -0.05, -0.049, ..., 0.049
+		returns[i] = float64(i-50) / 1000
+	}
+
+	tests := []struct {
+		name         string
+		confidence   float64
+		expectedVaR  float64
+		expectedCVaR float64
+	}{
+		{name: "95% confidence", confidence: 0.95, expectedVaR: 0.046, expectedCVaR: 0.048},
+		{name: "99% confidence", confidence: 0.99, expectedVaR: 0.05, expectedCVaR: 0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valueAtRisk, cvar := historicalVaR(returns, tt.confidence)
+			if math.Abs(valueAtRisk-tt.expectedVaR) > 1e-9 {
+				t.Errorf("expected VaR %.4f, got %.4f", tt.expectedVaR, valueAtRisk)
+			}
+			if math.Abs(cvar-tt.expectedCVaR) > 1e-9 {
+				t.Errorf("expected CVaR %.4f, got %.4f", tt.expectedCVaR, cvar)
+			}
+		})
+	}
+}
+
+func TestCalculateRiskMetrics(t *testing.T) {
+	if metrics := calculateRiskMetrics([]float64{0.01}, 0.02); metrics != nil {
+		t.Errorf("expected nil metrics for a single return, got %+v", metrics)
+	}
+
+	returns := []float64{0.01, -0.02, 0.015, -0.005, 0.02, -0.01}
+	metrics := calculateRiskMetrics(returns, 0)
+	if metrics == nil {
+		t.Fatal("expected metrics, got nil")
+	}
+	if metrics.DataPoints != len(returns) {
+		t.Errorf("expected %d data points, got %d", len(returns), metrics.DataPoints)
+	}
+	if metrics.VaR95 != 0.02 || metrics.CVaR95 != 0.02 {
+		t.Errorf("expected VaR95/CVaR95 of 0.02, got %.4f/%.4f", metrics.VaR95, metrics.CVaR95)
+	}
+	if metrics.SharpeRatio <= 0 || metrics.SortinoRatio <= metrics.SharpeRatio {
+		t.Errorf("expected positive Sortino above Sharpe, got Sharpe %.4f Sortino %.4f", metrics.SharpeRatio, metrics.SortinoRatio)
+	}
+
+	// 无风险利率高于平均收益时比率应为负
+	if high := calculateRiskMetrics(returns, 1); high.SharpeRatio >= 0 || high.SortinoRatio >= 0 {
+		t.Errorf("expected negative ratios, got Sharpe %.4f Sortino %.4f", high.SharpeRatio, high.SortinoRatio)
+	}
+}
+
+func TestNormalizeHoldings(t *testing.T) {
+	holdings, err := normalizeHoldings([]dto.PortfolioHolding{
+		{Symbol: "aapl", Weight: 2},
+		{Symbol: "MSFT", Weight: 1},
+		{Symbol: " AAPL ", Weight: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holdings) != 2 {
+		t.Fatalf("expected 2 holdings, got %d", len(holdings))
+	}
+	if holdings[0].Symbol != "AAPL" || math.Abs(holdings[0].Weight-0.75) > 1e-9 {
+		t.Errorf("expected AAPL weight 0.75, got %s %.4f", holdings[0].Symbol, holdings[0].Weight)
+	}
+	if holdings[1].Symbol != "MSFT" || math.Abs(holdings[1].Weight-0.25) > 1e-9 {
+		t.Errorf("expected MSFT weight 0.25, got %s %.4f", holdings[1].Symbol, holdings[1].Weight)
+	}
+
+	if _, err := normalizeHoldings([]dto.PortfolioHolding{{Symbol: "AAPL", Weight: 0}}); err == nil {
+		t.Error("expected error for zero weight")
+	}
+}
+
+func TestAlignSeriesByDate(t *testing.T) {
+	const day = 24 * 60 * 60
+	first := &dto.PriceSeries{
+		Timestamps: []int64{0, day, 2 * day, 3 * day},
+		Closes:     []float64{10, 11, 12, 13},
+	}
+	second := &dto.PriceSeries{
+		Timestamps: []int64{day, 3 * day, 4 * day},
+		Closes:     []float64{20, 21, 22},
+	}
+
+	aligned := alignSeriesByDate([]*dto.PriceSeries{first, second})
+	expected := [][]float64{{11, 13}, {20, 21}}
+	for i := range expected {
+		if len(aligned[i]) != len(expected[i]) {
+			t.Fatalf("series %d: expected %v, got %v", i, expected[i], aligned[i])
+		}
+		for j := range expected[i] {
+			if aligned[i][j] != expected[i][j] {
+				t.Errorf("series %d: expected %v, got %v", i, expected[i], aligned[i])
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/pagination"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// UserAdminService 用户管理服务接口
+type UserAdminService interface {
+	// ListUsers 按条件分页查询用户，默认使用游标分页
+	ListUsers(ctx context.Context, query *dto.UserListQuery) (*dto.UserListResponse, error)
+	// CreateUser 创建用户，用户名或邮箱已存在时返回冲突错误
+	CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error)
+	// DeleteUser 软删除用户并吊销其登录会话
+	DeleteUser(ctx context.Context, id int64) error
+	// RestoreUser 恢复已软删除的用户
+	RestoreUser(ctx context.Context, id int64) (*dto.UserResponse, error)
+	// PurgeDeletedUsers 永久删除超过保留期的软删除用户
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+}
+
+// userAdminService 用户管理服务实现
+type userAdminService struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	retention        time.Duration
+	events           webhook.Publisher
+	logger           *zap.Logger
+}
+
+// NewUserAdminService 创建用户管理服务，retentionDays 为软删除用户的保留天数，events 为空时不发布用户创建事件
+func NewUserAdminService(repoManager repository.RepositoryManager, retentionDays int, events webhook.Publisher, logger *zap.Logger) UserAdminService {
+	return &userAdminService{
+		userRepo:         repoManager.User(),
+		refreshTokenRepo: repoManager.RefreshToken(),
+		retention:        time.Duration(retentionDays) * 24 * time.Hour,
+		events:           events,
+		logger:           logger,
+	}
+}
+
+// ListUsers 按条件分页查询用户
+func (s *userAdminService) ListUsers(ctx context.Context, query *dto.UserListQuery) (*dto.UserListResponse, error) {
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedTo.Before(*query.CreatedFrom) {
+		return nil, errors.NewValidationError("创建日期范围无效").WithDetails("created_to must not be before created_from")
+	}
+
+	filter := &repository.UserFilter{
+		Search:      query.Search,
+		IsActive:    query.IsActive,
+		CreatedFrom: query.CreatedFrom,
+		SortBy:      query.SortBy,
+		SortOrder:   query.SortOrder,
+
+		SoftDeleteOptions: repository.SoftDeleteOptions{IncludeDeleted: query.IncludeDeleted},
+	}
+	if query.CreatedTo != nil {
+		// 结束日期按整天计算
+		before := query.CreatedTo.AddDate(0, 0, 1)
+		filter.CreatedBefore = &before
+	}
+
+	total, err := s.userRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 指定 page 且没有游标时按偏移分页，兼容旧客户端
+	if query.Page > 0 && query.Cursor == "" {
+		params := repository.NewPaginationParams(query.Page, query.Limit)
+		users, err := s.userRepo.List(ctx, params, filter)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.UserListResponse{
+			Users: users,
+			Total: total,
+			Page:  params.Page,
+			Limit: params.Limit,
+		}, nil
+	}
+
+	sortBy, sortOrder := repository.NormalizeUserSort(filter.SortBy, filter.SortOrder)
+	sort := sortBy + " " + sortOrder
+	var after repository.UserSortKey
+	cursor, err := pagination.Decode(query.Cursor, sort, &after)
+	if err != nil {
+		return nil, err
+	}
+
+	scan := *filter
+	scan.SortBy, scan.SortOrder = sortBy, sortOrder
+	var afterKey *repository.UserSortKey
+	if cursor != nil {
+		afterKey = &after
+		if cursor.Backward {
+			// 向前翻页按相反顺序查询
+			scan.SortOrder = "asc"
+			if sortOrder == "asc" {
+				scan.SortOrder = "desc"
+			}
+		}
+	}
+
+	limit := pagination.Limit(int(query.Limit), pagination.DefaultLimit)
+	users, err := s.userRepo.ListAfter(ctx, &scan, afterKey, int64(limit+1))
+	if err != nil {
+		return nil, err
+	}
+	users, page := pagination.Page(users, limit, cursor, sort, func(user *dto.UserResponse) interface{} {
+		return repository.UserSortKeyOf(user, sortBy)
+	})
+
+	return &dto.UserListResponse{
+		Users:    users,
+		Total:    total,
+		Limit:    int64(limit),
+		PageInfo: page,
+	}, nil
+}
+
+// CreateUser 创建用户，用户名或邮箱已存在时返回冲突错误
+func (s *userAdminService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	exists, err := s.userRepo.ExistsByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.NewUsernameExistsError()
+	}
+	exists, err = s.userRepo.ExistsByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.NewEmailExistsError()
+	}
+
+	user, err := s.userRepo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User created", zap.Int64("user_id", user.ID), zap.String("username", user.Username))
+	if s.events != nil {
+		s.events.Publish(ctx, webhook.EventUserCreated, webhook.UserCreatedData{
+			UserID:   user.ID,
+			Username: user.Username,
+			IsAdmin:  user.IsAdmin,
+		})
+	}
+	return user, nil
+}
+
+// DeleteUser 软删除用户并吊销其登录会话
+func (s *userAdminService) DeleteUser(ctx context.Context, id int64) error {
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.refreshTokenRepo.RevokeByUser(ctx, id); err != nil {
+		s.logger.Warn("吊销已删除用户的刷新令牌失败", zap.Int64("user_id", id), zap.Error(err))
+	}
+
+	s.logger.Info("User soft deleted", zap.Int64("user_id", id))
+	return nil
+}
+
+// RestoreUser 恢复已软删除的用户
+func (s *userAdminService) RestoreUser(ctx context.Context, id int64) (*dto.UserResponse, error) {
+	user, err := s.userRepo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User restored", zap.Int64("user_id", id), zap.String("username", user.Username))
+	return user, nil
+}
+
+// PurgeDeletedUsers 永久删除超过保留期的软删除用户
+func (s *userAdminService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.retention).UTC()
+	purged, err := s.userRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		s.logger.Info("Purged soft deleted users", zap.Int64("count", purged), zap.Time("deleted_before", before))
+	}
+	return purged, nil
+}
+
+// UserPurgeJob 定期永久删除软删除用户的后台任务
+type UserPurgeJob struct {
+	userAdminService UserAdminService
+	interval         time.Duration
+	logger           *zap.Logger
+	stop             chan struct{}
+	wg               sync.WaitGroup
+}
+
+// NewUserPurgeJob 创建用户清理任务，interval 不大于0时任务不会启动
+func NewUserPurgeJob(userAdminService UserAdminService, interval time.Duration, logger *zap.Logger) *UserPurgeJob {
+	return &UserPurgeJob{
+		userAdminService: userAdminService,
+		interval:         interval,
+		logger:           logger,
+		stop:             make(chan struct{}),
+	}
+}
+
+// Start 启动清理任务，启动时立即执行一次
+func (j *UserPurgeJob) Start() {
+	if j.interval <= 0 {
+		j.logger.Info("User purge job disabled")
+		return
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run()
+			select {
+			case <-ticker.C:
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+
+	j.logger.Info("User purge job started", zap.Duration("interval", j.interval))
+}
+
+// Stop 停止清理任务并等待当前执行结束
+func (j *UserPurgeJob) Stop() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+	j.wg.Wait()
+}
+
+// run 执行一次清理
+func (j *UserPurgeJob) run() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if _, err := j.userAdminService.PurgeDeletedUsers(ctx); err != nil {
+		j.logger.Error("清理软删除用户失败", zap.Error(err))
+	}
+}
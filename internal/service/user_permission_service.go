@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-springAi/internal/database/generated/user_permissions"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// UserPermissionService 用户权限服务接口
+type UserPermissionService interface {
+	// HasPermission 判断用户是否拥有指定权限
+	HasPermission(ctx context.Context, userID int64, permission string) (bool, error)
+	// ListPermissions 获取用户的所有权限
+	ListPermissions(ctx context.Context, userID int64) ([]dto.UserPermissionResponse, error)
+	// GrantPermission 管理员授予用户权限
+	GrantPermission(ctx context.Context, adminID, userID int64, permission string) error
+	// RevokePermission 管理员撤销用户权限
+	RevokePermission(ctx context.Context, adminID, userID int64, permission string) error
+}
+
+// userPermissionService 用户权限服务实现
+type userPermissionService struct {
+	userRepo       repository.UserRepository
+	permissionRepo repository.UserPermissionRepository
+	events         webhook.Publisher
+	logger         *zap.Logger
+}
+
+// NewUserPermissionService 创建用户权限服务，events 为空时不发布审计事件
+func NewUserPermissionService(repoManager repository.RepositoryManager, events webhook.Publisher, logger *zap.Logger) UserPermissionService {
+	return &userPermissionService{
+		userRepo:       repoManager.User(),
+		permissionRepo: repoManager.UserPermission(),
+		events:         events,
+		logger:         logger,
+	}
+}
+
+// HasPermission 判断用户是否拥有指定权限
+func (s *userPermissionService) HasPermission(ctx context.Context, userID int64, permission string) (bool, error) {
+	has, err := s.permissionRepo.Has(ctx, userID, permission)
+	if err != nil {
+		return false, errors.NewDatabaseError("check user permission", err)
+	}
+	return has, nil
+}
+
+// ListPermissions 获取用户的所有权限
+func (s *userPermissionService) ListPermissions(ctx context.Context, userID int64) ([]dto.UserPermissionResponse, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.permissionRepo.List(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list user permissions", err)
+	}
+
+	result := make([]dto.UserPermissionResponse, 0, len(permissions))
+	for _, p := range permissions {
+		result = append(result, toUserPermissionResponse(p))
+	}
+	return result, nil
+}
+
+// GrantPermission 管理员授予用户权限
+func (s *userPermissionService) GrantPermission(ctx context.Context, adminID, userID int64, permission string) error {
+	permission, err := normalizePermission(permission)
+	if err != nil {
+		return errors.NewValidationError(err.Error())
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.permissionRepo.Grant(ctx, userID, permission, adminID); err != nil {
+		return errors.NewDatabaseError("grant user permission", err)
+	}
+
+	s.logger.Warn("User permission granted",
+		zap.String("audit", "permission"),
+		zap.String("event", "granted"),
+		zap.Int64("admin_id", adminID),
+		zap.Int64("user_id", user.ID),
+		zap.String("username", user.Username),
+		zap.String("permission", permission))
+	if s.events != nil {
+		s.events.Publish(ctx, webhook.EventPermissionGranted, webhook.PermissionChangedData{
+			AdminID:    adminID,
+			UserID:     user.ID,
+			Username:   user.Username,
+			Permission: permission,
+		})
+	}
+	return nil
+}
+
+// RevokePermission 管理员撤销用户权限
+func (s *userPermissionService) RevokePermission(ctx context.Context, adminID, userID int64, permission string) error {
+	permission, err := normalizePermission(permission)
+	if err != nil {
+		return errors.NewValidationError(err.Error())
+	}
+
+	revoked, err := s.permissionRepo.Revoke(ctx, userID, permission)
+	if err != nil {
+		return errors.NewDatabaseError("revoke user permission", err)
+	}
+	if !revoked {
+		return errors.NewNotFoundError("User permission")
+	}
+
+	s.logger.Warn("User permission revoked",
+		zap.String("audit", "permission"),
+		zap.String("event", "revoked"),
+		zap.Int64("admin_id", adminID),
+		zap.Int64("user_id", userID),
+		zap.String("permission", permission))
+	if s.events != nil {
+		s.events.Publish(ctx, webhook.EventPermissionRevoked, webhook.PermissionChangedData{
+			AdminID:    adminID,
+			UserID:     userID,
+			Permission: permission,
+		})
+	}
+	return nil
+}
+
+// normalizePermission 校验权限名称
+func normalizePermission(permission string) (string, error) {
+	permission = strings.ToLower(strings.TrimSpace(permission))
+	for _, known := range dto.Permissions {
+		if permission == known {
+			return permission, nil
+		}
+	}
+	return "", fmt.Errorf("未知的权限: %s（可选: %s）", permission, strings.Join(dto.Permissions, ", "))
+}
+
+// toUserPermissionResponse 转换用户权限为响应模型
+func toUserPermissionResponse(p user_permissions.UserPermission) dto.UserPermissionResponse {
+	resp := dto.UserPermissionResponse{
+		Permission: p.Permission,
+		CreatedAt:  p.CreatedAt.Time,
+	}
+	if p.GrantedBy.Valid {
+		grantedBy := p.GrantedBy.Int64
+		resp.GrantedBy = &grantedBy
+	}
+	return resp
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"go-springAi/internal/database/generated/user_preferences"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// UserPreferenceService 用户偏好设置服务接口
+type UserPreferenceService interface {
+	// Get 获取用户偏好设置，未设置时返回空设置
+	Get(ctx context.Context, userID int64) (*dto.UserPreferences, error)
+	// Update 覆盖保存用户偏好设置
+	Update(ctx context.Context, userID int64, req *dto.UpdateUserPreferencesRequest) (*dto.UserPreferences, error)
+}
+
+// userPreferenceService 用户偏好设置服务实现
+type userPreferenceService struct {
+	preferenceRepo repository.UserPreferenceRepository
+	logger         *zap.Logger
+}
+
+// NewUserPreferenceService 创建用户偏好设置服务
+func NewUserPreferenceService(repoManager repository.RepositoryManager, logger *zap.Logger) UserPreferenceService {
+	return &userPreferenceService{
+		preferenceRepo: repoManager.UserPreference(),
+		logger:         logger,
+	}
+}
+
+// Get 获取用户偏好设置
+func (s *userPreferenceService) Get(ctx context.Context, userID int64) (*dto.UserPreferences, error) {
+	return loadUserPreferences(ctx, s.preferenceRepo, userID)
+}
+
+// Update 覆盖保存用户偏好设置
+func (s *userPreferenceService) Update(ctx context.Context, userID int64, req *dto.UpdateUserPreferencesRequest) (*dto.UserPreferences, error) {
+	params := repository.UpsertUserPreferencesParams{
+		UserID:          userID,
+		DefaultProvider: req.DefaultProvider,
+		DefaultModel:    req.DefaultModel,
+		Locale:          req.Locale,
+		DefaultPeriod:   req.DefaultPeriod,
+	}
+	if req.Temperature != nil {
+		params.Temperature = sql.NullFloat64{Float64: float64(*req.Temperature), Valid: true}
+	}
+
+	prefs, err := s.preferenceRepo.Upsert(ctx, params)
+	if err != nil {
+		return nil, errors.NewDatabaseError("save user preferences", err)
+	}
+
+	s.logger.Info("User preferences updated", zap.Int64("user_id", userID))
+	return toUserPreferences(prefs), nil
+}
+
+// loadUserPreferences 读取用户偏好设置，未设置时返回空设置
+func loadUserPreferences(ctx context.Context, repo repository.UserPreferenceRepository, userID int64) (*dto.UserPreferences, error) {
+	prefs, err := repo.Get(ctx, userID)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrCodeNotFound {
+			return &dto.UserPreferences{}, nil
+		}
+		return nil, errors.NewDatabaseError("get user preferences", err)
+	}
+	return toUserPreferences(prefs), nil
+}
+
+// toUserPreferences 转换为偏好设置响应
+func toUserPreferences(prefs *user_preferences.UserPreference) *dto.UserPreferences {
+	resp := &dto.UserPreferences{
+		DefaultProvider: prefs.DefaultProvider,
+		DefaultModel:    prefs.DefaultModel,
+		Locale:          prefs.Locale,
+		DefaultPeriod:   prefs.DefaultPeriod,
+	}
+	if prefs.Temperature.Valid {
+		temperature := float32(prefs.Temperature.Float64)
+		resp.Temperature = &temperature
+	}
+	return resp
+}
+
+// applyChatPreferences 用用户偏好补全请求中未指定的提供商、模型和温度
+func applyChatPreferences(req *ChatRequest, prefs *dto.UserPreferences) {
+	if prefs == nil {
+		return
+	}
+	if req.Provider == "" && req.Model == "" {
+		req.Provider = prefs.DefaultProvider
+		req.Model = prefs.DefaultModel
+	} else if req.Model == "" && req.Provider == prefs.DefaultProvider {
+		// 仅在指定的提供商与偏好一致时补全模型，避免模型与提供商不匹配
+		req.Model = prefs.DefaultModel
+	}
+	if req.Temperature == nil && prefs.Temperature != nil {
+		temperature := *prefs.Temperature
+		req.Temperature = &temperature
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"go-springAi/internal/dto"
+)
+
+func TestApplyChatPreferences(t *testing.T) {
+	temperature := float32(0.3)
+	explicit := float32(0.9)
+	prefs := &dto.UserPreferences{DefaultProvider: "openai", DefaultModel: "gpt-4o", Temperature: &temperature}
+
+	tests := []struct {
+		name         string
+		req          ChatRequest
+		wantProvider string
+		wantModel    string
+		wantTemp     float32
+	}{
+		{name: "All omitted", req: ChatRequest{}, wantProvider: "openai", wantModel: "gpt-4o", wantTemp: 0.3},
+		{name: "Explicit model keeps auto provider", req: ChatRequest{Model: "gemini-1.5-flash"}, wantModel: "gemini-1.5-flash", wantTemp: 0.3},
+		{name: "Same provider fills model", req: ChatRequest{Provider: "openai"}, wantProvider: "openai", wantModel: "gpt-4o", wantTemp: 0.3},
+		{name: "Other provider keeps model empty", req: ChatRequest{Provider: "googleai"}, wantProvider: "googleai", wantTemp: 0.3},
+		{name: "Explicit temperature wins", req: ChatRequest{Temperature: &explicit}, wantProvider: "openai", wantModel: "gpt-4o", wantTemp: 0.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			applyChatPreferences(&req, prefs)
+			if req.Provider != tt.wantProvider || req.Model != tt.wantModel {
+				t.Errorf("expected %s/%s, got %s/%s", tt.wantProvider, tt.wantModel, req.Provider, req.Model)
+			}
+			if req.Temperature == nil || *req.Temperature != tt.wantTemp {
+				t.Errorf("expected temperature %v, got %v", tt.wantTemp, req.Temperature)
+			}
+		})
+	}
+}
+
+func TestApplyChatPreferencesNil(t *testing.T) {
+	req := ChatRequest{Model: "gpt-4o"}
+	applyChatPreferences(&req, nil)
+	if req.Model != "gpt-4o" || req.Provider != "" || req.Temperature != nil {
+		t.Errorf("nil preferences should leave the request unchanged, got %+v", req)
+	}
+}
@@ -64,7 +64,7 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (*dto.UserRe
 // List 获取用户列表
 func (s *userService) List(ctx context.Context, page, limit int64) ([]*dto.UserResponse, error) {
 	params := repository.NewPaginationParams(page, limit)
-	return s.userRepo.List(ctx, params)
+	return s.userRepo.List(ctx, params, nil)
 }
 
 // Update 更新用户
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"go-springAi/internal/mcp/tools"
+)
+
+// NewSearchKeyResolver 创建网页搜索工具的密钥获取函数：优先使用调用者通过 API 密钥接口
+// 以后端名称（brave、bing、serpapi）保存的密钥，其次使用配置文件中的密钥
+func NewSearchKeyResolver(apiKeys APIKeyService, fallback map[string]string) tools.SearchKeyFunc {
+	return func(ctx context.Context, backend string) (string, error) {
+		if userID, err := strconv.ParseInt(getUserIDFromContext(ctx), 10, 64); err == nil && apiKeys != nil {
+			exists, err := apiKeys.CheckAPIKeyExists(ctx, userID, 0, backend)
+			if err != nil {
+				return "", err
+			}
+			if exists {
+				return apiKeys.GetAPIKey(ctx, userID, 0, backend)
+			}
+		}
+		return fallback[backend], nil
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchKeyService 按 "用户ID/提供商类型" 保存密钥
+type fakeSearchKeyService struct {
+	APIKeyService
+	keys map[string]string
+}
+
+func (f *fakeSearchKeyService) CheckAPIKeyExists(ctx context.Context, userID, projectID int64, providerType string) (bool, error) {
+	_, ok := f.keys[fmt.Sprintf("%d/%s", userID, providerType)]
+	return ok, nil
+}
+
+func (f *fakeSearchKeyService) GetAPIKey(ctx context.Context, userID, projectID int64, providerType string) (string, error) {
+	return f.keys[fmt.Sprintf("%d/%s", userID, providerType)], nil
+}
+
+func TestSearchKeyResolver(t *testing.T) {
+	resolve := NewSearchKeyResolver(&fakeSearchKeyService{keys: map[string]string{"7/brave": "user-key"}},
+		map[string]string{"brave": "config-key"})
+	tests := []struct {
+		name    string
+		userID  string
+		backend string
+		want    string
+	}{
+		{name: "caller key wins", userID: "7", backend: "brave", want: "user-key"},
+		{name: "other user falls back to config", userID: "8", backend: "brave", want: "config-key"},
+		{name: "anonymous falls back to config", backend: "brave", want: "config-key"},
+		{name: "nothing configured", userID: "7", backend: "bing", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.userID != "" {
+				ctx = WithCaller(ctx, tt.userID)
+			}
+			key, err := resolve(ctx, tt.backend)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-springAi/internal/database/generated/webhooks"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/errors"
+	"go-springAi/internal/repository"
+	"go-springAi/internal/webhook"
+
+	"go.uber.org/zap"
+)
+
+// 投递记录查询条数
+const (
+	DefaultWebhookDeliveryLimit = 50
+	MaxWebhookDeliveryLimit     = 200
+)
+
+// WebhookDispatcher 投递测试事件和重新投递时用到的投递器方法
+type WebhookDispatcher interface {
+	Enabled() bool
+	Enqueue(ctx context.Context, endpointID int64, eventType string, data interface{}) (*webhooks.WebhookDelivery, error)
+	Wake()
+}
+
+// WebhookService Webhook端点管理服务接口
+type WebhookService interface {
+	// List 获取所有端点及可订阅的事件类型
+	List(ctx context.Context) (*dto.WebhookEndpointListResponse, error)
+	// Create 创建端点，响应中包含签名密钥
+	Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookEndpointResponse, error)
+	// Update 更新端点，轮换密钥时响应中包含新密钥
+	Update(ctx context.Context, id int64, req *dto.UpdateWebhookRequest) (*dto.WebhookEndpointResponse, error)
+	// Delete 删除端点及其投递记录
+	Delete(ctx context.Context, id int64) error
+	// ListDeliveries 获取端点最近的投递记录，limit 为 0 时使用默认条数
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]dto.WebhookDeliveryResponse, error)
+	// Ping 向端点投递一个测试事件
+	Ping(ctx context.Context, endpointID int64) (*dto.WebhookDeliveryResponse, error)
+	// Redeliver 重新投递端点的一条投递记录
+	Redeliver(ctx context.Context, endpointID, deliveryID int64) error
+}
+
+// webhookService Webhook端点管理服务实现
+type webhookService struct {
+	repo       repository.WebhookRepository
+	dispatcher WebhookDispatcher
+	logger     *zap.Logger
+}
+
+// NewWebhookService 创建Webhook端点管理服务
+func NewWebhookService(repoManager repository.RepositoryManager, dispatcher WebhookDispatcher, logger *zap.Logger) WebhookService {
+	return &webhookService{
+		repo:       repoManager.Webhook(),
+		dispatcher: dispatcher,
+		logger:     logger,
+	}
+}
+
+// List 获取所有端点及可订阅的事件类型
+func (s *webhookService) List(ctx context.Context) (*dto.WebhookEndpointListResponse, error) {
+	endpoints, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list webhook endpoints", err)
+	}
+
+	result := &dto.WebhookEndpointListResponse{
+		Endpoints:  make([]dto.WebhookEndpointResponse, 0, len(endpoints)),
+		EventTypes: webhook.EventTypes,
+	}
+	for i := range endpoints {
+		result.Endpoints = append(result.Endpoints, toWebhookEndpointResponse(&endpoints[i], false))
+	}
+	return result, nil
+}
+
+// Create 创建端点
+func (s *webhookService) Create(ctx context.Context, req *dto.CreateWebhookRequest) (*dto.WebhookEndpointResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = webhook.GenerateSecret(); err != nil {
+			return nil, errors.NewInternalError("Failed to generate webhook secret").WithCause(err)
+		}
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	endpoint, err := s.repo.CreateEndpoint(ctx, webhooks.CreateWebhookEndpointParams{
+		Url:         req.URL,
+		Secret:      secret,
+		EventTypes:  webhook.FormatEventTypes(eventTypes),
+		Description: strings.TrimSpace(req.Description),
+		Enabled:     enabled,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create webhook endpoint", err)
+	}
+
+	s.logger.Info("Webhook endpoint created",
+		zap.Int64("endpoint_id", endpoint.ID),
+		zap.String("url", endpoint.Url),
+		zap.Strings("event_types", eventTypes))
+
+	resp := toWebhookEndpointResponse(endpoint, true)
+	return &resp, nil
+}
+
+// Update 更新端点
+func (s *webhookService) Update(ctx context.Context, id int64, req *dto.UpdateWebhookRequest) (*dto.WebhookEndpointResponse, error) {
+	endpoint, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := webhooks.UpdateWebhookEndpointParams{
+		ID:          endpoint.ID,
+		Url:         endpoint.Url,
+		Secret:      endpoint.Secret,
+		EventTypes:  endpoint.EventTypes,
+		Description: endpoint.Description,
+		Enabled:     endpoint.Enabled,
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		params.Url = *req.URL
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		params.EventTypes = webhook.FormatEventTypes(eventTypes)
+	}
+	if req.Description != nil {
+		params.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Enabled != nil {
+		params.Enabled = *req.Enabled
+	}
+	if req.RotateSecret {
+		if params.Secret, err = webhook.GenerateSecret(); err != nil {
+			return nil, errors.NewInternalError("Failed to generate webhook secret").WithCause(err)
+		}
+	}
+
+	updated, err := s.repo.UpdateEndpoint(ctx, params)
+	if err != nil {
+		if _, ok := errors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, errors.NewDatabaseError("update webhook endpoint", err)
+	}
+
+	s.logger.Info("Webhook endpoint updated",
+		zap.Int64("endpoint_id", updated.ID),
+		zap.Bool("enabled", updated.Enabled),
+		zap.Bool("secret_rotated", req.RotateSecret))
+
+	resp := toWebhookEndpointResponse(updated, req.RotateSecret)
+	return &resp, nil
+}
+
+// Delete 删除端点及其投递记录
+func (s *webhookService) Delete(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteEndpoint(ctx, id)
+	if err != nil {
+		return errors.NewDatabaseError("delete webhook endpoint", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("Webhook endpoint")
+	}
+
+	s.logger.Info("Webhook endpoint deleted", zap.Int64("endpoint_id", id))
+	return nil
+}
+
+// ListDeliveries 获取端点最近的投递记录
+func (s *webhookService) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]dto.WebhookDeliveryResponse, error) {
+	if limit <= 0 {
+		limit = DefaultWebhookDeliveryLimit
+	}
+	if limit > MaxWebhookDeliveryLimit {
+		limit = MaxWebhookDeliveryLimit
+	}
+	if _, err := s.repo.GetEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, endpointID, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list webhook deliveries", err)
+	}
+
+	result := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		result = append(result, toWebhookDeliveryResponse(&deliveries[i]))
+	}
+	return result, nil
+}
+
+// Ping 向端点投递一个测试事件
+func (s *webhookService) Ping(ctx context.Context, endpointID int64) (*dto.WebhookDeliveryResponse, error) {
+	if !s.dispatcher.Enabled() {
+		return nil, errors.NewServiceUnavailableError("webhook delivery")
+	}
+	endpoint, err := s.repo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.dispatcher.Enqueue(ctx, endpoint.ID, webhook.EventPing, map[string]interface{}{
+		"endpoint_id": endpoint.ID,
+		"url":         endpoint.Url,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("create webhook delivery", err)
+	}
+
+	resp := toWebhookDeliveryResponse(delivery)
+	return &resp, nil
+}
+
+// Redeliver 重新投递端点的一条投递记录，尝试次数从头计算
+func (s *webhookService) Redeliver(ctx context.Context, endpointID, deliveryID int64) error {
+	if !s.dispatcher.Enabled() {
+		return errors.NewServiceUnavailableError("webhook delivery")
+	}
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.EndpointID != endpointID {
+		return errors.NewNotFoundError("Webhook delivery")
+	}
+	if delivery.Status == webhook.StatusPending {
+		return errors.NewConflictError("Webhook delivery is already pending")
+	}
+
+	if _, err := s.repo.RequeueDelivery(ctx, deliveryID, time.Now().UTC()); err != nil {
+		return errors.NewDatabaseError("requeue webhook delivery", err)
+	}
+	s.dispatcher.Wake()
+
+	s.logger.Info("Webhook delivery requeued",
+		zap.Int64("endpoint_id", endpointID),
+		zap.Int64("delivery_id", deliveryID))
+	return nil
+}
+
+// validateWebhookURL 端点地址只允许 http 和 https
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.NewValidationError("Webhook URL must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes 去除重复并校验订阅的事件类型
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	seen := make(map[string]bool, len(eventTypes))
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if !webhook.ValidEventType(eventType) {
+			return nil, errors.NewValidationError(fmt.Sprintf("unknown webhook event type %q", eventType)).
+				WithDetails(fmt.Sprintf("available event types: %s", strings.Join(webhook.EventTypes, ", ")))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			result = append(result, eventType)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.NewValidationError("at least one webhook event type is required")
+	}
+	return result, nil
+}
+
+// toWebhookEndpointResponse 转换端点信息，withSecret 为 true 时返回完整密钥
+func toWebhookEndpointResponse(e *webhooks.WebhookEndpoint, withSecret bool) dto.WebhookEndpointResponse {
+	resp := dto.WebhookEndpointResponse{
+		ID:          e.ID,
+		URL:         e.Url,
+		EventTypes:  webhook.ParseEventTypes(e.EventTypes),
+		Description: e.Description,
+		Enabled:     e.Enabled,
+		CreatedAt:   e.CreatedAt.Time,
+		UpdatedAt:   e.UpdatedAt.Time,
+	}
+	if len(e.Secret) > 4 {
+		resp.SecretHint = e.Secret[len(e.Secret)-4:]
+	}
+	if withSecret {
+		resp.Secret = e.Secret
+	}
+	return resp
+}
+
+// toWebhookDeliveryResponse 转换投递记录
+func toWebhookDeliveryResponse(d *webhooks.WebhookDelivery) dto.WebhookDeliveryResponse {
+	resp := dto.WebhookDeliveryResponse{
+		ID:           d.ID,
+		EndpointID:   d.EndpointID,
+		EventID:      d.EventID,
+		EventType:    d.EventType,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		ResponseCode: d.ResponseCode,
+		Error:        d.Error,
+		CreatedAt:    d.CreatedAt.Time,
+		UpdatedAt:    d.UpdatedAt.Time,
+	}
+	if json.Valid([]byte(d.Payload)) {
+		resp.Payload = json.RawMessage(d.Payload)
+	}
+	if d.Status == webhook.StatusPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
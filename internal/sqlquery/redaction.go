@@ -0,0 +1,53 @@
+package sqlquery
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// ErrRedacted 查询引用了被脱敏的列
+var ErrRedacted = errors.New("sqlquery: column is redacted")
+
+// RedactedValue 被脱敏列的替代值
+const RedactedValue = "[REDACTED]"
+
+// Redaction 列脱敏规则，按列名匹配（不区分大小写），支持 * 和 ? 通配符，例如 "email"、"*_ssn"、"card_*"
+type Redaction struct {
+	patterns []string
+}
+
+// NewRedaction 创建脱敏规则，规则无效时返回错误
+func NewRedaction(patterns []string) (*Redaction, error) {
+	r := &Redaction{}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.New("sqlquery: invalid redaction pattern " + pattern)
+		}
+		r.patterns = append(r.patterns, pattern)
+	}
+	return r, nil
+}
+
+// Empty 是否没有任何规则
+func (r *Redaction) Empty() bool {
+	return r == nil || len(r.patterns) == 0
+}
+
+// Match 列名是否需要脱敏
+func (r *Redaction) Match(column string) bool {
+	if r.Empty() {
+		return false
+	}
+	column = strings.ToLower(column)
+	for _, pattern := range r.patterns {
+		if matched, _ := path.Match(pattern, column); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+// Package sqlquery 对配置的外部业务数据库执行只读分析查询。每个数据源使用独立的连接，
+// 与应用自身的数据库无关；查询先经过只读语句检查，再在只读事务中执行并始终回滚，
+// 结果受行数、时间和单元格长度限制，匹配脱敏规则的列不会返回原值
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// 支持的驱动
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite3"
+)
+
+// 默认限制
+const (
+	DefaultMaxRows = 500
+	DefaultTimeout = 15 * time.Second
+	// maxCellRunes 单个单元格返回的最大字符数
+	maxCellRunes = 2000
+)
+
+// ErrUnknownDatabase 数据源不存在
+var ErrUnknownDatabase = errors.New("sqlquery: unknown database")
+
+// Source 数据源配置
+type Source struct {
+	Name        string
+	Driver      string // postgres / mysql / sqlite3
+	DSN         string // 建议使用只有只读权限的数据库账号
+	Description string // 提供给模型的数据源说明
+	Redact      []string
+}
+
+// Config 查询配置
+type Config struct {
+	Sources []Source
+	MaxRows int
+	Timeout time.Duration
+}
+
+// Database 数据源信息，不包含连接串
+type Database struct {
+	Name        string
+	Driver      string
+	Description string
+}
+
+// Column 表结构中的列
+type Column struct {
+	Table    string
+	Name     string
+	Type     string
+	Redacted bool
+}
+
+// Result 查询结果
+type Result struct {
+	Columns   []string
+	Rows      [][]interface{}
+	Truncated bool     // 结果行数超过上限，只返回了前 MaxRows 行
+	Redacted  []string // 被脱敏的结果列
+	Duration  time.Duration
+}
+
+type source struct {
+	Source
+	db        *sql.DB
+	redaction *Redaction
+}
+
+// Manager 管理外部数据源
+type Manager struct {
+	sources map[string]*source
+	names   []string
+	maxRows int
+	timeout time.Duration
+}
+
+// New 创建数据源管理器。连接在首次查询时建立，配置错误在这里返回
+func New(cfg Config) (*Manager, error) {
+	m := &Manager{sources: make(map[string]*source), maxRows: cfg.MaxRows, timeout: cfg.Timeout}
+	if m.maxRows <= 0 {
+		m.maxRows = DefaultMaxRows
+	}
+	if m.timeout <= 0 {
+		m.timeout = DefaultTimeout
+	}
+
+	for _, src := range cfg.Sources {
+		if src.Name == "" || src.DSN == "" {
+			m.Close()
+			return nil, errors.New("sqlquery: database name and dsn are required")
+		}
+		if _, exists := m.sources[src.Name]; exists {
+			m.Close()
+			return nil, fmt.Errorf("sqlquery: duplicate database %q", src.Name)
+		}
+		driver, err := parseDriver(src.Driver)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		redaction, err := NewRedaction(src.Redact)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		db, err := open(driver, src.DSN)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("sqlquery: open %s: %w", src.Name, err)
+		}
+		db.SetMaxOpenConns(2)
+		db.SetConnMaxIdleTime(5 * time.Minute)
+
+		src.Driver = driver
+		m.sources[src.Name] = &source{Source: src, db: db, redaction: redaction}
+		m.names = append(m.names, src.Name)
+	}
+	sort.Strings(m.names)
+	return m, nil
+}
+
+// parseDriver 规范化驱动名称
+func parseDriver(driver string) (string, error) {
+	switch strings.ToLower(driver) {
+	case "postgres", "postgresql", "pgx":
+		return DriverPostgres, nil
+	case "mysql", "mariadb":
+		return DriverMySQL, nil
+	case "sqlite", "sqlite3":
+		return DriverSQLite, nil
+	}
+	return "", fmt.Errorf("sqlquery: unsupported driver %q", driver)
+}
+
+// open 打开连接池。SQLite 驱动不支持只读事务，改为在连接上开启 query_only
+func open(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case DriverPostgres:
+		return sql.Open("pgx", dsn)
+	case DriverMySQL:
+		return sql.Open("mysql", dsn)
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return sql.Open("sqlite3", dsn+separator+"_query_only=1")
+}
+
+// Databases 返回按名称排序的数据源
+func (m *Manager) Databases() []Database {
+	databases := make([]Database, 0, len(m.names))
+	for _, name := range m.names {
+		src := m.sources[name]
+		databases = append(databases, Database{Name: src.Name, Driver: src.Driver, Description: src.Description})
+	}
+	return databases
+}
+
+// Names 返回按名称排序的数据源名称
+func (m *Manager) Names() []string {
+	return m.names
+}
+
+// MaxRows 返回单次查询的行数上限
+func (m *Manager) MaxRows() int {
+	return m.maxRows
+}
+
+// Query 执行只读查询，maxRows 为 0 或超过配置上限时使用配置的上限
+func (m *Manager) Query(ctx context.Context, name, query string, maxRows int) (*Result, error) {
+	src, ok := m.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatabase, name)
+	}
+	if err := CheckReadOnly(query, src.Driver, src.redaction); err != nil {
+		return nil, err
+	}
+	if maxRows <= 0 || maxRows > m.maxRows {
+		maxRows = m.maxRows
+	}
+
+	start := time.Now()
+	result := &Result{Columns: []string{}, Rows: [][]interface{}{}}
+	err := m.readOnly(ctx, src, func(ctx context.Context, tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		result.Columns = columns
+		redacted := make([]bool, len(columns))
+		for i, column := range columns {
+			if src.redaction.Match(column) {
+				redacted[i] = true
+				result.Redacted = append(result.Redacted, column)
+			}
+		}
+
+		for rows.Next() {
+			if len(result.Rows) == maxRows {
+				result.Truncated = true
+				break
+			}
+			values := make([]interface{}, len(columns))
+			pointers := make([]interface{}, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				return err
+			}
+			for i := range values {
+				if redacted[i] {
+					values[i] = RedactedValue
+				} else {
+					values[i] = normalizeValue(values[i])
+				}
+			}
+			result.Rows = append(result.Rows, values)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// Schema 返回数据源的表结构，table 非空时只返回该表
+func (m *Manager) Schema(ctx context.Context, name, table string) ([]Column, error) {
+	src, ok := m.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatabase, name)
+	}
+
+	var query string
+	switch src.Driver {
+	case DriverPostgres:
+		query = `SELECT CASE WHEN table_schema = 'public' THEN table_name ELSE table_schema || '.' || table_name END, column_name, data_type
+			FROM information_schema.columns
+			WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+			ORDER BY table_schema, table_name, ordinal_position`
+	case DriverMySQL:
+		query = `SELECT table_name, column_name, column_type
+			FROM information_schema.columns
+			WHERE table_schema = DATABASE()
+			ORDER BY table_name, ordinal_position`
+	default:
+		query = `SELECT m.name, p.name, p.type
+			FROM sqlite_master m JOIN pragma_table_info(m.name) p
+			WHERE m.type IN ('table', 'view') AND m.name NOT LIKE 'sqlite_%'
+			ORDER BY m.name, p.cid`
+	}
+
+	columns := []Column{}
+	err := m.readOnly(ctx, src, func(ctx context.Context, tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var column Column
+			if err := rows.Scan(&column.Table, &column.Name, &column.Type); err != nil {
+				return err
+			}
+			if table != "" && !strings.EqualFold(column.Table, table) {
+				continue
+			}
+			column.Redacted = src.redaction.Match(column.Name)
+			columns = append(columns, column)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// readOnly 在带超时的只读事务中执行 fn，事务始终回滚
+func (m *Manager) readOnly(ctx context.Context, src *source, fn func(ctx context.Context, tx *sql.Tx) error) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	tx, err := src.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: src.Driver != DriverSQLite})
+	if err != nil {
+		return m.wrapTimeout(ctx, err)
+	}
+	defer tx.Rollback()
+	return m.wrapTimeout(ctx, fn(ctx, tx))
+}
+
+// wrapTimeout 超时导致的错误统一报告为超时
+func (m *Manager) wrapTimeout(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("sqlquery: query exceeded %s", m.timeout)
+	}
+	return err
+}
+
+// Close 关闭所有连接池
+func (m *Manager) Close() error {
+	var errs []error
+	for _, src := range m.sources {
+		errs = append(errs, src.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// normalizeValue 将驱动返回的值转换为可 JSON 编码的值
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		if !utf8.Valid(v) {
+			return fmt.Sprintf("<binary %d bytes>", len(v))
+		}
+		return truncateCell(string(v))
+	case string:
+		return truncateCell(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// truncateCell 截断过长的单元格
+func truncateCell(s string) string {
+	if utf8.RuneCountInString(s) <= maxCellRunes {
+		return s
+	}
+	return string([]rune(s)[:maxCellRunes]) + "…"
+}
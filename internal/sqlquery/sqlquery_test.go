@@ -0,0 +1,122 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	redaction, err := NewRedaction([]string{"email", "*_ssn"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		driver  string
+		query   string
+		wantErr error
+	}{
+		{"select", DriverPostgres, "SELECT region, SUM(amount) FROM orders GROUP BY region;", nil},
+		{"cte", DriverPostgres, "WITH t AS (SELECT 1 AS n) SELECT n FROM t", nil},
+		{"parenthesized", DriverSQLite, "(SELECT 1) UNION (SELECT 2)", nil},
+		{"keywords inside strings", DriverPostgres, "SELECT 'delete; drop table x' AS note -- insert\n", nil},
+		{"dollar quoted string", DriverPostgres, "SELECT $$ ; delete $$", nil},
+		{"quoted identifier named like a keyword", DriverPostgres, `SELECT "update" FROM audit`, nil},
+		{"alias named like a redacted column", DriverPostgres, "SELECT name AS email FROM users", nil},
+		{"insert", DriverPostgres, "INSERT INTO t VALUES (1)", ErrNotReadOnly},
+		{"stacked statements", DriverMySQL, "SELECT 1; DROP TABLE users", ErrNotReadOnly},
+		{"writable cte", DriverPostgres, "WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d", ErrNotReadOnly},
+		{"select into", DriverPostgres, "SELECT * INTO backup FROM t", ErrNotReadOnly},
+		{"into outfile", DriverMySQL, "SELECT * FROM t INTO OUTFILE '/tmp/x'", ErrNotReadOnly},
+		{"for update", DriverPostgres, "SELECT * FROM t FOR UPDATE", ErrNotReadOnly},
+		{"for share", DriverPostgres, "SELECT * FROM t FOR SHARE", ErrNotReadOnly},
+		{"side effect function", DriverPostgres, "SELECT pg_sleep(100)", ErrNotReadOnly},
+		{"executable comment", DriverMySQL, "SELECT /*! 1; DROP TABLE t */ 1", ErrNotReadOnly},
+		{"pragma", DriverSQLite, "PRAGMA table_info(users)", ErrNotReadOnly},
+		{"redacted column", DriverPostgres, "SELECT id FROM users WHERE email LIKE '%@x.com'", ErrRedacted},
+		{"redacted column via alias", DriverPostgres, `SELECT "Tax_SSN" AS x FROM users`, ErrRedacted},
+		{"whole row json", DriverPostgres, "SELECT row_to_json(u) FROM users u", ErrRedacted},
+		// MySQL 中反斜杠转义引号，ssn 在字符串外
+		{"mysql backslash escape", DriverMySQL, `SELECT 'a\'' , tax_ssn -- '`, ErrRedacted},
+		// PostgreSQL 普通字符串中反斜杠不是转义
+		{"postgres standard string", DriverPostgres, `SELECT 'C:\' AS path, tax_ssn`, ErrRedacted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReadOnly(tt.query, tt.driver, redaction)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManagerSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sales.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT, email TEXT);
+		INSERT INTO customers (name, email) VALUES ('alice', 'alice@example.com'), ('bob', 'bob@example.com'), ('carol', NULL);`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	m, err := New(Config{
+		Sources: []Source{{Name: "sales", Driver: "sqlite", DSN: path, Description: "CRM", Redact: []string{"email"}}},
+		MaxRows: 2,
+	})
+	require.NoError(t, err)
+	defer m.Close()
+
+	assert.Equal(t, []Database{{Name: "sales", Driver: DriverSQLite, Description: "CRM"}}, m.Databases())
+
+	result, err := m.Query(context.Background(), "sales", "SELECT id, name FROM customers ORDER BY id", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{int64(1), "alice"}, {int64(2), "bob"}}, result.Rows)
+	assert.True(t, result.Truncated)
+
+	// SELECT * 返回的脱敏列被替换
+	result, err = m.Query(context.Background(), "sales", "SELECT * FROM customers WHERE id = 1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{int64(1), "alice", RedactedValue}}, result.Rows)
+	assert.Equal(t, []string{"email"}, result.Redacted)
+	assert.False(t, result.Truncated)
+
+	_, err = m.Query(context.Background(), "sales", "SELECT email FROM customers", 0)
+	assert.ErrorIs(t, err, ErrRedacted)
+	_, err = m.Query(context.Background(), "sales", "DELETE FROM customers", 0)
+	assert.ErrorIs(t, err, ErrNotReadOnly)
+	_, err = m.Query(context.Background(), "crm", "SELECT 1", 0)
+	assert.ErrorIs(t, err, ErrUnknownDatabase)
+
+	// 即使绕过语句检查，连接本身也是只读的
+	src := m.sources["sales"]
+	err = m.readOnly(context.Background(), src, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM customers")
+		return err
+	})
+	assert.Error(t, err)
+
+	columns, err := m.Schema(context.Background(), "sales", "CUSTOMERS")
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Table: "customers", Name: "id", Type: "INTEGER"},
+		{Table: "customers", Name: "name", Type: "TEXT"},
+		{Table: "customers", Name: "email", Type: "TEXT", Redacted: true},
+	}, columns)
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(Config{Sources: []Source{{Name: "a", Driver: "oracle", DSN: "x"}}})
+	assert.Error(t, err)
+	_, err = New(Config{Sources: []Source{{Name: "a", Driver: "sqlite", DSN: "x"}, {Name: "a", Driver: "sqlite", DSN: "y"}}})
+	assert.Error(t, err)
+	_, err = New(Config{Sources: []Source{{Name: "a", Driver: "sqlite", DSN: "x", Redact: []string{"[x"}}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotReadOnly 语句不是单条只读查询
+var ErrNotReadOnly = errors.New("sqlquery: only a single SELECT statement is allowed")
+
+// forbiddenKeywords 只读查询中不允许出现的关键字。查询本身还在只读事务中执行，这里提前拒绝
+// PostgreSQL 可写 CTE、SELECT INTO、MySQL INTO OUTFILE、加锁读以及各类 DDL
+var forbiddenKeywords = map[string]bool{
+	"insert": true, "update": true, "delete": true, "merge": true, "into": true,
+	"outfile": true, "dumpfile": true, "lock": true, "create": true, "drop": true,
+	"alter": true, "truncate": true, "grant": true, "revoke": true, "copy": true,
+	"call": true, "execute": true, "attach": true, "detach": true, "pragma": true, "vacuum": true,
+}
+
+// forbiddenFunctions 有副作用、可读取服务器文件或用于拖延执行的函数
+var forbiddenFunctions = map[string]bool{
+	"pg_read_file": true, "pg_read_binary_file": true, "pg_ls_dir": true, "pg_stat_file": true,
+	"pg_sleep": true, "pg_sleep_for": true, "pg_sleep_until": true, "pg_terminate_backend": true,
+	"pg_cancel_backend": true, "pg_reload_conf": true, "lo_import": true, "lo_export": true,
+	"dblink": true, "dblink_exec": true, "set_config": true, "nextval": true, "setval": true,
+	"load_file": true, "sleep": true, "benchmark": true, "get_lock": true,
+	"load_extension": true, "readfile": true, "writefile": true,
+}
+
+// rowFunctions 将整行或多列打包为一个值的函数，存在脱敏规则时禁止使用，避免绕过按列名脱敏
+var rowFunctions = map[string]bool{
+	"row_to_json": true, "to_json": true, "to_jsonb": true, "json_agg": true, "jsonb_agg": true,
+	"json_object": true, "json_objectagg": true, "json_arrayagg": true, "row": true, "hstore": true,
+}
+
+// token 词法单元，字符串和注释已被跳过
+type token struct {
+	text  string // 标识符和关键字为小写，带引号的标识符去掉引号；字符串字面量为 "'"
+	ident bool   // 是否为标识符或关键字
+	quote bool   // 是否为带引号的标识符
+}
+
+// tokenize 按驱动的字符串转义规则将 SQL 切分为词法单元，跳过字符串字面量和注释；
+// 分号之后还有内容时返回 ErrNotReadOnly
+func tokenize(query, driver string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '\f':
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 1
+			}
+		case strings.HasPrefix(query[i:], "/*!"):
+			// MySQL 会执行这种注释中的内容
+			return nil, fmt.Errorf("%w: executable comments are not allowed", ErrNotReadOnly)
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("sqlquery: unterminated comment")
+			}
+			i += end + 4
+		case ch == '\'' || (ch == '"' && driver == DriverMySQL):
+			// MySQL 字符串始终支持反斜杠转义，PostgreSQL 只有 E'...' 字符串支持
+			backslash := driver == DriverMySQL ||
+				(driver == DriverPostgres && len(tokens) > 0 && tokens[len(tokens)-1].text == "e" && i > 0 && (query[i-1] == 'e' || query[i-1] == 'E'))
+			end, err := closingQuote(query, i, ch, backslash)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{text: "'"})
+			i = end + 1
+		case ch == '"' || ch == '`':
+			end, err := closingQuote(query, i, ch, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{text: strings.ToLower(query[i+1 : end]), ident: true, quote: true})
+			i = end + 1
+		case ch == '$' && driver == DriverPostgres && dollarTag(query[i:]) != "":
+			// PostgreSQL 美元符号引用的字符串
+			tag := dollarTag(query[i:])
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				return nil, errors.New("sqlquery: unterminated dollar-quoted string")
+			}
+			tokens = append(tokens, token{text: "'"})
+			i += len(tag) + end + len(tag)
+		case isIdentStart(ch):
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			tokens = append(tokens, token{text: strings.ToLower(query[i:j]), ident: true})
+			i = j
+		case ch == ';':
+			rest, err := tokenize(query[i+1:], driver)
+			if err != nil || len(rest) > 0 {
+				return nil, ErrNotReadOnly
+			}
+			i = len(query)
+		default:
+			tokens = append(tokens, token{text: string(ch)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// closingQuote 返回与 start 处引号配对的结束引号位置，连续两个引号表示转义，
+// backslash 为 true 时反斜杠转义下一个字符
+func closingQuote(query string, start int, closing byte, backslash bool) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case backslash && query[i] == '\\':
+			i++
+		case query[i] != closing:
+		case i+1 < len(query) && query[i+1] == closing:
+			i++
+		default:
+			return i, nil
+		}
+	}
+	return 0, errors.New("sqlquery: unterminated quoted string")
+}
+
+// dollarTag 返回 $tag$ 形式的起始标记，不是美元符号引用（例如 $1 占位符）时返回空串
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1]
+		}
+		if !isIdentPart(s[i]) || s[i] == '$' || (i == 1 && s[i] >= '0' && s[i] <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= 0x80
+}
+
+func isIdentPart(ch byte) bool {
+	return isIdentStart(ch) || (ch >= '0' && ch <= '9') || ch == '$'
+}
+
+// CheckReadOnly 检查查询是否为单条只读语句：必须以 SELECT、WITH 或括号开头，且不包含写入、
+// DDL、加锁和有副作用的函数。redaction 非空时，引用被脱敏的列或使用整行打包函数的查询也会被拒绝
+func CheckReadOnly(query, driver string, redaction *Redaction) error {
+	tokens, err := tokenize(query, driver)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("sqlquery: empty query")
+	}
+	if first := tokens[0]; first.text != "(" && (!first.ident || first.quote || (first.text != "select" && first.text != "with")) {
+		return ErrNotReadOnly
+	}
+
+	for i, tok := range tokens {
+		if !tok.ident {
+			continue
+		}
+		prev, next := "", ""
+		if i > 0 {
+			prev = tokens[i-1].text
+		}
+		if i+1 < len(tokens) {
+			next = tokens[i+1].text
+		}
+
+		if !tok.quote {
+			if forbiddenKeywords[tok.text] {
+				return fmt.Errorf("%w: %s is not allowed", ErrNotReadOnly, strings.ToUpper(tok.text))
+			}
+			// FOR SHARE / FOR KEY SHARE 加锁读，FOR UPDATE 已被 update 拦截
+			if tok.text == "share" && (prev == "for" || prev == "key") {
+				return fmt.Errorf("%w: row locking is not allowed", ErrNotReadOnly)
+			}
+		}
+		if next == "(" && forbiddenFunctions[tok.text] {
+			return fmt.Errorf("%w: function %s is not allowed", ErrNotReadOnly, tok.text)
+		}
+
+		if redaction.Empty() || prev == "as" {
+			continue
+		}
+		if next == "(" && rowFunctions[tok.text] {
+			return fmt.Errorf("%w: function %s is not allowed on databases with redacted columns", ErrRedacted, tok.text)
+		}
+		if redaction.Match(tok.text) {
+			return fmt.Errorf("%w: %s", ErrRedacted, tok.text)
+		}
+	}
+	return nil
+}
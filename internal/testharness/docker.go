@@ -0,0 +1,111 @@
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// 使用的镜像，与生产环境支持的最低版本保持一致
+const (
+	PostgresImage = "postgres:16-alpine"
+	RedisImage    = "redis:7-alpine"
+)
+
+// 环境变量指定已有的服务时不再启动容器，便于在CI的 service 容器中运行
+const (
+	EnvPostgresDSN = "TEST_POSTGRES_DSN"
+	EnvRedisAddr   = "TEST_REDIS_ADDR"
+)
+
+// startTimeout 等待容器内服务就绪的时长
+const startTimeout = 60 * time.Second
+
+// StartPostgres 启动一次性的 Postgres 容器并返回 DSN，测试结束时删除容器，
+// Docker 不可用时跳过测试
+func StartPostgres(t testing.TB) string {
+	t.Helper()
+	if dsn := os.Getenv(EnvPostgresDSN); dsn != "" {
+		return dsn
+	}
+
+	const password = "harness"
+	hostPort := runContainer(t, testcontainers.ContainerRequest{
+		Image:        PostgresImage,
+		ExposedPorts: []string{"5432/tcp"},
+		Env:          map[string]string{"POSTGRES_PASSWORD": password, "POSTGRES_DB": "harness"},
+		// 初始化脚本执行期间服务会重启一次，第二次就绪后才接受外部连接
+		WaitingFor: wait.ForAll(
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+			wait.ForListeningPort("5432/tcp"),
+		).WithDeadline(startTimeout),
+	})
+	return fmt.Sprintf("postgres://postgres:%s@%s/harness?sslmode=disable", password, hostPort)
+}
+
+// StartRedis 启动一次性的 Redis 容器并返回地址，测试结束时删除容器，
+// Docker 不可用时跳过测试
+func StartRedis(t testing.TB) string {
+	t.Helper()
+	if addr := os.Getenv(EnvRedisAddr); addr != "" {
+		return addr
+	}
+
+	return runContainer(t, testcontainers.ContainerRequest{
+		Image:        RedisImage,
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor: wait.ForAll(
+			wait.ForLog("Ready to accept connections"),
+			wait.ForListeningPort("6379/tcp"),
+		).WithDeadline(startTimeout),
+	})
+}
+
+// runContainer 启动容器并等待就绪，返回第一个暴露端口映射到本机的 host:port
+func runContainer(t testing.TB, req testcontainers.ContainerRequest) string {
+	t.Helper()
+	skipWithoutDocker(t)
+
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	// 启动失败时容器可能已创建，同样需要删除
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start %s: %v", req.Image, err)
+	}
+
+	hostPort, err := container.PortEndpoint(ctx, nat.Port(req.ExposedPorts[0]), "")
+	if err != nil {
+		t.Fatalf("inspect %s port %s: %v", req.Image, req.ExposedPorts[0], err)
+	}
+	return hostPort
+}
+
+// skipWithoutDocker Docker 未安装或守护进程不可用时跳过测试
+func skipWithoutDocker(t testing.TB) {
+	t.Helper()
+	// 找不到 Docker 配置时 testcontainers 可能 panic
+	defer func() {
+		if r := recover(); r != nil {
+			t.Skipf("docker is not available: %v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	defer provider.Close()
+	if err := provider.Health(context.Background()); err != nil {
+		t.Skipf("docker daemon is not available: %v", err)
+	}
+}
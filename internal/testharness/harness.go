@@ -0,0 +1,248 @@
+// Package testharness 为黑盒API测试启动真实的数据库和Redis容器、执行迁移，
+// 并用 wire 组装完整应用，替代各层零散的 mock 做端到端覆盖
+package testharness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/dto"
+	"go-springAi/internal/wire"
+	"go-springAi/schemas"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/viper"
+)
+
+// Options 测试应用配置
+type Options struct {
+	// Driver 数据库驱动，postgres（默认，启动容器）或 sqlite3（临时文件，无需Docker）
+	Driver string
+	// Redis 为 true 时启动 Redis 容器，限流和幂等存储改用 redis 后端
+	Redis bool
+	// Config 覆盖的配置项，键为点分路径，如 "chaos.enabled"
+	Config map[string]interface{}
+}
+
+// Harness 运行中的测试应用
+type Harness struct {
+	App    *wire.App
+	Server *httptest.Server
+	URL    string // 服务地址，不带结尾斜杠
+
+	t testing.TB
+}
+
+// New 启动依赖、执行迁移并启动完整应用，测试结束时自动关闭。
+// 应用使用全局 viper 和日志器，同一进程内不要并行创建多个 Harness
+func New(t testing.TB, opts Options) *Harness {
+	t.Helper()
+
+	dir := t.TempDir()
+	settings := map[string]interface{}{
+		"server.mode":                  "test",
+		"jwt.secret":                   "testharness-jwt-secret",
+		"admin_ui.enabled":             false,
+		"openai.api_key":               "sk-mock-api-key-for-development-testing-only",
+		"googleai.api_key":             "mock-google-ai-api-key-for-development",
+		"rate_limit.enabled":           false,
+		"idempotency.enabled":          true,
+		"idempotency.backend":          "memory",
+		"database.sqlite.foreign_keys": true,
+	}
+
+	switch opts.Driver {
+	case "", "postgres":
+		settings["database.driver"] = "postgres"
+		settings["database.dsn"] = StartPostgres(t)
+	case "sqlite3":
+		settings["database.driver"] = "sqlite3"
+		settings["database.dsn"] = filepath.Join(dir, "harness.db")
+	default:
+		t.Fatalf("testharness: unsupported driver %q", opts.Driver)
+	}
+	if opts.Redis {
+		addr := StartRedis(t)
+		settings["idempotency.backend"] = "redis"
+		settings["idempotency.redis.addr"] = addr
+		settings["rate_limit.backend"] = "redis"
+		settings["rate_limit.redis.addr"] = addr
+	}
+	for key, value := range opts.Config {
+		settings[key] = value
+	}
+
+	migrate(t, settings["database.driver"].(string), settings["database.dsn"].(string))
+	writeConfig(t, dir, settings)
+
+	// LoadConfig 使用全局 viper，先清除上一个 Harness 留下的配置路径
+	viper.Reset()
+	app, cleanup, err := wire.InitializeApp(dir)
+	if err != nil {
+		t.Fatalf("testharness: initialize app: %v", err)
+	}
+	server := httptest.NewServer(app.Router)
+	t.Cleanup(func() {
+		server.Close()
+		cleanup()
+		viper.Reset()
+	})
+
+	return &Harness{App: app, Server: server, URL: server.URL, t: t}
+}
+
+// migrate 在应用启动前执行全部迁移，避免后台任务访问尚未创建的表
+func migrate(t testing.TB, driver, dsn string) {
+	t.Helper()
+	db, err := database.NewConnection(driver, dsn, database.Options{SQLite: database.SQLiteOptions{ForeignKeys: true}})
+	if err != nil {
+		t.Fatalf("testharness: connect database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, schemas.FS)
+	if err != nil {
+		t.Fatalf("testharness: load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("testharness: migrate: %v", err)
+	}
+}
+
+// writeConfig 把点分路径的配置项写成 dir/config.yaml
+func writeConfig(t testing.TB, dir string, settings map[string]interface{}) {
+	t.Helper()
+	tree := map[string]interface{}{}
+	for key, value := range settings {
+		node := tree
+		parts := strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	data, err := yaml.Marshal(tree)
+	if err != nil {
+		t.Fatalf("testharness: encode config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0o600); err != nil {
+		t.Fatalf("testharness: write config: %v", err)
+	}
+}
+
+// Response 解码后的接口响应
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Decode 把响应的 data 字段解码到 out
+func (r *Response) Decode(out interface{}) error {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.Body, &envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// Do 发送请求，body 不为 nil 时编码为JSON，token 不为空时作为 Bearer 令牌
+func (h *Harness) Do(method, path, token string, body interface{}) *Response {
+	h.t.Helper()
+	return h.Send(h.NewRequest(method, path, token, body))
+}
+
+// NewRequest 构造请求，用于需要设置额外请求头的场景
+func (h *Harness) NewRequest(method, path, token string, body interface{}) *http.Request {
+	h.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("testharness: encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, h.URL+path, reader)
+	if err != nil {
+		h.t.Fatalf("testharness: build request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+// Send 发送请求并读取完整响应
+func (h *Harness) Send(req *http.Request) *Response {
+	h.t.Helper()
+	resp, err := h.Server.Client().Do(req)
+	if err != nil {
+		h.t.Fatalf("testharness: %s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("testharness: read response: %v", err)
+	}
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: data}
+}
+
+// CreateUser 直接通过数据访问层创建用户，admin 为 true 时设为管理员
+func (h *Harness) CreateUser(username, password string, admin bool) *dto.UserResponse {
+	h.t.Helper()
+	ctx := context.Background()
+	user, err := h.App.RepoManager.User().Create(ctx, dto.CreateUserRequest{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: password,
+	})
+	if err != nil {
+		h.t.Fatalf("testharness: create user %s: %v", username, err)
+	}
+	if admin {
+		query := "UPDATE users SET is_admin = ? WHERE id = ?"
+		if h.App.DB.Dialect() == database.DialectPostgres {
+			query = "UPDATE users SET is_admin = $1 WHERE id = $2"
+		}
+		if _, err := h.App.DB.GetConnection().ExecContext(ctx, query, true, user.ID); err != nil {
+			h.t.Fatalf("testharness: grant admin to %s: %v", username, err)
+		}
+		user.IsAdmin = true
+	}
+	return user
+}
+
+// Login 登录并返回访问令牌
+func (h *Harness) Login(username, password string) string {
+	h.t.Helper()
+	resp := h.Do(http.MethodPost, "/api/v1/auth/login", "", dto.LoginRequest{Username: username, Password: password})
+	if resp.Status != http.StatusOK {
+		h.t.Fatalf("testharness: login %s: status %d: %s", username, resp.Status, resp.Body)
+	}
+	var tokens dto.TokenResponse
+	if err := resp.Decode(&tokens); err != nil {
+		h.t.Fatalf("testharness: decode login response: %v", err)
+	}
+	return tokens.AccessToken
+}
@@ -0,0 +1,97 @@
+//go:build integration
+
+package testharness
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"go-springAi/internal/dto"
+	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/sandbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// harnessOptions 默认使用 Postgres 和 Redis 容器，TEST_HARNESS_DRIVER=sqlite3 时无需Docker
+func harnessOptions() Options {
+	if os.Getenv("TEST_HARNESS_DRIVER") == "sqlite3" {
+		return Options{Driver: "sqlite3"}
+	}
+	return Options{Redis: true}
+}
+
+func TestAccountFlow(t *testing.T) {
+	h := New(t, harnessOptions())
+	h.CreateUser("alice", "password123", false)
+	h.CreateUser("root", "password123", true)
+
+	assert.Equal(t, http.StatusUnauthorized, h.Do(http.MethodGet, "/api/v1/preferences", "", nil).Status)
+	alice := h.Login("alice", "password123")
+	assert.Equal(t, http.StatusOK, h.Do(http.MethodGet, "/api/v1/preferences", alice, nil).Status)
+
+	// 管理员端点只对管理员开放
+	assert.Equal(t, http.StatusForbidden, h.Do(http.MethodGet, "/api/v1/admin/users", alice, nil).Status)
+	root := h.Login("root", "password123")
+	resp := h.Do(http.MethodGet, "/api/v1/admin/users", root, nil)
+	require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+	assert.Contains(t, string(resp.Body), `"alice"`)
+}
+
+func TestIdempotentProjectCreation(t *testing.T) {
+	h := New(t, harnessOptions())
+	h.CreateUser("alice", "password123", false)
+	token := h.Login("alice", "password123")
+
+	create := func() *Response {
+		req := h.NewRequest(http.MethodPost, "/api/v1/projects", token, dto.CreateProjectRequest{Name: "reports"})
+		req.Header.Set("Idempotency-Key", "create-reports")
+		return h.Send(req)
+	}
+	first := create()
+	require.Equal(t, http.StatusCreated, first.Status, string(first.Body))
+	second := create()
+	assert.Equal(t, first.Status, second.Status)
+	assert.Equal(t, "true", second.Header.Get("Idempotent-Replayed"))
+
+	var created, replayed dto.ProjectResponse
+	require.NoError(t, first.Decode(&created))
+	require.NoError(t, second.Decode(&replayed))
+	assert.Equal(t, created.ID, replayed.ID)
+
+	var projects []dto.ProjectResponse
+	require.NoError(t, h.Do(http.MethodGet, "/api/v1/projects", token, nil).Decode(&projects))
+	assert.Len(t, projects, 1)
+}
+
+func TestSandboxMode(t *testing.T) {
+	opts := harnessOptions()
+	opts.Config = map[string]interface{}{
+		"sandbox.enabled":      true,
+		"sandbox.fixtures_dir": "../../testdata/sandbox",
+	}
+	h := New(t, opts)
+	t.Cleanup(sandbox.Disable)
+
+	// OpenAI 模型由模拟提供商应答
+	resp := h.Do(http.MethodPost, "/v1/chat/completions", "", map[string]interface{}{
+		"model":    "gpt-3.5-turbo",
+		"messages": []map[string]string{{"role": "user", "content": "hello"}},
+	})
+	require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+	assert.Contains(t, string(resp.Body), `"id":"mock-`)
+
+	// 行情工具从录制文件回放
+	resp = h.Do(http.MethodPost, "/api/v1/mcp/execute", "", dto.MCPExecuteRequest{
+		Name:      tools.YahooFinanceToolName,
+		Arguments: map[string]interface{}{"action": "quote", "symbol": "AAPL"},
+	})
+	require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+	assert.Contains(t, string(resp.Body), "185.64")
+
+	// 其余出站请求不会到达网络
+	_, err := http.Get("https://example.com")
+	assert.ErrorIs(t, err, sandbox.ErrBlocked)
+}
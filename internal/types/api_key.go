@@ -0,0 +1,41 @@
+package types
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrProviderUnauthorized 提供商拒绝了当前 API 密钥（401/403 等鉴权失败）
+var ErrProviderUnauthorized = errors.New("provider rejected API key")
+
+// ErrProviderRateLimited 提供商对当前 API 密钥限流（429）
+var ErrProviderRateLimited = errors.New("provider rate limited API key")
+
+// ErrModelNotAllowed 当前 API 密钥被限制为不能访问请求的模型
+var ErrModelNotAllowed = errors.New("model not allowed for API key")
+
+type apiKeyContextKey struct{}
+
+// WithAPIKey 返回携带指定 API 密钥的上下文，客户端会优先使用该密钥
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext 获取上下文中指定的 API 密钥
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+type allowedModelsContextKey struct{}
+
+// WithAllowedModels 返回携带密钥可用模型限制的上下文，与 WithAPIKey 一起使用
+func WithAllowedModels(ctx context.Context, models []string) context.Context {
+	return context.WithValue(ctx, allowedModelsContextKey{}, models)
+}
+
+// AllowedModelsFromContext 获取上下文中密钥的可用模型，为空表示不限制
+func AllowedModelsFromContext(ctx context.Context) []string {
+	models, _ := ctx.Value(allowedModelsContextKey{}).([]string)
+	return models
+}
@@ -49,6 +49,7 @@ const (
 	ProviderTypeOpenAI   ProviderType = "openai"
 	ProviderTypeGoogleAI ProviderType = "googleai"
 	ProviderTypeMock     ProviderType = "mock"
+	ProviderTypeLoadTest ProviderType = "loadtest"
 )
 
 // CommonErrorResponse 通用错误响应
@@ -70,4 +71,31 @@ type CommonProviderInfo struct {
 	Description string       `json:"description"`
 	Healthy     bool         `json:"healthy"`
 	ModelCount  int          `json:"model_count"`
-}
\ No newline at end of file
+}
+
+// ModelMetadata 模型元数据，由管理员维护并持久化，覆盖在提供商的模型配置之上
+type ModelMetadata struct {
+	ContextWindow  int      `json:"context_window,omitempty"`  // 上下文窗口令牌数，0 表示未知
+	InputPrice     *float64 `json:"input_price,omitempty"`     // 每百万输入令牌的美元价格
+	OutputPrice    *float64 `json:"output_price,omitempty"`    // 每百万输出令牌的美元价格
+	SupportsTools  bool     `json:"supports_tools,omitempty"`  // 支持工具调用
+	SupportsVision bool     `json:"supports_vision,omitempty"` // 支持图像输入
+}
+
+// ModelSyncResult 单个提供商的模型同步结果
+type ModelSyncResult struct {
+	Provider ProviderType `json:"provider"`
+	Remote   int          `json:"remote"`             // 提供商返回的模型数
+	New      []string     `json:"new"`                // 提供商提供但未配置的模型
+	Missing  []string     `json:"missing"`            // 已配置但提供商不再提供的模型
+	Added    []string     `json:"added,omitempty"`    // 以禁用状态新增到本地配置的模型
+	Flagged  []string     `json:"flagged,omitempty"`  // 新标记为不再提供的模型
+	Restored []string     `json:"restored,omitempty"` // 重新出现而取消标记的模型
+	Disabled []string     `json:"disabled,omitempty"` // 因不再提供而被禁用的模型
+	Error    string       `json:"error,omitempty"`
+}
+
+// Changed 同步是否修改了本地模型配置
+func (r ModelSyncResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Flagged) > 0 || len(r.Restored) > 0 || len(r.Disabled) > 0
+}
@@ -0,0 +1,241 @@
+// Package units 物理单位和金融单位的精确换算。线性单位按换算系数计算，温度按仿射变换计算，
+// 利率在不同计息周期之间按复利或单利换算；所有运算使用十进制数
+package units
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Precision 非精确运算（除法、小数次幂、指数、对数）保留的小数位数
+const Precision = 34
+
+// 单位类别
+const (
+	CategoryLength      = "length"
+	CategoryMass        = "mass"
+	CategoryVolume      = "volume"
+	CategoryArea        = "area"
+	CategoryTemperature = "temperature"
+	CategoryRatio       = "ratio" // 小数、百分比、基点
+	CategoryRate        = "rate"  // 不同计息周期的利率，数值为百分比
+)
+
+// Compounding 利率换算方式
+const (
+	CompoundingCompound = "compound" // 复利: (1 + r)^(n_from / n_to) - 1
+	CompoundingSimple   = "simple"   // 单利: r × n_from / n_to
+)
+
+// Unit 单位定义。线性单位的 Factor 为 1 单位等于多少基准单位，
+// 利率单位的 Factor 为每年的计息期数，连续复利为 0
+type Unit struct {
+	Name     string
+	Category string
+	Factor   decimal.Decimal
+}
+
+var (
+	one     = decimal.NewFromInt(1)
+	hundred = decimal.NewFromInt(100)
+	// kelvinOffset 摄氏度零点对应的开尔文温度
+	kelvinOffset = decimal.RequireFromString("273.15")
+	// maxRatePercent 利率换算接受的最大百分比，限制复利指数运算的规模
+	maxRatePercent = decimal.NewFromInt(10000)
+)
+
+// definitions 单位定义，键为规范名称，值为类别、换算系数和别名。
+// 基准单位: 米、千克、升、平方米、小数
+var definitions = map[string]struct {
+	category string
+	factor   string
+	aliases  []string
+}{
+	"m":   {CategoryLength, "1", []string{"meter", "meters", "metre", "metres"}},
+	"km":  {CategoryLength, "1000", []string{"kilometer", "kilometers", "kilometre", "kilometres"}},
+	"cm":  {CategoryLength, "0.01", []string{"centimeter", "centimeters"}},
+	"mm":  {CategoryLength, "0.001", []string{"millimeter", "millimeters"}},
+	"in":  {CategoryLength, "0.0254", []string{"inch", "inches"}},
+	"ft":  {CategoryLength, "0.3048", []string{"foot", "feet"}},
+	"yd":  {CategoryLength, "0.9144", []string{"yard", "yards"}},
+	"mi":  {CategoryLength, "1609.344", []string{"mile", "miles"}},
+	"nmi": {CategoryLength, "1852", []string{"nautical_mile", "nautical_miles"}},
+
+	"kg":        {CategoryMass, "1", []string{"kilogram", "kilograms"}},
+	"g":         {CategoryMass, "0.001", []string{"gram", "grams"}},
+	"mg":        {CategoryMass, "0.000001", []string{"milligram", "milligrams"}},
+	"t":         {CategoryMass, "1000", []string{"tonne", "tonnes", "metric_ton"}},
+	"lb":        {CategoryMass, "0.45359237", []string{"lbs", "pound", "pounds"}},
+	"oz":        {CategoryMass, "0.028349523125", []string{"ounce", "ounces"}},
+	"troy_oz":   {CategoryMass, "0.0311034768", []string{"ozt", "troy_ounce", "troy_ounces"}},
+	"short_ton": {CategoryMass, "907.18474", []string{"ton", "tons", "us_ton"}},
+	"long_ton":  {CategoryMass, "1016.0469088", []string{"imperial_ton"}},
+
+	"l":       {CategoryVolume, "1", []string{"liter", "liters", "litre", "litres"}},
+	"ml":      {CategoryVolume, "0.001", []string{"milliliter", "milliliters"}},
+	"m3":      {CategoryVolume, "1000", []string{"cubic_meter", "cubic_meters"}},
+	"gal":     {CategoryVolume, "3.785411784", []string{"gallon", "gallons", "us_gal"}},
+	"imp_gal": {CategoryVolume, "4.54609", []string{"imperial_gallon", "imperial_gallons"}},
+	"qt":      {CategoryVolume, "0.946352946", []string{"quart", "quarts"}},
+	"pt":      {CategoryVolume, "0.473176473", []string{"pint", "pints"}},
+	"fl_oz":   {CategoryVolume, "0.0295735295625", []string{"fluid_ounce", "fluid_ounces"}},
+	"bbl":     {CategoryVolume, "158.987294928", []string{"barrel", "barrels"}},
+	"cu_ft":   {CategoryVolume, "28.316846592", []string{"ft3", "cubic_foot", "cubic_feet"}},
+
+	"m2":   {CategoryArea, "1", []string{"sq_m", "square_meter", "square_meters"}},
+	"km2":  {CategoryArea, "1000000", []string{"sq_km", "square_kilometer", "square_kilometers"}},
+	"ha":   {CategoryArea, "10000", []string{"hectare", "hectares"}},
+	"acre": {CategoryArea, "4046.8564224", []string{"acres"}},
+	"ft2":  {CategoryArea, "0.09290304", []string{"sq_ft", "square_foot", "square_feet"}},
+	"mi2":  {CategoryArea, "2589988.110336", []string{"sq_mi", "square_mile", "square_miles"}},
+
+	// 温度的换算系数不使用，见 toKelvin/fromKelvin
+	"c": {CategoryTemperature, "1", []string{"celsius", "°c"}},
+	"f": {CategoryTemperature, "1", []string{"fahrenheit", "°f"}},
+	"k": {CategoryTemperature, "1", []string{"kelvin"}},
+
+	"decimal":  {CategoryRatio, "1", []string{"fraction", "ratio"}},
+	"percent":  {CategoryRatio, "0.01", []string{"%", "pct"}},
+	"permille": {CategoryRatio, "0.001", []string{"‰"}},
+	"bp":       {CategoryRatio, "0.0001", []string{"bps", "basis_point", "basis_points"}},
+
+	// 日利率按每年252个交易日计算
+	"daily_rate":      {CategoryRate, "252", []string{"rate_daily"}},
+	"weekly_rate":     {CategoryRate, "52", []string{"rate_weekly"}},
+	"monthly_rate":    {CategoryRate, "12", []string{"rate_monthly"}},
+	"quarterly_rate":  {CategoryRate, "4", []string{"rate_quarterly"}},
+	"semiannual_rate": {CategoryRate, "2", []string{"rate_semiannual"}},
+	"annual_rate":     {CategoryRate, "1", []string{"rate_annual", "apy", "ear"}},
+	"continuous_rate": {CategoryRate, "0", []string{"rate_continuous"}},
+}
+
+// registry 规范名称和别名到单位的映射
+var registry = buildRegistry()
+
+func buildRegistry() map[string]Unit {
+	units := make(map[string]Unit)
+	for name, def := range definitions {
+		unit := Unit{Name: name, Category: def.category, Factor: decimal.RequireFromString(def.factor)}
+		units[name] = unit
+		for _, alias := range def.aliases {
+			units[alias] = unit
+		}
+	}
+	return units
+}
+
+// Lookup 按名称或别名查找单位，不区分大小写，空格和连字符视为下划线
+func Lookup(name string) (Unit, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+	unit, ok := registry[key]
+	return unit, ok
+}
+
+// Names 返回各类别按字母排序的规范单位名称
+func Names() map[string][]string {
+	names := make(map[string][]string)
+	for name, def := range definitions {
+		names[def.category] = append(names[def.category], name)
+	}
+	for _, list := range names {
+		sort.Strings(list)
+	}
+	return names
+}
+
+// Convert 将 value 从 from 单位换算为 to 单位，compounding 只对利率单位有效，默认为复利
+func Convert(value decimal.Decimal, from, to Unit, compounding string) (decimal.Decimal, error) {
+	if from.Category != to.Category {
+		return decimal.Decimal{}, fmt.Errorf("cannot convert %s (%s) to %s (%s)", from.Name, from.Category, to.Name, to.Category)
+	}
+	switch from.Category {
+	case CategoryTemperature:
+		kelvin := toKelvin(value, from.Name)
+		if kelvin.IsNegative() {
+			return decimal.Decimal{}, errors.New("temperature below absolute zero")
+		}
+		return fromKelvin(kelvin, to.Name), nil
+	case CategoryRate:
+		return convertRate(value, from, to, compounding)
+	}
+	return value.Mul(from.Factor).DivRound(to.Factor, Precision), nil
+}
+
+func toKelvin(value decimal.Decimal, unit string) decimal.Decimal {
+	switch unit {
+	case "c":
+		return value.Add(kelvinOffset)
+	case "f":
+		return value.Sub(decimal.NewFromInt(32)).Mul(decimal.NewFromInt(5)).DivRound(decimal.NewFromInt(9), Precision).Add(kelvinOffset)
+	}
+	return value
+}
+
+func fromKelvin(kelvin decimal.Decimal, unit string) decimal.Decimal {
+	switch unit {
+	case "c":
+		return kelvin.Sub(kelvinOffset)
+	case "f":
+		return kelvin.Sub(kelvinOffset).Mul(decimal.NewFromInt(9)).DivRound(decimal.NewFromInt(5), Precision).Add(decimal.NewFromInt(32))
+	}
+	return kelvin
+}
+
+// convertRate 换算不同计息周期的利率，输入和输出均为百分比
+func convertRate(percent decimal.Decimal, from, to Unit, compounding string) (decimal.Decimal, error) {
+	if from.Name == to.Name {
+		return percent, nil
+	}
+	if compounding != "" && compounding != CompoundingCompound && compounding != CompoundingSimple {
+		return decimal.Decimal{}, fmt.Errorf("unknown compounding %q", compounding)
+	}
+	if percent.Abs().GreaterThan(maxRatePercent) {
+		return decimal.Decimal{}, fmt.Errorf("rate must be between -%s%% and %s%%", maxRatePercent, maxRatePercent)
+	}
+	rate := percent.DivRound(hundred, Precision)
+
+	// 连续复利没有计息期数，始终按 e^r 换算
+	if compounding == CompoundingSimple && !from.Factor.IsZero() && !to.Factor.IsZero() {
+		return rate.Mul(from.Factor).DivRound(to.Factor, Precision).Mul(hundred), nil
+	}
+
+	// 先换算为年化实际利率的增长因子 1 + EAR
+	var growth decimal.Decimal
+	if from.Factor.IsZero() {
+		exp, err := rate.ExpTaylor(Precision)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		growth = exp
+	} else {
+		if !one.Add(rate).IsPositive() {
+			return decimal.Decimal{}, errors.New("rate must be greater than -100%")
+		}
+		g, err := one.Add(rate).PowWithPrecision(from.Factor, Precision)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		growth = g
+	}
+
+	var result decimal.Decimal
+	if to.Factor.IsZero() {
+		ln, err := growth.Ln(Precision)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		result = ln
+	} else {
+		g, err := growth.PowWithPrecision(one.DivRound(to.Factor, Precision), Precision)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		result = g.Sub(one)
+	}
+	return result.Mul(hundred).Round(Precision), nil
+}
@@ -0,0 +1,69 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value       string
+		from        string
+		to          string
+		compounding string
+		want        string // 保留 8 位小数
+	}{
+		{"1", "mile", "km", "", "1.609344"},
+		{"12", "in", "ft", "", "1"},
+		{"1", "troy oz", "g", "", "31.1034768"},
+		{"1", "bbl", "gal", "", "42"},
+		{"1", "acre", "ha", "", "0.40468564"},
+		{"100", "C", "°F", "", "212"},
+		{"-40", "fahrenheit", "celsius", "", "-40"},
+		{"0", "K", "C", "", "-273.15"},
+		{"25", "bps", "%", "", "0.25"},
+		{"1.5", "percent", "bp", "", "150"},
+		{"0.035", "decimal", "bps", "", "350"},
+		{"1", "monthly_rate", "annual_rate", "", "12.68250301"},
+		{"12", "annual_rate", "monthly_rate", "", "0.94887929"},
+		{"1", "monthly_rate", "annual_rate", "simple", "12"},
+		{"5", "continuous_rate", "annual_rate", "", "5.12710964"},
+		{"5", "annual_rate", "continuous_rate", "", "4.87901642"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value+" "+tt.from+" to "+tt.to, func(t *testing.T) {
+			from, ok := Lookup(tt.from)
+			require.True(t, ok)
+			to, ok := Lookup(tt.to)
+			require.True(t, ok)
+			got, err := Convert(decimal.RequireFromString(tt.value), from, to, tt.compounding)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Round(8).String())
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	m, _ := Lookup("m")
+	kg, _ := Lookup("kg")
+	c, _ := Lookup("c")
+	monthly, _ := Lookup("monthly_rate")
+	annual, _ := Lookup("annual_rate")
+
+	_, err := Convert(decimal.NewFromInt(1), m, kg, "")
+	assert.ErrorContains(t, err, "cannot convert m (length) to kg (mass)")
+	_, err = Convert(decimal.NewFromInt(-300), c, c, "")
+	assert.ErrorContains(t, err, "absolute zero")
+	_, err = Convert(decimal.NewFromInt(-100), monthly, annual, "")
+	assert.ErrorContains(t, err, "greater than -100%")
+	_, err = Convert(decimal.NewFromInt(1), monthly, annual, "daily")
+	assert.ErrorContains(t, err, "unknown compounding")
+	_, err = Convert(decimal.NewFromInt(20000), monthly, annual, "")
+	assert.ErrorContains(t, err, "rate must be between")
+
+	_, ok := Lookup("furlong")
+	assert.False(t, ok)
+}
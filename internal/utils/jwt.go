@@ -9,11 +9,21 @@ import (
 
 // Claims JWT声明结构
 type Claims struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
+	UserID        int64               `json:"user_id"`
+	Username      string              `json:"username"`
+	Impersonation *ImpersonationClaim `json:"impersonation,omitempty"`
+	AuthTime      *jwt.NumericDate    `json:"auth_time,omitempty"` // 最近一次重新认证的时间
 	jwt.RegisteredClaims
 }
 
+// ImpersonationClaim 模拟登录声明，存在时表示管理员正在以该用户身份操作
+type ImpersonationClaim struct {
+	AdminID       int64  `json:"admin_id"`
+	AdminUsername string `json:"admin_username"`
+	Reason        string `json:"reason"`
+	Banner        string `json:"banner"` // 前端需显著展示的提示文案
+}
+
 // JWTManager JWT管理器
 type JWTManager struct {
 	secretKey  string
@@ -47,6 +57,60 @@ func (j *JWTManager) GenerateToken(userID int64, username string) (string, error
 	return token.SignedString([]byte(j.secretKey))
 }
 
+// GenerateReauthToken 生成携带重新认证时间的访问令牌
+func (j *JWTManager) GenerateReauthToken(userID int64, username string) (string, time.Time, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:   userID,
+		Username: username,
+		AuthTime: jwt.NewNumericDate(now),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.expireTime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "admin-system",
+			Subject:   username,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(j.secretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, now, nil
+}
+
+// GenerateImpersonationToken 生成模拟登录令牌，返回令牌和过期时间
+func (j *JWTManager) GenerateImpersonationToken(userID int64, username string, impersonation ImpersonationClaim, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := &Claims{
+		UserID:        userID,
+		Username:      username,
+		Impersonation: &impersonation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "admin-system",
+			Subject:   username,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(j.secretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ExpireTime 获取访问令牌有效期
+func (j *JWTManager) ExpireTime() time.Duration {
+	return j.expireTime
+}
+
 // ValidateToken 验证JWT令牌
 func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -74,6 +138,11 @@ func (j *JWTManager) RefreshToken(tokenString string) (string, error) {
 		return "", err
 	}
 
+	// 模拟登录令牌不允许续期
+	if claims.Impersonation != nil {
+		return "", errors.New("impersonation tokens cannot be refreshed")
+	}
+
 	// 检查令牌是否即将过期（在过期前30分钟内可以刷新）
 	if time.Until(claims.ExpiresAt.Time) > 30*time.Minute {
 		return "", errors.New("token is not eligible for refresh")
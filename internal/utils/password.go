@@ -12,3 +12,8 @@ func HashPassword(password string) (string, error) {
 	}
 	return string(hashedBytes), nil
 }
+
+// CheckPassword 校验密码是否与哈希匹配
+func CheckPassword(hashedPassword, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
+}
@@ -0,0 +1,286 @@
+// Package vcr 录制外部HTTP调用的响应并在测试或离线模式下回放
+//
+// 录制模式下请求照常发出，响应写入固定目录下的 JSON 文件；回放模式下不发出请求，
+// 直接返回文件中的响应，找不到文件时返回 ErrFixtureNotFound。文件名由请求方法、
+// 去除密钥参数后的URL和请求体决定，因此用真实密钥录制的文件可以用任意密钥回放。
+package vcr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Mode 录制回放模式
+type Mode string
+
+const (
+	ModeOff    Mode = "off"    // 直接发出请求
+	ModeRecord Mode = "record" // 发出请求并覆盖写入响应文件
+	ModeReplay Mode = "replay" // 只从响应文件回放，不访问网络
+)
+
+// ErrFixtureNotFound 回放模式下请求没有对应的响应文件
+var ErrFixtureNotFound = errors.New("vcr: fixture not found")
+
+// redactedParams 不参与文件名计算且不写入文件的查询参数
+var redactedParams = map[string]bool{
+	"apikey":       true,
+	"api_key":      true,
+	"key":          true,
+	"token":        true,
+	"access_token": true,
+}
+
+// maxNameLength 文件名中路径部分的最大长度
+const maxNameLength = 80
+
+// droppedHeaders 不写入文件的响应头
+var droppedHeaders = []string{"Set-Cookie"}
+
+// Options 录制回放配置
+type Options struct {
+	Mode Mode
+	Dir  string // 响应文件目录，按请求域名分子目录
+}
+
+// Recorder 按配置录制或回放请求
+type Recorder struct {
+	opts Options
+}
+
+// defaultRecorder Configure 设置的全局录制器，Transport 未指定录制器时使用
+var defaultRecorder atomic.Pointer[Recorder]
+
+// New 创建录制器
+func New(opts Options) (*Recorder, error) {
+	switch opts.Mode {
+	case "", ModeOff:
+		opts.Mode = ModeOff
+	case ModeRecord, ModeReplay:
+		if opts.Dir == "" {
+			return nil, fmt.Errorf("vcr: fixture directory is required in %s mode", opts.Mode)
+		}
+	default:
+		return nil, fmt.Errorf("vcr: unknown mode %q", opts.Mode)
+	}
+	return &Recorder{opts: opts}, nil
+}
+
+// Configure 设置全局录制器，所有使用 Transport 的客户端随之生效
+func Configure(opts Options) error {
+	recorder, err := New(opts)
+	if err != nil {
+		return err
+	}
+	defaultRecorder.Store(recorder)
+	return nil
+}
+
+// Transport 按录制器的模式处理请求，未启用时透明转发
+type Transport struct {
+	Base     http.RoundTripper // 为空时使用 http.DefaultTransport
+	Recorder *Recorder         // 为空时使用 Configure 设置的全局录制器
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorder := t.Recorder
+	if recorder == nil {
+		recorder = defaultRecorder.Load()
+	}
+	if recorder == nil || recorder.opts.Mode == ModeOff {
+		return base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("vcr: read request body: %w", err)
+		}
+	}
+	path, requestURL := recorder.fixturePath(req, body)
+
+	if recorder.opts.Mode == ModeReplay {
+		return replay(req, path, requestURL)
+	}
+
+	// RoundTripper 不能修改原请求
+	outgoing := req.Clone(req.Context())
+	if body != nil {
+		outgoing.Body = io.NopCloser(bytes.NewReader(body))
+		outgoing.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	resp, err := base.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	return record(resp, path, req.Method, requestURL)
+}
+
+// fixture 响应文件内容
+type fixture struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+// fixtureRequest 录制时的请求，只用于人工查看
+type fixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// fixtureResponse 录制的响应
+type fixtureResponse struct {
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"body_encoding,omitempty"` // 非 UTF-8 响应体为 base64
+}
+
+// fixturePath 计算请求对应的文件路径，并返回去除密钥参数后的URL
+func (r *Recorder) fixturePath(req *http.Request, body []byte) (string, string) {
+	u := *req.URL
+	query := u.Query()
+	for name := range query {
+		if redactedParams[strings.ToLower(name)] {
+			query.Del(name)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.User = nil
+	requestURL := u.String()
+
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + requestURL + "\n"))
+	hash.Write(body)
+	sum := hex.EncodeToString(hash.Sum(nil))[:12]
+
+	name := strings.ToLower(req.Method)
+	if path := sanitize(u.Path); path != "" {
+		name += "_" + path
+	}
+	name += "-" + sum + ".json"
+	return filepath.Join(r.opts.Dir, sanitize(u.Host), name), requestURL
+}
+
+// sanitize 将路径转换为可用作文件名的字符串，过长时截断，唯一性由哈希保证
+func sanitize(s string) string {
+	result := []byte(s)
+	for i, c := range result {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-':
+		default:
+			result[i] = '_'
+		}
+	}
+	if len(result) > maxNameLength {
+		result = result[:maxNameLength]
+	}
+	return strings.Trim(string(result), "_")
+}
+
+// replay 从文件构造响应
+func replay(req *http.Request, path, requestURL string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s %s (%s)", ErrFixtureNotFound, req.Method, requestURL, path)
+		}
+		return nil, fmt.Errorf("vcr: read fixture: %w", err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("vcr: parse fixture %s: %w", path, err)
+	}
+
+	body := []byte(f.Response.Body)
+	if f.Response.BodyEncoding == "base64" {
+		if body, err = base64.StdEncoding.DecodeString(f.Response.Body); err != nil {
+			return nil, fmt.Errorf("vcr: decode fixture body %s: %w", path, err)
+		}
+	}
+	header := f.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.Status, http.StatusText(f.Response.Status)),
+		StatusCode:    f.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// record 读取完整响应写入文件，并返回可再次读取的响应
+func record(resp *http.Response, path, method, requestURL string) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("vcr: read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+
+	header := resp.Header.Clone()
+	for _, name := range droppedHeaders {
+		header.Del(name)
+	}
+	f := fixture{
+		Request:  fixtureRequest{Method: method, URL: requestURL},
+		Response: fixtureResponse{Status: resp.StatusCode, Header: header, Body: string(body)},
+	}
+	if !utf8.Valid(body) {
+		f.Response.Body = base64.StdEncoding.EncodeToString(body)
+		f.Response.BodyEncoding = "base64"
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("vcr: encode fixture: %w", err)
+	}
+
+	// 先写临时文件再重命名，并发录制同一请求时不会留下半个文件
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("vcr: create fixture directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return nil, fmt.Errorf("vcr: write fixture: %w", err)
+	}
+	_, writeErr := tmp.Write(append(data, '\n'))
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmp.Name(), path)
+	}
+	if writeErr != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("vcr: write fixture: %w", writeErr)
+	}
+	return resp, nil
+}
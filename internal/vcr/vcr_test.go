@@ -0,0 +1,119 @@
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{"path":"` + r.URL.Path + `","body":"` + string(body) + `"}`))
+	}))
+
+	recorder, err := New(Options{Mode: ModeRecord, Dir: dir})
+	require.NoError(t, err)
+	recording := &http.Client{Transport: &Transport{Recorder: recorder}}
+
+	requests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		status   int
+		wantBody string
+	}{
+		{name: "GET", method: http.MethodGet, url: "/v8/finance/chart/AAPL?apikey=real", status: http.StatusOK, wantBody: `{"path":"/v8/finance/chart/AAPL","body":""}`},
+		{name: "POST body a", method: http.MethodPost, url: "/v1/chat", body: "a", status: http.StatusCreated, wantBody: `{"path":"/v1/chat","body":"a"}`},
+		{name: "POST body b", method: http.MethodPost, url: "/v1/chat", body: "b", status: http.StatusCreated, wantBody: `{"path":"/v1/chat","body":"b"}`},
+	}
+	do := func(client *http.Client, method, url, body string) (*http.Response, string, error) {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(data), nil
+	}
+
+	for _, tt := range requests {
+		resp, body, err := do(recording, tt.method, server.URL+tt.url, tt.body)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.name)
+		assert.Equal(t, tt.wantBody, body, tt.name)
+	}
+	assert.Equal(t, 3, hits)
+	server.Close()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "real")
+		assert.NotContains(t, string(data), "session=secret")
+	}
+
+	recorder, err = New(Options{Mode: ModeReplay, Dir: dir})
+	require.NoError(t, err)
+	replaying := &http.Client{Transport: &Transport{Recorder: recorder}}
+	for _, tt := range requests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 回放时密钥参数不同也能命中
+			url := strings.Replace(server.URL+tt.url, "apikey=real", "apikey=other", 1)
+			resp, body, err := do(replaying, tt.method, url, tt.body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+
+	_, _, err = do(replaying, http.MethodPost, server.URL+"/v1/chat", "c")
+	assert.ErrorIs(t, err, ErrFixtureNotFound)
+	assert.Equal(t, 3, hits)
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "Empty mode is off", opts: Options{}},
+		{name: "Replay", opts: Options{Mode: ModeReplay, Dir: "fixtures"}},
+		{name: "Record without directory", opts: Options{Mode: ModeRecord}, wantErr: true},
+		{name: "Unknown mode", opts: Options{Mode: "rewind", Dir: "fixtures"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
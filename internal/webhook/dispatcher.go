@@ -0,0 +1,417 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-springAi/internal/database/generated/webhooks"
+	"go-springAi/internal/repository"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// 投递状态
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// 投递默认参数
+const (
+	DefaultWorkers      = 4
+	DefaultMaxAttempts  = 6
+	DefaultTimeout      = 10 * time.Second
+	DefaultPollInterval = 5 * time.Second
+	DefaultBackoffBase  = 30 * time.Second
+	DefaultBackoffMax   = time.Hour
+)
+
+// publishTimeout 发布事件时写入投递记录的超时时间
+const publishTimeout = 5 * time.Second
+
+// maxErrorBodyBytes 失败时记录的响应体长度上限
+const maxErrorBodyBytes = 512
+
+// pruneInterval 清理过期投递记录的间隔
+const pruneInterval = time.Hour
+
+// ErrDisabled 投递未启用
+var ErrDisabled = errors.New("webhook delivery is disabled")
+
+// Options 投递配置，零值使用默认参数
+type Options struct {
+	Enabled      bool
+	Workers      int           // 并发投递数
+	MaxAttempts  int           // 包括首次投递在内的最大尝试次数
+	Timeout      time.Duration // 单次请求超时
+	PollInterval time.Duration // 检查到期重试的间隔
+	BackoffBase  time.Duration // 第一次重试前的等待时间，之后每次翻倍
+	BackoffMax   time.Duration // 重试等待时间上限
+	Retention    time.Duration // 已结束的投递记录保留时长，0 表示不清理
+}
+
+// Dispatcher 将事件写入投递记录并在后台发送，失败时按指数退避重试；
+// 投递记录保存在数据库中，重启后未完成的投递会继续进行
+type Dispatcher struct {
+	repo      repository.WebhookRepository
+	client    *http.Client
+	opts      Options
+	logger    *zap.Logger
+	now       func() time.Time
+	lastPrune time.Time
+	wake      chan struct{}
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewDispatcher 创建投递器
+func NewDispatcher(repo repository.WebhookRepository, opts Options, logger *zap.Logger) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = DefaultBackoffBase
+	}
+	if opts.BackoffMax <= 0 {
+		opts.BackoffMax = DefaultBackoffMax
+	}
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: opts.Timeout},
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Enabled 是否启用投递
+func (d *Dispatcher) Enabled() bool {
+	return d.opts.Enabled
+}
+
+// Publish 为订阅了该事件的所有启用端点创建投递记录，失败只记录日志
+func (d *Dispatcher) Publish(ctx context.Context, eventType string, data interface{}) {
+	if !d.opts.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+	defer cancel()
+
+	endpoints, err := d.repo.ListEnabledEndpoints(ctx)
+	if err != nil {
+		d.logger.Error("获取Webhook端点失败", zap.String("event_type", eventType), zap.Error(err))
+		return
+	}
+
+	var event *Event
+	var payload []byte
+	queued := 0
+	for _, endpoint := range endpoints {
+		if !Matches(ParseEventTypes(endpoint.EventTypes), eventType) {
+			continue
+		}
+		if event == nil {
+			event = d.newEvent(eventType, data)
+			if payload, err = json.Marshal(event); err != nil {
+				d.logger.Error("序列化Webhook事件失败", zap.String("event_type", eventType), zap.Error(err))
+				return
+			}
+		}
+		if _, err := d.createDelivery(ctx, endpoint.ID, event, payload); err != nil {
+			d.logger.Error("创建Webhook投递记录失败",
+				zap.Int64("endpoint_id", endpoint.ID),
+				zap.String("event_type", eventType),
+				zap.Error(err))
+			continue
+		}
+		queued++
+	}
+	if queued > 0 {
+		d.Wake()
+	}
+}
+
+// Enqueue 向单个端点投递事件，用于测试端点
+func (d *Dispatcher) Enqueue(ctx context.Context, endpointID int64, eventType string, data interface{}) (*webhooks.WebhookDelivery, error) {
+	if !d.opts.Enabled {
+		return nil, ErrDisabled
+	}
+
+	event := d.newEvent(eventType, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook event: %w", err)
+	}
+	delivery, err := d.createDelivery(ctx, endpointID, event, payload)
+	if err != nil {
+		return nil, err
+	}
+	d.Wake()
+	return delivery, nil
+}
+
+// Wake 立即检查待投递记录，不等待下一次轮询
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台投递
+func (d *Dispatcher) Start() {
+	if !d.opts.Enabled {
+		d.logger.Info("Webhook dispatcher disabled")
+		return
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(d.opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			d.run()
+			select {
+			case <-ticker.C:
+			case <-d.wake:
+			case <-d.stop:
+				return
+			}
+		}
+	}()
+
+	d.logger.Info("Webhook dispatcher started",
+		zap.Int("workers", d.opts.Workers),
+		zap.Int("max_attempts", d.opts.MaxAttempts))
+}
+
+// Stop 停止后台投递并等待进行中的请求结束
+func (d *Dispatcher) Stop() {
+	select {
+	case <-d.stop:
+	default:
+		close(d.stop)
+	}
+	d.wg.Wait()
+}
+
+// Backoff 第 attempt 次尝试失败后到下一次重试的等待时间
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	wait := d.opts.BackoffBase
+	for i := 1; i < attempt && wait < d.opts.BackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > d.opts.BackoffMax {
+		wait = d.opts.BackoffMax
+	}
+	return wait
+}
+
+// newEvent 创建事件
+func (d *Dispatcher) newEvent(eventType string, data interface{}) *Event {
+	return &Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: d.now().UTC(),
+		Data:      data,
+	}
+}
+
+// createDelivery 写入一条立即到期的投递记录
+func (d *Dispatcher) createDelivery(ctx context.Context, endpointID int64, event *Event, payload []byte) (*webhooks.WebhookDelivery, error) {
+	return d.repo.CreateDelivery(ctx, webhooks.CreateWebhookDeliveryParams{
+		EndpointID:    endpointID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       string(payload),
+		NextAttemptAt: d.now().UTC(),
+	})
+}
+
+// run 投递所有到期的记录，每批并发 Workers 个请求
+func (d *Dispatcher) run() {
+	d.prune()
+
+	batch := d.opts.Workers * 10
+	for {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		due, err := d.repo.ListDueDeliveries(ctx, d.now().UTC(), batch)
+		var endpoints []webhooks.WebhookEndpoint
+		if err == nil && len(due) > 0 {
+			endpoints, err = d.repo.ListEndpoints(ctx)
+		}
+		cancel()
+		if err != nil {
+			d.logger.Error("获取待投递Webhook失败", zap.Error(err))
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+
+		byID := make(map[int64]*webhooks.WebhookEndpoint, len(endpoints))
+		for i := range endpoints {
+			byID[endpoints[i].ID] = &endpoints[i]
+		}
+
+		sem := make(chan struct{}, d.opts.Workers)
+		var wg sync.WaitGroup
+		for i := range due {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(delivery *webhooks.WebhookDelivery) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				d.attempt(byID[delivery.EndpointID], delivery)
+			}(&due[i])
+		}
+		wg.Wait()
+
+		if len(due) < batch {
+			return
+		}
+	}
+}
+
+// attempt 执行一次投递并保存结果
+func (d *Dispatcher) attempt(endpoint *webhooks.WebhookEndpoint, delivery *webhooks.WebhookDelivery) {
+	now := d.now().UTC()
+	params := webhooks.UpdateWebhookDeliveryAttemptParams{
+		ID:            delivery.ID,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: now,
+	}
+
+	if endpoint == nil || !endpoint.Enabled {
+		params.Status = StatusFailed
+		params.Error = "endpoint disabled"
+	} else {
+		params.Attempts++
+		code, err := d.send(endpoint, delivery, params.Attempts, now)
+		params.ResponseCode = int64(code)
+		switch {
+		case err == nil:
+			params.Status = StatusSucceeded
+		case params.Attempts >= int64(d.opts.MaxAttempts):
+			params.Status = StatusFailed
+			params.Error = err.Error()
+		default:
+			params.Status = StatusPending
+			params.Error = err.Error()
+			params.NextAttemptAt = now.Add(d.Backoff(int(params.Attempts)))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := d.repo.UpdateDeliveryAttempt(ctx, params); err != nil {
+		d.logger.Error("保存Webhook投递结果失败", zap.Int64("delivery_id", delivery.ID), zap.Error(err))
+		return
+	}
+
+	fields := []zap.Field{
+		zap.Int64("delivery_id", delivery.ID),
+		zap.Int64("endpoint_id", delivery.EndpointID),
+		zap.String("event_type", delivery.EventType),
+		zap.Int64("attempt", params.Attempts),
+		zap.String("status", params.Status),
+		zap.Int64("response_code", params.ResponseCode),
+	}
+	switch params.Status {
+	case StatusSucceeded:
+		d.logger.Debug("Webhook delivered", fields...)
+	case StatusPending:
+		d.logger.Warn("Webhook delivery failed, will retry", append(fields, zap.Time("next_attempt_at", params.NextAttemptAt), zap.String("error", params.Error))...)
+	default:
+		d.logger.Error("Webhook delivery failed permanently", append(fields, zap.String("error", params.Error))...)
+	}
+}
+
+// send 发送签名后的请求，返回响应状态码，非2xx视为失败
+func (d *Dispatcher) send(endpoint *webhooks.WebhookEndpoint, delivery *webhooks.WebhookDelivery, attempt int64, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-springAi-Webhook/1.0")
+	req.Header.Set(HeaderID, delivery.EventID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+	req.Header.Set(HeaderAttempt, strconv.FormatInt(attempt, 10))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return resp.StatusCode, nil
+}
+
+// prune 定期删除超过保留时长的已结束投递记录
+func (d *Dispatcher) prune() {
+	if d.opts.Retention <= 0 {
+		return
+	}
+	now := d.now().UTC()
+	if now.Sub(d.lastPrune) < pruneInterval {
+		return
+	}
+	d.lastPrune = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	deleted, err := d.repo.DeleteFinishedDeliveries(ctx, now.Add(-d.opts.Retention))
+	if err != nil {
+		d.logger.Error("清理Webhook投递记录失败", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		d.logger.Info("Webhook deliveries pruned", zap.Int64("count", deleted))
+	}
+}
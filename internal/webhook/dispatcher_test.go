@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go-springAi/internal/database"
+	"go-springAi/internal/database/generated/webhooks"
+	"go-springAi/internal/repository"
+	"go-springAi/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestRepository(t *testing.T) repository.WebhookRepository {
+	ctx := context.Background()
+	db, err := database.NewConnection("sqlite3", filepath.Join(t.TempDir(), "test.db"), database.Options{SQLite: database.SQLiteOptions{ForeignKeys: true}})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db, schemas.FS)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, 0)
+	require.NoError(t, err)
+	return repository.NewWebhookRepository(db)
+}
+
+// receiver 记录收到的请求，前 failures 次返回 500
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	if len(r.requests) <= r.failures {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		failures     int
+		wantStatus   string
+		wantAttempts int64
+	}{
+		{name: "Succeeds after retry", failures: 1, wantStatus: StatusSucceeded, wantAttempts: 2},
+		{name: "Gives up after max attempts", failures: 10, wantStatus: StatusFailed, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			recv := &receiver{failures: tt.failures}
+			server := httptest.NewServer(recv)
+			defer server.Close()
+
+			endpoint, err := repo.CreateEndpoint(ctx, webhooks.CreateWebhookEndpointParams{
+				Url: server.URL, Secret: "secret", EventTypes: "tool.*", Enabled: true,
+			})
+			require.NoError(t, err)
+			_, err = repo.CreateEndpoint(ctx, webhooks.CreateWebhookEndpointParams{
+				Url: server.URL, Secret: "other", EventTypes: EventQuotaExceeded, Enabled: true,
+			})
+			require.NoError(t, err)
+
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			d := NewDispatcher(repo, Options{Enabled: true, MaxAttempts: 3, BackoffBase: time.Minute}, zap.NewNop())
+			d.now = func() time.Time { return now }
+
+			d.Publish(ctx, EventToolExecuted, ToolExecutedData{ExecutionID: "exec-1", ToolName: "echo", Status: "completed"})
+			for i := 0; i < 4; i++ {
+				d.run()
+				// 退避期间不会重试
+				d.run()
+				now = now.Add(d.Backoff(i + 1))
+			}
+
+			deliveries, err := repo.ListDeliveries(ctx, endpoint.ID, 10)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 1, "only the subscribed endpoint gets a delivery")
+			delivery := deliveries[0]
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantAttempts, delivery.Attempts)
+			require.Len(t, recv.requests, int(tt.wantAttempts))
+
+			for i, req := range recv.requests {
+				assert.Equal(t, delivery.EventID, req.Header.Get(HeaderID))
+				assert.Equal(t, EventToolExecuted, req.Header.Get(HeaderEvent))
+				assert.Equal(t, strconv.Itoa(i+1), req.Header.Get(HeaderAttempt))
+				timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+				require.NoError(t, err)
+				assert.NoError(t, Verify("secret", req.Header.Get(HeaderSignature), timestamp, recv.bodies[i], 0, now))
+			}
+
+			var event Event
+			require.NoError(t, json.Unmarshal(recv.bodies[0], &event))
+			assert.Equal(t, EventToolExecuted, event.Type)
+			assert.Equal(t, "exec-1", event.Data.(map[string]interface{})["execution_id"])
+		})
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(nil, Options{BackoffBase: 30 * time.Second, BackoffMax: 5 * time.Minute}, zap.NewNop())
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		assert.Equal(t, w, d.Backoff(i+1), "attempt %d", i+1)
+	}
+}
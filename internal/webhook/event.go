@@ -0,0 +1,204 @@
+// Package webhook 向外部系统投递事件通知。
+//
+// 事件按端点订阅的事件类型写入投递记录，由后台任务发送并在失败时按指数退避重试，
+// 请求体使用端点密钥做 HMAC-SHA256 签名，接收方可据此校验来源和防止重放。
+package webhook
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// 事件类型
+const (
+	EventPing                = "webhook.ping"
+	EventAPIKeyInvalid       = "alert.api_key_invalid"
+	EventAPIKeyExpiring      = "alert.api_key_expiring"
+	EventAPIKeyExpired       = "alert.api_key_expired"
+	EventToolExecuted        = "tool.executed"
+	EventQuotaExceeded       = "quota.exceeded"
+	EventQuotaWarning        = "quota.warning"
+	EventBudgetWarning       = "budget.warning"
+	EventBudgetExceeded      = "budget.exceeded"
+	EventPermissionGranted   = "audit.permission_granted"
+	EventPermissionRevoked   = "audit.permission_revoked"
+	EventImpersonationIssued = "audit.impersonation"
+	EventChatCompleted       = "chat.completed"
+	EventUserCreated         = "user.created"
+	EventReportGenerated     = "report.generated"
+)
+
+// EventTypes 可订阅的事件类型
+var EventTypes = []string{
+	EventAPIKeyInvalid,
+	EventAPIKeyExpiring,
+	EventAPIKeyExpired,
+	EventToolExecuted,
+	EventQuotaExceeded,
+	EventQuotaWarning,
+	EventBudgetWarning,
+	EventBudgetExceeded,
+	EventPermissionGranted,
+	EventPermissionRevoked,
+	EventImpersonationIssued,
+	EventChatCompleted,
+	EventUserCreated,
+	EventReportGenerated,
+}
+
+// Event 投递给端点的事件，序列化后即为请求体
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Publisher 发布事件，实现需保证不阻塞调用方也不返回错误
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{})
+}
+
+// ValidEventType 判断订阅项是否有效，支持 *、alert.* 形式的通配和具体事件类型
+func ValidEventType(pattern string) bool {
+	for _, eventType := range EventTypes {
+		if matchPattern(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches 判断订阅列表是否包含事件类型
+func Matches(patterns []string, eventType string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern 判断单个订阅项是否匹配事件类型
+func matchPattern(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	return strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*"))
+}
+
+// ParseEventTypes 解析数据库中逗号分隔的订阅列表
+func ParseEventTypes(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// FormatEventTypes 将订阅列表保存为逗号分隔的字符串
+func FormatEventTypes(patterns []string) string {
+	return strings.Join(patterns, ",")
+}
+
+// ToolExecutedData tool.executed 事件数据
+type ToolExecutedData struct {
+	ExecutionID string `json:"execution_id"`
+	ToolName    string `json:"tool_name"`
+	Status      string `json:"status"` // completed / failed
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+	UserID      string `json:"user_id,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+}
+
+// QuotaExceededData quota.exceeded 事件数据，每个用户的每项配额在一个周期内只通知一次
+type QuotaExceededData struct {
+	UserID  int64     `json:"user_id"`
+	Quota   string    `json:"quota"` // requests_per_day / tokens_per_month
+	Limit   int64     `json:"limit"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// QuotaWarningData quota.warning 事件数据，用量达到配额的预警比例时发送，每个周期只通知一次
+type QuotaWarningData struct {
+	UserID  int64     `json:"user_id"`
+	Quota   string    `json:"quota"` // requests_per_day / tokens_per_month
+	Limit   int64     `json:"limit"`
+	Used    int64     `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// BudgetAlertData budget.warning / budget.exceeded 事件数据，每项预算在一个月内各只通知一次
+type BudgetAlertData struct {
+	UserID   int64     `json:"user_id,omitempty"` // 用户和项目预算的所属用户，为 0 时通知管理员
+	Budget   string    `json:"budget"`            // user / project / provider
+	Target   string    `json:"target"`            // 用户名、所有者/项目名或提供商类型
+	LimitUSD float64   `json:"limit_usd"`
+	SpentUSD float64   `json:"spent_usd"`
+	ResetAt  time.Time `json:"reset_at"`
+}
+
+// APIKeyInvalidData alert.api_key_invalid 事件数据，密钥由有效或未验证变为被拒绝时发送
+type APIKeyInvalidData struct {
+	APIKeyID  int64  `json:"api_key_id"`
+	UserID    int64  `json:"user_id"`
+	ProjectID int64  `json:"project_id"`
+	Provider  string `json:"provider"`
+	Error     string `json:"error"`
+}
+
+// APIKeyExpirationData alert.api_key_expiring / alert.api_key_expired 事件数据，每个过期时间各只通知一次
+type APIKeyExpirationData struct {
+	APIKeyID  int64     `json:"api_key_id"`
+	UserID    int64     `json:"user_id"`
+	ProjectID int64     `json:"project_id"`
+	Provider  string    `json:"provider"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PermissionChangedData audit.permission_granted / audit.permission_revoked 事件数据
+type PermissionChangedData struct {
+	AdminID    int64  `json:"admin_id"`
+	UserID     int64  `json:"user_id"`
+	Username   string `json:"username,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// ImpersonationData audit.impersonation 事件数据
+type ImpersonationData struct {
+	AdminID       int64     `json:"admin_id"`
+	AdminUsername string    `json:"admin_username"`
+	UserID        int64     `json:"user_id"`
+	Username      string    `json:"username"`
+	Reason        string    `json:"reason"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// ChatCompletedData chat.completed 事件数据，流式请求的令牌数为估算值
+type ChatCompletedData struct {
+	UserID           int64  `json:"user_id,omitempty"`
+	ProjectID        int64  `json:"project_id,omitempty"`
+	Provider         string `json:"provider"`
+	Model            string `json:"model"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+}
+
+// UserCreatedData user.created 事件数据
+type UserCreatedData struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	IsAdmin  bool   `json:"is_admin"`
+}
+
+// ReportGeneratedData report.generated 事件数据，由定时报告任务生成报告后发送
+type ReportGeneratedData struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"` // 报告在存储中的路径
+	Size   int    `json:"size"`
+}
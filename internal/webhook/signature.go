@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// 投递请求头
+const (
+	HeaderID        = "X-Webhook-ID"        // 事件ID，重试时不变，可用于去重
+	HeaderEvent     = "X-Webhook-Event"     // 事件类型
+	HeaderTimestamp = "X-Webhook-Timestamp" // 发送时的Unix秒级时间戳
+	HeaderSignature = "X-Webhook-Signature" // sha256=<hex>
+	HeaderAttempt   = "X-Webhook-Attempt"   // 第几次尝试，从1开始
+)
+
+// signaturePrefix 签名前缀，便于以后更换算法
+const signaturePrefix = "sha256="
+
+// secretPrefix 自动生成的端点密钥前缀
+const secretPrefix = "whsec_"
+
+// 签名校验错误
+var (
+	ErrSignatureMismatch = errors.New("webhook signature mismatch")
+	ErrTimestampExpired  = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign 计算请求签名，签名内容为 "<timestamp>.<body>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验请求签名，tolerance 大于0时拒绝时间戳与 now 相差超过 tolerance 的请求
+func Verify(secret, signature string, timestamp int64, body []byte, tolerance time.Duration, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrSignatureMismatch
+	}
+	if tolerance > 0 {
+		diff := now.Sub(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrTimestampExpired
+		}
+	}
+	return nil
+}
+
+// GenerateSecret 生成端点签名密钥
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"tool.executed"}`)
+	now := time.Unix(1700000000, 0)
+	signature := Sign("secret", now.Unix(), body)
+	assert.Equal(t, "sha256=", signature[:7])
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		now       time.Time
+		err       error
+	}{
+		{name: "Valid", secret: "secret", timestamp: now.Unix(), body: body, now: now.Add(time.Minute)},
+		{name: "Wrong secret", secret: "other", timestamp: now.Unix(), body: body, now: now, err: ErrSignatureMismatch},
+		{name: "Tampered body", secret: "secret", timestamp: now.Unix(), body: []byte(`{}`), now: now, err: ErrSignatureMismatch},
+		{name: "Replayed timestamp", secret: "secret", timestamp: now.Unix() + 1, body: body, now: now, err: ErrSignatureMismatch},
+		{name: "Expired", secret: "secret", timestamp: now.Unix(), body: body, now: now.Add(time.Hour), err: ErrTimestampExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, signature, tt.timestamp, tt.body, 5*time.Minute, tt.now)
+			assert.ErrorIs(t, err, tt.err)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	require.NoError(t, err)
+	b, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+	assert.Len(t, a, len("whsec_")+48)
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		patterns  []string
+		eventType string
+		want      bool
+	}{
+		{patterns: []string{"*"}, eventType: EventToolExecuted, want: true},
+		{patterns: []string{"audit.*"}, eventType: EventPermissionGranted, want: true},
+		{patterns: []string{"audit.*"}, eventType: EventQuotaExceeded, want: false},
+		{patterns: []string{EventQuotaExceeded, EventToolExecuted}, eventType: EventToolExecuted, want: true},
+		{patterns: []string{"tool"}, eventType: EventToolExecuted, want: false},
+		{patterns: nil, eventType: EventToolExecuted, want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Matches(tt.patterns, tt.eventType), "%v %s", tt.patterns, tt.eventType)
+	}
+
+	assert.True(t, ValidEventType("alert.*"))
+	assert.True(t, ValidEventType(EventImpersonationIssued))
+	assert.False(t, ValidEventType("unknown.*"))
+	assert.False(t, ValidEventType("tool.unknown"))
+	assert.Equal(t, []string{"a", "b"}, ParseEventTypes(" a, ,b "))
+}
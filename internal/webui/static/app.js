@@ -0,0 +1,518 @@
+// Embedded admin console. Talks to the /api/v1 REST endpoints with the
+// logged-in admin's session tokens, kept in sessionStorage for this tab only.
+"use strict";
+
+const API = "/api/v1";
+const TOKENS_KEY = "adminTokens";
+
+// ---- Session ---------------------------------------------------------------
+
+function loadTokens() {
+  try {
+    return JSON.parse(sessionStorage.getItem(TOKENS_KEY));
+  } catch {
+    return null;
+  }
+}
+
+function saveTokens(tokens) {
+  if (tokens) {
+    sessionStorage.setItem(TOKENS_KEY, JSON.stringify(tokens));
+  } else {
+    sessionStorage.removeItem(TOKENS_KEY);
+  }
+}
+
+let tokens = loadTokens();
+
+// errorMessage reads the message from any of the server's error formats:
+// the response envelope, AppError bodies and problem+json.
+function errorMessage(body, status) {
+  if (body) {
+    if (body.error && typeof body.error === "object") return body.error.message;
+    if (body.detail || body.title) return body.detail || body.title;
+    if (body.error) return body.message ? `${body.message}: ${body.error}` : body.error;
+    if (body.message) return body.message;
+  }
+  return `HTTP ${status}`;
+}
+
+class APIError extends Error {
+  constructor(status, body) {
+    super(errorMessage(body, status));
+    this.status = status;
+    this.body = body;
+  }
+}
+
+async function send(method, path, body) {
+  const headers = { Accept: "application/json" };
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  if (tokens) headers.Authorization = `Bearer ${tokens.access_token}`;
+  const resp = await fetch(API + path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const text = await resp.text();
+  let parsed = null;
+  if (text) {
+    try {
+      parsed = JSON.parse(text);
+    } catch {
+      parsed = { message: text };
+    }
+  }
+  return { resp, body: parsed };
+}
+
+async function refresh() {
+  const { resp, body } = await send("POST", "/auth/refresh", { refresh_token: tokens.refresh_token });
+  if (!resp.ok) return false;
+  tokens = { ...tokens, ...body.data };
+  saveTokens(tokens);
+  return true;
+}
+
+// api calls an endpoint and returns the envelope's data. An expired access
+// token is refreshed once; a failed refresh ends the session.
+async function api(method, path, body) {
+  let { resp, body: result } = await send(method, path, body);
+  if (resp.status === 401 && tokens && tokens.refresh_token) {
+    if (await refresh()) {
+      ({ resp, body: result } = await send(method, path, body));
+    } else {
+      endSession("Your session has expired. Please log in again.");
+      throw new APIError(401, result);
+    }
+  }
+  if (!resp.ok) throw new APIError(resp.status, result);
+  return result ? result.data : null;
+}
+
+function endSession(message) {
+  tokens = null;
+  saveTokens(null);
+  render();
+  if (message) notify(message, true);
+}
+
+// ---- DOM helpers -------------------------------------------------------------
+
+// h builds an element. Text children are always inserted as text nodes, so
+// values from the server are never interpreted as HTML.
+function h(tag, attrs, ...children) {
+  const el = document.createElement(tag);
+  for (const [key, value] of Object.entries(attrs || {})) {
+    if (value === undefined || value === null || value === false) continue;
+    if (key.startsWith("on")) {
+      el.addEventListener(key.slice(2), value);
+    } else if (key === "class") {
+      el.className = value;
+    } else if (value === true) {
+      el.setAttribute(key, "");
+    } else {
+      el.setAttribute(key, value);
+    }
+  }
+  for (const child of children.flat()) {
+    if (child === undefined || child === null || child === false) continue;
+    el.append(child instanceof Node ? child : document.createTextNode(String(child)));
+  }
+  return el;
+}
+
+function table(headers, rows) {
+  return h("table", null,
+    h("thead", null, h("tr", null, headers.map((title) => h("th", null, title)))),
+    h("tbody", null, rows.length ? rows : h("tr", null, h("td", { colspan: headers.length, class: "muted" }, "Nothing to show"))));
+}
+
+function badge(ok, yes, no) {
+  return h("span", { class: ok ? "badge ok" : "badge bad" }, ok ? yes : no);
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+let noticeTimer;
+
+function notify(message, isError) {
+  const notice = document.getElementById("notice");
+  notice.textContent = message;
+  notice.className = isError ? "error" : "";
+  notice.hidden = false;
+  clearTimeout(noticeTimer);
+  noticeTimer = setTimeout(() => { notice.hidden = true; }, isError ? 8000 : 4000);
+}
+
+// run wraps an async action and reports its error instead of throwing.
+async function run(action) {
+  try {
+    return await action();
+  } catch (err) {
+    if (!(err instanceof APIError && err.status === 401 && !tokens)) notify(err.message, true);
+    return undefined;
+  }
+}
+
+function formValues(form) {
+  return Object.fromEntries(new FormData(form).entries());
+}
+
+// ---- Views -------------------------------------------------------------------
+
+const views = {
+  providers: providersView,
+  keys: keysView,
+  users: usersView,
+  logs: logsView,
+  chat: chatView,
+};
+
+let stopView = null;
+
+function render() {
+  if (stopView) {
+    stopView();
+    stopView = null;
+  }
+  const view = document.getElementById("view");
+  view.replaceChildren();
+  const loggedIn = Boolean(tokens);
+  document.getElementById("nav").hidden = !loggedIn;
+  document.getElementById("session").hidden = !loggedIn;
+
+  if (!loggedIn) {
+    view.append(loginView());
+    return;
+  }
+  document.getElementById("whoami").textContent = tokens.user ? tokens.user.username : "";
+
+  const name = location.hash.slice(1) || "providers";
+  for (const link of document.querySelectorAll("nav a")) {
+    link.classList.toggle("active", link.getAttribute("href") === `#${name}`);
+  }
+  const build = views[name] || providersView;
+  stopView = build(view) || null;
+}
+
+function loginView() {
+  const fragment = document.getElementById("login-template").content.cloneNode(true);
+  const form = fragment.querySelector("form");
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(async () => {
+      const data = await api("POST", "/auth/login", formValues(form));
+      if (!data.user || !data.user.is_admin) {
+        // Admin endpoints would reject this session, so revoke it right away.
+        tokens = data;
+        await send("POST", "/auth/logout", { refresh_token: data.refresh_token });
+        tokens = null;
+        throw new Error("This console is for administrators only.");
+      }
+      tokens = data;
+      saveTokens(tokens);
+      render();
+    });
+  });
+  return fragment;
+}
+
+// providersView lists providers and lets the admin toggle several models and
+// apply them in one all-or-nothing bulk update.
+function providersView(root) {
+  const providerList = h("div", { class: "card" }, h("h2", null, "Providers"));
+  const modelsCard = h("div", { class: "card" });
+  root.append(providerList, modelsCard);
+
+  run(async () => {
+    const data = await api("GET", "/ai/providers");
+    const providers = data.providers || [];
+    providerList.append(table(["Type", "Name", "Health", "Models", ""], providers.map((p) =>
+      h("tr", null,
+        h("td", null, p.type),
+        h("td", null, p.name),
+        h("td", null, badge(p.healthy, "healthy", "unhealthy")),
+        h("td", null, p.model_count),
+        h("td", null, h("button", { type: "button", onclick: () => showModels(modelsCard, p.type) }, "Models"))))));
+    if (providers.length) showModels(modelsCard, providers[0].type);
+  });
+}
+
+function showModels(card, provider) {
+  card.replaceChildren(h("h2", null, `Models: ${provider}`), h("p", { class: "muted" }, "Loading…"));
+  run(async () => {
+    const data = await api("GET", `/ai/${encodeURIComponent(provider)}/models/all`);
+    const models = Object.values(data.models || {}).sort((a, b) => a.name.localeCompare(b.name));
+    const pending = new Map();
+    const save = h("button", { type: "button", class: "primary", disabled: true }, "Apply changes");
+
+    const rows = models.map((model) => {
+      const row = h("tr", null);
+      const toggle = h("input", {
+        type: "checkbox",
+        checked: model.enabled,
+        onchange: () => {
+          if (toggle.checked === model.enabled) {
+            pending.delete(model.name);
+          } else {
+            pending.set(model.name, toggle.checked);
+          }
+          row.classList.toggle("changed", pending.has(model.name));
+          save.disabled = pending.size === 0;
+        },
+      });
+      row.append(
+        h("td", null, toggle),
+        h("td", null, model.name),
+        h("td", null, model.display_name),
+        h("td", null, model.max_tokens),
+        h("td", null, model.temperature));
+      return row;
+    });
+
+    save.addEventListener("click", () => run(async () => {
+      const changes = [...pending].map(([model, enabled]) => ({ model, enabled }));
+      try {
+        await api("PATCH", `/ai/${encodeURIComponent(provider)}/models`, { changes });
+      } catch (err) {
+        const results = err.body && err.body.error && err.body.error.metadata && err.body.error.metadata.results;
+        if (results) {
+          const failed = results.filter((r) => r.error).map((r) => `${r.model}: ${r.error}`);
+          throw new Error(`${err.message}. ${failed.join("; ")}`);
+        }
+        throw err;
+      }
+      notify(`Updated ${changes.length} model(s).`);
+      showModels(card, provider);
+    }));
+
+    card.replaceChildren(
+      h("h2", null, `Models: ${provider}`),
+      h("div", { class: "toolbar" }, save, h("span", { class: "muted" }, "Changes are applied together; if one fails, none are kept.")),
+      table(["Enabled", "Name", "Display name", "Max tokens", "Temperature"], rows));
+  });
+}
+
+function keysView(root) {
+  const status = h("div", { class: "card" }, h("h2", null, "API key status"));
+  const form = h("form", { class: "card" },
+    h("h2", null, "Set an API key"),
+    h("label", null, "Provider ", h("select", { name: "provider", required: true })),
+    h("label", null, "API key ", h("input", { name: "api_key", type: "password", autocomplete: "off", required: true })),
+    h("label", null, "Allowed models (comma separated, empty for all) ", h("input", { name: "allowed_models" })),
+    h("button", { type: "submit", class: "primary" }, "Save key"));
+  root.append(status, form);
+
+  const load = () => run(async () => {
+    const data = await api("GET", "/ai/api-keys/status");
+    const entries = Object.entries(data || {}).sort(([a], [b]) => a.localeCompare(b));
+    status.replaceChildren(h("h2", null, "API key status"), table(["Provider", "Key", "Allowed models", "Last validation"], entries.map(([provider, info]) =>
+      h("tr", null,
+        h("td", null, provider),
+        h("td", null, info.has_key ? info.masked_key || "set" : badge(false, "", "not set")),
+        h("td", null, (info.allowed_models || []).join(", ") || "all"),
+        h("td", null, info.validation ? [badge(info.validation.valid, "valid", "invalid"), " ", formatTime(info.validation.validated_at)] : "")))));
+    const select = form.querySelector("select");
+    select.replaceChildren(...entries.map(([provider]) => h("option", { value: provider }, provider)));
+  });
+
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(async () => {
+      const values = formValues(form);
+      const allowed = values.allowed_models.split(",").map((m) => m.trim()).filter(Boolean);
+      await api("POST", `/ai/${encodeURIComponent(values.provider)}/api-key`, { api_key: values.api_key, allowed_models: allowed });
+      form.querySelector("input[name=api_key]").value = "";
+      notify(`API key for ${values.provider} saved.`);
+      load();
+    });
+  });
+  load();
+}
+
+function usersView(root) {
+  const search = h("input", { type: "search", placeholder: "Search username or email" });
+  const deleted = h("input", { type: "checkbox" });
+  const list = h("div");
+  const pager = h("div", { class: "toolbar" });
+  const createForm = h("form", { class: "card" },
+    h("h2", null, "Create user"),
+    h("label", null, "Username ", h("input", { name: "username", required: true, minlength: 3, maxlength: 50 })),
+    h("label", null, "Email ", h("input", { name: "email", type: "email", required: true })),
+    h("label", null, "Full name ", h("input", { name: "full_name" })),
+    h("label", null, "Password ", h("input", { name: "password", type: "password", autocomplete: "new-password", required: true, minlength: 6 })),
+    h("button", { type: "submit", class: "primary" }, "Create"));
+
+  root.append(
+    h("div", { class: "card" },
+      h("h2", null, "Users"),
+      h("div", { class: "toolbar" }, search, h("label", null, deleted, "Include deleted"), h("button", { type: "button", onclick: () => load("") }, "Search")),
+      list,
+      pager),
+    createForm);
+
+  let cursor = "";
+  const load = (next) => run(async () => {
+    cursor = next;
+    const query = new URLSearchParams({ limit: "20" });
+    if (search.value) query.set("search", search.value);
+    if (deleted.checked) query.set("include_deleted", "true");
+    if (cursor) query.set("cursor", cursor);
+    const data = await api("GET", `/admin/users?${query}`);
+    list.replaceChildren(table(["ID", "Username", "Email", "Full name", "Status", "Created", ""], (data.users || []).map((user) =>
+      h("tr", null,
+        h("td", null, user.id),
+        h("td", null, user.username, user.is_admin ? [" ", h("span", { class: "badge" }, "admin")] : null),
+        h("td", null, user.email),
+        h("td", null, user.full_name || ""),
+        h("td", null, user.deleted_at ? badge(false, "", "deleted") : badge(user.is_active, "active", "inactive")),
+        h("td", null, formatTime(user.created_at)),
+        h("td", null, user.deleted_at
+          ? h("button", { type: "button", onclick: () => restoreUser(user) }, "Restore")
+          : h("button", { type: "button", class: "danger", onclick: () => deleteUser(user) }, "Delete"))))));
+    pager.replaceChildren(
+      h("span", { class: "muted" }, `${data.total} user(s)`),
+      h("button", { type: "button", disabled: !data.prev_cursor, onclick: () => load(data.prev_cursor) }, "Previous"),
+      h("button", { type: "button", disabled: !data.next_cursor, onclick: () => load(data.next_cursor) }, "Next"));
+  });
+
+  const deleteUser = (user) => {
+    if (!confirm(`Delete user ${user.username}? The account can be restored until it is purged.`)) return;
+    run(async () => {
+      await api("DELETE", `/admin/users/${user.id}`);
+      notify(`User ${user.username} deleted.`);
+      load(cursor);
+    });
+  };
+  const restoreUser = (user) => run(async () => {
+    await api("POST", `/admin/users/${user.id}/restore`);
+    notify(`User ${user.username} restored.`);
+    load(cursor);
+  });
+
+  search.addEventListener("keydown", (event) => {
+    if (event.key === "Enter") load("");
+  });
+  createForm.addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(async () => {
+      const user = await api("POST", "/admin/users", formValues(createForm));
+      createForm.reset();
+      notify(`User ${user.username} created.`);
+      load("");
+    });
+  });
+  load("");
+}
+
+// logsView shows the newest tool execution logs and can refresh them every
+// few seconds while the view is open.
+function logsView(root) {
+  const list = h("div");
+  const more = h("button", { type: "button", hidden: true }, "Older logs");
+  const auto = h("input", { type: "checkbox" });
+  root.append(h("div", { class: "card" },
+    h("h2", null, "Execution logs"),
+    h("div", { class: "toolbar" },
+      h("button", { type: "button", onclick: () => load("") }, "Refresh"),
+      h("label", null, auto, "Refresh every 5 seconds")),
+    list,
+    h("div", { class: "toolbar" }, more)));
+
+  let rows = [];
+  let next = "";
+  const load = (cursor) => run(async () => {
+    const query = new URLSearchParams({ limit: "50" });
+    if (cursor) query.set("cursor", cursor);
+    const data = await api("GET", `/mcp/logs?${query}`);
+    const logs = data.logs || [];
+    rows = cursor ? rows.concat(logs) : logs;
+    next = data.next_cursor || "";
+    more.hidden = !next;
+    list.replaceChildren(table(["Started", "Tool", "User", "Duration", "Result"], rows.map((log) =>
+      h("tr", null,
+        h("td", null, formatTime(log.startTime)),
+        h("td", null, log.toolName),
+        h("td", null, log.userId || ""),
+        h("td", null, log.duration ? `${Math.round(log.duration / 1e6)} ms` : log.endTime ? "" : "running"),
+        h("td", null, log.error
+          ? badge(false, "", log.error.message)
+          : h("details", null, h("summary", null, "arguments"), h("pre", null, JSON.stringify(log.arguments, null, 2))))))));
+  });
+  more.addEventListener("click", () => load(next));
+
+  const timer = setInterval(() => {
+    if (auto.checked) load("");
+  }, 5000);
+  load("");
+  return () => clearInterval(timer);
+}
+
+function chatView(root) {
+  const provider = h("select", { name: "provider" }, h("option", { value: "" }, "Default provider"));
+  const model = h("input", { name: "model", placeholder: "Default model" });
+  const useTools = h("input", { type: "checkbox", checked: true });
+  const log = h("div", { class: "chat-log" });
+  const input = h("textarea", { placeholder: "Ask something, e.g. Analyse AAPL over the last month", required: true });
+  const sendButton = h("button", { type: "submit", class: "primary" }, "Send");
+  const form = h("form", null, input, h("div", { class: "toolbar" }, sendButton,
+    h("button", { type: "button", onclick: () => { messages = []; log.replaceChildren(); } }, "Clear")));
+
+  root.append(h("div", { class: "card" },
+    h("h2", null, "Chat"),
+    h("div", { class: "toolbar" }, provider, model, h("label", null, useTools, "Use MCP tools")),
+    log,
+    form));
+
+  run(async () => {
+    const data = await api("GET", "/ai/providers");
+    provider.append(...(data.providers || []).map((p) => h("option", { value: p.type }, p.name)));
+  });
+
+  let messages = [];
+  const show = (role, text) => {
+    log.append(h("div", { class: `message ${role}` }, text));
+    log.scrollTop = log.scrollHeight;
+  };
+
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    const content = input.value.trim();
+    if (!content) return;
+    input.value = "";
+    messages.push({ role: "user", content });
+    show("user", content);
+    sendButton.disabled = true;
+    api("POST", "/assistant/chat", {
+      messages,
+      provider: provider.value || undefined,
+      model: model.value || undefined,
+      use_tools: useTools.checked,
+    }).then((data) => {
+      const choice = data.choices && data.choices[0];
+      const reply = choice ? choice.message.content : "";
+      messages.push({ role: "assistant", content: reply });
+      const tools = choice && choice.tool_calls ? choice.tool_calls.length : 0;
+      show("assistant", tools ? `${reply}\n\n(${tools} tool call(s) · ${data.model})` : reply);
+    }).catch((err) => {
+      messages.pop();
+      show("error", err.message);
+    }).finally(() => {
+      sendButton.disabled = false;
+    });
+  });
+}
+
+// ---- Startup -----------------------------------------------------------------
+
+document.getElementById("logout").addEventListener("click", () => {
+  const refreshToken = tokens && tokens.refresh_token;
+  if (refreshToken) send("POST", "/auth/logout", { refresh_token: refreshToken });
+  endSession();
+});
+
+window.addEventListener("hashchange", render);
+render();
@@ -0,0 +1,34 @@
+// Package webui 内嵌的管理后台单页应用，随二进制一起发布，无需单独部署前端
+package webui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Prefix 管理后台的挂载路径
+const Prefix = "/admin"
+
+//go:embed static
+var files embed.FS
+
+// Handler 返回管理后台静态文件处理器，请求路径需以 Prefix 开头。
+// 页面只加载同源脚本和样式，并禁止被其他站点嵌入
+func Handler() http.Handler {
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		panic(err)
+	}
+	fileServer := http.StripPrefix(Prefix, http.FileServer(http.FS(static)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'self'")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "no-referrer")
+		// 嵌入的文件没有修改时间，升级后需要重新获取
+		header.Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		wantStatus  int
+		wantType    string
+		wantContain string
+	}{
+		{name: "Index", path: "/admin/", wantStatus: http.StatusOK, wantType: "text/html", wantContain: `<script src="app.js">`},
+		{name: "Script", path: "/admin/app.js", wantStatus: http.StatusOK, wantType: "javascript", wantContain: `const API = "/api/v1"`},
+		{name: "Stylesheet", path: "/admin/style.css", wantStatus: http.StatusOK, wantType: "text/css"},
+		{name: "Missing file", path: "/admin/missing.js", wantStatus: http.StatusNotFound},
+	}
+
+	handler := Handler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+			assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'self'")
+			if tt.wantType != "" {
+				// 脚本的MIME类型取决于系统的 mime.types
+				assert.Contains(t, w.Header().Get("Content-Type"), tt.wantType)
+			}
+			assert.Contains(t, w.Body.String(), tt.wantContain)
+		})
+	}
+}
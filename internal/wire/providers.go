@@ -2,39 +2,90 @@ package wire
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"go-springAi/internal/archive"
+	"go-springAi/internal/calendar"
+	"go-springAi/internal/capture"
+	"go-springAi/internal/chaos"
+	"go-springAi/internal/cmdsandbox"
 	"go-springAi/internal/config"
 	"go-springAi/internal/controllers"
 	"go-springAi/internal/database"
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
+	"go-springAi/internal/errreport"
+	"go-springAi/internal/eventbus"
 	"go-springAi/internal/googleai"
+	"go-springAi/internal/googleauth"
+	"go-springAi/internal/graphqlapi"
+	"go-springAi/internal/grpcapi"
 
 	"go-springAi/internal/i18n"
+	"go-springAi/internal/idempotency"
+	"go-springAi/internal/k8sops"
 	"go-springAi/internal/logger"
 	"go-springAi/internal/mcp"
+	"go-springAi/internal/mcp/tools"
+	"go-springAi/internal/metrics"
+	"go-springAi/internal/middleware"
+	"go-springAi/internal/notify"
 	"go-springAi/internal/openai"
 	"go-springAi/internal/provider"
+	"go-springAi/internal/ratelimit"
 	"go-springAi/internal/repository"
 	"go-springAi/internal/route"
+	"go-springAi/internal/runtimeconfig"
+	"go-springAi/internal/sandbox"
+	"go-springAi/internal/secrets"
+	"go-springAi/internal/scheduler"
 	"go-springAi/internal/service"
+	"go-springAi/internal/sqlquery"
 	"go-springAi/internal/types"
 	"go-springAi/internal/utils"
+	"go-springAi/internal/vcr"
+	"go-springAi/internal/webhook"
+	"go-springAi/internal/webui"
+	"go-springAi/internal/workspace"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // ProvideConfig 提供配置
 func ProvideConfig(configPath string) (*config.Config, error) {
-	return config.LoadConfig(configPath)
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	// 沙箱模式下行情接口只从录制文件回放，其余出站请求在 http.DefaultTransport 处被拒绝
+	if cfg.Sandbox.Enabled {
+		cfg.HTTPFixtures = config.HTTPFixturesConfig{Mode: string(vcr.ModeReplay), Dir: cfg.Sandbox.FixturesDir}
+		sandbox.Enable()
+	}
+	// 外部HTTP调用的录制回放对所有客户端全局生效
+	if err := vcr.Configure(vcr.Options{Mode: vcr.Mode(cfg.HTTPFixtures.Mode), Dir: cfg.HTTPFixtures.Dir}); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // ProvideLogger 提供日志器
 func ProvideLogger(cfg *config.Config) (*zap.Logger, error) {
-	// 初始化全局日志器
-	if err := logger.InitGlobalLogger(cfg.Server.Mode); err != nil {
+	// 初始化全局日志器和日志文件
+	if err := logger.Configure(logger.Options{
+		Mode:      cfg.Server.Mode,
+		File:      toRotationConfig(cfg.Log.App),
+		AccessLog: toRotationConfig(cfg.Log.Access.LogFileConfig),
+	}); err != nil {
 		return nil, err
 	}
 
@@ -55,6 +106,7 @@ func ProvideLogger(cfg *config.Config) (*zap.Logger, error) {
 	if err != nil {
 		return nil, err
 	}
+	zapLogger = logger.WithFileSink(zapLogger)
 
 	// 设置全局日志器
 	zap.ReplaceGlobals(zapLogger)
@@ -62,20 +114,325 @@ func ProvideLogger(cfg *config.Config) (*zap.Logger, error) {
 	return zapLogger, nil
 }
 
+// toRotationConfig 将配置中的日志文件设置转换为日志包结构
+func toRotationConfig(cfg config.LogFileConfig) logger.RotationConfig {
+	return logger.RotationConfig{
+		Filename:   cfg.Filename,
+		MaxSizeMB:  cfg.MaxSizeMB,
+		MaxAgeDays: cfg.MaxAgeDays,
+		MaxBackups: cfg.MaxBackups,
+		Compress:   cfg.Compress,
+	}
+}
+
 // ProvideDatabase 提供数据库连接
 func ProvideDatabase(cfg *config.Config) (*database.DB, error) {
-	return database.NewConnection(cfg.Database.Driver, cfg.Database.DSN)
+	dbCfg := cfg.Database
+	return database.NewConnection(dbCfg.Driver, dbCfg.DSN, database.Options{
+		MaxOpenConns:    dbCfg.MaxOpenConns,
+		MaxIdleConns:    dbCfg.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(dbCfg.ConnMaxLifetimeSeconds) * time.Second,
+		ConnMaxIdleTime: time.Duration(dbCfg.ConnMaxIdleTimeSeconds) * time.Second,
+		SQLite: database.SQLiteOptions{
+			JournalMode: dbCfg.SQLite.JournalMode,
+			BusyTimeout: time.Duration(dbCfg.SQLite.BusyTimeoutMS) * time.Millisecond,
+			ForeignKeys: dbCfg.SQLite.ForeignKeys,
+			Synchronous: dbCfg.SQLite.Synchronous,
+		},
+	})
 }
 
 // ProvideJWTManager 提供JWT管理器
-func ProvideJWTManager(cfg *config.Config) *utils.JWTManager {
-	return utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireTime)
+func ProvideJWTManager(cfg *config.Config, store secrets.Store) (*utils.JWTManager, error) {
+	if store == nil {
+		return utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireTime), nil
+	}
+
+	// 启用外部密钥存储时，JWT 密钥从存储中读取
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Secrets.Timeout)*time.Second)
+	defer cancel()
+	secret, err := store.Get(ctx, cfg.Secrets.JWTSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT secret %q: %w", cfg.Secrets.JWTSecretName, err)
+	}
+	return utils.NewJWTManager(secret, cfg.JWT.ExpireTime), nil
+}
+
+// ProvideSecretsStore 提供外部密钥存储，database 后端返回 nil
+func ProvideSecretsStore(cfg *config.Config) (secrets.Store, error) {
+	return secrets.New(secrets.Config{
+		Backend: cfg.Secrets.Backend,
+		Timeout: time.Duration(cfg.Secrets.Timeout) * time.Second,
+		Vault: secrets.VaultConfig{
+			Address:   cfg.Secrets.Vault.Address,
+			Token:     cfg.Secrets.Vault.Token,
+			Namespace: cfg.Secrets.Vault.Namespace,
+			Mount:     cfg.Secrets.Vault.Mount,
+			Prefix:    cfg.Secrets.Vault.Prefix,
+		},
+		AWS: secrets.AWSConfig{
+			Region:          cfg.Secrets.AWS.Region,
+			AccessKeyID:     cfg.Secrets.AWS.AccessKeyID,
+			SecretAccessKey: cfg.Secrets.AWS.SecretAccessKey,
+			SessionToken:    cfg.Secrets.AWS.SessionToken,
+			Endpoint:        cfg.Secrets.AWS.Endpoint,
+			Prefix:          cfg.Secrets.AWS.Prefix,
+		},
+	})
+}
+
+// ProvideLogArchiveStore 提供执行日志归档存储，未配置后端时返回 nil
+func ProvideLogArchiveStore(cfg *config.Config) (archive.Store, error) {
+	archiveCfg := cfg.MCP.LogArchive
+	return archive.New(archive.Config{
+		Backend: archiveCfg.Backend,
+		Timeout: time.Duration(archiveCfg.Timeout) * time.Second,
+		Local:   archive.LocalConfig{Dir: archiveCfg.Local.Dir},
+		S3: archive.S3Config{
+			Bucket:          archiveCfg.S3.Bucket,
+			Region:          archiveCfg.S3.Region,
+			AccessKeyID:     archiveCfg.S3.AccessKeyID,
+			SecretAccessKey: archiveCfg.S3.SecretAccessKey,
+			SessionToken:    archiveCfg.S3.SessionToken,
+			Endpoint:        archiveCfg.S3.Endpoint,
+			Prefix:          archiveCfg.S3.Prefix,
+		},
+	})
 }
 
 // ProvideMCPService 提供MCP服务
-func ProvideMCPService(repoManager repository.RepositoryManager, logger *zap.Logger) service.MCPService {
+func ProvideMCPService(repoManager repository.RepositoryManager, providerManager *provider.Manager, apiKeyService service.APIKeyService, i18nManager *i18n.Manager, events webhook.Publisher, notifier *notify.Notifier, cfg *config.Config, logger *zap.Logger) service.MCPService {
 	userService := service.NewUserServiceAdapter(repoManager)
-	return service.NewMCPService(userService, logger)
+	sampler := service.NewProviderSampler(&ProviderManagerAdapter{manager: providerManager}, cfg.MCP.SamplingModel, logger)
+	mcpService := service.NewMCPService(userService, sampler, cfg.Stock.TranscriptAPIKey, i18nManager, events, logger)
+
+	// 网页搜索工具的密钥按调用者从API密钥服务获取，未保存时使用配置中的密钥
+	searchKeys := service.NewSearchKeyResolver(apiKeyService, map[string]string{
+		tools.SearchBackendBrave:   cfg.MCP.WebSearch.BraveAPIKey,
+		tools.SearchBackendBing:    cfg.MCP.WebSearch.BingAPIKey,
+		tools.SearchBackendSerpAPI: cfg.MCP.WebSearch.SerpAPIAPIKey,
+	})
+	if err := mcpService.RegisterTool(tools.NewWebSearchTool(cfg.MCP.WebSearch.Backend, cfg.MCP.WebSearch.MaxResults, searchKeys)); err != nil {
+		logger.Warn("Failed to register web search tool", zap.Error(err))
+	}
+
+	// 沙箱命令工具默认关闭，启用后仅管理员可用
+	if shellCfg := cfg.MCP.Shell; shellCfg.Enabled && len(shellCfg.AllowedCommands) > 0 {
+		shellSandbox, err := cmdsandbox.New(cmdsandbox.Config{
+			AllowedCommands: shellCfg.AllowedCommands,
+			WorkDir:         shellCfg.WorkDir,
+			Timeout:         time.Duration(shellCfg.TimeoutSeconds) * time.Second,
+			CPUSeconds:      shellCfg.CPUSeconds,
+			MemoryMB:        shellCfg.MemoryMB,
+			MaxOutputBytes:  shellCfg.MaxOutputBytes,
+			IsolateNetwork:  shellCfg.NetworkIsolation,
+		})
+		if err != nil {
+			logger.Warn("Failed to create shell sandbox", zap.Error(err))
+		} else if err := mcpService.RegisterTool(tools.NewShellTool(shellSandbox, service.NewAdminChecker(repoManager.User()), logger)); err != nil {
+			logger.Warn("Failed to register shell tool", zap.Error(err))
+		}
+	}
+
+	// 文件工作区工具按调用者隔离，保存多步骤会话的中间结果
+	if workspaceCfg := cfg.MCP.Workspace; workspaceCfg.Enabled {
+		manager, err := workspace.New(workspace.Config{
+			Root:         workspaceCfg.Root,
+			QuotaBytes:   int64(workspaceCfg.QuotaMB) << 20,
+			MaxFileBytes: int64(workspaceCfg.MaxFileMB) << 20,
+			MaxFiles:     workspaceCfg.MaxFiles,
+		})
+		if err != nil {
+			logger.Warn("Failed to create file workspace", zap.Error(err))
+		} else {
+			for _, tool := range []mcp.Tool{
+				tools.NewFileReadTool(manager, service.CallerUserID),
+				tools.NewFileWriteTool(manager, service.CallerUserID),
+				tools.NewFileListTool(manager, service.CallerUserID),
+			} {
+				if err := mcpService.RegisterTool(tool); err != nil {
+					logger.Warn("Failed to register file workspace tool", zap.String("tool", tool.GetDefinition().Name), zap.Error(err))
+				}
+			}
+		}
+	}
+
+	// 只读 SQL 分析工具，仅在配置了外部数据库时注册
+	if sqlCfg := cfg.MCP.SQLQuery; len(sqlCfg.Databases) > 0 {
+		sources := make([]sqlquery.Source, 0, len(sqlCfg.Databases))
+		for _, db := range sqlCfg.Databases {
+			sources = append(sources, sqlquery.Source{
+				Name:        db.Name,
+				Driver:      db.Driver,
+				DSN:         db.DSN,
+				Description: db.Description,
+				Redact:      db.RedactColumns,
+			})
+		}
+		manager, err := sqlquery.New(sqlquery.Config{
+			Sources: sources,
+			MaxRows: sqlCfg.MaxRows,
+			Timeout: time.Duration(sqlCfg.TimeoutSeconds) * time.Second,
+		})
+		if err != nil {
+			logger.Warn("Failed to configure SQL query databases", zap.Error(err))
+		} else if err := mcpService.RegisterTool(tools.NewSQLQueryTool(manager, service.CallerUserID)); err != nil {
+			logger.Warn("Failed to register SQL query tool", zap.Error(err))
+		}
+	}
+
+	// Kubernetes 只读运维工具，仅在配置了集群时注册，仅管理员可用
+	if k8sCfg := cfg.MCP.K8s; len(k8sCfg.Clusters) > 0 {
+		clusters := make([]k8sops.ClusterConfig, 0, len(k8sCfg.Clusters))
+		for _, cluster := range k8sCfg.Clusters {
+			clusters = append(clusters, k8sops.ClusterConfig{
+				Name:       cluster.Name,
+				Kubeconfig: cluster.Kubeconfig,
+				Context:    cluster.Context,
+				InCluster:  cluster.InCluster,
+				Namespaces: cluster.Namespaces,
+			})
+		}
+		manager, err := k8sops.New(clusters, time.Duration(k8sCfg.TimeoutSeconds)*time.Second, int64(k8sCfg.MaxLogBytes))
+		if err != nil {
+			logger.Warn("Failed to configure Kubernetes clusters", zap.Error(err))
+		} else if err := mcpService.RegisterTool(tools.NewK8sTool(manager, service.NewAdminChecker(repoManager.User()), logger)); err != nil {
+			logger.Warn("Failed to register k8s tool", zap.Error(err))
+		}
+	}
+
+	// GitHub 工具，仅在配置了仓库时注册，令牌按调用者从API密钥服务获取
+	if githubCfg := cfg.MCP.GitHub; len(githubCfg.Repos) > 0 {
+		githubToken := service.NewIntegrationTokenResolver(apiKeyService, tools.GitHubKeyProvider, githubCfg.Token)
+		githubTool := tools.NewGitHubTool(githubCfg.BaseURL, githubCfg.Repos, githubCfg.MaxFileKB<<10, githubToken, service.CallerUserID)
+		if err := mcpService.RegisterTool(githubTool); err != nil {
+			logger.Warn("Failed to register GitHub tool", zap.Error(err))
+		}
+	}
+
+	// Jira 工具，仅在配置了地址时注册，令牌按调用者从API密钥服务获取
+	if jiraCfg := cfg.MCP.Jira; jiraCfg.BaseURL != "" {
+		jiraToken := service.NewIntegrationTokenResolver(apiKeyService, tools.JiraKeyProvider, jiraCfg.Token)
+		if err := mcpService.RegisterTool(tools.NewJiraTool(jiraCfg.BaseURL, jiraCfg.Projects, jiraToken, service.CallerUserID)); err != nil {
+			logger.Warn("Failed to register Jira tool", zap.Error(err))
+		}
+	}
+
+	// 邮件发送工具使用通知服务的 SMTP 通道，每个用户单独限流
+	if emailCfg := cfg.MCP.SendEmail; emailCfg.Enabled && slices.Contains(notifier.Channels(), notify.ChannelEmail) {
+		sendMail := func(ctx context.Context, to, subject, body string) error {
+			return notifier.SendMessage(ctx, notify.ChannelEmail, to, notify.Message{Subject: subject, Text: body})
+		}
+		emailTool := tools.NewSendEmailTool(tools.SendEmailConfig{
+			AllowedRecipients: emailCfg.AllowedRecipients,
+			AllowSelf:         emailCfg.AllowSelf,
+			MaxRecipients:     emailCfg.MaxRecipients,
+			MaxBodyBytes:      emailCfg.MaxBodyKB << 10,
+			RateLimit:         ratelimit.Rule{Rate: float64(emailCfg.PerUserPerHour) / 3600, Burst: emailCfg.PerUserPerHour},
+		}, sendMail, service.NewCallerEmailLookup(repoManager.User()), ratelimit.NewMemoryStore(), logger)
+		if err := mcpService.RegisterTool(emailTool); err != nil {
+			logger.Warn("Failed to register send email tool", zap.Error(err))
+		}
+	}
+
+	// Google Sheets 导出工具使用服务账号，新建的表格共享给调用者和配置的邮箱
+	if sheetsCfg := cfg.MCP.Sheets; sheetsCfg.CredentialsFile != "" {
+		credentials, err := os.ReadFile(sheetsCfg.CredentialsFile)
+		var tokens *googleauth.TokenSource
+		if err == nil {
+			tokens, err = googleauth.NewTokenSource(credentials, []string{googleauth.ScopeSpreadsheets, googleauth.ScopeDriveFile}, "")
+		}
+		if err != nil {
+			logger.Warn("Failed to load Google service account for sheets export tool", zap.Error(err))
+		} else if err := mcpService.RegisterTool(tools.NewSheetsExportTool(tools.SheetsExportConfig{
+			ShareWith:       sheetsCfg.ShareWith,
+			ShareWithCaller: sheetsCfg.ShareWithCaller,
+			MaxRows:         sheetsCfg.MaxRows,
+		}, tokens.Token, service.NewCallerEmailLookup(repoManager.User()))); err != nil {
+			logger.Warn("Failed to register sheets export tool", zap.Error(err))
+		}
+	}
+
+	// Slack 消息工具，只能发送到配置的频道
+	if slackCfg := cfg.MCP.Slack; (slackCfg.BotToken != "" && len(slackCfg.Channels) > 0) || len(slackCfg.Webhooks) > 0 {
+		slackTool := tools.NewSlackPostTool(tools.SlackPostConfig{
+			BotToken: slackCfg.BotToken,
+			Channels: slackCfg.Channels,
+			Webhooks: slackCfg.Webhooks,
+		}, service.CallerUserID, logger)
+		if err := mcpService.RegisterTool(slackTool); err != nil {
+			logger.Warn("Failed to register Slack post tool", zap.Error(err))
+		}
+	}
+
+	// 日历工具，Google Calendar 默认以域范围授权访问调用者自己的日历
+	if calendarCfg := cfg.MCP.Calendar; calendarCfg.Provider != "" {
+		if calendarTool, err := newCalendarTool(calendarCfg, service.NewCallerEmailLookup(repoManager.User())); err != nil {
+			logger.Warn("Failed to configure calendar tool", zap.Error(err))
+		} else if err := mcpService.RegisterTool(calendarTool); err != nil {
+			logger.Warn("Failed to register calendar tool", zap.Error(err))
+		}
+	}
+
+	// 新闻订阅源聚合工具，只读取配置的 http(s) 订阅源
+	if rssCfg := cfg.MCP.RSSFeed; len(rssCfg.Feeds) > 0 {
+		feeds := make([]tools.RSSFeed, 0, len(rssCfg.Feeds))
+		for _, feed := range rssCfg.Feeds {
+			if u, err := url.Parse(feed.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || feed.Name == "" {
+				logger.Warn("Skipping invalid RSS feed", zap.String("name", feed.Name), zap.String("url", feed.URL))
+				continue
+			}
+			feeds = append(feeds, tools.RSSFeed{Name: feed.Name, URL: feed.URL, Category: feed.Category})
+		}
+		if len(feeds) > 0 {
+			rssTool := tools.NewRSSFeedTool(tools.RSSFeedConfig{
+				Feeds:    feeds,
+				CacheTTL: time.Duration(rssCfg.CacheMinutes) * time.Minute,
+				MaxItems: rssCfg.MaxItems,
+			})
+			if err := mcpService.RegisterTool(rssTool); err != nil {
+				logger.Warn("Failed to register RSS feed tool", zap.Error(err))
+			}
+		}
+	}
+	return mcpService
+}
+
+// newCalendarTool 按配置创建日历后端和日历工具
+func newCalendarTool(calendarCfg config.CalendarConfig, caller tools.CallerEmailFunc) (*tools.CalendarTool, error) {
+	location, err := time.LoadLocation(calendarCfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load calendar timezone: %w", err)
+	}
+
+	var cal calendar.Calendar
+	switch calendarCfg.Provider {
+	case calendar.ProviderGoogle:
+		credentials, err := os.ReadFile(calendarCfg.Google.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("read google credentials: %w", err)
+		}
+		tokens, err := googleauth.NewTokenSource(credentials, []string{googleauth.ScopeCalendar}, "")
+		if err != nil {
+			return nil, err
+		}
+		cal = calendar.NewGoogle(calendar.GoogleConfig{
+			CalendarID:        calendarCfg.Google.CalendarID,
+			ImpersonateCaller: calendarCfg.Google.ImpersonateCaller,
+		}, googleauth.NewSubjectTokens(tokens).Token, location)
+	case calendar.ProviderCalDAV:
+		if calendarCfg.CalDAV.URL == "" {
+			return nil, fmt.Errorf("mcp.calendar.caldav.url is required")
+		}
+		cal = calendar.NewCalDAV(calendar.CalDAVConfig{
+			URL:      calendarCfg.CalDAV.URL,
+			Username: calendarCfg.CalDAV.Username,
+			Password: calendarCfg.CalDAV.Password,
+		}, location)
+	default:
+		return nil, fmt.Errorf("unknown calendar provider %q", calendarCfg.Provider)
+	}
+	return tools.NewCalendarTool(cal, location, caller), nil
 }
 
 // ProvideMCPController 提供MCP控制器
@@ -149,41 +506,133 @@ func ProvideGoogleAIService(cfg *config.Config, zapLogger *zap.Logger) (*service
 
 
 // ProvideProviderManager 提供Provider管理器
-func ProvideProviderManager(openaiService *service.OpenAIService, googleaiService *service.GoogleAIService, zapLogger *zap.Logger) *provider.Manager {
+func ProvideProviderManager(cfg *config.Config, openaiService *service.OpenAIService, googleaiService *service.GoogleAIService, zapLogger *zap.Logger) (*provider.Manager, error) {
 	// 使用全局日志器
 	globalLogger := logger.GetGlobalLogger()
 	manager := provider.NewManager(globalLogger)
 	
-	// 创建并注册OpenAI Provider
-	openaiProvider := provider.NewOpenAIProvider(openaiService)
-	manager.RegisterProvider(openaiProvider)
-	
-	// 创建并注册Google AI Provider
-	googleaiProvider := provider.NewGoogleAIProvider(googleaiService)
-	manager.RegisterProvider(googleaiProvider)
-	
+	// 沙箱模式下 OpenAI 和 Google AI 由使用相同名称和模型列表的模拟提供商代替
+	if cfg.Sandbox.Enabled {
+		ctx := context.Background()
+		for _, upstream := range []provider.Provider{provider.NewOpenAIProvider(openaiService), provider.NewGoogleAIProvider(googleaiService)} {
+			models, err := upstream.ListAllModels(ctx)
+			if err != nil {
+				return nil, err
+			}
+			stub, err := newMockProvider(cfg, upstream.GetName(), upstream.GetType(), globalLogger)
+			if err != nil {
+				return nil, err
+			}
+			stub.SetModels(models)
+			manager.RegisterProvider(stub)
+		}
+		globalLogger.Warn("Sandbox mode: OpenAI and Google AI are served by the mock provider")
+	} else {
+		// 创建并注册OpenAI Provider
+		openaiProvider := provider.NewOpenAIProvider(openaiService)
+		openaiProvider.SetKeyPool(keyPoolConfig(cfg, cfg.OpenAI.ExtraAPIKeys))
+		manager.RegisterProvider(openaiProvider)
+
+		// 创建并注册Google AI Provider
+		googleaiProvider := provider.NewGoogleAIProvider(googleaiService)
+		googleaiProvider.SetKeyPool(keyPoolConfig(cfg, cfg.GoogleAI.ExtraAPIKeys))
+		manager.RegisterProvider(googleaiProvider)
+	}
+
 	// 创建并注册Mock Provider（用于测试）
-	mockProvider := provider.NewMockProvider("mock", types.ProviderTypeMock)
+	mockProvider, err := newMockProvider(cfg, "mock", types.ProviderTypeMock, globalLogger)
+	if err != nil {
+		return nil, err
+	}
 	manager.RegisterProvider(mockProvider)
+
+	// 压测提供商（用于容量评估）
+	if lc := cfg.LoadTest; lc.Enabled {
+		loadTestProvider, err := provider.NewLoadTestProvider(provider.LoadTestOptions{
+			Models: lc.Models,
+			FirstTokenLatency: provider.LatencyDistribution{
+				Distribution: lc.FirstTokenLatency.Distribution,
+				Mean:         time.Duration(lc.FirstTokenLatency.MeanMS) * time.Millisecond,
+				StdDev:       time.Duration(lc.FirstTokenLatency.StdDevMS) * time.Millisecond,
+				Min:          time.Duration(lc.FirstTokenLatency.MinMS) * time.Millisecond,
+				Max:          time.Duration(lc.FirstTokenLatency.MaxMS) * time.Millisecond,
+			},
+			TokensPerSecond:  lc.TokensPerSecond,
+			CompletionTokens: lc.CompletionTokens,
+			Seed:             lc.Seed,
+		})
+		if err != nil {
+			return nil, err
+		}
+		manager.RegisterProvider(loadTestProvider)
+	}
 	
-	return manager
+	return manager, nil
+}
+
+// newMockProvider 创建应用了确定性输出配置和脚本场景的模拟提供商
+func newMockProvider(cfg *config.Config, name string, providerType types.ProviderType, log logger.Logger) (*provider.MockProvider, error) {
+	mockProvider := provider.NewMockProvider(name, providerType)
+	if err := mockProvider.SetOptions(provider.MockOptions{
+		Seed: cfg.Mock.Seed,
+		Usage: provider.MockUsage{
+			PromptTokens:     cfg.Mock.Usage.PromptTokens,
+			CompletionTokens: cfg.Mock.Usage.CompletionTokens,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	if len(cfg.Mock.ScenarioFiles) > 0 {
+		scenarios, err := provider.LoadMockScenarios(cfg.Mock.ScenarioFiles)
+		if err != nil {
+			return nil, err
+		}
+		mockProvider.SetScenarios(scenarios)
+		log.Info("Loaded mock provider scenarios", zap.String("provider", name), zap.Int("count", len(scenarios)))
+	}
+	return mockProvider, nil
+}
+
+// keyPoolConfig 根据配置构建附加密钥池配置
+func keyPoolConfig(cfg *config.Config, keys []string) provider.KeyPoolConfig {
+	return provider.KeyPoolConfig{
+		Keys:     keys,
+		Strategy: provider.KeyStrategy(cfg.APIKeys.PoolStrategy),
+		Cooldown: time.Duration(cfg.APIKeys.CooldownSeconds) * time.Second,
+	}
 }
 
 // ProvideAPIKeyService 提供API密钥服务
-func ProvideAPIKeyService(repoManager repository.RepositoryManager) service.APIKeyService {
-	return service.NewAPIKeyService(repoManager.APIKey())
+func ProvideAPIKeyService(repoManager repository.RepositoryManager, cfg *config.Config, store secrets.Store) service.APIKeyService {
+	return service.NewAPIKeyService(repoManager.APIKey(), time.Duration(cfg.APIKeys.RotationGraceMinutes)*time.Minute, store)
 }
 
 // ProvideAIController 提供AI控制器
-func ProvideAIController(providerManager *provider.Manager, apiKeyService service.APIKeyService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AIController {
-	return controllers.NewAIController(providerManager, apiKeyService, logger, errorHandler)
+func ProvideAIController(providerManager *provider.Manager, apiKeyService service.APIKeyService, projectService service.ProjectService, modelMetadata service.ModelMetadataService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AIController {
+	return controllers.NewAIController(providerManager, apiKeyService, projectService, modelMetadata, logger, errorHandler)
+}
+
+// ProvideModelMetadataService 提供模型元数据服务，加载已保存的元数据，并让费用指标优先使用其中的价格
+func ProvideModelMetadataService(repoManager repository.RepositoryManager, usageMetrics *metrics.AIUsageMetrics, logger *zap.Logger) service.ModelMetadataService {
+	modelMetadata := service.NewModelMetadataService(repoManager, logger)
+	if err := modelMetadata.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load model metadata", zap.Error(err))
+	}
+	usageMetrics.SetPriceLookup(func(provider, model string) (metrics.ModelPrice, bool) {
+		metadata, ok := modelMetadata.Get(provider, model)
+		if !ok || metadata.InputPrice == nil || metadata.OutputPrice == nil {
+			return metrics.ModelPrice{}, false
+		}
+		return metrics.ModelPrice{Model: model, Prompt: *metadata.InputPrice, Completion: *metadata.OutputPrice}, true
+	})
+	return modelMetadata
 }
 
 // ProvideAIAssistantService 提供AI助手服务
-func ProvideAIAssistantService(mcpService service.MCPService, openaiService *service.OpenAIService, providerManager *provider.Manager, stockAnalysisService *service.StockAnalysisService, logger *zap.Logger) *service.AIAssistantService {
+func ProvideAIAssistantService(mcpService service.MCPService, openaiService *service.OpenAIService, providerManager *provider.Manager, stockAnalysisService *service.StockAnalysisService, quotaService service.QuotaService, budgetService service.BudgetService, preferenceService service.UserPreferenceService, projectService service.ProjectService, modelMetadata service.ModelMetadataService, usageMetrics *metrics.AIUsageMetrics, events webhook.Publisher, experimentService service.ExperimentService, feedbackService service.FeedbackService, personaService service.PersonaService, memoryService service.MemoryService, logger *zap.Logger) *service.AIAssistantService {
 	// 创建适配器来实现接口
 	adapter := &ProviderManagerAdapter{manager: providerManager}
-	return service.NewAIAssistantService(mcpService, openaiService, adapter, logger)
+	return service.NewAIAssistantService(mcpService, openaiService, adapter, quotaService, budgetService, preferenceService, projectService, modelMetadata, usageMetrics, events, experimentService, feedbackService, personaService, memoryService, logger)
 }
 
 // ProviderManagerAdapter 适配器，将provider.Manager适配为service.ProviderManager接口
@@ -211,6 +660,34 @@ func (a *ProviderManagerAdapter) GetProviderByName(name string) (service.Provide
 	return &ProviderAdapter{provider: provider}, nil
 }
 
+// ValidateProviderAPIKey 使用指定密钥验证提供商API密钥
+func (a *ProviderManagerAdapter) ValidateProviderAPIKey(ctx context.Context, providerType, apiKey string) error {
+	prov, err := a.manager.GetProvider(provider.ProviderType(providerType))
+	if err != nil {
+		return err
+	}
+	return prov.ValidateAPIKey(types.WithAPIKey(ctx, apiKey))
+}
+
+// ListEnabledModels 按提供商类型列出启用的模型，获取失败的提供商被跳过
+func (a *ProviderManagerAdapter) ListEnabledModels(ctx context.Context) map[string][]string {
+	enabled := make(map[string][]string)
+	for _, providerType := range a.manager.GetProviderTypes() {
+		prov, err := a.manager.GetProvider(providerType)
+		if err != nil {
+			continue
+		}
+		models, err := prov.ListModels(ctx)
+		if err != nil {
+			continue
+		}
+		for name := range models {
+			enabled[string(providerType)] = append(enabled[string(providerType)], name)
+		}
+	}
+	return enabled
+}
+
 func (a *ProviderManagerAdapter) ValidateModelForProvider(ctx context.Context, providerName, modelName string) error {
 	return a.manager.ValidateModelForProvider(ctx, providerName, modelName)
 }
@@ -238,8 +715,8 @@ func (a *ProviderAdapter) GetName() string {
 	return a.provider.GetName()
 }
 
-func (a *ProviderAdapter) ChatCompletion(ctx context.Context, request *service.ProviderChatRequest) (*service.ProviderChatResponse, error) {
-	// 转换请求格式
+// toProviderChatRequest 转换请求格式
+func toProviderChatRequest(request *service.ProviderChatRequest) *provider.ChatRequest {
 	providerMessages := make([]provider.Message, len(request.Messages))
 	for i, msg := range request.Messages {
 		providerMessages[i] = provider.Message{
@@ -247,8 +724,8 @@ func (a *ProviderAdapter) ChatCompletion(ctx context.Context, request *service.P
 			Content: msg.Content,
 		}
 	}
-	
-	providerReq := &provider.ChatRequest{
+
+	return &provider.ChatRequest{
 		Model:       request.Model,
 		Messages:    providerMessages,
 		MaxTokens:   request.MaxTokens,
@@ -258,9 +735,18 @@ func (a *ProviderAdapter) ChatCompletion(ctx context.Context, request *service.P
 		Stream:      request.Stream,
 		Options:     request.Options,
 	}
-	
+}
+
+// ChatCompletionStream 流式聊天完成，返回 OpenAI 格式的 SSE 流
+func (a *ProviderAdapter) ChatCompletionStream(ctx context.Context, request *service.ProviderChatRequest) (io.ReadCloser, error) {
+	providerReq := toProviderChatRequest(request)
+	providerReq.Stream = true
+	return a.provider.ChatCompletionStream(ctx, providerReq)
+}
+
+func (a *ProviderAdapter) ChatCompletion(ctx context.Context, request *service.ProviderChatRequest) (*service.ProviderChatResponse, error) {
 	// 调用实际的provider
-	resp, err := a.provider.ChatCompletion(ctx, providerReq)
+	resp, err := a.provider.ChatCompletion(ctx, toProviderChatRequest(request))
 	if err != nil {
 		return nil, err
 	}
@@ -293,8 +779,13 @@ func (a *ProviderAdapter) ChatCompletion(ctx context.Context, request *service.P
 }
 
 // ProvideAIAssistantController 提供AI助手控制器
-func ProvideAIAssistantController(aiAssistantService *service.AIAssistantService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AIAssistantController {
-	return controllers.NewAIAssistantController(aiAssistantService, logger, errorHandler)
+func ProvideAIAssistantController(aiAssistantService *service.AIAssistantService, quotaService service.QuotaService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AIAssistantController {
+	return controllers.NewAIAssistantController(aiAssistantService, quotaService, logger, errorHandler)
+}
+
+// ProvideChatCompletionController 提供OpenAI兼容接口控制器
+func ProvideChatCompletionController(aiAssistantService *service.AIAssistantService, providerManager *provider.Manager, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.ChatCompletionController {
+	return controllers.NewChatCompletionController(aiAssistantService, providerManager, logger, errorHandler)
 }
 
 // ProvideInternalMCPClient 提供内部MCP客户端
@@ -306,25 +797,445 @@ func ProvideInternalMCPClient(mcpService service.MCPService) mcp.InternalMCPClie
 	return mcp.NewInternalMCPClient(mcpService, clientInfo)
 }
 
+// ProvideFXService 提供汇率服务，并注册通过它换算货币的单位换算工具。
+// 汇率经内部MCP客户端调用 Yahoo Finance 工具获取，因此工具只能在客户端创建后注册
+func ProvideFXService(mcpClient mcp.InternalMCPClient, mcpService service.MCPService, logger *zap.Logger) *service.FXService {
+	fxService := service.NewFXService(mcpClient, logger)
+	if err := mcpService.RegisterTool(tools.NewConvertUnitsTool(fxService.GetRate)); err != nil {
+		logger.Warn("Failed to register unit conversion tool", zap.Error(err))
+	}
+	return fxService
+}
+
 // ProvideStockAnalysisService 提供股票分析服务
-func ProvideStockAnalysisService(mcpClient mcp.InternalMCPClient, logger *zap.Logger) *service.StockAnalysisService {
-	return service.NewStockAnalysisService(mcpClient, logger)
+func ProvideStockAnalysisService(mcpClient mcp.InternalMCPClient, fxService *service.FXService, i18nManager *i18n.Manager, cfg *config.Config, logger *zap.Logger) *service.StockAnalysisService {
+	return service.NewStockAnalysisService(mcpClient, fxService, i18nManager, cfg.Stock.RiskFreeRate, logger)
+}
+
+// ProvideStockReportService 提供股票分析报告服务
+func ProvideStockReportService(stockAnalysisService *service.StockAnalysisService, cfg *config.Config, logger *zap.Logger) *service.StockReportService {
+	return service.NewStockReportService(stockAnalysisService, cfg.Report.FontPath, logger)
+}
+
+// ProvideAuthService 提供认证服务
+func ProvideAuthService(repoManager repository.RepositoryManager, jwtManager *utils.JWTManager, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) service.AuthService {
+	return service.NewAuthService(repoManager, jwtManager, cfg.JWT.RefreshExpireTime, cfg.JWT.ImpersonationTTL, cfg.JWT.ReauthMaxAge, events, logger)
+}
+
+// ProvideAuthController 提供认证控制器
+func ProvideAuthController(authService service.AuthService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AuthController {
+	return controllers.NewAuthController(authService, logger, errorHandler)
+}
+
+// ProvideAPITokenService 提供个人访问令牌服务
+func ProvideAPITokenService(repoManager repository.RepositoryManager, logger *zap.Logger) service.APITokenService {
+	return service.NewAPITokenService(repoManager, logger)
+}
+
+// ProvideAPITokenController 提供个人访问令牌控制器
+func ProvideAPITokenController(apiTokenService service.APITokenService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.APITokenController {
+	return controllers.NewAPITokenController(apiTokenService, logger, errorHandler)
+}
+
+// ProvideUserPreferenceService 提供用户偏好设置服务
+func ProvideUserPreferenceService(repoManager repository.RepositoryManager, logger *zap.Logger) service.UserPreferenceService {
+	return service.NewUserPreferenceService(repoManager, logger)
+}
+
+// ProvideUserPreferenceController 提供用户偏好设置控制器
+func ProvideUserPreferenceController(preferenceService service.UserPreferenceService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.UserPreferenceController {
+	return controllers.NewUserPreferenceController(preferenceService, logger, errorHandler)
+}
+
+// ProvideProjectService 提供项目服务
+func ProvideProjectService(repoManager repository.RepositoryManager, apiKeyService service.APIKeyService, logger *zap.Logger) service.ProjectService {
+	return service.NewProjectService(repoManager, apiKeyService, logger)
+}
+
+// ProvideProjectController 提供项目控制器
+func ProvideProjectController(projectService service.ProjectService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.ProjectController {
+	return controllers.NewProjectController(projectService, logger, errorHandler)
+}
+
+// ProvideUserPermissionService 提供用户权限服务
+func ProvideUserPermissionService(repoManager repository.RepositoryManager, events webhook.Publisher, logger *zap.Logger) service.UserPermissionService {
+	return service.NewUserPermissionService(repoManager, events, logger)
+}
+
+// ProvideUserAdminService 提供用户管理服务
+func ProvideUserAdminService(repoManager repository.RepositoryManager, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) service.UserAdminService {
+	return service.NewUserAdminService(repoManager, cfg.User.PurgeRetentionDays, events, logger)
+}
+
+// ProvideQuotaService 提供AI用量配额服务
+func ProvideQuotaService(repoManager repository.RepositoryManager, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) service.QuotaService {
+	policy := service.QuotaPolicy{
+		Enabled:        cfg.Quota.Enabled,
+		WarningPercent: cfg.Quota.WarningPercent,
+		Default:        toQuotaLimits(cfg.Quota.Default),
+		Roles:          make(map[string]service.QuotaLimits, len(cfg.Quota.Roles)),
+		Users:          make(map[string]service.QuotaLimits, len(cfg.Quota.Users)),
+	}
+	for role, limit := range cfg.Quota.Roles {
+		policy.Roles[strings.ToLower(role)] = toQuotaLimits(limit)
+	}
+	for username, limit := range cfg.Quota.Users {
+		policy.Users[strings.ToLower(username)] = toQuotaLimits(limit)
+	}
+	return service.NewQuotaService(repoManager, policy, events, logger)
+}
+
+// toQuotaLimits 将配置中的配额上限转换为服务层结构
+func toQuotaLimits(limit config.QuotaLimit) service.QuotaLimits {
+	return service.QuotaLimits{
+		RequestsPerDay: limit.RequestsPerDay,
+		TokensPerMonth: limit.TokensPerMonth,
+	}
+}
+
+// ProvideBudgetService 提供AI费用预算服务，费用按用量指标中的模型价格计算
+func ProvideBudgetService(repoManager repository.RepositoryManager, usageMetrics *metrics.AIUsageMetrics, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) service.BudgetService {
+	policy := service.BudgetPolicy{
+		Enabled:   cfg.Budget.Enabled,
+		Default:   toBudgetLimit(cfg.Budget.Default),
+		Users:     make(map[string]service.BudgetLimit, len(cfg.Budget.Users)),
+		Projects:  make(map[string]service.BudgetLimit, len(cfg.Budget.Projects)),
+		Providers: make(map[string]service.BudgetLimit, len(cfg.Budget.Providers)),
+	}
+	for username, limit := range cfg.Budget.Users {
+		policy.Users[strings.ToLower(username)] = toBudgetLimit(limit)
+	}
+	for project, limit := range cfg.Budget.Projects {
+		policy.Projects[strings.ToLower(project)] = toBudgetLimit(limit)
+	}
+	for providerType, limit := range cfg.Budget.Providers {
+		policy.Providers[strings.ToLower(providerType)] = toBudgetLimit(limit)
+	}
+	return service.NewBudgetService(repoManager, usageMetrics, policy, events, logger)
+}
+
+// toBudgetLimit 将配置中的预算阈值转换为服务层结构
+func toBudgetLimit(limit config.BudgetLimit) service.BudgetLimit {
+	return service.BudgetLimit{
+		SoftUSD: limit.SoftUSD,
+		HardUSD: limit.HardUSD,
+	}
+}
+
+// ProvideUserPurgeJob 提供软删除用户清理任务
+func ProvideUserPurgeJob(userAdminService service.UserAdminService, cfg *config.Config, logger *zap.Logger) *service.UserPurgeJob {
+	return service.NewUserPurgeJob(userAdminService, time.Duration(cfg.User.PurgeIntervalHours)*time.Hour, logger)
+}
+
+// ProvideAPIKeyValidationJob 提供API密钥定期验证任务
+func ProvideAPIKeyValidationJob(apiKeyService service.APIKeyService, providerManager *provider.Manager, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) *service.APIKeyValidationJob {
+	validator := &ProviderManagerAdapter{manager: providerManager}
+	return service.NewAPIKeyValidationJob(apiKeyService, validator, time.Duration(cfg.APIKeys.ValidationIntervalMinutes)*time.Minute, events, logger)
+}
+
+// ProvideAPIKeyExpirationJob 提供API密钥过期检查任务
+func ProvideAPIKeyExpirationJob(apiKeyService service.APIKeyService, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) *service.APIKeyExpirationJob {
+	interval := time.Duration(cfg.APIKeys.ExpirationCheckIntervalMinutes) * time.Minute
+	warnBefore := time.Duration(cfg.APIKeys.ExpiryWarningDays) * 24 * time.Hour
+	return service.NewAPIKeyExpirationJob(apiKeyService, interval, warnBefore, events, logger)
+}
+
+// ProvideWebhookDispatcher 提供出站 Webhook 投递器，事件由各服务发布
+func ProvideWebhookDispatcher(repoManager repository.RepositoryManager, cfg *config.Config, logger *zap.Logger) *webhook.Dispatcher {
+	wh := cfg.Webhooks
+	return webhook.NewDispatcher(repoManager.Webhook(), webhook.Options{
+		Enabled:      wh.Enabled,
+		Workers:      wh.Workers,
+		MaxAttempts:  wh.MaxAttempts,
+		Timeout:      time.Duration(wh.Timeout) * time.Second,
+		PollInterval: time.Duration(wh.PollIntervalSeconds) * time.Second,
+		BackoffBase:  time.Duration(wh.BackoffSeconds) * time.Second,
+		BackoffMax:   time.Duration(wh.MaxBackoffMinutes) * time.Minute,
+		Retention:    time.Duration(wh.RetentionDays) * 24 * time.Hour,
+	}, logger)
+}
+
+// ProvideEventBus 提供领域事件总线，未配置后端时不发布，关闭时发送完队列中的事件
+func ProvideEventBus(cfg *config.Config, logger *zap.Logger) (eventbus.Bus, func(), error) {
+	eb := cfg.EventBus
+	source := eb.Source
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	bus, err := eventbus.New(eventbus.Config{
+		Backend:     eb.Backend,
+		TopicPrefix: eb.TopicPrefix,
+		Source:      source,
+		BufferSize:  eb.BufferSize,
+		Timeout:     time.Duration(eb.Timeout) * time.Second,
+		NATS: eventbus.NATSConfig{
+			URL:      eb.NATS.URL,
+			Username: eb.NATS.Username,
+			Password: eb.NATS.Password,
+			Token:    eb.NATS.Token,
+		},
+		Kafka: eventbus.KafkaConfig{
+			Brokers:  eb.Kafka.Brokers,
+			ClientID: eb.Kafka.ClientID,
+			Acks:     eb.Kafka.Acks,
+		},
+	}, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create event bus: %w", err)
+	}
+	return bus, func() {
+		if err := bus.Close(); err != nil {
+			logger.Warn("关闭事件总线失败", zap.Error(err))
+		}
+	}, nil
+}
+
+// ProvideNotifier 提供邮件、Slack 和 Telegram 通知发送器
+func ProvideNotifier(cfg *config.Config) (*notify.Notifier, error) {
+	nc := cfg.Notifications
+	return notify.New(notify.Config{
+		Timeout:     time.Duration(nc.Timeout) * time.Second,
+		TemplateDir: nc.TemplateDir,
+		SMTP: notify.SMTPConfig{
+			Host:        nc.Email.Host,
+			Port:        nc.Email.Port,
+			Username:    nc.Email.Username,
+			Password:    nc.Email.Password,
+			From:        nc.Email.From,
+			ImplicitTLS: nc.Email.ImplicitTLS,
+		},
+		Slack: notify.SlackConfig{AllowedHosts: nc.Slack.AllowedHosts},
+		Telegram: notify.TelegramConfig{
+			BotToken: nc.Telegram.BotToken,
+			APIURL:   nc.Telegram.APIURL,
+		},
+	})
+}
+
+// ProvideNotificationDispatcher 提供向用户通知渠道发送事件的分发器
+func ProvideNotificationDispatcher(repoManager repository.RepositoryManager, notifier *notify.Notifier, cfg *config.Config, logger *zap.Logger) *service.NotificationDispatcher {
+	nc := cfg.Notifications
+	return service.NewNotificationDispatcher(repoManager, notifier, service.NotificationDispatcherOptions{
+		Enabled:   nc.Enabled,
+		Workers:   nc.Workers,
+		QueueSize: nc.QueueSize,
+	}, logger)
+}
+
+// ProvideNotificationService 提供用户通知渠道服务
+func ProvideNotificationService(repoManager repository.RepositoryManager, notifier *notify.Notifier, logger *zap.Logger) service.NotificationService {
+	return service.NewNotificationService(repoManager, notifier, logger)
+}
+
+// ProvideNotificationController 提供用户通知渠道控制器
+func ProvideNotificationController(notificationService service.NotificationService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.NotificationController {
+	return controllers.NewNotificationController(notificationService, logger, errorHandler)
+}
+
+// ProvideEventPublisher 提供各服务使用的事件发布者，事件同时交给 Webhook 投递器、用户通知和事件总线
+func ProvideEventPublisher(webhookDispatcher *webhook.Dispatcher, notificationDispatcher *service.NotificationDispatcher, bus eventbus.Bus) webhook.Publisher {
+	return eventbus.Fanout(webhookDispatcher, notificationDispatcher, bus)
+}
+
+// ProvideWebhookService 提供 Webhook 端点管理服务
+func ProvideWebhookService(repoManager repository.RepositoryManager, webhookDispatcher *webhook.Dispatcher, logger *zap.Logger) service.WebhookService {
+	return service.NewWebhookService(repoManager, webhookDispatcher, logger)
+}
+
+// ProvideWebhookController 提供 Webhook 端点管理控制器
+func ProvideWebhookController(webhookService service.WebhookService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.WebhookController {
+	return controllers.NewWebhookController(webhookService, logger, errorHandler)
+}
+
+// ProvideScheduler 提供定时任务调度器并注册内置任务类型。定时报告优先保存到日志归档存储，
+// 未配置时保存到 scheduler.report_dir
+func ProvideScheduler(repoManager repository.RepositoryManager, apiKeyValidationJob *service.APIKeyValidationJob, mcpService service.MCPService, archiveService service.ExecutionLogArchiveService, archiveStore archive.Store, stockReportService *service.StockReportService, providerManager *provider.Manager, events webhook.Publisher, cfg *config.Config, logger *zap.Logger) (*scheduler.Scheduler, error) {
+	sc := cfg.Scheduler
+	location, err := time.LoadLocation(sc.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load scheduler timezone: %w", err)
+	}
+
+	reportStore := archiveStore
+	if reportStore == nil {
+		if reportStore, err = archive.NewLocalStore(archive.LocalConfig{Dir: sc.ReportDir}); err != nil {
+			return nil, fmt.Errorf("create report store: %w", err)
+		}
+	}
+	if archiveStore == nil {
+		archiveService = nil
+	}
+
+	s := scheduler.New(repoManager.ScheduledJob(), scheduler.Options{
+		Enabled:      sc.Enabled,
+		PollInterval: time.Duration(sc.PollIntervalSeconds) * time.Second,
+		Timeout:      time.Duration(sc.TimeoutMinutes) * time.Minute,
+		Location:     location,
+	}, logger)
+	s.Register(service.NewAPIKeyValidationJobType(apiKeyValidationJob))
+	s.Register(service.NewLogCleanupJobType(mcpService, archiveService))
+	s.Register(service.NewStockReportJobType(stockReportService, reportStore, events))
+	s.Register(service.NewModelSyncJobType(providerManager, mcpService))
+	return s, nil
+}
+
+// ProvideSchedulerService 提供定时任务管理服务
+func ProvideSchedulerService(repoManager repository.RepositoryManager, jobScheduler *scheduler.Scheduler, logger *zap.Logger) service.SchedulerService {
+	return service.NewSchedulerService(repoManager, jobScheduler, logger)
+}
+
+// ProvideSchedulerController 提供定时任务管理控制器
+func ProvideSchedulerController(schedulerService service.SchedulerService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.SchedulerController {
+	return controllers.NewSchedulerController(schedulerService, logger, errorHandler)
+}
+
+// ProvideFineTuningService 提供 OpenAI 微调任务管理服务，沙箱模式下不调用 OpenAI
+func ProvideFineTuningService(repoManager repository.RepositoryManager, openaiService *service.OpenAIService, providerManager *provider.Manager, mcpService service.MCPService, cfg *config.Config, logger *zap.Logger) service.FineTuningService {
+	var client openai.FineTuningClient
+	if !cfg.Sandbox.Enabled {
+		client, _ = openaiService.FineTuning()
+	}
+	return service.NewFineTuningService(repoManager, client, providerManager, mcpService, logger)
+}
+
+// ProvideFineTuningSyncJob 提供微调任务定期同步任务
+func ProvideFineTuningSyncJob(fineTuningService service.FineTuningService, cfg *config.Config, logger *zap.Logger) *service.FineTuningSyncJob {
+	return service.NewFineTuningSyncJob(fineTuningService, time.Duration(cfg.OpenAI.FineTuningSyncIntervalMinutes)*time.Minute, logger)
+}
+
+// ProvideFineTuningController 提供微调任务管理控制器
+func ProvideFineTuningController(fineTuningService service.FineTuningService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.FineTuningController {
+	return controllers.NewFineTuningController(fineTuningService, logger, errorHandler)
+}
+
+// ProvideEvalService 提供提示词评测服务
+func ProvideEvalService(repoManager repository.RepositoryManager, providerManager *provider.Manager, logger *zap.Logger) service.EvalService {
+	return service.NewEvalService(repoManager, &ProviderManagerAdapter{manager: providerManager}, logger)
+}
+
+// ProvideEvalController 提供提示词评测控制器
+func ProvideEvalController(evalService service.EvalService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.EvalController {
+	return controllers.NewEvalController(evalService, logger, errorHandler)
+}
+
+// ProvideExperimentService 提供 A/B 实验服务
+func ProvideExperimentService(repoManager repository.RepositoryManager, providerManager *provider.Manager, logger *zap.Logger) service.ExperimentService {
+	return service.NewExperimentService(repoManager, &ProviderManagerAdapter{manager: providerManager}, logger)
+}
+
+// ProvideExperimentController 提供 A/B 实验控制器
+func ProvideExperimentController(experimentService service.ExperimentService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.ExperimentController {
+	return controllers.NewExperimentController(experimentService, logger, errorHandler)
+}
+
+// ProvideFeedbackService 提供回复评价服务
+func ProvideFeedbackService(repoManager repository.RepositoryManager, experimentService service.ExperimentService, evalService service.EvalService, logger *zap.Logger) service.FeedbackService {
+	return service.NewFeedbackService(repoManager, experimentService, evalService, logger)
+}
+
+// ProvideFeedbackController 提供回复评价控制器
+func ProvideFeedbackController(feedbackService service.FeedbackService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.FeedbackController {
+	return controllers.NewFeedbackController(feedbackService, logger, errorHandler)
+}
+
+// ProvidePersonaService 提供助手角色服务
+func ProvidePersonaService(repoManager repository.RepositoryManager, providerManager *provider.Manager, logger *zap.Logger) service.PersonaService {
+	return service.NewPersonaService(repoManager, &ProviderManagerAdapter{manager: providerManager}, logger)
+}
+
+// ProvidePersonaController 提供助手角色控制器
+func ProvidePersonaController(personaService service.PersonaService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.PersonaController {
+	return controllers.NewPersonaController(personaService, logger, errorHandler)
+}
+
+// ProvideMemoryService 提供用户长期记忆服务
+func ProvideMemoryService(repoManager repository.RepositoryManager, logger *zap.Logger) service.MemoryService {
+	return service.NewMemoryService(repoManager, logger)
+}
+
+// ProvideMemoryController 提供用户长期记忆控制器
+func ProvideMemoryController(memoryService service.MemoryService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.MemoryController {
+	return controllers.NewMemoryController(memoryService, logger, errorHandler)
+}
+
+// ProvideExecutionLogArchiveService 提供执行日志归档服务
+func ProvideExecutionLogArchiveService(mcpService service.MCPService, store archive.Store, cfg *config.Config, logger *zap.Logger) service.ExecutionLogArchiveService {
+	return service.NewExecutionLogArchiveService(mcpService, store, cfg.MCP.LogArchive.RetentionDays, logger)
+}
+
+// ProvideExecutionLogArchiveJob 提供执行日志定期归档任务，未配置归档存储时不启动
+func ProvideExecutionLogArchiveJob(archiveService service.ExecutionLogArchiveService, store archive.Store, cfg *config.Config, logger *zap.Logger) *service.ExecutionLogArchiveJob {
+	interval := time.Duration(cfg.MCP.LogArchive.IntervalHours) * time.Hour
+	if store == nil {
+		interval = 0
+	}
+	return service.NewExecutionLogArchiveJob(archiveService, interval, logger)
+}
+
+// ProvideExecutionLogArchiveController 提供执行日志归档管理控制器
+func ProvideExecutionLogArchiveController(archiveService service.ExecutionLogArchiveService, errorHandler *errors.ErrorHandler) *controllers.ExecutionLogArchiveController {
+	return controllers.NewExecutionLogArchiveController(archiveService, errorHandler)
+}
+
+// ProvideAdminConfigController 提供运行时配置导出导入控制器
+func ProvideAdminConfigController(cfg *config.Config, providerManager *provider.Manager, mcpService service.MCPService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AdminConfigController {
+	return controllers.NewAdminConfigController(runtimeconfig.NewService(cfg, providerManager, mcpService, logger), errorHandler)
+}
+
+// ProvideGraphQLController 提供管理后台 GraphQL 查询控制器
+func ProvideGraphQLController(userAdminService service.UserAdminService, repoManager repository.RepositoryManager, providerManager *provider.Manager, mcpService service.MCPService, errorHandler *errors.ErrorHandler) (*controllers.GraphQLController, error) {
+	schema, err := graphqlapi.NewSchema(graphqlapi.Services{
+		Users:      userAdminService,
+		UserLookup: repoManager.User(),
+		Providers:  providerManager,
+		MCP:        mcpService,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return controllers.NewGraphQLController(schema, errorHandler), nil
+}
+
+// ProvideAdminUserController 提供用户管理控制器
+func ProvideAdminUserController(userAdminService service.UserAdminService, authService service.AuthService, permissionService service.UserPermissionService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.AdminUserController {
+	return controllers.NewAdminUserController(userAdminService, authService, permissionService, logger, errorHandler)
 }
 
 // ProvideStockController 提供股票控制器
-func ProvideStockController(stockAnalysisService *service.StockAnalysisService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.StockController {
-	return controllers.NewStockController(stockAnalysisService, logger, errorHandler)
+func ProvideStockController(stockAnalysisService *service.StockAnalysisService, stockReportService *service.StockReportService, preferenceService service.UserPreferenceService, logger *zap.Logger, errorHandler *errors.ErrorHandler) *controllers.StockController {
+	return controllers.NewStockController(stockAnalysisService, stockReportService, preferenceService, logger, errorHandler)
 }
 
 // ProvideI18nManager 提供国际化管理器
 func ProvideI18nManager() (*i18n.Manager, error) {
-	supportedLangs := []string{"en", "zh"}
+	supportedLangs := []string{"en", "zh", "ja", "es", "de"}
 	return i18n.NewManager("en", supportedLangs)
 }
 
-// ProvideErrorHandler 提供错误处理器
-func ProvideErrorHandler(i18nManager *i18n.Manager) *errors.ErrorHandler {
-	return errors.NewErrorHandler(i18nManager)
+// ProvideErrorHandler 提供错误处理器，配置了错误上报时将严重错误发送到Sentry，按配置选择错误响应格式
+func ProvideErrorHandler(i18nManager *i18n.Manager, cfg *config.Config) (*errors.ErrorHandler, error) {
+	environment := cfg.ErrorReporting.Environment
+	if environment == "" {
+		environment = cfg.Server.Mode
+	}
+	if err := errreport.Init(errreport.Options{
+		DSN:         cfg.ErrorReporting.DSN,
+		Environment: environment,
+		Release:     cfg.ErrorReporting.Release,
+		SampleRate:  cfg.ErrorReporting.SampleRate,
+	}); err != nil {
+		return nil, err
+	}
+
+	format := cfg.ErrorResponse.Format
+	if format != "" && format != errors.FormatDefault && format != errors.FormatProblem {
+		return nil, fmt.Errorf("unsupported error response format: %s", format)
+	}
+
+	handler := errors.NewErrorHandler(i18nManager)
+	handler.SetReporter(errreport.ReportAppError)
+	handler.SetProblemDetails(format == errors.FormatProblem, cfg.ErrorResponse.TypeBaseURI)
+	return handler, nil
 }
 
 // ProvideTestI18nController 提供测试国际化控制器
@@ -333,6 +1244,191 @@ func ProvideTestI18nController() *controllers.TestI18nController {
 }
 
 // ProvideRouter 提供路由器
-func ProvideRouter(logger *zap.Logger, jwtManager *utils.JWTManager, mcpController *controllers.MCPController, aiController *controllers.AIController, aiAssistantController *controllers.AIAssistantController, stockController *controllers.StockController, testI18nController *controllers.TestI18nController, i18nManager *i18n.Manager) *gin.Engine {
-	return route.SetupRoutes(logger, jwtManager, mcpController, aiController, aiAssistantController, stockController, testI18nController, i18nManager)
+func ProvideRouter(logger *zap.Logger, cfg *config.Config, jwtManager *utils.JWTManager, apiTokenService service.APITokenService, repoManager repository.RepositoryManager, permissionService service.UserPermissionService, preferenceService service.UserPreferenceService, authController *controllers.AuthController, apiTokenController *controllers.APITokenController, userPreferenceController *controllers.UserPreferenceController, notificationController *controllers.NotificationController, projectController *controllers.ProjectController, adminUserController *controllers.AdminUserController, executionLogArchiveController *controllers.ExecutionLogArchiveController, providerCaptureController *controllers.ProviderCaptureController, adminConfigController *controllers.AdminConfigController, webhookController *controllers.WebhookController, schedulerController *controllers.SchedulerController, fineTuningController *controllers.FineTuningController, evalController *controllers.EvalController, experimentController *controllers.ExperimentController, feedbackController *controllers.FeedbackController, personaController *controllers.PersonaController, memoryController *controllers.MemoryController, graphqlController *controllers.GraphQLController, mcpController *controllers.MCPController, aiController *controllers.AIController, aiAssistantController *controllers.AIAssistantController, chatCompletionController *controllers.ChatCompletionController, stockController *controllers.StockController, testI18nController *controllers.TestI18nController, i18nManager *i18n.Manager, usageMetrics *metrics.AIUsageMetrics, limiter *ratelimit.Limiter, idempotencyStore idempotency.Store, chaosInjector *chaos.Injector) *gin.Engine {
+	var metricsHandler http.Handler
+	if cfg.Metrics.Enabled {
+		metricsHandler = usageMetrics.Handler()
+	}
+	var adminUI http.Handler
+	if cfg.AdminUI.Enabled {
+		adminUI = webui.Handler()
+	}
+	accessLog := middleware.AccessLogOptions{
+		LogBodies:    cfg.Log.Access.LogBodies,
+		MaxBodyBytes: cfg.Log.Access.MaxBodyBytes,
+	}
+	cors := middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           time.Duration(cfg.CORS.MaxAgeSeconds) * time.Second,
+	}
+	var compress *middleware.CompressOptions
+	if cfg.Compression.Enabled {
+		compress = &middleware.CompressOptions{
+			MinLength:    cfg.Compression.MinLength,
+			ExcludePaths: cfg.Compression.ExcludePaths,
+		}
+	}
+	bodyLimit := middleware.BodyLimitOptions{Default: cfg.BodyLimit.DefaultBytes}
+	for _, p := range cfg.BodyLimit.Paths {
+		bodyLimit.Paths = append(bodyLimit.Paths, middleware.PathBodyLimit{Prefix: p.Prefix, MaxBytes: p.MaxBytes})
+	}
+	timeout := middleware.TimeoutOptions{Default: time.Duration(cfg.Timeout.DefaultSeconds) * time.Second}
+	for _, p := range cfg.Timeout.Paths {
+		timeout.Paths = append(timeout.Paths, middleware.PathTimeout{Prefix: p.Prefix, Timeout: time.Duration(p.Seconds) * time.Second})
+	}
+	idempotent := middleware.IdempotencyOptions{
+		Store: idempotencyStore,
+		TTL:   time.Duration(cfg.Idempotency.TTLHours) * time.Hour,
+	}
+	return route.SetupRoutes(logger, cfg.Server.TrustedProxies, accessLog, cors, compress, bodyLimit, timeout, chaosInjector, limiter, idempotent, jwtManager, apiTokenService, repoManager.User(), permissionService, preferenceService, time.Duration(cfg.JWT.ReauthMaxAge)*time.Minute, authController, apiTokenController, userPreferenceController, notificationController, projectController, adminUserController, executionLogArchiveController, providerCaptureController, adminConfigController, webhookController, schedulerController, fineTuningController, evalController, experimentController, feedbackController, personaController, memoryController, graphqlController, mcpController, aiController, aiAssistantController, chatCompletionController, stockController, testI18nController, i18nManager, metricsHandler, cfg.Metrics.Token, adminUI)
+}
+
+// ProvideGRPCServer 提供与HTTP并行运行的gRPC服务，和HTTP控制器共用同一组服务
+func ProvideGRPCServer(cfg *config.Config, logger *zap.Logger, jwtManager *utils.JWTManager, apiTokenService service.APITokenService, repoManager repository.RepositoryManager, aiAssistantService *service.AIAssistantService, mcpService service.MCPService, providerManager *provider.Manager) (*grpcapi.Server, error) {
+	auth := grpcapi.NewAuthenticator(jwtManager, apiTokenService, logger)
+	return grpcapi.NewServer(cfg.Server.GRPC, grpcapi.Services{
+		Assistant: aiAssistantService,
+		MCP:       mcpService,
+		Providers: providerManager,
+		Users:     repoManager.User(),
+	}, auth, logger)
+}
+
+// ProvideRateLimiter 提供请求限流器，未启用时返回 nil
+func ProvideRateLimiter(cfg *config.Config) (*ratelimit.Limiter, func(), error) {
+	rl := cfg.RateLimit
+	if !rl.Enabled {
+		return nil, func() {}, nil
+	}
+
+	rules := ratelimit.Rules{
+		Global:  ratelimit.Rule{Rate: rl.Global.Rate, Burst: rl.Global.Burst},
+		PerIP:   ratelimit.Rule{Rate: rl.PerIP.Rate, Burst: rl.PerIP.Burst},
+		PerUser: ratelimit.Rule{Rate: rl.PerUser.Rate, Burst: rl.PerUser.Burst},
+		Groups:  make(map[string]ratelimit.Rule, len(rl.Groups)),
+	}
+	for _, g := range rl.Groups {
+		rules.Groups[g.Name] = ratelimit.Rule{Rate: g.Rate, Burst: g.Burst}
+	}
+
+	switch rl.Backend {
+	case "", "memory":
+		return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), rules), func() {}, nil
+	case "redis":
+		client, err := newRedisClient(rl.Redis)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect to rate limit redis: %w", err)
+		}
+		store := ratelimit.NewRedisStore(client, rl.Redis.KeyPrefix)
+		return ratelimit.NewLimiter(store, rules), func() { store.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported rate limit backend: %s", rl.Backend)
+	}
+}
+
+// ProvideIdempotencyStore 提供幂等记录存储，未启用时返回 nil
+func ProvideIdempotencyStore(cfg *config.Config) (idempotency.Store, func(), error) {
+	ic := cfg.Idempotency
+	if !ic.Enabled {
+		return nil, func() {}, nil
+	}
+
+	switch ic.Backend {
+	case "", "memory":
+		return idempotency.NewMemoryStore(), func() {}, nil
+	case "redis":
+		client, err := newRedisClient(ic.Redis)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect to idempotency redis: %w", err)
+		}
+		store := idempotency.NewRedisStore(client, ic.Redis.KeyPrefix)
+		return store, func() { store.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported idempotency backend: %s", ic.Backend)
+	}
+}
+
+// ProvideProviderCapture 提供AI提供商调用捕获器，未启用时返回 nil；启用时对 OpenAI 和 Google AI 客户端生效
+func ProvideProviderCapture(cfg *config.Config, logger *zap.Logger) (*capture.Recorder, error) {
+	pc := cfg.ProviderCapture
+	if !pc.Enabled {
+		return nil, nil
+	}
+
+	recorder, err := capture.New(capture.Options{Size: pc.Size, MaxBodyBytes: pc.MaxBodyBytes})
+	if err != nil {
+		return nil, err
+	}
+	capture.SetDefault(recorder)
+	logger.Warn("Provider request capture is enabled, prompts and responses are kept in memory",
+		zap.Int("size", pc.Size))
+	return recorder, nil
+}
+
+// ProvideProviderCaptureController 提供AI提供商调用捕获查询控制器
+func ProvideProviderCaptureController(recorder *capture.Recorder, errorHandler *errors.ErrorHandler) *controllers.ProviderCaptureController {
+	return controllers.NewProviderCaptureController(recorder, errorHandler)
+}
+
+// ProvideChaosInjector 提供故障注入器，未启用时返回 nil；启用 providers 时同时对AI提供商调用生效
+func ProvideChaosInjector(cfg *config.Config, logger *zap.Logger) (*chaos.Injector, error) {
+	cc := cfg.Chaos
+	if !cc.Enabled {
+		return nil, nil
+	}
+
+	injector, err := chaos.New(chaos.Options{
+		LatencyRate:   cc.LatencyRate,
+		MaxLatency:    time.Duration(cc.MaxLatencyMS) * time.Millisecond,
+		ErrorRate:     cc.ErrorRate,
+		ErrorStatuses: cc.ErrorStatuses,
+		ResetRate:     cc.ResetRate,
+		Paths:         cc.Paths,
+		Seed:          cc.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cc.Providers {
+		chaos.SetDefault(injector)
+	}
+	logger.Warn("Chaos fault injection is enabled",
+		zap.Float64("latency_rate", cc.LatencyRate),
+		zap.Float64("error_rate", cc.ErrorRate),
+		zap.Float64("reset_rate", cc.ResetRate),
+		zap.Bool("providers", cc.Providers))
+	return injector, nil
+}
+
+// newRedisClient 创建Redis客户端并检查连接
+func newRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// ProvideAIUsageMetrics 提供AI用量指标
+func ProvideAIUsageMetrics(cfg *config.Config) *metrics.AIUsageMetrics {
+	prices := make([]metrics.ModelPrice, 0, len(cfg.Metrics.ModelPrices))
+	for _, p := range cfg.Metrics.ModelPrices {
+		prices = append(prices, metrics.ModelPrice{
+			Model:      p.Model,
+			Prompt:     p.Prompt,
+			Completion: p.Completion,
+		})
+	}
+	return metrics.NewAIUsageMetrics(prices)
 }
@@ -5,18 +5,23 @@ package wire
 
 import (
 	"context"
+	"time"
 	
 	"go-springAi/internal/config"
 	"go-springAi/internal/controllers"
 	"go-springAi/internal/database"
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
+	"go-springAi/internal/errreport"
+	"go-springAi/internal/grpcapi"
 
 	"go-springAi/internal/i18n"
 	"go-springAi/internal/provider"
 	"go-springAi/internal/repository"
+	"go-springAi/internal/scheduler"
 	"go-springAi/internal/service"
 	"go-springAi/internal/utils"
+	"go-springAi/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
@@ -35,6 +40,9 @@ func InitializeApp(configPath string) (*App, func(), error) {
 		// 数据库
 		ProvideDatabase,
 
+		// 外部密钥存储
+		ProvideSecretsStore,
+
 		// JWT管理器
 		ProvideJWTManager,
 
@@ -53,15 +61,72 @@ func InitializeApp(configPath string) (*App, func(), error) {
 		// Services
 		ProvideMCPService,
 		ProvideInternalMCPClient,
+		ProvideFXService,
 		ProvideOpenAIService,
 		ProvideGoogleAIService,
 		ProvideAPIKeyService,
 		ProvideStockAnalysisService,
+		ProvideStockReportService,
+		ProvideQuotaService,
+		ProvideBudgetService,
 		ProvideAIAssistantService,
+		ProvideAuthService,
+		ProvideAPITokenService,
+		ProvideUserPreferenceService,
+		ProvideModelMetadataService,
+		ProvideProjectService,
+		ProvideUserPermissionService,
+		ProvideUserAdminService,
+		ProvideUserPurgeJob,
+		ProvideAPIKeyValidationJob,
+		ProvideAPIKeyExpirationJob,
+		ProvideFineTuningService,
+		ProvideFineTuningSyncJob,
+		ProvideEvalService,
+		ProvideExperimentService,
+		ProvideFeedbackService,
+		ProvidePersonaService,
+		ProvideMemoryService,
+		ProvideWebhookDispatcher,
+		ProvideWebhookService,
+		ProvideScheduler,
+		ProvideSchedulerService,
+		ProvideNotifier,
+		ProvideNotificationDispatcher,
+		ProvideNotificationService,
+		ProvideEventBus,
+		ProvideEventPublisher,
+		ProvideLogArchiveStore,
+		ProvideExecutionLogArchiveService,
+		ProvideExecutionLogArchiveJob,
+		ProvideAIUsageMetrics,
+		ProvideRateLimiter,
+		ProvideIdempotencyStore,
+		ProvideChaosInjector,
+		ProvideProviderCapture,
 
 		// Controllers
+		ProvideAuthController,
+		ProvideAPITokenController,
+		ProvideUserPreferenceController,
+		ProvideNotificationController,
+		ProvideProjectController,
+		ProvideAdminUserController,
+		ProvideExecutionLogArchiveController,
+		ProvideProviderCaptureController,
+		ProvideAdminConfigController,
+		ProvideWebhookController,
+		ProvideSchedulerController,
+		ProvideFineTuningController,
+		ProvideEvalController,
+		ProvideExperimentController,
+		ProvideFeedbackController,
+		ProvidePersonaController,
+		ProvideMemoryController,
+		ProvideGraphQLController,
 		ProvideMCPController,
 		ProvideAIAssistantController,
+		ProvideChatCompletionController,
 		ProvideTestI18nController,
 		ProvideStockController,
 
@@ -74,6 +139,9 @@ func InitializeApp(configPath string) (*App, func(), error) {
 		// Router
 		ProvideRouter,
 
+		// gRPC
+		ProvideGRPCServer,
+
 		// App
 		NewApp,
 	)
@@ -102,6 +170,15 @@ type App struct {
 	StockController        *controllers.StockController
 	ProviderManager        *provider.Manager
 	AIController           *controllers.AIController
+	UserPurgeJob           *service.UserPurgeJob
+	LogArchiveJob          *service.ExecutionLogArchiveJob
+	APIKeyValidationJob    *service.APIKeyValidationJob
+	APIKeyExpirationJob    *service.APIKeyExpirationJob
+	FineTuningSyncJob      *service.FineTuningSyncJob
+	WebhookDispatcher      *webhook.Dispatcher
+	NotificationDispatcher *service.NotificationDispatcher
+	Scheduler              *scheduler.Scheduler
+	GRPCServer             *grpcapi.Server
 	Router                 *gin.Engine
 }
 
@@ -127,37 +204,88 @@ func NewApp(
 	stockController *controllers.StockController,
 	providerManager *provider.Manager,
 	aiController *controllers.AIController,
+	userPurgeJob *service.UserPurgeJob,
+	logArchiveJob *service.ExecutionLogArchiveJob,
+	apiKeyValidationJob *service.APIKeyValidationJob,
+	apiKeyExpirationJob *service.APIKeyExpirationJob,
+	fineTuningSyncJob *service.FineTuningSyncJob,
+	webhookDispatcher *webhook.Dispatcher,
+	notificationDispatcher *service.NotificationDispatcher,
+	jobScheduler *scheduler.Scheduler,
+	grpcServer *grpcapi.Server,
 	router *gin.Engine,
 ) (*App, func()) {
 	app := &App{
-		Config:                config,
-		Logger:                logger,
-		DB:                    db,
-		JWTManager:            jwtManager,
-		I18nManager:           i18nManager,
-		ErrorHandler:          errorHandler,
-		Validator:             validator,
-		RepoManager:           repoManager,
-		MCPService:            mcpService,
-		OpenAIService:         openaiService,
-		GoogleAIService:       googleaiService,
-		APIKeyService:         apiKeyService,
-		StockAnalysisService:  stockAnalysisService,
-		AIAssistantService:    aiAssistantService,
-		MCPController:         mcpController,
-		AIAssistantController: aiAssistantController,
-		TestI18nController:    testI18nController,
-		StockController:       stockController,
-		ProviderManager:       providerManager,
-		AIController:          aiController,
-		Router:                router,
+		Config:                 config,
+		Logger:                 logger,
+		DB:                     db,
+		JWTManager:             jwtManager,
+		I18nManager:            i18nManager,
+		ErrorHandler:           errorHandler,
+		Validator:              validator,
+		RepoManager:            repoManager,
+		MCPService:             mcpService,
+		OpenAIService:          openaiService,
+		GoogleAIService:        googleaiService,
+		APIKeyService:          apiKeyService,
+		StockAnalysisService:   stockAnalysisService,
+		AIAssistantService:     aiAssistantService,
+		MCPController:          mcpController,
+		AIAssistantController:  aiAssistantController,
+		TestI18nController:     testI18nController,
+		StockController:        stockController,
+		ProviderManager:        providerManager,
+		AIController:           aiController,
+		UserPurgeJob:           userPurgeJob,
+		LogArchiveJob:          logArchiveJob,
+		APIKeyValidationJob:    apiKeyValidationJob,
+		APIKeyExpirationJob:    apiKeyExpirationJob,
+		FineTuningSyncJob:      fineTuningSyncJob,
+		WebhookDispatcher:      webhookDispatcher,
+		NotificationDispatcher: notificationDispatcher,
+		Scheduler:              jobScheduler,
+		GRPCServer:             grpcServer,
+		Router:                 router,
 	}
 
 	// 自动初始化MCP系统
 	app.initializeMCPSystem()
 
+	// 启动软删除用户清理任务
+	app.UserPurgeJob.Start()
+
+	// 启动API密钥定期验证任务
+	app.APIKeyValidationJob.Start()
+
+	// 启动API密钥过期检查任务
+	app.APIKeyExpirationJob.Start()
+
+	// 启动微调任务同步
+	app.FineTuningSyncJob.Start()
+
+	// 启动执行日志归档任务
+	app.LogArchiveJob.Start()
+
+	// 启动出站 Webhook 投递
+	app.WebhookDispatcher.Start()
+
+	// 启动用户通知发送
+	app.NotificationDispatcher.Start()
+
+	// 启动定时任务调度器
+	app.Scheduler.Start()
+
 	// 清理函数
 	cleanup := func() {
+		app.UserPurgeJob.Stop()
+		app.APIKeyValidationJob.Stop()
+		app.APIKeyExpirationJob.Stop()
+		app.FineTuningSyncJob.Stop()
+		app.LogArchiveJob.Stop()
+		app.Scheduler.Stop()
+		app.NotificationDispatcher.Stop()
+		app.WebhookDispatcher.Stop()
+		errreport.Flush(2 * time.Second)
 		if app.DB != nil {
 			app.DB.Close()
 		}
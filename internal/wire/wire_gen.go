@@ -8,17 +8,22 @@ package wire
 
 import (
 	"context"
+	"time"
 	"github.com/gin-gonic/gin"
 	"go-springAi/internal/config"
 	"go-springAi/internal/controllers"
 	"go-springAi/internal/database"
 	"go-springAi/internal/dto"
 	"go-springAi/internal/errors"
+	"go-springAi/internal/errreport"
+	"go-springAi/internal/grpcapi"
 	"go-springAi/internal/i18n"
 	"go-springAi/internal/provider"
 	"go-springAi/internal/repository"
+	"go-springAi/internal/scheduler"
 	"go-springAi/internal/service"
 	"go-springAi/internal/utils"
+	"go-springAi/internal/webhook"
 	"go.uber.org/zap"
 )
 
@@ -38,33 +43,152 @@ func InitializeApp(configPath string) (*App, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	jwtManager := ProvideJWTManager(config)
+	store, err := ProvideSecretsStore(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	jwtManager, err := ProvideJWTManager(config, store)
+	if err != nil {
+		return nil, nil, err
+	}
 	manager, err := ProvideI18nManager()
 	if err != nil {
 		return nil, nil, err
 	}
-	errorHandler := ProvideErrorHandler(manager)
+	errorHandler, err := ProvideErrorHandler(manager, config)
+	if err != nil {
+		return nil, nil, err
+	}
 	customValidator := utils.NewCustomValidator()
 	repositoryManager := repository.NewRepositoryManager(db)
-	mcpService := ProvideMCPService(repositoryManager, logger)
 	openAIService := ProvideOpenAIService(config, logger)
 	googleAIService, err := ProvideGoogleAIService(config, logger)
 	if err != nil {
 		return nil, nil, err
 	}
-	apiKeyService := ProvideAPIKeyService(repositoryManager)
+	providerManager, err := ProvideProviderManager(config, openAIService, googleAIService, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	dispatcher := ProvideWebhookDispatcher(repositoryManager, config, logger)
+	notifier, err := ProvideNotifier(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	notificationDispatcher := ProvideNotificationDispatcher(repositoryManager, notifier, config, logger)
+	bus, cleanup, err := ProvideEventBus(config, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	publisher := ProvideEventPublisher(dispatcher, notificationDispatcher, bus)
+	apiKeyService := ProvideAPIKeyService(repositoryManager, config, store)
+	mcpService := ProvideMCPService(repositoryManager, providerManager, apiKeyService, manager, publisher, notifier, config, logger)
 	internalMCPClient := ProvideInternalMCPClient(mcpService)
-	stockAnalysisService := ProvideStockAnalysisService(internalMCPClient, logger)
-	providerManager := ProvideProviderManager(openAIService, googleAIService, logger)
-	aiAssistantService := ProvideAIAssistantService(mcpService, openAIService, providerManager, stockAnalysisService, logger)
+	fxService := ProvideFXService(internalMCPClient, mcpService, logger)
+	stockAnalysisService := ProvideStockAnalysisService(internalMCPClient, fxService, manager, config, logger)
+	quotaService := ProvideQuotaService(repositoryManager, publisher, config, logger)
+	userPreferenceService := ProvideUserPreferenceService(repositoryManager, logger)
+	projectService := ProvideProjectService(repositoryManager, apiKeyService, logger)
+	aiUsageMetrics := ProvideAIUsageMetrics(config)
+	modelMetadataService := ProvideModelMetadataService(repositoryManager, aiUsageMetrics, logger)
+	budgetService := ProvideBudgetService(repositoryManager, aiUsageMetrics, publisher, config, logger)
+	experimentService := ProvideExperimentService(repositoryManager, providerManager, logger)
+	evalService := ProvideEvalService(repositoryManager, providerManager, logger)
+	feedbackService := ProvideFeedbackService(repositoryManager, experimentService, evalService, logger)
+	personaService := ProvidePersonaService(repositoryManager, providerManager, logger)
+	memoryService := ProvideMemoryService(repositoryManager, logger)
+	aiAssistantService := ProvideAIAssistantService(mcpService, openAIService, providerManager, stockAnalysisService, quotaService, budgetService, userPreferenceService, projectService, modelMetadataService, aiUsageMetrics, publisher, experimentService, feedbackService, personaService, memoryService, logger)
 	mcpController := ProvideMCPController(mcpService, logger, errorHandler)
-	aiAssistantController := ProvideAIAssistantController(aiAssistantService, logger, errorHandler)
+	aiAssistantController := ProvideAIAssistantController(aiAssistantService, quotaService, logger, errorHandler)
+	chatCompletionController := ProvideChatCompletionController(aiAssistantService, providerManager, logger, errorHandler)
 	testI18nController := ProvideTestI18nController()
-	stockController := ProvideStockController(stockAnalysisService, logger, errorHandler)
-	aiController := ProvideAIController(providerManager, apiKeyService, logger, errorHandler)
-	engine := ProvideRouter(logger, jwtManager, mcpController, aiController, aiAssistantController, stockController, testI18nController, manager)
-	app, cleanup := NewApp(config, logger, db, jwtManager, manager, errorHandler, customValidator, repositoryManager, mcpService, openAIService, googleAIService, apiKeyService, stockAnalysisService, aiAssistantService, mcpController, aiAssistantController, testI18nController, stockController, providerManager, aiController, engine)
+	stockReportService := ProvideStockReportService(stockAnalysisService, config, logger)
+	stockController := ProvideStockController(stockAnalysisService, stockReportService, userPreferenceService, logger, errorHandler)
+	aiController := ProvideAIController(providerManager, apiKeyService, projectService, modelMetadataService, logger, errorHandler)
+	authService := ProvideAuthService(repositoryManager, jwtManager, publisher, config, logger)
+	authController := ProvideAuthController(authService, logger, errorHandler)
+	apiTokenService := ProvideAPITokenService(repositoryManager, logger)
+	apiTokenController := ProvideAPITokenController(apiTokenService, logger, errorHandler)
+	userPreferenceController := ProvideUserPreferenceController(userPreferenceService, logger, errorHandler)
+	notificationService := ProvideNotificationService(repositoryManager, notifier, logger)
+	notificationController := ProvideNotificationController(notificationService, logger, errorHandler)
+	projectController := ProvideProjectController(projectService, logger, errorHandler)
+	userAdminService := ProvideUserAdminService(repositoryManager, publisher, config, logger)
+	userPermissionService := ProvideUserPermissionService(repositoryManager, publisher, logger)
+	adminUserController := ProvideAdminUserController(userAdminService, authService, userPermissionService, logger, errorHandler)
+	userPurgeJob := ProvideUserPurgeJob(userAdminService, config, logger)
+	apiKeyValidationJob := ProvideAPIKeyValidationJob(apiKeyService, providerManager, publisher, config, logger)
+	apiKeyExpirationJob := ProvideAPIKeyExpirationJob(apiKeyService, publisher, config, logger)
+	archiveStore, err := ProvideLogArchiveStore(config)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	executionLogArchiveService := ProvideExecutionLogArchiveService(mcpService, archiveStore, config, logger)
+	executionLogArchiveController := ProvideExecutionLogArchiveController(executionLogArchiveService, errorHandler)
+	adminConfigController := ProvideAdminConfigController(config, providerManager, mcpService, logger, errorHandler)
+	webhookService := ProvideWebhookService(repositoryManager, dispatcher, logger)
+	webhookController := ProvideWebhookController(webhookService, logger, errorHandler)
+	schedulerScheduler, err := ProvideScheduler(repositoryManager, apiKeyValidationJob, mcpService, executionLogArchiveService, archiveStore, stockReportService, providerManager, publisher, config, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	schedulerService := ProvideSchedulerService(repositoryManager, schedulerScheduler, logger)
+	schedulerController := ProvideSchedulerController(schedulerService, logger, errorHandler)
+	fineTuningService := ProvideFineTuningService(repositoryManager, openAIService, providerManager, mcpService, config, logger)
+	fineTuningController := ProvideFineTuningController(fineTuningService, logger, errorHandler)
+	evalController := ProvideEvalController(evalService, logger, errorHandler)
+	experimentController := ProvideExperimentController(experimentService, logger, errorHandler)
+	feedbackController := ProvideFeedbackController(feedbackService, logger, errorHandler)
+	personaController := ProvidePersonaController(personaService, logger, errorHandler)
+	memoryController := ProvideMemoryController(memoryService, logger, errorHandler)
+	graphQLController, err := ProvideGraphQLController(userAdminService, repositoryManager, providerManager, mcpService, errorHandler)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	fineTuningSyncJob := ProvideFineTuningSyncJob(fineTuningService, config, logger)
+	executionLogArchiveJob := ProvideExecutionLogArchiveJob(executionLogArchiveService, archiveStore, config, logger)
+	limiter, cleanup2, err := ProvideRateLimiter(config)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	idempotencyStore, cleanup3, err := ProvideIdempotencyStore(config)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	injector, err := ProvideChaosInjector(config, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	recorder, err := ProvideProviderCapture(config, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	providerCaptureController := ProvideProviderCaptureController(recorder, errorHandler)
+	engine := ProvideRouter(logger, config, jwtManager, apiTokenService, repositoryManager, userPermissionService, userPreferenceService, authController, apiTokenController, userPreferenceController, notificationController, projectController, adminUserController, executionLogArchiveController, providerCaptureController, adminConfigController, webhookController, schedulerController, fineTuningController, evalController, experimentController, feedbackController, personaController, memoryController, graphQLController, mcpController, aiController, aiAssistantController, chatCompletionController, stockController, testI18nController, manager, aiUsageMetrics, limiter, idempotencyStore, injector)
+	grpcServer, err := ProvideGRPCServer(config, logger, jwtManager, apiTokenService, repositoryManager, aiAssistantService, mcpService, providerManager)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	app, cleanup4 := NewApp(config, logger, db, jwtManager, manager, errorHandler, customValidator, repositoryManager, mcpService, openAIService, googleAIService, apiKeyService, stockAnalysisService, aiAssistantService, mcpController, aiAssistantController, testI18nController, stockController, providerManager, aiController, userPurgeJob, executionLogArchiveJob, apiKeyValidationJob, apiKeyExpirationJob, fineTuningSyncJob, dispatcher, notificationDispatcher, schedulerScheduler, grpcServer, engine)
 	return app, func() {
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
@@ -73,27 +197,36 @@ func InitializeApp(configPath string) (*App, func(), error) {
 
 // App 应用程序结构
 type App struct {
-	Config                *config.Config
-	Logger                *zap.Logger
-	DB                    *database.DB
-	JWTManager            *utils.JWTManager
-	I18nManager           *i18n.Manager
-	ErrorHandler          *errors.ErrorHandler
-	Validator             *utils.CustomValidator
-	RepoManager           repository.RepositoryManager
-	MCPService            service.MCPService
-	OpenAIService         *service.OpenAIService
-	GoogleAIService       *service.GoogleAIService
-	APIKeyService         service.APIKeyService
-	StockAnalysisService  *service.StockAnalysisService
-	AIAssistantService    *service.AIAssistantService
-	MCPController         *controllers.MCPController
-	AIAssistantController *controllers.AIAssistantController
-	TestI18nController    *controllers.TestI18nController
-	StockController       *controllers.StockController
-	ProviderManager       *provider.Manager
-	AIController          *controllers.AIController
-	Router                *gin.Engine
+	Config                 *config.Config
+	Logger                 *zap.Logger
+	DB                     *database.DB
+	JWTManager             *utils.JWTManager
+	I18nManager            *i18n.Manager
+	ErrorHandler           *errors.ErrorHandler
+	Validator              *utils.CustomValidator
+	RepoManager            repository.RepositoryManager
+	MCPService             service.MCPService
+	OpenAIService          *service.OpenAIService
+	GoogleAIService        *service.GoogleAIService
+	APIKeyService          service.APIKeyService
+	StockAnalysisService   *service.StockAnalysisService
+	AIAssistantService     *service.AIAssistantService
+	MCPController          *controllers.MCPController
+	AIAssistantController  *controllers.AIAssistantController
+	TestI18nController     *controllers.TestI18nController
+	StockController        *controllers.StockController
+	ProviderManager        *provider.Manager
+	AIController           *controllers.AIController
+	UserPurgeJob           *service.UserPurgeJob
+	LogArchiveJob          *service.ExecutionLogArchiveJob
+	APIKeyValidationJob    *service.APIKeyValidationJob
+	APIKeyExpirationJob    *service.APIKeyExpirationJob
+	FineTuningSyncJob      *service.FineTuningSyncJob
+	WebhookDispatcher      *webhook.Dispatcher
+	NotificationDispatcher *service.NotificationDispatcher
+	Scheduler              *scheduler.Scheduler
+	GRPCServer             *grpcapi.Server
+	Router                 *gin.Engine
 }
 
 // NewApp 创建应用程序实例
@@ -117,35 +250,78 @@ func NewApp(config2 *config.Config,
 	stockController *controllers.StockController,
 	providerManager *provider.Manager,
 	aiController *controllers.AIController,
+	userPurgeJob *service.UserPurgeJob,
+	logArchiveJob *service.ExecutionLogArchiveJob,
+	apiKeyValidationJob *service.APIKeyValidationJob,
+	apiKeyExpirationJob *service.APIKeyExpirationJob,
+	fineTuningSyncJob *service.FineTuningSyncJob,
+	webhookDispatcher *webhook.Dispatcher,
+	notificationDispatcher *service.NotificationDispatcher,
+	jobScheduler *scheduler.Scheduler,
+	grpcServer *grpcapi.Server,
 	router *gin.Engine,
 ) (*App, func()) {
 	app := &App{
-		Config:                config2,
-		Logger:                logger,
-		DB:                    db,
-		JWTManager:            jwtManager,
-		I18nManager:           i18nManager,
-		ErrorHandler:          errorHandler,
-		Validator:             validator,
-		RepoManager:           repoManager,
-		MCPService:            mcpService,
-		OpenAIService:         openaiService,
-		GoogleAIService:       googleaiService,
-		APIKeyService:         apiKeyService,
-		StockAnalysisService:  stockAnalysisService,
-		AIAssistantService:    aiAssistantService,
-		MCPController:         mcpController,
-		AIAssistantController: aiAssistantController,
-		TestI18nController:    testI18nController,
-		StockController:       stockController,
-		ProviderManager:       providerManager,
-		AIController:          aiController,
-		Router:                router,
+		Config:                 config2,
+		Logger:                 logger,
+		DB:                     db,
+		JWTManager:             jwtManager,
+		I18nManager:            i18nManager,
+		ErrorHandler:           errorHandler,
+		Validator:              validator,
+		RepoManager:            repoManager,
+		MCPService:             mcpService,
+		OpenAIService:          openaiService,
+		GoogleAIService:        googleaiService,
+		APIKeyService:          apiKeyService,
+		StockAnalysisService:   stockAnalysisService,
+		AIAssistantService:     aiAssistantService,
+		MCPController:          mcpController,
+		AIAssistantController:  aiAssistantController,
+		TestI18nController:     testI18nController,
+		StockController:        stockController,
+		ProviderManager:        providerManager,
+		AIController:           aiController,
+		UserPurgeJob:           userPurgeJob,
+		LogArchiveJob:          logArchiveJob,
+		APIKeyValidationJob:    apiKeyValidationJob,
+		APIKeyExpirationJob:    apiKeyExpirationJob,
+		FineTuningSyncJob:      fineTuningSyncJob,
+		WebhookDispatcher:      webhookDispatcher,
+		NotificationDispatcher: notificationDispatcher,
+		Scheduler:              jobScheduler,
+		GRPCServer:             grpcServer,
+		Router:                 router,
 	}
 
 	app.initializeMCPSystem()
 
+	app.UserPurgeJob.Start()
+
+	app.APIKeyValidationJob.Start()
+
+	app.APIKeyExpirationJob.Start()
+
+	app.FineTuningSyncJob.Start()
+
+	app.LogArchiveJob.Start()
+
+	app.WebhookDispatcher.Start()
+
+	app.NotificationDispatcher.Start()
+
+	app.Scheduler.Start()
+
 	cleanup := func() {
+		app.UserPurgeJob.Stop()
+		app.APIKeyValidationJob.Stop()
+		app.APIKeyExpirationJob.Stop()
+		app.FineTuningSyncJob.Stop()
+		app.LogArchiveJob.Stop()
+		app.Scheduler.Stop()
+		app.NotificationDispatcher.Stop()
+		app.WebhookDispatcher.Stop()
+		errreport.Flush(2 * time.Second)
 		if app.DB != nil {
 			app.DB.Close()
 		}
@@ -0,0 +1,343 @@
+// Package workspace 为每个用户提供独立的文件工作区，供多步骤的助手会话保存中间结果（如 CSV 导出）。
+// 所有访问都经由 os.Root 限制在用户目录内，符号链接也无法逃逸；每个用户的总大小和单个文件大小受配额限制
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认配额
+const (
+	DefaultQuotaBytes   = 50 << 20
+	DefaultMaxFileBytes = 10 << 20
+	DefaultMaxFiles     = 1000
+)
+
+// MaxPathLength 相对路径的最大长度
+const MaxPathLength = 255
+
+var (
+	// ErrInvalidPath 路径为绝对路径、包含 .. 或超出工作区
+	ErrInvalidPath = errors.New("workspace: invalid path")
+	// ErrNotFound 文件或目录不存在
+	ErrNotFound = errors.New("workspace: not found")
+	// ErrQuotaExceeded 超出工作区配额
+	ErrQuotaExceeded = errors.New("workspace: quota exceeded")
+	// ErrFileTooLarge 单个文件超出大小上限
+	ErrFileTooLarge = errors.New("workspace: file too large")
+)
+
+// Config 工作区配置
+type Config struct {
+	Root         string // 所有用户工作区的根目录，每个用户一个子目录
+	QuotaBytes   int64  // 每个用户的总大小上限
+	MaxFileBytes int64  // 单个文件的大小上限
+	MaxFiles     int    // 每个用户的文件数上限
+}
+
+// Entry 工作区中的文件或目录
+type Entry struct {
+	Path       string
+	Size       int64
+	IsDir      bool
+	ModifiedAt time.Time
+}
+
+// Usage 工作区用量
+type Usage struct {
+	Bytes int64
+	Files int
+}
+
+// Manager 工作区管理器
+type Manager struct {
+	cfg Config
+	// mu 串行化写入，保证配额检查和写入之间用量不变
+	mu sync.Mutex
+}
+
+// New 创建工作区管理器，未设置的配额使用默认值
+func New(cfg Config) (*Manager, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("workspace: root is required")
+	}
+	if cfg.QuotaBytes <= 0 {
+		cfg.QuotaBytes = DefaultQuotaBytes
+	}
+	if cfg.MaxFileBytes <= 0 {
+		cfg.MaxFileBytes = DefaultMaxFileBytes
+	}
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = DefaultMaxFiles
+	}
+	if err := os.MkdirAll(cfg.Root, 0o750); err != nil {
+		return nil, fmt.Errorf("workspace: create root: %w", err)
+	}
+	return &Manager{cfg: cfg}, nil
+}
+
+// QuotaBytes 返回每个用户的总大小上限
+func (m *Manager) QuotaBytes() int64 {
+	return m.cfg.QuotaBytes
+}
+
+// MaxFileBytes 返回单个文件的大小上限
+func (m *Manager) MaxFileBytes() int64 {
+	return m.cfg.MaxFileBytes
+}
+
+// CleanPath 校验并规范化工作区内的相对路径，空路径和 "." 表示工作区根目录
+func CleanPath(name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "./")
+	if name == "" || name == "." {
+		return ".", nil
+	}
+	if len(name) > MaxPathLength || strings.ContainsAny(name, "\\\x00") || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, name)
+	}
+	return path.Clean(name), nil
+}
+
+// Read 读取文件，文件超过 MaxFileBytes 时返回 ErrFileTooLarge
+func (m *Manager) Read(userID, name string) ([]byte, error) {
+	clean, err := CleanPath(name)
+	if err != nil {
+		return nil, err
+	}
+	root, err := m.open(userID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	file, err := root.Open(clean)
+	if err != nil {
+		return nil, wrapError(err, clean)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("workspace: %s is a directory", clean)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, m.cfg.MaxFileBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > m.cfg.MaxFileBytes {
+		return nil, fmt.Errorf("%w: %s", ErrFileTooLarge, clean)
+	}
+	return data, nil
+}
+
+// Write 写入文件，append 为 true 时追加到文件末尾；父目录不存在时自动创建
+func (m *Manager) Write(userID, name string, data []byte, appendData bool) (*Entry, error) {
+	clean, err := CleanPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if clean == "." {
+		return nil, fmt.Errorf("%w: path is required", ErrInvalidPath)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	root, err := m.open(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	var existing int64
+	info, err := root.Stat(clean)
+	exists := err == nil
+	switch {
+	case exists && info.IsDir():
+		return nil, fmt.Errorf("workspace: %s is a directory", clean)
+	case exists:
+		existing = info.Size()
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, wrapError(err, clean)
+	}
+
+	size := int64(len(data))
+	if appendData {
+		size += existing
+	}
+	if size > m.cfg.MaxFileBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrFileTooLarge, size, m.cfg.MaxFileBytes)
+	}
+	usage, err := usageOf(root)
+	if err != nil {
+		return nil, err
+	}
+	if usage.Bytes-existing+size > m.cfg.QuotaBytes {
+		return nil, fmt.Errorf("%w: %d of %d bytes used", ErrQuotaExceeded, usage.Bytes, m.cfg.QuotaBytes)
+	}
+	if !exists && usage.Files >= m.cfg.MaxFiles {
+		return nil, fmt.Errorf("%w: at most %d files", ErrQuotaExceeded, m.cfg.MaxFiles)
+	}
+
+	if err := mkdirAll(root, path.Dir(clean)); err != nil {
+		return nil, wrapError(err, clean)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendData {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := root.OpenFile(clean, flags, 0o640)
+	if err != nil {
+		return nil, wrapError(err, clean)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	info, err = root.Stat(clean)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{Path: clean, Size: info.Size(), ModifiedAt: info.ModTime()}, nil
+}
+
+// List 列出目录下的文件，recursive 为 true 时包含子目录中的文件；结果按路径排序
+func (m *Manager) List(userID, dir string, recursive bool) ([]Entry, error) {
+	clean, err := CleanPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	root, err := m.open(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	info, err := root.Stat(clean)
+	if err != nil {
+		return nil, wrapError(err, clean)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("workspace: %s is not a directory", clean)
+	}
+
+	entries := []Entry{}
+	err = fs.WalkDir(root.FS(), clean, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == clean {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry := Entry{Path: name, IsDir: d.IsDir(), ModifiedAt: info.ModTime()}
+		if !d.IsDir() {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+		if d.IsDir() && !recursive {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// Usage 返回用户工作区的用量
+func (m *Manager) Usage(userID string) (Usage, error) {
+	root, err := m.open(userID, true)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer root.Close()
+	return usageOf(root)
+}
+
+// open 打开用户工作区目录，create 为 false 且目录不存在时返回 ErrNotFound
+func (m *Manager) open(userID string, create bool) (*os.Root, error) {
+	if userID == "" || userID == "." || strings.ContainsAny(userID, `/\`) || !filepath.IsLocal(userID) {
+		return nil, fmt.Errorf("%w: user %q", ErrInvalidPath, userID)
+	}
+	dir := filepath.Join(m.cfg.Root, userID)
+	if create {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, err
+		}
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, wrapError(err, ".")
+	}
+	return root, nil
+}
+
+// usageOf 统计工作区中文件的总大小和数量
+func usageOf(root *os.Root) (Usage, error) {
+	var usage Usage
+	err := fs.WalkDir(root.FS(), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		usage.Bytes += info.Size()
+		usage.Files++
+		return nil
+	})
+	return usage, err
+}
+
+// mkdirAll 在工作区内逐级创建目录
+func mkdirAll(root *os.Root, dir string) error {
+	if dir == "." {
+		return nil
+	}
+	if err := mkdirAll(root, path.Dir(dir)); err != nil {
+		return err
+	}
+	if err := root.Mkdir(dir, 0o750); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+// wrapError 将文件系统错误转换为工作区错误，不暴露服务器上的绝对路径
+func wrapError(err error, name string) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	// os.Root 拒绝逃逸出根目录的路径（包括经由符号链接）时返回 "path escapes from parent"
+	if strings.Contains(err.Error(), "escapes from parent") {
+		return fmt.Errorf("%w: %s", ErrInvalidPath, name)
+	}
+	return fmt.Errorf("workspace: %s: %w", name, err)
+}
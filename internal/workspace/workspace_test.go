@@ -0,0 +1,120 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{"", ".", false},
+		{"./", ".", false},
+		{"report.csv", "report.csv", false},
+		{"./data//aapl.csv", "data/aapl.csv", false},
+		{"a/b/../c.txt", "a/c.txt", false},
+		{"../secret", "", true},
+		{"a/../../b", "", true},
+		{"/etc/passwd", "", true},
+		{`..\windows`, "", true},
+		{"a\x00b", "", true},
+	}
+	for _, tt := range tests {
+		got, err := CleanPath(tt.input)
+		if tt.err {
+			assert.ErrorIs(t, err, ErrInvalidPath, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
+
+func TestManager(t *testing.T) {
+	root := t.TempDir()
+	m, err := New(Config{Root: root, QuotaBytes: 20, MaxFileBytes: 12, MaxFiles: 3})
+	require.NoError(t, err)
+
+	_, err = m.Read("1", "missing.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	entry, err := m.Write("1", "data/aapl.csv", []byte("a,b\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "data/aapl.csv", entry.Path)
+	_, err = m.Write("1", "data/aapl.csv", []byte("1,2\n"), true)
+	require.NoError(t, err)
+	data, err := m.Read("1", "data/aapl.csv")
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	// 用户之间相互隔离
+	_, err = m.Read("2", "data/aapl.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// 单文件大小和总配额
+	_, err = m.Write("1", "big.txt", make([]byte, 13), false)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	_, err = m.Write("1", "data/aapl.csv", []byte("12345"), true)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	_, err = m.Write("1", "b.txt", make([]byte, 12), false)
+	require.NoError(t, err)
+	_, err = m.Write("1", "c.txt", make([]byte, 1), false)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	// 覆盖已有文件时按差值计算
+	_, err = m.Write("1", "b.txt", make([]byte, 4), false)
+	require.NoError(t, err)
+	_, err = m.Write("1", "c.txt", make([]byte, 1), false)
+	require.NoError(t, err)
+	_, err = m.Write("1", "d.txt", nil, false)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+
+	usage, err := m.Usage("1")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Bytes: 13, Files: 3}, usage)
+
+	entries, err := m.List("1", "", false)
+	require.NoError(t, err)
+	var paths []string
+	for _, e := range entries {
+		paths = append(paths, e.Path)
+	}
+	assert.Equal(t, []string{"b.txt", "c.txt", "data"}, paths)
+	entries, err = m.List("1", ".", true)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+	entries, err = m.List("1", "data", false)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(8), entries[0].Size)
+
+	_, err = m.Read("1", "../2/x")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = m.Write("../1", "x", nil, false)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
+
+func TestManagerSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o600))
+
+	m, err := New(Config{Root: root})
+	require.NoError(t, err)
+	_, err = m.Write("1", "a.txt", []byte("a"), false)
+	require.NoError(t, err)
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "1", "link")))
+
+	_, err = m.Read("1", "link/secret")
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = m.Write("1", "link/new", []byte("x"), false)
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(outside, "new"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,12 @@
+-- AI费用统计表结构定义，按用户、项目、提供商和UTC日期聚合，用于每月预算（MySQL）
+CREATE TABLE IF NOT EXISTS ai_spend (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL, -- 0 表示匿名请求
+    project_id BIGINT NOT NULL DEFAULT 0, -- 0 表示未指定项目
+    provider VARCHAR(50) NOT NULL,
+    usage_date VARCHAR(10) NOT NULL, -- UTC日期，格式 YYYY-MM-DD
+    cost_usd DOUBLE NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_ai_spend_scope_date (user_id, project_id, provider, usage_date),
+    INDEX idx_ai_spend_usage_date (usage_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+-- AI费用统计表结构定义，按用户、项目、提供商和UTC日期聚合，用于每月预算（PostgreSQL）
+CREATE TABLE IF NOT EXISTS ai_spend (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL, -- 0 表示匿名请求
+    project_id BIGINT NOT NULL DEFAULT 0, -- 0 表示未指定项目
+    provider VARCHAR(50) NOT NULL,
+    usage_date VARCHAR(10) NOT NULL, -- UTC日期，格式 YYYY-MM-DD
+    cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, project_id, provider, usage_date)
+);
+
+-- 创建索引以提高按月汇总的性能
+CREATE INDEX IF NOT EXISTS idx_ai_spend_usage_date ON ai_spend(usage_date);
@@ -0,0 +1,10 @@
+-- AI用量统计表结构定义，按用户和UTC日期聚合
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL, -- 0 表示匿名请求
+    usage_date VARCHAR(10) NOT NULL, -- UTC日期，格式 YYYY-MM-DD
+    request_count INTEGER NOT NULL DEFAULT 0,
+    token_count INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, usage_date)
+);
@@ -0,0 +1,47 @@
+-- 重建两张表去掉 project_id，只保留用户的默认密钥
+CREATE TABLE api_keys_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    provider_type VARCHAR(50) NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, provider_type)
+);
+
+INSERT INTO api_keys_old (id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at)
+SELECT id, user_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at FROM api_keys WHERE project_id = 0;
+
+DROP TABLE api_keys;
+ALTER TABLE api_keys_old RENAME TO api_keys;
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_provider_type ON api_keys(provider_type);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_provider ON api_keys(user_id, provider_type);
+CREATE INDEX IF NOT EXISTS idx_api_keys_is_active ON api_keys(is_active);
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_at ON api_keys(created_at);
+
+CREATE TABLE api_key_versions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    provider_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    rotated_at DATETIME,
+    grace_expires_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, provider_type, version)
+);
+
+INSERT INTO api_key_versions_old (id, user_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at)
+SELECT id, user_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at FROM api_key_versions WHERE project_id = 0;
+
+DROP TABLE api_key_versions;
+ALTER TABLE api_key_versions_old RENAME TO api_key_versions;
+
+CREATE INDEX IF NOT EXISTS idx_api_key_versions_user_provider ON api_key_versions(user_id, provider_type);
@@ -0,0 +1,50 @@
+-- API密钥按项目分组：project_id 为 0 表示用户的默认密钥
+-- SQLite 无法修改唯一约束，这里重建两张表并迁移已有数据
+CREATE TABLE api_keys_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL DEFAULT 0,
+    provider_type VARCHAR(50) NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, project_id, provider_type) -- 每个项目每个提供商只能有一个密钥
+);
+
+INSERT INTO api_keys_new (id, user_id, project_id, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at)
+SELECT id, user_id, 0, provider_type, encrypted_key, key_hash, is_active, created_at, updated_at FROM api_keys;
+
+DROP TABLE api_keys;
+ALTER TABLE api_keys_new RENAME TO api_keys;
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_provider_type ON api_keys(provider_type);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_project_provider ON api_keys(user_id, project_id, provider_type);
+CREATE INDEX IF NOT EXISTS idx_api_keys_is_active ON api_keys(is_active);
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_at ON api_keys(created_at);
+
+CREATE TABLE api_key_versions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL DEFAULT 0,
+    provider_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    rotated_at DATETIME,
+    grace_expires_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, project_id, provider_type, version)
+);
+
+INSERT INTO api_key_versions_new (id, user_id, project_id, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at)
+SELECT id, user_id, 0, provider_type, version, encrypted_key, key_hash, created_at, rotated_at, grace_expires_at FROM api_key_versions;
+
+DROP TABLE api_key_versions;
+ALTER TABLE api_key_versions_new RENAME TO api_key_versions;
+
+CREATE INDEX IF NOT EXISTS idx_api_key_versions_user_project_provider ON api_key_versions(user_id, project_id, provider_type);
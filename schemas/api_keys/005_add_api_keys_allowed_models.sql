@@ -0,0 +1,2 @@
+-- 限制密钥只能访问指定模型，逗号分隔，为空表示不限制
+ALTER TABLE api_keys ADD COLUMN allowed_models TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_api_keys_deleted_at;
+ALTER TABLE api_keys DROP COLUMN deleted_at;
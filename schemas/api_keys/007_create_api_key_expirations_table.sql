@@ -0,0 +1,9 @@
+-- API密钥过期时间，key_hash 与当前密钥不一致时视为未设置过期时间
+CREATE TABLE IF NOT EXISTS api_key_expirations (
+    api_key_id INTEGER PRIMARY KEY,
+    key_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    warned_at DATETIME,
+    expired_at DATETIME,
+    FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE
+);
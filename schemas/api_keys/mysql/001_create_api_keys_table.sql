@@ -0,0 +1,17 @@
+-- API密钥表结构定义（MySQL）
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    provider_type VARCHAR(50) NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL, -- 用于验证密钥是否已存在，不存储明文
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_api_keys_user_provider (user_id, provider_type), -- 每个用户每个提供商只能有一个密钥
+    INDEX idx_api_keys_user_id (user_id),
+    INDEX idx_api_keys_provider_type (provider_type),
+    INDEX idx_api_keys_is_active (is_active),
+    INDEX idx_api_keys_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,15 @@
+-- API密钥版本表结构定义，记录每次轮换的历史
+CREATE TABLE IF NOT EXISTS api_key_versions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    provider_type VARCHAR(50) NOT NULL,
+    version BIGINT NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    rotated_at DATETIME, -- 被新版本替换的时间，为空表示当前版本
+    grace_expires_at DATETIME, -- 旧版本在此之前仍可用于回退
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_api_key_versions_user_provider_version (user_id, provider_type, version),
+    INDEX idx_api_key_versions_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,12 @@
+-- 去掉 project_id，只保留用户的默认密钥
+DELETE FROM api_keys WHERE project_id <> 0;
+ALTER TABLE api_keys
+    ADD UNIQUE KEY uk_api_keys_user_provider (user_id, provider_type),
+    DROP INDEX uk_api_keys_user_project_provider,
+    DROP COLUMN project_id;
+
+DELETE FROM api_key_versions WHERE project_id <> 0;
+ALTER TABLE api_key_versions
+    ADD UNIQUE KEY uk_api_key_versions_user_provider_version (user_id, provider_type, version),
+    DROP INDEX uk_api_key_versions_user_project_provider_version,
+    DROP COLUMN project_id;
@@ -0,0 +1,10 @@
+-- API密钥按项目分组：project_id 为 0 表示用户的默认密钥
+ALTER TABLE api_keys
+    ADD COLUMN project_id BIGINT NOT NULL DEFAULT 0 AFTER user_id,
+    ADD UNIQUE KEY uk_api_keys_user_project_provider (user_id, project_id, provider_type), -- 每个项目每个提供商只能有一个密钥
+    DROP INDEX uk_api_keys_user_provider;
+
+ALTER TABLE api_key_versions
+    ADD COLUMN project_id BIGINT NOT NULL DEFAULT 0 AFTER user_id,
+    ADD UNIQUE KEY uk_api_key_versions_user_project_provider_version (user_id, project_id, provider_type, version),
+    DROP INDEX uk_api_key_versions_user_provider_version;
@@ -0,0 +1,2 @@
+-- 限制密钥只能访问指定模型，逗号分隔，为空表示不限制（TEXT 列不能有默认值，这里使用 VARCHAR）
+ALTER TABLE api_keys ADD COLUMN allowed_models VARCHAR(2048) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE api_keys DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- API密钥软删除：deleted_at 非空表示已删除，可恢复；同一密钥重新创建时才永久删除旧记录
+ALTER TABLE api_keys ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_api_keys_deleted_at ON api_keys(deleted_at);
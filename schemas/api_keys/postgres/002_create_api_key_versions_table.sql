@@ -0,0 +1,13 @@
+-- API密钥版本表结构定义，记录每次轮换的历史
+CREATE TABLE IF NOT EXISTS api_key_versions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider_type VARCHAR(50) NOT NULL,
+    version BIGINT NOT NULL,
+    encrypted_key TEXT NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    rotated_at TIMESTAMPTZ, -- 被新版本替换的时间，为空表示当前版本
+    grace_expires_at TIMESTAMPTZ, -- 旧版本在此之前仍可用于回退
+    CONSTRAINT api_key_versions_user_provider_version_key UNIQUE (user_id, provider_type, version)
+);
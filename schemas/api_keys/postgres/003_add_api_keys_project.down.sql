@@ -0,0 +1,10 @@
+-- 去掉 project_id，只保留用户的默认密钥
+DELETE FROM api_keys WHERE project_id <> 0;
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_user_project_provider_key;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_user_provider_key UNIQUE (user_id, provider_type);
+ALTER TABLE api_keys DROP COLUMN project_id;
+
+DELETE FROM api_key_versions WHERE project_id <> 0;
+ALTER TABLE api_key_versions DROP CONSTRAINT api_key_versions_user_project_provider_version_key;
+ALTER TABLE api_key_versions ADD CONSTRAINT api_key_versions_user_provider_version_key UNIQUE (user_id, provider_type, version);
+ALTER TABLE api_key_versions DROP COLUMN project_id;
@@ -0,0 +1,8 @@
+-- API密钥按项目分组：project_id 为 0 表示用户的默认密钥
+ALTER TABLE api_keys ADD COLUMN project_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_user_provider_key;
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_user_project_provider_key UNIQUE (user_id, project_id, provider_type); -- 每个项目每个提供商只能有一个密钥
+
+ALTER TABLE api_key_versions ADD COLUMN project_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE api_key_versions DROP CONSTRAINT api_key_versions_user_provider_version_key;
+ALTER TABLE api_key_versions ADD CONSTRAINT api_key_versions_user_project_provider_version_key UNIQUE (user_id, project_id, provider_type, version);
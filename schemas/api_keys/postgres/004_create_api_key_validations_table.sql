@@ -0,0 +1,8 @@
+-- API密钥定期验证结果，key_hash 与当前密钥不一致时视为尚未验证
+CREATE TABLE IF NOT EXISTS api_key_validations (
+    api_key_id BIGINT PRIMARY KEY REFERENCES api_keys(id) ON DELETE CASCADE,
+    key_hash VARCHAR(64) NOT NULL,
+    valid BOOLEAN NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    validated_at TIMESTAMPTZ NOT NULL
+);
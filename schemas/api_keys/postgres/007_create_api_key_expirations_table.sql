@@ -0,0 +1,8 @@
+-- API密钥过期时间，key_hash 与当前密钥不一致时视为未设置过期时间
+CREATE TABLE IF NOT EXISTS api_key_expirations (
+    api_key_id BIGINT PRIMARY KEY REFERENCES api_keys(id) ON DELETE CASCADE,
+    key_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    warned_at TIMESTAMPTZ,
+    expired_at TIMESTAMPTZ
+);
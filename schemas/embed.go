@@ -0,0 +1,10 @@
+// Package schemas 内嵌数据库迁移文件
+package schemas
+
+import "embed"
+
+// FS 迁移文件：SQLite 位于 <表>/，PostgreSQL 和 MySQL 分别位于 <表>/postgres/ 和 <表>/mysql/，
+// 回滚语句位于同名的 .down.sql 文件
+//
+//go:embed */*.sql */postgres/*.sql */mysql/*.sql
+var FS embed.FS
@@ -0,0 +1,10 @@
+-- 评测套件，cases 为 JSON 数组，每个用例包含提示词和预期标准
+CREATE TABLE IF NOT EXISTS eval_suites (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    cases TEXT NOT NULL,
+    created_by INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- 评测运行记录，status 为 running / completed / failed，targets、judge、results、summary 为 JSON
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    suite_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',
+    targets TEXT NOT NULL,
+    judge TEXT NOT NULL DEFAULT '',
+    results TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_by INTEGER NOT NULL DEFAULT 0,
+    finished_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (suite_id) REFERENCES eval_suites(id) ON DELETE CASCADE
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_eval_runs_suite_id ON eval_runs(suite_id);
@@ -0,0 +1,10 @@
+-- 评测套件，cases 为 JSON 数组，每个用例包含提示词和预期标准（MySQL）
+CREATE TABLE IF NOT EXISTS eval_suites (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+    cases MEDIUMTEXT NOT NULL,
+    created_by BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
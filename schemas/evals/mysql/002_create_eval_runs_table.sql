@@ -0,0 +1,17 @@
+-- 评测运行记录，status 为 running / completed / failed，targets、judge、results、summary 为 JSON（MySQL）
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    suite_id BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',
+    targets TEXT NOT NULL,
+    judge TEXT NOT NULL,
+    results MEDIUMTEXT NOT NULL,
+    summary TEXT NOT NULL,
+    error TEXT NOT NULL,
+    created_by BIGINT NOT NULL DEFAULT 0,
+    finished_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (suite_id) REFERENCES eval_suites(id) ON DELETE CASCADE,
+    INDEX idx_eval_runs_suite_id (suite_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+-- 评测套件，cases 为 JSON 数组，每个用例包含提示词和预期标准（PostgreSQL）
+CREATE TABLE IF NOT EXISTS eval_suites (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    cases TEXT NOT NULL,
+    created_by BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
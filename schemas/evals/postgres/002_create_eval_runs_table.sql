@@ -0,0 +1,18 @@
+-- 评测运行记录，status 为 running / completed / failed，targets、judge、results、summary 为 JSON（PostgreSQL）
+CREATE TABLE IF NOT EXISTS eval_runs (
+    id BIGSERIAL PRIMARY KEY,
+    suite_id BIGINT NOT NULL REFERENCES eval_suites(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'running',
+    targets TEXT NOT NULL,
+    judge TEXT NOT NULL DEFAULT '',
+    results TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_by BIGINT NOT NULL DEFAULT 0,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_eval_runs_suite_id ON eval_runs(suite_id);
@@ -0,0 +1,20 @@
+-- 实验中每个请求的结果，feedback 为 1（赞）/ -1（踩）/ 0（未评价）
+CREATE TABLE IF NOT EXISTS experiment_outcomes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    experiment_id INTEGER NOT NULL,
+    variant VARCHAR(1) NOT NULL,
+    response_id VARCHAR(128) NOT NULL DEFAULT '',
+    user_id INTEGER NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    tool_calls INTEGER NOT NULL DEFAULT 0,
+    tool_failures INTEGER NOT NULL DEFAULT 0,
+    feedback INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_experiment_outcomes_experiment_id ON experiment_outcomes(experiment_id);
+CREATE INDEX IF NOT EXISTS idx_experiment_outcomes_response_id ON experiment_outcomes(response_id);
@@ -0,0 +1,17 @@
+-- A/B 实验，status 为 draft / running / stopped，variant_a、variant_b 为 JSON，traffic_split 为分配给 B 的流量百分比（MySQL）
+CREATE TABLE IF NOT EXISTS experiments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+    persona VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'draft',
+    traffic_split BIGINT NOT NULL DEFAULT 50,
+    variant_a TEXT NOT NULL,
+    variant_b TEXT NOT NULL,
+    created_by BIGINT NOT NULL DEFAULT 0,
+    started_at DATETIME NULL,
+    stopped_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_experiments_persona_status (persona, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,18 @@
+-- 实验中每个请求的结果，feedback 为 1（赞）/ -1（踩）/ 0（未评价）（MySQL）
+CREATE TABLE IF NOT EXISTS experiment_outcomes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    experiment_id BIGINT NOT NULL,
+    variant VARCHAR(1) NOT NULL,
+    response_id VARCHAR(128) NOT NULL DEFAULT '',
+    user_id BIGINT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    tool_calls BIGINT NOT NULL DEFAULT 0,
+    tool_failures BIGINT NOT NULL DEFAULT 0,
+    feedback BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (experiment_id) REFERENCES experiments(id) ON DELETE CASCADE,
+    INDEX idx_experiment_outcomes_experiment_id (experiment_id),
+    INDEX idx_experiment_outcomes_response_id (response_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,19 @@
+-- A/B 实验，status 为 draft / running / stopped，variant_a、variant_b 为 JSON，traffic_split 为分配给 B 的流量百分比（PostgreSQL）
+CREATE TABLE IF NOT EXISTS experiments (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    persona VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'draft',
+    traffic_split BIGINT NOT NULL DEFAULT 50,
+    variant_a TEXT NOT NULL,
+    variant_b TEXT NOT NULL,
+    created_by BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ,
+    stopped_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_experiments_persona_status ON experiments(persona, status);
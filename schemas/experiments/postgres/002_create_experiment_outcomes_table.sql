@@ -0,0 +1,19 @@
+-- 实验中每个请求的结果，feedback 为 1（赞）/ -1（踩）/ 0（未评价）（PostgreSQL）
+CREATE TABLE IF NOT EXISTS experiment_outcomes (
+    id BIGSERIAL PRIMARY KEY,
+    experiment_id BIGINT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    variant VARCHAR(1) NOT NULL,
+    response_id VARCHAR(128) NOT NULL DEFAULT '',
+    user_id BIGINT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    tool_calls BIGINT NOT NULL DEFAULT 0,
+    tool_failures BIGINT NOT NULL DEFAULT 0,
+    feedback BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_experiment_outcomes_experiment_id ON experiment_outcomes(experiment_id);
+CREATE INDEX IF NOT EXISTS idx_experiment_outcomes_response_id ON experiment_outcomes(response_id);
@@ -0,0 +1,20 @@
+-- 用户对助手回复的评价，rating 为 1（赞）/ -1（踩），conversation 为 JSON 格式的请求消息和回复，
+-- 同一用户对同一回复只保留最后一次评价
+CREATE TABLE IF NOT EXISTS response_feedback (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    response_id VARCHAR(128) NOT NULL,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    rating INTEGER NOT NULL,
+    category VARCHAR(32) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    provider VARCHAR(64) NOT NULL DEFAULT '',
+    model VARCHAR(128) NOT NULL DEFAULT '',
+    persona VARCHAR(64) NOT NULL DEFAULT '',
+    conversation TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (response_id, user_id)
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_response_feedback_rating ON response_feedback(rating);
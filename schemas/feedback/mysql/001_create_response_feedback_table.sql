@@ -0,0 +1,18 @@
+-- 用户对助手回复的评价，rating 为 1（赞）/ -1（踩），conversation 为 JSON 格式的请求消息和回复，
+-- 同一用户对同一回复只保留最后一次评价（MySQL）
+CREATE TABLE IF NOT EXISTS response_feedback (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    response_id VARCHAR(128) NOT NULL,
+    user_id BIGINT NOT NULL DEFAULT 0,
+    rating INT NOT NULL,
+    category VARCHAR(32) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL,
+    provider VARCHAR(64) NOT NULL DEFAULT '',
+    model VARCHAR(128) NOT NULL DEFAULT '',
+    persona VARCHAR(64) NOT NULL DEFAULT '',
+    conversation MEDIUMTEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_response_feedback_response_user (response_id, user_id),
+    INDEX idx_response_feedback_rating (rating)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
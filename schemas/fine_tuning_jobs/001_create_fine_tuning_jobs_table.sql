@@ -0,0 +1,19 @@
+-- OpenAI 微调任务，status 与 OpenAI 一致，model_registered 表示成功后的模型已添加到模型配置
+CREATE TABLE IF NOT EXISTS fine_tuning_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id VARCHAR(128) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL,
+    base_model VARCHAR(128) NOT NULL,
+    training_file VARCHAR(128) NOT NULL,
+    validation_file VARCHAR(128) NOT NULL DEFAULT '',
+    suffix VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL,
+    fine_tuned_model VARCHAR(255) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    model_registered BOOLEAN NOT NULL DEFAULT FALSE,
+    finished_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fine_tuning_jobs_status ON fine_tuning_jobs(status);
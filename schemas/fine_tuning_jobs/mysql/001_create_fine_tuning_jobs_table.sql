@@ -0,0 +1,18 @@
+-- OpenAI 微调任务，status 与 OpenAI 一致，model_registered 表示成功后的模型已添加到模型配置（MySQL）
+CREATE TABLE IF NOT EXISTS fine_tuning_jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    job_id VARCHAR(128) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL,
+    base_model VARCHAR(128) NOT NULL,
+    training_file VARCHAR(128) NOT NULL,
+    validation_file VARCHAR(128) NOT NULL DEFAULT '',
+    suffix VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL,
+    fine_tuned_model VARCHAR(255) NOT NULL DEFAULT '',
+    error VARCHAR(1024) NOT NULL DEFAULT '',
+    model_registered BOOLEAN NOT NULL DEFAULT FALSE,
+    finished_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_fine_tuning_jobs_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,7 @@
+-- 用户记忆开关，没有记录时默认开启
+CREATE TABLE IF NOT EXISTS user_memory_settings (
+    user_id INTEGER PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- 从对话中提取的用户长期记忆，memory_key 用于去重，同一用户的同一事实只保留一条，
+-- 风险偏好等唯一事实的 key 为类别本身，新值覆盖旧值（MySQL）
+CREATE TABLE IF NOT EXISTS user_memories (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    memory_key VARCHAR(191) NOT NULL,
+    content VARCHAR(500) NOT NULL,
+    source_response_id VARCHAR(128) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_user_memories_user_key (user_id, memory_key),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
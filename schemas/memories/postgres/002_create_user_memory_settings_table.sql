@@ -0,0 +1,6 @@
+-- 用户记忆开关，没有记录时默认开启（PostgreSQL）
+CREATE TABLE IF NOT EXISTS user_memory_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
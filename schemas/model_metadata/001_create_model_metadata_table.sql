@@ -0,0 +1,13 @@
+-- 模型元数据，管理员维护，覆盖在提供商的模型配置之上；context_window 为 0 表示未知，
+-- 价格为每百万令牌的美元价格，为空时使用 metrics.model_prices 中的配置
+CREATE TABLE IF NOT EXISTS model_metadata (
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    context_window INTEGER NOT NULL DEFAULT 0,
+    input_price REAL,
+    output_price REAL,
+    supports_tools BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_vision BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, model)
+);
@@ -0,0 +1,15 @@
+-- 用户通知渠道，每个用户每种渠道一条；target 为邮箱、Slack Webhook 地址或 Telegram 会话ID，
+-- 邮件渠道为空时使用账号邮箱；events 为逗号分隔的订阅事件，支持 * 和 quota.* 形式的通配（PostgreSQL）
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(16) NOT NULL,
+    target VARCHAR(512) NOT NULL DEFAULT '',
+    events VARCHAR(512) NOT NULL DEFAULT '*',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_channels_enabled ON notification_channels(enabled);
@@ -0,0 +1,15 @@
+-- 个人访问令牌表结构定义（MySQL）
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- 令牌的SHA-256哈希，不存储明文
+    token_prefix VARCHAR(16) NOT NULL, -- 令牌前缀，便于用户识别
+    scopes TEXT NOT NULL, -- 逗号分隔的权限范围
+    expires_at DATETIME, -- 为空表示永不过期
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_personal_access_tokens_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
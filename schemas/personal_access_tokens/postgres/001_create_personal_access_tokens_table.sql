@@ -0,0 +1,16 @@
+-- 个人访问令牌表结构定义（PostgreSQL）
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- 令牌的SHA-256哈希，不存储明文
+    token_prefix VARCHAR(16) NOT NULL, -- 令牌前缀，便于用户识别
+    scopes TEXT NOT NULL, -- 逗号分隔的权限范围
+    expires_at TIMESTAMPTZ, -- 为空表示永不过期
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
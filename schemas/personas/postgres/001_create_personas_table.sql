@@ -0,0 +1,15 @@
+-- 助手角色，name 与聊天请求的 persona 对应，system_prompt 为模板，allowed_tools 为允许使用的工具名称 JSON 数组，包含 "*" 时允许所有工具（PostgreSQL）
+CREATE TABLE IF NOT EXISTS personas (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    system_prompt TEXT NOT NULL,
+    allowed_tools TEXT NOT NULL,
+    default_provider VARCHAR(64) NOT NULL DEFAULT '',
+    default_model VARCHAR(128) NOT NULL DEFAULT '',
+    temperature DOUBLE PRECISION, -- 为空时使用模型默认值
+    max_tokens BIGINT, -- 为空时不限制
+    created_by BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
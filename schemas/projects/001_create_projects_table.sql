@@ -0,0 +1,14 @@
+-- 项目表结构定义，用于按应用或团队对API密钥和用量分组
+CREATE TABLE IF NOT EXISTS projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(user_id, name) -- 项目名称在用户内唯一
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
@@ -0,0 +1,11 @@
+-- 项目表结构定义，用于按应用或团队对API密钥和用量分组（MySQL）
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    description VARCHAR(1024) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_projects_user_name (user_id, name) -- 项目名称在用户内唯一
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
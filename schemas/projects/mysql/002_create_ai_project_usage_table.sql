@@ -0,0 +1,11 @@
+-- 按项目和UTC日期聚合的AI用量统计
+CREATE TABLE IF NOT EXISTS ai_project_usage (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    usage_date VARCHAR(10) NOT NULL, -- UTC日期，格式 YYYY-MM-DD
+    request_count BIGINT NOT NULL DEFAULT 0,
+    token_count BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_ai_project_usage_project_date (project_id, usage_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,13 @@
+-- 项目表结构定义，用于按应用或团队对API密钥和用量分组（PostgreSQL）
+CREATE TABLE IF NOT EXISTS projects (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name) -- 项目名称在用户内唯一
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
@@ -0,0 +1,20 @@
+-- 定时任务，schedule 为 cron 表达式，params 为任务类型的 JSON 参数，last_status 为 succeeded / failed
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    job_type VARCHAR(64) NOT NULL,
+    schedule VARCHAR(128) NOT NULL,
+    params TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at DATETIME NOT NULL,
+    last_run_at DATETIME,
+    last_status VARCHAR(16) NOT NULL DEFAULT '',
+    last_result TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    last_duration_ms INTEGER NOT NULL DEFAULT 0,
+    run_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_due ON scheduled_jobs(enabled, next_run_at);
@@ -0,0 +1,19 @@
+-- 定时任务，schedule 为 cron 表达式，params 为任务类型的 JSON 参数，last_status 为 succeeded / failed（MySQL）
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    job_type VARCHAR(64) NOT NULL,
+    schedule VARCHAR(128) NOT NULL,
+    params TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at DATETIME NOT NULL,
+    last_run_at DATETIME NULL,
+    last_status VARCHAR(16) NOT NULL DEFAULT '',
+    last_result VARCHAR(1024) NOT NULL DEFAULT '',
+    last_error VARCHAR(1024) NOT NULL DEFAULT '',
+    last_duration_ms BIGINT NOT NULL DEFAULT 0,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_scheduled_jobs_due (enabled, next_run_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+-- 用户权限表结构定义，记录在管理员身份之外单独授予的敏感操作权限
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id INTEGER NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    granted_by INTEGER, -- 授权的管理员，删除后置空
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, permission),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (granted_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,8 @@
+-- 用户权限表结构定义，记录在管理员身份之外单独授予的敏感操作权限（PostgreSQL）
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    granted_by BIGINT REFERENCES users(id) ON DELETE SET NULL, -- 授权的管理员，删除后置空
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, permission)
+);
@@ -0,0 +1,10 @@
+-- 用户偏好设置表结构定义，空值表示未设置（PostgreSQL）
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    default_provider VARCHAR(50) NOT NULL DEFAULT '',
+    default_model VARCHAR(100) NOT NULL DEFAULT '',
+    temperature DOUBLE PRECISION, -- 为空时使用模型默认值
+    locale VARCHAR(10) NOT NULL DEFAULT '',
+    default_period VARCHAR(10) NOT NULL DEFAULT '', -- 股票分析默认周期
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- 用户软删除：deleted_at 非空表示已删除，超过保留期后由清理任务永久删除
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,21 @@
+-- 用户表结构定义（PostgreSQL）
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(50) UNIQUE NOT NULL,
+    email VARCHAR(100) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    first_name VARCHAR(50),
+    last_name VARCHAR(50),
+    is_active BOOLEAN DEFAULT TRUE,
+    is_admin BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引以提高查询性能
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+
+-- 查询按 SQLite 语法编写，用户列表的时间筛选使用 datetime() 规范化参数
+CREATE OR REPLACE FUNCTION datetime(ts TIMESTAMPTZ) RETURNS TIMESTAMPTZ
+    AS 'SELECT ts' LANGUAGE SQL IMMUTABLE;
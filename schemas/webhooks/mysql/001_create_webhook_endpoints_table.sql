@@ -0,0 +1,11 @@
+-- Webhook 端点，event_types 为逗号分隔的订阅事件类型，支持 * 和 audit.* 形式的通配（MySQL）
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    event_types VARCHAR(1024) NOT NULL,
+    description VARCHAR(1024) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
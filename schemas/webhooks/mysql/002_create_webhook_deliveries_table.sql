@@ -0,0 +1,18 @@
+-- Webhook 投递记录，每个事件对每个订阅端点一条，status 为 pending / succeeded / failed
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    endpoint_id BIGINT NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload MEDIUMTEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts BIGINT NOT NULL DEFAULT 0,
+    response_code BIGINT NOT NULL DEFAULT 0, -- 最近一次尝试的HTTP状态码，0 表示未收到响应
+    error TEXT NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_endpoint_id (endpoint_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;